@@ -21,7 +21,6 @@ import (
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/servicedversion"
-	"github.com/zenoss/glog"
 )
 
 const defaultHostName = "defaultHost"
@@ -127,12 +126,12 @@ func (agent *Agent) readConfiguration() error {
 func (agent *Agent) runDaemon() error {
 	daemon, err := newDaemon(&agent.hostConfig, rpc.NewServer())
 	if err != nil {
-		glog.Fatalf("could not create server: %v", err)
+		plog.Fatalf("could not create server: %v", err)
 	}
 
 	err = daemon.run(agent.options.IPAddress)
 	if err != nil {
-		glog.Fatalf("could not start server: %v", err)
+		plog.Fatalf("could not start server: %v", err)
 	}
 
 	return nil
@@ -25,7 +25,6 @@ import (
 	"github.com/control-center/serviced/proxy"
 	"github.com/control-center/serviced/rpc/rpcutils"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 )
 
 type daemon struct {
@@ -53,20 +52,20 @@ func (d *daemon) run(address string) (err error) {
 
 	d.startRPC()
 	if err := d.registerAgentRPC(); err != nil {
-		glog.Fatal(err)
+		plog.Fatal(err)
 	}
 
 	signalC := make(chan os.Signal, 10)
 	signal.Notify(signalC, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 	sig := <-signalC
-	glog.Infof("Shutting down due to interrupt - %v", sig)
+	plog.Infof("Shutting down due to interrupt - %v", sig)
 	return nil
 }
 
 func (d *daemon) startRPC() {
 	cert, err := tls.X509KeyPair([]byte(proxy.InsecureCertPEM), []byte(proxy.InsecureKeyPEM))
 	if err != nil {
-		glog.Fatalf("Could not parse public/private key pair (tls.X509KeyPair): %v", err)
+		plog.Fatalf("Could not parse public/private key pair (tls.X509KeyPair): %v", err)
 	}
 
 	tlsConfig := tls.Config{
@@ -78,17 +77,17 @@ func (d *daemon) startRPC() {
 
 	listener, err := tls.Listen("tcp", d.hostConfig.Listen, &tlsConfig)
 	if err != nil {
-		glog.Fatalf("Unable to bind to port %s. Is another instance running?", d.hostConfig.Listen)
+		plog.Fatalf("Unable to bind to port %s. Is another instance running?", d.hostConfig.Listen)
 	}
 
 	d.rpcServer.HandleHTTP(rpc.DefaultRPCPath, rpc.DefaultDebugPath)
 
-	glog.Infof("Listening on %s", listener.Addr().String())
+	plog.Infof("Listening on %s", listener.Addr().String())
 	go func() {
 		for {
 			conn, err := listener.Accept()
 			if err != nil {
-				glog.Fatalf("Error accepting connections: %s", err)
+				plog.Fatalf("Error accepting connections: %s", err)
 			}
 			go d.rpcServer.ServeCodec(rpcutils.NewDefaultAuthServerCodec(conn))
 		}
@@ -102,11 +101,11 @@ func (d *daemon) newMock() *MockAgent {
 }
 
 func (d *daemon) registerAgentRPC() error {
-	glog.Infof("agent start staticips: %v [%d]", d.hostConfig.StaticIPs, len(d.hostConfig.StaticIPs))
+	plog.Infof("agent start staticips: %v [%d]", d.hostConfig.StaticIPs, len(d.hostConfig.StaticIPs))
 	if err := d.rpcServer.RegisterName("Agent", d.newMock()); err != nil {
 		return fmt.Errorf("could not register Agent RPC server: %v", err)
 	}
-	glog.Infof("finished rpcServer.RegisterName")
+	plog.Infof("finished rpcServer.RegisterName")
 	return nil
 }
 
@@ -120,7 +119,7 @@ func (d *daemon) buildHost() error {
 	}
 
 	var err error
-	glog.Infof("Outbound IP: %s", d.hostConfig.OutboundIP)
+	plog.Infof("Outbound IP: %s", d.hostConfig.OutboundIP)
 
 	d.host, err = host.Build(d.hostConfig.OutboundIP, rpcPort, d.hostConfig.PoolID, fmt.Sprintf("%d", d.hostConfig.Memory))
 	if err != nil {
@@ -18,7 +18,6 @@ import (
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/rpc/agent"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 )
 
 type MockAgent struct {
@@ -28,7 +27,7 @@ type MockAgent struct {
 func (m *MockAgent) BuildHost(request agent.BuildHostRequest, hostResponse *host.Host) error {
 	*hostResponse = host.Host{}
 
-	glog.Infof("Build Host Request: %s:%d, %s, %s", request.IP, request.Port, request.PoolID, request.Memory)
+	plog.Infof("Build Host Request: %s:%d, %s, %s", request.IP, request.Port, request.PoolID, request.Memory)
 
 	if _, err := utils.ParseEngineeringNotation(request.Memory); err == nil {
 		m.mockHost.RAMLimit = request.Memory
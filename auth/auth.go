@@ -32,6 +32,12 @@ var (
 	ErrIdentityTokenExpired = errors.New("Identity token expired")
 	// ErrIdentityTokenNotValidYet is thrown when an identity token is used before its issue time
 	ErrIdentityTokenNotValidYet = errors.New("Identity token used before issue time")
+	// ErrIdentityTokenClockSkew is thrown when an identity token is rejected
+	// because the sender's clock does not match the receiver's clock
+	ErrIdentityTokenClockSkew = errors.New("Identity token rejected because the sender's clock is out of sync")
+	// ErrRequestClockSkew is thrown when an authentication request is rejected
+	// because the sender's clock does not match the receiver's clock
+	ErrRequestClockSkew = errors.New("Authentication request rejected because the sender's clock is out of sync")
 	// ErrIdentityTokenBadSig is thrown when an identity token has a bad signature
 	ErrIdentityTokenBadSig = errors.New("Identity token signature cannot be verified")
 	// ErrNoPublicKey is thrown when no public key is available to verify a signature
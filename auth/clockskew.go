@@ -0,0 +1,87 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// HostClockSkew is the most recent measurement of how far a host's clock is
+// behind the master's clock.  A negative skew means the host's clock is ahead.
+type HostClockSkew struct {
+	Skew       time.Duration
+	MeasuredAt time.Time
+}
+
+// HostClockSkewRegistry is a threadsafe map of host id to the host's most
+// recently measured clock skew
+type HostClockSkewRegistry struct {
+	registry map[string]HostClockSkew
+	sync.RWMutex
+}
+
+// Set records the clock skew of a host and returns its previous measurement
+func (reg *HostClockSkewRegistry) Set(hostid string, skew time.Duration) (HostClockSkew, bool) {
+	reg.Lock()
+	defer reg.Unlock()
+	prev, ok := reg.registry[hostid]
+	reg.registry[hostid] = HostClockSkew{Skew: skew, MeasuredAt: time.Now()}
+	return prev, ok
+}
+
+// Get returns the most recent clock skew of a host
+func (reg *HostClockSkewRegistry) Get(hostid string) (HostClockSkew, bool) {
+	reg.RLock()
+	defer reg.RUnlock()
+	skew, ok := reg.registry[hostid]
+	return skew, ok
+}
+
+// Remove removes a host from the clock skew registry
+func (reg *HostClockSkewRegistry) Remove(hostid string) {
+	reg.Lock()
+	defer reg.Unlock()
+	delete(reg.registry, hostid)
+}
+
+// Exceeding returns the most recent clock skew of each host whose skew is
+// larger than max
+func (reg *HostClockSkewRegistry) Exceeding(max time.Duration) map[string]HostClockSkew {
+	reg.RLock()
+	defer reg.RUnlock()
+	skewed := make(map[string]HostClockSkew)
+	for hostid, skew := range reg.registry {
+		if skew.Exceeds(max) {
+			skewed[hostid] = skew
+		}
+	}
+	return skewed
+}
+
+// Exceeds returns true if the magnitude of the skew is larger than max
+func (s HostClockSkew) Exceeds(max time.Duration) bool {
+	skew := s.Skew
+	if skew < 0 {
+		skew = -skew
+	}
+	return skew > max
+}
+
+// NewHostClockSkewRegistry creates a new HostClockSkewRegistry
+func NewHostClockSkewRegistry() *HostClockSkewRegistry {
+	return &HostClockSkewRegistry{
+		registry: make(map[string]HostClockSkew),
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package auth_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/auth"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAuthSuite) TestHostClockSkew(c *C) {
+	reg := auth.NewHostClockSkewRegistry()
+	_, ok := reg.Get("fakehost")
+	c.Assert(ok, Equals, false)
+
+	_, ok = reg.Set("fakehost", 2*time.Second)
+	c.Assert(ok, Equals, false)
+	prev, ok := reg.Set("fakehost", -time.Minute)
+	c.Assert(ok, Equals, true)
+	c.Assert(prev.Skew, Equals, 2*time.Second)
+
+	skew, ok := reg.Get("fakehost")
+	c.Assert(ok, Equals, true)
+	c.Assert(skew.Skew, Equals, -time.Minute)
+	c.Assert(skew.Exceeds(30*time.Second), Equals, true)
+	c.Assert(skew.Exceeds(time.Minute), Equals, false)
+	c.Assert(prev.Exceeds(time.Second), Equals, true)
+
+	reg.Set("otherhost", time.Second)
+	skewed := reg.Exceeding(30 * time.Second)
+	c.Assert(skewed, HasLen, 1)
+	c.Assert(skewed["fakehost"].Skew, Equals, -time.Minute)
+
+	reg.Remove("fakehost")
+	_, ok = reg.Get("fakehost")
+	c.Assert(ok, Equals, false)
+}
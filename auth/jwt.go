@@ -104,14 +104,16 @@ func CreateJWTIdentity(hostID, poolID string, admin, dfs bool, pubKeyPEM []byte,
 	return signed, claims.ExpiresAt, err
 }
 
+// Valid checks the expiration and issue time of the token, allowing for the
+// configured clock skew tolerance
 func (id *jwtIdentity) Valid() error {
-
-	if id.Expired() {
+	tolerance := int64(ClockSkewTolerance() / time.Second)
+	now := jwt.TimeFunc().UTC().Unix()
+	if now >= id.ExpiresAt+tolerance {
 		return ErrIdentityTokenExpired
 	}
 
-	now := jwt.TimeFunc().UTC().Unix()
-	if now < id.IssuedAt {
+	if now+tolerance < id.IssuedAt {
 		return ErrIdentityTokenNotValidYet
 	}
 
@@ -74,3 +74,43 @@ func (s *TestAuthSuite) TestBadSignature(c *C) {
 	_, err := auth.ParseJWTIdentity(token)
 	c.Assert(err, Equals, auth.ErrIdentityTokenBadSig)
 }
+
+func (s *TestAuthSuite) TestTokenExpiresWithoutClockSkewTolerance(c *C) {
+	c.Assert(auth.ClockSkewTolerance(), Equals, time.Duration(0))
+	token, expires, _ := auth.CreateJWTIdentity("host", "pool", true, false, s.delegatePubPEM, time.Minute)
+
+	auth.At(time.Unix(expires-1, 0).UTC(), func() {
+		_, err := auth.ParseJWTIdentity(token)
+		c.Assert(err, IsNil)
+	})
+	auth.At(time.Unix(expires, 0).UTC(), func() {
+		_, err := auth.ParseJWTIdentity(token)
+		c.Assert(err, Equals, auth.ErrIdentityTokenExpired)
+	})
+}
+
+func (s *TestAuthSuite) TestTokenClockSkewTolerance(c *C) {
+	defer auth.SetClockSkewTolerance(auth.DefaultClockSkewTolerance)
+	auth.SetClockSkewTolerance(30 * time.Second)
+	token, expires, _ := auth.CreateJWTIdentity("host", "pool", true, false, s.delegatePubPEM, time.Minute)
+
+	// within the tolerance on either side of the token's lifetime
+	auth.At(time.Unix(expires+29, 0).UTC(), func() {
+		_, err := auth.ParseJWTIdentity(token)
+		c.Assert(err, IsNil)
+	})
+	auth.At(time.Now().UTC().Add(-10*time.Second), func() {
+		_, err := auth.ParseJWTIdentity(token)
+		c.Assert(err, IsNil)
+	})
+
+	// beyond the tolerance
+	auth.At(time.Unix(expires+30, 0).UTC(), func() {
+		_, err := auth.ParseJWTIdentity(token)
+		c.Assert(err, Equals, auth.ErrIdentityTokenExpired)
+	})
+	auth.At(time.Now().UTC().Add(-time.Minute), func() {
+		_, err := auth.ParseJWTIdentity(token)
+		c.Assert(err, Equals, auth.ErrIdentityTokenNotValidYet)
+	})
+}
@@ -40,6 +40,7 @@ type RestToken interface {
 	RestToken() string
 	ValidateRequestHash(r *http.Request) bool
 	HasAdminAccess() bool
+	TenantID() string
 }
 
 type jwtRestClaims struct {
@@ -47,6 +48,7 @@ type jwtRestClaims struct {
 	ExpiresAt     int64  `json:"exp,omitempty"`
 	DelegateToken string `json:"tkn,omitempty"`
 	ReqHash       []byte `json:"req,omitempty"`
+	Tenant        string `json:"tnt,omitempty"`
 }
 
 func (t *jwtRestClaims) Valid() error {
@@ -80,6 +82,11 @@ func (t *jwtRestToken) HasAdminAccess() bool {
 	return t.authIdentity.HasAdminAccess()
 }
 
+// TenantID returns the tenant of the service that the token was issued to.
+func (t *jwtRestToken) TenantID() string {
+	return t.Tenant
+}
+
 func (t *jwtRestToken) RestToken() string {
 	return t.restToken
 }
@@ -106,7 +113,9 @@ func GetRequestHash(r *http.Request) []byte {
 	return hashedReq[:]
 }
 
-func BuildRestToken(r *http.Request) (string, error) {
+// BuildRestToken signs a request that a service of the given tenant makes to
+// the REST API.  The api only lets the request reach services of that tenant.
+func BuildRestToken(r *http.Request, tenantID string) (string, error) {
 	now := jwt.TimeFunc().UTC()
 	requestHash := GetRequestHash(r)
 	iat := now.Unix()
@@ -115,7 +124,7 @@ func BuildRestToken(r *http.Request) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	claims := &jwtRestClaims{iat, exp, authToken, requestHash}
+	claims := &jwtRestClaims{iat, exp, authToken, requestHash, tenantID}
 	restToken := jwt.NewWithClaims(jwt.SigningMethodPS256, claims)
 	delegatePrivKey, err := getDelegatePrivateKey()
 	if err != nil {
@@ -58,7 +58,7 @@ func (s *TestAuthSuite) TestBuildAndExtractRestToken(c *C) {
 		return authToken, nil
 	}
 	// Create Rest Token
-	restToken, err := auth.BuildRestToken(req)
+	restToken, err := auth.BuildRestToken(req, "tenant")
 	c.Assert(err, IsNil)
 	c.Assert(restToken, NotNil)
 
@@ -78,6 +78,7 @@ func (s *TestAuthSuite) TestBuildAndExtractRestToken(c *C) {
 	c.Assert(parsedToken, NotNil)
 	c.Assert(parsedToken.RestToken(), DeepEquals, restToken)
 	c.Assert(parsedToken.AuthToken(), DeepEquals, authToken)
+	c.Assert(parsedToken.TenantID(), Equals, "tenant")
 	c.Assert(parsedToken.HasAdminAccess(), Equals, cfg.admin)
 	c.Assert(parsedToken.Expired(), Equals, false)
 	c.Assert(parsedToken.Valid(), IsNil)
@@ -97,7 +98,7 @@ func (s *TestAuthSuite) TestExpiredRestToken(c *C) {
 	}
 	auth.RestTokenExpiration = -1 * time.Hour
 	// Create Rest Token
-	restToken, err := auth.BuildRestToken(req)
+	restToken, err := auth.BuildRestToken(req, "tenant")
 	c.Assert(err, IsNil)
 	// Add rest token to request header
 	auth.AddRestTokenToRequest(req, restToken)
@@ -121,7 +122,7 @@ func (s *TestAuthSuite) TestTamperedRestToken(c *C) {
 		return authToken, nil
 	}
 	// Create Rest Token
-	restToken, err := auth.BuildRestToken(req)
+	restToken, err := auth.BuildRestToken(req, "tenant")
 	c.Assert(err, IsNil)
 	// modify token
 	l := len(restToken)
@@ -170,7 +171,7 @@ func (s *TestAuthSuite) TestValidateRequestHash(c *C) {
 		return authToken, nil
 	}
 	// Create Rest Token
-	restToken, err := auth.BuildRestToken(req)
+	restToken, err := auth.BuildRestToken(req, "tenant")
 	auth.AddRestTokenToRequest(req, restToken)
 	extractedToken, err := auth.ExtractRestToken(req)
 	c.Assert(err, IsNil)
@@ -194,7 +195,7 @@ func (s *TestAuthSuite) TestExpiredAuthToken(c *C) {
 		return authToken, nil
 	}
 	// Create Rest Token
-	restToken, err := auth.BuildRestToken(req)
+	restToken, err := auth.BuildRestToken(req, "tenant")
 	c.Assert(err, IsNil)
 	// Add rest token to request header
 	auth.AddRestTokenToRequest(req, restToken)
@@ -219,7 +220,7 @@ func (s *TestAuthSuite) TestTamperedAuthToken(c *C) {
 		return authToken, nil
 	}
 	// Create Rest Token
-	restToken, err := auth.BuildRestToken(req)
+	restToken, err := auth.BuildRestToken(req, "tenant")
 	c.Assert(err, IsNil)
 	// Add rest token to request header
 	auth.AddRestTokenToRequest(req, restToken)
@@ -141,7 +141,17 @@ func (r *RPCHeaderHandler) ParseHeader(rawHeader []byte, req []byte) (Identity,
 
 	// Validate the token can be parsed
 	senderIdentity, err := ParseJWTIdentity(token)
-	if err != nil {
+	if err == ErrIdentityTokenExpired || err == ErrIdentityTokenNotValidYet {
+		// if the sender's clock disagrees with ours, the token is not stale;
+		// the clocks are out of sync.
+		if len(rawHeader) >= int(offset)+TIMESTAMP_BYTES {
+			timestamp := endian.Uint64(rawHeader[offset : offset+TIMESTAMP_BYTES])
+			if isClockSkewed(time.Unix(int64(timestamp), 0)) {
+				return nil, ErrIdentityTokenClockSkew
+			}
+		}
+		return nil, err
+	} else if err != nil {
 		return nil, err
 	}
 	if senderIdentity == nil {
@@ -153,10 +163,16 @@ func (r *RPCHeaderHandler) ParseHeader(rawHeader []byte, req []byte) (Identity,
 	timestamp := endian.Uint64(timestampBuf)
 	offset += TIMESTAMP_BYTES
 
-	// Validate timestamp (should be no earlier than current time - expirationDelta)
+	// Validate timestamp (should be no earlier than current time -
+	// expirationDelta, allowing for the configured clock skew tolerance)
 	requestTime := time.Unix(int64(timestamp), 0)
-	cutoffTime := jwt.TimeFunc().UTC().Add(-expirationDelta)
+	cutoffTime := jwt.TimeFunc().UTC().Add(-expirationDelta - ClockSkewTolerance())
 	if requestTime.Before(cutoffTime) {
+		// a sender that is further behind than the tolerance is reported as
+		// out of sync, so that the skew can be diagnosed
+		if ClockSkewTolerance() > 0 {
+			return nil, ErrIdentityTokenClockSkew
+		}
 		return nil, ErrRequestExpired
 	}
 
@@ -184,3 +200,13 @@ func (r *RPCHeaderHandler) ParseHeader(rawHeader []byte, req []byte) (Identity,
 
 	return senderIdentity, nil
 }
+
+// isClockSkewed returns true if a time reported by the sender differs from
+// the current time by more than the clock skew threshold
+func isClockSkewed(sent time.Time) bool {
+	skew := jwt.TimeFunc().UTC().Sub(sent)
+	if skew < 0 {
+		skew = -skew
+	}
+	return skew > ClockSkewThreshold()
+}
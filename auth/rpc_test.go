@@ -18,6 +18,7 @@ package auth_test
 import (
 	"crypto/rsa"
 	"encoding/binary"
+	"errors"
 	"time"
 
 	"github.com/control-center/serviced/auth"
@@ -198,3 +199,59 @@ func (s *TestAuthSuite) TestBuildAndExtractRPCHeader_DelegateSigned_WrongKey(c *
 	_, err = rpcHeaderHandler.ParseHeader(header, request)
 	c.Assert(err, Equals, rsa.ErrVerification)
 }
+
+func (s *TestAuthSuite) TestBuildAndExtractRPCHeader_ClockSkew(c *C) {
+	request := []byte("request body")
+	// the sender's clock is an hour behind, so it still thinks its token is valid
+	fakeToken, _, err := auth.CreateJWTIdentity(s.hostId, s.poolId, s.admin, s.dfs, s.delegatePubPEM, time.Minute)
+	c.Assert(err, IsNil)
+	header, err := rpcHeaderHandler.BuildAuthRPCHeader(fakeToken, request, false)
+	c.Assert(err, IsNil)
+
+	fakenow := time.Now().UTC().Add(time.Hour)
+	auth.At(fakenow, func() {
+		_, err = rpcHeaderHandler.ParseHeader(header, request)
+		c.Assert(err, Equals, auth.ErrIdentityTokenClockSkew)
+		c.Assert(auth.IsClockSkewError(errors.New(err.Error())), Equals, true)
+	})
+}
+
+func (s *TestAuthSuite) TestBuildAndExtractRPCHeader_ClockSkewTolerance(c *C) {
+	defer auth.SetClockSkewTolerance(auth.DefaultClockSkewTolerance)
+	auth.SetClockSkewTolerance(30 * time.Second)
+
+	request := []byte("request body")
+	fakeToken, _, err := auth.CreateJWTIdentity(s.hostId, s.poolId, s.admin, s.dfs, s.delegatePubPEM, time.Hour)
+	c.Assert(err, IsNil)
+	header, err := rpcHeaderHandler.BuildAuthRPCHeader(fakeToken, request, false)
+	c.Assert(err, IsNil)
+
+	// the sender's clock is 20 seconds behind, which is within the tolerance
+	auth.At(time.Now().UTC().Add(20*time.Second), func() {
+		_, err = rpcHeaderHandler.ParseHeader(header, request)
+		c.Assert(err, IsNil)
+	})
+
+	// the sender's clock is 45 seconds behind, which is beyond the tolerance
+	auth.At(time.Now().UTC().Add(45*time.Second), func() {
+		_, err = rpcHeaderHandler.ParseHeader(header, request)
+		c.Assert(err, Equals, auth.ErrIdentityTokenClockSkew)
+		c.Assert(auth.IsClockSkewError(errors.New(err.Error())), Equals, true)
+	})
+}
+
+func (s *TestAuthSuite) TestExtractStaleToken(c *C) {
+	request := []byte("request body")
+	var fakeToken string
+	var err error
+	auth.At(time.Now().UTC().Add(-time.Hour), func() {
+		fakeToken, _, err = auth.CreateJWTIdentity(s.hostId, s.poolId, s.admin, s.dfs, s.delegatePubPEM, time.Minute)
+	})
+	c.Assert(err, IsNil)
+	header, err := rpcHeaderHandler.BuildAuthRPCHeader(fakeToken, request, false)
+	c.Assert(err, IsNil)
+
+	_, err = rpcHeaderHandler.ParseHeader(header, request)
+	c.Assert(err, Equals, auth.ErrIdentityTokenExpired)
+	c.Assert(auth.IsClockSkewError(err), Equals, false)
+}
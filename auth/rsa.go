@@ -7,6 +7,7 @@ import (
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
 )
 
@@ -292,3 +293,41 @@ func LoadRSAKeyPair(pub, priv []byte) (public crypto.PublicKey, private crypto.P
 
 	return public, private, nil
 }
+
+// RSAFingerprint returns the SHA256 fingerprint of an RSA public key, in the
+// same SHA256:BASE64 format that ssh-keygen uses
+func RSAFingerprint(key crypto.PublicKey) (string, error) {
+	pkey, err := verifyRSAPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalPKIXPublicKey(pkey)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:]), nil
+}
+
+// RSAFingerprintFromPEM returns the fingerprint of a PEM-encoded RSA public key
+func RSAFingerprintFromPEM(key []byte) (string, error) {
+	pkey, err := RSAPublicKeyFromPEM(key)
+	if err != nil {
+		return "", err
+	}
+	return RSAFingerprint(pkey)
+}
+
+// DelegateKeyFingerprint returns the fingerprint of the delegate's public key
+// from a package of delegate keys, as loaded by LoadRSAKeyPairPackage
+func DelegateKeyFingerprint(data []byte) (string, error) {
+	_, private, err := LoadRSAKeyPairPackage(data)
+	if err != nil {
+		return "", err
+	}
+	pkey, err := verifyRSAPrivateKey(private)
+	if err != nil {
+		return "", err
+	}
+	return RSAFingerprint(pkey.Public())
+}
@@ -316,3 +316,34 @@ func (s *TestAuthSuite) TestLoadRSAKeyPair(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(rsaprivpem, DeepEquals, priv)
 }
+
+func (s *TestAuthSuite) TestRSAFingerprint(c *C) {
+	pub, priv, err := auth.GenerateRSAKeyPairPEM(nil)
+	c.Assert(err, IsNil)
+	fingerprint, err := auth.RSAFingerprintFromPEM(pub)
+	c.Assert(err, IsNil)
+	c.Assert(strings.HasPrefix(fingerprint, "SHA256:"), Equals, true)
+
+	// The fingerprint of the dev key does not change
+	devFingerprint, err := auth.RSAFingerprintFromPEM(auth.DevPubKeyPEM)
+	c.Assert(err, IsNil)
+	c.Assert(devFingerprint, Not(Equals), fingerprint)
+	again, err := auth.RSAFingerprintFromPEM(auth.DevPubKeyPEM)
+	c.Assert(err, IsNil)
+	c.Assert(again, Equals, devFingerprint)
+
+	// Private keys have no fingerprint
+	_, err = auth.RSAFingerprintFromPEM(priv)
+	c.Assert(err, Equals, auth.ErrNotRSAPublicKey)
+
+	// The delegate key package reports the fingerprint of the delegate key
+	// rather than the master key
+	masterPub, _, err := auth.GenerateRSAKeyPairPEM(nil)
+	c.Assert(err, IsNil)
+	delegateFingerprint, err := auth.DelegateKeyFingerprint(append(priv, masterPub...))
+	c.Assert(err, IsNil)
+	c.Assert(delegateFingerprint, Equals, fingerprint)
+
+	_, err = auth.DelegateKeyFingerprint([]byte("not a key"))
+	c.Assert(err, Equals, auth.ErrBadKeysFile)
+}
@@ -29,6 +29,11 @@ const (
 	expirationDelta = 10 * time.Second
 )
 
+// DefaultClockSkewTolerance is how far the clocks of two hosts may differ
+// before identity tokens passed between them are rejected.  Tolerating skew
+// extends the lifetime of every token, so it is off unless configured.
+const DefaultClockSkewTolerance = time.Duration(0)
+
 var (
 	// TokenFileName is the file in which we store the current token
 	TokenFileName = "auth.token"
@@ -38,8 +43,42 @@ var (
 	zerotime        time.Time
 	expiration      time.Time
 	cond            = utils.NewChannelCond()
+
+	clockSkewTolerance = DefaultClockSkewTolerance
 )
 
+// SetClockSkewTolerance sets how far the clocks of two hosts may differ before
+// identity tokens passed between them are rejected
+func SetClockSkewTolerance(tolerance time.Duration) {
+	clockSkewTolerance = tolerance
+}
+
+// ClockSkewTolerance returns how far the clocks of two hosts may differ before
+// identity tokens passed between them are rejected
+func ClockSkewTolerance() time.Duration {
+	return clockSkewTolerance
+}
+
+// ClockSkewThreshold returns how far the clocks of two hosts may differ
+// before a rejected token or request is blamed on the clocks rather than on
+// its age.
+func ClockSkewThreshold() time.Duration {
+	if clockSkewTolerance > expirationDelta {
+		return clockSkewTolerance
+	}
+	return expirationDelta
+}
+
+// IsClockSkewError returns true if the error, which may have been passed
+// back over rpc, was caused by clocks that are out of sync
+func IsClockSkewError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return msg == ErrIdentityTokenClockSkew.Error() || msg == ErrRequestClockSkew.Error()
+}
+
 // TokenFunc is a function that can return an authentication token and its
 // expiration time
 type TokenFunc func() (string, int64, error)
@@ -127,7 +166,11 @@ func TokenLoop(f TokenFunc, tokenfile string, done <-chan interface{}) {
 	for {
 		expires, err := RefreshToken(f, tokenfile)
 		if err != nil {
-			log.WithError(err).Warn("Unable to obtain authentication token. Retrying in 10s")
+			if IsClockSkewError(err) {
+				log.WithError(err).Warn("Unable to obtain authentication token because this host's clock is out of sync with the master; check time synchronization. Retrying in 10s")
+			} else {
+				log.WithError(err).Warn("Unable to obtain authentication token. Retrying in 10s")
+			}
 			select {
 			case <-done:
 				return
@@ -21,15 +21,16 @@ import (
 	"time"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/dao/client"
+	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/rpc/agent"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/utils"
 
 	dockerclient "github.com/fsouza/go-dockerclient"
-	"github.com/zenoss/glog"
 )
 
 type api struct {
@@ -53,6 +54,9 @@ func NewAPI(master master.ClientInterface, agent *agent.Client, docker *dockercl
 func (a *api) StartServer() error {
 	options := config.GetOptions()
 	configureLoggingForLogstash(options.LogstashURL)
+	if err := configureLogging(options); err != nil {
+		return err
+	}
 	log := log.WithFields(logrus.Fields{
 		"staticips": options.StaticIPs,
 	})
@@ -111,6 +115,8 @@ func (a *api) StartServer() error {
 		defer pprof.StopCPUProfile()
 	}
 
+	auth.SetClockSkewTolerance(time.Duration(options.AuthClockSkewTolerance) * time.Second)
+
 	d, err := newDaemon(options.Endpoint, options.StaticIPs, options.MasterPoolID, time.Duration(options.TokenExpiration)*time.Second)
 	if err != nil {
 		return err
@@ -123,8 +129,7 @@ func configureLoggingForLogstash(logstashURL string) {
 	if err != nil {
 		hostname = "unknown"
 	}
-	glog.SetLogstashType("serviced-" + hostname)
-	glog.SetLogstashURL(logstashURL)
+	logging.SetLogstash("serviced-"+hostname, logstashURL)
 }
 
 // Opens a connection to the master if not already connected
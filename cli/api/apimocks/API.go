@@ -4,17 +4,30 @@ import "github.com/control-center/serviced/cli/api"
 import "github.com/stretchr/testify/mock"
 
 import "io"
+import "time"
 import "github.com/control-center/serviced/dao"
+import "github.com/control-center/serviced/coordinator/storage"
+import "github.com/control-center/serviced/dfs"
+import "github.com/control-center/serviced/domain/addressassignment"
 import "github.com/control-center/serviced/domain/applicationendpoint"
+import "github.com/control-center/serviced/domain/backup"
+import "github.com/control-center/serviced/domain/dbmigration"
 import "github.com/control-center/serviced/domain/host"
+import "github.com/control-center/serviced/domain/maintenance"
+import "github.com/control-center/serviced/domain/schedule"
 import "github.com/control-center/serviced/domain/pool"
+import "github.com/control-center/serviced/domain/registry"
 import "github.com/control-center/serviced/domain/service"
+import "github.com/control-center/serviced/domain/statehistory"
 import "github.com/control-center/serviced/domain/servicedefinition"
 import template "github.com/control-center/serviced/domain/servicetemplate"
 import "github.com/control-center/serviced/isvcs"
 import "github.com/control-center/serviced/metrics"
 import "github.com/control-center/serviced/script"
 import "github.com/control-center/serviced/volume"
+import "github.com/control-center/serviced/zzk/ensemble"
+import "github.com/control-center/serviced/zzk/ha"
+import zkservice "github.com/control-center/serviced/zzk/service"
 
 type API struct {
 	mock.Mock
@@ -53,6 +66,27 @@ func (_m *API) ServicedHealthCheck(IServiceNames []string) ([]isvcs.IServiceHeal
 
 	return r0, r1
 }
+func (_m *API) RestartISvc(name string) ([]string, error) {
+	ret := _m.Called(name)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) GetHosts() ([]host.Host, error) {
 	ret := _m.Called()
 
@@ -254,18 +288,39 @@ func (_m *API) RegisterHost(_a0 []byte) error {
 
 	return r0
 }
-func (_m *API) RegisterRemoteHost(_a0 *host.Host, _a1 []byte) error {
-	ret := _m.Called(_a0, _a1)
+func (_m *API) RegisterRemoteHost(_a0 *host.Host, _a1 []byte, _a2 []string) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*host.Host, []byte) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(*host.Host, []byte, []string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
 
 	return r0
 }
+func (_m *API) GetSSHHostKeys(_a0 *host.Host) ([]api.SSHHostKey, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []api.SSHHostKey
+	if rf, ok := ret.Get(0).(func(*host.Host) []api.SSHHostKey); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]api.SSHHostKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*host.Host) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) WriteDelegateKey(_a0 string, _a1 []byte) error {
 	ret := _m.Called(_a0, _a1)
 
@@ -299,6 +354,102 @@ func (_m *API) ResetHostKey(_a0 string) ([]byte, error) {
 
 	return r0, r1
 }
+func (_m *API) SSHHost(_a0 api.HostSSHConfig) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(api.HostSSHConfig) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) UpgradeDelegates(_a0 host.UpgradeRequest) ([]host.UpgradeResult, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []host.UpgradeResult
+	if rf, ok := ret.Get(0).(func(host.UpgradeRequest) []host.UpgradeResult); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]host.UpgradeResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(host.UpgradeRequest) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) SelfTestHost(_a0 string) (*host.SelfTestReport, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *host.SelfTestReport
+	if rf, ok := ret.Get(0).(func(string) *host.SelfTestReport); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.SelfTestReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) GetHostEvents(_a0 string) ([]host.Event, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []host.Event
+	if rf, ok := ret.Get(0).(func(string) []host.Event); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]host.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) GetHostStorage(_a0 string) (*host.StorageHealth, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *host.StorageHealth
+	if rf, ok := ret.Get(0).(func(string) *host.StorageHealth); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.StorageHealth)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) GetResourcePools() ([]pool.ResourcePool, error) {
 	ret := _m.Called()
 
@@ -407,6 +558,27 @@ func (_m *API) GetPoolIPs(_a0 string) (*pool.PoolIPs, error) {
 
 	return r0, r1
 }
+func (_m *API) GetPoolPortClaims(_a0 string) ([]pool.PortClaim, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []pool.PortClaim
+	if rf, ok := ret.Get(0).(func(string) []pool.PortClaim); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.PortClaim)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) AddVirtualIP(_a0 pool.VirtualIP) error {
 	ret := _m.Called(_a0)
 
@@ -431,6 +603,177 @@ func (_m *API) RemoveVirtualIP(_a0 pool.VirtualIP) error {
 
 	return r0
 }
+func (_m *API) EnableChaos(poolID string, interval time.Duration) error {
+	ret := _m.Called(poolID, interval)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Duration) error); ok {
+		r0 = rf(poolID, interval)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) DisableChaos(poolID string) error {
+	ret := _m.Called(poolID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(poolID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) GetChaosStatus(poolID string) (*service.ChaosStatus, error) {
+	ret := _m.Called(poolID)
+
+	var r0 *service.ChaosStatus
+	if rf, ok := ret.Get(0).(func(string) *service.ChaosStatus); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ChaosStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) GetMasterHAStatus() (*ha.ClusterStatus, error) {
+	ret := _m.Called()
+
+	var r0 *ha.ClusterStatus
+	if rf, ok := ret.Get(0).(func() *ha.ClusterStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ha.ClusterStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) GetZKEnsembleStatus() (*ensemble.Status, error) {
+	ret := _m.Called()
+
+	var r0 *ensemble.Status
+	if rf, ok := ret.Get(0).(func() *ensemble.Status); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ensemble.Status)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) AddZKEnsembleServer(server ensemble.Server, force bool) (*ensemble.Status, error) {
+	ret := _m.Called(server, force)
+
+	var r0 *ensemble.Status
+	if rf, ok := ret.Get(0).(func(ensemble.Server, bool) *ensemble.Status); ok {
+		r0 = rf(server, force)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ensemble.Status)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ensemble.Server, bool) error); ok {
+		r1 = rf(server, force)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) RemoveZKEnsembleServer(id string, force bool) (*ensemble.Status, error) {
+	ret := _m.Called(id, force)
+
+	var r0 *ensemble.Status
+	if rf, ok := ret.Get(0).(func(string, bool) *ensemble.Status); ok {
+		r0 = rf(id, force)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ensemble.Status)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(id, force)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) QueryServices(query service.ServiceQuery) (*service.ServiceSummaryPage, error) {
+	ret := _m.Called(query)
+
+	var r0 *service.ServiceSummaryPage
+	if rf, ok := ret.Get(0).(func(service.ServiceQuery) *service.ServiceSummaryPage); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ServiceSummaryPage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(service.ServiceQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) ResolveServicePath(servicePath string) ([]service.ServiceMatch, error) {
+	ret := _m.Called(servicePath)
+
+	var r0 []service.ServiceMatch
+	if rf, ok := ret.Get(0).(func(string) []service.ServiceMatch); ok {
+		r0 = rf(servicePath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceMatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(servicePath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) GetServices() ([]service.Service, error) {
 	ret := _m.Called()
 
@@ -557,22 +900,74 @@ func (_m *API) CloneService(_a0 string, _a1 string) (*service.Service, error) {
 
 	return r0, r1
 }
-func (_m *API) RemoveService(_a0 string) error {
+func (_m *API) CloneDeployment(_a0 dao.DeploymentCloneRequest) (string, error) {
 	ret := _m.Called(_a0)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
+	var r0 string
+	if rf, ok := ret.Get(0).(func(dao.DeploymentCloneRequest) string); ok {
 		r0 = rf(_a0)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(string)
 	}
 
-	return r0
-}
-func (_m *API) UpdateService(_a0 io.Reader) (*service.Service, error) {
-	ret := _m.Called(_a0)
-
-	var r0 *service.Service
+	var r1 error
+	if rf, ok := ret.Get(1).(func(dao.DeploymentCloneRequest) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) GetServiceConfigConflicts(serviceID string) ([]service.ConfigConflict, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.ConfigConflict
+	if rf, ok := ret.Get(0).(func(string) []service.ConfigConflict); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ConfigConflict)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) ResolveServiceConfigConflict(fileID string, resolution string, content string) error {
+	ret := _m.Called(fileID, resolution, content)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(fileID, resolution, content)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) RemoveService(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) UpdateService(_a0 io.Reader) (*service.Service, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *service.Service
 	if rf, ok := ret.Get(0).(func(io.Reader) *service.Service); ok {
 		r0 = rf(_a0)
 	} else {
@@ -647,6 +1042,117 @@ func (_m *API) StopService(_a0 api.SchedulerConfig) (int, error) {
 
 	return r0, r1
 }
+func (_m *API) PauseService(_a0 api.SchedulerConfig) (int, error) {
+	ret := _m.Called(_a0)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(api.SchedulerConfig) int); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(api.SchedulerConfig) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) ResumeService(_a0 api.SchedulerConfig) (int, error) {
+	ret := _m.Called(_a0)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(api.SchedulerConfig) int); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(api.SchedulerConfig) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) SetServicePriority(serviceID string, priority string) (*service.Service, error) {
+	ret := _m.Called(serviceID, priority)
+
+	var r0 *service.Service
+	if rf, ok := ret.Get(0).(func(string, string) *service.Service); ok {
+		r0 = rf(serviceID, priority)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(serviceID, priority)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) WaitService(serviceIDs []string, state service.DesiredState, timeout time.Duration, recursive bool) error {
+	ret := _m.Called(serviceIDs, state, timeout, recursive)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]string, service.DesiredState, time.Duration, bool) error); ok {
+		r0 = rf(serviceIDs, state, timeout, recursive)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) GetServiceStateHistory(serviceID string) ([]statehistory.Change, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []statehistory.Change
+	if rf, ok := ret.Get(0).(func(string) []statehistory.Change); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]statehistory.Change)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) ClearEmergencyShutdown(serviceID string) (int, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(serviceID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) AssignIP(_a0 api.IPConfig) error {
 	ret := _m.Called(_a0)
 
@@ -659,6 +1165,48 @@ func (_m *API) AssignIP(_a0 api.IPConfig) error {
 
 	return r0
 }
+func (_m *API) RebalanceIPs(poolID string) ([]addressassignment.Reassignment, error) {
+	ret := _m.Called(poolID)
+
+	var r0 []addressassignment.Reassignment
+	if rf, ok := ret.Get(0).(func(string) []addressassignment.Reassignment); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]addressassignment.Reassignment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) AssignDeploymentIPs(deploymentID string, dryRun bool) ([]addressassignment.PlannedAssignment, error) {
+	ret := _m.Called(deploymentID, dryRun)
+
+	var r0 []addressassignment.PlannedAssignment
+	if rf, ok := ret.Get(0).(func(string, bool) []addressassignment.PlannedAssignment); ok {
+		r0 = rf(deploymentID, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]addressassignment.PlannedAssignment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(deploymentID, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) GetEndpoints(serviceID string, reportImports bool, reportExports bool, validate bool) ([]applicationendpoint.EndpointReport, error) {
 	ret := _m.Called(serviceID, reportImports, reportExports, validate)
 
@@ -680,6 +1228,27 @@ func (_m *API) GetEndpoints(serviceID string, reportImports bool, reportExports
 
 	return r0, r1
 }
+func (_m *API) GetEndpointStats(serviceID string) ([]applicationendpoint.EndpointStats, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []applicationendpoint.EndpointStats
+	if rf, ok := ret.Get(0).(func(string) []applicationendpoint.EndpointStats); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]applicationendpoint.EndpointStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) StartShell(_a0 api.ShellConfig) error {
 	ret := _m.Called(_a0)
 
@@ -711,6 +1280,48 @@ func (_m *API) RunShell(_a0 api.ShellConfig, _a1 chan struct{}) (int, error) {
 
 	return r0, r1
 }
+func (_m *API) RunMigration(_a0 api.MigrationConfig) (*dao.ServiceMigrationRequest, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *dao.ServiceMigrationRequest
+	if rf, ok := ret.Get(0).(func(api.MigrationConfig) *dao.ServiceMigrationRequest); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.ServiceMigrationRequest)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(api.MigrationConfig) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) GetSnapshotService(snapshotID string, serviceID string) (*service.Service, error) {
+	ret := _m.Called(snapshotID, serviceID)
+
+	var r0 *service.Service
+	if rf, ok := ret.Get(0).(func(string, string) *service.Service); ok {
+		r0 = rf(snapshotID, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(snapshotID, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) GetSnapshots() ([]dao.SnapshotInfo, error) {
 	ret := _m.Called()
 
@@ -909,6 +1520,27 @@ func (_m *API) AddServiceTemplate(_a0 io.Reader) (*template.ServiceTemplate, err
 
 	return r0, r1
 }
+func (_m *API) ValidateServiceTemplate(_a0 io.Reader) ([]template.LintIssue, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []template.LintIssue
+	if rf, ok := ret.Get(0).(func(io.Reader) []template.LintIssue); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]template.LintIssue)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(io.Reader) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) RemoveServiceTemplate(_a0 string) error {
 	ret := _m.Called(_a0)
 
@@ -982,26 +1614,268 @@ func (_m *API) Backup(_a0 string, _a1 []string) (string, error) {
 
 	return r0, r1
 }
-func (_m *API) Restore(_a0 string) error {
-	ret := _m.Called(_a0)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(_a0)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-func (_m *API) ResetRegistry() error {
+func (_m *API) ListBackups() ([]backup.Backup, error) {
 	ret := _m.Called()
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
+	var r0 []backup.Backup
+	if rf, ok := ret.Get(0).(func() []backup.Backup); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]backup.Backup)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) EstimateBackup(excludes []string) (*dfs.BackupEstimate, error) {
+	ret := _m.Called(excludes)
+
+	var r0 *dfs.BackupEstimate
+	if rf, ok := ret.Get(0).(func([]string) *dfs.BackupEstimate); ok {
+		r0 = rf(excludes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.BackupEstimate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(excludes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) CleanupFailedRestore(dryRun bool) (*dfs.RestoreQuarantine, error) {
+	ret := _m.Called(dryRun)
+
+	var r0 *dfs.RestoreQuarantine
+	if rf, ok := ret.Get(0).(func(bool) *dfs.RestoreQuarantine); ok {
+		r0 = rf(dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.RestoreQuarantine)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) InspectBackup(path string) (*dfs.BackupInspection, error) {
+	ret := _m.Called(path)
+
+	var r0 *dfs.BackupInspection
+	if rf, ok := ret.Get(0).(func(string) *dfs.BackupInspection); ok {
+		r0 = rf(path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.BackupInspection)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) AddMaintenanceWindow(window maintenance.Window) (string, error) {
+	ret := _m.Called(window)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(maintenance.Window) string); ok {
+		r0 = rf(window)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(maintenance.Window) error); ok {
+		r1 = rf(window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) RemoveMaintenanceWindow(windowID string) error {
+	ret := _m.Called(windowID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(windowID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) ListMaintenanceWindows() ([]maintenance.Window, error) {
+	ret := _m.Called()
+
+	var r0 []maintenance.Window
+	if rf, ok := ret.Get(0).(func() []maintenance.Window); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]maintenance.Window)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) AddScheduleProfile(profile schedule.Profile) (string, error) {
+	ret := _m.Called(profile)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(schedule.Profile) string); ok {
+		r0 = rf(profile)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(schedule.Profile) error); ok {
+		r1 = rf(profile)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) RemoveScheduleProfile(profileID string) error {
+	ret := _m.Called(profileID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(profileID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) ListScheduleProfiles() ([]schedule.Profile, error) {
+	ret := _m.Called()
+
+	var r0 []schedule.Profile
+	if rf, ok := ret.Get(0).(func() []schedule.Profile); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schedule.Profile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) GetUserTenants(userName string) ([]string, error) {
+	ret := _m.Called(userName)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(userName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) SetUserTenants(userName string, tenantIDs []string) error {
+	ret := _m.Called(userName, tenantIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []string) error); ok {
+		r0 = rf(userName, tenantIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) GetDatastoreMigrations() ([]dbmigration.Status, error) {
+	ret := _m.Called()
+
+	var r0 []dbmigration.Status
+	if rf, ok := ret.Get(0).(func() []dbmigration.Status); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dbmigration.Status)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) Restore(_a0 string, _a1 []string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) ResetRegistry() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
 	}
 
 	return r0
@@ -1030,6 +1904,79 @@ func (_m *API) UpgradeRegistry(endpoint string, override bool) error {
 
 	return r0
 }
+func (_m *API) MigrateRegistry(fromVersion int, toVersion int, override bool) error {
+	ret := _m.Called(fromVersion, toVersion, override)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, bool) error); ok {
+		r0 = rf(fromVersion, toVersion, override)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) ListRegistryImages(tenantID string) ([]registry.ImageDetails, error) {
+	ret := _m.Called(tenantID)
+
+	var r0 []registry.ImageDetails
+	if rf, ok := ret.Get(0).(func(string) []registry.ImageDetails); ok {
+		r0 = rf(tenantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]registry.ImageDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) InspectRegistryImage(image string) (*registry.ImageDetails, error) {
+	ret := _m.Called(image)
+
+	var r0 *registry.ImageDetails
+	if rf, ok := ret.Get(0).(func(string) *registry.ImageDetails); ok {
+		r0 = rf(image)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*registry.ImageDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(image)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) PromoteImage(image string, tenantID string, tag string) (string, error) {
+	ret := _m.Called(image, tenantID, tag)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
+		r0 = rf(image, tenantID, tag)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(image, tenantID, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) DockerOverride(newImage string, oldImage string) error {
 	ret := _m.Called(newImage, oldImage)
 
@@ -1054,6 +2001,39 @@ func (_m *API) ExportLogs(config api.ExportLogsConfig) error {
 
 	return r0
 }
+func (_m *API) SetLogLevel(hostID string, component string, level string) error {
+	ret := _m.Called(hostID, component, level)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(hostID, component, level)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) GetLogLevels(hostID string) (map[string]string, error) {
+	ret := _m.Called(hostID)
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func(string) map[string]string); ok {
+		r0 = rf(hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) PostMetric(metricName string, metricValue string) (string, error) {
 	ret := _m.Called(metricName, metricValue)
 
@@ -1119,6 +2099,72 @@ func (_m *API) GetVolumeStatus() (*volume.Statuses, error) {
 
 	return r0, r1
 }
+func (_m *API) GetDFSStats() (*dfs.Stats, error) {
+	ret := _m.Called()
+
+	var r0 *dfs.Stats
+	if rf, ok := ret.Get(0).(func() *dfs.Stats); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.Stats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) FreezeDFS(reason string) error {
+	ret := _m.Called(reason)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) ThawDFS() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *API) GetDFSFreezeStatus() (*storage.FreezeStatus, error) {
+	ret := _m.Called()
+
+	var r0 *storage.FreezeStatus
+	if rf, ok := ret.Get(0).(func() *storage.FreezeStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.FreezeStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) AddPublicEndpointPort(serviceid string, endpointName string, portAddr string, usetls bool, protocol string, isEnabled bool, restart bool) (*servicedefinition.Port, error) {
 	ret := _m.Called(serviceid, endpointName, portAddr, usetls, protocol, isEnabled, restart)
 
@@ -1230,6 +2276,48 @@ func (_m *API) GetServiceInstances(serviceID string) ([]service.Instance, error)
 
 	return r0, r1
 }
+func (_m *API) WaitServiceStatus(serviceIDs []string, timeout time.Duration) ([]string, error) {
+	ret := _m.Called(serviceIDs, timeout)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func([]string, time.Duration) []string); ok {
+		r0 = rf(serviceIDs, timeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, time.Duration) error); ok {
+		r1 = rf(serviceIDs, timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) GetDeploymentStatus(deploymentID string) (*service.DeploymentStatus, error) {
+	ret := _m.Called(deploymentID)
+
+	var r0 *service.DeploymentStatus
+	if rf, ok := ret.Get(0).(func(string) *service.DeploymentStatus); ok {
+		r0 = rf(deploymentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.DeploymentStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(deploymentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) StopServiceInstance(serviceID string, instanceID int) error {
 	ret := _m.Called(serviceID, instanceID)
 
@@ -1254,6 +2342,39 @@ func (_m *API) AttachServiceInstance(serviceID string, instanceID int, command s
 
 	return r0
 }
+
+// CommitServiceInstance provides a mock function with given fields: serviceID, instanceID, message
+func (_m *API) CommitServiceInstance(serviceID string, instanceID int, message string) (string, error) {
+	ret := _m.Called(serviceID, instanceID, message)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, int, string) string); ok {
+		r0 = rf(serviceID, instanceID, message)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int, string) error); ok {
+		r1 = rf(serviceID, instanceID, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) FollowServiceLogs(cfg api.ServiceLogsConfig, w io.Writer) error {
+	ret := _m.Called(cfg, w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(api.ServiceLogsConfig, io.Writer) error); ok {
+		r0 = rf(cfg, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
 func (_m *API) LogsForServiceInstance(serviceID string, instanceID int, command string, args []string) error {
 	ret := _m.Called(serviceID, instanceID, command, args)
 
@@ -1266,6 +2387,48 @@ func (_m *API) LogsForServiceInstance(serviceID string, instanceID int, command
 
 	return r0
 }
+func (_m *API) GetInstanceDiagnostics(serviceID string, instanceID int) ([]service.DiagnosticOutput, error) {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 []service.DiagnosticOutput
+	if rf, ok := ret.Get(0).(func(string, int) []service.DiagnosticOutput); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.DiagnosticOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(serviceID, instanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) GetPreviousInstanceOutput(serviceID string, instanceID int) (*service.InstanceOutput, error) {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 *service.InstanceOutput
+	if rf, ok := ret.Get(0).(func(string, int) *service.InstanceOutput); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.InstanceOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(serviceID, instanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *API) SendDockerAction(serviceID string, instanceID int, action string, args []string) error {
 	ret := _m.Called(serviceID, instanceID, action, args)
 
@@ -1278,3 +2441,45 @@ func (_m *API) SendDockerAction(serviceID string, instanceID int, action string,
 
 	return r0
 }
+func (_m *API) CheckConsistency(repair bool) (*zkservice.ConsistencyReport, error) {
+	ret := _m.Called(repair)
+
+	var r0 *zkservice.ConsistencyReport
+	if rf, ok := ret.Get(0).(func(bool) *zkservice.ConsistencyReport); ok {
+		r0 = rf(repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*zkservice.ConsistencyReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *API) CheckNetwork(poolID string) (*host.NetworkReport, error) {
+	ret := _m.Called(poolID)
+
+	var r0 *host.NetworkReport
+	if rf, ok := ret.Get(0).(func(string) *host.NetworkReport); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.NetworkReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
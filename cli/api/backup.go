@@ -19,6 +19,8 @@ import (
 
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backup"
 )
 
 // Dump all templates and services to a tgz file.
@@ -43,9 +45,47 @@ func (a *api) Backup(dirpath string, excludes []string) (string, error) {
 	return path, nil
 }
 
+// ListBackups returns the catalog of completed backups
+func (a *api) ListBackups() ([]backup.Backup, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.ListBackups()
+}
+
+// EstimateBackup returns the estimated size and duration of a backup
+func (a *api) EstimateBackup(excludes []string) (*dfs.BackupEstimate, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.EstimateBackup(excludes)
+}
+
+// CleanupFailedRestore removes the data imported by restores that did not
+// complete
+func (a *api) CleanupFailedRestore(dryRun bool) (*dfs.RestoreQuarantine, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.CleanupFailedRestore(dryRun)
+}
+
+// InspectBackup reports the version of a backup file and what this binary
+// can restore from it
+func (a *api) InspectBackup(path string) (*dfs.BackupInspection, error) {
+	info, err := dfs.ExtractBackupInfo(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read backup %s: %s", path, err)
+	}
+	return dfs.InspectBackup(*info), nil
+}
+
 // Restores templates, services, snapshots, and docker images from a tgz file.
 // This is the inverse of CmdBackup.
-func (a *api) Restore(path string) error {
+func (a *api) Restore(path string, force []string) error {
 	client, err := a.connectDAO()
 	if err != nil {
 		return err
@@ -56,5 +96,9 @@ func (a *api) Restore(path string) error {
 		return fmt.Errorf("could not convert '%s' to an absolute file path: %v", path, err)
 	}
 
-	return client.Restore(filepath.Clean(fp), &unusedInt)
+	req := dao.RestoreRequest{
+		Filename: filepath.Clean(fp),
+		Force:    force,
+	}
+	return client.Restore(req, &unusedInt)
 }
@@ -0,0 +1,50 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+)
+
+// EnableChaos starts killing random service instances in a pool
+func (a *api) EnableChaos(poolID string, interval time.Duration) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.EnableChaos(poolID, interval)
+}
+
+// DisableChaos stops killing service instances in a pool
+func (a *api) DisableChaos(poolID string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.DisableChaos(poolID)
+}
+
+// GetChaosStatus returns the chaos mode settings and kill history of a pool
+func (a *api) GetChaosStatus(poolID string) (*service.ChaosStatus, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetChaosStatus(poolID)
+}
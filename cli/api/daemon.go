@@ -32,11 +32,16 @@ import (
 	"github.com/control-center/serviced/dfs/nfs"
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/backup"
+	"github.com/control-center/serviced/domain/dbmigration"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/maintenance"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/schedule"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/statehistory"
 	"github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/health"
@@ -51,6 +56,7 @@ import (
 	"github.com/control-center/serviced/scheduler"
 	"github.com/control-center/serviced/shell"
 	"github.com/control-center/serviced/stats"
+	"github.com/control-center/serviced/tracing"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/validation"
 	"github.com/control-center/serviced/volume"
@@ -58,9 +64,12 @@ import (
 
 	"github.com/control-center/serviced/web"
 	"github.com/control-center/serviced/zzk"
+	"github.com/control-center/serviced/zzk/ensemble"
+	"github.com/control-center/serviced/zzk/ha"
 
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -68,12 +77,15 @@ import (
 	"net/http"
 	"net/rpc"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -106,6 +118,7 @@ type daemon struct {
 	waitGroup        *sync.WaitGroup
 	rpcServer        *rpc.Server
 	tokenExpiration  time.Duration
+	started          int32 // set once the master has started
 
 	facade *facade.Facade
 	hcache *health.HealthStatusCache
@@ -161,13 +174,21 @@ func (d *daemon) getEsClusterName(name string) string {
 
 func (d *daemon) startISVCS() {
 	options := config.GetOptions()
+	if err := initExternalISVCS(&options); err != nil {
+		log.WithError(err).Fatal("Unable to configure external internal services")
+	}
+	if err := isvcs.WaitForExternal(time.Duration(options.ESStartupTimeout) * time.Second); err != nil {
+		log.WithError(err).Fatal("Unable to reach external internal services")
+	}
 	isvcs.Init(options.ESStartupTimeout, options.DockerLogDriver, convertStringSliceToMap(options.DockerLogConfigList), d.docker)
 	isvcs.Mgr.SetVolumesDir(options.IsvcsPath)
-	servicedClusterName := d.getEsClusterName("elasticsearch-serviced")
-	if err := isvcs.Mgr.SetConfigurationOption("elasticsearch-serviced", "cluster", servicedClusterName); err != nil {
-		log.WithFields(logrus.Fields{
-			"clustername": servicedClusterName,
-		}).WithError(err).Fatal("Could not set Elastic configuration")
+	if !isvcs.IsExternal("elasticsearch-serviced") {
+		servicedClusterName := d.getEsClusterName("elasticsearch-serviced")
+		if err := isvcs.Mgr.SetConfigurationOption("elasticsearch-serviced", "cluster", servicedClusterName); err != nil {
+			log.WithFields(logrus.Fields{
+				"clustername": servicedClusterName,
+			}).WithError(err).Fatal("Could not set Elastic configuration")
+		}
 	}
 	logstashClusterName := d.getEsClusterName("elasticsearch-logstash")
 	if err := isvcs.Mgr.SetConfigurationOption("elasticsearch-logstash", "cluster", logstashClusterName); err != nil {
@@ -194,6 +215,10 @@ func (d *daemon) startAgentISVCS(serviceNames []string) {
 }
 
 func (d *daemon) stopISVCS() {
+	if isvcs.Mgr == nil {
+		// a standby master has not started any internal services
+		return
+	}
 	log.Debug("Beginning shutdown of internal services")
 	if err := isvcs.Mgr.Stop(); err != nil {
 		log.WithError(err).Error("Error while stopping internal services")
@@ -247,6 +272,7 @@ func (d *daemon) startRPC() {
 
 	rpcutils.SetDialTimeout(options.RPCDialTimeout)
 	d.rpcServer.HandleHTTP(rpc.DefaultRPCPath, rpc.DefaultDebugPath)
+	slowThreshold := time.Duration(options.RPCSlowCallThreshold) * time.Millisecond
 
 	logger.Info("Listening for incoming RPC requests")
 
@@ -256,7 +282,16 @@ func (d *daemon) startRPC() {
 			if err != nil {
 				logger.WithError(err).Fatal("Error accepting RPC connection")
 			}
-			go d.rpcServer.ServeCodec(rpcutils.NewDefaultAuthServerCodec(conn))
+			codec := rpcutils.NewDefaultAuthServerCodec(conn)
+			codec = rpcutils.NewContextServerCodec(codec)
+			codec = rpcutils.NewStatsServerCodec(codec, conn.RemoteAddr().String(), slowThreshold)
+			if tracing.Enabled() {
+				codec = rpcutils.NewTracingServerCodec(codec)
+			}
+			if options.MasterReplica {
+				codec = master.NewReplicaServerCodec(codec)
+			}
+			go d.rpcServer.ServeCodec(codec)
 		}
 	}()
 }
@@ -297,28 +332,33 @@ func (d *daemon) run() (err error) {
 	// Set up the Docker registry
 	d.reg = registry.NewRegistryListener(d.docker, options.DockerRegistry, d.hostID)
 
-	// Initialize the application storage
-	storagelogger := log.WithFields(logrus.Fields{
-		"driver":  options.FSType,
-		"path":    options.VolumesPath,
-		"args":    options.StorageArgs,
-		"options": options.StorageOptions,
-	})
-	storagelogger.Debug("Initializing application storage")
-	if !volume.Registered(options.FSType) {
-		storagelogger.Fatal("Invalid storage driver")
-	}
-	if !filepath.IsAbs(options.VolumesPath) {
-		storagelogger.Fatal("Volume path is not absolute")
-	}
-	if err := volume.InitDriver(options.FSType, options.VolumesPath, options.StorageArgs); err != nil {
-		storagelogger.WithError(err).Fatal("Unable to initialize application storage")
+	// Initialize the application storage.  If it lives on the shared storage
+	// device, a standby master waits until it has mounted the device at
+	// takeover.
+	sharedStorage := options.Master && options.MasterHA && storageOnSharedDevice()
+	if !sharedStorage {
+		d.initStorage()
 	}
-	storagelogger.Info("Initialized application storage")
 
 	// Start the RPC server
+	d.startTracing()
+	if options.MasterReplica {
+		d.startMasterReplica()
+	}
 	d.startRPC()
 
+	// Use the ZooKeeper ensemble last recorded by this host, if any
+	configuredZookeepers := options.Zookeepers
+	ensembleFile := filepath.Join(options.EtcPath, ensemble.ClientFileName)
+	if clients, err := ensemble.ReadClientFile(ensembleFile, configuredZookeepers); err == nil && len(clients) > 0 {
+		log.WithFields(logrus.Fields{
+			"configured": configuredZookeepers,
+			"ensemble":   clients,
+		}).Info("Using the recorded ZooKeeper ensemble")
+		options.Zookeepers = clients
+		config.LoadOptions(options)
+	}
+
 	//Start the zookeeper client
 	localClient, err := d.initZK(options.Zookeepers)
 	if err != nil {
@@ -328,8 +368,12 @@ func (d *daemon) run() (err error) {
 	}
 	zzk.InitializeLocalClient(localClient)
 	log.Info("Established ZooKeeper connection")
+	go zzk.RunWatchdog(d.shutdown, time.Minute)
+	d.startZKEnsemble(configuredZookeepers)
 
-	if options.Master {
+	if options.Master && options.MasterHA {
+		d.startMasterHA()
+	} else if options.Master {
 		d.startISVCS()
 		if err := d.startMaster(); err != nil {
 			log.WithError(err).Fatal("Unable to start as a serviced master")
@@ -338,7 +382,7 @@ func (d *daemon) run() (err error) {
 		d.startAgentISVCS(options.StartISVCS)
 	}
 
-	if options.Agent {
+	if options.Agent && !sharedStorage {
 		if err := d.startAgent(); err != nil {
 			log.WithError(err).Fatal("Unable to start as a serviced delegate")
 		}
@@ -387,6 +431,67 @@ func (d *daemon) run() (err error) {
 	return nil
 }
 
+// initStorage initializes the application storage driver
+func (d *daemon) initStorage() {
+	options := config.GetOptions()
+	storagelogger := log.WithFields(logrus.Fields{
+		"driver":  options.FSType,
+		"path":    options.VolumesPath,
+		"args":    options.StorageArgs,
+		"options": options.StorageOptions,
+	})
+	storagelogger.Debug("Initializing application storage")
+	if !volume.Registered(options.FSType) {
+		storagelogger.Fatal("Invalid storage driver")
+	}
+	if !filepath.IsAbs(options.VolumesPath) {
+		storagelogger.Fatal("Volume path is not absolute")
+	}
+	if err := volume.InitDriver(options.FSType, options.VolumesPath, options.StorageArgs); err != nil {
+		storagelogger.WithError(err).Fatal("Unable to initialize application storage")
+	}
+	storagelogger.Info("Initialized application storage")
+}
+
+// storageOnSharedDevice returns true if the application storage is on the
+// shared storage device that the active master mounts.
+func storageOnSharedDevice() bool {
+	options := config.GetOptions()
+	if options.MasterHADevice == "" {
+		return false
+	}
+	rel, err := filepath.Rel(options.MasterHAMountPath, options.VolumesPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// startZKEnsemble keeps this host's record of the managed ZooKeeper ensemble
+// up to date.  A master also seeds the ensemble from the isvcs zookeeper
+// quorum if it has not been configured.
+func (d *daemon) startZKEnsemble(configured []string) {
+	options := config.GetOptions()
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		log.WithError(err).Warn("Unable to watch the ZooKeeper ensemble")
+		return
+	}
+
+	if options.Master {
+		servers := make([]ensemble.Server, len(options.IsvcsZKQuorum))
+		for i, member := range options.IsvcsZKQuorum {
+			if servers[i], err = ensemble.ParseServer(member); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			log.WithError(err).Warn("Unable to seed the ZooKeeper ensemble from the isvcs zookeeper quorum")
+		} else if err := ensemble.Initialize(conn, servers); err != nil {
+			log.WithError(err).Warn("Unable to seed the ZooKeeper ensemble")
+		}
+	}
+
+	go ensemble.WatchEnsemble(d.shutdown, conn, filepath.Join(options.EtcPath, ensemble.ClientFileName), configured)
+}
+
 func (d *daemon) initContext() datastore.Context {
 	log.Debug("Acquiring application context from Elastic")
 	datastore.Register(d.dsDriver)
@@ -400,9 +505,10 @@ func (d *daemon) initContext() datastore.Context {
 func (d *daemon) initZK(zks []string) (*coordclient.Client, error) {
 	options := config.GetOptions()
 	coordzk.RegisterZKLogger()
-	dsn := coordzk.NewDSN(zks, time.Duration(options.ZKSessionTimeout)*time.Second).String()
+	dsnVal := coordzk.NewDSN(zks, time.Duration(options.ZKSessionTimeout)*time.Second)
+	dsnVal.Auth = options.ZKAuth
+	dsn := dsnVal.String()
 	log.WithFields(logrus.Fields{
-		"dsn":            dsn,
 		"sessiontimeout": options.ZKSessionTimeout,
 		"ensemble":       zks,
 	}).Debug("Establishing connection to ZooKeeper")
@@ -489,6 +595,10 @@ func (d *daemon) startMaster() (err error) {
 	d.facade = d.initFacade()
 	d.cpDao = d.initDAO()
 
+	if err = d.facade.MigrateDatastore(d.dsContext); err != nil {
+		log.WithError(err).Fatal("Unable to apply datastore migrations")
+	}
+
 	if err = d.facade.CreateDefaultPool(d.dsContext, d.masterPoolID); err != nil {
 		log.WithError(err).Fatal("Unable to create default pool")
 	}
@@ -508,13 +618,162 @@ func (d *daemon) startMaster() (err error) {
 
 	d.initWeb()
 	d.addTemplates()
+	d.checkConsistency(options.RepairConsistency)
 	d.startScheduler()
+	go d.startEmergencyMonitor(time.Minute)
+	go d.startLostHostMonitor(time.Minute, facade.DefaultLostHostTimeout)
+	go d.startMaintenanceSync(5 * time.Minute)
+	go d.startScheduleProfiles(time.Minute)
 
+	atomic.StoreInt32(&d.started, 1)
 	log.Info("Started serviced master")
 
 	return nil
 }
 
+// startMasterHA waits for this master to take over as the active master
+// before starting the internal services and the master's listeners.
+func (d *daemon) startMasterHA() {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		log.WithError(err).Fatal("Unable to connect to ZooKeeper for master failover")
+	}
+	lease := time.Duration(config.GetOptions().ZKSessionTimeout) * time.Second
+	mgr := ha.NewManager(d.hostID, lease, &masterHAHandler{d})
+	go func() {
+		if err := mgr.Run(d.shutdown, conn); err == ha.ErrLostLead {
+			// the internal services are stopped and the shared storage is
+			// unmounted, so exit to come back as a standby.
+			log.Fatal("Lost the lead as the active master")
+		} else if err != nil {
+			log.WithError(err).Fatal("Unable to fail over internal services to this master")
+		}
+	}()
+	log.Info("Started serviced master in standby")
+}
+
+// startMasterReplica registers the read replica of the master, which answers
+// reads from a cache and forwards all other master requests to the master.
+func (d *daemon) startMasterReplica() {
+	options := config.GetOptions()
+	staleness := time.Duration(options.ReplicaMaxStaleness) * time.Second
+	replica, err := master.NewReplicaServer(options.Endpoint, staleness)
+	if err != nil {
+		log.WithError(err).Fatal("Unable to connect the read replica to the master")
+	}
+	if err := d.rpcServer.RegisterName("Replica", replica); err != nil {
+		log.WithError(err).Fatal("Unable to register the read replica")
+	}
+	go replica.Run(d.shutdown)
+	log.WithFields(logrus.Fields{
+		"master":       options.Endpoint,
+		"maxstaleness": staleness,
+	}).Info("Started serviced master read replica")
+}
+
+// masterHAHandler brings this master online when it takes over as the active
+// master
+type masterHAHandler struct {
+	d *daemon
+}
+
+// MountStorage mounts the shared storage device, if one is configured, and
+// initializes the application storage if it is on that device
+func (h *masterHAHandler) MountStorage() error {
+	options := config.GetOptions()
+	if options.MasterHADevice == "" {
+		return nil
+	}
+	if err := mountSharedStorage(); err != nil {
+		return err
+	}
+	if storageOnSharedDevice() {
+		h.d.initStorage()
+	}
+	return nil
+}
+
+// mountSharedStorage mounts the shared storage device at the master HA mount
+// path
+func mountSharedStorage() error {
+	options := config.GetOptions()
+	logger := log.WithFields(logrus.Fields{
+		"device": options.MasterHADevice,
+		"path":   options.MasterHAMountPath,
+	})
+	if mounted, err := utils.GetDefaultMountProc().IsMounted(options.MasterHAMountPath); err != nil {
+		return err
+	} else if mounted {
+		logger.Info("Shared storage is already mounted")
+		return nil
+	}
+	if err := os.MkdirAll(options.MasterHAMountPath, 0755); err != nil {
+		return err
+	}
+	if output, err := exec.Command("mount", options.MasterHADevice, options.MasterHAMountPath).CombinedOutput(); err != nil {
+		return fmt.Errorf("could not mount %s on %s: %s (%s)", options.MasterHADevice, options.MasterHAMountPath, err, strings.TrimSpace(string(output)))
+	}
+	logger.Info("Mounted shared storage")
+	return nil
+}
+
+// StartServices starts the internal services
+func (h *masterHAHandler) StartServices() error {
+	h.d.startISVCS()
+	return nil
+}
+
+// StopServices stops the internal services
+func (h *masterHAHandler) StopServices() error {
+	h.d.stopISVCS()
+	return nil
+}
+
+// UnmountStorage unmounts the shared storage device, if one is configured
+func (h *masterHAHandler) UnmountStorage() error {
+	if config.GetOptions().MasterHADevice == "" {
+		return nil
+	}
+	return unmountSharedStorage()
+}
+
+// unmountSharedStorage unmounts the shared storage device from the master HA
+// mount path.  The master is about to exit, so if the storage is still busy
+// it is detached lazily and released as soon as the master's files close.
+func unmountSharedStorage() error {
+	options := config.GetOptions()
+	logger := log.WithFields(logrus.Fields{
+		"device": options.MasterHADevice,
+		"path":   options.MasterHAMountPath,
+	})
+	if mounted, err := utils.GetDefaultMountProc().IsMounted(options.MasterHAMountPath); err != nil {
+		return err
+	} else if !mounted {
+		logger.Info("Shared storage is not mounted")
+		return nil
+	}
+	if output, err := exec.Command("umount", options.MasterHAMountPath).CombinedOutput(); err != nil {
+		logger.WithError(err).WithField("output", strings.TrimSpace(string(output))).Warn("Could not unmount shared storage; detaching it lazily")
+		if output, err := exec.Command("umount", "-l", options.MasterHAMountPath).CombinedOutput(); err != nil {
+			return fmt.Errorf("could not unmount %s: %s (%s)", options.MasterHAMountPath, err, strings.TrimSpace(string(output)))
+		}
+	}
+	logger.Info("Unmounted shared storage")
+	return nil
+}
+
+// ResumeListeners starts the master's rpc, web, and scheduler listeners, and
+// the delegate if it was waiting on the shared storage
+func (h *masterHAHandler) ResumeListeners() error {
+	if err := h.d.startMaster(); err != nil {
+		return err
+	}
+	if options := config.GetOptions(); options.Agent && storageOnSharedDevice() {
+		return h.d.startAgent()
+	}
+	return nil
+}
+
 func getKeyPairs(certPEMFile, keyPEMFile string) (certPEM, keyPEM []byte, err error) {
 	if len(certPEMFile) > 0 {
 		certPEM, err = ioutil.ReadFile(certPEMFile)
@@ -812,8 +1071,18 @@ func (d *daemon) startAgent() error {
 			DockerLogDriver:      options.DockerLogDriver,
 			DockerLogConfig:      convertStringSliceToMap(options.DockerLogConfigList),
 			ZKSessionTimeout:     options.ZKSessionTimeout,
+			ZKAuth:               options.ZKAuth,
 			DelegateKeyFile:      delegateKeyFile,
 			TokenFile:            tokenFile,
+			Heartbeat:            getHeartbeatConfig(options),
+			OutputPath:           options.ContainerOutputPath,
+			OutputSize:           int64(options.ContainerOutputSize) * 1024,
+		}
+		if options.PreserveInstances {
+			agentOptions.PreserveTimeout = time.Duration(options.PreserveInstancesTimeout) * time.Second
+		}
+		if options.OfflineInstances {
+			agentOptions.InstanceCachePath = options.InstanceCachePath
 		}
 		// creates a zClient that is not pool based!
 		hostAgent, err := node.NewHostAgent(agentOptions, d.reg)
@@ -859,6 +1128,13 @@ func (d *daemon) startAgent() error {
 	}()
 
 	agentServer := agent.NewServer(d.staticIPs)
+	agentServer.SetMuxTLS(!options.MuxDisableTLS)
+	agentServer.SetContainerOutputPath(options.ContainerOutputPath)
+	agentServer.SetSelfTestConfig(selfTestConfig(d.hostID, options))
+	agentServer.SetUpgradeCommand(options.UpgradeCommand, func() {
+		// restart through the SIGHUP handler so the new binary is exec'd
+		syscall.Kill(os.Getpid(), syscall.SIGHUP)
+	})
 	if err = d.rpcServer.RegisterName("Agent", agentServer); err != nil {
 		log.WithError(err).Fatal("Unable to register Agent RPC service")
 	}
@@ -883,7 +1159,7 @@ func (d *daemon) registerMasterRPC() error {
 	log.Debug("Registering master RPC services")
 	options := config.GetOptions()
 
-	server := master.NewServer(d.facade, d.tokenExpiration)
+	server := master.NewServer(d.facade, d.tokenExpiration, time.Duration(options.RPCCallTimeout)*time.Second)
 	disableLocal := os.Getenv("DISABLE_RPC_BYPASS")
 	if disableLocal == "" {
 		rpcutils.RegisterLocalAddress(options.Endpoint, fmt.Sprintf("localhost:%s", options.RPCPort),
@@ -909,12 +1185,22 @@ func (d *daemon) registerMasterRPC() error {
 }
 
 func (d *daemon) initDriver() datastore.Driver {
+	ep := d.getElasticEndpoint()
 	log := log.WithFields(logrus.Fields{
-		"address": "localhost:9200",
+		"address": ep.String(),
 		"index":   "controlplane",
 	})
 	log.Debug("Establishing connection with Elastic")
-	eDriver := elastic.New("localhost", 9200, "controlplane")
+	var eDriver elastic.ElasticDriver
+	if options := config.GetOptions(); options.ESURL != "" {
+		tlsConfig, err := getExternalTLSConfig(&options)
+		if err != nil {
+			log.WithError(err).Fatal("Unable to load external Elastic certificates")
+		}
+		eDriver = elastic.NewWithEndpoint(ep, "controlplane", tlsConfig)
+	} else {
+		eDriver = elastic.New(ep.Host, ep.Port, "controlplane")
+	}
 	eDriver.AddMapping(host.MAPPING)
 	eDriver.AddMapping(pool.MAPPING)
 	eDriver.AddMapping(servicetemplate.MAPPING)
@@ -922,6 +1208,11 @@ func (d *daemon) initDriver() datastore.Driver {
 	eDriver.AddMapping(addressassignment.MAPPING)
 	eDriver.AddMapping(serviceconfigfile.MAPPING)
 	eDriver.AddMapping(user.MAPPING)
+	eDriver.AddMapping(backup.MAPPING)
+	eDriver.AddMapping(dbmigration.MAPPING)
+	eDriver.AddMapping(maintenance.MAPPING)
+	eDriver.AddMapping(schedule.MAPPING)
+	eDriver.AddMapping(statehistory.MAPPING)
 	err := eDriver.Initialize(10 * time.Second)
 	if err != nil {
 		log.WithError(err).Fatal("Unable to establish connection to Elastic database")
@@ -929,6 +1220,19 @@ func (d *daemon) initDriver() datastore.Driver {
 	return eDriver
 }
 
+// getElasticEndpoint returns the endpoint of the controlplane datastore
+func (d *daemon) getElasticEndpoint() elastic.Endpoint {
+	options := config.GetOptions()
+	if options.ESURL == "" {
+		return elastic.Endpoint{Scheme: "http", Host: "localhost", Port: 9200}
+	}
+	ep, err := elastic.ParseEndpoint(options.ESURL)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid Elastic url")
+	}
+	return ep
+}
+
 func initMetricsClient() *metrics.Client {
 	addr := fmt.Sprintf("http://%s:8888", localhost)
 	log := log.WithFields(logrus.Fields{
@@ -949,8 +1253,30 @@ func (d *daemon) initFacade() *facade.Facade {
 	index := registry.NewRegistryIndexClient(f)
 	dfs := dfs.NewDistributedFilesystem(d.docker, index, d.reg, d.disk, d.net, time.Duration(options.MaxDFSTimeout)*time.Second)
 	dfs.SetTmp(os.Getenv("TMP"))
+	dfs.SetQuarantinePath(filepath.Join(options.VolumesPath, ".restore-quarantine.json"))
+	dfs.SetIOLimits(int64(options.BackupIOLimit)*1024*1024, int64(options.RestoreIOLimit)*1024*1024)
 	f.SetDFS(dfs)
+	f.SetDelegateClient(agent.Delegates{})
 	f.SetIsvcsPath(options.IsvcsPath)
+	f.SetAllowChaos(options.AllowChaos)
+	f.SetRollbackWindow(time.Duration(options.RollbackWindow) * time.Second)
+	f.SetSnapshotSpacePercent(options.SnapshotSpacePercent)
+	f.SetMaxClockSkew(time.Duration(options.MaxClockSkew) * time.Second)
+	f.SetStorageThresholds(options.StorageWarningPercent, options.StorageCriticalPercent)
+	minFreeDFS, minFreeThinPool, _ := getEmergencyThresholds(options)
+	f.SetEmergencyThresholds(minFreeDFS, minFreeThinPool)
+	var targets []host.NetworkTarget
+	masterIP := options.OutboundIP
+	if masterIP == "" {
+		var err error
+		if masterIP, err = utils.GetIPAddress(); err != nil {
+			log.WithError(err).Warn("Unable to determine outbound IP address; network checks will not probe the master")
+		}
+	}
+	if masterIP != "" {
+		targets = masterNetworkTargets(masterIP, options)
+	}
+	f.SetNetworkTargets(options.MuxPort, targets)
 	d.hcache = health.New()
 	d.hcache.SetPurgeFrequency(5 * time.Second)
 	f.SetHealthCache(d.hcache)
@@ -965,6 +1291,75 @@ func (d *daemon) initFacade() *facade.Facade {
 	return f
 }
 
+// masterNetworkTargets returns the ports on the master that every delegate
+// must reach.  ZooKeeper servers configured by a loopback address are
+// reached at the master's address.
+func masterNetworkTargets(masterIP string, options config.Options) []host.NetworkTarget {
+	targets := []host.NetworkTarget{
+		{Name: "rpc", Address: net.JoinHostPort(masterIP, options.RPCPort)},
+		{Name: "nfs", Address: net.JoinHostPort(masterIP, "2049")},
+	}
+	servers := options.Zookeepers
+	if len(servers) == 0 {
+		servers = []string{"127.0.0.1:2181"}
+	}
+	for _, server := range servers {
+		h, port, err := net.SplitHostPort(server)
+		if err != nil {
+			continue
+		}
+		if ip := net.ParseIP(h); h == "localhost" || (ip != nil && ip.IsLoopback()) {
+			server = net.JoinHostPort(masterIP, port)
+		}
+		targets = append(targets, host.NetworkTarget{Name: "zookeeper", Address: server})
+	}
+	return targets
+}
+
+// checkConsistency reports the differences between the datastore and
+// zookeeper left behind by the last run of the master, before the scheduler
+// starts reconciling them.
+func (d *daemon) checkConsistency(repair bool) {
+	report, err := d.facade.CheckConsistency(d.dsContext, repair)
+	if err != nil {
+		log.WithError(err).Warn("Unable to check the consistency of the datastore and zookeeper")
+		return
+	}
+	for _, s := range report.OrphanedServices {
+		log.WithFields(logrus.Fields{
+			"serviceid":   s.ServiceID,
+			"servicename": s.Name,
+			"poolid":      s.PoolID,
+		}).Warn("Service is scheduled in zookeeper but not in the datastore")
+	}
+	for _, s := range report.DesiredStateMismatches {
+		log.WithFields(logrus.Fields{
+			"serviceid":      s.ServiceID,
+			"servicename":    s.Name,
+			"poolid":         s.PoolID,
+			"desiredstate":   s.DesiredState,
+			"zkdesiredstate": s.ZKDesiredState,
+		}).Warn("Desired state of service differs between the datastore and zookeeper")
+	}
+	for _, h := range report.DatastoreOnlyHosts {
+		log.WithFields(logrus.Fields{
+			"hostid": h.HostID,
+			"poolid": h.PoolID,
+		}).Warn("Host is in the datastore but not in zookeeper")
+	}
+	for _, h := range report.ZKOnlyHosts {
+		log.WithFields(logrus.Fields{
+			"hostid": h.HostID,
+			"poolid": h.PoolID,
+		}).Warn("Host is in zookeeper but not in the datastore")
+	}
+	if report.Repaired {
+		log.Info("Repaired the differences between the datastore and zookeeper")
+	} else if !report.Consistent() {
+		log.Warn("Run serviced check consistency --repair to repair the differences between the datastore and zookeeper")
+	}
+}
+
 // startLogstashPurger purges logstash based on days and size
 func (d *daemon) startLogstashPurger(initialStart, cycleTime time.Duration) {
 	options := config.GetOptions()
@@ -984,6 +1379,69 @@ func (d *daemon) startLogstashPurger(initialStart, cycleTime time.Duration) {
 	}
 }
 
+// startEmergencyMonitor periodically stops the services affected by storage
+// that is below its minimum free space
+func (d *daemon) startEmergencyMonitor(cycleTime time.Duration) {
+	for {
+		select {
+		case <-d.shutdown:
+			return
+		case <-time.After(cycleTime):
+		}
+		if count, err := d.facade.CheckStorageEmergency(d.dsContext); err != nil {
+			log.WithError(err).Warn("Unable to check storage for an emergency shutdown")
+		} else if count > 0 {
+			log.WithField("services", count).Error("Emergency stopped services because storage is low")
+		}
+	}
+}
+
+// startLostHostMonitor periodically moves the address assignments of hosts
+// that have been disconnected for longer than the timeout to other hosts
+func (d *daemon) startLostHostMonitor(cycleTime, timeout time.Duration) {
+	for {
+		select {
+		case <-d.shutdown:
+			return
+		case <-time.After(cycleTime):
+		}
+		if _, err := d.facade.ReassignLostHostIPs(d.dsContext, timeout); err != nil {
+			log.WithError(err).Warn("Unable to reassign address assignments from lost hosts")
+		}
+	}
+}
+
+// startMaintenanceSync publishes the maintenance windows to the resource pools
+// on startup and periodically afterwards, so that services added to a
+// deployment or service under maintenance are covered by its window.
+func (d *daemon) startMaintenanceSync(cycleTime time.Duration) {
+	for {
+		if err := d.facade.SyncMaintenanceWindows(d.dsContext); err != nil {
+			log.WithError(err).Warn("Unable to publish maintenance windows")
+		}
+		select {
+		case <-d.shutdown:
+			return
+		case <-time.After(cycleTime):
+		}
+	}
+}
+
+// startScheduleProfiles starts and stops the services of the schedule
+// profiles as their windows open and close.
+func (d *daemon) startScheduleProfiles(cycleTime time.Duration) {
+	for {
+		if err := d.facade.ApplyScheduleProfiles(d.dsContext); err != nil {
+			log.WithError(err).Warn("Unable to apply schedule profiles")
+		}
+		select {
+		case <-d.shutdown:
+			return
+		case <-time.After(cycleTime):
+		}
+	}
+}
+
 // FIXME: The dao package is deprecated and should be removed.
 func (d *daemon) initDAO() dao.ControlPlane {
 	options := config.GetOptions()
@@ -997,7 +1455,8 @@ func (d *daemon) initDAO() dao.ControlPlane {
 			"backupspath": options.BackupsPath,
 		}).WithError(err).Fatal("Unable to create backup path")
 	}
-	cp, err := elasticsearch.NewControlSvc("localhost", 9200, d.facade, options.BackupsPath, rpcPortInt)
+	ep := d.getElasticEndpoint()
+	cp, err := elasticsearch.NewControlSvc(ep.String(), d.facade, options.BackupsPath, rpcPortInt, time.Duration(options.RPCCallTimeout)*time.Second)
 	if err != nil {
 		log.WithError(err).Fatal("Unable to initialize DAO layer")
 	}
@@ -1026,6 +1485,10 @@ func (d *daemon) initWeb() {
 		options.UIPollFrequency,
 		options.SnapshotSpacePercent,
 		d.facade)
+	cpserver.SetCORSOrigins(options.UICORSOrigins)
+	cpserver.SetCompression(!options.UIDisableCompression)
+	cpserver.SetHeadless(options.UIHeadless)
+	d.addProbes(cpserver)
 
 	web.SetServiceStatsCacheTimeout(options.SvcStatsCacheTimeout)
 	log.WithFields(logrus.Fields{
@@ -1036,6 +1499,61 @@ func (d *daemon) initWeb() {
 	log.Info("Started Control Center UI server")
 }
 
+// addProbes adds the dependency checks of the health and readiness endpoints
+// of the UI server.
+func (d *daemon) addProbes(cpserver *web.ServiceConfig) {
+	if driver, ok := d.dsDriver.(elastic.ElasticDriver); ok {
+		cpserver.AddHealthCheck("datastore", driver.CheckHealth)
+	}
+	cpserver.AddHealthCheck("zookeeper", func() error {
+		conn, err := zzk.GetLocalConnection("/")
+		if err != nil {
+			return err
+		}
+		_, err = conn.Exists("/")
+		return err
+	})
+	cpserver.AddReadinessCheck("dfs", func() error {
+		_, err := d.disk.Status()
+		return err
+	})
+	cpserver.AddReadinessCheck("isvcs", checkISvcsHealth)
+	cpserver.AddReadinessCheck("master", func() error {
+		if atomic.LoadInt32(&d.started) == 0 {
+			return errors.New("master is starting")
+		}
+		return nil
+	})
+	cpserver.SetClockSkewReporter(func() map[string]time.Duration {
+		return d.facade.GetClockSkewedHosts(datastore.Get())
+	})
+}
+
+// checkISvcsHealth returns an error naming the internal services whose health
+// checks have not passed.
+func checkISvcsHealth() error {
+	if isvcs.Mgr == nil {
+		return errors.New("internal services are not running")
+	}
+	var failed []string
+	for _, name := range isvcs.Mgr.GetServiceNames() {
+		result, err := isvcs.Mgr.GetHealthStatus(name)
+		if err != nil {
+			return err
+		}
+		for _, status := range result.HealthStatuses {
+			if status.Status != "passed" {
+				failed = append(failed, fmt.Sprintf("%s/%s is %s", name, status.Name, status.Status))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return errors.New(strings.Join(failed, ", "))
+	}
+	return nil
+}
+
 func (d *daemon) startScheduler() {
 	go d.runScheduler()
 }
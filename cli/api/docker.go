@@ -13,6 +13,8 @@
 
 package api
 
+import "github.com/control-center/serviced/domain/registry"
+
 // ResetRegistry moves all relevant images into the new docker registry
 func (a *api) ResetRegistry() error {
 	client, err := a.connectMaster()
@@ -41,6 +43,45 @@ func (a *api) UpgradeRegistry(endpoint string, override bool) error {
 	return client.UpgradeRegistry(endpoint, override)
 }
 
+// MigrateRegistry moves images from one version of the local docker registry
+// to another.
+func (a *api) MigrateRegistry(fromVersion, toVersion int, override bool) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.MigrateRegistry(fromVersion, toVersion, override)
+}
+
+// ListRegistryImages returns the images in the docker registry index, limited
+// to a tenant's library if tenantID is set.
+func (a *api) ListRegistryImages(tenantID string) ([]registry.ImageDetails, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.ListRegistryImages(tenantID)
+}
+
+// InspectRegistryImage returns an image in the docker registry index.
+func (a *api) InspectRegistryImage(image string) (*registry.ImageDetails, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.InspectRegistryImage(image)
+}
+
+// PromoteImage copies an image in the docker registry to another tenant and
+// returns the name of the new image.
+func (a *api) PromoteImage(image, tenantID, tag string) (string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", err
+	}
+	return client.PromoteImage(image, tenantID, tag)
+}
+
 // DockerOverride replaces an image in the docker registry with the specified image
 func (a *api) DockerOverride(newImage string, oldImage string) error {
 	client, err := a.connectMaster()
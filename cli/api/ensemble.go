@@ -0,0 +1,49 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/zzk/ensemble"
+)
+
+// GetZKEnsembleStatus returns the health of the quorum of the managed
+// ZooKeeper ensemble
+func (a *api) GetZKEnsembleStatus() (*ensemble.Status, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetZKEnsembleStatus()
+}
+
+// AddZKEnsembleServer adds a server to the managed ZooKeeper ensemble
+func (a *api) AddZKEnsembleServer(server ensemble.Server, force bool) (*ensemble.Status, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.AddZKEnsembleServer(server, force)
+}
+
+// RemoveZKEnsembleServer removes a server from the managed ZooKeeper ensemble
+func (a *api) RemoveZKEnsembleServer(id string, force bool) (*ensemble.Status, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.RemoveZKEnsembleServer(id, force)
+}
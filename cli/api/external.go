@@ -0,0 +1,178 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/datastore/elastic"
+	"github.com/control-center/serviced/isvcs"
+)
+
+// externalCheckTimeout is the timeout of a single external endpoint check
+const externalCheckTimeout = 10 * time.Second
+
+// validateExternalOptions verifies the settings of the external endpoints
+// that replace internal services
+func validateExternalOptions(options *config.Options) error {
+	if options.ESURL != "" {
+		if _, err := elastic.ParseEndpoint(options.ESURL); err != nil {
+			return err
+		}
+	}
+	if options.OpenTSDBURL != "" {
+		if _, err := parseOpenTSDBURL(options.OpenTSDBURL); err != nil {
+			return err
+		}
+	}
+	if options.ZKExternal && len(options.Zookeepers) == 0 {
+		return fmt.Errorf("an external ZooKeeper requires the ZooKeeper ensemble to be set")
+	}
+	if options.ZKAuth != "" && !strings.Contains(options.ZKAuth, ":") {
+		return fmt.Errorf("zookeeper credentials must be of the form user:password")
+	}
+	if _, err := getExternalTLSConfig(options); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseOpenTSDBURL parses the url of an external OpenTSDB
+func parseOpenTSDBURL(rawurl string) (*url.URL, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("opentsdb url %s must use http or https", rawurl)
+	} else if u.Host == "" {
+		return nil, fmt.Errorf("opentsdb url %s has no host", rawurl)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u, nil
+}
+
+// getExternalTLSConfig returns the tls settings for the external endpoints,
+// or nil if the system certificates should be used and no client certificate
+// is configured.
+func getExternalTLSConfig(options *config.Options) (*tls.Config, error) {
+	if options.ExternalCAFile == "" && options.ExternalCertFile == "" && options.ExternalKeyFile == "" {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{}
+	if options.ExternalCAFile != "" {
+		pem, err := ioutil.ReadFile(options.ExternalCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read external CA file: %s", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in external CA file %s", options.ExternalCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if options.ExternalCertFile != "" || options.ExternalKeyFile != "" {
+		if options.ExternalCertFile == "" || options.ExternalKeyFile == "" {
+			return nil, fmt.Errorf("an external client certificate requires both a certificate and a key file")
+		}
+		cert, err := tls.LoadX509KeyPair(options.ExternalCertFile, options.ExternalKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load external client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// getExternalClient returns an http client for the external endpoints
+func getExternalClient(options *config.Options) (*http.Client, error) {
+	tlsConfig, err := getExternalTLSConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: externalCheckTimeout}
+	if tlsConfig != nil {
+		client.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}
+	}
+	return client, nil
+}
+
+// initExternalISVCS registers the external endpoints that replace internal
+// services.  Credentials in the urls are sent as basic authentication, and the
+// client certificate, if any, is presented to https endpoints.
+func initExternalISVCS(options *config.Options) error {
+	client, err := getExternalClient(options)
+	if err != nil {
+		return err
+	}
+
+	if options.ESURL != "" {
+		ep, err := elastic.ParseEndpoint(options.ESURL)
+		if err != nil {
+			return err
+		}
+		if err := isvcs.AddExternal(&isvcs.ExternalService{
+			Name:    "elasticsearch-serviced",
+			Address: ep.String(),
+			Replace: true,
+			Check:   isvcs.HTTPCheck(client, strings.TrimSuffix(options.ESURL, "/")+"/_cluster/health"),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if options.OpenTSDBURL != "" {
+		u, err := parseOpenTSDBURL(options.OpenTSDBURL)
+		if err != nil {
+			return err
+		}
+		address := *u
+		address.User = nil
+		// The opentsdb internal service still runs, since it also hosts the
+		// metric consumer and query services.
+		if err := isvcs.AddExternal(&isvcs.ExternalService{
+			Name:    "opentsdb-external",
+			Address: address.String(),
+			Check:   isvcs.HTTPCheck(client, u.String()+"/api/version"),
+		}); err != nil {
+			return err
+		}
+		put := *u
+		put.Path += "/api/put"
+		isvcs.SetStatsEndpoint(&put, client)
+	}
+
+	if options.ZKExternal {
+		if err := isvcs.AddExternal(&isvcs.ExternalService{
+			Name:    "zookeeper",
+			Address: strings.Join(options.Zookeepers, ","),
+			Replace: true,
+			Check:   isvcs.ZKCheck(options.Zookeepers, externalCheckTimeout),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fake provides an in-memory implementation of the serviced cli api.
+// It is used by setting SERVICED_MOCK=1, which lets the command line be
+// exercised against realistic data without a running master.
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	template "github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/statehistory"
+)
+
+var (
+	// ErrNotSupported is returned by operations that cannot be simulated
+	ErrNotSupported = errors.New("operation is not supported by the mock driver")
+
+	// ErrNotFound is returned when an entity does not exist
+	ErrNotFound = errors.New("not found")
+)
+
+// assert interface
+var _ api.API = &Driver{}
+
+// Driver is an in-memory implementation of api.API
+type Driver struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	nextID    int
+	hosts     map[string]host.Host
+	pools     map[string]pool.ResourcePool
+	services  map[string]service.Service
+	snapshots map[string]dao.SnapshotInfo
+	snapsvcs  map[string][]service.Service // service definitions by snapshot id
+	templates map[string]template.ServiceTemplate
+	chaos     map[string]service.ChaosStatus
+	users     map[string][]string
+	history   map[string][]statehistory.Change // desired state changes by service id
+}
+
+// New returns a driver populated with a sample deployment
+func New() *Driver {
+	d := NewEmpty()
+	d.seed()
+	return d
+}
+
+// NewEmpty returns a driver without any data
+func NewEmpty() *Driver {
+	return &Driver{
+		now:       time.Now,
+		hosts:     make(map[string]host.Host),
+		pools:     make(map[string]pool.ResourcePool),
+		services:  make(map[string]service.Service),
+		snapshots: make(map[string]dao.SnapshotInfo),
+		snapsvcs:  make(map[string][]service.Service),
+		templates: make(map[string]template.ServiceTemplate),
+		chaos:     make(map[string]service.ChaosStatus),
+		users:     make(map[string][]string),
+		history:   make(map[string][]statehistory.Change),
+	}
+}
+
+// newID returns a unique id with the given prefix; the caller must hold the
+// lock.
+func (d *Driver) newID(prefix string) string {
+	d.nextID++
+	return fmt.Sprintf("%s-%d", prefix, d.nextID)
+}
@@ -0,0 +1,227 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package fake
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type DriverSuite struct {
+	d *Driver
+}
+
+var _ = Suite(&DriverSuite{})
+
+func (s *DriverSuite) SetUpTest(c *C) {
+	s.d = New()
+	s.d.now = func() time.Time { return time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC) }
+}
+
+func (s *DriverSuite) TestSeed(c *C) {
+	hosts, err := s.d.GetHosts()
+	c.Assert(err, IsNil)
+	c.Assert(hosts, HasLen, 2)
+
+	p, err := s.d.GetResourcePool("default")
+	c.Assert(err, IsNil)
+	c.Assert(p.CoreCapacity, Equals, 8)
+
+	insts, err := s.d.GetServiceInstances("mock-web")
+	c.Assert(err, IsNil)
+	c.Assert(insts, HasLen, 2)
+	c.Assert(insts[0].HostID, Equals, "mock-host-1")
+	c.Assert(insts[1].HostID, Equals, "mock-host-2")
+
+	status, err := s.d.GetDeploymentStatus("mock")
+	c.Assert(err, IsNil)
+	c.Assert(status.Services, Equals, 5)
+	c.Assert(status.Stopped, Equals, 1)
+	c.Assert(status.Instances, Equals, status.ExpectedInstances)
+}
+
+func (s *DriverSuite) TestScheduleService(c *C) {
+	count, err := s.d.StopService(api.SchedulerConfig{ServiceID: "mock-tenant", AutoLaunch: true})
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 5)
+
+	// manual services are only started explicitly
+	count, err = s.d.StartService(api.SchedulerConfig{ServiceID: "mock-tenant", AutoLaunch: true})
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 4)
+	svc, err := s.d.GetService("mock-reports")
+	c.Assert(err, IsNil)
+	c.Assert(svc.DesiredState, Equals, int(service.SVCStop))
+
+	count, err = s.d.StartService(api.SchedulerConfig{ServiceID: "mock-reports"})
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 1)
+
+	_, err = s.d.StartService(api.SchedulerConfig{ServiceID: "missing"})
+	c.Assert(err, NotNil)
+}
+
+func (s *DriverSuite) TestPauseAndResumeService(c *C) {
+	// only running services are paused
+	count, err := s.d.PauseService(api.SchedulerConfig{ServiceID: "mock-tenant", AutoLaunch: true})
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 4)
+	svc, err := s.d.GetService("mock-reports")
+	c.Assert(err, IsNil)
+	c.Assert(svc.DesiredState, Equals, int(service.SVCStop))
+
+	count, err = s.d.ResumeService(api.SchedulerConfig{ServiceID: "mock-tenant", AutoLaunch: true})
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 4)
+	svc, err = s.d.GetService("mock-tenant")
+	c.Assert(err, IsNil)
+	c.Assert(svc.DesiredState, Equals, int(service.SVCRun))
+
+	count, err = s.d.ResumeService(api.SchedulerConfig{ServiceID: "mock-tenant", AutoLaunch: true})
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 0)
+
+	changes, err := s.d.GetServiceStateHistory("mock-tenant")
+	c.Assert(err, IsNil)
+	c.Assert(changes, HasLen, 2)
+	c.Assert(changes[0].From, Equals, service.SVCRun)
+	c.Assert(changes[0].To, Equals, service.SVCPause)
+	c.Assert(changes[1].To, Equals, service.SVCRun)
+	changes, err = s.d.GetServiceStateHistory("mock-reports")
+	c.Assert(err, IsNil)
+	c.Assert(changes, HasLen, 0)
+	_, err = s.d.GetServiceStateHistory("missing")
+	c.Assert(err, NotNil)
+}
+
+func (s *DriverSuite) TestQueryServices(c *C) {
+	page, err := s.d.QueryServices(service.ServiceQuery{PathSuffix: "MOCKAPP/WEB"})
+	c.Assert(err, IsNil)
+	c.Assert(page.Total, Equals, 1)
+	c.Assert(page.Services[0].ID, Equals, "mock-web")
+	c.Assert(page.Services[0].Path, Equals, "mockapp/web")
+
+	page, err = s.d.QueryServices(service.ServiceQuery{Limit: 1})
+	c.Assert(err, IsNil)
+	c.Assert(page.Total > 1, Equals, true)
+	c.Assert(page.Services, HasLen, 1)
+}
+
+func (s *DriverSuite) TestResolveServicePath(c *C) {
+	matches, err := s.d.ResolveServicePath("mockapp/web")
+	c.Assert(err, IsNil)
+	c.Assert(matches, HasLen, 1)
+	c.Assert(matches[0].ID, Equals, "mock-web")
+
+	matches, err = s.d.ResolveServicePath("s")
+	c.Assert(err, IsNil)
+	c.Assert(len(matches) > 1, Equals, true)
+	c.Assert(matches[0].Fuzzy, Equals, true)
+}
+
+func (s *DriverSuite) TestUpdateAndRemoveService(c *C) {
+	_, err := s.d.UpdateService(strings.NewReader(`{"ID": "mock-web", "Name": "frontend", "ParentServiceID": "mock-tenant"}`))
+	c.Assert(err, IsNil)
+	svcs, err := s.d.GetServicesByName("frontend")
+	c.Assert(err, IsNil)
+	c.Assert(svcs, HasLen, 1)
+
+	err = s.d.RemoveService("mock-tenant")
+	c.Assert(err, IsNil)
+	svcs, err = s.d.GetServices()
+	c.Assert(err, IsNil)
+	c.Assert(svcs, HasLen, 0)
+}
+
+func (s *DriverSuite) TestCloneDeployment(c *C) {
+	_, err := s.d.CloneDeployment(dao.DeploymentCloneRequest{TenantID: "mock-web", PoolID: "default", DeploymentID: "staging"})
+	c.Assert(err, NotNil)
+	_, err = s.d.CloneDeployment(dao.DeploymentCloneRequest{TenantID: "mock-tenant", PoolID: "default", DeploymentID: "mock"})
+	c.Assert(err, NotNil)
+
+	tenantID, err := s.d.CloneDeployment(dao.DeploymentCloneRequest{TenantID: "mock-tenant", PoolID: "default", DeploymentID: "staging", SnapshotID: "mock-tenant_20160101-000000"})
+	c.Assert(err, IsNil)
+	c.Assert(tenantID, Not(Equals), "mock-tenant")
+	status, err := s.d.GetDeploymentStatus("staging")
+	c.Assert(err, IsNil)
+	c.Assert(status.Services, Equals, 5)
+	c.Assert(status.Stopped, Equals, 5)
+}
+
+func (s *DriverSuite) TestSnapshots(c *C) {
+	snapshotID, err := s.d.AddSnapshot(api.SnapshotConfig{ServiceID: "mock-web", Tag: "before-upgrade"})
+	c.Assert(err, IsNil)
+	c.Assert(snapshotID, Equals, "mock-tenant_20160601-120000")
+
+	id, err := s.d.GetSnapshotByServiceIDAndTag("mock-redis", "before-upgrade")
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, snapshotID)
+
+	err = s.d.TagSnapshot("mock-tenant_20160101-000000", "before-upgrade")
+	c.Assert(err, NotNil)
+
+	id, err = s.d.RemoveSnapshotTag("mock-tenant", "before-upgrade")
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, snapshotID)
+	_, err = s.d.GetSnapshotByServiceIDAndTag("mock-tenant", "before-upgrade")
+	c.Assert(err, NotNil)
+
+	c.Assert(s.d.RemoveSnapshot(snapshotID), IsNil)
+	snapshots, err := s.d.GetSnapshots()
+	c.Assert(err, IsNil)
+	c.Assert(snapshots, HasLen, 1)
+}
+
+func (s *DriverSuite) TestRemoveResourcePool(c *C) {
+	_, err := s.d.AddResourcePool(api.PoolConfig{PoolID: "empty"})
+	c.Assert(err, IsNil)
+	c.Assert(s.d.RemoveResourcePool("empty"), IsNil)
+	c.Assert(s.d.RemoveResourcePool("default"), NotNil)
+}
+
+func (s *DriverSuite) TestDeployServiceTemplate(c *C) {
+	svcs, err := s.d.DeployServiceTemplate(api.DeployTemplateConfig{ID: "mock-template", PoolID: "default", DeploymentID: "second"})
+	c.Assert(err, IsNil)
+	c.Assert(svcs, HasLen, 1)
+	c.Assert(svcs[0].DeploymentID, Equals, "second")
+
+	_, err = s.d.DeployServiceTemplate(api.DeployTemplateConfig{ID: "mock-template", PoolID: "default", DeploymentID: "second"})
+	c.Assert(err, NotNil)
+}
+
+func (s *DriverSuite) TestNotSupported(c *C) {
+	c.Assert(s.d.StartServer(), Equals, ErrNotSupported)
+}
+
+func (s *DriverSuite) TestUserTenants(c *C) {
+	tenants, err := s.d.GetUserTenants("alice")
+	c.Assert(err, IsNil)
+	c.Assert(tenants, HasLen, 0)
+
+	c.Assert(s.d.SetUserTenants("alice", []string{"mock-web"}), NotNil)
+	c.Assert(s.d.SetUserTenants("alice", []string{"mock-tenant"}), IsNil)
+	tenants, err = s.d.GetUserTenants("alice")
+	c.Assert(err, IsNil)
+	c.Assert(tenants, DeepEquals, []string{"mock-tenant"})
+}
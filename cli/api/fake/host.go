@@ -0,0 +1,193 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/metrics"
+)
+
+type hostsByID []host.Host
+
+func (s hostsByID) Len() int           { return len(s) }
+func (s hostsByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s hostsByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+
+// GetHosts returns all hosts ordered by id
+func (d *Driver) GetHosts() ([]host.Host, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	hosts := make([]host.Host, 0, len(d.hosts))
+	for _, h := range d.hosts {
+		hosts = append(hosts, h)
+	}
+	sort.Sort(hostsByID(hosts))
+	return hosts, nil
+}
+
+// GetHost returns a host by id
+func (d *Driver) GetHost(id string) (*host.Host, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	h, ok := d.hosts[id]
+	if !ok {
+		return nil, fmt.Errorf("host %s %s", id, ErrNotFound)
+	}
+	return &h, nil
+}
+
+// GetHostMap returns all hosts keyed by id
+func (d *Driver) GetHostMap() (map[string]host.Host, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	hostmap := make(map[string]host.Host, len(d.hosts))
+	for id, h := range d.hosts {
+		hostmap[id] = h
+	}
+	return hostmap, nil
+}
+
+// AddHost adds a host at the given address to a pool
+func (d *Driver) AddHost(config api.HostConfig) (*host.Host, []byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.pools[config.PoolID]; !ok {
+		return nil, nil, fmt.Errorf("pool %s %s", config.PoolID, ErrNotFound)
+	}
+	for _, h := range d.hosts {
+		if h.IPAddr == config.Address.Host {
+			return nil, nil, fmt.Errorf("host already exists at %s", config.Address.Host)
+		}
+	}
+
+	h := host.Host{
+		ID:       d.newID("mock-host"),
+		PoolID:   config.PoolID,
+		IPAddr:   config.Address.Host,
+		RPCPort:  config.Address.Port,
+		Cores:    4,
+		Memory:   16 * 1024 * 1024 * 1024,
+		RAMLimit: config.Memory,
+	}
+	h.Name = h.ID
+	for _, ip := range config.IPs {
+		h.IPs = append(h.IPs, host.HostIPResource{HostID: h.ID, IPAddress: ip})
+	}
+	h.CreatedAt = d.now()
+	h.UpdatedAt = h.CreatedAt
+	d.hosts[h.ID] = h
+	return &h, []byte("mock delegate key for " + h.ID), nil
+}
+
+// RemoveHost removes a host by id
+func (d *Driver) RemoveHost(id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.hosts[id]; !ok {
+		return fmt.Errorf("host %s %s", id, ErrNotFound)
+	}
+	delete(d.hosts, id)
+	return nil
+}
+
+// GetHostMemory returns empty memory usage for an existing host
+func (d *Driver) GetHostMemory(id string) (*metrics.MemoryUsageStats, error) {
+	if _, err := d.GetHost(id); err != nil {
+		return nil, err
+	}
+	return &metrics.MemoryUsageStats{HostID: id}, nil
+}
+
+// SetHostMemory updates the memory limit of a host
+func (d *Driver) SetHostMemory(config api.HostUpdateConfig) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	h, ok := d.hosts[config.HostID]
+	if !ok {
+		return fmt.Errorf("host %s %s", config.HostID, ErrNotFound)
+	}
+	h.RAMLimit = config.Memory
+	h.UpdatedAt = d.now()
+	d.hosts[h.ID] = h
+	return nil
+}
+
+// GetHostPublicKey is not supported
+func (d *Driver) GetHostPublicKey(id string) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+// RegisterHost is not supported
+func (d *Driver) RegisterHost(keydata []byte) error {
+	return ErrNotSupported
+}
+
+// CheckNetwork is not supported
+func (d *Driver) CheckNetwork(poolID string) (*host.NetworkReport, error) {
+	return nil, ErrNotSupported
+}
+
+// SelfTestHost is not supported
+func (d *Driver) SelfTestHost(hostID string) (*host.SelfTestReport, error) {
+	return nil, ErrNotSupported
+}
+
+// RegisterRemoteHost is not supported
+func (d *Driver) RegisterRemoteHost(h *host.Host, keydata []byte, fingerprints []string) error {
+	return ErrNotSupported
+}
+
+// GetSSHHostKeys is not supported
+func (d *Driver) GetSSHHostKeys(h *host.Host) ([]api.SSHHostKey, error) {
+	return nil, ErrNotSupported
+}
+
+// WriteDelegateKey is not supported
+func (d *Driver) WriteDelegateKey(filename string, keydata []byte) error {
+	return ErrNotSupported
+}
+
+// AuthenticateHost is not supported
+func (d *Driver) AuthenticateHost(hostID string) (string, int64, error) {
+	return "", 0, ErrNotSupported
+}
+
+// ResetHostKey is not supported
+func (d *Driver) ResetHostKey(hostID string) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+// SSHHost is not supported
+func (d *Driver) SSHHost(config api.HostSSHConfig) error {
+	return ErrNotSupported
+}
+
+// UpgradeDelegates is not supported
+func (d *Driver) UpgradeDelegates(req host.UpgradeRequest) ([]host.UpgradeResult, error) {
+	return nil, ErrNotSupported
+}
+
+// GetHostEvents is not supported
+func (d *Driver) GetHostEvents(hostID string) ([]host.Event, error) {
+	return nil, ErrNotSupported
+}
+
+// GetHostStorage is not supported
+func (d *Driver) GetHostStorage(hostID string) (*host.StorageHealth, error) {
+	return nil, ErrNotSupported
+}
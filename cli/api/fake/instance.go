@@ -0,0 +1,165 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// getInstances simulates the instances of a running service by spreading
+// them across the hosts in its pool; the caller must hold the lock.
+func (d *Driver) getInstances(svc *service.Service) []service.Instance {
+	if service.DesiredState(svc.DesiredState) != service.SVCRun || svc.Instances == 0 {
+		return nil
+	}
+	var hosts []host.Host
+	for _, h := range d.hosts {
+		if h.PoolID == svc.PoolID {
+			hosts = append(hosts, h)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil
+	}
+	sort.Sort(hostsByID(hosts))
+
+	insts := make([]service.Instance, svc.Instances)
+	for i := range insts {
+		h := hosts[i%len(hosts)]
+		insts[i] = service.Instance{
+			InstanceID:   i,
+			HostID:       h.ID,
+			HostName:     h.Name,
+			ServiceID:    svc.ID,
+			ServiceName:  svc.Name,
+			ContainerID:  fmt.Sprintf("%s-%d", svc.ID, i),
+			ImageSynced:  true,
+			DesiredState: service.SVCRun,
+			CurrentState: service.Running,
+			Scheduled:    svc.UpdatedAt,
+			Started:      svc.UpdatedAt,
+		}
+	}
+	return insts
+}
+
+// GetServiceInstances returns the simulated instances of a service
+func (d *Driver) GetServiceInstances(serviceID string) ([]service.Instance, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	svc, err := d.getService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return d.getInstances(svc), nil
+}
+
+// WaitServiceStatus is not supported; the simulated services only change when
+// the driver is called.
+func (d *Driver) WaitServiceStatus(serviceIDs []string, timeout time.Duration) ([]string, error) {
+	return nil, ErrNotSupported
+}
+
+// GetDeploymentStatus summarizes the services in a deployment
+func (d *Driver) GetDeploymentStatus(deploymentID string) (*service.DeploymentStatus, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status := &service.DeploymentStatus{DeploymentID: deploymentID}
+	for _, svc := range d.services {
+		if svc.DeploymentID != deploymentID {
+			continue
+		}
+		status.Services++
+		switch service.DesiredState(svc.DesiredState) {
+		case service.SVCRun:
+			status.Running++
+			status.ExpectedInstances += svc.Instances
+			status.Instances += len(d.getInstances(&svc))
+		case service.SVCPause:
+			status.Paused++
+		default:
+			status.Stopped++
+		}
+	}
+	if status.Services == 0 {
+		return nil, fmt.Errorf("no services found for deployment %s", deploymentID)
+	}
+	return status, nil
+}
+
+// StopServiceInstance validates the instance; the mock instance restarts
+// immediately
+func (d *Driver) StopServiceInstance(serviceID string, instanceID int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	svc, err := d.getService(serviceID)
+	if err != nil {
+		return err
+	}
+	if instanceID < 0 || instanceID >= len(d.getInstances(svc)) {
+		return fmt.Errorf("instance %d of service %s %s", instanceID, serviceID, ErrNotFound)
+	}
+	return nil
+}
+
+// AttachServiceInstance is not supported
+func (d *Driver) AttachServiceInstance(serviceID string, instanceID int, command string, args []string) error {
+	return ErrNotSupported
+}
+
+// CommitServiceInstance validates the instance and snapshots its tenant
+func (d *Driver) CommitServiceInstance(serviceID string, instanceID int, message string) (string, error) {
+	d.mu.Lock()
+	svc, err := d.getService(serviceID)
+	if err == nil && (instanceID < 0 || instanceID >= len(d.getInstances(svc))) {
+		err = fmt.Errorf("instance %d of service %s %s", instanceID, serviceID, ErrNotFound)
+	}
+	d.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	return d.AddSnapshot(api.SnapshotConfig{ServiceID: serviceID, Message: message})
+}
+
+// FollowServiceLogs is not supported
+func (d *Driver) FollowServiceLogs(cfg api.ServiceLogsConfig, w io.Writer) error {
+	return ErrNotSupported
+}
+
+// LogsForServiceInstance is not supported
+func (d *Driver) LogsForServiceInstance(serviceID string, instanceID int, command string, args []string) error {
+	return ErrNotSupported
+}
+
+// GetPreviousInstanceOutput is not supported
+func (d *Driver) GetPreviousInstanceOutput(serviceID string, instanceID int) (*service.InstanceOutput, error) {
+	return nil, ErrNotSupported
+}
+
+// GetInstanceDiagnostics is not supported
+func (d *Driver) GetInstanceDiagnostics(serviceID string, instanceID int) ([]service.DiagnosticOutput, error) {
+	return nil, ErrNotSupported
+}
+
+// SendDockerAction is not supported
+func (d *Driver) SendDockerAction(serviceID string, instanceID int, action string, args []string) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,238 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+)
+
+type poolsByID []pool.ResourcePool
+
+func (s poolsByID) Len() int           { return len(s) }
+func (s poolsByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s poolsByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+
+// getPool returns a pool with its capacity calculated from its hosts; the
+// caller must hold the lock.
+func (d *Driver) getPool(id string) (*pool.ResourcePool, error) {
+	p, ok := d.pools[id]
+	if !ok {
+		return nil, fmt.Errorf("pool %s %s", id, ErrNotFound)
+	}
+	p.CoreCapacity, p.MemoryCapacity = 0, 0
+	for _, h := range d.hosts {
+		if h.PoolID == id {
+			p.CoreCapacity += h.Cores
+			p.MemoryCapacity += h.Memory
+		}
+	}
+	p.MemoryCommitment = 0
+	for _, svc := range d.services {
+		if svc.PoolID == id {
+			p.MemoryCommitment += svc.RAMCommitment.Value * uint64(svc.Instances)
+		}
+	}
+	return &p, nil
+}
+
+// GetResourcePools returns all pools ordered by id
+func (d *Driver) GetResourcePools() ([]pool.ResourcePool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	pools := make([]pool.ResourcePool, 0, len(d.pools))
+	for id := range d.pools {
+		p, _ := d.getPool(id)
+		pools = append(pools, *p)
+	}
+	sort.Sort(poolsByID(pools))
+	return pools, nil
+}
+
+// GetResourcePool returns a pool by id
+func (d *Driver) GetResourcePool(id string) (*pool.ResourcePool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.getPool(id)
+}
+
+// AddResourcePool adds a new pool
+func (d *Driver) AddResourcePool(config api.PoolConfig) (*pool.ResourcePool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if config.PoolID == "" {
+		return nil, fmt.Errorf("pool id cannot be empty")
+	} else if _, ok := d.pools[config.PoolID]; ok {
+		return nil, fmt.Errorf("pool %s already exists", config.PoolID)
+	}
+	p := pool.ResourcePool{
+		ID:          config.PoolID,
+		Realm:       config.Realm,
+		CoreLimit:   config.CoreLimit,
+		MemoryLimit: config.MemoryLimit,
+		Permissions: config.Permissions,
+		CreatedAt:   d.now(),
+	}
+	p.UpdatedAt = p.CreatedAt
+	d.pools[p.ID] = p
+	return d.getPool(p.ID)
+}
+
+// RemoveResourcePool removes a pool that has no hosts or services
+func (d *Driver) RemoveResourcePool(id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.pools[id]; !ok {
+		return fmt.Errorf("pool %s %s", id, ErrNotFound)
+	}
+	for _, h := range d.hosts {
+		if h.PoolID == id {
+			return fmt.Errorf("cannot delete resource pool with %s hosts", id)
+		}
+	}
+	for _, svc := range d.services {
+		if svc.PoolID == id {
+			return fmt.Errorf("cannot delete resource pool with services")
+		}
+	}
+	delete(d.pools, id)
+	delete(d.chaos, id)
+	return nil
+}
+
+// UpdateResourcePool replaces an existing pool
+func (d *Driver) UpdateResourcePool(p pool.ResourcePool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.pools[p.ID]; !ok {
+		return fmt.Errorf("pool %s %s", p.ID, ErrNotFound)
+	}
+	p.UpdatedAt = d.now()
+	d.pools[p.ID] = p
+	return nil
+}
+
+// GetPoolIPs returns the host and virtual ips of a pool
+func (d *Driver) GetPoolIPs(id string) (*pool.PoolIPs, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	p, err := d.getPool(id)
+	if err != nil {
+		return nil, err
+	}
+	ips := &pool.PoolIPs{PoolID: id, VirtualIPs: p.VirtualIPs}
+	var hosts []host.Host
+	for _, h := range d.hosts {
+		if h.PoolID == id {
+			hosts = append(hosts, h)
+		}
+	}
+	sort.Sort(hostsByID(hosts))
+	for _, h := range hosts {
+		ips.HostIPs = append(ips.HostIPs, h.IPs...)
+	}
+	return ips, nil
+}
+
+// GetPoolPortClaims returns no port claims; the mock services do not export
+// public ports
+func (d *Driver) GetPoolPortClaims(id string) ([]pool.PortClaim, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.getPool(id); err != nil {
+		return nil, err
+	}
+	return []pool.PortClaim{}, nil
+}
+
+// AddVirtualIP adds a virtual ip to a pool
+func (d *Driver) AddVirtualIP(vip pool.VirtualIP) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	p, ok := d.pools[vip.PoolID]
+	if !ok {
+		return fmt.Errorf("pool %s %s", vip.PoolID, ErrNotFound)
+	}
+	for _, v := range p.VirtualIPs {
+		if v.IP == vip.IP {
+			return fmt.Errorf("virtual ip %s already exists", vip.IP)
+		}
+	}
+	p.VirtualIPs = append(p.VirtualIPs, vip)
+	d.pools[p.ID] = p
+	return nil
+}
+
+// RemoveVirtualIP removes a virtual ip from a pool
+func (d *Driver) RemoveVirtualIP(vip pool.VirtualIP) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	p, ok := d.pools[vip.PoolID]
+	if !ok {
+		return fmt.Errorf("pool %s %s", vip.PoolID, ErrNotFound)
+	}
+	for i, v := range p.VirtualIPs {
+		if v.IP == vip.IP {
+			p.VirtualIPs = append(p.VirtualIPs[:i], p.VirtualIPs[i+1:]...)
+			d.pools[p.ID] = p
+			return nil
+		}
+	}
+	return fmt.Errorf("virtual ip %s %s", vip.IP, ErrNotFound)
+}
+
+// EnableChaos records that chaos mode is enabled on a pool.  No instances are
+// killed.
+func (d *Driver) EnableChaos(poolID string, interval time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.pools[poolID]; !ok {
+		return fmt.Errorf("pool %s %s", poolID, ErrNotFound)
+	}
+	status := d.chaos[poolID]
+	status.PoolID = poolID
+	status.Enabled = true
+	status.Interval = interval
+	status.Since = d.now()
+	d.chaos[poolID] = status
+	return nil
+}
+
+// DisableChaos records that chaos mode is disabled on a pool
+func (d *Driver) DisableChaos(poolID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if status, ok := d.chaos[poolID]; ok {
+		status.Enabled = false
+		d.chaos[poolID] = status
+	}
+	return nil
+}
+
+// GetChaosStatus returns the chaos mode settings of a pool
+func (d *Driver) GetChaosStatus(poolID string) (*service.ChaosStatus, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status, ok := d.chaos[poolID]
+	if !ok {
+		status.PoolID = poolID
+	}
+	return &status, nil
+}
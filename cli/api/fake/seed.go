@@ -0,0 +1,101 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	template "github.com/control-center/serviced/domain/servicetemplate"
+)
+
+// seedTime is the creation time of the sample data
+var seedTime = time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// seed populates the driver with a sample deployment of a small application
+// running on two hosts in the default pool.
+func (d *Driver) seed() {
+	d.pools["default"] = pool.ResourcePool{
+		ID:          "default",
+		Realm:       "default",
+		Description: "Default resource pool",
+		Permissions: pool.AdminAccess | pool.DFSAccess,
+		CreatedAt:   seedTime,
+		UpdatedAt:   seedTime,
+	}
+
+	for name, ip := range map[string]string{"mock-host-1": "10.87.110.1", "mock-host-2": "10.87.110.2"} {
+		h := host.Host{
+			ID:            name,
+			Name:          name,
+			PoolID:        "default",
+			IPAddr:        ip,
+			RPCPort:       4979,
+			Cores:         4,
+			Memory:        16 * 1024 * 1024 * 1024,
+			KernelVersion: "3.10.0-327.el7.x86_64",
+			KernelRelease: "#1 SMP",
+			CreatedAt:     seedTime,
+			UpdatedAt:     seedTime,
+		}
+		h.IPs = []host.HostIPResource{{HostID: h.ID, IPAddress: h.IPAddr, InterfaceName: "eth0"}}
+		d.hosts[h.ID] = h
+	}
+
+	svcs := []service.Service{
+		{ID: "mock-tenant", Name: "mockapp", Instances: 0},
+		{ID: "mock-web", Name: "web", ParentServiceID: "mock-tenant", Instances: 2, Startup: "/bin/web -port 8080"},
+		{ID: "mock-worker", Name: "worker", ParentServiceID: "mock-tenant", Instances: 1, Startup: "/bin/worker"},
+		{ID: "mock-redis", Name: "redis", ParentServiceID: "mock-tenant", Instances: 1, Startup: "/usr/bin/redis-server"},
+		{ID: "mock-reports", Name: "reports", ParentServiceID: "mock-tenant", Instances: 1, Startup: "/bin/reports", Launch: commons.MANUAL},
+	}
+	for _, svc := range svcs {
+		svc.PoolID = "default"
+		svc.DeploymentID = "mock"
+		svc.ImageID = "mockapp/app:1.0"
+		svc.DesiredState = int(service.SVCRun)
+		svc.InstanceLimits.Min = 1
+		if svc.Launch == "" {
+			svc.Launch = commons.AUTO
+		} else {
+			svc.DesiredState = int(service.SVCStop)
+		}
+		svc.CreatedAt = seedTime
+		svc.UpdatedAt = seedTime
+		d.services[svc.ID] = svc
+	}
+
+	d.snapshots["mock-tenant_20160101-000000"] = dao.SnapshotInfo{
+		SnapshotID:  "mock-tenant_20160101-000000",
+		TenantID:    "mock-tenant",
+		Description: "initial deployment",
+		Tags:        []string{"initial"},
+		Created:     seedTime,
+	}
+
+	d.templates["mock-template"] = template.ServiceTemplate{
+		ID:          "mock-template",
+		Name:        "mockapp",
+		Version:     "1.0",
+		Description: "Sample application",
+		Services: []servicedefinition.ServiceDefinition{
+			{Name: "mockapp", Launch: commons.AUTO},
+		},
+	}
+}
@@ -0,0 +1,567 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/statehistory"
+)
+
+type servicesByID []service.Service
+
+func (s servicesByID) Len() int           { return len(s) }
+func (s servicesByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s servicesByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+
+// getService returns a service by id; the caller must hold the lock.
+func (d *Driver) getService(id string) (*service.Service, error) {
+	svc, ok := d.services[id]
+	if !ok {
+		return nil, fmt.Errorf("service %s %s", id, ErrNotFound)
+	}
+	return &svc, nil
+}
+
+// getChildren returns the children of a service ordered by id; the caller
+// must hold the lock.
+func (d *Driver) getChildren(id string) []service.Service {
+	var children []service.Service
+	for _, svc := range d.services {
+		if svc.ParentServiceID == id {
+			children = append(children, svc)
+		}
+	}
+	sort.Sort(servicesByID(children))
+	return children
+}
+
+// getTenantID returns the id of the top-level service; the caller must hold
+// the lock.
+func (d *Driver) getTenantID(id string) (string, error) {
+	for {
+		svc, err := d.getService(id)
+		if err != nil {
+			return "", err
+		} else if svc.ParentServiceID == "" {
+			return svc.ID, nil
+		}
+		id = svc.ParentServiceID
+	}
+}
+
+// GetServices returns all services ordered by id
+func (d *Driver) GetServices() ([]service.Service, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	svcs := make([]service.Service, 0, len(d.services))
+	for _, svc := range d.services {
+		svcs = append(svcs, svc)
+	}
+	sort.Sort(servicesByID(svcs))
+	return svcs, nil
+}
+
+// QueryServices returns a page of the summaries of the services matching the
+// query
+func (d *Driver) QueryServices(query service.ServiceQuery) (*service.ServiceSummaryPage, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	page := service.QueryServiceSummaries(d.summaries(), query)
+	return &page, nil
+}
+
+// summaries returns the summaries of all of the services
+func (d *Driver) summaries() []service.ServiceSummary {
+	summaries := make([]service.ServiceSummary, 0, len(d.services))
+	for _, svc := range d.services {
+		summaries = append(summaries, svc.Summary())
+	}
+	service.SetSummaryPaths(summaries)
+	return summaries
+}
+
+// ResolveServicePath returns the services that a service id or a
+// deployment, pool, or name path resolves to
+func (d *Driver) ResolveServicePath(servicePath string) ([]service.ServiceMatch, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return service.ResolveServicePath(d.summaries(), servicePath), nil
+}
+
+// GetServiceStatus returns the status rows of a service and its ancestors,
+// or of all services if no id is given.
+func (d *Driver) GetServiceStatus(serviceID string) (map[string]map[string]interface{}, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var svcs []service.Service
+	if serviceID != "" {
+		for serviceID != "" {
+			svc, err := d.getService(serviceID)
+			if err != nil {
+				return nil, err
+			}
+			svcs = append(svcs, *svc)
+			serviceID = svc.ParentServiceID
+		}
+	} else {
+		for _, svc := range d.services {
+			svcs = append(svcs, svc)
+		}
+	}
+
+	rowmap := make(map[string]map[string]interface{})
+	for _, svc := range svcs {
+		parentID := ""
+		if svc.ParentServiceID != "" {
+			parentID = fmt.Sprintf("%s/%d", svc.ParentServiceID, 0)
+		}
+		insts := d.getInstances(&svc)
+		if len(insts) == 0 {
+			row := map[string]interface{}{
+				"ServiceID": svc.ID,
+				"Name":      svc.Name,
+				"ParentID":  parentID,
+			}
+			if svc.Instances > 0 {
+				switch service.DesiredState(svc.DesiredState) {
+				case service.SVCRun:
+					row["Status"] = "Scheduled"
+				case service.SVCPause:
+					row["Status"] = service.Paused
+				case service.SVCStop:
+					row["Status"] = service.Stopped
+				}
+			}
+			rowmap[fmt.Sprintf("%s/%d", svc.ID, 0)] = row
+			continue
+		}
+		for _, inst := range insts {
+			name := svc.Name
+			if svc.Instances > 1 {
+				name = fmt.Sprintf("%s/%d", svc.Name, inst.InstanceID)
+			}
+			rowmap[fmt.Sprintf("%s/%d", svc.ID, inst.InstanceID)] = map[string]interface{}{
+				"ServiceID": svc.ID,
+				"Name":      name,
+				"ParentID":  parentID,
+				"Status":    inst.CurrentState,
+				"Hostname":  inst.HostName,
+				"DockerID":  fmt.Sprintf("%.12s", inst.ContainerID),
+				"InSync":    "Y",
+			}
+		}
+	}
+	return rowmap, nil
+}
+
+// GetService returns a service by id
+func (d *Driver) GetService(id string) (*service.Service, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.getService(id)
+}
+
+// GetServicesByName returns the services whose name or id matches
+func (d *Driver) GetServicesByName(name string) ([]service.Service, error) {
+	svcs, err := d.GetServices()
+	if err != nil {
+		return nil, err
+	}
+	var matches []service.Service
+	for _, svc := range svcs {
+		if svc.Name == name || svc.ID == name {
+			matches = append(matches, svc)
+		}
+	}
+	return matches, nil
+}
+
+// AddService adds a service as a child of an existing service
+func (d *Driver) AddService(config api.ServiceConfig) (*service.Service, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	parent, err := d.getService(config.ParentServiceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range d.getChildren(parent.ID) {
+		if child.Name == config.Name {
+			return nil, fmt.Errorf("service %s already exists under %s", config.Name, parent.ID)
+		}
+	}
+
+	svc := service.Service{
+		ID:              d.newID("mock-service"),
+		Name:            config.Name,
+		ParentServiceID: parent.ID,
+		PoolID:          parent.PoolID,
+		DeploymentID:    parent.DeploymentID,
+		ImageID:         config.ImageID,
+		Startup:         config.Command,
+		Instances:       1,
+		Launch:          commons.AUTO,
+		DesiredState:    int(service.SVCStop),
+	}
+	svc.InstanceLimits.Min = 1
+	svc.InstanceLimits.Max = 1
+	svc.InstanceLimits.Default = 1
+	addEndpoints := func(ports *api.PortMap, purpose string) {
+		if ports == nil {
+			return
+		}
+		var names []string
+		for name := range *ports {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ep := (*ports)[name]
+			ep.Purpose = purpose
+			svc.Endpoints = append(svc.Endpoints, service.BuildServiceEndpoint(ep))
+		}
+	}
+	addEndpoints(config.LocalPorts, "local")
+	addEndpoints(config.RemotePorts, "remote")
+	svc.CreatedAt = d.now()
+	svc.UpdatedAt = svc.CreatedAt
+	d.services[svc.ID] = svc
+	return &svc, nil
+}
+
+// CloneService copies a service, appending the suffix to its name
+func (d *Driver) CloneService(serviceID string, suffix string) (*service.Service, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	svc, err := d.getService(serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("copy service failed: %s", err)
+	}
+	if suffix == "" {
+		suffix = "-clone"
+	}
+	svc.ID = d.newID("mock-service")
+	svc.Name += suffix
+	svc.DesiredState = int(service.SVCStop)
+	svc.CreatedAt = d.now()
+	svc.UpdatedAt = svc.CreatedAt
+	d.services[svc.ID] = *svc
+	return svc, nil
+}
+
+// CloneDeployment copies a tenant and its services into a new deployment
+// with their public endpoints disabled, unless requested otherwise
+func (d *Driver) CloneDeployment(request dao.DeploymentCloneRequest) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tenant, err := d.getService(request.TenantID)
+	if err != nil {
+		return "", fmt.Errorf("clone deployment failed: %s", err)
+	} else if tenant.ParentServiceID != "" {
+		return "", fmt.Errorf("clone deployment failed: service %s is not a tenant", tenant.ID)
+	}
+	if _, err := d.getPool(request.PoolID); err != nil {
+		return "", fmt.Errorf("clone deployment failed: %s", err)
+	}
+	for _, svc := range d.services {
+		if svc.DeploymentID == request.DeploymentID {
+			return "", fmt.Errorf("clone deployment failed: deployment ID %s is already in use", request.DeploymentID)
+		}
+	}
+	if request.SnapshotID != "" {
+		if snapshot, ok := d.snapshots[request.SnapshotID]; !ok || snapshot.TenantID != tenant.ID {
+			return "", fmt.Errorf("clone deployment failed: snapshot %s of tenant %s %s", request.SnapshotID, tenant.ID, ErrNotFound)
+		}
+	}
+	var clone func(svc service.Service, parentID string) string
+	clone = func(svc service.Service, parentID string) string {
+		children := d.getChildren(svc.ID)
+		svc.ID = d.newID("mock-service")
+		svc.ParentServiceID = parentID
+		svc.PoolID = request.PoolID
+		svc.DeploymentID = request.DeploymentID
+		svc.DesiredState = int(service.SVCStop)
+		svc.CreatedAt = d.now()
+		svc.UpdatedAt = svc.CreatedAt
+		svc.Endpoints = append([]service.ServiceEndpoint{}, svc.Endpoints...)
+		for i := range svc.Endpoints {
+			ep := &svc.Endpoints[i]
+			ep.VHostList = append(ep.VHostList[:0:0], ep.VHostList...)
+			ep.PortList = append(ep.PortList[:0:0], ep.PortList...)
+			if !request.EnablePublicEndpoints {
+				for j := range ep.VHostList {
+					ep.VHostList[j].Enabled = false
+				}
+				for j := range ep.PortList {
+					ep.PortList[j].Enabled = false
+				}
+			}
+		}
+		d.services[svc.ID] = svc
+		for _, child := range children {
+			clone(child, svc.ID)
+		}
+		return svc.ID
+	}
+	return clone(*tenant, ""), nil
+}
+
+// RemoveService removes a service and all of its children
+func (d *Driver) RemoveService(id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.getService(id); err != nil {
+		return fmt.Errorf("could not remove service %s: %s", id, err)
+	}
+	var remove func(string)
+	remove = func(id string) {
+		for _, child := range d.getChildren(id) {
+			remove(child.ID)
+		}
+		delete(d.services, id)
+		delete(d.history, id)
+	}
+	remove(id)
+	return nil
+}
+
+// UpdateService replaces an existing service with the json or yaml service read
+// from the reader
+func (d *Driver) UpdateService(reader io.Reader) (*service.Service, error) {
+	var svc service.Service
+	if err := api.DecodeData(reader, &svc); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.getService(svc.ID); err != nil {
+		return nil, err
+	}
+	svc.UpdatedAt = d.now()
+	d.services[svc.ID] = svc
+	return &svc, nil
+}
+
+// scheduleService sets the desired state of a service, and of its auto-launch
+// children if autoLaunch is set.  If from is given, only services currently in
+// one of those states are changed.  It returns the number of services
+// affected.
+func (d *Driver) scheduleService(config api.SchedulerConfig, state service.DesiredState, from ...service.DesiredState) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	serviceIDs := config.ServiceIDs
+	if config.ServiceID != "" || len(serviceIDs) == 0 {
+		serviceIDs = append([]string{config.ServiceID}, serviceIDs...)
+	}
+	var svcs []service.Service
+	for _, serviceID := range serviceIDs {
+		svc, err := d.getService(serviceID)
+		if err != nil {
+			return 0, err
+		}
+		svcs = append(svcs, *svc)
+	}
+
+	affected := 0
+	var schedule func(svc service.Service)
+	schedule = func(svc service.Service) {
+		changes := len(from) == 0
+		for _, f := range from {
+			changes = changes || svc.DesiredState == int(f)
+		}
+		if changes {
+			d.history[svc.ID] = append(d.history[svc.ID], statehistory.Change{
+				Time:   d.now(),
+				From:   service.DesiredState(svc.DesiredState),
+				To:     state,
+				Source: statehistory.SourceCLI,
+			})
+			svc.DesiredState = int(state)
+			d.services[svc.ID] = svc
+			affected++
+		}
+		if !config.AutoLaunch {
+			return
+		}
+		for _, child := range d.getChildren(svc.ID) {
+			if child.Launch == commons.MANUAL && state != service.SVCStop {
+				continue
+			}
+			schedule(child)
+		}
+	}
+	for _, svc := range svcs {
+		schedule(d.services[svc.ID])
+	}
+	return affected, nil
+}
+
+// SetServicePriority updates the priority class of a service
+func (d *Driver) SetServicePriority(serviceID, priority string) (*service.Service, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	svc, err := d.getService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := service.PriorityRank(priority); !ok {
+		return nil, fmt.Errorf("service priority must be one of %s", strings.Join(service.Priorities, ", "))
+	}
+	svc.Priority = priority
+	d.services[svc.ID] = *svc
+	return svc, nil
+}
+
+// StartService schedules a service to run
+func (d *Driver) StartService(config api.SchedulerConfig) (int, error) {
+	return d.scheduleService(config, service.SVCRun)
+}
+
+// RestartService schedules a service to run; the mock services restart
+// immediately
+func (d *Driver) RestartService(config api.SchedulerConfig) (int, error) {
+	return d.scheduleService(config, service.SVCRun)
+}
+
+// StopService schedules a service to stop
+func (d *Driver) StopService(config api.SchedulerConfig) (int, error) {
+	return d.scheduleService(config, service.SVCStop)
+}
+
+// PauseService schedules a running service to pause
+func (d *Driver) PauseService(config api.SchedulerConfig) (int, error) {
+	return d.scheduleService(config, service.SVCPause, service.SVCRun)
+}
+
+// ResumeService schedules a paused service to run
+func (d *Driver) ResumeService(config api.SchedulerConfig) (int, error) {
+	return d.scheduleService(config, service.SVCRun, service.SVCPause)
+}
+
+// WaitService checks that the services, and their children if recursive is
+// set, are in the desired state; the mock services only change when the
+// driver is called, so there is nothing to wait for.
+func (d *Driver) WaitService(serviceIDs []string, state service.DesiredState, timeout time.Duration, recursive bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var pending []string
+	var wait func(svc service.Service)
+	wait = func(svc service.Service) {
+		if svc.DesiredState != int(state) {
+			pending = append(pending, svc.ID)
+		}
+		if !recursive {
+			return
+		}
+		for _, child := range d.getChildren(svc.ID) {
+			wait(child)
+		}
+	}
+	for _, serviceID := range serviceIDs {
+		svc, err := d.getService(serviceID)
+		if err != nil {
+			return err
+		}
+		wait(*svc)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("timeout waiting for service(s) %s to %s", strings.Join(pending, ", "), state)
+	}
+	return nil
+}
+
+// GetServiceStateHistory returns the desired state changes made through the
+// driver
+func (d *Driver) GetServiceStateHistory(serviceID string) ([]statehistory.Change, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.getService(serviceID); err != nil {
+		return nil, err
+	}
+	return append([]statehistory.Change{}, d.history[serviceID]...), nil
+}
+
+// ClearEmergencyShutdown clears the emergency flag of a service and its
+// children
+func (d *Driver) ClearEmergencyShutdown(serviceID string) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	svc, err := d.getService(serviceID)
+	if err != nil {
+		return 0, err
+	}
+
+	cleared := 0
+	var clear func(svc service.Service)
+	clear = func(svc service.Service) {
+		if svc.EmergencyShutdown {
+			svc.EmergencyShutdown = false
+			d.services[svc.ID] = svc
+			cleared++
+		}
+		for _, child := range d.getChildren(svc.ID) {
+			clear(child)
+		}
+	}
+	clear(*svc)
+	return cleared, nil
+}
+
+// AssignIP validates the service; the mock services have no configurable
+// endpoints, so there is nothing to assign
+func (d *Driver) AssignIP(config api.IPConfig) error {
+	_, err := d.GetService(config.ServiceID)
+	return err
+}
+
+// RebalanceIPs returns no reassignments
+func (d *Driver) RebalanceIPs(poolID string) ([]addressassignment.Reassignment, error) {
+	if _, err := d.GetResourcePool(poolID); err != nil {
+		return nil, err
+	}
+	return []addressassignment.Reassignment{}, nil
+}
+
+// AssignDeploymentIPs returns no planned assignments
+func (d *Driver) AssignDeploymentIPs(deploymentID string, dryRun bool) ([]addressassignment.PlannedAssignment, error) {
+	return []addressassignment.PlannedAssignment{}, nil
+}
+
+// GetEndpoints returns no endpoints
+func (d *Driver) GetEndpoints(serviceID string, reportImports, reportExports, validate bool) ([]applicationendpoint.EndpointReport, error) {
+	if _, err := d.GetService(serviceID); err != nil {
+		return nil, err
+	}
+	return []applicationendpoint.EndpointReport{}, nil
+}
+
+// GetEndpointStats returns no endpoint stats
+func (d *Driver) GetEndpointStats(serviceID string) ([]applicationendpoint.EndpointStats, error) {
+	if _, err := d.GetService(serviceID); err != nil {
+		return nil, err
+	}
+	return []applicationendpoint.EndpointStats{}, nil
+}
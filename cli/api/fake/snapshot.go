@@ -0,0 +1,202 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/service"
+)
+
+type snapshotsByID []dao.SnapshotInfo
+
+func (s snapshotsByID) Len() int           { return len(s) }
+func (s snapshotsByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s snapshotsByID) Less(i, j int) bool { return s[i].SnapshotID < s[j].SnapshotID }
+
+// getSnapshotByTag returns the snapshot of a tenant with the given tag; the
+// caller must hold the lock.
+func (d *Driver) getSnapshotByTag(tenantID, tag string) (*dao.SnapshotInfo, bool) {
+	for _, snapshot := range d.snapshots {
+		if snapshot.TenantID != tenantID {
+			continue
+		}
+		for _, t := range snapshot.Tags {
+			if t == tag {
+				return &snapshot, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// GetSnapshots returns all snapshots ordered by id
+func (d *Driver) GetSnapshots() ([]dao.SnapshotInfo, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var snapshots []dao.SnapshotInfo
+	for _, snapshot := range d.snapshots {
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Sort(snapshotsByID(snapshots))
+	return snapshots, nil
+}
+
+// GetSnapshotsByServiceID returns the snapshots of the tenant of a service
+func (d *Driver) GetSnapshotsByServiceID(serviceID string) ([]dao.SnapshotInfo, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tenantID, err := d.getTenantID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []dao.SnapshotInfo
+	for _, snapshot := range d.snapshots {
+		if snapshot.TenantID == tenantID {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.Sort(snapshotsByID(snapshots))
+	return snapshots, nil
+}
+
+// GetSnapshotByServiceIDAndTag returns the id of the tagged snapshot
+func (d *Driver) GetSnapshotByServiceIDAndTag(serviceID string, tag string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tenantID, err := d.getTenantID(serviceID)
+	if err != nil {
+		return "", err
+	}
+	snapshot, ok := d.getSnapshotByTag(tenantID, tag)
+	if !ok {
+		return "", fmt.Errorf("snapshot with tag %s %s", tag, ErrNotFound)
+	}
+	return snapshot.SnapshotID, nil
+}
+
+// AddSnapshot snapshots the tenant of a service
+func (d *Driver) AddSnapshot(config api.SnapshotConfig) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tenantID, err := d.getTenantID(config.ServiceID)
+	if err != nil {
+		return "", err
+	}
+	snapshot := dao.SnapshotInfo{
+		TenantID:    tenantID,
+		Description: config.Message,
+		Created:     d.now().UTC(),
+	}
+	snapshot.SnapshotID = fmt.Sprintf("%s_%s", tenantID, snapshot.Created.Format("20060102-150405"))
+	if _, ok := d.snapshots[snapshot.SnapshotID]; ok {
+		return "", fmt.Errorf("snapshot %s already exists", snapshot.SnapshotID)
+	}
+	if config.Tag != "" {
+		if _, ok := d.getSnapshotByTag(tenantID, config.Tag); ok {
+			return "", fmt.Errorf("tag %s is already in use", config.Tag)
+		}
+		snapshot.Tags = []string{config.Tag}
+	}
+	d.snapshots[snapshot.SnapshotID] = snapshot
+	var svcs []service.Service
+	for _, svc := range d.services {
+		if id, _ := d.getTenantID(svc.ID); id == tenantID {
+			svcs = append(svcs, svc)
+		}
+	}
+	d.snapsvcs[snapshot.SnapshotID] = svcs
+	return snapshot.SnapshotID, nil
+}
+
+// GetSnapshotService returns the definition of a service when the snapshot
+// was taken
+func (d *Driver) GetSnapshotService(snapshotID, serviceID string) (*service.Service, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.snapshots[snapshotID]; !ok {
+		return nil, fmt.Errorf("snapshot %s %s", snapshotID, ErrNotFound)
+	}
+	for _, svc := range d.snapsvcs[snapshotID] {
+		if svc.ID == serviceID {
+			return &svc, nil
+		}
+	}
+	return nil, fmt.Errorf("service %s is not in snapshot %s", serviceID, snapshotID)
+}
+
+// RemoveSnapshot deletes a snapshot
+func (d *Driver) RemoveSnapshot(snapshotID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.snapshots[snapshotID]; !ok {
+		return fmt.Errorf("snapshot %s %s", snapshotID, ErrNotFound)
+	}
+	delete(d.snapshots, snapshotID)
+	delete(d.snapsvcs, snapshotID)
+	return nil
+}
+
+// Rollback validates the snapshot; the mock services are not changed
+func (d *Driver) Rollback(snapshotID string, forceRestart bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.snapshots[snapshotID]; !ok {
+		return fmt.Errorf("snapshot %s %s", snapshotID, ErrNotFound)
+	}
+	return nil
+}
+
+// TagSnapshot adds a tag to a snapshot
+func (d *Driver) TagSnapshot(snapshotID string, tagName string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	snapshot, ok := d.snapshots[snapshotID]
+	if !ok {
+		return fmt.Errorf("snapshot %s %s", snapshotID, ErrNotFound)
+	}
+	if _, ok := d.getSnapshotByTag(snapshot.TenantID, tagName); ok {
+		return fmt.Errorf("tag %s is already in use", tagName)
+	}
+	snapshot.Tags = append(snapshot.Tags, tagName)
+	d.snapshots[snapshotID] = snapshot
+	return nil
+}
+
+// RemoveSnapshotTag removes a tag from the tenant's snapshot and returns the
+// id of the snapshot
+func (d *Driver) RemoveSnapshotTag(serviceID string, tagName string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tenantID, err := d.getTenantID(serviceID)
+	if err != nil {
+		return "", err
+	}
+	snapshot, ok := d.getSnapshotByTag(tenantID, tagName)
+	if !ok {
+		return "", fmt.Errorf("snapshot with tag %s %s", tagName, ErrNotFound)
+	}
+	var tags []string
+	for _, t := range snapshot.Tags {
+		if t != tagName {
+			tags = append(tags, t)
+		}
+	}
+	snapshot.Tags = tags
+	d.snapshots[snapshot.SnapshotID] = *snapshot
+	return snapshot.SnapshotID, nil
+}
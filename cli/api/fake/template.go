@@ -0,0 +1,132 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	template "github.com/control-center/serviced/domain/servicetemplate"
+)
+
+// GetServiceTemplates returns all templates ordered by id
+func (d *Driver) GetServiceTemplates() ([]template.ServiceTemplate, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var ids []string
+	for id := range d.templates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	templates := make([]template.ServiceTemplate, len(ids))
+	for i, id := range ids {
+		templates[i] = d.templates[id]
+	}
+	return templates, nil
+}
+
+// GetServiceTemplate returns a template by id
+func (d *Driver) GetServiceTemplate(id string) (*template.ServiceTemplate, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	t, ok := d.templates[id]
+	if !ok {
+		return nil, fmt.Errorf("template %s %s", id, ErrNotFound)
+	}
+	return &t, nil
+}
+
+// AddServiceTemplate adds the json or yaml template read from the reader
+func (d *Driver) AddServiceTemplate(reader io.Reader) (*template.ServiceTemplate, error) {
+	var t template.ServiceTemplate
+	if err := api.DecodeData(reader, &t); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	t.ID = d.newID("mock-template")
+	d.templates[t.ID] = t
+	return &t, nil
+}
+
+// ValidateServiceTemplate lints the template; this does not require a master
+func (d *Driver) ValidateServiceTemplate(reader io.Reader) ([]template.LintIssue, error) {
+	return api.New().ValidateServiceTemplate(reader)
+}
+
+// RemoveServiceTemplate removes a template by id
+func (d *Driver) RemoveServiceTemplate(id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.templates[id]; !ok {
+		return fmt.Errorf("template %s %s", id, ErrNotFound)
+	}
+	delete(d.templates, id)
+	return nil
+}
+
+// CompileServiceTemplate builds a template from a directory; this does not
+// require a master
+func (d *Driver) CompileServiceTemplate(config api.CompileTemplateConfig) (*template.ServiceTemplate, error) {
+	return api.New().CompileServiceTemplate(config)
+}
+
+// DeployServiceTemplate creates the services of a template in a pool
+func (d *Driver) DeployServiceTemplate(config api.DeployTemplateConfig) ([]service.Service, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	t, ok := d.templates[config.ID]
+	if !ok {
+		return nil, fmt.Errorf("template %s %s", config.ID, ErrNotFound)
+	}
+	if _, ok := d.pools[config.PoolID]; !ok {
+		return nil, fmt.Errorf("pool %s %s", config.PoolID, ErrNotFound)
+	}
+	for _, svc := range d.services {
+		if svc.DeploymentID == config.DeploymentID {
+			return nil, fmt.Errorf("deployment %s already exists", config.DeploymentID)
+		}
+	}
+
+	var svcs []service.Service
+	var deploy func(sd servicedefinition.ServiceDefinition, parentID string) error
+	deploy = func(sd servicedefinition.ServiceDefinition, parentID string) error {
+		svc, err := service.BuildService(sd, parentID, config.PoolID, int(service.SVCStop), config.DeploymentID)
+		if err != nil {
+			return err
+		}
+		svc.ID = d.newID("mock-service")
+		svc.CreatedAt = d.now()
+		svc.UpdatedAt = svc.CreatedAt
+		d.services[svc.ID] = *svc
+		svcs = append(svcs, *svc)
+		for _, child := range sd.Services {
+			if err := deploy(child, svc.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, sd := range t.Services {
+		if err := deploy(sd, ""); err != nil {
+			return nil, err
+		}
+	}
+	return svcs, nil
+}
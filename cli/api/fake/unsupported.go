@@ -0,0 +1,292 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/coordinator/storage"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backup"
+	"github.com/control-center/serviced/domain/dbmigration"
+	"github.com/control-center/serviced/domain/maintenance"
+	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/schedule"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/isvcs"
+	"github.com/control-center/serviced/script"
+	"github.com/control-center/serviced/volume"
+	"github.com/control-center/serviced/zzk/ensemble"
+	"github.com/control-center/serviced/zzk/ha"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// The operations below require a running daemon, docker, or a real
+// filesystem and are not simulated.
+
+// StartServer is not supported
+func (d *Driver) StartServer() error {
+	return ErrNotSupported
+}
+
+// ServicedHealthCheck is not supported
+func (d *Driver) ServicedHealthCheck(IServiceNames []string) ([]isvcs.IServiceHealthResult, error) {
+	return nil, ErrNotSupported
+}
+
+// RestartISvc is not supported
+func (d *Driver) RestartISvc(name string) ([]string, error) {
+	return nil, ErrNotSupported
+}
+
+// GetMasterHAStatus is not supported
+func (d *Driver) GetMasterHAStatus() (*ha.ClusterStatus, error) {
+	return nil, ErrNotSupported
+}
+
+// GetZKEnsembleStatus is not supported
+func (d *Driver) GetZKEnsembleStatus() (*ensemble.Status, error) {
+	return nil, ErrNotSupported
+}
+
+// AddZKEnsembleServer is not supported
+func (d *Driver) AddZKEnsembleServer(server ensemble.Server, force bool) (*ensemble.Status, error) {
+	return nil, ErrNotSupported
+}
+
+// RemoveZKEnsembleServer is not supported
+func (d *Driver) RemoveZKEnsembleServer(id string, force bool) (*ensemble.Status, error) {
+	return nil, ErrNotSupported
+}
+
+// CheckConsistency is not supported
+func (d *Driver) CheckConsistency(repair bool) (*zkservice.ConsistencyReport, error) {
+	return nil, ErrNotSupported
+}
+
+// StartShell is not supported
+func (d *Driver) StartShell(config api.ShellConfig) error {
+	return ErrNotSupported
+}
+
+// RunShell is not supported
+func (d *Driver) RunShell(config api.ShellConfig, stopChan chan struct{}) (int, error) {
+	return 1, ErrNotSupported
+}
+
+// RunMigration is not supported
+func (d *Driver) RunMigration(config api.MigrationConfig) (*dao.ServiceMigrationRequest, error) {
+	return nil, ErrNotSupported
+}
+
+// Backup is not supported
+func (d *Driver) Backup(dirpath string, excludes []string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// ListBackups is not supported
+func (d *Driver) ListBackups() ([]backup.Backup, error) {
+	return nil, ErrNotSupported
+}
+
+// EstimateBackup is not supported
+func (d *Driver) EstimateBackup(excludes []string) (*dfs.BackupEstimate, error) {
+	return nil, ErrNotSupported
+}
+
+// CleanupFailedRestore is not supported
+func (d *Driver) CleanupFailedRestore(dryRun bool) (*dfs.RestoreQuarantine, error) {
+	return nil, ErrNotSupported
+}
+
+// InspectBackup is not supported
+func (d *Driver) InspectBackup(path string) (*dfs.BackupInspection, error) {
+	return nil, ErrNotSupported
+}
+
+// AddMaintenanceWindow is not supported
+func (d *Driver) AddMaintenanceWindow(window maintenance.Window) (string, error) {
+	return "", ErrNotSupported
+}
+
+// RemoveMaintenanceWindow is not supported
+func (d *Driver) RemoveMaintenanceWindow(windowID string) error {
+	return ErrNotSupported
+}
+
+// ListMaintenanceWindows is not supported
+func (d *Driver) ListMaintenanceWindows() ([]maintenance.Window, error) {
+	return nil, ErrNotSupported
+}
+
+// AddScheduleProfile is not supported
+func (d *Driver) AddScheduleProfile(profile schedule.Profile) (string, error) {
+	return "", ErrNotSupported
+}
+
+// RemoveScheduleProfile is not supported
+func (d *Driver) RemoveScheduleProfile(profileID string) error {
+	return ErrNotSupported
+}
+
+// ListScheduleProfiles is not supported
+func (d *Driver) ListScheduleProfiles() ([]schedule.Profile, error) {
+	return nil, ErrNotSupported
+}
+
+// GetServiceConfigConflicts is not supported
+func (d *Driver) GetServiceConfigConflicts(serviceID string) ([]service.ConfigConflict, error) {
+	return nil, ErrNotSupported
+}
+
+// ResolveServiceConfigConflict is not supported
+func (d *Driver) ResolveServiceConfigConflict(fileID, resolution, content string) error {
+	return ErrNotSupported
+}
+
+// GetDatastoreMigrations is not supported
+func (d *Driver) GetDatastoreMigrations() ([]dbmigration.Status, error) {
+	return nil, ErrNotSupported
+}
+
+// Restore is not supported
+func (d *Driver) Restore(path string, force []string) error {
+	return ErrNotSupported
+}
+
+// ResetRegistry is not supported
+func (d *Driver) ResetRegistry() error {
+	return ErrNotSupported
+}
+
+// RegistrySync is not supported
+func (d *Driver) RegistrySync() error {
+	return ErrNotSupported
+}
+
+// UpgradeRegistry is not supported
+func (d *Driver) UpgradeRegistry(endpoint string, override bool) error {
+	return ErrNotSupported
+}
+
+// MigrateRegistry is not supported
+func (d *Driver) MigrateRegistry(fromVersion, toVersion int, override bool) error {
+	return ErrNotSupported
+}
+
+// ListRegistryImages is not supported
+func (d *Driver) ListRegistryImages(tenantID string) ([]registry.ImageDetails, error) {
+	return nil, ErrNotSupported
+}
+
+// InspectRegistryImage is not supported
+func (d *Driver) InspectRegistryImage(image string) (*registry.ImageDetails, error) {
+	return nil, ErrNotSupported
+}
+
+// PromoteImage is not supported
+func (d *Driver) PromoteImage(image, tenantID, tag string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// DockerOverride is not supported
+func (d *Driver) DockerOverride(newImage string, oldImage string) error {
+	return ErrNotSupported
+}
+
+// ExportLogs is not supported
+func (d *Driver) ExportLogs(config api.ExportLogsConfig) error {
+	return ErrNotSupported
+}
+
+// SetLogLevel is not supported
+func (d *Driver) SetLogLevel(hostID, component, level string) error {
+	return ErrNotSupported
+}
+
+// GetLogLevels is not supported
+func (d *Driver) GetLogLevels(hostID string) (map[string]string, error) {
+	return nil, ErrNotSupported
+}
+
+// PostMetric is not supported
+func (d *Driver) PostMetric(metricName string, metricValue string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// ScriptRun is not supported
+func (d *Driver) ScriptRun(fileName string, config *script.Config, stopChan chan struct{}) error {
+	return ErrNotSupported
+}
+
+// ScriptParse parses a script file; this does not require a master
+func (d *Driver) ScriptParse(fileName string, config *script.Config) error {
+	return api.New().ScriptParse(fileName, config)
+}
+
+// GetVolumeStatus is not supported
+func (d *Driver) GetVolumeStatus() (*volume.Statuses, error) {
+	return nil, ErrNotSupported
+}
+
+// GetDFSStats is not supported
+func (d *Driver) GetDFSStats() (*dfs.Stats, error) {
+	return nil, ErrNotSupported
+}
+
+// FreezeDFS is not supported
+func (d *Driver) FreezeDFS(reason string) error {
+	return ErrNotSupported
+}
+
+// ThawDFS is not supported
+func (d *Driver) ThawDFS() error {
+	return ErrNotSupported
+}
+
+// GetDFSFreezeStatus is not supported
+func (d *Driver) GetDFSFreezeStatus() (*storage.FreezeStatus, error) {
+	return nil, ErrNotSupported
+}
+
+// AddPublicEndpointPort is not supported
+func (d *Driver) AddPublicEndpointPort(serviceid, endpointName, portAddr string, usetls bool, protocol string, isEnabled, restart bool) (*servicedefinition.Port, error) {
+	return nil, ErrNotSupported
+}
+
+// RemovePublicEndpointPort is not supported
+func (d *Driver) RemovePublicEndpointPort(serviceid, endpointName, portAddr string) error {
+	return ErrNotSupported
+}
+
+// EnablePublicEndpointPort is not supported
+func (d *Driver) EnablePublicEndpointPort(serviceid, endpointName, portAddr string, isEnabled bool) error {
+	return ErrNotSupported
+}
+
+// AddPublicEndpointVHost is not supported
+func (d *Driver) AddPublicEndpointVHost(serviceid, endpointName, vhost string, isEnabled, restart bool) (*servicedefinition.VHost, error) {
+	return nil, ErrNotSupported
+}
+
+// RemovePublicEndpointVHost is not supported
+func (d *Driver) RemovePublicEndpointVHost(serviceid, endpointName, vhost string) error {
+	return ErrNotSupported
+}
+
+// EnablePublicEndpointVHost is not supported
+func (d *Driver) EnablePublicEndpointVHost(serviceid, endpointName, vhost string, isEnabled bool) error {
+	return ErrNotSupported
+}
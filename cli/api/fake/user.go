@@ -0,0 +1,39 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import "fmt"
+
+// GetUserTenants returns the tenants assigned to a user
+func (d *Driver) GetUserTenants(userName string) ([]string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if tenantIDs, ok := d.users[userName]; ok {
+		return append([]string{}, tenantIDs...), nil
+	}
+	return []string{}, nil
+}
+
+// SetUserTenants assigns tenants to a user
+func (d *Driver) SetUserTenants(userName string, tenantIDs []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, tenantID := range tenantIDs {
+		if svc, ok := d.services[tenantID]; !ok || svc.ParentServiceID != "" {
+			return fmt.Errorf("tenant %s not found", tenantID)
+		}
+	}
+	d.users[userName] = append([]string{}, tenantIDs...)
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Formats of the data printed and read by the cli
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// ValidateFormat returns an error if the data format is not supported
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatJSON, FormatYAML:
+		return nil
+	}
+	return fmt.Errorf("format %s must be %s or %s", format, FormatJSON, FormatYAML)
+}
+
+// MarshalFormat marshals a value as indented json or as yaml.  The yaml is
+// converted from the json, so that it has the same fields in the same order.
+func MarshalFormat(v interface{}, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(v, " ", "  ")
+	if err != nil || format != FormatYAML {
+		return data, err
+	}
+	return JSONToYAML(data)
+}
+
+// JSONToYAML converts a json document to yaml, keeping the order of the keys
+func JSONToYAML(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	v, err := decodeJSONValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(v)
+}
+
+// decodeJSONValue reads the next json value as a value that marshals to
+// equivalent yaml; objects become ordered yaml.MapSlices.
+func decodeJSONValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := yaml.MapSlice{}
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeJSONValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				obj = append(obj, yaml.MapItem{Key: key, Value: value})
+			}
+			_, err := decoder.Token()
+			return obj, err
+		case '[':
+			arr := []interface{}{}
+			for decoder.More() {
+				value, err := decodeJSONValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, value)
+			}
+			_, err := decoder.Token()
+			return arr, err
+		}
+		return nil, fmt.Errorf("unexpected %s", t)
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	}
+	return token, nil
+}
+
+// YAMLToJSON converts a yaml document to json
+func YAMLToJSON(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	v, err := jsonValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// jsonValue converts the maps decoded from yaml, which may have keys of any
+// type, to maps that json can marshal.
+func jsonValue(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(t))
+		for key, value := range t {
+			k, ok := key.(string)
+			if !ok {
+				k = fmt.Sprintf("%v", key)
+			}
+			value, err := jsonValue(value)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = value
+		}
+		return obj, nil
+	case []interface{}:
+		arr := make([]interface{}, len(t))
+		for i, value := range t {
+			value, err := jsonValue(value)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = value
+		}
+		return arr, nil
+	}
+	return v, nil
+}
+
+// isJSON returns true if the data looks like a json object or array rather
+// than yaml
+func isJSON(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && (data[0] == '{' || data[0] == '[')
+}
+
+// DecodeData unmarshals a json or yaml document from a reader
+func DecodeData(reader io.Reader, v interface{}) error {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	if !isJSON(data) {
+		if data, err = YAMLToJSON(data); err != nil {
+			return fmt.Errorf("could not unmarshal yaml: %s", err)
+		}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("could not unmarshal json: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package api
+
+import (
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAPISuite) TestJSONToYAML(c *C) {
+	data, err := JSONToYAML([]byte(`{"Name": "zope", "Instances": 2, "Ratio": 0.5, "Tags": ["a", "b"], "Parent": null, "Launch": {"Auto": true}}`))
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, strings.Join([]string{
+		"Name: zope",
+		"Instances: 2",
+		"Ratio: 0.5",
+		"Tags:",
+		"- a",
+		"- b",
+		"Parent: null",
+		"Launch:",
+		"  Auto: true",
+		"",
+	}, "\n"))
+}
+
+func (s *TestAPISuite) TestYAMLToJSON(c *C) {
+	data, err := YAMLToJSON([]byte("Name: zope\nInstances: 2\nTags:\n- a\nLaunch:\n  Auto: true\n"))
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, `{"Instances":2,"Launch":{"Auto":true},"Name":"zope","Tags":["a"]}`)
+}
+
+func (s *TestAPISuite) TestDecodeData(c *C) {
+	type value struct {
+		Name      string
+		Instances int
+	}
+	for _, data := range []string{
+		`{"Name": "zope", "Instances": 2}`,
+		"Name: zope\nInstances: 2\n",
+	} {
+		var v value
+		c.Assert(DecodeData(strings.NewReader(data), &v), IsNil)
+		c.Check(v, Equals, value{Name: "zope", Instances: 2})
+	}
+
+	var v value
+	c.Check(DecodeData(strings.NewReader("Name: [zope"), &v), ErrorMatches, "could not unmarshal yaml: .*")
+	c.Check(DecodeData(strings.NewReader(`{"Name": 2}`), &v), ErrorMatches, "could not unmarshal json: .*")
+}
+
+func (s *TestAPISuite) TestValidateFormat(c *C) {
+	c.Check(ValidateFormat(FormatJSON), IsNil)
+	c.Check(ValidateFormat(FormatYAML), IsNil)
+	c.Check(ValidateFormat("xml"), ErrorMatches, "format xml must be json or yaml")
+}
@@ -29,3 +29,13 @@ func (a *api) ServicedHealthCheck(IServiceNames []string) ([]isvcs.IServiceHealt
 		return results, nil
 	}
 }
+
+// RestartISvc restarts the internal services matching the name with the
+// resource settings in the master's configuration
+func (a *api) RestartISvc(name string) ([]string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.RestartISvc(name)
+}
@@ -15,10 +15,13 @@ package api
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/control-center/serviced/auth"
@@ -26,6 +29,7 @@ import (
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/metrics"
+	"github.com/control-center/serviced/node"
 	"github.com/control-center/serviced/rpc/agent"
 	"github.com/control-center/serviced/utils"
 )
@@ -43,6 +47,26 @@ type HostUpdateConfig struct {
 	Memory string
 }
 
+// SSHHostKey is the fingerprint of one of a host's ssh keys
+type SSHHostKey struct {
+	Type        string
+	Fingerprint string
+}
+
+// sshHostKeyEntry is a host key as it appears in a known_hosts file
+type sshHostKeyEntry struct {
+	SSHHostKey
+	Line string
+}
+
+// HostSSHConfig describes an ssh session to a registered host
+type HostSSHConfig struct {
+	Host    *host.Host
+	User    string
+	Options []string
+	Command []string
+}
+
 // Returns a list of all hosts
 func (a *api) GetHosts() ([]host.Host, error) {
 	client, err := a.connectMaster()
@@ -171,6 +195,65 @@ func (a *api) ResetHostKey(id string) ([]byte, error) {
 	return client.ResetHostKey(id)
 }
 
+// Returns the usage of a host's storage pools
+func (a *api) GetHostStorage(hostID string) (*host.StorageHealth, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetHostStorage(hostID)
+}
+
+// Returns the event history of a host
+func (a *api) GetHostEvents(hostID string) ([]host.Event, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetHostEvents(hostID)
+}
+
+// SelfTestHost validates that a host can reach the services it needs to run
+// as a delegate.  The self-test runs on this host if no host is given.
+func (a *api) SelfTestHost(hostID string) (*host.SelfTestReport, error) {
+	if hostID == "" {
+		myHostID, err := utils.HostID()
+		if err != nil {
+			return nil, err
+		}
+		report := node.RunSelfTest(selfTestConfig(myHostID, config.GetOptions()))
+		return &report, nil
+	}
+	client, err := a.connectHostAgent(hostID)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return client.SelfTest()
+}
+
+// selfTestConfig describes what the self-test of a host validates
+func selfTestConfig(hostID string, options config.Options) node.SelfTestConfig {
+	return node.SelfTestConfig{
+		HostID:         hostID,
+		DockerRegistry: options.DockerRegistry,
+		VolumesPath:    options.VolumesPath,
+		MuxPort:        options.MuxPort,
+		Zookeepers:     options.Zookeepers,
+		MasterAddress:  options.Endpoint,
+		MaxClockSkew:   time.Duration(options.MaxClockSkew) * time.Second,
+	}
+}
+
+// Upgrade serviced on the delegates
+func (a *api) UpgradeDelegates(req host.UpgradeRequest) ([]host.UpgradeResult, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.UpgradeDelegates(req)
+}
+
 // Write delegate keys to disk
 func (a *api) RegisterHost(keydata []byte) error {
 	keyfile := filepath.Join(config.GetOptions().EtcPath, auth.DelegateKeyFileName)
@@ -181,19 +264,224 @@ func (a *api) RegisterHost(keydata []byte) error {
 	return ioutil.WriteFile(keyfile, keydata, 0644)
 }
 
-func (a *api) RegisterRemoteHost(h *host.Host, keyData []byte) error {
+// Install delegate keys on a host and verify with the master that the host
+// installed the key generated for it.  If fingerprints are set, the keys are
+// only sent over ssh to a host whose host key has one of the fingerprints.
+func (a *api) RegisterRemoteHost(h *host.Host, keyData []byte, fingerprints []string) error {
 	hostID, err := utils.HostID()
 	if err != nil {
 		return err
 	}
+
+	var installed string
 	if h.ID == hostID {
-		return a.RegisterHost(keyData)
+		if err := a.RegisterHost(keyData); err != nil {
+			return err
+		}
+		if installed, err = auth.DelegateKeyFingerprint(keyData); err != nil {
+			return err
+		}
 	} else {
-		remoteCmd := "serviced host register -"
-		cmd := exec.Command("/usr/bin/ssh", h.IPAddr, "--", remoteCmd)
-		cmd.Stdin = bytes.NewReader(keyData)
-		return cmd.Run()
+		sshPath, err := exec.LookPath("ssh")
+		if err != nil {
+			return err
+		}
+		cmd := []string{sshPath}
+		if len(fingerprints) > 0 {
+			knownHosts, err := writeKnownHosts(h.IPAddr, fingerprints)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(knownHosts)
+			cmd = append(cmd, "-o", "StrictHostKeyChecking=yes", "-o", "UserKnownHostsFile="+knownHosts)
+		}
+		cmd = append(cmd, h.IPAddr, "--", "serviced host register -")
+
+		ssh := exec.Command(cmd[0], cmd[1:]...)
+		ssh.Stdin = bytes.NewReader(keyData)
+		ssh.Stderr = os.Stderr
+		output, err := ssh.Output()
+		if err != nil {
+			return err
+		}
+		if installed = parseRegisteredFingerprint(output); installed == "" {
+			return fmt.Errorf("host %s did not report the fingerprint of its delegate key", h.IPAddr)
+		}
+	}
+
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.VerifyHostKey(h.ID, installed)
+}
+
+// GetSSHHostKeys returns the fingerprints of the ssh host keys that a remote
+// host presents.  Returns nil for the local host, whose keys are not sent
+// over ssh.
+func (a *api) GetSSHHostKeys(h *host.Host) ([]SSHHostKey, error) {
+	hostID, err := utils.HostID()
+	if err != nil {
+		return nil, err
+	}
+	if h.ID == hostID {
+		return nil, nil
+	}
+	entries, err := scanSSHHostKeys(h.IPAddr)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]SSHHostKey, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.SSHHostKey
+	}
+	return keys, nil
+}
+
+// scanSSHHostKeys looks up the ssh host keys of an address and their
+// fingerprints
+func scanSSHHostKeys(address string) ([]sshHostKeyEntry, error) {
+	keyscan, err := exec.LookPath("ssh-keyscan")
+	if err != nil {
+		return nil, err
+	}
+	keygen, err := exec.LookPath("ssh-keygen")
+	if err != nil {
+		return nil, err
+	}
+	scan, err := exec.Command(keyscan, address).Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not scan the ssh host keys of %s: %s", address, err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(scan), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("host %s did not present any ssh host keys", address)
+	}
+
+	f, err := ioutil.TempFile("", "serviced-known-hosts-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(strings.Join(lines, "\n") + "\n")
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	output, err := exec.Command(keygen, "-l", "-E", "sha256", "-f", f.Name()).Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not fingerprint the ssh host keys of %s: %s", address, err)
+	}
+	keys, err := parseSSHFingerprints(output)
+	if err != nil {
+		return nil, err
+	} else if len(keys) != len(lines) {
+		return nil, fmt.Errorf("could not fingerprint the ssh host keys of %s", address)
+	}
+
+	entries := make([]sshHostKeyEntry, len(keys))
+	for i := range keys {
+		entries[i] = sshHostKeyEntry{SSHHostKey: keys[i], Line: lines[i]}
+	}
+	return entries, nil
+}
+
+// parseSSHFingerprints parses the output of ssh-keygen -l, which prints a line
+// of the form "BITS FINGERPRINT COMMENT (TYPE)" for each key
+func parseSSHFingerprints(output []byte) ([]SSHHostKey, error) {
+	var keys []SSHHostKey
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !strings.Contains(fields[1], ":") {
+			return nil, fmt.Errorf("could not parse ssh key fingerprint %q", line)
+		}
+		keys = append(keys, SSHHostKey{
+			Type:        strings.Trim(fields[len(fields)-1], "()"),
+			Fingerprint: fields[1],
+		})
+	}
+	return keys, nil
+}
+
+// writeKnownHosts writes a known_hosts file that only trusts the host keys of
+// the address that have one of the fingerprints, and returns its path
+func writeKnownHosts(address string, fingerprints []string) (string, error) {
+	entries, err := scanSSHHostKeys(address)
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, entry := range entries {
+		for _, fingerprint := range fingerprints {
+			if entry.Fingerprint == fingerprint {
+				lines = append(lines, entry.Line)
+				break
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return "", fmt.Errorf("ssh host key of %s does not match the verified fingerprint", address)
+	}
+
+	f, err := ioutil.TempFile("", "serviced-known-hosts-")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// parseRegisteredFingerprint returns the delegate key fingerprint printed by
+// serviced host register
+func parseRegisteredFingerprint(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); strings.HasPrefix(line, "SHA256:") {
+			return line
+		}
+	}
+	return ""
+}
+
+// SSHHost replaces the current process with an ssh session to the host's
+// registered address
+func (a *api) SSHHost(config HostSSHConfig) error {
+	ssh, err := exec.LookPath("ssh")
+	if err != nil {
+		return err
+	}
+	cmd := sshCommand(ssh, config)
+	return syscall.Exec(cmd[0], cmd[0:], os.Environ())
+}
+
+// sshCommand builds the command line of the ssh client at the given path for
+// the given configuration
+func sshCommand(ssh string, config HostSSHConfig) []string {
+	cmd := []string{ssh}
+	for _, opt := range config.Options {
+		cmd = append(cmd, "-o", opt)
+	}
+	if config.User != "" {
+		cmd = append(cmd, "-l", config.User)
+	}
+	if len(config.Command) == 0 {
+		cmd = append(cmd, "-t")
+	}
+	cmd = append(cmd, config.Host.IPAddr)
+	if len(config.Command) > 0 {
+		cmd = append(cmd, "--")
+		cmd = append(cmd, config.Command...)
 	}
+	return cmd
 }
 
 // Output a delegate key file to a given location on disk
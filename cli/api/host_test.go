@@ -0,0 +1,68 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package api
+
+import (
+	"github.com/control-center/serviced/domain/host"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAPISuite) TestParseSSHFingerprints(c *C) {
+	output := []byte(`256 SHA256:o1hvZKr7Hyc1XcXwCZiI9nPnaBQB0xEbcmUNmZEXv6A 10.0.0.5 (ECDSA)
+2048 SHA256:2PZ6a4Ly1mWz/1qaOUpVVu3vHB0Zo4KbVXGVp08xc0E 10.0.0.5 (RSA)
+`)
+	keys, err := parseSSHFingerprints(output)
+	c.Assert(err, IsNil)
+	c.Assert(keys, DeepEquals, []SSHHostKey{
+		{Type: "ECDSA", Fingerprint: "SHA256:o1hvZKr7Hyc1XcXwCZiI9nPnaBQB0xEbcmUNmZEXv6A"},
+		{Type: "RSA", Fingerprint: "SHA256:2PZ6a4Ly1mWz/1qaOUpVVu3vHB0Zo4KbVXGVp08xc0E"},
+	})
+
+	_, err = parseSSHFingerprints([]byte("not a fingerprint"))
+	c.Assert(err, NotNil)
+}
+
+func (s *TestAPISuite) TestParseRegisteredFingerprint(c *C) {
+	output := []byte("Warning: Permanently added '10.0.0.5' (ECDSA) to the list of known hosts.\nSHA256:delegate\n")
+	c.Assert(parseRegisteredFingerprint(output), Equals, "SHA256:delegate")
+
+	// older releases of serviced do not report a fingerprint
+	c.Assert(parseRegisteredFingerprint([]byte("")), Equals, "")
+}
+
+func (s *TestAPISuite) TestSSHCommand(c *C) {
+	h := &host.Host{ID: "deadb10c", Name: "delegate1", IPAddr: "10.0.0.5"}
+
+	// an interactive session
+	cmd := sshCommand("/usr/bin/ssh", HostSSHConfig{Host: h})
+	c.Assert(cmd, DeepEquals, []string{"/usr/bin/ssh", "-t", "10.0.0.5"})
+
+	// a command as another user with extra options
+	cmd = sshCommand("/usr/local/bin/ssh", HostSSHConfig{
+		Host:    h,
+		User:    "admin",
+		Options: []string{"StrictHostKeyChecking=no", "ConnectTimeout=5"},
+		Command: []string{"docker", "ps"},
+	})
+	c.Assert(cmd, DeepEquals, []string{
+		"/usr/local/bin/ssh",
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "ConnectTimeout=5",
+		"-l", "admin",
+		"10.0.0.5",
+		"--", "docker", "ps",
+	})
+}
@@ -15,11 +15,16 @@ package api
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"syscall"
+	"time"
 
 	dockerclient "github.com/control-center/serviced/commons/docker"
+	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -34,6 +39,26 @@ func (a *api) GetServiceInstances(serviceID string) ([]service.Instance, error)
 	return client.GetServiceInstances(serviceID)
 }
 
+// WaitServiceStatus waits until the status of any of the services changes or
+// the timeout expires, and returns the ids of the services that changed.
+func (a *api) WaitServiceStatus(serviceIDs []string, timeout time.Duration) ([]string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.WaitServiceInstances(serviceIDs, timeout)
+}
+
+// GetDeploymentStatus returns a summary of the state of the services in a
+// deployment.
+func (a *api) GetDeploymentStatus(deploymentID string) (*service.DeploymentStatus, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetDeploymentStatus(deploymentID)
+}
+
 // StopServiceInstance stops a running instance of a service.
 func (a *api) StopServiceInstance(serviceID string, instanceID int) error {
 	client, err := a.connectMaster()
@@ -127,6 +152,141 @@ func (a *api) LogsForServiceInstance(serviceID string, instanceID int, command s
 	}
 }
 
+// ServiceLogsConfig selects the logs of the running instances of a service
+type ServiceLogsConfig struct {
+	ServiceID  string
+	InstanceID int       // Instance to follow; all instances if negative
+	Since      time.Time // Only show lines written after this time
+	Tail       int       // Only show the last lines of each instance; all if zero
+}
+
+// logsPollInterval is how often the logs of the instances are read while
+// they are followed
+var logsPollInterval = time.Second
+
+// FollowServiceLogs streams the logs of the running instances of a service
+// to w, prefixing each line with the instance that wrote it.  Instances that
+// start while the logs are followed are picked up; it returns only if the
+// instances of the service cannot be looked up.
+func (a *api) FollowServiceLogs(cfg ServiceLogsConfig, w io.Writer) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	f := newLogFollower(client, cfg, w)
+	for {
+		if err := f.poll(); err != nil {
+			return err
+		}
+		time.Sleep(logsPollInterval)
+	}
+}
+
+// logFollower reads the lines that the instances of a service wrote since it
+// last read them
+type logFollower struct {
+	client  master.ClientInterface
+	cfg     ServiceLogsConfig
+	w       io.Writer
+	since   map[int]time.Time // time of the last line read, by instance
+	failing map[int]bool      // instances whose logs could not be read
+}
+
+func newLogFollower(client master.ClientInterface, cfg ServiceLogsConfig, w io.Writer) *logFollower {
+	return &logFollower{
+		client:  client,
+		cfg:     cfg,
+		w:       w,
+		since:   make(map[int]time.Time),
+		failing: make(map[int]bool),
+	}
+}
+
+// poll writes the lines that each instance wrote since the last poll,
+// interleaved in the order they were written
+func (f *logFollower) poll() error {
+	instances, err := f.client.GetServiceInstances(f.cfg.ServiceID)
+	if err != nil {
+		return err
+	}
+
+	var lines []instanceLogLine
+	for _, inst := range instances {
+		if f.cfg.InstanceID >= 0 && inst.InstanceID != f.cfg.InstanceID {
+			continue
+		}
+		since, seen := f.since[inst.InstanceID]
+		tail := 0
+		if !seen {
+			since, tail = f.cfg.Since, f.cfg.Tail
+		}
+		read, err := f.client.GetInstanceLogs(inst.ServiceID, inst.InstanceID, since, tail)
+		if err != nil {
+			if !f.failing[inst.InstanceID] {
+				fmt.Fprintf(os.Stderr, "could not read the logs of %s/%d: %s\n", inst.ServiceName, inst.InstanceID, err)
+				f.failing[inst.InstanceID] = true
+			}
+			continue
+		}
+		delete(f.failing, inst.InstanceID)
+		prefix := fmt.Sprintf("%s/%d", inst.ServiceName, inst.InstanceID)
+		for _, line := range read {
+			lines = append(lines, instanceLogLine{prefix, line})
+			since = line.Time
+		}
+		f.since[inst.InstanceID] = since
+	}
+
+	sort.Stable(instanceLogLinesByTime(lines))
+	for _, line := range lines {
+		fmt.Fprintf(f.w, "%s | %s\n", line.prefix, line.Text)
+	}
+	return nil
+}
+
+// instanceLogLine is a log line with the instance that wrote it
+type instanceLogLine struct {
+	prefix string
+	service.LogLine
+}
+
+type instanceLogLinesByTime []instanceLogLine
+
+func (s instanceLogLinesByTime) Len() int           { return len(s) }
+func (s instanceLogLinesByTime) Less(i, j int) bool { return s[i].Time.Before(s[j].Time) }
+func (s instanceLogLinesByTime) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// GetPreviousInstanceOutput returns the output of the most recently exited
+// container of a service instance, as buffered by the host that ran it
+func (a *api) GetPreviousInstanceOutput(serviceID string, instanceID int) (*service.InstanceOutput, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetPreviousInstanceOutput(serviceID, instanceID)
+}
+
+// GetInstanceDiagnostics returns the output of the built-in diagnostic actions
+// that were run on a service instance, as saved by the host that ran them
+func (a *api) GetInstanceDiagnostics(serviceID string, instanceID int) ([]service.DiagnosticOutput, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetInstanceDiagnostics(serviceID, instanceID)
+}
+
+// CommitServiceInstance commits the container of a running service instance
+// to the docker registry and snapshots the tenant.  Returns the id of the
+// snapshot.
+func (a *api) CommitServiceInstance(serviceID string, instanceID int, message string) (string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", err
+	}
+	return client.CommitServiceInstance(serviceID, instanceID, message, currentUser(), config.GetOptions().SnapshotSpacePercent)
+}
+
 // SendDockerAction submits an action to a running service instance
 func (a *api) SendDockerAction(serviceID string, instanceID int, action string, args []string) error {
 	client, err := a.connectMaster()
@@ -0,0 +1,75 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package api
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAPISuite) TestLogFollower(c *C) {
+	t0 := time.Date(2016, 5, 4, 3, 2, 1, 0, time.UTC)
+	instances := []service.Instance{
+		{ServiceID: "svc", ServiceName: "zope", InstanceID: 0},
+		{ServiceID: "svc", ServiceName: "zope", InstanceID: 1},
+	}
+	s.mockMasterClient.On("GetServiceInstances", "svc").Return(instances, nil)
+
+	// the first poll is limited by the filters of the config
+	s.mockMasterClient.On("GetInstanceLogs", "svc", 0, t0, 10).Return([]service.LogLine{
+		{Time: t0.Add(1 * time.Second), Text: "zero one"},
+		{Time: t0.Add(3 * time.Second), Text: "zero two", Stderr: true},
+	}, nil).Once()
+	s.mockMasterClient.On("GetInstanceLogs", "svc", 1, t0, 10).Return([]service.LogLine{
+		{Time: t0.Add(2 * time.Second), Text: "one one"},
+	}, nil).Once()
+
+	var out bytes.Buffer
+	f := newLogFollower(s.mockMasterClient, ServiceLogsConfig{ServiceID: "svc", InstanceID: -1, Since: t0, Tail: 10}, &out)
+	c.Assert(f.poll(), IsNil)
+	c.Assert(out.String(), Equals, "zope/0 | zero one\nzope/1 | one one\nzope/0 | zero two\n")
+
+	// later polls read the lines written after the last line read
+	s.mockMasterClient.On("GetInstanceLogs", "svc", 0, t0.Add(3*time.Second), 0).Return([]service.LogLine{
+		{Time: t0.Add(4 * time.Second), Text: "zero three"},
+	}, nil).Once()
+	s.mockMasterClient.On("GetInstanceLogs", "svc", 1, t0.Add(2*time.Second), 0).Return([]service.LogLine{}, nil).Once()
+
+	out.Reset()
+	c.Assert(f.poll(), IsNil)
+	c.Assert(out.String(), Equals, "zope/0 | zero three\n")
+	s.mockMasterClient.AssertExpectations(c)
+}
+
+func (s *TestAPISuite) TestLogFollowerInstance(c *C) {
+	instances := []service.Instance{
+		{ServiceID: "svc", ServiceName: "zope", InstanceID: 0},
+		{ServiceID: "svc", ServiceName: "zope", InstanceID: 1},
+	}
+	s.mockMasterClient.On("GetServiceInstances", "svc").Return(instances, nil)
+	s.mockMasterClient.On("GetInstanceLogs", "svc", 1, time.Time{}, 0).Return([]service.LogLine{
+		{Time: time.Now(), Text: "one one"},
+	}, nil)
+
+	var out bytes.Buffer
+	f := newLogFollower(s.mockMasterClient, ServiceLogsConfig{ServiceID: "svc", InstanceID: 1}, &out)
+	c.Assert(f.poll(), IsNil)
+	c.Assert(out.String(), Equals, "zope/1 | one one\n")
+	s.mockMasterClient.AssertNotCalled(c, "GetInstanceLogs", "svc", 0, time.Time{}, 0)
+}
@@ -15,18 +15,31 @@ package api
 
 import (
 	"io"
+	"time"
 
+	"github.com/control-center/serviced/coordinator/storage"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/backup"
+	"github.com/control-center/serviced/domain/dbmigration"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/maintenance"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/schedule"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	template "github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/statehistory"
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/script"
 	"github.com/control-center/serviced/volume"
+	"github.com/control-center/serviced/zzk/ensemble"
+	"github.com/control-center/serviced/zzk/ha"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 // API is the intermediary between the command-line interface and the dao layer
@@ -35,6 +48,7 @@ type API interface {
 	// Server
 	StartServer() error
 	ServicedHealthCheck(IServiceNames []string) ([]isvcs.IServiceHealthResult, error)
+	RestartISvc(name string) ([]string, error)
 
 	// Hosts
 	GetHosts() ([]host.Host, error)
@@ -46,10 +60,16 @@ type API interface {
 	SetHostMemory(HostUpdateConfig) error
 	GetHostPublicKey(string) ([]byte, error)
 	RegisterHost([]byte) error
-	RegisterRemoteHost(*host.Host, []byte) error
+	RegisterRemoteHost(*host.Host, []byte, []string) error
+	GetSSHHostKeys(*host.Host) ([]SSHHostKey, error)
 	WriteDelegateKey(string, []byte) error
 	AuthenticateHost(string) (string, int64, error)
 	ResetHostKey(string) ([]byte, error)
+	SSHHost(HostSSHConfig) error
+	UpgradeDelegates(host.UpgradeRequest) ([]host.UpgradeResult, error)
+	GetHostStorage(string) (*host.StorageHealth, error)
+	GetHostEvents(string) ([]host.Event, error)
+	SelfTestHost(string) (*host.SelfTestReport, error)
 
 	// Pools
 	GetResourcePools() ([]pool.ResourcePool, error)
@@ -58,32 +78,66 @@ type API interface {
 	RemoveResourcePool(string) error
 	UpdateResourcePool(pool pool.ResourcePool) error
 	GetPoolIPs(string) (*pool.PoolIPs, error)
+	GetPoolPortClaims(string) ([]pool.PortClaim, error)
 	AddVirtualIP(pool.VirtualIP) error
 	RemoveVirtualIP(pool.VirtualIP) error
 
+	// Chaos
+	EnableChaos(poolID string, interval time.Duration) error
+	DisableChaos(poolID string) error
+	GetChaosStatus(poolID string) (*service.ChaosStatus, error)
+
+	// Master
+	GetMasterHAStatus() (*ha.ClusterStatus, error)
+
+	// ZooKeeper
+	GetZKEnsembleStatus() (*ensemble.Status, error)
+	AddZKEnsembleServer(server ensemble.Server, force bool) (*ensemble.Status, error)
+	RemoveZKEnsembleServer(id string, force bool) (*ensemble.Status, error)
+	CheckConsistency(repair bool) (*zkservice.ConsistencyReport, error)
+	CheckNetwork(poolID string) (*host.NetworkReport, error)
+
 	// Services
 	GetServices() ([]service.Service, error)
+	QueryServices(service.ServiceQuery) (*service.ServiceSummaryPage, error)
+	ResolveServicePath(servicePath string) ([]service.ServiceMatch, error)
 	GetServiceStatus(string) (map[string]map[string]interface{}, error)
 	GetService(string) (*service.Service, error)
 	GetServicesByName(string) ([]service.Service, error)
 	AddService(ServiceConfig) (*service.Service, error)
 	CloneService(string, string) (*service.Service, error)
+	CloneDeployment(dao.DeploymentCloneRequest) (string, error)
+	GetServiceConfigConflicts(serviceID string) ([]service.ConfigConflict, error)
+	ResolveServiceConfigConflict(fileID, resolution, content string) error
 	RemoveService(string) error
 	UpdateService(io.Reader) (*service.Service, error)
+	SetServicePriority(serviceID, priority string) (*service.Service, error)
 	StartService(SchedulerConfig) (int, error)
 	RestartService(SchedulerConfig) (int, error)
 	StopService(SchedulerConfig) (int, error)
+	PauseService(SchedulerConfig) (int, error)
+	ResumeService(SchedulerConfig) (int, error)
+	WaitService(serviceIDs []string, state service.DesiredState, timeout time.Duration, recursive bool) error
+	ClearEmergencyShutdown(serviceID string) (int, error)
+	GetServiceStateHistory(serviceID string) ([]statehistory.Change, error)
 	AssignIP(IPConfig) error
+	RebalanceIPs(poolID string) ([]addressassignment.Reassignment, error)
+	AssignDeploymentIPs(deploymentID string, dryRun bool) ([]addressassignment.PlannedAssignment, error)
 	GetEndpoints(serviceID string, reportImports, reportExports, validate bool) ([]applicationendpoint.EndpointReport, error)
+	GetEndpointStats(serviceID string) ([]applicationendpoint.EndpointStats, error)
 
 	// Shell
 	StartShell(ShellConfig) error
 	RunShell(ShellConfig, chan struct{}) (int, error)
 
+	// Migrations
+	RunMigration(MigrationConfig) (*dao.ServiceMigrationRequest, error)
+
 	// Snapshots
 	GetSnapshots() ([]dao.SnapshotInfo, error)
 	GetSnapshotsByServiceID(string) ([]dao.SnapshotInfo, error)
 	GetSnapshotByServiceIDAndTag(string, string) (string, error)
+	GetSnapshotService(snapshotID, serviceID string) (*service.Service, error)
 	AddSnapshot(SnapshotConfig) (string, error)
 	RemoveSnapshot(string) error
 	Rollback(string, bool) error
@@ -94,22 +148,50 @@ type API interface {
 	GetServiceTemplates() ([]template.ServiceTemplate, error)
 	GetServiceTemplate(string) (*template.ServiceTemplate, error)
 	AddServiceTemplate(io.Reader) (*template.ServiceTemplate, error)
+	ValidateServiceTemplate(io.Reader) ([]template.LintIssue, error)
 	RemoveServiceTemplate(string) error
 	CompileServiceTemplate(CompileTemplateConfig) (*template.ServiceTemplate, error)
 	DeployServiceTemplate(DeployTemplateConfig) ([]service.Service, error)
 
 	// Backup & Restore
 	Backup(string, []string) (string, error)
-	Restore(string) error
+	ListBackups() ([]backup.Backup, error)
+	EstimateBackup(excludes []string) (*dfs.BackupEstimate, error)
+	CleanupFailedRestore(dryRun bool) (*dfs.RestoreQuarantine, error)
+	InspectBackup(path string) (*dfs.BackupInspection, error)
+	Restore(string, []string) error
+
+	// Maintenance windows
+	AddMaintenanceWindow(window maintenance.Window) (string, error)
+	RemoveMaintenanceWindow(windowID string) error
+	ListMaintenanceWindows() ([]maintenance.Window, error)
+
+	// Schedule profiles
+	AddScheduleProfile(profile schedule.Profile) (string, error)
+	RemoveScheduleProfile(profileID string) error
+	ListScheduleProfiles() ([]schedule.Profile, error)
+
+	// Users
+	GetUserTenants(userName string) ([]string, error)
+	SetUserTenants(userName string, tenantIDs []string) error
+
+	// Datastore
+	GetDatastoreMigrations() ([]dbmigration.Status, error)
 
 	// Docker
 	ResetRegistry() error
 	RegistrySync() error
 	UpgradeRegistry(endpoint string, override bool) error
+	MigrateRegistry(fromVersion, toVersion int, override bool) error
+	ListRegistryImages(tenantID string) ([]registry.ImageDetails, error)
+	InspectRegistryImage(image string) (*registry.ImageDetails, error)
+	PromoteImage(image, tenantID, tag string) (string, error)
 	DockerOverride(newImage string, oldImage string) error
 
 	// Logs
 	ExportLogs(config ExportLogsConfig) error
+	SetLogLevel(hostID, component, level string) error
+	GetLogLevels(hostID string) (map[string]string, error)
 
 	// Metric
 	PostMetric(metricName string, metricValue string) (string, error)
@@ -121,6 +203,12 @@ type API interface {
 	// Volumes
 	GetVolumeStatus() (*volume.Statuses, error)
 
+	// DFS
+	GetDFSStats() (*dfs.Stats, error)
+	FreezeDFS(reason string) error
+	ThawDFS() error
+	GetDFSFreezeStatus() (*storage.FreezeStatus, error)
+
 	// Public endpoints
 	AddPublicEndpointPort(serviceid, endpointName, portAddr string, usetls bool, protocol string, isEnabled, restart bool) (*servicedefinition.Port, error)
 	RemovePublicEndpointPort(serviceid, endpointName, portAddr string) error
@@ -131,8 +219,14 @@ type API interface {
 
 	// Service Instances
 	GetServiceInstances(serviceID string) ([]service.Instance, error)
+	WaitServiceStatus(serviceIDs []string, timeout time.Duration) ([]string, error)
+	GetDeploymentStatus(deploymentID string) (*service.DeploymentStatus, error)
 	StopServiceInstance(serviceID string, instanceID int) error
 	AttachServiceInstance(serviceID string, instanceID int, command string, args []string) error
+	CommitServiceInstance(serviceID string, instanceID int, message string) (string, error)
 	LogsForServiceInstance(serviceID string, instanceID int, command string, args []string) error
+	FollowServiceLogs(cfg ServiceLogsConfig, w io.Writer) error
+	GetPreviousInstanceOutput(serviceID string, instanceID int) (*service.InstanceOutput, error)
+	GetInstanceDiagnostics(serviceID string, instanceID int) ([]service.DiagnosticOutput, error)
 	SendDockerAction(serviceID string, instanceID int, action string, args []string) error
 }
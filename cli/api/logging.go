@@ -0,0 +1,118 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/logging"
+	"github.com/control-center/serviced/rpc/agent"
+)
+
+// validateLoggingOptions verifies the log format, file, and component levels
+func validateLoggingOptions(options *config.Options) error {
+	if options.LogFormat != "" && options.LogFormat != "text" && options.LogFormat != "json" {
+		return fmt.Errorf("log format must be text or json")
+	}
+	if options.LogFile != "" {
+		if options.LogMaxSize <= 0 {
+			return fmt.Errorf("log max size must be positive")
+		} else if options.LogMaxBackups < 0 {
+			return fmt.Errorf("log max backups cannot be negative")
+		}
+	}
+	for _, setting := range options.LogLevels {
+		if _, _, err := parseLogLevel(setting); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseLogLevel parses a component level setting of the form component=level
+func parseLogLevel(setting string) (string, string, error) {
+	parts := strings.SplitN(setting, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("log level %s must be of the form component=level", setting)
+	}
+	if _, err := logrus.ParseLevel(parts[1]); err != nil {
+		return "", "", err
+	}
+	return parts[0], parts[1], nil
+}
+
+// configureLogging sets the format, output, and component levels of the
+// server's logs
+func configureLogging(options config.Options) error {
+	if err := logging.SetFormat(options.LogFormat); err != nil {
+		return err
+	}
+	if options.LogFile != "" {
+		file, err := logging.NewRotatingFile(options.LogFile, int64(options.LogMaxSize)*1024*1024, options.LogMaxBackups)
+		if err != nil {
+			return fmt.Errorf("could not open log file %s: %s", options.LogFile, err)
+		}
+		logging.SetOutput(file)
+	}
+	for _, setting := range options.LogLevels {
+		component, level, err := parseLogLevel(setting)
+		if err != nil {
+			return err
+		}
+		if err := logging.SetComponentLevel(component, level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetLogLevel overrides the log level of a component on a host, or on the
+// host running the master if no host is given.  An empty level resets the
+// component to the level of its parent.
+func (a *api) SetLogLevel(hostID, component, level string) error {
+	client, err := a.connectHostAgent(hostID)
+	if err != nil {
+		return err
+	}
+	return client.SetLogLevel(component, level)
+}
+
+// GetLogLevels returns the log level overrides by component on a host, or on
+// the host running the master if no host is given.
+func (a *api) GetLogLevels(hostID string) (map[string]string, error) {
+	client, err := a.connectHostAgent(hostID)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetLogLevels()
+}
+
+// connectHostAgent opens a connection to the agent on a host, or to the
+// agent on the master's endpoint if no host is given
+func (a *api) connectHostAgent(hostID string) (*agent.Client, error) {
+	address := config.GetOptions().Endpoint
+	if hostID != "" {
+		h, err := a.GetHost(hostID)
+		if err != nil {
+			return nil, err
+		} else if h == nil {
+			return nil, fmt.Errorf("host %s not found", hostID)
+		}
+		address = fmt.Sprintf("%s:%d", h.IPAddr, h.RPCPort)
+	}
+	return a.connectAgent(address)
+}
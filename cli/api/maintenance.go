@@ -0,0 +1,68 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/maintenance"
+)
+
+// maintenanceTimeFormat is the format of the end of a maintenance window in
+// the service status
+const maintenanceTimeFormat = "2006-01-02 15:04"
+
+// AddMaintenanceWindow schedules a maintenance window and returns its id
+func (a *api) AddMaintenanceWindow(window maintenance.Window) (string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", err
+	}
+	return client.AddMaintenanceWindow(window)
+}
+
+// RemoveMaintenanceWindow deletes a maintenance window
+func (a *api) RemoveMaintenanceWindow(windowID string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.RemoveMaintenanceWindow(windowID)
+}
+
+// ListMaintenanceWindows returns all of the maintenance windows
+func (a *api) ListMaintenanceWindows() ([]maintenance.Window, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.ListMaintenanceWindows()
+}
+
+// maintenanceUntil returns when the latest active maintenance window that
+// covers an instance of a service ends, or the zero time if the instance is
+// not under maintenance.  The service ids are the id of the service followed
+// by the ids of its parents.
+func maintenanceUntil(windows []maintenance.Window, deploymentID string, serviceIDs []string, hostID string, at time.Time) time.Time {
+	var until time.Time
+	for _, w := range windows {
+		if !w.Covers(deploymentID, serviceIDs, hostID) {
+			continue
+		}
+		if end := w.ActiveUntil(at); end.After(until) {
+			until = end
+		}
+	}
+	return until
+}
@@ -0,0 +1,260 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	ccconfig "github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/utils"
+)
+
+const (
+	// migrationMountPoint is where the migration directory is mounted in the
+	// sandbox container
+	migrationMountPoint = "/migration"
+	// migrationInputFile is the file containing the services to be migrated
+	migrationInputFile = "input.json"
+	// migrationScriptFile is the copy of the migration script that is run
+	migrationScriptFile = "migrate"
+)
+
+// ErrMigrationTimeout is returned when a migration script runs longer than
+// its timeout
+var ErrMigrationTimeout = errors.New("migration script timed out")
+
+// MigrationConfig is the deserialized object from the command-line
+type MigrationConfig struct {
+	ServiceID        string
+	ScriptFile       string
+	DryRun           bool
+	Memory           string        // Memory limit of the sandbox, e.g. 512m
+	CPUShares        int64         // Relative cpu weight of the sandbox
+	Timeout          time.Duration // Time to wait for the script to finish
+	ServicedEndpoint string
+}
+
+// MigrationResult is the structured result a migration script writes to
+// stdout.  Changes to existing services are described as JSON patches so that
+// the script only needs to report the fields it modifies.
+type MigrationResult struct {
+	Error   string // Set by the script if the migration cannot be performed
+	Patches []*dao.ServicePatch
+	Added   []*service.Service
+	Deploy  []*dao.ServiceDeploymentRequest
+}
+
+// RunMigration runs a migration script against a service and its children in
+// a locked-down container, then submits the proposed changes to the master.
+// If DryRun is set, the changes are validated but not applied.
+func (a *api) RunMigration(config MigrationConfig) (*dao.ServiceMigrationRequest, error) {
+	log := log.WithFields(logrus.Fields{
+		"serviceid": config.ServiceID,
+		"script":    config.ScriptFile,
+	})
+
+	svc, err := a.GetService(config.ServiceID)
+	if err != nil {
+		return nil, err
+	} else if svc.ImageID == "" {
+		return nil, fmt.Errorf("service %s has no image to run the migration in", svc.ID)
+	}
+	svcs, err := a.GetServices()
+	if err != nil {
+		return nil, err
+	}
+	input, err := json.MarshalIndent(serviceTree(config.ServiceID, svcs), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	script, err := ioutil.ReadFile(config.ScriptFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// pull the service's image so the script runs against the same
+	// environment as the service
+	agentClient, err := a.connectAgent(config.ServicedEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	image, err := agentClient.PullImage(ccconfig.GetOptions().DockerRegistry, svc.ImageID, time.Minute)
+	if err != nil {
+		log.WithError(err).Error("Unable to pull image")
+		return nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "serviced-migration-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Chmod(dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, migrationInputFile), input, 0644); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, migrationScriptFile), script, 0755); err != nil {
+		return nil, err
+	}
+
+	uuid, err := utils.NewUUID36()
+	if err != nil {
+		return nil, err
+	}
+	name := "serviced-migration-" + uuid
+	output, err := runMigrationContainer(name, migrationDockerArgs(name, image, dir, config), config.Timeout)
+	if err != nil {
+		log.WithError(err).Error("Migration script failed")
+		return nil, err
+	}
+
+	result, err := parseMigrationResult(output)
+	if err != nil {
+		return nil, err
+	}
+	req := &dao.ServiceMigrationRequest{
+		ServiceID: config.ServiceID,
+		Patches:   result.Patches,
+		Added:     result.Added,
+		Deploy:    result.Deploy,
+		DryRun:    config.DryRun,
+	}
+
+	client, err := a.connectDAO()
+	if err != nil {
+		return nil, err
+	}
+	var unused int
+	if err := client.MigrateServices(*req, &unused); err != nil {
+		return nil, err
+	}
+	log.WithFields(logrus.Fields{
+		"patched":  len(req.Patches),
+		"added":    len(req.Added),
+		"deployed": len(req.Deploy),
+		"dryrun":   req.DryRun,
+	}).Debug("Submitted service migration")
+	return req, nil
+}
+
+// serviceTree returns the service with the given id followed by all of its
+// descendants.
+func serviceTree(serviceID string, svcs []service.Service) []service.Service {
+	children := make(map[string][]service.Service)
+	var tree []service.Service
+	for _, svc := range svcs {
+		if svc.ID == serviceID {
+			tree = append(tree, svc)
+		}
+		children[svc.ParentServiceID] = append(children[svc.ParentServiceID], svc)
+	}
+	for i := 0; i < len(tree); i++ {
+		tree = append(tree, children[tree[i].ID]...)
+	}
+	return tree
+}
+
+// migrationDockerArgs returns the arguments to docker to run the migration
+// script with no network, a read-only root filesystem, no capabilities, and
+// the configured resource limits.
+func migrationDockerArgs(name, image, dir string, config MigrationConfig) []string {
+	argv := []string{
+		"run", "--rm",
+		"--name", name,
+		"--net=none",
+		"--read-only",
+		"--tmpfs", "/tmp",
+		"--cap-drop=ALL",
+		"--security-opt=no-new-privileges",
+		"--user=nobody",
+	}
+	if config.Memory != "" {
+		argv = append(argv, "--memory="+config.Memory, "--memory-swap="+config.Memory)
+	}
+	if config.CPUShares > 0 {
+		argv = append(argv, fmt.Sprintf("--cpu-shares=%d", config.CPUShares))
+	}
+	argv = append(argv,
+		"-v", fmt.Sprintf("%s:%s:ro", dir, migrationMountPoint),
+		"-e", fmt.Sprintf("MIGRATION_INPUT=%s/%s", migrationMountPoint, migrationInputFile),
+		"--entrypoint", fmt.Sprintf("%s/%s", migrationMountPoint, migrationScriptFile),
+		image,
+	)
+	return argv
+}
+
+// runMigrationContainer runs docker and returns the script's stdout.  The
+// container is removed if it does not finish before the timeout.
+func runMigrationContainer(name string, argv []string, timeout time.Duration) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("docker", argv...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- cmd.Wait()
+	}()
+
+	var timer <-chan time.Time
+	if timeout > 0 {
+		timer = time.After(timeout)
+	}
+	select {
+	case err := <-errc:
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return stdout.Bytes(), nil
+	case <-timer:
+		if err := exec.Command("docker", "rm", "-f", name).Run(); err != nil {
+			log.WithError(err).WithField("containername", name).Warn("Unable to remove migration container")
+		}
+		<-errc
+		return nil, ErrMigrationTimeout
+	}
+}
+
+// parseMigrationResult decodes the output of a migration script
+func parseMigrationResult(output []byte) (*MigrationResult, error) {
+	result := &MigrationResult{}
+	if err := json.Unmarshal(output, result); err != nil {
+		return nil, fmt.Errorf("could not parse migration result: %s", err)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("migration script reported an error: %s", result.Error)
+	}
+	for _, p := range result.Patches {
+		if p.ServiceID == "" {
+			return nil, errors.New("migration result has a patch without a service id")
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package api
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAPISuite) TestMigrationDockerArgs(c *C) {
+	cfg := MigrationConfig{
+		Memory:    "256m",
+		CPUShares: 128,
+		Timeout:   time.Minute,
+	}
+	argv := migrationDockerArgs("serviced-migration-1", "localhost:5000/tenant/image:latest", "/tmp/dir", cfg)
+	c.Assert(argv, DeepEquals, []string{
+		"run", "--rm",
+		"--name", "serviced-migration-1",
+		"--net=none",
+		"--read-only",
+		"--tmpfs", "/tmp",
+		"--cap-drop=ALL",
+		"--security-opt=no-new-privileges",
+		"--user=nobody",
+		"--memory=256m", "--memory-swap=256m",
+		"--cpu-shares=128",
+		"-v", "/tmp/dir:/migration:ro",
+		"-e", "MIGRATION_INPUT=/migration/input.json",
+		"--entrypoint", "/migration/migrate",
+		"localhost:5000/tenant/image:latest",
+	})
+
+	// no resource limits
+	argv = migrationDockerArgs("serviced-migration-1", "image", "/tmp/dir", MigrationConfig{})
+	c.Assert(argv, HasLen, 18)
+}
+
+func (s *TestAPISuite) TestServiceTree(c *C) {
+	svcs := []service.Service{
+		{ID: "child2", ParentServiceID: "parent"},
+		{ID: "other"},
+		{ID: "grandchild", ParentServiceID: "child1"},
+		{ID: "parent"},
+		{ID: "child1", ParentServiceID: "parent"},
+	}
+	var ids []string
+	for _, svc := range serviceTree("parent", svcs) {
+		ids = append(ids, svc.ID)
+	}
+	c.Assert(ids, DeepEquals, []string{"parent", "child2", "child1", "grandchild"})
+	c.Assert(serviceTree("missing", svcs), HasLen, 0)
+}
+
+func (s *TestAPISuite) TestParseMigrationResult(c *C) {
+	result, err := parseMigrationResult([]byte(`{"Patches":[{"ServiceID":"svc1","Patch":[{"op":"replace","path":"/Instances","value":2}]}]}`))
+	c.Assert(err, IsNil)
+	c.Assert(result.Patches, HasLen, 1)
+	c.Assert(result.Patches[0].ServiceID, Equals, "svc1")
+	c.Assert(result.Patches[0].Patch[0].Op, Equals, "replace")
+	c.Assert(string(result.Patches[0].Patch[0].Value), Equals, "2")
+
+	_, err = parseMigrationResult([]byte(`{"Error":"unsupported version"}`))
+	c.Assert(err, ErrorMatches, "migration script reported an error: unsupported version")
+
+	_, err = parseMigrationResult([]byte(`{"Patches":[{"Patch":[]}]}`))
+	c.Assert(err, ErrorMatches, "migration result has a patch without a service id")
+
+	_, err = parseMigrationResult([]byte(`not json`))
+	c.Assert(err, ErrorMatches, "could not parse migration result: .*")
+}
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -26,6 +27,9 @@ import (
 	"github.com/control-center/serviced/node"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/volume"
+	"github.com/control-center/serviced/web"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/docker/go-units"
 )
 
 const (
@@ -61,9 +65,97 @@ func ValidateServerOptions(options *config.Options) error {
 			"poolid": options.MasterPoolID,
 		}).Debug("Using configured default pool ID")
 	}
+
+	if err := getHeartbeatConfig(*options).Validate(); err != nil {
+		return fmt.Errorf("serviced cannot be started: %s", err)
+	}
+	if options.MaxClockSkew <= 0 {
+		return fmt.Errorf("serviced cannot be started: max clock skew must be positive")
+	}
+	if options.AuthClockSkewTolerance < 0 {
+		return fmt.Errorf("serviced cannot be started: auth clock skew tolerance cannot be negative")
+	}
+	if options.StorageWarningPercent <= 0 || options.StorageWarningPercent > options.StorageCriticalPercent || options.StorageCriticalPercent > 100 {
+		return fmt.Errorf("serviced cannot be started: storage thresholds must satisfy 0 < warning <= critical <= 100")
+	}
+	if _, _, err := getEmergencyThresholds(*options); err != nil {
+		return fmt.Errorf("serviced cannot be started: %s", err)
+	}
+	if options.MasterHA {
+		if !options.Master {
+			return fmt.Errorf("serviced cannot be started: master failover requires master mode")
+		} else if len(options.Zookeepers) == 0 {
+			return fmt.Errorf("serviced cannot be started: master failover requires an external ZooKeeper ensemble")
+		}
+	}
+	if options.MasterReplica {
+		if options.Master {
+			return fmt.Errorf("serviced cannot be started: a read replica cannot run in master mode")
+		} else if options.ReplicaMaxStaleness <= 0 {
+			return fmt.Errorf("serviced cannot be started: replica max staleness must be positive")
+		}
+	}
+	if err := validateExternalOptions(options); err != nil {
+		return fmt.Errorf("serviced cannot be started: %s", err)
+	}
+	if err := validateTracingOptions(options); err != nil {
+		return fmt.Errorf("serviced cannot be started: %s", err)
+	}
+	if err := validateLoggingOptions(options); err != nil {
+		return fmt.Errorf("serviced cannot be started: %s", err)
+	}
+	if options.PreserveInstances && options.PreserveInstancesTimeout <= 0 {
+		return fmt.Errorf("serviced cannot be started: preserve instances timeout must be positive")
+	}
+	if options.ContainerOutputSize < 0 {
+		return fmt.Errorf("serviced cannot be started: container output size cannot be negative")
+	}
+	if options.SnapshotIOLimit < 0 || options.BackupIOLimit < 0 || options.RestoreIOLimit < 0 {
+		return fmt.Errorf("serviced cannot be started: io limits cannot be negative")
+	}
+	if options.OfflineInstances && options.InstanceCachePath == "" {
+		return fmt.Errorf("serviced cannot be started: instance cache path is required for offline instances")
+	}
+	for _, origin := range options.UICORSOrigins {
+		if err := web.ValidCORSOrigin(origin); err != nil {
+			return fmt.Errorf("serviced cannot be started: %s", err)
+		}
+	}
 	return nil
 }
 
+// getHeartbeatConfig returns the delegate heartbeat configuration from options
+func getHeartbeatConfig(options config.Options) zkservice.HeartbeatConfig {
+	return zkservice.HeartbeatConfig{
+		MinInterval: time.Duration(options.HeartbeatMinInterval) * time.Second,
+		MaxInterval: time.Duration(options.HeartbeatMaxInterval) * time.Second,
+		Jitter:      float64(options.HeartbeatJitter) / 100,
+	}
+}
+
+// getEmergencyThresholds returns the minimum free space in bytes of the
+// application storage and of the thin pools; an empty size is 0 and disables
+// the check.
+func getEmergencyThresholds(options config.Options) (minFreeDFS, minFreeThinPool uint64, err error) {
+	parse := func(name, size string) (uint64, error) {
+		if size = strings.TrimSpace(size); size == "" {
+			return 0, nil
+		}
+		bytes, err := units.RAMInBytes(size)
+		if err != nil || bytes < 0 {
+			return 0, fmt.Errorf("invalid emergency minimum free %s %q", name, size)
+		}
+		return uint64(bytes), nil
+	}
+	if minFreeDFS, err = parse("dfs", options.EmergencyMinFreeDFS); err != nil {
+		return 0, 0, err
+	}
+	if minFreeThinPool, err = parse("thin pool", options.EmergencyMinFreeThinPool); err != nil {
+		return 0, 0, err
+	}
+	return minFreeDFS, minFreeThinPool, nil
+}
+
 // GetOptionsRPCEndpoint returns the serviced RPC endpoint from options
 func GetOptionsRPCEndpoint() string {
 	return config.GetOptions().Endpoint
@@ -136,6 +228,8 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		MUXTLSCiphers:              cfg.StringSlice("MUX_TLS_CIPHERS", utils.GetDefaultCiphers("mux")),
 		MUXTLSMinVersion:           cfg.StringVal("MUX_TLS_MIN_VERSION", utils.DefaultTLSMinVersion),
 		RPCDialTimeout:             cfg.IntVal("RPC_DIAL_TIMEOUT", 30),
+		RPCCallTimeout:             cfg.IntVal("RPC_CALL_TIMEOUT", 0),
+		RPCSlowCallThreshold:       cfg.IntVal("RPC_SLOW_CALL_THRESHOLD", 0),
 		RPCCertVerify:              strconv.FormatBool(cfg.BoolVal("RPC_CERT_VERIFY", false)),
 		RPCDisableTLS:              strconv.FormatBool(cfg.BoolVal("RPC_DISABLE_TLS", false)),
 		RPCTLSCiphers:              cfg.StringSlice("RPC_TLS_CIPHERS", utils.GetDefaultCiphers("rpc")),
@@ -150,10 +244,51 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		DockerLogConfigList:        cfg.StringSlice("DOCKER_LOG_CONFIG", []string{"max-file=5", "max-size=10m"}),
 		AllowLoopBack:              strconv.FormatBool(cfg.BoolVal("ALLOW_LOOP_BACK", false)),
 		UIPollFrequency:            cfg.IntVal("UI_POLL_FREQUENCY", 3),
+		UICORSOrigins:              cfg.StringSlice("UI_CORS_ORIGINS", []string{}),
+		UIDisableCompression:       cfg.BoolVal("UI_DISABLE_COMPRESSION", false),
+		UIHeadless:                 cfg.BoolVal("UI_HEADLESS", false),
 		StorageStatsUpdateInterval: cfg.IntVal("STORAGE_STATS_UPDATE_INTERVAL", 300),
 		SnapshotSpacePercent:       cfg.IntVal("SNAPSHOT_USE_PERCENT", 20),
 		ZKSessionTimeout:           cfg.IntVal("ZK_SESSION_TIMEOUT", 15),
 		TokenExpiration:            cfg.IntVal("AUTH_TOKEN_EXPIRATION", 60*60),
+		AllowChaos:                 cfg.BoolVal("ALLOW_CHAOS", false),
+		RepairConsistency:          cfg.BoolVal("REPAIR_CONSISTENCY", false),
+		HeartbeatMinInterval:       cfg.IntVal("HEARTBEAT_MIN_INTERVAL", 5),
+		HeartbeatMaxInterval:       cfg.IntVal("HEARTBEAT_MAX_INTERVAL", 60),
+		HeartbeatJitter:            cfg.IntVal("HEARTBEAT_JITTER", 20),
+		MaxClockSkew:               cfg.IntVal("MAX_CLOCK_SKEW", 10),
+		AuthClockSkewTolerance:     cfg.IntVal("AUTH_CLOCK_SKEW_TOLERANCE", 0),
+		StorageWarningPercent:      cfg.IntVal("STORAGE_WARNING_PERCENT", 80),
+		StorageCriticalPercent:     cfg.IntVal("STORAGE_CRITICAL_PERCENT", 90),
+		EmergencyMinFreeDFS:        cfg.StringVal("EMERGENCY_MIN_FREE_DFS", ""),
+		EmergencyMinFreeThinPool:   cfg.StringVal("EMERGENCY_MIN_FREE_THINPOOL", ""),
+		MasterHA:                   cfg.BoolVal("MASTER_HA", false),
+		MasterHADevice:             cfg.StringVal("MASTER_HA_DEVICE", ""),
+		ESURL:                      cfg.StringVal("ES_URL", ""),
+		OpenTSDBURL:                cfg.StringVal("OPENTSDB_URL", ""),
+		ZKExternal:                 cfg.BoolVal("ZK_EXTERNAL", false),
+		ExternalCAFile:             cfg.StringVal("ISVCS_EXTERNAL_CA", ""),
+		ExternalCertFile:           cfg.StringVal("ISVCS_EXTERNAL_CERT", ""),
+		ExternalKeyFile:            cfg.StringVal("ISVCS_EXTERNAL_KEY", ""),
+		ZKAuth:                     cfg.StringVal("ZK_AUTH", ""),
+		MasterReplica:              cfg.BoolVal("MASTER_REPLICA", false),
+		ReplicaMaxStaleness:        cfg.IntVal("REPLICA_MAX_STALENESS", 10),
+		TraceURL:                   cfg.StringVal("TRACE_URL", ""),
+		TraceSamplePercent:         cfg.IntVal("TRACE_SAMPLE_PERCENT", 10),
+		LogFormat:                  cfg.StringVal("LOG_FORMAT", "text"),
+		LogFile:                    cfg.StringVal("LOG_FILE", ""),
+		LogMaxSize:                 cfg.IntVal("LOG_MAX_SIZE", 100),
+		LogMaxBackups:              cfg.IntVal("LOG_MAX_BACKUPS", 5),
+		LogLevels:                  cfg.StringSlice("LOG_LEVELS", []string{}),
+		PreserveInstances:          cfg.BoolVal("PRESERVE_INSTANCES", false),
+		PreserveInstancesTimeout:   cfg.IntVal("PRESERVE_INSTANCES_TIMEOUT", 600),
+		UpgradeCommand:             cfg.StringVal("UPGRADE_COMMAND", ""),
+		RollbackWindow:             cfg.IntVal("ROLLBACK_WINDOW", 300),
+		ContainerOutputSize:        cfg.IntVal("CONTAINER_OUTPUT_SIZE", 1024),
+		OfflineInstances:           cfg.BoolVal("OFFLINE_INSTANCES", false),
+		SnapshotIOLimit:            cfg.IntVal("SNAPSHOT_IO_LIMIT", 0),
+		BackupIOLimit:              cfg.IntVal("BACKUP_IO_LIMIT", 0),
+		RestoreIOLimit:             cfg.IntVal("RESTORE_IO_LIMIT", 0),
 	}
 
 	options.Endpoint = cfg.StringVal("ENDPOINT", "")
@@ -174,6 +309,9 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 	options.VolumesPath = cfg.StringVal("VOLUMES_PATH", filepath.Join(varpath, "volumes"))
 	options.BackupsPath = cfg.StringVal("BACKUPS_PATH", filepath.Join(varpath, "backups"))
 	options.EtcPath = cfg.StringVal("ETC_PATH", filepath.Join(homepath, "etc"))
+	options.MasterHAMountPath = cfg.StringVal("MASTER_HA_MOUNT_PATH", varpath)
+	options.ContainerOutputPath = cfg.StringVal("CONTAINER_OUTPUT_PATH", filepath.Join(varpath, "output"))
+	options.InstanceCachePath = cfg.StringVal("INSTANCE_CACHE_PATH", filepath.Join(varpath, "instances.json"))
 	options.StorageArgs = getDefaultStorageOptions(options.FSType, cfg)
 
 	return options
@@ -102,6 +102,16 @@ func (a *api) GetPoolIPs(id string) (*pool.PoolIPs, error) {
 	return client.GetPoolIPs(id)
 }
 
+// Returns the host ports claimed by services in a given pool
+func (a *api) GetPoolPortClaims(id string) ([]pool.PortClaim, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetPoolPortClaims(id)
+}
+
 // Add a VirtualIP to a specific pool
 func (a *api) AddVirtualIP(requestVirtualIP pool.VirtualIP) error {
 	client, err := a.connectMaster()
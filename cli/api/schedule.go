@@ -0,0 +1,43 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import "github.com/control-center/serviced/domain/schedule"
+
+// AddScheduleProfile adds a schedule profile for a service and returns its id
+func (a *api) AddScheduleProfile(profile schedule.Profile) (string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", err
+	}
+	return client.AddScheduleProfile(profile)
+}
+
+// RemoveScheduleProfile deletes a schedule profile
+func (a *api) RemoveScheduleProfile(profileID string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.RemoveScheduleProfile(profileID)
+}
+
+// ListScheduleProfiles returns all of the schedule profiles
+func (a *api) ListScheduleProfiles() ([]schedule.Profile, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.ListScheduleProfiles()
+}
@@ -14,7 +14,6 @@
 package api
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
@@ -27,6 +26,7 @@ import (
 	"github.com/control-center/serviced/domain/applicationendpoint"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/domain/statehistory"
 	"github.com/control-center/serviced/health"
 
 	"github.com/control-center/serviced/domain/host"
@@ -49,6 +49,7 @@ type ServiceConfig struct {
 
 type SchedulerConfig struct {
 	ServiceID  string
+	ServiceIDs []string // Additional services to schedule in the same request
 	AutoLaunch bool
 }
 
@@ -77,6 +78,26 @@ func (a *api) GetServices() ([]service.Service, error) {
 	return services, nil
 }
 
+// QueryServices returns a page of the summaries of the services matching the
+// query
+func (a *api) QueryServices(query service.ServiceQuery) (*service.ServiceSummaryPage, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.QueryServices(query)
+}
+
+// ResolveServicePath returns the services that a service id or a
+// deployment, pool, or name path resolves to
+func (a *api) ResolveServicePath(servicePath string) ([]service.ServiceMatch, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.ResolveServicePath(servicePath)
+}
+
 func (a *api) GetServiceStatus(serviceID string) (map[string]map[string]interface{}, error) {
 	client, err := a.connectDAO()
 	if err != nil {
@@ -100,9 +121,30 @@ func (a *api) GetServiceStatus(serviceID string) (map[string]map[string]interfac
 		}
 	}
 
+	// get the maintenance windows and the parents of each service, to report
+	// the instances that are under maintenance
+	masterClient, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	windows, err := masterClient.ListMaintenanceWindows()
+	if err != nil {
+		return nil, err
+	}
+	parents := make(map[string]string)
+	for _, svc := range svcs {
+		parents[svc.ID] = svc.ParentServiceID
+	}
+	now := time.Now()
+
 	// get status
 	rowmap := make(map[string]map[string]interface{})
 	for _, svc := range svcs {
+		lineage := []string{svc.ID}
+		for id := svc.ParentServiceID; id != "" && len(lineage) <= len(parents); id = parents[id] {
+			lineage = append(lineage, id)
+		}
+
 		var status []service.Instance
 		if err := client.GetServiceStatus(svc.ID, &status); err != nil {
 			return nil, err
@@ -118,15 +160,26 @@ func (a *api) GetServiceStatus(serviceID string) (map[string]map[string]interfac
 				row["ParentID"] = ""
 			}
 			row["RAM"] = bytefmt.ByteSize(svc.RAMCommitment.Value)
+			if until := maintenanceUntil(windows, svc.DeploymentID, lineage, "", now); !until.IsZero() {
+				row["Maintenance"] = "until " + until.Local().Format(maintenanceTimeFormat)
+			}
 
 			if svc.Instances > 0 {
 				switch service.DesiredState(svc.DesiredState) {
 				case service.SVCRun:
-					row["Status"] = "Scheduled"
+					if svc.AddressBlocked != "" {
+						row["Status"] = "blocked: " + svc.AddressBlocked
+					} else {
+						row["Status"] = "Scheduled"
+					}
 				case service.SVCPause:
 					row["Status"] = service.Paused
 				case service.SVCStop:
-					row["Status"] = service.Stopped
+					if svc.EmergencyShutdown {
+						row["Status"] = "emergency stopped"
+					} else {
+						row["Status"] = service.Stopped
+					}
 				}
 			}
 			rowmap[fmt.Sprintf("%s/%d", svc.ID, 0)] = row
@@ -156,6 +209,12 @@ func (a *api) GetServiceStatus(serviceID string) (map[string]map[string]interfac
 				row["Hostname"] = stat.HostName
 				row["DockerID"] = fmt.Sprintf("%.12s", stat.ContainerID)
 				row["Uptime"] = uptime.String()
+				if n := len(stat.Exits); n > 0 && stat.CurrentState != service.Running {
+					row["Exit Reason"] = stat.Exits[n-1].Reason()
+				}
+				if until := maintenanceUntil(windows, svc.DeploymentID, lineage, stat.HostID, now); !until.IsZero() {
+					row["Maintenance"] = "until " + until.Local().Format(maintenanceTimeFormat)
+				}
 
 				if stat.ImageSynced {
 					row["InSync"] = "Y"
@@ -167,10 +226,21 @@ func (a *api) GetServiceStatus(serviceID string) (map[string]map[string]interfac
 				} else {
 					row["Name"] = svc.Name
 				}
-				cur := bytefmt.ByteSize(uint64(stat.MemoryUsage.Cur))
-				max := bytefmt.ByteSize(uint64(stat.MemoryUsage.Max))
-				avg := bytefmt.ByteSize(uint64(stat.MemoryUsage.Avg))
-				row["Cur/Max/Avg"] = fmt.Sprintf("%s / %s / %s", cur, max, avg)
+				// usage is cached by the host running the instance, so
+				// report how old it is
+				if stat.UsageUpdated.IsZero() {
+					row["Cur/Max/Avg"] = "--"
+					row["CPU"] = "--"
+					row["Usage Age"] = "--"
+				} else {
+					cur := bytefmt.ByteSize(uint64(stat.MemoryUsage.Cur))
+					max := bytefmt.ByteSize(uint64(stat.MemoryUsage.Max))
+					avg := bytefmt.ByteSize(uint64(stat.MemoryUsage.Avg))
+					row["Cur/Max/Avg"] = fmt.Sprintf("%s / %s / %s", cur, max, avg)
+					row["CPU"] = fmt.Sprintf("%.1f%%", stat.CPUUsage)
+					age := time.Since(stat.UsageUpdated)
+					row["Usage Age"] = (age - age%time.Second).String()
+				}
 
 				rowmap[fmt.Sprintf("%s/%d", svc.ID, stat.InstanceID)] = row
 
@@ -221,6 +291,16 @@ func (a *api) GetEndpoints(serviceID string, reportImports, reportExports, valid
 	}
 }
 
+// GetEndpointStats returns the connections the mux has forwarded to the
+// exported endpoints of a service
+func (a *api) GetEndpointStats(serviceID string) ([]applicationendpoint.EndpointStats, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetServiceEndpointStats(serviceID)
+}
+
 // Gets the service definition identified by its service ID
 func (a *api) GetService(id string) (*service.Service, error) {
 	client, err := a.connectDAO()
@@ -305,6 +385,21 @@ func (a *api) CloneService(serviceID string, suffix string) (*service.Service, e
 	return a.GetService(clonedServiceID)
 }
 
+// CloneDeployment copies a tenant and its services into a new deployment and
+// returns the tenant id of the copy
+func (a *api) CloneDeployment(request dao.DeploymentCloneRequest) (string, error) {
+	client, err := a.connectDAO()
+	if err != nil {
+		return "", err
+	}
+
+	tenantID := ""
+	if err := client.CloneDeployment(request, &tenantID); err != nil {
+		return "", fmt.Errorf("clone deployment failed: %s", err)
+	}
+	return tenantID, nil
+}
+
 // RemoveService removes an existing service
 func (a *api) RemoveService(id string) error {
 	client, err := a.connectDAO()
@@ -312,7 +407,7 @@ func (a *api) RemoveService(id string) error {
 		return err
 	}
 
-	if err := client.RemoveService(id, new(int)); err != nil {
+	if err := client.RemoveService(dao.RemoveServiceRequest{ServiceID: id}, new(int)); err != nil {
 		return fmt.Errorf("could not remove service %s: %s", id, err)
 	}
 	return nil
@@ -320,10 +415,10 @@ func (a *api) RemoveService(id string) error {
 
 // UpdateService updates an existing service
 func (a *api) UpdateService(reader io.Reader) (*service.Service, error) {
-	// Unmarshal JSON from the reader
+	// Unmarshal JSON or YAML from the reader
 	var s service.Service
-	if err := json.NewDecoder(reader).Decode(&s); err != nil {
-		return nil, fmt.Errorf("could not unmarshal json: %s", err)
+	if err := DecodeData(reader, &s); err != nil {
+		return nil, err
 	}
 
 	// Connect to the client
@@ -340,6 +435,37 @@ func (a *api) UpdateService(reader io.Reader) (*service.Service, error) {
 	return a.GetService(s.ID)
 }
 
+// SetServicePriority updates the priority class of a service
+func (a *api) SetServicePriority(serviceID, priority string) (*service.Service, error) {
+	client, err := a.connectDAO()
+	if err != nil {
+		return nil, err
+	}
+
+	var s service.Service
+	if err := client.GetService(serviceID, &s); err != nil {
+		return nil, err
+	}
+	s.Priority = priority
+	if err := client.UpdateService(s, &unusedInt); err != nil {
+		return nil, err
+	}
+
+	return a.GetService(serviceID)
+}
+
+// scheduleRequest returns the request to schedule the services of the config,
+// attributed to the user running the cli
+func scheduleRequest(config SchedulerConfig) dao.ScheduleServiceRequest {
+	return dao.ScheduleServiceRequest{
+		ServiceID:  config.ServiceID,
+		ServiceIDs: config.ServiceIDs,
+		AutoLaunch: config.AutoLaunch,
+		Source:     statehistory.SourceCLI,
+		User:       currentUser(),
+	}
+}
+
 // StartService starts a service
 func (a *api) StartService(config SchedulerConfig) (int, error) {
 	client, err := a.connectDAO()
@@ -348,7 +474,7 @@ func (a *api) StartService(config SchedulerConfig) (int, error) {
 	}
 
 	var affected int
-	err = client.StartService(dao.ScheduleServiceRequest{config.ServiceID, config.AutoLaunch}, &affected)
+	err = client.StartService(scheduleRequest(config), &affected)
 	return affected, err
 }
 
@@ -360,7 +486,7 @@ func (a *api) RestartService(config SchedulerConfig) (int, error) {
 	}
 
 	var affected int
-	err = client.RestartService(dao.ScheduleServiceRequest{config.ServiceID, config.AutoLaunch}, &affected)
+	err = client.RestartService(scheduleRequest(config), &affected)
 	return affected, err
 }
 
@@ -372,10 +498,64 @@ func (a *api) StopService(config SchedulerConfig) (int, error) {
 	}
 
 	var affected int
-	err = client.StopService(dao.ScheduleServiceRequest{config.ServiceID, config.AutoLaunch}, &affected)
+	err = client.StopService(scheduleRequest(config), &affected)
+	return affected, err
+}
+
+// PauseService pauses a running service without stopping its containers
+func (a *api) PauseService(config SchedulerConfig) (int, error) {
+	client, err := a.connectDAO()
+	if err != nil {
+		return 0, err
+	}
+
+	var affected int
+	err = client.PauseService(scheduleRequest(config), &affected)
+	return affected, err
+}
+
+// ResumeService resumes a paused service
+func (a *api) ResumeService(config SchedulerConfig) (int, error) {
+	client, err := a.connectDAO()
+	if err != nil {
+		return 0, err
+	}
+
+	var affected int
+	err = client.ResumeService(scheduleRequest(config), &affected)
 	return affected, err
 }
 
+// WaitService blocks until the services, and their children if recursive is
+// set, reach the desired state or the timeout expires.
+func (a *api) WaitService(serviceIDs []string, state service.DesiredState, timeout time.Duration, recursive bool) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.WaitService(serviceIDs, state, timeout, recursive)
+}
+
+// GetServiceStateHistory returns the changes of the desired state of a
+// service, oldest first
+func (a *api) GetServiceStateHistory(serviceID string) ([]statehistory.Change, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetServiceStateHistory(serviceID)
+}
+
+// ClearEmergencyShutdown clears the emergency flag of a service and its
+// children so that they can be started again
+func (a *api) ClearEmergencyShutdown(serviceID string) (int, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return 0, err
+	}
+	return client.ClearEmergencyShutdown(serviceID)
+}
+
 // AssignIP assigns an IP address to a service
 func (a *api) AssignIP(config IPConfig) error {
 	client, err := a.connectDAO()
@@ -396,6 +576,26 @@ func (a *api) AssignIP(config IPConfig) error {
 	return nil
 }
 
+// RebalanceIPs moves the address assignments of a pool off of ips that are
+// no longer available
+func (a *api) RebalanceIPs(poolID string) ([]addressassignment.Reassignment, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.RebalanceAddressAssignments(poolID)
+}
+
+// AssignDeploymentIPs reports the current and proposed address assignment of
+// every endpoint in a deployment, applying the proposals unless dryRun is set
+func (a *api) AssignDeploymentIPs(deploymentID string, dryRun bool) ([]addressassignment.PlannedAssignment, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.AssignDeploymentIPs(deploymentID, dryRun)
+}
+
 func (a *api) GetHostMap() (map[string]host.Host, error) {
 	hosts, err := a.GetHosts()
 	if err != nil {
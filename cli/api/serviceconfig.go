@@ -0,0 +1,34 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package api
+
+import "github.com/control-center/serviced/domain/service"
+
+// GetServiceConfigConflicts returns the config files of a service that
+// conflict with a redeploy of its template
+func (a *api) GetServiceConfigConflicts(serviceID string) ([]service.ConfigConflict, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetServiceConfigConflicts(serviceID)
+}
+
+// ResolveServiceConfigConflict resolves the conflict of a config file
+func (a *api) ResolveServiceConfigConflict(fileID, resolution, content string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.ResolveServiceConfigConflict(fileID, resolution, content)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	ccconfig "github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/node"
 	"github.com/control-center/serviced/shell"
@@ -266,7 +267,14 @@ func (a *api) RunShell(config ShellConfig, stopChan chan struct{}) (int, error)
 			// Commit the container
 			label := ""
 			log.Info("Committing container")
-			if err := client.Snapshot(dao.SnapshotRequest{ContainerID: container.ID, SnapshotSpacePercent: options.SnapshotSpacePercent}, &label); err != nil {
+			req := dao.SnapshotRequest{
+				ContainerID:          container.ID,
+				Message:              fmt.Sprintf("Run %s on service %s\n\n%s", config.Command, svc.Name, command),
+				SnapshotSpacePercent: options.SnapshotSpacePercent,
+				User:                 currentUser(),
+				Source:               dfs.SnapshotSourceShell,
+			}
+			if err := client.Snapshot(req, &label); err != nil {
 				log.WithError(err).Fatal("Unable to commit container")
 			}
 		}
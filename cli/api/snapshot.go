@@ -15,9 +15,11 @@ package api
 
 import (
 	"fmt"
+	"os/user"
 
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/service"
 )
 
 type SnapshotConfig struct {
@@ -82,6 +84,16 @@ func (a *api) GetSnapshotByServiceIDAndTag(serviceID string, tag string) (string
 	return snapshot.SnapshotID, nil
 }
 
+// GetSnapshotService returns the definition of a service stored in a
+// snapshot
+func (a *api) GetSnapshotService(snapshotID, serviceID string) (*service.Service, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetSnapshotService(snapshotID, serviceID)
+}
+
 // Snapshots a service
 func (a *api) AddSnapshot(cfg SnapshotConfig) (string, error) {
 	client, err := a.connectDAO()
@@ -94,6 +106,7 @@ func (a *api) AddSnapshot(cfg SnapshotConfig) (string, error) {
 		Tag:                  cfg.Tag,
 		ContainerID:          cfg.DockerID,
 		SnapshotSpacePercent: config.GetOptions().SnapshotSpacePercent,
+		User:                 currentUser(),
 	}
 	var snapshotID string
 	if err := client.Snapshot(req, &snapshotID); err != nil {
@@ -161,3 +174,12 @@ func (a *api) RemoveSnapshotTag(serviceID string, tagName string) (string, error
 
 	return snapshotID, nil
 }
+
+// currentUser returns the name of the user running the cli, to be recorded
+// with the snapshots it takes
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
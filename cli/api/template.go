@@ -14,7 +14,6 @@
 package api
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 
@@ -82,10 +81,10 @@ func (a *api) GetServiceTemplate(id string) (*template.ServiceTemplate, error) {
 
 // Adds a new service template
 func (a *api) AddServiceTemplate(reader io.Reader) (*template.ServiceTemplate, error) {
-	// Unmarshal JSON from the reader
+	// Unmarshal JSON or YAML from the reader
 	var t template.ServiceTemplate
-	if err := json.NewDecoder(reader).Decode(&t); err != nil {
-		return nil, fmt.Errorf("could not unmarshal json: %s", err)
+	if err := DecodeData(reader, &t); err != nil {
+		return nil, err
 	}
 
 	// Connect to the client
@@ -103,6 +102,28 @@ func (a *api) AddServiceTemplate(reader io.Reader) (*template.ServiceTemplate, e
 
 }
 
+// ValidateServiceTemplate checks a service template for errors and likely
+// mistakes without adding it
+func (a *api) ValidateServiceTemplate(reader io.Reader) ([]template.LintIssue, error) {
+	var t template.ServiceTemplate
+	if err := DecodeData(reader, &t); err != nil {
+		return nil, err
+	}
+
+	var issues []template.LintIssue
+	for i := range t.Services {
+		if err := t.Services[i].ValidEntity(); err != nil {
+			issues = append(issues, template.LintIssue{
+				Rule:     "definition",
+				Severity: template.LintError,
+				Service:  "/" + t.Services[i].Name,
+				Message:  err.Error(),
+			})
+		}
+	}
+	return append(issues, template.Lint(&t)...), nil
+}
+
 // RemoveTemplate removes an existing template by its template ID
 func (a *api) RemoveServiceTemplate(id string) error {
 	client, err := a.connectMaster()
@@ -0,0 +1,60 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/tracing"
+)
+
+// validateTracingOptions verifies the trace collector settings
+func validateTracingOptions(options *config.Options) error {
+	if options.TraceURL == "" {
+		return nil
+	}
+	u, err := url.Parse(options.TraceURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("trace url %s must use http or https", options.TraceURL)
+	} else if u.Host == "" {
+		return fmt.Errorf("trace url %s has no host", options.TraceURL)
+	}
+	if options.TraceSamplePercent < 0 || options.TraceSamplePercent > 100 {
+		return fmt.Errorf("trace sample percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// startTracing sends traces of requests to the trace collector, if one is
+// configured
+func (d *daemon) startTracing() {
+	options := config.GetOptions()
+	if options.TraceURL == "" {
+		return
+	}
+	exporter := tracing.NewZipkinExporter(options.TraceURL, "serviced")
+	tracer := tracing.NewTracer(float64(options.TraceSamplePercent)/100, exporter)
+	tracing.SetTracer(tracer)
+	go tracer.Run(d.shutdown)
+	log.WithFields(logrus.Fields{
+		"url":           options.TraceURL,
+		"samplepercent": options.TraceSamplePercent,
+	}).Info("Sending request traces to the trace collector")
+}
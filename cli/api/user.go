@@ -0,0 +1,32 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+// GetUserTenants returns the tenants a user is allowed to access
+func (a *api) GetUserTenants(userName string) ([]string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetUserTenants(userName)
+}
+
+// SetUserTenants restricts a user to the given tenants
+func (a *api) SetUserTenants(userName string, tenantIDs []string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.SetUserTenants(userName, tenantIDs)
+}
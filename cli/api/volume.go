@@ -13,7 +13,11 @@
 
 package api
 
-import "github.com/control-center/serviced/volume"
+import (
+	"github.com/control-center/serviced/coordinator/storage"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/volume"
+)
 
 func (a *api) GetVolumeStatus() (*volume.Statuses, error) {
 	client, err := a.connectMaster()
@@ -27,3 +31,41 @@ func (a *api) GetVolumeStatus() (*volume.Statuses, error) {
 	}
 	return response, nil
 }
+
+// GetDFSStats returns the latency, throughput, and concurrency of dfs
+// operations on the master
+func (a *api) GetDFSStats() (*dfs.Stats, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetDFSStats()
+}
+
+// FreezeDFS makes the distributed filesystem read-only for maintenance
+func (a *api) FreezeDFS(reason string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.FreezeDFS(reason)
+}
+
+// ThawDFS makes the distributed filesystem writable after maintenance
+func (a *api) ThawDFS() error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.ThawDFS()
+}
+
+// GetDFSFreezeStatus returns whether the distributed filesystem is read-only
+// for maintenance
+func (a *api) GetDFSFreezeStatus() (*storage.FreezeStatus, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetDFSFreezeStatus()
+}
@@ -16,8 +16,11 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
+	"time"
 
 	"github.com/codegangsta/cli"
+	"github.com/pivotal-golang/bytefmt"
 )
 
 // Initializer for serviced backup and serviced restore
@@ -27,7 +30,7 @@ func (c *ServicedCli) initBackup() {
 		cli.Command{
 			Name:        "backup",
 			Usage:       "Dump all templates and services to a tgz file",
-			Description: "serviced backup DIRPATH",
+			Description: "serviced backup [--exclude SUBDIR] DIRPATH",
 			Action:      c.cmdBackup,
 			Flags: []cli.Flag{
 				cli.StringSliceFlag{
@@ -36,12 +39,76 @@ func (c *ServicedCli) initBackup() {
 					Usage: "Subdirectory of the tenant volume to exclude from backup",
 				},
 			},
+			Subcommands: []cli.Command{
+				{
+					Name:        "list",
+					Usage:       "Lists the catalog of completed backups",
+					Description: "serviced backup list",
+					Action:      c.cmdBackupList,
+					Flags: append([]cli.Flag{
+						cli.BoolFlag{
+							Name:  "verbose, v",
+							Usage: "Show JSON format, including the contents of each backup",
+						},
+						cli.StringFlag{
+							Name:  "show-fields",
+							Value: "ID,Location,Size,Started,Duration,Available",
+							Usage: "Comma-delimited list describing which fields to display",
+						},
+					}, tableFlags()...),
+				}, {
+					Name:        "estimate",
+					Usage:       "Estimates the size and duration of a backup",
+					Description: "serviced backup estimate [--exclude SUBDIR]",
+					Action:      c.cmdBackupEstimate,
+					Flags: []cli.Flag{
+						cli.StringSliceFlag{
+							Name:  "exclude",
+							Value: &cli.StringSlice{},
+							Usage: "Subdirectory of the tenant volume to exclude from backup",
+						},
+						cli.BoolFlag{
+							Name:  "verbose, v",
+							Usage: "Show JSON format",
+						},
+					},
+				}, {
+					Name:        "inspect",
+					Usage:       "Reports the version of a backup and what can be restored from it",
+					Description: "serviced backup inspect FILEPATH",
+					Action:      c.cmdBackupInspect,
+					Flags: []cli.Flag{
+						cli.BoolFlag{
+							Name:  "verbose, v",
+							Usage: "Show JSON format",
+						},
+					},
+				}, {
+					Name:        "cleanup-failed",
+					Usage:       "Removes the volumes, snapshots, and images imported by a failed restore",
+					Description: "serviced backup cleanup-failed [--dry-run]",
+					Action:      c.cmdBackupCleanupFailed,
+					Flags: []cli.Flag{
+						cli.BoolFlag{
+							Name:  "dry-run",
+							Usage: "List what would be removed without removing it",
+						},
+					},
+				},
+			},
 		},
 		cli.Command{
 			Name:        "restore",
 			Usage:       "Restore templates and services from a tgz file",
-			Description: "serviced restore FILEPATH",
+			Description: "serviced restore [--force CHECK] FILEPATH",
 			Action:      c.cmdRestore,
+			Flags: []cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "force",
+					Value: &cli.StringSlice{},
+					Usage: "Compatibility check to ignore (backup-version cannot be ignored): docker-version, driver-type, template-version, or all",
+				},
+			},
 		},
 	)
 }
@@ -49,9 +116,10 @@ func (c *ServicedCli) initBackup() {
 // serviced backup DIRPATH
 func (c *ServicedCli) cmdBackup(ctx *cli.Context) {
 	args := ctx.Args()
-	if len(args) < 1 {
+	if len(args) != 1 {
+		// flags must precede DIRPATH, so anything after it is an error
 		fmt.Printf("Incorrect Usage.\n\n")
-		cli.ShowCommandHelp(ctx, "backup")
+		cli.ShowSubcommandHelp(ctx)
 		return
 	}
 	if path, err := c.driver.Backup(args[0], ctx.StringSlice("exclude")); err != nil {
@@ -63,6 +131,156 @@ func (c *ServicedCli) cmdBackup(ctx *cli.Context) {
 	}
 }
 
+// serviced backup list [--verbose, -v]
+func (c *ServicedCli) cmdBackupList(ctx *cli.Context) {
+	backups, err := c.driver.ListBackups()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(backups) == 0 {
+		fmt.Fprintln(os.Stderr, "no backups found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonBackups, err := c.marshalOutput(backups); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal backup list: %s", err)
+		} else {
+			fmt.Println(string(jsonBackups))
+		}
+		return
+	}
+
+	t, err := newTableFromContext(ctx, ctx.String("show-fields"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	t.Padding = 4
+	for _, b := range backups {
+		t.AddRow(map[string]interface{}{
+			"ID":        b.ID,
+			"Location":  b.Location,
+			"Size":      bytefmt.ByteSize(uint64(b.Size)),
+			"Checksum":  b.Checksum,
+			"Started":   b.StartedAt.Local().Format(time.RFC3339),
+			"Duration":  b.Duration.String(),
+			"Available": b.Available,
+		})
+	}
+	t.Print()
+}
+
+// serviced backup estimate [--exclude SUBDIR] [--verbose, -v]
+func (c *ServicedCli) cmdBackupEstimate(ctx *cli.Context) {
+	estimate, err := c.driver.EstimateBackup(ctx.StringSlice("exclude"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonEstimate, err := c.marshalOutput(estimate); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal backup estimate: %s", err)
+		} else {
+			fmt.Println(string(jsonEstimate))
+		}
+		return
+	}
+
+	t := NewTable("Type,Name,Size")
+	t.Padding = 4
+	for _, component := range estimate.Components {
+		size := bytefmt.ByteSize(uint64(component.Size))
+		if component.Error != "" {
+			size = "unknown"
+		}
+		t.AddRow(map[string]interface{}{
+			"Type": component.Type,
+			"Name": component.Name,
+			"Size": size,
+		})
+	}
+	t.Print()
+	fmt.Printf("\nEstimated size: %s (uncompressed)\n", bytefmt.ByteSize(uint64(estimate.Size)))
+	fmt.Printf("Estimated duration: %s at %s/s\n", estimate.Duration-estimate.Duration%time.Second, bytefmt.ByteSize(uint64(estimate.Throughput)))
+}
+
+// serviced backup inspect FILEPATH [--verbose, -v]
+func (c *ServicedCli) cmdBackupInspect(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "inspect")
+		return
+	}
+	inspection, err := c.driver.InspectBackup(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonInspection, err := c.marshalOutput(inspection); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal backup inspection: %s", err)
+		} else {
+			fmt.Println(string(jsonInspection))
+		}
+		return
+	}
+
+	fmt.Printf("Backup version: %d (this system writes version %d)\n", inspection.BackupVersion, inspection.CurrentVersion)
+	fmt.Printf("Format: %s\n", inspection.Format)
+	fmt.Printf("Created: %s\n", inspection.Timestamp.UTC().Format(time.RFC3339))
+	if inspection.Supported {
+		fmt.Printf("Restorable: yes\n\n")
+	} else {
+		fmt.Printf("Restorable: no\n\n")
+	}
+	t := NewTable("Feature,Restorable,Note")
+	t.Padding = 4
+	for _, feature := range inspection.Features {
+		t.AddRow(map[string]interface{}{
+			"Feature":    feature.Name,
+			"Restorable": feature.Restorable,
+			"Note":       feature.Note,
+		})
+	}
+	t.Print()
+}
+
+// serviced backup cleanup-failed [--dry-run]
+func (c *ServicedCli) cmdBackupCleanupFailed(ctx *cli.Context) {
+	quarantine, err := c.driver.CleanupFailedRestore(ctx.Bool("dry-run"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if quarantine.Empty() {
+		fmt.Fprintln(os.Stderr, "no failed restores found")
+		return
+	}
+
+	t := NewTable("Type,Tenant,Name")
+	t.Padding = 4
+	tenants := make([]string, 0, len(quarantine.Snapshots))
+	for tenant := range quarantine.Snapshots {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	for _, tenant := range tenants {
+		for _, label := range quarantine.Snapshots[tenant] {
+			t.AddRow(map[string]interface{}{"Type": "snapshot", "Tenant": tenant, "Name": label})
+		}
+	}
+	for _, tenant := range quarantine.Volumes {
+		t.AddRow(map[string]interface{}{"Type": "volume", "Tenant": tenant, "Name": tenant})
+	}
+	for _, image := range quarantine.Images {
+		t.AddRow(map[string]interface{}{"Type": "image", "Tenant": "", "Name": image})
+	}
+	t.Print()
+}
+
 // serviced restore FILEPATH
 func (c *ServicedCli) cmdRestore(ctx *cli.Context) {
 	args := ctx.Args()
@@ -72,7 +290,7 @@ func (c *ServicedCli) cmdRestore(ctx *cli.Context) {
 		return
 	}
 
-	err := c.driver.Restore(args[0])
+	err := c.driver.Restore(args[0], ctx.StringSlice("force"))
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 	}
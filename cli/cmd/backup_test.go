@@ -19,8 +19,12 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backup"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -29,15 +33,33 @@ const (
 	NilPath      = "NilPath"
 )
 
-var DefaultBackupAPITest = BackupAPITest{}
+var DefaultBackupAPITest = BackupAPITest{backups: DefaultTestBackups}
+
+var DefaultTestBackups = []backup.Backup{
+	{
+		ID:        "backup-2016-08-01-120000.tgz",
+		Location:  "/opt/serviced/var/backups/backup-2016-08-01-120000.tgz",
+		Size:      2 * 1024 * 1024,
+		Checksum:  "abc123",
+		StartedAt: time.Date(2016, 8, 1, 12, 0, 0, 0, time.UTC),
+		Duration:  90 * time.Second,
+		Templates: []string{"Zenoss.core"},
+		Available: true,
+	},
+}
 
 var (
-	ErrBackupFailed  = errors.New("backup failed")
-	ErrRestoreFailed = errors.New("restore failed")
+	ErrBackupFailed   = errors.New("backup failed")
+	ErrRestoreFailed  = errors.New("restore failed")
+	ErrListFailed     = errors.New("could not list backups")
+	ErrEstimateFailed = errors.New("could not estimate backup")
+	ErrCleanupFailed  = errors.New("could not clean up failed restore")
 )
 
 type BackupAPITest struct {
 	api.API
+	backups []backup.Backup
+	fail    bool
 }
 
 func InitBackupAPITest(args ...string) {
@@ -55,7 +77,63 @@ func (t BackupAPITest) Backup(dirpath string, excludes []string) (string, error)
 	}
 }
 
-func (t BackupAPITest) Restore(path string) error {
+func (t BackupAPITest) ListBackups() ([]backup.Backup, error) {
+	if t.fail {
+		return nil, ErrListFailed
+	}
+	return t.backups, nil
+}
+
+func (t BackupAPITest) EstimateBackup(excludes []string) (*dfs.BackupEstimate, error) {
+	if t.fail {
+		return nil, ErrEstimateFailed
+	}
+	estimate := &dfs.BackupEstimate{
+		Components: []dfs.BackupComponent{
+			{Type: dfs.BackupComponentImage, Name: "zenoss/core:latest", Size: 3 * 1024 * 1024 * 1024},
+			{Type: dfs.BackupComponentImage, Name: "zenoss/missing:latest", Error: "image not found"},
+			{Type: dfs.BackupComponentVolume, Name: "tenant", Size: 1024 * 1024 * 1024},
+		},
+		Size:       4 * 1024 * 1024 * 1024,
+		Throughput: 50 * 1024 * 1024,
+	}
+	if len(excludes) > 0 {
+		estimate.Components[2].Size = 512 * 1024 * 1024
+		estimate.Size -= 512 * 1024 * 1024
+	}
+	estimate.Duration = time.Duration(float64(estimate.Size)/estimate.Throughput) * time.Second
+	return estimate, nil
+}
+
+func (t BackupAPITest) CleanupFailedRestore(dryRun bool) (*dfs.RestoreQuarantine, error) {
+	if t.fail {
+		return nil, ErrCleanupFailed
+	}
+	return &dfs.RestoreQuarantine{
+		Volumes:   []string{"tenant2"},
+		Snapshots: map[string][]string{"tenant2": {"LABEL2"}, "tenant1": {"LABEL1"}},
+		Images:    []string{"tenant1/repo:LABEL1"},
+	}, nil
+}
+
+func (t BackupAPITest) InspectBackup(path string) (*dfs.BackupInspection, error) {
+	if path == PathNotFound {
+		return nil, ErrRestoreFailed
+	}
+	return &dfs.BackupInspection{
+		BackupVersion:  0,
+		CurrentVersion: 1,
+		Supported:      true,
+		Format:         "pre-1.1.3 tar of snapshot tarballs and a docker image tarball",
+		Timestamp:      time.Date(2016, 8, 1, 12, 0, 0, 0, time.UTC),
+		Features: []dfs.BackupFeature{
+			{Name: dfs.FeatureSnapshots, Restorable: true, Note: "2 snapshots"},
+			{Name: dfs.FeatureDriverTypeCheck, Note: "not in backup version 0"},
+		},
+	}, nil
+}
+
+func (t BackupAPITest) Restore(path string, force []string) error {
 	switch path {
 	case PathNotFound:
 		return ErrRestoreFailed
@@ -77,28 +155,170 @@ func ExampleServicedCli_cmdBackup() {
 }
 
 func ExampleServicedCLI_CmdBackup_usage() {
-	InitBackupAPITest("serviced", "backup")
+	// the subcommand help pads some lines with trailing whitespace
+	output := pipe(InitBackupAPITest, "serviced", "backup")
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fmt.Println(strings.TrimRight(line, " \t"))
+	}
 
 	// Output:
 	// Incorrect Usage.
 	//
 	// NAME:
-	//    backup - Dump all templates and services to a tgz file
+	//    serviced backup - serviced backup [--exclude SUBDIR] DIRPATH
 	//
 	// USAGE:
-	//    command backup [command options] [arguments...]
+	//    serviced backup [global options] command [command options] [arguments...]
 	//
-	// DESCRIPTION:
-	//    serviced backup DIRPATH
+	// COMMANDS:
+	//    list			Lists the catalog of completed backups
+	//    estimate		Estimates the size and duration of a backup
+	//    inspect		Reports the version of a backup and what can be restored from it
+	//    cleanup-failed	Removes the volumes, snapshots, and images imported by a failed restore
+	//    help, h		Shows a list of commands or help for one command
 	//
 	// OPTIONS:
 	//    --exclude '--exclude option --exclude option'	Subdirectory of the tenant volume to exclude from backup
+	//    --generate-bash-completion
+	//    --help, -h						show help
+}
+
+func ExampleServicedCLI_CmdBackup_trailingArgs() {
+	// the subcommand help pads some lines with trailing whitespace
+	output := pipe(InitBackupAPITest, "serviced", "backup", "path/to/dir", "--exclude", "dir")
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fmt.Println(strings.TrimRight(line, " \t"))
+	}
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    serviced backup - serviced backup [--exclude SUBDIR] DIRPATH
+	//
+	// USAGE:
+	//    serviced backup [global options] command [command options] [arguments...]
+	//
+	// COMMANDS:
+	//    list			Lists the catalog of completed backups
+	//    estimate		Estimates the size and duration of a backup
+	//    inspect		Reports the version of a backup and what can be restored from it
+	//    cleanup-failed	Removes the volumes, snapshots, and images imported by a failed restore
+	//    help, h		Shows a list of commands or help for one command
+	//
+	// OPTIONS:
+	//    --exclude '--exclude option --exclude option'	Subdirectory of the tenant volume to exclude from backup
+	//    --generate-bash-completion
+	//    --help, -h						show help
+}
+
+func ExampleServicedCLI_CmdBackupList() {
+	InitBackupAPITest("serviced", "backup", "list", "--show-fields", "ID,Size,Duration,Available")
+
+	// Output:
+	// ID                              Size    Duration    Available
+	// backup-2016-08-01-120000.tgz    2M      1m30s       true
+}
+
+func ExampleServicedCLI_CmdBackupList_verbose() {
+	InitBackupAPITest("serviced", "backup", "list", "--verbose")
+
+	// Output:
+	// [
+	//    {
+	//      "ID": "backup-2016-08-01-120000.tgz",
+	//      "Location": "/opt/serviced/var/backups/backup-2016-08-01-120000.tgz",
+	//      "Size": 2097152,
+	//      "Checksum": "abc123",
+	//      "StartedAt": "2016-08-01T12:00:00Z",
+	//      "Duration": 90000000000,
+	//      "Templates": [
+	//        "Zenoss.core"
+	//      ],
+	//      "BaseImages": null,
+	//      "Pools": null,
+	//      "Snapshots": null,
+	//      "BackupVersion": 0,
+	//      "Available": true
+	//    }
+	//  ]
+}
+
+func ExampleServicedCLI_CmdBackupList_fail() {
+	New(BackupAPITest{fail: true}, utils.TestConfigReader{}).Run([]string{"serviced", "backup", "list"})
+	New(BackupAPITest{}, utils.TestConfigReader{}).Run([]string{"serviced", "backup", "list"})
+
+	// Output:
+}
+
+func ExampleServicedCLI_CmdBackupEstimate() {
+	InitBackupAPITest("serviced", "backup", "estimate")
+	InitBackupAPITest("serviced", "backup", "estimate", "--exclude", "cache")
+
+	// Output:
+	// Type      Name                     Size
+	// image     zenoss/core:latest       3G
+	// image     zenoss/missing:latest    unknown
+	// volume    tenant                   1G
+	//
+	// Estimated size: 4G (uncompressed)
+	// Estimated duration: 1m21s at 50M/s
+	// Type      Name                     Size
+	// image     zenoss/core:latest       3G
+	// image     zenoss/missing:latest    unknown
+	// volume    tenant                   512M
 	//
+	// Estimated size: 3.5G (uncompressed)
+	// Estimated duration: 1m11s at 50M/s
+}
+
+func ExampleServicedCLI_CmdBackupEstimate_fail() {
+	New(BackupAPITest{fail: true}, utils.TestConfigReader{}).Run([]string{"serviced", "backup", "estimate"})
+
+	// Output:
+}
+
+func ExampleServicedCLI_CmdBackupInspect() {
+	InitBackupAPITest("serviced", "backup", "inspect", "path/to/file")
+
+	// Output:
+	// Backup version: 0 (this system writes version 1)
+	// Format: pre-1.1.3 tar of snapshot tarballs and a docker image tarball
+	// Created: 2016-08-01T12:00:00Z
+	// Restorable: yes
+	//
+	// Feature              Restorable    Note
+	// snapshots            true          2 snapshots
+	// driver-type-check    false         not in backup version 0
+}
+
+func ExampleServicedCLI_CmdBackupInspect_fail() {
+	InitBackupAPITest("serviced", "backup", "inspect", PathNotFound)
+
+	// Output:
+}
+
+func ExampleServicedCLI_CmdBackupCleanupFailed() {
+	InitBackupAPITest("serviced", "backup", "cleanup-failed", "--dry-run")
+
+	// Output:
+	// Type        Tenant     Name
+	// snapshot    tenant1    LABEL1
+	// snapshot    tenant2    LABEL2
+	// volume      tenant2    tenant2
+	// image                  tenant1/repo:LABEL1
+}
+
+func ExampleServicedCLI_CmdBackupCleanupFailed_fail() {
+	New(BackupAPITest{fail: true}, utils.TestConfigReader{}).Run([]string{"serviced", "backup", "cleanup-failed"})
+
+	// Output:
 }
 
 func ExampleServicedCli_cmdRestore() {
 	InitBackupAPITest("serviced", "restore", PathNotFound)
 	InitBackupAPITest("serviced", "restore", "path/to/file")
+	InitBackupAPITest("serviced", "restore", "--force", "docker-version", "path/to/file")
 
 	// Output:
 }
@@ -116,7 +336,8 @@ func ExampleServicedCLI_CmdRestore_usage() {
 	//    command restore [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced restore FILEPATH
+	//    serviced restore [--force CHECK] FILEPATH
 	//
 	// OPTIONS:
+	//    --force '--force option --force option'	Compatibility check to ignore (backup-version cannot be ignored): docker-version, driver-type, template-version, or all
 }
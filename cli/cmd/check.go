@@ -0,0 +1,203 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// Initializer for serviced check subcommands
+func (c *ServicedCli) initCheck() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "check",
+		Usage:       "Checks the state of the control center",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "consistency",
+				Usage:       "Compares the services and hosts in the datastore with their state in ZooKeeper",
+				Description: "serviced check consistency [--repair]",
+				Action:      c.cmdCheckConsistency,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "repair",
+						Usage: "Update ZooKeeper to match the datastore",
+					},
+				},
+			}, {
+				Name:         "network",
+				Usage:        "Probes the ports that the hosts of a resource pool need to reach on each other and on the master",
+				Description:  "serviced check network POOLID",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdCheckNetwork,
+			},
+		},
+	})
+}
+
+// serviced check consistency [--repair]
+func (c *ServicedCli) cmdCheckConsistency(ctx *cli.Context) {
+	report, err := c.driver.CheckConsistency(ctx.Bool("repair"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	printConsistencyReport(report)
+}
+
+func printConsistencyReport(report *zkservice.ConsistencyReport) {
+	if report.Consistent() {
+		fmt.Println("The datastore and ZooKeeper are consistent")
+		return
+	}
+	if report.Repaired {
+		fmt.Println("Repaired the differences between the datastore and ZooKeeper:")
+	} else {
+		fmt.Println("Found differences between the datastore and ZooKeeper:")
+	}
+
+	fmt.Println()
+	t := NewTable("Difference,ID,Name,Pool,Datastore,ZooKeeper")
+	t.Padding = 6
+	for _, s := range report.OrphanedServices {
+		t.AddRow(map[string]interface{}{
+			"Difference": "orphaned service",
+			"ID":         s.ServiceID,
+			"Name":       s.Name,
+			"Pool":       s.PoolID,
+			"Datastore":  "-",
+			"ZooKeeper":  service.DesiredState(s.ZKDesiredState).String(),
+		})
+	}
+	for _, s := range report.DesiredStateMismatches {
+		t.AddRow(map[string]interface{}{
+			"Difference": "desired state",
+			"ID":         s.ServiceID,
+			"Name":       s.Name,
+			"Pool":       s.PoolID,
+			"Datastore":  service.DesiredState(s.DesiredState).String(),
+			"ZooKeeper":  service.DesiredState(s.ZKDesiredState).String(),
+		})
+	}
+	for _, h := range report.DatastoreOnlyHosts {
+		t.AddRow(map[string]interface{}{
+			"Difference": "host not in ZooKeeper",
+			"ID":         h.HostID,
+			"Name":       "-",
+			"Pool":       h.PoolID,
+			"Datastore":  "-",
+			"ZooKeeper":  "-",
+		})
+	}
+	for _, h := range report.ZKOnlyHosts {
+		t.AddRow(map[string]interface{}{
+			"Difference": "orphaned host",
+			"ID":         h.HostID,
+			"Name":       "-",
+			"Pool":       h.PoolID,
+			"Datastore":  "-",
+			"ZooKeeper":  "-",
+		})
+	}
+	t.Print()
+}
+
+// serviced check network POOLID
+func (c *ServicedCli) cmdCheckNetwork(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "network")
+		return
+	}
+
+	report, err := c.driver.CheckNetwork(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if len(report.HostIDs) == 0 {
+		fmt.Fprintf(os.Stderr, "no hosts found in pool %s\n", report.PoolID)
+		return
+	}
+	printNetworkReport(report)
+	if len(report.Blocked()) > 0 {
+		c.exit(1)
+	}
+}
+
+// printNetworkReport prints the connectivity matrix between the hosts of a
+// pool, with a row for each source host and a column for each destination,
+// followed by the paths that are blocked.
+func printNetworkReport(report *host.NetworkReport) {
+	destinations := append([]string{}, report.HostIDs...)
+	for _, path := range report.Paths {
+		if path.ToHostID == host.NetworkMaster {
+			destinations = append(destinations, host.NetworkMaster)
+			break
+		}
+	}
+
+	// names of the blocked ports by source and destination
+	blocked := make(map[string]map[string][]string)
+	for _, path := range report.Blocked() {
+		if blocked[path.FromHostID] == nil {
+			blocked[path.FromHostID] = make(map[string][]string)
+		}
+		blocked[path.FromHostID][path.ToHostID] = append(blocked[path.FromHostID][path.ToHostID], path.Name)
+	}
+
+	t := NewTable("From," + strings.Join(destinations, ","))
+	t.Padding = 6
+	for _, from := range report.HostIDs {
+		row := map[string]interface{}{"From": from}
+		for _, to := range destinations {
+			if to == from {
+				row[to] = "-"
+			} else if names := blocked[from][to]; len(names) > 0 {
+				row[to] = "blocked: " + strings.Join(names, ",")
+			} else {
+				row[to] = "ok"
+			}
+		}
+		t.AddRow(row)
+	}
+	t.Print()
+
+	paths := report.Blocked()
+	if len(paths) == 0 {
+		fmt.Printf("\nAll %d paths are open\n", len(report.Paths))
+		return
+	}
+	fmt.Printf("\n%d of %d paths are blocked:\n\n", len(paths), len(report.Paths))
+	t = NewTable("From,To,Port,Address,Error")
+	t.Padding = 6
+	for _, path := range paths {
+		t.AddRow(map[string]interface{}{
+			"From":    path.FromHostID,
+			"To":      path.ToHostID,
+			"Port":    path.Name,
+			"Address": path.Address,
+			"Error":   path.Error,
+		})
+	}
+	t.Print()
+}
@@ -0,0 +1,150 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/utils"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+type CheckAPITest struct {
+	api.API
+	report  *zkservice.ConsistencyReport
+	network *host.NetworkReport
+}
+
+func (t CheckAPITest) CheckConsistency(repair bool) (*zkservice.ConsistencyReport, error) {
+	report := *t.report
+	report.Repaired = repair && !report.Consistent()
+	return &report, nil
+}
+
+func (t CheckAPITest) CheckNetwork(poolID string) (*host.NetworkReport, error) {
+	if t.network == nil || t.network.PoolID != poolID {
+		return nil, errors.New("pool not found")
+	}
+	return t.network, nil
+}
+
+func InitCheckAPITest(report *zkservice.ConsistencyReport, args ...string) {
+	New(CheckAPITest{report: report}, utils.TestConfigReader(make(map[string]string))).Run(args)
+}
+
+var inconsistentReport = &zkservice.ConsistencyReport{
+	OrphanedServices: []zkservice.ServiceInconsistency{
+		{ServiceID: "svc-deleted", Name: "deleted", PoolID: "default", ZKDesiredState: int(service.SVCRun)},
+	},
+	DesiredStateMismatches: []zkservice.ServiceInconsistency{
+		{ServiceID: "svc-web", Name: "web", PoolID: "default", DesiredState: int(service.SVCStop), ZKDesiredState: int(service.SVCRun)},
+	},
+	ZKOnlyHosts: []zkservice.HostInconsistency{{HostID: "host3", PoolID: "default"}},
+}
+
+func ExampleServicedCLI_CmdCheckConsistency() {
+	InitCheckAPITest(&zkservice.ConsistencyReport{}, "serviced", "check", "consistency")
+
+	// Output:
+	// The datastore and ZooKeeper are consistent
+}
+
+func ExampleServicedCLI_CmdCheckConsistency_inconsistent() {
+	InitCheckAPITest(inconsistentReport, "serviced", "check", "consistency")
+
+	// Output:
+	// Found differences between the datastore and ZooKeeper:
+	//
+	// Difference            ID               Name         Pool         Datastore      ZooKeeper
+	// orphaned service      svc-deleted      deleted      default      -              go
+	// desired state         svc-web          web          default      stop           go
+	// orphaned host         host3            -            default      -              -
+}
+
+func ExampleServicedCLI_CmdCheckConsistency_repair() {
+	InitCheckAPITest(inconsistentReport, "serviced", "check", "consistency", "--repair")
+
+	// Output:
+	// Repaired the differences between the datastore and ZooKeeper:
+	//
+	// Difference            ID               Name         Pool         Datastore      ZooKeeper
+	// orphaned service      svc-deleted      deleted      default      -              go
+	// desired state         svc-web          web          default      stop           go
+	// orphaned host         host3            -            default      -              -
+}
+
+var networkReport = &host.NetworkReport{
+	PoolID:  "default",
+	HostIDs: []string{"host1", "host2"},
+	Paths: []host.NetworkPath{
+		{FromHostID: "host1", ToHostID: "host2", Name: "mux", Address: "10.0.0.2:22250", Open: true},
+		{FromHostID: "host1", ToHostID: "host2", Name: "rpc", Address: "10.0.0.2:4979", Open: true},
+		{FromHostID: "host1", ToHostID: host.NetworkMaster, Name: "nfs", Address: "10.0.0.9:2049", Open: true},
+		{FromHostID: "host2", ToHostID: "host1", Name: "mux", Address: "10.0.0.1:22250", Error: "connection refused"},
+		{FromHostID: "host2", ToHostID: "host1", Name: "rpc", Address: "10.0.0.1:4979", Error: "i/o timeout"},
+		{FromHostID: "host2", ToHostID: host.NetworkMaster, Name: "nfs", Address: "10.0.0.9:2049", Open: true},
+	},
+}
+
+func ExampleServicedCLI_CmdCheckNetwork() {
+	report := *networkReport
+	report.Paths = make([]host.NetworkPath, len(networkReport.Paths))
+	for i, path := range networkReport.Paths {
+		path.Open, path.Error = true, ""
+		report.Paths[i] = path
+	}
+	c := New(CheckAPITest{network: &report}, utils.TestConfigReader(make(map[string]string)))
+	c.Run([]string{"serviced", "check", "network", "default"})
+
+	// Output:
+	// From       host1      host2      master
+	// host1      -          ok         ok
+	// host2      ok         -          ok
+	//
+	// All 6 paths are open
+}
+
+func ExampleServicedCLI_CmdCheckNetwork_blocked() {
+	c := New(CheckAPITest{network: networkReport}, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	c.Run([]string{"serviced", "check", "network", "default"})
+	fmt.Println("exit code", c.exitCode)
+
+	// Output:
+	// From       host1                 host2      master
+	// host1      -                     ok         ok
+	// host2      blocked: mux,rpc      -          ok
+	//
+	// 2 of 6 paths are blocked:
+	//
+	// From       To         Port      Address             Error
+	// host2      host1      mux       10.0.0.1:22250      connection refused
+	// host2      host1      rpc       10.0.0.1:4979       i/o timeout
+	// exit code 1
+}
+
+func ExampleServicedCLI_CmdCheckNetwork_err() {
+	c := New(CheckAPITest{network: networkReport}, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	pipeStderr(func(args ...string) { c.Run(args) }, "serviced", "check", "network", "nopool")
+
+	// Output:
+	// pool not found
+}
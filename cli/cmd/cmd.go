@@ -20,6 +20,7 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/codegangsta/cli"
@@ -34,6 +35,7 @@ import (
 	"github.com/control-center/serviced/validation"
 	"github.com/control-center/serviced/volume"
 	"github.com/control-center/serviced/volume/nfs"
+	"github.com/control-center/serviced/volume/rsync"
 	"github.com/zenoss/glog"
 	"github.com/zenoss/logri"
 )
@@ -48,6 +50,12 @@ type ServicedCli struct {
 	app          *cli.App
 	config       utils.ConfigReader
 	exitDisabled bool
+	output       string // format of the data printed by commands
+
+	// telemetry of the running command
+	args     []string
+	started  time.Time
+	exitCode int
 }
 
 // New instantiates a new command-line client
@@ -113,9 +121,12 @@ func New(driver api.API, config utils.ConfigReader) *ServicedCli {
 		cli.StringFlag{"mc-password", defaultOps.MCPasswd, "Password for the Zenoss metric consumer"},
 		cli.StringFlag{"cpuprofile", defaultOps.CPUProfile, "write cpu profile to file"},
 		cli.StringSliceFlag{"isvcs-env", convertToStringSlice(config.StringNumberedList("ISVCS_ENV", []string{})), "internal-service environment variable: ISVC:KEY=VAL"},
+		cli.StringSliceFlag{"isvcs-resource", convertToStringSlice(config.StringNumberedList("ISVCS_RESOURCE", []string{})), "internal-service resource setting (heap, cpu_shares, memory, volume.NAME): ISVC:KEY=VAL"},
 		cli.IntFlag{"debug-port", defaultOps.DebugPort, "Port on which to listen for profiler connections"},
 		cli.IntFlag{"max-rpc-clients", defaultOps.MaxRPCClients, "max number of rpc clients to an endpoint"},
 		cli.IntFlag{"rpc-dial-timeout", defaultOps.RPCDialTimeout, "timeout for creating rpc connections"},
+		cli.IntFlag{"rpc-call-timeout", defaultOps.RPCCallTimeout, "seconds before an rpc call to the master is canceled, 0 for no limit"},
+		cli.IntFlag{"rpc-slow-call-threshold", defaultOps.RPCSlowCallThreshold, "milliseconds after which a served rpc call is logged as slow, 0 to disable"},
 		cli.StringFlag{"rpc-cert-verify", defaultOps.RPCCertVerify, "enable verification of rpc server certificate"},
 		cli.StringFlag{"rpc-disable-tls", defaultOps.RPCDisableTLS, "disable tls for RPC connections"},
 		cli.StringSliceFlag{"rpc-tls-ciphers", convertToStringSlice(defaultOps.RPCTLSCiphers), "list of supported TLS ciphers for RPC"},
@@ -127,9 +138,54 @@ func New(driver api.API, config utils.ConfigReader) *ServicedCli {
 		cli.StringSliceFlag{"log-config", convertToStringSlice(defaultOps.DockerLogConfigList), "comma-separated list of key=value settings for docker log driver"},
 
 		cli.IntFlag{"ui-poll-frequency", defaultOps.UIPollFrequency, "frequency in seconds that the UI polls serviced for changes"},
+		cli.StringSliceFlag{"ui-cors-origin", convertToStringSlice(defaultOps.UICORSOrigins), "origin allowed to make cross-origin requests to the api (e.g. https://dashboard.example.com), * for any"},
+		cli.BoolFlag{"ui-disable-compression", "do not gzip the responses of the UI server"},
+		cli.BoolFlag{"ui-headless", "serve only the JSON api, without the static assets of the UI"},
 		cli.IntFlag{"storage-stats-update-interval", defaultOps.StorageStatsUpdateInterval, "frequency in seconds that the thin pool usage will be analyzed"},
 		cli.IntFlag{"zk-session-timeout", defaultOps.ZKSessionTimeout, "zookeeper session timeout in seconds"},
 		cli.IntFlag{"auth-token-expiry", defaultOps.TokenExpiration, "authentication token expiration in seconds"},
+		cli.BoolFlag{"allow-chaos", "allow chaos mode to kill random service instances (testing only)"},
+		cli.BoolFlag{"repair-consistency", "repair differences between the datastore and zookeeper found when the master starts"},
+		cli.IntFlag{"heartbeat-min-interval", defaultOps.HeartbeatMinInterval, "seconds between delegate heartbeats while instances are changing"},
+		cli.IntFlag{"heartbeat-max-interval", defaultOps.HeartbeatMaxInterval, "seconds between delegate heartbeats while the host is idle"},
+		cli.IntFlag{"heartbeat-jitter", defaultOps.HeartbeatJitter, "percent of the heartbeat interval to randomly add or remove"},
+		cli.IntFlag{"max-clock-skew", defaultOps.MaxClockSkew, "seconds a delegate clock may differ from the master before it is flagged"},
+		cli.IntFlag{"auth-clock-skew-tolerance", defaultOps.AuthClockSkewTolerance, "seconds of clock skew tolerated when validating authentication tokens"},
+		cli.IntFlag{"storage-warning-percent", defaultOps.StorageWarningPercent, "percent of a host's storage pool used before the host is flagged with a warning"},
+		cli.IntFlag{"storage-critical-percent", defaultOps.StorageCriticalPercent, "percent of a host's storage pool used before the host is flagged as critical"},
+		cli.StringFlag{"emergency-min-free-dfs", defaultOps.EmergencyMinFreeDFS, "free space of the application storage below which all services are emergency stopped (e.g. 3G)"},
+		cli.StringFlag{"emergency-min-free-thinpool", defaultOps.EmergencyMinFreeThinPool, "free space of a thin pool below which all services are emergency stopped (e.g. 3G)"},
+		cli.BoolFlag{"master-ha", "fail over internal services to this master when the active master goes away"},
+		cli.StringFlag{"master-ha-device", defaultOps.MasterHADevice, "shared storage device mounted by the active master"},
+		cli.StringFlag{"master-ha-mount-path", defaultOps.MasterHAMountPath, "path where the active master mounts the shared storage"},
+		cli.StringFlag{"es-url", defaultOps.ESURL, "url of an external elasticsearch cluster to use instead of elasticsearch-serviced (e.g. https://user:pass@es:9200)"},
+		cli.StringFlag{"opentsdb-url", defaultOps.OpenTSDBURL, "url of an external opentsdb cluster that receives internal service stats"},
+		cli.BoolFlag{"zk-external", "use the configured zookeeper ensemble instead of starting the zookeeper internal service"},
+		cli.StringFlag{"isvcs-external-ca", defaultOps.ExternalCAFile, "file of CA certificates used to verify external internal service endpoints"},
+		cli.StringFlag{"isvcs-external-cert", defaultOps.ExternalCertFile, "client certificate file presented to external internal service endpoints"},
+		cli.StringFlag{"isvcs-external-key", defaultOps.ExternalKeyFile, "key file of the client certificate presented to external internal service endpoints"},
+		cli.StringFlag{"zk-auth", defaultOps.ZKAuth, "digest credentials (user:password) used for zookeeper sessions"},
+		cli.BoolFlag{"master-replica", "serve master reads from a cache and forward writes to the master"},
+		cli.IntFlag{"replica-max-staleness", defaultOps.ReplicaMaxStaleness, "seconds a read replica may serve cached reads"},
+		cli.StringFlag{"trace-url", defaultOps.TraceURL, "zipkin spans endpoint that receives request traces (e.g. http://jaeger:9411/api/v2/spans)"},
+		cli.IntFlag{"trace-sample-percent", defaultOps.TraceSamplePercent, "percent of requests that are traced"},
+		cli.StringFlag{"log-format", defaultOps.LogFormat, "format of log messages: text or json"},
+		cli.StringFlag{"log-file", defaultOps.LogFile, "file that receives log messages instead of stderr"},
+		cli.IntFlag{"log-max-size", defaultOps.LogMaxSize, "megabytes the log file may grow to before it is rotated"},
+		cli.IntFlag{"log-max-backups", defaultOps.LogMaxBackups, "number of rotated log files to keep"},
+		cli.StringSliceFlag{"log-level", convertToStringSlice(defaultOps.LogLevels), "log level of a component, of the form component=level (e.g. zzk=debug)"},
+		cli.BoolFlag{"preserve-instances", "leave service instances running when the agent shuts down so they are re-attached on restart"},
+		cli.IntFlag{"preserve-instances-timeout", defaultOps.PreserveInstancesTimeout, "seconds the master waits for a restarting agent before rescheduling its instances"},
+		cli.StringFlag{"upgrade-command", defaultOps.UpgradeCommand, "shell command that installs the release of serviced in $SERVICED_UPGRADE_VERSION"},
+		cli.StringFlag{"output", api.FormatJSON, "format of the data printed and edited by commands: json or yaml"},
+		cli.IntFlag{"rollback-window", defaultOps.RollbackWindow, "seconds that a commit or image upgrade is verified before it is kept, 0 to disable automatic rollback"},
+		cli.StringFlag{"container-output-path", defaultOps.ContainerOutputPath, "path where the agent buffers the recent output of its containers"},
+		cli.IntFlag{"container-output-size", defaultOps.ContainerOutputSize, "kilobytes of recent output buffered for each container, 0 to disable"},
+		cli.BoolFlag{"offline-instances", "keep running and restarting service instances while the agent is disconnected from the master"},
+		cli.StringFlag{"instance-cache-path", defaultOps.InstanceCachePath, "file where the agent caches its assigned instances for offline operation"},
+		cli.IntFlag{"snapshot-io-limit", defaultOps.SnapshotIOLimit, "megabytes per second copied by rsync snapshots, 0 for no limit"},
+		cli.IntFlag{"backup-io-limit", defaultOps.BackupIOLimit, "megabytes per second read from application volumes during a backup, 0 for no limit"},
+		cli.IntFlag{"restore-io-limit", defaultOps.RestoreIOLimit, "megabytes per second written to application volumes during a restore, 0 for no limit"},
 
 		// Reimplementing GLOG flags :(
 		cli.BoolTFlag{"logtostderr", "log to standard error instead of files"},
@@ -151,27 +207,44 @@ func New(driver api.API, config utils.ConfigReader) *ServicedCli {
 	c.initPool()
 	c.initConfig()
 	c.initHealthCheck()
+	c.initDeployment()
+	c.initDebug()
+	c.initMaster()
+	c.initCheck()
+	c.initISvcs()
+	c.initZooKeeper()
+	c.initDFS()
 	c.initHost()
 	c.initTemplate()
 	c.initService()
 	c.initSnapshot()
 	c.initLog()
 	c.initBackup()
+	c.initMaintenance()
+	c.initSchedule()
+	c.initUser()
+	c.initStats()
 	c.initMetric()
 	c.initDocker()
 	c.initScript()
 	c.initServer()
 	c.initVolume()
 	c.initKey()
+	c.initDB()
+	c.initUpgrade()
+	c.initFormat()
 
 	return c
 }
 
 // Run builds the command-line interface for serviced and runs.
 func (c *ServicedCli) Run(args []string) {
-	if err := c.app.Run(args); err != nil {
+	c.args, c.started, c.exitCode = args, time.Now(), 0
+	err := c.app.Run(args)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 	}
+	c.recordCommand(args, c.started, err)
 }
 
 // cmdInit is executed before EVERY CLI command/subcommand. Any messages output by this
@@ -188,6 +261,12 @@ func (c *ServicedCli) cmdInit(ctx *cli.Context) error {
 	}
 	config.LoadOptions(options)
 
+	c.output = ctx.String("output")
+	if err := api.ValidateFormat(c.output); err != nil {
+		fmt.Printf("Invalid option(s) found: %s\n", err)
+		return err
+	}
+
 	// Set logging options
 	if err := setLogging(ctx); err != nil {
 		fmt.Printf("Unable to set logging options: %s\n", err)
@@ -208,6 +287,12 @@ func (c *ServicedCli) cmdInit(ctx *cli.Context) error {
 	return nil
 }
 
+// marshalOutput marshals the data printed by a command in the format chosen
+// with the --output flag
+func (c *ServicedCli) marshalOutput(v interface{}) ([]byte, error) {
+	return api.MarshalFormat(v, c.output)
+}
+
 // This will authenticate the host once to get a valid token for any CLI commands
 //  that require it.
 func (c *ServicedCli) authenticateHost(options *config.Options) error {
@@ -245,9 +330,11 @@ func (c *ServicedCli) authenticateHost(options *config.Options) error {
 }
 
 func (c *ServicedCli) exit(code int) error {
+	c.exitCode = code
 	if c.exitDisabled {
 		return fmt.Errorf("exit code %v", code)
 	}
+	c.recordCommand(c.args, c.started, nil)
 	os.Exit(code)
 	return nil
 }
@@ -259,6 +346,7 @@ func getRuntimeOptions(ctx *cli.Context) config.Options {
 		DockerRegistry:             ctx.GlobalString("docker-registry"),
 		NFSClient:                  ctx.GlobalString("nfs-client"),
 		Endpoint:                   ctx.GlobalString("endpoint"),
+		ConfigFile:                 ctx.GlobalString("config-file"),
 		StaticIPs:                  ctx.GlobalStringSlice("static-ip"),
 		UIPort:                     service.ScrubPortString(ctx.GlobalString("uiport")),
 		RPCPort:                    fmt.Sprintf("%d", ctx.GlobalInt("listen")),
@@ -301,6 +389,8 @@ func getRuntimeOptions(ctx *cli.Context) config.Options {
 		AdminGroup:                 ctx.GlobalString("admin-group"),
 		MaxRPCClients:              ctx.GlobalInt("max-rpc-clients"),
 		RPCDialTimeout:             ctx.GlobalInt("rpc-dial-timeout"),
+		RPCCallTimeout:             ctx.GlobalInt("rpc-call-timeout"),
+		RPCSlowCallThreshold:       ctx.GlobalInt("rpc-slow-call-threshold"),
 		RPCCertVerify:              ctx.GlobalString("rpc-cert-verify"),
 		RPCDisableTLS:              ctx.GlobalString("rpc-disable-tls"),
 		RPCTLSCiphers:              ctx.GlobalStringSlice("rpc-tls-ciphers"),
@@ -318,9 +408,53 @@ func getRuntimeOptions(ctx *cli.Context) config.Options {
 		DockerLogConfigList:        ctx.GlobalStringSlice("log-config"),
 		AllowLoopBack:              ctx.GlobalString("allow-loop-back"),
 		UIPollFrequency:            ctx.GlobalInt("ui-poll-frequency"),
+		UICORSOrigins:              ctx.GlobalStringSlice("ui-cors-origin"),
+		UIDisableCompression:       ctx.GlobalBool("ui-disable-compression"),
+		UIHeadless:                 ctx.GlobalBool("ui-headless"),
 		StorageStatsUpdateInterval: ctx.GlobalInt("storage-stats-update-interval"),
 		ZKSessionTimeout:           ctx.GlobalInt("zk-session-timeout"),
 		TokenExpiration:            ctx.GlobalInt("auth-token-expiry"),
+		AllowChaos:                 ctx.GlobalBool("allow-chaos"),
+		RepairConsistency:          ctx.GlobalBool("repair-consistency"),
+		HeartbeatMinInterval:       ctx.GlobalInt("heartbeat-min-interval"),
+		HeartbeatMaxInterval:       ctx.GlobalInt("heartbeat-max-interval"),
+		HeartbeatJitter:            ctx.GlobalInt("heartbeat-jitter"),
+		MaxClockSkew:               ctx.GlobalInt("max-clock-skew"),
+		AuthClockSkewTolerance:     ctx.GlobalInt("auth-clock-skew-tolerance"),
+		StorageWarningPercent:      ctx.GlobalInt("storage-warning-percent"),
+		StorageCriticalPercent:     ctx.GlobalInt("storage-critical-percent"),
+		EmergencyMinFreeDFS:        ctx.GlobalString("emergency-min-free-dfs"),
+		EmergencyMinFreeThinPool:   ctx.GlobalString("emergency-min-free-thinpool"),
+		MasterHA:                   ctx.GlobalBool("master-ha"),
+		MasterHADevice:             ctx.GlobalString("master-ha-device"),
+		MasterHAMountPath:          ctx.GlobalString("master-ha-mount-path"),
+		ESURL:                      ctx.GlobalString("es-url"),
+		OpenTSDBURL:                ctx.GlobalString("opentsdb-url"),
+		ZKExternal:                 ctx.GlobalBool("zk-external"),
+		ExternalCAFile:             ctx.GlobalString("isvcs-external-ca"),
+		ExternalCertFile:           ctx.GlobalString("isvcs-external-cert"),
+		ExternalKeyFile:            ctx.GlobalString("isvcs-external-key"),
+		ZKAuth:                     ctx.GlobalString("zk-auth"),
+		MasterReplica:              ctx.GlobalBool("master-replica"),
+		ReplicaMaxStaleness:        ctx.GlobalInt("replica-max-staleness"),
+		TraceURL:                   ctx.GlobalString("trace-url"),
+		TraceSamplePercent:         ctx.GlobalInt("trace-sample-percent"),
+		LogFormat:                  ctx.GlobalString("log-format"),
+		LogFile:                    ctx.GlobalString("log-file"),
+		LogMaxSize:                 ctx.GlobalInt("log-max-size"),
+		LogMaxBackups:              ctx.GlobalInt("log-max-backups"),
+		LogLevels:                  ctx.GlobalStringSlice("log-level"),
+		PreserveInstances:          ctx.GlobalBool("preserve-instances"),
+		PreserveInstancesTimeout:   ctx.GlobalInt("preserve-instances-timeout"),
+		UpgradeCommand:             ctx.GlobalString("upgrade-command"),
+		RollbackWindow:             ctx.GlobalInt("rollback-window"),
+		ContainerOutputPath:        ctx.GlobalString("container-output-path"),
+		ContainerOutputSize:        ctx.GlobalInt("container-output-size"),
+		OfflineInstances:           ctx.GlobalBool("offline-instances"),
+		InstanceCachePath:          ctx.GlobalString("instance-cache-path"),
+		SnapshotIOLimit:            ctx.GlobalInt("snapshot-io-limit"),
+		BackupIOLimit:              ctx.GlobalInt("backup-io-limit"),
+		RestoreIOLimit:             ctx.GlobalInt("restore-io-limit"),
 	}
 
 	// Long story, but due to the way codegantsta handles bools and the way we start system services vs
@@ -332,9 +466,40 @@ func getRuntimeOptions(ctx *cli.Context) config.Options {
 	if os.Getenv("SERVICED_AGENT") == "1" {
 		options.Agent = true
 	}
+	if os.Getenv("SERVICED_ALLOW_CHAOS") == "1" {
+		options.AllowChaos = true
+	}
+	if os.Getenv("SERVICED_REPAIR_CONSISTENCY") == "1" {
+		options.RepairConsistency = true
+	}
+
+	if os.Getenv("SERVICED_MASTER_HA") == "1" {
+		options.MasterHA = true
+	}
+	if os.Getenv("SERVICED_ZK_EXTERNAL") == "1" {
+		options.ZKExternal = true
+	}
+	if os.Getenv("SERVICED_MASTER_REPLICA") == "1" {
+		options.MasterReplica = true
+	}
+	if os.Getenv("SERVICED_PRESERVE_INSTANCES") == "1" {
+		options.PreserveInstances = true
+	}
+	if os.Getenv("SERVICED_OFFLINE_INSTANCES") == "1" {
+		options.OfflineInstances = true
+	}
+	if os.Getenv("SERVICED_UI_DISABLE_COMPRESSION") == "1" {
+		options.UIDisableCompression = true
+	}
+	if os.Getenv("SERVICED_UI_HEADLESS") == "1" {
+		options.UIHeadless = true
+	}
 	if options.Master {
 		fstype := ctx.GlobalString("fstype")
 		options.FSType = volume.DriverType(fstype)
+		if options.FSType == volume.DriverTypeRsync && options.SnapshotIOLimit > 0 {
+			options.StorageArgs = append(options.StorageArgs, fmt.Sprintf("%s=%d", rsync.BandwidthLimit, options.SnapshotIOLimit*1024))
+		}
 	} else {
 		options.FSType = volume.DriverTypeNFS
 		if options.NFSClient == "0" {
@@ -421,12 +586,14 @@ func setLogging(ctx *cli.Context) error {
 			log.Debug("Received signal to toggle logging")
 			if glog.GetVerbosity() == 0 {
 				glog.SetVerbosity(2)
+				logri.SetLevel(logrus.DebugLevel)
 			} else {
 				glog.SetVerbosity(0)
+				logri.SetLevel(logrus.InfoLevel)
 			}
 			log.WithFields(logrus.Fields{
 				"level": glog.GetVerbosity(),
-			}).Info("Changed glog logging level")
+			}).Info("Changed logging level")
 		}
 	}()
 	return nil
@@ -448,6 +615,11 @@ func setIsvcsEnv(ctx *cli.Context) error {
 			return err
 		}
 	}
+	for _, val := range ctx.GlobalStringSlice("isvcs-resource") {
+		if err := isvcs.AddResource(val); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
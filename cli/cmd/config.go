@@ -27,6 +27,66 @@ func (c *ServicedCli) initConfig() {
 		Usage:       "Reports on serviced configuration",
 		Description: "serviced config",
 		Action:      c.cmdConfig,
+		Subcommands: []cli.Command{
+			{
+				Name:        "view",
+				Usage:       "Administers saved views of the status and list commands",
+				Description: "",
+				Subcommands: []cli.Command{
+					{
+						Name:        "list",
+						Usage:       "Lists the saved views",
+						Description: "serviced config view list",
+						Action:      c.cmdConfigViewList,
+					}, {
+						Name:        "save",
+						Usage:       "Saves the fields, selector and layout of a view",
+						Description: "serviced config view save NAME [--show-fields FIELDS] [--selector SELECTOR] [--flat]",
+						Action:      c.cmdConfigViewSave,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "show-fields",
+								Value: "",
+								Usage: "Comma-delimited list describing which fields to display",
+							},
+							selectorFlag(),
+							cli.BoolFlag{
+								Name:  "flat",
+								Usage: "Print the services as a flat list instead of a tree",
+							},
+						},
+					}, {
+						Name:        "remove",
+						ShortName:   "rm",
+						Usage:       "Removes saved views",
+						Description: "serviced config view remove NAME ...",
+						Action:      c.cmdConfigViewRemove,
+					},
+				},
+			}, {
+				Name:        "generate-systemd",
+				Usage:       "Generates the systemd drop-in and environment file for this host from the configuration",
+				Description: "serviced config generate-systemd [--role ROLE] [--env-file PATH] [--output DIR]",
+				Action:      c.cmdConfigGenerateSystemd,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "role",
+						Value: "",
+						Usage: "Role of the host, master or delegate (default: from SERVICED_MASTER)",
+					},
+					cli.StringFlag{
+						Name:  "env-file",
+						Value: systemdEnvFile,
+						Usage: "Path of the environment file loaded by the drop-in",
+					},
+					cli.StringFlag{
+						Name:  "output",
+						Value: "",
+						Usage: "Write the files below this directory instead of printing them",
+					},
+				},
+			},
+		},
 	})
 }
 
@@ -0,0 +1,91 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/codegangsta/cli"
+)
+
+// Initializer for serviced db subcommands
+func (c *ServicedCli) initDB() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "db",
+		Usage:       "Administers the control center datastore",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "migrations",
+				Usage:       "Manages the versioned migrations of the datastore",
+				Description: "",
+				Subcommands: []cli.Command{
+					{
+						Name:        "status",
+						Usage:       "Lists the datastore migrations and whether they have been applied",
+						Description: "serviced db migrations status",
+						Action:      c.cmdDBMigrationsStatus,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "verbose, v",
+								Usage: "Show JSON format",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// serviced db migrations status [--verbose]
+func (c *ServicedCli) cmdDBMigrationsStatus(ctx *cli.Context) {
+	statuses, err := c.driver.GetDatastoreMigrations()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(statuses) == 0 {
+		fmt.Fprintln(os.Stderr, "no migrations found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonStatuses, err := c.marshalOutput(statuses); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal migrations: %s", err)
+		} else {
+			fmt.Println(string(jsonStatuses))
+		}
+		return
+	}
+
+	t := NewTable("Version,Description,Kinds,Status,Applied")
+	t.Padding = 4
+	for _, s := range statuses {
+		status, applied := "pending", ""
+		if s.Applied {
+			status = "applied"
+			applied = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		t.AddRow(map[string]interface{}{
+			"Version":     s.Version,
+			"Description": s.Description,
+			"Kinds":       strings.Join(s.Kinds, ","),
+			"Status":      status,
+			"Applied":     applied,
+		})
+	}
+	t.Print()
+}
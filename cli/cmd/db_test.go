@@ -0,0 +1,93 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/dbmigration"
+	"github.com/control-center/serviced/utils"
+)
+
+var DefaultDatastoreMigrations = []dbmigration.Status{
+	{
+		Version:     1,
+		Description: "Baseline datastore schema",
+		Kinds:       []string{"host", "pool", "service", "servicetemplate"},
+		Applied:     true,
+		AppliedAt:   time.Date(2016, 8, 1, 12, 0, 0, 0, time.Local),
+	}, {
+		Version:     2,
+		Description: "Add service tags",
+		Kinds:       []string{"service"},
+	},
+}
+
+type DBAPITest struct {
+	api.API
+	statuses []dbmigration.Status
+	err      error
+}
+
+func InitDBAPITest(t DBAPITest, args ...string) {
+	New(t, utils.TestConfigReader(map[string]string{})).Run(args)
+}
+
+func (t DBAPITest) GetDatastoreMigrations() ([]dbmigration.Status, error) {
+	return t.statuses, t.err
+}
+
+func ExampleServicedCLI_CmdDBMigrationsStatus() {
+	InitDBAPITest(DBAPITest{statuses: DefaultDatastoreMigrations}, "serviced", "db", "migrations", "status")
+
+	// Output:
+	// Version    Description                  Kinds                                Status     Applied
+	// 1          Baseline datastore schema    host,pool,service,servicetemplate    applied    2016-08-01 12:00:00
+	// 2          Add service tags             service                              pending
+}
+
+func ExampleServicedCLI_CmdDBMigrationsStatus_verbose() {
+	statuses := []dbmigration.Status{DefaultDatastoreMigrations[1]}
+	InitDBAPITest(DBAPITest{statuses: statuses}, "serviced", "db", "migrations", "status", "--verbose")
+
+	// Output:
+	// [
+	//    {
+	//      "Version": 2,
+	//      "Description": "Add service tags",
+	//      "Kinds": [
+	//        "service"
+	//      ],
+	//      "Applied": false,
+	//      "AppliedAt": "0001-01-01T00:00:00Z"
+	//    }
+	//  ]
+}
+
+func ExampleServicedCLI_CmdDBMigrationsStatus_err() {
+	pipeStderr(func(args ...string) {
+		InitDBAPITest(DBAPITest{err: errors.New("connection refused")}, args...)
+	}, "serviced", "db", "migrations", "status")
+	pipeStderr(func(args ...string) {
+		InitDBAPITest(DBAPITest{}, args...)
+	}, "serviced", "db", "migrations", "status")
+
+	// Output:
+	// connection refused
+	// no migrations found
+}
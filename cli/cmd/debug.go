@@ -0,0 +1,177 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/codegangsta/cli"
+)
+
+// Initializer for serviced debug subcommands
+func (c *ServicedCli) initDebug() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "debug",
+		Usage:       "Tools for testing the resilience of deployed applications",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "chaos",
+				Usage:       "Kills random service instances to test health checks and rescheduling",
+				Description: "Chaos mode must be allowed by starting the master with --allow-chaos",
+				Subcommands: []cli.Command{
+					{
+						Name:        "enable",
+						Usage:       "Starts killing a random non-critical instance in a pool at every interval",
+						Description: "serviced debug chaos enable --pool POOLID [--interval DURATION]",
+						Action:      c.cmdChaosEnable,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "pool",
+								Value: "",
+								Usage: "Resource pool whose instances are killed",
+							},
+							cli.StringFlag{
+								Name:  "interval",
+								Value: "10m",
+								Usage: "Time between kills (e.g. 30s, 10m, 1h)",
+							},
+						},
+					}, {
+						Name:        "disable",
+						Usage:       "Stops killing instances in a pool",
+						Description: "serviced debug chaos disable --pool POOLID",
+						Action:      c.cmdChaosDisable,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "pool",
+								Value: "",
+								Usage: "Resource pool whose instances are killed",
+							},
+						},
+					}, {
+						Name:        "status",
+						Usage:       "Shows the instances killed in a pool and their recovery times",
+						Description: "serviced debug chaos status --pool POOLID",
+						Action:      c.cmdChaosStatus,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "pool",
+								Value: "",
+								Usage: "Resource pool whose instances are killed",
+							},
+							cli.BoolFlag{
+								Name:  "verbose, v",
+								Usage: "Show JSON format",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// serviced debug chaos enable --pool POOLID [--interval DURATION]
+func (c *ServicedCli) cmdChaosEnable(ctx *cli.Context) {
+	poolID := ctx.String("pool")
+	if poolID == "" {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "enable")
+		return
+	}
+
+	interval, err := time.ParseDuration(ctx.String("interval"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid interval %s: %s\n", ctx.String("interval"), err)
+		return
+	}
+
+	if err := c.driver.EnableChaos(poolID, interval); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Printf("Chaos mode enabled on pool %s; killing an instance every %s\n", poolID, interval)
+}
+
+// serviced debug chaos disable --pool POOLID
+func (c *ServicedCli) cmdChaosDisable(ctx *cli.Context) {
+	poolID := ctx.String("pool")
+	if poolID == "" {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "disable")
+		return
+	}
+
+	if err := c.driver.DisableChaos(poolID); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Printf("Chaos mode disabled on pool %s\n", poolID)
+}
+
+// serviced debug chaos status --pool POOLID
+func (c *ServicedCli) cmdChaosStatus(ctx *cli.Context) {
+	poolID := ctx.String("pool")
+	if poolID == "" {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "status")
+		return
+	}
+
+	status, err := c.driver.GetChaosStatus(poolID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonStatus, err := c.marshalOutput(status); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal chaos status: %s", err)
+		} else {
+			fmt.Println(string(jsonStatus))
+		}
+		return
+	}
+
+	if status.Enabled {
+		fmt.Printf("Chaos mode is enabled on pool %s; killing an instance every %s\n", status.PoolID, status.Interval)
+	} else {
+		fmt.Printf("Chaos mode is disabled on pool %s\n", status.PoolID)
+	}
+	if len(status.Kills) == 0 {
+		return
+	}
+
+	fmt.Println()
+	t := NewTable("Killed,ServiceID,Name,Instance,HostID,Recovery")
+	t.Padding = 6
+	for _, kill := range status.Kills {
+		recovery := "pending"
+		if kill.Recovered() {
+			recovery = kill.Recovery.String()
+		}
+		t.AddRow(map[string]interface{}{
+			"Killed":    kill.KilledAt.Format(time.RFC3339),
+			"ServiceID": kill.ServiceID,
+			"Name":      kill.ServiceName,
+			"Instance":  kill.InstanceID,
+			"HostID":    kill.HostID,
+			"Recovery":  recovery,
+		})
+	}
+	t.Print()
+}
@@ -0,0 +1,143 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/utils"
+)
+
+var ErrChaosNotAllowed = errors.New("chaos mode is not allowed; restart serviced with --allow-chaos")
+
+var chaosKilledAt = time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+
+var DefaultDebugAPITest = DebugAPITest{
+	statuses: map[string]service.ChaosStatus{
+		"test-pool": {
+			PoolID:   "test-pool",
+			Enabled:  true,
+			Interval: 10 * time.Minute,
+			Kills: []service.ChaosKill{
+				{
+					ServiceID:   "test-service-1",
+					ServiceName: "Zope",
+					InstanceID:  1,
+					HostID:      "test-host-1",
+					KilledAt:    chaosKilledAt,
+					RecoveredAt: chaosKilledAt.Add(42 * time.Second),
+					Recovery:    42 * time.Second,
+				}, {
+					ServiceID:   "test-service-2",
+					ServiceName: "zenhub",
+					InstanceID:  0,
+					HostID:      "test-host-2",
+					KilledAt:    chaosKilledAt.Add(10 * time.Minute),
+				},
+			},
+		},
+	},
+}
+
+type DebugAPITest struct {
+	api.API
+	statuses map[string]service.ChaosStatus
+}
+
+func InitDebugAPITest(args ...string) {
+	New(DefaultDebugAPITest, utils.TestConfigReader(make(map[string]string))).Run(args)
+}
+
+func (t DebugAPITest) EnableChaos(poolID string, interval time.Duration) error {
+	if _, ok := t.statuses[poolID]; !ok {
+		return ErrChaosNotAllowed
+	}
+	return nil
+}
+
+func (t DebugAPITest) DisableChaos(poolID string) error {
+	return nil
+}
+
+func (t DebugAPITest) GetChaosStatus(poolID string) (*service.ChaosStatus, error) {
+	status, ok := t.statuses[poolID]
+	if !ok {
+		return &service.ChaosStatus{PoolID: poolID}, nil
+	}
+	return &status, nil
+}
+
+func ExampleServicedCLI_CmdChaosEnable() {
+	InitDebugAPITest("serviced", "debug", "chaos", "enable", "--pool", "test-pool", "--interval", "30s")
+
+	// Output:
+	// Chaos mode enabled on pool test-pool; killing an instance every 30s
+}
+
+func ExampleServicedCLI_CmdChaosEnable_usage() {
+	InitDebugAPITest("serviced", "debug", "chaos", "enable")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    enable - Starts killing a random non-critical instance in a pool at every interval
+	//
+	// USAGE:
+	//    command enable [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced debug chaos enable --pool POOLID [--interval DURATION]
+	//
+	// OPTIONS:
+	//    --pool 		Resource pool whose instances are killed
+	//    --interval '10m'	Time between kills (e.g. 30s, 10m, 1h)
+}
+
+func ExampleServicedCLI_CmdChaosEnable_err() {
+	pipeStderr(InitDebugAPITest, "serviced", "debug", "chaos", "enable", "--pool", "test-pool-0")
+
+	// Output:
+	// chaos mode is not allowed; restart serviced with --allow-chaos
+}
+
+func ExampleServicedCLI_CmdChaosDisable() {
+	InitDebugAPITest("serviced", "debug", "chaos", "disable", "--pool", "test-pool")
+
+	// Output:
+	// Chaos mode disabled on pool test-pool
+}
+
+func ExampleServicedCLI_CmdChaosStatus() {
+	InitDebugAPITest("serviced", "debug", "chaos", "status", "--pool", "test-pool")
+
+	// Output:
+	// Chaos mode is enabled on pool test-pool; killing an instance every 10m0s
+	//
+	// Killed                    ServiceID           Name        Instance      HostID           Recovery
+	// 2016-06-01T12:00:00Z      test-service-1      Zope        1             test-host-1      42s
+	// 2016-06-01T12:10:00Z      test-service-2      zenhub      0             test-host-2      pending
+}
+
+func ExampleServicedCLI_CmdChaosStatus_disabled() {
+	InitDebugAPITest("serviced", "debug", "chaos", "status", "--pool", "test-pool-0")
+
+	// Output:
+	// Chaos mode is disabled on pool test-pool-0
+}
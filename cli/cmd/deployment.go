@@ -0,0 +1,98 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// Initializer for serviced deployment subcommands
+func (c *ServicedCli) initDeployment() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "deployment",
+		Usage:       "Reports on deployed applications",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "status",
+				Usage:       "Summarizes the health of the services in a deployment",
+				Description: "serviced deployment status DEPLOYMENTID",
+				Action:      c.cmdDeploymentStatus,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+				},
+			},
+		},
+	})
+}
+
+// serviced deployment status DEPLOYMENTID
+func (c *ServicedCli) cmdDeploymentStatus(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "status")
+		return
+	}
+
+	status, err := c.driver.GetDeploymentStatus(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonStatus, err := c.marshalOutput(status); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal deployment status: %s", err)
+		} else {
+			fmt.Println(string(jsonStatus))
+		}
+		return
+	}
+
+	fmt.Printf("Deployment:  %s\n", status.DeploymentID)
+	fmt.Printf("Services:    %d (%d running, %d stopped, %d paused)\n", status.Services, status.Running, status.Stopped, status.Paused)
+	fmt.Printf("Instances:   %d of %d scheduled\n", status.Instances, status.ExpectedInstances)
+	fmt.Printf("Issues:      %d failing health checks, %d unassigned endpoints, %d missing images, %d pending scheduling\n",
+		len(status.FailingHealthChecks), len(status.UnassignedEndpoints), len(status.MissingImages), len(status.PendingScheduling))
+	if status.Healthy() {
+		return
+	}
+
+	fmt.Println()
+	t := NewTable("Issue,ServiceID,Name,Detail")
+	t.Padding = 6
+	addIssues := func(kind string, issues []service.DeploymentIssue) {
+		for _, issue := range issues {
+			t.AddRow(map[string]interface{}{
+				"Issue":     kind,
+				"ServiceID": issue.ServiceID,
+				"Name":      issue.ServiceName,
+				"Detail":    issue.Detail,
+			})
+		}
+	}
+	addIssues("healthcheck", status.FailingHealthChecks)
+	addIssues("address", status.UnassignedEndpoints)
+	addIssues("image", status.MissingImages)
+	addIssues("scheduling", status.PendingScheduling)
+	t.Print()
+}
@@ -0,0 +1,147 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/utils"
+)
+
+var ErrNoDeploymentFound = errors.New("no services found for deployment")
+
+var DefaultDeploymentAPITest = DeploymentAPITest{
+	statuses: map[string]service.DeploymentStatus{
+		"healthy": {
+			DeploymentID:      "healthy",
+			Services:          2,
+			Running:           2,
+			Instances:         3,
+			ExpectedInstances: 3,
+		},
+		"unhealthy": {
+			DeploymentID:      "unhealthy",
+			Services:          4,
+			Running:           2,
+			Stopped:           1,
+			Paused:            1,
+			Instances:         1,
+			ExpectedInstances: 2,
+			FailingHealthChecks: []service.DeploymentIssue{
+				{ServiceID: "test-service-1", ServiceName: "Zope", Detail: "instance 0 failing health check answering"},
+			},
+			UnassignedEndpoints: []service.DeploymentIssue{
+				{ServiceID: "test-service-2", ServiceName: "zenping", Detail: "endpoint ping requires an address assignment on port 7"},
+			},
+			MissingImages: []service.DeploymentIssue{
+				{ServiceID: "test-service-3", ServiceName: "redis", Detail: "image zenoss/redis not found in the registry"},
+			},
+			PendingScheduling: []service.DeploymentIssue{
+				{ServiceID: "test-service-4", ServiceName: "zenhub", Detail: "0 of 1 instances scheduled"},
+			},
+		},
+	},
+}
+
+type DeploymentAPITest struct {
+	api.API
+	statuses map[string]service.DeploymentStatus
+}
+
+func InitDeploymentAPITest(args ...string) {
+	New(DefaultDeploymentAPITest, utils.TestConfigReader(make(map[string]string))).Run(args)
+}
+
+func (t DeploymentAPITest) GetDeploymentStatus(deploymentID string) (*service.DeploymentStatus, error) {
+	status, ok := t.statuses[deploymentID]
+	if !ok {
+		return nil, ErrNoDeploymentFound
+	}
+	return &status, nil
+}
+
+func ExampleServicedCLI_CmdDeploymentStatus() {
+	InitDeploymentAPITest("serviced", "deployment", "status", "healthy")
+
+	// Output:
+	// Deployment:  healthy
+	// Services:    2 (2 running, 0 stopped, 0 paused)
+	// Instances:   3 of 3 scheduled
+	// Issues:      0 failing health checks, 0 unassigned endpoints, 0 missing images, 0 pending scheduling
+}
+
+func ExampleServicedCLI_CmdDeploymentStatus_issues() {
+	InitDeploymentAPITest("serviced", "deployment", "status", "unhealthy")
+
+	// Output:
+	// Deployment:  unhealthy
+	// Services:    4 (2 running, 1 stopped, 1 paused)
+	// Instances:   1 of 2 scheduled
+	// Issues:      1 failing health checks, 1 unassigned endpoints, 1 missing images, 1 pending scheduling
+	//
+	// Issue            ServiceID           Name         Detail
+	// healthcheck      test-service-1      Zope         instance 0 failing health check answering
+	// address          test-service-2      zenping      endpoint ping requires an address assignment on port 7
+	// image            test-service-3      redis        image zenoss/redis not found in the registry
+	// scheduling       test-service-4      zenhub       0 of 1 instances scheduled
+}
+
+func ExampleServicedCLI_CmdDeploymentStatus_verbose() {
+	InitDeploymentAPITest("serviced", "deployment", "status", "--verbose", "healthy")
+
+	// Output:
+	// {
+	//    "DeploymentID": "healthy",
+	//    "Services": 2,
+	//    "Running": 2,
+	//    "Stopped": 0,
+	//    "Paused": 0,
+	//    "Instances": 3,
+	//    "ExpectedInstances": 3,
+	//    "FailingHealthChecks": null,
+	//    "UnassignedEndpoints": null,
+	//    "MissingImages": null,
+	//    "PendingScheduling": null
+	//  }
+}
+
+func ExampleServicedCLI_CmdDeploymentStatus_usage() {
+	InitDeploymentAPITest("serviced", "deployment", "status")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    status - Summarizes the health of the services in a deployment
+	//
+	// USAGE:
+	//    command status [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced deployment status DEPLOYMENTID
+	//
+	// OPTIONS:
+	//    --verbose, -v	Show JSON format
+}
+
+func ExampleServicedCLI_CmdDeploymentStatus_err() {
+	pipeStderr(InitDeploymentAPITest, "serviced", "deployment", "status", "test-deployment-0")
+
+	// Output:
+	// no services found for deployment
+}
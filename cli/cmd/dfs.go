@@ -0,0 +1,147 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/coordinator/storage"
+	"github.com/pivotal-golang/bytefmt"
+)
+
+// Initializer for serviced dfs subcommands
+func (c *ServicedCli) initDFS() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "dfs",
+		Usage:       "Administers the distributed filesystem",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "stats",
+				Usage:       "Shows the latency, throughput, and concurrency of dfs operations",
+				Description: "serviced dfs stats",
+				Action:      c.cmdDFSStats,
+			}, {
+				Name:        "status",
+				Usage:       "Shows whether the distributed filesystem is read-only for maintenance",
+				Description: "serviced dfs status",
+				Action:      c.cmdDFSStatus,
+			}, {
+				Name:        "freeze",
+				Usage:       "Makes the distributed filesystem read-only for maintenance",
+				Description: "serviced dfs freeze [--reason REASON]",
+				Action:      c.cmdDFSFreeze,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "reason",
+						Value: "",
+						Usage: "Reason for the maintenance window",
+					},
+				},
+			}, {
+				Name:        "thaw",
+				Usage:       "Makes the distributed filesystem writable after maintenance",
+				Description: "serviced dfs thaw",
+				Action:      c.cmdDFSThaw,
+			},
+		},
+	})
+}
+
+// serviced dfs stats
+func (c *ServicedCli) cmdDFSStats(ctx *cli.Context) {
+	stats, err := c.driver.GetDFSStats()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	t := NewTable("Operation,Count,Errors,Active,Mean,P95,Max,Throughput")
+	t.Padding = 6
+	for _, op := range stats.Operations {
+		throughput := "-"
+		if op.Throughput > 0 {
+			throughput = bytefmt.ByteSize(uint64(op.Throughput)) + "/s"
+		}
+		t.AddRow(map[string]interface{}{
+			"Operation":  op.Operation,
+			"Count":      op.Count,
+			"Errors":     op.Errors,
+			"Active":     op.Active,
+			"Mean":       op.Mean - op.Mean%time.Millisecond,
+			"P95":        op.P95 - op.P95%time.Millisecond,
+			"Max":        op.Max - op.Max%time.Millisecond,
+			"Throughput": throughput,
+		})
+	}
+	t.Print()
+	fmt.Printf("\nOperations waiting for the DFS lock: %d\n", stats.LockWaiting)
+
+	status, err := c.driver.GetDFSFreezeStatus()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if status.Frozen {
+		printDFSFreezeStatus(status)
+	}
+}
+
+// serviced dfs status
+func (c *ServicedCli) cmdDFSStatus(ctx *cli.Context) {
+	status, err := c.driver.GetDFSFreezeStatus()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	printDFSFreezeStatus(status)
+}
+
+// serviced dfs freeze [--reason REASON]
+func (c *ServicedCli) cmdDFSFreeze(ctx *cli.Context) {
+	if len(ctx.Args()) > 0 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "freeze")
+		return
+	}
+	if err := c.driver.FreezeDFS(ctx.String("reason")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println("The distributed filesystem is read-only; snapshots, commits, and restores are blocked until it is thawed")
+}
+
+// serviced dfs thaw
+func (c *ServicedCli) cmdDFSThaw(ctx *cli.Context) {
+	if err := c.driver.ThawDFS(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println("The distributed filesystem is writable")
+}
+
+// printDFSFreezeStatus prints whether the distributed filesystem is read-only
+func printDFSFreezeStatus(status *storage.FreezeStatus) {
+	if !status.Frozen {
+		fmt.Println("The distributed filesystem is writable")
+		return
+	}
+	fmt.Printf("The distributed filesystem is read-only for maintenance since %s\n", status.Since.Format(time.RFC3339))
+	if status.Reason != "" {
+		fmt.Printf("Reason: %s\n", status.Reason)
+	}
+}
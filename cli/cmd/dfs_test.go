@@ -0,0 +1,156 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/coordinator/storage"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/utils"
+)
+
+type DFSAPITest struct {
+	api.API
+	fail   bool
+	frozen bool
+}
+
+func InitDFSAPITest(args ...string) {
+	New(DFSAPITest{}, utils.TestConfigReader(map[string]string{})).Run(args)
+}
+
+func (t DFSAPITest) GetDFSStats() (*dfs.Stats, error) {
+	if t.fail {
+		return nil, errors.New("master is not running")
+	}
+	return &dfs.Stats{
+		Operations: []dfs.OperationStats{
+			{
+				Operation:  dfs.OpSnapshot,
+				Count:      12,
+				Mean:       3200*time.Millisecond + 412*time.Microsecond,
+				P95:        5 * time.Second,
+				Max:        6*time.Second + 100*time.Millisecond,
+				Active:     1,
+				Throughput: 0,
+			}, {
+				Operation:  dfs.OpBackup,
+				Count:      2,
+				Errors:     1,
+				Mean:       90 * time.Second,
+				P95:        2 * time.Minute,
+				Max:        2 * time.Minute,
+				Bytes:      10 * 1024 * 1024 * 1024,
+				Throughput: 50 * 1024 * 1024,
+			},
+		},
+		LockWaiting: 2,
+	}, nil
+}
+
+func (t DFSAPITest) GetDFSFreezeStatus() (*storage.FreezeStatus, error) {
+	if t.fail {
+		return nil, errors.New("master is not running")
+	}
+	if !t.frozen {
+		return &storage.FreezeStatus{}, nil
+	}
+	return &storage.FreezeStatus{
+		Frozen: true,
+		Reason: "san firmware upgrade",
+		Since:  time.Date(2016, 5, 4, 10, 30, 0, 0, time.UTC),
+	}, nil
+}
+
+func (t DFSAPITest) FreezeDFS(reason string) error {
+	if t.fail {
+		return errors.New("master is not running")
+	}
+	return nil
+}
+
+func (t DFSAPITest) ThawDFS() error {
+	if t.fail {
+		return errors.New("master is not running")
+	}
+	return nil
+}
+
+func ExampleServicedCLI_CmdDFSStats() {
+	InitDFSAPITest("serviced", "dfs", "stats")
+
+	// Output:
+	// Operation      Count      Errors      Active      Mean       P95       Max       Throughput
+	// snapshot       12         0           1           3.2s       5s        6.1s      -
+	// backup         2          1           0           1m30s      2m0s      2m0s      50M/s
+	//
+	// Operations waiting for the DFS lock: 2
+}
+
+func ExampleServicedCLI_CmdDFSStats_err() {
+	pipeStderr(func(args ...string) {
+		New(DFSAPITest{fail: true}, utils.TestConfigReader(map[string]string{})).Run(args)
+	}, "serviced", "dfs", "stats")
+
+	// Output:
+	// master is not running
+}
+
+func ExampleServicedCLI_CmdDFSStats_frozen() {
+	New(DFSAPITest{frozen: true}, utils.TestConfigReader(map[string]string{})).Run([]string{"serviced", "dfs", "stats"})
+
+	// Output:
+	// Operation      Count      Errors      Active      Mean       P95       Max       Throughput
+	// snapshot       12         0           1           3.2s       5s        6.1s      -
+	// backup         2          1           0           1m30s      2m0s      2m0s      50M/s
+	//
+	// Operations waiting for the DFS lock: 2
+	// The distributed filesystem is read-only for maintenance since 2016-05-04T10:30:00Z
+	// Reason: san firmware upgrade
+}
+
+func ExampleServicedCLI_CmdDFSStatus() {
+	InitDFSAPITest("serviced", "dfs", "status")
+
+	// Output:
+	// The distributed filesystem is writable
+}
+
+func ExampleServicedCLI_CmdDFSFreeze() {
+	InitDFSAPITest("serviced", "dfs", "freeze", "--reason", "san firmware upgrade")
+
+	// Output:
+	// The distributed filesystem is read-only; snapshots, commits, and restores are blocked until it is thawed
+}
+
+func ExampleServicedCLI_CmdDFSFreeze_err() {
+	pipeStderr(func(args ...string) {
+		New(DFSAPITest{fail: true}, utils.TestConfigReader(map[string]string{})).Run(args)
+	}, "serviced", "dfs", "freeze")
+
+	// Output:
+	// master is not running
+}
+
+func ExampleServicedCLI_CmdDFSThaw() {
+	InitDFSAPITest("serviced", "dfs", "thaw")
+
+	// Output:
+	// The distributed filesystem is writable
+}
@@ -16,6 +16,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/codegangsta/cli"
@@ -49,11 +52,59 @@ func (c *ServicedCli) initDocker() {
 						Value: "",
 						Usage: "host:port where the registry is running",
 					},
+					cli.StringFlag{
+						Name:  "from",
+						Value: "",
+						Usage: "version of the local registry to migrate from (e.g. v1); resumes an interrupted migration",
+					},
+					cli.StringFlag{
+						Name:  "to",
+						Value: "",
+						Usage: "version of the local registry to migrate to (e.g. v2)",
+					},
 					cli.BoolFlag{
 						Name:  "override, f",
 						Usage: "overrides all existing image records",
 					},
 				},
+			}, {
+				Name:        "images",
+				Usage:       "Lists the images in the docker registry index",
+				Description: "serviced docker images [--tenant TENANTID]",
+				Action:      c.cmdDockerImages,
+				Flags: append([]cli.Flag{
+					cli.StringFlag{
+						Name:  "tenant",
+						Value: "",
+						Usage: "Only list the images of this tenant",
+					},
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "Image,UUID,Hash,Services,Pushed",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				}, tableFlags()...),
+			}, {
+				Name:        "inspect-image",
+				Usage:       "Displays an image in the docker registry index",
+				Description: "serviced docker inspect-image IMAGE",
+				Action:      c.cmdDockerInspectImage,
+			}, {
+				Name:        "promote",
+				Usage:       "Copies an image in the registry to another tenant",
+				Description: "serviced docker promote [--tag TAG] IMAGE TENANTID",
+				Action:      c.cmdDockerPromote,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "tag",
+						Value: "",
+						Usage: "Tag for the promoted image (defaults to the tag of IMAGE)",
+					},
+				},
 			}, {
 				Name:        "override",
 				Usage:       "Replace an image in the registry with a new image",
@@ -79,10 +130,35 @@ func (c *ServicedCli) cmdResetRegistry(ctx *cli.Context) {
 	}
 }
 
-// serviced migrate-registry
+// serviced migrate-registry [--registry HOST:PORT | --from VERSION --to VERSION]
 func (c *ServicedCli) cmdMigrateRegistry(ctx *cli.Context) {
 	endpoint := ctx.String("registry")
 	override := ctx.Bool("override")
+	from, to := ctx.String("from"), ctx.String("to")
+	if from != "" || to != "" {
+		if endpoint != "" {
+			fmt.Fprintln(os.Stderr, "--registry cannot be used with --from or --to")
+			return
+		}
+		fromVersion, err := parseRegistryVersion(from)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		toVersion, err := parseRegistryVersion(to)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		if err := c.driver.MigrateRegistry(fromVersion, toVersion, override); err != nil {
+			log.WithFields(logrus.Fields{
+				"from":     from,
+				"to":       to,
+				"override": override,
+			}).WithError(err).Fatal("Unable to migrate local Docker registry")
+		}
+		return
+	}
 	if err := c.driver.UpgradeRegistry(endpoint, override); err != nil {
 		log.WithFields(logrus.Fields{
 			"registry": endpoint,
@@ -91,6 +167,109 @@ func (c *ServicedCli) cmdMigrateRegistry(ctx *cli.Context) {
 	}
 }
 
+// parseRegistryVersion converts a registry version such as v2 to its number.
+// An empty version is 0.
+func parseRegistryVersion(version string) (int, error) {
+	if version == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(version), "v"))
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("invalid registry version %s", version)
+	}
+	return v, nil
+}
+
+// serviced docker images [--tenant TENANTID]
+func (c *ServicedCli) cmdDockerImages(ctx *cli.Context) {
+	images, err := c.driver.ListRegistryImages(ctx.String("tenant"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(images) == 0 {
+		fmt.Fprintln(os.Stderr, "no images found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonImages, err := c.marshalOutput(images); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal image list: %s", err)
+		} else {
+			fmt.Println(string(jsonImages))
+		}
+		return
+	}
+
+	t, err := newTableFromContext(ctx, ctx.String("show-fields"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	for _, image := range images {
+		pushed := "unknown"
+		if !image.PushedAt.IsZero() {
+			pushed = image.PushedAt.Local().Format(time.RFC3339)
+		}
+		t.AddRow(map[string]interface{}{
+			"Image":    image.Image.String(),
+			"Library":  image.Library,
+			"Repo":     image.Repo,
+			"Tag":      image.Tag,
+			"UUID":     shortID(image.UUID),
+			"Hash":     shortID(image.Hash),
+			"Services": len(image.Services),
+			"Pushed":   pushed,
+		})
+	}
+	t.Padding = 4
+	t.Print()
+}
+
+// serviced docker inspect-image IMAGE
+func (c *ServicedCli) cmdDockerInspectImage(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "inspect-image")
+		return
+	}
+
+	image, err := c.driver.InspectRegistryImage(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if jsonImage, err := c.marshalOutput(image); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to marshal image: %s", err)
+	} else {
+		fmt.Println(string(jsonImage))
+	}
+}
+
+// serviced docker promote [--tag TAG] IMAGE TENANTID
+func (c *ServicedCli) cmdDockerPromote(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "promote")
+		return
+	}
+
+	if image, err := c.driver.PromoteImage(args[0], args[1], ctx.String("tag")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else {
+		fmt.Println(image)
+	}
+}
+
+// shortID truncates a docker image id or hash for display
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
 // serviced docker override NEWIMAGE OLDIMAGE
 func (c *ServicedCli) cmdDockerOverride(ctx *cli.Context) {
 	args := ctx.Args()
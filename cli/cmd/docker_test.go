@@ -17,8 +17,11 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/registry"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -28,8 +31,32 @@ const (
 
 var DefaultDockerAPITest = DockerAPITest{}
 
+var DefaultTestImages = []registry.ImageDetails{
+	{
+		Image: registry.Image{
+			Library:  "tenant1",
+			Repo:     "repo",
+			Tag:      "latest",
+			UUID:     "0123456789abcdef",
+			Hash:     "abcdef0123456789",
+			PushedAt: time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+		Services: []string{"svc1", "svc2"},
+	}, {
+		Image: registry.Image{
+			Library: "tenant2",
+			Repo:    "other",
+			Tag:     "1.0",
+			UUID:    "fedcba9876543210",
+			Hash:    "fedcba9876543210",
+		},
+	},
+}
+
 var (
 	ErrOverrideFailed = errors.New("override failed")
+	ErrNoImageFound   = errors.New("no image found")
+	ErrNoTenantFound  = errors.New("tenant not found")
 )
 
 type DockerAPITest struct {
@@ -49,6 +76,147 @@ func (t DockerAPITest) DockerOverride(newImage, oldImage string) error {
 	}
 }
 
+func (t DockerAPITest) MigrateRegistry(fromVersion, toVersion int, override bool) error {
+	fmt.Printf("migrate v%d to v%d (override=%t)\n", fromVersion, toVersion, override)
+	return nil
+}
+
+func (t DockerAPITest) ListRegistryImages(tenantID string) ([]registry.ImageDetails, error) {
+	var images []registry.ImageDetails
+	for _, image := range DefaultTestImages {
+		if tenantID == "" || image.Library == tenantID {
+			images = append(images, image)
+		}
+	}
+	return images, nil
+}
+
+func (t DockerAPITest) InspectRegistryImage(image string) (*registry.ImageDetails, error) {
+	for _, details := range DefaultTestImages {
+		if details.Image.String() == image {
+			return &details, nil
+		}
+	}
+	return nil, ErrNoImageFound
+}
+
+func ExampleServicedCli_cmdDockerImages() {
+	InitDockerAPITest("serviced", "docker", "images", "--show-fields", "Image,UUID,Hash,Services")
+
+	// Output:
+	// Image                  UUID            Hash            Services
+	// tenant1/repo:latest    0123456789ab    abcdef012345    2
+	// tenant2/other:1.0      fedcba987654    fedcba987654    0
+}
+
+func ExampleServicedCli_cmdDockerImages_tenant() {
+	InitDockerAPITest("serviced", "docker", "images", "--tenant", "tenant2", "--show-fields", "Image,Services")
+	pipeStderr(InitDockerAPITest, "serviced", "docker", "images", "--tenant", "tenant3")
+
+	// Output:
+	// Image                Services
+	// tenant2/other:1.0    0
+	// no images found
+}
+
+func ExampleServicedCli_cmdDockerInspectImage() {
+	InitDockerAPITest("serviced", "docker", "inspect-image", "tenant1/repo:latest")
+	pipeStderr(InitDockerAPITest, "serviced", "docker", "inspect-image", "tenant1/repo:2.0")
+
+	// Output:
+	// {
+	//    "Library": "tenant1",
+	//    "Repo": "repo",
+	//    "Tag": "latest",
+	//    "UUID": "0123456789abcdef",
+	//    "Hash": "abcdef0123456789",
+	//    "PushedAt": "2016-06-01T12:00:00Z",
+	//    "Services": [
+	//      "svc1",
+	//      "svc2"
+	//    ]
+	//  }
+	// no image found
+}
+
+func ExampleServicedCLI_CmdDockerInspectImage_usage() {
+	InitDockerAPITest("serviced", "docker", "inspect-image")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    inspect-image - Displays an image in the docker registry index
+	//
+	// USAGE:
+	//    command inspect-image [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced docker inspect-image IMAGE
+	//
+	// OPTIONS:
+}
+
+func (t DockerAPITest) PromoteImage(image, tenantID, tag string) (string, error) {
+	if tenantID == "missing" {
+		return "", ErrNoTenantFound
+	}
+	if tag == "" {
+		tag = "latest"
+	}
+	return fmt.Sprintf("%s/repo:%s", tenantID, tag), nil
+}
+
+func ExampleServicedCli_cmdDockerPromote() {
+	InitDockerAPITest("serviced", "docker", "promote", "staging/repo:latest", "production")
+	InitDockerAPITest("serviced", "docker", "promote", "--tag", "tested", "staging/repo:latest", "staging")
+	pipeStderr(InitDockerAPITest, "serviced", "docker", "promote", "staging/repo:latest", "missing")
+
+	// Output:
+	// production/repo:latest
+	// staging/repo:tested
+	// tenant not found
+}
+
+func ExampleServicedCLI_CmdDockerPromote_usage() {
+	InitDockerAPITest("serviced", "docker", "promote", "staging/repo:latest")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    promote - Copies an image in the registry to another tenant
+	//
+	// USAGE:
+	//    command promote [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced docker promote [--tag TAG] IMAGE TENANTID
+	//
+	// OPTIONS:
+	//    --tag 	Tag for the promoted image (defaults to the tag of IMAGE)
+}
+
+func ExampleServicedCli_cmdMigrateRegistry() {
+	InitDockerAPITest("serviced", "docker", "migrate-registry", "--from", "v1", "--to", "v2")
+	InitDockerAPITest("serviced", "docker", "migrate-registry", "--from", "1", "-f")
+	InitDockerAPITest("serviced", "docker", "migrate-registry", "--to", "V2")
+
+	// Output:
+	// migrate v1 to v2 (override=false)
+	// migrate v1 to v0 (override=true)
+	// migrate v0 to v2 (override=false)
+}
+
+func ExampleServicedCli_cmdMigrateRegistry_fail() {
+	pipeStderr(InitDockerAPITest, "serviced", "docker", "migrate-registry", "--from", "latest")
+	pipeStderr(InitDockerAPITest, "serviced", "docker", "migrate-registry", "--from", "v1", "--registry", "host:5000")
+
+	// Output:
+	// invalid registry version latest
+	// --registry cannot be used with --from or --to
+}
+
 func ExampleServicedCLI_CmdDockerOverride_usage() {
 	InitDockerAPITest("serviced", "docker", "override")
 
@@ -0,0 +1,48 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"github.com/control-center/serviced/cli/api/fake"
+	"github.com/control-center/serviced/utils"
+)
+
+// InitFakeAPITest runs a sequence of commands against the same in-memory
+// driver
+func InitFakeAPITest(commands ...[]string) {
+	driver := fake.New()
+	for _, args := range commands {
+		New(driver, utils.TestConfigReader(make(map[string]string))).Run(args)
+	}
+}
+
+func ExampleServicedCLI_fakeDriver() {
+	InitFakeAPITest(
+		[]string{"serviced", "host", "list", "--show-fields", "ID,Pool,Addr"},
+		[]string{"serviced", "service", "stop", "mockapp"},
+		[]string{"serviced", "deployment", "status", "mock"},
+	)
+
+	// Output:
+	// ID               Pool         Addr
+	// mock-host-1      default      10.87.110.1
+	// mock-host-2      default      10.87.110.2
+	// Scheduled 5 service(s) to stop
+	// Deployment:  mock
+	// Services:    5 (0 running, 5 stopped, 0 paused)
+	// Instances:   0 of 0 scheduled
+	// Issues:      0 failing health checks, 0 unassigned endpoints, 0 missing images, 0 pending scheduling
+}
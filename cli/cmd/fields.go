@@ -0,0 +1,163 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+var (
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// templateField is a path that a go template can reference on a value, e.g.
+// .InstanceLimits.Min
+type templateField struct {
+	Path string
+	Type string
+}
+
+// templateFields returns the fields, nested fields, and computed fields
+// (methods without arguments) that a go template can reference on a value of
+// the given type.  Elements of slices are shown as [] and values of maps as
+// [KEY]; templates reach them with index or range.
+func templateFields(t reflect.Type) []templateField {
+	var fields []templateField
+	found := make(map[string]bool)
+	add := func(path string, t reflect.Type) {
+		if !found[path] {
+			found[path] = true
+			fields = append(fields, templateField{Path: path, Type: t.String()})
+		}
+	}
+
+	visiting := make(map[reflect.Type]bool)
+	var walk func(prefix string, t reflect.Type)
+	walk = func(prefix string, t reflect.Type) {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || visiting[t] {
+			return
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Anonymous {
+				// promoted fields are referenced without the embedded name
+				walk(prefix, field.Type)
+				continue
+			} else if field.PkgPath != "" {
+				continue
+			}
+			path := prefix + "." + field.Name
+			add(path, field.Type)
+
+			elem := field.Type
+			for elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+			switch elem.Kind() {
+			case reflect.Slice, reflect.Array:
+				path, elem = path+"[]", elem.Elem()
+			case reflect.Map:
+				path, elem = path+"[KEY]", elem.Elem()
+			}
+			if !isTemplateLeaf(elem) {
+				walk(path, elem)
+			}
+		}
+
+		// templates execute against pointers, so pointer methods may be called
+		ptr := reflect.PtrTo(t)
+		for i := 0; i < ptr.NumMethod(); i++ {
+			method := ptr.Method(i)
+			if out, ok := templateMethodResult(method.Type); ok {
+				add(prefix+"."+method.Name, out)
+			}
+		}
+	}
+	walk("", t)
+	return fields
+}
+
+// isTemplateLeaf returns true if the fields of the type are not worth
+// listing, because it is not a struct or it formats itself
+func isTemplateLeaf(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+	for _, iface := range []reflect.Type{stringerType, textMarshalerType, jsonMarshalerType} {
+		if t.Implements(iface) || reflect.PtrTo(t).Implements(iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// templateMethodResult returns the result type of a method that a template
+// can call, which takes no arguments besides its receiver and returns a
+// value and optionally an error.
+func templateMethodResult(t reflect.Type) (reflect.Type, bool) {
+	if t.NumIn() != 1 {
+		return nil, false
+	}
+	switch t.NumOut() {
+	case 1:
+		return t.Out(0), t.Out(0) != errorType
+	case 2:
+		return t.Out(0), t.Out(1) == errorType
+	}
+	return nil, false
+}
+
+// commandFields are all of the fields that the --show-fields flag of a
+// command accepts
+type commandFields struct {
+	Command string
+	Fields  string
+}
+
+// printFields prints the fields accepted by --show-fields, which --add-column
+// templates may also reference, followed by the fields that --format
+// templates can reference on the value.
+func printFields(commands []commandFields, value interface{}) {
+	for _, command := range commands {
+		fmt.Printf("%s --show-fields:\n", command.Command)
+		fmt.Printf("    %s\n\n", command.Fields)
+	}
+
+	t := reflect.TypeOf(value)
+	fmt.Printf("--format templates (%s):\n", t)
+	table := NewTable("Field,Type")
+	table.Padding = 6
+	for _, field := range templateFields(t) {
+		table.AddRow(map[string]interface{}{
+			"Field": field.Path,
+			"Type":  field.Type,
+		})
+	}
+	table.Print()
+}
@@ -0,0 +1,84 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fieldsTestBase struct {
+	Version int
+}
+
+type fieldsTestChild struct {
+	Name   string
+	Parent *fieldsTestChild
+}
+
+type fieldsTestValue struct {
+	fieldsTestBase
+	ID       string
+	Children []fieldsTestChild
+	Labels   map[string]fieldsTestChild
+	Created  time.Time
+	hidden   string
+}
+
+func (v *fieldsTestValue) Title() string          { return v.ID }
+func (v fieldsTestValue) Count() (int, error)     { return len(v.Children), nil }
+func (v fieldsTestValue) Validate() error         { return nil }
+func (v fieldsTestValue) Lookup(name string) bool { return false }
+
+func TestTemplateFields(t *testing.T) {
+	expected := []templateField{
+		{".Version", "int"},
+		{".ID", "string"},
+		{".Children", "[]cmd.fieldsTestChild"},
+		{".Children[].Name", "string"},
+		{".Children[].Parent", "*cmd.fieldsTestChild"},
+		{".Labels", "map[string]cmd.fieldsTestChild"},
+		{".Labels[KEY].Name", "string"},
+		{".Labels[KEY].Parent", "*cmd.fieldsTestChild"},
+		{".Created", "time.Time"},
+		{".Count", "int"},
+		{".Title", "string"},
+	}
+	if actual := templateFields(reflect.TypeOf(fieldsTestValue{})); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected fields %v, got %v", expected, actual)
+	}
+}
+
+func TestServicedCLI_CmdServiceFields(t *testing.T) {
+	output := string(pipe(InitServiceAPITest, "serviced", "service", "fields"))
+	for _, line := range []string{
+		"serviced service list --show-fields:",
+		"    " + serviceListFields,
+		"serviced service status --show-fields:",
+		"--format templates (service.Service):",
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("expected line %q in output:\n%s", line, output)
+		}
+	}
+	for _, field := range []string{".InstanceLimits.Min", ".Endpoints[].Application", ".Summary"} {
+		if !strings.Contains(output, "\n"+field+" ") {
+			t.Errorf("expected field %s in output:\n%s", field, output)
+		}
+	}
+}
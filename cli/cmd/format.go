@@ -0,0 +1,277 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/utils"
+	"github.com/pivotal-golang/bytefmt"
+)
+
+// formatFunction is a function that --format and --add-column templates can
+// call
+type formatFunction struct {
+	Name        string
+	Usage       string
+	Description string
+	Func        interface{}
+}
+
+// formatFunctions are the functions available to every command that accepts
+// templates.  Functions that take a list take it last, so that it can be
+// piped in, e.g. {{.Endpoints | where "Purpose" "import" | pluck "Application" | join ","}}
+var formatFunctions = []formatFunction{
+	{"json", "json VALUE", "Formats a value as JSON", formatJSON},
+	{"humanizeBytes", "humanizeBytes BYTES", "Formats a number of bytes with a unit, e.g. 1.5G", formatHumanizeBytes},
+	{"timeAgo", "timeAgo TIME", "Formats the time elapsed since a time, e.g. 3h ago", formatTimeAgo},
+	{"upper", "upper STRING", "Converts a string to upper case", strings.ToUpper},
+	{"lower", "lower STRING", "Converts a string to lower case", strings.ToLower},
+	{"join", "join SEP LIST", "Joins the elements of a list with a separator", formatJoin},
+	{"where", "where FIELD VALUE LIST", "Selects the elements of a list whose field, e.g. Purpose or AddressConfig.Port, has the value", formatWhere},
+	{"pluck", "pluck FIELD LIST", "Lists the value of a field of each element of a list", formatPluck},
+}
+
+// parseFormat parses a --format or --add-column template with the format
+// functions
+func parseFormat(name, text string) (*template.Template, error) {
+	funcs := make(template.FuncMap)
+	for _, f := range formatFunctions {
+		funcs[f.Name] = f.Func
+	}
+	return template.New(name).Funcs(funcs).Parse(text)
+}
+
+// Initializer for serviced format-functions
+func (c *ServicedCli) initFormat() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "format-functions",
+		Usage:       "Lists the functions available in --format and --add-column templates",
+		Description: strings.Join(formatFunctionsHelp(), "\n   "),
+		Action:      c.cmdFormatFunctions,
+	})
+}
+
+// serviced format-functions
+func (c *ServicedCli) cmdFormatFunctions(ctx *cli.Context) {
+	fmt.Println(strings.Join(formatFunctionsHelp(), "\n"))
+}
+
+// formatFunctionsHelp returns the lines that document the format functions
+func formatFunctionsHelp() []string {
+	width := 0
+	for _, f := range formatFunctions {
+		if len(f.Usage) > width {
+			width = len(f.Usage)
+		}
+	}
+	lines := []string{`Functions available in --format and --add-column templates, e.g. '{{.Tags | join ","}}':`, ""}
+	for _, f := range formatFunctions {
+		lines = append(lines, fmt.Sprintf("%-*s   %s", width, f.Usage, f.Description))
+	}
+	return lines
+}
+
+func formatJSON(value interface{}) (string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func formatHumanizeBytes(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case utils.EngNotation:
+		return bytefmt.ByteSize(v.Value), nil
+	case *utils.EngNotation:
+		return bytefmt.ByteSize(v.Value), nil
+	case string:
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("humanizeBytes: %q is not a number of bytes", v)
+		}
+		return bytefmt.ByteSize(n), nil
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(value))
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() >= 0 {
+			return bytefmt.ByteSize(uint64(v.Int())), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return bytefmt.ByteSize(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		if v.Float() >= 0 {
+			return bytefmt.ByteSize(uint64(v.Float())), nil
+		}
+	}
+	return "", fmt.Errorf("humanizeBytes: %v is not a number of bytes", value)
+}
+
+func formatTimeAgo(value interface{}) (string, error) {
+	var t time.Time
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		if v != nil {
+			t = *v
+		}
+	default:
+		return "", fmt.Errorf("timeAgo: %v is not a time", value)
+	}
+	if t.IsZero() {
+		return "--", nil
+	}
+
+	d := time.Since(t)
+	suffix := " ago"
+	if d < 0 {
+		d, suffix = -d, " from now"
+	}
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd%s", d/(24*time.Hour), suffix), nil
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%s", d/time.Hour, suffix), nil
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm%s", d/time.Minute, suffix), nil
+	}
+	return fmt.Sprintf("%ds%s", d/time.Second, suffix), nil
+}
+
+func formatJoin(sep string, list interface{}) (string, error) {
+	items, err := formatList("join", list)
+	if err != nil {
+		return "", err
+	}
+	values := make([]string, len(items))
+	for i, item := range items {
+		values[i] = fmt.Sprintf("%v", item.Interface())
+	}
+	return strings.Join(values, sep), nil
+}
+
+func formatWhere(field string, value interface{}, list interface{}) ([]interface{}, error) {
+	items, err := formatList("where", list)
+	if err != nil {
+		return nil, err
+	}
+	want := fmt.Sprintf("%v", value)
+	selected := []interface{}{}
+	for _, item := range items {
+		if v, ok := formatField(item, field); ok && fmt.Sprintf("%v", v.Interface()) == want {
+			selected = append(selected, item.Interface())
+		}
+	}
+	return selected, nil
+}
+
+func formatPluck(field string, list interface{}) ([]interface{}, error) {
+	items, err := formatList("pluck", list)
+	if err != nil {
+		return nil, err
+	}
+	values := []interface{}{}
+	for _, item := range items {
+		v, ok := formatField(item, field)
+		if !ok {
+			return nil, fmt.Errorf("pluck: no field %s in %s", field, item.Type())
+		}
+		values = append(values, v.Interface())
+	}
+	return values, nil
+}
+
+// formatList returns the elements of a slice, an array, or the values of a
+// map ordered by key
+func formatList(name string, list interface{}) ([]reflect.Value, error) {
+	v := reflect.Indirect(reflect.ValueOf(list))
+	var items []reflect.Value
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, v.Index(i))
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sortValues(keys)
+		for _, key := range keys {
+			items = append(items, v.MapIndex(key))
+		}
+	case reflect.Invalid:
+	default:
+		return nil, fmt.Errorf("%s: %v is not a list", name, list)
+	}
+	return items, nil
+}
+
+// formatField returns the value of a field, or a dotted path of fields, of a
+// struct or map
+func formatField(item reflect.Value, field string) (reflect.Value, bool) {
+	for _, name := range strings.Split(field, ".") {
+		for item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface {
+			if item.IsNil() {
+				return reflect.Value{}, false
+			}
+			item = item.Elem()
+		}
+		switch item.Kind() {
+		case reflect.Struct:
+			item = item.FieldByName(name)
+		case reflect.Map:
+			if item.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, false
+			}
+			item = item.MapIndex(reflect.ValueOf(name).Convert(item.Type().Key()))
+		default:
+			return reflect.Value{}, false
+		}
+		if !item.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+	return item, item.CanInterface()
+}
+
+// sortValues sorts map keys by their formatted values
+func sortValues(values []reflect.Value) {
+	keys := make([]string, len(values))
+	for i, v := range values {
+		keys[i] = fmt.Sprintf("%v", v.Interface())
+	}
+	sort.Sort(byKey{keys, values})
+}
+
+type byKey struct {
+	keys   []string
+	values []reflect.Value
+}
+
+func (s byKey) Len() int           { return len(s.keys) }
+func (s byKey) Less(i, j int) bool { return s.keys[i] < s.keys[j] }
+func (s byKey) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+}
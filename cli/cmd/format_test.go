@@ -0,0 +1,101 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/utils"
+)
+
+type formatTestEndpoint struct {
+	Name    string
+	Purpose string
+	Config  map[string]string
+}
+
+func TestFormatFunctions(t *testing.T) {
+	endpoints := []formatTestEndpoint{
+		{Name: "web", Purpose: "export", Config: map[string]string{"Port": "80"}},
+		{Name: "db", Purpose: "import", Config: map[string]string{"Port": "5432"}},
+		{Name: "cache", Purpose: "import", Config: map[string]string{"Port": "6379"}},
+	}
+	data := map[string]interface{}{
+		"Name":      "Zope",
+		"RAM":       utils.NewEngNotation(1024 * 1024 * 1024),
+		"Memory":    uint64(512 * 1024 * 1024),
+		"Started":   time.Now().Add(-90 * time.Minute),
+		"Never":     time.Time{},
+		"Tags":      []string{"a", "b"},
+		"Labels":    map[string]int{"b": 2, "a": 1},
+		"Endpoints": endpoints,
+	}
+	for text, expected := range map[string]string{
+		`{{.Name | upper}} {{.Name | lower}}`:                  "ZOPE zope",
+		`{{.RAM | humanizeBytes}} {{.Memory | humanizeBytes}}`: "1G 512M",
+		`{{.Started | timeAgo}} {{.Never | timeAgo}}`:          "1h ago --",
+		`{{.Tags | join ","}} {{.Labels | join "+"}}`:          "a,b 1+2",
+		`{{.Tags | json}}`: `["a","b"]`,
+		`{{.Endpoints | where "Purpose" "import" | pluck "Name" | join ","}}`: "db,cache",
+		`{{.Endpoints | where "Config.Port" 80 | pluck "Name" | join ","}}`:   "web",
+		`{{.Endpoints | pluck "Config.Port" | join ","}}`:                     "80,5432,6379",
+		`{{len (.Endpoints | where "Purpose" "none")}}`:                       "0",
+	} {
+		tmpl, err := parseFormat("test", text)
+		if err != nil {
+			t.Errorf("could not parse %s: %s", text, err)
+			continue
+		}
+		buffer := &bytes.Buffer{}
+		if err := tmpl.Execute(buffer, data); err != nil {
+			t.Errorf("could not execute %s: %s", text, err)
+		} else if actual := buffer.String(); actual != expected {
+			t.Errorf("template %s: expected %q, got %q", text, expected, actual)
+		}
+	}
+
+	for _, text := range []string{
+		`{{.Name | humanizeBytes}}`,
+		`{{.Name | timeAgo}}`,
+		`{{.Name | join ","}}`,
+		`{{.Endpoints | pluck "Missing"}}`,
+	} {
+		tmpl, err := parseFormat("test", text)
+		if err != nil {
+			t.Errorf("could not parse %s: %s", text, err)
+		} else if err := tmpl.Execute(&bytes.Buffer{}, data); err == nil {
+			t.Errorf("expected template %s to fail", text)
+		}
+	}
+}
+
+func ExampleServicedCLI_CmdFormatFunctions() {
+	InitServiceAPITest("serviced", "format-functions")
+
+	// Output:
+	// Functions available in --format and --add-column templates, e.g. '{{.Tags | join ","}}':
+	//
+	// json VALUE               Formats a value as JSON
+	// humanizeBytes BYTES      Formats a number of bytes with a unit, e.g. 1.5G
+	// timeAgo TIME             Formats the time elapsed since a time, e.g. 3h ago
+	// upper STRING             Converts a string to upper case
+	// lower STRING             Converts a string to lower case
+	// join SEP LIST            Joins the elements of a list with a separator
+	// where FIELD VALUE LIST   Selects the elements of a list whose field, e.g. Purpose or AddressConfig.Port, has the value
+	// pluck FIELD LIST         Lists the value of a field of each element of a list
+}
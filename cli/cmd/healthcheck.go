@@ -27,6 +27,7 @@ func (c *ServicedCli) initHealthCheck() {
 		Usage:       "Reports on health of serviced",
 		Description: "serviced healthcheck [ISERVICENAME-1 [ISERVICENAME-2 ... [ISERVICENAME-N]]]",
 		Before:      c.cmdHealthCheck,
+		Flags:       []cli.Flag{colorFlag()},
 	})
 }
 
@@ -42,8 +43,10 @@ func (c *ServicedCli) cmdHealthCheck(ctx *cli.Context) error {
 	} else {
 
 		exitStatus := 0
+		cmdSetTableColor(ctx, c.config)
 		t := NewTable("Service Name,Container Name,Container ID,Health Check,Status")
 		t.Padding = 2
+		t.StatusFields = []string{"Status"}
 		for _, serviceHealth := range results {
 			for _, status := range serviceHealth.HealthStatuses {
 				if status.Status != "passed" {
@@ -14,15 +14,17 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/utils"
 	"github.com/pivotal-golang/bytefmt"
 )
@@ -40,17 +42,28 @@ func (c *ServicedCli) initHost() {
 				Description:  "serviced host list [SERVICEID]",
 				BashComplete: c.printHostsFirst,
 				Action:       c.cmdHostList,
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					cli.BoolFlag{
 						Name:  "verbose, v",
 						Usage: "Show JSON format",
 					},
+					cli.StringFlag{
+						Name:  "format",
+						Value: "",
+						Usage: "format the output using the given go template (see serviced help format-functions)",
+					},
 					cli.StringFlag{
 						Name:  "show-fields",
-						Value: "ID,Pool,Name,Addr,RPCPort,Cores,RAM,Cur/Max/Avg,Network,Release",
+						Value: hostListFields,
 						Usage: "Comma-delimited list describing which fields to display",
 					},
-				},
+					colorFlag(),
+				}, tableFlags()...),
+			}, {
+				Name:        "fields",
+				Usage:       "Lists the fields accepted by --show-fields and --format",
+				Description: "serviced host fields",
+				Action:      c.cmdHostFields,
 			}, {
 				Name:         "add",
 				Usage:        "Adds a new host",
@@ -72,6 +85,11 @@ func (c *ServicedCli) initHost() {
 						Name:  "register, r",
 						Usage: "Register delegate keys on the host via ssh",
 					},
+					cli.StringFlag{
+						Name:  "fingerprint",
+						Value: "",
+						Usage: "Fingerprint of the host's ssh host key to verify before registering, e.g. SHA256:...",
+					},
 				},
 			}, {
 				Name:         "remove",
@@ -91,6 +109,42 @@ func (c *ServicedCli) initHost() {
 				Description:  "serviced host set-memory HOSTID ALLOCATION",
 				BashComplete: c.printHostsAll,
 				Action:       c.cmdHostSetMemory,
+			}, {
+				Name:         "events",
+				Usage:        "Shows the event history of a host",
+				Description:  "serviced host events { HOSTID | HOSTNAME }",
+				BashComplete: c.printHostsFirst,
+				Action:       c.cmdHostEvents,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+				},
+			}, {
+				Name:         "self-test",
+				Usage:        "Validates that a host can reach the services it needs to run as a delegate",
+				Description:  "serviced host self-test [HOSTID | HOSTNAME]",
+				BashComplete: c.printHostsFirst,
+				Action:       c.cmdHostSelfTest,
+			}, {
+				Name:         "ssh",
+				Usage:        "Opens an ssh session to a registered host",
+				Description:  "serviced host ssh { HOSTID | HOSTNAME } [COMMAND]",
+				BashComplete: c.printHostsFirst,
+				Action:       c.cmdHostSSH,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "user, u",
+						Value: "",
+						Usage: "Login name to use on the remote host",
+					},
+					cli.StringSliceFlag{
+						Name:  "ssh-option, o",
+						Value: &cli.StringSlice{},
+						Usage: "Additional ssh option in ssh_config format (e.g. -o StrictHostKeyChecking=no)",
+					},
+				},
 			},
 		},
 	})
@@ -111,6 +165,41 @@ func (c *ServicedCli) hosts() (data []string) {
 	return
 }
 
+// searches for a host by its id or name
+func (c *ServicedCli) searchForHost(keyword string) (*host.Host, error) {
+	hosts, err := c.driver.GetHosts()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []host.Host
+	for _, h := range hosts {
+		if h.ID == keyword || strings.EqualFold(h.Name, keyword) {
+			matches = append(matches, h)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("host not found")
+	case 1:
+		return &matches[0], nil
+	}
+
+	t := NewTable("ID,Name,Addr,Pool")
+	t.Padding = 6
+	for _, h := range matches {
+		t.AddRow(map[string]interface{}{
+			"ID":   h.ID,
+			"Name": h.Name,
+			"Addr": h.IPAddr,
+			"Pool": h.PoolID,
+		})
+	}
+	t.Print()
+	return nil, fmt.Errorf("multiple results found; select one from list")
+}
+
 // Bash-completion command that prints a list of available hosts as the first
 // argument
 func (c *ServicedCli) printHostsFirst(ctx *cli.Context) {
@@ -150,15 +239,40 @@ func (c *ServicedCli) printHostAdd(ctx *cli.Context) {
 	fmt.Println(strings.Join(output, "\n"))
 }
 
-// serviced host list [--verbose, -v] [HOSTID]
+// hostListFields are all of the fields of serviced host list
+var hostListFields = "ID,Pool,Name,Addr,RPCPort,Cores,RAM,Cur/Max/Avg,Storage,Network,Release"
+
+// serviced host fields
+func (c *ServicedCli) cmdHostFields(ctx *cli.Context) {
+	printFields([]commandFields{{"serviced host list", hostListFields}}, host.Host{})
+}
+
+// printHostsFormat prints each host using the go template
+func printHostsFormat(text string, hosts []host.Host) {
+	tmpl, err := parseFormat("template", text)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not parse format template: %s\n", err)
+		return
+	}
+	for i := range hosts {
+		if err := tmpl.Execute(os.Stdout, &hosts[i]); err != nil {
+			fmt.Fprintf(os.Stderr, "could not execute format template: %s\n", err)
+			return
+		}
+	}
+}
+
+// serviced host list [--verbose, -v] [--format TEMPLATE] [HOSTID]
 func (c *ServicedCli) cmdHostList(ctx *cli.Context) {
 	if len(ctx.Args()) > 0 {
 		hostID := ctx.Args()[0]
-		if host, err := c.driver.GetHost(hostID); err != nil {
+		if h, err := c.driver.GetHost(hostID); err != nil {
 			fmt.Fprintln(os.Stderr, err)
-		} else if host == nil {
+		} else if h == nil {
 			fmt.Fprintln(os.Stderr, "host not found")
-		} else if jsonHost, err := json.MarshalIndent(host, " ", "  "); err != nil {
+		} else if format := ctx.String("format"); format != "" {
+			printHostsFormat(format, []host.Host{*h})
+		} else if jsonHost, err := c.marshalOutput(h); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal host: %s", err)
 		} else {
 			fmt.Println(string(jsonHost))
@@ -175,14 +289,22 @@ func (c *ServicedCli) cmdHostList(ctx *cli.Context) {
 		return
 	}
 
-	if ctx.Bool("verbose") {
-		if jsonHost, err := json.MarshalIndent(hosts, " ", "  "); err != nil {
+	if format := ctx.String("format"); format != "" {
+		printHostsFormat(format, hosts)
+	} else if ctx.Bool("verbose") {
+		if jsonHost, err := c.marshalOutput(hosts); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal host list: %s", err)
 		} else {
 			fmt.Println(string(jsonHost))
 		}
 	} else {
-		t := NewTable(ctx.String("show-fields"))
+		cmdSetTableColor(ctx, c.config)
+		t, err := newTableFromContext(ctx, ctx.String("show-fields"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		t.StatusFields = []string{"Storage"}
 		for _, h := range hosts {
 			var usage string
 			if stats, err := c.driver.GetHostMemory(h.ID); err != nil {
@@ -190,6 +312,10 @@ func (c *ServicedCli) cmdHostList(ctx *cli.Context) {
 			} else {
 				usage = fmt.Sprintf("%s / %s / %s", bytefmt.ByteSize(uint64(stats.Last)), bytefmt.ByteSize(uint64(stats.Max)), bytefmt.ByteSize(uint64(stats.Average)))
 			}
+			storage := "--"
+			if health, err := c.driver.GetHostStorage(h.ID); err == nil && health != nil {
+				storage = health.String()
+			}
 			t.AddRow(map[string]interface{}{
 				"ID":          h.ID,
 				"Pool":        h.PoolID,
@@ -199,6 +325,7 @@ func (c *ServicedCli) cmdHostList(ctx *cli.Context) {
 				"Cores":       h.Cores,
 				"RAM":         bytefmt.ByteSize(h.TotalRAM()),
 				"Cur/Max/Avg": usage,
+				"Storage":     storage,
 				"Network":     h.PrivateNetwork,
 				"Release":     h.ServiceD.Release,
 			})
@@ -253,7 +380,7 @@ func (c *ServicedCli) cmdHostAdd(ctx *cli.Context) {
 
 	keyfileName := ctx.String("key-file")
 	registerHost := ctx.Bool("register")
-	c.outputDelegateKey(host, privateKey, keyfileName, registerHost)
+	c.outputDelegateKey(host, privateKey, keyfileName, registerHost, ctx.String("fingerprint"))
 }
 
 // serviced host remove HOSTID ...
@@ -288,6 +415,109 @@ func (c *ServicedCli) cmdHostSetMemory(ctx *cli.Context) {
 	}
 }
 
+// serviced host events { HOSTID | HOSTNAME }
+func (c *ServicedCli) cmdHostEvents(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "events")
+		return
+	}
+
+	h, err := c.searchForHost(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	events, err := c.driver.GetHostEvents(h.ID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(events) == 0 {
+		fmt.Fprintln(os.Stderr, "no events found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonEvents, err := c.marshalOutput(events); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal host events: %s", err)
+		} else {
+			fmt.Println(string(jsonEvents))
+		}
+		return
+	}
+
+	for _, e := range events {
+		fmt.Printf("%s  %s  %s\n", e.Time.Format(time.RFC3339), e.Type, e.Message)
+		if r := e.Reconcile; r != nil {
+			for _, detail := range []struct {
+				label     string
+				instances []string
+			}{
+				{"killed", r.Killed},
+				{"restarted", r.Restarted},
+				{"adopted", r.Adopted},
+				{"removed", r.Removed},
+			} {
+				if len(detail.instances) > 0 {
+					fmt.Printf("    %-10s %s\n", detail.label+":", strings.Join(detail.instances, ", "))
+				}
+			}
+		}
+	}
+}
+
+// serviced host self-test [HOSTID | HOSTNAME]
+func (c *ServicedCli) cmdHostSelfTest(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) > 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "self-test")
+		c.exit(1)
+		return
+	}
+
+	var hostID string
+	if len(args) == 1 {
+		h, err := c.searchForHost(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		hostID = h.ID
+	}
+	report, err := c.driver.SelfTestHost(hostID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	t := NewTable("Check,Result,Detail")
+	t.Padding = 6
+	failed := 0
+	for _, result := range report.Results {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+			failed++
+		}
+		t.AddRow(map[string]interface{}{
+			"Check":  result.Name,
+			"Result": status,
+			"Detail": result.Detail,
+		})
+	}
+	t.Print()
+	if failed > 0 {
+		fmt.Printf("host %s failed %d of %d checks\n", report.HostID, failed, len(report.Results))
+		c.exit(1)
+		return
+	}
+	fmt.Printf("host %s passed all %d checks\n", report.HostID, len(report.Results))
+}
+
 // serviced host register (KEYSFILE | -)
 func (c *ServicedCli) cmdHostRegister(ctx *cli.Context) {
 	args := ctx.Args()
@@ -317,4 +547,36 @@ func (c *ServicedCli) cmdHostRegister(ctx *cli.Context) {
 		os.Exit(1)
 	}
 
+	// report the installed key so that the master can verify it
+	if fingerprint, err := auth.DelegateKeyFingerprint(data); err == nil {
+		fmt.Println(fingerprint)
+	}
+
+}
+
+// serviced host ssh [--user USER] [[-o OPTION]...] { HOSTID | HOSTNAME } [COMMAND]
+func (c *ServicedCli) cmdHostSSH(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "ssh")
+		return
+	}
+
+	h, err := c.searchForHost(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	cfg := api.HostSSHConfig{
+		Host:    h,
+		User:    ctx.String("user"),
+		Options: ctx.StringSlice("ssh-option"),
+		Command: args[1:],
+	}
+
+	if err := c.driver.SSHHost(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
 }
@@ -19,11 +19,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -72,6 +75,7 @@ type HostAPITest struct {
 	writeFail    bool
 	pools        []pool.ResourcePool
 	hosts        []host.Host
+	sshHostKeys  []api.SSHHostKey
 }
 
 func InitHostAPITest(args ...string) {
@@ -106,6 +110,79 @@ func (t HostAPITest) GetHost(id string) (*host.Host, error) {
 	return nil, nil
 }
 
+func (t HostAPITest) GetHostMemory(id string) (*metrics.MemoryUsageStats, error) {
+	return nil, ErrInvalidHost
+}
+
+func (t HostAPITest) GetHostStorage(id string) (*host.StorageHealth, error) {
+	if t.fail {
+		return nil, ErrInvalidHost
+	}
+	switch id {
+	case "test-host-id-1":
+		health := &host.StorageHealth{
+			Usage: []host.StorageUsage{
+				{Name: "docker thin pool data", Total: 100, Used: 40},
+				{Name: "docker thin pool metadata", Total: 100, Used: 85},
+			},
+		}
+		health.SetLevels(80, 90)
+		return health, nil
+	case "test-host-id-2":
+		health := &host.StorageHealth{
+			Usage: []host.StorageUsage{{Name: "docker root", Total: 100, Used: 10}},
+		}
+		health.SetLevels(80, 90)
+		return health, nil
+	}
+	return nil, nil
+}
+
+func (t HostAPITest) GetHostEvents(id string) ([]host.Event, error) {
+	if t.fail {
+		return nil, ErrInvalidHost
+	} else if id != "test-host-id-1" {
+		return []host.Event{}, nil
+	}
+	disconnected := time.Date(2016, 5, 4, 3, 2, 1, 0, time.UTC)
+	report := &host.ReconcileReport{
+		Disconnected: disconnected,
+		Reconnected:  disconnected.Add(5 * time.Minute),
+		Killed:       []string{"test-service-1/0"},
+		Restarted:    []string{"test-service-2/0", "test-service-2/1"},
+		Adopted:      []string{"test-service-2/1"},
+	}
+	return []host.Event{
+		{
+			Time:      report.Reconnected,
+			Type:      host.EventReconcile,
+			Message:   report.Summary(),
+			Reconcile: report,
+		},
+	}, nil
+}
+
+func (t HostAPITest) SelfTestHost(id string) (*host.SelfTestReport, error) {
+	if t.fail {
+		return nil, ErrInvalidHost
+	}
+	report := &host.SelfTestReport{
+		HostID: id,
+		Ran:    time.Date(2016, 5, 4, 3, 2, 1, 0, time.UTC),
+		Results: []host.SelfTestResult{
+			{Name: "docker", Passed: true, Detail: "docker daemon responded"},
+			{Name: "zookeeper", Passed: true, Detail: "reached 127.0.0.1:2181"},
+			{Name: "clock", Passed: true, Detail: "clock differs from the master's by 12ms"},
+		},
+	}
+	if id == "" {
+		report.HostID = "test-host-id-1"
+	} else if id == "test-host-id-2" {
+		report.Results[1] = host.SelfTestResult{Name: "zookeeper", Detail: "could not reach any zookeeper server: 127.0.0.1:2181 (connection refused)"}
+	}
+	return report, nil
+}
+
 func (t HostAPITest) AddHost(config api.HostConfig) (*host.Host, []byte, error) {
 	if t.fail {
 		return nil, nil, ErrInvalidHost
@@ -129,7 +206,11 @@ func (t HostAPITest) RemoveHost(id string) error {
 	return nil
 }
 
-func (t HostAPITest) RegisterRemoteHost(h *host.Host, data []byte) error {
+func (t HostAPITest) GetSSHHostKeys(h *host.Host) ([]api.SSHHostKey, error) {
+	return t.sshHostKeys, nil
+}
+
+func (t HostAPITest) RegisterRemoteHost(h *host.Host, data []byte, fingerprints []string) error {
 	if t.registerFail {
 		return errors.New("Forcing RemoteRegisterHost to fail for testing")
 	}
@@ -143,6 +224,14 @@ func (t HostAPITest) WriteDelegateKey(filename string, data []byte) error {
 	return nil
 }
 
+func (t HostAPITest) SSHHost(cfg api.HostSSHConfig) error {
+	if t.fail {
+		return ErrInvalidHost
+	}
+	fmt.Printf("%s %s %v %v\n", cfg.Host.IPAddr, cfg.User, cfg.Options, cfg.Command)
+	return nil
+}
+
 func TestServicedCLI_CmdHostList_one(t *testing.T) {
 	hostID := "test-host-id-1"
 
@@ -163,6 +252,25 @@ func TestServicedCLI_CmdHostList_one(t *testing.T) {
 	}
 }
 
+func TestServicedCLI_CmdHostList_color(t *testing.T) {
+	defer func() { tableColor = false }()
+
+	output := string(pipe(InitHostAPITest, "serviced", "host", "list", "--show-fields", "ID,Storage", "--color", "always"))
+	for _, expected := range []string{
+		colorYellow + "warning (docker thin pool metadata 85%)" + colorReset,
+		colorGreen + "ok (docker root 10%)" + colorReset,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, output)
+		}
+	}
+
+	output = string(pipe(InitHostAPITest, "serviced", "host", "list", "--show-fields", "ID,Storage", "--color", "never"))
+	if strings.Contains(output, "\x1b[") {
+		t.Errorf("unexpected color in output:\n%s", output)
+	}
+}
+
 func TestServicedCLI_CmdHostList_all(t *testing.T) {
 	expected, err := DefaultHostAPITest.GetHosts()
 	if err != nil {
@@ -191,6 +299,55 @@ func ExampleServicedCLI_CmdHostList() {
 	InitHostAPITest("serviced", "host", "list")
 }
 
+func ExampleServicedCLI_CmdHostList_storage() {
+	InitHostAPITest("serviced", "host", "list", "--show-fields", "ID,Storage")
+
+	// Output:
+	// ID                  Storage
+	// test-host-id-1      warning (docker thin pool metadata 85%)
+	// test-host-id-2      ok (docker root 10%)
+	// test-host-id-3      --
+}
+
+func ExampleServicedCLI_CmdHostList_columns() {
+	InitHostAPITest("serviced", "host", "list", "--show-fields", "ID,Name",
+		"--add-column", "Label={{.Name}}@{{.Pool}}", "--max-width", "ID=8")
+	InitHostAPITest("serviced", "host", "list", "--show-fields", "Name,Pool", "--no-header")
+
+	// Output:
+	// ID            Name       Label
+	// test-...      alpha      alpha@default
+	// test-...      beta       beta@default
+	// test-...      gamma      gamma@testpool
+	// alpha      default
+	// beta       default
+	// gamma      testpool
+}
+
+func ExampleServicedCLI_CmdHostList_format() {
+	InitHostAPITest("serviced", "host", "list", "--format", "{{.Name}} {{.IPAddr}}\n")
+	InitHostAPITest("serviced", "host", "list", "--format", "{{.ID}}:{{.PrivateNetwork}}\n", "test-host-id-2")
+	InitHostAPITest("serviced", "host", "list", "--format", "{{.Name | upper}} {{.Memory | humanizeBytes}}\n", "test-host-id-2")
+	pipeStderr(InitHostAPITest, "serviced", "host", "list", "--format", "{{.Name")
+
+	// Output:
+	// alpha 127.0.0.1
+	// beta 192.168.0.1
+	// gamma 0.0.0.0
+	// test-host-id-2:10.0.0.1/66
+	// BETA 512M
+	// could not parse format template: template: template:1: unclosed action
+}
+
+func ExampleServicedCLI_CmdHostList_badColumn() {
+	pipeStderr(InitHostAPITest, "serviced", "host", "list", "--add-column", "Label")
+	pipeStderr(InitHostAPITest, "serviced", "host", "list", "--max-width", "ID=none")
+
+	// Output:
+	// column "Label" must be NAME=TEMPLATE
+	// max width "ID=none" must be a positive number
+}
+
 func ExampleServicedCLI_CmdHostList_fail() {
 	DefaultHostAPITest.fail = true
 	defer func() { DefaultHostAPITest.fail = false }()
@@ -251,6 +408,17 @@ func ExampleServicedCLI_CmdHostAdd_register() {
 	// 127.0.0.33-default
 }
 
+func ExampleServicedCLI_CmdHostAdd_registerFingerprint() {
+	// Register a remote host after verifying its ssh host key
+	DefaultHostAPITest.sshHostKeys = []api.SSHHostKey{{Type: "ECDSA", Fingerprint: "SHA256:ecdsa"}}
+	defer func() { DefaultHostAPITest.sshHostKeys = nil }()
+	InitHostAPITest("serviced", "host", "add", "--register", "--fingerprint", "SHA256:ecdsa", "127.0.0.34:8080", "default")
+
+	// Output:
+	// Registered host at 127.0.0.34
+	// 127.0.0.34-default
+}
+
 /* The output of this command is dynamic, so disabling until we figure out how to do this
 
 func ExampleServicedCLI_CmdHostAdd_registerfail() {
@@ -410,3 +578,115 @@ func ExampleServicedCLI_CmdHostRegister_usage() {
 
 	// OPTIONS:
 }
+
+func ExampleServicedCLI_CmdHostSSH() {
+	InitHostAPITest("serviced", "host", "ssh", "test-host-id-2")
+	InitHostAPITest("serviced", "host", "ssh", "-u", "zenoss", "-o", "Port=2222", "GAMMA", "uptime")
+
+	// Output:
+	// 192.168.0.1  [] []
+	// 0.0.0.0 zenoss [Port=2222] [uptime]
+}
+
+func ExampleServicedCLI_CmdHostSSH_err() {
+	pipeStderr(InitHostAPITest, "serviced", "host", "ssh", "test-host-id-0")
+
+	DefaultHostAPITest.fail = true
+	defer func() { DefaultHostAPITest.fail = false }()
+	pipeStderr(InitHostAPITest, "serviced", "host", "ssh", "test-host-id-1")
+
+	// Output:
+	// host not found
+	// invalid host
+}
+
+func ExampleServicedCLI_CmdHostSSH_complete() {
+	InitHostAPITest("serviced", "host", "ssh", "--generate-bash-completion")
+	fmt.Println("")
+	InitHostAPITest("serviced", "host", "ssh", "test-host-id-1", "--generate-bash-completion")
+
+	// Output:
+	// test-host-id-1
+	// test-host-id-2
+	// test-host-id-3
+}
+
+func ExampleServicedCLI_CmdHostEvents() {
+	InitHostAPITest("serviced", "host", "events", "alpha")
+
+	// Output:
+	// 2016-05-04T03:07:01Z  reconcile  offline for 5m0s: 1 killed, 2 restarted, 1 adopted, 0 removed
+	//     killed:    test-service-1/0
+	//     restarted: test-service-2/0, test-service-2/1
+	//     adopted:   test-service-2/1
+}
+
+func ExampleServicedCLI_CmdHostEvents_none() {
+	pipeStderr(InitHostAPITest, "serviced", "host", "events", "test-host-id-2")
+	pipeStderr(InitHostAPITest, "serviced", "host", "events", "delta")
+
+	// Output:
+	// no events found
+	// host not found
+}
+
+func ExampleServicedCLI_CmdHostEvents_usage() {
+	InitHostAPITest("serviced", "host", "events")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    events - Shows the event history of a host
+	//
+	// USAGE:
+	//    command events [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced host events { HOSTID | HOSTNAME }
+	//
+	// OPTIONS:
+	//    --verbose, -v	Show JSON format
+}
+
+func ExampleServicedCLI_CmdHostSelfTest() {
+	InitHostAPITest("serviced", "host", "self-test")
+
+	// Output:
+	// Check          Result      Detail
+	// docker         PASS        docker daemon responded
+	// zookeeper      PASS        reached 127.0.0.1:2181
+	// clock          PASS        clock differs from the master's by 12ms
+	// host test-host-id-1 passed all 3 checks
+}
+
+func ExampleServicedCLI_CmdHostSelfTest_fail() {
+	c := New(DefaultHostAPITest, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	c.Run([]string{"serviced", "host", "self-test", "beta"})
+	fmt.Println("exit code", c.exitCode)
+
+	// Output:
+	// Check          Result      Detail
+	// docker         PASS        docker daemon responded
+	// zookeeper      FAIL        could not reach any zookeeper server: 127.0.0.1:2181 (connection refused)
+	// clock          PASS        clock differs from the master's by 12ms
+	// host test-host-id-2 failed 1 of 3 checks
+	// exit code 1
+}
+
+func ExampleServicedCLI_CmdHostSelfTest_err() {
+	api := DefaultHostAPITest
+	api.fail = true
+	for _, c := range []*ServicedCli{
+		New(api, utils.TestConfigReader(make(map[string]string))),
+		New(DefaultHostAPITest, utils.TestConfigReader(make(map[string]string))),
+	} {
+		c.exitDisabled = true
+		pipeStderr(func(args ...string) { c.Run(args) }, "serviced", "host", "self-test", "delta")
+	}
+
+	// Output:
+	// invalid host
+	// host not found
+}
@@ -0,0 +1,57 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/codegangsta/cli"
+)
+
+// Initializer for serviced isvcs subcommands
+func (c *ServicedCli) initISvcs() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "isvcs",
+		Usage:       "Administers the internal services",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "restart",
+				Usage:       "Restarts an internal service, applying its configured resources",
+				Description: "serviced isvcs restart ISVC",
+				Action:      c.cmdISvcsRestart,
+			},
+		},
+	})
+}
+
+// serviced isvcs restart ISVC
+func (c *ServicedCli) cmdISvcsRestart(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "restart")
+		return
+	}
+
+	names, err := c.driver.RestartISvc(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	for _, name := range names {
+		fmt.Printf("Restarted internal service %s\n", name)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/utils"
+)
+
+type ISvcsAPITest struct {
+	api.API
+}
+
+func InitISvcsAPITest(args ...string) {
+	New(ISvcsAPITest{}, utils.TestConfigReader(make(map[string]string))).Run(args)
+}
+
+func (t ISvcsAPITest) RestartISvc(name string) ([]string, error) {
+	switch name {
+	case "elasticsearch":
+		return []string{"elasticsearch-logstash", "elasticsearch-serviced"}, nil
+	case "zookeeper":
+		return []string{"zookeeper"}, nil
+	}
+	return nil, fmt.Errorf("could not find isvc %q", name)
+}
+
+func ExampleServicedCLI_CmdISvcsRestart() {
+	InitISvcsAPITest("serviced", "isvcs", "restart", "elasticsearch")
+
+	// Output:
+	// Restarted internal service elasticsearch-logstash
+	// Restarted internal service elasticsearch-serviced
+}
+
+func ExampleServicedCLI_CmdISvcsRestart_usage() {
+	InitISvcsAPITest("serviced", "isvcs", "restart")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    restart - Restarts an internal service, applying its configured resources
+	//
+	// USAGE:
+	//    command restart [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced isvcs restart ISVC
+	//
+	// OPTIONS:
+}
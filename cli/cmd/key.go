@@ -14,13 +14,16 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/domain/host"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 func (c *ServicedCli) initKey() {
@@ -51,6 +54,11 @@ func (c *ServicedCli) initKey() {
 						Name:  "register, r",
 						Usage: "Register delegate keys on the host via ssh",
 					},
+					cli.StringFlag{
+						Name:  "fingerprint",
+						Value: "",
+						Usage: "Fingerprint of the host's ssh host key to verify before registering, e.g. SHA256:...",
+					},
 				},
 			},
 		},
@@ -98,13 +106,13 @@ func (c *ServicedCli) cmdKeyReset(ctx *cli.Context) {
 
 	keyfileName := ctx.String("key-file")
 	registerHost := ctx.Bool("register")
-	c.outputDelegateKey(host, key, keyfileName, registerHost)
+	c.outputDelegateKey(host, key, keyfileName, registerHost, ctx.String("fingerprint"))
 }
 
-func (c *ServicedCli) outputDelegateKey(host *host.Host, keyData []byte, keyfileName string, register bool) {
+func (c *ServicedCli) outputDelegateKey(host *host.Host, keyData []byte, keyfileName string, register bool, fingerprint string) {
 	writeKeyFile := false
 	if register {
-		if err := c.driver.RegisterRemoteHost(host, keyData); err != nil {
+		if err := c.registerDelegateKey(host, keyData, fingerprint); err != nil {
 			fmt.Fprintf(os.Stderr, "Error registering host: %s\n", err.Error())
 			writeKeyFile = true
 		} else {
@@ -133,3 +141,43 @@ func (c *ServicedCli) outputDelegateKey(host *host.Host, keyData []byte, keyfile
 	}
 	fmt.Println(host.ID)
 }
+
+// registerDelegateKey sends the delegate keys to the host once its ssh host
+// key is verified, either against the fingerprint or by asking the user.
+func (c *ServicedCli) registerDelegateKey(host *host.Host, keyData []byte, fingerprint string) error {
+	keys, err := c.driver.GetSSHHostKeys(host)
+	if err != nil {
+		return err
+	}
+
+	var trusted []string
+	if len(keys) > 0 {
+		if fingerprint != "" {
+			for _, key := range keys {
+				if key.Fingerprint == fingerprint {
+					trusted = []string{fingerprint}
+					break
+				}
+			}
+			if trusted == nil {
+				return fmt.Errorf("no ssh host key of %s matches fingerprint %s", host.IPAddr, fingerprint)
+			}
+		} else {
+			if !terminal.IsTerminal(syscall.Stdin) {
+				return fmt.Errorf("cannot verify the ssh host key of %s; use --fingerprint", host.IPAddr)
+			}
+			fmt.Printf("The ssh host keys of %s are:\n", host.IPAddr)
+			for _, key := range keys {
+				fmt.Printf("  %s %s\n", key.Type, key.Fingerprint)
+				trusted = append(trusted, key.Fingerprint)
+			}
+			fmt.Printf("Send delegate keys to this host (yes/no)? ")
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "yes" && answer != "y" {
+				return fmt.Errorf("ssh host key of %s was not accepted", host.IPAddr)
+			}
+		}
+	}
+
+	return c.driver.RegisterRemoteHost(host, keyData, trusted)
+}
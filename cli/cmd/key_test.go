@@ -20,9 +20,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/cli/api/apimocks"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/utils"
+	"github.com/stretchr/testify/mock"
 
 	. "gopkg.in/check.v1"
 )
@@ -78,42 +80,76 @@ func (s *mySuite) Test_cmdKeyReset_resetError(c *C) {
 
 func (s *mySuite) Test_outputDelegateKey(c *C) {
 	s.api.On("WriteDelegateKey", testHostFilename, testKeyData).Return(nil)
-	s.cli.outputDelegateKey(&testHost, testKeyData, "", false)
+	s.cli.outputDelegateKey(&testHost, testKeyData, "", false, "")
 	s.api.AssertExpectations(c)
 }
 
 func (s *mySuite) Test_outputDelegateKey_keyfile(c *C) {
 	keyfileName := "foo.bar"
 	s.api.On("WriteDelegateKey", keyfileName, testKeyData).Return(nil)
-	s.cli.outputDelegateKey(&testHost, testKeyData, keyfileName, false)
+	s.cli.outputDelegateKey(&testHost, testKeyData, keyfileName, false, "")
 	s.api.AssertExpectations(c)
 }
 
 func (s *mySuite) Test_outputDelegateKey_register(c *C) {
-	s.api.On("RegisterRemoteHost", &testHost, testKeyData).Return(nil)
-	s.cli.outputDelegateKey(&testHost, testKeyData, "", true)
+	s.api.On("GetSSHHostKeys", &testHost).Return(nil, nil)
+	s.api.On("RegisterRemoteHost", &testHost, testKeyData, []string(nil)).Return(nil)
+	s.cli.outputDelegateKey(&testHost, testKeyData, "", true, "")
 	s.api.AssertExpectations(c)
 }
 
 func (s *mySuite) Test_outputDelegateKey_registerfail(c *C) {
-	s.api.On("RegisterRemoteHost", &testHost, testKeyData).Return(errors.New("woot"))
+	s.api.On("GetSSHHostKeys", &testHost).Return(nil, nil)
+	s.api.On("RegisterRemoteHost", &testHost, testKeyData, []string(nil)).Return(errors.New("woot"))
 	s.api.On("WriteDelegateKey", testHostFilename, testKeyData).Return(nil)
-	s.cli.outputDelegateKey(&testHost, testKeyData, "", true)
+	s.cli.outputDelegateKey(&testHost, testKeyData, "", true, "")
 	s.api.AssertExpectations(c)
 }
 
 func (s *mySuite) Test_outputDelegateKey_register_keyfile(c *C) {
 	keyfileName := "foo-bar"
-	s.api.On("RegisterRemoteHost", &testHost, testKeyData).Return(nil)
+	s.api.On("GetSSHHostKeys", &testHost).Return(nil, nil)
+	s.api.On("RegisterRemoteHost", &testHost, testKeyData, []string(nil)).Return(nil)
 	s.api.On("WriteDelegateKey", keyfileName, testKeyData).Return(nil)
-	s.cli.outputDelegateKey(&testHost, testKeyData, keyfileName, true)
+	s.cli.outputDelegateKey(&testHost, testKeyData, keyfileName, true, "")
 	s.api.AssertExpectations(c)
 }
 
 func (s *mySuite) Test_outputDelegateKey_registerfail_keyfile(c *C) {
 	keyfileName := "foo-bar"
-	s.api.On("RegisterRemoteHost", &testHost, testKeyData).Return(errors.New("woot"))
+	s.api.On("GetSSHHostKeys", &testHost).Return(nil, nil)
+	s.api.On("RegisterRemoteHost", &testHost, testKeyData, []string(nil)).Return(errors.New("woot"))
 	s.api.On("WriteDelegateKey", keyfileName, testKeyData).Return(nil)
-	s.cli.outputDelegateKey(&testHost, testKeyData, keyfileName, true)
+	s.cli.outputDelegateKey(&testHost, testKeyData, keyfileName, true, "")
 	s.api.AssertExpectations(c)
 }
+
+var testSSHHostKeys = []api.SSHHostKey{
+	{Type: "ECDSA", Fingerprint: "SHA256:ecdsa"},
+	{Type: "RSA", Fingerprint: "SHA256:rsa"},
+}
+
+func (s *mySuite) Test_outputDelegateKey_register_fingerprint(c *C) {
+	s.api.On("GetSSHHostKeys", &testHost).Return(testSSHHostKeys, nil)
+	s.api.On("RegisterRemoteHost", &testHost, testKeyData, []string{"SHA256:rsa"}).Return(nil)
+	s.cli.outputDelegateKey(&testHost, testKeyData, "", true, "SHA256:rsa")
+	s.api.AssertExpectations(c)
+}
+
+func (s *mySuite) Test_outputDelegateKey_register_fingerprintMismatch(c *C) {
+	// Keys are not sent to a host with an unexpected host key
+	s.api.On("GetSSHHostKeys", &testHost).Return(testSSHHostKeys, nil)
+	s.api.On("WriteDelegateKey", testHostFilename, testKeyData).Return(nil)
+	s.cli.outputDelegateKey(&testHost, testKeyData, "", true, "SHA256:other")
+	s.api.AssertExpectations(c)
+	s.api.AssertNotCalled(c, "RegisterRemoteHost", &testHost, testKeyData, mock.Anything)
+}
+
+func (s *mySuite) Test_outputDelegateKey_register_unverified(c *C) {
+	// Without a fingerprint or a terminal to ask on, keys are not sent
+	s.api.On("GetSSHHostKeys", &testHost).Return(testSSHHostKeys, nil)
+	s.api.On("WriteDelegateKey", testHostFilename, testKeyData).Return(nil)
+	s.cli.outputDelegateKey(&testHost, testKeyData, "", true, "")
+	s.api.AssertExpectations(c)
+	s.api.AssertNotCalled(c, "RegisterRemoteHost", &testHost, testKeyData, mock.Anything)
+}
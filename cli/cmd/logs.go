@@ -16,6 +16,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
@@ -60,11 +61,111 @@ func (c *ServicedCli) initLog() {
 						Usage: "Show additional diagnostic messages",
 					},
 				},
+			}, {
+				Name:        "level",
+				Usage:       "Manages the log levels of serviced components",
+				Description: "",
+				Subcommands: []cli.Command{
+					{
+						Name:        "set",
+						Usage:       "Sets the log level of a component (e.g. zzk or zzk.service) and the components beneath it",
+						Description: "serviced log level set COMPONENT LEVEL",
+						Action:      c.cmdLogLevelSet,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "host",
+								Value: "",
+								Usage: "ID of the host; defaults to the master's host",
+							},
+						},
+					}, {
+						Name:        "reset",
+						Usage:       "Resets the log level of a component to the level of its parent",
+						Description: "serviced log level reset COMPONENT",
+						Action:      c.cmdLogLevelReset,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "host",
+								Value: "",
+								Usage: "ID of the host; defaults to the master's host",
+							},
+						},
+					}, {
+						Name:        "list",
+						Usage:       "Lists the components whose log levels have been set",
+						Description: "serviced log level list",
+						Action:      c.cmdLogLevelList,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "host",
+								Value: "",
+								Usage: "ID of the host; defaults to the master's host",
+							},
+						},
+					},
+				},
 			},
 		},
 	})
 }
 
+// serviced log level set [--host HOSTID] COMPONENT LEVEL
+func (c *ServicedCli) cmdLogLevelSet(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set")
+		return
+	}
+	if err := c.driver.SetLogLevel(ctx.String("host"), args[0], args[1]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Printf("%s: %s\n", args[0], args[1])
+}
+
+// serviced log level reset [--host HOSTID] COMPONENT
+func (c *ServicedCli) cmdLogLevelReset(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "reset")
+		return
+	}
+	if err := c.driver.SetLogLevel(ctx.String("host"), args[0], ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(args[0])
+}
+
+// serviced log level list [--host HOSTID]
+func (c *ServicedCli) cmdLogLevelList(ctx *cli.Context) {
+	levels, err := c.driver.GetLogLevels(ctx.String("host"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(levels) == 0 {
+		fmt.Fprintln(os.Stderr, "no log levels have been set")
+		return
+	}
+
+	var components []string
+	for component := range levels {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	t := NewTable("Component,Level")
+	t.Padding = 4
+	for _, component := range components {
+		t.AddRow(map[string]interface{}{
+			"Component": component,
+			"Level":     levels[component],
+		})
+	}
+	t.Print()
+}
+
 // serviced log export
 func (c *ServicedCli) cmdExportLogs(ctx *cli.Context) {
 	if len(ctx.Args()) > 0 {
@@ -20,14 +20,15 @@ import (
 
 	"github.com/control-center/serviced/cli/api"
 	mocks "github.com/control-center/serviced/cli/api/apimocks"
+	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/utils"
 	"github.com/stretchr/testify/mock"
 )
 
 type LogsCLITestCase struct {
-	Args                      []string
-	ExpectedExportLogsConfig  api.ExportLogsConfig
-	Expected_GetServicesCalls int
+	Args                        []string
+	ExpectedExportLogsConfig    api.ExportLogsConfig
+	Expected_QueryServicesCalls int
 }
 
 func ExampleServicedCLI_CmdLogExport_usage() {
@@ -59,7 +60,7 @@ func TestLogsCLI_CmdLogExport_SingleServiceName(t *testing.T) {
 		ExpectedExportLogsConfig: api.ExportLogsConfig{
 			ServiceIDs: []string{"test-service-3"},
 		},
-		Expected_GetServicesCalls: 1,
+		Expected_QueryServicesCalls: 1,
 	}
 	testCmdLogExport(t, testCase)
 }
@@ -70,7 +71,7 @@ func TestLogsCLI_CmdLogExport_MultipleServiceNames(t *testing.T) {
 		ExpectedExportLogsConfig: api.ExportLogsConfig{
 			ServiceIDs: []string{"test-service-3", "test-service-2"},
 		},
-		Expected_GetServicesCalls: 2,
+		Expected_QueryServicesCalls: 2,
 	}
 	testCmdLogExport(t, testCase)
 }
@@ -83,7 +84,6 @@ func TestLogsCLI_CmdLogExport_FromToDate(t *testing.T) {
 			FromDate:   "2001.05.01",
 			ToDate:     "2010.06.27",
 		},
-		Expected_GetServicesCalls: 1,
 	}
 	testCmdLogExport(t, testCase)
 }
@@ -170,8 +170,20 @@ func makeMatcher(c api.ExportLogsConfig) func(api.ExportLogsConfig) bool {
 func testCmdLogExport(t *testing.T, tc LogsCLITestCase) {
 	mockAPI := mocks.API{}
 
-	if tc.Expected_GetServicesCalls > 0 {
-		mockAPI.On("GetServices").Times(tc.Expected_GetServicesCalls).Return(DefaultTestServices, nil) // DefaultTestServices from cmd/service_test.go
+	stub := ServiceAPITest{services: DefaultTestServices} // from cmd/service_test.go
+	if len(tc.ExpectedExportLogsConfig.ServiceIDs) > 0 {
+		getService := func(id string) *service.Service {
+			svc, _ := stub.GetService(id)
+			return svc
+		}
+		mockAPI.On("GetService", mock.Anything).Return(getService, nil)
+	}
+	if tc.Expected_QueryServicesCalls > 0 {
+		queryServices := func(query service.ServiceQuery) *service.ServiceSummaryPage {
+			page, _ := stub.QueryServices(query)
+			return page
+		}
+		mockAPI.On("QueryServices", mock.Anything).Times(tc.Expected_QueryServicesCalls).Return(queryServices, nil)
 	}
 	matcher := makeMatcher(tc.ExpectedExportLogsConfig)
 	mockAPI.On("ExportLogs", mock.MatchedBy(matcher)).Once().Return(nil)
@@ -184,3 +196,52 @@ func runLogsAPITest(driver api.API, args ...string) {
 	c.exitDisabled = true
 	c.Run(args)
 }
+
+func ExampleServicedCLI_CmdLogLevelSet() {
+	driver := &mocks.API{}
+	driver.On("SetLogLevel", "host1", "zzk", "debug").Return(nil)
+	runLogsAPITest(driver, "serviced", "log", "level", "set", "--host", "host1", "zzk", "debug")
+
+	// Output:
+	// zzk: debug
+}
+
+func ExampleServicedCLI_CmdLogLevelSet_usage() {
+	runLogsAPITest(&mocks.API{}, "serviced", "log", "level", "set", "zzk")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    set - Sets the log level of a component (e.g. zzk or zzk.service) and the components beneath it
+	//
+	// USAGE:
+	//    command set [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced log level set COMPONENT LEVEL
+	//
+	// OPTIONS:
+	//    --host 	ID of the host; defaults to the master's host
+}
+
+func ExampleServicedCLI_CmdLogLevelReset() {
+	driver := &mocks.API{}
+	driver.On("SetLogLevel", "", "zzk.service", "").Return(nil)
+	runLogsAPITest(driver, "serviced", "log", "level", "reset", "zzk.service")
+
+	// Output:
+	// zzk.service
+}
+
+func ExampleServicedCLI_CmdLogLevelList() {
+	driver := &mocks.API{}
+	driver.On("GetLogLevels", "").Return(map[string]string{"zzk.service": "warning", "facade": "debug", "zzk": "debug"}, nil)
+	runLogsAPITest(driver, "serviced", "log", "level", "list")
+
+	// Output:
+	// Component      Level
+	// facade         debug
+	// zzk            debug
+	// zzk.service    warning
+}
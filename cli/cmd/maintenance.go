@@ -0,0 +1,204 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/maintenance"
+)
+
+// maintenanceStartFormat is the local time format accepted for the start of
+// a maintenance window, in addition to RFC3339
+const maintenanceStartFormat = "2006-01-02 15:04"
+
+// Initializer for serviced maintenance
+func (c *ServicedCli) initMaintenance() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "maintenance",
+		Usage:       "Administers maintenance windows",
+		Description: "Suppresses automatic restarts and rescheduling of service instances during scheduled maintenance",
+		Subcommands: []cli.Command{
+			{
+				Name:        "list",
+				Usage:       "Lists all maintenance windows",
+				Description: "serviced maintenance list",
+				Action:      c.cmdMaintenanceList,
+				Flags: append([]cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "ID,Scope,Target,Start,Duration,Repeat,Status,Description",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				}, tableFlags()...),
+			}, {
+				Name:        "add",
+				Usage:       "Schedules a maintenance window for a deployment, service, or host",
+				Description: "serviced maintenance add [--start TIME] [--repeat daily|weekly] SCOPE TARGET DURATION",
+				Action:      c.cmdMaintenanceAdd,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "start",
+						Value: "",
+						Usage: "When the window starts, as RFC3339 or local 'YYYY-MM-DD HH:MM' (default: now)",
+					},
+					cli.StringFlag{
+						Name:  "repeat",
+						Value: "",
+						Usage: "Repeat the window daily or weekly",
+					},
+					cli.StringFlag{
+						Name:  "description, d",
+						Value: "",
+						Usage: "Description of the maintenance",
+					},
+				},
+			}, {
+				Name:        "remove",
+				ShortName:   "rm",
+				Usage:       "Removes an existing maintenance window",
+				Description: "serviced maintenance remove WINDOWID ...",
+				Action:      c.cmdMaintenanceRemove,
+			},
+		},
+	})
+}
+
+// serviced maintenance list [--verbose, -v]
+func (c *ServicedCli) cmdMaintenanceList(ctx *cli.Context) {
+	windows, err := c.driver.ListMaintenanceWindows()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(windows) == 0 {
+		fmt.Fprintln(os.Stderr, "no maintenance windows found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonWindows, err := c.marshalOutput(windows); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal maintenance window list: %s", err)
+		} else {
+			fmt.Println(string(jsonWindows))
+		}
+		return
+	}
+
+	t, err := newTableFromContext(ctx, ctx.String("show-fields"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	t.Padding = 4
+	now := time.Now()
+	for _, w := range windows {
+		status := "scheduled"
+		if until := w.ActiveUntil(now); !until.IsZero() {
+			status = "active until " + until.Local().Format(maintenanceStartFormat)
+		} else if w.Expired(now) {
+			status = "expired"
+		}
+		repeat := w.Repeat
+		if repeat == "" {
+			repeat = "never"
+		}
+		t.AddRow(map[string]interface{}{
+			"ID":          w.ID,
+			"Scope":       w.Scope,
+			"Target":      w.Target,
+			"Start":       w.Start.Local().Format(time.RFC3339),
+			"Duration":    w.Duration.String(),
+			"Repeat":      repeat,
+			"Status":      status,
+			"Description": w.Description,
+		})
+	}
+	t.Print()
+}
+
+// serviced maintenance add [--start TIME] [--repeat daily|weekly] [--description DESC] SCOPE TARGET DURATION
+func (c *ServicedCli) cmdMaintenanceAdd(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 3 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "add")
+		return
+	}
+
+	window := maintenance.Window{
+		Scope:       args[0],
+		Target:      args[1],
+		Repeat:      ctx.String("repeat"),
+		Description: ctx.String("description"),
+	}
+	if window.Scope == maintenance.ScopeService {
+		serviceID, _, err := c.parseServiceInstance(window.Target)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		window.Target = serviceID
+	} else if window.Scope != maintenance.ScopeDeployment && window.Scope != maintenance.ScopeHost {
+		fmt.Fprintf(os.Stderr, "invalid scope %s; must be one of %s\n", window.Scope, strings.Join(maintenance.Scopes, ", "))
+		return
+	}
+
+	var err error
+	if window.Duration, err = time.ParseDuration(args[2]); err != nil || window.Duration <= 0 {
+		fmt.Fprintf(os.Stderr, "invalid duration %s\n", args[2])
+		return
+	}
+	if start := ctx.String("start"); start != "" {
+		if window.Start, err = time.Parse(time.RFC3339, start); err != nil {
+			if window.Start, err = time.ParseInLocation(maintenanceStartFormat, start, time.Local); err != nil {
+				fmt.Fprintf(os.Stderr, "invalid start time %s\n", start)
+				return
+			}
+		}
+	}
+
+	if windowID, err := c.driver.AddMaintenanceWindow(window); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else if windowID == "" {
+		fmt.Fprintln(os.Stderr, "received nil maintenance window")
+	} else {
+		fmt.Println(windowID)
+	}
+}
+
+// serviced maintenance remove WINDOWID ...
+func (c *ServicedCli) cmdMaintenanceRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	for _, windowID := range args {
+		if err := c.driver.RemoveMaintenanceWindow(windowID); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", windowID, err)
+		} else {
+			fmt.Println(windowID)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/domain/maintenance"
+	"github.com/control-center/serviced/utils"
+)
+
+var DefaultMaintenanceAPITest = MaintenanceAPITest{
+	ServiceAPITest: DefaultServiceAPITest,
+	windows:        DefaultTestMaintenanceWindows,
+}
+
+var DefaultTestMaintenanceWindows = []maintenance.Window{
+	{
+		ID:          "window-1",
+		Description: "os patches",
+		Scope:       maintenance.ScopeHost,
+		Target:      "test-host-id-1",
+		Start:       time.Date(2016, 8, 1, 2, 0, 0, 0, time.UTC),
+		Duration:    2 * time.Hour,
+	}, {
+		ID:       "window-2",
+		Scope:    maintenance.ScopeDeployment,
+		Target:   "Zenoss",
+		Start:    time.Date(2116, 8, 1, 2, 0, 0, 0, time.UTC),
+		Duration: 30 * time.Minute,
+		Repeat:   maintenance.RepeatWeekly,
+	},
+}
+
+var ErrNoMaintenanceWindow = errors.New("maintenance window not found")
+
+type MaintenanceAPITest struct {
+	ServiceAPITest
+	windows []maintenance.Window
+}
+
+func InitMaintenanceAPITest(args ...string) {
+	c := New(DefaultMaintenanceAPITest, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func (t MaintenanceAPITest) ListMaintenanceWindows() ([]maintenance.Window, error) {
+	return t.windows, nil
+}
+
+func (t MaintenanceAPITest) AddMaintenanceWindow(window maintenance.Window) (string, error) {
+	window.ID = window.Scope + "-" + window.Target
+	if window.Start.IsZero() {
+		window.Start = time.Now()
+	}
+	if err := window.ValidEntity(); err != nil {
+		return "", ErrStub
+	}
+	return window.ID, nil
+}
+
+func (t MaintenanceAPITest) RemoveMaintenanceWindow(windowID string) error {
+	for _, w := range t.windows {
+		if w.ID == windowID {
+			return nil
+		}
+	}
+	return ErrNoMaintenanceWindow
+}
+
+func ExampleServicedCLI_CmdMaintenanceList() {
+	InitMaintenanceAPITest("serviced", "maintenance", "list")
+
+	// Output:
+	// ID          Scope         Target            Start                   Duration    Repeat    Status       Description
+	// window-1    host          test-host-id-1    2016-08-01T02:00:00Z    2h0m0s      never     expired      os patches
+	// window-2    deployment    Zenoss            2116-08-01T02:00:00Z    30m0s       weekly    scheduled
+}
+
+func ExampleServicedCLI_CmdMaintenanceList_none() {
+	api := DefaultMaintenanceAPITest
+	api.windows = nil
+	c := New(api, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	pipeStderr(func(args ...string) { c.Run(args) }, "serviced", "maintenance", "list")
+
+	// Output:
+	// no maintenance windows found
+}
+
+func ExampleServicedCLI_CmdMaintenanceAdd() {
+	InitMaintenanceAPITest("serviced", "maintenance", "add", "--start", "2016-08-01 02:00", "--repeat", "daily", "service", "test-service-1", "1h")
+	InitMaintenanceAPITest("serviced", "maintenance", "add", "host", "test-host-id-1", "30m")
+
+	// Output:
+	// service-test-service-1
+	// host-test-host-id-1
+}
+
+func ExampleServicedCLI_CmdMaintenanceAdd_err() {
+	pipeStderr(InitMaintenanceAPITest, "serviced", "maintenance", "add", "pool", "default", "1h")
+	pipeStderr(InitMaintenanceAPITest, "serviced", "maintenance", "add", "host", "test-host-id-1", "soon")
+	pipeStderr(InitMaintenanceAPITest, "serviced", "maintenance", "add", "--start", "tomorrow", "host", "test-host-id-1", "1h")
+	pipeStderr(InitMaintenanceAPITest, "serviced", "maintenance", "add", "--repeat", "daily", "host", "test-host-id-1", "24h")
+
+	// Output:
+	// invalid scope pool; must be one of deployment, service, host
+	// invalid duration soon
+	// invalid start time tomorrow
+	// stub for facade failed
+}
+
+func ExampleServicedCLI_CmdMaintenanceRemove() {
+	InitMaintenanceAPITest("serviced", "maintenance", "remove", "window-1")
+	pipeStderr(InitMaintenanceAPITest, "serviced", "maintenance", "remove", "window-3")
+
+	// Output:
+	// window-1
+	// window-3: maintenance window not found
+}
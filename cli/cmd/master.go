@@ -0,0 +1,89 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/codegangsta/cli"
+)
+
+// Initializer for serviced master subcommands
+func (c *ServicedCli) initMaster() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "master",
+		Usage:       "Administers the serviced masters",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "ha-status",
+				Usage:       "Shows the failover state of each master",
+				Description: "serviced master ha-status",
+				Action:      c.cmdMasterHAStatus,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format, including state transitions",
+					},
+				},
+			},
+		},
+	})
+}
+
+// serviced master ha-status [--verbose]
+func (c *ServicedCli) cmdMasterHAStatus(ctx *cli.Context) {
+	status, err := c.driver.GetMasterHAStatus()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonStatus, err := c.marshalOutput(status); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal master ha status: %s", err)
+		} else {
+			fmt.Println(string(jsonStatus))
+		}
+		return
+	}
+
+	if status.Leader == "" {
+		fmt.Println("No master is running the internal services")
+	} else {
+		fmt.Printf("Master %s is running the internal services\n", status.Leader)
+	}
+	if len(status.Masters) == 0 {
+		return
+	}
+
+	fmt.Println()
+	t := NewTable("HostID,Leader,Since,State")
+	t.Padding = 6
+	for _, m := range status.Masters {
+		state := string(m.State)
+		if m.Error != "" {
+			state = fmt.Sprintf("%s: %s", m.State, m.Error)
+		}
+		t.AddRow(map[string]interface{}{
+			"HostID": m.HostID,
+			"Leader": m.HostID == status.Leader,
+			"Since":  m.Since.Format(time.RFC3339),
+			"State":  state,
+		})
+	}
+	t.Print()
+}
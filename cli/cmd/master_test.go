@@ -0,0 +1,80 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/utils"
+	"github.com/control-center/serviced/zzk/ha"
+)
+
+var haSince = time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+
+var DefaultMasterAPITest = MasterAPITest{
+	status: &ha.ClusterStatus{
+		Leader: "test-master-1",
+		Masters: []ha.Status{
+			{
+				HostID: "test-master-1",
+				State:  ha.StateActive,
+				Since:  haSince,
+			}, {
+				HostID: "test-master-2",
+				State:  ha.StateStandby,
+				Since:  haSince.Add(time.Minute),
+			}, {
+				HostID: "test-master-3",
+				State:  ha.StateFailed,
+				Since:  haSince.Add(2 * time.Minute),
+				Error:  "master lost the lead",
+			},
+		},
+	},
+}
+
+type MasterAPITest struct {
+	api.API
+	status *ha.ClusterStatus
+}
+
+func InitMasterAPITest(args ...string) {
+	New(DefaultMasterAPITest, utils.TestConfigReader(make(map[string]string))).Run(args)
+}
+
+func (t MasterAPITest) GetMasterHAStatus() (*ha.ClusterStatus, error) {
+	return t.status, nil
+}
+
+func ExampleServicedCLI_CmdMasterHAStatus() {
+	InitMasterAPITest("serviced", "master", "ha-status")
+
+	// Output:
+	// Master test-master-1 is running the internal services
+	//
+	// HostID             Leader      Since                     State
+	// test-master-1      true        2016-06-01T12:00:00Z      active
+	// test-master-2      false       2016-06-01T12:01:00Z      standby
+	// test-master-3      false       2016-06-01T12:02:00Z      failed: master lost the lead
+}
+
+func ExampleServicedCLI_CmdMasterHAStatus_noLeader() {
+	New(MasterAPITest{status: &ha.ClusterStatus{}}, utils.TestConfigReader(make(map[string]string))).Run([]string{"serviced", "master", "ha-status"})
+
+	// Output:
+	// No master is running the internal services
+}
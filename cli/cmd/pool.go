@@ -14,7 +14,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -38,7 +37,7 @@ func (c *ServicedCli) initPool() {
 				Description:  "serviced pool list [POOLID]",
 				BashComplete: c.printPoolsFirst,
 				Action:       c.cmdPoolList,
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					cli.BoolFlag{
 						Name:  "verbose, v",
 						Usage: "Show JSON format",
@@ -48,7 +47,7 @@ func (c *ServicedCli) initPool() {
 						Value: "ID",
 						Usage: "Comma-delimited list describing which fields to display",
 					},
-				},
+				}, tableFlags()...),
 			}, {
 				Name:  "add",
 				Usage: "Adds a new resource pool",
@@ -79,7 +78,7 @@ func (c *ServicedCli) initPool() {
 				Description:  "serviced pool list-ips POOLID",
 				BashComplete: c.printPoolsFirst,
 				Action:       c.cmdPoolListIPs,
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					cli.BoolFlag{
 						Name:  "verbose, v",
 						Usage: "Show JSON format",
@@ -89,6 +88,22 @@ func (c *ServicedCli) initPool() {
 						Value: "InterfaceName,IPAddress,Type",
 						Usage: "Comma-delimited list describing which fields to display",
 					},
+				}, tableFlags()...),
+			}, {
+				Name:         "port-report",
+				Usage:        "Lists the host ports claimed by services in a resource pool",
+				Description:  "serviced pool port-report POOLID",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdPoolPortReport,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.BoolFlag{
+						Name:  "conflicts",
+						Usage: "Only show ports claimed by more than one service",
+					},
 				},
 			}, {
 				Name:         "add-virtual-ip",
@@ -124,6 +139,48 @@ func (c *ServicedCli) initPool() {
 						Usage: "Control permission to use administrative functions",
 					},
 				},
+			}, {
+				Name:         "set-storage",
+				Usage:        "Set the transport that shares the distributed filesystem with hosts in a pool (e.g. nfs, cephfs, glusterfs)",
+				Description:  "serviced pool set-storage [--source SOURCE] [--options OPTIONS] POOLID TRANSPORT",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdSetStorage,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "source",
+						Value: "",
+						Usage: "Remote location of the application volumes (e.g. 10.0.0.1:6789:/serviced)",
+					},
+					cli.StringFlag{
+						Name:  "options",
+						Value: "",
+						Usage: "Comma separated mount options",
+					},
+				},
+			}, {
+				Name:         "set-macvlan",
+				Usage:        "Set the host interface that macvlan services in a pool attach to; omit PARENT to disable macvlan",
+				Description:  "serviced pool set-macvlan [--subnet SUBNET] [--gateway GATEWAY] POOLID [PARENT]",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdSetMacvlan,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "subnet",
+						Value: "",
+						Usage: "Subnet of the parent's network in CIDR notation (e.g. 10.0.0.0/24)",
+					},
+					cli.StringFlag{
+						Name:  "gateway",
+						Value: "",
+						Usage: "Gateway of the parent's network",
+					},
+				},
+			}, {
+				Name:         "set-scheduler",
+				Usage:        "Set who schedules the services in a pool: the master, or a delegate elected from the pool",
+				Description:  "serviced pool set-scheduler POOLID master|delegate",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdSetScheduler,
 			},
 		},
 	})
@@ -178,7 +235,7 @@ func (c *ServicedCli) cmdPoolList(ctx *cli.Context) {
 			fmt.Fprintln(os.Stderr, err)
 		} else if pool == nil {
 			fmt.Fprintln(os.Stderr, "pool not found")
-		} else if jsonPool, err := json.MarshalIndent(pool, " ", "  "); err != nil {
+		} else if jsonPool, err := c.marshalOutput(pool); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal resource pool: %s", err)
 		} else {
 			fmt.Println(string(jsonPool))
@@ -196,13 +253,17 @@ func (c *ServicedCli) cmdPoolList(ctx *cli.Context) {
 	}
 
 	if ctx.Bool("verbose") {
-		if jsonPool, err := json.MarshalIndent(pools, " ", "  "); err != nil {
+		if jsonPool, err := c.marshalOutput(pools); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal resource pool list: %s", err)
 		} else {
 			fmt.Println(string(jsonPool))
 		}
 	} else {
-		t := NewTable(ctx.String("show-fields"))
+		t, err := newTableFromContext(ctx, ctx.String("show-fields"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
 		t.Padding = 6
 		for _, p := range pools {
 			t.AddRow(map[string]interface{}{
@@ -299,13 +360,17 @@ func (c *ServicedCli) cmdPoolListIPs(ctx *cli.Context) {
 		fmt.Fprintln(os.Stderr, "no resource pool IPs found")
 		return
 	} else if ctx.Bool("verbose") {
-		if jsonPoolIP, err := json.MarshalIndent(poolIps.HostIPs, " ", "  "); err != nil {
+		if jsonPoolIP, err := c.marshalOutput(poolIps.HostIPs); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal resource pool IPs: %s", err)
 		} else {
 			fmt.Println(string(jsonPoolIP))
 		}
 	} else {
-		t := NewTable(ctx.String("show-fields"))
+		t, err := newTableFromContext(ctx, ctx.String("show-fields"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
 		for _, ip := range poolIps.HostIPs {
 			t.AddRow(map[string]interface{}{
 				"InterfaceName": ip.InterfaceName,
@@ -325,6 +390,65 @@ func (c *ServicedCli) cmdPoolListIPs(ctx *cli.Context) {
 	}
 }
 
+// serviced pool port-report POOLID
+func (c *ServicedCli) cmdPoolPortReport(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "port-report")
+		return
+	}
+
+	claims, err := c.driver.GetPoolPortClaims(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if ctx.Bool("conflicts") {
+		var conflicts []pool.PortClaim
+		for _, claim := range claims {
+			if claim.Conflict {
+				conflicts = append(conflicts, claim)
+			}
+		}
+		claims = conflicts
+	}
+
+	if len(claims) == 0 {
+		fmt.Fprintln(os.Stderr, "no host ports found")
+		return
+	} else if ctx.Bool("verbose") {
+		if jsonClaims, err := c.marshalOutput(claims); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal host ports: %s", err)
+		} else {
+			fmt.Println(string(jsonClaims))
+		}
+		return
+	}
+
+	t := NewTable("Address,Port,ServiceID,Name,Endpoint,Type,Conflict")
+	for _, claim := range claims {
+		addr, claimType := claim.IPAddr, "assigned"
+		if addr == "" {
+			addr = "*"
+		}
+		if claim.Public {
+			claimType = "public"
+		}
+		t.AddRow(map[string]interface{}{
+			"Address":   addr,
+			"Port":      fmt.Sprintf("%d/%s", claim.Port, claim.Protocol),
+			"ServiceID": claim.ServiceID,
+			"Name":      claim.ServiceName,
+			"Endpoint":  claim.EndpointName,
+			"Type":      claimType,
+			"Conflict":  claim.Conflict,
+		})
+	}
+	t.Padding = 6
+	t.Print()
+}
+
 // serviced pool add-virtual-ip POOLID IPADDRESS NETMASK BINDINTERFACE
 func (c *ServicedCli) cmdAddVirtualIP(ctx *cli.Context) {
 	args := ctx.Args()
@@ -436,3 +560,92 @@ func (c *ServicedCli) cmdSetPermission(ctx *cli.Context) {
 		return
 	}
 }
+
+// serviced pool set-storage [--source SOURCE] [--options OPTIONS] POOLID TRANSPORT
+func (c *ServicedCli) cmdSetStorage(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-storage")
+		return
+	}
+
+	p, err := c.driver.GetResourcePool(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if p == nil {
+		fmt.Fprintln(os.Stderr, "pool not found")
+		return
+	}
+
+	p.SharedStorage = pool.SharedStorage{
+		Transport: args[1],
+		Source:    ctx.String("source"),
+		Options:   ctx.String("options"),
+	}
+	if err := c.driver.UpdateResourcePool(*p); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+}
+
+// serviced pool set-macvlan [--subnet SUBNET] [--gateway GATEWAY] POOLID [PARENT]
+func (c *ServicedCli) cmdSetMacvlan(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-macvlan")
+		return
+	}
+
+	p, err := c.driver.GetResourcePool(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if p == nil {
+		fmt.Fprintln(os.Stderr, "pool not found")
+		return
+	}
+
+	p.Macvlan = pool.Macvlan{
+		Parent:  args.Get(1),
+		Subnet:  ctx.String("subnet"),
+		Gateway: ctx.String("gateway"),
+	}
+	if err := c.driver.UpdateResourcePool(*p); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+}
+
+// serviced pool set-scheduler POOLID master|delegate
+func (c *ServicedCli) cmdSetScheduler(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-scheduler")
+		return
+	}
+
+	scheduler := args[1]
+	if scheduler != pool.SchedulerMaster && scheduler != pool.SchedulerDelegate {
+		fmt.Fprintf(os.Stderr, "scheduler must be %s or %s\n", pool.SchedulerMaster, pool.SchedulerDelegate)
+		return
+	}
+
+	p, err := c.driver.GetResourcePool(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if p == nil {
+		fmt.Fprintln(os.Stderr, "pool not found")
+		return
+	}
+
+	p.Scheduler = scheduler
+	if err := c.driver.UpdateResourcePool(*p); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+}
@@ -16,6 +16,7 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -64,6 +65,34 @@ var DefaultTestHostIPs = []host.HostIPResource{
 	},
 }
 
+var DefaultTestPortClaims = []pool.PortClaim{
+	{
+		IPAddr:       "",
+		Port:         22,
+		Protocol:     "tcp",
+		ServiceID:    "test-service-1",
+		ServiceName:  "Zenoss",
+		EndpointName: "ssh",
+		Public:       true,
+		Conflict:     true,
+	}, {
+		IPAddr:       "192.168.0.1",
+		Port:         22,
+		Protocol:     "tcp",
+		ServiceID:    "test-service-2",
+		ServiceName:  "Zope",
+		EndpointName: "sshd",
+		Conflict:     true,
+	}, {
+		IPAddr:       "192.168.0.1",
+		Port:         514,
+		Protocol:     "udp",
+		ServiceID:    "test-service-3",
+		ServiceName:  "zensyslog",
+		EndpointName: "syslog",
+	},
+}
+
 var (
 	ErrNoPoolFound = errors.New("no pool found")
 	ErrInvalidPool = errors.New("invalid pool")
@@ -161,6 +190,19 @@ func (t PoolAPITest) GetPoolIPs(id string) (*pool.PoolIPs, error) {
 	return &pool.PoolIPs{PoolID: p.ID, HostIPs: t.hostIPs}, nil
 }
 
+func (t PoolAPITest) GetPoolPortClaims(id string) ([]pool.PortClaim, error) {
+	p, err := t.GetResourcePool(id)
+	if err != nil {
+		return nil, err
+	} else if p == nil {
+		return nil, ErrNoPoolFound
+	} else if id != "test-pool-id-1" {
+		return nil, nil
+	}
+
+	return DefaultTestPortClaims, nil
+}
+
 func (t PoolAPITest) UpdateResourcePool(pool pool.ResourcePool) error {
 	for i, p := range *t.pools {
 		if p.ID == pool.ID {
@@ -192,6 +234,25 @@ func TestServicedCLI_CmdPoolList_one(t *testing.T) {
 	}
 }
 
+func TestServicedCLI_CmdPoolList_yaml(t *testing.T) {
+	poolID := "test-pool-id-1"
+
+	test := DefaultPoolAPI()
+	expected, err := test.GetResourcePool(poolID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual pool.ResourcePool
+	output := pipeAPI(RunCmd, test, "serviced", "--output", "yaml", "pool", "list", poolID)
+	if err := api.DecodeData(bytes.NewReader(output), &actual); err != nil {
+		t.Fatalf("error unmarshalling resource: %s", err)
+	}
+	if !actual.Equals(expected) {
+		t.Fatalf("\ngot:\n%+v\nwant:\n%+v", actual, expected)
+	}
+}
+
 func TestServicedCLI_CmdPoolList_all(t *testing.T) {
 	test := DefaultPoolAPI()
 	expected, err := test.GetResourcePools()
@@ -395,8 +456,11 @@ func ExampleServicedCLI_CmdPoolListIPs_usage() {
 	//    serviced pool list-ips POOLID
 	//
 	// OPTIONS:
-	//    --verbose, -v				Show JSON format
-	//    --show-fields 'InterfaceName,IPAddress,Type'	Comma-delimited list describing which fields to display
+	//    --verbose, -v						Show JSON format
+	//    --show-fields 'InterfaceName,IPAddress,Type'			Comma-delimited list describing which fields to display
+	//    --add-column '--add-column option --add-column option'	Add a column computed from the fields of each row, e.g. 'Mem={{.RAM}}'
+	//    --max-width '--max-width option --max-width option'		Truncate the values of a field to a width, e.g. 'Name=20', or of every field, e.g. '20'
+	//    --no-header							Do not print the header row
 }
 
 func ExampleServicedCLI_CmdPoolListIPs_fail() {
@@ -415,6 +479,49 @@ func ExampleServicedCLI_CmdPoolListIPs_err() {
 	// no resource pool IPs found
 }
 
+func ExampleServicedCLI_CmdPoolPortReport() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "port-report", "test-pool-id-1")
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "port-report", "--conflicts", "test-pool-id-1")
+
+	// Output:
+	// Address          Port         ServiceID           Name           Endpoint      Type          Conflict
+	// *                22/tcp       test-service-1      Zenoss         ssh           public        true
+	// 192.168.0.1      22/tcp       test-service-2      Zope           sshd          assigned      true
+	// 192.168.0.1      514/udp      test-service-3      zensyslog      syslog        assigned      false
+	// Address          Port        ServiceID           Name        Endpoint      Type          Conflict
+	// *                22/tcp      test-service-1      Zenoss      ssh           public        true
+	// 192.168.0.1      22/tcp      test-service-2      Zope        sshd          assigned      true
+}
+
+func ExampleServicedCLI_CmdPoolPortReport_usage() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "port-report")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    port-report - Lists the host ports claimed by services in a resource pool
+	//
+	// USAGE:
+	//    command port-report [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced pool port-report POOLID
+	//
+	// OPTIONS:
+	//    --verbose, -v	Show JSON format
+	//    --conflicts		Only show ports claimed by more than one service
+}
+
+func ExampleServicedCLI_CmdPoolPortReport_fail() {
+	pipeAPIStderr(RunCmd, DefaultPoolAPI(), "serviced", "pool", "port-report", "test-pool-id-0")
+	pipeAPIStderr(RunCmd, DefaultPoolAPI(), "serviced", "pool", "port-report", "test-pool-id-2")
+
+	// Output:
+	// no pool found
+	// no host ports found
+}
+
 func TestServicedCLI_CmdPoolSetPermission(t *testing.T) {
 	test := EmptyPoolAPI()
 	assertPerm := func(poolID string, expected pool.Permission) {
@@ -447,3 +554,131 @@ func TestServicedCLI_CmdPoolSetPermission(t *testing.T) {
 	RunCmd(test, "serviced", "pool", "set-permission", "--admin", "--dfs=false", poolID)
 	assertPerm(poolID, pool.AdminAccess)
 }
+
+func TestServicedCLI_CmdPoolSetStorage(t *testing.T) {
+	test := EmptyPoolAPI()
+	poolID := "poolID"
+	RunCmd(test, "serviced", "pool", "add", poolID)
+	RunCmd(test, "serviced", "pool", "set-storage", "--source", "10.0.0.1:6789:/serviced", "--options", "name=admin", poolID, "cephfs")
+
+	expected := pool.SharedStorage{
+		Transport: "cephfs",
+		Source:    "10.0.0.1:6789:/serviced",
+		Options:   "name=admin",
+	}
+	if p, err := test.GetResourcePool(poolID); err != nil {
+		t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err)
+	} else if p.SharedStorage != expected {
+		t.Fatalf("Unexpected shared storage for %s: %+v != %+v", poolID, p.SharedStorage, expected)
+	}
+
+	RunCmd(test, "serviced", "pool", "set-storage", poolID, "nfs")
+	if p, err := test.GetResourcePool(poolID); err != nil {
+		t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err)
+	} else if !p.SharedStorage.IsNFS() || p.SharedStorage.Source != "" {
+		t.Fatalf("Unexpected shared storage for %s: %+v", poolID, p.SharedStorage)
+	}
+}
+
+func ExampleServicedCLI_CmdPoolSetStorage_usage() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "set-storage", "test-pool-id-1")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    set-storage - Set the transport that shares the distributed filesystem with hosts in a pool (e.g. nfs, cephfs, glusterfs)
+	//
+	// USAGE:
+	//    command set-storage [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced pool set-storage [--source SOURCE] [--options OPTIONS] POOLID TRANSPORT
+	//
+	// OPTIONS:
+	//    --source 	Remote location of the application volumes (e.g. 10.0.0.1:6789:/serviced)
+	//    --options 	Comma separated mount options
+}
+
+func TestServicedCLI_CmdPoolSetMacvlan(t *testing.T) {
+	test := EmptyPoolAPI()
+	poolID := "poolID"
+	RunCmd(test, "serviced", "pool", "add", poolID)
+	RunCmd(test, "serviced", "pool", "set-macvlan", "--subnet", "10.0.0.0/24", "--gateway", "10.0.0.1", poolID, "eth1")
+
+	expected := pool.Macvlan{
+		Parent:  "eth1",
+		Subnet:  "10.0.0.0/24",
+		Gateway: "10.0.0.1",
+	}
+	if p, err := test.GetResourcePool(poolID); err != nil {
+		t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err)
+	} else if p.Macvlan != expected {
+		t.Fatalf("Unexpected macvlan for %s: %+v != %+v", poolID, p.Macvlan, expected)
+	}
+
+	RunCmd(test, "serviced", "pool", "set-macvlan", poolID)
+	if p, err := test.GetResourcePool(poolID); err != nil {
+		t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err)
+	} else if p.Macvlan.Enabled() {
+		t.Fatalf("Unexpected macvlan for %s: %+v", poolID, p.Macvlan)
+	}
+}
+
+func ExampleServicedCLI_CmdPoolSetMacvlan_usage() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "set-macvlan")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    set-macvlan - Set the host interface that macvlan services in a pool attach to; omit PARENT to disable macvlan
+	//
+	// USAGE:
+	//    command set-macvlan [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced pool set-macvlan [--subnet SUBNET] [--gateway GATEWAY] POOLID [PARENT]
+	//
+	// OPTIONS:
+	//    --subnet 	Subnet of the parent's network in CIDR notation (e.g. 10.0.0.0/24)
+	//    --gateway 	Gateway of the parent's network
+}
+
+func TestServicedCLI_CmdPoolSetScheduler(t *testing.T) {
+	test := EmptyPoolAPI()
+	poolID := "poolID"
+	RunCmd(test, "serviced", "pool", "add", poolID)
+	RunCmd(test, "serviced", "pool", "set-scheduler", poolID, "delegate")
+
+	if p, err := test.GetResourcePool(poolID); err != nil {
+		t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err)
+	} else if !p.DelegatesScheduling() {
+		t.Fatalf("Expected %s to delegate its scheduling, got scheduler %q", poolID, p.Scheduler)
+	}
+
+	RunCmd(test, "serviced", "pool", "set-scheduler", poolID, "master")
+	if p, err := test.GetResourcePool(poolID); err != nil {
+		t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err)
+	} else if p.DelegatesScheduling() {
+		t.Fatalf("Expected %s to be scheduled by the master, got scheduler %q", poolID, p.Scheduler)
+	}
+}
+
+func ExampleServicedCLI_CmdPoolSetScheduler_usage() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "set-scheduler")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    set-scheduler - Set who schedules the services in a pool: the master, or a delegate elected from the pool
+	//
+	// USAGE:
+	//    command set-scheduler [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced pool set-scheduler POOLID master|delegate
+	//
+	// OPTIONS:
+}
@@ -14,7 +14,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
@@ -97,7 +96,7 @@ func cmdPublicEndpointsList(c *ServicedCli, ctx *cli.Context, showVHosts bool, s
 
 	// If we're generating JSON..
 	if ctx.Bool("verbose") {
-		if jsonOutput, err := json.MarshalIndent(publicEndpoints, " ", "  "); err != nil {
+		if jsonOutput, err := c.marshalOutput(publicEndpoints); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal public endoints: %s\n", err)
 		} else {
 			fmt.Println(string(jsonOutput))
@@ -124,7 +123,11 @@ func cmdPublicEndpointsList(c *ServicedCli, ctx *cli.Context, showVHosts bool, s
 	*/
 
 	cmdSetTreeCharset(ctx, c.config)
-	t := NewTable(ctx.String("show-fields"))
+	t, err := newTableFromContext(ctx, ctx.String("show-fields"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
 	t.IndentRow()
 
 	for _, pep := range publicEndpoints {
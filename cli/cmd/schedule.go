@@ -0,0 +1,189 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/schedule"
+)
+
+// Initializer for serviced schedule
+func (c *ServicedCli) initSchedule() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "schedule",
+		Usage:       "Administers schedule profiles",
+		Description: "Runs services only during scheduled windows",
+		Subcommands: []cli.Command{
+			{
+				Name:        "list",
+				Usage:       "Lists all schedule profiles and their upcoming transitions",
+				Description: "serviced schedule list",
+				Action:      c.cmdScheduleList,
+				Flags: append([]cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "ID,ServiceID,Start,Duration,Repeat,State,Next,Description",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				}, tableFlags()...),
+			}, {
+				Name:        "add",
+				Usage:       "Runs a service and its children only during scheduled windows",
+				Description: "serviced schedule add [--start TIME] [--repeat daily|weekly] SERVICEID DURATION",
+				Action:      c.cmdScheduleAdd,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "start",
+						Value: "",
+						Usage: "When the first window opens, as RFC3339 or local 'YYYY-MM-DD HH:MM' (default: now)",
+					},
+					cli.StringFlag{
+						Name:  "repeat",
+						Value: "",
+						Usage: "Repeat the window daily or weekly",
+					},
+					cli.StringFlag{
+						Name:  "description, d",
+						Value: "",
+						Usage: "Description of the schedule",
+					},
+				},
+			}, {
+				Name:        "remove",
+				ShortName:   "rm",
+				Usage:       "Removes an existing schedule profile",
+				Description: "serviced schedule remove PROFILEID ...",
+				Action:      c.cmdScheduleRemove,
+			},
+		},
+	})
+}
+
+// serviced schedule list [--verbose, -v]
+func (c *ServicedCli) cmdScheduleList(ctx *cli.Context) {
+	profiles, err := c.driver.ListScheduleProfiles()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(profiles) == 0 {
+		fmt.Fprintln(os.Stderr, "no schedule profiles found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonProfiles, err := c.marshalOutput(profiles); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal schedule profile list: %s", err)
+		} else {
+			fmt.Println(string(jsonProfiles))
+		}
+		return
+	}
+
+	t, err := newTableFromContext(ctx, ctx.String("show-fields"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	t.Padding = 4
+	now := time.Now()
+	for _, p := range profiles {
+		next := "none"
+		if transition, ok := p.NextTransition(now); ok {
+			next = transition.State + " at " + transition.At.Local().Format(maintenanceStartFormat)
+		}
+		repeat := p.Repeat
+		if repeat == "" {
+			repeat = "never"
+		}
+		t.AddRow(map[string]interface{}{
+			"ID":          p.ID,
+			"ServiceID":   p.ServiceID,
+			"Start":       p.Start.Local().Format(time.RFC3339),
+			"Duration":    p.Duration.String(),
+			"Repeat":      repeat,
+			"State":       p.State(now),
+			"Next":        next,
+			"Description": p.Description,
+		})
+	}
+	t.Print()
+}
+
+// serviced schedule add [--start TIME] [--repeat daily|weekly] [--description DESC] SERVICEID DURATION
+func (c *ServicedCli) cmdScheduleAdd(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "add")
+		return
+	}
+
+	serviceID, _, err := c.parseServiceInstance(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	profile := schedule.Profile{
+		ServiceID:   serviceID,
+		Repeat:      ctx.String("repeat"),
+		Description: ctx.String("description"),
+	}
+
+	if profile.Duration, err = time.ParseDuration(args[1]); err != nil || profile.Duration <= 0 {
+		fmt.Fprintf(os.Stderr, "invalid duration %s\n", args[1])
+		return
+	}
+	if start := ctx.String("start"); start != "" {
+		if profile.Start, err = time.Parse(time.RFC3339, start); err != nil {
+			if profile.Start, err = time.ParseInLocation(maintenanceStartFormat, start, time.Local); err != nil {
+				fmt.Fprintf(os.Stderr, "invalid start time %s\n", start)
+				return
+			}
+		}
+	}
+
+	if profileID, err := c.driver.AddScheduleProfile(profile); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else if profileID == "" {
+		fmt.Fprintln(os.Stderr, "received nil schedule profile")
+	} else {
+		fmt.Println(profileID)
+	}
+}
+
+// serviced schedule remove PROFILEID ...
+func (c *ServicedCli) cmdScheduleRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	for _, profileID := range args {
+		if err := c.driver.RemoveScheduleProfile(profileID); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", profileID, err)
+		} else {
+			fmt.Println(profileID)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/domain/schedule"
+	"github.com/control-center/serviced/utils"
+)
+
+var DefaultScheduleAPITest = ScheduleAPITest{
+	ServiceAPITest: DefaultServiceAPITest,
+	profiles:       DefaultTestScheduleProfiles,
+}
+
+var DefaultTestScheduleProfiles = []schedule.Profile{
+	{
+		ID:          "profile-1",
+		Description: "nightly reports",
+		ServiceID:   "test-service-1",
+		Start:       time.Date(2016, 8, 1, 22, 0, 0, 0, time.UTC),
+		Duration:    4 * time.Hour,
+	}, {
+		ID:        "profile-2",
+		ServiceID: "test-service-2",
+		Start:     time.Date(2116, 8, 1, 2, 0, 0, 0, time.UTC),
+		Duration:  30 * time.Minute,
+		Repeat:    schedule.RepeatWeekly,
+	},
+}
+
+var ErrNoScheduleProfile = errors.New("schedule profile not found")
+
+type ScheduleAPITest struct {
+	ServiceAPITest
+	profiles []schedule.Profile
+}
+
+func InitScheduleAPITest(args ...string) {
+	c := New(DefaultScheduleAPITest, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func (t ScheduleAPITest) ListScheduleProfiles() ([]schedule.Profile, error) {
+	return t.profiles, nil
+}
+
+func (t ScheduleAPITest) AddScheduleProfile(profile schedule.Profile) (string, error) {
+	profile.ID = "profile-" + profile.ServiceID
+	if profile.Start.IsZero() {
+		profile.Start = time.Now()
+	}
+	if err := profile.ValidEntity(); err != nil {
+		return "", ErrStub
+	}
+	return profile.ID, nil
+}
+
+func (t ScheduleAPITest) RemoveScheduleProfile(profileID string) error {
+	for _, p := range t.profiles {
+		if p.ID == profileID {
+			return nil
+		}
+	}
+	return ErrNoScheduleProfile
+}
+
+func ExampleServicedCLI_CmdScheduleList() {
+	InitScheduleAPITest("serviced", "schedule", "list")
+
+	// Output:
+	// ID           ServiceID         Start                   Duration    Repeat    State    Next                       Description
+	// profile-1    test-service-1    2016-08-01T22:00:00Z    4h0m0s      never     stop     none                       nightly reports
+	// profile-2    test-service-2    2116-08-01T02:00:00Z    30m0s       weekly    stop     run at 2116-08-01 02:00
+}
+
+func ExampleServicedCLI_CmdScheduleList_none() {
+	api := DefaultScheduleAPITest
+	api.profiles = nil
+	c := New(api, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	pipeStderr(func(args ...string) { c.Run(args) }, "serviced", "schedule", "list")
+
+	// Output:
+	// no schedule profiles found
+}
+
+func ExampleServicedCLI_CmdScheduleAdd() {
+	InitScheduleAPITest("serviced", "schedule", "add", "--start", "2016-08-01 22:00", "--repeat", "daily", "test-service-1", "4h")
+
+	// Output:
+	// profile-test-service-1
+}
+
+func ExampleServicedCLI_CmdScheduleAdd_err() {
+	pipeStderr(InitScheduleAPITest, "serviced", "schedule", "add", "test-service-1", "soon")
+	pipeStderr(InitScheduleAPITest, "serviced", "schedule", "add", "--start", "tonight", "test-service-1", "4h")
+	pipeStderr(InitScheduleAPITest, "serviced", "schedule", "add", "--repeat", "daily", "test-service-1", "24h")
+
+	// Output:
+	// invalid duration soon
+	// invalid start time tonight
+	// stub for facade failed
+}
+
+func ExampleServicedCLI_CmdScheduleRemove() {
+	InitScheduleAPITest("serviced", "schedule", "remove", "profile-1")
+	pipeStderr(InitScheduleAPITest, "serviced", "schedule", "remove", "profile-3")
+
+	// Output:
+	// profile-1
+	// profile-3: schedule profile not found
+}
@@ -14,6 +14,9 @@
 package cmd
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,13 +28,15 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
-	"text/template"
 	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/applicationendpoint"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/script"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -51,7 +56,7 @@ func (c *ServicedCli) initService() {
 				Description:  "serviced service list [SERVICEID]",
 				BashComplete: c.printServicesFirst,
 				Action:       c.cmdServiceList,
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					cli.BoolFlag{
 						Name:  "verbose, v",
 						Usage: "Show JSON format",
@@ -63,30 +68,52 @@ func (c *ServicedCli) initService() {
 					cli.StringFlag{
 						Name:  "format",
 						Value: "",
-						Usage: "format the output using the given go template",
+						Usage: "format the output using the given go template (see serviced help format-functions)",
 					},
 					cli.StringFlag{
 						Name:  "show-fields",
-						Value: "Name,ServiceID,Inst,ImageID,Pool,DState,Launch,DepID",
+						Value: "Name,ServiceID,Inst,ImageID,Pool,DState,Launch,DepID,Tenant",
 						Usage: "Comma-delimited list describing which fields to display",
 					},
-				},
+					selectorFlag(),
+				}, append(viewFlags(), tableFlags()...)...),
+			}, {
+				Name:        "fields",
+				Usage:       "Lists the fields accepted by --show-fields and --format",
+				Description: "serviced service fields",
+				Action:      c.cmdServiceFields,
 			}, {
 				Name:        "status",
 				Usage:       "Displays the status of deployed services",
 				Description: "serviced service status { SERVICEID | SERVICENAME | [POOL/]...PARENTNAME.../SERVICENAME }",
 				Action:      c.cmdServiceStatus,
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					cli.BoolFlag{
 						Name:  "ascii, a",
 						Usage: "use ascii characters for service tree (env SERVICED_TREE_ASCII=1 will default to ascii)",
 					},
 					cli.StringFlag{
 						Name:  "show-fields",
-						Value: "Name,ServiceID,Status,HC Fail,Healthcheck,Healthcheck Status,Uptime,RAM,Cur/Max/Avg,Hostname,InSync,DockerID",
+						Value: "Name,ServiceID,Status,Exit Reason,HC Fail,Healthcheck,Healthcheck Status,Maintenance,Uptime,RAM,Cur/Max/Avg,CPU,Hostname,InSync,DockerID",
 						Usage: "Comma-delimited list describing which fields to display",
 					},
-				},
+					colorFlag(),
+					cli.BoolFlag{
+						Name:  "watch, w",
+						Usage: "Refresh the status in place as it changes, highlighting the rows that changed",
+					},
+					cli.StringFlag{
+						Name:  "interval",
+						Value: "30s",
+						Usage: "Time between full refreshes when watching",
+					},
+					cli.IntFlag{
+						Name:  "count",
+						Value: 0,
+						Usage: "Number of times to refresh when watching; 0 refreshes until interrupted",
+					},
+					selectorFlag(),
+				}, append(viewFlags(), tableFlags()...)...),
 			}, {
 				Name:        "add",
 				Usage:       "Adds a new service",
@@ -120,6 +147,25 @@ func (c *ServicedCli) initService() {
 						Value: "",
 						Usage: "name to append to service name, volumes, endpoints",
 					},
+					cli.StringFlag{
+						Name:  "deployment-id",
+						Value: "",
+						Usage: "clone the whole tenant into a new deployment with this ID",
+					},
+					cli.StringFlag{
+						Name:  "pool",
+						Value: "",
+						Usage: "pool of the cloned deployment (default: the pool of the tenant)",
+					},
+					cli.StringFlag{
+						Name:  "snapshot",
+						Value: "",
+						Usage: "snapshot of the tenant to seed the volume of the cloned deployment",
+					},
+					cli.BoolFlag{
+						Name:  "enable-public-endpoints",
+						Usage: "keep the public endpoints of the cloned deployment enabled",
+					},
 				},
 			}, {
 				Name:         "remove",
@@ -141,16 +187,42 @@ func (c *ServicedCli) initService() {
 						Usage: "Editor used to update the service definition",
 					},
 				},
+			}, {
+				Name:         "tag",
+				Usage:        "Adds tags to a service",
+				Description:  "serviced service tag SERVICEID TAG ...",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceTag,
+			}, {
+				Name:         "untag",
+				Usage:        "Removes tags from a service",
+				Description:  "serviced service untag SERVICEID TAG ...",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceUntag,
 			}, {
 				Name:         "assign-ip",
 				Usage:        "Assigns an IP address to a service's endpoints requiring an explicit IP address",
-				Description:  "serviced service assign-ip SERVICEID [IPADDRESS]",
+				Description:  "serviced service assign-ip { SERVICEID [IPADDRESS] | --rebalance POOLID | --all [--dry-run] DEPLOYMENTID }",
 				BashComplete: c.printServicesFirst,
 				Action:       c.cmdServiceAssignIP,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "rebalance",
+						Usage: "Reassign the addresses of a pool that point to removed or inactive hosts",
+					},
+					cli.BoolFlag{
+						Name:  "all",
+						Usage: "Assign addresses to every endpoint of a deployment that requires one",
+					},
+					cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Report the current and proposed assignments without applying them (requires --all)",
+					},
+				},
 			}, {
 				Name:         "start",
 				Usage:        "Starts a service",
-				Description:  "serviced service start SERVICEID",
+				Description:  "serviced service start { SERVICEID ... | --filter GLOB | --selector SELECTOR }",
 				BashComplete: c.printServicesFirst,
 				Action:       c.cmdServiceStart,
 				Flags: []cli.Flag{
@@ -158,11 +230,13 @@ func (c *ServicedCli) initService() {
 						Name:  "auto-launch",
 						Usage: "Recursively schedules child services",
 					},
+					selectorFlag(),
+					filterFlag(),
 				},
 			}, {
 				Name:         "restart",
 				Usage:        "Restarts a service",
-				Description:  "serviced service restart { SERVICEID | INSTANCEID }",
+				Description:  "serviced service restart { SERVICEID ... | INSTANCEID | --filter GLOB | --selector SELECTOR }",
 				BashComplete: c.printServicesFirst,
 				Action:       c.cmdServiceRestart,
 				Flags: []cli.Flag{
@@ -170,11 +244,13 @@ func (c *ServicedCli) initService() {
 						Name:  "auto-launch",
 						Usage: "Recursively schedules child services",
 					},
+					selectorFlag(),
+					filterFlag(),
 				},
 			}, {
 				Name:         "stop",
 				Usage:        "Stops a service",
-				Description:  "serviced service stop SERVICEID",
+				Description:  "serviced service stop { SERVICEID ... | --filter GLOB | --selector SELECTOR }",
 				BashComplete: c.printServicesFirst,
 				Action:       c.cmdServiceStop,
 				Flags: []cli.Flag{
@@ -182,7 +258,81 @@ func (c *ServicedCli) initService() {
 						Name:  "auto-launch",
 						Usage: "Recursively schedules child services",
 					},
+					selectorFlag(),
+					filterFlag(),
+				},
+			}, {
+				Name:         "pause",
+				Usage:        "Pauses a running service without stopping its containers",
+				Description:  "serviced service pause SERVICEID",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServicePause,
+				Flags: []cli.Flag{
+					cli.BoolTFlag{
+						Name:  "auto-launch",
+						Usage: "Recursively schedules child services",
+					},
+				},
+			}, {
+				Name:         "resume",
+				Usage:        "Resumes a paused service",
+				Description:  "serviced service resume SERVICEID",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceResume,
+				Flags: []cli.Flag{
+					cli.BoolTFlag{
+						Name:  "auto-launch",
+						Usage: "Recursively schedules child services",
+					},
+				},
+			}, {
+				Name:         "wait",
+				Usage:        "Waits for services to reach a desired state",
+				Description:  "serviced service wait [--state STATE] [--timeout DURATION] { SERVICEID ... | --filter GLOB | --selector SELECTOR }",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceWait,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "state",
+						Value: "started",
+						Usage: "State to wait for: started, stopped or paused",
+					},
+					cli.StringFlag{
+						Name:  "timeout",
+						Value: "5m",
+						Usage: "Time to wait for the services to reach the state",
+					},
+					cli.BoolFlag{
+						Name:  "recursive, r",
+						Usage: "Also wait for the child services",
+					},
+					selectorFlag(),
+					filterFlag(),
+				},
+			}, {
+				Name:         "clear-emergency",
+				Usage:        "Allows services stopped by an emergency shutdown to start",
+				Description:  "serviced service clear-emergency SERVICEID",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceClearEmergency,
+			}, {
+				Name:         "history",
+				Usage:        "Shows who and what changed the desired state of a service",
+				Description:  "serviced service history SERVICEID",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceHistory,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
 				},
+			}, {
+				Name:         "set-priority",
+				Usage:        "Sets the priority class used to order shutdown and startup",
+				Description:  "serviced service set-priority SERVICEID PRIORITY (" + strings.Join(service.Priorities, ", ") + ")",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceSetPriority,
 			}, {
 				Name:         "shell",
 				Usage:        "Starts a service instance",
@@ -245,6 +395,33 @@ func (c *ServicedCli) initService() {
 						Usage: "container username used to run command",
 					},
 				},
+			}, {
+				Name:         "migrate",
+				Usage:        "Runs a migration script against a service and its children in a sandboxed container",
+				Description:  "serviced service migrate [--dry-run] SERVICEID SCRIPT",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceMigrate,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Validate and print the proposed changes without applying them",
+					},
+					cli.StringFlag{
+						Name:  "memory",
+						Value: "512m",
+						Usage: "Memory limit of the migration container",
+					},
+					cli.IntFlag{
+						Name:  "cpu-shares",
+						Value: 512,
+						Usage: "CPU shares of the migration container",
+					},
+					cli.StringFlag{
+						Name:  "timeout",
+						Value: "10m",
+						Usage: "Time to wait for the migration script to finish",
+					},
+				},
 			}, {
 				Name:         "attach",
 				Usage:        "Run an arbitrary command in a running service container",
@@ -257,12 +434,81 @@ func (c *ServicedCli) initService() {
 				Description:  "serviced service action { SERVICEID | SERVICENAME | DOCKERID | POOL/...PARENTNAME.../SERVICENAME/INSTANCE } ACTION",
 				BashComplete: c.printServicesFirst,
 				Before:       c.cmdServiceAction,
+			}, {
+				Name:         "diagnostics",
+				Usage:        "Runs the built-in diagnostic actions in a running service container and gathers their output",
+				Description:  "serviced service diagnostics [--action ACTION] [--wait DURATION] [--out FILE] { SERVICEID | SERVICENAME | DOCKERID | POOL/...PARENTNAME.../SERVICENAME/INSTANCE }",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceDiagnostics,
+				Flags: []cli.Flag{
+					cli.StringSliceFlag{
+						Name:  "action",
+						Value: &cli.StringSlice{},
+						Usage: "Diagnostic action to run (default all): " + strings.Join(service.DiagnosticActionNames(), ", "),
+					},
+					cli.StringFlag{
+						Name:  "wait",
+						Value: "10s",
+						Usage: "Time to wait for the actions to finish before gathering their output; 0 only gathers the output of earlier runs",
+					},
+					cli.StringFlag{
+						Name:  "out",
+						Value: "",
+						Usage: "Write the output to a support bundle (.tgz) instead of stdout",
+					},
+				},
+			}, {
+				Name:         "commit",
+				Usage:        "Commits a running service container to the registry and snapshots its tenant",
+				Description:  "serviced service commit { SERVICEID | SERVICENAME | DOCKERID | POOL/...PARENTNAME.../SERVICENAME/INSTANCE }",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceCommit,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "message, m",
+						Value: "",
+						Usage: "a description of the commit",
+					},
+				},
 			}, {
 				Name:         "logs",
 				Usage:        "Output the logs of a running service container - calls docker logs",
-				Description:  "serviced service logs { SERVICEID | SERVICENAME | DOCKERID | POOL/...PARENTNAME.../SERVICENAME/INSTANCE }",
+				Description:  "serviced service logs [--follow] [--since SINCE] [--tail LINES] { SERVICEID | SERVICENAME | DOCKERID | POOL/...PARENTNAME.../SERVICENAME/INSTANCE | --selector SELECTOR }",
 				BashComplete: c.printServicesFirst,
 				Before:       c.cmdServiceLogs,
+				Flags: []cli.Flag{
+					selectorFlag(),
+					cli.BoolFlag{
+						Name:  "previous",
+						Usage: "Output the buffered stdout and stderr of the instance's most recently exited container",
+					},
+					cli.BoolFlag{
+						Name:  "follow, f",
+						Usage: "Stream the logs of every running instance of the service, or of the given instance, prefixing each line with its instance",
+					},
+					cli.StringFlag{
+						Name:  "since",
+						Value: "",
+						Usage: "Only output lines written after a time (RFC3339) or within a duration (e.g. 10m)",
+					},
+					cli.IntFlag{
+						Name:  "tail",
+						Value: 0,
+						Usage: "Only output the last lines of each instance; all lines if 0",
+					},
+				},
+			}, {
+				Name:         "diff",
+				Usage:        "Shows how a service differs from a snapshot or from another service",
+				Description:  "serviced service diff [--patch] SERVICEID [SNAPSHOTID | TAG | OTHER_SERVICEID]",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceDiff,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "patch",
+						Usage: "Print the changes as a JSON patch instead of a unified diff",
+					},
+				},
 			}, {
 				Name:         "list-snapshots",
 				Usage:        "Lists the snapshots for a service",
@@ -310,7 +556,11 @@ func (c *ServicedCli) initService() {
 					},
 					cli.BoolFlag{
 						Name:  "verify, v",
-						Usage: "verify endpoints",
+						Usage: "verify that exports are listening, imports resolve, and the mux forwards connections",
+					},
+					cli.BoolFlag{
+						Name:  "stats, s",
+						Usage: "show the connections the mux has forwarded to each exported endpoint",
 					},
 				},
 			}, {
@@ -323,7 +573,7 @@ func (c *ServicedCli) initService() {
 						Usage:       "Lists public endpoints for a service",
 						Description: "serviced service public-endpoints list [SERVICEID] [ENDPOINTNAME]",
 						Action:      c.cmdPublicEndpointsListAll,
-						Flags: []cli.Flag{
+						Flags: append([]cli.Flag{
 							cli.BoolFlag{
 								Name:  "ascii, a",
 								Usage: "use ascii characters for service tree (env SERVICED_TREE_ASCII=1 will default to ascii)",
@@ -345,7 +595,7 @@ func (c *ServicedCli) initService() {
 								Name:  "verbose, v",
 								Usage: "Show JSON format",
 							},
-						},
+						}, tableFlags()...),
 					},
 					{
 						Name:        "port",
@@ -357,7 +607,7 @@ func (c *ServicedCli) initService() {
 								Usage:       "List port public endpoints for a service",
 								Description: "serviced service public-endpoints port list [SERVICEID] [ENDPOINTNAME]",
 								Action:      c.cmdPublicEndpointsPortList,
-								Flags: []cli.Flag{
+								Flags: append([]cli.Flag{
 									cli.BoolFlag{
 										Name:  "ascii, a",
 										Usage: "use ascii characters for service tree (env SERVICED_TREE_ASCII=1 will default to ascii)",
@@ -371,7 +621,7 @@ func (c *ServicedCli) initService() {
 										Name:  "verbose, v",
 										Usage: "Show JSON format",
 									},
-								},
+								}, tableFlags()...),
 							},
 							{
 								Name:        "add",
@@ -410,7 +660,7 @@ func (c *ServicedCli) initService() {
 								Usage:       "List vhost public endpoints for a service",
 								Description: "serviced service public-endpoints vhost list [SERVICEID] [ENDPOINTNAME]",
 								Action:      c.cmdPublicEndpointsVHostList,
-								Flags: []cli.Flag{
+								Flags: append([]cli.Flag{
 									cli.BoolFlag{
 										Name:  "ascii, a",
 										Usage: "use ascii characters for service tree (env SERVICED_TREE_ASCII=1 will default to ascii)",
@@ -424,7 +674,7 @@ func (c *ServicedCli) initService() {
 										Name:  "verbose, v",
 										Usage: "Show JSON format",
 									},
-								},
+								}, tableFlags()...),
 							},
 							{
 								Name:        "add",
@@ -448,6 +698,71 @@ func (c *ServicedCli) initService() {
 						},
 					},
 				},
+			}, {
+				Name:        "config-conflicts",
+				Usage:       "Manage config files changed by both the user and a redeploy",
+				Description: "serviced service config-conflicts",
+				Subcommands: []cli.Command{
+					{
+						Name:         "list",
+						Usage:        "Lists the conflicting config files of a service",
+						Description:  "serviced service config-conflicts list SERVICEID",
+						BashComplete: c.printServicesFirst,
+						Action:       c.cmdConfigConflictsList,
+						Flags: append([]cli.Flag{
+							cli.StringFlag{
+								Name:  "show-fields",
+								Value: "ID,Filename,Merge",
+								Usage: "Comma-delimited list describing which fields to display",
+							},
+						}, tableFlags()...),
+					}, {
+						Name:         "show",
+						Usage:        "Shows a three-way merge of a conflicting config file",
+						Description:  "serviced service config-conflicts show SERVICEID FILENAME",
+						BashComplete: c.printServicesFirst,
+						Action:       c.cmdConfigConflictsShow,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "base",
+								Usage: "Show the file in the previous template",
+							},
+							cli.BoolFlag{
+								Name:  "mine",
+								Usage: "Show the current file",
+							},
+							cli.BoolFlag{
+								Name:  "theirs",
+								Usage: "Show the file in the new template",
+							},
+						},
+					}, {
+						Name:         "resolve",
+						Usage:        "Resolves a conflicting config file",
+						Description:  "serviced service config-conflicts resolve {--mine | --theirs | --merged | --file PATH} SERVICEID FILENAME",
+						BashComplete: c.printServicesFirst,
+						Action:       c.cmdConfigConflictsResolve,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "mine",
+								Usage: "Keep the current file",
+							},
+							cli.BoolFlag{
+								Name:  "theirs",
+								Usage: "Take the file in the new template",
+							},
+							cli.BoolFlag{
+								Name:  "merged",
+								Usage: "Take the three-way merge, if it is clean",
+							},
+							cli.StringFlag{
+								Name:  "file",
+								Value: "",
+								Usage: "Replace the file with the contents of PATH, e.g. a merge resolved by hand",
+							},
+						},
+					},
+				},
 			},
 		},
 	})
@@ -498,6 +813,11 @@ func (c *ServicedCli) serviceActions(id string) (data []string) {
 		data[i] = a
 		i++
 	}
+	for _, a := range service.DiagnosticActionNames() {
+		if _, ok := svc.Actions[a]; !ok {
+			data = append(data, a)
+		}
+	}
 
 	return
 }
@@ -624,54 +944,101 @@ func (c *ServicedCli) buildServicePaths(svcs []service.Service) (map[string]stri
 	return pathmap, nil
 }
 
+// serviceTenants maps the id of each service to the name of its tenant
+func serviceTenants(svcs []service.Service) map[string]string {
+	svcMap := make(map[string]service.Service)
+	for _, svc := range svcs {
+		svcMap[svc.ID] = svc
+	}
+	tenants := make(map[string]string)
+	for _, svc := range svcs {
+		tenant := svc
+		for i := 0; tenant.ParentServiceID != "" && i < len(svcs); i++ {
+			parent, ok := svcMap[tenant.ParentServiceID]
+			if !ok {
+				break
+			}
+			tenant = parent
+		}
+		tenants[svc.ID] = tenant.Name
+	}
+	return tenants
+}
+
+// serviceSearchLimit is the maximum number of matches listed when a service
+// keyword is ambiguous
+const serviceSearchLimit = 100
+
 // searches for service from definitions given keyword
 func (c *ServicedCli) searchForService(keyword string) (*service.Service, error) {
-	svcs, err := c.driver.GetServices()
-	if err != nil {
-		return nil, err
+	// is the keyword a service id?
+	if keyword != "" {
+		if svc, _ := c.driver.GetService(keyword); svc != nil {
+			return svc, nil
+		}
 	}
 
-	pathmap, err := c.buildServicePaths(svcs)
-	if err != nil {
-		return nil, err
+	// match the keyword against the end of the service path, or against
+	// POOL/PATH
+	queries := []service.ServiceQuery{{PathSuffix: keyword, Limit: serviceSearchLimit}}
+	if parts := strings.SplitN(keyword, "/", 2); len(parts) == 2 {
+		queries = append(queries, service.ServiceQuery{PoolID: parts[0], PathSuffix: "/" + parts[1], Limit: serviceSearchLimit})
 	}
 
-	var services []service.Service
-	for _, svc := range svcs {
-		poolPath := path.Join(strings.ToLower(svc.PoolID), pathmap[svc.ID])
-		switch strings.ToLower(keyword) {
-		case svc.ID, strings.ToLower(svc.Name), pathmap[svc.ID], poolPath:
-			services = append(services, svc)
-		default:
-			if keyword == "" {
-				services = append(services, svc)
-			} else if strings.HasSuffix(pathmap[svc.ID], strings.ToLower(keyword)) {
-				services = append(services, svc)
+	var matches []service.ServiceSummary
+	hidden := 0
+	found := make(map[string]struct{})
+	for _, query := range queries {
+		page, err := c.driver.QueryServices(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range page.Services {
+			if _, ok := found[summary.ID]; !ok {
+				found[summary.ID] = struct{}{}
+				matches = append(matches, summary)
 			}
 		}
-	}
-
-	switch len(services) {
-	case 0:
-		return nil, fmt.Errorf("service not found")
-	case 1:
-		return &services[0], nil
+		hidden += page.Total - len(page.Services)
+	}
+
+	if hidden == 0 {
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("service not found")
+		case 1:
+			svc, err := c.driver.GetService(matches[0].ID)
+			if err != nil {
+				return nil, err
+			} else if svc == nil {
+				return nil, fmt.Errorf("service not found")
+			}
+			return svc, nil
+		}
 	}
 
 	t := NewTable("Name,ServiceID,DepID,Pool/Path")
 	t.Padding = 6
-	for _, row := range services {
+	for _, row := range matches {
 		t.AddRow(map[string]interface{}{
 			"Name":      row.Name,
 			"ServiceID": row.ID,
 			"DepID":     row.DeploymentID,
-			"Pool/Path": path.Join(row.PoolID, pathmap[row.ID]),
+			"Pool/Path": path.Join(row.PoolID, row.Path),
 		})
 	}
 	t.Print()
+	printHiddenMatches(hidden)
 	return nil, fmt.Errorf("multiple results found; select one from list")
 }
 
+// printHiddenMatches reports the number of matches left out of a listing
+func printHiddenMatches(hidden int) {
+	if hidden > 0 {
+		fmt.Printf("... and %d more\n", hidden)
+	}
+}
+
 // cmdSetTreeCharset sets the default behavior for --ASCII, SERVICED_TREE_ASCII, and stdout pipe
 func cmdSetTreeCharset(ctx *cli.Context, config utils.ConfigReader) {
 	if ctx.Bool("ascii") {
@@ -704,65 +1071,12 @@ func (c *ServicedCli) parseServiceInstance(keyword string) (string, int, error)
 		}
 	}
 
-	// is the servicepath a serviceid?
-	if svc, _ := c.driver.GetService(servicepath); svc != nil {
-		return svc.ID, instanceID, nil
-	}
-
-	// try to figure out what service this is
-	// FIXME: this is really expensive
-	svcs, err := c.driver.GetServices()
+	// resolve the service id or path on the master
+	matches, err := c.driver.ResolveServicePath(servicepath)
 	if err != nil {
 		return "", 0, err
 	}
 
-	// set up a service map
-	svcmap := make(map[string]service.Service)
-	for _, svc := range svcs {
-		svcmap[svc.ID] = svc
-	}
-
-	// do a service lookup for the service path
-	var match func(string, string) bool
-	match = func(serviceID string, servicePath string) bool {
-		// if the servicePath is an empty string, then it is a match
-		if servicePath == "" {
-			return true
-		}
-
-		// get the service
-		svc := svcmap[serviceID]
-		serviceName := strings.ToLower(svc.Name)
-		deploymentID := strings.ToLower(svc.DeploymentID)
-
-		// split the service path and the name
-		pth, name := path.Split(servicePath)
-		pth = strings.TrimRight(pth, "/")
-
-		// does the name match?
-		if name == serviceName {
-			if svc.ParentServiceID == "" {
-				// this is a top level service, so compare the deployment id
-				return strings.HasSuffix(deploymentID, pth)
-			} else {
-				// keep checking the tree
-				return match(svc.ParentServiceID, pth)
-			}
-		}
-
-		// do a fuzzy check on the suffix
-		return strings.HasSuffix(serviceName, servicePath)
-	}
-
-	// filter all of the matches
-	servicepath = strings.ToLower(servicepath)
-	matches := []service.Service{}
-	for _, svc := range svcs {
-		if match(svc.ID, servicepath) {
-			matches = append(matches, svc)
-		}
-	}
-
 	// check the number of matches
 	if count := len(matches); count == 0 {
 		return "", 0, errors.New("service not found")
@@ -771,24 +1085,13 @@ func (c *ServicedCli) parseServiceInstance(keyword string) (string, int, error)
 	}
 
 	// more than one match, display a dialog
-	var svcpath func(string) string
-	svcpath = func(serviceID string) string {
-		svc := svcmap[serviceID]
-		if svc.ParentServiceID == "" {
-			return path.Join(svc.DeploymentID, svc.Name)
-		} else {
-			return path.Join(svcpath(svc.ParentServiceID), svc.Name)
-		}
-	}
-
 	t := NewTable("Name,ServiceID,DepID/Path")
 	t.Padding = 6
 	for _, row := range matches {
 		t.AddRow(map[string]interface{}{
 			"Name":       row.Name,
 			"ServiceID":  row.ID,
-			"PoolID":     row.PoolID,
-			"DepID/Path": svcpath(row.ID),
+			"DepID/Path": row.DeploymentPath(),
 		})
 	}
 	t.Print()
@@ -798,7 +1101,12 @@ func (c *ServicedCli) parseServiceInstance(keyword string) (string, int, error)
 // serviced service status
 func (c *ServicedCli) cmdServiceStatus(ctx *cli.Context) {
 	var states map[string]map[string]interface{}
-	var err error
+
+	view, err := c.viewFromContext(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
 
 	//Determine whether to show healthcheck fields and rows based on user input:
 	//   By default, we only show individual healthcheck rows if a specific service is requested
@@ -806,7 +1114,9 @@ func (c *ServicedCli) cmdServiceStatus(ctx *cli.Context) {
 	showIndividualHealthChecks := false       //whether or not to add rows to the table for individual health checks.
 	fieldsToShow := ctx.String("show-fields") //we will modify this if not user-set
 
-	if !ctx.IsSet("show-fields") {
+	if view.Fields != "" {
+		fieldsToShow = view.Fields
+	} else {
 		//only show the appropriate health fields based on arguments
 		if len(ctx.Args()) > 0 { //don't show "HC Fail"
 			fieldsToShow = strings.Replace(fieldsToShow, "HC Fail,", "", -1)
@@ -824,26 +1134,79 @@ func (c *ServicedCli) cmdServiceStatus(ctx *cli.Context) {
 	//set showIndividualHealthChecks based on the fields
 	showIndividualHealthChecks = strings.Contains(fieldsToShow, "Healthcheck") || strings.Contains(fieldsToShow, "Healthcheck Status")
 
-	if len(ctx.Args()) > 0 {
-		serviceID, _, err := c.parseServiceInstance(ctx.Args().First())
+	var serviceID string
+	var selected map[string]bool
+	if view.Selector != "" {
+		svcs, err := c.searchForServicesBySelector(view.Selector)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return
 		}
-		if states, err = c.driver.GetServiceStatus(serviceID); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+		selected = make(map[string]bool)
+		for _, svc := range svcs {
+			selected[svc.ID] = true
 		}
-	} else {
-		if states, err = c.driver.GetServiceStatus(""); err != nil {
+	} else if len(ctx.Args()) > 0 {
+		if serviceID, _, err = c.parseServiceInstance(ctx.Args().First()); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return
 		}
 	}
+	if states, err = c.driver.GetServiceStatus(serviceID); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	states = filterServiceStatus(states, selected)
 
 	cmdSetTreeCharset(ctx, c.config)
+	cmdSetTableColor(ctx, c.config)
+
+	if !ctx.Bool("watch") {
+		if err := c.printServiceStatus(ctx, states, nil, fieldsToShow, showIndividualHealthChecks, view.Flat); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return
+	}
+
+	interval, err := time.ParseDuration(ctx.String("interval"))
+	if err != nil || interval <= 0 {
+		fmt.Fprintf(os.Stderr, "invalid interval %s\n", ctx.String("interval"))
+		return
+	}
+	clearScreen := utils.Isatty(os.Stdout)
+	count := ctx.Int("count")
+	var changed map[string]bool
+	for i := 1; ; i++ {
+		if clearScreen {
+			fmt.Print("\x1b[H\x1b[2J")
+		}
+		if err := c.printServiceStatus(ctx, states, changed, fieldsToShow, showIndividualHealthChecks, view.Flat); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		if count > 0 && i >= count {
+			return
+		}
+
+		next, err := c.refreshServiceStatus(serviceID, states, interval)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		next = filterServiceStatus(next, selected)
+		changed = diffServiceStatus(states, next)
+		states = next
+	}
+}
 
-	t := NewTable(fieldsToShow)
+// printServiceStatus prints the service status tree, or a flat list if
+// requested, highlighting the rows that changed since the last refresh.
+func (c *ServicedCli) printServiceStatus(ctx *cli.Context, states map[string]map[string]interface{}, changed map[string]bool, fieldsToShow string, showIndividualHealthChecks, flat bool) error {
+	t, err := newTableFromContext(ctx, fieldsToShow)
+	if err != nil {
+		return err
+	}
+	t.StatusFields = []string{"Status", "HC Fail", "Healthcheck Status"}
 	childmap := make(map[string][]string)
 	for id, state := range states {
 		parent := fmt.Sprintf("%v", state["ParentID"])
@@ -855,12 +1218,17 @@ func (c *ServicedCli) cmdServiceStatus(ctx *cli.Context) {
 		rows := childmap[root]
 		if len(rows) > 0 {
 			sort.Strings(rows)
-			t.IndentRow()
-			defer t.DedentRow()
+			if !flat {
+				t.IndentRow()
+				defer t.DedentRow()
+			}
 			for _, rowid := range childmap[root] {
 				row := states[rowid]
 				if _, ok := row["Healthcheck"]; !ok || showIndividualHealthChecks { //if this is a healthcheck row, only include it if showIndividualHealthChecks is true
 					t.AddRow(row)
+					if changed[rowid] {
+						t.HighlightRow()
+					}
 				}
 
 				nextRoot := rowid
@@ -871,7 +1239,109 @@ func (c *ServicedCli) cmdServiceStatus(ctx *cli.Context) {
 	addRows("")
 	t.Padding = 3
 	t.Print()
-	return
+	return nil
+}
+
+// refreshServiceStatus waits for the instances of the services in the status
+// tree to change and refetches the status of only the services that changed.
+// If nothing changes within the interval, or if the master cannot report
+// changes, the whole tree is refetched.
+func (c *ServicedCli) refreshServiceStatus(serviceID string, states map[string]map[string]interface{}, interval time.Duration) (map[string]map[string]interface{}, error) {
+	idmap := make(map[string]struct{})
+	for _, row := range states {
+		if id, ok := row["ServiceID"].(string); ok {
+			idmap[id] = struct{}{}
+		}
+	}
+	serviceIDs := make([]string, 0, len(idmap))
+	for id := range idmap {
+		serviceIDs = append(serviceIDs, id)
+	}
+	sort.Strings(serviceIDs)
+
+	changedIDs, err := c.driver.WaitServiceStatus(serviceIDs, interval)
+	if err != nil {
+		log.WithError(err).Debug("Could not wait for service status changes; polling instead")
+		time.Sleep(interval)
+		return c.driver.GetServiceStatus(serviceID)
+	} else if len(changedIDs) == 0 {
+		return c.driver.GetServiceStatus(serviceID)
+	}
+
+	next := make(map[string]map[string]interface{})
+	for key, row := range states {
+		next[key] = row
+	}
+	for _, id := range changedIDs {
+		prefix := id + "/"
+		svcStates, err := c.driver.GetServiceStatus(id)
+		if err != nil {
+			// the service may have been removed
+			return c.driver.GetServiceStatus(serviceID)
+		}
+		for key := range next {
+			if strings.HasPrefix(key, prefix) {
+				delete(next, key)
+			}
+		}
+		for key, row := range svcStates {
+			if strings.HasPrefix(key, prefix) {
+				next[key] = row
+			}
+		}
+	}
+	return next, nil
+}
+
+// filterServiceStatus returns the status rows of the selected services and
+// their instances, moving the rows whose parents were filtered out to the top
+// of the tree.  If no services are selected, all of the rows are returned.
+func filterServiceStatus(states map[string]map[string]interface{}, selected map[string]bool) map[string]map[string]interface{} {
+	if selected == nil {
+		return states
+	}
+	filtered := make(map[string]map[string]interface{})
+	for key, row := range states {
+		if selected[strings.SplitN(key, "/", 2)[0]] {
+			filtered[key] = row
+		}
+	}
+	for key, row := range filtered {
+		if parent := fmt.Sprintf("%v", row["ParentID"]); parent != "" {
+			if _, ok := filtered[parent]; !ok {
+				newrow := make(map[string]interface{})
+				for field, value := range row {
+					newrow[field] = value
+				}
+				newrow["ParentID"] = ""
+				filtered[key] = newrow
+			}
+		}
+	}
+	return filtered
+}
+
+// diffServiceStatus returns the keys of the status rows that were added or
+// whose values changed, ignoring the values that change with every refresh.
+func diffServiceStatus(prev, next map[string]map[string]interface{}) map[string]bool {
+	changed := make(map[string]bool)
+	for key, row := range next {
+		old, ok := prev[key]
+		if !ok || len(old) != len(row) {
+			changed[key] = true
+			continue
+		}
+		for field, value := range row {
+			if field == "Uptime" || field == "Cur/Max/Avg" || field == "CPU" || field == "Usage Age" {
+				continue
+			}
+			if oldValue, ok := old[field]; !ok || fmt.Sprintf("%v", oldValue) != fmt.Sprintf("%v", value) {
+				changed[key] = true
+				break
+			}
+		}
+	}
+	return changed
 }
 
 // serviced service list [--verbose, -v] [SERVICEID]
@@ -891,7 +1361,7 @@ func (c *ServicedCli) cmdServiceList(ctx *cli.Context) {
 			return
 		} else {
 			if ctx.String("format") == "" {
-				if jsonService, err := json.MarshalIndent(service, " ", "  "); err != nil {
+				if jsonService, err := c.marshalOutput(service); err != nil {
 					fmt.Fprintf(os.Stderr, "failed to marshal service definition: %s\n", err)
 				} else {
 					fmt.Println(string(jsonService))
@@ -901,7 +1371,7 @@ func (c *ServicedCli) cmdServiceList(ctx *cli.Context) {
 				log := log.WithFields(logrus.Fields{
 					"format": tpl,
 				})
-				if tmpl, err := template.New("template").Parse(tpl); err != nil {
+				if tmpl, err := parseFormat("template", tpl); err != nil {
 					log.WithError(err).Error("Unable to parse format template")
 				} else if err := tmpl.Execute(os.Stdout, service); err != nil {
 					log.WithError(err).Error("Unable to execute template")
@@ -911,6 +1381,12 @@ func (c *ServicedCli) cmdServiceList(ctx *cli.Context) {
 		return
 	}
 
+	view, err := c.viewFromContext(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
 	services, err := c.driver.GetServices()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -920,8 +1396,28 @@ func (c *ServicedCli) cmdServiceList(ctx *cli.Context) {
 		return
 	}
 
+	tenants := serviceTenants(services)
+	if view.Selector != "" {
+		sel, err := service.ParseTagSelector(view.Selector)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		var selected []service.Service
+		for _, svc := range services {
+			if sel.Matches(svc.Tags) {
+				selected = append(selected, svc)
+			}
+		}
+		if len(selected) == 0 {
+			fmt.Fprintf(os.Stderr, "no services match selector %s\n", sel)
+			return
+		}
+		services = selected
+	}
+
 	if ctx.Bool("verbose") {
-		if jsonService, err := json.MarshalIndent(services, " ", "  "); err != nil {
+		if jsonService, err := c.marshalOutput(services); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal service definitions: %s\n", err)
 		} else {
 			fmt.Println(string(jsonService))
@@ -931,15 +1427,32 @@ func (c *ServicedCli) cmdServiceList(ctx *cli.Context) {
 		cmdSetTreeCharset(ctx, c.config)
 
 		servicemap := api.NewServiceMap(services)
-		t := NewTable(ctx.String("show-fields"))
+		for _, svc := range services {
+			// show the services whose parents were not selected at the top
+			if _, ok := servicemap[svc.ParentServiceID]; !ok && svc.ParentServiceID != "" {
+				svc.ParentServiceID = ""
+				servicemap.Update(svc)
+			}
+		}
+		fields := ctx.String("show-fields")
+		if view.Fields != "" {
+			fields = view.Fields
+		}
+		t, err := newTableFromContext(ctx, fields)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
 
 		var addRows func(string)
 		addRows = func(root string) {
 			rowids := servicemap.Tree()[root]
 			if len(rowids) > 0 {
 				sort.Strings(rowids)
-				t.IndentRow()
-				defer t.DedentRow()
+				if !view.Flat {
+					t.IndentRow()
+					defer t.DedentRow()
+				}
 				for _, rowid := range rowids {
 					row := servicemap.Get(rowid)
 					// truncate the image id
@@ -959,6 +1472,8 @@ func (c *ServicedCli) cmdServiceList(ctx *cli.Context) {
 						"DState":    row.DesiredState,
 						"Launch":    row.Launch,
 						"DepID":     row.DeploymentID,
+						"Tenant":    tenants[row.ID],
+						"Tags":      strings.Join(row.Tags, ","),
 					})
 					addRows(row.ID)
 				}
@@ -972,18 +1487,32 @@ func (c *ServicedCli) cmdServiceList(ctx *cli.Context) {
 		log := log.WithFields(logrus.Fields{
 			"format": tpl,
 		})
-		tmpl, err := template.New("template").Parse(tpl)
+		tmpl, err := parseFormat("template", tpl)
 		if err != nil {
 			log.WithError(err).Error("Unable to parse template")
 		}
-		for _, service := range services {
-			if err := tmpl.Execute(os.Stdout, service); err != nil {
+		for i := range services {
+			if err := tmpl.Execute(os.Stdout, &services[i]); err != nil {
 				log.WithError(err).Error("Unable to execute template")
 			}
 		}
 	}
 }
 
+// serviceListFields are all of the fields of serviced service list
+var serviceListFields = "Name,ServiceID,Inst,ImageID,Pool,DState,Launch,DepID,Tenant,Tags"
+
+// serviceStatusFields are all of the fields of serviced service status
+var serviceStatusFields = "Name,ServiceID,Status,Exit Reason,HC Fail,Healthcheck,Healthcheck Status,Maintenance,Uptime,RAM,Cur/Max/Avg,CPU,Usage Age,Hostname,InSync,DockerID"
+
+// serviced service fields
+func (c *ServicedCli) cmdServiceFields(ctx *cli.Context) {
+	printFields([]commandFields{
+		{"serviced service list", serviceListFields},
+		{"serviced service status", serviceStatusFields},
+	}, service.Service{})
+}
+
 // serviced service add [[-p PORT]...] [[-q REMOTE]...] [--parent-id SERVICEID] NAME IMAGEID COMMAND
 func (c *ServicedCli) cmdServiceAdd(ctx *cli.Context) {
 	args := ctx.Args()
@@ -1024,6 +1553,7 @@ func (c *ServicedCli) cmdServiceAdd(ctx *cli.Context) {
 }
 
 // serviced service clone --config config { SERVICEID | SERVICENAME | [POOL/]...PARENTNAME.../SERVICENAME }
+// serviced service clone --deployment-id DEPLOYMENTID [--pool POOLID] [--snapshot SNAPSHOTID] [--enable-public-endpoints] TENANTID
 func (c *ServicedCli) cmdServiceClone(ctx *cli.Context) {
 	args := ctx.Args()
 	if len(args) < 1 {
@@ -1038,6 +1568,33 @@ func (c *ServicedCli) cmdServiceClone(ctx *cli.Context) {
 		return
 	}
 
+	if deploymentID := ctx.String("deployment-id"); deploymentID != "" {
+		request := dao.DeploymentCloneRequest{
+			TenantID:              serviceID,
+			PoolID:                ctx.String("pool"),
+			DeploymentID:          deploymentID,
+			SnapshotID:            ctx.String("snapshot"),
+			EnablePublicEndpoints: ctx.Bool("enable-public-endpoints"),
+		}
+		if request.PoolID == "" {
+			if svc, err := c.driver.GetService(serviceID); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", serviceID, err)
+				return
+			} else if svc == nil {
+				fmt.Fprintf(os.Stderr, "%s: service not found\n", serviceID)
+				return
+			} else {
+				request.PoolID = svc.PoolID
+			}
+		}
+		if tenantID, err := c.driver.CloneDeployment(request); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", serviceID, err)
+		} else {
+			fmt.Println(tenantID)
+		}
+		return
+	}
+
 	if copiedSvc, err := c.driver.CloneService(serviceID, ctx.String("suffix")); err != nil {
 		fmt.Fprintf(os.Stderr, "%s: %s\n", serviceID, err)
 	} else if copiedSvc == nil {
@@ -1084,7 +1641,7 @@ func (c *ServicedCli) cmdServiceEdit(ctx *cli.Context) {
 		return
 	}
 
-	jsonService, err := json.MarshalIndent(service, " ", "  ")
+	jsonService, err := c.marshalOutput(service)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error marshalling service: %s\n", err)
 		return
@@ -1106,7 +1663,88 @@ func (c *ServicedCli) cmdServiceEdit(ctx *cli.Context) {
 	}
 }
 
-// serviced service assign-ip SERVICEID [IPADDRESS]
+// serviced service tag SERVICEID TAG ...
+func (c *ServicedCli) cmdServiceTag(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "tag")
+		return
+	}
+
+	c.updateServiceTags(args[0], func(tags []string) ([]string, error) {
+		for _, tag := range args[1:] {
+			if err := service.ValidTag(tag); err != nil {
+				return nil, err
+			}
+			found := false
+			for _, t := range tags {
+				if t == tag {
+					found = true
+					break
+				}
+			}
+			if !found {
+				tags = append(tags, tag)
+			}
+		}
+		return tags, nil
+	})
+}
+
+// serviced service untag SERVICEID TAG ...
+func (c *ServicedCli) cmdServiceUntag(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "untag")
+		return
+	}
+
+	c.updateServiceTags(args[0], func(tags []string) ([]string, error) {
+		remove := make(map[string]bool)
+		for _, tag := range args[1:] {
+			remove[tag] = true
+		}
+		var kept []string
+		for _, t := range tags {
+			if !remove[t] {
+				kept = append(kept, t)
+			}
+		}
+		return kept, nil
+	})
+}
+
+// updateServiceTags updates the tags of a service and prints its id
+func (c *ServicedCli) updateServiceTags(keyword string, update func([]string) ([]string, error)) {
+	svc, err := c.searchForService(keyword)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if svc.Tags, err = update(svc.Tags); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	jsonService, err := json.Marshal(svc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error marshalling service: %s\n", err)
+		return
+	}
+
+	if svc, err := c.driver.UpdateService(bytes.NewReader(jsonService)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else if svc == nil {
+		fmt.Fprintln(os.Stderr, "received nil service")
+	} else {
+		fmt.Println(svc.ID)
+	}
+}
+
+// serviced service assign-ip { SERVICEID [IPADDRESS] | --rebalance POOLID }
 func (c *ServicedCli) cmdServiceAssignIP(ctx *cli.Context) {
 	args := ctx.Args()
 	if len(args) < 1 {
@@ -1115,6 +1753,19 @@ func (c *ServicedCli) cmdServiceAssignIP(ctx *cli.Context) {
 		return
 	}
 
+	if ctx.Bool("rebalance") {
+		c.rebalanceIPs(args[0])
+		return
+	}
+
+	if ctx.Bool("all") {
+		c.assignDeploymentIPs(args[0], ctx.Bool("dry-run"))
+		return
+	} else if ctx.Bool("dry-run") {
+		fmt.Fprintln(os.Stderr, "--dry-run can only be used with --all")
+		return
+	}
+
 	serviceID, _, err := c.parseServiceInstance(ctx.Args().First())
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -1136,22 +1787,234 @@ func (c *ServicedCli) cmdServiceAssignIP(ctx *cli.Context) {
 	}
 }
 
-// serviced service start SERVICEID
-func (c *ServicedCli) cmdServiceStart(ctx *cli.Context) {
-	args := ctx.Args()
-	if len(args) < 1 {
-		fmt.Printf("Incorrect Usage.\n\n")
-		cli.ShowCommandHelp(ctx, "start")
+// rebalanceIPs reassigns the stale address assignments of a pool and reports
+// the outcome for each affected service
+func (c *ServicedCli) rebalanceIPs(poolID string) {
+	report, err := c.driver.RebalanceIPs(poolID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(report) == 0 {
+		fmt.Println("No address assignments need to be reassigned")
 		return
 	}
 
-	serviceID, _, err := c.parseServiceInstance(ctx.Args().First())
+	t := NewTable("ServiceID,From,To,Status")
+	t.Padding = 6
+	for _, r := range report {
+		status := "reassigned"
+		if r.Error != "" {
+			status = fmt.Sprintf("blocked: %s", r.Error)
+		}
+		t.AddRow(map[string]interface{}{
+			"ServiceID": r.ServiceID,
+			"From":      strings.Join(r.OldIPAddrs, ","),
+			"To":        r.NewIPAddr,
+			"Status":    status,
+		})
+	}
+	t.Print()
+}
+
+// assignDeploymentIPs reports the current and proposed address assignment of
+// every endpoint in a deployment, applying the proposals unless dryRun is set
+func (c *ServicedCli) assignDeploymentIPs(deploymentID string, dryRun bool) {
+	plan, err := c.driver.AssignDeploymentIPs(deploymentID, dryRun)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return
+	} else if len(plan) == 0 {
+		fmt.Println("No endpoints require an address assignment")
+		return
+	}
+
+	t := NewTable("ServiceID,Name,Endpoint,Port,Current,Proposed,Status")
+	t.Padding = 6
+	for _, p := range plan {
+		var status string
+		switch {
+		case p.Error != "":
+			status = fmt.Sprintf("error: %s", p.Error)
+		case !p.NeedsAssignment():
+			status = "unchanged"
+		case dryRun:
+			status = "pending"
+		default:
+			status = "assigned"
+		}
+		t.AddRow(map[string]interface{}{
+			"ServiceID": p.ServiceID,
+			"Name":      p.ServiceName,
+			"Endpoint":  p.EndpointName,
+			"Port":      p.Port,
+			"Current":   p.CurrentIPAddr,
+			"Proposed":  p.ProposedIPAddr,
+			"Status":    status,
+		})
+	}
+	t.Print()
+}
+
+// selectorFlag is the flag accepted by every command that can operate on
+// services selected by their tags
+func selectorFlag() cli.Flag {
+	return cli.StringFlag{
+		Name:  "selector",
+		Value: "",
+		Usage: "Select services by tag instead of by name, e.g. 'env=staging,team=db'",
+	}
+}
+
+// searchForServicesBySelector returns the services whose tags match the
+// selector
+func (c *ServicedCli) searchForServicesBySelector(selector string) ([]service.Service, error) {
+	sel, err := service.ParseTagSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	svcs, err := c.driver.GetServices()
+	if err != nil {
+		return nil, err
+	}
+	var services []service.Service
+	for _, svc := range svcs {
+		if sel.Matches(svc.Tags) {
+			services = append(services, svc)
+		}
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no services match selector %s", sel)
+	}
+	return services, nil
+}
+
+// filterFlag returns the flag used to select services by a glob over their
+// paths
+func filterFlag() cli.Flag {
+	return cli.StringFlag{
+		Name:  "filter",
+		Value: "",
+		Usage: "Select services whose path matches a glob, e.g. 'Zenoss.Core/*collector*'",
+	}
+}
+
+// isServiceGlob returns true if the argument should be matched as a glob
+// rather than searched for as a service id or path
+func isServiceGlob(arg string) bool {
+	return strings.ContainsAny(arg, "*?[")
+}
+
+// searchForServicesByGlob returns the services whose path, pool path or name
+// matches the glob
+func (c *ServicedCli) searchForServicesByGlob(pattern string) ([]service.Service, error) {
+	pattern = strings.ToLower(pattern)
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %s", pattern, err)
+	}
+	svcs, err := c.driver.GetServices()
+	if err != nil {
+		return nil, err
+	}
+	pathmap, err := c.buildServicePaths(svcs)
+	if err != nil {
+		return nil, err
+	}
+	var services []service.Service
+	for _, svc := range svcs {
+		poolPath := path.Join(strings.ToLower(svc.PoolID), pathmap[svc.ID])
+		for _, name := range []string{pathmap[svc.ID], poolPath, strings.ToLower(svc.Name)} {
+			if ok, _ := path.Match(pattern, name); ok {
+				services = append(services, svc)
+				break
+			}
+		}
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no services match filter %s", pattern)
+	}
+	return services, nil
+}
+
+// schedulingServiceIDs returns the ids of the services named by the
+// arguments, the --filter glob and the --selector flag, in that order and
+// without duplicates.  Arguments containing glob characters are matched like
+// --filter.
+func (c *ServicedCli) schedulingServiceIDs(ctx *cli.Context) ([]string, error) {
+	var svcs []service.Service
+	for _, arg := range ctx.Args() {
+		if isServiceGlob(arg) {
+			matches, err := c.searchForServicesByGlob(arg)
+			if err != nil {
+				return nil, err
+			}
+			svcs = append(svcs, matches...)
+			continue
+		}
+		serviceID, _, err := c.parseServiceInstance(arg)
+		if err != nil {
+			if len(ctx.Args()) > 1 {
+				err = fmt.Errorf("%s: %s", arg, err)
+			}
+			return nil, err
+		}
+		svcs = append(svcs, service.Service{ID: serviceID})
+	}
+	if filter := ctx.String("filter"); filter != "" {
+		matches, err := c.searchForServicesByGlob(filter)
+		if err != nil {
+			return nil, err
+		}
+		svcs = append(svcs, matches...)
+	}
+	if selector := ctx.String("selector"); selector != "" {
+		matches, err := c.searchForServicesBySelector(selector)
+		if err != nil {
+			return nil, err
+		}
+		svcs = append(svcs, matches...)
+	}
+
+	var serviceIDs []string
+	for _, svc := range svcs {
+		if !utils.StringInSlice(svc.ID, serviceIDs) {
+			serviceIDs = append(serviceIDs, svc.ID)
+		}
+	}
+	return serviceIDs, nil
+}
+
+// scheduleServices schedules every service named by the arguments, the
+// --filter glob and the --selector flag in a single request, and returns the
+// number of services affected.
+func (c *ServicedCli) scheduleServices(ctx *cli.Context, schedule func(api.SchedulerConfig) (int, error)) (int, error) {
+	serviceIDs, err := c.schedulingServiceIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return schedule(api.SchedulerConfig{
+		ServiceID:  serviceIDs[0],
+		ServiceIDs: serviceIDs[1:],
+		AutoLaunch: ctx.Bool("auto-launch"),
+	})
+}
+
+// isBulkSchedule returns true if the command names more than one service or
+// selects services by --filter or --selector
+func isBulkSchedule(ctx *cli.Context) bool {
+	args := ctx.Args()
+	return len(args) > 1 || (len(args) == 1 && isServiceGlob(args[0])) || ctx.String("filter") != "" || ctx.String("selector") != ""
+}
+
+// serviced service start { SERVICEID ... | --filter GLOB | --selector SELECTOR }
+func (c *ServicedCli) cmdServiceStart(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 && ctx.String("selector") == "" && ctx.String("filter") == "" {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "start")
+		return
 	}
 
-	if affected, err := c.driver.StartService(api.SchedulerConfig{serviceID, ctx.Bool("auto-launch")}); err != nil {
+	if affected, err := c.scheduleServices(ctx, c.driver.StartService); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 	} else if affected == 0 {
 		fmt.Println("Service already started")
@@ -1160,15 +2023,24 @@ func (c *ServicedCli) cmdServiceStart(ctx *cli.Context) {
 	}
 }
 
-// serviced service restart SERVICEID
+// serviced service restart { SERVICEID ... | INSTANCEID | --filter GLOB | --selector SELECTOR }
 func (c *ServicedCli) cmdServiceRestart(ctx *cli.Context) {
 	args := ctx.Args()
-	if len(args) < 1 {
+	if len(args) < 1 && ctx.String("selector") == "" && ctx.String("filter") == "" {
 		fmt.Printf("Incorrect Usage.\n\n")
 		cli.ShowCommandHelp(ctx, "restart")
 		return
 	}
 
+	if isBulkSchedule(ctx) {
+		if affected, err := c.scheduleServices(ctx, c.driver.RestartService); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		} else {
+			fmt.Printf("Restarting %d service(s)\n", affected)
+		}
+		return
+	}
+
 	serviceID, instanceID, err := c.parseServiceInstance(ctx.Args().First())
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -1176,7 +2048,7 @@ func (c *ServicedCli) cmdServiceRestart(ctx *cli.Context) {
 	}
 
 	if instanceID < 0 {
-		if affected, err := c.driver.RestartService(api.SchedulerConfig{serviceID, ctx.Bool("auto-launch")}); err != nil {
+		if affected, err := c.driver.RestartService(api.SchedulerConfig{ServiceID: serviceID, AutoLaunch: ctx.Bool("auto-launch")}); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 		} else {
 			fmt.Printf("Restarting %d service(s)\n", affected)
@@ -1190,27 +2062,218 @@ func (c *ServicedCli) cmdServiceRestart(ctx *cli.Context) {
 	}
 }
 
-// serviced service stop SERVICEID
-func (c *ServicedCli) cmdServiceStop(ctx *cli.Context) {
+// serviced service stop { SERVICEID ... | --filter GLOB | --selector SELECTOR }
+func (c *ServicedCli) cmdServiceStop(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 && ctx.String("selector") == "" && ctx.String("filter") == "" {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "stop")
+		return
+	}
+
+	if affected, err := c.scheduleServices(ctx, c.driver.StopService); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else if affected == 0 {
+		fmt.Println("Service already stopped")
+	} else {
+		fmt.Printf("Scheduled %d service(s) to stop\n", affected)
+	}
+}
+
+// serviced service pause SERVICEID
+func (c *ServicedCli) cmdServicePause(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "pause")
+		return
+	}
+
+	serviceID, _, err := c.parseServiceInstance(ctx.Args().First())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if affected, err := c.driver.PauseService(api.SchedulerConfig{ServiceID: serviceID, AutoLaunch: ctx.Bool("auto-launch")}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else if affected == 0 {
+		fmt.Println("No running services to pause")
+	} else {
+		fmt.Printf("Scheduled %d service(s) to pause\n", affected)
+	}
+}
+
+// serviced service resume SERVICEID
+func (c *ServicedCli) cmdServiceResume(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "resume")
+		return
+	}
+
+	serviceID, _, err := c.parseServiceInstance(ctx.Args().First())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if affected, err := c.driver.ResumeService(api.SchedulerConfig{ServiceID: serviceID, AutoLaunch: ctx.Bool("auto-launch")}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else if affected == 0 {
+		fmt.Println("No paused services to resume")
+	} else {
+		fmt.Printf("Scheduled %d service(s) to resume\n", affected)
+	}
+}
+
+// serviced service wait [--state STATE] [--timeout DURATION] { SERVICEID ... | --filter GLOB | --selector SELECTOR }
+func (c *ServicedCli) cmdServiceWait(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 && ctx.String("selector") == "" && ctx.String("filter") == "" {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "wait")
+		return
+	}
+
+	state, err := script.ScriptStateToDesiredState(script.ServiceState(ctx.String("state")))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	timeout, err := time.ParseDuration(ctx.String("timeout"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid timeout %s: %s\n", ctx.String("timeout"), err)
+		c.exit(1)
+		return
+	}
+
+	serviceIDs, err := c.schedulingServiceIDs(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	if err := c.driver.WaitService(serviceIDs, state, timeout, ctx.Bool("recursive")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Printf("%d service(s) %s\n", len(serviceIDs), ctx.String("state"))
+}
+
+// serviced service clear-emergency SERVICEID
+func (c *ServicedCli) cmdServiceClearEmergency(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "clear-emergency")
+		return
+	}
+
+	serviceID, _, err := c.parseServiceInstance(ctx.Args().First())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if affected, err := c.driver.ClearEmergencyShutdown(serviceID); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else if affected == 0 {
+		fmt.Println("No emergency stopped services to clear")
+	} else {
+		fmt.Printf("Cleared the emergency shutdown of %d service(s)\n", affected)
+	}
+}
+
+// serviced service history SERVICEID
+func (c *ServicedCli) cmdServiceHistory(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "history")
+		return
+	}
+
+	serviceID, _, err := c.parseServiceInstance(args.First())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	changes, err := c.driver.GetServiceStateHistory(serviceID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonChanges, err := c.marshalOutput(changes); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal state history: %s", err)
+		} else {
+			fmt.Println(string(jsonChanges))
+		}
+		return
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("No desired state changes recorded")
+		return
+	}
+	t := NewTable("Time,From,To,Source,User")
+	t.Padding = 6
+	for _, change := range changes {
+		t.AddRow(map[string]interface{}{
+			"Time":   change.Time.Format(time.RFC3339),
+			"From":   desiredStateVerb(change.From),
+			"To":     desiredStateVerb(change.To),
+			"Source": change.Source,
+			"User":   change.User,
+		})
+	}
+	t.Print()
+}
+
+// desiredStateVerb names a desired state the way the scheduling commands do
+func desiredStateVerb(state service.DesiredState) string {
+	switch state {
+	case service.SVCRun:
+		return "start"
+	case service.SVCStop:
+		return "stop"
+	case service.SVCPause:
+		return "pause"
+	case service.SVCRestart:
+		return "restart"
+	}
+	return state.String()
+}
+
+// serviced service set-priority SERVICEID PRIORITY
+func (c *ServicedCli) cmdServiceSetPriority(ctx *cli.Context) {
 	args := ctx.Args()
-	if len(args) < 1 {
+	if len(args) < 2 {
 		fmt.Printf("Incorrect Usage.\n\n")
-		cli.ShowCommandHelp(ctx, "stop")
+		cli.ShowCommandHelp(ctx, "set-priority")
 		return
 	}
 
-	serviceID, _, err := c.parseServiceInstance(ctx.Args().First())
+	serviceID, _, err := c.parseServiceInstance(args[0])
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return
 	}
 
-	if affected, err := c.driver.StopService(api.SchedulerConfig{serviceID, ctx.Bool("auto-launch")}); err != nil {
+	if svc, err := c.driver.SetServicePriority(serviceID, args[1]); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-	} else if affected == 0 {
-		fmt.Println("Service already stopped")
+	} else if svc == nil {
+		fmt.Fprintln(os.Stderr, "received nil service")
 	} else {
-		fmt.Printf("Scheduled %d service(s) to stop\n", affected)
+		fmt.Println(svc.ID)
 	}
 }
 
@@ -1350,6 +2413,53 @@ func (c *ServicedCli) cmdServiceRun(ctx *cli.Context) error {
 	return c.exit(exitcode)
 }
 
+// serviced service migrate [--dry-run] SERVICEID SCRIPT
+func (c *ServicedCli) cmdServiceMigrate(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "migrate")
+		return
+	}
+
+	timeout, err := time.ParseDuration(ctx.String("timeout"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid timeout %s: %s\n", ctx.String("timeout"), err)
+		return
+	}
+
+	svc, err := c.searchForService(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	cfg := api.MigrationConfig{
+		ServiceID:        svc.ID,
+		ScriptFile:       args[1],
+		DryRun:           ctx.Bool("dry-run"),
+		Memory:           ctx.String("memory"),
+		CPUShares:        int64(ctx.Int("cpu-shares")),
+		Timeout:          timeout,
+		ServicedEndpoint: fmt.Sprintf("localhost:%s", api.GetOptionsRPCPort()),
+	}
+	req, err := c.driver.RunMigration(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if !cfg.DryRun {
+		fmt.Printf("Migrated service %s\n", svc.ID)
+		return
+	}
+	if jsonReq, err := c.marshalOutput(req); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to marshal migration: %s\n", err)
+	} else {
+		fmt.Println(string(jsonReq))
+	}
+}
+
 // serviced service attach { SERVICEID | SERVICENAME | DOCKERID | POOL/...PARENTNAME.../SERVICENAME/INSTANCE } [COMMAND ...]
 func (c *ServicedCli) cmdServiceAttach(ctx *cli.Context) error {
 	// verify args
@@ -1385,6 +2495,146 @@ func (c *ServicedCli) cmdServiceAttach(ctx *cli.Context) error {
 	return nil
 }
 
+// serviced service commit [--message MESSAGE] { SERVICEID | SERVICENAME | DOCKERID | POOL/...PARENTNAME.../SERVICENAME/INSTANCE }
+func (c *ServicedCli) cmdServiceCommit(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "commit")
+		return
+	}
+
+	serviceID, instanceID, err := c.parseServiceInstance(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if instanceID < 0 {
+		instanceID = 0
+	}
+
+	if snapshotID, err := c.driver.CommitServiceInstance(serviceID, instanceID, ctx.String("message")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else if snapshotID == "" {
+		fmt.Fprintln(os.Stderr, "received nil snapshot")
+	} else {
+		fmt.Println(snapshotID)
+	}
+}
+
+// serviced service diagnostics [--action ACTION] [--wait DURATION] [--out FILE] { SERVICEID | SERVICENAME | DOCKERID | POOL/...PARENTNAME.../SERVICENAME/INSTANCE }
+func (c *ServicedCli) cmdServiceDiagnostics(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "diagnostics")
+		return
+	}
+
+	serviceID, instanceID, err := c.parseServiceInstance(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if instanceID < 0 {
+		instanceID = 0
+	}
+	wait, err := time.ParseDuration(ctx.String("wait"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid wait %s: %s\n", ctx.String("wait"), err)
+		return
+	}
+	actions := ctx.StringSlice("action")
+	if len(actions) == 0 {
+		actions = service.DiagnosticActionNames()
+	}
+	for _, action := range actions {
+		if _, ok := service.DiagnosticActions[action]; !ok {
+			fmt.Fprintf(os.Stderr, "%s is not a diagnostic action\n", action)
+			return
+		}
+	}
+
+	if wait > 0 {
+		for _, action := range actions {
+			if err := c.driver.SendDockerAction(serviceID, instanceID, action, []string{}); err != nil {
+				fmt.Fprintf(os.Stderr, "could not run %s: %s\n", action, err)
+			}
+		}
+		time.Sleep(wait)
+	}
+
+	results, err := c.driver.GetInstanceDiagnostics(serviceID, instanceID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	wanted := make(map[string]bool)
+	for _, action := range actions {
+		wanted[action] = true
+	}
+	diagnostics := []service.DiagnosticOutput{}
+	for _, result := range results {
+		if wanted[result.Action] {
+			diagnostics = append(diagnostics, result)
+		}
+	}
+	if len(diagnostics) == 0 {
+		fmt.Fprintln(os.Stderr, "no diagnostic output found")
+		return
+	}
+
+	if out := ctx.String("out"); out != "" {
+		if err := writeDiagnosticsBundle(out, diagnostics); err != nil {
+			fmt.Fprintf(os.Stderr, "could not write %s: %s\n", out, err)
+			return
+		}
+		fmt.Println(out)
+		return
+	}
+	for _, result := range diagnostics {
+		fmt.Printf("==> %s on %s/%d (host %s, ran %s) <==\n", result.Action, result.ServiceID, result.InstanceID, result.HostID, result.Ran.UTC().Format(time.RFC3339))
+		os.Stdout.Write(result.Output)
+		if result.Error != "" {
+			fmt.Printf("error: %s\n", result.Error)
+		}
+	}
+}
+
+// writeDiagnosticsBundle writes the output of diagnostic actions to a gzipped
+// tar file, one file per action and host
+func writeDiagnosticsBundle(filename string, diagnostics []service.DiagnosticOutput) error {
+	fh, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	gz := gzip.NewWriter(fh)
+	tarfile := tar.NewWriter(gz)
+	for _, result := range diagnostics {
+		data := result.Output
+		if result.Error != "" {
+			data = append(append([]byte{}, data...), []byte(fmt.Sprintf("error: %s\n", result.Error))...)
+		}
+		hdr := &tar.Header{
+			Name:    fmt.Sprintf("%s-%d/%s/%s.txt", result.ServiceID, result.InstanceID, result.HostID, result.Action),
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: result.Ran,
+		}
+		if err := tarfile.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tarfile.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tarfile.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
 // serviced service action { SERVICEID | SERVICENAME | DOCKERID | POOL/...PARENTNAME.../SERVICENAME/INSTANCE } ACTION
 func (c *ServicedCli) cmdServiceAction(ctx *cli.Context) error {
 	// verify args
@@ -1430,11 +2680,11 @@ func (c *ServicedCli) cmdServiceAction(ctx *cli.Context) error {
 	return fmt.Errorf("serviced service action")
 }
 
-// serviced service logs { SERVICEID | SERVICENAME | DOCKERID | POOL/...PARENTNAME.../SERVICENAME/INSTANCE }
+// serviced service logs [--previous] [--follow] [--since SINCE] [--tail LINES] { SERVICEID | SERVICENAME | DOCKERID | POOL/...PARENTNAME.../SERVICENAME/INSTANCE }
 func (c *ServicedCli) cmdServiceLogs(ctx *cli.Context) error {
 	// verify args
 	args := ctx.Args()
-	if len(args) < 1 {
+	if len(args) < 1 && ctx.String("selector") == "" {
 		if !ctx.Bool("help") {
 			fmt.Fprintf(os.Stderr, "Incorrect Usage.\n\n")
 		}
@@ -1442,6 +2692,64 @@ func (c *ServicedCli) cmdServiceLogs(ctx *cli.Context) error {
 		return nil
 	}
 
+	since, err := parseLogsSince(ctx.String("since"), time.Now())
+	if err != nil {
+		return err
+	} else if ctx.Int("tail") < 0 {
+		return fmt.Errorf("invalid tail %d: must not be negative", ctx.Int("tail"))
+	}
+
+	if ctx.Bool("follow") {
+		if ctx.String("selector") != "" || ctx.Bool("previous") {
+			return errors.New("--follow cannot be used with --selector or --previous")
+		}
+		serviceID, instanceID, err := c.parseServiceInstance(ctx.Args().First())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+		cfg := api.ServiceLogsConfig{
+			ServiceID:  serviceID,
+			InstanceID: instanceID,
+			Since:      since,
+			Tail:       ctx.Int("tail"),
+		}
+		if err := c.driver.FollowServiceLogs(cfg, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return fmt.Errorf("serviced service logs")
+	}
+
+	// the filters are passed on to docker logs
+	var filters []string
+	if value := ctx.String("since"); value != "" {
+		filters = append(filters, "--since="+value)
+	}
+	if tail := ctx.Int("tail"); tail > 0 {
+		filters = append(filters, fmt.Sprintf("--tail=%d", tail))
+	}
+
+	if selector := ctx.String("selector"); selector != "" {
+		svcs, err := c.searchForServicesBySelector(selector)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+		command, argv := dockerLogsArgs(filters, args)
+		for _, svc := range svcs {
+			fmt.Printf("==> %s (%s) <==\n", svc.Name, svc.ID)
+			if ctx.Bool("previous") {
+				err = c.printPreviousOutput(svc.ID, 0)
+			} else {
+				err = c.driver.LogsForServiceInstance(svc.ID, 0, command, argv)
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+		return fmt.Errorf("serviced service logs")
+	}
+
 	serviceID, instanceID, err := c.parseServiceInstance(ctx.Args().First())
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -1451,13 +2759,16 @@ func (c *ServicedCli) cmdServiceLogs(ctx *cli.Context) error {
 	if instanceID < 0 {
 		instanceID = 0
 	}
-	command := ""
-	argv := []string{}
-	if len(args) > 1 {
-		command = args[1]
-		argv = args[2:]
+
+	if ctx.Bool("previous") {
+		if err := c.printPreviousOutput(serviceID, instanceID); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return fmt.Errorf("serviced service logs")
 	}
 
+	command, argv := dockerLogsArgs(filters, args[1:])
+
 	if err := c.driver.LogsForServiceInstance(serviceID, instanceID, command, argv); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 	}
@@ -1465,6 +2776,141 @@ func (c *ServicedCli) cmdServiceLogs(ctx *cli.Context) error {
 	return fmt.Errorf("serviced service logs")
 }
 
+// parseLogsSince parses the time after which log lines are shown, given as
+// a time (RFC3339) or as a duration before now
+func parseLogsSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %s: must be a time (RFC3339) or a duration", value)
+	}
+	return t, nil
+}
+
+// dockerLogsArgs returns the command and arguments passed to docker logs
+func dockerLogsArgs(filters, args []string) (string, []string) {
+	argv := append(append([]string{}, filters...), args...)
+	if len(argv) == 0 {
+		return "", []string{}
+	}
+	return argv[0], argv[1:]
+}
+
+// printPreviousOutput prints the output of the most recently exited container
+// of a service instance
+func (c *ServicedCli) printPreviousOutput(serviceID string, instanceID int) error {
+	output, err := c.driver.GetPreviousInstanceOutput(serviceID, instanceID)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Output of %s/%d on host %s, exited %s\n", serviceID, instanceID, output.HostID, output.Exited.Format(time.RFC3339))
+	os.Stdout.Write(output.Output)
+	return nil
+}
+
+// serviced service diff [--patch] SERVICEID [SNAPSHOTID | TAG | OTHER_SERVICEID]
+func (c *ServicedCli) cmdServiceDiff(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "diff")
+		return
+	}
+
+	svc, err := c.searchForService(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	other, label, err := c.diffTarget(svc.ID, args.Get(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	if ctx.Bool("patch") {
+		changes, err := service.Diff(other, svc)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		if data, err := c.marshalOutput(changes); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal changes: %s\n", err)
+			c.exit(1)
+		} else {
+			fmt.Println(string(data))
+		}
+		return
+	}
+
+	diff, err := service.UnifiedDiff(label, "service "+svc.ID, other, svc)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+	} else if diff == "" {
+		fmt.Println("No differences")
+	} else {
+		fmt.Print(diff)
+	}
+}
+
+// diffTarget returns the definition a service is compared with and its label:
+// the service in a snapshot named by id or tag, another service, or the
+// service in the most recent snapshot of its tenant if target is empty
+func (c *ServicedCli) diffTarget(serviceID, target string) (*service.Service, string, error) {
+	snapshots, err := c.driver.GetSnapshotsByServiceID(serviceID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	snapshotID := ""
+	if target == "" {
+		var latest *dao.SnapshotInfo
+		for i, snapshot := range snapshots {
+			if snapshot.Invalid {
+				continue
+			}
+			if latest == nil || snapshot.Created.After(latest.Created) || (snapshot.Created.Equal(latest.Created) && snapshot.SnapshotID > latest.SnapshotID) {
+				latest = &snapshots[i]
+			}
+		}
+		if latest == nil {
+			return nil, "", errors.New("no snapshots found")
+		}
+		snapshotID = latest.SnapshotID
+	} else {
+		for _, snapshot := range snapshots {
+			if snapshot.SnapshotID == target || utils.StringInSlice(target, snapshot.Tags) {
+				snapshotID = snapshot.SnapshotID
+				break
+			}
+		}
+	}
+
+	if snapshotID != "" {
+		svc, err := c.driver.GetSnapshotService(snapshotID, serviceID)
+		if err != nil {
+			return nil, "", err
+		}
+		return svc, "snapshot " + snapshotID, nil
+	}
+
+	svc, err := c.searchForService(target)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s is not a snapshot or a service: %s", target, err)
+	}
+	return svc, "service " + svc.ID, nil
+}
+
 // serviced service list-snapshot SERVICEID [--show-tags]
 func (c *ServicedCli) cmdServiceListSnapshots(ctx *cli.Context) {
 	showTags := ctx.Bool("show-tags")
@@ -1568,6 +3014,11 @@ func (c *ServicedCli) cmdServiceEndpoints(ctx *cli.Context) {
 		return
 	}
 
+	if ctx.Bool("stats") {
+		c.printEndpointStats(svc)
+		return
+	}
+
 	var reportExports, reportImports bool
 	if ctx.Bool("all") {
 		reportImports = true
@@ -1592,8 +3043,14 @@ func (c *ServicedCli) cmdServiceEndpoints(ctx *cli.Context) {
 			fmt.Fprintf(os.Stderr, "Unable to get host info, printing host IDs instead of names: %s", err)
 		}
 
-		t := NewTable("Name,ServiceID,Endpoint,Purpose,Host,HostIP,HostPort,ContainerID,ContainerIP,ContainerPort")
+		verify := ctx.Bool("verify")
+		fields := "Name,ServiceID,Endpoint,Purpose,Host,HostIP,HostPort,ContainerID,ContainerIP,ContainerPort"
+		if verify {
+			fields += ",Status"
+		}
+		t := NewTable(fields)
 		t.Padding = 4
+		var diagnostics []string
 		for _, endpoint := range endpoints {
 			serviceName := svc.Name
 			if svc.Instances > 1 && endpoint.Endpoint.ContainerID != "" {
@@ -1622,8 +3079,86 @@ func (c *ServicedCli) cmdServiceEndpoints(ctx *cli.Context) {
 				"ContainerID":   fmt.Sprintf("%-12.12s", endpoint.Endpoint.ContainerID),
 				"ContainerIP":   endpoint.Endpoint.ContainerIP,
 				"ContainerPort": endpoint.Endpoint.ContainerPort,
+				"Status":        endpointStatus(endpoint),
 			})
+			if verify {
+				for _, message := range endpoint.Messages {
+					diagnostics = append(diagnostics, fmt.Sprintf("%s %s (%s): %s", serviceName, endpoint.Endpoint.Application, endpoint.Endpoint.Purpose, message))
+				}
+			}
 		}
 		t.Print()
+		if len(diagnostics) > 0 {
+			fmt.Println()
+			for _, diagnostic := range diagnostics {
+				fmt.Println(diagnostic)
+			}
+		}
+	}
+}
+
+// printEndpointStats prints the connections the mux has forwarded to each
+// exported endpoint of the running instances of a service
+func (c *ServicedCli) printEndpointStats(svc *service.Service) {
+	stats, err := c.driver.GetEndpointStats(svc.ID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(stats) == 0 {
+		fmt.Fprintf(os.Stderr, "%s - no running exported endpoints\n", svc.Name)
+		return
+	}
+
+	hostmap, err := c.driver.GetHostMap()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to get host info, printing host IDs instead of names: %s", err)
+	}
+
+	t := NewTable("Name,Endpoint,Host,ContainerIP,ContainerPort,Active,Total,ConnectErrors,BytesReceived,BytesSent")
+	t.Padding = 4
+	var errs []string
+	for _, s := range stats {
+		serviceName := svc.Name
+		if svc.Instances > 1 {
+			serviceName = fmt.Sprintf("%s/%d", serviceName, s.InstanceID)
+		}
+
+		host := s.HostID
+		if hostinfo, ok := hostmap[s.HostID]; ok {
+			host = hostinfo.Name
+		}
+
+		row := map[string]interface{}{
+			"Name":          serviceName,
+			"Endpoint":      s.Application,
+			"Host":          host,
+			"ContainerIP":   s.ContainerIP,
+			"ContainerPort": s.ContainerPort,
+		}
+		if s.Error != "" {
+			errs = append(errs, fmt.Sprintf("%s %s: %s", serviceName, s.Application, s.Error))
+			for _, col := range []string{"Active", "Total", "ConnectErrors", "BytesReceived", "BytesSent"} {
+				row[col] = "-"
+			}
+		} else {
+			row["Active"] = s.ActiveConnections
+			row["Total"] = s.TotalConnections
+			row["ConnectErrors"] = s.ConnectErrors
+			row["BytesReceived"] = s.BytesReceived
+			row["BytesSent"] = s.BytesSent
+		}
+		t.AddRow(row)
+	}
+	t.Print()
+	for _, e := range errs {
+		fmt.Fprintln(os.Stderr, e)
+	}
+}
+
+// endpointStatus describes the outcome of verifying an endpoint
+func endpointStatus(endpoint applicationendpoint.EndpointReport) string {
+	if status := endpoint.Status(); status != "" {
+		return string(status)
 	}
+	return "unverified"
 }
@@ -16,21 +16,31 @@
 package cmd
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	//	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/commons/jsonpatch"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/applicationendpoint"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/statehistory"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -103,6 +113,7 @@ var DefaultTestServices = []service.Service{
 		DesiredState:   int(service.SVCRun),
 		Launch:         "auto",
 		DeploymentID:   "Zenoss-core",
+		Tags:           []string{"env=staging", "team=db"},
 	}, {
 		ID:             "test-service-3",
 		Name:           "zencommand",
@@ -114,6 +125,7 @@ var DefaultTestServices = []service.Service{
 		DesiredState:   int(service.SVCRun),
 		Launch:         "manual",
 		DeploymentID:   "Zenoss-core",
+		Tags:           []string{"env=staging"},
 	},
 }
 
@@ -179,6 +191,30 @@ func (t ServiceAPITest) GetServices() ([]service.Service, error) {
 	return t.services, nil
 }
 
+func (t ServiceAPITest) summaries() []service.ServiceSummary {
+	summaries := make([]service.ServiceSummary, len(t.services))
+	for i := range t.services {
+		summaries[i] = t.services[i].Summary()
+	}
+	service.SetSummaryPaths(summaries)
+	return summaries
+}
+
+func (t ServiceAPITest) QueryServices(query service.ServiceQuery) (*service.ServiceSummaryPage, error) {
+	if t.errs["QueryServices"] != nil {
+		return nil, t.errs["QueryServices"]
+	}
+	page := service.QueryServiceSummaries(t.summaries(), query)
+	return &page, nil
+}
+
+func (t ServiceAPITest) ResolveServicePath(servicePath string) ([]service.ServiceMatch, error) {
+	if t.errs["ResolveServicePath"] != nil {
+		return nil, t.errs["ResolveServicePath"]
+	}
+	return service.ResolveServicePath(t.summaries(), servicePath), nil
+}
+
 func (t ServiceAPITest) GetResourcePools() ([]pool.ResourcePool, error) {
 	if t.errs["GetResourcePools"] != nil {
 		return nil, t.errs["GetResourcePools"]
@@ -204,24 +240,98 @@ func (t ServiceAPITest) GetEndpoints(serviceID string, reportImports, reportExpo
 	if t.errs["GetEndpoints"] != nil {
 		return nil, t.errs["GetEndpoints"]
 	} else if serviceID == "test-service-2" {
-		return t.endpoints, nil
+		if !validate {
+			return t.endpoints, nil
+		}
+		reports := make([]applicationendpoint.EndpointReport, len(t.endpoints))
+		copy(reports, t.endpoints)
+		for i := range reports {
+			if reports[i].Endpoint.Purpose == "export" {
+				reports[i].AddCheck("listening", applicationendpoint.CheckOK, "")
+			} else {
+				reports[i].AddCheck("resolves", applicationendpoint.CheckFailed, "no exports match the import")
+			}
+		}
+		return reports, nil
 	}
 	return []applicationendpoint.EndpointReport{}, nil
 }
 
+func (t ServiceAPITest) GetEndpointStats(serviceID string) ([]applicationendpoint.EndpointStats, error) {
+	if t.errs["GetEndpointStats"] != nil {
+		return nil, t.errs["GetEndpointStats"]
+	} else if serviceID == "test-service-2" {
+		return []applicationendpoint.EndpointStats{
+			{
+				ServiceID:         "test-service-2",
+				InstanceID:        1,
+				Application:       "endpointName1",
+				HostID:            "hostID1",
+				ContainerIP:       "containerIP1",
+				ContainerPort:     100,
+				ActiveConnections: 2,
+				TotalConnections:  15,
+				ConnectErrors:     1,
+				BytesReceived:     1024,
+				BytesSent:         4096,
+			}, {
+				ServiceID:     "test-service-2",
+				InstanceID:    2,
+				Application:   "endpointName1",
+				HostID:        "hostID2",
+				ContainerIP:   "containerIP2",
+				ContainerPort: 100,
+				Error:         "connection refused",
+			},
+		}, nil
+	}
+	return []applicationendpoint.EndpointStats{}, nil
+}
+
 func (t ServiceAPITest) GetService(id string) (*service.Service, error) {
 	if t.errs["GetService"] != nil {
 		return nil, t.errs["GetService"]
 	}
 
-	for i, s := range t.services {
+	for _, s := range t.services {
 		if s.ID == id {
-			return &t.services[i], nil
+			return &s, nil
 		}
 	}
 	return nil, nil
 }
 
+func (t ServiceAPITest) GetServiceStatus(serviceID string) (map[string]map[string]interface{}, error) {
+	if t.errs["GetServiceStatus"] != nil {
+		return nil, t.errs["GetServiceStatus"]
+	}
+
+	states := make(map[string]map[string]interface{})
+	for _, s := range t.services {
+		if serviceID != "" && s.ID != serviceID {
+			continue
+		}
+		status := service.Stopped
+		if service.DesiredState(s.DesiredState) == service.SVCRun {
+			status = service.Running
+		}
+		states[s.ID+"/0"] = map[string]interface{}{
+			"ServiceID": s.ID,
+			"Name":      s.Name,
+			"ParentID":  "",
+			"Status":    status,
+		}
+	}
+	return states, nil
+}
+
+func (t ServiceAPITest) WaitServiceStatus(serviceIDs []string, timeout time.Duration) ([]string, error) {
+	if t.errs["WaitServiceStatus"] != nil {
+		return nil, t.errs["WaitServiceStatus"]
+	}
+	return serviceIDs[:1], nil
+}
+
 func (t ServiceAPITest) AddService(config api.ServiceConfig) (*service.Service, error) {
 	if t.errs["AddService"] != nil {
 		return nil, t.errs["AddService"]
@@ -263,6 +373,13 @@ func (t ServiceAPITest) RemoveService(id string) error {
 	return nil
 }
 
+func (t ServiceAPITest) CloneDeployment(request dao.DeploymentCloneRequest) (string, error) {
+	if t.errs["CloneDeployment"] != nil {
+		return "", t.errs["CloneDeployment"]
+	}
+	return fmt.Sprintf("%s-%s-%s", request.TenantID, request.PoolID, request.DeploymentID), nil
+}
+
 func (t ServiceAPITest) UpdateService(reader io.Reader) (*service.Service, error) {
 	var svc service.Service
 
@@ -281,14 +398,14 @@ func (t ServiceAPITest) StartService(cfg api.SchedulerConfig) (int, error) {
 	if t.errs["StartService"] != nil {
 		return 0, t.errs["StartService"]
 	}
-	return 1, nil
+	return 1 + len(cfg.ServiceIDs), nil
 }
 
 func (t ServiceAPITest) RestartService(cfg api.SchedulerConfig) (int, error) {
 	if t.errs["RestartService"] != nil {
 		return 0, t.errs["RestartService"]
 	}
-	return 1, nil
+	return 1 + len(cfg.ServiceIDs), nil
 }
 
 func (t ServiceAPITest) StopServiceInstance(serviceID string, instanceID int) error {
@@ -309,9 +426,150 @@ func (t ServiceAPITest) StopService(cfg api.SchedulerConfig) (int, error) {
 		return 0, ErrNoServiceFound
 	}
 
+	return 1 + len(cfg.ServiceIDs), nil
+}
+
+func (t ServiceAPITest) WaitService(serviceIDs []string, state service.DesiredState, timeout time.Duration, recursive bool) error {
+	if t.errs["WaitService"] != nil {
+		return t.errs["WaitService"]
+	}
+	for _, serviceID := range serviceIDs {
+		if s, err := t.GetService(serviceID); err != nil {
+			return err
+		} else if s == nil {
+			return ErrNoServiceFound
+		}
+	}
+	return nil
+}
+
+func (t ServiceAPITest) PauseService(cfg api.SchedulerConfig) (int, error) {
+	if s, err := t.GetService(cfg.ServiceID); err != nil {
+		return 0, err
+	} else if s == nil {
+		return 0, ErrNoServiceFound
+	} else if s.DesiredState != int(service.SVCRun) {
+		return 0, nil
+	}
+
 	return 1, nil
 }
 
+func (t ServiceAPITest) ResumeService(cfg api.SchedulerConfig) (int, error) {
+	if s, err := t.GetService(cfg.ServiceID); err != nil {
+		return 0, err
+	} else if s == nil {
+		return 0, ErrNoServiceFound
+	} else if s.DesiredState != int(service.SVCPause) {
+		return 0, nil
+	}
+
+	return 1, nil
+}
+
+func (t ServiceAPITest) SetServicePriority(serviceID, priority string) (*service.Service, error) {
+	if _, ok := service.PriorityRank(priority); !ok {
+		return nil, ErrStub
+	}
+	s, err := t.GetService(serviceID)
+	if err != nil {
+		return nil, err
+	} else if s == nil {
+		return nil, ErrNoServiceFound
+	}
+	svc := *s
+	svc.Priority = priority
+	return &svc, nil
+}
+
+func (t ServiceAPITest) CommitServiceInstance(serviceID string, instanceID int, message string) (string, error) {
+	s, err := t.GetService(serviceID)
+	if err != nil {
+		return "", err
+	} else if s == nil {
+		return "", ErrNoServiceFound
+	} else if message == "" {
+		return "", ErrStub
+	}
+	return fmt.Sprintf("%s-snapshot", s.ID), nil
+}
+
+func (t ServiceAPITest) GetPreviousInstanceOutput(serviceID string, instanceID int) (*service.InstanceOutput, error) {
+	if serviceID != "test-service-2" || instanceID != 0 {
+		return nil, ErrStub
+	}
+	return &service.InstanceOutput{
+		HostID:     "test-host-1",
+		ServiceID:  serviceID,
+		InstanceID: instanceID,
+		Exited:     time.Date(2016, 5, 4, 3, 2, 1, 0, time.UTC),
+		Output:     []byte("starting zope\npanic: out of memory\n"),
+	}, nil
+}
+
+func (t ServiceAPITest) LogsForServiceInstance(serviceID string, instanceID int, command string, args []string) error {
+	fmt.Printf("docker logs %s/%d %s\n", serviceID, instanceID, strings.TrimSpace(strings.Join(append([]string{command}, args...), " ")))
+	return nil
+}
+
+func (t ServiceAPITest) FollowServiceLogs(cfg api.ServiceLogsConfig, w io.Writer) error {
+	if cfg.ServiceID != "test-service-2" {
+		return ErrStub
+	}
+	fmt.Fprintf(w, "following %s instance %d since %s tail %d\n", cfg.ServiceID, cfg.InstanceID, cfg.Since.Format(time.RFC3339), cfg.Tail)
+	return nil
+}
+
+func (t ServiceAPITest) SendDockerAction(serviceID string, instanceID int, action string, args []string) error {
+	if _, ok := service.DiagnosticActions[action]; !ok {
+		return ErrStub
+	}
+	return nil
+}
+
+func (t ServiceAPITest) GetInstanceDiagnostics(serviceID string, instanceID int) ([]service.DiagnosticOutput, error) {
+	if serviceID != "test-service-2" || instanceID != 0 {
+		return nil, ErrStub
+	}
+	ran := time.Date(2016, 5, 4, 3, 2, 1, 0, time.UTC)
+	return []service.DiagnosticOutput{
+		{HostID: "test-host-1", ServiceID: serviceID, Action: "diag-lsof", Ran: ran, Output: []byte("bash: lsof: command not found\n"), Error: "exit status 127"},
+		{HostID: "test-host-1", ServiceID: serviceID, Action: "diag-netstat", Ran: ran, Output: []byte("tcp 0 0 0.0.0.0:8080 LISTEN\n")},
+	}, nil
+}
+
+func (t ServiceAPITest) ClearEmergencyShutdown(serviceID string) (int, error) {
+	if t.errs["ClearEmergencyShutdown"] != nil {
+		return 0, t.errs["ClearEmergencyShutdown"]
+	} else if s, err := t.GetService(serviceID); err != nil {
+		return 0, err
+	} else if s == nil {
+		return 0, ErrNoServiceFound
+	} else if !s.EmergencyShutdown {
+		return 0, nil
+	}
+
+	return 1, nil
+}
+
+func (t ServiceAPITest) GetServiceStateHistory(serviceID string) ([]statehistory.Change, error) {
+	if t.errs["GetServiceStateHistory"] != nil {
+		return nil, t.errs["GetServiceStateHistory"]
+	} else if s, err := t.GetService(serviceID); err != nil {
+		return nil, err
+	} else if s == nil {
+		return nil, ErrNoServiceFound
+	} else if s.ID != "test-service-2" {
+		return []statehistory.Change{}, nil
+	}
+
+	stopped := time.Date(2016, 6, 1, 3, 0, 0, 0, time.UTC)
+	return []statehistory.Change{
+		{Time: stopped, From: service.SVCRun, To: service.SVCStop, Source: statehistory.SourceCLI, User: "zenoss"},
+		{Time: stopped.Add(4 * time.Hour), From: service.SVCStop, To: service.SVCRun, Source: statehistory.SourceScheduler},
+	}, nil
+}
+
 func (t ServiceAPITest) AssignIP(config api.IPConfig) error {
 	if t.errs["AssignIP"] != nil {
 		return t.errs["AssignIP"]
@@ -319,6 +577,31 @@ func (t ServiceAPITest) AssignIP(config api.IPConfig) error {
 	return nil
 }
 
+func (t ServiceAPITest) RebalanceIPs(poolID string) ([]addressassignment.Reassignment, error) {
+	if t.errs["RebalanceIPs"] != nil {
+		return nil, t.errs["RebalanceIPs"]
+	} else if poolID != "default" {
+		return nil, nil
+	}
+	return []addressassignment.Reassignment{
+		{ServiceID: "test-service-1", OldIPAddrs: []string{"10.0.0.1"}, NewIPAddr: "10.0.0.2"},
+		{ServiceID: "test-service-2", OldIPAddrs: []string{"10.0.0.1", "10.0.0.3"}, Error: "no ips available"},
+	}, nil
+}
+
+func (t ServiceAPITest) AssignDeploymentIPs(deploymentID string, dryRun bool) ([]addressassignment.PlannedAssignment, error) {
+	if t.errs["AssignDeploymentIPs"] != nil {
+		return nil, t.errs["AssignDeploymentIPs"]
+	} else if deploymentID != "test-deployment" {
+		return nil, nil
+	}
+	return []addressassignment.PlannedAssignment{
+		{ServiceID: "test-service-1", ServiceName: "Zenoss", EndpointName: "http", Port: 80, CurrentIPAddr: "10.0.0.1", ProposedIPAddr: "10.0.0.1"},
+		{ServiceID: "test-service-2", ServiceName: "Zope", EndpointName: "https", Port: 443, ProposedIPAddr: "10.0.0.2"},
+		{ServiceID: "test-service-3", ServiceName: "zencommand", EndpointName: "ssh", Port: 22, Error: "no ips available"},
+	}, nil
+}
+
 func (t ServiceAPITest) StartShell(config api.ShellConfig) error {
 	if s, err := t.GetService(config.ServiceID); err != nil {
 		return err
@@ -347,6 +630,24 @@ func (t ServiceAPITest) RunShell(config api.ShellConfig, stopChan chan struct{})
 	return 0, nil
 }
 
+func (t ServiceAPITest) RunMigration(config api.MigrationConfig) (*dao.ServiceMigrationRequest, error) {
+	if t.errs["RunMigration"] != nil {
+		return nil, t.errs["RunMigration"]
+	}
+	return &dao.ServiceMigrationRequest{
+		ServiceID: config.ServiceID,
+		Patches: []*dao.ServicePatch{
+			{
+				ServiceID: config.ServiceID,
+				Patch: jsonpatch.Patch{
+					{Op: "replace", Path: "/Instances", Value: json.RawMessage("2")},
+				},
+			},
+		},
+		DryRun: config.DryRun,
+	}, nil
+}
+
 func (t ServiceAPITest) GetSnapshotsByServiceID(id string) ([]dao.SnapshotInfo, error) {
 	if t.errs["GetSnapshotsByServiceID"] != nil {
 		return nil, t.errs["GetSnapshotsByServiceID"]
@@ -362,6 +663,30 @@ func (t ServiceAPITest) GetSnapshotsByServiceID(id string) ([]dao.SnapshotInfo,
 	return snapshots, nil
 }
 
+func (t ServiceAPITest) GetSnapshotService(snapshotID, serviceID string) (*service.Service, error) {
+	if t.errs["GetSnapshotService"] != nil {
+		return nil, t.errs["GetSnapshotService"]
+	}
+
+	for _, snapshot := range t.snapshots {
+		if snapshot.SnapshotID != snapshotID {
+			continue
+		}
+		svc, err := t.GetService(serviceID)
+		if err != nil {
+			return nil, err
+		} else if svc == nil {
+			return nil, ErrNoServiceFound
+		}
+		// the snapshot has one fewer instance and is stopped
+		snapshotSvc := *svc
+		snapshotSvc.Instances--
+		snapshotSvc.DesiredState = int(service.SVCStop)
+		return &snapshotSvc, nil
+	}
+	return nil, ErrNoSnapshotFound
+}
+
 func (t ServiceAPITest) AddSnapshot(config api.SnapshotConfig) (string, error) {
 	if t.errs["AddSnapshot"] != nil {
 		return "", t.errs["AddSnapshot"]
@@ -420,7 +745,11 @@ func ExampleServicedCLI_CmdServiceList() {
 
 func ExampleServicedCLI_CmdServiceList_fail() {
 	DefaultServiceAPITest.errs["GetServices"] = ErrInvalidService
-	defer func() { DefaultServiceAPITest.errs["GetServices"] = nil }()
+	DefaultServiceAPITest.errs["QueryServices"] = ErrInvalidService
+	defer func() {
+		DefaultServiceAPITest.errs["GetServices"] = nil
+		DefaultServiceAPITest.errs["QueryServices"] = nil
+	}()
 	// Error retrieving service
 	pipeStderr(InitServiceAPITest, "serviced", "service", "list", "test-service-0")
 	// Error retrieving all services
@@ -431,6 +760,17 @@ func ExampleServicedCLI_CmdServiceList_fail() {
 	// invalid service
 }
 
+func ExampleServicedCLI_CmdServiceList_format() {
+	InitServiceAPITest("serviced", "service", "list", "--format", `{{.Name | lower}}={{.Endpoints | where "Purpose" "import" | pluck "Application" | join ","}}{{"\n"}}`)
+	InitServiceAPITest("serviced", "service", "list", "--format", "{{.ID}} {{.Tags | json}}\n", "test-service-2")
+
+	// Output:
+	// zenoss=zope
+	// zope=
+	// zencommand=
+	// test-service-2 ["env=staging","team=db"]
+}
+
 func ExampleServicedCLI_CmdServiceList_err() {
 	DefaultServiceAPITest.services = nil
 	defer func() { DefaultServiceAPITest.services = DefaultTestServices }()
@@ -508,6 +848,25 @@ func ExampleServicedCLI_CmdServiceAdd_parentNotFound() {
 	// Error searching for parent service: service not found
 }
 
+func ExampleServicedCLI_CmdServiceClone_deployment() {
+	InitServiceAPITest("serviced", "service", "clone", "--deployment-id", "staging", "--pool", "test", "test-service-1")
+	InitServiceAPITest("serviced", "service", "clone", "--deployment-id", "staging", "test-service-1")
+
+	// Output:
+	// test-service-1-test-staging
+	// test-service-1-default-staging
+}
+
+func ExampleServicedCLI_CmdServiceClone_deploymentFailed() {
+	DefaultServiceAPITest.errs["CloneDeployment"] = ErrStub
+	defer func() { DefaultServiceAPITest.errs["CloneDeployment"] = nil }()
+
+	pipeStderr(InitServiceAPITest, "serviced", "service", "clone", "--deployment-id", "staging", "test-service-1")
+
+	// Output:
+	// test-service-1: stub for facade failed
+}
+
 func ExampleServicedCLI_CmdServiceRemove() {
 	InitServiceAPITest("serviced", "service", "remove", "test-service-1")
 	InitServiceAPITest("serviced", "service", "remove", "test-service-2")
@@ -596,8 +955,8 @@ func ExampleServicedCLI_CmdServiceEdit_usage() {
 }
 
 func ExampleServicedCLI_CmdServiceEdit_fail() {
-	DefaultServiceAPITest.errs["GetServices"] = ErrInvalidService
-	defer func() { DefaultServiceAPITest.errs["GetServices"] = nil }()
+	DefaultServiceAPITest.errs["QueryServices"] = ErrInvalidService
+	defer func() { DefaultServiceAPITest.errs["QueryServices"] = nil }()
 	// Failed to get service
 	pipeStderr(InitServiceAPITest, "serviced", "service", "edit", "test-service-0")
 	// TODO: Failed to update service
@@ -615,6 +974,104 @@ func ExampleServicedCLI_CmdServiceEdit_err() {
 	// service not found
 }
 
+func ExampleServicedCLI_CmdServiceTag() {
+	InitServiceAPITest("serviced", "service", "tag", "test-service-2", "env=staging", "tier=1")
+	InitServiceAPITest("serviced", "service", "untag", "test-service-2", "team=db")
+
+	// Output:
+	// test-service-2
+	// test-service-2
+}
+
+func ExampleServicedCLI_CmdServiceTag_err() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "tag", "test-service-2", "env staging")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "untag", "test-service-0", "env=staging")
+
+	// Output:
+	// tag "env staging" contains whitespace
+	// service not found
+}
+
+func ExampleServicedCLI_CmdServiceTag_usage() {
+	InitServiceAPITest("serviced", "service", "tag", "test-service-2")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    tag - Adds tags to a service
+	//
+	// USAGE:
+	//    command tag [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced service tag SERVICEID TAG ...
+	//
+	// OPTIONS:
+}
+
+func ExampleServicedCLI_CmdServiceMigrate() {
+	InitServiceAPITest("serviced", "service", "migrate", "test-service-1", "migrate.py")
+	InitServiceAPITest("serviced", "service", "migrate", "--dry-run", "test-service-1", "migrate.py")
+
+	// Output:
+	// Migrated service test-service-1
+	// {
+	//    "ServiceID": "test-service-1",
+	//    "Modified": null,
+	//    "Added": null,
+	//    "Deploy": null,
+	//    "Patches": [
+	//      {
+	//        "ServiceID": "test-service-1",
+	//        "Patch": [
+	//          {
+	//            "op": "replace",
+	//            "path": "/Instances",
+	//            "value": 2
+	//          }
+	//        ]
+	//      }
+	//    ],
+	//    "DryRun": true
+	//  }
+}
+
+func ExampleServicedCLI_CmdServiceMigrate_usage() {
+	InitServiceAPITest("serviced", "service", "migrate", "test-service-1")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    migrate - Runs a migration script against a service and its children in a sandboxed container
+	//
+	// USAGE:
+	//    command migrate [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced service migrate [--dry-run] SERVICEID SCRIPT
+	//
+	// OPTIONS:
+	//    --dry-run		Validate and print the proposed changes without applying them
+	//    --memory '512m'	Memory limit of the migration container
+	//    --cpu-shares '512'	CPU shares of the migration container
+	//    --timeout '10m'	Time to wait for the migration script to finish
+}
+
+func ExampleServicedCLI_CmdServiceMigrate_fail() {
+	DefaultServiceAPITest.errs["RunMigration"] = ErrInvalidService
+	defer func() { DefaultServiceAPITest.errs["RunMigration"] = nil }()
+	pipeStderr(InitServiceAPITest, "serviced", "service", "migrate", "test-service-1", "migrate.py")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "migrate", "test-service-0", "migrate.py")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "migrate", "--timeout", "soon", "test-service-1", "migrate.py")
+
+	// Output:
+	// invalid service
+	// service not found
+	// invalid timeout soon: time: invalid duration "soon"
+}
+
 func ExampleServicedCLI_CmdServiceAssignIPs() {
 	// Auto-assign
 	InitServiceAPITest("serviced", "service", "assign-ip", "test-service-1")
@@ -638,9 +1095,12 @@ func ExampleServicedCLI_CmdServiceAssignIPs_usage() {
 	//    command assign-ip [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service assign-ip SERVICEID [IPADDRESS]
+	//    serviced service assign-ip { SERVICEID [IPADDRESS] | --rebalance POOLID | --all [--dry-run] DEPLOYMENTID }
 	//
 	// OPTIONS:
+	//    --rebalance	Reassign the addresses of a pool that point to removed or inactive hosts
+	//    --all	Assign addresses to every endpoint of a deployment that requires one
+	//    --dry-run	Report the current and proposed assignments without applying them (requires --all)
 }
 
 func ExampleServicedCLI_CmdServiceAssignIPs_fail() {
@@ -659,6 +1119,54 @@ func ExampleServicedCLI_CmdServiceAssignIPs_err() {
 	// service not found
 }
 
+func ExampleServicedCLI_CmdServiceAssignIPs_rebalance() {
+	InitServiceAPITest("serviced", "service", "assign-ip", "--rebalance", "default")
+	InitServiceAPITest("serviced", "service", "assign-ip", "--rebalance", "emptypool")
+
+	// Output:
+	// ServiceID           From                   To            Status
+	// test-service-1      10.0.0.1               10.0.0.2      reassigned
+	// test-service-2      10.0.0.1,10.0.0.3                    blocked: no ips available
+	// No address assignments need to be reassigned
+}
+
+func ExampleServicedCLI_CmdServiceAssignIPs_rebalanceFail() {
+	DefaultServiceAPITest.errs["RebalanceIPs"] = ErrInvalidService
+	defer func() { DefaultServiceAPITest.errs["RebalanceIPs"] = nil }()
+	pipeStderr(InitServiceAPITest, "serviced", "service", "assign-ip", "--rebalance", "default")
+
+	// Output:
+	// invalid service
+}
+
+func ExampleServicedCLI_CmdServiceAssignIPs_all() {
+	InitServiceAPITest("serviced", "service", "assign-ip", "--all", "--dry-run", "test-deployment")
+	InitServiceAPITest("serviced", "service", "assign-ip", "--all", "test-deployment")
+	InitServiceAPITest("serviced", "service", "assign-ip", "--all", "empty-deployment")
+
+	// Output:
+	// ServiceID           Name            Endpoint      Port      Current       Proposed      Status
+	// test-service-1      Zenoss          http          80        10.0.0.1      10.0.0.1      unchanged
+	// test-service-2      Zope            https         443                     10.0.0.2      pending
+	// test-service-3      zencommand      ssh           22                                    error: no ips available
+	// ServiceID           Name            Endpoint      Port      Current       Proposed      Status
+	// test-service-1      Zenoss          http          80        10.0.0.1      10.0.0.1      unchanged
+	// test-service-2      Zope            https         443                     10.0.0.2      assigned
+	// test-service-3      zencommand      ssh           22                                    error: no ips available
+	// No endpoints require an address assignment
+}
+
+func ExampleServicedCLI_CmdServiceAssignIPs_allFail() {
+	DefaultServiceAPITest.errs["AssignDeploymentIPs"] = ErrInvalidService
+	defer func() { DefaultServiceAPITest.errs["AssignDeploymentIPs"] = nil }()
+	pipeStderr(InitServiceAPITest, "serviced", "service", "assign-ip", "--all", "test-deployment")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "assign-ip", "--dry-run", "test-service-1")
+
+	// Output:
+	// invalid service
+	// --dry-run can only be used with --all
+}
+
 func ExampleServicedCLI_CmdServiceStart_usage() {
 	InitServiceAPITest("serviced", "service", "start")
 
@@ -672,10 +1180,12 @@ func ExampleServicedCLI_CmdServiceStart_usage() {
 	//    command start [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service start SERVICEID
+	//    serviced service start { SERVICEID ... | --filter GLOB | --selector SELECTOR }
 	//
 	// OPTIONS:
 	//    --auto-launch	Recursively schedules child services
+	//    --selector 		Select services by tag instead of by name, e.g. 'env=staging,team=db'
+	//    --filter 		Select services whose path matches a glob, e.g. 'Zenoss.Core/*collector*'
 }
 
 func ExampleServicedCLI_CmdServiceStart_fail() {
@@ -701,6 +1211,34 @@ func ExampleServicedCLI_CmdServiceStart() {
 	// Scheduled 1 service(s) to start
 }
 
+func ExampleServicedCLI_CmdServiceStart_selector() {
+	InitServiceAPITest("serviced", "service", "start", "--selector", "env=staging")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "start", "--selector", "env=prod")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "start", "--selector", "env=prod,")
+
+	// Output:
+	// Scheduled 2 service(s) to start
+	// no services match selector env=prod
+	// invalid selector "env=prod,": tag is empty
+}
+
+func ExampleServicedCLI_CmdServiceStart_multiple() {
+	InitServiceAPITest("serviced", "service", "start", "test-service-1", "test-service-2")
+	InitServiceAPITest("serviced", "service", "start", "--filter", "zen*")
+	InitServiceAPITest("serviced", "service", "start", "Z*", "--selector", "env=staging")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "start", "test-service-1", "test-service-0")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "start", "--filter", "x*")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "start", "--filter", "[")
+
+	// Output:
+	// Scheduled 2 service(s) to start
+	// Scheduled 2 service(s) to start
+	// Scheduled 3 service(s) to start
+	// test-service-0: service not found
+	// no services match filter x*
+	// invalid filter "[": syntax error in pattern
+}
+
 func ExampleServicedCLI_CmdServiceRestart_usage() {
 	InitServiceAPITest("serviced", "service", "restart")
 
@@ -714,10 +1252,12 @@ func ExampleServicedCLI_CmdServiceRestart_usage() {
 	//    command restart [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service restart { SERVICEID | INSTANCEID }
+	//    serviced service restart { SERVICEID ... | INSTANCEID | --filter GLOB | --selector SELECTOR }
 	//
 	// OPTIONS:
 	//    --auto-launch	Recursively schedules child services
+	//    --selector 		Select services by tag instead of by name, e.g. 'env=staging,team=db'
+	//    --filter 		Select services whose path matches a glob, e.g. 'Zenoss.Core/*collector*'
 }
 
 func ExampleServicedCLI_CmdServiceRestart_fail() {
@@ -751,6 +1291,22 @@ func ExampleServicedCLI_CmdServiceRestart() {
 	// Restarting 1 service(s)
 }
 
+func ExampleServicedCLI_CmdServiceRestart_selector() {
+	InitServiceAPITest("serviced", "service", "restart", "--selector", "env")
+
+	// Output:
+	// Restarting 2 service(s)
+}
+
+func ExampleServicedCLI_CmdServiceRestart_multiple() {
+	InitServiceAPITest("serviced", "service", "restart", "test-service-2", "test-service-3")
+	InitServiceAPITest("serviced", "service", "restart", "zen*")
+
+	// Output:
+	// Restarting 2 service(s)
+	// Restarting 2 service(s)
+}
+
 func ExampleServicedCLI_CmdServiceStop_usage() {
 	InitServiceAPITest("serviced", "service", "stop")
 
@@ -764,10 +1320,12 @@ func ExampleServicedCLI_CmdServiceStop_usage() {
 	//    command stop [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service stop SERVICEID
+	//    serviced service stop { SERVICEID ... | --filter GLOB | --selector SELECTOR }
 	//
 	// OPTIONS:
 	//    --auto-launch	Recursively schedules child services
+	//    --selector 		Select services by tag instead of by name, e.g. 'env=staging,team=db'
+	//    --filter 		Select services whose path matches a glob, e.g. 'Zenoss.Core/*collector*'
 }
 
 func ExampleServicedCLI_CmdServiceStop_err() {
@@ -784,6 +1342,369 @@ func ExampleServicedCLI_CmdServiceStop() {
 	// Scheduled 1 service(s) to stop
 }
 
+func ExampleServicedCLI_CmdServiceStop_selector() {
+	InitServiceAPITest("serviced", "service", "stop", "--selector", "team=db")
+
+	// Output:
+	// Scheduled 1 service(s) to stop
+}
+
+func ExampleServicedCLI_CmdServiceStop_multiple() {
+	InitServiceAPITest("serviced", "service", "stop", "zope", "test-service-3", "test-service-2")
+
+	// Output:
+	// Scheduled 2 service(s) to stop
+}
+
+func ExampleServicedCLI_CmdServiceWait_usage() {
+	InitServiceAPITest("serviced", "service", "wait")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    wait - Waits for services to reach a desired state
+	//
+	// USAGE:
+	//    command wait [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced service wait [--state STATE] [--timeout DURATION] { SERVICEID ... | --filter GLOB | --selector SELECTOR }
+	//
+	// OPTIONS:
+	//    --state 'started'	State to wait for: started, stopped or paused
+	//    --timeout '5m'	Time to wait for the services to reach the state
+	//    --recursive, -r	Also wait for the child services
+	//    --selector 		Select services by tag instead of by name, e.g. 'env=staging,team=db'
+	//    --filter 		Select services whose path matches a glob, e.g. 'Zenoss.Core/*collector*'
+}
+
+func ExampleServicedCLI_CmdServiceWait() {
+	InitServiceAPITest("serviced", "service", "wait", "--state", "stopped", "--recursive", "test-service-1", "test-service-2")
+
+	// Output:
+	// 2 service(s) stopped
+}
+
+func ExampleServicedCLI_CmdServiceWait_badState() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "wait", "--state", "running", "test-service-1")
+
+	// Output:
+	// service state running unknown
+}
+
+func ExampleServicedCLI_CmdServiceWait_err() {
+	api := DefaultServiceAPITest
+	api.errs = map[string]error{"WaitService": errors.New("timeout waiting for service(s) test-service-2 to go")}
+	c := New(api, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	pipeStderr(func(args ...string) { c.Run(args) }, "serviced", "service", "wait", "test-service-2")
+
+	// Output:
+	// timeout waiting for service(s) test-service-2 to go
+}
+
+func ExampleServicedCLI_CmdServiceStatus() {
+	InitServiceAPITest("serviced", "service", "status", "--ascii", "--show-fields", "Name,ServiceID,Status")
+
+	// Output:
+	// Name           ServiceID        Status
+	// |-Zenoss       test-service-1   running
+	// |-Zope         test-service-2   running
+	// +-zencommand   test-service-3   running
+}
+
+func ExampleServicedCLI_CmdServiceStatus_selector() {
+	InitServiceAPITest("serviced", "service", "status", "--ascii", "--show-fields", "Name,ServiceID,Status", "--selector", "team=db")
+
+	// Output:
+	// Name     ServiceID        Status
+	// +-Zope   test-service-2   running
+}
+
+func ExampleServicedCLI_CmdServiceStatus_watch() {
+	InitServiceAPITest("serviced", "service", "status", "--ascii", "--show-fields", "Name,Status", "--watch", "--count", "2", "test-service-2")
+
+	// Output:
+	// Name     Status
+	// +-Zope   running
+	// Name     Status
+	// +-Zope   running
+}
+
+func ExampleServicedCLI_CmdServiceStatus_watchPoll() {
+	api := DefaultServiceAPITest
+	api.errs = map[string]error{"WaitServiceStatus": ErrStub}
+	c := New(api, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	c.Run([]string{"serviced", "service", "status", "--ascii", "--show-fields", "Name,Status", "--watch", "--interval", "1ms", "--count", "2", "test-service-2"})
+
+	// Output:
+	// Name     Status
+	// +-Zope   running
+	// Name     Status
+	// +-Zope   running
+}
+
+func ExampleServicedCLI_CmdServiceStatus_badInterval() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "status", "--watch", "--interval", "soon")
+
+	// Output:
+	// invalid interval soon
+}
+
+func TestDiffServiceStatus(t *testing.T) {
+	prev := map[string]map[string]interface{}{
+		"a/0": {"Name": "a", "Status": service.Running, "Uptime": "1m"},
+		"b/0": {"Name": "b", "Status": service.Running, "Uptime": "1m"},
+		"c/0": {"Name": "c", "Status": service.Running},
+	}
+	next := map[string]map[string]interface{}{
+		"a/0": {"Name": "a", "Status": service.Running, "Uptime": "2m"},
+		"b/0": {"Name": "b", "Status": service.Stopped, "Uptime": "2m"},
+		"d/0": {"Name": "d", "Status": service.Running},
+	}
+	changed := diffServiceStatus(prev, next)
+	if len(changed) != 2 || !changed["b/0"] || !changed["d/0"] {
+		t.Errorf("expected b/0 and d/0 to change, got %v", changed)
+	}
+}
+
+func ExampleServicedCLI_CmdServicePause_usage() {
+	InitServiceAPITest("serviced", "service", "pause")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    pause - Pauses a running service without stopping its containers
+	//
+	// USAGE:
+	//    command pause [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced service pause SERVICEID
+	//
+	// OPTIONS:
+	//    --auto-launch	Recursively schedules child services
+}
+
+func ExampleServicedCLI_CmdServicePause_err() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "pause", "test-service-0")
+
+	// Output:
+	// service not found
+}
+
+func ExampleServicedCLI_CmdServicePause() {
+	InitServiceAPITest("serviced", "service", "pause", "test-service-2")
+
+	// Output:
+	// Scheduled 1 service(s) to pause
+}
+
+func ExampleServicedCLI_CmdServiceResume() {
+	// The service is running, so there is nothing to resume
+	InitServiceAPITest("serviced", "service", "resume", "test-service-2")
+
+	// Output:
+	// No paused services to resume
+}
+
+func ExampleServicedCLI_CmdServiceClearEmergency() {
+	// The service was not emergency stopped, so there is nothing to clear
+	InitServiceAPITest("serviced", "service", "clear-emergency", "test-service-2")
+
+	// Output:
+	// No emergency stopped services to clear
+}
+
+func ExampleServicedCLI_CmdServiceClearEmergency_err() {
+	api := DefaultServiceAPITest
+	api.errs = map[string]error{"ClearEmergencyShutdown": ErrStub}
+	c := New(api, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	pipeStderr(func(args ...string) { c.Run(args) }, "serviced", "service", "clear-emergency", "test-service-2")
+
+	// Output:
+	// stub for facade failed
+}
+
+func ExampleServicedCLI_CmdServiceHistory() {
+	InitServiceAPITest("serviced", "service", "history", "test-service-2")
+
+	// Output:
+	// Time                      From       To         Source         User
+	// 2016-06-01T03:00:00Z      start      stop       cli            zenoss
+	// 2016-06-01T07:00:00Z      stop       start      scheduler
+}
+
+func ExampleServicedCLI_CmdServiceHistory_empty() {
+	InitServiceAPITest("serviced", "service", "history", "test-service-1")
+
+	// Output:
+	// No desired state changes recorded
+}
+
+func ExampleServicedCLI_CmdServiceHistory_err() {
+	api := DefaultServiceAPITest
+	api.errs = map[string]error{"GetServiceStateHistory": ErrStub}
+	c := New(api, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	pipeStderr(func(args ...string) { c.Run(args) }, "serviced", "service", "history", "test-service-2")
+
+	// Output:
+	// stub for facade failed
+}
+
+func ExampleServicedCLI_CmdServiceSetPriority() {
+	InitServiceAPITest("serviced", "service", "set-priority", "test-service-2", "high")
+
+	// Output:
+	// test-service-2
+}
+
+func ExampleServicedCLI_CmdServiceSetPriority_err() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "set-priority", "test-service-2", "urgent")
+
+	// Output:
+	// stub for facade failed
+}
+
+func ExampleServicedCLI_CmdServiceLogs_previous() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "logs", "--previous", "test-service-2")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "logs", "--previous", "test-service-1")
+
+	// Output:
+	// starting zope
+	// panic: out of memory
+	// Output of test-service-2/0 on host test-host-1, exited 2016-05-04T03:02:01Z
+	// serviced service logs
+	// stub for facade failed
+	// serviced service logs
+}
+
+func ExampleServicedCLI_CmdServiceLogs_previousSelector() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "logs", "--previous", "--selector", "team=db")
+
+	// Output:
+	// ==> Zope (test-service-2) <==
+	// starting zope
+	// panic: out of memory
+	// Output of test-service-2/0 on host test-host-1, exited 2016-05-04T03:02:01Z
+	// serviced service logs
+}
+
+func ExampleServicedCLI_CmdServiceLogs_filters() {
+	InitServiceAPITest("serviced", "service", "logs", "--since", "10m", "--tail", "5", "test-service-2")
+	InitServiceAPITest("serviced", "service", "logs", "test-service-2", "-t")
+
+	// Output:
+	// docker logs test-service-2/0 --since=10m --tail=5
+	// docker logs test-service-2/0 -t
+}
+
+func ExampleServicedCLI_CmdServiceLogs_follow() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "logs", "--follow", "--since", "2016-05-04T03:02:01Z", "--tail", "5", "test-service-2")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "logs", "-f", "test-service-1")
+
+	// Output:
+	// following test-service-2 instance -1 since 2016-05-04T03:02:01Z tail 5
+	// serviced service logs
+	// stub for facade failed
+	// serviced service logs
+}
+
+func ExampleServicedCLI_CmdServiceLogs_followErr() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "logs", "--follow", "--selector", "team=db")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "logs", "--follow", "--since", "yesterday", "test-service-2")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "logs", "--follow", "--tail", "-1", "test-service-2")
+
+	// Output:
+	// --follow cannot be used with --selector or --previous
+	// invalid since yesterday: must be a time (RFC3339) or a duration
+	// invalid tail -1: must not be negative
+}
+
+func ExampleServicedCLI_CmdServiceDiagnostics() {
+	InitServiceAPITest("serviced", "service", "diagnostics", "--wait", "1ms", "test-service-2")
+	InitServiceAPITest("serviced", "service", "diagnostics", "--wait", "0", "--action", "diag-netstat", "test-service-2")
+
+	// Output:
+	// ==> diag-lsof on test-service-2/0 (host test-host-1, ran 2016-05-04T03:02:01Z) <==
+	// bash: lsof: command not found
+	// error: exit status 127
+	// ==> diag-netstat on test-service-2/0 (host test-host-1, ran 2016-05-04T03:02:01Z) <==
+	// tcp 0 0 0.0.0.0:8080 LISTEN
+	// ==> diag-netstat on test-service-2/0 (host test-host-1, ran 2016-05-04T03:02:01Z) <==
+	// tcp 0 0 0.0.0.0:8080 LISTEN
+}
+
+func ExampleServicedCLI_CmdServiceDiagnostics_fail() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "diagnostics", "--wait", "0", "--action", "diag-nothing", "test-service-2")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "diagnostics", "--wait", "0", "test-service-1")
+
+	// Output:
+	// diag-nothing is not a diagnostic action
+	// stub for facade failed
+}
+
+func TestServicedCLI_CmdServiceDiagnostics_bundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diagnostics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "bundle.tgz")
+	InitServiceAPITest("serviced", "service", "diagnostics", "--wait", "0", "--out", filename, "test-service-2")
+
+	fh, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+	gz, err := gzip.NewReader(fh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tarfile := tar.NewReader(gz)
+	contents := make(map[string]string)
+	for {
+		hdr, err := tarfile.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(tarfile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[hdr.Name] = string(data)
+	}
+	expected := map[string]string{
+		"test-service-2-0/test-host-1/diag-lsof.txt":    "bash: lsof: command not found\nerror: exit status 127\n",
+		"test-service-2-0/test-host-1/diag-netstat.txt": "tcp 0 0 0.0.0.0:8080 LISTEN\n",
+	}
+	if !reflect.DeepEqual(contents, expected) {
+		t.Errorf("expected %v, got %v", expected, contents)
+	}
+}
+
+func ExampleServicedCLI_CmdServiceCommit() {
+	InitServiceAPITest("serviced", "service", "commit", "--message", "patched config", "test-service-2")
+
+	// Output:
+	// test-service-2-snapshot
+}
+
+func ExampleServicedCLI_CmdServiceCommit_err() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "commit", "test-service-2")
+
+	// Output:
+	// stub for facade failed
+}
+
 func ExampleServicedCLI_CmdServiceProxy_usage() {
 	// FIXME: Non-reproducible error on buildbox
 	InitServiceAPITest("serviced", "service", "proxy")
@@ -944,6 +1865,108 @@ func ExampleServicedCLI_CmdServiceRun_complete() {
 // TODO: ServicedCLI.CmdServiceAttach
 // TODO: ServicedCLI.CmdServiceAction
 
+func ExampleServicedCLI_CmdServiceDiff() {
+	InitServiceAPITest("serviced", "service", "diff", "test-service-2")
+
+	// Output:
+	// --- snapshot test-service-2-snapshot-1
+	// +++ service test-service-2
+	// @@ -13,7 +13,7 @@
+	//    ],
+	//    "OriginalConfigs": null,
+	//    "ConfigFiles": null,
+	// -  "Instances": 0,
+	// +  "Instances": 1,
+	//    "InstanceLimits": {
+	//      "Min": 1,
+	//      "Max": 1,
+}
+
+func ExampleServicedCLI_CmdServiceDiff_patch() {
+	InitServiceAPITest("serviced", "service", "diff", "--patch", "test-service-2", "test-service-2-snapshot-1")
+
+	// Output:
+	// [
+	//    {
+	//      "op": "replace",
+	//      "path": "/Instances",
+	//      "value": 1
+	//    }
+	//  ]
+}
+
+func ExampleServicedCLI_CmdServiceDiff_service() {
+	InitServiceAPITest("serviced", "service", "diff", "--patch", "test-service-2", "test-service-3")
+
+	// Output:
+	// [
+	//    {
+	//      "op": "replace",
+	//      "path": "/ID",
+	//      "value": "test-service-2"
+	//    },
+	//    {
+	//      "op": "replace",
+	//      "path": "/ImageID",
+	//      "value": "quay.io/zenossinc/tenantid2-core5x"
+	//    },
+	//    {
+	//      "op": "replace",
+	//      "path": "/InstanceLimits/Default",
+	//      "value": 1
+	//    },
+	//    {
+	//      "op": "replace",
+	//      "path": "/InstanceLimits/Max",
+	//      "value": 1
+	//    },
+	//    {
+	//      "op": "replace",
+	//      "path": "/InstanceLimits/Min",
+	//      "value": 1
+	//    },
+	//    {
+	//      "op": "replace",
+	//      "path": "/Instances",
+	//      "value": 1
+	//    },
+	//    {
+	//      "op": "replace",
+	//      "path": "/Launch",
+	//      "value": "auto"
+	//    },
+	//    {
+	//      "op": "replace",
+	//      "path": "/Name",
+	//      "value": "Zope"
+	//    },
+	//    {
+	//      "op": "replace",
+	//      "path": "/PoolID",
+	//      "value": "default"
+	//    },
+	//    {
+	//      "op": "replace",
+	//      "path": "/Startup",
+	//      "value": "startup command 2"
+	//    },
+	//    {
+	//      "op": "add",
+	//      "path": "/Tags/1",
+	//      "value": "team=db"
+	//    }
+	//  ]
+}
+
+func ExampleServicedCLI_CmdServiceDiff_err() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "diff", "test-service-3")
+	pipeStderr(InitServiceAPITest, "serviced", "service", "diff", "test-service-2", "nosuchthing")
+
+	// Output:
+	// no snapshots found
+	// nosuchthing is not a snapshot or a service: service not found
+}
+
 func ExampleServicedCLI_CmdServiceListSnapshots() {
 	InitServiceAPITest("serviced", "service", "list-snapshots", "test-service-1")
 
@@ -1097,7 +2120,8 @@ func ExampleServicedCLI_CmdServiceEndpoints_usage() {
 	// OPTIONS:
 	//    --imports, -i	include only imported endpoints
 	//    --all, -a		include all endpoints (imports and exports)
-	//    --verify, -v		verify endpoints
+	//    --verify, -v		verify that exports are listening, imports resolve, and the mux forwards connections
+	//    --stats, -s		show the connections the mux has forwarded to each exported endpoint
 
 }
 
@@ -1123,3 +2147,31 @@ func ExampleServicedCLI_CmdServiceEndpoints_works() {
 	// Zope    test-service-2    endpointName1    export     hostID1    hostIP1    10          containerID1    containerIP1    100
 	// Zope    test-service-2    endpointName2    import     hostID2    hostIP2    20          containerID2    containerIP2    200
 }
+
+func ExampleServicedCLI_CmdServiceEndpoints_verify() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "endpoints", "--all", "--verify", "test-service-2")
+
+	// Output:
+	// Name    ServiceID         Endpoint         Purpose    Host       HostIP     HostPort    ContainerID     ContainerIP     ContainerPort    Status
+	// Zope    test-service-2    endpointName1    export     hostID1    hostIP1    10          containerID1    containerIP1    100              ok
+	// Zope    test-service-2    endpointName2    import     hostID2    hostIP2    20          containerID2    containerIP2    200              failed
+	//
+	// Zope endpointName2 (import): resolves: no exports match the import
+}
+
+func ExampleServicedCLI_CmdServiceEndpoints_stats() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "endpoints", "--stats", "test-service-2")
+
+	// Output:
+	// Name    Endpoint         Host       ContainerIP     ContainerPort    Active    Total    ConnectErrors    BytesReceived    BytesSent
+	// Zope    endpointName1    hostID1    containerIP1    100              2         15       1                1024             4096
+	// Zope    endpointName1    hostID2    containerIP2    100              -         -        -                -                -
+	// Zope endpointName1: connection refused
+}
+
+func ExampleServicedCLI_CmdServiceEndpoints_statsNone() {
+	pipeStderr(InitServiceAPITest, "serviced", "service", "endpoints", "--stats", "test-service-1")
+
+	// Output:
+	// Zenoss - no running exported endpoints
+}
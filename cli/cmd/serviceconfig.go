@@ -0,0 +1,153 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+)
+
+// serviced service config-conflicts list SERVICEID
+func (c *ServicedCli) cmdConfigConflictsList(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "list")
+		return
+	}
+
+	serviceID, _, err := c.parseServiceInstance(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error searching for service: %s\n", err)
+		return
+	}
+	conflicts, err := c.driver.GetServiceConfigConflicts(serviceID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(conflicts) == 0 {
+		fmt.Fprintln(os.Stderr, "no config conflicts found")
+		return
+	}
+
+	t, err := newTableFromContext(ctx, ctx.String("show-fields"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	t.Padding = 4
+	for _, conflict := range conflicts {
+		merge := "conflict"
+		if conflict.Clean {
+			merge = "clean"
+		}
+		t.AddRow(map[string]interface{}{
+			"ID":       conflict.ID,
+			"Filename": conflict.Filename,
+			"Merge":    merge,
+		})
+	}
+	t.Print()
+}
+
+// serviced service config-conflicts show [--base|--mine|--theirs] SERVICEID FILENAME
+func (c *ServicedCli) cmdConfigConflictsShow(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "show")
+		return
+	}
+
+	conflict, err := c.findConfigConflict(args[0], args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	switch {
+	case ctx.Bool("base"):
+		fmt.Print(conflict.Base)
+	case ctx.Bool("mine"):
+		fmt.Print(conflict.Mine)
+	case ctx.Bool("theirs"):
+		fmt.Print(conflict.Theirs)
+	default:
+		fmt.Print(conflict.Merged)
+	}
+}
+
+// serviced service config-conflicts resolve {--mine|--theirs|--merged|--file PATH} SERVICEID FILENAME
+func (c *ServicedCli) cmdConfigConflictsResolve(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "resolve")
+		return
+	}
+
+	var resolution, content string
+	count := 0
+	for _, r := range []string{serviceconfigfile.ResolveMine, serviceconfigfile.ResolveTheirs, serviceconfigfile.ResolveMerged} {
+		if ctx.Bool(r) {
+			resolution = r
+			count++
+		}
+	}
+	if path := ctx.String("file"); path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not read %s: %s\n", path, err)
+			return
+		}
+		content = string(data)
+		count++
+	}
+	if count != 1 {
+		fmt.Fprintln(os.Stderr, "Specify exactly one of --mine, --theirs, --merged, or --file")
+		return
+	}
+
+	conflict, err := c.findConfigConflict(args[0], args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if err := c.driver.ResolveServiceConfigConflict(conflict.ID, resolution, content); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", conflict.Filename, err)
+		return
+	}
+	fmt.Println(conflict.ID)
+}
+
+// findConfigConflict returns the conflict of a config file of a service
+func (c *ServicedCli) findConfigConflict(keyword, filename string) (*service.ConfigConflict, error) {
+	serviceID, _, err := c.parseServiceInstance(keyword)
+	if err != nil {
+		return nil, fmt.Errorf("Error searching for service: %s", err)
+	}
+	conflicts, err := c.driver.GetServiceConfigConflicts(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	for i, conflict := range conflicts {
+		if conflict.Filename == filename {
+			return &conflicts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no config conflict found for %s", filename)
+}
@@ -0,0 +1,140 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/utils"
+)
+
+var DefaultConfigConflictAPITest = ConfigConflictAPITest{
+	ServiceAPITest: DefaultServiceAPITest,
+	conflicts:      DefaultTestConfigConflicts,
+}
+
+var DefaultTestConfigConflicts = []service.ConfigConflict{
+	{
+		ID:       "file-1",
+		Filename: "/etc/app.conf",
+		Base:     "port=8080\n",
+		Mine:     "port=9090\n",
+		Theirs:   "port=8080\nworkers=4\n",
+		Merged:   "port=9090\nworkers=4\n",
+		Clean:    true,
+	}, {
+		ID:       "file-2",
+		Filename: "/etc/db.conf",
+		Base:     "pool=10\n",
+		Mine:     "pool=20\n",
+		Theirs:   "pool=30\n",
+		Merged: serviceconfigfile.MarkerMine + "\npool=20\n" + serviceconfigfile.MarkerBase + "\npool=10\n" +
+			serviceconfigfile.MarkerSep + "\npool=30\n" + serviceconfigfile.MarkerTheirs + "\n",
+	},
+}
+
+var ErrConfigNotMerged = errors.New("config file versions do not merge cleanly")
+
+type ConfigConflictAPITest struct {
+	ServiceAPITest
+	conflicts []service.ConfigConflict
+}
+
+func InitConfigConflictAPITest(args ...string) {
+	c := New(DefaultConfigConflictAPITest, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func (t ConfigConflictAPITest) GetServiceConfigConflicts(serviceID string) ([]service.ConfigConflict, error) {
+	if serviceID != "test-service-1" {
+		return []service.ConfigConflict{}, nil
+	}
+	return t.conflicts, nil
+}
+
+func (t ConfigConflictAPITest) ResolveServiceConfigConflict(fileID, resolution, content string) error {
+	for _, conflict := range t.conflicts {
+		if conflict.ID == fileID {
+			if resolution == serviceconfigfile.ResolveMerged && !conflict.Clean {
+				return ErrConfigNotMerged
+			}
+			return nil
+		}
+	}
+	return ErrStub
+}
+
+func ExampleServicedCLI_CmdConfigConflictsList() {
+	InitConfigConflictAPITest("serviced", "service", "config-conflicts", "list", "test-service-1")
+
+	// Output:
+	// ID        Filename         Merge
+	// file-1    /etc/app.conf    clean
+	// file-2    /etc/db.conf     conflict
+}
+
+func ExampleServicedCLI_CmdConfigConflictsList_none() {
+	pipeStderr(InitConfigConflictAPITest, "serviced", "service", "config-conflicts", "list", "test-service-2")
+
+	// Output:
+	// no config conflicts found
+}
+
+func ExampleServicedCLI_CmdConfigConflictsShow() {
+	InitConfigConflictAPITest("serviced", "service", "config-conflicts", "show", "test-service-1", "/etc/db.conf")
+	InitConfigConflictAPITest("serviced", "service", "config-conflicts", "show", "--theirs", "test-service-1", "/etc/db.conf")
+
+	// Output:
+	// <<<<<<< current
+	// pool=20
+	// ||||||| previous template
+	// pool=10
+	// =======
+	// pool=30
+	// >>>>>>> new template
+	// pool=30
+}
+
+func ExampleServicedCLI_CmdConfigConflictsResolve() {
+	InitConfigConflictAPITest("serviced", "service", "config-conflicts", "resolve", "--merged", "test-service-1", "/etc/app.conf")
+
+	f, _ := ioutil.TempFile("", "db.conf")
+	defer os.Remove(f.Name())
+	f.WriteString("pool=25\n")
+	f.Close()
+	InitConfigConflictAPITest("serviced", "service", "config-conflicts", "resolve", "--file", f.Name(), "test-service-1", "/etc/db.conf")
+
+	// Output:
+	// file-1
+	// file-2
+}
+
+func ExampleServicedCLI_CmdConfigConflictsResolve_err() {
+	pipeStderr(InitConfigConflictAPITest, "serviced", "service", "config-conflicts", "resolve", "test-service-1", "/etc/db.conf")
+	pipeStderr(InitConfigConflictAPITest, "serviced", "service", "config-conflicts", "resolve", "--mine", "--theirs", "test-service-1", "/etc/db.conf")
+	pipeStderr(InitConfigConflictAPITest, "serviced", "service", "config-conflicts", "resolve", "--merged", "test-service-1", "/etc/db.conf")
+	pipeStderr(InitConfigConflictAPITest, "serviced", "service", "config-conflicts", "resolve", "--mine", "test-service-1", "/etc/missing.conf")
+
+	// Output:
+	// Specify exactly one of --mine, --theirs, --merged, or --file
+	// Specify exactly one of --mine, --theirs, --merged, or --file
+	// /etc/db.conf: config file versions do not merge cleanly
+	// no config conflict found for /etc/missing.conf
+}
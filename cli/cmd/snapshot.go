@@ -21,6 +21,7 @@ import (
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
 // initSnapshot is the initializer for serviced snapshot
@@ -199,8 +200,8 @@ func (c *ServicedCli) cmdSnapshotList(ctx *cli.Context) {
 	if snapshots == nil || len(snapshots) == 0 {
 		fmt.Fprintln(os.Stderr, "no snapshots found")
 	} else {
-		if showTags { //print a table of snapshot, description, tag list
-			t := NewTable("Snapshot,Description,Tags")
+		if showTags { //print a table of snapshot, description, tag list and provenance
+			t := NewTable("Snapshot,Description,Tags,User,Source,Images,Groups")
 			for _, s := range snapshots {
 				//build a comma-delimited list of the tags
 				tags := strings.Join(s.Tags, ",")
@@ -214,6 +215,10 @@ func (c *ServicedCli) cmdSnapshotList(ctx *cli.Context) {
 				row["Snapshot"] = snapshotID
 				row["Description"] = s.Description
 				row["Tags"] = tags
+				row["User"] = s.User
+				row["Source"] = s.Source
+				row["Images"] = strings.Join(s.ImageIDs, ",")
+				row["Groups"] = snapshotGroups(s.Groups)
 				t.Padding = 6
 				t.AddRow(row)
 			}
@@ -228,6 +233,16 @@ func (c *ServicedCli) cmdSnapshotList(ctx *cli.Context) {
 	return
 }
 
+// snapshotGroups describes the consistency groups of a snapshot, listing the
+// members of each group in the order they were paused
+func snapshotGroups(groups []servicedefinition.SnapshotGroup) string {
+	descs := make([]string, len(groups))
+	for i, group := range groups {
+		descs[i] = fmt.Sprintf("%s(%s)", group.Name, strings.Join(group.ServiceIDs, ","))
+	}
+	return strings.Join(descs, " ")
+}
+
 // serviced snapshot add SERVICEID [--tags=<tag1>,<tag2>...]
 func (c *ServicedCli) cmdSnapshotAdd(ctx *cli.Context) {
 	nArgs := len(ctx.Args())
@@ -23,6 +23,7 @@ import (
 
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/volume/btrfs"
 )
@@ -35,7 +36,7 @@ var DefaultSnapshotAPITest = SnapshotAPITest{snapshots: DefaultTestSnapshots}
 
 var DefaultTestSnapshots = []dao.SnapshotInfo{
 	dao.SnapshotInfo{SnapshotID: "test-service-1-snapshot-1", TenantID: "test-service-1", Description: "description 1", Tags: []string{"tag-1"}},
-	dao.SnapshotInfo{SnapshotID: "test-service-1-snapshot-2", TenantID: "test-service-1", Description: "description 2", Tags: []string{"tag-2", "tag-3"}},
+	dao.SnapshotInfo{SnapshotID: "test-service-1-snapshot-2", TenantID: "test-service-1", Description: "description 2", Tags: []string{"tag-2", "tag-3"}, User: "zenoss", Source: "shell", ImageIDs: []string{"test-service-1/repo"}, Groups: []servicedefinition.SnapshotGroup{{Name: "app", ServiceIDs: []string{"web", "db"}}}},
 	dao.SnapshotInfo{SnapshotID: "test-service-1-invalid", Invalid: true},
 	dao.SnapshotInfo{SnapshotID: "test-service-2-snapshot-1", TenantID: "test-service-2", Description: "", Tags: []string{""}},
 	dao.SnapshotInfo{SnapshotID: "test-service-2-invalid", Invalid: true},
@@ -195,9 +196,9 @@ func ExampleServicedCLI_CmdSnapshotList() {
 func TestServicedCLI_CmdSnapshotList_ShowTagsShort(t *testing.T) {
 	output := pipe(InitSnapshotAPITest, "serviced", "snapshot", "list", "-t")
 	expected :=
-		"Snapshot                                 Description        Tags" +
+		"Snapshot                                 Description        Tags             User        Source      Images                   Groups" +
 			"\ntest-service-1-snapshot-1                description 1      tag-1" +
-			"\ntest-service-1-snapshot-2                description 2      tag-2,tag-3" +
+			"\ntest-service-1-snapshot-2                description 2      tag-2,tag-3      zenoss      shell       test-service-1/repo      app(web,db)" +
 			"\ntest-service-1-invalid [DEPRECATED]" +
 			"\ntest-service-2-snapshot-1" +
 			"\ntest-service-2-invalid [DEPRECATED]"
@@ -213,9 +214,9 @@ func TestServicedCLI_CmdSnapshotList_ShowTagsShort(t *testing.T) {
 func TestServicedCLI_CmdSnapshotList_ShowTagsLong(t *testing.T) {
 	output := pipe(InitSnapshotAPITest, "serviced", "snapshot", "list", "--show-tags")
 	expected :=
-		"Snapshot                                 Description        Tags" +
+		"Snapshot                                 Description        Tags             User        Source      Images                   Groups" +
 			"\ntest-service-1-snapshot-1                description 1      tag-1" +
-			"\ntest-service-1-snapshot-2                description 2      tag-2,tag-3" +
+			"\ntest-service-1-snapshot-2                description 2      tag-2,tag-3      zenoss      shell       test-service-1/repo      app(web,db)" +
 			"\ntest-service-1-invalid [DEPRECATED]" +
 			"\ntest-service-2-snapshot-1" +
 			"\ntest-service-2-invalid [DEPRECATED]"
@@ -240,9 +241,9 @@ func ExampleServicedCLI_CmdSnapshotList_byServiceID() {
 func TestServicedCLI_CmdSnapshotList_byServiceID_ShowTagsShort(t *testing.T) {
 	output := pipe(InitSnapshotAPITest, "serviced", "snapshot", "list", "test-service-1", "-t")
 	expected :=
-		"Snapshot                                 Description        Tags" +
+		"Snapshot                                 Description        Tags             User        Source      Images                   Groups" +
 			"\ntest-service-1-snapshot-1                description 1      tag-1" +
-			"\ntest-service-1-snapshot-2                description 2      tag-2,tag-3" +
+			"\ntest-service-1-snapshot-2                description 2      tag-2,tag-3      zenoss      shell       test-service-1/repo      app(web,db)" +
 			"\ntest-service-1-invalid [DEPRECATED]"
 
 	outStr := TrimLines(fmt.Sprintf("%s", output))
@@ -257,9 +258,9 @@ func TestServicedCLI_CmdSnapshotList_byServiceID_ShowTagsShort(t *testing.T) {
 func TestervicedCLI_CmdSnapshotList_byServiceID_ShowTagsLong(t *testing.T) {
 	output := pipe(InitSnapshotAPITest, "serviced", "snapshot", "list", "test-service-1", "--show-tags")
 	expected :=
-		"Snapshot                                 Description        Tags" +
+		"Snapshot                                 Description        Tags             User        Source      Images                   Groups" +
 			"\ntest-service-1-snapshot-1                description 1      tag-1" +
-			"\ntest-service-1-snapshot-2                description 2      tag-2,tag-3" +
+			"\ntest-service-1-snapshot-2                description 2      tag-2,tag-3      zenoss      shell       test-service-1/repo      app(web,db)" +
 			"\ntest-service-1-invalid [DEPRECATED]"
 
 	outStr := TrimLines(fmt.Sprintf("%s", output))
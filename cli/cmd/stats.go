@@ -0,0 +1,181 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+)
+
+// Initializer for serviced stats
+func (c *ServicedCli) initStats() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:  "stats",
+		Usage: "Displays statistics about serviced",
+		Subcommands: []cli.Command{
+			{
+				Name:  "cli",
+				Usage: "Summarizes the duration of cli commands",
+				Description: "serviced stats cli\n\n" +
+					"   Commands are only recorded if SERVICED_CLI_TELEMETRY_FILE is set.",
+				Action: c.cmdStatsCLI,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "since",
+						Value: "",
+						Usage: "Only include commands run within this duration, e.g. 24h",
+					},
+					cli.StringFlag{
+						Name:  "command",
+						Value: "",
+						Usage: "Only include commands that start with this name, e.g. 'service'",
+					},
+				},
+			},
+		},
+	})
+}
+
+// commandSummary summarizes the records of a single command
+type commandSummary struct {
+	count         int
+	errors        int
+	durations     []time.Duration
+	masterCalls   int64
+	masterLatency time.Duration
+}
+
+func (s *commandSummary) add(record CommandRecord) {
+	s.count++
+	if record.Result != CommandOK {
+		s.errors++
+	}
+	s.durations = append(s.durations, record.Duration)
+	s.masterCalls += record.MasterCalls
+	s.masterLatency += record.MasterLatency
+}
+
+// percentile returns the duration below which p percent of the durations fall
+func (s *commandSummary) percentile(p int) time.Duration {
+	durations := make([]time.Duration, len(s.durations))
+	copy(durations, s.durations)
+	sort.Sort(durationSlice(durations))
+	i := (len(durations)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return durations[i]
+}
+
+func (s *commandSummary) average(total time.Duration) time.Duration {
+	return total / time.Duration(s.count)
+}
+
+type durationSlice []time.Duration
+
+func (d durationSlice) Len() int           { return len(d) }
+func (d durationSlice) Less(i, j int) bool { return d[i] < d[j] }
+func (d durationSlice) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+
+// bySlowest sorts the names of commands by their 95th percentile duration
+type bySlowest struct {
+	names     []string
+	summaries map[string]*commandSummary
+}
+
+func (s bySlowest) Len() int      { return len(s.names) }
+func (s bySlowest) Swap(i, j int) { s.names[i], s.names[j] = s.names[j], s.names[i] }
+func (s bySlowest) Less(i, j int) bool {
+	pi, pj := s.summaries[s.names[i]].percentile(95), s.summaries[s.names[j]].percentile(95)
+	if pi != pj {
+		return pi > pj
+	}
+	return s.names[i] < s.names[j]
+}
+
+// serviced stats cli [--since DURATION] [--command NAME]
+func (c *ServicedCli) cmdStatsCLI(ctx *cli.Context) {
+	filename := c.telemetryFile()
+	if filename == "" {
+		fmt.Fprintln(os.Stderr, "cli telemetry is disabled; set SERVICED_CLI_TELEMETRY_FILE to enable it")
+		return
+	}
+
+	var since time.Time
+	if s := ctx.String("since"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid duration %s: %s\n", s, err)
+			return
+		}
+		since = time.Now().Add(-d)
+	}
+	prefix := ctx.String("command")
+
+	records, err := readCommandRecords(filename)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	summaries := make(map[string]*commandSummary)
+	for _, record := range records {
+		if record.Time.Before(since) || !strings.HasPrefix(record.Command, prefix) {
+			continue
+		}
+		summary, ok := summaries[record.Command]
+		if !ok {
+			summary = &commandSummary{}
+			summaries[record.Command] = summary
+		}
+		summary.add(record)
+	}
+	if len(summaries) == 0 {
+		fmt.Fprintln(os.Stderr, "no commands found")
+		return
+	}
+
+	// slowest commands first
+	names := make([]string, 0, len(summaries))
+	for name := range summaries {
+		names = append(names, name)
+	}
+	sort.Sort(bySlowest{names, summaries})
+
+	t := NewTable("Command,Count,Errors,Avg,P95,Max,MasterCalls,MasterLatency")
+	t.Padding = 4
+	for _, name := range names {
+		summary := summaries[name]
+		var total time.Duration
+		for _, d := range summary.durations {
+			total += d
+		}
+		t.AddRow(map[string]interface{}{
+			"Command":       name,
+			"Count":         summary.count,
+			"Errors":        summary.errors,
+			"Avg":           summary.average(total).Round(time.Millisecond),
+			"P95":           summary.percentile(95).Round(time.Millisecond),
+			"Max":           summary.percentile(100).Round(time.Millisecond),
+			"MasterCalls":   summary.masterCalls / int64(summary.count),
+			"MasterLatency": summary.average(summary.masterLatency).Round(time.Millisecond),
+		})
+	}
+	t.Print()
+}
@@ -0,0 +1,126 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/control-center/serviced/utils"
+)
+
+// statsCLI returns a function that runs the cli with telemetry written to
+// filename
+func statsCLI(filename string) func(...string) {
+	return func(args ...string) {
+		c := New(DefaultServiceAPITest, utils.TestConfigReader{"CLI_TELEMETRY_FILE": filename})
+		c.exitDisabled = true
+		c.Run(args)
+	}
+}
+
+func tempTelemetryFile() string {
+	f, err := ioutil.TempFile("", "serviced-telemetry")
+	if err != nil {
+		panic(err)
+	}
+	f.Close()
+	return f.Name()
+}
+
+func ExampleServicedCLI_CmdStatsCLI_record() {
+	filename := tempTelemetryFile()
+	defer os.Remove(filename)
+	run := statsCLI(filename)
+
+	run("serviced", "--endpoint", "localhost:4979", "service", "list", "--show-fields", "Name")
+	pipeStderr(run, "serviced", "service", "shell", "test-service-0")
+
+	records, err := readCommandRecords(filename)
+	if err != nil {
+		panic(err)
+	}
+	for _, record := range records {
+		fmt.Println(record.Command, record.Result, record.ExitCode)
+	}
+
+	// Output:
+	// Name
+	// Zenoss
+	// Zope
+	// zencommand
+	// service not found
+	// exit code 1
+	// service list ok 0
+	// service shell error 1
+}
+
+func ExampleServicedCLI_CmdStatsCLI() {
+	filename := tempTelemetryFile()
+	defer os.Remove(filename)
+
+	now := time.Now()
+	for i, d := range []time.Duration{100, 200, 300, 400} {
+		appendCommandRecord(filename, CommandRecord{
+			Time:          now,
+			Command:       "service list",
+			Duration:      d * time.Millisecond,
+			Result:        CommandOK,
+			MasterCalls:   2,
+			MasterLatency: d * time.Millisecond / 2,
+		})
+		if i == 0 {
+			appendCommandRecord(filename, CommandRecord{
+				Time:          now,
+				Command:       "service start",
+				Duration:      2 * time.Second,
+				Result:        CommandError,
+				ExitCode:      1,
+				MasterCalls:   3,
+				MasterLatency: time.Second,
+			})
+			appendCommandRecord(filename, CommandRecord{
+				Time:     now.Add(-48 * time.Hour),
+				Command:  "host list",
+				Duration: time.Second,
+				Result:   CommandOK,
+			})
+		}
+	}
+
+	run := statsCLI(filename)
+	run("serviced", "stats", "cli")
+	run("serviced", "stats", "cli", "--since", "24h", "--command", "service l")
+
+	// Output:
+	// Command          Count    Errors    Avg      P95      Max      MasterCalls    MasterLatency
+	// service start    1        1         2s       2s       2s       3              1s
+	// host list        1        0         1s       1s       1s       0              0s
+	// service list     4        0         250ms    400ms    400ms    2              125ms
+	// Command         Count    Errors    Avg      P95      Max      MasterCalls    MasterLatency
+	// service list    4        0         250ms    400ms    400ms    2              125ms
+}
+
+func ExampleServicedCLI_CmdStatsCLI_disabled() {
+	pipeStderr(InitServiceAPITest, "serviced", "stats", "cli")
+	pipeStderr(statsCLI("/nonexistent/telemetry"), "serviced", "stats", "cli")
+
+	// Output:
+	// cli telemetry is disabled; set SERVICED_CLI_TELEMETRY_FILE to enable it
+	// no commands found
+}
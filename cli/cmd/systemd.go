@@ -0,0 +1,185 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/utils"
+)
+
+const (
+	// systemdDropInPath is where the generated drop-in for serviced.service
+	// is installed
+	systemdDropInPath = "/etc/systemd/system/serviced.service.d/serviced.conf"
+
+	// systemdEnvFile is the default path of the generated environment file
+	systemdEnvFile = "/etc/default/serviced"
+)
+
+// numberedConfigKeys are the settings that the config reader accepts as
+// numbered lists (e.g. SERVICED_ISVCS_ENV_0, SERVICED_ISVCS_ENV_1, ...)
+var numberedConfigKeys = []string{"ISVCS_ENV", "ISVCS_RESOURCE"}
+
+// numberedConfigKey matches one item of a numbered list
+var numberedConfigKey = regexp.MustCompile(`^(.+)_[0-9]+$`)
+
+// serviced config generate-systemd [--role ROLE] [--env-file PATH] [--output DIR]
+func (c *ServicedCli) cmdConfigGenerateSystemd(ctx *cli.Context) {
+	options := config.GetOptions()
+	switch role := ctx.String("role"); role {
+	case "":
+	case "master":
+		options.Master = true
+	case "delegate":
+		options.Master = false
+	default:
+		fmt.Fprintf(os.Stderr, "invalid role %q: must be master or delegate\n", role)
+		c.exit(1)
+		return
+	}
+	options.Agent = true
+	if err := api.ValidateServerOptions(&options); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	envFile := ctx.String("env-file")
+	if !filepath.IsAbs(envFile) {
+		fmt.Fprintf(os.Stderr, "environment file %s must be an absolute path\n", envFile)
+		c.exit(1)
+		return
+	}
+	files := []struct {
+		path    string
+		content []byte
+	}{
+		{path: systemdDropInPath, content: renderSystemdDropIn(envFile, options.Master)},
+		{path: envFile, content: renderSystemdEnvironment(c.config.GetConfigValues(), options.Master)},
+	}
+
+	output := ctx.String("output")
+	if output == "" {
+		for i, f := range files {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("# %s\n%s", f.path, f.content)
+		}
+		return
+	}
+	for _, f := range files {
+		filename := filepath.Join(output, f.path)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		if err := ioutil.WriteFile(filename, f.content, 0644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		fmt.Println(filename)
+	}
+}
+
+// systemdRole names the role of a host in the generated files
+func systemdRole(master bool) string {
+	if master {
+		return "master"
+	}
+	return "delegate"
+}
+
+// renderSystemdDropIn returns a drop-in for serviced.service that loads the
+// generated environment file in place of the packaged one.
+func renderSystemdDropIn(envFile string, master bool) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# Generated by serviced config generate-systemd for a %s\n", systemdRole(master))
+	fmt.Fprintf(buf, "[Unit]\nDescription=Zenoss ServiceD (%s)\n", systemdRole(master))
+	fmt.Fprintf(buf, "\n[Service]\n")
+	fmt.Fprintf(buf, "Environment=SERVICED_MASTER=%s SERVICED_AGENT=1\n", systemdFlag(master))
+	fmt.Fprintf(buf, "EnvironmentFile=\nEnvironmentFile=%s\n", envFile)
+	return buf.Bytes()
+}
+
+// renderSystemdEnvironment returns an environment file holding every
+// non-empty setting the config reader resolved, with the role settings
+// overridden.  The values are those the reader accepted, so settings that
+// failed to parse are written with their defaults.
+func renderSystemdEnvironment(values map[string]utils.ConfigValue, master bool) []byte {
+	env := make(map[string]string)
+	for key, value := range values {
+		if m := numberedConfigKey.FindStringSubmatch(key); m != nil && utils.StringInSlice(m[1], numberedConfigKeys) {
+			// written below from the combined list
+			continue
+		} else if utils.StringInSlice(key, numberedConfigKeys) {
+			if value.Value != "" {
+				for i, item := range strings.Split(value.Value, ",") {
+					env[fmt.Sprintf("%s_%d", value.Name, i)] = item
+				}
+			}
+			continue
+		}
+		if value.Value != "" {
+			env[value.Name] = value.Value
+		}
+	}
+	env["SERVICED_MASTER"] = systemdFlag(master)
+	env["SERVICED_AGENT"] = "1"
+
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# Generated by serviced config generate-systemd for a %s\n", systemdRole(master))
+	for _, name := range names {
+		fmt.Fprintf(buf, "%s=%s\n", name, systemdEnvValue(env[name]))
+	}
+	return buf.Bytes()
+}
+
+// systemdFlag returns the value of a boolean serviced environment variable;
+// SERVICED_MASTER and SERVICED_AGENT are only honored when set to 1.
+func systemdFlag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// systemdEnvValue quotes a value containing whitespace or shell characters so
+// that both systemd and the serviced config reader read it back unchanged.
+func systemdEnvValue(value string) string {
+	if !strings.ContainsAny(value, " \t\"'\\$`") {
+		return value
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+	return `"` + r.Replace(value) + `"`
+}
@@ -0,0 +1,111 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/control-center/serviced/utils"
+)
+
+func systemdCLI(args ...string) {
+	c := New(DefaultServiceAPITest, utils.TestConfigReader{"ENDPOINT": "10.0.0.1:4979"})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func TestRenderSystemdEnvironment(t *testing.T) {
+	values := map[string]utils.ConfigValue{
+		"MASTER":         {Name: "SERVICED_MASTER", Value: "true"},
+		"ZK":             {Name: "SERVICED_ZK", Value: "10.0.0.1:2181"},
+		"OUTBOUND_IP":    {Name: "SERVICED_OUTBOUND_IP", Value: ""},
+		"UI_PORT":        {Name: "SERVICED_UI_PORT", Value: ":443"},
+		"LOG_ADDRESS":    {Name: "SERVICED_LOG_ADDRESS", Value: "a b$c"},
+		"ISVCS_ENV":      {Name: "SERVICED_ISVCS_ENV", Value: "zk:A=1,es:B=2"},
+		"ISVCS_ENV_0":    {Name: "SERVICED_ISVCS_ENV_0", Value: "zk:A=1"},
+		"ISVCS_RESOURCE": {Name: "SERVICED_ISVCS_RESOURCE", Value: ""},
+		"TZ":             {Name: "TZ", Value: "UTC"},
+	}
+	expected := `# Generated by serviced config generate-systemd for a delegate
+SERVICED_AGENT=1
+SERVICED_ISVCS_ENV_0=zk:A=1
+SERVICED_ISVCS_ENV_1=es:B=2
+SERVICED_LOG_ADDRESS="a b\$c"
+SERVICED_MASTER=0
+SERVICED_UI_PORT=:443
+SERVICED_ZK=10.0.0.1:2181
+TZ=UTC
+`
+	if actual := string(renderSystemdEnvironment(values, false)); actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func ExampleServicedCLI_CmdConfigGenerateSystemd() {
+	systemdCLI("serviced", "config", "generate-systemd", "--role", "master", "--env-file", "/etc/serviced/env")
+
+	// Output:
+	// # /etc/systemd/system/serviced.service.d/serviced.conf
+	// # Generated by serviced config generate-systemd for a master
+	// [Unit]
+	// Description=Zenoss ServiceD (master)
+	//
+	// [Service]
+	// Environment=SERVICED_MASTER=1 SERVICED_AGENT=1
+	// EnvironmentFile=
+	// EnvironmentFile=/etc/serviced/env
+	//
+	// # /etc/serviced/env
+	// # Generated by serviced config generate-systemd for a master
+	// SERVICED_AGENT=1
+	// SERVICED_MASTER=1
+}
+
+func TestServicedCLI_CmdConfigGenerateSystemd_output(t *testing.T) {
+	dir, err := ioutil.TempDir("", "serviced-systemd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	systemdCLI("serviced", "config", "generate-systemd", "--role", "delegate", "--output", dir)
+	for _, name := range []string{systemdDropInPath, systemdEnvFile} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(data), "# Generated by serviced config generate-systemd for a delegate\n") {
+			t.Errorf("unexpected content of %s:\n%s", name, data)
+		}
+	}
+	data, _ := ioutil.ReadFile(filepath.Join(dir, systemdEnvFile))
+	if !strings.Contains(string(data), "\nSERVICED_MASTER=0\n") {
+		t.Errorf("expected a delegate environment, got:\n%s", data)
+	}
+}
+
+func ExampleServicedCLI_CmdConfigGenerateSystemd_err() {
+	pipeStderr(systemdCLI, "serviced", "config", "generate-systemd", "--role", "worker")
+	pipeStderr(systemdCLI, "serviced", "config", "generate-systemd", "--env-file", "serviced.env")
+
+	// Output:
+	// invalid role "worker": must be master or delegate
+	// environment file serviced.env must be an absolute path
+}
@@ -14,8 +14,16 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
+	"text/template"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/utils"
 )
 
 var (
@@ -47,10 +55,96 @@ func init() {
 	treeCharset = treeUTF8
 }
 
+// tableColor is true if new tables color their status fields
+var tableColor = false
+
+// ANSI colors of status values
+const (
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorRed    = "\x1b[31m"
+	colorReset  = "\x1b[0m"
+	colorBold   = "\x1b[1m"
+)
+
+// statusColors maps the first word of a status value to its color
+var statusColors = map[string]string{
+	"running":     colorGreen,
+	"passed":      colorGreen,
+	"ok":          colorGreen,
+	"starting":    colorYellow,
+	"stopping":    colorYellow,
+	"pausing":     colorYellow,
+	"paused":      colorYellow,
+	"scheduled":   colorYellow,
+	"warning":     colorYellow,
+	"unknown":     colorYellow,
+	"not_running": colorYellow,
+	"failed":      colorRed,
+	"timeout":     colorRed,
+	"critical":    colorRed,
+	"x":           colorRed,
+	"emergency":   colorRed,
+}
+
+// statusColor returns the color of a status value, or "" if the value is not
+// a known status
+func statusColor(value interface{}) string {
+	if status, ok := value.(health.Status); ok {
+		switch status {
+		case health.OK:
+			return colorGreen
+		case health.Failed, health.Timeout:
+			return colorRed
+		default:
+			return colorYellow
+		}
+	}
+	fields := strings.Fields(strings.ToLower(fmt.Sprintf("%v", value)))
+	if len(fields) == 0 {
+		return ""
+	}
+	return statusColors[strings.TrimSuffix(fields[0], ":")]
+}
+
+// colorFlag is the flag accepted by every command that colors its status
+// values
+func colorFlag() cli.Flag {
+	return cli.StringFlag{
+		Name:  "color",
+		Value: "",
+		Usage: "Color status values: auto, always, or never (env SERVICED_COLOR, default auto; auto is disabled when NO_COLOR is set)",
+	}
+}
+
+// cmdSetTableColor colors the status values of new tables if requested, or
+// if stdout is a terminal and NO_COLOR is not set.
+func cmdSetTableColor(ctx *cli.Context, config utils.ConfigReader) {
+	mode := ctx.String("color")
+	if mode == "" {
+		mode = config.StringVal("COLOR", "auto")
+	}
+	switch mode {
+	case "always":
+		tableColor = true
+	case "never":
+		tableColor = false
+	default:
+		tableColor = os.Getenv("NO_COLOR") == "" && utils.Isatty(os.Stdout)
+	}
+}
+
 type Table struct {
 	Fields                 []string
 	Padding                int
+	NoHeader               bool           // Do not print the header row
+	MaxWidth               map[string]int // Truncate the values of a field to a width; "" applies to every field
+	Color                  bool           // Color the values of the status fields
+	StatusFields           []string       // Fields whose values are colored by status
+	columns                []tableColumn
 	rows                   []map[string]string
+	colors                 []map[string]string
+	highlights             map[int]bool
 	fieldSize              map[string]int
 	treeIndent             []int
 	rowsAddedSinceLastDent bool
@@ -65,26 +159,158 @@ func NewTable(fieldString string) *Table {
 	return &Table{
 		Fields:     fields,
 		Padding:    1,
+		MaxWidth:   make(map[string]int),
+		Color:      tableColor,
 		rows:       make([]map[string]string, 0),
 		fieldSize:  make(map[string]int),
 		treeIndent: make([]int, 0),
 	}
 }
+
+// tableColumn is a column computed from the other values of a row
+type tableColumn struct {
+	Name     string
+	Template *template.Template
+}
+
+// tableFlags are the flags accepted by every command that prints a table with
+// --show-fields
+func tableFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "add-column",
+			Value: &cli.StringSlice{},
+			Usage: "Add a column computed from the fields of each row, e.g. 'Mem={{.RAM}}'",
+		},
+		cli.StringSliceFlag{
+			Name:  "max-width",
+			Value: &cli.StringSlice{},
+			Usage: "Truncate the values of a field to a width, e.g. 'Name=20', or of every field, e.g. '20'",
+		},
+		cli.BoolFlag{
+			Name:  "no-header",
+			Usage: "Do not print the header row",
+		},
+	}
+}
+
+// newTableFromContext creates a table with the given fields and the columns,
+// widths, and header set by tableFlags.
+func newTableFromContext(ctx *cli.Context, fieldString string) (*Table, error) {
+	t := NewTable(fieldString)
+	t.NoHeader = ctx.Bool("no-header")
+	for _, column := range ctx.StringSlice("add-column") {
+		parts := strings.SplitN(column, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("column %q must be NAME=TEMPLATE", column)
+		}
+		if err := t.AddColumn(strings.TrimSpace(parts[0]), parts[1]); err != nil {
+			return nil, err
+		}
+	}
+	for _, width := range ctx.StringSlice("max-width") {
+		var field, value string
+		if parts := strings.SplitN(width, "=", 2); len(parts) == 2 {
+			field, value = strings.TrimSpace(parts[0]), parts[1]
+		} else {
+			value = parts[0]
+		}
+		w, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || w <= 0 {
+			return nil, fmt.Errorf("max width %q must be a positive number", width)
+		}
+		t.MaxWidth[field] = w
+	}
+	return t, nil
+}
+
+// AddColumn adds a column whose value is computed by executing a go template
+// against each row as it is added.  The column is displayed after the other
+// fields unless it is already one of the fields.
+func (t *Table) AddColumn(name, text string) error {
+	tmpl, err := parseFormat(name, text)
+	if err != nil {
+		return fmt.Errorf("could not parse column %s: %s", name, err)
+	}
+	t.columns = append(t.columns, tableColumn{Name: name, Template: tmpl})
+	for _, field := range t.Fields {
+		if field == name {
+			return nil
+		}
+	}
+	t.Fields = append(t.Fields, name)
+	return nil
+}
+
+// truncate shortens a value to the max width of its field
+func (t *Table) truncate(field, value string) string {
+	width, ok := t.MaxWidth[field]
+	if !ok {
+		if width, ok = t.MaxWidth[""]; !ok {
+			return value
+		}
+	}
+	if runes := []rune(value); len(runes) > width {
+		if width <= 3 {
+			return string(runes[:width])
+		}
+		return string(runes[:width-3]) + "..."
+	}
+	return value
+}
+
 func (t *Table) AddRow(row map[string]interface{}) {
+	if len(t.columns) > 0 {
+		// compute the columns against a copy, so that a column may use the
+		// columns added before it
+		values := make(map[string]interface{})
+		for name, value := range row {
+			values[name] = value
+		}
+		for _, column := range t.columns {
+			buffer := &bytes.Buffer{}
+			if err := column.Template.Execute(buffer, values); err != nil {
+				values[column.Name] = "--"
+			} else {
+				values[column.Name] = buffer.String()
+			}
+		}
+		row = values
+	}
 	tblrow := make(map[string]string)
 	for name, value := range row {
-		v := fmt.Sprintf("%v", value)
+		v := t.truncate(name, fmt.Sprintf("%v", value))
 		tblrow[name] = v
 		if maxWidth := len(v); t.fieldSize[name] < maxWidth {
 			t.fieldSize[name] = maxWidth
 		}
 	}
 	t.rows = append(t.rows, tblrow)
+	colors := make(map[string]string)
+	for _, field := range t.StatusFields {
+		if value, ok := row[field]; ok {
+			colors[field] = statusColor(value)
+		}
+	}
+	t.colors = append(t.colors, colors)
 	if len(t.rows) > len(t.treeIndent) {
 		t.treeIndent = append(t.treeIndent, 0)
 	}
 	t.rowsAddedSinceLastDent = true
 }
+
+// HighlightRow marks the last row added, so that it is printed in bold if the
+// table is colored
+func (t *Table) HighlightRow() {
+	if len(t.rows) == 0 {
+		return
+	}
+	if t.highlights == nil {
+		t.highlights = make(map[int]bool)
+	}
+	t.highlights[len(t.rows)-1] = true
+}
+
 func (t *Table) IndentRow() {
 	if t.rowsAddedSinceLastDent || len(t.treeIndent) == 0 {
 		t.treeIndent = append(t.treeIndent, 1)
@@ -124,21 +350,50 @@ func (t *Table) Print() {
 			}
 			fieldSize = col0width
 		}
-		fmt.Printf("%-"+fmt.Sprintf("%d", fieldSize)+"s"+padding, field)
+		if !t.NoHeader {
+			fmt.Printf("%-"+fmt.Sprintf("%d", fieldSize)+"s"+padding, field)
+		}
+	}
+	if !t.NoHeader {
+		fmt.Printf("%-s\n", t.Fields[colCount-1])
 	}
-	fmt.Printf("%-s\n", t.Fields[colCount-1])
 
 	// display the rows
 	for i, row := range t.rows {
+		line := &bytes.Buffer{}
 		for j, field := range t.Fields[:colCount-1] {
 			if j > 0 {
-				fmt.Printf("%-"+fmt.Sprintf("%d", t.fieldSize[field])+"s"+padding, row[field])
+				line.WriteString(t.colorize(i, field, fmt.Sprintf("%-"+fmt.Sprintf("%d", t.fieldSize[field])+"s", row[field]), row[field]) + padding)
 			} else {
-				fmt.Printf("%-"+fmt.Sprintf("%d", col0width)+"s"+padding, col0rows[i])
+				line.WriteString(t.colorize(i, field, fmt.Sprintf("%-"+fmt.Sprintf("%d", col0width)+"s", col0rows[i]), col0rows[i]) + padding)
 			}
 		}
-		fmt.Printf("%-s\n", row[t.Fields[colCount-1]])
+		field := t.Fields[colCount-1]
+		line.WriteString(t.colorize(i, field, row[field], row[field]))
+		fmt.Printf("%-s\n", t.highlight(i, line.String()))
+	}
+}
+
+// highlight prints a highlighted row in bold, restoring the bold after each
+// colored value
+func (t *Table) highlight(i int, line string) string {
+	if !t.Color || !t.highlights[i] {
+		return line
+	}
+	return colorBold + strings.Replace(line, colorReset, colorReset+colorBold, -1) + colorReset
+}
+
+// colorize wraps the value within a padded cell in the color of its status,
+// leaving the padding uncolored
+func (t *Table) colorize(i int, field, cell, value string) string {
+	if !t.Color || i >= len(t.colors) {
+		return cell
+	}
+	color := t.colors[i][field]
+	if color == "" || value == "" {
+		return cell
 	}
+	return strings.Replace(cell, value, color+value+colorReset, 1)
 }
 func (t *Table) getIndents(field string) (int, []string) {
 	// determines if the row is the last parent in the tree
@@ -0,0 +1,57 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/health"
+)
+
+func TestStatusColor(t *testing.T) {
+	for value, expected := range map[interface{}]string{
+		"running":                    colorGreen,
+		"Paused":                     colorYellow,
+		"failed: exit status 1":      colorRed,
+		"critical (docker root 95%)": colorRed,
+		health.Status(health.OK):     colorGreen,
+		health.Status(health.Failed): colorRed,
+		"stopped":                    "",
+		"":                           "",
+	} {
+		if actual := statusColor(value); actual != expected {
+			t.Errorf("status %v: expected color %q, got %q", value, expected, actual)
+		}
+	}
+}
+
+func TestTableHighlightRow(t *testing.T) {
+	table := NewTable("Name,Status")
+	table.Color = true
+	table.StatusFields = []string{"Status"}
+	table.AddRow(map[string]interface{}{"Name": "a", "Status": "running"})
+	table.HighlightRow()
+	table.AddRow(map[string]interface{}{"Name": "b", "Status": "running"})
+
+	line := table.highlight(0, "a "+colorGreen+"running"+colorReset)
+	expected := colorBold + "a " + colorGreen + "running" + colorReset + colorBold + colorReset
+	if line != expected {
+		t.Errorf("expected highlighted row %q, got %q", expected, line)
+	}
+	if line := table.highlight(1, "b"); line != "b" {
+		t.Errorf("expected row to not be highlighted, got %q", line)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/rpc/rpcutils"
+)
+
+// CommandRecord describes a single run of a cli command.  Arguments are not
+// recorded, only the names of the command and its subcommands.
+type CommandRecord struct {
+	Time          time.Time
+	Command       string
+	Duration      time.Duration
+	Result        string
+	ExitCode      int
+	MasterCalls   int64
+	MasterErrors  int64
+	MasterLatency time.Duration
+}
+
+// Command results
+const (
+	CommandOK    = "ok"
+	CommandError = "error"
+)
+
+// telemetryFile returns the file where command telemetry is written.
+// Telemetry is disabled if no file is configured.
+func (c *ServicedCli) telemetryFile() string {
+	return c.config.StringVal("CLI_TELEMETRY_FILE", "")
+}
+
+// commandName returns the names of the command and subcommands that are run
+// by the given arguments
+func (c *ServicedCli) commandName(args []string) string {
+	var names []string
+	commands := c.app.Commands
+	for i := 1; i < len(args); i++ {
+		if strings.HasPrefix(args[i], "-") {
+			continue
+		}
+		var found *cli.Command
+		for j := range commands {
+			if commands[j].HasName(args[i]) {
+				found = &commands[j]
+				break
+			}
+		}
+		if found == nil {
+			if len(names) > 0 {
+				break
+			}
+			// probably the value of a global flag
+			continue
+		}
+		names = append(names, found.Name)
+		commands = found.Subcommands
+	}
+	return strings.Join(names, " ")
+}
+
+// recordCommand appends the telemetry of the command that started running at
+// the given time to the telemetry file, if one is configured.
+func (c *ServicedCli) recordCommand(args []string, start time.Time, err error) {
+	filename := c.telemetryFile()
+	if filename == "" {
+		return
+	}
+	record := CommandRecord{
+		Time:     start,
+		Command:  c.commandName(args),
+		Duration: time.Since(start),
+		Result:   CommandOK,
+		ExitCode: c.exitCode,
+	}
+	if record.Command == "" {
+		// the daemon and help are not interesting
+		return
+	}
+	if err != nil || record.ExitCode != 0 {
+		record.Result = CommandError
+	}
+	stats := rpcutils.GetCallStats()
+	record.MasterCalls = stats.Calls
+	record.MasterErrors = stats.Errors
+	record.MasterLatency = stats.Duration
+
+	if err := appendCommandRecord(filename, record); err != nil {
+		log.WithError(err).WithField("file", filename).Debug("Unable to write command telemetry")
+	}
+}
+
+// appendCommandRecord writes a record as a line of json at the end of a file
+func appendCommandRecord(filename string, record CommandRecord) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(record)
+}
+
+// readCommandRecords reads the records of a telemetry file, skipping any
+// lines that cannot be parsed
+func readCommandRecords(filename string) ([]CommandRecord, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []CommandRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record CommandRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
@@ -14,7 +14,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -39,7 +38,7 @@ func (c *ServicedCli) initTemplate() {
 				Description:  "serviced template list [TEMPLATEID]",
 				BashComplete: c.printTemplatesFirst,
 				Action:       c.cmdTemplateList,
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					cli.BoolFlag{
 						Name:  "verbose, v",
 						Usage: "Show JSON format",
@@ -49,12 +48,23 @@ func (c *ServicedCli) initTemplate() {
 						Value: "TemplateID,Name,Description",
 						Usage: "Comma-delimited list describing which fields to display",
 					},
-				},
+				}, tableFlags()...),
 			}, {
 				Name:        "add",
 				Usage:       "Add a new template",
 				Description: "serviced template add FILE",
 				Action:      c.cmdTemplateAdd,
+			}, {
+				Name:        "validate",
+				Usage:       "Check a template for errors without adding it",
+				Description: "serviced template validate FILE",
+				Action:      c.cmdTemplateValidate,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "strict",
+						Usage: "Fail on warnings as well as errors",
+					},
+				},
 			}, {
 				Name:         "remove",
 				ShortName:    "rm",
@@ -158,7 +168,7 @@ func (c *ServicedCli) cmdTemplateList(ctx *cli.Context) {
 			fmt.Fprintln(os.Stderr, err)
 		} else if template == nil {
 			fmt.Fprintln(os.Stderr, "template not found")
-		} else if jsonTemplate, err := json.MarshalIndent(template, " ", "  "); err != nil {
+		} else if jsonTemplate, err := c.marshalOutput(template); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal template: %s\n", err)
 		} else {
 			fmt.Println(string(jsonTemplate))
@@ -176,13 +186,17 @@ func (c *ServicedCli) cmdTemplateList(ctx *cli.Context) {
 	}
 
 	if ctx.Bool("verbose") {
-		if jsonTemplate, err := json.MarshalIndent(templates, " ", "  "); err != nil {
+		if jsonTemplate, err := c.marshalOutput(templates); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal template list: %s\n", err)
 		} else {
 			fmt.Println(string(jsonTemplate))
 		}
 	} else {
-		t := NewTable(ctx.String("show-fields"))
+		t, err := newTableFromContext(ctx, ctx.String("show-fields"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
 		t.Padding = 6
 		for _, tmp := range templates {
 			t.AddRow(map[string]interface{}{
@@ -219,6 +233,49 @@ func (c *ServicedCli) cmdTemplateAdd(ctx *cli.Context) {
 	}
 }
 
+// serviced template validate [--strict] FILE
+func (c *ServicedCli) cmdTemplateValidate(ctx *cli.Context) {
+	var input *os.File
+
+	if filepath := ctx.Args().First(); filepath != "" {
+		var err error
+		if input, err = os.Open(filepath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(2)
+			return
+		}
+		defer input.Close()
+	} else {
+		input = os.Stdin
+	}
+
+	issues, err := c.driver.ValidateServiceTemplate(input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(2)
+		return
+	} else if len(issues) == 0 {
+		fmt.Println("Template is valid")
+		return
+	}
+
+	t := NewTable("Severity,Rule,Service,Message")
+	t.Padding = 6
+	for _, issue := range issues {
+		t.AddRow(map[string]interface{}{
+			"Severity": issue.Severity,
+			"Rule":     issue.Rule,
+			"Service":  issue.Service,
+			"Message":  issue.Message,
+		})
+	}
+	t.Print()
+
+	if template.LintFailed(issues, ctx.Bool("strict")) {
+		c.exit(1)
+	}
+}
+
 // serviced template remove TEMPLATEID ...
 func (c *ServicedCli) cmdTemplateRemove(ctx *cli.Context) {
 	args := ctx.Args()
@@ -317,7 +374,7 @@ func (c *ServicedCli) cmdTemplateCompile(ctx *cli.Context) {
 			"commit": strings.Trim(string(commit), "\n"),
 		}
 		mTemplate := metaTemplate{*template, servicedversion.GetVersion(), templateVersion}
-		jsonTemplate, err := json.MarshalIndent(mTemplate, " ", "  ")
+		jsonTemplate, err := c.marshalOutput(mTemplate)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal template: %s\n", err)
 		} else {
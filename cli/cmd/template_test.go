@@ -25,6 +25,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"testing"
 )
 
@@ -96,6 +98,14 @@ func (t TemplateAPITest) AddServiceTemplate(r io.Reader) (*template.ServiceTempl
 	return &template, nil
 }
 
+func (t TemplateAPITest) ValidateServiceTemplate(r io.Reader) ([]template.LintIssue, error) {
+	var tpl template.ServiceTemplate
+	if err := json.NewDecoder(r).Decode(&tpl); err != nil {
+		return nil, ErrInvalidTemplate
+	}
+	return template.Lint(&tpl), nil
+}
+
 func (t TemplateAPITest) RemoveServiceTemplate(id string) error {
 	if t, err := t.GetServiceTemplate(id); err != nil {
 		return err
@@ -219,6 +229,71 @@ func ExampleServicedCLI_CmdTemplateAdd_err() {
 	InitTemplateAPITest("serviced", "template", "add")
 }
 
+// writeTemplateFile writes the template to a temporary file and returns its
+// path
+func writeTemplateFile(tpl string) string {
+	f, err := ioutil.TempFile("", "template-validate")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	f.WriteString(tpl)
+	return f.Name()
+}
+
+func InitTemplateValidateTest(args ...string) {
+	c := New(DefaultTemplateAPITest, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func ExampleServicedCLI_CmdTemplateValidate() {
+	valid := writeTemplateFile(`{"Services": [{"Name": "Zenoss", "ImageID": "zenoss/core:5.2"}]}`)
+	defer os.Remove(valid)
+	warning := writeTemplateFile(`{"Services": [{"Name": "Zenoss", "ImageID": "zenoss/core"}]}`)
+	defer os.Remove(warning)
+
+	InitTemplateValidateTest("serviced", "template", "validate", valid)
+	InitTemplateValidateTest("serviced", "template", "validate", "--strict", warning)
+
+	// Output:
+	// Template is valid
+	// Severity      Rule           Service      Message
+	// warning       image-tag      /Zenoss      image zenoss/core does not specify a tag
+}
+
+func ExampleServicedCLI_CmdTemplateValidate_errors() {
+	tpl := writeTemplateFile(`{"Services": [{
+		"Name": "Zenoss",
+		"Actions": {"debug": "echo debug"},
+		"Services": [{
+			"Name": "Zope",
+			"ImageID": "zenoss/core:5.2",
+			"HealthChecks": {"answering": {"Script": "curl 'http://localhost"}},
+			"ConfigFiles": {"a": {"Filename": "/etc/zope.conf"}, "b": {"Filename": "/etc/zope.conf"}}
+		}]
+	}]}`)
+	defer os.Remove(tpl)
+
+	InitTemplateValidateTest("serviced", "template", "validate", tpl)
+
+	// Output:
+	// Severity      Rule                     Service           Message
+	// error         config-file-path         /Zenoss/Zope      config files a and b both write to /etc/zope.conf
+	// error         healthcheck              /Zenoss/Zope      health check answering has an unterminated quote
+	// error         unreachable-command      /Zenoss           action debug cannot be run because the service has no image
+}
+
+func ExampleServicedCLI_CmdTemplateValidate_err() {
+	tpl := writeTemplateFile(`{"Services": [`)
+	defer os.Remove(tpl)
+
+	pipeStderr(InitTemplateValidateTest, "serviced", "template", "validate", tpl)
+
+	// Output:
+	// invalid template
+}
+
 func ExampleServicedCLI_CmdTemplateRemove() {
 	InitTemplateAPITest("serviced", "template", "remove", "test-template-1")
 
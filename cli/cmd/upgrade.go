@@ -0,0 +1,109 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/host"
+)
+
+// Initializer for serviced upgrade-delegates
+func (c *ServicedCli) initUpgrade() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "upgrade-delegates",
+		Usage:       "Upgrades serviced on the delegates one host at a time",
+		Description: "serviced upgrade-delegates --version VERSION [--pool POOLID ...] [--drain] [--timeout DURATION]",
+		Action:      c.cmdUpgradeDelegates,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "version",
+				Value: "",
+				Usage: "Version of serviced to install on the delegates",
+			},
+			cli.StringSliceFlag{
+				Name:  "pool",
+				Value: &cli.StringSlice{},
+				Usage: "Resource pool to upgrade; upgrades all pools if not set",
+			},
+			cli.BoolFlag{
+				Name:  "drain",
+				Usage: "Stop the instances on each delegate before it is upgraded",
+			},
+			cli.StringFlag{
+				Name:  "timeout",
+				Value: "10m",
+				Usage: "Time to wait for each delegate to drain and come back online and healthy (e.g. 30s, 10m, 1h)",
+			},
+		},
+	})
+}
+
+// serviced upgrade-delegates --version VERSION [--pool POOLID ...] [--drain] [--timeout DURATION]
+func (c *ServicedCli) cmdUpgradeDelegates(ctx *cli.Context) {
+	version := ctx.String("version")
+	if version == "" {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "upgrade-delegates")
+		return
+	}
+
+	timeout, err := time.ParseDuration(ctx.String("timeout"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid timeout %s: %s\n", ctx.String("timeout"), err)
+		c.exit(1)
+		return
+	}
+
+	req := host.UpgradeRequest{
+		Version: version,
+		PoolIDs: ctx.StringSlice("pool"),
+		Drain:   ctx.Bool("drain"),
+		Timeout: timeout,
+	}
+	results, err := c.driver.UpgradeDelegates(req)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if len(results) == 0 {
+		fmt.Fprintln(os.Stderr, "no delegates found")
+		return
+	}
+
+	failed := false
+	t := NewTable("Host,Pool,Previous,Version,Status,Message")
+	t.Padding = 4
+	for _, result := range results {
+		if result.Status == host.UpgradeFailed {
+			failed = true
+		}
+		t.AddRow(map[string]interface{}{
+			"Host":     result.HostID,
+			"Pool":     result.PoolID,
+			"Previous": result.PreviousVersion,
+			"Version":  result.Version,
+			"Status":   result.Status,
+			"Message":  result.Message,
+		})
+	}
+	t.Print()
+	if failed {
+		fmt.Fprintln(os.Stderr, "upgrade halted; remaining delegates were not upgraded")
+		c.exit(1)
+	}
+}
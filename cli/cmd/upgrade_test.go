@@ -0,0 +1,89 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	mocks "github.com/control-center/serviced/cli/api/apimocks"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/utils"
+)
+
+func runUpgradeAPITest(driver api.API, args ...string) {
+	c := New(driver, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func ExampleServicedCLI_CmdUpgradeDelegates() {
+	driver := &mocks.API{}
+	driver.On("UpgradeDelegates", host.UpgradeRequest{
+		Version: "1.2.0",
+		PoolIDs: []string{"default"},
+		Drain:   true,
+		Timeout: 5 * time.Minute,
+	}).Return([]host.UpgradeResult{
+		{HostID: "host1", PoolID: "default", PreviousVersion: "1.2.0", Version: "1.2.0", Status: host.UpgradeSkipped, Message: "already running 1.2.0"},
+		{HostID: "host2", PoolID: "default", PreviousVersion: "1.1.9", Version: "1.2.0", Status: host.UpgradeUpgraded},
+	}, nil)
+	runUpgradeAPITest(driver, "serviced", "upgrade-delegates", "--version", "1.2.0", "--pool", "default", "--drain", "--timeout", "5m")
+
+	// Output:
+	// Host     Pool       Previous    Version    Status      Message
+	// host1    default    1.2.0       1.2.0      skipped     already running 1.2.0
+	// host2    default    1.1.9       1.2.0      upgraded
+}
+
+func ExampleServicedCLI_CmdUpgradeDelegates_usage() {
+	runUpgradeAPITest(&mocks.API{}, "serviced", "upgrade-delegates")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    upgrade-delegates - Upgrades serviced on the delegates one host at a time
+	//
+	// USAGE:
+	//    command upgrade-delegates [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced upgrade-delegates --version VERSION [--pool POOLID ...] [--drain] [--timeout DURATION]
+	//
+	// OPTIONS:
+	//    --version 				Version of serviced to install on the delegates
+	//    --pool '--pool option --pool option'	Resource pool to upgrade; upgrades all pools if not set
+	//    --drain				Stop the instances on each delegate before it is upgraded
+	//    --timeout '10m'			Time to wait for each delegate to drain and come back online and healthy (e.g. 30s, 10m, 1h)
+}
+
+func ExampleServicedCLI_CmdUpgradeDelegates_failed() {
+	driver := &mocks.API{}
+	driver.On("UpgradeDelegates", host.UpgradeRequest{
+		Version: "1.2.0",
+		PoolIDs: []string{},
+		Timeout: 10 * time.Minute,
+	}).Return([]host.UpgradeResult{
+		{HostID: "host1", PoolID: "default", PreviousVersion: "1.1.9", Version: "1.1.9", Status: host.UpgradeFailed, Message: "host did not come back online"},
+	}, nil)
+	pipeStderr(func(args ...string) { runUpgradeAPITest(driver, args...) }, "serviced", "upgrade-delegates", "--version", "1.2.0")
+
+	// Output:
+	// Host     Pool       Previous    Version    Status    Message
+	// host1    default    1.1.9       1.1.9      failed    host did not come back online
+	// upgrade halted; remaining delegates were not upgraded
+}
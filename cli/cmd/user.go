@@ -0,0 +1,114 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/codegangsta/cli"
+)
+
+// Initializer for serviced user
+func (c *ServicedCli) initUser() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "user",
+		Usage:       "Administers user access to tenants",
+		Description: "Restricts users of the UI and REST API to the services of their assigned tenants",
+		Subcommands: []cli.Command{
+			{
+				Name:        "tenants",
+				Usage:       "Lists the tenants a user may access",
+				Description: "serviced user tenants USERNAME",
+				Action:      c.cmdUserTenants,
+				Flags: append([]cli.Flag{
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "Tenant,TenantID,DepID",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				}, tableFlags()...),
+			}, {
+				Name:        "set-tenants",
+				Usage:       "Restricts a user to the given tenants; no tenants removes the restriction",
+				Description: "serviced user set-tenants USERNAME [TENANT ...]",
+				Action:      c.cmdUserSetTenants,
+			},
+		},
+	})
+}
+
+// serviced user tenants USERNAME
+func (c *ServicedCli) cmdUserTenants(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "tenants")
+		return
+	}
+
+	tenantIDs, err := c.driver.GetUserTenants(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(tenantIDs) == 0 {
+		fmt.Printf("%s may access all tenants\n", args[0])
+		return
+	}
+
+	t, err := newTableFromContext(ctx, ctx.String("show-fields"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	t.Padding = 4
+	for _, tenantID := range tenantIDs {
+		row := map[string]interface{}{"TenantID": tenantID}
+		if svc, err := c.driver.GetService(tenantID); err == nil && svc != nil {
+			row["Tenant"] = svc.Name
+			row["DepID"] = svc.DeploymentID
+		}
+		t.AddRow(row)
+	}
+	t.Print()
+}
+
+// serviced user set-tenants USERNAME [TENANT ...]
+func (c *ServicedCli) cmdUserSetTenants(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-tenants")
+		return
+	}
+
+	tenantIDs := []string{}
+	for _, arg := range args[1:] {
+		svc, err := c.searchForService(arg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", arg, err)
+			return
+		} else if svc.ParentServiceID != "" {
+			fmt.Fprintf(os.Stderr, "%s is not a tenant\n", arg)
+			return
+		}
+		tenantIDs = append(tenantIDs, svc.ID)
+	}
+
+	if err := c.driver.SetUserTenants(args[0], tenantIDs); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(args[0])
+}
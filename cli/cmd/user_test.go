@@ -0,0 +1,100 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/utils"
+)
+
+var DefaultUserAPITest = UserAPITest{
+	ServiceAPITest: DefaultServiceAPITest,
+	users: map[string][]string{
+		"alice": []string{"test-service-1", "test-service-2"},
+	},
+}
+
+var ErrNoTenant = errors.New("tenant not found")
+
+type UserAPITest struct {
+	ServiceAPITest
+	users map[string][]string
+}
+
+func InitUserAPITest(args ...string) {
+	c := New(DefaultUserAPITest, utils.TestConfigReader(make(map[string]string)))
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func (t UserAPITest) GetUserTenants(userName string) ([]string, error) {
+	return t.users[userName], nil
+}
+
+func (t UserAPITest) SetUserTenants(userName string, tenantIDs []string) error {
+	for _, tenantID := range tenantIDs {
+		if svc, err := t.GetService(tenantID); err != nil || svc == nil {
+			return ErrNoTenant
+		}
+	}
+	return nil
+}
+
+func ExampleServicedCLI_CmdUserTenants() {
+	InitUserAPITest("serviced", "user", "tenants", "alice")
+	InitUserAPITest("serviced", "user", "tenants", "bob")
+
+	// Output:
+	// Tenant    TenantID          DepID
+	// Zenoss    test-service-1    Zenoss-resmgr
+	// Zope      test-service-2    Zenoss-core
+	// bob may access all tenants
+}
+
+func ExampleServicedCLI_CmdUserSetTenants() {
+	InitUserAPITest("serviced", "user", "set-tenants", "alice", "Zenoss", "test-service-2")
+	InitUserAPITest("serviced", "user", "set-tenants", "alice")
+
+	// Output:
+	// alice
+	// alice
+}
+
+func ExampleServicedCLI_CmdUserSetTenants_usage() {
+	InitUserAPITest("serviced", "user", "set-tenants")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    set-tenants - Restricts a user to the given tenants; no tenants removes the restriction
+	//
+	// USAGE:
+	//    command set-tenants [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced user set-tenants USERNAME [TENANT ...]
+	//
+	// OPTIONS:
+}
+
+func ExampleServicedCLI_CmdUserSetTenants_fail() {
+	pipeStderr(InitUserAPITest, "serviced", "user", "set-tenants", "alice", "nosuchtenant")
+
+	// Output:
+	// nosuchtenant: service not found
+}
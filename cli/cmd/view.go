@@ -0,0 +1,210 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// View is a saved combination of the fields, tag selector, and layout of the
+// tables printed by the status and list commands
+type View struct {
+	Fields   string `json:",omitempty"`
+	Selector string `json:",omitempty"`
+	Flat     bool   `json:",omitempty"`
+}
+
+// viewFlags are the flags accepted by every command that can print a view
+func viewFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:  "view",
+			Value: "",
+			Usage: "Use the fields, selector and layout of a view saved with 'serviced config view save'",
+		},
+		cli.BoolFlag{
+			Name:  "flat",
+			Usage: "Print the services as a flat list instead of a tree",
+		},
+	}
+}
+
+// viewsFile returns the path of the file that holds the views of the current
+// user
+func (c *ServicedCli) viewsFile() string {
+	return c.config.StringVal("VIEWS_FILE", filepath.Join(os.Getenv("HOME"), ".serviced", "views.json"))
+}
+
+// loadViews reads the saved views from a file; a missing file has no views
+func loadViews(filename string) (map[string]View, error) {
+	views := make(map[string]View)
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return views, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &views); err != nil {
+		return nil, fmt.Errorf("could not read views from %s: %s", filename, err)
+	}
+	return views, nil
+}
+
+// saveViews writes the views to a file
+func saveViews(filename string, views map[string]View) error {
+	data, err := json.MarshalIndent(views, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// viewFromContext returns the view named by the --view flag, overridden by
+// the --show-fields, --selector, and --flat flags set on the command line.
+// Fields are empty if the command should use its default fields.
+func (c *ServicedCli) viewFromContext(ctx *cli.Context) (View, error) {
+	var view View
+	if name := ctx.String("view"); name != "" {
+		views, err := loadViews(c.viewsFile())
+		if err != nil {
+			return view, err
+		}
+		var ok bool
+		if view, ok = views[name]; !ok {
+			return view, fmt.Errorf("view %s not found", name)
+		}
+	}
+	if ctx.IsSet("show-fields") {
+		view.Fields = ctx.String("show-fields")
+	}
+	if ctx.IsSet("selector") {
+		view.Selector = ctx.String("selector")
+	}
+	if ctx.IsSet("flat") {
+		view.Flat = ctx.Bool("flat")
+	}
+	return view, nil
+}
+
+// serviced config view list
+func (c *ServicedCli) cmdConfigViewList(ctx *cli.Context) {
+	views, err := loadViews(c.viewsFile())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(views) == 0 {
+		fmt.Fprintln(os.Stderr, "no views found")
+		return
+	}
+
+	names := make([]string, 0, len(views))
+	for name := range views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	t := NewTable("Name,Fields,Selector,Layout")
+	t.Padding = 4
+	for _, name := range names {
+		view := views[name]
+		layout := "tree"
+		if view.Flat {
+			layout = "flat"
+		}
+		t.AddRow(map[string]interface{}{
+			"Name":     name,
+			"Fields":   view.Fields,
+			"Selector": view.Selector,
+			"Layout":   layout,
+		})
+	}
+	t.Print()
+}
+
+// serviced config view save NAME [--show-fields FIELDS] [--selector SELECTOR] [--flat]
+func (c *ServicedCli) cmdConfigViewSave(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "save")
+		return
+	}
+
+	view := View{
+		Fields:   ctx.String("show-fields"),
+		Selector: ctx.String("selector"),
+		Flat:     ctx.Bool("flat"),
+	}
+	if view.Selector != "" {
+		if _, err := service.ParseTagSelector(view.Selector); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+	}
+
+	filename := c.viewsFile()
+	views, err := loadViews(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	views[args[0]] = view
+	if err := saveViews(filename, views); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(args[0])
+}
+
+// serviced config view remove NAME ...
+func (c *ServicedCli) cmdConfigViewRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	filename := c.viewsFile()
+	views, err := loadViews(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	for _, name := range args {
+		if _, ok := views[name]; !ok {
+			fmt.Fprintf(os.Stderr, "view %s not found\n", name)
+			return
+		}
+		delete(views, name)
+	}
+	if err := saveViews(filename, views); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	for _, name := range args {
+		fmt.Println(name)
+	}
+}
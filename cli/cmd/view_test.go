@@ -0,0 +1,139 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/control-center/serviced/utils"
+)
+
+// viewCLI returns a function that runs the cli with the views stored in dir
+func viewCLI(dir string) func(...string) {
+	return func(args ...string) {
+		c := New(DefaultServiceAPITest, utils.TestConfigReader{"VIEWS_FILE": filepath.Join(dir, "views.json")})
+		c.exitDisabled = true
+		c.Run(args)
+	}
+}
+
+func tempViewsDir() string {
+	dir, err := ioutil.TempDir("", "serviced-views")
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+func ExampleServicedCLI_CmdConfigViewSave() {
+	dir := tempViewsDir()
+	defer os.RemoveAll(dir)
+	run := viewCLI(dir)
+
+	run("serviced", "config", "view", "save", "--show-fields", "Name,ServiceID", "--selector", "env=staging", "--flat", "dbteam")
+	run("serviced", "config", "view", "save", "--show-fields", "Name,Status", "all")
+	run("serviced", "config", "view", "list")
+
+	// Output:
+	// dbteam
+	// all
+	// Name      Fields            Selector       Layout
+	// all       Name,Status                      tree
+	// dbteam    Name,ServiceID    env=staging    flat
+}
+
+func ExampleServicedCLI_CmdConfigViewSave_invalid() {
+	dir := tempViewsDir()
+	defer os.RemoveAll(dir)
+	run := viewCLI(dir)
+
+	pipeStderr(run, "serviced", "config", "view", "save", "--selector", "env=a,,b", "dbteam")
+
+	// Output:
+	// invalid selector "env=a,,b": tag is empty
+}
+
+func ExampleServicedCLI_CmdConfigViewList_empty() {
+	dir := tempViewsDir()
+	defer os.RemoveAll(dir)
+	run := viewCLI(dir)
+
+	pipeStderr(run, "serviced", "config", "view", "list")
+
+	// Output:
+	// no views found
+}
+
+func ExampleServicedCLI_CmdConfigViewRemove() {
+	dir := tempViewsDir()
+	defer os.RemoveAll(dir)
+	run := viewCLI(dir)
+
+	run("serviced", "config", "view", "save", "--show-fields", "Name", "dbteam")
+	run("serviced", "config", "view", "rm", "dbteam")
+	pipeStderr(run, "serviced", "config", "view", "rm", "dbteam")
+
+	// Output:
+	// dbteam
+	// dbteam
+	// view dbteam not found
+}
+
+func ExampleServicedCLI_CmdServiceStatus_view() {
+	dir := tempViewsDir()
+	defer os.RemoveAll(dir)
+	run := viewCLI(dir)
+
+	run("serviced", "config", "view", "save", "--show-fields", "Name,ServiceID,Status", "--selector", "env=staging", "--flat", "staging")
+	run("serviced", "service", "status", "--view", "staging")
+	run("serviced", "service", "status", "--view", "staging", "--show-fields", "Name")
+
+	// Output:
+	// staging
+	// Name         ServiceID        Status
+	// Zope         test-service-2   running
+	// zencommand   test-service-3   running
+	// Name
+	// Zope
+	// zencommand
+}
+
+func ExampleServicedCLI_CmdServiceList_view() {
+	dir := tempViewsDir()
+	defer os.RemoveAll(dir)
+	run := viewCLI(dir)
+
+	run("serviced", "config", "view", "save", "--show-fields", "Name,ServiceID,Tenant", "--selector", "team=db", "staging")
+	run("serviced", "service", "list", "--ascii", "--view", "staging")
+
+	// Output:
+	// staging
+	// Name        ServiceID           Tenant
+	// +-Zope      test-service-2      Zope
+}
+
+func ExampleServicedCLI_CmdServiceStatus_viewNotFound() {
+	dir := tempViewsDir()
+	defer os.RemoveAll(dir)
+	run := viewCLI(dir)
+
+	pipeStderr(run, "serviced", "service", "status", "--view", "missing")
+
+	// Output:
+	// view missing not found
+}
@@ -14,7 +14,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -53,7 +52,7 @@ func (c *ServicedCli) cmdVolumeStatus(ctx *cli.Context) {
 		return
 	}
 	if ctx.Bool("verbose") {
-		printStatusesJson(response)
+		c.printStatusesJson(response)
 	} else {
 		printStatuses(response)
 	}
@@ -67,8 +66,8 @@ func printStatuses(statuses *volume.Statuses) {
 	}
 }
 
-func printStatusesJson(statuses *volume.Statuses) {
-	if jsonStatuses, err := json.MarshalIndent(statuses, " ", "  "); err != nil {
+func (c *ServicedCli) printStatusesJson(statuses *volume.Statuses) {
+	if jsonStatuses, err := c.marshalOutput(statuses); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to marshal volume status list: %s", err)
 	} else {
 		fmt.Println(string(jsonStatuses))
@@ -0,0 +1,172 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/zzk/ensemble"
+)
+
+// Initializer for serviced zk subcommands
+func (c *ServicedCli) initZooKeeper() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "zk",
+		Usage:       "Administers the ZooKeeper ensemble",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "ensemble",
+				Usage:       "Manages the servers of the ZooKeeper ensemble",
+				Description: "",
+				Subcommands: []cli.Command{
+					{
+						Name:        "status",
+						Usage:       "Shows the health of the ensemble's quorum",
+						Description: "serviced zk ensemble status",
+						Action:      c.cmdZKEnsembleStatus,
+					}, {
+						Name:  "add",
+						Usage: "Records a server that was added to the ensemble",
+						Description: "serviced zk ensemble add ID@HOST:PEERPORT:ELECTIONPORT[:CLIENTPORT]\n\n" +
+							"   Configure every member with the new SERVICED_ISVCS_ZOOKEEPER_QUORUM and start the new server first.",
+						Action: c.cmdZKEnsembleAdd,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "force",
+								Usage: "Record the change even if the quorum is not healthy before or after it",
+							},
+						},
+					}, {
+						Name:  "remove",
+						Usage: "Records a server that was removed from the ensemble",
+						Description: "serviced zk ensemble remove ID\n\n" +
+							"   Stop the server and configure the remaining members with the new SERVICED_ISVCS_ZOOKEEPER_QUORUM first.",
+						Action: c.cmdZKEnsembleRemove,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "force",
+								Usage: "Record the change even if the quorum is not healthy before or after it",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// serviced zk ensemble status
+func (c *ServicedCli) cmdZKEnsembleStatus(ctx *cli.Context) {
+	status, err := c.driver.GetZKEnsembleStatus()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	printZKEnsembleStatus(status)
+}
+
+// serviced zk ensemble add ID@HOST:PEERPORT:ELECTIONPORT[:CLIENTPORT]
+func (c *ServicedCli) cmdZKEnsembleAdd(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "add")
+		return
+	}
+
+	server, err := ensemble.ParseServer(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	status, err := c.driver.AddZKEnsembleServer(server, ctx.Bool("force"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Printf("Recorded server %s as a member of the ensemble\n\n", server.ID)
+	printZKEnsembleStatus(status)
+	printZKEnsembleSettings(status)
+}
+
+// serviced zk ensemble remove ID
+func (c *ServicedCli) cmdZKEnsembleRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	status, err := c.driver.RemoveZKEnsembleServer(args[0], ctx.Bool("force"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Printf("Recorded the removal of server %s from the ensemble\n\n", args[0])
+	printZKEnsembleStatus(status)
+	printZKEnsembleSettings(status)
+}
+
+func printZKEnsembleStatus(status *ensemble.Status) {
+	if status.Healthy {
+		fmt.Printf("Quorum is healthy (leader %s)\n", status.Leader)
+	} else {
+		fmt.Println("Quorum is not healthy")
+	}
+	for _, problem := range status.Problems {
+		fmt.Printf("  %s\n", problem)
+	}
+	if len(status.Servers) == 0 {
+		return
+	}
+
+	fmt.Println()
+	t := NewTable("ID,Address,Mode,Zxid")
+	t.Padding = 6
+	for _, s := range status.Servers {
+		mode, zxid := s.Mode, s.Zxid
+		if s.Error != "" {
+			mode = s.Error
+		}
+		if mode == "" {
+			mode = "-"
+		}
+		if zxid == "" {
+			zxid = "-"
+		}
+		t.AddRow(map[string]interface{}{
+			"ID":      s.ID,
+			"Address": s.ClientAddress(),
+			"Mode":    mode,
+			"Zxid":    zxid,
+		})
+	}
+	t.Print()
+}
+
+// printZKEnsembleSettings shows the configuration of the ensemble's members.
+// Hosts record the new client list themselves and use it the next time
+// serviced starts.
+func printZKEnsembleSettings(status *ensemble.Status) {
+	fmt.Println()
+	fmt.Println("Every ZooKeeper member must be configured with:")
+	fmt.Printf("  SERVICED_ISVCS_ZOOKEEPER_QUORUM=%s\n", strings.Join(status.Quorum, ","))
+	fmt.Println("Hosts use the new ensemble when serviced restarts; to make it permanent, set:")
+	fmt.Printf("  SERVICED_ZK=%s\n", strings.Join(status.Clients, ","))
+}
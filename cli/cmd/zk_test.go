@@ -0,0 +1,156 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/utils"
+	"github.com/control-center/serviced/zzk/ensemble"
+)
+
+var DefaultZKEnsembleStatus = ensemble.Status{
+	Servers: []ensemble.ServerStatus{
+		{
+			Server: ensemble.Server{ID: "1", Host: "zk1", PeerPort: 2888, ElectionPort: 3888, ClientPort: 2181},
+			Mode:   "leader",
+			Zxid:   "0x100000002",
+		}, {
+			Server: ensemble.Server{ID: "2", Host: "zk2", PeerPort: 2888, ElectionPort: 3888, ClientPort: 2181},
+			Mode:   "follower",
+			Zxid:   "0x100000002",
+		}, {
+			Server: ensemble.Server{ID: "3", Host: "zk3", PeerPort: 2888, ElectionPort: 3888, ClientPort: 2181},
+			Error:  "connection refused",
+		},
+	},
+	Leader:   "1",
+	Healthy:  true,
+	Problems: []string{"server 3 is not serving: connection refused"},
+	Quorum:   []string{"1@zk1:2888:3888", "2@zk2:2888:3888", "3@zk3:2888:3888"},
+	Clients:  []string{"zk1:2181", "zk2:2181", "zk3:2181"},
+}
+
+type ZKAPITest struct {
+	api.API
+}
+
+func InitZKAPITest(args ...string) {
+	New(ZKAPITest{}, utils.TestConfigReader(map[string]string{})).Run(args)
+}
+
+func (t ZKAPITest) GetZKEnsembleStatus() (*ensemble.Status, error) {
+	return &DefaultZKEnsembleStatus, nil
+}
+
+func (t ZKAPITest) AddZKEnsembleServer(server ensemble.Server, force bool) (*ensemble.Status, error) {
+	fmt.Printf("server: %s client port: %d force: %t\n", server, server.ClientPort, force)
+	return &DefaultZKEnsembleStatus, nil
+}
+
+func (t ZKAPITest) RemoveZKEnsembleServer(id string, force bool) (*ensemble.Status, error) {
+	if id != "3" {
+		return nil, errors.New("server is not a member of the ensemble")
+	}
+	status := DefaultZKEnsembleStatus
+	status.Servers = status.Servers[:2]
+	status.Problems = []string{}
+	status.Quorum = status.Quorum[:2]
+	status.Clients = status.Clients[:2]
+	return &status, nil
+}
+
+func ExampleServicedCLI_CmdZKEnsembleStatus() {
+	InitZKAPITest("serviced", "zk", "ensemble", "status")
+
+	// Output:
+	// Quorum is healthy (leader 1)
+	//   server 3 is not serving: connection refused
+	//
+	// ID      Address       Mode                    Zxid
+	// 1       zk1:2181      leader                  0x100000002
+	// 2       zk2:2181      follower                0x100000002
+	// 3       zk3:2181      connection refused      -
+}
+
+func ExampleServicedCLI_CmdZKEnsembleAdd() {
+	InitZKAPITest("serviced", "zk", "ensemble", "add", "--force", "4@zk4:2888:3888:2182")
+
+	// Output:
+	// server: 4@zk4:2888:3888 client port: 2182 force: true
+	// Recorded server 4 as a member of the ensemble
+	//
+	// Quorum is healthy (leader 1)
+	//   server 3 is not serving: connection refused
+	//
+	// ID      Address       Mode                    Zxid
+	// 1       zk1:2181      leader                  0x100000002
+	// 2       zk2:2181      follower                0x100000002
+	// 3       zk3:2181      connection refused      -
+	//
+	// Every ZooKeeper member must be configured with:
+	//   SERVICED_ISVCS_ZOOKEEPER_QUORUM=1@zk1:2888:3888,2@zk2:2888:3888,3@zk3:2888:3888
+	// Hosts use the new ensemble when serviced restarts; to make it permanent, set:
+	//   SERVICED_ZK=zk1:2181,zk2:2181,zk3:2181
+}
+
+func ExampleServicedCLI_CmdZKEnsembleAdd_usage() {
+	InitZKAPITest("serviced", "zk", "ensemble", "add")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    add - Records a server that was added to the ensemble
+	//
+	// USAGE:
+	//    command add [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced zk ensemble add ID@HOST:PEERPORT:ELECTIONPORT[:CLIENTPORT]
+	//
+	//    Configure every member with the new SERVICED_ISVCS_ZOOKEEPER_QUORUM and start the new server first.
+	//
+	// OPTIONS:
+	//    --force	Record the change even if the quorum is not healthy before or after it
+}
+
+func ExampleServicedCLI_CmdZKEnsembleRemove() {
+	InitZKAPITest("serviced", "zk", "ensemble", "remove", "3")
+
+	// Output:
+	// Recorded the removal of server 3 from the ensemble
+	//
+	// Quorum is healthy (leader 1)
+	//
+	// ID      Address       Mode          Zxid
+	// 1       zk1:2181      leader        0x100000002
+	// 2       zk2:2181      follower      0x100000002
+	//
+	// Every ZooKeeper member must be configured with:
+	//   SERVICED_ISVCS_ZOOKEEPER_QUORUM=1@zk1:2888:3888,2@zk2:2888:3888
+	// Hosts use the new ensemble when serviced restarts; to make it permanent, set:
+	//   SERVICED_ZK=zk1:2181,zk2:2181
+}
+
+func ExampleServicedCLI_CmdZKEnsembleRemove_err() {
+	pipeStderr(InitZKAPITest, "serviced", "zk", "ensemble", "remove", "5")
+
+	// Output:
+	// server is not a member of the ensemble
+}
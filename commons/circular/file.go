@@ -0,0 +1,126 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circular
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// A FileBuffer is a ring buffer on disk that retains at least the last half
+// and at most all of the last size bytes written to it.  Data is written to
+// the file at path until it holds size/2 bytes, after which the file is moved
+// to path.1, replacing the older data, and a new file is started.  Unlike
+// Buffer, a FileBuffer is safe for concurrent writes and its data outlives the
+// process that wrote it.
+type FileBuffer struct {
+	mu      sync.Mutex
+	path    string
+	size    int64
+	file    *os.File
+	written int64
+}
+
+// NewFileBuffer opens a ring buffer of the given size at path, keeping any
+// data already in it.
+func NewFileBuffer(path string, size int64) (*FileBuffer, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &FileBuffer{path: path, size: size, file: file, written: info.Size()}, nil
+}
+
+// Write appends p to the buffer, discarding the oldest data if the buffer is
+// full.
+func (b *FileBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := len(p)
+	half := b.size / 2
+	if half <= 0 {
+		return n, nil
+	}
+	if int64(len(p)) > half {
+		p = p[int64(len(p))-half:]
+	}
+	if b.written+int64(len(p)) > half {
+		if err := b.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	m, err := b.file.Write(p)
+	b.written += int64(m)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// rotate moves the current file to path.1 and starts a new file
+func (b *FileBuffer) rotate() error {
+	if err := b.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(b.path, b.path+".1"); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(b.path, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	b.file, b.written = file, 0
+	return nil
+}
+
+// Close closes the buffer.  Its data is left on disk.
+func (b *FileBuffer) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.file.Close()
+}
+
+// ReadFileBuffer returns the data in the ring buffer at path, oldest first.
+func ReadFileBuffer(path string) ([]byte, error) {
+	older, err := ioutil.ReadFile(path + ".1")
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	newer, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && older != nil {
+			return older, nil
+		}
+		return nil, err
+	}
+	return append(older, newer...), nil
+}
+
+// RemoveFileBuffer deletes the files of the ring buffer at path.
+func RemoveFileBuffer(path string) error {
+	if err := os.Remove(path + ".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
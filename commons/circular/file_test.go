@@ -0,0 +1,73 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package circular
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *CircularSuite) TestFileBuffer(c *C) {
+	path := filepath.Join(c.MkDir(), "output")
+	b, err := NewFileBuffer(path, 8)
+	c.Assert(err, IsNil)
+
+	// fits in the first file
+	_, err = b.Write([]byte("abc"))
+	c.Assert(err, IsNil)
+	data, err := ReadFileBuffer(path)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "abc")
+
+	// rotates the first file
+	_, err = b.Write([]byte("de"))
+	c.Assert(err, IsNil)
+	data, err = ReadFileBuffer(path)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "abcde")
+
+	// discards the oldest data
+	_, err = b.Write([]byte("fgh"))
+	c.Assert(err, IsNil)
+	data, err = ReadFileBuffer(path)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "defgh")
+
+	// keeps only the end of large writes
+	n, err := b.Write([]byte("0123456789"))
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, 10)
+	data, err = ReadFileBuffer(path)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "fgh6789")
+	c.Assert(b.Close(), IsNil)
+
+	// reopening keeps the data
+	b, err = NewFileBuffer(path, 8)
+	c.Assert(err, IsNil)
+	_, err = b.Write([]byte("x"))
+	c.Assert(err, IsNil)
+	c.Assert(b.Close(), IsNil)
+	data, err = ReadFileBuffer(path)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "6789x")
+
+	c.Assert(RemoveFileBuffer(path), IsNil)
+	_, err = ReadFileBuffer(path)
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
@@ -14,17 +14,20 @@
 package docker
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/control-center/serviced/commons"
 	dockerclient "github.com/fsouza/go-dockerclient"
-	"github.com/zenoss/glog"
 )
 
 const DockerLatest = "latest"
@@ -45,6 +48,7 @@ var (
 	ErrRequestTimeout  = errors.New("docker: request timed out")
 	ErrKernelShutdown  = errors.New("docker: kernel shutdown")
 	ErrNoSuchContainer = errors.New("docker: no such container")
+	ErrNoSuchNetwork   = errors.New("docker: no such network")
 )
 
 // ImageNotFound is a an error type when an image is not found
@@ -109,25 +113,25 @@ func NewContainer(cd *dockerclient.CreateContainerOptions, start bool, timeout t
 	if err != nil {
 		return nil, err
 	}
-	glog.V(2).Infof("creating container: %#v", *args.containerOptions)
+	plog.Debugf("creating container: %#v", *args.containerOptions)
 	ctr, err := dc.CreateContainer(*args.containerOptions)
 	switch {
 	case IsImageNotFound(err):
 		if err := PullImage(iid.String()); err != nil {
-			glog.V(2).Infof("Unable to pull image %s: %v", iid.String(), err)
+			plog.Debugf("Unable to pull image %s: %v", iid.String(), err)
 			return nil, err
 		}
 		ctr, err = dc.CreateContainer(*args.containerOptions)
 		if err != nil {
-			glog.V(2).Infof("container creation failed %+v: %v", *args.containerOptions, err)
+			plog.Debugf("container creation failed %+v: %v", *args.containerOptions, err)
 			return nil, err
 		}
 	case err != nil:
-		glog.V(2).Infof("container creation failed %+v: %v", *args.containerOptions, err)
+		plog.Debugf("container creation failed %+v: %v", *args.containerOptions, err)
 		return nil, err
 	}
 
-	glog.V(2).Infof("created container: %+v", *ctr)
+	plog.Debugf("created container: %+v", *ctr)
 	if args.createaction != nil {
 		args.createaction(ctr.ID)
 	}
@@ -144,55 +148,55 @@ func NewContainer(cd *dockerclient.CreateContainerOptions, start bool, timeout t
 			if args.startaction != nil {
 				args.startaction(ctr.ID)
 			}
-			glog.V(2).Infof("handling event: %+v for %s", e, ctr.ID)
+			plog.Debugf("handling event: %+v for %s", e, ctr.ID)
 			close(sc)
 			return nil
 		})
 		defer ss.Cancel()
 
-		glog.V(2).Infof("post creation start of %s", ctr.ID)
+		plog.Debugf("post creation start of %s", ctr.ID)
 		err = dc.StartContainer(ctr.ID, nil)
 		if err != nil {
-			glog.V(1).Infof("post creation start of %s failed: %v", ctr.ID, err)
+			plog.Debugf("post creation start of %s failed: %v", ctr.ID, err)
 			return nil, err
 		}
 
-		glog.V(2).Infof("======= wait for %s to start =======", ctr.ID)
+		plog.Debugf("======= wait for %s to start =======", ctr.ID)
 		attempts := 0
 
 	WaitForContainerStart:
 		for {
 			select {
 			case <-timeoutc:
-				glog.V(2).Infof("timeout starting container")
+				plog.Debugf("timeout starting container")
 				return nil, fmt.Errorf("docker timeout starting container after %s", timeout)
 			case <-sc:
-				glog.V(2).Infof("update container %s state post start", ctr.ID)
+				plog.Debugf("update container %s state post start", ctr.ID)
 				ctrID := ctr.ID
 				ctr, err = dc.InspectContainer(ctrID)
 				if err != nil {
-					glog.V(1).Infof("failed to update container %s state post start: %v", ctrID, err)
+					plog.Debugf("failed to update container %s state post start: %v", ctrID, err)
 					return nil, err
 				}
-				glog.V(2).Infof("container %s is started", ctr.ID)
+				plog.Debugf("container %s is started", ctr.ID)
 				break WaitForContainerStart
 			case <-time.After(5 * time.Second):
 				nctr, err := dc.InspectContainer(ctr.ID)
 				if err != nil {
-					glog.V(2).Infof("can't inspect container %s: %v", ctr.ID, err)
+					plog.Debugf("can't inspect container %s: %v", ctr.ID, err)
 					return nil, err
 				}
 				ctr = nctr
 
 				switch {
 				case !ctr.State.Running && attempts > maxStartAttempts:
-					glog.V(2).Infof("timed out starting container")
+					plog.Debugf("timed out starting container")
 					return nil, fmt.Errorf("timed out starting container: %s", ctr.ID)
 				case !ctr.State.Running:
 					attempts = attempts + 1
 					continue WaitForContainerStart
 				default:
-					glog.V(2).Infof("container %s is running", ctr.ID)
+					plog.Debugf("container %s is running", ctr.ID)
 					break WaitForContainerStart
 				}
 			}
@@ -231,11 +235,27 @@ func Logs(dockerID string, args []string) error {
 	}
 	command = append(command, dockerID)
 
-	glog.V(1).Infof("exec logs command for container:%v command: %+v\n", dockerID, command)
+	plog.Debugf("exec logs command for container:%v command: %+v\n", dockerID, command)
 	return syscall.Exec(command[0], command[0:], os.Environ())
 }
 
-// Containers retrieves a list of all the Docker containers.
+// CreateNetwork creates a network unless one with the same name already
+// exists.
+func CreateNetwork(opts dockerclient.CreateNetworkOptions) error {
+	dc, err := getDockerClient()
+	if err != nil {
+		return err
+	}
+	opts.CheckDuplicate = true
+	if _, err := dc.CreateNetwork(opts); err != nil && err != dockerclient.ErrNetworkAlreadyExists {
+		return err
+	}
+	return nil
+}
+
+// Containers retrieves a list of all the Docker containers.  It inspects
+// every container, so callers that need to follow container state should
+// use the event stream instead of calling it periodically.
 func Containers() ([]*Container, error) {
 	dc, err := getDockerClient()
 	if err != nil {
@@ -279,7 +299,7 @@ func (c *Container) Commit(iidstr string, push bool) (*Image, error) {
 		})
 
 	if err != nil {
-		glog.V(1).Infof("unable to commit container %s: %v", c.ID, err)
+		plog.Debugf("unable to commit container %s: %v", c.ID, err)
 		return nil, err
 	}
 	if push {
@@ -301,6 +321,19 @@ func (c *Container) Delete(volumes bool) error {
 	return err
 }
 
+// ConnectNetwork attaches the container to the named network.
+func (c *Container) ConnectNetwork(network string) error {
+	dc, err := getDockerClient()
+	if err != nil {
+		return err
+	}
+	err = dc.ConnectNetwork(network, dockerclient.NetworkConnectionOptions{Container: c.ID})
+	if _, ok := err.(*dockerclient.NoSuchNetworkOrContainer); ok {
+		return ErrNoSuchNetwork
+	}
+	return err
+}
+
 // Export writes the contents of the container's filesystem as a tar archive to outfile.
 func (c *Container) Export(outfile *os.File) error {
 	dc, err := getDockerClient()
@@ -375,7 +408,7 @@ func (c *Container) Start() error {
 	// check to see if the container is already running
 	ctr, err := dc.InspectContainer(args.id)
 	if err != nil {
-		glog.V(1).Infof("unable to inspect container %s prior to starting it: %v", args.id, err)
+		plog.Debugf("unable to inspect container %s prior to starting it: %v", args.id, err)
 		return err
 	}
 
@@ -383,17 +416,17 @@ func (c *Container) Start() error {
 		return ErrAlreadyStarted
 	}
 
-	glog.V(2).Infof("starting container %s", args.id)
+	plog.Debugf("starting container %s", args.id)
 	err = dc.StartContainer(args.id, nil)
 	if err != nil {
-		glog.V(2).Infof("unable to start %s: %v", args.id, err)
+		plog.Debugf("unable to start %s: %v", args.id, err)
 		return err
 	}
 
-	glog.V(2).Infof("update container %s state post start", args.id)
+	plog.Debugf("update container %s state post start", args.id)
 	ctr, err = dc.InspectContainer(args.id)
 	if err != nil {
-		glog.V(2).Infof("failed to update container %s state post start: %v", args.id, err)
+		plog.Debugf("failed to update container %s state post start: %v", args.id, err)
 		return err
 	}
 	c.Container = ctr
@@ -411,6 +444,54 @@ func (c *Container) Stop(timeout time.Duration) error {
 	return dc.StopContainer(c.ID, uint(timeout.Seconds()))
 }
 
+// FollowOutput copies the stdout and stderr of the container, starting at
+// since, to the given writers until the container stops.  A zero since copies
+// all of the output.
+func (c *Container) FollowOutput(since time.Time, stdout, stderr io.Writer) error {
+	dc, err := getDockerClient()
+	if err != nil {
+		return err
+	}
+	opts := dockerclient.LogsOptions{
+		Container:    c.ID,
+		OutputStream: stdout,
+		ErrorStream:  stderr,
+		Follow:       true,
+		Stdout:       true,
+		Stderr:       true,
+	}
+	if !since.IsZero() {
+		opts.Since = since.Unix()
+	}
+	return dc.Logs(opts)
+}
+
+// TailOutput returns the last lines of the stdout and stderr of the
+// container.
+func (c *Container) TailOutput(lines int) ([]string, error) {
+	dc, err := getDockerClient()
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	opts := dockerclient.LogsOptions{
+		Container:    c.ID,
+		OutputStream: buf,
+		ErrorStream:  buf,
+		Stdout:       true,
+		Stderr:       true,
+		Tail:         strconv.Itoa(lines),
+	}
+	if err := dc.Logs(opts); err != nil {
+		return nil, err
+	}
+	output := strings.TrimRight(buf.String(), "\n")
+	if output == "" {
+		return []string{}, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
 // Wait blocks until the container stops or the timeout expires and then returns its exit code.
 func (c *Container) Wait(timeout time.Duration) (int, error) {
 
@@ -431,7 +512,7 @@ func (c *Container) Wait(timeout time.Duration) (int, error) {
 				if !ok {
 					err = fmt.Errorf("%v", r)
 				}
-				glog.Warningf("recovered from panic: %s", err)
+				plog.Warningf("recovered from panic: %s", err)
 				errc <- waitResult{-127, err}
 			}
 		}()
@@ -491,7 +572,7 @@ func ImportImage(repotag, filename string) error {
 	if err != nil {
 		return err
 	}
-	glog.V(1).Infof("importing image %s from %s", repotag, filename)
+	plog.Debugf("importing image %s from %s", repotag, filename)
 	f, err := os.Open(filename)
 	if err != nil {
 		return err
@@ -511,7 +592,7 @@ func ImportImage(repotag, filename string) error {
 	}
 
 	if err = dc.ImportImage(opts); err != nil {
-		glog.V(1).Infof("unable to import %s: %v", repotag, err)
+		plog.Debugf("unable to import %s: %v", repotag, err)
 		return err
 	}
 	return err
@@ -520,10 +601,10 @@ func ImportImage(repotag, filename string) error {
 // FindImage looks up an image by repotag, e.g., zenoss/devimg, from the local repository
 // TODO: add a FindImageByFilter that returns collections of images
 func FindImage(repotag string, pull bool) (*Image, error) {
-	glog.V(1).Infof("looking up image: %s (pull if neccessary %t)", repotag, pull)
+	plog.Debugf("looking up image: %s (pull if neccessary %t)", repotag, pull)
 	if pull {
 		if err := PullImage(repotag); err != nil && !IsImageNotFound(err) {
-			glog.Warningf("Unable to call PullImage: %s", err)
+			plog.Warningf("Unable to call PullImage: %s", err)
 		}
 	}
 	return lookupImage(repotag)
@@ -559,11 +640,11 @@ func (img *Image) Tag(tag string, push bool) (*Image, error) {
 		tag      string
 	}{img.UUID, img.ID.String(), iid.BaseName(), iid.Registry(), iid.Tag}
 
-	glog.V(1).Infof("tagging image %s as: %s", args.repo, args.tag)
+	plog.Debugf("tagging image %s as: %s", args.repo, args.tag)
 	opts := dockerclient.TagImageOptions{Repo: args.repo, Tag: args.tag, Force: true}
 	err = dc.TagImage(args.name, opts)
 	if err != nil {
-		glog.V(1).Infof("unable to tag image %s: %v", args.repo, err)
+		plog.Debugf("unable to tag image %s: %v", args.repo, err)
 		return nil, err
 	}
 
@@ -613,7 +694,7 @@ func LoadImages(filename string) error {
 		return err
 	}
 
-	glog.V(1).Infof("importing images from %s", filename)
+	plog.Debugf("importing images from %s", filename)
 	f, err := os.Open(filename)
 	if err != nil {
 		return err
@@ -707,7 +788,7 @@ func lookupImage(repotag string) (*Image, error) {
 
 	for _, img := range imgs {
 		if img.ID.String() == repotag {
-			glog.V(1).Info("found: ", repotag)
+			plog.Debug("found: ", repotag)
 			return img, nil
 		}
 	}
@@ -729,7 +810,7 @@ func pullImage(repo, registry, tag string) error {
 		return err
 	}
 
-	glog.V(0).Infof("Pulling image from repo: %s and registry: %s with tag: %s", repo, registry, tag)
+	plog.Infof("Pulling image from repo: %s and registry: %s with tag: %s", repo, registry, tag)
 	opts := dockerclient.PullImageOptions{
 		Repository: repo,
 		Registry:   registry,
@@ -739,10 +820,10 @@ func pullImage(repo, registry, tag string) error {
 	startPull := time.Now()
 	err = dc.PullImage(opts, fetchRegistryCreds(registry))
 	if err != nil {
-		glog.V(2).Infof("failed to pull %s: %v", repo, err)
+		plog.Debugf("failed to pull %s: %v", repo, err)
 		return err
 	}
-	glog.V(0).Infof("Finished pulling image from repo: %s and registry: %s with tag: %s in %s", repo, registry, tag, time.Since(startPull))
+	plog.Infof("Finished pulling image from repo: %s and registry: %s with tag: %s in %s", repo, registry, tag, time.Since(startPull))
 	return nil
 }
 
@@ -766,7 +847,7 @@ func pushImage(repo, registry, tag string) error {
 		tag = DockerLatest
 	}
 
-	glog.V(0).Infof("Pushing image from repo: %s to registry: %s with tag: %s", repo, registry, tag)
+	plog.Infof("Pushing image from repo: %s to registry: %s with tag: %s", repo, registry, tag)
 	opts := dockerclient.PushImageOptions{
 		Name:     repo,
 		Registry: registry,
@@ -774,14 +855,14 @@ func pushImage(repo, registry, tag string) error {
 	}
 	defer func(stime time.Time) {
 		duration := time.Now().Sub(stime)
-		glog.V(0).Infof("Finished pushing image from repo: %s to registry: %s with tag: %s in %s", repo, registry, tag, duration)
+		plog.Infof("Finished pushing image from repo: %s to registry: %s with tag: %s in %s", repo, registry, tag, duration)
 	}(time.Now())
 
 	pushLock.Lock()
 	defer pushLock.Unlock()
 	err = dc.PushImage(opts, fetchRegistryCreds(registry))
 	if err != nil {
-		glog.V(2).Infof("Failed to push %s: %v", repo, err)
+		plog.Debugf("Failed to push %s: %v", repo, err)
 		return err
 	}
 	return nil
@@ -796,21 +877,21 @@ func fetchRegistryCreds(registry string) (authConfig dockerclient.AuthConfigurat
 	if len(registry) == 0 {
 		registry = "https://index.docker.io/v1/"
 	}
-	glog.V(1).Infof("Fetching creds for registry %s", registry)
+	plog.Debugf("Fetching creds for registry %s", registry)
 
 	var authConfigs *dockerclient.AuthConfigurations
 	var err error
 	if authConfigs, err = dockerclient.NewAuthConfigurationsFromDockerCfg(); err != nil {
-		glog.V(1).Infof("Unable to find any docker creds: %s", err)
+		plog.Debugf("Unable to find any docker creds: %s", err)
 		return
 	}
 
 	var ok bool
 	if authConfig, ok = authConfigs.Configs[registry]; !ok {
-		glog.V(1).Infof("No docker creds available for registry %s", registry)
+		plog.Debugf("No docker creds available for registry %s", registry)
 		return
 	}
 
-	glog.Infof("Found creds for registry %s - %s", registry, authConfig.Email)
+	plog.Infof("Found creds for registry %s - %s", registry, authConfig.Email)
 	return
 }
@@ -41,8 +41,12 @@ type Client struct {
 type ClientInterface interface {
 	CommitContainer(opts dockerclient.CommitContainerOptions) (*dockerclient.Image, error)
 
+	ConnectNetwork(id string, opts dockerclient.NetworkConnectionOptions) error
+
 	CreateContainer(opts dockerclient.CreateContainerOptions) (*dockerclient.Container, error)
 
+	CreateNetwork(opts dockerclient.CreateNetworkOptions) (*dockerclient.Network, error)
+
 	ExportContainer(opts dockerclient.ExportContainerOptions) error
 
 	ImportImage(opts dockerclient.ImportImageOptions) error
@@ -61,6 +65,8 @@ type ClientInterface interface {
 
 	ListImages(opts dockerclient.ListImagesOptions) ([]dockerclient.APIImages, error)
 
+	Logs(opts dockerclient.LogsOptions) error
+
 	MonitorEvents() (EventMonitor, error)
 
 	PullImage(opts dockerclient.PullImageOptions, auth dockerclient.AuthConfiguration) error
@@ -99,14 +105,26 @@ func (c *Client) CommitContainer(opts dockerclient.CommitContainerOptions) (*doc
 	return c.dc.CommitContainer(opts)
 }
 
+func (c *Client) ConnectNetwork(id string, opts dockerclient.NetworkConnectionOptions) error {
+	return c.dc.ConnectNetwork(id, opts)
+}
+
 func (c *Client) CreateContainer(opts dockerclient.CreateContainerOptions) (*dockerclient.Container, error) {
 	return c.dc.CreateContainer(opts)
 }
 
+func (c *Client) CreateNetwork(opts dockerclient.CreateNetworkOptions) (*dockerclient.Network, error) {
+	return c.dc.CreateNetwork(opts)
+}
+
 func (c *Client) ExportContainer(opts dockerclient.ExportContainerOptions) error {
 	return c.dc.ExportContainer(opts)
 }
 
+func (c *Client) Logs(opts dockerclient.LogsOptions) error {
+	return c.dc.Logs(opts)
+}
+
 func (c *Client) KillContainer(opts dockerclient.KillContainerOptions) error {
 	return c.dc.KillContainer(opts)
 }
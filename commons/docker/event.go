@@ -2,12 +2,10 @@ package docker
 
 import (
 	"fmt"
-	"io"
 	"sync"
 	"time"
 
 	dockerclient "github.com/fsouza/go-dockerclient"
-	"github.com/zenoss/glog"
 )
 
 // AllThingsDocker is a wildcard used to express interest in the Docker
@@ -22,12 +20,23 @@ const (
 	Die     = "die"
 	Export  = "export"
 	Kill    = "kill"
+	OOM     = "oom"
 	Restart = "restart"
 	Start   = "start"
 	Stop    = "stop"
 	Untag   = "untag"
 )
 
+// Resync is not a Docker lifecycle event; it is dispatched with an empty ID
+// after the Docker event stream is reestablished, because any events that
+// occurred while the stream was down are lost.
+const Resync = "resync"
+
+const (
+	eventRetryMin = time.Second
+	eventRetryMax = 30 * time.Second
+)
+
 // EventMonitor implementations may be used to subscribe to Docker
 // lifecycle events. This package provides such an implementation.
 // Instances of it may be retreived via the client.EventMonitor() method.
@@ -85,7 +94,9 @@ var validEvents = map[string]struct{}{
 	Die:     struct{}{},
 	Export:  struct{}{},
 	Kill:    struct{}{},
+	OOM:     struct{}{},
 	Restart: struct{}{},
+	Resync:  struct{}{},
 	Start:   struct{}{},
 	Stop:    struct{}{},
 	Untag:   struct{}{},
@@ -164,14 +175,13 @@ func (em *clientEventMonitor) run(c *Client) error {
 	}
 
 	go func() {
-		r, w := io.Pipe()
+		stop := make(chan struct{})
 
-		go listenAndDispatch(c, em, r, w)
+		go listenAndDispatch(c, em, stop)
 
 		select {
 		case crc := <-em.closeChannel:
-			w.Close()
-			r.Close()
+			close(stop)
 			close(em.done)
 			crc <- struct{}{}
 			return
@@ -197,7 +207,7 @@ func (em *clientEventMonitor) dispatch(evt *dockerclient.APIEvents) error {
 			select {
 			case sub.eventChannel <- evt:
 			case <-time.After(time.Second):
-				glog.V(2).Infof("timeout sending event: %v, %v", evt, sub)
+				plog.Debugf("timeout sending event: %v, %v", evt, sub)
 			}
 		}
 	}
@@ -209,7 +219,7 @@ func (em *clientEventMonitor) dispatch(evt *dockerclient.APIEvents) error {
 				select {
 				case sub.eventChannel <- evt:
 				case <-time.After(time.Second):
-					glog.V(2).Infof("timeout sending event: %v, %v", evt, sub)
+					plog.Debugf("timeout sending event: %v, %v", evt, sub)
 				}
 			}
 		}
@@ -235,14 +245,81 @@ func (em *clientEventMonitor) unsubscribe(s *Subscription) error {
 }
 
 // listenAndDispatch reads the Docker event stream and dispatches the events
-// it receives.
-func listenAndDispatch(c *Client, em *clientEventMonitor, r *io.PipeReader, w *io.PipeWriter) {
+// it receives.  If the stream is lost, it reconnects and dispatches a Resync
+// event so that subscribers can catch up on anything they missed.
+func listenAndDispatch(c *Client, em *clientEventMonitor, stop <-chan struct{}) {
+	wait := time.Duration(0)
+	for {
+		select {
+		case <-time.After(wait):
+		case <-stop:
+			return
+		}
+
+		listener, err := addEventListener(c)
+		if err != nil {
+			plog.Debugf("Could not listen for docker events: %s", err)
+			if wait *= 2; wait < eventRetryMin {
+				wait = eventRetryMin
+			} else if wait > eventRetryMax {
+				wait = eventRetryMax
+			}
+			continue
+		}
+		wait = 0
+		em.dispatch(&dockerclient.APIEvents{Status: Resync, Time: time.Now().Unix()})
+
+		for listener != nil {
+			select {
+			case evt, ok := <-listener:
+				if !ok {
+					// the docker client closes its listeners when the event
+					// stream is lost.
+					plog.Warningf("Lost connection to the docker event stream, reconnecting")
+					listener = nil
+				} else if evt != nil {
+					em.dispatch(evt)
+				}
+			case <-stop:
+				removeEventListener(c, listener)
+				return
+			}
+		}
+	}
+}
+
+// addEventListener registers a new listener with the docker client.  The
+// daemon is pinged first, otherwise the client gives up on the stream and
+// closes the listener right away.
+func addEventListener(c *Client) (chan *dockerclient.APIEvents, error) {
+	if err := c.dc.Ping(); err != nil {
+		return nil, err
+	}
 	listener := make(chan *dockerclient.APIEvents)
-	c.dc.AddEventListener(listener)
+	if err := c.dc.AddEventListener(listener); err != nil {
+		return nil, err
+	}
+	return listener, nil
+}
+
+// removeEventListener unregisters the listener from the docker client.  The
+// listener is drained until it is removed, because the client holds its lock
+// while sending events.
+func removeEventListener(c *Client, listener chan *dockerclient.APIEvents) {
+	removed := make(chan struct{})
+	go func() {
+		c.dc.RemoveEventListener(listener)
+		close(removed)
+	}()
 	for {
-		evt := <-listener
-		if evt != nil {
-			em.dispatch(evt)
+		select {
+		case _, ok := <-listener:
+			if !ok {
+				<-removed
+				return
+			}
+		case <-removed:
+			return
 		}
 	}
 }
@@ -273,7 +350,7 @@ func (s *Subscription) Cancel() error {
 	select {
 	case <-crc:
 		if err := s.monitor.unsubscribe(s); err != nil {
-			glog.V(2).Infof("could not unsubscribe %v (%v)", s, err)
+			plog.Debugf("could not unsubscribe %v (%v)", s, err)
 		}
 		s.active = false
 		return nil
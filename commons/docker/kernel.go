@@ -18,7 +18,6 @@ import (
 	"time"
 
 	dockerclient "github.com/fsouza/go-dockerclient"
-	"github.com/zenoss/glog"
 )
 
 const (
@@ -61,6 +60,10 @@ type oneventreq struct {
 	}
 }
 
+type resyncreq struct {
+	request
+}
+
 type onstopreq struct {
 	request
 	args struct {
@@ -75,11 +78,13 @@ var (
 		CancelAction    chan cancelactionreq
 		OnContainerStop chan onstopreq
 		OnEvent         chan oneventreq
+		Resync          chan resyncreq
 	}{
 		make(chan addactionreq),
 		make(chan cancelactionreq),
 		make(chan onstopreq),
 		make(chan oneventreq),
+		make(chan resyncreq),
 	}
 	dockerevents = []string{
 		Create,
@@ -88,6 +93,7 @@ var (
 		Die,
 		Export,
 		Kill,
+		OOM,
 		Restart,
 		Start,
 		Stop,
@@ -128,7 +134,7 @@ func kernel(dc ClientInterface, done <-chan struct{}) error {
 
 			eventactions[event][req.args.id] = req.args.action
 
-			glog.V(1).Info("added action for: ", event)
+			plog.Debug("added action for: ", event)
 			close(req.errchan)
 		case req := <-cmds.CancelAction:
 			event := req.args.event
@@ -140,18 +146,25 @@ func kernel(dc ClientInterface, done <-chan struct{}) error {
 
 			delete(eventactions[event], req.args.id)
 
-			glog.V(1).Info("removed action for: ", event)
+			plog.Debug("removed action for: ", event)
 			close(req.errchan)
 		case req := <-cmds.OnEvent:
 			if wcaction, ok := eventactions[req.args.event][Wildcard]; ok {
-				glog.V(1).Info("executing wildcard action for event: ", req.args.event)
+				plog.Debug("executing wildcard action for event: ", req.args.event)
 				go wcaction(req.args.id)
 			}
 			if action, ok := eventactions[req.args.event][req.args.id]; ok {
-				glog.V(1).Infof("executing action for %s on %s", req.args.event, req.args.id)
+				plog.Debugf("executing action for %s on %s", req.args.event, req.args.id)
 				go action(req.args.id)
 			}
 			close(req.errchan)
+		case req := <-cmds.Resync:
+			for id, action := range eventactions[Die] {
+				if id != Wildcard {
+					go resyncContainer(dc, id, action)
+				}
+			}
+			close(req.errchan)
 		case <-done:
 			return nil
 		}
@@ -182,10 +195,27 @@ func routeEventsToKernel(dc ClientInterface) {
 	for _, de := range dockerevents {
 		s.Handle(de, eventToKernel)
 	}
+	s.Handle(Resync, resyncToKernel)
+}
+
+// resyncContainer runs the die action of a container that stopped while the
+// docker event stream was down.
+func resyncContainer(dc ClientInterface, id string, action ContainerActionFunc) {
+	ctr, err := dc.InspectContainer(id)
+	if err != nil {
+		if _, ok := err.(*dockerclient.NoSuchContainer); !ok {
+			plog.Warningf("Could not resync container %s: %s", id, err)
+			return
+		}
+	} else if ctr.State.Running {
+		return
+	}
+	plog.Infof("Container %s stopped while the docker event stream was down", id)
+	action(id)
 }
 
 func eventToKernel(e *dockerclient.APIEvents) error {
-	glog.V(2).Infof("sending %+v to kernel", e)
+	plog.Debugf("sending %+v to kernel", e)
 	ec := make(chan error)
 
 	cmds.OnEvent <- oneventreq{
@@ -210,3 +240,24 @@ func eventToKernel(e *dockerclient.APIEvents) error {
 		}
 	}
 }
+
+func resyncToKernel(e *dockerclient.APIEvents) error {
+	plog.Debug("sending resync to kernel")
+	ec := make(chan error)
+
+	cmds.Resync <- resyncreq{request{ec}}
+
+	select {
+	case <-time.After(1 * time.Second):
+		return ErrRequestTimeout
+	case <-done:
+		return ErrKernelShutdown
+	default:
+		switch err, ok := <-ec; {
+		case !ok:
+			return nil
+		default:
+			return fmt.Errorf("docker: resync failed: %v", err)
+		}
+	}
+}
@@ -32,15 +32,28 @@ func (mdc *MockDockerClient) CommitContainer(opts dockerclient.CommitContainerOp
 	return args.Get(0).(*dockerclient.Image), args.Error(1)
 }
 
+func (mdc *MockDockerClient) ConnectNetwork(id string, opts dockerclient.NetworkConnectionOptions) error {
+	return mdc.Mock.Called(id, opts).Error(0)
+}
+
 func (mdc *MockDockerClient) CreateContainer(opts dockerclient.CreateContainerOptions) (*dockerclient.Container, error) {
 	args := mdc.Mock.Called(opts)
 	return args.Get(0).(*dockerclient.Container), args.Error(1)
 }
 
+func (mdc *MockDockerClient) CreateNetwork(opts dockerclient.CreateNetworkOptions) (*dockerclient.Network, error) {
+	args := mdc.Mock.Called(opts)
+	return args.Get(0).(*dockerclient.Network), args.Error(1)
+}
+
 func (mdc *MockDockerClient) ExportContainer(opts dockerclient.ExportContainerOptions) error {
 	return mdc.Mock.Called(opts).Error(0)
 }
 
+func (mdc *MockDockerClient) Logs(opts dockerclient.LogsOptions) error {
+	return mdc.Mock.Called(opts).Error(0)
+}
+
 func (mdc *MockDockerClient) KillContainer(opts dockerclient.KillContainerOptions) error {
 	return mdc.Mock.Called(opts).Error(0)
 }
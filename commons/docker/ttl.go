@@ -14,46 +14,180 @@
 package docker
 
 import (
+	"sync"
 	"time"
 
-	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
+	dockerclient "github.com/fsouza/go-dockerclient"
 )
 
-// DockerTTL is the ttl manager for stale docker containers.
-type DockerTTL struct{}
+// DockerTTL is the ttl manager for stale docker containers.  Stopped
+// containers are tracked from the docker event stream; the container list is
+// only read when the stream is (re)established.
+type DockerTTL struct {
+	mu      sync.Mutex
+	stopped map[string]time.Time // finish time of each stopped container
+	resync  bool
+	wake    chan struct{}
+}
 
 // RunTTL starts the ttl to reap stale docker containers.
 func RunTTL(cancel <-chan interface{}, min, max time.Duration) {
-	utils.RunTTL(DockerTTL{}, cancel, min, max)
+	dc, err := getDockerClient()
+	if err != nil {
+		plog.Errorf("Could not create docker client: %s", err)
+		return
+	}
+	em, err := dc.MonitorEvents()
+	if err != nil {
+		plog.Errorf("Could not monitor docker events: %s", err)
+		return
+	}
+	s, err := em.Subscribe(AllThingsDocker)
+	if err != nil {
+		plog.Errorf("Could not subscribe to docker events: %s", err)
+		return
+	}
+	defer s.Cancel()
+
+	// the event stream may already be up, so start with a resync
+	ttl := &DockerTTL{
+		stopped: make(map[string]time.Time),
+		resync:  true,
+		wake:    make(chan struct{}, 1),
+	}
+	s.Handle(Resync, ttl.onResync)
+	s.Handle(Die, ttl.onDie)
+	s.Handle(Start, ttl.onRemove)
+	s.Handle(Destroy, ttl.onRemove)
+
+	for {
+		wait, err := ttl.Purge(max)
+		if err != nil {
+			plog.Warningf("Could not purge: %s", err)
+			wait = min
+		}
+
+		plog.Debugf("Next purge in %s", wait)
+		select {
+		case <-time.After(wait):
+		case <-ttl.wake:
+		case <-cancel:
+			return
+		}
+	}
 }
 
 // Purge cleans up old docker containers and returns the time to live til the
 // next purge.
 // Implements utils.TTL
-func (ttl DockerTTL) Purge(age time.Duration) (time.Duration, error) {
-	expire := time.Now().Add(-age)
-	ctrs, err := Containers()
-	if err != nil {
-		glog.Errorf("Could not look up containers: %s", err)
+func (ttl *DockerTTL) Purge(age time.Duration) (time.Duration, error) {
+	if err := ttl.sync(); err != nil {
+		plog.Errorf("Could not look up containers: %s", err)
 		return 0, err
 	}
-	for _, ctr := range ctrs {
-		if finishTime := ctr.State.FinishedAt; finishTime.Unix() <= 0 || ctr.IsRunning() {
-			// container is still running or hasn't started; skip
-			continue
-		} else if timeToLive := finishTime.Sub(expire); timeToLive <= 0 {
+
+	expire := time.Now().Add(-age)
+	ttl.mu.Lock()
+	stopped := make(map[string]time.Time)
+	for id, finishTime := range ttl.stopped {
+		stopped[id] = finishTime
+	}
+	ttl.mu.Unlock()
+
+	for id, finishTime := range stopped {
+		if timeToLive := finishTime.Sub(expire); timeToLive <= 0 {
 			// container has exceeded its expiration date
-			if err := ctr.Delete(true); err != nil {
-				glog.Errorf("Could not delete container %s (%s): %s", ctr.Name, ctr.ID, err)
+			ctr := &Container{&dockerclient.Container{ID: id}}
+			if err := ctr.Delete(true); err != nil && err != ErrNoSuchContainer {
+				plog.Errorf("Could not delete container %s: %s", id, err)
 				return 0, err
 			}
+			ttl.forget(id)
 		} else if timeToLive < age {
 			// set the new time to live based on the age of the oldest
-			// non-expired snapshot.
+			// non-expired container.
 			age = timeToLive
 		}
 	}
 
 	return age, nil
-}
\ No newline at end of file
+}
+
+// sync reloads the stopped containers from docker if the event stream was
+// reestablished since the last purge.
+func (ttl *DockerTTL) sync() error {
+	ttl.mu.Lock()
+	resync := ttl.resync
+	ttl.resync = false
+	ttl.mu.Unlock()
+
+	if !resync {
+		return nil
+	}
+
+	since := time.Now().Unix()
+	ctrs, err := Containers()
+	if err != nil {
+		ttl.mu.Lock()
+		ttl.resync = true
+		ttl.mu.Unlock()
+		return err
+	}
+
+	stopped := make(map[string]time.Time)
+	for _, ctr := range ctrs {
+		if finishTime := ctr.State.FinishedAt; finishTime.Unix() > 0 && !ctr.IsRunning() {
+			stopped[ctr.ID] = finishTime
+		}
+	}
+
+	ttl.mu.Lock()
+	defer ttl.mu.Unlock()
+
+	// keep containers that died while the list was being read
+	for id, finishTime := range ttl.stopped {
+		if finishTime.Unix() >= since {
+			stopped[id] = finishTime
+		}
+	}
+	ttl.stopped = stopped
+	return nil
+}
+
+func (ttl *DockerTTL) forget(id string) {
+	ttl.mu.Lock()
+	delete(ttl.stopped, id)
+	ttl.mu.Unlock()
+}
+
+func (ttl *DockerTTL) notify() {
+	select {
+	case ttl.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (ttl *DockerTTL) onResync(e *dockerclient.APIEvents) error {
+	ttl.mu.Lock()
+	ttl.resync = true
+	ttl.mu.Unlock()
+	ttl.notify()
+	return nil
+}
+
+func (ttl *DockerTTL) onDie(e *dockerclient.APIEvents) error {
+	finishTime := time.Unix(e.Time, 0)
+	if e.Time <= 0 {
+		finishTime = time.Now()
+	}
+	ttl.mu.Lock()
+	ttl.stopped[e.ID] = finishTime
+	ttl.mu.Unlock()
+	ttl.notify()
+	return nil
+}
+
+func (ttl *DockerTTL) onRemove(e *dockerclient.APIEvents) error {
+	ttl.forget(e.ID)
+	return nil
+}
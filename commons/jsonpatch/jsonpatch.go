@@ -0,0 +1,217 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonpatch applies JSON patches (RFC 6902) to JSON documents.  The
+// add, remove, replace, and test operations are supported.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPath is returned when a path is not a valid JSON pointer
+	ErrInvalidPath = errors.New("invalid path")
+	// ErrPathNotFound is returned when a path does not exist in the document
+	ErrPathNotFound = errors.New("path not found")
+	// ErrTestFailed is returned when the value of a test operation does not
+	// match the document
+	ErrTestFailed = errors.New("test failed")
+)
+
+// Operation is a single change to a JSON document
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch is a list of operations that are applied in order
+type Patch []Operation
+
+// Apply applies the patch to the JSON document and returns the patched
+// document.  If any operation fails, the document is not changed.
+func Apply(doc []byte, patch Patch) ([]byte, error) {
+	var node interface{}
+	if err := json.Unmarshal(doc, &node); err != nil {
+		return nil, err
+	}
+	for i, op := range patch {
+		var err error
+		if node, err = op.apply(node); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %s", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(node)
+}
+
+// apply performs the operation on the document and returns the new document
+func (op Operation) apply(doc interface{}) (interface{}, error) {
+	tokens, err := parsePath(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return nil, errors.New("missing value")
+		} else if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, err
+		}
+	case "remove":
+	default:
+		return nil, fmt.Errorf("unsupported operation %q", op.Op)
+	}
+
+	// the empty path refers to the whole document
+	if len(tokens) == 0 {
+		switch op.Op {
+		case "add", "replace":
+			return value, nil
+		case "test":
+			if !reflect.DeepEqual(doc, value) {
+				return nil, ErrTestFailed
+			}
+			return doc, nil
+		default:
+			return nil, ErrInvalidPath
+		}
+	}
+
+	return update(doc, tokens, func(parent interface{}, key string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			current, ok := p[key]
+			switch op.Op {
+			case "add":
+				p[key] = value
+			case "remove":
+				if !ok {
+					return nil, ErrPathNotFound
+				}
+				delete(p, key)
+			case "replace":
+				if !ok {
+					return nil, ErrPathNotFound
+				}
+				p[key] = value
+			case "test":
+				if !ok {
+					return nil, ErrPathNotFound
+				} else if !reflect.DeepEqual(current, value) {
+					return nil, ErrTestFailed
+				}
+			}
+			return p, nil
+		case []interface{}:
+			if op.Op == "add" {
+				if key == "-" {
+					return append(p, value), nil
+				}
+				i, err := index(key, len(p)+1)
+				if err != nil {
+					return nil, err
+				}
+				p = append(p, nil)
+				copy(p[i+1:], p[i:])
+				p[i] = value
+				return p, nil
+			}
+			i, err := index(key, len(p))
+			if err != nil {
+				return nil, err
+			}
+			switch op.Op {
+			case "remove":
+				return append(p[:i], p[i+1:]...), nil
+			case "replace":
+				p[i] = value
+			case "test":
+				if !reflect.DeepEqual(p[i], value) {
+					return nil, ErrTestFailed
+				}
+			}
+			return p, nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+// update walks the document to the parent of the last token and replaces it
+// with the result of fn.
+func update(node interface{}, tokens []string, fn func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return fn(node, tokens[0])
+	}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[tokens[0]]
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		child, err := update(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[tokens[0]] = child
+		return n, nil
+	case []interface{}:
+		i, err := index(tokens[0], len(n))
+		if err != nil {
+			return nil, err
+		}
+		child, err := update(n[i], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = child
+		return n, nil
+	default:
+		return nil, ErrPathNotFound
+	}
+}
+
+// parsePath splits a JSON pointer into its unescaped reference tokens
+func parsePath(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	} else if !strings.HasPrefix(path, "/") {
+		return nil, ErrInvalidPath
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// index parses an array index that must be less than size
+func index(token string, size int) (int, error) {
+	if token != "0" && strings.HasPrefix(token, "0") {
+		return 0, ErrInvalidPath
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 {
+		return 0, ErrInvalidPath
+	} else if i >= size {
+		return 0, ErrPathNotFound
+	}
+	return i, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package jsonpatch
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func TestJSONPatch(t *testing.T) { TestingT(t) }
+
+type JSONPatchSuite struct{}
+
+var _ = Suite(&JSONPatchSuite{})
+
+const testDoc = `{"Name":"svc","Instances":1,"Tags":["a","b"],"Context":{"x/y":1,"m~n":2}}`
+
+func (s *JSONPatchSuite) apply(c *C, patch string) (string, error) {
+	var p Patch
+	err := json.Unmarshal([]byte(patch), &p)
+	c.Assert(err, IsNil)
+	result, err := Apply([]byte(testDoc), p)
+	return string(result), err
+}
+
+func (s *JSONPatchSuite) TestAdd(c *C) {
+	result, err := s.apply(c, `[{"op":"add","path":"/Description","value":"new"}]`)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, `{"Context":{"m~n":2,"x/y":1},"Description":"new","Instances":1,"Name":"svc","Tags":["a","b"]}`)
+
+	result, err = s.apply(c, `[{"op":"add","path":"/Tags/1","value":"c"},{"op":"add","path":"/Tags/-","value":"d"}]`)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, `{"Context":{"m~n":2,"x/y":1},"Instances":1,"Name":"svc","Tags":["a","c","b","d"]}`)
+
+	_, err = s.apply(c, `[{"op":"add","path":"/Tags/3","value":"c"}]`)
+	c.Assert(err, ErrorMatches, ".*path not found")
+	_, err = s.apply(c, `[{"op":"add","path":"/Missing/Key","value":"c"}]`)
+	c.Assert(err, ErrorMatches, ".*path not found")
+}
+
+func (s *JSONPatchSuite) TestRemove(c *C) {
+	result, err := s.apply(c, `[{"op":"remove","path":"/Context/x~1y"},{"op":"remove","path":"/Tags/0"}]`)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, `{"Context":{"m~n":2},"Instances":1,"Name":"svc","Tags":["b"]}`)
+
+	_, err = s.apply(c, `[{"op":"remove","path":"/Description"}]`)
+	c.Assert(err, ErrorMatches, ".*path not found")
+	_, err = s.apply(c, `[{"op":"remove","path":""}]`)
+	c.Assert(err, ErrorMatches, ".*invalid path")
+}
+
+func (s *JSONPatchSuite) TestReplace(c *C) {
+	result, err := s.apply(c, `[{"op":"replace","path":"/Instances","value":3},{"op":"replace","path":"/Context/m~0n","value":null}]`)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, `{"Context":{"m~n":null,"x/y":1},"Instances":3,"Name":"svc","Tags":["a","b"]}`)
+
+	result, err = s.apply(c, `[{"op":"replace","path":"","value":{"Name":"other"}}]`)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, `{"Name":"other"}`)
+
+	_, err = s.apply(c, `[{"op":"replace","path":"/Description","value":"new"}]`)
+	c.Assert(err, ErrorMatches, ".*path not found")
+	_, err = s.apply(c, `[{"op":"replace","path":"/Instances"}]`)
+	c.Assert(err, ErrorMatches, ".*missing value")
+}
+
+func (s *JSONPatchSuite) TestTest(c *C) {
+	result, err := s.apply(c, `[{"op":"test","path":"/Instances","value":1},{"op":"replace","path":"/Instances","value":2}]`)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, `{"Context":{"m~n":2,"x/y":1},"Instances":2,"Name":"svc","Tags":["a","b"]}`)
+
+	_, err = s.apply(c, `[{"op":"replace","path":"/Instances","value":2},{"op":"test","path":"/Tags","value":["a"]}]`)
+	c.Assert(err, ErrorMatches, "operation 1 \\(test /Tags\\): test failed")
+}
+
+func (s *JSONPatchSuite) TestInvalid(c *C) {
+	_, err := s.apply(c, `[{"op":"move","path":"/Instances"}]`)
+	c.Assert(err, ErrorMatches, `.*unsupported operation "move"`)
+	_, err = s.apply(c, `[{"op":"remove","path":"Instances"}]`)
+	c.Assert(err, ErrorMatches, ".*invalid path")
+	_, err = s.apply(c, `[{"op":"remove","path":"/Tags/01"}]`)
+	c.Assert(err, ErrorMatches, ".*invalid path")
+	_, err = s.apply(c, `[{"op":"remove","path":"/Name/x"}]`)
+	c.Assert(err, ErrorMatches, ".*path not found")
+}
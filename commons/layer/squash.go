@@ -17,7 +17,6 @@ import (
 	"github.com/control-center/serviced/commons"
 	"github.com/control-center/serviced/commons/circular"
 	"github.com/fsouza/go-dockerclient"
-	"github.com/zenoss/glog"
 
 	"archive/tar"
 	"bufio"
@@ -57,15 +56,15 @@ type DockerClient interface {
 
 func export(client DockerClient, image string) (f *os.File, err error) {
 
-	glog.V(1).Infof("creating container for export of %s", image)
+	plog.Debugf("creating container for export of %s", image)
 	container, err := client.CreateContainer(docker.CreateContainerOptions{Config: &docker.Config{Cmd: []string{"/bin/true"}, Image: image}})
 	if err != nil {
 		return f, err
 	}
-	glog.V(1).Infof("create container %s for image %s", container.ID, image)
+	plog.Debugf("create container %s for image %s", container.ID, image)
 	defer client.RemoveContainer(docker.RemoveContainerOptions{ID: container.ID})
 
-	glog.V(1).Infof("exporting %s", image)
+	plog.Debugf("exporting %s", image)
 	file, err := ioutil.TempFile("", fmt.Sprintf("docker_squash_%s_", image))
 	if err == nil {
 		err = client.ExportContainer(docker.ExportContainerOptions{container.ID, file})
@@ -160,7 +159,7 @@ func Squash(client DockerClient, imageName, downToLayer, newName, tempDir string
 	}
 
 	// let's extract the headers of the base image and sort them
-	glog.V(1).Infof("exporting base layer %s", downToLayer)
+	plog.Debugf("exporting base layer %s", downToLayer)
 	baseTar, err := export(client, downToLayer)
 	if err != nil {
 		return "", fmt.Errorf("error exporting base image %s: %s", downToLayer, err)
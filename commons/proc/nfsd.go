@@ -14,8 +14,6 @@
 package proc
 
 import (
-	"github.com/zenoss/glog"
-
 	"bufio"
 	"fmt"
 	"io/ioutil"
@@ -83,7 +81,7 @@ func GetProcNFSDExports() (map[string]ProcNFSDExports, error) {
 		line := strings.TrimSpace(scanner.Text())
 
 		linenum++
-		glog.V(4).Infof("%d: %s", linenum, line)
+		plog.Debugf("%d: %s", linenum, line)
 		if strings.HasPrefix(line, "#") {
 			continue
 		}
@@ -93,7 +91,7 @@ func GetProcNFSDExports() (map[string]ProcNFSDExports, error) {
 		case 0:
 			continue
 		case 1:
-			glog.Errorf("expected at least 2 fields, got %d: %s", len(fields), line)
+			plog.Errorf("expected at least 2 fields, got %d: %s", len(fields), line)
 			continue
 		}
 
@@ -107,7 +105,7 @@ func GetProcNFSDExports() (map[string]ProcNFSDExports, error) {
 			case 0:
 				continue
 			case 1:
-				glog.Errorf("expected at least 2 parts, got %d: %s", len(parts), clientSpec)
+				plog.Errorf("expected at least 2 parts, got %d: %s", len(parts), clientSpec)
 				continue
 			}
 
@@ -117,7 +115,7 @@ func GetProcNFSDExports() (map[string]ProcNFSDExports, error) {
 		exports[svr.MountPoint] = svr
 	}
 
-	glog.V(4).Infof("nfsd exports: %+v", exports)
+	plog.Debugf("nfsd exports: %+v", exports)
 	return exports, nil
 }
 
@@ -129,11 +127,11 @@ func parseOptions(line string) map[string]string {
 		pairs := strings.SplitN(option, "=", 2)
 		switch len(pairs) {
 		case 1:
-			glog.V(4).Infof("option: %s", pairs[0])
+			plog.Debugf("option: %s", pairs[0])
 			options[pairs[0]] = ""
 			continue
 		case 2:
-			glog.V(4).Infof("option: %s  k:%s  v:%s", option, pairs[0], pairs[1])
+			plog.Debugf("option: %s  k:%s  v:%s", option, pairs[0], pairs[1])
 			options[pairs[0]] = pairs[1]
 			continue
 		}
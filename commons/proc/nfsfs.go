@@ -20,8 +20,6 @@ import (
 	"io/ioutil"
 	"os/exec"
 	"strings"
-
-	"github.com/zenoss/glog"
 )
 
 var ErrMountPointNotFound = errors.New("mount point not found")
@@ -83,7 +81,7 @@ func GetProcNFSFSServers() (map[string]ProcNFSFSServer, error) {
 		line := scanner.Text()
 
 		linenum++
-		glog.V(4).Infof("%d: %s", linenum, line)
+		plog.Debugf("%d: %s", linenum, line)
 		if linenum < 2 {
 			continue
 		} else if strings.HasPrefix(line, "#") {
@@ -108,7 +106,7 @@ func GetProcNFSFSServers() (map[string]ProcNFSFSServer, error) {
 		key := fmt.Sprintf("%s:%s:%s", svr.Version, svr.ServerID, svr.Port)
 		servers[key] = svr
 	}
-	glog.V(4).Infof("nfsfs servers: %+v", servers)
+	plog.Debugf("nfsfs servers: %+v", servers)
 	return servers, nil
 }
 
@@ -117,10 +115,10 @@ func GetMountInfo(mountpoint string) (*MountInfo, error) {
 	command := []string{"bash", "-c", fmt.Sprintf(procFindmntCommand, mountpoint)}
 
 	thecmd := exec.Command(command[0], command[1:]...)
-	glog.V(1).Infof("command: %+v", command)
+	plog.Debugf("command: %+v", command)
 	output, err := thecmd.CombinedOutput()
 	if err != nil {
-		glog.Warningf("could not find mountpoint:%s with command:%+v  output:%s (%s)", mountpoint, command, string(output), err)
+		plog.Warningf("could not find mountpoint:%s with command:%+v  output:%s (%s)", mountpoint, command, string(output), err)
 		return nil, ErrMountPointNotFound
 	}
 
@@ -128,7 +126,7 @@ func GetMountInfo(mountpoint string) (*MountInfo, error) {
 	// 0:329 nfs4 10.87.209.168:/serviced_var /tmp/serviced/var rw,relatime,vers=4.0,rsize=1048576,wsize=1048576,namlen=255,hard,proto=tcp,port=0,timeo=600,retrans=2,sec=sys,clientaddr=10.87.209.168,local_lock=none,addr=10.87.209.168
 	line := strings.TrimSpace(string(output))
 
-	glog.V(4).Infof("line: %s", line)
+	plog.Debugf("line: %s", line)
 
 	fields := strings.Fields(line)
 	switch len(fields) {
@@ -137,7 +135,7 @@ func GetMountInfo(mountpoint string) (*MountInfo, error) {
 	case 0:
 		return nil, ErrMountPointNotFound
 	default:
-		glog.Infof("command: %+v", command)
+		plog.Infof("command: %+v", command)
 		return nil, fmt.Errorf("expected 5 fields, got %d: %s", len(fields), line)
 	}
 
@@ -147,7 +145,7 @@ func GetMountInfo(mountpoint string) (*MountInfo, error) {
 	for _, option := range optionParts {
 		pairs := strings.Split(option, "=")
 		if len(pairs) == 2 {
-			glog.V(4).Infof("option: %s  k:%s  v:%s", option, pairs[0], pairs[1])
+			plog.Debugf("option: %s  k:%s  v:%s", option, pairs[0], pairs[1])
 			options[pairs[0]] = pairs[1]
 		}
 	}
@@ -160,7 +158,7 @@ func GetMountInfo(mountpoint string) (*MountInfo, error) {
 		LocalPath:  fields[3],
 		ServerIP:   options["addr"],
 	}
-	glog.V(4).Infof("mount info: %+v", info)
+	plog.Debugf("mount info: %+v", info)
 	return &info, nil
 }
 
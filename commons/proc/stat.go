@@ -14,8 +14,6 @@
 package proc
 
 import (
-	"github.com/zenoss/glog"
-
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -76,7 +74,7 @@ func ReapZombies() {
 	var done sync.WaitGroup
 	for _, pid := range pids {
 		stat, err := GetProcStat(pid)
-		glog.V(8).Infof("found pid %d procstat %s", pid, err)
+		plog.Debugf("found pid %d procstat %s", pid, err)
 		if err != nil || stat.State != "Z" {
 			continue
 		}
@@ -104,7 +102,7 @@ func KillGroup(pgrp int, timeout time.Duration) error {
 	var done sync.WaitGroup
 	for _, pid := range pids {
 		stat, err := GetProcStat(pid)
-		glog.V(8).Infof("found pid %d procstat %s", pid, err)
+		plog.Debugf("found pid %d procstat %s", pid, err)
 		if err != nil || stat.Pgrp != pgrp {
 			continue
 		}
@@ -118,18 +116,18 @@ func KillGroup(pgrp int, timeout time.Duration) error {
 			if process == nil {
 				return
 			}
-			glog.V(8).Infof("sending %d SIGTERM", process.Pid)
+			plog.Debugf("sending %d SIGTERM", process.Pid)
 			process.Signal(syscall.SIGTERM)
 			exited := make(chan error)
 			go func(e chan error) {
-				glog.V(8).Infof("process %d exited", process.Pid)
+				plog.Debugf("process %d exited", process.Pid)
 				_, err := process.Wait()
 				e <- err
 			}(exited)
 			select {
 			case <-exited:
 			case <-timedout:
-				glog.V(8).Infof("process %d sigterm timedout, sending SIGKILL", process.Pid)
+				plog.Debugf("process %d sigterm timedout, sending SIGKILL", process.Pid)
 				process.Signal(syscall.SIGKILL)
 			}
 		}(pid)
@@ -14,8 +14,6 @@
 package subprocess
 
 import (
-	"github.com/zenoss/glog"
-
 	"errors"
 	"os"
 	"os/exec"
@@ -52,14 +50,14 @@ func New(sigtermTimeout time.Duration, env []string, command string, args ...str
 
 // Notify sends the sig to the subprocess instance.
 func (s *Instance) Notify(sig os.Signal) {
-	glog.V(1).Infof("Notify: sending signal %v", sig)
+	plog.Debugf("Notify: sending signal %v", sig)
 	select {
 	case s.signalChan <- sig:
-		glog.V(1).Infof("Notify: sent signal %v", sig)
+		plog.Debugf("Notify: sent signal %v", sig)
 	default:
 		// This may happen if we're trying to kill a process that has already died. The controller will first send
 		// SIGTERM, then it will try SIGKILL
-		glog.Warningf("Notify: unable to send signal %v because channel is full", sig)
+		plog.Warningf("Notify: unable to send signal %v because channel is full", sig)
 	}
 }
 
@@ -84,7 +82,7 @@ func (s *Instance) Close() error {
 func (s *Instance) loop() {
 
 	setUpCmd := func(exitChan chan error) *exec.Cmd {
-		glog.Infof("about to execute: %s , %v[%d]", s.command, s.args, len(s.args))
+		plog.Infof("about to execute: %s , %v[%d]", s.command, s.args, len(s.args))
 		cmd := exec.Command(s.command, s.args...)
 		cmd.Env = s.env
 		cmd.Stdout = os.Stdout
@@ -107,12 +105,12 @@ func (s *Instance) loop() {
 
 		select {
 		case s := <-s.signalChan:
-			glog.V(1).Infof("loop: sending signal %v", s)
+			plog.Debugf("loop: sending signal %v", s)
 			cmd.Process.Signal(s)
-			glog.V(1).Infof("loop: sent signal %v", s)
+			plog.Debugf("loop: sent signal %v", s)
 
 		case exitError := <-processExit:
-			glog.V(1).Infof("loop: process exited with error %v", exitError)
+			plog.Debugf("loop: process exited with error %v", exitError)
 			select {
 			case s.commandExit <- exitError: // tell our the parent controller that the command has exited
 			}
@@ -44,6 +44,7 @@ var (
 // Options are the server options
 type Options struct {
 	Endpoint                   string // the endpoint address to make RPC requests to
+	ConfigFile                 string // the configuration file the options were read from
 	UIPort                     string
 	NFSClient                  string
 	RPCPort                    string
@@ -92,6 +93,8 @@ type Options struct {
 	MUXTLSCiphers              []string // List of tls ciphers supported for mux
 	MUXTLSMinVersion           string   // Minimum TLS version supported for mux
 	RPCDialTimeout             int
+	RPCCallTimeout             int               // seconds an rpc call to the master may run, zero for no limit
+	RPCSlowCallThreshold       int               // milliseconds after which served rpc calls are logged, zero to disable
 	RPCCertVerify              string            //  server certificate verify for rpc connections, string val of bool
 	RPCDisableTLS              string            //  Disable TLS for RPC connections, string val of bool
 	RPCTLSCiphers              []string          // List of tls ciphers supported for rpc
@@ -109,10 +112,54 @@ type Options struct {
 	DockerLogConfigList        []string          // List of comma-separated key=value options for docker logging
 	AllowLoopBack              string            // Allow loop back devices for DM storage, string val of bool
 	UIPollFrequency            int               // frequency in seconds that UI should poll for service changes
+	UICORSOrigins              []string          // Origins allowed to make cross-origin requests to the api; "*" for any
+	UIDisableCompression       bool              // Do not gzip responses of the ui server
+	UIHeadless                 bool              // Serve only the json api, without the static assets of the ui
 	StorageStatsUpdateInterval int               // frequency in seconds that low-level devicemapper storage stats should be refreshed
 	SnapshotSpacePercent       int               // Percent of tenant volume size that is assumed to be needed to create a snapshot
 	ZKSessionTimeout           int               // The session timeout of a zookeeper client connection.
 	TokenExpiration            int               // The time in seconds before an authentication token expires
+	AllowChaos                 bool              // Allow chaos mode to kill service instances
+	RepairConsistency          bool              // Repair differences between the datastore and zookeeper found when the master starts
+	HeartbeatMinInterval       int               // Seconds between delegate heartbeats while instances are changing
+	HeartbeatMaxInterval       int               // Seconds between delegate heartbeats while the host is idle
+	HeartbeatJitter            int               // Percent of the heartbeat interval randomly added or removed
+	MaxClockSkew               int               // Seconds a delegate clock may differ from the master before it is flagged
+	AuthClockSkewTolerance     int               // Seconds of clock skew tolerated when validating authentication tokens
+	StorageWarningPercent      int               // Percent of a host's storage pool used before the host is flagged with a warning
+	StorageCriticalPercent     int               // Percent of a host's storage pool used before the host is flagged as critical
+	EmergencyMinFreeDFS        string            // Free space of the application storage below which services are emergency stopped
+	EmergencyMinFreeThinPool   string            // Free space of a thin pool below which services are emergency stopped
+	MasterHA                   bool              // Fail over internal services between masters
+	MasterHADevice             string            // Shared storage device mounted by the active master
+	MasterHAMountPath          string            // Path where the active master mounts the shared storage
+	ESURL                      string            // Url of an external Elasticsearch cluster used instead of elasticsearch-serviced
+	OpenTSDBURL                string            // Url of an external OpenTSDB cluster that receives internal service stats
+	ZKExternal                 bool              // Use the configured ZooKeeper ensemble instead of the zookeeper isvc
+	ExternalCAFile             string            // CA certificates used to verify external internal service endpoints
+	ExternalCertFile           string            // Client certificate presented to external internal service endpoints
+	ExternalKeyFile            string            // Key of the client certificate presented to external endpoints
+	ZKAuth                     string            // Digest credentials (user:password) used for ZooKeeper sessions
+	MasterReplica              bool              // Serve reads from a cache of the master and forward writes to it
+	ReplicaMaxStaleness        int               // Seconds a read replica may serve cached reads
+	TraceURL                   string            // Zipkin spans endpoint that receives request traces
+	TraceSamplePercent         int               // Percent of requests that are traced
+	LogFormat                  string            // Format of log messages: text or json
+	LogFile                    string            // File that receives log messages instead of stderr
+	LogMaxSize                 int               // Megabytes the log file may grow to before it is rotated
+	LogMaxBackups              int               // Number of rotated log files to keep
+	LogLevels                  []string          // Log level overrides of the form component=level
+	PreserveInstances          bool              // Leave containers running when the agent shuts down
+	PreserveInstancesTimeout   int               // Seconds the master waits for the agent to restart before rescheduling
+	UpgradeCommand             string            // Shell command that installs a release of serviced on this host
+	RollbackWindow             int               // Seconds a commit or image upgrade is verified before it is kept; 0 to disable automatic rollback
+	ContainerOutputPath        string            // Path where the agent buffers the output of its containers
+	ContainerOutputSize        int               // Kilobytes of output buffered for each container; 0 to disable
+	OfflineInstances           bool              // Keep running and restarting instances while disconnected from the master
+	InstanceCachePath          string            // File where the agent caches its assigned instances for offline operation
+	SnapshotIOLimit            int               // Megabytes per second copied by rsync snapshots; 0 for no limit
+	BackupIOLimit              int               // Megabytes per second read from volumes during a backup; 0 for no limit
+	RestoreIOLimit             int               // Megabytes per second written to volumes during a restore; 0 for no limit
 }
 
 // GetOptions returns a COPY of the global options struct
@@ -0,0 +1,126 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // connections are let through
+	breakerOpen                         // connections are refused
+	breakerHalfOpen                     // a single probe connection is let through
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreaker refuses connections to an endpoint once a number of
+// consecutive connections have failed.  After the reset timeout, a single
+// probe connection is let through; if it succeeds the breaker closes,
+// otherwise it opens again.  A nil breaker lets every connection through.
+type circuitBreaker struct {
+	mu           sync.Mutex
+	threshold    int
+	resetTimeout time.Duration
+	failures     int
+	state        breakerState
+	openedAt     time.Time
+	now          func() time.Time
+}
+
+// newCircuitBreaker returns a breaker for the given config, or nil if the
+// breaker is disabled.
+func newCircuitBreaker(cfg servicedefinition.CircuitBreaker) *circuitBreaker {
+	if !cfg.Enabled() {
+		return nil
+	}
+	return &circuitBreaker{
+		threshold:    cfg.Failures,
+		resetTimeout: time.Duration(cfg.ResetTimeout) * time.Second,
+		now:          time.Now,
+	}
+}
+
+// Allow returns true if a connection may be attempted
+func (b *circuitBreaker) Allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.resetTimeout {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		// a probe is already in flight
+		return false
+	default:
+		return true
+	}
+}
+
+// Success records a connection that was established
+func (b *circuitBreaker) Success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.state = breakerClosed
+}
+
+// Failure records a connection that could not be established, and returns
+// true if the breaker is now open.
+func (b *circuitBreaker) Failure() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+	return b.state == breakerOpen
+}
+
+// State returns the current state of the breaker
+func (b *circuitBreaker) State() breakerState {
+	if b == nil {
+		return breakerClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
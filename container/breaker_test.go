@@ -0,0 +1,89 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package container
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	b := newCircuitBreaker(servicedefinition.CircuitBreaker{})
+	if b != nil {
+		t.Fatalf("Expected breaker to be disabled")
+	}
+	for i := 0; i < 10; i++ {
+		if b.Failure() {
+			t.Errorf("Disabled breaker should never open")
+		}
+	}
+	if !b.Allow() {
+		t.Errorf("Disabled breaker should allow connections")
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(servicedefinition.CircuitBreaker{Failures: 2, ResetTimeout: 30})
+	b.now = func() time.Time { return now }
+
+	// trips after the configured number of consecutive failures
+	if !b.Allow() || b.Failure() {
+		t.Fatalf("Expected breaker to stay closed after one failure")
+	}
+	b.Success()
+	if b.Failure() {
+		t.Fatalf("Expected success to reset the failure count")
+	}
+	if !b.Failure() {
+		t.Fatalf("Expected breaker to open after two failures")
+	}
+	if b.Allow() {
+		t.Errorf("Expected open breaker to refuse connections")
+	}
+
+	// lets a single probe through after the reset timeout
+	now = now.Add(30 * time.Second)
+	if !b.Allow() {
+		t.Fatalf("Expected breaker to allow a probe after the reset timeout")
+	}
+	if b.State() != breakerHalfOpen {
+		t.Errorf("Expected breaker to be half-open, got %s", b.State())
+	}
+	if b.Allow() {
+		t.Errorf("Expected half-open breaker to allow only one probe")
+	}
+
+	// a failed probe opens the breaker again
+	if !b.Failure() {
+		t.Fatalf("Expected failed probe to open the breaker")
+	}
+	if b.Allow() {
+		t.Errorf("Expected breaker to refuse connections after a failed probe")
+	}
+
+	// a successful probe closes the breaker
+	now = now.Add(30 * time.Second)
+	if !b.Allow() {
+		t.Fatalf("Expected breaker to allow a probe after the reset timeout")
+	}
+	b.Success()
+	if b.State() != breakerClosed || !b.Allow() {
+		t.Errorf("Expected successful probe to close the breaker")
+	}
+}
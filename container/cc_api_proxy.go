@@ -35,13 +35,15 @@ type servicedApiProxy struct {
 	proxy     *httputil.ReverseProxy
 }
 
-func newServicedApiProxy() *servicedApiProxy {
+// newServicedApiProxy returns a proxy that signs the requests of a service in
+// the given tenant.
+func newServicedApiProxy(tenantID string) *servicedApiProxy {
 	proxyPort := node.SERVICED_UI_ENDPOINT_PROXY
 	ccApiPort := node.SERVICED_UI_ENDPOINT
 	director := func(req *http.Request) {
 		req.URL.Scheme = "https"
 		req.URL.Host = "localhost:" + strconv.Itoa(ccApiPort)
-		token, err := auth.BuildRestToken(req)
+		token, err := auth.BuildRestToken(req, tenantID)
 		if err != nil {
 			plog.WithError(err).Info("Error building rest token")
 		}
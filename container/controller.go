@@ -30,7 +30,6 @@ import (
 	"github.com/control-center/serviced/zzk/registry"
 	zkservice "github.com/control-center/serviced/zzk/service"
 	"github.com/docker/docker/pkg/mount"
-	"github.com/zenoss/glog"
 
 	"bufio"
 	"errors"
@@ -70,8 +69,26 @@ const (
 	// ContainerKeysDir holds the delegate's private key and auth token
 	containerDelegateKeyFile = "/etc/serviced/delegate.keys"
 	containerTokenFile       = "/etc/serviced/auth.token"
+	// readOnlyRunDir is a tmpfs that the agent mounts in containers whose
+	// root filesystem is read-only, where the controller writes its files
+	readOnlyRunDir = "/run/serviced"
 )
 
+// readOnlyRootfs returns true if the root filesystem of the container is
+// read-only
+func readOnlyRootfs() bool {
+	return os.Getenv("SERVICED_READONLY_ROOTFS") == "true"
+}
+
+// controllerFile returns the path where the controller writes the file at
+// path, which moves to readOnlyRunDir if the root filesystem is read-only.
+func controllerFile(path string) string {
+	if readOnlyRootfs() {
+		return filepath.Join(readOnlyRunDir, filepath.Base(path))
+	}
+	return path
+}
+
 // ControllerOptions are options to be run when starting a new proxy server
 type ControllerOptions struct {
 	ServicedEndpoint string
@@ -135,7 +152,7 @@ func (c *Controller) Close() error {
 func getService(lbClientPort string, serviceID string, instanceID int) (*service.Service, string, error) {
 	client, err := node.NewLBClient(lbClientPort)
 	if err != nil {
-		glog.Errorf("Could not create a client to endpoint: %s, %s", lbClientPort, err)
+		plog.Errorf("Could not create a client to endpoint: %s, %s", lbClientPort, err)
 		return nil, "", err
 	}
 	defer client.Close()
@@ -144,11 +161,11 @@ func getService(lbClientPort string, serviceID string, instanceID int) (*service
 	err = client.GetEvaluatedService(node.EvaluateServiceRequest{serviceID, instanceID}, &evaluatedServiceResponse)
 
 	if err != nil {
-		glog.Errorf("Error getting service %s  error: %s", serviceID, err)
+		plog.Errorf("Error getting service %s  error: %s", serviceID, err)
 		return nil, "", err
 	}
 
-	glog.V(2).Infof("getService: serviceID=%s, tenantID=%s: %+v", serviceID, evaluatedServiceResponse.TenantID, evaluatedServiceResponse.Service)
+	plog.Debugf("getService: serviceID=%s, tenantID=%s: %+v", serviceID, evaluatedServiceResponse.TenantID, evaluatedServiceResponse.Service)
 	return &evaluatedServiceResponse.Service, evaluatedServiceResponse.TenantID, nil
 }
 
@@ -156,7 +173,7 @@ func getService(lbClientPort string, serviceID string, instanceID int) (*service
 func getAgentHostID(lbClientPort string) (string, error) {
 	client, err := node.NewLBClient(lbClientPort)
 	if err != nil {
-		glog.Errorf("Could not create a client to endpoint: %s, %s", lbClientPort, err)
+		plog.Errorf("Could not create a client to endpoint: %s, %s", lbClientPort, err)
 		return "", err
 	}
 	defer client.Close()
@@ -164,11 +181,11 @@ func getAgentHostID(lbClientPort string) (string, error) {
 	var hostID string
 	err = client.GetHostID(&hostID)
 	if err != nil {
-		glog.Errorf("Error getting host id, error: %s", err)
+		plog.Errorf("Error getting host id, error: %s", err)
 		return "", err
 	}
 
-	glog.V(1).Infof("getAgentHostID: %s", hostID)
+	plog.Debugf("getAgentHostID: %s", hostID)
 	return hostID, nil
 }
 
@@ -177,18 +194,18 @@ func getAgentZkInfo(lbClientPort string) (node.ZkInfo, error) {
 	var zkInfo node.ZkInfo
 	client, err := node.NewLBClient(lbClientPort)
 	if err != nil {
-		glog.Errorf("Could not create a client to endpoint: %s, %s", lbClientPort, err)
+		plog.Errorf("Could not create a client to endpoint: %s, %s", lbClientPort, err)
 		return zkInfo, err
 	}
 	defer client.Close()
 
 	err = client.GetZkInfo(&zkInfo)
 	if err != nil {
-		glog.Errorf("Error getting zookeeper dsn/poolID, error: %s", err)
+		plog.Errorf("Error getting zookeeper dsn/poolID, error: %s", err)
 		return zkInfo, err
 	}
 
-	glog.V(1).Infof("GetZkInfo: %+v", zkInfo)
+	plog.Debugf("GetZkInfo: %+v", zkInfo)
 	return zkInfo, nil
 }
 
@@ -199,10 +216,10 @@ func chownConfFile(filename, owner, permissions string) error {
 		command := exec.Command(exe, arg, filename)
 		output, err := command.CombinedOutput()
 		if err != nil {
-			glog.Errorf("Error running command:'%v' output: %s  error: %s\n", command, output, err)
+			plog.Errorf("Error running command:'%v' output: %s  error: %s\n", command, output, err)
 			return err
 		}
-		glog.Infof("Successfully ran command:'%v' output: %s\n", command, output)
+		plog.Infof("Successfully ran command:'%v' output: %s\n", command, output)
 		return nil
 	}
 
@@ -224,14 +241,14 @@ func chownConfFile(filename, owner, permissions string) error {
 func writeConfFile(config servicedefinition.ConfigFile) error {
 	// write file with default perms
 	if err := os.MkdirAll(filepath.Dir(config.Filename), 0755); err != nil {
-		glog.Errorf("could not create directories for config file: %s", config.Filename)
+		plog.Errorf("could not create directories for config file: %s", config.Filename)
 		return err
 	}
 	if err := ioutil.WriteFile(config.Filename, []byte(config.Content), os.FileMode(0664)); err != nil {
-		glog.Errorf("Could not write out config file %s", config.Filename)
+		plog.Errorf("Could not write out config file %s", config.Filename)
 		return err
 	}
-	glog.Infof("Wrote config file %s", config.Filename)
+	plog.Infof("Wrote config file %s", config.Filename)
 
 	// change owner and permissions
 	if err := chownConfFile(config.Filename, config.Owner, config.Permissions); err != nil {
@@ -257,7 +274,7 @@ func setupConfigFiles(svc *service.Service) error {
 func setupLogstashFiles(hostID string, service *service.Service, instanceID string, resourcePath string) error {
 	// write out logstash files
 	if len(service.LogConfigs) != 0 {
-		err := writeLogstashAgentConfig(logstashContainerConfig, hostID, service, instanceID, resourcePath)
+		err := writeLogstashAgentConfig(controllerFile(logstashContainerConfig), hostID, service, instanceID, resourcePath)
 		if err != nil {
 			return err
 		}
@@ -293,13 +310,13 @@ func NewController(options ControllerOptions) (*Controller, error) {
 	// get service
 	instanceID, err := strconv.Atoi(options.Service.InstanceID)
 	if err != nil {
-		glog.Errorf("Invalid instance from instanceID:%s", options.Service.InstanceID)
+		plog.Errorf("Invalid instance from instanceID:%s", options.Service.InstanceID)
 		return c, fmt.Errorf("Invalid instance from instanceID:%s", options.Service.InstanceID)
 	}
 	service, tenantID, err := getService(options.ServicedEndpoint, options.Service.ID, instanceID)
 	if err != nil {
-		glog.Errorf("%+v", err)
-		glog.Errorf("Invalid service from serviceID:%s", options.Service.ID)
+		plog.Errorf("%+v", err)
+		plog.Errorf("Invalid service from serviceID:%s", options.Service.ID)
 		return c, ErrInvalidService
 	}
 	c.healthChecks = service.HealthChecks
@@ -310,7 +327,7 @@ func NewController(options ControllerOptions) (*Controller, error) {
 			cmd := service.PIDFile[5:len(service.PIDFile)]
 			out, err := exec.Command("sh", "-c", cmd).Output()
 			if err != nil {
-				glog.Errorf("Unable to run command '%s'", cmd)
+				plog.Errorf("Unable to run command '%s'", cmd)
 			} else {
 				c.PIDFile = strings.Trim(string(out), "\n ")
 			}
@@ -321,20 +338,20 @@ func NewController(options ControllerOptions) (*Controller, error) {
 
 	// create config files
 	if err := setupConfigFiles(service); err != nil {
-		glog.Errorf("Could not setup config files error:%s", err)
+		plog.Errorf("Could not setup config files error:%s", err)
 		return c, fmt.Errorf("container: invalid ConfigFiles error:%s", err)
 	}
 
 	// get host id
 	c.hostID, err = getAgentHostID(options.ServicedEndpoint)
 	if err != nil {
-		glog.Errorf("Invalid hostID")
+		plog.Errorf("Invalid hostID")
 		return c, ErrInvalidHostID
 	}
 
 	if options.Logforwarder.Enabled {
 		if err := setupLogstashFiles(c.hostID, service, options.Service.InstanceID, filepath.Dir(options.Logforwarder.Path)); err != nil {
-			glog.Errorf("Could not setup logstash files error:%s", err)
+			plog.Errorf("Could not setup logstash files error:%s", err)
 			return c, fmt.Errorf("container: invalid LogStashFiles error:%s", err)
 		}
 
@@ -344,7 +361,7 @@ func NewController(options ControllerOptions) (*Controller, error) {
 		logforwarder, exited, err := subprocess.New(time.Second,
 			nil,
 			options.Logforwarder.Path,
-			"-c", options.Logforwarder.ConfigFile)
+			"-c", controllerFile(options.Logforwarder.ConfigFile))
 		if err != nil {
 			return nil, err
 		}
@@ -355,9 +372,9 @@ func NewController(options ControllerOptions) (*Controller, error) {
 	//build metric redirect url -- assumes 8444 is port mapped
 	metricRedirect := options.Metric.RemoteEndoint
 	if len(metricRedirect) == 0 {
-		glog.V(1).Infof("container.Controller does not have metric forwarding")
+		plog.Debugf("container.Controller does not have metric forwarding")
 	} else if !options.MetricForwarding {
-		glog.V(1).Infof("Not forwarding metrics for this container (%v)", c.tenantID)
+		plog.Debugf("Not forwarding metrics for this container (%v)", c.tenantID)
 	} else {
 		if len(c.tenantID) <= 0 {
 			return nil, ErrInvalidTenantID
@@ -383,7 +400,7 @@ func NewController(options ControllerOptions) (*Controller, error) {
 
 		// setup network stats
 		destination := fmt.Sprintf("http://localhost%s/api/metrics/store", options.Metric.Address)
-		glog.Infof("pushing network stats to: %s", destination)
+		plog.Infof("pushing network stats to: %s", destination)
 		go statReporter(destination, time.Second*15)
 	}
 
@@ -393,16 +410,16 @@ func NewController(options ControllerOptions) (*Controller, error) {
 	// set up the zookeeper client
 	c.zkInfo, err = getAgentZkInfo(options.ServicedEndpoint)
 	if err != nil {
-		glog.Errorf("Invalid zk info: %v", err)
+		plog.Errorf("Invalid zk info: %v", err)
 		return c, err
 	}
-	glog.Infof(" c.zkInfo: %+v", c.zkInfo)
+	plog.Infof(" c.zkInfo: %+v", c.zkInfo)
 
 	// endpoints are created at the root level (not pool aware)
 	rootBasePath := ""
 	zClient, err := coordclient.New("zookeeper", c.zkInfo.ZkDSN, rootBasePath, nil)
 	if err != nil {
-		glog.Errorf("failed create a new coordclient: %v", err)
+		plog.Errorf("failed create a new coordclient: %v", err)
 		return c, err
 	}
 
@@ -424,12 +441,12 @@ func NewController(options ControllerOptions) (*Controller, error) {
 	}
 
 	// CC Rest API proxy
-	c.ccApiProxy = newServicedApiProxy()
+	c.ccApiProxy = newServicedApiProxy(c.tenantID)
 
 	// check command
-	glog.Infof("command: %v [%d]", options.Service.Command, len(options.Service.Command))
+	plog.Infof("command: %v [%d]", options.Service.Command, len(options.Service.Command))
 	if len(options.Service.Command) < 1 {
-		glog.Errorf("Invalid commandif ")
+		plog.Errorf("Invalid commandif ")
 		return c, ErrInvalidCommand
 	}
 
@@ -437,9 +454,10 @@ func NewController(options ControllerOptions) (*Controller, error) {
 }
 
 func writeEnvFile(env []string) (err error) {
-	fo, err := os.Create(containerEnvironmentFile)
+	envFile := controllerFile(containerEnvironmentFile)
+	fo, err := os.Create(envFile)
 	if err != nil {
-		glog.Errorf("Could not create container environment file '%s': %s", containerEnvironmentFile, err)
+		plog.Errorf("Could not create container environment file '%s': %s", envFile, err)
 		return err
 	}
 	defer func() {
@@ -467,23 +485,23 @@ func writeEnvFile(env []string) (err error) {
 func (c *Controller) forwardSignal(sig os.Signal) {
 	pidBuffer, err := ioutil.ReadFile(c.PIDFile)
 	if err != nil {
-		glog.Errorf("Error reading PID file while forwarding signal: %v", err)
+		plog.Errorf("Error reading PID file while forwarding signal: %v", err)
 		return
 	}
 	pid, err := strconv.Atoi(strings.Trim(string(pidBuffer), "\n "))
 	if err != nil {
-		glog.Errorf("Error reading PID file while forwarding signal: %v", err)
+		plog.Errorf("Error reading PID file while forwarding signal: %v", err)
 		return
 	}
 	process, err := os.FindProcess(pid)
 	if err != nil {
-		glog.Errorf("Error finding process while forwarding signal: %v", err)
+		plog.Errorf("Error finding process while forwarding signal: %v", err)
 		return
 	}
-	glog.Infof("Sending signal %v to pid %d provided by PIDFile.", sig, pid)
+	plog.Infof("Sending signal %v to pid %d provided by PIDFile.", sig, pid)
 	err = process.Signal(sig)
 	if err != nil {
-		glog.Errorf("Encountered error sending signal %v to pid %d: %v", sig, pid, err)
+		plog.Errorf("Encountered error sending signal %v to pid %d: %v", sig, pid, err)
 	}
 }
 
@@ -541,7 +559,7 @@ func (c *Controller) storageHealthCheck() (chan struct{}, error) {
 }
 
 func (c *Controller) shutdown() {
-	glog.V(1).Infof("controller for %v shutting down\n", c.dockerID)
+	plog.Debugf("controller for %v shutting down\n", c.dockerID)
 	//defers run in LIFO order
 	defer os.Exit(c.exitStatus)
 	defer zzk.ShutdownConnections()
@@ -553,7 +571,7 @@ func (c *Controller) reapZombies(close chan struct{}) {
 		case <-close:
 			return
 		case <-time.After(time.Second * 10):
-			glog.V(5).Info("reaping zombies")
+			plog.Debug("reaping zombies")
 			proc.ReapZombies()
 		}
 	}
@@ -607,13 +625,13 @@ func (c *Controller) Run() (err error) {
 
 	rpcDead, err := c.rpcHealthCheck()
 	if err != nil {
-		glog.Errorf("Could not setup RPC ping check: %s", err)
+		plog.Errorf("Could not setup RPC ping check: %s", err)
 		return err
 	}
 
 	storageDead, err := c.storageHealthCheck()
 	if err != nil {
-		glog.Errorf("Could not set up storage check: %s", err)
+		plog.Errorf("Could not set up storage check: %s", err)
 		return err
 	}
 
@@ -636,7 +654,7 @@ func (c *Controller) Run() (err error) {
 	}()
 
 	if err := c.handleControlCenterImports(rpcDead); err != nil {
-		glog.Error("Could not setup Control Center specific imports: ", err)
+		plog.Error("Could not setup Control Center specific imports: ", err)
 		return err
 	}
 	go c.checkPrereqs(prereqsPassed, rpcDead)
@@ -663,27 +681,27 @@ func (c *Controller) Run() (err error) {
 			// exitAfter is the deadman switch for unresponsive processes and any processes that have already exited
 			exitAfter = time.After(time.Second * 30)
 
-			glog.Infof("Closing healthExit on signal %v for %q", sig, c.options.Service.ID)
+			plog.Infof("Closing healthExit on signal %v for %q", sig, c.options.Service.ID)
 			close(healthExit)
-			glog.Infof("Closed healthExit on signal %v for %q", sig, c.options.Service.ID)
+			plog.Infof("Closed healthExit on signal %v for %q", sig, c.options.Service.ID)
 		} else {
 			c.exitStatus = 1
 			exited = true
-			glog.Infof("Exited due to sendSignal(%v) failed for %q", sig, c.options.Service.ID)
+			plog.Infof("Exited due to sendSignal(%v) failed for %q", sig, c.options.Service.ID)
 		}
 	}
 
 	for !exited {
 		select {
 		case sig := <-sigc:
-			glog.Infof("Notifying subprocess of signal %v for service %s", sig, c.options.Service.ID)
+			plog.Infof("Notifying subprocess of signal %v for service %s", sig, c.options.Service.ID)
 			shutdownService(service, sig)
-			glog.Infof("Notification complete for signal %v for service %s", sig, c.options.Service.ID)
+			plog.Infof("Notification complete for signal %v for service %s", sig, c.options.Service.ID)
 
 		case <-exitAfter:
-			glog.Infof("Killing unresponsive subprocess for service %s", c.options.Service.ID)
+			plog.Infof("Killing unresponsive subprocess for service %s", c.options.Service.ID)
 			sendSignal(service, syscall.SIGKILL)
-			glog.Infof("Kill signal sent for service %s", c.options.Service.ID)
+			plog.Infof("Kill signal sent for service %s", c.options.Service.ID)
 			c.exitStatus = 1
 			exited = true
 
@@ -697,36 +715,36 @@ func (c *Controller) Run() (err error) {
 				if c.options.Logforwarder.Enabled {
 					time.Sleep(c.options.Logforwarder.SettleTime)
 				}
-				glog.Infof("Service %s Exited with status:%d due to %+v", c.options.Service.ID, exitStatus, exitError)
+				plog.Infof("Service %s Exited with status:%d due to %+v", c.options.Service.ID, exitStatus, exitError)
 				//set loop to end
 				exited = true
 				//exit with exit code, defer so that other cleanup can happen
 				c.exitStatus = exitStatus
 
 			} else {
-				glog.Infof("Restarting service process for service %s in 10 seconds.", c.options.Service.ID)
+				plog.Infof("Restarting service process for service %s in 10 seconds.", c.options.Service.ID)
 				service = nil
 				startAfter = time.After(time.Second * 10)
 			}
 
 		case <-startAfter:
-			glog.Infof("Starting service process for service %s", c.options.Service.ID)
+			plog.Infof("Starting service process for service %s", c.options.Service.ID)
 			service, serviceExited = startService()
 			startAfter = nil
 		case <-rpcDead:
-			glog.Infof("RPC Server has gone away, cleaning up service %s", c.options.Service.ID)
+			plog.Infof("RPC Server has gone away, cleaning up service %s", c.options.Service.ID)
 			shutdownService(service, syscall.SIGTERM)
-			glog.Infof("RPC Server shutdown for service %s complete", c.options.Service.ID)
+			plog.Infof("RPC Server shutdown for service %s complete", c.options.Service.ID)
 		case <-storageDead:
-			glog.Infof("Distributed storage for service %s has gone away; shutting down", c.options.Service.ID)
+			plog.Infof("Distributed storage for service %s has gone away; shutting down", c.options.Service.ID)
 			shutdownService(service, syscall.SIGTERM)
-			glog.Infof("Distributed storage shutdown for service %s complete", c.options.Service.ID)
+			plog.Infof("Distributed storage shutdown for service %s complete", c.options.Service.ID)
 		}
 	}
 	// Signal to health check registry that this instance is giving up the ghost.
 	client, err := node.NewLBClient(c.options.ServicedEndpoint)
 	if err != nil {
-		glog.Errorf("Could not create a client to endpoint: %s, %s", c.options.ServicedEndpoint, err)
+		plog.Errorf("Could not create a client to endpoint: %s, %s", c.options.ServicedEndpoint, err)
 		return nil
 	}
 	defer client.Close()
@@ -741,7 +759,7 @@ func (c *Controller) Run() (err error) {
 
 func (c *Controller) checkPrereqs(prereqsPassed chan bool, rpcDead chan struct{}) error {
 	if len(c.prereqs) == 0 {
-		glog.Infof("No prereqs to pass.")
+		plog.Infof("No prereqs to pass.")
 		prereqsPassed <- true
 		return nil
 	}
@@ -749,24 +767,24 @@ func (c *Controller) checkPrereqs(prereqsPassed chan bool, rpcDead chan struct{}
 	for {
 		select {
 		case <-rpcDead:
-			glog.Fatalf("Exiting, RPC server has gone away")
+			plog.Fatalf("Exiting, RPC server has gone away")
 		case <-healthCheckInterval:
 			failedAny := false
 			for _, script := range c.prereqs {
-				glog.Infof("Running prereq command: %s", script.Script)
+				plog.Infof("Running prereq command: %s", script.Script)
 				out, err := exec.Command("sh", "-c", script.Script).CombinedOutput()
 				if err != nil {
 					msg := fmt.Sprintf("Service %s not starting. Output: %s; error: %s", script.Name, out, err)
-					glog.Warning(msg)
+					plog.Warning(msg)
 					fmt.Fprintln(os.Stderr, msg)
 					failedAny = true
 					break
 				} else {
-					glog.Infof("Passed prereq [%s].", script.Name)
+					plog.Infof("Passed prereq [%s].", script.Name)
 				}
 			}
 			if !failedAny {
-				glog.Infof("Passed all prereqs.")
+				plog.Infof("Passed all prereqs.")
 				prereqsPassed <- true
 				return nil
 			}
@@ -777,29 +795,95 @@ func (c *Controller) checkPrereqs(prereqsPassed chan bool, rpcDead chan struct{}
 func (c *Controller) kickOffHealthChecks(healthExit chan struct{}) {
 	client, err := node.NewLBClient(c.options.ServicedEndpoint)
 	if err != nil {
-		glog.Errorf("Could not create a client to endpoint: %s, %s", c.options.ServicedEndpoint, err)
+		plog.Errorf("Could not create a client to endpoint: %s, %s", c.options.ServicedEndpoint, err)
 		return
 	}
 	defer client.Close()
 
 	instanceID, err := strconv.Atoi(c.options.Service.InstanceID)
 	if err != nil {
-		glog.Errorf("Invalid instance from instanceID:%s", c.options.Service.InstanceID)
+		plog.Errorf("Invalid instance from instanceID:%s", c.options.Service.InstanceID)
 		return
 	}
-	for name, hc := range c.healthChecks {
-		glog.Infof("Kicking off health check %s.", name)
-		glog.Infof("Setting up health check: %s", hc.Script)
+
+	// each health check can be stopped on its own when its settings change
+	stops := make(map[string]chan struct{})
+	startHealthCheck := func(name string, hc health.HealthCheck) {
 		key := health.HealthStatusKey{
 			ServiceID:       c.options.Service.ID,
 			InstanceID:      instanceID,
 			HealthCheckName: name,
 		}
-		go c.doHealthCheck(healthExit, key, hc)
+		stop := make(chan struct{})
+		stops[name] = stop
+		cancel := make(chan struct{})
+		go func() {
+			select {
+			case <-healthExit:
+			case <-stop:
+			}
+			close(cancel)
+		}()
+		go c.doHealthCheck(cancel, key, hc)
+	}
+
+	for name, hc := range c.healthChecks {
+		plog.Infof("Kicking off health check %s.", name)
+		plog.Infof("Setting up health check: %s", hc.Script)
+		startHealthCheck(name, hc)
+	}
+
+	if len(c.healthChecks) > 0 {
+		go c.watchHealthChecks(healthExit, func(name string, hc health.HealthCheck) {
+			plog.Infof("Restarting health check %s with interval %s, timeout %s, and failure threshold %d", name, hc.Interval, hc.Timeout, hc.FailureThreshold)
+			close(stops[name])
+			startHealthCheck(name, hc)
+		})
 	}
 	return
 }
 
+// watchHealthChecks waits for the settings of the health checks to be updated
+// on the service, and restarts each health check that changed, so that they
+// take effect without restarting the instance.
+func (c *Controller) watchHealthChecks(healthExit <-chan struct{}, restart func(name string, hc health.HealthCheck)) {
+	for {
+		var checks map[string]health.HealthCheck
+		client, err := node.NewLBClient(c.options.ServicedEndpoint)
+		if err == nil {
+			req := node.WaitHealthChecksRequest{
+				ServiceID:    c.options.Service.ID,
+				HealthChecks: c.healthChecks,
+				Timeout:      time.Minute,
+			}
+			err = client.WaitHealthChecks(req, &checks)
+			client.Close()
+		}
+		if err != nil {
+			plog.Debugf("Could not watch health checks for service %s: %s", c.options.Service.ID, err)
+			select {
+			case <-time.After(time.Minute):
+				continue
+			case <-healthExit:
+				return
+			}
+		}
+
+		select {
+		case <-healthExit:
+			return
+		default:
+		}
+
+		for name, hc := range checks {
+			if current, ok := c.healthChecks[name]; ok && current != hc {
+				c.healthChecks[name] = hc
+				restart(name, hc)
+			}
+		}
+	}
+}
+
 func (c *Controller) doHealthCheck(cancel <-chan struct{}, key health.HealthStatusKey, hc health.HealthCheck) {
 	hc.Ping(cancel, func(stat health.HealthStatus) {
 		req := master.HealthStatusRequest{
@@ -809,7 +893,7 @@ func (c *Controller) doHealthCheck(cancel <-chan struct{}, key health.HealthStat
 		}
 		client, err := node.NewLBClient(c.options.ServicedEndpoint)
 		if err != nil {
-			glog.Errorf("Could not create a client to endpoint: %s, %s", c.options.ServicedEndpoint, err)
+			plog.Errorf("Could not create a client to endpoint: %s, %s", c.options.ServicedEndpoint, err)
 			return
 		}
 		defer client.Close()
@@ -823,7 +907,7 @@ func (c *Controller) handleControlCenterImports(rpcdead chan struct{}) error {
 	// get service endpoints
 	client, err := node.NewLBClient(c.options.ServicedEndpoint)
 	if err != nil {
-		glog.Errorf("Could not create a client to endpoint: %s, %s", c.options.ServicedEndpoint, err)
+		plog.Errorf("Could not create a client to endpoint: %s, %s", c.options.ServicedEndpoint, err)
 		return err
 	}
 	defer client.Close()
@@ -839,10 +923,10 @@ func (c *Controller) handleControlCenterImports(rpcdead chan struct{}) error {
 			if err != nil {
 				select {
 				case <-time.After(1 * time.Second):
-					glog.V(3).Info("Couldn't retrieve service endpoints, trying again")
+					plog.Debug("Couldn't retrieve service endpoints, trying again")
 					continue RetryGetISvcEndpoints
 				case <-timeout:
-					glog.V(3).Info("Timed out trying to retrieve service endpoints")
+					plog.Debug("Timed out trying to retrieve service endpoints")
 					return
 				}
 			}
@@ -857,7 +941,7 @@ func (c *Controller) handleControlCenterImports(rpcdead chan struct{}) error {
 			if ok {
 				panic("should never receive anything on the endpoints channel")
 			}
-			glog.V(3).Info("Endpoint channel closed, giving up")
+			plog.Debug("Endpoint channel closed, giving up")
 			return
 		default:
 			epc <- endpoints
@@ -876,7 +960,7 @@ func (c *Controller) handleControlCenterImports(rpcdead chan struct{}) error {
 		timeout <- struct{}{}
 		return fmt.Errorf("RPC Service has gone away")
 	case endpoints = <-epchan:
-		glog.Infof("Got service endpoints for %s: %+v", c.options.Service.ID, endpoints)
+		plog.Infof("Got service endpoints for %s: %+v", c.options.Service.ID, endpoints)
 	}
 
 	for _, eps := range endpoints {
@@ -24,6 +24,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/zzk"
 	"github.com/control-center/serviced/zzk/registry"
 	zkservice "github.com/control-center/serviced/zzk/service"
@@ -118,6 +119,7 @@ func (ce *ContainerEndpoints) loadState(svc *service.Service) (bool, error) {
 					Purpose:        ep.Purpose,
 					PortNumber:     ep.PortNumber,
 					VirtualAddress: ep.VirtualAddress,
+					CircuitBreaker: ep.CircuitBreaker,
 				})
 			}
 		}
@@ -377,7 +379,7 @@ func (ce *ContainerEndpoints) UpdateRemoteExports(bind zkservice.ImportBinding,
 			}
 
 			// update the proxy; returns a boolean if a new proxy was created.
-			isNew, err := ce.cache.Set(bind.Application, port, export)
+			isNew, err := ce.cache.Set(bind.Application, port, bind.CircuitBreaker, export)
 			if err != nil {
 				exLogger.WithError(err).Error("Could not update proxy")
 				return
@@ -433,7 +435,7 @@ func (ce *ContainerEndpoints) UpdateRemoteExports(bind zkservice.ImportBinding,
 		}
 
 		// update the proxy
-		isNew, err := ce.cache.Set(bind.Application, port, exports...)
+		isNew, err := ce.cache.Set(bind.Application, port, bind.CircuitBreaker, exports...)
 		if err != nil {
 			exLogger.WithError(err).Error("Could not update proxy")
 			return
@@ -490,7 +492,7 @@ func newProxyCache(tenantID string, tcpMuxPort uint16, useTLS, allowDirect bool)
 }
 
 // Set returns true if the key was created and an error
-func (c *proxyCache) Set(application string, portNumber uint16, exports ...registry.ExportDetails) (bool, error) {
+func (c *proxyCache) Set(application string, portNumber uint16, breaker servicedefinition.CircuitBreaker, exports ...registry.ExportDetails) (bool, error) {
 	logger := plog.WithFields(log.Fields{
 		"application": application,
 		"portnumber":  portNumber,
@@ -527,6 +529,7 @@ func (c *proxyCache) Set(application string, portNumber uint16, exports ...regis
 			c.useTLS,
 			listener,
 			c.allowDirect,
+			breaker,
 		)
 		if err != nil {
 			logger.WithError(err).Debug("Could not start proxy")
@@ -16,9 +16,10 @@ package container
 import (
     "bytes"
 	"fmt"
+	"path/filepath"
+
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
-	"github.com/zenoss/glog"
 )
 
 const (
@@ -59,7 +60,7 @@ func formatTagsForConfFile(tags map[string]string) string {
 
 // writeLogstashAgentConfig creates the logstash forwarder config file
 func writeLogstashAgentConfig(confPath string, hostID string, service *service.Service, instanceID string, resourcePath string) error {
-	glog.Infof("Using logstash resourcePath: %s", resourcePath)
+	plog.Infof("Using logstash resourcePath: %s", resourcePath)
 
 	// generate the json config.
 	filebeatLogConf := ``
@@ -76,7 +77,7 @@ func writeLogstashAgentConfig(confPath string, hostID string, service *service.S
 
 	filebeatShipperConf :=
 `filebeat:
-  idle_timeout: 5s
+  idle_timeout: 5s%s
   prospectors: %s
 output:
   logstash:
@@ -93,7 +94,14 @@ output:
 logging:
   to_syslog: false`
 
+	// filebeat keeps its registry in its working directory by default
+	registryConf := ""
+	if readOnlyRootfs() {
+		registryConf = "\n  registry_file: " + filepath.Join(readOnlyRunDir, ".filebeat")
+	}
+
 	filebeatShipperConf = fmt.Sprintf(filebeatShipperConf,
+        registryConf,
         filebeatLogConf,
 //		"172.17.42.1:5043",
 		"127.0.0.1:5043",
@@ -14,7 +14,6 @@
 package container
 
 import (
-	"github.com/zenoss/glog"
 	rest "github.com/zenoss/go-json-rest"
 
 	"fmt"
@@ -92,7 +91,7 @@ func postAPIMetricsStore(redirectURL string) func(*rest.ResponseWriter, *rest.Re
 			w.WriteHeader(proxyResponse.StatusCode)
 			io.Copy(w, proxyResponse.Body)
 		} else {
-			glog.Errorf("Failed to proxy request: %s", err)
+			plog.Errorf("Failed to proxy request: %s", err)
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 	}
@@ -15,6 +15,7 @@ package container
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
@@ -24,8 +25,8 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 )
 
 func init() {
@@ -86,10 +87,12 @@ type proxy struct {
 	newAddresses     chan []addressTuple // a stream of updates to the addresses
 	listener         net.Listener        // handle on the listening socket
 	allowDirectConn  bool                // allow container to container connections
+	breaker          *circuitBreaker     // refuses connections while the backends are down
+	retries          int                 // additional backends to try before a connection fails
 }
 
 // Newproxy create a new proxy object. It starts listening on the prxy port asynchronously.
-func newProxy(name, tenantEndpointID string, tcpMuxPort uint16, useTLS bool, listener net.Listener, allowDirectConn bool) (p *proxy, err error) {
+func newProxy(name, tenantEndpointID string, tcpMuxPort uint16, useTLS bool, listener net.Listener, allowDirectConn bool, breaker servicedefinition.CircuitBreaker) (p *proxy, err error) {
 	if len(name) == 0 {
 		return nil, fmt.Errorf("prxy: name can not be empty")
 	}
@@ -101,6 +104,8 @@ func newProxy(name, tenantEndpointID string, tcpMuxPort uint16, useTLS bool, lis
 		useTLS:           useTLS,
 		listener:         listener,
 		allowDirectConn:  allowDirectConn,
+		breaker:          newCircuitBreaker(breaker),
+		retries:          breaker.Retries,
 	}
 	p.newAddresses = make(chan []addressTuple, 2)
 	go p.listenAndproxy()
@@ -155,7 +160,7 @@ func (p *proxy) listenAndproxy() {
 		for {
 			conn, err := lsocket.Accept()
 			if err != nil {
-				glog.Fatal("Error (net.Accept): ", err)
+				plog.Fatal("Error (net.Accept): ", err)
 			}
 			conns <- conn
 		}
@@ -166,14 +171,14 @@ func (p *proxy) listenAndproxy() {
 		select {
 		case conn := <-connections:
 			if len(p.addresses) == 0 {
-				glog.Warningf("No remote services available for prxying %v", p)
+				plog.Warningf("No remote services available for prxying %v", p)
 				conn.Close()
 				continue
 			}
 			i++
 			// round robin connections to list of addresses
-			glog.V(1).Infof("choosing address from %v", p.addresses)
-			go p.prxy(conn, p.addresses[i%len(p.addresses)])
+			plog.Debugf("choosing address from %v", p.addresses)
+			go p.prxy(conn, p.addresses, i)
 		case p.addresses = <-p.newAddresses:
 		case errc := <-p.closing:
 			p.listener.Close()
@@ -192,22 +197,71 @@ func getPort(addr string) (int, error) {
 	return strconv.Atoi(port)
 }
 
-// prxy takes an established local connection, Dials the remote address specified
-// by the proxy structure and then copies data to and from the resulting pair
-// of endpoints.
-func (p *proxy) prxy(local net.Conn, address addressTuple) {
+// prxy takes an established local connection, Dials the i'th remote address
+// and then copies data to and from the resulting pair of endpoints.  If the
+// dial fails, up to p.retries of the following addresses are tried.  While the
+// circuit breaker is open the local connection is closed immediately.
+func (p *proxy) prxy(local net.Conn, addresses []addressTuple, i int) {
+	if !p.breaker.Allow() {
+		plog.Warningf("Circuit breaker for %s is open; refusing connection from %s", p.name, local.RemoteAddr())
+		local.Close()
+		return
+	}
+
+	var (
+		remote  net.Conn
+		address addressTuple
+		err     error
+	)
+	for n := 0; n <= p.retries && n < len(addresses); n++ {
+		address = addresses[(i+n)%len(addresses)]
+		if remote, err = p.dial(address); err == nil {
+			break
+		}
+		plog.Warningf("Could not connect %s to %s: %s", p.name, address.containerAddr, err)
+	}
+	if err != nil {
+		if p.breaker.Failure() {
+			plog.Warningf("Circuit breaker for %s is open; refusing connections for the next %s", p.name, p.breaker.resetTimeout)
+		}
+		local.Close()
+		return
+	}
+	p.breaker.Success()
+
+	plog.Debugf("Using hostAgent:%v to prxy %v<->%v<->%v<->%v",
+		remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
+	go func(address string) {
+		defer local.Close()
+		defer remote.Close()
+		io.Copy(local, remote)
+		plog.Debugf("Closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
+			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
+	}(address.containerAddr)
+	go func(address string) {
+		defer local.Close()
+		defer remote.Close()
+		io.Copy(remote, local)
+		plog.Debugf("closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
+			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
+	}(address.containerAddr)
+}
+
+// dial connects to the remote address, either directly if the container is
+// local or through the mux on its host.
+func (p *proxy) dial(address addressTuple) (net.Conn, error) {
 
 	var (
 		remote net.Conn
 		err    error
 	)
-	glog.V(2).Infof("Setting up proxy for %#v", address)
+	plog.Debugf("Setting up proxy for %#v", address)
 	isLocalContainer := false
 	localAddr := address.containerAddr
 	if p.allowDirectConn {
 		//check if the host for the container is running on the same host
 		isLocalContainer = isLocalAddress(address.host)
-		glog.V(4).Infof("Checking is local for %s %s in %#v", address.host, isLocalContainer, hostIPs)
+		plog.Debugf("Checking is local for %s %s in %#v", address.host, isLocalContainer, hostIPs)
 		// don't proxy localhost addresses, we'll end up in a loop
 		if isLocalContainer {
 			switch {
@@ -219,7 +273,7 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 				//if the host is local and the container has a local style addr
 				//then container is exposing port directly on host; go to host and use container port
 				if containerPort, err := getPort(address.containerAddr); err != nil {
-					glog.Warningf("could not get port %v", err)
+					plog.Warningf("could not get port %v", err)
 					isLocalContainer = false
 				} else {
 					localAddr = fmt.Sprintf("%s:%d", address.host, containerPort)
@@ -229,7 +283,7 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 	}
 	if p.tcpMuxPort == 0 {
 		// TODO: Do this properly
-		glog.Errorf("Mux port is unspecified. Using default of 22250.")
+		plog.Errorf("Mux port is unspecified. Using default of 22250.")
 		p.tcpMuxPort = 22250
 	}
 
@@ -246,20 +300,20 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 	if !isLocalContainer {
 		muxHeader, err := utils.PackTCPAddressString(address.containerAddr)
 		if err != nil {
-			glog.Errorf("Container address is invalid. Can't create proxy: %s", address.containerAddr)
-			return
+			plog.Errorf("Container address is invalid. Can't create proxy: %s", address.containerAddr)
+			return nil, err
 		}
 		var token string
 		select {
 		case token = <-auth.AuthToken(nil):
 		case <-time.After(tokenTimeout):
-			glog.Error("Unable to retrieve authentication token with 30 seconds")
-			return
+			plog.Error("Unable to retrieve authentication token with 30 seconds")
+			return nil, errors.New("timed out waiting for authentication token")
 		}
 		muxAuthHeader, err = auth.BuildAuthMuxHeader(muxHeader, token)
 		if err != nil {
-			glog.Errorf("Error building authenticated mux header. %s", err)
-			return
+			plog.Errorf("Error building authenticated mux header. %s", err)
+			return nil, err
 		}
 	}
 
@@ -267,50 +321,37 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 	// address or a mux port on a remote host.
 	switch {
 	case isLocalContainer:
-		glog.V(2).Infof("dialing local addr=> %s", localAddr)
+		plog.Debugf("dialing local addr=> %s", localAddr)
 		remote, err = net.Dial("tcp4", localAddr)
 		if err != nil {
-			glog.Errorf("Error Local (net.Dial): %s", err)
-			return
+			plog.Errorf("Error Local (net.Dial): %s", err)
+			return nil, err
 		}
 	case p.useTLS:
-		glog.V(2).Infof("dialing remote tls => %s", muxAddr)
+		plog.Debugf("dialing remote tls => %s", muxAddr)
 		config := tls.Config{InsecureSkipVerify: true}
 		tlsConn, err := tls.Dial("tcp4", muxAddr, &config)
 		if err != nil {
-			glog.Errorf("Error TLS (net.Dial): %s", err)
-			return
+			plog.Errorf("Error TLS (net.Dial): %s", err)
+			return nil, err
 		}
 		remote = tlsConn // cast it to the net.Conn interface
 		cipher := tlsConn.ConnectionState().CipherSuite
-		glog.V(2).Infof("Proxy connected to mux with TLS cipher=%s (%d)", utils.GetCipherName(cipher), cipher)
+		plog.Debugf("Proxy connected to mux with TLS cipher=%s (%d)", utils.GetCipherName(cipher), cipher)
 	default:
-		glog.V(2).Infof("dialing remote => %s", muxAddr)
+		plog.Debugf("dialing remote => %s", muxAddr)
 		remote, err = net.Dial("tcp4", muxAddr)
 		if err != nil {
-			glog.Errorf("Error Remote (net.Dial): %s", err)
-			return
+			plog.Errorf("Error Remote (net.Dial): %s", err)
+			return nil, err
 		}
 	}
 
 	// Write the authentication header, which will be empty if this is a local
 	// container.
-	remote.Write(muxAuthHeader)
-
-	glog.V(2).Infof("Using hostAgent:%v to prxy %v<->%v<->%v<->%v",
-		remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
-	go func(address string) {
-		defer local.Close()
-		defer remote.Close()
-		io.Copy(local, remote)
-		glog.V(2).Infof("Closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
-			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
-	}(address.containerAddr)
-	go func(address string) {
-		defer local.Close()
-		defer remote.Close()
-		io.Copy(remote, local)
-		glog.V(2).Infof("closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
-			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
-	}(address.containerAddr)
+	if _, err := remote.Write(muxAuthHeader); err != nil {
+		remote.Close()
+		return nil, err
+	}
+	return remote, nil
 }
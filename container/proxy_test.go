@@ -18,7 +18,7 @@ package container
 import (
 	"strings"
 
-	"github.com/zenoss/glog"
+	"github.com/control-center/serviced/domain/servicedefinition"
 
 	"net"
 	"testing"
@@ -32,13 +32,13 @@ func stringAcceptor(listener net.Listener) chan string {
 		for {
 			conn, err := listener.Accept()
 			if err != nil {
-				glog.Errorf("unexpected error: %s", err)
+				plog.Errorf("unexpected error: %s", err)
 				return
 			}
 			buffer := make([]byte, 1000)
 			n, err := conn.Read(buffer)
 			if err != nil {
-				glog.Errorf("problem reading from socket: %s", err)
+				plog.Errorf("problem reading from socket: %s", err)
 			}
 			stringChan <- string(buffer[:n])
 		}
@@ -57,7 +57,7 @@ func TestNoMux(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Could not bind to a port for test")
 	}
-	prxy, err := newProxy("foo", "endpointfoo", 0, false, local, false)
+	prxy, err := newProxy("foo", "endpointfoo", 0, false, local, false, servicedefinition.CircuitBreaker{})
 	if err != nil {
 		t.Fatalf("Could not create a prxy: %s", err)
 	}
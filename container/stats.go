@@ -15,7 +15,6 @@ package container
 
 import (
 	"github.com/control-center/serviced/stats"
-	"github.com/zenoss/glog"
 
 	"io/ioutil"
 	"path"
@@ -53,7 +52,7 @@ func collect(ts time.Time, statsUrl string) {
 	// collect eth0 statistics
 	netStats, err := readInt64Stats(eth0StatsDir)
 	if err != nil {
-		glog.Errorf("Could not collect eth0 stats: %s", err)
+		plog.Errorf("Could not collect eth0 stats: %s", err)
 		return
 	}
 
@@ -76,7 +75,7 @@ func collect(ts time.Time, statsUrl string) {
 	for proto, procFile := range procNetFiles {
 		conns, err := getOpenConnections(procFile)
 		if err != nil {
-			glog.Errorf("Could not collect open connection information: %s", err)
+			plog.Errorf("Could not collect open connection information: %s", err)
 			return
 		}
 
@@ -90,9 +89,9 @@ func collect(ts time.Time, statsUrl string) {
 	}
 
 
-	glog.V(4).Infof("posting samples: %+v", samples)
+	plog.Debugf("posting samples: %+v", samples)
 	if err := stats.Post(statsUrl, samples); err != nil {
-		glog.Errorf("could not post stats: %s", err)
+		plog.Errorf("could not post stats: %s", err)
 	}
 }
 
@@ -140,7 +139,7 @@ func getOpenConnections(fileLoc string) (openConns int, err error) {
 				openConns++
 			}
 		} else {
-			glog.Errorf("Unable to read open connection information from %s", fileLoc)
+			plog.Errorf("Unable to read open connection information from %s", fileLoc)
 		}
 	}
 	return openConns, scanner.Err()
@@ -25,7 +25,6 @@ import (
 
 	"github.com/control-center/serviced/node"
 	"github.com/control-center/serviced/validation"
-	"github.com/zenoss/glog"
 )
 
 const defaultSubnet string = "10.3.0.0/16" // /16 subnet for virtual addresses
@@ -57,7 +56,7 @@ func (reg *VIFRegistry) SetSubnet(subnet string) error {
 		return err
 	}
 	reg.subnet = subnet
-	glog.Infof("vif subnet is: %s", reg.subnet)
+	plog.Infof("vif subnet is: %s", reg.subnet)
 	return nil
 }
 
@@ -90,10 +89,10 @@ func (reg *VIFRegistry) nextIP() (string, error) {
 // address, assigns it to the virtual interface, adds an entry to /etc/hosts,
 // and sets up the iptables rule to redirect traffic to the specified port.
 func (reg *VIFRegistry) RegisterVirtualAddress(address, toport, protocol string) error {
-	glog.Infof("RegisterVirtualAddress address:%s toport:%s protocol:%s", address, toport, protocol)
+	plog.Infof("RegisterVirtualAddress address:%s toport:%s protocol:%s", address, toport, protocol)
 	reg.Lock()
 	defer reg.Unlock()
-	glog.V(2).Infof("RegisterVirtualAddress address:%s toport:%s protocol:%s  locked", address, toport, protocol)
+	plog.Debugf("RegisterVirtualAddress address:%s toport:%s protocol:%s  locked", address, toport, protocol)
 
 	var (
 		host, port string
@@ -132,7 +131,7 @@ func (reg *VIFRegistry) RegisterVirtualAddress(address, toport, protocol string)
 		return fmt.Errorf("invalid protocol: %s", protocol)
 	}
 
-	glog.V(2).Infof("RegisterVirtualAddress portmap: %+v", *portmap)
+	plog.Debugf("RegisterVirtualAddress portmap: %+v", *portmap)
 	if _, ok := (*portmap)[toport]; !ok {
 		// dest isn't there, let's DO IT!!!!!
 		if err := viface.redirectCommand(port, toport, protocol); err != nil {
@@ -155,7 +154,7 @@ func (viface *vif) createCommand() error {
 	c.Stderr = os.Stdout
 
 	if err := c.Run(); err != nil {
-		glog.Errorf("Adding virtual interface failed using cmd:%+v  error:%+v", command, err)
+		plog.Errorf("Adding virtual interface failed using cmd:%+v  error:%+v", command, err)
 		return err
 	}
 	command = []string{
@@ -165,7 +164,7 @@ func (viface *vif) createCommand() error {
 	c.Stdout = os.Stdout
 	c.Stderr = os.Stdout
 	if err := c.Run(); err != nil {
-		glog.Errorf("Adding IP to virtual interface failed using cmd:%+v  error:%+v", command, err)
+		plog.Errorf("Adding IP to virtual interface failed using cmd:%+v  error:%+v", command, err)
 		return err
 	}
 	command = []string{
@@ -175,14 +174,14 @@ func (viface *vif) createCommand() error {
 	c.Stdout = os.Stdout
 	c.Stderr = os.Stdout
 	if err := c.Run(); err != nil {
-		glog.Errorf("Bringing interface %s up failed using cmd:%+v  error:%+v", viface.name, command, err)
+		plog.Errorf("Bringing interface %s up failed using cmd:%+v  error:%+v", viface.name, command, err)
 		return err
 	}
 	return nil
 }
 
 func (viface *vif) redirectCommand(from, to, protocol string) error {
-	glog.Infof("Trying to set up redirect %s:%s->:%s %s", viface.hostname, from, to, protocol)
+	plog.Infof("Trying to set up redirect %s:%s->:%s %s", viface.hostname, from, to, protocol)
 	for _, chain := range []string{"OUTPUT", "PREROUTING"} {
 		command := []string{
 			"iptables",
@@ -199,15 +198,15 @@ func (viface *vif) redirectCommand(from, to, protocol string) error {
 		c.Stdout = os.Stdout
 		c.Stderr = os.Stdout
 		if err := c.Run(); err != nil {
-			glog.Errorf("Unable to set up redirect %s:%s->:%s %s command:%+v", viface.hostname, from, to, protocol, command)
+			plog.Errorf("Unable to set up redirect %s:%s->:%s %s command:%+v", viface.hostname, from, to, protocol, command)
 			return err
 		}
 	}
 
-	glog.Infof("AddToEtcHosts(%s, %s)", viface.hostname, viface.ip)
+	plog.Infof("AddToEtcHosts(%s, %s)", viface.hostname, viface.ip)
 	err := node.AddToEtcHosts(viface.hostname, viface.ip)
 	if err != nil {
-		glog.Errorf("Unable to add %s %s to /etc/hosts", viface.ip, viface.hostname)
+		plog.Errorf("Unable to add %s %s to /etc/hosts", viface.ip, viface.hostname)
 		return err
 	}
 	return nil
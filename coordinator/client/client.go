@@ -20,7 +20,6 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/coordinator/client/retry"
-	"github.com/zenoss/glog"
 )
 
 // EventType is a numerical type to identify event types.
@@ -278,7 +277,7 @@ func (client *Client) loop() {
 				func() {
 					defer func() {
 						if r := recover(); r != nil {
-							glog.Errorf("recovered from: %s", r)
+							plog.Errorf("recovered from: %s", r)
 						}
 					}()
 					(*connections[id]).Close()
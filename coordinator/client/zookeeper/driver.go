@@ -19,7 +19,6 @@ import (
 
 	zklib "github.com/control-center/go-zookeeper/zk"
 	"github.com/control-center/serviced/coordinator/client"
-	"github.com/zenoss/glog"
 )
 
 // Driver implements a Zookeeper based client.Driver interface
@@ -37,6 +36,7 @@ func init() {
 type DSN struct {
 	Servers []string
 	Timeout time.Duration
+	Auth    string `json:",omitempty"` // digest credentials (user:password) added to each session
 }
 
 // NewDSN returns a new DSN object from servers and timeout.
@@ -80,6 +80,15 @@ func (driver *Driver) GetConnection(dsn, basePath string) (client.Connection, er
 		return nil, err
 	}
 
+	// the connection does not remember its credentials, so they are added
+	// again whenever it reestablishes the session
+	addAuth := func() error {
+		if dsnVal.Auth == "" {
+			return nil
+		}
+		return conn.AddAuth("digest", []byte(dsnVal.Auth))
+	}
+
 	// wait for session event
 	connected := false
 	for !connected {
@@ -87,21 +96,32 @@ func (driver *Driver) GetConnection(dsn, basePath string) (client.Connection, er
 		case e := <-event:
 			if e.State == zklib.StateHasSession {
 				connected = true
-				glog.V(1).Infof("zk connection has session %v", e)
+				plog.Debugf("zk connection has session %v", e)
 			} else {
-				glog.V(1).Infof("waiting for zk connection to have session %v", e)
+				plog.Debugf("waiting for zk connection to have session %v", e)
 			}
 		}
 	}
+	if err := addAuth(); err != nil {
+		conn.Close()
+		return nil, err
+	}
 	go func() {
 		for {
 			select {
 			case e, ok := <-event:
-				glog.V(1).Infof("zk event %s", e)
+				plog.Debugf("zk event %s", e)
 				if !ok {
-					glog.V(1).Infoln("zk eventchannel closed")
+					plog.Debugln("zk eventchannel closed")
 					return
 				}
+				if e.State == zklib.StateHasSession {
+					go func() {
+						if err := addAuth(); err != nil {
+							plog.Warningf("Could not authenticate the zk session: %s", err)
+						}
+					}()
+				}
 			}
 		}
 	}()
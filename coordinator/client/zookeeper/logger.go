@@ -16,7 +16,6 @@ import (
 	"fmt"
 
 	zklib "github.com/control-center/go-zookeeper/zk"
-	"github.com/zenoss/glog"
 )
 
 type zkLogger struct {}
@@ -30,5 +29,5 @@ func RegisterZKLogger() {
 }
 
 func (logger zkLogger) Printf(format string, args ...interface{}) {
-	glog.V(1).Infof("zklib: %s", fmt.Sprintf(format, args))
+	plog.Debugf("zklib: %s", fmt.Sprintf(format, args))
 }
@@ -19,7 +19,6 @@ import (
 
 	zklib "github.com/control-center/go-zookeeper/zk"
 	"github.com/control-center/serviced/coordinator/client"
-	"github.com/zenoss/glog"
 )
 
 const (
@@ -65,7 +64,7 @@ func (t *Transaction) Commit() error {
 		path := path.Join(t.conn.basePath, op.Path)
 		data, err := json.Marshal(op.Node)
 		if err != nil {
-			glog.Errorf("Could not serialize node at path %s (%+v): %s", path, op.Node, err)
+			plog.Errorf("Could not serialize node at path %s (%+v): %s", path, op.Node, err)
 			return client.ErrSerialization
 		}
 		switch op.Type {
@@ -81,7 +80,7 @@ func (t *Transaction) Commit() error {
 			stat := zklib.Stat{}
 			if vers := op.Node.Version(); vers != nil {
 				if zstat, ok := vers.(*zklib.Stat); !ok {
-					glog.Errorf("Could not parse version of node at path %s (%+v): %s", path, op.Node, err)
+					plog.Errorf("Could not parse version of node at path %s (%+v): %s", path, op.Node, err)
 					return client.ErrInvalidVersionObj
 				} else {
 					stat = *zstat
@@ -95,7 +94,7 @@ func (t *Transaction) Commit() error {
 		case multiDelete:
 			_, stat, err := t.conn.conn.Get(path)
 			if err != nil {
-				glog.Errorf("Could not find path %s for delete: %s", path, err)
+				plog.Errorf("Could not find path %s for delete: %s", path, err)
 				return xlateError(err)
 			}
 			ops = append(ops, &zklib.DeleteRequest{
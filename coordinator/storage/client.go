@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -25,9 +26,10 @@ import (
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/dfs/nfs"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/zzk"
-	"github.com/zenoss/glog"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 type nfsMountT func(string, string) error
@@ -45,10 +47,14 @@ type Client struct {
 	host         *host.Host
 	exportedPath string
 	localPath    string
+	shared       pool.SharedStorage
+	transport    Transport
 	closing      chan struct{}
 	mounted      chan chan<- string
 	conn         client.Connection
 	setLock      sync.Mutex
+	freezeLock   sync.Mutex
+	frozen       bool
 }
 
 func GetClient() (*Client, error) {
@@ -78,13 +84,13 @@ func NewClient(host *host.Host, localPath string) (*Client, error) {
 func removeDeprecated(path string) {
 	mounts, err := mp.ListAll()
 	if err != nil {
-		glog.Warningf("Could not get mounts: %s", err)
+		plog.Warningf("Could not get mounts: %s", err)
 		return
 	}
 	for _, mount := range mounts {
 		if strings.HasSuffix(mount.Device, ":"+path) {
 			if err := mp.Unmount(mount.Device); err != nil {
-				glog.Warningf("Could not unmount deprecated path %s (%s): %s", mount.Device, mount.MountPoint, err)
+				plog.Warningf("Could not unmount deprecated path %s (%s): %s", mount.Device, mount.MountPoint, err)
 			}
 		}
 	}
@@ -114,13 +120,41 @@ func (c *Client) Close() {
 	close(c.closing)
 }
 
-// Mount source  to local destination path. The source is relative to the exported path
+// Mount source  to local destination path. The source is relative to the
+// exported path, or to the shared storage source of the pool if it does not
+// use nfs.
 func (c *Client) Mount(source, destination string) error {
-	return nfsMount(&nfs.NFSDriver{}, path.Join(c.exportedPath, source), destination)
+	var err error
+	if c.transport == nil {
+		err = nfsMount(&nfs.NFSDriver{}, path.Join(c.exportedPath, source), destination)
+	} else {
+		err = c.transport.Mount(c.remotePath(source), destination, c.shared.Options)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.freezeLock.Lock()
+	defer c.freezeLock.Unlock()
+	if c.frozen {
+		return remount(destination, true)
+	}
+	return nil
 }
 
 func (c *Client) Unmount(destination string) error {
-	return nfsUnmount(&nfs.NFSDriver{}, destination)
+	if c.transport == nil {
+		return nfsUnmount(&nfs.NFSDriver{}, destination)
+	}
+	return c.transport.Unmount(destination)
+}
+
+// remotePath returns the location of the source on the shared storage
+func (c *Client) remotePath(source string) string {
+	if c.shared.IsNFS() {
+		return path.Join(c.exportedPath, source)
+	}
+	return path.Join(c.shared.Source, source)
 }
 
 func (c *Client) loop() {
@@ -173,55 +207,73 @@ func (c *Client) loop() {
 			if err != nil {
 				continue
 			}
+			go c.watchFreeze(c.conn, c.setReadOnly)
 		}
 
-		glog.Infof("creating %s", nodePath)
+		plog.Infof("creating %s", nodePath)
 		if err = c.conn.Create(nodePath, node); err != nil && err != client.ErrNodeExists {
-			glog.Errorf("could not create %s: %s", nodePath, err)
+			plog.Errorf("could not create %s: %s", nodePath, err)
 			continue
 		}
 		if err == client.ErrNodeExists {
 			err = c.conn.Get(nodePath, node)
 			if err != nil && err != client.ErrEmptyNode {
-				glog.Errorf("could not get %s: %s", nodePath, err)
+				plog.Errorf("could not get %s: %s", nodePath, err)
 				continue
 			}
 		}
 		node.Host = *c.host
 		if err := c.setNode(nodePath, node, false); err != nil {
-			glog.Errorf("problem updating %s: %s", nodePath, err)
+			plog.Errorf("problem updating %s: %s", nodePath, err)
 			continue
 		}
 
 		e, err = c.conn.GetW(nodePath, node, doneW)
 		if err != nil {
-			glog.Errorf("err getting node %s: %s", nodePath, err)
+			plog.Errorf("err getting node %s: %s", nodePath, err)
 			continue
 		}
 		if err = leader.Current(leaderNode); err != nil {
-			glog.Errorf("err getting current leader: %s", err)
+			plog.Errorf("err getting current leader: %s", err)
 			continue
 		}
 
-		if leaderNode.IPAddr != c.host.IPAddr {
-			glog.Infof("Check nfs supported")
-			nfsd := &nfs.NFSDriver{}
-			err = nfsd.Installed()
+		poolNode := &zkservice.PoolNode{ResourcePool: &pool.ResourcePool{}}
+		if err = c.conn.Get(path.Join("/pools", c.host.PoolID), poolNode); err != nil {
+			plog.Errorf("could not get resource pool %s: %s", c.host.PoolID, err)
+			continue
+		}
+		shared := poolNode.SharedStorage
+		name := shared.Transport
+		if shared.IsNFS() {
+			name = DefaultTransport
+		}
+		var transport Transport
+		if transport, err = GetTransport(name); err != nil {
+			plog.Errorf("could not use shared storage transport %s for pool %s: %s", name, c.host.PoolID, err)
+			continue
+		}
+
+		if leaderNode.IPAddr != c.host.IPAddr || !shared.IsNFS() {
+			plog.Infof("Check %s supported", name)
+			err = transport.Installed()
 			if err != nil {
 				if err == nfs.ErrNfsMountingUnsupported {
-					glog.Errorf("Install the nfs-common package: %s", err)
+					plog.Errorf("Install the nfs-common package: %s", err)
 				}
-				glog.Errorf("Problem determining NFS available %s", err)
+				plog.Errorf("Problem determining %s available %s", name, err)
 				continue
 			}
 
 		} else {
-			glog.Info("skipping nfs mounting, server is localhost")
+			plog.Info("skipping nfs mounting, server is localhost")
 		}
-		glog.Infof("At this point we know the leader is: %s", leaderNode.Host.IPAddr)
+		plog.Infof("At this point we know the leader is: %s", leaderNode.Host.IPAddr)
 		select {
 		case doneC <- leaderNode.ExportPath:
 			c.exportedPath = leaderNode.ExportPath
+			c.shared = shared
+			c.transport = transport
 			storageClient = c
 			// notifying someone who cares
 			doneC = nil
@@ -229,7 +281,7 @@ func (c *Client) loop() {
 			remoteShutdown <- true
 			return
 		case evt := <-e:
-			glog.Errorf("got zk event: %v", evt)
+			plog.Errorf("got zk event: %v", evt)
 			continue
 		}
 
@@ -239,15 +291,15 @@ func (c *Client) loop() {
 }
 
 func (c *Client) setNode(nodePath string, node *Node, doGetBeforeSet bool) error {
-	glog.V(4).Infof("waiting on lock for node %s: %+v", nodePath, node)
+	plog.Debugf("waiting on lock for node %s: %+v", nodePath, node)
 	c.setLock.Lock()
 	defer c.setLock.Unlock()
-	glog.V(4).Infof("got lock for node %s: %+v", nodePath, node)
+	plog.Debugf("got lock for node %s: %+v", nodePath, node)
 
 	if doGetBeforeSet {
 		err := c.conn.Get(nodePath, node)
 		if err != nil && err != client.ErrEmptyNode {
-			glog.Warningf("could not get %s: %s", nodePath, err)
+			plog.Warningf("could not get %s: %s", nodePath, err)
 			return err
 		}
 	}
@@ -258,6 +310,45 @@ func (c *Client) setNode(nodePath string, node *Node, doGetBeforeSet bool) error
 		return err
 	}
 
-	glog.V(4).Infof("updated node %s: %+v", nodePath, node)
+	plog.Debugf("updated node %s: %+v", nodePath, node)
+	return nil
+}
+
+// setReadOnly remounts the shared volumes on this host as read-only while the
+// distributed filesystem is frozen for maintenance, and as read-write after
+// it is thawed.
+func (c *Client) setReadOnly(frozen bool) {
+	c.freezeLock.Lock()
+	defer c.freezeLock.Unlock()
+	c.frozen = frozen
+	mounts, err := mp.ListAll()
+	if err != nil {
+		plog.Errorf("Could not get mounts: %s", err)
+		return
+	}
+	for _, mount := range mounts {
+		if !IsSharedFSType(mount.FSType) {
+			continue
+		}
+		if rel, err := filepath.Rel(c.localPath, mount.MountPoint); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if err := remount(mount.MountPoint, frozen); err != nil {
+			plog.Errorf("Could not remount %s (read-only=%t): %s", mount.MountPoint, frozen, err)
+		}
+	}
+}
+
+// remount changes a mounted volume between read-only and read-write
+func remount(localPath string, readOnly bool) error {
+	mode := "rw"
+	if readOnly {
+		mode = "ro"
+	}
+	plog.Infof("Remounting %s %s", localPath, mode)
+	output, err := commandFactory("mount", "-o", "remount,"+mode, localPath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s (%s)", string(output), err)
+	}
 	return nil
 }
@@ -0,0 +1,116 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// freezePath is the node that holds the cluster-wide read-only state of the
+// distributed filesystem
+const freezePath = "/storage/freeze"
+
+// ErrDFSFrozen is returned when an operation would write to the distributed
+// filesystem while it is read-only for maintenance
+var ErrDFSFrozen = errors.New("the distributed filesystem is read-only for maintenance; run serviced dfs thaw to resume")
+
+// FreezeStatus describes whether the distributed filesystem is read-only for
+// maintenance
+type FreezeStatus struct {
+	Frozen bool
+	Reason string
+	Since  time.Time // When the dfs was frozen or thawed
+}
+
+// FreezeNode is the zookeeper node for the freeze status
+type FreezeNode struct {
+	FreezeStatus
+	version interface{}
+}
+
+// Version implements client.Node
+func (n *FreezeNode) Version() interface{} {
+	return n.version
+}
+
+// SetVersion implements client.Node
+func (n *FreezeNode) SetVersion(version interface{}) {
+	n.version = version
+}
+
+// GetFreezeStatus returns the read-only state of the distributed filesystem.
+// The connection is expected to be at the root.
+func GetFreezeStatus(conn client.Connection) (*FreezeStatus, error) {
+	node := &FreezeNode{}
+	if err := conn.Get(freezePath, node); err == client.ErrNoNode {
+		return &FreezeStatus{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &node.FreezeStatus, nil
+}
+
+// SetFreezeStatus updates the read-only state of the distributed filesystem.
+// The connection is expected to be at the root.
+func SetFreezeStatus(conn client.Connection, status FreezeStatus) error {
+	node := &FreezeNode{FreezeStatus: status}
+	existing := &FreezeNode{}
+	if err := conn.Get(freezePath, existing); err == client.ErrNoNode {
+		return conn.Create(freezePath, node)
+	} else if err != nil {
+		return err
+	}
+	node.SetVersion(existing.Version())
+	return conn.Set(freezePath, node)
+}
+
+// watchFreeze calls the handler every time the read-only state of the
+// distributed filesystem changes, until the client is closed.
+func (c *Client) watchFreeze(conn client.Connection, handler func(frozen bool)) {
+	frozen := false
+	for {
+		done := make(chan struct{})
+		isFrozen := false
+		exists, ev, err := conn.ExistsW(freezePath, done)
+		if err == nil && exists {
+			node := &FreezeNode{}
+			ev, err = conn.GetW(freezePath, node, done)
+			isFrozen = node.Frozen
+		}
+		if err != nil {
+			plog.Warningf("Could not watch the read-only state of the dfs: %s", err)
+			close(done)
+			select {
+			case <-time.After(10 * time.Second):
+				continue
+			case <-c.closing:
+				return
+			}
+		}
+		if isFrozen != frozen {
+			frozen = isFrozen
+			handler(frozen)
+		}
+		select {
+		case <-ev:
+		case <-c.closing:
+			close(done)
+			return
+		}
+		close(done)
+	}
+}
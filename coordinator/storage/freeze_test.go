@@ -0,0 +1,56 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/control-center/serviced/utils"
+)
+
+func TestClient_SetReadOnly(t *testing.T) {
+	mounts := []utils.MountInfo{
+		{Device: "10.0.0.1:/exports/serviced_volumes_v2/tenant1", MountPoint: "/volumes/tenant1", FSType: "nfs4"},
+		{Device: "10.0.0.2:6789:/serviced/tenant2", MountPoint: "/volumes/tenant2", FSType: "ceph"},
+		{Device: "/dev/sda1", MountPoint: "/volumes/local", FSType: "ext4"},
+		{Device: "10.0.0.1:/other", MountPoint: "/mnt/other", FSType: "nfs4"},
+	}
+	cmds, restore := mockTransportEnv(mounts, nil)
+	defer restore()
+
+	c := &Client{localPath: "/volumes"}
+	c.setReadOnly(true)
+	if !c.frozen {
+		t.Errorf("Expected the client to be frozen")
+	}
+	var remounted []string
+	for _, cmd := range *cmds {
+		if !reflect.DeepEqual(cmd.args[:2], []string{"-o", "remount,ro"}) {
+			t.Errorf("Unexpected command %s %v", cmd.name, cmd.args)
+		}
+		remounted = append(remounted, cmd.args[2])
+	}
+	if expected := []string{"/volumes/tenant1", "/volumes/tenant2"}; !reflect.DeepEqual(remounted, expected) {
+		t.Errorf("Expected %v to be remounted, got %v", expected, remounted)
+	}
+
+	*cmds = nil
+	c.setReadOnly(false)
+	if c.frozen || len(*cmds) != 2 || (*cmds)[0].args[1] != "remount,rw" {
+		t.Errorf("Expected the volumes to be remounted read-write")
+	}
+}
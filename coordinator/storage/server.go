@@ -21,7 +21,6 @@ import (
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/coordinator/client/zookeeper"
 	"github.com/control-center/serviced/domain/host"
-	"github.com/zenoss/glog"
 )
 
 // Server manages the exporting of a file system to clients.
@@ -85,7 +84,7 @@ func (s *Server) Run(shutdown <-chan interface{}, conn client.Connection) error
 	defer close(leaderDone)
 	leaderW, err := leader.TakeLead(node, leaderDone)
 	if err != zookeeper.ErrDeadlock && err != nil {
-		glog.Errorf("Could not take storage lead: %s", err)
+		plog.Errorf("Could not take storage lead: %s", err)
 		return err
 	}
 
@@ -97,19 +96,19 @@ func (s *Server) Run(shutdown <-chan interface{}, conn client.Connection) error
 	for {
 		clients, clientW, err := conn.ChildrenW(storageClientsPath, done)
 		if err != nil {
-			glog.Errorf("Could not set up watch for storage clients: %s", err)
+			plog.Errorf("Could not set up watch for storage clients: %s", err)
 			return err
 		}
 
 		s.driver.SetClients(clients...)
 		if err := s.driver.Sync(); err != nil {
-			glog.Errorf("Error syncing driver: %s", err)
+			plog.Errorf("Error syncing driver: %s", err)
 			return err
 		}
 
 		select {
 		case e := <-clientW:
-			glog.Info("storage.server: received event: %s", e)
+			plog.Info("storage.server: received event: %s", e)
 		case <-leaderW:
 			err := fmt.Errorf("storage.server: lost lead")
 			return err
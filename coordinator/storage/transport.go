@@ -0,0 +1,251 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/dfs/nfs"
+)
+
+// DefaultTransport is the transport used by pools that do not specify one
+const DefaultTransport = "nfs"
+
+// transportMountTimeout is how long to wait for a mount command to complete
+const transportMountTimeout = 30 * time.Second
+
+// ErrUnknownTransport is returned when a pool selects a transport that is not
+// registered
+var ErrUnknownTransport = errors.New("unknown shared storage transport")
+
+// Transport mounts the shared storage of the application volumes on a host.
+type Transport interface {
+	// Installed returns an error if the transport cannot mount on this host
+	Installed() error
+	// Mount mounts the remote path to the local path
+	Mount(remotePath, localPath, options string) error
+	// Unmount force unmounts the local path
+	Unmount(localPath string) error
+	// FSTypes returns the filesystem types of the mounts created by the
+	// transport, as they appear in the mount table
+	FSTypes() []string
+}
+
+var (
+	transportsLock sync.RWMutex
+	transports     = make(map[string]Transport)
+)
+
+func init() {
+	RegisterTransport(DefaultTransport, &nfsTransport{})
+	RegisterTransport("cephfs", &mountTransport{
+		FSType:  "ceph",
+		Helper:  "/sbin/mount.ceph",
+		Package: "ceph-common",
+	})
+	RegisterTransport("glusterfs", &mountTransport{
+		FSType:       "glusterfs",
+		MountFSTypes: []string{"fuse.glusterfs"},
+		Helper:       "/sbin/mount.glusterfs",
+		Package:      "glusterfs-client",
+	})
+}
+
+// RegisterTransport makes a transport available to resource pools.  A
+// transport with the same name as an existing transport replaces it.
+func RegisterTransport(name string, transport Transport) {
+	transportsLock.Lock()
+	defer transportsLock.Unlock()
+	transports[name] = transport
+}
+
+// GetTransport returns the transport with the given name.  An empty name
+// returns the default transport.
+func GetTransport(name string) (Transport, error) {
+	if name == "" {
+		name = DefaultTransport
+	}
+	transportsLock.RLock()
+	defer transportsLock.RUnlock()
+	transport, ok := transports[name]
+	if !ok {
+		return nil, ErrUnknownTransport
+	}
+	return transport, nil
+}
+
+// Transports returns the sorted names of the registered transports
+func Transports() []string {
+	transportsLock.RLock()
+	defer transportsLock.RUnlock()
+	names := make([]string, 0, len(transports))
+	for name := range transports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsSharedFSType returns true if mounts of the filesystem type are created
+// by one of the registered transports.
+func IsSharedFSType(fsType string) bool {
+	transportsLock.RLock()
+	defer transportsLock.RUnlock()
+	for _, transport := range transports {
+		for _, t := range transport.FSTypes() {
+			if t == fsType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nfsTransport mounts the volumes exported by the master over nfs
+type nfsTransport struct{}
+
+// Installed implements Transport
+func (t *nfsTransport) Installed() error {
+	return (&nfs.NFSDriver{}).Installed()
+}
+
+// Mount implements Transport.  The nfs driver manages its own mount options.
+func (t *nfsTransport) Mount(remotePath, localPath, options string) error {
+	return nfsMount(&nfs.NFSDriver{}, remotePath, localPath)
+}
+
+// Unmount implements Transport
+func (t *nfsTransport) Unmount(localPath string) error {
+	return nfsUnmount(&nfs.NFSDriver{}, localPath)
+}
+
+// FSTypes implements Transport
+func (t *nfsTransport) FSTypes() []string {
+	return []string{"nfs", "nfs4"}
+}
+
+// exec.Command interface (for mocking)
+type command interface {
+	CombinedOutput() ([]byte, error)
+}
+
+var commandFactory = func(name string, args ...string) command {
+	return exec.Command(name, args...)
+}
+
+var lookPath = exec.LookPath
+
+// mountTransport mounts a network filesystem using the mount helper of its
+// filesystem type, such as cephfs or glusterfs.
+type mountTransport struct {
+	FSType       string   // Filesystem type passed to mount -t
+	MountFSTypes []string // Filesystem types in the mount table, if different from FSType
+	Helper       string   // Mount helper that must be installed
+	Package      string   // Package that provides the mount helper
+}
+
+// Installed implements Transport
+func (t *mountTransport) Installed() error {
+	if _, err := lookPath(t.Helper); err != nil {
+		return fmt.Errorf("%s mounting not supported; install %s", t.FSType, t.Package)
+	}
+	return nil
+}
+
+// Mount implements Transport.  Nothing is done if the remote path is already
+// mounted at the local path.
+func (t *mountTransport) Mount(remotePath, localPath, options string) error {
+	if err := t.Installed(); err != nil {
+		return err
+	}
+	mounts, err := mp.ListAll()
+	if err != nil {
+		return err
+	}
+	for _, mount := range mounts {
+		if mount.MountPoint != localPath {
+			continue
+		}
+		if mount.Device == remotePath && t.isFSType(mount.FSType) {
+			return nil
+		}
+		plog.Infof("Replacing mount of %s at %s", mount.Device, localPath)
+		if err := t.Unmount(localPath); err != nil {
+			return err
+		}
+		break
+	}
+	if err := mkdirAll(localPath, 0775); err != nil {
+		return err
+	}
+
+	args := []string{"-t", t.FSType}
+	if options != "" {
+		args = append(args, "-o", options)
+	}
+	args = append(args, remotePath, localPath)
+
+	plog.Infof("Mounting %s -> %s (%s)", remotePath, localPath, t.FSType)
+	cmd := commandFactory("mount", args...)
+	errC := make(chan error, 1)
+	go func() {
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			err = fmt.Errorf("%s (%s)", string(output), err)
+		}
+		errC <- err
+	}()
+
+	select {
+	case <-time.After(transportMountTimeout):
+		if execCmd, ok := cmd.(*exec.Cmd); ok && execCmd.Process != nil {
+			execCmd.Process.Kill()
+		}
+		return fmt.Errorf("timeout waiting for %s mount", t.FSType)
+	case err := <-errC:
+		return err
+	}
+}
+
+// Unmount implements Transport
+func (t *mountTransport) Unmount(localPath string) error {
+	plog.Infof("Unmounting %s", localPath)
+	output, err := commandFactory("umount", "-f", localPath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s (%s)", string(output), err)
+	}
+	return nil
+}
+
+// FSTypes implements Transport
+func (t *mountTransport) FSTypes() []string {
+	if len(t.MountFSTypes) > 0 {
+		return t.MountFSTypes
+	}
+	return []string{t.FSType}
+}
+
+func (t *mountTransport) isFSType(fsType string) bool {
+	for _, f := range t.FSTypes() {
+		if f == fsType {
+			return true
+		}
+	}
+	return false
+}
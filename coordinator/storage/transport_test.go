@@ -0,0 +1,145 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package storage
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/control-center/serviced/utils"
+)
+
+type mockCommand struct {
+	name   string
+	args   []string
+	output []byte
+	err    error
+}
+
+func (c *mockCommand) CombinedOutput() ([]byte, error) {
+	return c.output, c.err
+}
+
+type mockMountProc struct {
+	mounts []utils.MountInfo
+}
+
+func (m *mockMountProc) ListAll() ([]utils.MountInfo, error) { return m.mounts, nil }
+func (m *mockMountProc) IsMounted(string) (bool, error)      { return false, nil }
+func (m *mockMountProc) Unmount(string) error                { return nil }
+
+// mockTransportEnv replaces the commands, mount table, and filesystem calls
+// used by the transports and returns the commands that were run.
+func mockTransportEnv(mounts []utils.MountInfo, cmdErr error) (*[]*mockCommand, func()) {
+	oldFactory, oldLookPath, oldMp, oldMkdirAll := commandFactory, lookPath, mp, mkdirAll
+	cmds := &[]*mockCommand{}
+	commandFactory = func(name string, args ...string) command {
+		cmd := &mockCommand{name: name, args: args, err: cmdErr}
+		*cmds = append(*cmds, cmd)
+		return cmd
+	}
+	lookPath = func(file string) (string, error) { return file, nil }
+	mp = &mockMountProc{mounts: mounts}
+	mkdirAll = func(string, os.FileMode) error { return nil }
+	return cmds, func() {
+		commandFactory, lookPath, mp, mkdirAll = oldFactory, oldLookPath, oldMp, oldMkdirAll
+	}
+}
+
+func TestGetTransport(t *testing.T) {
+	nfsT, err := GetTransport("")
+	if err != nil {
+		t.Fatalf("Unexpected error getting the default transport: %s", err)
+	}
+	if _, ok := nfsT.(*nfsTransport); !ok {
+		t.Errorf("Expected the nfs transport by default, got %T", nfsT)
+	}
+	if _, err := GetTransport("smb"); err != ErrUnknownTransport {
+		t.Errorf("Expected %s, got %v", ErrUnknownTransport, err)
+	}
+	expected := []string{"cephfs", "glusterfs", "nfs"}
+	if names := Transports(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected transports %v, got %v", expected, names)
+	}
+	for _, fsType := range []string{"nfs4", "ceph", "fuse.glusterfs"} {
+		if !IsSharedFSType(fsType) {
+			t.Errorf("Expected %s to be a shared filesystem type", fsType)
+		}
+	}
+	if IsSharedFSType("ext4") {
+		t.Errorf("Expected ext4 not to be a shared filesystem type")
+	}
+}
+
+func TestMountTransport_Mount(t *testing.T) {
+	cmds, restore := mockTransportEnv(nil, nil)
+	defer restore()
+
+	transport, _ := GetTransport("cephfs")
+	if err := transport.Mount("10.0.0.1:6789:/serviced/tenant", "/volumes/tenant", "name=admin"); err != nil {
+		t.Fatalf("Unexpected error mounting: %s", err)
+	}
+	if len(*cmds) != 1 {
+		t.Fatalf("Expected 1 command, got %d", len(*cmds))
+	}
+	cmd := (*cmds)[0]
+	expected := []string{"-t", "ceph", "-o", "name=admin", "10.0.0.1:6789:/serviced/tenant", "/volumes/tenant"}
+	if cmd.name != "mount" || !reflect.DeepEqual(cmd.args, expected) {
+		t.Errorf("Unexpected command %s %v", cmd.name, cmd.args)
+	}
+}
+
+func TestMountTransport_AlreadyMounted(t *testing.T) {
+	mounts := []utils.MountInfo{
+		{Device: "gluster1:/serviced/tenant", MountPoint: "/volumes/tenant", FSType: "fuse.glusterfs"},
+	}
+	cmds, restore := mockTransportEnv(mounts, nil)
+	defer restore()
+
+	transport, _ := GetTransport("glusterfs")
+	if err := transport.Mount("gluster1:/serviced/tenant", "/volumes/tenant", ""); err != nil {
+		t.Fatalf("Unexpected error mounting: %s", err)
+	}
+	if len(*cmds) != 0 {
+		t.Errorf("Expected no commands, got %d", len(*cmds))
+	}
+
+	// a different remote is replaced
+	if err := transport.Mount("gluster2:/serviced/tenant", "/volumes/tenant", ""); err != nil {
+		t.Fatalf("Unexpected error mounting: %s", err)
+	}
+	if len(*cmds) != 2 || (*cmds)[0].name != "umount" || (*cmds)[1].name != "mount" {
+		t.Errorf("Expected an unmount and a mount, got %d commands", len(*cmds))
+	}
+}
+
+func TestMountTransport_Errors(t *testing.T) {
+	_, restore := mockTransportEnv(nil, errors.New("exit status 32"))
+	defer restore()
+
+	transport, _ := GetTransport("cephfs")
+	if err := transport.Mount("10.0.0.1:6789:/serviced/tenant", "/volumes/tenant", ""); err == nil {
+		t.Errorf("Expected an error mounting")
+	}
+
+	lookPath = func(file string) (string, error) { return "", errors.New("not found") }
+	if err := transport.Installed(); err == nil || !strings.Contains(err.Error(), "ceph-common") {
+		t.Errorf("Expected an error naming the package to install, got %v", err)
+	}
+}
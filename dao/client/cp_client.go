@@ -94,6 +94,10 @@ func (s *ControlClient) CloneService(request dao.ServiceCloneRequest, copiedServ
 	return s.rpcClient.Call("ControlCenter.CloneService", request, copiedServiceId, 0)
 }
 
+func (s *ControlClient) CloneDeployment(request dao.DeploymentCloneRequest, tenantID *string) (err error) {
+	return s.rpcClient.Call("ControlCenter.CloneDeployment", request, tenantID, 0)
+}
+
 func (s *ControlClient) DeployService(service dao.ServiceDeploymentRequest, serviceId *string) (err error) {
 	return s.rpcClient.Call("ControlCenter.DeployService", service, serviceId, 0)
 }
@@ -110,8 +114,8 @@ func (s *ControlClient) GetServiceList(serviceID string, services *[]service.Ser
 	return s.rpcClient.Call("ControlCenter.GetServiceList", serviceID, services, 0)
 }
 
-func (s *ControlClient) RemoveService(serviceId string, unused *int) (err error) {
-	return s.rpcClient.Call("ControlCenter.RemoveService", serviceId, unused, 0)
+func (s *ControlClient) RemoveService(request dao.RemoveServiceRequest, unused *int) (err error) {
+	return s.rpcClient.Call("ControlCenter.RemoveService", request, unused, 0)
 }
 
 func (s *ControlClient) AssignIPs(assignmentRequest addressassignment.AssignmentRequest, _ *int) (err error) {
@@ -154,6 +158,14 @@ func (s *ControlClient) StopService(request dao.ScheduleServiceRequest, affected
 	return s.rpcClient.Call("ControlCenter.StopService", request, affected, 0)
 }
 
+func (s *ControlClient) PauseService(request dao.ScheduleServiceRequest, affected *int) (err error) {
+	return s.rpcClient.Call("ControlCenter.PauseService", request, affected, 0)
+}
+
+func (s *ControlClient) ResumeService(request dao.ScheduleServiceRequest, affected *int) (err error) {
+	return s.rpcClient.Call("ControlCenter.ResumeService", request, affected, 0)
+}
+
 func (s *ControlClient) WaitService(request dao.WaitServiceRequest, _ *int) (err error) {
 	return s.rpcClient.Call("ControlCenter.WaitService", request, nil, 0)
 }
@@ -201,12 +213,12 @@ func (s *ControlClient) AsyncBackup(backupRequest dao.BackupRequest, filename *s
 	return s.rpcClient.Call("ControlCenter.AsyncBackup", backupRequest, filename, 0)
 }
 
-func (s *ControlClient) Restore(filename string, unused *int) (err error) {
-	return s.rpcClient.Call("ControlCenter.Restore", filename, unused, 0)
+func (s *ControlClient) Restore(restoreRequest dao.RestoreRequest, unused *int) (err error) {
+	return s.rpcClient.Call("ControlCenter.Restore", restoreRequest, unused, 0)
 }
 
-func (s *ControlClient) AsyncRestore(filename string, unused *int) (err error) {
-	return s.rpcClient.Call("ControlCenter.AsyncRestore", filename, unused, 0)
+func (s *ControlClient) AsyncRestore(restoreRequest dao.RestoreRequest, unused *int) (err error) {
+	return s.rpcClient.Call("ControlCenter.AsyncRestore", restoreRequest, unused, 0)
 }
 
 func (s *ControlClient) ListBackups(dirpath string, files *[]dao.BackupFile) (err error) {
@@ -32,16 +32,18 @@ package elasticsearch
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/zzk"
 	zkdocker "github.com/control-center/serviced/zzk/docker"
 	"github.com/zenoss/elastigo/api"
-	"github.com/zenoss/glog"
+	"golang.org/x/net/context"
 )
 
 const (
@@ -58,6 +60,20 @@ type ControlPlaneDao struct {
 	facade       *facade.Facade
 	metricClient *metrics.Client
 	backupsPath  string
+	callTimeout  time.Duration // how long service tree calls may run
+}
+
+// context returns the context of a single call, which is canceled once the
+// call timeout elapses or once the call context, if any, is done, and which
+// is traced as part of the rpc call.  The CancelFunc must be called when the
+// call returns.
+func (this *ControlPlaneDao) context(call context.Context) (datastore.Context, context.CancelFunc) {
+	ctx, cancel := datastore.WithCallContext(datastore.GetTracedFrom(call), call)
+	ctx, cancelTimeout := datastore.WithTimeout(ctx, this.callTimeout)
+	return ctx, func() {
+		cancelTimeout()
+		cancel()
+	}
 }
 
 func serviceGetter(ctx datastore.Context, f *facade.Facade) service.GetService {
@@ -83,7 +99,7 @@ func childFinder(ctx datastore.Context, f *facade.Facade) service.FindChildServi
 }
 
 func (this *ControlPlaneDao) Action(request dao.AttachRequest, unused *int) error {
-	ctx := datastore.Get()
+	ctx := datastore.GetTraced()
 	svc, err := this.facade.GetService(ctx, request.Running.ServiceID)
 	if err != nil {
 		return err
@@ -119,36 +135,45 @@ func (this *ControlPlaneDao) Action(request dao.AttachRequest, unused *int) erro
 	return err
 }
 
-// Create a elastic search control center data access object
-func NewControlPlaneDao(hostName string, port int, rpcPort int) (*ControlPlaneDao, error) {
-	glog.V(0).Infof("Opening ElasticSearch ControlPlane Dao: hostName=%s, port=%d", hostName, port)
-	api.Domain = hostName
-	api.Port = strconv.Itoa(port)
+// Create a elastic search control center data access object.  elasticURL is
+// the url of the cluster, of the form scheme://host[:port].
+func NewControlPlaneDao(elasticURL string, rpcPort int) (*ControlPlaneDao, error) {
+	ep, err := elastic.ParseEndpoint(elasticURL)
+	if err != nil {
+		return nil, err
+	}
+	plog.Infof("Opening ElasticSearch ControlPlane Dao: url=%s", ep)
+	api.Protocol = ep.Scheme
+	api.Domain = ep.Host
+	api.Port = strconv.Itoa(int(ep.Port))
 
 	dao := &ControlPlaneDao{
-		hostName: hostName,
-		port:     port,
+		hostName: ep.Host,
+		port:     int(ep.Port),
 		rpcPort:  rpcPort,
 	}
 
 	return dao, nil
 }
 
-func NewControlSvc(hostName string, port int, facade *facade.Facade, backupsPath string, rpcPort int) (*ControlPlaneDao, error) {
-	glog.V(2).Info("calling NewControlSvc()")
-	defer glog.V(2).Info("leaving NewControlSvc()")
+func NewControlSvc(elasticURL string, facade *facade.Facade, backupsPath string, rpcPort int, callTimeout time.Duration) (*ControlPlaneDao, error) {
+	plog.Debug("calling NewControlSvc()")
+	defer plog.Debug("leaving NewControlSvc()")
 
-	s, err := NewControlPlaneDao(hostName, port, rpcPort)
+	s, err := NewControlPlaneDao(elasticURL, rpcPort)
 	if err != nil {
 		return nil, err
 	}
 	s.backupsPath = backupsPath
+	s.callTimeout = callTimeout
 
 	//Used to bridge old to new
 	s.facade = facade
 
 	// initialize the metrics client
-	metricClient, err := metrics.NewClient(fmt.Sprintf("http://%s:8888", hostName))
+	// the metrics query service always runs on the master, even when the
+	// datastore is external
+	metricClient, err := metrics.NewClient("http://localhost:8888")
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +42,6 @@ import (
 	"github.com/control-center/serviced/volume"
 	_ "github.com/control-center/serviced/volume/rsync"
 	"github.com/control-center/serviced/zzk"
-	"github.com/zenoss/glog"
 	. "gopkg.in/check.v1"
 )
 
@@ -127,10 +126,10 @@ func (dt *DaoTest) SetUpSuite(c *C) {
 	dt.FacadeIntegrationTest.SetUpSuite(c)
 
 	dsn := coordzk.NewDSN([]string{"127.0.0.1:2181"}, time.Second*15).String()
-	glog.Infof("zookeeper dsn: %s", dsn)
+	plog.Infof("zookeeper dsn: %s", dsn)
 	zClient, err := coordclient.New("zookeeper", dsn, "", nil)
 	if err != nil {
-		glog.Fatalf("Could not start es container: %s", err)
+		plog.Fatalf("Could not start es container: %s", err)
 	}
 
 	zzk.InitializeLocalClient(zClient)
@@ -144,17 +143,17 @@ func (dt *DaoTest) SetUpSuite(c *C) {
 	err = volume.InitDriver(volume.DriverTypeRsync, tmpdir, []string{})
 	c.Assert(err, IsNil)
 
-	dt.Dao, err = NewControlSvc("localhost", int(dt.Port), dt.Facade, "", 4979)
+	dt.Dao, err = NewControlSvc(fmt.Sprintf("http://localhost:%d", dt.Port), dt.Facade, "", 4979, 0)
 	if err != nil {
-		glog.Fatalf("Could not start es container: %s", err)
+		plog.Fatalf("Could not start es container: %s", err)
 	} else {
 		for i := 0; i < 10; i += 1 {
 			id := strconv.Itoa(i)
-			dt.Dao.RemoveService(id, &unused)
+			dt.Dao.RemoveService(dao.RemoveServiceRequest{ServiceID: id}, &unused)
 		}
 		for i := 100; i < 110; i += 1 {
 			id := strconv.Itoa(i)
-			dt.Dao.RemoveService(id, &unused)
+			dt.Dao.RemoveService(dao.RemoveServiceRequest{ServiceID: id}, &unused)
 		}
 	}
 }
@@ -312,7 +311,7 @@ func (dt *DaoTest) TestDao_NewService(t *C) {
 }
 
 func (dt *DaoTest) TestDao_UpdateService(t *C) {
-	dt.Dao.RemoveService("default", &unused)
+	dt.Dao.RemoveService(dao.RemoveServiceRequest{ServiceID: "default"}, &unused)
 
 	svc, _ := service.NewService()
 	svc.ID = "default0"
@@ -398,7 +397,7 @@ func (dt *DaoTest) TestDao_UpdateServiceWithConfigFile(t *C) {
 	t.Assert(result.ConfigFiles, Not(DeepEquals), result.OriginalConfigs)
 
 	//now delete service and re-add, it should have previous modified config file
-	err = dt.Dao.RemoveService(svc.ID, &unused)
+	err = dt.Dao.RemoveService(dao.RemoveServiceRequest{ServiceID: svc.ID}, &unused)
 	t.Assert(err, IsNil)
 	err = dt.Dao.AddService(*svc, &id)
 	t.Assert(err, IsNil)
@@ -497,26 +496,26 @@ func (dt *DaoTest) TestStoppingParentStopsChildren(t *C) {
 	id := "ParentServiceID"
 	var err error
 	if err = dt.Dao.AddService(svc, &id); err != nil {
-		glog.Fatalf("Failed Loading Parent Service Service: %+v, %s", svc, err)
+		plog.Fatalf("Failed Loading Parent Service Service: %+v, %s", svc, err)
 	}
 
 	childService1Id := "childService1"
 	childService2Id := "childService2"
 	if err = dt.Dao.AddService(childService1, &childService1Id); err != nil {
-		glog.Fatalf("Failed Loading Child Service 1: %+v, %s", childService1, err)
+		plog.Fatalf("Failed Loading Child Service 1: %+v, %s", childService1, err)
 	}
 	if err = dt.Dao.AddService(childService2, &childService2Id); err != nil {
-		glog.Fatalf("Failed Loading Child Service 2: %+v, %s", childService2, err)
+		plog.Fatalf("Failed Loading Child Service 2: %+v, %s", childService2, err)
 	}
 
 	// start the service
 	var affected int
-	if err = dt.Dao.StartService(dao.ScheduleServiceRequest{id, true}, &affected); err != nil {
-		glog.Fatalf("Unable to stop parent service: %+v, %s", svc, err)
+	if err = dt.Dao.StartService(dao.ScheduleServiceRequest{ServiceID: id, AutoLaunch: true}, &affected); err != nil {
+		plog.Fatalf("Unable to stop parent service: %+v, %s", svc, err)
 	}
 	// stop the parent
-	if err = dt.Dao.StopService(dao.ScheduleServiceRequest{id, true}, &affected); err != nil {
-		glog.Fatalf("Unable to stop parent service: %+v, %s", svc, err)
+	if err = dt.Dao.StopService(dao.ScheduleServiceRequest{ServiceID: id, AutoLaunch: true}, &affected); err != nil {
+		plog.Fatalf("Unable to stop parent service: %+v, %s", svc, err)
 	}
 	// verify the children have all stopped
 	var services []service.Service
@@ -577,7 +576,7 @@ func (dt *DaoTest) TestDao_StartService(t *C) {
 	t.Assert(err, IsNil)
 
 	var affected int
-	if err := dt.Dao.StartService(dao.ScheduleServiceRequest{"0", true}, &affected); err != nil {
+	if err := dt.Dao.StartService(dao.ScheduleServiceRequest{ServiceID: "0", AutoLaunch: true}, &affected); err != nil {
 		t.Fatalf("could not start services: %v", err)
 	}
 
@@ -687,14 +686,14 @@ func (dt *DaoTest) TestDao_NewSnapshot(t *C) {
 	// dependent waiting for that leader to start the watch
 	t.Skip("TODO: fix this test")
 
-	glog.V(0).Infof("TestDao_NewSnapshot started")
-	defer glog.V(0).Infof("TestDao_NewSnapshot finished")
+	plog.Infof("TestDao_NewSnapshot started")
+	defer plog.Infof("TestDao_NewSnapshot finished")
 
 	time.Sleep(2 * time.Second) // wait for Leader to start watching for snapshot requests
 
 	service := service.Service{}
 	service.ID = "service-without-quiesce"
-	dt.Dao.RemoveService(service.ID, &unused)
+	dt.Dao.RemoveService(dao.RemoveServiceRequest{ServiceID: service.ID}, &unused)
 	// snapshot should work for services without Snapshot Pause/Resume
 	err := dt.Dao.AddService(service, &id)
 	if err != nil {
@@ -702,7 +701,7 @@ func (dt *DaoTest) TestDao_NewSnapshot(t *C) {
 	}
 
 	service.ID = "service1-quiesce"
-	dt.Dao.RemoveService(service.ID, &unused)
+	dt.Dao.RemoveService(dao.RemoveServiceRequest{ServiceID: service.ID}, &unused)
 	service.Snapshot.Pause = fmt.Sprintf("STATE=paused echo %s quiesce $STATE", service.ID)
 	service.Snapshot.Resume = fmt.Sprintf("STATE=resumed echo %s quiesce $STATE", service.ID)
 	err = dt.Dao.AddService(service, &id)
@@ -711,7 +710,7 @@ func (dt *DaoTest) TestDao_NewSnapshot(t *C) {
 	}
 
 	service.ID = "service2-quiesce"
-	dt.Dao.RemoveService(service.ID, &unused)
+	dt.Dao.RemoveService(dao.RemoveServiceRequest{ServiceID: service.ID}, &unused)
 	service.Snapshot.Pause = fmt.Sprintf("STATE=paused echo %s quiesce $STATE", service.ID)
 	service.Snapshot.Resume = fmt.Sprintf("STATE=resumed echo %s quiesce $STATE", service.ID)
 	err = dt.Dao.AddService(service, &id)
@@ -729,7 +728,7 @@ func (dt *DaoTest) TestDao_NewSnapshot(t *C) {
 	if id == "" {
 		t.Fatalf("Failure creating snapshot for service %+v - label is empty", service)
 	}
-	glog.V(0).Infof("successfully created 1st snapshot with label:%s", id)
+	plog.Infof("successfully created 1st snapshot with label:%s", id)
 
 	err = dt.Dao.Snapshot(req, &id)
 	if err != nil {
@@ -738,7 +737,7 @@ func (dt *DaoTest) TestDao_NewSnapshot(t *C) {
 	if id == "" {
 		t.Fatalf("Failure creating snapshot for service %+v - label is empty", service)
 	}
-	glog.V(0).Infof("successfully created 2nd snapshot with label:%s", id)
+	plog.Infof("successfully created 2nd snapshot with label:%s", id)
 
 	time.Sleep(10 * time.Second)
 }
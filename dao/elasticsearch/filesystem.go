@@ -14,19 +14,23 @@
 package elasticsearch
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	model "github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backup"
 	"github.com/control-center/serviced/volume"
 	gzip "github.com/klauspost/pgzip"
-	"github.com/zenoss/glog"
 )
 
 // InProgress prompts which backup is currently backing up or restoring
@@ -86,7 +90,7 @@ var inprogress = &InProgress{locker: &sync.RWMutex{}}
 // Backup takes a backup of the full application stack and returns the filename
 // that it is written to.
 func (dao *ControlPlaneDao) Backup(backupRequest model.BackupRequest, filename *string) (err error) {
-	ctx := datastore.Get()
+	ctx := datastore.GetTraced()
 
 	dirpath := backupRequest.Dirpath
 	// synchronize the dfs
@@ -95,7 +99,8 @@ func (dao *ControlPlaneDao) Backup(backupRequest model.BackupRequest, filename *
 	defer dfslocker.Unlock()
 
 	// set the progress of the backup file
-	*filename = time.Now().UTC().Format("backup-2006-01-02-150405.tgz")
+	started := time.Now().UTC()
+	*filename = started.Format("backup-2006-01-02-150405.tgz")
 	backupfilename := filepath.Join(dirpath, *filename)
 	if dirpath == "" {
 		backupfilename = filepath.Join(dao.backupsPath, *filename)
@@ -103,7 +108,7 @@ func (dao *ControlPlaneDao) Backup(backupRequest model.BackupRequest, filename *
 	inprogress.SetProgress(backupfilename, "backup")
 	defer func() {
 		if err != nil {
-			glog.Errorf("Backup failed with error: %s", err)
+			plog.Errorf("Backup failed with error: %s", err)
 			os.Remove(backupfilename)
 		}
 		inprogress.SetError(err)
@@ -111,24 +116,51 @@ func (dao *ControlPlaneDao) Backup(backupRequest model.BackupRequest, filename *
 	// create the file and write
 	fh, err := os.Create(backupfilename)
 	if err != nil {
-		glog.Errorf("Could not create backup file at %s: %s", backupfilename, err)
+		plog.Errorf("Could not create backup file at %s: %s", backupfilename, err)
 		return
 	}
 	defer fh.Close()
-	w := gzip.NewWriter(fh)
+	checksum := sha256.New()
+	w := gzip.NewWriter(io.MultiWriter(fh, checksum))
 	// CC-2292: Limit concurrency of backup gzipping
 	// This setting will cause the writer to process up to 2 100KB blocks
 	// at a time before the writer blocks. The default was 16 250KB blocks.
 	// Smaller blocks will allow other goroutines to get time more frequently.
 	w.SetConcurrency(100000, 2)
 	defer w.Close()
-	err = dao.facade.Backup(ctx, w, backupRequest.Excludes, backupRequest.SnapshotSpacePercent)
-	return
+	if err = dao.facade.Backup(ctx, w, backupRequest.Excludes, backupRequest.SnapshotSpacePercent); err != nil {
+		return
+	}
+	if err = w.Close(); err != nil {
+		plog.Errorf("Could not write backup file at %s: %s", backupfilename, err)
+		return
+	}
+
+	// add the backup to the catalog; the backup itself is still usable if
+	// this fails
+	entry := backup.Backup{
+		ID:        *filename,
+		Location:  backupfilename,
+		Checksum:  hex.EncodeToString(checksum.Sum(nil)),
+		StartedAt: started,
+		Duration:  time.Since(started),
+	}
+	if fi, err := fh.Stat(); err == nil {
+		entry.Size = fi.Size()
+	}
+	info, err := dfs.ExtractBackupInfo(backupfilename)
+	if err != nil {
+		plog.Warningf("Could not read the metadata of backup %s: %s", backupfilename, err)
+	}
+	if err := dao.facade.AddBackup(ctx, entry, info); err != nil {
+		plog.Warningf("Could not add backup %s to the catalog: %s", backupfilename, err)
+	}
+	return nil
 }
 
 // AsyncBackup is the same as backup, but asynchronous
 func (dao *ControlPlaneDao) AsyncBackup(backupRequest model.BackupRequest, filename *string) (err error) {
-	ctx := datastore.Get()
+	ctx := datastore.GetTraced()
 	dfslocker := dao.facade.DFSLock(ctx)
 	dfslocker.Lock("backup")
 	inprogress.Reset()
@@ -138,8 +170,9 @@ func (dao *ControlPlaneDao) AsyncBackup(backupRequest model.BackupRequest, filen
 }
 
 // Restore restores the full application stack from a backup file.
-func (dao *ControlPlaneDao) Restore(filename string, _ *int) (err error) {
-	ctx := datastore.Get()
+func (dao *ControlPlaneDao) Restore(restoreRequest model.RestoreRequest, _ *int) (err error) {
+	filename := restoreRequest.Filename
+	ctx := datastore.GetTraced()
 
 	dfslocker := dao.facade.DFSLock(ctx)
 	dfslocker.Lock("restore")
@@ -147,7 +180,7 @@ func (dao *ControlPlaneDao) Restore(filename string, _ *int) (err error) {
 	inprogress.SetProgress(filename, "restore")
 	defer func() {
 		if err != nil {
-			glog.Errorf("Restore failed with error: %s", err)
+			plog.Errorf("Restore failed with error: %s", err)
 		}
 		inprogress.SetError(err)
 	}()
@@ -165,18 +198,18 @@ func (dao *ControlPlaneDao) Restore(filename string, _ *int) (err error) {
 		return err
 	}
 	defer gz.Close()
-	err = dao.facade.Restore(ctx, gz, info)
+	err = dao.facade.Restore(ctx, gz, info, restoreRequest.Force)
 	return err
 }
 
 // AsyncRestore is the same as restore, but asynchronous.
-func (dao *ControlPlaneDao) AsyncRestore(filename string, unused *int) (err error) {
-	ctx := datastore.Get()
+func (dao *ControlPlaneDao) AsyncRestore(restoreRequest model.RestoreRequest, unused *int) (err error) {
+	ctx := datastore.GetTraced()
 	dfslocker := dao.facade.DFSLock(ctx)
 	dfslocker.Lock("restore")
 	inprogress.Reset()
 	dfslocker.Unlock()
-	go dao.Restore(filename, unused)
+	go dao.Restore(restoreRequest, unused)
 	return
 }
 
@@ -200,6 +233,7 @@ func (dao *ControlPlaneDao) ListBackups(dirpath string, files *[]model.BackupFil
 	}
 	// What is currently running?
 	running, fp, _, _ := inprogress.GetProgress()
+	ctx := datastore.GetTraced()
 
 	for _, fi := range fis {
 		if !fi.IsDir() {
@@ -226,7 +260,7 @@ func (dao *ControlPlaneDao) ListBackups(dirpath string, files *[]model.BackupFil
 						return false
 					}
 					defer gz.Close()
-					_, err = dao.facade.BackupInfo(datastore.Get(), gz)
+					_, err = dao.facade.BackupInfo(ctx, gz)
 					if err != nil {
 						return false
 					}
@@ -262,7 +296,7 @@ func (dao *ControlPlaneDao) BackupStatus(_ model.EntityRequest, status *string)
 
 // Snapshot captures the current state of a single application
 func (dao *ControlPlaneDao) Snapshot(req model.SnapshotRequest, snapshotID *string) (err error) {
-	ctx := datastore.Get()
+	ctx := datastore.GetTraced()
 
 	// synchronize the dfs
 	dfslocker := dao.facade.DFSLock(ctx)
@@ -274,17 +308,24 @@ func (dao *ControlPlaneDao) Snapshot(req model.SnapshotRequest, snapshotID *stri
 		tagList = []string{req.Tag}
 	}
 
+	provenance := dfs.SnapshotProvenance{User: req.User, Source: req.Source}
 	if req.ContainerID != "" {
-		*snapshotID, err = dao.facade.Commit(ctx, req.ContainerID, req.Message, tagList, req.SnapshotSpacePercent)
+		if provenance.Source == "" {
+			provenance.Source = dfs.SnapshotSourceCommit
+		}
+		*snapshotID, err = dao.facade.Commit(ctx, req.ContainerID, req.Message, tagList, req.SnapshotSpacePercent, provenance)
 	} else {
-		*snapshotID, err = dao.facade.Snapshot(ctx, req.ServiceID, req.Message, tagList, req.SnapshotSpacePercent)
+		if provenance.Source == "" {
+			provenance.Source = dfs.SnapshotSourceUser
+		}
+		*snapshotID, err = dao.facade.Snapshot(ctx, req.ServiceID, req.Message, tagList, req.SnapshotSpacePercent, provenance)
 	}
 	return
 }
 
 // Rollback reverts a single application to a particular state
 func (dao *ControlPlaneDao) Rollback(req model.RollbackRequest, _ *int) (err error) {
-	ctx := datastore.Get()
+	ctx := datastore.GetTraced()
 
 	// synchronize the dfs
 	dfslocker := dao.facade.DFSLock(ctx)
@@ -296,7 +337,7 @@ func (dao *ControlPlaneDao) Rollback(req model.RollbackRequest, _ *int) (err err
 
 // DeleteSnapshot deletes a single snapshot
 func (dao *ControlPlaneDao) DeleteSnapshot(snapshotID string, _ *int) (err error) {
-	ctx := datastore.Get()
+	ctx := datastore.GetTraced()
 
 	// synchronize the dfs
 	dfslocker := dao.facade.DFSLock(ctx)
@@ -308,7 +349,7 @@ func (dao *ControlPlaneDao) DeleteSnapshot(snapshotID string, _ *int) (err error
 
 // DeleteSnapshots deletes all snapshots for a service
 func (dao *ControlPlaneDao) DeleteSnapshots(serviceID string, _ *int) (err error) {
-	ctx := datastore.Get()
+	ctx := datastore.GetTraced()
 
 	// synchronize the dfs
 	dfslocker := dao.facade.DFSLock(ctx)
@@ -320,7 +361,7 @@ func (dao *ControlPlaneDao) DeleteSnapshots(serviceID string, _ *int) (err error
 
 // ListSnapshots returns a list of all snapshots for a service
 func (dao *ControlPlaneDao) ListSnapshots(serviceID string, snapshots *[]model.SnapshotInfo) (err error) {
-	ctx := datastore.Get()
+	ctx := datastore.GetTraced()
 
 	// synchronize the dfs
 	dfslocker := dao.facade.DFSLock(ctx)
@@ -345,14 +386,7 @@ func (dao *ControlPlaneDao) ListSnapshots(serviceID string, snapshots *[]model.S
 		} else if err != nil {
 			return err
 		} else {
-			newInfo = model.SnapshotInfo{
-				SnapshotID:  info.Name,
-				TenantID:    info.TenantID,
-				Description: info.Message,
-				Tags:        info.Tags,
-				Created:     info.Created,
-				Invalid:     false,
-			}
+			newInfo = snapshotInfo(info)
 		}
 		*snapshots = append(*snapshots, newInfo)
 	}
@@ -362,7 +396,7 @@ func (dao *ControlPlaneDao) ListSnapshots(serviceID string, snapshots *[]model.S
 // ResetRegistry prompts all images to be pushed back into the docker registry
 func (dao *ControlPlaneDao) ResetRegistry(_ model.EntityRequest, _ *int) (err error) {
 	// Do not DFSLock here, Facade does that
-	err = dao.facade.SyncRegistryImages(datastore.Get(), true)
+	err = dao.facade.SyncRegistryImages(datastore.GetTraced(), true)
 	return
 }
 
@@ -370,13 +404,13 @@ func (dao *ControlPlaneDao) ResetRegistry(_ model.EntityRequest, _ *int) (err er
 // from the docker registry and save it to the index.
 func (dao *ControlPlaneDao) RepairRegistry(_ model.EntityRequest, _ *int) (err error) {
 	// Do not DFSLock here, Facade does that
-	err = dao.facade.RepairRegistry(datastore.Get())
+	err = dao.facade.RepairRegistry(datastore.GetTraced())
 	return
 }
 
 // ReadyDFS locks until it receives notice that the dfs is idle
 func (dao *ControlPlaneDao) ReadyDFS(serviceID string, _ *int) (err error) {
-	ctx := datastore.Get()
+	ctx := datastore.GetTraced()
 
 	// synchronize the dfs
 	dfslocker := dao.facade.DFSLock(ctx)
@@ -394,24 +428,34 @@ func (dao *ControlPlaneDao) TagSnapshot(request model.TagSnapshotRequest, _ *int
 
 // RemoveSnapshotTag removes a tag from an existing snapshot
 func (dao *ControlPlaneDao) RemoveSnapshotTag(request model.SnapshotByTagRequest, snapshotID *string) (err error) {
-	ctx := datastore.Get()
+	ctx := datastore.GetTraced()
 	*snapshotID, err = dao.facade.RemoveSnapshotTag(ctx, request.ServiceID, request.TagName)
 	return err
 }
 
 // GetSnapshotByServiceIDAndTag Gets the snapshot from a specific service with a specific tag
 func (dao *ControlPlaneDao) GetSnapshotByServiceIDAndTag(request model.SnapshotByTagRequest, snapshot *model.SnapshotInfo) (err error) {
-	ctx := datastore.Get()
+	ctx := datastore.GetTraced()
 	info, err := dao.facade.GetSnapshotByServiceIDAndTag(ctx, request.ServiceID, request.TagName)
 	if err != nil {
 		return
 	}
-	*snapshot = model.SnapshotInfo{
+	*snapshot = snapshotInfo(info)
+	return nil
+}
+
+// snapshotInfo describes a snapshot and where it came from
+func snapshotInfo(info *dfs.SnapshotInfo) model.SnapshotInfo {
+	return model.SnapshotInfo{
 		SnapshotID:  info.Name,
 		TenantID:    info.TenantID,
-		Description: info.Message,
+		Description: strings.SplitN(strings.TrimSpace(info.Message), "\n", 2)[0],
 		Tags:        info.Tags,
 		Created:     info.Created,
+		Message:     info.Message,
+		User:        info.Provenance.User,
+		Source:      info.Provenance.Source,
+		ImageIDs:    info.Provenance.ImageIDs,
+		Groups:      info.Provenance.Groups,
 	}
-	return nil
 }
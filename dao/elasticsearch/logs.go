@@ -20,26 +20,25 @@ import (
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/rpc/agent"
 	zks "github.com/control-center/serviced/zzk/service"
-	"github.com/zenoss/glog"
 )
 
 func (this *ControlPlaneDao) GetServiceLogs(serviceID string, logs *string) error {
-	location, err := this.facade.LocateServiceInstance(datastore.Get(), serviceID, 0)
+	location, err := this.facade.LocateServiceInstance(datastore.GetTraced(), serviceID, 0)
 	if err != nil {
-		glog.Errorf("ControlPlaneDao.GetServiceStateLogs servicestate=%+v err=%s", serviceID, err)
+		plog.Errorf("ControlPlaneDao.GetServiceStateLogs servicestate=%+v err=%s", serviceID, err)
 		return err
 	}
 
 	endpoint := fmt.Sprintf("%s:%d", location.HostIP, this.rpcPort)
 	agentClient, err := agent.NewClient(endpoint)
 	if err != nil {
-		glog.Errorf("could not create client to %s", endpoint)
+		plog.Errorf("could not create client to %s", endpoint)
 		return err
 	}
 
 	defer agentClient.Close()
 	if mylogs, err := agentClient.GetDockerLogs(location.ContainerID); err != nil {
-		glog.Errorf("could not get docker logs from agent client: %s", err)
+		plog.Errorf("could not get docker logs from agent client: %s", err)
 		return err
 	} else {
 		*logs = mylogs
@@ -54,22 +53,22 @@ func (this *ControlPlaneDao) GetServiceStateLogs(request dao.ServiceStateRequest
 		return err
 	}
 
-	location, err := this.facade.LocateServiceInstance(datastore.Get(), serviceID, instanceID)
+	location, err := this.facade.LocateServiceInstance(datastore.GetTraced(), serviceID, instanceID)
 	if err != nil {
-		glog.Errorf("ControlPlaneDao.GetServiceStateLogs servicestate=%+v err=%s", request, err)
+		plog.Errorf("ControlPlaneDao.GetServiceStateLogs servicestate=%+v err=%s", request, err)
 		return err
 	}
 
 	endpoint := fmt.Sprintf("%s:%d", location.HostIP, this.rpcPort)
 	agentClient, err := agent.NewClient(endpoint)
 	if err != nil {
-		glog.Errorf("could not create client to %s", endpoint)
+		plog.Errorf("could not create client to %s", endpoint)
 		return err
 	}
 
 	defer agentClient.Close()
 	if mylogs, err := agentClient.GetDockerLogs(location.ContainerID); err != nil {
-		glog.Errorf("could not get docker logs from agent client: %s", err)
+		plog.Errorf("could not get docker logs from agent client: %s", err)
 		return err
 	} else {
 		*logs = mylogs
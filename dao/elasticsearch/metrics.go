@@ -16,13 +16,12 @@ package elasticsearch
 import (
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/metrics"
-	"github.com/zenoss/glog"
 )
 
 func (dao *ControlPlaneDao) GetHostMemoryStats(req dao.MetricRequest, stats *metrics.MemoryUsageStats) error {
 	s, err := dao.metricClient.GetHostMemoryStats(req.StartTime, req.HostID)
 	if err != nil {
-		glog.Errorf("Could not get host memory stats for %s: %s", req.HostID, err)
+		plog.Errorf("Could not get host memory stats for %s: %s", req.HostID, err)
 		return err
 	}
 	*stats = *s
@@ -32,7 +31,7 @@ func (dao *ControlPlaneDao) GetHostMemoryStats(req dao.MetricRequest, stats *met
 func (dao *ControlPlaneDao) GetServiceMemoryStats(req dao.MetricRequest, stats *metrics.MemoryUsageStats) error {
 	s, err := dao.metricClient.GetServiceMemoryStats(req.StartTime, req.ServiceID)
 	if err != nil {
-		glog.V(2).Infof("Could not get service memory stats for %s: %s", req.ServiceID, err)
+		plog.Debugf("Could not get service memory stats for %s: %s", req.ServiceID, err)
 		return err
 	}
 	*stats = *s
@@ -42,7 +41,7 @@ func (dao *ControlPlaneDao) GetServiceMemoryStats(req dao.MetricRequest, stats *
 func (dao *ControlPlaneDao) GetInstanceMemoryStats(req dao.MetricRequest, stats *[]metrics.MemoryUsageStats) error {
 	s, err := dao.facade.GetInstanceMemoryStats(req.StartTime, req.Instances...)
 	if err != nil {
-		glog.V(2).Infof("Could not get service instance stats for %+v: %s", req.Instances, err)
+		plog.Debugf("Could not get service instance stats for %+v: %s", req.Instances, err)
 		return err
 	}
 	*stats = s
@@ -14,30 +14,28 @@
 package elasticsearch
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
-	"github.com/control-center/serviced/domain/service"
-	"github.com/control-center/serviced/utils"
 )
 
 func (this *ControlPlaneDao) GetRunningServices(request dao.EntityRequest, allRunningServices *[]dao.RunningService) (err error) {
 	since := time.Now().Add(-time.Hour)
 
-	hosts, err := this.facade.GetHosts(datastore.Get())
+	ctx := datastore.GetTraced()
+	hosts, err := this.facade.GetHosts(ctx)
 	if err != nil {
 		return err
 	}
 	var rss []dao.RunningService
 	for _, h := range hosts {
-		insts, err := this.facade.GetHostInstances(datastore.Get(), since, h.ID)
+		insts, err := this.facade.GetHostInstances(ctx, since, h.ID)
 		if err != nil {
 			return err
 		}
 		for _, inst := range insts {
-			rss = append(rss, convertInstanceToRunningService(inst))
+			rss = append(rss, dao.NewRunningService(inst))
 		}
 	}
 	*allRunningServices = rss
@@ -47,14 +45,14 @@ func (this *ControlPlaneDao) GetRunningServices(request dao.EntityRequest, allRu
 func (this *ControlPlaneDao) GetRunningServicesForHost(hostID string, services *[]dao.RunningService) error {
 	since := time.Now().Add(-time.Hour)
 
-	insts, err := this.facade.GetHostInstances(datastore.Get(), since, hostID)
+	insts, err := this.facade.GetHostInstances(datastore.GetTraced(), since, hostID)
 	if err != nil {
 		return nil
 	}
 
 	rss := make([]dao.RunningService, len(insts))
 	for i, inst := range insts {
-		rss[i] = convertInstanceToRunningService(inst)
+		rss[i] = dao.NewRunningService(inst)
 	}
 	*services = rss
 	return nil
@@ -63,31 +61,15 @@ func (this *ControlPlaneDao) GetRunningServicesForHost(hostID string, services *
 func (this *ControlPlaneDao) GetRunningServicesForService(serviceID string, services *[]dao.RunningService) error {
 	since := time.Now().Add(-time.Hour)
 
-	insts, err := this.facade.GetServiceInstances(datastore.Get(), since, serviceID)
+	insts, err := this.facade.GetServiceInstances(datastore.GetTraced(), since, serviceID)
 	if err != nil {
 		return err
 	}
 
 	rss := make([]dao.RunningService, len(insts))
 	for i, inst := range insts {
-		rss[i] = convertInstanceToRunningService(inst)
+		rss[i] = dao.NewRunningService(inst)
 	}
 	*services = rss
 	return nil
 }
-
-// FIXME: this will be deleted
-func convertInstanceToRunningService(inst service.Instance) dao.RunningService {
-	return dao.RunningService{
-		ID:            fmt.Sprintf("%s-%s-%d", inst.HostID, inst.ServiceID, inst.InstanceID),
-		ServiceID:     inst.ServiceID,
-		HostID:        inst.HostID,
-		DockerID:      inst.ContainerID,
-		StartedAt:     inst.Started,
-		InSync:        inst.ImageSynced,
-		Name:          inst.ServiceName,
-		DesiredState:  int(inst.DesiredState),
-		RAMCommitment: utils.NewEngNotation(inst.RAMCommitment),
-		InstanceID:    inst.InstanceID,
-	}
-}
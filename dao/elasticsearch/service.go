@@ -20,7 +20,6 @@ import (
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/service"
-	"github.com/zenoss/glog"
 )
 
 const (
@@ -29,17 +28,18 @@ const (
 
 // AddService adds a new service. Returns an error if service already exists.
 func (this *ControlPlaneDao) AddService(svc service.Service, serviceId *string) error {
-	if err := this.facade.DFSLock(datastore.Get()).LockWithTimeout("add service", userLockTimeout); err != nil {
-		glog.Warningf("Cannot add service: %s", err)
+	ctx := datastore.GetTraced()
+	if err := this.facade.DFSLock(ctx).LockWithTimeout("add service", userLockTimeout); err != nil {
+		plog.Warningf("Cannot add service: %s", err)
 		return err
 	}
-	defer this.facade.DFSLock(datastore.Get()).Unlock()
+	defer this.facade.DFSLock(ctx).Unlock()
 
-	return this.addService(svc, serviceId)
+	return this.addService(ctx, svc, serviceId)
 }
 
-func (this *ControlPlaneDao) addService(svc service.Service, serviceId *string) error {
-	if err := this.facade.AddService(datastore.Get(), svc); err != nil {
+func (this *ControlPlaneDao) addService(ctx datastore.Context, svc service.Service, serviceId *string) error {
+	if err := this.facade.AddService(ctx, svc); err != nil {
 		return err
 	}
 	*serviceId = svc.ID
@@ -48,55 +48,76 @@ func (this *ControlPlaneDao) addService(svc service.Service, serviceId *string)
 
 // CloneService clones a service. Returns an error if given serviceID is not found.
 func (this *ControlPlaneDao) CloneService(request dao.ServiceCloneRequest, clonedServiceId *string) error {
-	if err := this.facade.DFSLock(datastore.Get()).LockWithTimeout("clone service", userLockTimeout); err != nil {
-		glog.Warningf("Cannot clone service: %s", err)
+	ctx := datastore.GetTraced()
+	if err := this.facade.DFSLock(ctx).LockWithTimeout("clone service", userLockTimeout); err != nil {
+		plog.Warningf("Cannot clone service: %s", err)
 		return err
 	}
-	defer this.facade.DFSLock(datastore.Get()).Unlock()
+	defer this.facade.DFSLock(ctx).Unlock()
 
-	svc, err := this.facade.GetService(datastore.Get(), request.ServiceID)
+	svc, err := this.facade.GetService(ctx, request.ServiceID)
 	if err != nil {
-		glog.Errorf("ControlPlaneDao.CloneService: unable to find service id %+v: %s", request.ServiceID, err)
+		plog.Errorf("ControlPlaneDao.CloneService: unable to find service id %+v: %s", request.ServiceID, err)
 		return err
 	}
 
 	cloned, err := service.CloneService(svc, request.Suffix)
 	if err != nil {
-		glog.Errorf("ControlPlaneDao.CloneService: unable to rename service %+v %v: %s", svc.ID, svc.Name, err)
+		plog.Errorf("ControlPlaneDao.CloneService: unable to rename service %+v %v: %s", svc.ID, svc.Name, err)
 		return err
 	}
 
-	if err := this.addService(*cloned, clonedServiceId); err != nil {
+	if err := this.addService(ctx, *cloned, clonedServiceId); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-//
+// CloneDeployment clones a tenant and its services into a new deployment.
+func (this *ControlPlaneDao) CloneDeployment(request dao.DeploymentCloneRequest, tenantID *string) error {
+	ctx := datastore.GetTraced()
+	if err := this.facade.DFSLock(ctx).LockWithTimeout("clone deployment", userLockTimeout); err != nil {
+		plog.Warningf("Cannot clone deployment: %s", err)
+		return err
+	}
+	defer this.facade.DFSLock(ctx).Unlock()
+
+	id, err := this.facade.CloneDeployment(ctx, request)
+	if err != nil {
+		return err
+	}
+	*tenantID = id
+	return nil
+}
+
 func (this *ControlPlaneDao) UpdateService(svc service.Service, unused *int) error {
-	if err := this.facade.DFSLock(datastore.Get()).LockWithTimeout("update service", userLockTimeout); err != nil {
-		glog.Warningf("Cannot update service: %s", err)
+	ctx := datastore.GetTraced()
+	if err := this.facade.DFSLock(ctx).LockWithTimeout("update service", userLockTimeout); err != nil {
+		plog.Warningf("Cannot update service: %s", err)
 		return err
 	}
-	defer this.facade.DFSLock(datastore.Get()).Unlock()
+	defer this.facade.DFSLock(ctx).Unlock()
 
-	return this.facade.UpdateService(datastore.Get(), svc)
+	return this.facade.UpdateService(ctx, svc)
 }
 
 //
 func (this *ControlPlaneDao) MigrateServices(request dao.ServiceMigrationRequest, unused *int) error {
-	if err := this.facade.DFSLock(datastore.Get()).LockWithTimeout("migrate service", userLockTimeout); err != nil {
-		glog.Warningf("Cannot migrate service: %s", err)
+	ctx := datastore.GetTraced()
+	if err := this.facade.DFSLock(ctx).LockWithTimeout("migrate service", userLockTimeout); err != nil {
+		plog.Warningf("Cannot migrate service: %s", err)
 		return err
 	}
-	defer this.facade.DFSLock(datastore.Get()).Unlock()
+	defer this.facade.DFSLock(ctx).Unlock()
 
-	return this.facade.MigrateServices(datastore.Get(), request)
+	return this.facade.MigrateServices(ctx, request)
 }
 
 func (this *ControlPlaneDao) GetServiceList(serviceID string, services *[]service.Service) error {
-	if svcs, err := this.facade.GetServiceList(datastore.Get(), serviceID); err != nil {
+	ctx, cancel := this.context(nil)
+	defer cancel()
+	if svcs, err := this.facade.GetServiceList(ctx, serviceID); err != nil {
 		return err
 	} else {
 		var out []service.Service
@@ -109,19 +130,21 @@ func (this *ControlPlaneDao) GetServiceList(serviceID string, services *[]servic
 }
 
 //
-func (this *ControlPlaneDao) RemoveService(id string, unused *int) error {
-	if err := this.facade.DFSLock(datastore.Get()).LockWithTimeout("remove service", userLockTimeout); err != nil {
-		glog.Warningf("Cannot remove service: %s", err)
+func (this *ControlPlaneDao) RemoveService(request dao.RemoveServiceRequest, unused *int) error {
+	ctx, cancel := this.context(request.CallContext())
+	defer cancel()
+	if err := this.facade.DFSLock(ctx).LockWithTimeout("remove service", userLockTimeout); err != nil {
+		plog.Warningf("Cannot remove service: %s", err)
 		return err
 	}
-	defer this.facade.DFSLock(datastore.Get()).Unlock()
+	defer this.facade.DFSLock(ctx).Unlock()
 
-	return this.facade.RemoveService(datastore.Get(), id)
+	return this.facade.RemoveService(ctx, request.ServiceID)
 }
 
 // GetService gets a service.
 func (this *ControlPlaneDao) GetService(id string, myService *service.Service) error {
-	svc, err := this.facade.GetService(datastore.Get(), id)
+	svc, err := this.facade.GetService(datastore.GetTraced(), id)
 	if svc != nil {
 		*myService = *svc
 	}
@@ -130,7 +153,9 @@ func (this *ControlPlaneDao) GetService(id string, myService *service.Service) e
 
 // Get the services (can filter by name and/or tenantID)
 func (this *ControlPlaneDao) GetServices(request dao.ServiceRequest, services *[]service.Service) error {
-	if svcs, err := this.facade.GetServices(datastore.Get(), request); err == nil {
+	ctx, cancel := this.context(request.CallContext())
+	defer cancel()
+	if svcs, err := this.facade.GetServices(ctx, request); err == nil {
 		*services = svcs
 		return nil
 	} else {
@@ -140,7 +165,7 @@ func (this *ControlPlaneDao) GetServices(request dao.ServiceRequest, services *[
 
 //
 func (this *ControlPlaneDao) FindChildService(request dao.FindChildRequest, service *service.Service) error {
-	svc, err := this.facade.FindChildService(datastore.Get(), request.ServiceID, request.ChildName)
+	svc, err := this.facade.FindChildService(datastore.GetTraced(), request.ServiceID, request.ChildName)
 	if err != nil {
 		return err
 	}
@@ -148,14 +173,16 @@ func (this *ControlPlaneDao) FindChildService(request dao.FindChildRequest, serv
 	if svc != nil {
 		*service = *svc
 	} else {
-		glog.Warningf("unable to find child of service: %+v", service)
+		plog.Warningf("unable to find child of service: %+v", service)
 	}
 	return nil
 }
 
 // Get tagged services (can also filter by name and/or tenantID)
 func (this *ControlPlaneDao) GetTaggedServices(request dao.ServiceRequest, services *[]service.Service) error {
-	if svcs, err := this.facade.GetTaggedServices(datastore.Get(), request); err == nil {
+	ctx, cancel := this.context(request.CallContext())
+	defer cancel()
+	if svcs, err := this.facade.GetTaggedServices(ctx, request); err == nil {
 		*services = svcs
 		return nil
 	} else {
@@ -165,33 +192,45 @@ func (this *ControlPlaneDao) GetTaggedServices(request dao.ServiceRequest, servi
 
 // start the provided service
 func (this *ControlPlaneDao) StartService(request dao.ScheduleServiceRequest, affected *int) (err error) {
-	*affected, err = this.facade.StartService(datastore.Get(), request)
+	*affected, err = this.facade.StartService(datastore.GetTraced(), request)
 	return err
 }
 
 // restart the provided service
 func (this *ControlPlaneDao) RestartService(request dao.ScheduleServiceRequest, affected *int) (err error) {
-	*affected, err = this.facade.RestartService(datastore.Get(), request)
+	*affected, err = this.facade.RestartService(datastore.GetTraced(), request)
 	return err
 }
 
 // stop the provided service
 func (this *ControlPlaneDao) StopService(request dao.ScheduleServiceRequest, affected *int) (err error) {
-	*affected, err = this.facade.StopService(datastore.Get(), request)
+	*affected, err = this.facade.StopService(datastore.GetTraced(), request)
+	return err
+}
+
+// pause the provided service
+func (this *ControlPlaneDao) PauseService(request dao.ScheduleServiceRequest, affected *int) (err error) {
+	*affected, err = this.facade.PauseService(datastore.GetTraced(), request)
+	return err
+}
+
+// resume the provided paused service
+func (this *ControlPlaneDao) ResumeService(request dao.ScheduleServiceRequest, affected *int) (err error) {
+	*affected, err = this.facade.ResumeService(datastore.GetTraced(), request)
 	return err
 }
 
 // WaitService waits for the given service IDs to reach a particular state
 func (this *ControlPlaneDao) WaitService(request dao.WaitServiceRequest, _ *int) (err error) {
-	return this.facade.WaitService(datastore.Get(), request.DesiredState, request.Timeout, request.Recursive, request.ServiceIDs...)
+	return this.facade.WaitService(datastore.GetTraced(), request.DesiredState, request.Timeout, request.Recursive, request.ServiceIDs...)
 }
 
 // assign an IP address to a service (and all its child services) containing non default AddressResourceConfig
 func (this *ControlPlaneDao) AssignIPs(assignmentRequest addressassignment.AssignmentRequest, _ *int) error {
-	return this.facade.AssignIPs(datastore.Get(), assignmentRequest)
+	return this.facade.AssignIPs(datastore.GetTraced(), assignmentRequest)
 }
 
 func (this *ControlPlaneDao) DeployService(request dao.ServiceDeploymentRequest, serviceID *string) (err error) {
-	*serviceID, err = this.facade.DeployService(datastore.Get(), request.PoolID, request.ParentID, request.Overwrite, request.Service)
+	*serviceID, err = this.facade.DeployService(datastore.GetTraced(), request.PoolID, request.ParentID, request.Overwrite, request.Service)
 	return
 }
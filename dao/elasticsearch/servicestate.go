@@ -24,19 +24,18 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/zzk"
 	zks "github.com/control-center/serviced/zzk/service"
-	"github.com/zenoss/glog"
 )
 
 func (this *ControlPlaneDao) getPoolBasedConnection(serviceID string) (client.Connection, error) {
-	poolID, err := this.facade.GetPoolForService(datastore.Get(), serviceID)
+	poolID, err := this.facade.GetPoolForService(datastore.GetTraced(), serviceID)
 	if err != nil {
-		glog.V(2).Infof("ControlPlaneDao.GetPoolForService service=%+v err=%s", serviceID, err)
+		plog.Debugf("ControlPlaneDao.GetPoolForService service=%+v err=%s", serviceID, err)
 		return nil, err
 	}
 
 	poolBasedConn, err := zzk.GetLocalConnection(zzk.GeneratePoolPath(poolID))
 	if err != nil {
-		glog.Errorf("Error in getting a connection based on pool %v: %v", poolID, err)
+		plog.Errorf("Error in getting a connection based on pool %v: %v", poolID, err)
 		return nil, err
 	}
 	return poolBasedConn, nil
@@ -47,12 +46,12 @@ func (this *ControlPlaneDao) StopRunningInstance(request dao.HostServiceRequest,
 	if err != nil {
 		return err
 	}
-	return this.facade.StopServiceInstance(datastore.Get(), serviceID, instanceID)
+	return this.facade.StopServiceInstance(datastore.GetTraced(), serviceID, instanceID)
 }
 
 func (this *ControlPlaneDao) GetServiceStatus(serviceID string, status *[]service.Instance) error {
 	since := time.Now().Add(-time.Hour)
-	inst, err := this.facade.GetServiceInstances(datastore.Get(), since, serviceID)
+	inst, err := this.facade.GetServiceInstances(datastore.GetTraced(), since, serviceID)
 	if err != nil {
 		return err
 	}
@@ -27,9 +27,11 @@ package dao
 import (
 	"time"
 
+	"github.com/control-center/serviced/commons/jsonpatch"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/metrics"
+	"golang.org/x/net/context"
 )
 
 // A generic ControlPlane error
@@ -50,6 +52,41 @@ type ServiceRequest struct {
 	TenantID     string
 	UpdatedSince time.Duration
 	NameRegex    string
+	ctx          context.Context // canceled when the rpc client disconnects
+}
+
+// SetCallContext sets the context of the rpc call that delivered the request
+func (r *ServiceRequest) SetCallContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// CallContext returns the context of the rpc call that delivered the
+// request, or nil if it was not delivered over rpc
+func (r ServiceRequest) CallContext() context.Context {
+	return r.ctx
+}
+
+// Unfiltered returns true if the request asks for all services
+func (r ServiceRequest) Unfiltered() bool {
+	return len(r.Tags) == 0 && r.TenantID == "" && r.UpdatedSince == 0 && r.NameRegex == ""
+}
+
+// RemoveServiceRequest asks to remove a service and its children, which for
+// a tenant is the whole application
+type RemoveServiceRequest struct {
+	ServiceID string
+	ctx       context.Context // canceled when the rpc client disconnects
+}
+
+// SetCallContext sets the context of the rpc call that delivered the request
+func (r *RemoveServiceRequest) SetCallContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// CallContext returns the context of the rpc call that delivered the
+// request, or nil if it was not delivered over rpc
+func (r RemoveServiceRequest) CallContext() context.Context {
+	return r.ctx
 }
 
 type ServiceCloneRequest struct {
@@ -57,11 +94,27 @@ type ServiceCloneRequest struct {
 	Suffix    string
 }
 
+type DeploymentCloneRequest struct {
+	TenantID              string
+	PoolID                string
+	DeploymentID          string
+	SnapshotID            string // seeds the volume of the clone, if set
+	EnablePublicEndpoints bool
+}
+
 type ServiceMigrationRequest struct {
 	ServiceID string
 	Modified  []*service.Service
 	Added     []*service.Service
 	Deploy    []*ServiceDeploymentRequest
+	Patches   []*ServicePatch
+	DryRun    bool // Validate the migration without applying it
+}
+
+// ServicePatch is a JSON patch (RFC 6902) of changes to an existing service
+type ServicePatch struct {
+	ServiceID string
+	Patch     jsonpatch.Patch
 }
 
 type ServiceStateRequest struct {
@@ -71,7 +124,10 @@ type ServiceStateRequest struct {
 
 type ScheduleServiceRequest struct {
 	ServiceID  string
+	ServiceIDs []string // Additional services to schedule in the same request
 	AutoLaunch bool
+	Source     string // what requested the change, for the state history of the services
+	User       string // user that requested the change, if known
 }
 
 type WaitServiceRequest struct {
@@ -103,6 +159,8 @@ type SnapshotRequest struct {
 	Tag                  string
 	ContainerID          string
 	SnapshotSpacePercent int
+	User                 string // user that requested the snapshot
+	Source               string // defaults to commit if ContainerID is set, otherwise user
 }
 
 type TagSnapshotRequest struct {
@@ -139,6 +197,9 @@ type ControlPlane interface {
 	// Clones a new service
 	CloneService(request ServiceCloneRequest, serviceId *string) error
 
+	// Clones a tenant into a new deployment
+	CloneDeployment(request DeploymentCloneRequest, tenantID *string) error
+
 	// Deploy a new service
 	DeployService(svc ServiceDeploymentRequest, serviceId *string) error
 
@@ -149,7 +210,7 @@ type ControlPlane interface {
 	MigrateServices(request ServiceMigrationRequest, unused *int) error
 
 	// Remove a service definition
-	RemoveService(serviceId string, unused *int) error
+	RemoveService(request RemoveServiceRequest, unused *int) error
 
 	// Get a service from serviced
 	GetService(serviceId string, svc *service.Service) error
@@ -178,6 +239,12 @@ type ControlPlane interface {
 	// Schedule the given service to stop
 	StopService(request ScheduleServiceRequest, affected *int) error
 
+	// Schedule the given service to pause without stopping its containers
+	PauseService(request ScheduleServiceRequest, affected *int) error
+
+	// Schedule the given paused service to run
+	ResumeService(request ScheduleServiceRequest, affected *int) error
+
 	// Stop a running instance of a service
 	StopRunningInstance(request HostServiceRequest, unused *int) error
 
@@ -228,10 +295,10 @@ type ControlPlane interface {
 	AsyncBackup(backupRequest BackupRequest, filename *string) (err error)
 
 	// Restore reverts the full application stack from a backup file
-	Restore(filename string, unused *int) (err error)
+	Restore(restoreRequest RestoreRequest, unused *int) (err error)
 
 	// AsyncRestore is the same as restore but asynchronous
-	AsyncRestore(filename string, unused *int) (err error)
+	AsyncRestore(restoreRequest RestoreRequest, unused *int) (err error)
 
 	// Adds 1 or more tags to an existing snapshot
 	TagSnapshot(request TagSnapshotRequest, unused *int) error
@@ -35,6 +35,18 @@ func (_m *ControlPlane) CloneService(request dao.ServiceCloneRequest, serviceId
 
 	return r0
 }
+func (_m *ControlPlane) CloneDeployment(request dao.DeploymentCloneRequest, tenantID *string) error {
+	ret := _m.Called(request, tenantID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(dao.DeploymentCloneRequest, *string) error); ok {
+		r0 = rf(request, tenantID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
 func (_m *ControlPlane) DeployService(svc dao.ServiceDeploymentRequest, serviceId *string) error {
 	ret := _m.Called(svc, serviceId)
 
@@ -71,12 +83,12 @@ func (_m *ControlPlane) MigrateServices(request dao.ServiceMigrationRequest, unu
 
 	return r0
 }
-func (_m *ControlPlane) RemoveService(serviceId string, unused *int) error {
-	ret := _m.Called(serviceId, unused)
+func (_m *ControlPlane) RemoveService(request dao.RemoveServiceRequest, unused *int) error {
+	ret := _m.Called(request, unused)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *int) error); ok {
-		r0 = rf(serviceId, unused)
+	if rf, ok := ret.Get(0).(func(dao.RemoveServiceRequest, *int) error); ok {
+		r0 = rf(request, unused)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -179,6 +191,30 @@ func (_m *ControlPlane) StopService(request dao.ScheduleServiceRequest, affected
 
 	return r0
 }
+func (_m *ControlPlane) PauseService(request dao.ScheduleServiceRequest, affected *int) error {
+	ret := _m.Called(request, affected)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(dao.ScheduleServiceRequest, *int) error); ok {
+		r0 = rf(request, affected)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ControlPlane) ResumeService(request dao.ScheduleServiceRequest, affected *int) error {
+	ret := _m.Called(request, affected)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(dao.ScheduleServiceRequest, *int) error); ok {
+		r0 = rf(request, affected)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
 func (_m *ControlPlane) StopRunningInstance(request dao.HostServiceRequest, unused *int) error {
 	ret := _m.Called(request, unused)
 
@@ -347,24 +383,24 @@ func (_m *ControlPlane) AsyncBackup(backupRequest dao.BackupRequest, filename *s
 
 	return r0
 }
-func (_m *ControlPlane) Restore(filename string, unused *int) error {
-	ret := _m.Called(filename, unused)
+func (_m *ControlPlane) Restore(restoreRequest dao.RestoreRequest, unused *int) error {
+	ret := _m.Called(restoreRequest, unused)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *int) error); ok {
-		r0 = rf(filename, unused)
+	if rf, ok := ret.Get(0).(func(dao.RestoreRequest, *int) error); ok {
+		r0 = rf(restoreRequest, unused)
 	} else {
 		r0 = ret.Error(0)
 	}
 
 	return r0
 }
-func (_m *ControlPlane) AsyncRestore(filename string, unused *int) error {
-	ret := _m.Called(filename, unused)
+func (_m *ControlPlane) AsyncRestore(restoreRequest dao.RestoreRequest, unused *int) error {
+	ret := _m.Called(restoreRequest, unused)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *int) error); ok {
-		r0 = rf(filename, unused)
+	if rf, ok := ret.Get(0).(func(dao.RestoreRequest, *int) error); ok {
+		r0 = rf(restoreRequest, unused)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -14,10 +14,12 @@
 package dao
 
 import (
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/utils"
 )
@@ -63,6 +65,22 @@ type RunningService struct {
 	MonitoringProfile domain.MonitorProfile
 }
 
+// NewRunningService converts a service instance into a running service
+func NewRunningService(inst service.Instance) RunningService {
+	return RunningService{
+		ID:            fmt.Sprintf("%s-%s-%d", inst.HostID, inst.ServiceID, inst.InstanceID),
+		ServiceID:     inst.ServiceID,
+		HostID:        inst.HostID,
+		DockerID:      inst.ContainerID,
+		StartedAt:     inst.Started,
+		InSync:        inst.ImageSynced,
+		Name:          inst.ServiceName,
+		DesiredState:  int(inst.DesiredState),
+		RAMCommitment: utils.NewEngNotation(inst.RAMCommitment),
+		InstanceID:    inst.InstanceID,
+	}
+}
+
 // BackupFile is the structure for backup file data
 type BackupFile struct {
 	InProgress bool        `json:"in_progress"`
@@ -76,10 +94,15 @@ type BackupFile struct {
 type SnapshotInfo struct {
 	SnapshotID  string
 	TenantID    string
-	Description string
+	Description string // first line of the message
 	Tags        []string
 	Created     time.Time
 	Invalid     bool
+	Message     string                            // full message of the snapshot
+	User        string                            // user that triggered the snapshot
+	Source      string                            // what created the snapshot (user, shell, commit, scheduled)
+	ImageIDs    []string                          // ids of the service images that were snapshotted
+	Groups      []servicedefinition.SnapshotGroup // consistency groups that were quiesced together
 }
 
 func (s SnapshotInfo) String() string {
@@ -110,7 +133,10 @@ func (s *SnapshotInfo) Equals(s2 *SnapshotInfo) bool {
 		s.TenantID == s2.TenantID &&
 		s.Description == s2.Description &&
 		s.Created == s2.Created &&
-		s.Invalid == s2.Invalid
+		s.Invalid == s2.Invalid &&
+		s.Message == s2.Message &&
+		s.User == s2.User &&
+		s.Source == s2.Source
 }
 
 // ServiceInstanceRequest requests information about a service instance given
@@ -125,3 +151,8 @@ type BackupRequest struct {
 	SnapshotSpacePercent int
 	Excludes             []string
 }
+
+type RestoreRequest struct {
+	Filename string
+	Force    []string // restore compatibility checks to ignore
+}
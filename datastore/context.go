@@ -14,11 +14,19 @@
 package datastore
 
 import (
+	"time"
+
 	"github.com/control-center/serviced/metrics"
+	"github.com/control-center/serviced/tracing"
+	netcontext "golang.org/x/net/context"
 )
 
-// Context is the context of the application or request being made
+// Context is the context of the application or request being made.  It
+// carries the cancellation signal and deadline of the request, so that long
+// running operations can stop early when the caller gives up.
 type Context interface {
+	netcontext.Context
+
 	// Get a connection to the datastore
 	Connection() (Connection, error)
 
@@ -39,6 +47,102 @@ func Get() Context {
 	return ctx
 }
 
+// GetTraced returns a new context that records the calls made with it as
+// trace spans if tracing is enabled, or the global Context if it is not.  Get
+// a new context for each request being traced.
+func GetTraced() Context {
+	if !tracing.Enabled() {
+		return ctx
+	}
+	return &context{netcontext.Background(), savedDriver, metrics.NewTracedMetrics()}
+}
+
+// GetTracedFrom returns a new traced context like GetTraced, except that its
+// calls are recorded in the trace of the span carried by the parent, such as
+// the span of the rpc call that the context serves.  The parent only provides
+// the span; use WithCallContext to follow its cancellation.
+func GetTracedFrom(parent netcontext.Context) Context {
+	if !tracing.Enabled() {
+		return ctx
+	}
+	if parent != nil {
+		if span, ok := tracing.SpanFromContext(parent); ok {
+			return &context{netcontext.Background(), savedDriver, metrics.NewChildTracedMetrics(span)}
+		}
+	}
+	return GetTraced()
+}
+
+// WithCancel returns a copy of the parent context that is canceled when the
+// returned CancelFunc is called or when the parent is canceled.
+func WithCancel(parent Context) (Context, netcontext.CancelFunc) {
+	c, cancel := netcontext.WithCancel(parent)
+	return &derivedContext{c, parent}, cancel
+}
+
+// WithTimeout returns a copy of the parent context that is canceled after the
+// timeout elapses.  A timeout of zero or less sets no deadline.  Callers
+// should always call the CancelFunc to release the resources of the context
+// when the request completes.
+func WithTimeout(parent Context, timeout time.Duration) (Context, netcontext.CancelFunc) {
+	if timeout <= 0 {
+		return WithCancel(parent)
+	}
+	c, cancel := netcontext.WithTimeout(parent, timeout)
+	return &derivedContext{c, parent}, cancel
+}
+
+// WithCallContext returns a copy of the parent context that is also canceled
+// when the context of the call is done, such as when the client of an rpc
+// call disconnects.  A nil call context is ignored.
+func WithCallContext(parent Context, call netcontext.Context) (Context, netcontext.CancelFunc) {
+	c, cancel := netcontext.WithCancel(parent)
+	if call != nil {
+		go func() {
+			select {
+			case <-call.Done():
+				cancel()
+			case <-c.Done():
+			}
+		}()
+	}
+	return &derivedContext{c, parent}, cancel
+}
+
+// actorKey is the key of the actor recorded in a context
+type actorKey struct{}
+
+// Actor describes who or what a request is made on behalf of: the source of
+// the request, i.e. the cli or the scheduler, and the user that made it, if
+// known.  Requests that a service makes from inside its container have no
+// user, but record the tenant of the service instead.
+type Actor struct {
+	Source string
+	User   string
+	Tenant string
+}
+
+// WithActor returns a copy of the parent context that records the actor of
+// the request, so that the changes made with it can be attributed.
+func WithActor(parent Context, source, user string) Context {
+	c := netcontext.WithValue(parent, actorKey{}, Actor{Source: source, User: user})
+	return &derivedContext{c, parent}
+}
+
+// WithTenantActor returns a copy of the parent context that records a request
+// made by a service of the given tenant.
+func WithTenantActor(parent Context, source, tenantID string) Context {
+	c := netcontext.WithValue(parent, actorKey{}, Actor{Source: source, Tenant: tenantID})
+	return &derivedContext{c, parent}
+}
+
+// GetActor returns the actor recorded in the context, or an empty actor if
+// there is none.
+func GetActor(ctx Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
 // GetNew() returns a new global context.
 // This function is not intended for production use, but is for the purpose
 // of getting fresh contexts for performance testing with metrics for troubleshooting.
@@ -50,10 +154,11 @@ var ctx Context
 
 //new Creates a new context with a Driver to a datastore
 func newCtx(driver Driver) Context {
-	return &context{driver, metrics.NewMetrics()}
+	return &context{netcontext.Background(), driver, metrics.NewMetrics()}
 }
 
 type context struct {
+	netcontext.Context
 	driver  Driver
 	metrics *metrics.Metrics
 }
@@ -65,3 +170,18 @@ func (c *context) Connection() (Connection, error) {
 func (c *context) Metrics() *metrics.Metrics {
 	return c.metrics
 }
+
+// derivedContext shares the connection and metrics of its parent, but has its
+// own cancellation.
+type derivedContext struct {
+	netcontext.Context
+	parent Context
+}
+
+func (c *derivedContext) Connection() (Connection, error) {
+	return c.parent.Connection()
+}
+
+func (c *derivedContext) Metrics() *metrics.Metrics {
+	return c.parent.Metrics()
+}
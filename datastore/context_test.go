@@ -17,6 +17,9 @@ package datastore
 
 import (
 	"testing"
+	"time"
+
+	netcontext "golang.org/x/net/context"
 )
 
 type testDriver struct{}
@@ -53,3 +56,113 @@ func TestContext(t *testing.T) {
 		t.Error("Expected connection, got nil")
 	}
 }
+
+func TestContextWithCancel(t *testing.T) {
+	parent := newCtx(&testDriver{})
+	if err := parent.Err(); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	ctx, cancel := WithCancel(parent)
+	if ctx.Metrics() != parent.Metrics() {
+		t.Error("Expected metrics of the parent context")
+	}
+	if conn, _ := ctx.Connection(); conn == nil {
+		t.Error("Expected connection, got nil")
+	}
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	cancel()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected context to be canceled")
+	}
+	if ctx.Err() == nil {
+		t.Error("Expected error, got nil")
+	}
+	if err := parent.Err(); err != nil {
+		t.Errorf("Expected parent not to be canceled, got %s", err)
+	}
+}
+
+func TestContextWithTimeout(t *testing.T) {
+	parent := newCtx(&testDriver{})
+
+	ctx, cancel := WithTimeout(parent, time.Millisecond)
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("Expected a deadline")
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected context to time out")
+	}
+	if ctx.Err() == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	ctx, cancel = WithTimeout(parent, 0)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Expected no deadline")
+	}
+}
+
+func TestContextWithActor(t *testing.T) {
+	parent := newCtx(&testDriver{})
+	if actor := GetActor(parent); actor != (Actor{}) {
+		t.Errorf("Expected no actor, got %+v", actor)
+	}
+
+	ctx := WithActor(parent, "cli", "zenoss")
+	if ctx.Metrics() != parent.Metrics() {
+		t.Error("Expected metrics of the parent context")
+	}
+	if actor := GetActor(ctx); actor != (Actor{Source: "cli", User: "zenoss"}) {
+		t.Errorf("Unexpected actor %+v", actor)
+	}
+
+	ctx, cancel := WithCancel(ctx)
+	defer cancel()
+	if actor := GetActor(ctx); actor.Source != "cli" {
+		t.Errorf("Expected actor to be inherited, got %+v", actor)
+	}
+}
+
+func TestContextWithTenantActor(t *testing.T) {
+	parent := newCtx(&testDriver{})
+	ctx := WithTenantActor(parent, "api", "tenant-a")
+	if actor := GetActor(ctx); actor != (Actor{Source: "api", Tenant: "tenant-a"}) {
+		t.Errorf("Unexpected actor %+v", actor)
+	}
+}
+
+func TestContextWithCallContext(t *testing.T) {
+	parent := newCtx(&testDriver{})
+
+	call, hangup := netcontext.WithCancel(netcontext.Background())
+	ctx, cancel := WithCallContext(parent, call)
+	defer cancel()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	hangup()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected context to be canceled with the call")
+	}
+	if err := parent.Err(); err != nil {
+		t.Errorf("Expected parent not to be canceled, got %s", err)
+	}
+
+	ctx, cancel = WithCallContext(parent, nil)
+	cancel()
+	if ctx.Err() == nil {
+		t.Error("Expected error, got nil")
+	}
+}
@@ -76,6 +76,7 @@ func (ds *DataStore) Put(ctx Context, key Key, entity ValidEntity) error {
 	if ctx == nil {
 		return ErrNilContext
 	}
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("datastore.Put"))
 	if key == nil {
 		return ErrNilKey
 	}
@@ -106,6 +107,7 @@ func (ds *DataStore) Get(ctx Context, key Key, entity ValidEntity) error {
 	if ctx == nil {
 		return ErrNilContext
 	}
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("datastore.Get"))
 	if key == nil {
 		return ErrNilKey
 	}
@@ -134,6 +136,7 @@ func (ds *DataStore) Delete(ctx Context, key Key) error {
 	if ctx == nil {
 		return ErrNilContext
 	}
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("datastore.Delete"))
 	if key == nil {
 		return ErrNilKey
 	}
@@ -19,7 +19,6 @@ import (
 	"github.com/zenoss/elastigo/core"
 	"github.com/zenoss/elastigo/indices"
 	"github.com/zenoss/elastigo/search"
-	"github.com/zenoss/glog"
 
 	"encoding/json"
 	"fmt"
@@ -33,12 +32,12 @@ type elasticConnection struct {
 func (ec *elasticConnection) Put(key datastore.Key, msg datastore.JSONMessage) error {
 	//func Index(pretty bool, index string, _type string, id string, data interface{}) (api.BaseResponse, error) {
 
-	glog.V(4).Infof("Put for {kind:%s, id:%s} %v", key.Kind(), key.ID(), string(msg.Bytes()))
+	plog.Debugf("Put for {kind:%s, id:%s} %v", key.Kind(), key.ID(), string(msg.Bytes()))
 	var raw json.RawMessage
 	raw = msg.Bytes()
 	resp, err := core.IndexWithParameters(false, ec.index, key.Kind(), key.ID(), "", msg.Version(), "", "", "", 0, "", "", false, &raw)
 	if err != nil {
-		glog.Errorf("Put err: %+v", err)
+		plog.Errorf("Put err: %+v", err)
 		if eserr, iseserror := err.(api.ESError); iseserror && eserr.Code == 409 {
 			// Conflict
 			return fmt.Errorf("Your changes conflict with those made by another user. Please reload and try your changes again.")
@@ -46,7 +45,7 @@ func (ec *elasticConnection) Put(key datastore.Key, msg datastore.JSONMessage) e
 		return err
 	}
 	indices.Refresh(ec.index)
-	glog.V(4).Infof("Put response: %v", resp)
+	plog.Debugf("Put response: %v", resp)
 	if !resp.Ok {
 		return fmt.Errorf("non OK response: %v", resp)
 	}
@@ -55,15 +54,15 @@ func (ec *elasticConnection) Put(key datastore.Key, msg datastore.JSONMessage) e
 
 func (ec *elasticConnection) Get(key datastore.Key) (datastore.JSONMessage, error) {
 	//	func Get(pretty bool, index string, _type string, id string) (api.BaseResponse, error) {
-	glog.V(4).Infof("Get for {kind:%v, id:%v}", key.Kind(), key.ID())
+	plog.Debugf("Get for {kind:%v, id:%v}", key.Kind(), key.ID())
 	//	err := core.GetSource(ec.index, key.Kind(), key.ID(), &bytes)
 	response, err := elasticGet(false, ec.index, key.Kind(), key.ID())
 	if err != nil {
-		glog.Errorf("Error is %v", err)
+		plog.Errorf("Error is %v", err)
 		return nil, err
 	}
 	if !response.Exists {
-		glog.V(4).Infof("Entity not found for {kind:%s, id:%s}", key.Kind(), key.ID())
+		plog.Debugf("Entity not found for {kind:%s, id:%s}", key.Kind(), key.ID())
 		return nil, datastore.ErrNoSuchEntity{Key: key}
 	}
 	bytes := response.Source
@@ -75,7 +74,7 @@ func (ec *elasticConnection) Delete(key datastore.Key) error {
 	//func Delete(pretty bool, index string, _type string, id string, version int, routing string) (api.BaseResponse, error) {
 	resp, err := core.Delete(false, ec.index, key.Kind(), key.ID(), 0, "")
 	indices.Refresh(ec.index)
-	glog.V(4).Infof("Delete response: %v", resp)
+	plog.Debugf("Delete response: %v", resp)
 	if err != nil {
 		return err
 	}
@@ -88,7 +87,7 @@ func (ec *elasticConnection) Query(query interface{}) ([]datastore.JSONMessage,
 		resp, err := s.Result()
 		if err != nil {
 			err = fmt.Errorf("error executing query %v", err)
-			glog.Errorf("%v", err)
+			plog.Errorf("%v", err)
 			return nil, err
 		}
 		return toJSONMessages(resp), nil
@@ -113,11 +112,11 @@ func (ec *elasticConnection) Query(query interface{}) ([]datastore.JSONMessage,
 
 // convert search result of json host to dao.Host array
 func toJSONMessages(result *core.SearchResult) []datastore.JSONMessage {
-	glog.V(4).Infof("Converting results %v", result)
+	plog.Debugf("Converting results %v", result)
 	var total = len(result.Hits.Hits)
 	var msgs = make([]datastore.JSONMessage, total)
 	for i := 0; i < total; i++ {
-		glog.V(4).Infof("Adding result %s", string(result.Hits.Hits[i].Source))
+		plog.Debugf("Adding result %s", string(result.Hits.Hits[i].Source))
 		src := result.Hits.Hits[i].Source
 		fields := result.Hits.Hits[i].Fields
 		var data []byte
@@ -18,8 +18,8 @@ package elastic_test
 import (
 	"github.com/control-center/serviced/datastore"
 	. "github.com/control-center/serviced/datastore/elastic"
+	"github.com/control-center/serviced/logging"
 	"github.com/zenoss/elastigo/search"
-	"github.com/zenoss/glog"
 	. "gopkg.in/check.v1"
 
 	"encoding/json"
@@ -27,6 +27,8 @@ import (
 	"testing"
 )
 
+var plog = logging.PackageLogger()
+
 // This plumbs gocheck into testing
 func Test(t *testing.T) {
 	TestingT(t)
@@ -68,10 +70,10 @@ func (s *S) TestPutGetDelete(t *C) {
 	if err != nil {
 		t.Fatalf("Unexpected: %v", err)
 	}
-	glog.Infof("raw is %v", string(raw.Bytes()))
+	plog.Infof("raw is %v", string(raw.Bytes()))
 	var tweetMap map[string]string
 	json.Unmarshal(raw.Bytes(), &tweetMap)
-	glog.Infof("tweet is %v", tweetMap)
+	plog.Infof("tweet is %v", tweetMap)
 
 	if tweetMap["user"] != "kimchy" {
 		t.Errorf("Expected kimchy, found %s", tweetMap["user"])
@@ -91,7 +93,7 @@ func (s *S) TestPutGetDelete(t *C) {
 	if err == nil {
 		t.Error("Expected error, not nil")
 	} else if !datastore.IsErrNoSuchEntity(err) {
-		glog.Infof("type is %s", reflect.ValueOf(err))
+		plog.Infof("type is %s", reflect.ValueOf(err))
 		t.Fatalf("Unexpected: %v", err)
 	}
 
@@ -16,7 +16,6 @@ package elastic
 import (
 	"github.com/control-center/serviced/datastore"
 	"github.com/zenoss/elastigo/api"
-	"github.com/zenoss/glog"
 
 	"bytes"
 	"encoding/json"
@@ -36,6 +35,8 @@ type ElasticDriver interface {
 	//Initialize the driver, register mappings with elasticserach. Timeout in ms to wait for elastic to be available.
 	Initialize(timeout time.Duration) error
 	GetConnection() (datastore.Connection, error)
+	// CheckHealth returns an error if the elastic cluster is unreachable or red
+	CheckHealth() error
 }
 
 // New creates a new ElasticDriver
@@ -49,6 +50,8 @@ func newDriver(host string, port uint16, index string) *elasticDriver {
 	//TODO: singleton since elastigo doesn't support multiple endpoints
 
 	driver := &elasticDriver{}
+	driver.scheme = "http"
+	driver.client = http.DefaultClient
 	driver.host = host
 	driver.port = port
 	driver.index = index
@@ -61,6 +64,8 @@ func newDriver(host string, port uint16, index string) *elasticDriver {
 var _ datastore.Driver = &elasticDriver{}
 
 type elasticDriver struct {
+	scheme   string
+	client   *http.Client
 	host     string
 	port     uint16
 	settings map[string]interface{}
@@ -81,7 +86,7 @@ func (ed *elasticDriver) Initialize(timeout time.Duration) error {
 
 	select {
 	case <-healthy:
-		glog.V(4).Infof("Got response from Elastic")
+		plog.Debugf("Got response from Elastic")
 	case <-time.After(timeout):
 		return errors.New("timed Out waiting for response from Elastic")
 	}
@@ -99,7 +104,7 @@ func (ed *elasticDriver) Initialize(timeout time.Duration) error {
 
 	select {
 	case <-healthy:
-		glog.V(4).Infof("Got response from Elastic")
+		plog.Debugf("Got response from Elastic")
 	case <-time.After(timeout):
 		return errors.New("timed Out waiting for response from Elastic")
 	}
@@ -107,6 +112,17 @@ func (ed *elasticDriver) Initialize(timeout time.Duration) error {
 	return nil
 }
 
+func (ed *elasticDriver) CheckHealth() error {
+	health, err := ed.getHealth()
+	if err != nil {
+		return err
+	}
+	if status := health["status"]; status != "green" && status != "yellow" {
+		return fmt.Errorf("elastic cluster status is %v", status)
+	}
+	return nil
+}
+
 func (ed *elasticDriver) SetProperty(name string, prop interface{}) error {
 	ed.settings[name] = prop
 	return nil
@@ -118,13 +134,13 @@ func (ed *elasticDriver) AddMapping(mapping Mapping) error {
 }
 
 func (ed *elasticDriver) AddMappingsFile(path string) error {
-	glog.Infof("AddMappingsFiles %v", path)
+	plog.Infof("AddMappingsFiles %v", path)
 
 	bytes, err := ioutil.ReadFile(path)
 	if err != nil {
 		return err
 	}
-	glog.V(4).Infof("AddMappingsFiles: content %v", string(bytes))
+	plog.Debugf("AddMappingsFiles: content %v", string(bytes))
 
 	type mapFile struct {
 		Mappings map[string]map[string]interface{}
@@ -143,7 +159,7 @@ func (ed *elasticDriver) AddMappingsFile(path string) error {
 		var rawMapping = make(map[string]map[string]interface{})
 		rawMapping[key] = mapping
 		if value, err := newMapping(rawMapping); err != nil {
-			glog.Errorf("%v; could not create mapping from: %v", err, rawMapping)
+			plog.Errorf("%v; could not create mapping from: %v", err, rawMapping)
 			return err
 		} else {
 			ed.AddMapping(value)
@@ -154,7 +170,7 @@ func (ed *elasticDriver) AddMappingsFile(path string) error {
 }
 
 func (ed *elasticDriver) elasticURL() string {
-	return fmt.Sprintf("http://%s:%d", ed.host, ed.port)
+	return fmt.Sprintf("%s://%s:%d", ed.scheme, ed.host, ed.port)
 }
 
 func (ed *elasticDriver) indexURL() string {
@@ -164,7 +180,7 @@ func (ed *elasticDriver) indexURL() string {
 func (ed *elasticDriver) isUp() bool {
 	health, err := ed.getHealth()
 	if err != nil {
-		glog.Errorf("isUp() err=%v", err)
+		plog.Errorf("isUp() err=%v", err)
 		return false
 	}
 	status := health["status"]
@@ -174,7 +190,7 @@ func (ed *elasticDriver) isUp() bool {
 func (ed *elasticDriver) getHealth() (map[string]interface{}, error) {
 	health := make(map[string]interface{})
 	healthURL := fmt.Sprintf("%v/_cluster/health", ed.elasticURL())
-	resp, err := http.Get(healthURL)
+	resp, err := ed.client.Get(healthURL)
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -187,14 +203,14 @@ func (ed *elasticDriver) getHealth() (map[string]interface{}, error) {
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		glog.Errorf("error reading elastic health: %v", err)
+		plog.Errorf("error reading elastic health: %v", err)
 		return health, err
 	}
 	if err := json.Unmarshal(body, &health); err != nil {
-		glog.Errorf("error unmarshalling elastic health: %v; err: %v", string(body), err)
+		plog.Errorf("error unmarshalling elastic health: %v; err: %v", string(body), err)
 		return health, err
 	}
-	glog.V(4).Infof("Elastic Health: %v; err: %v", string(body), err)
+	plog.Debugf("Elastic Health: %v; err: %v", string(body), err)
 	return health, nil
 
 }
@@ -207,7 +223,7 @@ func (ed *elasticDriver) checkHealth(quit chan int, healthy chan int) {
 				healthy <- 1
 				return
 			}
-			glog.Infof("Waiting for Elastic Search")
+			plog.Infof("Waiting for Elastic Search")
 			time.Sleep(1000 * time.Millisecond)
 
 		case <-quit:
@@ -221,17 +237,17 @@ func (ed *elasticDriver) postMappings() error {
 
 	post := func(typeName string, mappingBytes []byte) error {
 		mapURL := fmt.Sprintf("%s/%s/_mapping", ed.indexURL(), typeName)
-		glog.V(4).Infof("Posting mapping to %s", mapURL)
-		resp, err := http.Post(mapURL, "application/json", bytes.NewReader(mappingBytes))
+		plog.Debugf("Posting mapping to %s", mapURL)
+		resp, err := ed.client.Post(mapURL, "application/json", bytes.NewReader(mappingBytes))
 		if resp != nil {
 			defer resp.Body.Close()
 		}
 		if err != nil {
 			return fmt.Errorf("error mapping %s: %s", typeName, err)
 		}
-		glog.V(4).Infof("Response %v", resp)
+		plog.Debugf("Response %v", resp)
 		body, err := ioutil.ReadAll(resp.Body)
-		glog.V(4).Infof("Post result %s", body)
+		plog.Debugf("Post result %s", body)
 		if err != nil {
 			return err
 		}
@@ -247,7 +263,7 @@ func (ed *elasticDriver) postMappings() error {
 			return err
 		}
 
-		glog.V(4).Infof("mappping %v to  %v", mapping.Name, string(mappingBytes))
+		plog.Debugf("mappping %v to  %v", mapping.Name, string(mappingBytes))
 		err = post(mapping.Name, mappingBytes)
 		if err != nil {
 			return err
@@ -259,19 +275,19 @@ func (ed *elasticDriver) postMappings() error {
 
 func (ed *elasticDriver) deleteIndex() error {
 	url := ed.indexURL()
-	glog.Infof("Deleting Index %v", url)
+	plog.Infof("Deleting Index %v", url)
 
 	req, err := http.NewRequest("DELETE", url, nil)
 	if err != nil {
 		return err
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := ed.client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
-	glog.V(4).Infof("Delete response %s", body)
+	plog.Debugf("Delete response %s", body)
 	if err != nil {
 		return err
 	}
@@ -280,25 +296,25 @@ func (ed *elasticDriver) deleteIndex() error {
 
 func (ed *elasticDriver) postIndex() error {
 	url := ed.indexURL()
-	glog.V(4).Infof("Posting Index to %v", url)
+	plog.Debugf("Posting Index to %v", url)
 
 	config := make(map[string]interface{})
 	config["settings"] = ed.settings
 	configBytes, err := json.Marshal(config)
-	glog.V(4).Infof("Config is %v", string(configBytes))
+	plog.Debugf("Config is %v", string(configBytes))
 
 	if err != nil {
 		return err
 	}
 
-	resp, err := http.Post(url, "application/json", bytes.NewReader(configBytes))
+	resp, err := ed.client.Post(url, "application/json", bytes.NewReader(configBytes))
 	if resp != nil {
 		defer resp.Body.Close()
 	}
 	if err != nil {
 		return err
 	}
-	glog.V(4).Infof("Response %v", resp)
+	plog.Debugf("Response %v", resp)
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return err
@@ -306,13 +322,13 @@ func (ed *elasticDriver) postIndex() error {
 
 	errResponse := true
 	if resp.StatusCode == 400 {
-		glog.V(4).Info("400 response code")
+		plog.Debug("400 response code")
 		//ignore if 400 and IndexAlreadyExistsException
 		var result map[string]interface{}
 		err = json.Unmarshal(body, &result)
 		if err == nil {
 			if errString, found := result["error"]; found {
-				glog.V(4).Infof("Found error in response: '%v'", errString)
+				plog.Debugf("Found error in response: '%v'", errString)
 				switch errString.(type) {
 				case string:
 					if strings.Contains(errString.(string), "IndexAlreadyExistsException") {
@@ -325,7 +341,7 @@ func (ed *elasticDriver) postIndex() error {
 		errResponse = false
 	}
 	if errResponse {
-		glog.Errorf("Error creating index: %s", string(body))
+		plog.Errorf("Error creating index: %s", string(body))
 		return fmt.Errorf("error posting index: %v", resp.Status)
 	}
 	return nil
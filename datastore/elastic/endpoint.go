@@ -0,0 +1,136 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elastic
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/zenoss/elastigo/api"
+)
+
+// Endpoint describes how to reach an Elasticsearch cluster
+type Endpoint struct {
+	Scheme   string
+	Host     string
+	Port     uint16
+	Username string
+	Password string
+}
+
+// ParseEndpoint parses an url of the form scheme://[user:password@]host[:port].
+// The port defaults to 9200.
+func ParseEndpoint(rawurl string) (Endpoint, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return Endpoint{}, fmt.Errorf("elastic url %s must use http or https", rawurl)
+	}
+	ep := Endpoint{Scheme: u.Scheme, Host: u.Hostname(), Port: 9200}
+	if ep.Host == "" {
+		return Endpoint{}, fmt.Errorf("elastic url %s has no host", rawurl)
+	}
+	if p := u.Port(); p != "" {
+		port, err := strconv.ParseUint(p, 10, 16)
+		if err != nil {
+			return Endpoint{}, fmt.Errorf("elastic url %s has an invalid port", rawurl)
+		}
+		ep.Port = uint16(port)
+	}
+	if u.User != nil {
+		ep.Username = u.User.Username()
+		ep.Password, _ = u.User.Password()
+	}
+	return ep, nil
+}
+
+// Address returns the host:port of the endpoint
+func (ep Endpoint) Address() string {
+	return net.JoinHostPort(ep.Host, strconv.Itoa(int(ep.Port)))
+}
+
+// String returns the url of the endpoint without credentials
+func (ep Endpoint) String() string {
+	return fmt.Sprintf("%s://%s", ep.Scheme, ep.Address())
+}
+
+// NewWithEndpoint creates a new ElasticDriver for the endpoint.  elastigo
+// sends its requests through http.DefaultClient, so the transport that carries
+// the credentials and tls settings of the endpoint is installed there; requests
+// to any other host are passed on to the previous transport unchanged.
+func NewWithEndpoint(ep Endpoint, index string, tlsConfig *tls.Config) ElasticDriver {
+	driver := newDriver(ep.Host, ep.Port, index)
+	driver.scheme = ep.Scheme
+	api.Protocol = ep.Scheme
+
+	base := http.DefaultTransport
+	if tlsConfig != nil {
+		base = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}
+	}
+	fallback := http.DefaultClient.Transport
+	if t, ok := fallback.(*endpointTransport); ok {
+		// replace the transport of a previous endpoint
+		fallback = t.fallback
+	}
+	if fallback == nil {
+		fallback = http.DefaultTransport
+	}
+	transport := &endpointTransport{
+		address:  ep.Address(),
+		username: ep.Username,
+		password: ep.Password,
+		base:     base,
+		fallback: fallback,
+	}
+	driver.client = &http.Client{Transport: transport}
+	http.DefaultClient.Transport = transport
+	return driver
+}
+
+// endpointTransport sends requests for the endpoint address through the base
+// transport, adding basic authentication, and all other requests through the
+// fallback transport.
+type endpointTransport struct {
+	address  string
+	username string
+	password string
+	base     http.RoundTripper
+	fallback http.RoundTripper
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.address {
+		return t.fallback.RoundTrip(req)
+	}
+	if t.username != "" {
+		// copy the request, since a RoundTripper must not modify it
+		r := *req
+		r.Header = make(http.Header)
+		for k, v := range req.Header {
+			r.Header[k] = v
+		}
+		r.SetBasicAuth(t.username, t.password)
+		req = &r
+	}
+	return t.base.RoundTrip(req)
+}
@@ -18,8 +18,8 @@ package integration_test
 import (
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/datastore/elastic"
+	"github.com/control-center/serviced/logging"
 	"github.com/zenoss/elastigo/search"
-	"github.com/zenoss/glog"
 
 	. "gopkg.in/check.v1"
 
@@ -27,6 +27,8 @@ import (
 	"testing"
 )
 
+var plog = logging.PackageLogger()
+
 var version datastore.VersionedEntity
 
 // This plumbs gocheck into testing
@@ -64,7 +66,7 @@ func (s *S) TestPutGetDelete(t *C) {
 	if err != nil {
 		t.Fatalf("Unexpected: %v", err)
 	}
-	glog.Infof("tweet is %v", &storedtweet)
+	plog.Infof("tweet is %v", &storedtweet)
 
 	if storedtweet.User != "kimchy" {
 		t.Errorf("Expected kimchy, found %s", storedtweet.User)
@@ -81,7 +83,7 @@ func (s *S) TestPutGetDelete(t *C) {
 	if err == nil {
 		t.Error("Expected error, not nil")
 	} else if !datastore.IsErrNoSuchEntity(err) {
-		glog.Infof("type is %s", reflect.ValueOf(err))
+		plog.Infof("type is %s", reflect.ValueOf(err))
 		t.Fatalf("Unexpected: %v", err)
 	}
 }
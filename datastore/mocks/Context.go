@@ -14,6 +14,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/metrics"
 	"github.com/stretchr/testify/mock"
@@ -54,3 +56,64 @@ func (_m *Context) Metrics() *metrics.Metrics {
 	}
 	return r0
 }
+
+func (_m *Context) Deadline() (time.Time, bool) {
+	ret := _m.Called()
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+func (_m *Context) Done() <-chan struct{} {
+	ret := _m.Called()
+
+	var r0 <-chan struct{}
+	if rf, ok := ret.Get(0).(func() <-chan struct{}); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan struct{})
+		}
+	}
+
+	return r0
+}
+
+func (_m *Context) Err() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+func (_m *Context) Value(key interface{}) interface{} {
+	ret := _m.Called(key)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(interface{}) interface{}); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Get(0)
+	}
+
+	return r0
+}
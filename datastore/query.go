@@ -53,6 +53,7 @@ type query struct {
 
 func (q *query) Execute(query interface{}) (Results, error) {
 	ctx := q.ctx
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("datastore.Query"))
 	conn, err := ctx.Connection()
 	if err != nil {
 		return nil, err
@@ -23,7 +23,6 @@ import (
 
 	"github.com/control-center/serviced/commons/docker"
 	"github.com/control-center/serviced/volume"
-	"github.com/zenoss/glog"
 )
 
 const (
@@ -33,13 +32,23 @@ const (
 )
 
 // Backup writes all application data into an export stream
-func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
+func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) (err error) {
+	op := startOperation(OpBackup)
+	defer func() { op.done(err) }()
 
-	tarOut := tar.NewWriter(w)
+	tarOut := tar.NewWriter(op.countWriter(w))
+
+	// record the environment so restores can check their compatibility
+	data.DriverType = dfs.disk.DriverType()
+	if version, err := dfs.docker.Version(); err != nil {
+		plog.Warningf("Could not get the docker version for backup: %s", err)
+	} else {
+		data.DockerVersion = version
+	}
 
 	// write the backup metadata
 	if err := dfs.writeBackupMetadata(data, tarOut); err != nil {
-		glog.Errorf("Unable to write backup metadata: %s", err)
+		plog.Errorf("Unable to write backup metadata: %s", err)
 		return err
 	}
 
@@ -47,18 +56,18 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 	// download the base images
 	for _, image := range data.BaseImages {
 		if _, err := dfs.docker.FindImage(image); docker.IsImageNotFound(err) {
-			if err := dfs.docker.PullImage(image); docker.IsImageNotFound(err) {
-				glog.Warningf("Could not pull base image %s, skipping", image)
+			if err := dfs.pullDockerImage(image); docker.IsImageNotFound(err) {
+				plog.Warningf("Could not pull base image %s, skipping", image)
 				continue
 			} else if err != nil {
-				glog.Errorf("Could not pull image %s: %s", image, err)
+				plog.Errorf("Could not pull image %s: %s", image, err)
 				return err
 			}
 		} else if err != nil {
-			glog.Errorf("Could not find image %s: %s", image, err)
+			plog.Errorf("Could not find image %s: %s", image, err)
 			return err
 		}
-		glog.Infof("Prepared Docker image %s for backup", image)
+		plog.Infof("Prepared Docker image %s for backup", image)
 		images = append(images, image)
 	}
 
@@ -69,30 +78,30 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 			return err
 		}
 		// load the images from this snapshot
-		glog.Infof("Preparing images for tenant %s", info.TenantID)
+		plog.Infof("Preparing images for tenant %s", info.TenantID)
 		r, err := vol.ReadMetadata(info.Label, ImagesMetadataFile)
 		if err != nil {
-			glog.Errorf("Could not receive images metadata for tenant %s: %s", info.TenantID, err)
+			plog.Errorf("Could not receive images metadata for tenant %s: %s", info.TenantID, err)
 			return err
 		}
 		var imgs []string
 		if err := importJSON(r, &imgs); err != nil {
-			glog.Errorf("Could not interpret images metadata for tenant %s: %s", info.TenantID, err)
+			plog.Errorf("Could not interpret images metadata for tenant %s: %s", info.TenantID, err)
 			return err
 		}
 		timer := time.NewTimer(0)
 		for _, img := range imgs {
 			timer.Reset(dfs.timeout)
-			if err := dfs.reg.PullImage(timer.C, img); err != nil {
-				glog.Errorf("Could not pull image %s from registry: %s", img, err)
+			if err := dfs.pullRegistryImage(timer.C, img); err != nil {
+				plog.Errorf("Could not pull image %s from registry: %s", img, err)
 				return err
 			}
 			image, err := dfs.reg.ImagePath(img)
 			if err != nil {
-				glog.Errorf("Could not get the image path from registry %s: %s", img, err)
+				plog.Errorf("Could not get the image path from registry %s: %s", img, err)
 				return err
 			}
-			glog.Infof("Prepared Docker image %s for backup", image)
+			plog.Infof("Prepared Docker image %s for backup", image)
 			images = append(images, image)
 		}
 		timer.Stop()
@@ -102,26 +111,26 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 		if err := rewriteTar(prefix, tarOut, snapReader); err != nil {
 			// be a good citizen and clean up any running threads
 			<-errchan
-			glog.Errorf("Could not write snapshot %s to backup: %s", snapshot, err)
+			plog.Errorf("Could not write snapshot %s to backup: %s", snapshot, err)
 			return err
 		} else if err := <-errchan; err != nil {
-			glog.Errorf("Could not export snapshot %s for backup: %s", snapshot, err)
+			plog.Errorf("Could not export snapshot %s for backup: %s", snapshot, err)
 			return err
 		}
-		glog.Infof("Exported snapshot %s to backup", snapshot)
+		plog.Infof("Exported snapshot %s to backup", snapshot)
 	}
 	// dump the images from all the snapshots into the backup
 	imageReader, errchan := dfs.dockerSavePipe(images...)
 	if err := rewriteTar(DockerImagesFile, tarOut, imageReader); err != nil {
 		// be a good citizen and clean up any running threads
 		<-errchan
-		glog.Errorf("Could not write images %v to backup: %s", images, err)
+		plog.Errorf("Could not write images %v to backup: %s", images, err)
 		return err
 	} else if err := <-errchan; err != nil {
-		glog.Errorf("Could not export images %v for backup: %s", images, err)
+		plog.Errorf("Could not export images %v for backup: %s", images, err)
 		return err
 	}
-	glog.Infof("Exported images to backup")
+	plog.Infof("Exported images to backup")
 	tarOut.Close()
 	return nil
 }
@@ -148,7 +157,7 @@ func (dfs *DistributedFilesystem) dockerSavePipe(images ...string) (*io.PipeRead
 // snapshotSavePipe returns a pipe that exports a given volume to the pipe's stdout
 func (dfs *DistributedFilesystem) snapshotSavePipe(vol volume.Volume, label string, excludes []string) (*io.PipeReader, <-chan error) {
 	return savePipe(func(w io.Writer) error {
-		return vol.Export(label, "", w, excludes)
+		return vol.Export(label, "", volume.NewLimitedWriter(w, dfs.backupIOLimit), excludes)
 	})
 }
 
@@ -183,17 +192,17 @@ func (dfs *DistributedFilesystem) writeBackupMetadata(data BackupInfo, w *tar.Wr
 		jsonData []byte
 		err      error
 	)
-	glog.V(2).Infof("Writing backup metadata")
+	plog.Debugf("Writing backup metadata")
 	if jsonData, err = json.Marshal(data); err != nil {
 		return err
 	}
 	header := &tar.Header{Name: BackupMetadataFile, Size: int64(len(jsonData))}
 	if err := w.WriteHeader(header); err != nil {
-		glog.V(2).Infof("Could not create metadata header for backup: %s", err)
+		plog.Debugf("Could not create metadata header for backup: %s", err)
 		return err
 	}
 	if _, err := w.Write(jsonData); err != nil {
-		glog.V(2).Infof("Could not write backup metadata: %s", err)
+		plog.Debugf("Could not write backup metadata: %s", err)
 		return err
 	}
 	return nil
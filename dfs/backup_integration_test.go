@@ -93,6 +93,7 @@ func (s *DFSTestSuite) BenchmarkBackup(c *C) {
 		vol.On("ReadMetadata", SnapshotLabel, ImagesMetadataFile).Return(&NopCloser{imagesbuf}, nil)
 		c.Logf("ReadMetadata should return %#v", imagesbuf)
 		s.registry.On("PullImage", mock.AnythingOfType("<-chan time.Time"), "TENANT/repo:tag").Return(nil)
+		s.docker.On("Version").Return("1.12.6", nil)
 		s.docker.On("PullImage", "library/repo:tag").Return(nil)
 		s.docker.On("FindImage", "library/repo:tag").Return(&dockerclient.Image{}, dockerclient.ErrNoSuchImage)
 		s.registry.On("ImagePath", "TENANT/repo:tag").Return("testserver:5000/TENANT/repo:tag", nil)
@@ -40,6 +40,7 @@ func (s *DFSTestSuite) TestBackup_DelayOnExportError(c *C) {
 		Snapshots: []string{"BASE_LABEL"},
 		Timestamp: time.Now().UTC(),
 	}
+	s.docker.On("Version").Return("1.12.6", nil)
 	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
 	info := &volume.SnapshotInfo{
 		Name:     "BASE_LABEL",
@@ -79,6 +80,7 @@ func (s *DFSTestSuite) TestBackup_ImageNotFound(c *C) {
 		Snapshots: []string{"testtenant_testlabel"},
 		Timestamp: time.Now().UTC(),
 	}
+	s.docker.On("Version").Return("1.12.6", nil)
 	s.docker.On("FindImage", "library/repo:tag").Return(&dockerclient.Image{}, ErrTestImageNotFound).Once()
 	err := s.dfs.Backup(backupInfo, buf)
 	c.Assert(err, Equals, ErrTestImageNotFound)
@@ -102,6 +104,7 @@ func (s *DFSTestSuite) TestBackup_SkipTemplateImage(c *C) {
 		Snapshots: []string{"BASE_LABEL"},
 		Timestamp: time.Now().UTC(),
 	}
+	s.docker.On("Version").Return("1.12.6", nil)
 	s.docker.On("FindImage", "library/repo:tag").Return(&dockerclient.Image{}, dockerclient.ErrNoSuchImage).Once()
 	s.docker.On("PullImage", "library/repo:tag").Return(dockerclient.ErrNoSuchImage)
 	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
@@ -155,6 +158,7 @@ func (s *DFSTestSuite) TestBackup(c *C) {
 		Snapshots: []string{"BASE_LABEL"},
 		Timestamp: time.Now().UTC(),
 	}
+	s.docker.On("Version").Return("1.12.6", nil)
 	s.docker.On("FindImage", "library/repo:tag").Return(&dockerclient.Image{}, dockerclient.ErrNoSuchImage).Once()
 	s.docker.On("PullImage", "library/repo:tag").Return(nil)
 	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
@@ -193,4 +197,8 @@ func (s *DFSTestSuite) TestBackup(c *C) {
 	err = s.dfs.Backup(backupInfo, buf)
 	c.Assert(err, IsNil)
 	c.Assert(buf.Len() > 0, Equals, true)
+	metadata, err := s.dfs.BackupInfo(buf)
+	c.Assert(err, IsNil)
+	c.Assert(metadata.DockerVersion, Equals, "1.12.6")
+	c.Assert(metadata.DriverType, Equals, s.disk.DriverType())
 }
@@ -18,8 +18,6 @@ import (
 	"encoding/json"
 	"io"
 	"os/exec"
-
-	"github.com/zenoss/glog"
 )
 
 // BackupInfo provides metadata info about the contents of a backup
@@ -30,13 +28,13 @@ func (dfs *DistributedFilesystem) BackupInfo(r io.Reader) (*BackupInfo, error) {
 		if err == io.EOF {
 			return nil, ErrRestoreNoInfo
 		} else if err != nil {
-			glog.Errorf("Could not read backup: %s", err)
+			plog.Errorf("Could not read backup: %s", err)
 			return nil, err
 		}
 		if header.Name == BackupMetadataFile {
 			var data BackupInfo
 			if err := json.NewDecoder(tarfile).Decode(&data); err != nil {
-				glog.Errorf("Could not load backup metadata: %s", err)
+				plog.Errorf("Could not load backup metadata: %s", err)
 				return nil, err
 			}
 			return &data, nil
@@ -0,0 +1,150 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// CurrentBackupVersion is the version of the backup format written by this
+// binary
+const CurrentBackupVersion = 1
+
+// Features of a backup that a restore may bring back
+const (
+	FeatureTemplates          = "templates"
+	FeaturePools              = "resource-pools"
+	FeatureSnapshots          = "snapshots"
+	FeatureImages             = "docker-images"
+	FeatureSnapshotExcludes   = "snapshot-excludes"
+	FeatureDockerVersionCheck = "docker-version-check"
+	FeatureDriverTypeCheck    = "driver-type-check"
+)
+
+// backupAdapter reads a particular version of the backup format
+type backupAdapter struct {
+	format   string                                        // describes the layout of the backup
+	features []string                                      // what the format can carry
+	restore  func(*DistributedFilesystem, io.Reader) error // imports the snapshots and images
+}
+
+// backupAdapters are the backup versions this binary can restore, by version
+var backupAdapters = map[int]backupAdapter{
+	0: {
+		format:   "pre-1.1.3 tar of snapshot tarballs and a docker image tarball",
+		features: []string{FeatureTemplates, FeaturePools, FeatureSnapshots, FeatureImages},
+		restore:  (*DistributedFilesystem).restoreV0,
+	},
+	1: {
+		format:   "single tar streaming snapshot volumes and docker images",
+		features: []string{FeatureTemplates, FeaturePools, FeatureSnapshots, FeatureImages, FeatureSnapshotExcludes, FeatureDockerVersionCheck, FeatureDriverTypeCheck},
+		restore:  (*DistributedFilesystem).restoreV1,
+	},
+}
+
+// BackupFeature describes whether a part of a backup can be restored
+type BackupFeature struct {
+	Name       string
+	Restorable bool
+	Note       string
+}
+
+// BackupInspection reports the version of a backup and what this binary can
+// restore from it
+type BackupInspection struct {
+	BackupVersion  int
+	CurrentVersion int
+	Supported      bool
+	Format         string
+	Timestamp      time.Time
+	Features       []BackupFeature
+}
+
+// SupportedBackupVersions returns the backup versions this binary can restore
+func SupportedBackupVersions() []int {
+	versions := make([]int, 0, len(backupAdapters))
+	for version := range backupAdapters {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// InspectBackup reports what this binary can restore from a backup, given its
+// metadata.
+func InspectBackup(info BackupInfo) *BackupInspection {
+	inspection := &BackupInspection{
+		BackupVersion:  info.BackupVersion,
+		CurrentVersion: CurrentBackupVersion,
+		Timestamp:      info.Timestamp,
+	}
+	adapter, ok := backupAdapters[info.BackupVersion]
+	if !ok {
+		inspection.Format = "unknown"
+		for _, name := range backupAdapters[CurrentBackupVersion].features {
+			inspection.Features = append(inspection.Features, BackupFeature{
+				Name: name,
+				Note: fmt.Sprintf("backup version %d is not supported", info.BackupVersion),
+			})
+		}
+		return inspection
+	}
+	inspection.Supported = true
+	inspection.Format = adapter.format
+
+	carried := make(map[string]bool)
+	for _, name := range adapter.features {
+		carried[name] = true
+	}
+	for _, name := range backupAdapters[CurrentBackupVersion].features {
+		feature := BackupFeature{Name: name}
+		if !carried[name] {
+			feature.Note = fmt.Sprintf("not in backup version %d", info.BackupVersion)
+			inspection.Features = append(inspection.Features, feature)
+			continue
+		}
+		feature.Restorable = true
+		switch name {
+		case FeatureTemplates:
+			feature.Note = fmt.Sprintf("%d templates", len(info.Templates))
+		case FeaturePools:
+			feature.Note = fmt.Sprintf("%d resource pools", len(info.Pools))
+		case FeatureSnapshots:
+			feature.Note = fmt.Sprintf("%d snapshots", len(info.Snapshots))
+		case FeatureImages:
+			feature.Note = fmt.Sprintf("%d base images", len(info.BaseImages))
+		case FeatureSnapshotExcludes:
+			if info.SnapshotExcludes == nil {
+				feature.Restorable, feature.Note = false, "not recorded"
+			}
+		case FeatureDockerVersionCheck:
+			if info.DockerVersion == "" {
+				feature.Restorable, feature.Note = false, "not recorded; the check is skipped"
+			} else {
+				feature.Note = "saved by docker " + info.DockerVersion
+			}
+		case FeatureDriverTypeCheck:
+			if info.DriverType == "" {
+				feature.Restorable, feature.Note = false, "not recorded; the check is skipped"
+			} else {
+				feature.Note = "exported by the " + string(info.DriverType) + " driver"
+			}
+		}
+		inspection.Features = append(inspection.Features, feature)
+	}
+	return inspection
+}
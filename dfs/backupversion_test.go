@@ -0,0 +1,93 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	. "gopkg.in/check.v1"
+)
+
+func (s *DFSTestSuite) TestSupportedBackupVersions(c *C) {
+	versions := SupportedBackupVersions()
+	c.Assert(versions, DeepEquals, []int{0, 1})
+	c.Assert(versions[len(versions)-1], Equals, CurrentBackupVersion)
+}
+
+func (s *DFSTestSuite) TestInspectBackup_Current(c *C) {
+	timestamp := time.Date(2017, 5, 1, 12, 0, 0, 0, time.UTC)
+	info := BackupInfo{
+		Templates:        []servicetemplate.ServiceTemplate{{ID: "tmpl"}},
+		BaseImages:       []string{"zenoss/core:latest", "zenoss/hbase:latest"},
+		Snapshots:        []string{"tenant_label"},
+		SnapshotExcludes: map[string][]string{"tenant_label": {"cache"}},
+		Timestamp:        timestamp,
+		BackupVersion:    CurrentBackupVersion,
+		DockerVersion:    "1.12.6",
+	}
+	inspection := InspectBackup(info)
+	c.Assert(inspection.Supported, Equals, true)
+	c.Assert(inspection.BackupVersion, Equals, CurrentBackupVersion)
+	c.Assert(inspection.Timestamp, Equals, timestamp)
+	c.Assert(inspection.Features, DeepEquals, []BackupFeature{
+		{Name: FeatureTemplates, Restorable: true, Note: "1 templates"},
+		{Name: FeaturePools, Restorable: true, Note: "0 resource pools"},
+		{Name: FeatureSnapshots, Restorable: true, Note: "1 snapshots"},
+		{Name: FeatureImages, Restorable: true, Note: "2 base images"},
+		{Name: FeatureSnapshotExcludes, Restorable: true},
+		{Name: FeatureDockerVersionCheck, Restorable: true, Note: "saved by docker 1.12.6"},
+		{Name: FeatureDriverTypeCheck, Restorable: false, Note: "not recorded; the check is skipped"},
+	})
+}
+
+func (s *DFSTestSuite) TestInspectBackup_Version0(c *C) {
+	inspection := InspectBackup(BackupInfo{BackupVersion: 0})
+	c.Assert(inspection.Supported, Equals, true)
+	restorable := make(map[string]bool)
+	for _, feature := range inspection.Features {
+		restorable[feature.Name] = feature.Restorable
+		if !feature.Restorable {
+			c.Assert(feature.Note, Equals, "not in backup version 0")
+		}
+	}
+	c.Assert(restorable, DeepEquals, map[string]bool{
+		FeatureTemplates:          true,
+		FeaturePools:              true,
+		FeatureSnapshots:          true,
+		FeatureImages:             true,
+		FeatureSnapshotExcludes:   false,
+		FeatureDockerVersionCheck: false,
+		FeatureDriverTypeCheck:    false,
+	})
+}
+
+func (s *DFSTestSuite) TestInspectBackup_Unsupported(c *C) {
+	inspection := InspectBackup(BackupInfo{BackupVersion: CurrentBackupVersion + 1})
+	c.Assert(inspection.Supported, Equals, false)
+	c.Assert(inspection.Format, Equals, "unknown")
+	for _, feature := range inspection.Features {
+		c.Assert(feature.Restorable, Equals, false)
+	}
+}
+
+func (s *DFSTestSuite) TestCheckRestore_UnsupportedVersion(c *C) {
+	err := s.dfs.CheckRestore(BackupInfo{BackupVersion: -1}, RestoreOptions{})
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "this system restores versions [0 1]"), Equals, true)
+}
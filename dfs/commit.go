@@ -18,7 +18,6 @@ import (
 
 	"github.com/control-center/serviced/dfs/docker"
 	"github.com/control-center/serviced/domain/registry"
-	"github.com/zenoss/glog"
 
 	dockerclient "github.com/fsouza/go-dockerclient"
 )
@@ -30,20 +29,34 @@ var (
 
 // Commit commits a container spawned from the latest docker registry image
 // and updates the registry.  Returns the affected registry image.
-func (dfs *DistributedFilesystem) Commit(ctrID string) (string, error) {
+func (dfs *DistributedFilesystem) Commit(ctrID string) (tenantID string, err error) {
+	return dfs.commit(ctrID, false)
+}
+
+// CommitInstance commits the container of a service instance, which may still
+// be running, and updates the registry.  Docker pauses a running container
+// while it is committed.  Returns the affected registry image.
+func (dfs *DistributedFilesystem) CommitInstance(ctrID string) (tenantID string, err error) {
+	return dfs.commit(ctrID, true)
+}
+
+func (dfs *DistributedFilesystem) commit(ctrID string, allowRunning bool) (tenantID string, err error) {
+	op := startOperation(OpCommit)
+	defer func() { op.done(err) }()
+
 	ctr, err := dfs.docker.FindContainer(ctrID)
 	if err != nil {
-		glog.Errorf("Could not find container %s: %s", ctrID, err)
+		plog.Errorf("Could not find container %s: %s", ctrID, err)
 		return "", err
 	}
 	// do not commit if the container is running
-	if ctr.State.Running {
+	if ctr.State.Running && !allowRunning {
 		return "", ErrRunningContainer
 	}
 	// check if the container is stale (ctr.Config.Image is the repo:tag)
 	rImage, err := dfs.index.FindImage(ctr.Config.Image)
 	if err != nil {
-		glog.Errorf("Could not find image %s in registry for container %s: %s", ctr.Config.Image, ctr.ID, err)
+		plog.Errorf("Could not find image %s in registry for container %s: %s", ctr.Config.Image, ctr.ID, err)
 		return "", err
 	}
 	// verify that we are committing to latest
@@ -53,18 +66,18 @@ func (dfs *DistributedFilesystem) Commit(ctrID string) (string, error) {
 	// commit the container
 	img, err := dfs.docker.CommitContainer(ctr.ID, ctr.Config.Image)
 	if err != nil {
-		glog.Errorf("Could not commit container %s: %s", ctr.ID, err)
+		plog.Errorf("Could not commit container %s: %s", ctr.ID, err)
 		return "", err
 	}
 	// push the image into the registry
 	hash, err := dfs.docker.GetImageHash(img.ID)
 	if err != nil {
-		glog.Errorf("Could not get has for image %s: %s", img.ID, err)
+		plog.Errorf("Could not get has for image %s: %s", img.ID, err)
 		return "", err
 	}
 
-	if err := dfs.index.PushImage(rImage.String(), img.ID, hash); err != nil {
-		glog.Errorf("Could not push image %s (%s): %s", rImage, img.ID, err)
+	if err := dfs.pushImage(rImage.String(), img.ID, hash, imageArchs(img)); err != nil {
+		plog.Errorf("Could not push image %s (%s): %s", rImage, img.ID, err)
 		return "", err
 	}
 	return rImage.Library, nil
@@ -78,12 +91,12 @@ func (dfs *DistributedFilesystem) imagesIdentical(rImage *registry.Image, ctr *d
 
 	// If IDs do not match, the we have to compare image hashes
 	if ctrHash, err := dfs.docker.GetImageHash(ctr.Image); err == nil {
-		glog.V(2).Infof("For image %s, comparing hash (%s) to master's hash (%s)", ctr.ID, ctrHash, rImage.Hash)
+		plog.Debugf("For image %s, comparing hash (%s) to master's hash (%s)", ctr.ID, ctrHash, rImage.Hash)
 		if ctrHash == rImage.Hash {
 			return true
 		}
 	} else {
-		glog.Warningf("Error building hash of container %s (image %s): %s", ctr.ID, ctr.Image, err)
+		plog.Warningf("Error building hash of container %s (image %s): %s", ctr.ID, ctr.Image, err)
 	}
 	return false
 }
@@ -162,8 +162,8 @@ func (s *DFSTestSuite) TestCommit_NoPush(c *C) {
 	s.index.On("FindImage", "localhost:5000/libraryname/reponame:latest").Return(rImg, nil)
 	s.docker.On("CommitContainer", "testcontainer", "localhost:5000/libraryname/reponame:latest").Return(img, nil)
 	s.docker.On("GetImageHash", img.ID).Return("hashvalue", nil)
-	s.index.On("PushImage", "localhost:5000/libraryname/reponame:latest", "testimage2", "hashvalue").Return(ErrTestNoPush)
-	s.index.On("PushImage", "libraryname/reponame:latest", "testimage2", "hashvalue").Return(ErrTestNoPush)
+	s.index.On("PushImage", "localhost:5000/libraryname/reponame:latest", "testimage2", "hashvalue", []string(nil)).Return(ErrTestNoPush)
+	s.index.On("PushImage", "libraryname/reponame:latest", "testimage2", "hashvalue", []string(nil)).Return(ErrTestNoPush)
 	tenantID, err := s.dfs.Commit("testcontainer")
 	c.Assert(tenantID, Equals, "")
 	c.Assert(err, Equals, ErrTestNoPush)
@@ -222,9 +222,27 @@ func (s *DFSTestSuite) TestCommit_Success(c *C) {
 	s.index.On("FindImage", "localhost:5000/libraryname/reponame:latest").Return(rImg, nil)
 	s.docker.On("CommitContainer", "testcontainer", "localhost:5000/libraryname/reponame:latest").Return(img, nil)
 	s.docker.On("GetImageHash", img.ID).Return("hashvalue", nil)
-	s.index.On("PushImage", "localhost:5000/libraryname/reponame:latest", "testimage2", "hashvalue").Return(nil)
-	s.index.On("PushImage", "libraryname/reponame:latest", "testimage2", "hashvalue").Return(nil)
+	s.index.On("PushImage", "localhost:5000/libraryname/reponame:latest", "testimage2", "hashvalue", []string(nil)).Return(nil)
+	s.index.On("PushImage", "libraryname/reponame:latest", "testimage2", "hashvalue", []string(nil)).Return(nil)
 	tenantID, err := s.dfs.Commit("testcontainer")
 	c.Assert(tenantID, Equals, "libraryname")
 	c.Assert(err, IsNil)
 }
+
+func (s *DFSTestSuite) TestCommitInstance_Running(c *C) {
+	ctr := &dockerclient.Container{
+		ID:    "testcontainer",
+		Image: "testimage",
+		Config: &dockerclient.Config{
+			Image: "localhost:5000/libraryname/reponame:tagname",
+		},
+		State: dockerclient.State{
+			Running: true,
+		},
+	}
+	s.docker.On("FindContainer", "testcontainer").Return(ctr, nil)
+	s.index.On("FindImage", "localhost:5000/libraryname/reponame:tagname").Return(nil, ErrTestImageNotFound)
+	tenantID, err := s.dfs.CommitInstance("testcontainer")
+	c.Assert(tenantID, Equals, "")
+	c.Assert(err, Equals, ErrTestImageNotFound)
+}
@@ -0,0 +1,50 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/domain/registry"
+)
+
+// ErrSameImage is returned when an image would be copied onto itself
+var ErrSameImage = errors.New("source and destination images are the same")
+
+// CopyImage adds an image in the docker registry under the library of another
+// tenant and updates the registry.  If tag is not set, the tag of the source
+// image is used.  Returns the name of the new image in the registry.
+func (dfs *DistributedFilesystem) CopyImage(image, tenantID, tag string) (string, error) {
+	srcImage, err := dfs.index.FindImage(image)
+	if err != nil {
+		plog.Errorf("Could not find image %s in registry: %s", image, err)
+		return "", err
+	}
+	if tag == "" {
+		tag = srcImage.Tag
+	}
+	dstImage := (&registry.Image{
+		Library: tenantID,
+		Repo:    srcImage.Repo,
+		Tag:     tag,
+	}).String()
+	if dstImage == srcImage.String() {
+		return "", ErrSameImage
+	}
+	if err := dfs.pushImage(dstImage, srcImage.UUID, srcImage.Hash, srcImage.Archs); err != nil {
+		plog.Errorf("Could not copy image %s (%s) to %s: %s", srcImage, srcImage.UUID, dstImage, err)
+		return "", err
+	}
+	return dstImage, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	. "github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/registry"
+	. "gopkg.in/check.v1"
+)
+
+var stagingImage = registry.Image{Library: "staging", Repo: "testrepo", Tag: "latest", UUID: "stagingimageid", Hash: "stagingimagehash"}
+
+func (s *DFSTestSuite) TestCopyImage_NotFound(c *C) {
+	s.index.On("FindImage", "staging/testrepo").Return(nil, ErrTestImageNotInRegistry)
+	image, err := s.dfs.CopyImage("staging/testrepo", "production", "")
+	c.Assert(err, Equals, ErrTestImageNotInRegistry)
+	c.Assert(image, Equals, "")
+}
+
+func (s *DFSTestSuite) TestCopyImage_SameImage(c *C) {
+	s.index.On("FindImage", "staging/testrepo").Return(&stagingImage, nil)
+	_, err := s.dfs.CopyImage("staging/testrepo", "staging", "latest")
+	c.Assert(err, Equals, ErrSameImage)
+}
+
+func (s *DFSTestSuite) TestCopyImage_ErrOnPush(c *C) {
+	s.index.On("FindImage", "staging/testrepo").Return(&stagingImage, nil)
+	s.index.On("PushImage", "production/testrepo:latest", stagingImage.UUID, stagingImage.Hash, []string(nil)).Return(ErrTestNoPush)
+	_, err := s.dfs.CopyImage("staging/testrepo", "production", "")
+	c.Assert(err, Equals, ErrTestNoPush)
+}
+
+func (s *DFSTestSuite) TestCopyImage_Success(c *C) {
+	s.index.On("FindImage", "staging/testrepo").Return(&stagingImage, nil)
+	s.index.On("PushImage", "production/testrepo:latest", stagingImage.UUID, stagingImage.Hash, []string(nil)).Return(nil)
+	s.index.On("PushImage", "staging/testrepo:tested", stagingImage.UUID, stagingImage.Hash, []string(nil)).Return(nil)
+
+	image, err := s.dfs.CopyImage("staging/testrepo", "production", "")
+	c.Assert(err, IsNil)
+	c.Assert(image, Equals, "production/testrepo:latest")
+
+	image, err = s.dfs.CopyImage("staging/testrepo", "staging", "tested")
+	c.Assert(err, IsNil)
+	c.Assert(image, Equals, "staging/testrepo:tested")
+}
@@ -13,19 +13,17 @@
 
 package dfs
 
-import "github.com/zenoss/glog"
-
 // Create initializes an application volume on the dfs
 func (dfs *DistributedFilesystem) Create(tenantID string) error {
-	glog.V(1).Infof("Creating volume for %s", tenantID)
+	plog.Debugf("Creating volume for %s", tenantID)
 	vol, err := dfs.disk.Create(tenantID)
 	if err != nil {
-		glog.Errorf("Could not create volume for tenant %s: %s", tenantID, err)
+		plog.Errorf("Could not create volume for tenant %s: %s", tenantID, err)
 		return err
 	}
-	glog.V(1).Infof("Volume created for %s at %s", tenantID, vol.Path())
+	plog.Debugf("Volume created for %s at %s", tenantID, vol.Path())
 	if err := dfs.export(vol.Path()); err != nil {
-		glog.Errorf("Could not export volume at %s: %s", vol.Path(), err)
+		plog.Errorf("Could not export volume at %s: %s", vol.Path(), err)
 		return err
 	}
 	return nil
@@ -34,10 +32,10 @@ func (dfs *DistributedFilesystem) Create(tenantID string) error {
 // export exports a volume over a network file share
 func (dfs *DistributedFilesystem) export(path string) error {
 	if err := dfs.net.AddVolume(path); err != nil {
-		glog.Errorf("Error notifying storage of new volume %s: %s", path, err)
+		plog.Errorf("Error notifying storage of new volume %s: %s", path, err)
 		return err
 	} else if err := dfs.net.Sync(); err != nil {
-		glog.Errorf("Error syncing storage for volume %s: %s", path, err)
+		plog.Errorf("Error syncing storage for volume %s: %s", path, err)
 		return err
 	}
 	return nil
@@ -14,7 +14,6 @@
 package dfs
 
 import "github.com/control-center/serviced/volume"
-import "github.com/zenoss/glog"
 
 // Delete removes application data of a particular snapshot from the dfs and
 // registry.
@@ -33,7 +32,7 @@ func (dfs *DistributedFilesystem) Delete(snapshotID string) error {
 	}
 
 	if err := vol.RemoveSnapshot(snapshotID); err != nil {
-		glog.Errorf("Could not delete snapshot %s: %s", snapshotID, err)
+		plog.Errorf("Could not delete snapshot %s: %s", snapshotID, err)
 		return err
 	}
 
@@ -43,12 +42,12 @@ func (dfs *DistributedFilesystem) Delete(snapshotID string) error {
 func (dfs *DistributedFilesystem) deleteImages(tenantID, label string) error {
 	rImages, err := dfs.index.SearchLibraryByTag(tenantID, label)
 	if err != nil {
-		glog.Errorf("Could not search registry images for %s under label %s: %s", tenantID, label, err)
+		plog.Errorf("Could not search registry images for %s under label %s: %s", tenantID, label, err)
 		return err
 	}
 	for _, image := range rImages {
 		if err := dfs.index.RemoveImage(image.String()); err != nil {
-			glog.Errorf("Could not remove image %s for %s under label %s: %s", image.String(), tenantID, label, err)
+			plog.Errorf("Could not remove image %s for %s under label %s: %s", image.String(), tenantID, label, err)
 			return err
 		}
 	}
@@ -15,38 +15,37 @@ package dfs
 
 import (
 	"github.com/control-center/serviced/dfs/docker"
-	"github.com/zenoss/glog"
 )
 
 // Destroy destroys all application data from the dfs and docker registry
 func (dfs *DistributedFilesystem) Destroy(tenantID string) error {
 	vol, err := dfs.disk.Get(tenantID)
 	if err != nil {
-		glog.Errorf("Error destroying DFS:  Could not get volume for tenant %s: %s", tenantID, err)
+		plog.Errorf("Error destroying DFS:  Could not get volume for tenant %s: %s", tenantID, err)
 	} else {
 		// Remove all the stuff we need a volume object for
 		snapshots, err := vol.Snapshots()
 		if err != nil {
-			glog.Errorf("Could not get snapshots for tenant %s: %s", tenantID, err)
+			plog.Errorf("Could not get snapshots for tenant %s: %s", tenantID, err)
 		} else {
 			for _, snapshot := range snapshots {
 				if err := dfs.Delete(snapshot); err != nil {
-					glog.Errorf("Could not remove snapshot %s for tenant %s: %s", snapshot, tenantID, err)
+					plog.Errorf("Could not remove snapshot %s for tenant %s: %s", snapshot, tenantID, err)
 				}
 			}
 		}
 
 		if err := dfs.unexport(vol.Path()); err != nil {
-			glog.Errorf("Could not unexport path %s: %s", vol.Path(), err)
+			plog.Errorf("Could not unexport path %s: %s", vol.Path(), err)
 		}
 	}
 
 	if err := dfs.deleteImages(tenantID, docker.Latest); err != nil {
-		glog.Errorf("Could not delete images for tenant %s: %s", tenantID, err)
+		plog.Errorf("Could not delete images for tenant %s: %s", tenantID, err)
 	}
 
 	if err := dfs.disk.Remove(tenantID); err != nil {
-		glog.Errorf("Could not remove application data for tenant %s: %s", tenantID, err)
+		plog.Errorf("Could not remove application data for tenant %s: %s", tenantID, err)
 		return err
 	}
 
@@ -56,13 +55,13 @@ func (dfs *DistributedFilesystem) Destroy(tenantID string) error {
 // unexport removes an exported path from the network file share
 func (dfs *DistributedFilesystem) unexport(path string) error {
 	if err := dfs.net.RemoveVolume(path); err != nil {
-		glog.Errorf("Could not unexport volume %s: %s", path, err)
+		plog.Errorf("Could not unexport volume %s: %s", path, err)
 		return err
 	} else if err := dfs.net.Stop(); err != nil {
-		glog.Errorf("Could not stop nfs server: %s", err)
+		plog.Errorf("Could not stop nfs server: %s", err)
 		return err
 	} else if err := dfs.net.Restart(); err != nil {
-		glog.Errorf("Could not restart nfs server: %s", err)
+		plog.Errorf("Could not restart nfs server: %s", err)
 		return err
 	}
 	return nil
@@ -15,6 +15,7 @@ package dfs
 
 import (
 	"io"
+	"sync"
 	"time"
 
 	csync "github.com/control-center/serviced/commons/sync"
@@ -23,6 +24,7 @@ import (
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
 	"github.com/control-center/serviced/volume"
 )
@@ -40,6 +42,8 @@ type DFS interface {
 	Download(image, tenantID string, upgrade bool) (registry string, err error)
 	// Commit uploads a new image into the registry
 	Commit(ctrID string) (tenantID string, err error)
+	// CommitInstance uploads a new image from a running service instance
+	CommitInstance(ctrID string) (tenantID string, err error)
 	// Snapshot captures application data at a specific point in time
 	Snapshot(info SnapshotInfo, SnapshotSpacePercent int) (string, error)
 	// Rollback reverts application to a specific snapshot
@@ -52,10 +56,20 @@ type DFS interface {
 	Info(snapshotID string) (*SnapshotInfo, error)
 	// Backup saves and exports the current state of the system
 	Backup(info BackupInfo, w io.Writer) error
+	// CheckRestore verifies that a backup can be restored onto this system
+	CheckRestore(info BackupInfo, opts RestoreOptions) error
 	// Restore restores the system to the state of the backup
 	Restore(r io.Reader, version int) error
+	// RestoreQuarantine lists the data imported by restores that did not
+	// complete
+	RestoreQuarantine() (*RestoreQuarantine, error)
+	// CleanupFailedRestore removes the data imported by restores that did not
+	// complete
+	CleanupFailedRestore() (*RestoreQuarantine, error)
 	// BackupInfo provides detailed info for a particular backup
 	BackupInfo(r io.Reader) (*BackupInfo, error)
+	// EstimateBackup estimates the size and duration of a backup
+	EstimateBackup(req BackupEstimateRequest) (*BackupEstimate, error)
 	// Tag adds a tag to an existing snapshot
 	Tag(snapshotID string, tagName string) error
 	// Untag removes a tag from an existing snapshot
@@ -67,6 +81,10 @@ type DFS interface {
 	UpgradeRegistry(svcs []service.Service, tenantID, registryHost string, override bool) error
 	// Override replaces an image in the registry with a new image
 	Override(newImage, oldImage string) error
+	// CopyImage adds an image in the registry under another tenant's library
+	CopyImage(image, tenantID, tag string) (string, error)
+	// Seed copies the volume data of a snapshot into another tenant's volume
+	Seed(snapshotID, tenantID string) error
 }
 
 var _ = DFS(&DistributedFilesystem{})
@@ -80,13 +98,33 @@ type BackupInfo struct {
 	SnapshotExcludes map[string][]string
 	Timestamp        time.Time
 	BackupVersion    int
+	DockerVersion    string            // version of docker that saved the images
+	DriverType       volume.DriverType // volume driver that exported the snapshots
 }
 
 // SnapshotInfo provides meta info about a snapshot
 type SnapshotInfo struct {
 	*volume.SnapshotInfo
-	Images   []string
-	Services []service.Service
+	Images     []string
+	Services   []service.Service
+	Provenance SnapshotProvenance
+}
+
+// Sources of a snapshot
+const (
+	SnapshotSourceUser      = "user"      // requested directly by a user
+	SnapshotSourceShell     = "shell"     // a shell command committed on success
+	SnapshotSourceCommit    = "commit"    // a container was committed
+	SnapshotSourceScheduled = "scheduled" // taken by serviced before a backup or a change
+)
+
+// SnapshotProvenance describes who and what created a snapshot.  Snapshots
+// taken before provenance was recorded have an empty provenance.
+type SnapshotProvenance struct {
+	User     string                            // user that triggered the snapshot
+	Source   string                            // one of the SnapshotSource* values
+	ImageIDs []string                          // ids of the service images that were snapshotted
+	Groups   []servicedefinition.SnapshotGroup // consistency groups that were quiesced together
 }
 
 // DistributedFilesystem manages disk and registry data for all system
@@ -102,6 +140,13 @@ type DistributedFilesystem struct {
 	timeout time.Duration
 	locker  *csync.TimedMutex
 	tmp     string // tmp directory where backups are temporarily spooled
+
+	backupIOLimit  int64 // bytes per second exported from volumes; 0 for no limit
+	restoreIOLimit int64 // bytes per second imported into volumes; 0 for no limit
+
+	quarantinePath string             // file recording data imported by failed restores
+	quarantine     *RestoreQuarantine // loaded from quarantinePath on first use
+	quarantineMu   sync.Mutex
 }
 
 // NewDistributedFilesystem instantiates a new DistributedFilsystem object
@@ -126,3 +171,10 @@ func (dfs *DistributedFilesystem) Timeout() time.Duration {
 func (dfs *DistributedFilesystem) SetTmp(tmp string) {
 	dfs.tmp = tmp
 }
+
+// SetIOLimits sets the bytes per second that backups may export from and
+// restores may import into the application volumes; 0 for no limit.
+func (dfs *DistributedFilesystem) SetIOLimits(backup, restore int64) {
+	dfs.backupIOLimit = backup
+	dfs.restoreIOLimit = restore
+}
@@ -39,10 +39,12 @@ func (e ErrDfsBusy) Error() string {
 }
 
 func (dfs *DistributedFilesystem) Lock(opName string) {
+	defer lockWaiting()()
 	dfs.locker.Lock(opName)
 }
 
 func (dfs *DistributedFilesystem) LockWithTimeout(opName string, timeout time.Duration) error {
+	defer lockWaiting()()
 	if gotLock, blockingOp := dfs.locker.LockWithTimeout(opName, timeout); !gotLock {
 		return ErrDfsBusy{blockingOp}
 	}
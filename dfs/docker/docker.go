@@ -17,15 +17,17 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/control-center/serviced/commons"
 	dockerclient "github.com/fsouza/go-dockerclient"
-	"github.com/zenoss/glog"
 )
 
 const (
@@ -70,6 +72,9 @@ type Docker interface {
 	GetImageHash(image string) (string, error)
 	GetContainerStats(containerID string, timeout time.Duration) (*dockerclient.Stats, error)
 	FindImageByHash(imageHash string, checkAllLayers bool) (*dockerclient.Image, error)
+	Version() (string, error)
+	Info() (*dockerclient.DockerInfo, error)
+	ImageArchitectures(image string) ([]string, error)
 }
 
 type DockerClient struct {
@@ -93,7 +98,7 @@ func (d *DockerClient) SaveImages(images []string, writer io.Writer) error {
 		Names:        images,
 		OutputStream: writer,
 	}
-	glog.Infof("Exporting images %s", images)
+	plog.Infof("Exporting images %s", images)
 	return d.dc.ExportImages(opts)
 }
 
@@ -176,7 +181,7 @@ func (d *DockerClient) fetchCreds(registry string) (auth dockerclient.AuthConfig
 	}
 	auth, ok := auths.Configs[registry]
 	if ok {
-		glog.V(1).Infof("Authorized as %s in registry %s", auth.Email, registry)
+		plog.Debugf("Authorized as %s in registry %s", auth.Email, registry)
 	}
 	return
 }
@@ -255,9 +260,78 @@ func (d *DockerClient) FindImageByHash(imageHash string, checkAllLayers bool) (*
 				return d.FindImage(apiImage.ID)
 			}
 		} else {
-			glog.Warningf("Error computing hash for %s: %s", apiImage.ID, err)
+			plog.Warningf("Error computing hash for %s: %s", apiImage.ID, err)
 		}
 	}
 
 	return nil, dockerclient.ErrNoSuchImage
 }
+
+// Version returns the version of the docker daemon
+// Info returns the system-wide information reported by the docker daemon
+func (d *DockerClient) Info() (*dockerclient.DockerInfo, error) {
+	return d.dc.Info()
+}
+
+func (d *DockerClient) Version() (string, error) {
+	env, err := d.dc.Version()
+	if err != nil {
+		return "", err
+	}
+	return env.Get("Version"), nil
+}
+
+// ImageArchitectures returns the CPU architectures of every platform in the
+// manifest list of an image in its upstream registry.
+func (d *DockerClient) ImageArchitectures(image string) ([]string, error) {
+	imageID, err := commons.ParseImageID(image)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := json.Marshal(d.fetchCreds(imageID.Registry()))
+	if err != nil {
+		return nil, err
+	}
+
+	// the docker client does not wrap the distribution endpoint, so query the
+	// daemon directly
+	client := &http.Client{
+		Transport: &http.Transport{
+			Dial: func(network, addr string) (net.Conn, error) {
+				return net.Dial("unix", strings.TrimPrefix(DefaultSocket, "unix://"))
+			},
+		},
+		Timeout: 30 * time.Second,
+	}
+	req, err := http.NewRequest("GET", "http://docker/distribution/"+imageID.String()+"/json", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Registry-Auth", base64.URLEncoding.EncodeToString(creds))
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not inspect the distribution of image %s: %s", image, resp.Status)
+	}
+
+	var dist struct {
+		Platforms []struct {
+			Architecture string `json:"architecture"`
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&dist); err != nil {
+		return nil, err
+	}
+	var archs []string
+	seen := make(map[string]bool)
+	for _, p := range dist.Platforms {
+		if p.Architecture != "" && !seen[p.Architecture] {
+			seen[p.Architecture] = true
+			archs = append(archs, p.Architecture)
+		}
+	}
+	return archs, nil
+}
@@ -21,7 +21,6 @@ import (
 	index "github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/registry"
 	dockerclient "github.com/fsouza/go-dockerclient"
-	"github.com/zenoss/glog"
 )
 
 var (
@@ -52,20 +51,20 @@ func (dfs *DistributedFilesystem) Download(image, tenantID string, upgrade bool)
 
 	hash, err := dfs.docker.GetImageHash(img.ID)
 	if err != nil {
-		glog.Errorf("Could not get hash for image %s: %s", img.ID, err)
+		plog.Errorf("Could not get hash for image %s: %s", img.ID, err)
 		return "", err
 	}
 
 	rimg, err := dfs.index.FindImage(rImage)
 	if err == index.ErrImageNotFound {
 		// Image does not exist in the registry, so push
-		if err := dfs.index.PushImage(rImage, img.ID, hash); err != nil {
-			glog.Errorf("Could not push image %s into registry: %s", rImage, err)
+		if err := dfs.pushImage(rImage, img.ID, hash, dfs.upstreamArchs(image, img)); err != nil {
+			plog.Errorf("Could not push image %s into registry: %s", rImage, err)
 			return "", err
 		}
 		return rImage, nil
 	} else if err != nil {
-		glog.Errorf("Could not look up image %s from the registry: %s", rImage, err)
+		plog.Errorf("Could not look up image %s from the registry: %s", rImage, err)
 		return "", err
 	}
 	// Compare the uuids of the topmost layer
@@ -73,8 +72,8 @@ func (dfs *DistributedFilesystem) Download(image, tenantID string, upgrade bool)
 		if upgrade {
 			// We are upgrading the image, so overwrite the existing tag with
 			// the new UUID.
-			if err := dfs.index.PushImage(rImage, img.ID, hash); err != nil {
-				glog.Errorf("Could not upgrade image %s into registry: %s", rImage, err)
+			if err := dfs.pushImage(rImage, img.ID, hash, dfs.upstreamArchs(image, img)); err != nil {
+				plog.Errorf("Could not upgrade image %s into registry: %s", rImage, err)
 				return "", err
 			}
 		} else {
@@ -93,7 +92,7 @@ func (dfs *DistributedFilesystem) findImage(image, tenantID string) (string, err
 		if err == index.ErrImageNotFound {
 			return "", nil
 		}
-		glog.Errorf("Could not look up image %s from the registry: %s", image, err)
+		plog.Errorf("Could not look up image %s from the registry: %s", image, err)
 		return "", err
 	}
 	// Is this image latest under the same tenant?
@@ -109,7 +108,7 @@ func (dfs *DistributedFilesystem) parseRegistryImage(image, tenantID string) (st
 	// Is this a proper docker image?
 	imageID, err := commons.ParseImageID(image)
 	if err != nil {
-		glog.Errorf("Could not parse image %s: %s", image, err)
+		plog.Errorf("Could not parse image %s: %s", image, err)
 		return "", err
 	}
 	// Get the registry path
@@ -127,16 +126,16 @@ func (dfs *DistributedFilesystem) pullImage(image string) (*dockerclient.Image,
 	// Find (or download) the image
 	img, err := dfs.docker.FindImage(image)
 	if docker.IsImageNotFound(err) {
-		glog.Infof("Image %s not found locally, pulling", image)
-		if err := dfs.docker.PullImage(image); err != nil {
-			glog.Errorf("Could not pull image %s: %s", image, err)
+		plog.Infof("Image %s not found locally, pulling", image)
+		if err := dfs.pullDockerImage(image); err != nil {
+			plog.Errorf("Could not pull image %s: %s", image, err)
 			return nil, err
 		} else if img, err = dfs.docker.FindImage(image); err != nil {
-			glog.Errorf("Could not find image %s: %s", image, err)
+			plog.Errorf("Could not find image %s: %s", image, err)
 			return nil, err
 		}
 	} else if err != nil {
-		glog.Errorf("Could not find image %s: %s", image, err)
+		plog.Errorf("Could not find image %s: %s", image, err)
 		return nil, err
 	}
 	return img, nil
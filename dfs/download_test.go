@@ -128,7 +128,8 @@ func (s *DFSTestSuite) TestDownload_Upgrade(c *C) {
 	image := &dockerclient.Image{ID: "testimage1"}
 	s.docker.On("FindImage", "library/repo:tag").Return(image, nil)
 	s.docker.On("GetImageHash", "testimage1").Return("hashvalue", nil)
-	s.index.On("PushImage", "tenant/repo:latest", "testimage1", "hashvalue").Return(ErrTestImageNotInRegistry).Once()
+	s.docker.On("ImageArchitectures", "library/repo:tag").Return([]string{"amd64", "arm64"}, nil)
+	s.index.On("PushImage", "tenant/repo:latest", "testimage1", "hashvalue", []string{"amd64", "arm64"}).Return(ErrTestImageNotInRegistry).Once()
 	rImage := &registry.Image{
 		Library: "tenant",
 		Repo:    "repo",
@@ -140,7 +141,7 @@ func (s *DFSTestSuite) TestDownload_Upgrade(c *C) {
 	img, err := s.dfs.Download("library/repo:tag", "tenant", true)
 	c.Assert(img, Equals, "")
 	c.Assert(err, Equals, ErrTestImageNotInRegistry)
-	s.index.On("PushImage", "tenant/repo:latest", "testimage1", "hashvalue").Return(nil).Once()
+	s.index.On("PushImage", "tenant/repo:latest", "testimage1", "hashvalue", []string{"amd64", "arm64"}).Return(nil).Once()
 	img, err = s.dfs.Download("library/repo:tag", "tenant", true)
 	c.Assert(img, Equals, "tenant/repo:latest")
 	c.Assert(err, IsNil)
@@ -172,16 +173,17 @@ func (s *DFSTestSuite) TestDownload_NoUpgrade(c *C) {
 		UUID:    "testimage2",
 	}
 	s.index.On("FindImage", "tenant/repo2:latest").Return(rImage, nil)
-	s.index.On("PushImage", "tenant/repo2:latest", "testimage2", "hashvalue2").Return(nil)
+	s.index.On("PushImage", "tenant/repo2:latest", "testimage2", "hashvalue2", []string(nil)).Return(nil)
 	img, err = s.dfs.Download("library/repo2:tag", "tenant", false)
 	c.Assert(img, Equals, "tenant/repo2:latest")
 	c.Assert(err, IsNil)
 	s.index.On("FindImage", "library/repo3:tag").Return(nil, index.ErrImageNotFound)
-	image = &dockerclient.Image{ID: "testimage3"}
+	image = &dockerclient.Image{ID: "testimage3", Architecture: "amd64"}
 	s.docker.On("FindImage", "library/repo3:tag").Return(image, nil)
 	s.docker.On("GetImageHash", "testimage3").Return("hashvalue3", nil).Once()
 	s.index.On("FindImage", "tenant/repo3:latest").Return(nil, index.ErrImageNotFound)
-	s.index.On("PushImage", "tenant/repo3:latest", "testimage3", "hashvalue3").Return(nil)
+	s.docker.On("ImageArchitectures", "library/repo3:tag").Return(nil, ErrTestGeneric)
+	s.index.On("PushImage", "tenant/repo3:latest", "testimage3", "hashvalue3", []string{"amd64"}).Return(nil)
 	img, err = s.dfs.Download("library/repo3:tag", "tenant", false)
 	c.Assert(img, Equals, "tenant/repo3:latest")
 	c.Assert(err, IsNil)
@@ -0,0 +1,192 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/dfs/docker"
+)
+
+// DefaultBackupThroughput is the rate, in bytes per second, used to estimate
+// the duration of a backup when no backups have been measured on this host.
+const DefaultBackupThroughput = 50 * 1024 * 1024
+
+// Types of backup components
+const (
+	BackupComponentImage  = "image"
+	BackupComponentVolume = "volume"
+)
+
+// BackupEstimateRequest describes the contents of a backup to estimate
+type BackupEstimateRequest struct {
+	BaseImages []string
+	Tenants    []string
+	Excludes   map[string][]string // subdirectories excluded from each tenant volume
+}
+
+// BackupComponent is the estimated size of an image or volume in a backup
+type BackupComponent struct {
+	Type  string
+	Name  string
+	Size  int64
+	Error string // why the size could not be determined, if not empty
+}
+
+// BackupEstimate is the estimated size and duration of a backup.  Sizes are
+// uncompressed, so the backup file is usually smaller than the estimate.
+type BackupEstimate struct {
+	Components []BackupComponent
+	Size       int64
+	Duration   time.Duration
+	Throughput float64 // bytes per second used to estimate the duration
+}
+
+// EstimateBackup computes the size of the images and tenant volumes that would
+// be written to a backup, without taking any snapshots.
+func (dfs *DistributedFilesystem) EstimateBackup(req BackupEstimateRequest) (*BackupEstimate, error) {
+	estimate := &BackupEstimate{}
+	add := func(component BackupComponent) {
+		estimate.Components = append(estimate.Components, component)
+		estimate.Size += component.Size
+	}
+
+	// docker only saves each image once, no matter how many times it is tagged
+	imageIDs := make(map[string]struct{})
+	addImage := func(name, image string) {
+		component := BackupComponent{Type: BackupComponentImage, Name: name}
+		if img, err := dfs.docker.FindImage(image); err != nil {
+			plog.Warningf("Could not find image %s to estimate its size: %s", image, err)
+			component.Error = err.Error()
+		} else if _, ok := imageIDs[img.ID]; ok {
+			return
+		} else {
+			imageIDs[img.ID] = struct{}{}
+			component.Size = img.VirtualSize
+		}
+		add(component)
+	}
+
+	for _, image := range req.BaseImages {
+		addImage(image, image)
+	}
+	for _, tenantID := range req.Tenants {
+		rImages, err := dfs.index.SearchLibraryByTag(tenantID, docker.Latest)
+		if err != nil {
+			plog.Errorf("Could not search the registry index for the images of tenant %s: %s", tenantID, err)
+			return nil, err
+		}
+		for _, rImage := range rImages {
+			addImage(rImage.String(), rImage.UUID)
+		}
+
+		component := BackupComponent{Type: BackupComponentVolume, Name: tenantID}
+		vol, err := dfs.disk.Get(tenantID)
+		if err != nil {
+			plog.Errorf("Could not get volume for tenant %s: %s", tenantID, err)
+			return nil, err
+		}
+		if component.Size, err = directorySize(vol.Path(), req.Excludes[tenantID]); err != nil {
+			plog.Warningf("Could not compute the size of the volume for tenant %s: %s", tenantID, err)
+			component.Error = err.Error()
+		}
+		add(component)
+	}
+
+	estimate.Throughput = DefaultBackupThroughput
+	for _, op := range GetStats().Operations {
+		if op.Operation == OpBackup && op.Throughput > 0 {
+			estimate.Throughput = op.Throughput
+		}
+	}
+	estimate.Duration = time.Duration(float64(estimate.Size) / estimate.Throughput * float64(time.Second))
+	return estimate, nil
+}
+
+// directorySize returns the total size of the regular files below root,
+// skipping the paths that match the globs in excludes.
+func directorySize(root string, excludes []string) (int64, error) {
+	var patterns []*regexp.Regexp
+	for _, exclude := range excludes {
+		pattern, err := excludeRegexp(strings.TrimPrefix(filepath.Clean("/"+exclude), "/"))
+		if err != nil {
+			plog.Warningf("Could not apply backup exclude %s: %s", exclude, err)
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	var size int64
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if rel, err := filepath.Rel(root, path); err == nil && rel != "." {
+			for _, pattern := range patterns {
+				if pattern.MatchString(rel) {
+					if info.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+			}
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// excludeRegexp converts a backup exclude into a regular expression that
+// matches the same paths as tar --exclude, which is how the volume is
+// exported.  Unlike filepath.Match, tar does not anchor the pattern, so it
+// may match the path from any directory down (e.g. "cache" matches
+// "app/cache"), and its wildcards also match '/'.
+func excludeRegexp(pattern string) (*regexp.Regexp, error) {
+	expr := "^(?:.*/)?"
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			expr += ".*"
+		case '?':
+			expr += "."
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				expr += regexp.QuoteMeta(pattern[i : i+1])
+			} else {
+				expr += regexp.QuoteMeta("\\")
+			}
+		case '[':
+			if j := strings.IndexByte(pattern[i+1:], ']'); j > 0 {
+				class := pattern[i+1 : i+1+j]
+				if class[0] == '!' {
+					class = "^" + class[1:]
+				}
+				expr += "[" + strings.Replace(class, "\\", "\\\\", -1) + "]"
+				i += j + 1
+			} else {
+				expr += regexp.QuoteMeta("[")
+			}
+		default:
+			expr += regexp.QuoteMeta(string(c))
+		}
+	}
+	return regexp.Compile(expr + "$")
+}
@@ -0,0 +1,89 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/registry"
+	volumemocks "github.com/control-center/serviced/volume/mocks"
+	dockerclient "github.com/fsouza/go-dockerclient"
+	. "gopkg.in/check.v1"
+)
+
+func (s *DFSTestSuite) TestEstimateBackup_IndexError(c *C) {
+	s.index.On("SearchLibraryByTag", "BASE", "latest").Return(nil, ErrTestGeneric)
+	estimate, err := s.dfs.EstimateBackup(BackupEstimateRequest{Tenants: []string{"BASE"}})
+	c.Assert(err, Equals, ErrTestGeneric)
+	c.Assert(estimate, IsNil)
+}
+
+func (s *DFSTestSuite) TestEstimateBackup_VolumeNotFound(c *C) {
+	s.index.On("SearchLibraryByTag", "BASE", "latest").Return([]registry.Image{}, nil)
+	s.disk.On("Get", "BASE").Return(&volumemocks.Volume{}, ErrTestVolumeNotFound)
+	estimate, err := s.dfs.EstimateBackup(BackupEstimateRequest{Tenants: []string{"BASE"}})
+	c.Assert(err, Equals, ErrTestVolumeNotFound)
+	c.Assert(estimate, IsNil)
+}
+
+func (s *DFSTestSuite) TestEstimateBackup_Success(c *C) {
+	root, err := ioutil.TempDir("", "dfs-estimate-")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(root)
+	c.Assert(os.MkdirAll(filepath.Join(root, "data"), 0755), IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(root, "excluded", "sub"), 0755), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "data", "file"), make([]byte, 100), 0644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "excluded", "sub", "file"), make([]byte, 1000), 0644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "data", "file.tmp"), make([]byte, 10000), 0644), IsNil)
+
+	// tar --exclude patterns are not anchored, and their wildcards match '/'
+	c.Assert(os.MkdirAll(filepath.Join(root, "app", "cache"), 0755), IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(root, "data", "nested"), 0755), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "app", "cache", "file"), make([]byte, 500), 0644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "data", "nested", "file.tmp"), make([]byte, 5000), 0644), IsNil)
+
+	s.docker.On("FindImage", "base:latest").Return(&dockerclient.Image{ID: "baseid", VirtualSize: 2000}, nil)
+	s.docker.On("FindImage", "missing:latest").Return(nil, ErrTestImageNotFound)
+	rImages := []registry.Image{
+		{Library: "BASE", Repo: "repo", Tag: "latest", UUID: "repoid"},
+		{Library: "BASE", Repo: "same", Tag: "latest", UUID: "baseid"},
+	}
+	s.index.On("SearchLibraryByTag", "BASE", "latest").Return(rImages, nil)
+	s.docker.On("FindImage", "repoid").Return(&dockerclient.Image{ID: "repoid", VirtualSize: 3000}, nil)
+	s.docker.On("FindImage", "baseid").Return(&dockerclient.Image{ID: "baseid", VirtualSize: 2000}, nil)
+	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
+	vol.On("Path").Return(root)
+
+	estimate, err := s.dfs.EstimateBackup(BackupEstimateRequest{
+		BaseImages: []string{"base:latest", "missing:latest"},
+		Tenants:    []string{"BASE"},
+		Excludes:   map[string][]string{"BASE": {"excluded", "data/*.tmp", "cache"}},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(estimate.Components, DeepEquals, []BackupComponent{
+		{Type: BackupComponentImage, Name: "base:latest", Size: 2000},
+		{Type: BackupComponentImage, Name: "missing:latest", Error: ErrTestImageNotFound.Error()},
+		{Type: BackupComponentImage, Name: "BASE/repo:latest", Size: 3000},
+		{Type: BackupComponentVolume, Name: "BASE", Size: 100},
+	})
+	c.Assert(estimate.Size, Equals, int64(5100))
+	c.Assert(estimate.Throughput > 0, Equals, true)
+	c.Assert(estimate.Duration, Equals, time.Duration(float64(estimate.Size)/estimate.Throughput*float64(time.Second)))
+}
@@ -15,7 +15,6 @@ package dfs
 
 import (
 	"github.com/control-center/serviced/volume"
-	"github.com/zenoss/glog"
 )
 
 // Info returns information about an existing snapshot.
@@ -31,12 +30,12 @@ func (dfs *DistributedFilesystem) Info(snapshotID string) (*SnapshotInfo, error)
 func (dfs *DistributedFilesystem) getSnapshotVolumeAndInfo(snapshotID string) (volume.Volume, *volume.SnapshotInfo, error) {
 	vol, err := dfs.disk.GetTenant(snapshotID)
 	if err != nil {
-		glog.Errorf("Could not get tenant of snapshot %s: %s", snapshotID, err)
+		plog.Errorf("Could not get tenant of snapshot %s: %s", snapshotID, err)
 		return nil, nil, err
 	}
 	info, err := vol.SnapshotInfo(snapshotID)
 	if err != nil {
-		glog.Errorf("Could not get info for snapshot %s: %s", snapshotID, err)
+		plog.Errorf("Could not get info for snapshot %s: %s", snapshotID, err)
 		return nil, nil, err
 	}
 	return vol, info, nil
@@ -117,7 +117,12 @@ func (s *DFSTestSuite) TestInfo_Success(c *C) {
 	err = json.NewEncoder(imgsbuffer).Encode(imgs)
 	c.Assert(err, IsNil)
 	vol.On("ReadMetadata", "snapshot-label", ImagesMetadataFile).Return(&NopCloser{imgsbuffer}, nil)
+	provenance := SnapshotProvenance{User: "zenoss", Source: SnapshotSourceCommit, ImageIDs: []string{"test-tenant/repo"}}
+	provenancebuffer := bytes.NewBufferString("")
+	err = json.NewEncoder(provenancebuffer).Encode(provenance)
+	c.Assert(err, IsNil)
+	vol.On("ReadMetadata", "snapshot-label", ProvenanceMetadataFile).Return(&NopCloser{provenancebuffer}, nil)
 	info, err := s.dfs.Info("test-snapshot-label")
 	c.Assert(err, IsNil)
-	c.Assert(info, DeepEquals, &SnapshotInfo{vinfo, imgs, svcs})
+	c.Assert(info, DeepEquals, &SnapshotInfo{vinfo, imgs, svcs, provenance})
 }
@@ -13,18 +13,16 @@
 
 package dfs
 
-import "github.com/zenoss/glog"
-
 // List returns the list of snapshots for a given tenant.
 func (dfs *DistributedFilesystem) List(tenantID string) ([]string, error) {
 	vol, err := dfs.disk.Get(tenantID)
 	if err != nil {
-		glog.Errorf("Could not get volume for tenant %s: %s", tenantID, err)
+		plog.Errorf("Could not get volume for tenant %s: %s", tenantID, err)
 		return nil, err
 	}
 	snapshots, err := vol.Snapshots()
 	if err != nil {
-		glog.Errorf("Could not get snapshots for tenant %s: %s", tenantID, err)
+		plog.Errorf("Could not get snapshots for tenant %s: %s", tenantID, err)
 		return nil, err
 	}
 	return snapshots, nil
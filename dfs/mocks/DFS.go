@@ -111,6 +111,27 @@ func (_m *DFS) Commit(ctrID string) (string, error) {
 	return r0, r1
 }
 
+// CommitInstance provides a mock function with given fields: ctrID
+func (_m *DFS) CommitInstance(ctrID string) (string, error) {
+	ret := _m.Called(ctrID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(ctrID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ctrID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Snapshot provides a mock function with given fields: info
 func (_m *DFS) Snapshot(info dfs.SnapshotInfo, spaceFactor int) (string, error) {
 	ret := _m.Called(info)
@@ -257,6 +278,89 @@ func (_m *DFS) BackupInfo(r io.Reader) (*dfs.BackupInfo, error) {
 	return r0, r1
 }
 
+// CheckRestore provides a mock function with given fields: info, opts
+func (_m *DFS) CheckRestore(info dfs.BackupInfo, opts dfs.RestoreOptions) error {
+	ret := _m.Called(info, opts)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(dfs.BackupInfo, dfs.RestoreOptions) error); ok {
+		r0 = rf(info, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreQuarantine provides a mock function with given fields:
+func (_m *DFS) RestoreQuarantine() (*dfs.RestoreQuarantine, error) {
+	ret := _m.Called()
+
+	var r0 *dfs.RestoreQuarantine
+	if rf, ok := ret.Get(0).(func() *dfs.RestoreQuarantine); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.RestoreQuarantine)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CleanupFailedRestore provides a mock function with given fields:
+func (_m *DFS) CleanupFailedRestore() (*dfs.RestoreQuarantine, error) {
+	ret := _m.Called()
+
+	var r0 *dfs.RestoreQuarantine
+	if rf, ok := ret.Get(0).(func() *dfs.RestoreQuarantine); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.RestoreQuarantine)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EstimateBackup provides a mock function with given fields: req
+func (_m *DFS) EstimateBackup(req dfs.BackupEstimateRequest) (*dfs.BackupEstimate, error) {
+	ret := _m.Called(req)
+
+	var r0 *dfs.BackupEstimate
+	if rf, ok := ret.Get(0).(func(dfs.BackupEstimateRequest) *dfs.BackupEstimate); ok {
+		r0 = rf(req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.BackupEstimate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(dfs.BackupEstimateRequest) error); ok {
+		r1 = rf(req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Tag provides a mock function with given fields: snapshotID, tagName
 func (_m *DFS) Tag(snapshotID string, tagName string) error {
 	ret := _m.Called(snapshotID, tagName)
@@ -342,3 +446,36 @@ func (_m *DFS) Override(newImage string, oldImage string) error {
 
 	return r0
 }
+
+// CopyImage provides a mock function with given fields: image, tenantID, tag
+func (_m *DFS) CopyImage(image string, tenantID string, tag string) (string, error) {
+	ret := _m.Called(image, tenantID, tag)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
+		r0 = rf(image, tenantID, tag)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(image, tenantID, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *DFS) Seed(snapshotID string, tenantID string) error {
+	ret := _m.Called(snapshotID, tenantID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(snapshotID, tenantID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
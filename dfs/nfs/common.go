@@ -26,7 +26,6 @@ import (
 	"github.com/control-center/serviced/commons/proc"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/validation"
-	"github.com/zenoss/glog"
 )
 
 var etcHostsAllow = "/etc/hosts.allow"
@@ -88,12 +87,12 @@ func (d *NFSDriver) Info(localPath string, info *proc.NFSMountInfo) error {
 }
 
 func (d *NFSDriver) Mount(remotePath, localPath string, timeout time.Duration) error {
-	glog.Infof("Mounting %s -> %s", remotePath, localPath)
+	log.Infof("Mounting %s -> %s", remotePath, localPath)
 	cmd := commandFactory("mount.nfs4", "-o", "intr", remotePath, localPath)
 	errC := make(chan error, 1)
 	go func() {
 		output, err := cmd.CombinedOutput()
-		glog.V(1).Infof("Mounting %s -> %s: %s (%s)", remotePath, localPath, string(output), err)
+		log.Debugf("Mounting %s -> %s: %s (%s)", remotePath, localPath, string(output), err)
 		if exitCode, ok := utils.GetExitStatus(err); exitCode == 32 || !ok {
 			errC <- fmt.Errorf("%s (%s)", string(output), err)
 		} else {
@@ -114,7 +113,7 @@ func (d *NFSDriver) Mount(remotePath, localPath string, timeout time.Duration) e
 }
 
 func (d *NFSDriver) Unmount(localPath string) error {
-	glog.Infof("Unmounting %s", localPath)
+	log.Infof("Unmounting %s", localPath)
 	cmd := commandFactory("umount", "-f", localPath)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -156,11 +155,11 @@ func Mount(driver Driver, remotePath, localPath string) error {
 	if mountError == nil {
 		// we need to check for a stale NFS mount
 		if staleNFSCheck(localPath) {
-			glog.Infof("Detected stale NFS mount, re-mounting %s", localPath)
+			log.Infof("Detected stale NFS mount, re-mounting %s", localPath)
 			//unmount and re-mount
 			needsReMount = true
 			if err := driver.Unmount(localPath); err != nil {
-				glog.Errorf("Error while unmounting %s: %s", localPath, err)
+				log.Errorf("Error while unmounting %s: %s", localPath, err)
 				return err
 			}
 
@@ -169,12 +168,12 @@ func Mount(driver Driver, remotePath, localPath string) error {
 
 	if mountError == proc.ErrMountPointNotFound || needsReMount {
 		// the mountpoint is not found so try to mount
-		glog.Infof("Creating new mount for %s -> %s", remotePath, localPath)
+		log.Infof("Creating new mount for %s -> %s", remotePath, localPath)
 		if err := os.MkdirAll(localPath, 0775); err != nil {
 			return err
 		}
 		if err := driver.Mount(remotePath, localPath, time.Second*30); err != nil {
-			glog.Errorf("Error while creating mount point for %s -> %s: %s", remotePath, localPath, err)
+			log.Errorf("Error while creating mount point for %s -> %s: %s", remotePath, localPath, err)
 			return err
 		}
 
@@ -184,21 +183,21 @@ func Mount(driver Driver, remotePath, localPath string) error {
 
 	if mountError != nil {
 		// we should have a mount point by now or bust
-		glog.Errorf("Could not get volume info for %s (mounting from %s): %s", localPath, remotePath, mountError)
+		log.Errorf("Could not get volume info for %s (mounting from %s): %s", localPath, remotePath, mountError)
 		return mountError
 	}
 
 	// validate mount info
-	glog.Infof("Mount Info: %+v", mountInfo)
+	log.Infof("Mount Info: %+v", mountInfo)
 	verr := validation.NewValidationError()
 	verr.Add(validation.StringsEqual(remotePath, mountInfo.RemotePath, ""))
 	verr.Add(validation.StringsEqual("nfs4", mountInfo.FSType, fmt.Sprintf("%s not mounted nfs4, %s instead", mountInfo.LocalPath, mountInfo.FSType)))
 
 	if verr.HasError() {
 		// the mountpoint is stale or wrong, so unmount
-		glog.Warningf("Stale mount point at %s (mounting %s)", localPath, remotePath)
+		log.Warningf("Stale mount point at %s (mounting %s)", localPath, remotePath)
 		if err := driver.Unmount(localPath); err != nil {
-			glog.Errorf("Could not unmount %s: %s", localPath, err)
+			log.Errorf("Could not unmount %s: %s", localPath, err)
 		}
 		return verr
 	}
@@ -216,12 +215,12 @@ func Unmount(driver Driver, localPath string) error {
 	// verify mount
 	var mountInfo proc.NFSMountInfo
 	if err := driver.Info(localPath, &mountInfo); err != nil {
-		glog.Errorf("Could not get volume info for %s: %s", localPath, err)
+		log.Errorf("Could not get volume info for %s: %s", localPath, err)
 		return err
 	}
 
 	if err := driver.Unmount(localPath); err != nil {
-		glog.Errorf("Could not unmount %s: %s", localPath, err)
+		log.Errorf("Could not unmount %s: %s", localPath, err)
 		return err
 	}
 
@@ -18,7 +18,6 @@ import (
 	"os/exec"
 
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 )
 
 var nfsServiceName = determineNfsServiceName()
@@ -59,7 +58,7 @@ func reloadImpl() error {
 	if err != nil {
 		return fmt.Errorf("%s: %s", err, string(output))
 	}
-	glog.Infof("reloaded nfs server: %s", string(output))
+	log.Infof("reloaded nfs server: %s", string(output))
 	return nil
 }
 
@@ -75,7 +74,7 @@ func startImpl() error {
 	if err != nil {
 		return fmt.Errorf("%s: %s", err, string(output))
 	}
-	glog.Infof("started nfs server: %s", string(output))
+	log.Infof("started nfs server: %s", string(output))
 	return nil
 }
 
@@ -91,7 +90,7 @@ func restartImpl() error {
 	if err != nil {
 		return fmt.Errorf("%s: %s", err, string(output))
 	}
-	glog.Infof("restarted nfs server: %s", string(output))
+	log.Infof("restarted nfs server: %s", string(output))
 	return nil
 }
 
@@ -107,6 +106,6 @@ func stopImpl() error {
 	if err != nil {
 		return fmt.Errorf("%s: %s", err, string(output))
 	}
-	glog.Infof("stopped nfs server: %s", string(output))
+	log.Infof("stopped nfs server: %s", string(output))
 	return nil
 }
@@ -31,7 +31,6 @@ import (
 	"github.com/control-center/serviced/commons/atomicfile"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 )
 
 // Refine facade.DfsValidator to avoid circular dependencies
@@ -123,7 +122,7 @@ func NewServer(basePath, exportedName, network string) (*Server, error) {
 		return nil, err
 	}
 	if err := mp.Unmount(exportedNamePath); err != nil {
-		glog.Errorf("Could not unmount export directory %s: %s", exportedNamePath, err)
+		log.Errorf("Could not unmount export directory %s: %s", exportedNamePath, err)
 		return nil, err
 	}
 	if _, _, err := net.ParseCIDR(network); err != nil {
@@ -212,23 +211,23 @@ func (c *Server) Sync() error {
 	c.Lock()
 	defer c.Unlock()
 	if err := c.hostsDeny(); err != nil {
-		glog.Errorf("error writing host deny %v", err)
+		log.Errorf("error writing host deny %v", err)
 		return err
 	}
 	if err := c.hostsAllow(); err != nil {
-		glog.Errorf("error writing host allow %v", err)
+		log.Errorf("error writing host allow %v", err)
 		return err
 	}
 	if err := c.writeExports(); err != nil {
-		glog.Errorf("error writing exports %v", err)
+		log.Errorf("error writing exports %v", err)
 		return err
 	}
 	if err := start(); err != nil {
-		glog.Errorf("error running start %v", err)
+		log.Errorf("error running start %v", err)
 		return err
 	}
 	if err := reload(); err != nil {
-		glog.Errorf("error running reload %v", err)
+		log.Errorf("error running reload %v", err)
 		return err
 	}
 	c.cleanupBindMounts()
@@ -260,7 +259,7 @@ func (c *Server) Stop() error {
 	c.Lock()
 	defer c.Unlock()
 	if err := stop(); err != nil {
-		glog.Errorf("err running stop %v", err)
+		log.Errorf("err running stop %v", err)
 		return err
 	}
 	c.cleanupBindMounts()
@@ -351,7 +350,7 @@ func (c *Server) writeExports() error {
 	}
 	c.exported = exports
 
-	glog.Infof("serviced exports:\n %s", serviced_exports)
+	log.Infof("serviced exports:\n %s", serviced_exports)
 	originalContents, err := readFileIfExists(etcExports)
 	if err != nil {
 		return err
@@ -396,19 +395,19 @@ func (c *Server) cleanupBindMounts() {
 	edir := filepath.Join(exportsDir, c.exportedName)
 	//umount any directories not exported
 	if dirContents, err := ioutil.ReadDir(edir); err != nil {
-		glog.Warningf("could not read contents of %s; %v", edir, err)
+		log.Warningf("could not read contents of %s; %v", edir, err)
 	} else {
 		for _, file := range dirContents {
 			if _, found := c.exported[file.Name()]; !found && file.IsDir() {
 				dir := filepath.Join(edir, file.Name())
 				if err := mp.Unmount(dir); err != nil {
-					glog.Warningf("Could not unmount exported directory %s: %s", dir, err)
+					log.Warningf("Could not unmount exported directory %s: %s", dir, err)
 					continue
 				}
 				//remove the directory
-				glog.V(1).Infof("deleting dir %s as it is no longer exported", dir)
+				log.Debugf("deleting dir %s as it is no longer exported", dir)
 				if err := os.RemoveAll(dir); err != nil {
-					glog.Warningf("Error removing exported directory %s: %v", dir, err)
+					log.Warningf("Error removing exported directory %s: %v", dir, err)
 				}
 			}
 		}
@@ -421,25 +420,25 @@ func (c *Server) cleanupBindMounts() {
 func (c *Server) removeDeprecated(dirpath string) {
 	if dirContents, err := ioutil.ReadDir(dirpath); !os.IsNotExist(err) {
 		if err != nil {
-			glog.Warningf("Could not look up deprecated exports path %s: %s", dirpath, err)
+			log.Warningf("Could not look up deprecated exports path %s: %s", dirpath, err)
 			return
 		}
 
 		// Unmount path
 		if err := mp.Unmount(dirpath); err != nil {
-			glog.Warningf("Could not unmount deprecated path %s: %s", dirpath, err)
+			log.Warningf("Could not unmount deprecated path %s: %s", dirpath, err)
 		}
 
 		// Guarantee the path is now empty
 		if l := len(dirContents); l > 0 {
-			glog.Warningf("Path %s is not empty.", dirpath)
+			log.Warningf("Path %s is not empty.", dirpath)
 		} else {
 			// Remove the path only if it is empty
 			if err := os.Remove(dirpath); err != nil {
-				glog.Warningf("Could not remove deprecated path %s: %s", dirpath, err)
+				log.Warningf("Could not remove deprecated path %s: %s", dirpath, err)
 				return
 			}
-			glog.Infof("Deleted deprecated path %s", dirpath)
+			log.Infof("Deleted deprecated path %s", dirpath)
 		}
 	}
 }
@@ -450,7 +449,7 @@ var bindMount = bindMountImp
 
 // bindMountImp performs a bind mount of src to dst.
 func bindMountImp(src, dst string) error {
-	glog.Infof("bindMount %s at %s", src, dst)
+	log.Infof("bindMount %s at %s", src, dst)
 	if mounted, err := mp.IsMounted(dst); err != nil {
 		return err
 	} else if mounted {
@@ -462,7 +461,7 @@ func bindMountImp(src, dst string) error {
 	runMountCommand := func(options ...string) ([]byte, error) {
 		cmd, args := mntArgs(src, dst, "", options...)
 		mount := exec.Command(cmd, args...)
-		glog.Infof("running mount: %s %s", cmd, strings.Join(args, " "))
+		log.Infof("running mount: %s %s", cmd, strings.Join(args, " "))
 		return mount.CombinedOutput()
 	}
 	out, returnErr := runMountCommand("bind")
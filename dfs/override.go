@@ -13,10 +13,6 @@
 
 package dfs
 
-import (
-	"github.com/zenoss/glog"
-)
-
 // Override replaces an image in the docker registry with a new image
 // and updates the registry.
 func (dfs *DistributedFilesystem) Override(newimg, oldimg string) error {
@@ -24,26 +20,26 @@ func (dfs *DistributedFilesystem) Override(newimg, oldimg string) error {
 	// make sure the old image exists
 	oldImage, err := dfs.index.FindImage(oldimg)
 	if err != nil {
-		glog.Errorf("Could not find image %s in registry: %s", oldimg, err)
+		plog.Errorf("Could not find image %s in registry: %s", oldimg, err)
 		return err
 	}
 
 	// make sure the new image exists
 	newImage, err := dfs.docker.FindImage(newimg)
 	if err != nil {
-		glog.Errorf("Could not find replacement image %s: %s", newimg, err)
+		plog.Errorf("Could not find replacement image %s: %s", newimg, err)
 		return err
 	}
 
 	// push the image into the registry
 	hash, err := dfs.docker.GetImageHash(newImage.ID)
 	if err != nil {
-		glog.Errorf("Could not get hash for image %s: %s", newimg, err)
+		plog.Errorf("Could not get hash for image %s: %s", newimg, err)
 		return err
 	}
 
-	if err := dfs.index.PushImage(oldImage.String(), newImage.ID, hash); err != nil {
-		glog.Errorf("Could not replace image %s with %s (%s): %s", oldImage, newimg, newImage.ID, err)
+	if err := dfs.pushImage(oldImage.String(), newImage.ID, hash, imageArchs(newImage)); err != nil {
+		plog.Errorf("Could not replace image %s with %s (%s): %s", oldImage, newimg, newImage.ID, err)
 		return err
 	}
 	return nil
@@ -51,7 +51,7 @@ func (s *DFSTestSuite) TestOverride_ErrOnPush(c *C) {
 	s.index.On("FindImage", "oldimage").Return(&oldImage, nil)
 	s.docker.On("FindImage", "newimage").Return(&newImage, nil)
 	s.docker.On("GetImageHash", newImage.ID).Return("newimagehash", nil)
-	s.index.On("PushImage", oldImage.String(), newImage.ID, "newimagehash").Return(ErrTestNoPush)
+	s.index.On("PushImage", oldImage.String(), newImage.ID, "newimagehash", []string(nil)).Return(ErrTestNoPush)
 	err := s.dfs.Override("newimage", "oldimage")
 	c.Assert(err, Equals, ErrTestNoPush)
 }
@@ -60,7 +60,7 @@ func (s *DFSTestSuite) TestOverride_Success(c *C) {
 	s.index.On("FindImage", "oldimage").Return(&oldImage, nil)
 	s.docker.On("FindImage", "newimage").Return(&newImage, nil)
 	s.docker.On("GetImageHash", newImage.ID).Return("newimagehash", nil)
-	s.index.On("PushImage", oldImage.String(), newImage.ID, "newimagehash").Return(nil)
+	s.index.On("PushImage", oldImage.String(), newImage.ID, "newimagehash", []string(nil)).Return(nil)
 	err := s.dfs.Override("newimage", "oldimage")
 	c.Assert(err, IsNil)
 }
@@ -0,0 +1,258 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/control-center/serviced/commons/atomicfile"
+	"github.com/control-center/serviced/volume"
+)
+
+// RestoreQuarantine lists the data imported by restores that did not
+// complete, so that it can be removed without touching anything that existed
+// before the restore began.
+type RestoreQuarantine struct {
+	StartedAt time.Time
+	Volumes   []string            // tenant volumes created by the restore
+	Snapshots map[string][]string // snapshot labels imported by the restore, by tenant
+	Images    []string            // images pushed into the registry by the restore
+}
+
+// Empty returns true if nothing is in quarantine
+func (q *RestoreQuarantine) Empty() bool {
+	return len(q.Volumes) == 0 && len(q.Snapshots) == 0 && len(q.Images) == 0
+}
+
+// copy returns a copy of the quarantine that is safe to hand to callers
+func (q *RestoreQuarantine) copy() *RestoreQuarantine {
+	c := &RestoreQuarantine{
+		StartedAt: q.StartedAt,
+		Volumes:   append([]string{}, q.Volumes...),
+		Snapshots: make(map[string][]string),
+		Images:    append([]string{}, q.Images...),
+	}
+	for tenant, labels := range q.Snapshots {
+		c.Snapshots[tenant] = append([]string{}, labels...)
+	}
+	return c
+}
+
+// SetQuarantinePath sets the file where data imported by a failed restore is
+// recorded.  If unset, the quarantine is only kept in memory.
+func (dfs *DistributedFilesystem) SetQuarantinePath(path string) {
+	dfs.quarantinePath = path
+}
+
+// RestoreQuarantine returns the data imported by restores that did not
+// complete.
+func (dfs *DistributedFilesystem) RestoreQuarantine() (*RestoreQuarantine, error) {
+	dfs.quarantineMu.Lock()
+	defer dfs.quarantineMu.Unlock()
+	q, err := dfs.getQuarantine()
+	if err != nil {
+		return nil, err
+	}
+	return q.copy(), nil
+}
+
+// CleanupFailedRestore removes the snapshots, volumes, and registry images
+// that were imported by restores that did not complete, and returns what was
+// removed.  Volumes that have since been given other snapshots are left in
+// place.
+func (dfs *DistributedFilesystem) CleanupFailedRestore() (*RestoreQuarantine, error) {
+	dfs.quarantineMu.Lock()
+	defer dfs.quarantineMu.Unlock()
+	q, err := dfs.getQuarantine()
+	if err != nil {
+		return nil, err
+	}
+	removed := &RestoreQuarantine{StartedAt: q.StartedAt, Snapshots: make(map[string][]string)}
+	defer func() {
+		if err := dfs.saveQuarantine(); err != nil {
+			plog.Warningf("Could not update restore quarantine: %s", err)
+		}
+	}()
+
+	// remove the snapshots and the images tagged with them
+	for tenant, labels := range q.Snapshots {
+		if !dfs.disk.Exists(tenant) {
+			delete(q.Snapshots, tenant)
+			continue
+		}
+		vol, err := dfs.disk.Get(tenant)
+		if err != nil {
+			plog.Errorf("Could not get volume for tenant %s: %s", tenant, err)
+			return removed, err
+		}
+		for len(labels) > 0 {
+			label := labels[0]
+			if err := dfs.deleteImages(tenant, label); err != nil {
+				return removed, err
+			}
+			if err := vol.RemoveSnapshot(label); err != nil && err != volume.ErrSnapshotDoesNotExist {
+				plog.Errorf("Could not remove quarantined snapshot %s for tenant %s: %s", label, tenant, err)
+				return removed, err
+			}
+			plog.Infof("Removed quarantined snapshot %s for tenant %s", label, tenant)
+			removed.Snapshots[tenant] = append(removed.Snapshots[tenant], label)
+			labels = labels[1:]
+			q.Snapshots[tenant] = labels
+		}
+		delete(q.Snapshots, tenant)
+	}
+
+	// remove the volumes, unless they are holding other snapshots
+	for len(q.Volumes) > 0 {
+		tenant := q.Volumes[0]
+		if dfs.disk.Exists(tenant) {
+			vol, err := dfs.disk.Get(tenant)
+			if err != nil {
+				plog.Errorf("Could not get volume for tenant %s: %s", tenant, err)
+				return removed, err
+			}
+			if snapshots, err := vol.Snapshots(); err != nil {
+				plog.Errorf("Could not get snapshots for tenant %s: %s", tenant, err)
+				return removed, err
+			} else if len(snapshots) > 0 {
+				plog.Warningf("Not removing quarantined volume for tenant %s, because it has %d snapshots that were not restored", tenant, len(snapshots))
+			} else if err := dfs.disk.Remove(tenant); err != nil {
+				plog.Errorf("Could not remove quarantined volume for tenant %s: %s", tenant, err)
+				return removed, err
+			} else {
+				plog.Infof("Removed quarantined volume for tenant %s", tenant)
+				removed.Volumes = append(removed.Volumes, tenant)
+			}
+		}
+		q.Volumes = q.Volumes[1:]
+	}
+
+	// remove any images that were not tagged with a quarantined snapshot
+	for len(q.Images) > 0 {
+		image := q.Images[0]
+		if _, err := dfs.index.FindImage(image); err == nil {
+			if err := dfs.index.RemoveImage(image); err != nil {
+				plog.Errorf("Could not remove quarantined image %s: %s", image, err)
+				return removed, err
+			}
+		}
+		removed.Images = append(removed.Images, image)
+		q.Images = q.Images[1:]
+	}
+
+	return removed, nil
+}
+
+// quarantineVolume records a tenant volume created by the restore in progress
+func (dfs *DistributedFilesystem) quarantineVolume(tenant string) {
+	dfs.updateQuarantine(func(q *RestoreQuarantine) {
+		q.Volumes = append(q.Volumes, tenant)
+	})
+}
+
+// quarantineSnapshot records a snapshot imported by the restore in progress
+func (dfs *DistributedFilesystem) quarantineSnapshot(tenant, label string) {
+	dfs.updateQuarantine(func(q *RestoreQuarantine) {
+		q.Snapshots[tenant] = append(q.Snapshots[tenant], label)
+	})
+}
+
+// quarantineImage records an image pushed by the restore in progress
+func (dfs *DistributedFilesystem) quarantineImage(image string) {
+	dfs.updateQuarantine(func(q *RestoreQuarantine) {
+		q.Images = append(q.Images, image)
+	})
+}
+
+// releaseQuarantine clears the quarantine once a restore has completed, since
+// the data it imported is now the application's data.
+func (dfs *DistributedFilesystem) releaseQuarantine() {
+	dfs.quarantineMu.Lock()
+	defer dfs.quarantineMu.Unlock()
+	dfs.quarantine = &RestoreQuarantine{Snapshots: make(map[string][]string)}
+	if err := dfs.saveQuarantine(); err != nil {
+		plog.Warningf("Could not clear restore quarantine: %s", err)
+	}
+}
+
+// updateQuarantine applies an update to the quarantine and saves it, so that
+// a restore that is interrupted can still be cleaned up.
+func (dfs *DistributedFilesystem) updateQuarantine(update func(q *RestoreQuarantine)) {
+	dfs.quarantineMu.Lock()
+	defer dfs.quarantineMu.Unlock()
+	q, err := dfs.getQuarantine()
+	if err != nil {
+		plog.Warningf("Could not load restore quarantine: %s", err)
+		return
+	}
+	if q.StartedAt.IsZero() {
+		q.StartedAt = time.Now().UTC()
+	}
+	update(q)
+	if err := dfs.saveQuarantine(); err != nil {
+		plog.Warningf("Could not save restore quarantine: %s", err)
+	}
+}
+
+// getQuarantine returns the quarantine, loading it from disk if it is not
+// already in memory.
+func (dfs *DistributedFilesystem) getQuarantine() (*RestoreQuarantine, error) {
+	if dfs.quarantine != nil {
+		return dfs.quarantine, nil
+	}
+	q := &RestoreQuarantine{}
+	if dfs.quarantinePath != "" {
+		if r, err := os.Open(dfs.quarantinePath); err == nil {
+			if err := importJSON(r, q); err != nil {
+				plog.Errorf("Could not read restore quarantine at %s: %s", dfs.quarantinePath, err)
+				return nil, err
+			}
+		} else if !os.IsNotExist(err) {
+			plog.Errorf("Could not open restore quarantine at %s: %s", dfs.quarantinePath, err)
+			return nil, err
+		}
+	}
+	if q.Snapshots == nil {
+		q.Snapshots = make(map[string][]string)
+	}
+	dfs.quarantine = q
+	return q, nil
+}
+
+// saveQuarantine writes the quarantine to disk, or removes the file if there
+// is nothing in quarantine.
+func (dfs *DistributedFilesystem) saveQuarantine() error {
+	if dfs.quarantine == nil {
+		return nil
+	}
+	if dfs.quarantine.Empty() {
+		dfs.quarantine.StartedAt = time.Time{}
+	}
+	if dfs.quarantinePath == "" {
+		return nil
+	}
+	if dfs.quarantine.Empty() {
+		if err := os.Remove(dfs.quarantinePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(dfs.quarantine)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(dfs.quarantinePath, data, 0644)
+}
@@ -0,0 +1,152 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/volume"
+	volumemocks "github.com/control-center/serviced/volume/mocks"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+// failRestore runs a restore of snapshot BASE/LABEL onto a new volume that
+// fails while loading the snapshot's images.
+func (s *DFSTestSuite) failRestore(c *C) *volumemocks.Volume {
+	buf := bytes.NewBufferString("")
+	tarfile := tar.NewWriter(buf)
+	s.writeBackupInfo(c, tarfile, BackupInfo{Snapshots: []string{"BASE_LABEL"}, Timestamp: time.Now().UTC()})
+	err := tarfile.WriteHeader(&tar.Header{Name: path.Join(SnapshotsMetadataDir, "BASE", "LABEL"), Size: 0})
+	c.Assert(err, IsNil)
+	tarfile.Close()
+	vol := &volumemocks.Volume{}
+	s.disk.On("Create", "BASE").Return(vol, nil)
+	s.disk.On("Get", "BASE").Return(vol, nil)
+	vol.On("Import", "LABEL", mock.AnythingOfType("*tar.Reader")).Return(nil)
+	vol.On("ReadMetadata", "LABEL", ImagesMetadataFile).Return(&NopCloser{}, ErrTestNoImagesMetadata).Once()
+	vol.On("RemoveSnapshot", "LABEL").Return(nil).Once()
+	err = s.dfs.Restore(buf, 0)
+	c.Assert(err, Equals, ErrTestNoImagesMetadata)
+	return vol
+}
+
+func (s *DFSTestSuite) TestRestoreQuarantine_Empty(c *C) {
+	q, err := s.dfs.RestoreQuarantine()
+	c.Assert(err, IsNil)
+	c.Assert(q.Empty(), Equals, true)
+}
+
+func (s *DFSTestSuite) TestRestoreQuarantine_FailedRestore(c *C) {
+	s.failRestore(c)
+	q, err := s.dfs.RestoreQuarantine()
+	c.Assert(err, IsNil)
+	c.Assert(q.Volumes, DeepEquals, []string{"BASE"})
+	c.Assert(q.Snapshots, DeepEquals, map[string][]string{"BASE": {"LABEL"}})
+	c.Assert(q.StartedAt.IsZero(), Equals, false)
+}
+
+func (s *DFSTestSuite) TestRestoreQuarantine_ReleasedOnSuccess(c *C) {
+	s.failRestore(c)
+	buf := bytes.NewBufferString("")
+	tarfile := tar.NewWriter(buf)
+	s.writeBackupInfo(c, tarfile, BackupInfo{Timestamp: time.Now().UTC()})
+	tarfile.Close()
+	err := s.dfs.Restore(buf, 0)
+	c.Assert(err, IsNil)
+	q, err := s.dfs.RestoreQuarantine()
+	c.Assert(err, IsNil)
+	c.Assert(q.Empty(), Equals, true)
+}
+
+func (s *DFSTestSuite) TestRestoreQuarantine_Persisted(c *C) {
+	tmpdir, err := ioutil.TempDir("", "dfs-quarantine-test-")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(tmpdir)
+	qpath := filepath.Join(tmpdir, "quarantine")
+	s.dfs.SetQuarantinePath(qpath)
+	s.failRestore(c)
+
+	// a restarted dfs picks up where the failed restore left off
+	dfs := NewDistributedFilesystem(s.docker, s.index, s.registry, s.disk, s.net, time.Minute)
+	dfs.SetQuarantinePath(qpath)
+	q, err := dfs.RestoreQuarantine()
+	c.Assert(err, IsNil)
+	c.Assert(q.Volumes, DeepEquals, []string{"BASE"})
+	c.Assert(q.Snapshots, DeepEquals, map[string][]string{"BASE": {"LABEL"}})
+
+	// cleaning up removes the file
+	s.disk.On("Exists", "BASE").Return(false)
+	_, err = dfs.CleanupFailedRestore()
+	c.Assert(err, IsNil)
+	_, err = os.Stat(qpath)
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
+
+func (s *DFSTestSuite) TestCleanupFailedRestore_Success(c *C) {
+	vol := s.failRestore(c)
+	s.disk.On("Exists", "BASE").Return(true)
+	s.index.On("SearchLibraryByTag", "BASE", "LABEL").Return([]registry.Image{}, nil)
+	vol.On("RemoveSnapshot", "LABEL").Return(volume.ErrSnapshotDoesNotExist)
+	vol.On("Snapshots").Return([]string{}, nil)
+	s.disk.On("Remove", "BASE").Return(nil)
+	removed, err := s.dfs.CleanupFailedRestore()
+	c.Assert(err, IsNil)
+	c.Assert(removed.Volumes, DeepEquals, []string{"BASE"})
+	c.Assert(removed.Snapshots, DeepEquals, map[string][]string{"BASE": {"LABEL"}})
+	s.disk.AssertExpectations(c)
+	q, err := s.dfs.RestoreQuarantine()
+	c.Assert(err, IsNil)
+	c.Assert(q.Empty(), Equals, true)
+}
+
+func (s *DFSTestSuite) TestCleanupFailedRestore_KeepVolumeWithSnapshots(c *C) {
+	vol := s.failRestore(c)
+	s.disk.On("Exists", "BASE").Return(true)
+	s.index.On("SearchLibraryByTag", "BASE", "LABEL").Return([]registry.Image{}, nil)
+	vol.On("RemoveSnapshot", "LABEL").Return(nil)
+	vol.On("Snapshots").Return([]string{"BASE_OTHER"}, nil)
+	removed, err := s.dfs.CleanupFailedRestore()
+	c.Assert(err, IsNil)
+	c.Assert(removed.Volumes, HasLen, 0)
+	s.disk.AssertNotCalled(c, "Remove", "BASE")
+	q, err := s.dfs.RestoreQuarantine()
+	c.Assert(err, IsNil)
+	c.Assert(q.Empty(), Equals, true)
+}
+
+func (s *DFSTestSuite) TestCleanupFailedRestore_FailRemoveSnapshot(c *C) {
+	vol := s.failRestore(c)
+	s.disk.On("Exists", "BASE").Return(true)
+	s.index.On("SearchLibraryByTag", "BASE", "LABEL").Return([]registry.Image{}, nil)
+	vol.On("RemoveSnapshot", "LABEL").Return(ErrTestGeneric)
+	_, err := s.dfs.CleanupFailedRestore()
+	c.Assert(err, Equals, ErrTestGeneric)
+
+	// nothing was removed, so it all stays in quarantine
+	q, err := s.dfs.RestoreQuarantine()
+	c.Assert(err, IsNil)
+	c.Assert(q.Volumes, DeepEquals, []string{"BASE"})
+	c.Assert(q.Snapshots, DeepEquals, map[string][]string{"BASE": {"LABEL"}})
+}
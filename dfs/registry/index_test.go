@@ -81,8 +81,9 @@ func (s *RegistryIndexSuite) TestPushImage(c *C) {
 		Tag:     "tagname",
 		UUID:    "uuidvalue",
 		Hash:    hashValue,
+		Archs:   []string{"amd64", "arm64"},
 	}
-	err := s.index.PushImage("localhost:5000/libraryname/reponame:tagname", "uuidvalue", hashValue)
+	err := s.index.PushImage("localhost:5000/libraryname/reponame:tagname", "uuidvalue", hashValue, []string{"amd64", "arm64"})
 	c.Assert(err, IsNil)
 	s.facade.AssertCalled(c, "SetRegistryImage", s.ctx, expected)
 }
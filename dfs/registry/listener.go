@@ -21,7 +21,6 @@ import (
 	"github.com/control-center/serviced/dfs/docker"
 	"github.com/control-center/serviced/domain/registry"
 	dockerclient "github.com/fsouza/go-dockerclient"
-	"github.com/zenoss/glog"
 )
 
 const (
@@ -111,30 +110,30 @@ func (l *RegistryListener) Spawn(shutdown <-chan interface{}, id string) {
 		var node RegistryImageNode
 		evt, err := l.conn.GetW(imagepath, &node, done)
 		if err != nil {
-			glog.Errorf("Could not look up node at %s: %s", imagepath, err)
+			plog.Errorf("Could not look up node at %s: %s", imagepath, err)
 			return
 		}
 		repopath := path.Join(zkregistryrepos, node.Image.Library, node.Image.Repo)
 		reponode := &RegistryImageLeader{HostID: l.hostid}
 		leader, err := l.conn.NewLeader(repopath)
 		if err != nil {
-			glog.Errorf("Could not set up leader for %s: %s", repopath, err)
+			plog.Errorf("Could not set up leader for %s: %s", repopath, err)
 			return
 		}
 		// Has the image been pushed?
-		glog.V(1).Infof("Spawn id=%s node: %s:%s %s", id, node.Image.Repo, node.Image.Tag, node.Image.Tag)
+		plog.Debugf("Spawn id=%s node: %s:%s %s", id, node.Image.Repo, node.Image.Tag, node.Image.Tag)
 		if node.PushedAt.Unix() == 0 {
 			// Do I have the image?
-			glog.Infof("Checking if push required for id=%s node: %s:%s %s (UUID=%s)", id, node.Image.Repo, node.Image.Tag, node.Image.Tag, node.Image.UUID)
+			plog.Infof("Checking if push required for id=%s node: %s:%s %s (UUID=%s)", id, node.Image.Repo, node.Image.Tag, node.Image.Tag, node.Image.UUID)
 			if img, err := l.FindImage(&node.Image); err == nil {
-				glog.V(1).Infof("Found image %v locally, acquiring lead", node.Image)
+				plog.Debugf("Found image %v locally, acquiring lead", node.Image)
 				func() {
 					// Become the leader so I can push the image
 					leaderDone := make(chan struct{})
 					defer close(leaderDone)
 					_, err := leader.TakeLead(reponode, leaderDone)
 					if err != nil {
-						glog.Errorf("Could not take lead %s: %s", imagepath, err)
+						plog.Errorf("Could not take lead %s: %s", imagepath, err)
 						return
 					}
 					defer leader.ReleaseLead()
@@ -145,20 +144,20 @@ func (l *RegistryListener) Spawn(shutdown <-chan interface{}, id string) {
 					default:
 						// Did the image change (or get pushed) before I got the lead?
 						if err := l.conn.Get(imagepath, &node); err != nil {
-							glog.Errorf("Could not get %s: %s", imagepath, err)
+							plog.Errorf("Could not get %s: %s", imagepath, err)
 							return
 						}
 						if node.PushedAt.Unix() > 0 {
-							glog.V(1).Infof("Image %s already pushed, cancelling push", node.Image.String())
+							plog.Debugf("Image %s already pushed, cancelling push", node.Image.String())
 							return
 						}
 						if img.ID != node.Image.UUID {
 							localHash, err := l.docker.GetImageHash(img.ID)
 							if err != nil {
-								glog.Warningf("Error building hash of image: %s, cancelling push: %s", img.ID, err)
+								plog.Warningf("Error building hash of image: %s, cancelling push: %s", img.ID, err)
 								return
 							} else if localHash != node.Image.Hash {
-								glog.V(1).Infof("Image %s changed, cancelling push", node.Image.String())
+								plog.Debugf("Image %s changed, cancelling push", node.Image.String())
 								return
 							}
 						}
@@ -168,12 +167,12 @@ func (l *RegistryListener) Spawn(shutdown <-chan interface{}, id string) {
 					// so that the push will get retriggered the next time it
 					// is needed.
 					registrypath := path.Join(l.address, node.Image.String())
-					glog.V(1).Infof("Updating registry image %s from path=%s", img.ID, registrypath)
+					plog.Debugf("Updating registry image %s from path=%s", img.ID, registrypath)
 					if err := l.docker.TagImage(img.ID, registrypath); err != nil {
-						glog.Warningf("Could not tag %s as %s: %s", img.ID, registrypath, err)
+						plog.Warningf("Could not tag %s as %s: %s", img.ID, registrypath, err)
 						node.PushedAt = time.Unix(0, 0)
 					} else if err := l.docker.PushImage(registrypath); err != nil {
-						glog.Warningf("Could not push %s: %s", registrypath, err)
+						plog.Warningf("Could not push %s: %s", registrypath, err)
 						node.PushedAt = time.Unix(0, 0)
 					} else {
 						node.PushedAt = time.Now().UTC()
@@ -183,10 +182,10 @@ func (l *RegistryListener) Spawn(shutdown <-chan interface{}, id string) {
 					l.conn.Set(imagepath, &node)
 				}()
 			} else {
-				glog.Errorf("Could not find image %s: %s", node.Image.UUID, err)
+				plog.Errorf("Could not find image %s: %s", node.Image.UUID, err)
 			}
 		}
-		glog.V(1).Infof("Waiting for image %s to update (imagepath=%s)", node.Image, imagepath)
+		plog.Debugf("Waiting for image %s to update (imagepath=%s)", node.Image, imagepath)
 		select {
 		case <-evt:
 		case <-shutdown:
@@ -202,7 +201,7 @@ func (l *RegistryListener) Spawn(shutdown <-chan interface{}, id string) {
 //  then by repo, tag, and hash, then it checks if the image is already in the registry, and finally it searches by hash
 func (l *RegistryListener) FindImage(rImg *registry.Image) (*dockerclient.Image, error) {
 	regaddr := path.Join(l.address, rImg.String())
-	glog.V(1).Infof("Searching for image %s", regaddr)
+	plog.Debugf("Searching for image %s", regaddr)
 
 	// check for UUID
 	if img, err := l.docker.FindImage(rImg.UUID); err == nil {
@@ -210,32 +209,32 @@ func (l *RegistryListener) FindImage(rImg *registry.Image) (*dockerclient.Image,
 	}
 
 	// check by repo and tag, and compare hashes
-	glog.V(1).Infof("UUID %s not found locally, searching by registry address for %s", rImg.UUID, regaddr)
+	plog.Debugf("UUID %s not found locally, searching by registry address for %s", rImg.UUID, regaddr)
 	if img, err := l.docker.FindImage(regaddr); err == nil {
 		if localHash, err := l.docker.GetImageHash(img.ID); err != nil {
-			glog.Warningf("Error building hash of image: %s: %s", img.ID, err)
+			plog.Warningf("Error building hash of image: %s: %s", img.ID, err)
 		} else {
 			if localHash == rImg.Hash {
 				return img, nil
 			}
-			glog.V(1).Infof("Found %s locally, but hashes do not match", regaddr)
+			plog.Debugf("Found %s locally, but hashes do not match", regaddr)
 		}
 	}
 
 	// attempt to pull the image, then compare hashes
-	glog.V(0).Infof("Image address %s not found locally, attempting pull", regaddr)
+	plog.Infof("Image address %s not found locally, attempting pull", regaddr)
 	if err := l.docker.PullImage(regaddr); err == nil {
-		glog.V(1).Infof("Successfully pulled image %s from registry, checking for match", regaddr)
+		plog.Debugf("Successfully pulled image %s from registry, checking for match", regaddr)
 		if img, err := l.docker.FindImage(regaddr); err == nil {
 			if img.ID == rImg.UUID {
-				glog.V(1).Infof("Found image %s in registry with correct UUID", regaddr)
+				plog.Debugf("Found image %s in registry with correct UUID", regaddr)
 				return img, nil
 			}
 			if localHash, err := l.docker.GetImageHash(img.ID); err != nil {
-				glog.Warningf("Error building hash of image: %s: %s", img.ID, err)
+				plog.Warningf("Error building hash of image: %s: %s", img.ID, err)
 			} else {
 				if localHash == rImg.Hash {
-					glog.V(1).Infof("Found image %s in registry with correct Hash", regaddr)
+					plog.Debugf("Found image %s in registry with correct Hash", regaddr)
 					return img, nil
 				}
 			}
@@ -244,12 +243,12 @@ func (l *RegistryListener) FindImage(rImg *registry.Image) (*dockerclient.Image,
 
 	// search all images for a matching hash
 	// First just check top-level layers
-	glog.V(0).Infof("Image %s not found in registry, searching local images by hash", regaddr)
+	plog.Infof("Image %s not found in registry, searching local images by hash", regaddr)
 	if img, err := l.docker.FindImageByHash(rImg.Hash, false); err == nil {
 		return img, nil
 	}
 
 	// Now check all layers
-	glog.V(0).Infof("Hash for Image %s not found in top-level layers, searching all layers", regaddr)
+	plog.Infof("Hash for Image %s not found in top-level layers, searching all layers", regaddr)
 	return l.docker.FindImageByHash(rImg.Hash, true)
 }
@@ -42,12 +42,12 @@ func (_m *RegistryIndex) FindImage(image string) (*registry.Image, error) {
 
 	return r0, r1
 }
-func (_m *RegistryIndex) PushImage(image string, uuid string, hash string) error {
-	ret := _m.Called(image, uuid, hash)
+func (_m *RegistryIndex) PushImage(image string, uuid string, hash string, archs []string) error {
+	ret := _m.Called(image, uuid, hash, archs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(image, uuid, hash)
+	if rf, ok := ret.Get(0).(func(string, string, string, []string) error); ok {
+		r0 = rf(image, uuid, hash, archs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -23,7 +23,6 @@ import (
 	"github.com/control-center/serviced/dfs/docker"
 	"github.com/control-center/serviced/domain/registry"
 	dockerclient "github.com/fsouza/go-dockerclient"
-	"github.com/zenoss/glog"
 )
 
 var (
@@ -55,6 +54,28 @@ func (l *RegistryListener) ImagePath(image string) (string, error) {
 	return path.Join(l.address, rImage.String()), nil
 }
 
+// ImageUUID returns the id of the image as it is currently published in the
+// registry index.
+func (l *RegistryListener) ImageUUID(image string) (string, error) {
+	imageID, err := commons.ParseImageID(image)
+	if err != nil {
+		return "", err
+	}
+	rImage := &registry.Image{
+		Library: imageID.User,
+		Repo:    imageID.Repo,
+		Tag:     imageID.Tag,
+	}
+	if imageID.IsLatest() {
+		rImage.Tag = docker.Latest
+	}
+	var node RegistryImageNode
+	if err := l.conn.Get(path.Join(zkregistrytags, rImage.ID()), &node); err != nil {
+		return "", err
+	}
+	return node.Image.UUID, nil
+}
+
 // PullImage waits for an image to be available on the docker registry so it
 // can be pulled (if it does not exist locally).
 func (l *RegistryListener) PullImage(cancel <-chan time.Time, image string) error {
@@ -80,27 +101,27 @@ func (l *RegistryListener) PullImage(cancel <-chan time.Time, image string) erro
 		evt, err := l.conn.GetW(idpath, &node, done)
 		if err != nil {
 			if err == client.ErrNoNode {
-				glog.Errorf("Image %s not found", regaddr)
+				plog.Errorf("Image %s not found", regaddr)
 			}
 			return err
 		}
 		// check if the image exists locally
-		glog.Infof("Looking up image %s", regaddr)
+		plog.Infof("Looking up image %s", regaddr)
 		if err := l.docker.TagImage(node.Image.UUID, regaddr); docker.IsImageNotFound(err) {
 			// cannot find the image, so let's try to pull
-			glog.Infof("Pulling image %s from the docker registry", regaddr)
+			plog.Infof("Pulling image %s from the docker registry", regaddr)
 			if err := l.docker.PullImage(regaddr); err != nil && !docker.IsImageNotFound(err) {
-				glog.Errorf("Could not pull %s: %s", regaddr, err)
+				plog.Errorf("Could not pull %s: %s", regaddr, err)
 				return err
 			}
 			// was the pull successful?
 			if err := l.docker.TagImage(node.Image.UUID, regaddr); docker.IsImageNotFound(err) {
-				glog.Infof("Image %s not found by ID (%s), comparing hashes", regaddr, node.Image.UUID)
+				plog.Infof("Image %s not found by ID (%s), comparing hashes", regaddr, node.Image.UUID)
 				//IDs may not match, so lets compare hashes
 				if localHash, err := l.docker.GetImageHash(regaddr); err != nil {
-					glog.Warningf("Error building hash of image: %s: %s", regaddr, err)
+					plog.Warningf("Error building hash of image: %s: %s", regaddr, err)
 				} else {
-					glog.V(2).Infof("For image %s, comparing local hash (%s) to master's hash (%s)", regaddr, localHash, node.Image.Hash)
+					plog.Debugf("For image %s, comparing local hash (%s) to master's hash (%s)", regaddr, localHash, node.Image.Hash)
 					if localHash == node.Image.Hash {
 						//if the match, the image we have is current, just return
 						return nil
@@ -115,26 +136,26 @@ func (l *RegistryListener) PullImage(cancel <-chan time.Time, image string) erro
 					// error; let's just ignore those here.
 					node.PushedAt = time.Unix(0, 0)
 					if err := l.conn.Set(idpath, &node); err != nil && err != client.ErrBadVersion {
-						glog.Errorf("Image %s not found in the docker registry: %s", regaddr, err)
+						plog.Errorf("Image %s not found in the docker registry: %s", regaddr, err)
 						return err
 					}
 				}
 			} else if err != nil {
-				glog.Errorf("Could not update tag %s for image %s: %s", regaddr, node.Image.UUID, err)
+				plog.Errorf("Could not update tag %s for image %s: %s", regaddr, node.Image.UUID, err)
 				return err
 			} else {
 				return nil
 			}
 		} else if err != nil {
-			glog.Errorf("Could not update tag %s for image %s: %s", regaddr, node.Image.UUID, err)
+			plog.Errorf("Could not update tag %s for image %s: %s", regaddr, node.Image.UUID, err)
 			return err
 		} else {
 			return nil
 		}
-		glog.Infof("Waiting for image %s to be uploaded into the docker registry (idpath=%s)", regaddr, idpath)
+		plog.Infof("Waiting for image %s to be uploaded into the docker registry (idpath=%s)", regaddr, idpath)
 		select {
 		case e := <-evt:
-			glog.Infof("Got an event: %s", e)
+			plog.Infof("Got an event: %s", e)
 		case <-cancel:
 			return ErrOpTimeout
 		}
@@ -21,7 +21,6 @@ import (
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/dfs/docker"
 	"github.com/control-center/serviced/domain/registry"
-	"github.com/zenoss/glog"
 )
 
 // GetRegistryImage returns the registry image from the coordinator index.
@@ -39,7 +38,7 @@ func SetRegistryImage(conn client.Connection, rImage registry.Image) error {
 	leaderpath := path.Join(zkregistryrepos, rImage.Library, rImage.Repo)
 	leadernode := &RegistryImageLeader{HostID: "master"}
 	if err := conn.CreateDir(leaderpath); err != nil && err != client.ErrNodeExists {
-		glog.Errorf("Could not create repo path %s: %s", leaderpath, err)
+		plog.Errorf("Could not create repo path %s: %s", leaderpath, err)
 		return err
 	}
 	imagepath := path.Join(zkregistrytags, rImage.ID())
@@ -47,7 +46,7 @@ func SetRegistryImage(conn client.Connection, rImage registry.Image) error {
 	if err := conn.Create(imagepath, node); err == client.ErrNodeExists {
 		leader, err := conn.NewLeader(leaderpath)
 		if err != nil {
-			glog.Errorf("Could not establish leader at path %s: %s", leaderpath, err)
+			plog.Errorf("Could not establish leader at path %s: %s", leaderpath, err)
 			return err
 		}
 		leaderDone := make(chan struct{})
@@ -64,7 +63,7 @@ func SetRegistryImage(conn client.Connection, rImage registry.Image) error {
 		node.PushedAt = time.Unix(0, 0)
 		return conn.Set(imagepath, node)
 	} else if err != nil {
-		glog.Errorf("Could not create tag path %s: %s", imagepath, err)
+		plog.Errorf("Could not create tag path %s: %s", imagepath, err)
 		return err
 	}
 	return nil
@@ -74,10 +73,20 @@ func SetRegistryImage(conn client.Connection, rImage registry.Image) error {
 // GetImageUUID gets an image UUID from the respective tags registry path,
 // given an image tag, ex. "kjasd8912833hddhla/core_5.0:latest"
 func GetImageUUID(conn client.Connection, tag string) (string, error) {
-	imageID, err := commons.ParseImageID(tag)
+	rImage, err := FindRegistryImage(conn, tag)
 	if err != nil {
 		return "", err
 	}
+	return rImage.UUID, nil
+}
+
+// FindRegistryImage returns the registry image of an image tag from the
+// coordinator index, ex. "localhost:5000/kjasd8912833hddhla/core_5.0:latest"
+func FindRegistryImage(conn client.Connection, tag string) (*registry.Image, error) {
+	imageID, err := commons.ParseImageID(tag)
+	if err != nil {
+		return nil, err
+	}
 	rImage := &registry.Image{
 		Library: imageID.User,
 		Repo:    imageID.Repo,
@@ -86,13 +95,7 @@ func GetImageUUID(conn client.Connection, tag string) (string, error) {
 	if imageID.IsLatest() {
 		rImage.Tag = docker.Latest
 	}
-	idpath := path.Join(zkregistrytags, rImage.ID())
-
-	var node RegistryImageNode
-	if err = conn.Get(idpath, &node); err != nil {
-		return "", err
-	}
-	return node.Image.UUID, nil
+	return GetRegistryImage(conn, rImage.ID())
 }
 
 // DeleteRegistryImage removes a registry image from the coordinator index.
@@ -21,7 +21,6 @@ import (
 	"strings"
 
 	"github.com/control-center/serviced/volume"
-	"github.com/zenoss/glog"
 )
 
 var (
@@ -30,16 +29,24 @@ var (
 )
 
 // Restore restores application data from a backup.
-func (dfs *DistributedFilesystem) Restore(r io.Reader, version int) error {
-	glog.Infof("Detected backup version %d", version)
-	switch version {
-	case 0:
-		return dfs.restoreV0(r)
-	case 1:
-		return dfs.restoreV1(r)
-	default:
+func (dfs *DistributedFilesystem) Restore(r io.Reader, version int) (err error) {
+	op := startOperation(OpRestore)
+	defer func() { op.done(err) }()
+	defer func() {
+		if err == nil {
+			dfs.releaseQuarantine()
+		} else {
+			plog.Warningf("Data imported by the failed restore has been quarantined")
+		}
+	}()
+
+	r = op.countReader(r)
+	plog.Infof("Detected backup version %d", version)
+	adapter, ok := backupAdapters[version]
+	if !ok {
 		return ErrInvalidBackupVersion
 	}
+	return adapter.restore(dfs, r)
 }
 
 // restoreV0 restores a pre-1.1.3 backup
@@ -54,7 +61,7 @@ func (dfs *DistributedFilesystem) restoreV0(r io.Reader) error {
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			glog.Errorf("Could not read backup file: %s", err)
+			plog.Errorf("Could not read backup file: %s", err)
 			return err
 		}
 
@@ -73,7 +80,7 @@ func (dfs *DistributedFilesystem) restoreV0(r io.Reader) error {
 			// restore the snapshot
 			tenant, label := parts[1], parts[2]
 			if err := dfs.restoreSnapshot(tenant, label, backuptar); err != nil {
-				glog.Errorf("Could not restore snapshot %s for tenant %s: %s", label, tenant, err)
+				plog.Errorf("Could not restore snapshot %s for tenant %s: %s", label, tenant, err)
 				return err
 			}
 
@@ -83,11 +90,11 @@ func (dfs *DistributedFilesystem) restoreV0(r io.Reader) error {
 
 			// Load the images from the docker tar
 			if err := dfs.docker.LoadImage(backuptar); err != nil {
-				glog.Errorf("Could not load docker images: %s", err)
+				plog.Errorf("Could not load docker images: %s", err)
 				return err
 			}
 		default:
-			glog.Warningf("Unrecognized file %s", hdr.Name)
+			plog.Warningf("Unrecognized file %s", hdr.Name)
 		}
 	}
 
@@ -139,7 +146,7 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			glog.Errorf("Could not read backup file: %s", err)
+			plog.Errorf("Could not read backup file: %s", err)
 			dataError = err
 			return err
 		}
@@ -165,7 +172,7 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 			// volume reading from the other end
 			s, ok := streamMap[id]
 			if !ok {
-				glog.Infof("Loading snapshot %s for tenant %s from backup", label, tenant)
+				plog.Infof("Loading snapshot %s for tenant %s from backup", label, tenant)
 				writer, errc := dfs.snapshotLoadPipe(tenant, label)
 				tarwriter := tar.NewWriter(writer)
 				s = &stream{tarwriter: tarwriter, writer: writer, errc: errc}
@@ -176,13 +183,13 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 				// Snapshot already exists, so don't bother
 				continue
 			} else if err != nil {
-				glog.Errorf("Could not write header %s for snapshot %s on tenant %s: %s", hdr.Name, label, tenant, err)
+				plog.Errorf("Could not write header %s for snapshot %s on tenant %s: %s", hdr.Name, label, tenant, err)
 				dataError = err
 				return err
 			}
 
 			if _, err := io.Copy(s.tarwriter, backuptar); err != nil {
-				glog.Errorf("Could not write snapshot %s for tenant %s with header %s: %s", label, tenant, hdr.Name, err)
+				plog.Errorf("Could not write snapshot %s for tenant %s with header %s: %s", label, tenant, hdr.Name, err)
 				dataError = err
 				return err
 			}
@@ -197,7 +204,7 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 			id := parts[0]
 			s, ok := streamMap[id]
 			if !ok {
-				glog.Infof("Loading docker images from backup")
+				plog.Infof("Loading docker images from backup")
 				writer, errc := dfs.imageLoadPipe()
 				tarwriter := tar.NewWriter(writer)
 				s = &stream{tarwriter: tarwriter, writer: writer, errc: errc}
@@ -205,16 +212,16 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 			}
 			hdr.Name = parts[1]
 			if err := s.tarwriter.WriteHeader(hdr); err != nil {
-				glog.Errorf("Could not write image header %s: %s", hdr.Name, err)
+				plog.Errorf("Could not write image header %s: %s", hdr.Name, err)
 				dataError = err
 				return err
 			} else if _, err := io.Copy(s.tarwriter, backuptar); err != nil {
-				glog.Errorf("Could not write image data with header %s: %s", hdr.Name, err)
+				plog.Errorf("Could not write image data with header %s: %s", hdr.Name, err)
 				dataError = err
 				return err
 			}
 		default:
-			glog.Warningf("Unrecognized file %s", hdr.Name)
+			plog.Warningf("Unrecognized file %s", hdr.Name)
 		}
 	}
 
@@ -225,12 +232,12 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 		s.tarwriter.Close()
 		s.writer.Close()
 		if err := <-s.errc; err != nil {
-			glog.Errorf("Could not load docker images from backup: %s", err)
+			plog.Errorf("Could not load docker images from backup: %s", err)
 			dataError = err
 			return err
 		}
 	} else {
-		glog.Warningf("Backup missing docker image data")
+		plog.Warningf("Backup missing docker image data")
 	}
 
 	// load the snapshots and update the images in the registry
@@ -241,7 +248,7 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 		if err := <-s.errc; err != nil {
 			// this snapshot is no good, but maybe the other snapshots are
 			// better.
-			glog.Errorf("Error trying to import %s: %s", id, err)
+			plog.Errorf("Error trying to import %s: %s", id, err)
 			dataError = err
 			continue
 		}
@@ -283,13 +290,14 @@ func (dfs *DistributedFilesystem) restoreSnapshot(tenant, label string, r io.Rea
 	vol, err := dfs.disk.Create(tenant)
 	if err == volume.ErrVolumeExists {
 		if vol, err = dfs.disk.Get(tenant); err != nil {
-			glog.Errorf("Could not get volume for tenant %s: %s", tenant, err)
+			plog.Errorf("Could not get volume for tenant %s: %s", tenant, err)
 			return err
 		}
 	} else if err != nil {
-		glog.Errorf("Could not create volume for tenant %s: %s", tenant, err)
+		plog.Errorf("Could not create volume for tenant %s: %s", tenant, err)
 		return err
 	} else {
+		dfs.quarantineVolume(tenant)
 		defer func() {
 			if err != nil {
 				dfs.disk.Remove(tenant)
@@ -297,12 +305,14 @@ func (dfs *DistributedFilesystem) restoreSnapshot(tenant, label string, r io.Rea
 		}()
 	}
 
-	err = vol.Import(label, r)
+	err = vol.Import(label, volume.NewLimitedReader(r, dfs.restoreIOLimit))
 	if err == volume.ErrSnapshotExists {
 		err = nil // volume.ErrSnapshotExists is an error we can ignore
 	} else if err != nil {
-		glog.Errorf("Could not import snapshot %s for tenant %s: %s", label, tenant, err)
+		plog.Errorf("Could not import snapshot %s for tenant %s: %s", label, tenant, err)
 		return err
+	} else {
+		dfs.quarantineSnapshot(tenant, label)
 	}
 
 	return nil
@@ -313,7 +323,7 @@ func (dfs *DistributedFilesystem) restoreSnapshot(tenant, label string, r io.Rea
 func (dfs *DistributedFilesystem) loadSnapshotImages(tenant, label string) error {
 	vol, err := dfs.disk.Get(tenant)
 	if err != nil {
-		glog.Errorf("Could not get volume for tenant %s: %s", tenant, err)
+		plog.Errorf("Could not get volume for tenant %s: %s", tenant, err)
 		return err
 	}
 
@@ -322,13 +332,13 @@ func (dfs *DistributedFilesystem) loadSnapshotImages(tenant, label string) error
 		r, err := vol.ReadMetadata(label, ImagesMetadataFile)
 		defer r.Close()
 		if err != nil {
-			glog.Errorf("Could not read images metadata from snapshot %s for tenant %s: %s", label, tenant, err)
+			plog.Errorf("Could not read images metadata from snapshot %s for tenant %s: %s", label, tenant, err)
 			return nil, err
 		}
 
 		images := []string{}
 		if err := importJSON(r, &images); err != nil {
-			glog.Errorf("Could not interpret images metadata from snapshot %s for tenant %s: %s", label, tenant, err)
+			plog.Errorf("Could not interpret images metadata from snapshot %s for tenant %s: %s", label, tenant, err)
 			return nil, err
 		}
 		return images, nil
@@ -344,24 +354,25 @@ func (dfs *DistributedFilesystem) loadSnapshotImages(tenant, label string) error
 	for _, image := range images {
 		img, err := dfs.docker.FindImage(image)
 		if err != nil {
-			glog.Warningf("Missing image %s to import to the registry: %s", image, err)
+			plog.Warningf("Missing image %s to import to the registry: %s", image, err)
 			continue
 		}
 
 		hash, err := dfs.docker.GetImageHash(img.ID)
 		if err != nil {
-			glog.Errorf("Could not get hash for image %s: %s", img.ID, err)
+			plog.Errorf("Could not get hash for image %s: %s", img.ID, err)
 			return err
 		}
 
-		if err := dfs.index.PushImage(image, img.ID, hash); err != nil {
-			glog.Errorf("Could not push image %s into the registry: %s", image, err)
+		if err := dfs.pushImage(image, img.ID, hash, imageArchs(img)); err != nil {
+			plog.Errorf("Could not push image %s into the registry: %s", image, err)
 			return err
 		}
-		glog.V(2).Infof("Loaded image %s into the registry", image)
+		dfs.quarantineImage(image)
+		plog.Debugf("Loaded image %s into the registry", image)
 	}
 
-	glog.V(2).Infof("Loaded images from snapshot %s for tenant %s", label, tenant)
+	plog.Debugf("Loaded images from snapshot %s for tenant %s", label, tenant)
 	return nil
 }
 
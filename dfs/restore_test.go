@@ -213,7 +213,7 @@ func (s *DFSTestSuite) TestRestore_ImportSnapshotImageNoPush(c *C) {
 	vol.On("ReadMetadata", "LABEL", ImagesMetadataFile).Return(&NopCloser{imgbuffer}, nil)
 	s.docker.On("FindImage", "test:5000/image:now").Return(&dockerclient.Image{ID: "someimageid"}, nil)
 	s.docker.On("GetImageHash", "someimageid").Return("hashvalue", nil)
-	s.index.On("PushImage", "test:5000/image:now", "someimageid", "hashvalue").Return(ErrTestNoPush)
+	s.index.On("PushImage", "test:5000/image:now", "someimageid", "hashvalue", []string(nil)).Return(ErrTestNoPush)
 	err = s.dfs.Restore(buf, backupInfo.BackupVersion)
 	c.Assert(err, Equals, ErrTestNoPush)
 	s.disk.AssertExpectations(c)
@@ -0,0 +1,162 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/control-center/serviced/domain/servicetemplate"
+)
+
+// Names of the compatibility checks run before a restore
+const (
+	CheckBackupVersion   = "backup-version"
+	CheckDockerVersion   = "docker-version"
+	CheckDriverType      = "driver-type"
+	CheckTemplateVersion = "template-version"
+
+	// CheckAll overrides every check that can be overridden
+	CheckAll = "all"
+)
+
+// RestoreOptions describes the system a backup is being restored onto
+type RestoreOptions struct {
+	Templates []servicetemplate.ServiceTemplate // templates installed on this system
+	Force     []string                          // checks to override
+}
+
+// CompatibilityIssue is a difference between a backup and the system that
+// prevents it from being restored
+type CompatibilityIssue struct {
+	Check   string
+	Message string
+}
+
+// CompatibilityError is the report of all the issues that prevent a backup
+// from being restored
+type CompatibilityError struct {
+	Issues []CompatibilityIssue
+}
+
+func (err *CompatibilityError) Error() string {
+	buf := bytes.NewBufferString("backup is not compatible with this system:")
+	forceable := false
+	for _, issue := range err.Issues {
+		fmt.Fprintf(buf, "\n  %s: %s", issue.Check, issue.Message)
+		if issue.Check != CheckBackupVersion {
+			forceable = true
+		}
+	}
+	if forceable {
+		buf.WriteString("\nuse --force CHECK to restore anyway")
+	}
+	return buf.String()
+}
+
+// CheckRestore compares the backup with the docker version, volume driver,
+// and service templates of this system, and returns a CompatibilityError
+// describing every check that fails and has not been forced.  The backup
+// version check cannot be forced.
+func (dfs *DistributedFilesystem) CheckRestore(info BackupInfo, opts RestoreOptions) error {
+	forced := make(map[string]bool)
+	for _, check := range opts.Force {
+		forced[check] = true
+	}
+	var issues []CompatibilityIssue
+	report := func(check, format string, args ...interface{}) {
+		message := fmt.Sprintf(format, args...)
+		if check != CheckBackupVersion && (forced[check] || forced[CheckAll]) {
+			plog.Warningf("Ignoring failed restore check %s: %s", check, message)
+			return
+		}
+		issues = append(issues, CompatibilityIssue{Check: check, Message: message})
+	}
+
+	if _, ok := backupAdapters[info.BackupVersion]; !ok {
+		report(CheckBackupVersion, "backup version %d is not supported; this system restores versions %v", info.BackupVersion, SupportedBackupVersions())
+	}
+
+	// backups created before these checks existed did not record the docker
+	// version or the driver type
+	if info.DockerVersion != "" {
+		if version, err := dfs.docker.Version(); err != nil {
+			plog.Errorf("Could not get the docker version: %s", err)
+			return err
+		} else if compareVersions(info.DockerVersion, version) > 0 {
+			report(CheckDockerVersion, "images were saved by docker %s, which is newer than docker %s on this host", info.DockerVersion, version)
+		}
+	}
+	if info.DriverType != "" {
+		if driverType := dfs.disk.DriverType(); info.DriverType != driverType {
+			report(CheckDriverType, "snapshots were exported by the %s volume driver, but this host uses %s", info.DriverType, driverType)
+		}
+	}
+
+	installed := make(map[string]servicetemplate.ServiceTemplate)
+	for _, template := range opts.Templates {
+		installed[template.ID] = template
+	}
+	for _, template := range info.Templates {
+		if current, ok := installed[template.ID]; ok && compareVersions(current.Version, template.Version) > 0 {
+			report(CheckTemplateVersion, "template %s would be downgraded from version %s to %s", template.Name, current.Version, template.Version)
+		}
+	}
+
+	if len(issues) > 0 {
+		return &CompatibilityError{Issues: issues}
+	}
+	return nil
+}
+
+// compareVersions compares the numeric components of two dotted versions,
+// ignoring any suffix such as "-ce", and returns -1, 0, or 1.
+func compareVersions(a, b string) int {
+	parse := func(version string) []int {
+		var parts []int
+		for _, field := range strings.Split(version, ".") {
+			end := 0
+			for end < len(field) && field[end] >= '0' && field[end] <= '9' {
+				end++
+			}
+			n, err := strconv.Atoi(field[:end])
+			if err != nil {
+				break
+			}
+			parts = append(parts, n)
+			if end < len(field) {
+				break
+			}
+		}
+		return parts
+	}
+	va, vb := parse(a), parse(b)
+	for i := 0; i < len(va) || i < len(vb); i++ {
+		var x, y int
+		if i < len(va) {
+			x = va[i]
+		}
+		if i < len(vb) {
+			y = vb[i]
+		}
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+	}
+	return 0
+}
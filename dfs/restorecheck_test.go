@@ -0,0 +1,96 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"strings"
+
+	. "github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	volumemocks "github.com/control-center/serviced/volume/mocks"
+	. "gopkg.in/check.v1"
+)
+
+func (s *DFSTestSuite) TestCheckRestore_Compatible(c *C) {
+	s.docker.On("Version").Return("1.12.6", nil)
+	info := BackupInfo{
+		BackupVersion: 1,
+		DockerVersion: "1.12.1",
+		DriverType:    volumemocks.DriverName,
+		Templates:     []servicetemplate.ServiceTemplate{{ID: "tmpl", Name: "Zenoss.core", Version: "5.2.0"}},
+	}
+	opts := RestoreOptions{
+		Templates: []servicetemplate.ServiceTemplate{{ID: "tmpl", Name: "Zenoss.core", Version: "5.2.0"}},
+	}
+	c.Assert(s.dfs.CheckRestore(info, opts), IsNil)
+}
+
+func (s *DFSTestSuite) TestCheckRestore_OldBackup(c *C) {
+	// backups made before the checks were added do not record their
+	// environment
+	c.Assert(s.dfs.CheckRestore(BackupInfo{BackupVersion: 0}, RestoreOptions{}), IsNil)
+	s.docker.AssertNotCalled(c, "Version")
+}
+
+func (s *DFSTestSuite) TestCheckRestore_DockerError(c *C) {
+	s.docker.On("Version").Return("", ErrTestGeneric)
+	err := s.dfs.CheckRestore(BackupInfo{BackupVersion: 1, DockerVersion: "1.12.6"}, RestoreOptions{})
+	c.Assert(err, Equals, ErrTestGeneric)
+}
+
+func (s *DFSTestSuite) TestCheckRestore_Incompatible(c *C) {
+	s.docker.On("Version").Return("1.12.6", nil)
+	info := BackupInfo{
+		BackupVersion: 2,
+		DockerVersion: "17.03.1-ce",
+		DriverType:    "btrfs",
+		Templates:     []servicetemplate.ServiceTemplate{{ID: "tmpl", Name: "Zenoss.core", Version: "5.1.9"}},
+	}
+	opts := RestoreOptions{
+		Templates: []servicetemplate.ServiceTemplate{{ID: "tmpl", Name: "Zenoss.core", Version: "5.2.0"}},
+	}
+	err := s.dfs.CheckRestore(info, opts)
+	c.Assert(err, NotNil)
+	cerr, ok := err.(*CompatibilityError)
+	c.Assert(ok, Equals, true)
+	checks := make([]string, len(cerr.Issues))
+	for i, issue := range cerr.Issues {
+		checks[i] = issue.Check
+	}
+	c.Assert(checks, DeepEquals, []string{CheckBackupVersion, CheckDockerVersion, CheckDriverType, CheckTemplateVersion})
+	c.Assert(strings.Contains(err.Error(), "template Zenoss.core would be downgraded from version 5.2.0 to 5.1.9"), Equals, true)
+	c.Assert(strings.Contains(err.Error(), "--force"), Equals, true)
+
+	// the backup version cannot be forced
+	opts.Force = []string{CheckAll}
+	err = s.dfs.CheckRestore(info, opts)
+	cerr, ok = err.(*CompatibilityError)
+	c.Assert(ok, Equals, true)
+	c.Assert(cerr.Issues, HasLen, 1)
+	c.Assert(cerr.Issues[0].Check, Equals, CheckBackupVersion)
+	c.Assert(strings.Contains(err.Error(), "--force"), Equals, false)
+
+	info.BackupVersion = 1
+	opts.Force = []string{CheckDockerVersion, CheckDriverType}
+	err = s.dfs.CheckRestore(info, opts)
+	cerr, ok = err.(*CompatibilityError)
+	c.Assert(ok, Equals, true)
+	c.Assert(cerr.Issues, HasLen, 1)
+	c.Assert(cerr.Issues[0].Check, Equals, CheckTemplateVersion)
+
+	opts.Force = append(opts.Force, CheckTemplateVersion)
+	c.Assert(s.dfs.CheckRestore(info, opts), IsNil)
+}
@@ -15,7 +15,6 @@ package dfs
 
 import (
 	"github.com/control-center/serviced/dfs/docker"
-	"github.com/zenoss/glog"
 )
 
 // Rollback reverts an application to a previous snapshot.
@@ -27,33 +26,33 @@ func (dfs *DistributedFilesystem) Rollback(snapshotID string) error {
 	// do all the images exist in the registry?
 	r, err := vol.ReadMetadata(info.Label, ImagesMetadataFile)
 	if err != nil {
-		glog.Errorf("Could not receive images metadata from snapshot %s: %s", snapshotID, err)
+		plog.Errorf("Could not receive images metadata from snapshot %s: %s", snapshotID, err)
 		return err
 	}
 	var images []string
 	if err := importJSON(r, &images); err != nil {
-		glog.Errorf("Could not interpret images metadata file from snapshot %s: %s", snapshotID, err)
+		plog.Errorf("Could not interpret images metadata file from snapshot %s: %s", snapshotID, err)
 		return err
 	}
 	for _, image := range images {
 		rImage, err := dfs.index.FindImage(image)
 		if err != nil {
-			glog.Errorf("Could not find image %s from snapshot %s: %s", image, snapshotID, err)
+			plog.Errorf("Could not find image %s from snapshot %s: %s", image, snapshotID, err)
 			return err
 		}
 		rImage.Tag = docker.Latest
-		if err := dfs.index.PushImage(rImage.String(), rImage.UUID, rImage.Hash); err != nil {
-			glog.Errorf("Could not update image %s from snapshot %s in the registry: %s", image, snapshotID, err)
+		if err := dfs.pushImage(rImage.String(), rImage.UUID, rImage.Hash, rImage.Archs); err != nil {
+			plog.Errorf("Could not update image %s from snapshot %s in the registry: %s", image, snapshotID, err)
 			return err
 		}
 	}
 	if err := dfs.unexport(vol.Path()); err != nil {
-		glog.Errorf("Could not unexport path %s: %s", vol.Path(), err)
+		plog.Errorf("Could not unexport path %s: %s", vol.Path(), err)
 		return err
 	}
 	defer dfs.export(vol.Path())
 	if err := vol.Rollback(info.Label); err != nil {
-		glog.Errorf("Could not rollback snapshot %s for tenant %s: %s", snapshotID, info.TenantID, err)
+		plog.Errorf("Could not rollback snapshot %s for tenant %s: %s", snapshotID, info.TenantID, err)
 		return err
 	}
 	return nil
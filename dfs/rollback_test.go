@@ -78,7 +78,7 @@ func (s *DFSTestSuite) TestRollback_ImageNoPush(c *C) {
 		Hash:    "hashvalue",
 	}
 	s.index.On("FindImage", "BASE/repo:LABEL").Return(rImage, nil).Once()
-	s.index.On("PushImage", "BASE/repo:latest", "testuuid", "hashvalue").Return(ErrTestNoPush)
+	s.index.On("PushImage", "BASE/repo:latest", "testuuid", "hashvalue", []string(nil)).Return(ErrTestNoPush)
 	err = s.dfs.Rollback("BASE_LABEL")
 	c.Assert(err, Equals, ErrTestNoPush)
 }
@@ -106,7 +106,7 @@ func (s *DFSTestSuite) TestRollback_Success(c *C) {
 	vol.On("SnapshotInfo", "BASE_LABEL").Return(vinfo, nil)
 	vol.On("ReadMetadata", "LABEL", ImagesMetadataFile).Return(&NopCloser{vimagesbuf}, nil)
 	s.index.On("FindImage", "BASE/repo:LABEL").Return(rImage, nil)
-	s.index.On("PushImage", "BASE/repo:latest", "testuuid", "hashvalue").Return(nil)
+	s.index.On("PushImage", "BASE/repo:latest", "testuuid", "hashvalue", []string(nil)).Return(nil)
 	s.net.On("AddVolume", "/path/to/tenantID").Return(nil)
 	s.net.On("RemoveVolume", "/path/to/tenantID").Return(nil)
 	s.net.On("Stop").Return(nil)
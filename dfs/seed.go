@@ -0,0 +1,81 @@
+// Copyright 2015 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/control-center/serviced/volume"
+)
+
+// Seed copies the volume data of a snapshot into the volume of another
+// tenant, without its metadata or images.
+func (dfs *DistributedFilesystem) Seed(snapshotID, tenantID string) error {
+	srcVol, info, err := dfs.getSnapshotVolumeAndInfo(snapshotID)
+	if err != nil {
+		return err
+	}
+	dstVol, err := dfs.disk.Get(tenantID)
+	if err != nil {
+		plog.Errorf("Could not get volume for tenant %s: %s", tenantID, err)
+		return err
+	}
+
+	snapReader, errchan := dfs.snapshotSavePipe(srcVol, info.Label, nil)
+	if err := importVolumeData(snapReader, dstVol.Path()); err != nil {
+		// be a good citizen and clean up any running threads
+		<-errchan
+		plog.Errorf("Could not seed volume of tenant %s from snapshot %s: %s", tenantID, snapshotID, err)
+		return err
+	} else if err := <-errchan; err != nil {
+		plog.Errorf("Could not export snapshot %s: %s", snapshotID, err)
+		return err
+	}
+	plog.Infof("Seeded volume of tenant %s from snapshot %s", tenantID, snapshotID)
+	return nil
+}
+
+// importVolumeData extracts the volume directory of a snapshot export into
+// path.  Volume drivers export the data of a snapshot under a directory
+// named for the snapshot label, followed by "-volume".
+func importVolumeData(r *io.PipeReader, path string) error {
+	defer r.Close()
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			// drain any padding so the exporter is not left blocked
+			_, err = io.Copy(ioutil.Discard, r)
+			return err
+		} else if err != nil {
+			return err
+		}
+		parts := strings.SplitN(strings.TrimPrefix(header.Name, "/"), "/", 2)
+		if !strings.HasSuffix(parts[0], "-volume") || len(parts) < 2 || strings.Trim(parts[1], "/") == "" {
+			continue
+		}
+		header.Name = parts[1]
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(path, header.Name)), 0755); err != nil {
+			return err
+		}
+		if err := volume.ImportArchiveHeader(header, tarReader, path); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2015 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/control-center/serviced/volume"
+	volumemocks "github.com/control-center/serviced/volume/mocks"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (s *DFSTestSuite) TestSeed_NoSnapshot(c *C) {
+	s.disk.On("GetTenant", "BASE_LABEL").Return(&volumemocks.Volume{}, ErrTestVolumeNotFound)
+	err := s.dfs.Seed("BASE_LABEL", "CLONE")
+	c.Assert(err, Equals, ErrTestVolumeNotFound)
+}
+
+func (s *DFSTestSuite) TestSeed_NoTenantVolume(c *C) {
+	vol := &volumemocks.Volume{}
+	s.disk.On("GetTenant", "BASE_LABEL").Return(vol, nil)
+	vol.On("SnapshotInfo", "BASE_LABEL").Return(&volume.SnapshotInfo{Name: "BASE_LABEL", TenantID: "BASE", Label: "LABEL"}, nil)
+	s.disk.On("Get", "CLONE").Return(&volumemocks.Volume{}, ErrTestVolumeNotFound)
+	err := s.dfs.Seed("BASE_LABEL", "CLONE")
+	c.Assert(err, Equals, ErrTestVolumeNotFound)
+}
+
+func (s *DFSTestSuite) TestSeed_ExportFailed(c *C) {
+	srcVol := &volumemocks.Volume{}
+	s.disk.On("GetTenant", "BASE_LABEL").Return(srcVol, nil)
+	srcVol.On("SnapshotInfo", "BASE_LABEL").Return(&volume.SnapshotInfo{Name: "BASE_LABEL", TenantID: "BASE", Label: "LABEL"}, nil)
+	dstVol := &volumemocks.Volume{}
+	s.disk.On("Get", "CLONE").Return(dstVol, nil)
+	dstVol.On("Path").Return(c.MkDir())
+	expErr := errors.New("export failed")
+	srcVol.On("Export", "LABEL", "", mock.AnythingOfType("*io.PipeWriter")).Return(expErr)
+	err := s.dfs.Seed("BASE_LABEL", "CLONE")
+	c.Assert(err, Equals, expErr)
+}
+
+func (s *DFSTestSuite) TestSeed_Success(c *C) {
+	srcVol := &volumemocks.Volume{}
+	s.disk.On("GetTenant", "BASE_LABEL").Return(srcVol, nil)
+	srcVol.On("SnapshotInfo", "BASE_LABEL").Return(&volume.SnapshotInfo{Name: "BASE_LABEL", TenantID: "BASE", Label: "LABEL"}, nil)
+	dstVol := &volumemocks.Volume{}
+	s.disk.On("Get", "CLONE").Return(dstVol, nil)
+	dstPath := c.MkDir()
+	dstVol.On("Path").Return(dstPath)
+	srcVol.On("Export", "LABEL", "", mock.AnythingOfType("*io.PipeWriter")).Return(nil).Run(func(a mock.Arguments) {
+		tarWriter := tar.NewWriter(a.Get(2).(io.Writer))
+		defer tarWriter.Close()
+		files := []struct {
+			name string
+			data string
+		}{
+			{"LABEL-metadata/images.json", "[]"},
+			{"LABEL-volume/app/data.txt", "volume data"},
+		}
+		for _, f := range files {
+			err := tarWriter.WriteHeader(&tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.data)), Typeflag: tar.TypeReg})
+			c.Assert(err, IsNil)
+			_, err = tarWriter.Write([]byte(f.data))
+			c.Assert(err, IsNil)
+		}
+	})
+	err := s.dfs.Seed("BASE_LABEL", "CLONE")
+	c.Assert(err, IsNil)
+	data, err := ioutil.ReadFile(filepath.Join(dstPath, "app", "data.txt"))
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "volume data")
+	_, err = os.Stat(filepath.Join(dstPath, "images.json"))
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
@@ -18,12 +18,12 @@ import (
 
 	"errors"
 	"github.com/control-center/serviced/volume"
-	"github.com/zenoss/glog"
 )
 
 const (
-	ServicesMetadataFile = "./.snapshot/services.json"
-	ImagesMetadataFile   = "./.snapshot/images.json"
+	ServicesMetadataFile   = "./.snapshot/services.json"
+	ImagesMetadataFile     = "./.snapshot/images.json"
+	ProvenanceMetadataFile = "./.snapshot/provenance.json"
 )
 
 var (
@@ -31,14 +31,17 @@ var (
 )
 
 // Snapshot saves the current state of a particular application
-func (dfs *DistributedFilesystem) Snapshot(data SnapshotInfo, spaceFactor int) (string, error) {
+func (dfs *DistributedFilesystem) Snapshot(data SnapshotInfo, spaceFactor int) (snapshotID string, err error) {
+	op := startOperation(OpSnapshot)
+	defer func() { op.done(err) }()
+
 	label := generateSnapshotLabel()
 	vol, err := dfs.disk.Get(data.TenantID)
 
 	if volume.DriverTypeDeviceMapper == dfs.disk.DriverType() {
 		freeSpace, err := ensureFreeSpace(vol, dfs, spaceFactor)
 		if err != nil {
-			glog.Errorf("Could not determine freespace on devicemapper device %s", err)
+			plog.Errorf("Could not determine freespace on devicemapper device %s", err)
 			return "", err
 		}
 		if !freeSpace {
@@ -46,7 +49,7 @@ func (dfs *DistributedFilesystem) Snapshot(data SnapshotInfo, spaceFactor int) (
 		}
 	}
 	if err != nil {
-		glog.Errorf("Could not get volume for tenant %s: %s", data.TenantID, err)
+		plog.Errorf("Could not get volume for tenant %s: %s", data.TenantID, err)
 		return "", err
 	}
 	// relabel all registry tags for this snapshot
@@ -54,24 +57,24 @@ func (dfs *DistributedFilesystem) Snapshot(data SnapshotInfo, spaceFactor int) (
 	for i, image := range data.Images {
 		rImage, err := dfs.index.FindImage(image)
 		if err != nil {
-			glog.Errorf("Could not find image %s for snapshot: %s", image, err)
+			plog.Errorf("Could not find image %s for snapshot: %s", image, err)
 			return "", err
 		}
 
 		// make sure we actually have the image locally before changing the tag and triggering a push
 		if _, err := dfs.reg.FindImage(rImage); err != nil {
-			glog.Errorf("Could not find image %s locally for snapshot:  %s", rImage.String(), err)
+			plog.Errorf("Could not find image %s locally for snapshot:  %s", rImage.String(), err)
 			return "", err
 		}
 
 		rImage.Tag = label
-		if err := dfs.index.PushImage(rImage.String(), rImage.UUID, rImage.Hash); err != nil {
-			glog.Errorf("Could not retag image %s for snapshot: %s", image, err)
+		if err := dfs.pushImage(rImage.String(), rImage.UUID, rImage.Hash, rImage.Archs); err != nil {
+			plog.Errorf("Could not retag image %s for snapshot: %s", image, err)
 			return "", err
 		}
 		fullImagePath, err := dfs.reg.ImagePath(rImage.String())
 		if err != nil {
-			glog.Errorf("Could not get the full image path for image %s: %s", image, err)
+			plog.Errorf("Could not get the full image path for image %s: %s", image, err)
 			return "", err
 		}
 		images[i] = fullImagePath
@@ -79,30 +82,41 @@ func (dfs *DistributedFilesystem) Snapshot(data SnapshotInfo, spaceFactor int) (
 	// write snapshot metadata
 	w, err := vol.WriteMetadata(label, ImagesMetadataFile)
 	if err != nil {
-		glog.Errorf("Could not create image metadata file for tenant %s: %s", data.TenantID, err)
+		plog.Errorf("Could not create image metadata file for tenant %s: %s", data.TenantID, err)
 		return "", err
 	}
 	if err := exportJSON(w, images); err != nil {
-		glog.Errorf("Could not write service metadata file for tenant %s: %s", data.TenantID, err)
+		plog.Errorf("Could not write service metadata file for tenant %s: %s", data.TenantID, err)
 		return "", err
 	}
 	w, err = vol.WriteMetadata(label, ServicesMetadataFile)
 	if err != nil {
-		glog.Errorf("Could not create service metadata file for tenant %s: %s", data.TenantID, err)
+		plog.Errorf("Could not create service metadata file for tenant %s: %s", data.TenantID, err)
 		return "", err
 	}
 	if err := exportJSON(w, data.Services); err != nil {
-		glog.Errorf("Could not write service metadata file for tenant %s: %s", data.TenantID, err)
+		plog.Errorf("Could not write service metadata file for tenant %s: %s", data.TenantID, err)
+		return "", err
+	}
+	provenance := data.Provenance
+	provenance.ImageIDs = data.Images
+	w, err = vol.WriteMetadata(label, ProvenanceMetadataFile)
+	if err != nil {
+		plog.Errorf("Could not create provenance metadata file for tenant %s: %s", data.TenantID, err)
+		return "", err
+	}
+	if err := exportJSON(w, provenance); err != nil {
+		plog.Errorf("Could not write provenance metadata file for tenant %s: %s", data.TenantID, err)
 		return "", err
 	}
 	// snapshot the volume
 	if err := vol.Snapshot(label, data.Message, data.Tags); err != nil {
-		glog.Errorf("Could not snapshot volume for tenant %s: %s", data.TenantID, err)
+		plog.Errorf("Could not snapshot volume for tenant %s: %s", data.TenantID, err)
 		return "", err
 	}
 	info, err := vol.SnapshotInfo(label)
 	if err != nil {
-		glog.Errorf("Could not get info for snapshot %s of tenant %s: %s", label, data.TenantID, err)
+		plog.Errorf("Could not get info for snapshot %s of tenant %s: %s", label, data.TenantID, err)
 		return "", err
 	}
 	return info.Name, nil
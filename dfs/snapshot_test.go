@@ -67,7 +67,7 @@ func (s *DFSTestSuite) TestSnapshot_NoPush(c *C) {
 	}
 	s.index.On("FindImage", "BASE/repo:latest").Return(rImage, nil)
 	s.registry.On("FindImage", rImage).Return(&dockerclient.Image{}, nil).Once()
-	s.index.On("PushImage", mock.AnythingOfType("string"), "testuuid", "hashvalue").Return(ErrTestNoPush).Run(func(a mock.Arguments) {
+	s.index.On("PushImage", mock.AnythingOfType("string"), "testuuid", "hashvalue", []string(nil)).Return(ErrTestNoPush).Run(func(a mock.Arguments) {
 		newRegistryImage := a.Get(0).(string)
 		c.Assert(strings.HasPrefix(newRegistryImage, "BASE/repo:"), Equals, true)
 	})
@@ -123,7 +123,7 @@ func (s *DFSTestSuite) TestSnapshot_NoWriteMetadata(c *C) {
 	s.disk.On("Get", "BASE").Return(vol, nil)
 	s.index.On("FindImage", "BASE/repo:latest").Return(rImage, nil)
 	s.registry.On("FindImage", rImage).Return(&dockerclient.Image{}, nil)
-	s.index.On("PushImage", mock.AnythingOfType("string"), "testuuid", "hashvalue").Return(nil).Run(func(a mock.Arguments) {
+	s.index.On("PushImage", mock.AnythingOfType("string"), "testuuid", "hashvalue", []string(nil)).Return(nil).Run(func(a mock.Arguments) {
 		newRegistryImage := a.Get(0).(string)
 		c.Assert(strings.HasPrefix(newRegistryImage, "BASE/repo:"), Equals, true)
 		s.registry.On("ImagePath", newRegistryImage).Return("test:5000/"+newRegistryImage, nil)
@@ -162,13 +162,14 @@ func (s *DFSTestSuite) TestSnapshot_NoSnapshot(c *C) {
 	s.disk.On("Get", "BASE").Return(vol, nil)
 	s.index.On("FindImage", "BASE/repo:latest").Return(rImage, nil)
 	s.registry.On("FindImage", rImage).Return(&dockerclient.Image{}, nil)
-	s.index.On("PushImage", mock.AnythingOfType("string"), "testuuid", "hashvalue").Return(nil).Run(func(a mock.Arguments) {
+	s.index.On("PushImage", mock.AnythingOfType("string"), "testuuid", "hashvalue", []string(nil)).Return(nil).Run(func(a mock.Arguments) {
 		newRegistryImage := a.Get(0).(string)
 		c.Assert(strings.HasPrefix(newRegistryImage, "BASE/repo:"), Equals, true)
 		s.registry.On("ImagePath", newRegistryImage).Return("test:5000/"+newRegistryImage, nil)
 	})
 	vol.On("WriteMetadata", mock.AnythingOfType("string"), ImagesMetadataFile).Return(&NopCloser{bytes.NewBufferString("")}, nil)
 	vol.On("WriteMetadata", mock.AnythingOfType("string"), ServicesMetadataFile).Return(&NopCloser{bytes.NewBufferString("")}, nil)
+	vol.On("WriteMetadata", mock.AnythingOfType("string"), ProvenanceMetadataFile).Return(&NopCloser{bytes.NewBufferString("")}, nil)
 	vol.On("Snapshot", mock.AnythingOfType("string"), data.Message, data.Tags).Return(ErrTestSnapshotNotCreated).Once()
 	id, err := s.dfs.Snapshot(data, 100)
 	c.Assert(id, Equals, "")
@@ -190,6 +191,7 @@ func (s *DFSTestSuite) TestSnapshot_Success(c *C) {
 		Services: []service.Service{
 			{ID: "test-service", CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()},
 		},
+		Provenance: SnapshotProvenance{User: "zenoss", Source: SnapshotSourceShell},
 	}
 	vol := &volumemocks.Volume{}
 	rImage := &registry.Image{
@@ -202,15 +204,17 @@ func (s *DFSTestSuite) TestSnapshot_Success(c *C) {
 	s.disk.On("Get", "BASE").Return(vol, nil)
 	s.index.On("FindImage", "BASE/repo:latest").Return(rImage, nil)
 	s.registry.On("FindImage", rImage).Return(&dockerclient.Image{}, nil).Once()
-	s.index.On("PushImage", mock.AnythingOfType("string"), "testuuid", "hashvalue").Return(nil).Run(func(a mock.Arguments) {
+	s.index.On("PushImage", mock.AnythingOfType("string"), "testuuid", "hashvalue", []string(nil)).Return(nil).Run(func(a mock.Arguments) {
 		newRegistryImage := a.Get(0).(string)
 		c.Assert(strings.HasPrefix(newRegistryImage, "BASE/repo:"), Equals, true)
 		s.registry.On("ImagePath", newRegistryImage).Return("test:5000/"+newRegistryImage, nil)
 	})
 	imagesBuffer := bytes.NewBufferString("")
 	servicesBuffer := bytes.NewBufferString("")
+	provenanceBuffer := bytes.NewBufferString("")
 	vol.On("WriteMetadata", mock.AnythingOfType("string"), ImagesMetadataFile).Return(&NopCloser{imagesBuffer}, nil)
 	vol.On("WriteMetadata", mock.AnythingOfType("string"), ServicesMetadataFile).Return(&NopCloser{servicesBuffer}, nil)
+	vol.On("WriteMetadata", mock.AnythingOfType("string"), ProvenanceMetadataFile).Return(&NopCloser{provenanceBuffer}, nil)
 	var name string
 	vol.On("Snapshot", mock.AnythingOfType("string"), data.Message, data.Tags).Return(nil).Run(func(a mock.Arguments) {
 		label := a.Get(0).(string)
@@ -223,6 +227,14 @@ func (s *DFSTestSuite) TestSnapshot_Success(c *C) {
 		err = json.NewDecoder(servicesBuffer).Decode(&actualServices)
 		c.Assert(err, IsNil)
 		c.Assert(actualServices, DeepEquals, data.Services)
+		var actualProvenance SnapshotProvenance
+		err = json.NewDecoder(provenanceBuffer).Decode(&actualProvenance)
+		c.Assert(err, IsNil)
+		c.Assert(actualProvenance, DeepEquals, SnapshotProvenance{
+			User:     "zenoss",
+			Source:   SnapshotSourceShell,
+			ImageIDs: []string{"BASE/repo:latest"},
+		})
 
 		sInfo := volume.SnapshotInfo{
 			Name:     name,
@@ -0,0 +1,192 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// Names of the instrumented dfs operations
+const (
+	OpSnapshot     = "snapshot"
+	OpCommit       = "commit"
+	OpBackup       = "backup"
+	OpRestore      = "restore"
+	OpRegistryPush = "registry.push"
+	OpRegistryPull = "registry.pull"
+)
+
+var operations = []string{OpSnapshot, OpCommit, OpBackup, OpRestore, OpRegistryPush, OpRegistryPull}
+
+// Metrics is the registry of the latency, throughput, and concurrency of dfs
+// operations on this host.
+var Metrics = metrics.NewRegistry()
+
+// OperationStats summarizes the metrics of a dfs operation
+type OperationStats struct {
+	Operation  string
+	Count      int64 // completed operations
+	Errors     int64 // completed operations that failed
+	Active     int64 // operations in progress
+	Mean       time.Duration
+	P95        time.Duration
+	Max        time.Duration
+	Bytes      int64   // bytes transferred by completed operations
+	Throughput float64 // bytes per second of completed operations
+}
+
+// Stats summarizes the metrics of all dfs operations
+type Stats struct {
+	Operations  []OperationStats
+	LockWaiting int64 // operations waiting to acquire the dfs lock
+}
+
+func metricName(op, metric string) string {
+	return fmt.Sprintf("dfs.%s.%s", op, metric)
+}
+
+// GetStats returns the metrics of all dfs operations
+func GetStats() Stats {
+	stats := Stats{
+		Operations:  make([]OperationStats, len(operations)),
+		LockWaiting: metrics.GetOrRegisterCounter("dfs.lock.waiting", Metrics).Count(),
+	}
+	for i, op := range operations {
+		timer := metrics.GetOrRegisterTimer(metricName(op, "duration"), Metrics).Snapshot()
+		s := OperationStats{
+			Operation: op,
+			Count:     timer.Count(),
+			Errors:    metrics.GetOrRegisterCounter(metricName(op, "errors"), Metrics).Count(),
+			Active:    metrics.GetOrRegisterCounter(metricName(op, "active"), Metrics).Count(),
+			Mean:      time.Duration(timer.Mean()),
+			P95:       time.Duration(timer.Percentile(0.95)),
+			Max:       time.Duration(timer.Max()),
+			Bytes:     metrics.GetOrRegisterCounter(metricName(op, "bytes"), Metrics).Count(),
+		}
+		if elapsed := time.Duration(timer.Sum()).Seconds(); elapsed > 0 {
+			s.Throughput = float64(s.Bytes) / elapsed
+		}
+		stats.Operations[i] = s
+	}
+	return stats
+}
+
+// operation measures a single dfs operation
+type operation struct {
+	name  string
+	start time.Time
+	bytes int64
+}
+
+// startOperation marks an operation as in progress
+func startOperation(name string) *operation {
+	metrics.GetOrRegisterCounter(metricName(name, "active"), Metrics).Inc(1)
+	return &operation{name: name, start: time.Now()}
+}
+
+// done records the duration and outcome of the operation
+func (op *operation) done(err error) {
+	metrics.GetOrRegisterCounter(metricName(op.name, "active"), Metrics).Dec(1)
+	metrics.GetOrRegisterTimer(metricName(op.name, "duration"), Metrics).UpdateSince(op.start)
+	metrics.GetOrRegisterCounter(metricName(op.name, "bytes"), Metrics).Inc(atomic.LoadInt64(&op.bytes))
+	if err != nil {
+		metrics.GetOrRegisterCounter(metricName(op.name, "errors"), Metrics).Inc(1)
+	}
+}
+
+// Write counts the bytes written by the operation
+func (op *operation) Write(p []byte) (int, error) {
+	atomic.AddInt64(&op.bytes, int64(len(p)))
+	return len(p), nil
+}
+
+// countWriter counts the bytes written to w as transferred by the operation
+func (op *operation) countWriter(w io.Writer) io.Writer {
+	return io.MultiWriter(w, op)
+}
+
+// countReader counts the bytes read from r as transferred by the operation
+func (op *operation) countReader(r io.Reader) io.Reader {
+	return io.TeeReader(r, op)
+}
+
+// WritePrometheus writes the stats in the Prometheus text exposition format
+func WritePrometheus(w io.Writer, stats Stats) error {
+	type metric struct {
+		name, help, kind string
+		value            func(s OperationStats) float64
+	}
+	opMetrics := []metric{
+		{"serviced_dfs_operations_total", "Completed dfs operations.", "counter", func(s OperationStats) float64 { return float64(s.Count) }},
+		{"serviced_dfs_operation_errors_total", "Completed dfs operations that failed.", "counter", func(s OperationStats) float64 { return float64(s.Errors) }},
+		{"serviced_dfs_operations_active", "Dfs operations in progress.", "gauge", func(s OperationStats) float64 { return float64(s.Active) }},
+		{"serviced_dfs_operation_duration_mean_seconds", "Mean duration of dfs operations.", "gauge", func(s OperationStats) float64 { return s.Mean.Seconds() }},
+		{"serviced_dfs_operation_duration_p95_seconds", "95th percentile duration of dfs operations.", "gauge", func(s OperationStats) float64 { return s.P95.Seconds() }},
+		{"serviced_dfs_operation_duration_max_seconds", "Maximum duration of dfs operations.", "gauge", func(s OperationStats) float64 { return s.Max.Seconds() }},
+		{"serviced_dfs_operation_bytes_total", "Bytes transferred by completed dfs operations.", "counter", func(s OperationStats) float64 { return float64(s.Bytes) }},
+		{"serviced_dfs_operation_throughput_bytes_per_second", "Throughput of completed dfs operations.", "gauge", func(s OperationStats) float64 { return s.Throughput }},
+	}
+	for _, m := range opMetrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+		for _, s := range stats.Operations {
+			if _, err := fmt.Fprintf(w, "%s{operation=%q} %g\n", m.name, s.Operation, m.value(s)); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "# HELP serviced_dfs_lock_waiting Dfs operations waiting to acquire the dfs lock.\n# TYPE serviced_dfs_lock_waiting gauge\nserviced_dfs_lock_waiting %d\n", stats.LockWaiting)
+	return err
+}
+
+// lockWaiting counts an operation as waiting for the dfs lock until the
+// returned function is called
+func lockWaiting() func() {
+	waiting := metrics.GetOrRegisterCounter("dfs.lock.waiting", Metrics)
+	waiting.Inc(1)
+	return func() { waiting.Dec(1) }
+}
+
+// pushImage pushes an image built for the given CPU architectures into the
+// registry index
+func (dfs *DistributedFilesystem) pushImage(image, uuid, hash string, archs []string) error {
+	op := startOperation(OpRegistryPush)
+	err := dfs.index.PushImage(image, uuid, hash, archs)
+	op.done(err)
+	return err
+}
+
+// pullRegistryImage pulls an image from the docker registry into the local
+// library
+func (dfs *DistributedFilesystem) pullRegistryImage(cancel <-chan time.Time, image string) error {
+	op := startOperation(OpRegistryPull)
+	err := dfs.reg.PullImage(cancel, image)
+	op.done(err)
+	return err
+}
+
+// pullDockerImage pulls an image from its upstream registry into the local
+// library
+func (dfs *DistributedFilesystem) pullDockerImage(image string) error {
+	op := startOperation(OpRegistryPull)
+	err := dfs.docker.PullImage(image)
+	op.done(err)
+	return err
+}
@@ -0,0 +1,103 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	. "gopkg.in/check.v1"
+)
+
+func getOperationStats(c *C, name string) OperationStats {
+	for _, s := range GetStats().Operations {
+		if s.Operation == name {
+			return s
+		}
+	}
+	c.Fatalf("no stats for operation %s", name)
+	return OperationStats{}
+}
+
+func (s *DFSTestSuite) TestStats_Commit(c *C) {
+	before := getOperationStats(c, OpCommit)
+	s.docker.On("FindContainer", "testcontainer").Return(nil, ErrTestContainerNotFound)
+	_, err := s.dfs.Commit("testcontainer")
+	c.Assert(err, Equals, ErrTestContainerNotFound)
+	after := getOperationStats(c, OpCommit)
+	c.Check(after.Count, Equals, before.Count+1)
+	c.Check(after.Errors, Equals, before.Errors+1)
+	c.Check(after.Active, Equals, int64(0))
+}
+
+func (s *DFSTestSuite) TestStats_RegistryPush(c *C) {
+	before := getOperationStats(c, OpRegistryPush)
+	s.index.On("FindImage", "oldimage").Return(&oldImage, nil)
+	s.docker.On("FindImage", "newimage").Return(&newImage, nil)
+	s.docker.On("GetImageHash", newImage.ID).Return("newimagehash", nil)
+	s.index.On("PushImage", oldImage.String(), newImage.ID, "newimagehash", []string(nil)).Return(nil)
+	err := s.dfs.Override("newimage", "oldimage")
+	c.Assert(err, IsNil)
+	after := getOperationStats(c, OpRegistryPush)
+	c.Check(after.Count, Equals, before.Count+1)
+	c.Check(after.Errors, Equals, before.Errors)
+}
+
+func (s *DFSTestSuite) TestStats_Restore(c *C) {
+	before := getOperationStats(c, OpRestore)
+	err := s.dfs.Restore(strings.NewReader("data"), -1)
+	c.Assert(err, Equals, ErrInvalidBackupVersion)
+	after := getOperationStats(c, OpRestore)
+	c.Check(after.Count, Equals, before.Count+1)
+	c.Check(after.Errors, Equals, before.Errors+1)
+}
+
+func (s *DFSTestSuite) TestWritePrometheus(c *C) {
+	stats := Stats{
+		Operations: []OperationStats{
+			{
+				Operation:  OpBackup,
+				Count:      2,
+				Errors:     1,
+				Active:     1,
+				Mean:       90 * time.Second,
+				P95:        2 * time.Minute,
+				Max:        2 * time.Minute,
+				Bytes:      1024,
+				Throughput: 5.5,
+			},
+		},
+		LockWaiting: 3,
+	}
+	buf := &bytes.Buffer{}
+	c.Assert(WritePrometheus(buf, stats), IsNil)
+	out := buf.String()
+	for _, line := range []string{
+		"# TYPE serviced_dfs_operations_total counter",
+		`serviced_dfs_operations_total{operation="backup"} 2`,
+		`serviced_dfs_operation_errors_total{operation="backup"} 1`,
+		`serviced_dfs_operations_active{operation="backup"} 1`,
+		`serviced_dfs_operation_duration_mean_seconds{operation="backup"} 90`,
+		`serviced_dfs_operation_duration_p95_seconds{operation="backup"} 120`,
+		`serviced_dfs_operation_bytes_total{operation="backup"} 1024`,
+		`serviced_dfs_operation_throughput_bytes_per_second{operation="backup"} 5.5`,
+		"serviced_dfs_lock_waiting 3",
+	} {
+		c.Check(strings.Contains(out, line+"\n"), Equals, true, Commentf("missing %q", line))
+	}
+}
@@ -13,18 +13,16 @@
 
 package dfs
 
-import "github.com/zenoss/glog"
-
 // TagInfo returns information about an existing snapshot referenced by tag.
 func (dfs *DistributedFilesystem) TagInfo(tenantID, tagName string) (*SnapshotInfo, error) {
 	vol, err := dfs.disk.Get(tenantID)
 	if err != nil {
-		glog.Errorf("Could not get tenant volume %s: %s", tenantID, err)
+		plog.Errorf("Could not get tenant volume %s: %s", tenantID, err)
 		return nil, err
 	}
 	info, err := vol.GetSnapshotWithTag(tagName)
 	if err != nil {
-		glog.Errorf("Could not get info for snapshot with tag %s: %s", tagName, err)
+		plog.Errorf("Could not get info for snapshot with tag %s: %s", tagName, err)
 		return nil, err
 	}
 	return readSnapshotInfo(vol, info)
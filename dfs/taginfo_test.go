@@ -18,6 +18,7 @@ package dfs_test
 import (
 	"bytes"
 	"encoding/json"
+	"os"
 	"time"
 
 	. "github.com/control-center/serviced/dfs"
@@ -78,7 +79,9 @@ func (s *DFSTestSuite) TestTagInfo_Success(c *C) {
 	err = json.NewEncoder(imgsbuffer).Encode(imgs)
 	c.Assert(err, IsNil)
 	vol.On("ReadMetadata", "Snap", ImagesMetadataFile).Return(&NopCloser{imgsbuffer}, nil)
+	// snapshots taken before provenance was recorded
+	vol.On("ReadMetadata", "Snap", ProvenanceMetadataFile).Return(&NopCloser{}, os.ErrNotExist)
 	info, err := s.dfs.TagInfo("Base", "tagA")
 	c.Assert(err, IsNil)
-	c.Assert(info, DeepEquals, &SnapshotInfo{vinfo, imgs, svcs})
+	c.Assert(info, DeepEquals, &SnapshotInfo{vinfo, imgs, svcs, SnapshotProvenance{}})
 }
@@ -19,7 +19,6 @@ import (
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 )
 
 const timeFormat = "20060102-150405.000"
@@ -51,7 +50,7 @@ func (ttl *SnapshotTTL) Purge(age time.Duration) (time.Duration, error) {
 	expire := time.Now().Add(-age)
 	var svcs []service.Service
 	if err := ttl.client.GetServices(dao.ServiceRequest{}, &svcs); err != nil {
-		glog.Errorf("Could not look up services: %s", err)
+		plog.Errorf("Could not look up services: %s", err)
 		return 0, err
 	}
 
@@ -60,7 +59,7 @@ func (ttl *SnapshotTTL) Purge(age time.Duration) (time.Duration, error) {
 		if svc.ParentServiceID == "" {
 			var snapshots []dao.SnapshotInfo
 			if err := ttl.client.ListSnapshots(svc.ID, &snapshots); err != nil {
-				glog.Errorf("Could not look up snapshots for tenant service %s (%s): %s", svc.Name, svc.ID, err)
+				plog.Errorf("Could not look up snapshots for tenant service %s (%s): %s", svc.Name, svc.ID, err)
 				return 0, err
 			}
 			for _, s := range snapshots {
@@ -69,7 +68,7 @@ func (ttl *SnapshotTTL) Purge(age time.Duration) (time.Duration, error) {
 					// check the age of the snapshot
 					if timeToLive := s.Created.Sub(expire); timeToLive <= 0 {
 						if err := ttl.client.DeleteSnapshot(s.SnapshotID, nil); err != nil {
-							glog.Errorf("Could not delete snapshot %s for tenant service %s (%s): %s", s.SnapshotID, svc.Name, svc.ID, err)
+							plog.Errorf("Could not delete snapshot %s for tenant service %s (%s): %s", s.SnapshotID, svc.Name, svc.ID, err)
 							return 0, err
 						}
 					} else if timeToLive < age {
@@ -22,7 +22,6 @@ import (
 
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/domain/service"
-	"github.com/zenoss/glog"
 )
 
 type TestSnapshotTTLInterface struct {
@@ -106,7 +105,7 @@ func TestSnapshotTTL_Purge_SnapshotError(t *testing.T) {
 	}
 	ttl := &SnapshotTTL{iface}
 	if _, err := ttl.Purge(100); err == nil {
-		glog.Errorf("Expected error!")
+		plog.Errorf("Expected error!")
 	}
 }
 
@@ -13,24 +13,22 @@
 
 package dfs
 
-import "github.com/zenoss/glog"
-
 // Untag removes an existing snapshot tag and returns the name of the affected
 // snapshot.
 func (dfs *DistributedFilesystem) Untag(tenantID, tagName string) (string, error) {
 	vol, err := dfs.disk.Get(tenantID)
 	if err != nil {
-		glog.Errorf("Could not get tenant volume %s: %s", tenantID, err)
+		plog.Errorf("Could not get tenant volume %s: %s", tenantID, err)
 		return "", err
 	}
 	label, err := vol.UntagSnapshot(tagName)
 	if err != nil {
-		glog.Errorf("Could not remove tag %s from volume %s: %s", tagName, tenantID, err)
+		plog.Errorf("Could not remove tag %s from volume %s: %s", tagName, tenantID, err)
 		return "", err
 	}
 	info, err := vol.SnapshotInfo(label)
 	if err != nil {
-		glog.Errorf("Could not get info for snapshot %s of tenant %s: %s", label, tenantID, err)
+		plog.Errorf("Could not get info for snapshot %s of tenant %s: %s", label, tenantID, err)
 		return "", err
 	}
 	return info.Name, nil
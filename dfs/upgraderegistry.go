@@ -15,79 +15,137 @@ package dfs
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
 
 	"github.com/control-center/serviced/dfs/docker"
 	"github.com/control-center/serviced/domain/service"
-	"github.com/zenoss/glog"
 )
 
+// upgradeRegistryWorkers is the number of images that are upgraded at once
+const upgradeRegistryWorkers = 8
+
 // UpgradeRegistry loads images for each service into the docker registry
 // index.  Also migrates images from a previous (or V1) registry at
-// registryHost (host:port).
+// registryHost (host:port).  Images shared by several services are only
+// upgraded once, and images are upgraded in parallel.  Every image is
+// attempted; if any fail, the error of the first failed image is returned.
 func (dfs *DistributedFilesystem) UpgradeRegistry(svcs []service.Service, tenantID, registryHost string, override bool) error {
+	var images []service.Service
 	imageIDs := make(map[string]struct{})
 	for _, svc := range svcs {
 		if svc.ImageID == "" {
 			// no image, no migration needed
 			continue
 		}
-		image := svc.ImageID
-		if _, ok := imageIDs[image]; ok {
+		if _, ok := imageIDs[svc.ImageID]; ok {
 			// image has already been added
 			continue
 		}
-		imageIDs[image] = struct{}{}
-		if !override {
-			// is image in registry?
-			rImage, err := dfs.findImage(image, tenantID)
-			if err != nil {
-				return err
-			} else if rImage != "" {
-				// image is already in the registry
-				glog.V(2).Infof("Image %s for service %s (%s) already present in docker registry", rImage, svc.Name, svc.ID)
-				continue
+		imageIDs[svc.ImageID] = struct{}{}
+		images = append(images, svc)
+	}
+
+	workers := upgradeRegistryWorkers
+	if len(images) < workers {
+		workers = len(images)
+	}
+	errs := make([]error, len(images))
+	queue := make(chan int)
+	var count int32
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				svc := images[j]
+				errs[j] = dfs.upgradeImage(svc, tenantID, registryHost, override)
+				n := atomic.AddInt32(&count, 1)
+				if errs[j] != nil {
+					plog.Warningf("Could not upgrade image %s for service %s (%s) (%d of %d): %s", svc.ImageID, svc.Name, svc.ID, n, len(images), errs[j])
+				} else {
+					plog.Infof("Upgraded image %s for tenant %s (%d of %d)", svc.ImageID, tenantID, n, len(images))
+				}
 			}
-		}
-		// get registry image tag from image name
-		rImage, err := dfs.parseRegistryImage(image, tenantID)
-		if err != nil {
-			glog.Warningf("Cannot parse image name %s under service %s (%s)", image, svc.Name, svc.ID)
-			continue
-		}
-		// download image from old registry at registryHost defined at HOST:PORT
-		// and retag it at the original registry path as defined by the service.
-		if registryHost != "" {
-			glog.Infof("Downloading image %s from %s registry", image, registryHost)
-			oldImage := fmt.Sprintf("%s/%s", registryHost, rImage)
-			if err := dfs.docker.PullImage(oldImage); err != nil {
-				glog.Warningf("Could not pull image %s from registry %s, falling back to local library: %s", image, registryHost, err)
-			} else if err := dfs.docker.TagImage(oldImage, image); err != nil {
-				glog.Errorf("Could not retag image %s as %s: %s", oldImage, image, err)
-				return err
+		}()
+	}
+	for j := range images {
+		queue <- j
+	}
+	close(queue)
+	wg.Wait()
+
+	failed := 0
+	var err error
+	for _, e := range errs {
+		if e != nil {
+			if err == nil {
+				err = e
 			}
+			failed++
 		}
-		// find image in docker library
-		img, err := dfs.docker.FindImage(image)
-		if docker.IsImageNotFound(err) {
-			glog.Warningf("Could not find image %s for service %s (%s)", image, svc.Name, svc.ID)
-			continue
-		} else if err != nil {
-			glog.Errorf("Error looking up image %s for service %s (%s): %s", image, svc.Name, svc.ID, err)
-			return err
-		}
+	}
+	if failed > 0 {
+		plog.Errorf("Could not upgrade %d of %d images for tenant %s", failed, len(images), tenantID)
+	}
+	return err
+}
 
-		hash, err := dfs.docker.GetImageHash(img.ID)
+// upgradeImage loads the image of a service into the docker registry index,
+// migrating it from the registry at registryHost if it is set.
+func (dfs *DistributedFilesystem) upgradeImage(svc service.Service, tenantID, registryHost string, override bool) error {
+	image := svc.ImageID
+	if !override {
+		// is image in registry?
+		rImage, err := dfs.findImage(image, tenantID)
 		if err != nil {
-			glog.Errorf("Could not get hash for image %s: %s", img.ID, err)
 			return err
+		} else if rImage != "" {
+			// image is already in the registry
+			plog.Debugf("Image %s for service %s (%s) already present in docker registry", rImage, svc.Name, svc.ID)
+			return nil
 		}
-
-		// write to registry index
-		if err := dfs.index.PushImage(rImage, img.ID, hash); err != nil {
-			glog.Errorf("Could not write %s (%s) to registry index: %s", rImage, img.ID, err)
+	}
+	// get registry image tag from image name
+	rImage, err := dfs.parseRegistryImage(image, tenantID)
+	if err != nil {
+		plog.Warningf("Cannot parse image name %s under service %s (%s)", image, svc.Name, svc.ID)
+		return nil
+	}
+	// download image from old registry at registryHost defined at HOST:PORT
+	// and retag it at the original registry path as defined by the service.
+	if registryHost != "" {
+		plog.Infof("Downloading image %s from %s registry", image, registryHost)
+		oldImage := fmt.Sprintf("%s/%s", registryHost, rImage)
+		if err := dfs.pullDockerImage(oldImage); err != nil {
+			plog.Warningf("Could not pull image %s from registry %s, falling back to local library: %s", image, registryHost, err)
+		} else if err := dfs.docker.TagImage(oldImage, image); err != nil {
+			plog.Errorf("Could not retag image %s as %s: %s", oldImage, image, err)
 			return err
 		}
-		glog.Infof("Added image %s for service %s (%s) to the docker registry", rImage, svc.Name, svc.ID)
 	}
+	// find image in docker library
+	img, err := dfs.docker.FindImage(image)
+	if docker.IsImageNotFound(err) {
+		plog.Warningf("Could not find image %s for service %s (%s)", image, svc.Name, svc.ID)
+		return nil
+	} else if err != nil {
+		plog.Errorf("Error looking up image %s for service %s (%s): %s", image, svc.Name, svc.ID, err)
+		return err
+	}
+
+	hash, err := dfs.docker.GetImageHash(img.ID)
+	if err != nil {
+		plog.Errorf("Could not get hash for image %s: %s", img.ID, err)
+		return err
+	}
+
+	// write to registry index
+	if err := dfs.pushImage(rImage, img.ID, hash, imageArchs(img)); err != nil {
+		plog.Errorf("Could not write %s (%s) to registry index: %s", rImage, img.ID, err)
+		return err
+	}
+	plog.Infof("Added image %s for service %s (%s) to the docker registry", rImage, svc.Name, svc.ID)
 	return nil
 }
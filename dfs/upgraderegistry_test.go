@@ -16,6 +16,8 @@
 package dfs_test
 
 import (
+	"fmt"
+
 	index "github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/registry"
 	"github.com/control-center/serviced/domain/service"
@@ -79,7 +81,7 @@ func (s *DFSTestSuite) TestUpgradeRegistry_OverrideImageFound(c *C) {
 	image := &dockerclient.Image{ID: "xyzabc123"}
 	s.docker.On("GetImageHash", image.ID).Return("hashvalue", nil)
 	s.docker.On("FindImage", imageName).Return(image, nil)
-	s.index.On("PushImage", "tenantid/reponame:latest", "xyzabc123", "hashvalue").Return(nil)
+	s.index.On("PushImage", "tenantid/reponame:latest", "xyzabc123", "hashvalue", []string(nil)).Return(nil)
 	err := s.dfs.UpgradeRegistry(svcs, "tenantid", "", true)
 	c.Assert(err, IsNil)
 }
@@ -98,7 +100,7 @@ func (s *DFSTestSuite) TestUpgradeRegistry_OverrideImageNotFound(c *C) {
 	image := &dockerclient.Image{ID: "xyzabc123"}
 	s.docker.On("GetImageHash", image.ID).Return("hashvalue", nil)
 	s.docker.On("FindImage", imageName).Return(image, nil)
-	s.index.On("PushImage", "tenantid/reponame:latest", "xyzabc123", "hashvalue").Return(nil)
+	s.index.On("PushImage", "tenantid/reponame:latest", "xyzabc123", "hashvalue", []string(nil)).Return(nil)
 	err := s.dfs.UpgradeRegistry(svcs, "tenantid", "", true)
 	c.Assert(err, IsNil)
 }
@@ -167,7 +169,7 @@ func (s *DFSTestSuite) TestUpgradeRegistry_PushImageSuccess(c *C) {
 	image := &dockerclient.Image{ID: "youyoueyedee"}
 	s.docker.On("FindImage", imageName).Return(image, nil)
 	s.docker.On("GetImageHash", image.ID).Return("hashvalue", nil)
-	s.index.On("PushImage", "goodtenant/repo:latest", "youyoueyedee", "hashvalue").Return(nil)
+	s.index.On("PushImage", "goodtenant/repo:latest", "youyoueyedee", "hashvalue", []string(nil)).Return(nil)
 	err := s.dfs.UpgradeRegistry(svcs, "goodtenant", "", false)
 	c.Assert(err, IsNil)
 }
@@ -186,7 +188,7 @@ func (s *DFSTestSuite) TestUpgradeRegistry_PushImageFail(c *C) {
 	image := &dockerclient.Image{ID: "youyoueyedee"}
 	s.docker.On("FindImage", imageName).Return(image, nil)
 	s.docker.On("GetImageHash", image.ID).Return("hashvalue", nil)
-	s.index.On("PushImage", "goodtenant/repo:latest", "youyoueyedee", "hashvalue").Return(ErrTestNoPush)
+	s.index.On("PushImage", "goodtenant/repo:latest", "youyoueyedee", "hashvalue", []string(nil)).Return(ErrTestNoPush)
 	err := s.dfs.UpgradeRegistry(svcs, "goodtenant", "", false)
 	c.Assert(err, Equals, ErrTestNoPush)
 }
@@ -209,12 +211,33 @@ func (s *DFSTestSuite) TestUpgradeRegistry_NoDupes(c *C) {
 	image := &dockerclient.Image{ID: "youyoueyedee"}
 	s.docker.On("FindImage", imageName).Return(image, nil).Once()
 	s.docker.On("GetImageHash", image.ID).Return("hashvalue", nil)
-	s.index.On("PushImage", "goodtenant/repo:latest", "youyoueyedee", "hashvalue").Return(nil).Once()
+	s.index.On("PushImage", "goodtenant/repo:latest", "youyoueyedee", "hashvalue", []string(nil)).Return(nil).Once()
 	err := s.dfs.UpgradeRegistry(svcs, "goodtenant", "", false)
 	c.Assert(err, IsNil)
 }
 
 // no image in the old docker registry
+func (s *DFSTestSuite) TestUpgradeRegistry_ContinueOnFailure(c *C) {
+	var svcs []service.Service
+	for i := 0; i < 20; i++ {
+		imageName := fmt.Sprintf("localhost:5000/goodtenant/repo%d", i)
+		svcs = append(svcs, service.Service{Name: "service", ID: fmt.Sprintf("service%d", i), ImageID: imageName})
+		image := &dockerclient.Image{ID: fmt.Sprintf("uuid%d", i)}
+		s.index.On("FindImage", imageName).Return(nil, index.ErrImageNotFound).Once()
+		s.docker.On("FindImage", imageName).Return(image, nil).Once()
+		s.docker.On("GetImageHash", image.ID).Return("hashvalue", nil)
+		if i == 5 || i == 15 {
+			s.index.On("PushImage", fmt.Sprintf("goodtenant/repo%d:latest", i), image.ID, "hashvalue", []string(nil)).Return(ErrTestNoPush).Once()
+		} else {
+			s.index.On("PushImage", fmt.Sprintf("goodtenant/repo%d:latest", i), image.ID, "hashvalue", []string(nil)).Return(nil).Once()
+		}
+	}
+	err := s.dfs.UpgradeRegistry(svcs, "goodtenant", "", false)
+	c.Assert(err, Equals, ErrTestNoPush)
+	s.index.AssertExpectations(c)
+	s.docker.AssertExpectations(c)
+}
+
 func (s *DFSTestSuite) TestUpgradeRegistry_MigrateNoImage(c *C) {
 	imageName := "test-server:5000/tenantid/reponame"
 	svcs := []service.Service{
@@ -229,7 +252,7 @@ func (s *DFSTestSuite) TestUpgradeRegistry_MigrateNoImage(c *C) {
 	image := &dockerclient.Image{ID: "uuidvalue"}
 	s.docker.On("FindImage", imageName).Return(image, nil)
 	s.docker.On("GetImageHash", image.ID).Return("hashvalue", nil)
-	s.index.On("PushImage", "tenantid/reponame:latest", "uuidvalue", "hashvalue").Return(nil)
+	s.index.On("PushImage", "tenantid/reponame:latest", "uuidvalue", "hashvalue", []string(nil)).Return(nil)
 	err := s.dfs.UpgradeRegistry(svcs, "tenantid", "old-server:5001", false)
 	c.Assert(err, IsNil)
 }
@@ -267,7 +290,7 @@ func (s *DFSTestSuite) TestUpgradeRegistry_MigrateSuccess(c *C) {
 	image := &dockerclient.Image{ID: "uuidvalue"}
 	s.docker.On("FindImage", imageName).Return(image, nil)
 	s.docker.On("GetImageHash", image.ID).Return("hashvalue", nil)
-	s.index.On("PushImage", "tenantid/reponame:latest", "uuidvalue", "hashvalue").Return(nil)
+	s.index.On("PushImage", "tenantid/reponame:latest", "uuidvalue", "hashvalue", []string(nil)).Return(nil)
 	err := s.dfs.UpgradeRegistry(svcs, "tenantid", "old-server:5001", false)
 	c.Assert(err, IsNil)
 }
@@ -16,12 +16,35 @@ package dfs
 import (
 	"encoding/json"
 	"io"
+	"os"
 
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/volume"
-	"github.com/zenoss/glog"
+	dockerclient "github.com/fsouza/go-dockerclient"
 )
 
+// imageArchs returns the CPU architectures of a docker image
+func imageArchs(img *dockerclient.Image) []string {
+	if img.Architecture == "" {
+		return nil
+	}
+	return []string{img.Architecture}
+}
+
+// upstreamArchs returns the CPU architectures in the manifest list of an image
+// in its upstream registry, falling back to the architecture of the local
+// image if the registry cannot be queried.
+func (dfs *DistributedFilesystem) upstreamArchs(image string, img *dockerclient.Image) []string {
+	archs, err := dfs.docker.ImageArchitectures(image)
+	if err != nil {
+		plog.Warningf("Could not look up the architectures of image %s: %s", image, err)
+		return imageArchs(img)
+	} else if len(archs) == 0 {
+		return imageArchs(img)
+	}
+	return archs
+}
+
 func importJSON(r io.ReadCloser, v interface{}) error {
 	defer r.Close()
 	return json.NewDecoder(r).Decode(v)
@@ -36,24 +59,35 @@ func readSnapshotInfo(vol volume.Volume, info *volume.SnapshotInfo) (*SnapshotIn
 	// Retrieve the images metadata
 	r, err := vol.ReadMetadata(info.Label, ImagesMetadataFile)
 	if err != nil {
-		glog.Errorf("Could not read images metadata from snapshot %s: %s", info.Label, err)
+		plog.Errorf("Could not read images metadata from snapshot %s: %s", info.Label, err)
 		return nil, err
 	}
 	var images []string
 	if err := importJSON(r, &images); err != nil {
-		glog.Errorf("Could not interpret images metadata from snapshot %s: %s", info.Label, err)
+		plog.Errorf("Could not interpret images metadata from snapshot %s: %s", info.Label, err)
 		return nil, err
 	}
 	// Retrieve services metadata
 	r, err = vol.ReadMetadata(info.Label, ServicesMetadataFile)
 	if err != nil {
-		glog.Errorf("Could not read services metadata from snapshot %s: %s", info.Label, err)
+		plog.Errorf("Could not read services metadata from snapshot %s: %s", info.Label, err)
 		return nil, err
 	}
 	var svcs []service.Service
 	if err := importJSON(r, &svcs); err != nil {
-		glog.Errorf("Could not interpret services metadata from snapshot %s: %s", info.Label, err)
+		plog.Errorf("Could not interpret services metadata from snapshot %s: %s", info.Label, err)
+		return nil, err
+	}
+	// Retrieve provenance metadata, which older snapshots do not have
+	var provenance SnapshotProvenance
+	if r, err = vol.ReadMetadata(info.Label, ProvenanceMetadataFile); err == nil {
+		if err := importJSON(r, &provenance); err != nil {
+			plog.Errorf("Could not interpret provenance metadata from snapshot %s: %s", info.Label, err)
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		plog.Errorf("Could not read provenance metadata from snapshot %s: %s", info.Label, err)
 		return nil, err
 	}
-	return &SnapshotInfo{info, images, svcs}, nil
+	return &SnapshotInfo{info, images, svcs, provenance}, nil
 }
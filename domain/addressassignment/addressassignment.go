@@ -35,6 +35,32 @@ type AssignmentRequest struct {
 	AutoAssignment bool
 }
 
+// Reassignment describes the outcome of moving a service's address
+// assignments off of an ip that is no longer available
+type Reassignment struct {
+	ServiceID  string
+	OldIPAddrs []string // the distinct ips of the stale assignments
+	NewIPAddr  string   // empty if the service could not be reassigned
+	Error      string
+}
+
+// PlannedAssignment describes the current and proposed address assignment for
+// a single configurable endpoint of a service
+type PlannedAssignment struct {
+	ServiceID      string
+	ServiceName    string
+	EndpointName   string
+	Port           uint16
+	CurrentIPAddr  string // empty if the endpoint is not assigned
+	ProposedIPAddr string // empty if no ip is available
+	Error          string
+}
+
+// NeedsAssignment returns true if the endpoint's assignment would change
+func (p PlannedAssignment) NeedsAssignment() bool {
+	return p.CurrentIPAddr != p.ProposedIPAddr
+}
+
 // EqualIP verifies the address assignment is the same by IP ONLY
 func (assign AddressAssignment) EqualIP(b AddressAssignment) bool {
 	if assign.PoolID != b.PoolID {
@@ -43,6 +43,28 @@ func (s *Store) GetServiceAddressAssignments(ctx datastore.Context, serviceID st
 	return convert(results)
 }
 
+func (s *Store) GetHostAddressAssignments(ctx datastore.Context, hostID string) ([]AddressAssignment, error) {
+	q := datastore.NewQuery(ctx)
+	query := search.Query().Term("HostID", hostID)
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+func (s *Store) GetPoolAddressAssignments(ctx datastore.Context, poolID string) ([]AddressAssignment, error) {
+	q := datastore.NewQuery(ctx)
+	query := search.Query().Term("PoolID", poolID)
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
 func (s *Store) GetServiceAddressAssignmentsByPort(ctx datastore.Context, port uint16) ([]AddressAssignment, error) {
 	if port == 0 {
 		return nil, fmt.Errorf("port must be greater than 0")
@@ -14,7 +14,6 @@
 package addressassignment
 
 import (
-	"github.com/zenoss/glog"
 	"github.com/control-center/serviced/datastore/elastic"
 )
 
@@ -40,6 +39,6 @@ var (
 
 func init() {
 	if mappingError != nil {
-		glog.Fatalf("error creating  addressassignment: %v", mappingError)
+		plog.Fatalf("error creating  addressassignment: %v", mappingError)
 	}
 }
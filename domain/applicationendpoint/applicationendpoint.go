@@ -36,11 +36,72 @@ type ApplicationEndpoint struct {
 	ProxyPort      uint16
 }
 
+// CheckStatus is the outcome of an endpoint check
+type CheckStatus string
+
+const (
+	// CheckOK means the check passed
+	CheckOK CheckStatus = "ok"
+	// CheckWarning means the check could not be completed
+	CheckWarning CheckStatus = "warning"
+	// CheckFailed means the endpoint is broken
+	CheckFailed CheckStatus = "failed"
+)
+
+// EndpointCheck is the result of a single verification step run against an
+// endpoint
+type EndpointCheck struct {
+	Name    string
+	Status  CheckStatus
+	Message string
+}
+
 type EndpointReport struct {
 	Endpoint ApplicationEndpoint
 
-	// FIXME: Refactor into some kind of array of typed messages (e.g. info, warn and error)
+	// Messages describes every check that did not pass
 	Messages []string
+	Checks   []EndpointCheck
+}
+
+// AddCheck records the result of a check against the endpoint
+func (r *EndpointReport) AddCheck(name string, status CheckStatus, message string) {
+	r.Checks = append(r.Checks, EndpointCheck{Name: name, Status: status, Message: message})
+	if status != CheckOK {
+		r.Messages = append(r.Messages, fmt.Sprintf("%s: %s", name, message))
+	}
+}
+
+// Status returns the worst status of the checks run against the endpoint, or
+// an empty status if the endpoint was not checked.
+func (r EndpointReport) Status() CheckStatus {
+	var status CheckStatus
+	for _, check := range r.Checks {
+		switch {
+		case check.Status == CheckFailed:
+			return CheckFailed
+		case check.Status == CheckWarning, status == "":
+			status = check.Status
+		}
+	}
+	return status
+}
+
+// EndpointStats counts the connections the mux on the owning host has
+// forwarded to an exported endpoint of a service instance
+type EndpointStats struct {
+	ServiceID         string
+	InstanceID        int
+	Application       string
+	HostID            string
+	ContainerIP       string
+	ContainerPort     uint16
+	ActiveConnections int64
+	TotalConnections  int64
+	ConnectErrors     int64
+	BytesReceived     int64
+	BytesSent         int64
+	Error             string // set if the stats could not be retrieved from the host
 }
 
 // BuildEndpointReports converts an array of ApplicationEndpoints to an array of EndpointReports
@@ -221,3 +221,21 @@ func buildApplicationEndpoint() *ApplicationEndpoint {
 		ProxyPort:      1,
 	}
 }
+
+func TestEndpointReportAddCheck(t *testing.T) {
+	report := EndpointReport{Messages: []string{}}
+	assert.Equal(t, CheckStatus(""), report.Status())
+
+	report.AddCheck("listening", CheckOK, "")
+	assert.Equal(t, CheckOK, report.Status())
+	assert.Empty(t, report.Messages)
+
+	report.AddCheck("registered", CheckWarning, "not registered")
+	assert.Equal(t, CheckWarning, report.Status())
+
+	report.AddCheck("mux", CheckFailed, "connection refused")
+	report.AddCheck("resolves", CheckOK, "")
+	assert.Equal(t, CheckFailed, report.Status())
+	assert.Len(t, report.Checks, 4)
+	assert.Equal(t, []string{"registered: not registered", "mux: connection refused"}, report.Messages)
+}
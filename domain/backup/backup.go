@@ -0,0 +1,37 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// Backup is an entry in the catalog of completed backups
+type Backup struct {
+	ID            string        // Name of the backup file, eg backup-2016-01-02-150405.tgz
+	Location      string        // Path or URI where the backup file is stored
+	Size          int64         // Size of the backup file (bytes)
+	Checksum      string        // Hex encoded sha256 checksum of the backup file
+	StartedAt     time.Time     // When the backup started
+	Duration      time.Duration // How long the backup took to complete
+	Templates     []string      // Names of the service templates in the backup
+	BaseImages    []string      // Docker images of the service templates
+	Pools         []string      // IDs of the resource pools in the backup
+	Snapshots     []string      // Application snapshots in the backup
+	BackupVersion int           // Version of the backup file format
+	Available     bool          // Whether the backup file was found when the catalog was listed
+	datastore.VersionedEntity
+}
@@ -0,0 +1,60 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+)
+
+const kind = "backup"
+
+var (
+	mappingString = fmt.Sprintf(`
+{
+    "%s": {
+        "properties": {
+            "ID":            {"type": "string", "index": "not_analyzed"},
+            "Location":      {"type": "string", "index": "not_analyzed"},
+            "Size":          {"type": "long",   "index": "not_analyzed"},
+            "Checksum":      {"type": "string", "index": "not_analyzed"},
+            "StartedAt":     {"type": "date",   "format": "dateOptionalTime"},
+            "Duration":      {"type": "long",   "index": "not_analyzed"},
+            "Templates":     {"type": "string", "index": "not_analyzed"},
+            "BaseImages":    {"type": "string", "index": "not_analyzed"},
+            "Pools":         {"type": "string", "index": "not_analyzed"},
+            "Snapshots":     {"type": "string", "index": "not_analyzed"},
+            "BackupVersion": {"type": "long",   "index": "not_analyzed"}
+        }
+    }
+}
+`, kind)
+	// MAPPING is the elastic mapping for the backup catalog
+	MAPPING, mappingError = elastic.NewMapping(mappingString)
+)
+
+func init() {
+	if mappingError != nil {
+		plog.Fatalf("error creating backup mapping: %s", mappingError)
+	}
+}
+
+// Key returns the datastore key of a backup
+func Key(id string) datastore.Key {
+	id = strings.TrimSpace(id)
+	return datastore.NewKey(kind, id)
+}
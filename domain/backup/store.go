@@ -0,0 +1,80 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// NewStore creates a new backup catalog store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Store is the database for the catalog of completed backups
+type Store interface {
+	// Get a backup by id.  Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, id string) (*Backup, error)
+
+	// Put adds/updates a backup in the catalog
+	Put(ctx datastore.Context, val *Backup) error
+
+	// Delete removes a backup from the catalog
+	Delete(ctx datastore.Context, id string) error
+
+	// GetBackups returns all of the backups in the catalog
+	GetBackups(ctx datastore.Context) ([]Backup, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// Get a backup by id.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, id string) (*Backup, error) {
+	val := &Backup{}
+	if err := s.ds.Get(ctx, Key(id), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Put adds/updates a backup in the catalog
+func (s *storeImpl) Put(ctx datastore.Context, val *Backup) error {
+	return s.ds.Put(ctx, Key(val.ID), val)
+}
+
+// Delete removes a backup from the catalog
+func (s *storeImpl) Delete(ctx datastore.Context, id string) error {
+	return s.ds.Delete(ctx, Key(id))
+}
+
+// GetBackups returns all of the backups in the catalog
+func (s *storeImpl) GetBackups(ctx datastore.Context) ([]Backup, error) {
+	query := search.Query().Search("_exists_:ID")
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	q := datastore.NewQuery(ctx)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]Backup, results.Len())
+	for i := range backups {
+		if err := results.Get(i, &backups[i]); err != nil {
+			return nil, err
+		}
+	}
+	return backups, nil
+}
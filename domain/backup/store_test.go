@@ -0,0 +1,93 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&S{
+	ElasticTest: elastic.ElasticTest{
+		Index:    "controlplane",
+		Mappings: []elastic.Mapping{MAPPING},
+	}})
+
+type S struct {
+	elastic.ElasticTest
+	ctx   datastore.Context
+	store Store
+}
+
+func (s *S) SetUpTest(c *C) {
+	s.ElasticTest.SetUpTest(c)
+	datastore.Register(s.Driver())
+	s.ctx = datastore.Get()
+	s.store = NewStore()
+}
+
+func (s *S) Test_BackupCRUD(c *C) {
+	expected := &Backup{
+		ID:            "backup-2016-05-04-103000.tgz",
+		Location:      "/opt/serviced/var/backups/backup-2016-05-04-103000.tgz",
+		Size:          1024,
+		Checksum:      "abc123",
+		StartedAt:     time.Date(2016, 5, 4, 10, 30, 0, 0, time.UTC),
+		Duration:      time.Minute,
+		Templates:     []string{"Zenoss.core"},
+		Pools:         []string{"default"},
+		BackupVersion: 1,
+	}
+	_, err := s.store.Get(s.ctx, expected.ID)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+
+	err = s.store.Put(s.ctx, expected)
+	c.Assert(err, IsNil)
+	actual, err := s.store.Get(s.ctx, expected.ID)
+	c.Assert(err, IsNil)
+	c.Assert(actual.Location, Equals, expected.Location)
+	c.Assert(actual.Templates, DeepEquals, expected.Templates)
+
+	err = s.store.Delete(s.ctx, expected.ID)
+	c.Assert(err, IsNil)
+	_, err = s.store.Get(s.ctx, expected.ID)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+}
+
+func (s *S) Test_GetBackups(c *C) {
+	older := &Backup{ID: "backup-1.tgz", Location: "/backups/backup-1.tgz", StartedAt: time.Now().Add(-time.Hour)}
+	newer := &Backup{ID: "backup-2.tgz", Location: "/backups/backup-2.tgz", StartedAt: time.Now()}
+	c.Assert(s.store.Put(s.ctx, older), IsNil)
+	c.Assert(s.store.Put(s.ctx, newer), IsNil)
+
+	backups, err := s.store.GetBackups(s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(backups, HasLen, 2)
+}
+
+func (s *S) Test_ValidEntity(c *C) {
+	err := s.store.Put(s.ctx, &Backup{ID: "backup-3.tgz"})
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,35 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates Backup fields
+func (b *Backup) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Backup.ID", b.ID))
+	violations.Add(validation.StringsEqual(b.ID, strings.TrimSpace(b.ID), "leading and trailing spaces not allowed for backup id"))
+	violations.Add(validation.NotEmpty("Backup.Location", b.Location))
+	if b.Size < 0 {
+		violations.Add(validation.NewViolation("backup size cannot be less than 0"))
+	}
+	if violations.HasError() {
+		return violations
+	}
+	return nil
+}
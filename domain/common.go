@@ -17,6 +17,10 @@ import (
 	"fmt"
 )
 
+// DefaultArch is the CPU architecture assumed for hosts and images that were
+// recorded before serviced tracked architectures
+const DefaultArch = "amd64"
+
 type MinMax struct {
 	Min     int
 	Max     int
@@ -0,0 +1,49 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbmigration
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// Migration is a versioned change to the entities stored in the datastore.
+// Migrations are registered in code and applied in version order when the
+// master starts.
+type Migration struct {
+	Version     int
+	Description string
+	Kinds       []string // Kinds of entities changed by the migration, e.g. service
+	Apply       func(ctx datastore.Context) error
+}
+
+// Record is the datastore entry of a migration that has been applied
+type Record struct {
+	ID          string // Version of the migration, as a string
+	Version     int
+	Description string
+	AppliedAt   time.Time
+	Duration    time.Duration
+	datastore.VersionedEntity
+}
+
+// Status describes a registered migration and whether it has been applied
+type Status struct {
+	Version     int
+	Description string
+	Kinds       []string
+	Applied     bool
+	AppliedAt   time.Time
+}
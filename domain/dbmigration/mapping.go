@@ -0,0 +1,53 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbmigration
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+)
+
+const kind = "dbmigration"
+
+var (
+	mappingString = fmt.Sprintf(`
+{
+    "%s": {
+        "properties": {
+            "ID":          {"type": "string", "index": "not_analyzed"},
+            "Version":     {"type": "long",   "index": "not_analyzed"},
+            "Description": {"type": "string", "index": "not_analyzed"},
+            "AppliedAt":   {"type": "date",   "format": "dateOptionalTime"},
+            "Duration":    {"type": "long",   "index": "not_analyzed"}
+        }
+    }
+}
+`, kind)
+	// MAPPING is the elastic mapping for applied migrations
+	MAPPING, mappingError = elastic.NewMapping(mappingString)
+)
+
+func init() {
+	if mappingError != nil {
+		plog.Fatalf("error creating dbmigration mapping: %s", mappingError)
+	}
+}
+
+// Key returns the datastore key of the migration with the given version
+func Key(version int) datastore.Key {
+	return datastore.NewKey(kind, strconv.Itoa(version))
+}
@@ -0,0 +1,65 @@
+package mocks
+
+import "github.com/control-center/serviced/domain/dbmigration"
+import "github.com/stretchr/testify/mock"
+
+import "github.com/control-center/serviced/datastore"
+
+type Store struct {
+	mock.Mock
+}
+
+func (_m *Store) Get(ctx datastore.Context, version int) (*dbmigration.Record, error) {
+	ret := _m.Called(ctx, version)
+
+	var r0 *dbmigration.Record
+	if rf, ok := ret.Get(0).(func(datastore.Context, int) *dbmigration.Record); ok {
+		r0 = rf(ctx, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dbmigration.Record)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, int) error); ok {
+		r1 = rf(ctx, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *Store) Put(ctx datastore.Context, val *dbmigration.Record) error {
+	ret := _m.Called(ctx, val)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, *dbmigration.Record) error); ok {
+		r0 = rf(ctx, val)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *Store) GetRecords(ctx datastore.Context) ([]dbmigration.Record, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []dbmigration.Record
+	if rf, ok := ret.Get(0).(func(datastore.Context) []dbmigration.Record); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dbmigration.Record)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,57 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbmigration
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	migrationsLock sync.RWMutex
+	migrations     = make(map[int]Migration)
+)
+
+// Register adds a migration to the set applied on master startup.  It panics
+// if the version is not positive or is already registered, since either is a
+// programming error.
+func Register(m Migration) {
+	migrationsLock.Lock()
+	defer migrationsLock.Unlock()
+	if m.Version <= 0 {
+		panic(fmt.Sprintf("dbmigration: invalid version %d", m.Version))
+	} else if _, ok := migrations[m.Version]; ok {
+		panic(fmt.Sprintf("dbmigration: version %d is already registered", m.Version))
+	}
+	migrations[m.Version] = m
+}
+
+// Migrations returns the registered migrations in version order
+func Migrations() []Migration {
+	migrationsLock.RLock()
+	defer migrationsLock.RUnlock()
+	result := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		result = append(result, m)
+	}
+	sort.Sort(byVersion(result))
+	return result
+}
+
+type byVersion []Migration
+
+func (s byVersion) Len() int           { return len(s) }
+func (s byVersion) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byVersion) Less(i, j int) bool { return s[i].Version < s[j].Version }
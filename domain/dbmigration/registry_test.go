@@ -0,0 +1,51 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dbmigration
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func TestRegistry(t *testing.T) { TestingT(t) }
+
+type RegistrySuite struct{}
+
+var _ = Suite(&RegistrySuite{})
+
+func (s *RegistrySuite) SetUpTest(c *C) {
+	migrations = make(map[int]Migration)
+}
+
+func (s *RegistrySuite) TestRegister(c *C) {
+	Register(Migration{Version: 3, Description: "third"})
+	Register(Migration{Version: 1, Description: "first"})
+	Register(Migration{Version: 2, Description: "second"})
+
+	var descriptions []string
+	for _, m := range Migrations() {
+		descriptions = append(descriptions, m.Description)
+	}
+	c.Assert(descriptions, DeepEquals, []string{"first", "second", "third"})
+}
+
+func (s *RegistrySuite) TestRegisterInvalid(c *C) {
+	Register(Migration{Version: 1})
+	c.Assert(func() { Register(Migration{Version: 1}) }, PanicMatches, "dbmigration: version 1 is already registered")
+	c.Assert(func() { Register(Migration{Version: 0}) }, PanicMatches, "dbmigration: invalid version 0")
+	c.Assert(Migrations(), HasLen, 1)
+}
@@ -0,0 +1,68 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbmigration
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+type Store interface {
+	// Get an applied migration by version.  Return ErrNoSuchEntity if not
+	// found
+	Get(ctx datastore.Context, version int) (*Record, error)
+
+	// Put records that a migration has been applied
+	Put(ctx datastore.Context, val *Record) error
+
+	// GetRecords returns all of the applied migrations
+	GetRecords(ctx datastore.Context) ([]Record, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+func (s *storeImpl) Get(ctx datastore.Context, version int) (*Record, error) {
+	val := &Record{}
+	if err := s.ds.Get(ctx, Key(version), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+func (s *storeImpl) Put(ctx datastore.Context, val *Record) error {
+	return s.ds.Put(ctx, Key(val.Version), val)
+}
+
+func (s *storeImpl) GetRecords(ctx datastore.Context) ([]Record, error) {
+	query := search.Query().Search("_exists_:ID")
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	q := datastore.NewQuery(ctx)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, results.Len())
+	for i := range records {
+		if err := results.Get(i, &records[i]); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
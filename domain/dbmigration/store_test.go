@@ -0,0 +1,79 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package dbmigration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&S{
+	ElasticTest: elastic.ElasticTest{
+		Index:    "controlplane",
+		Mappings: []elastic.Mapping{MAPPING},
+	}})
+
+type S struct {
+	elastic.ElasticTest
+	ctx   datastore.Context
+	store Store
+}
+
+func (s *S) SetUpTest(c *C) {
+	s.ElasticTest.SetUpTest(c)
+	datastore.Register(s.Driver())
+	s.ctx = datastore.Get()
+	s.store = NewStore()
+}
+
+func (s *S) Test_RecordCRUD(c *C) {
+	expected := &Record{
+		ID:          "1",
+		Version:     1,
+		Description: "Baseline",
+		AppliedAt:   time.Date(2016, 5, 4, 10, 30, 0, 0, time.UTC),
+		Duration:    time.Second,
+	}
+	_, err := s.store.Get(s.ctx, 1)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+
+	err = s.store.Put(s.ctx, expected)
+	c.Assert(err, IsNil)
+	actual, err := s.store.Get(s.ctx, 1)
+	c.Assert(err, IsNil)
+	c.Assert(actual.Description, Equals, expected.Description)
+	c.Assert(actual.AppliedAt.Equal(expected.AppliedAt), Equals, true)
+
+	records, err := s.store.GetRecords(s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 1)
+}
+
+func (s *S) Test_ValidEntity(c *C) {
+	err := s.store.Put(s.ctx, &Record{ID: "2", Version: 3})
+	c.Assert(err, NotNil)
+	err = s.store.Put(s.ctx, &Record{ID: "0", Version: 0})
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,33 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbmigration
+
+import (
+	"strconv"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates Record fields
+func (r *Record) ValidEntity() error {
+	violations := validation.NewValidationError()
+	if r.Version <= 0 {
+		violations.Add(validation.NewViolation("migration version must be greater than 0"))
+	}
+	violations.Add(validation.StringsEqual(strconv.Itoa(r.Version), r.ID, "migration id must match its version"))
+	if violations.HasError() {
+		return violations
+	}
+	return nil
+}
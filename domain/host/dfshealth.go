@@ -0,0 +1,45 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host
+
+import "time"
+
+// DFSMount is the health of a distributed filesystem mount on a host
+type DFSMount struct {
+	Volume    string // name of the application volume
+	Remote    string // exported path on the storage server
+	LocalPath string
+	Available bool
+	Stale     bool          // the mount has a stale file handle
+	Latency   time.Duration // time to stat the mount point
+	Error     string
+	Remounts  int // remount attempts since the mount became unavailable
+}
+
+// DFSEvent records a change in the availability of an application's shared
+// volume on a host
+type DFSEvent struct {
+	Time      time.Time
+	Volume    string
+	Available bool
+	Message   string
+}
+
+// DFSHealth is the health of the distributed filesystem mounts on a host
+type DFSHealth struct {
+	Healthy bool
+	Mounts  []DFSMount
+	Events  []DFSEvent // most recent changes in availability, oldest first
+	Updated time.Time
+}
@@ -0,0 +1,49 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventReconcile is the type of event that a delegate records when it
+// reconciles its instances with the master after it reconnects
+const EventReconcile = "reconcile"
+
+// Event is an entry in the event history of a host
+type Event struct {
+	Time      time.Time
+	Type      string
+	Message   string
+	Reconcile *ReconcileReport `json:",omitempty"`
+}
+
+// ReconcileReport describes what a delegate changed when it reconciled its
+// instances with the master after it was disconnected.  Instances are of the
+// form SERVICEID/INSTANCEID.
+type ReconcileReport struct {
+	Disconnected time.Time
+	Reconnected  time.Time
+	Killed       []string // instances that were reassigned while offline and were stopped
+	Restarted    []string // instances whose containers were restarted while offline
+	Adopted      []string // instances that kept the containers started while offline
+	Removed      []string // orphaned containers that were removed
+}
+
+// Summary describes the report in a single line
+func (r ReconcileReport) Summary() string {
+	offline := r.Reconnected.Sub(r.Disconnected)
+	return fmt.Sprintf("offline for %s: %d killed, %d restarted, %d adopted, %d removed", offline-offline%time.Second, len(r.Killed), len(r.Restarted), len(r.Adopted), len(r.Removed))
+}
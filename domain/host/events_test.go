@@ -0,0 +1,35 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package host
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconcileReport_Summary(t *testing.T) {
+	disconnected := time.Date(2016, 5, 4, 3, 2, 1, 0, time.UTC)
+	report := ReconcileReport{
+		Disconnected: disconnected,
+		Reconnected:  disconnected.Add(5*time.Minute + 300*time.Millisecond),
+		Killed:       []string{"svc1/0"},
+		Restarted:    []string{"svc2/0", "svc2/1"},
+		Adopted:      []string{"svc2/0"},
+	}
+	if s := report.Summary(); s != "offline for 5m0s: 1 killed, 2 restarted, 1 adopted, 0 removed" {
+		t.Errorf("unexpected summary %q", s)
+	}
+}
@@ -26,7 +26,6 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/servicedversion"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 )
 
 var ErrSizeTooBig = errors.New("calculated memory exceeds base value")
@@ -61,6 +60,7 @@ type Host struct {
 	IPs             []HostIPResource // The static IP resources available on the host
 	KernelVersion   string
 	KernelRelease   string
+	Arch            string // CPU architecture of the host, eg amd64 or arm64
 	ServiceD        struct {
 		Version   string
 		Date      string
@@ -83,6 +83,7 @@ type ReadHost struct {
 	RAMLimit      string
 	KernelVersion string
 	KernelRelease string
+	Arch          string
 	ServiceD      ReadServiced
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
@@ -98,7 +99,12 @@ type HostStatus struct {
 	HostID        string
 	MemoryUsage   service.Usage
 	Active        bool
+	Restarting    bool // true if the host is restarting serviced and its instances are preserved
 	Authenticated bool
+	ClockSkew     time.Duration  // how far the host's clock is behind the master's
+	ClockSkewed   bool           // true if the clock skew exceeds the allowed maximum
+	DFS           *DFSHealth     // nil if the host does not report its dfs mounts
+	Storage       *StorageHealth // nil if the host does not report its storage
 }
 
 func (a *Host) TotalRAM() (mem uint64) {
@@ -115,6 +121,15 @@ func (a *Host) TotalRAM() (mem uint64) {
 	return a.Memory
 }
 
+// Architecture returns the CPU architecture of the host, defaulting to
+// domain.DefaultArch for hosts registered before architectures were recorded
+func (a *Host) Architecture() string {
+	if a.Arch == "" {
+		return domain.DefaultArch
+	}
+	return a.Arch
+}
+
 // Equals verifies whether two host objects are equal
 func (a *Host) Equals(b *Host) bool {
 	if a.ID != b.ID {
@@ -147,6 +162,9 @@ func (a *Host) Equals(b *Host) bool {
 	if a.KernelRelease != b.KernelRelease {
 		return false
 	}
+	if a.Arch != b.Arch {
+		return false
+	}
 	if !reflect.DeepEqual(a.IPs, b.IPs) {
 		return false
 	}
@@ -196,13 +214,13 @@ func Build(ip string, rpcport string, poolid string, memory string, ipAddrs ...s
 	}
 	host, err := currentHost(ip, rpcPort, poolid)
 	if err != nil {
-		glog.V(2).Infof("currentHost failed: %s", err)
+		plog.Debugf("currentHost failed: %s", err)
 		return nil, err
 	}
-	glog.Infof("Building host %s (%s) with ipsAddrs: %v [%d]", host.ID, host.IPAddr, ipAddrs, len(ipAddrs))
+	plog.Infof("Building host %s (%s) with ipsAddrs: %v [%d]", host.ID, host.IPAddr, ipAddrs, len(ipAddrs))
 	hostIPs, err := getIPResources(host.ID, host.IPAddr, ipAddrs...)
 	if err != nil {
-		glog.V(2).Infof("getIPResources failed: %s", err)
+		plog.Debugf("getIPResources failed: %s", err)
 		return nil, err
 	}
 	host.IPs = hostIPs
@@ -235,6 +253,7 @@ func UpdateHostInfo(h Host) (Host, error) {
 	h.Cores = currentHost.Cores
 	h.KernelRelease = currentHost.KernelRelease
 	h.KernelVersion = currentHost.KernelVersion
+	h.Arch = currentHost.Arch
 	h.PrivateNetwork = currentHost.PrivateNetwork
 	h.ServiceD = currentHost.ServiceD
 
@@ -18,7 +18,6 @@ package host
 import (
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/validation"
-	"github.com/zenoss/glog"
 
 	"strings"
 	"testing"
@@ -56,7 +55,7 @@ func init() {
 
 	ip, err := utils.GetIPAddress()
 	if err != nil {
-		glog.Errorf("Could not determine ip %v", err)
+		plog.Errorf("Could not determine ip %v", err)
 	}
 
 	validatetable = []validateCase{
@@ -139,7 +138,7 @@ func Test_Build(t *testing.T) {
 
 	empty := make([]string, 0)
 	host, err := Build("", "65535", "test_pool", "", empty...)
-	glog.Infof("build  error %v", err)
+	plog.Infof("build  error %v", err)
 	if err != nil {
 		t.Errorf("Unexpected error %v", err)
 	}
@@ -210,5 +209,5 @@ func Test_getOSKernelData(t *testing.T) {
 		t.Errorf("Unexpected error %v", err)
 	}
 
-	glog.Infof("Kernel Version:  %v Kernel Release: %v", kernelVersion, kernelRelease)
+	plog.Infof("Kernel Version:  %v Kernel Release: %v", kernelVersion, kernelRelease)
 }
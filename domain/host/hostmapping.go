@@ -14,7 +14,6 @@
 package host
 
 import (
-	"github.com/zenoss/glog"
 	"github.com/control-center/serviced/datastore/elastic"
 )
 
@@ -27,6 +26,7 @@ var (
         "Name":           {"type": "string", "index":"not_analyzed"},
         "KernelVersion":  {"type": "string", "index":"not_analyzed"},
         "KernelRelease":  {"type": "string", "index":"not_analyzed"},
+        "Arch":           {"type": "string", "index":"not_analyzed"},
         "PoolID":         {"type": "string", "index":"not_analyzed"},
         "IpAddr":         {"type": "string", "index":"not_analyzed"},
         "Cores":          {"type": "long", "index":"not_analyzed"},
@@ -51,6 +51,6 @@ var (
 
 func init() {
 	if mappingError != nil {
-		glog.Fatalf("error creating host mapping: %v", mappingError)
+		plog.Fatalf("error creating host mapping: %v", mappingError)
 	}
 }
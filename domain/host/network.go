@@ -0,0 +1,52 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host
+
+// NetworkMaster identifies the master as the destination of a network path
+const NetworkMaster = "master"
+
+// NetworkTarget is a port on the master that every delegate must reach
+type NetworkTarget struct {
+	Name    string // What listens on the port, eg rpc, nfs, zookeeper
+	Address string // host:port of the target
+}
+
+// NetworkPath is the outcome of probing a required port on one host from
+// another
+type NetworkPath struct {
+	FromHostID string
+	ToHostID   string // The destination host, or NetworkMaster
+	Name       string // What listens on the port, eg mux, rpc
+	Address    string
+	Open       bool
+	Error      string // Why the port could not be reached
+}
+
+// NetworkReport is the connectivity matrix between the hosts of a pool
+type NetworkReport struct {
+	PoolID  string
+	HostIDs []string
+	Paths   []NetworkPath
+}
+
+// Blocked returns the paths whose ports could not be reached
+func (r *NetworkReport) Blocked() []NetworkPath {
+	var blocked []NetworkPath
+	for _, path := range r.Paths {
+		if !path.Open {
+			blocked = append(blocked, path)
+		}
+	}
+	return blocked
+}
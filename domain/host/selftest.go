@@ -0,0 +1,41 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host
+
+import "time"
+
+// SelfTestResult is the outcome of one check of a host's self-test
+type SelfTestResult struct {
+	Name   string
+	Passed bool
+	Detail string // What was checked, or why the check failed
+}
+
+// SelfTestReport is the outcome of validating that a host can run as a
+// delegate
+type SelfTestReport struct {
+	HostID  string
+	Ran     time.Time
+	Results []SelfTestResult
+}
+
+// Passed returns true if every check of the self-test passed
+func (r *SelfTestReport) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
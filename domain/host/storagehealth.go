@@ -0,0 +1,137 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Levels of storage usage
+const (
+	StorageOK       = "ok"
+	StorageWarning  = "warning"
+	StorageCritical = "critical"
+)
+
+// StorageUsage is the space used by a storage pool on a host
+type StorageUsage struct {
+	Name      string // what the storage is used for, e.g. docker thin pool data
+	Driver    string // storage driver, e.g. devicemapper or btrfs
+	Total     uint64
+	Used      uint64
+	Available uint64
+	Level     string // set from the master's thresholds
+
+	// Space of a thin pool that is allocated to thinly provisioned storage,
+	// and how much of it is unused and can be reclaimed
+	Allocated   uint64
+	Reclaimable uint64
+}
+
+// PercentUsed returns the percentage of the storage that is used
+func (u StorageUsage) PercentUsed() int {
+	if u.Total == 0 {
+		return 0
+	}
+	return int(u.Used * 100 / u.Total)
+}
+
+// IsThinPool returns true if the storage is a devicemapper thin pool
+func (u StorageUsage) IsThinPool() bool {
+	return strings.Contains(u.Name, "thin pool")
+}
+
+// IsDFS returns true if the storage backs the application volumes
+func (u StorageUsage) IsDFS() bool {
+	return strings.HasPrefix(u.Name, "application ")
+}
+
+// StorageHealth is the usage of the storage pools on a host
+type StorageHealth struct {
+	Usage           []StorageUsage
+	Errors          []string // storage that could not be checked
+	Level           string   // the highest level of any usage
+	WarningPercent  int
+	CriticalPercent int
+	Updated         time.Time
+}
+
+// SetLevels sets the level of each storage pool from the percent used at
+// which it is a warning and at which it is critical.
+func (h *StorageHealth) SetLevels(warning, critical int) {
+	h.WarningPercent = warning
+	h.CriticalPercent = critical
+	h.Level = StorageOK
+	for i := range h.Usage {
+		u := &h.Usage[i]
+		switch used := u.PercentUsed(); {
+		case used >= critical:
+			u.Level = StorageCritical
+		case used >= warning:
+			u.Level = StorageWarning
+		default:
+			u.Level = StorageOK
+		}
+		if u.Level == StorageCritical || (u.Level == StorageWarning && h.Level == StorageOK) {
+			h.Level = u.Level
+		}
+	}
+}
+
+// Worst returns the most used storage pool at the highest level, or nil if
+// no usage was reported
+func (h *StorageHealth) Worst() *StorageUsage {
+	var worst *StorageUsage
+	rank := map[string]int{StorageOK: 0, StorageWarning: 1, StorageCritical: 2}
+	for i := range h.Usage {
+		u := &h.Usage[i]
+		if worst == nil || rank[u.Level] > rank[worst.Level] ||
+			(rank[u.Level] == rank[worst.Level] && u.PercentUsed() > worst.PercentUsed()) {
+			worst = u
+		}
+	}
+	return worst
+}
+
+// String summarizes the storage health by its most used storage pool
+func (h *StorageHealth) String() string {
+	worst := h.Worst()
+	if worst == nil {
+		return h.Level
+	}
+	return fmt.Sprintf("%s (%s %d%%)", h.Level, worst.Name, worst.PercentUsed())
+}
+
+// BelowMinFree returns the storage pool with the least available space of
+// those below their minimum free space, or nil if there are none.  Thin pools
+// are checked against minThinPool and the other storage backing the
+// application volumes against minDFS; a minimum of 0 is not checked.
+func (h *StorageHealth) BelowMinFree(minDFS, minThinPool uint64) *StorageUsage {
+	var low *StorageUsage
+	for i := range h.Usage {
+		u := &h.Usage[i]
+		min := minDFS
+		if u.IsThinPool() {
+			min = minThinPool
+		} else if !u.IsDFS() {
+			continue
+		}
+		if min > 0 && u.Available < min && (low == nil || u.Available < low.Available) {
+			low = u
+		}
+	}
+	return low
+}
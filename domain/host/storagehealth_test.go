@@ -0,0 +1,91 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package host
+
+import "testing"
+
+func TestStorageHealth_SetLevels(t *testing.T) {
+	health := &StorageHealth{
+		Usage: []StorageUsage{
+			{Name: "docker thin pool data", Total: 100, Used: 50},
+			{Name: "docker thin pool metadata", Total: 100, Used: 85},
+			{Name: "dfs", Total: 0, Used: 0},
+		},
+	}
+	health.SetLevels(80, 90)
+	if health.Level != StorageWarning {
+		t.Errorf("expected level %s, got %s", StorageWarning, health.Level)
+	}
+	expected := []string{StorageOK, StorageWarning, StorageOK}
+	for i, u := range health.Usage {
+		if u.Level != expected[i] {
+			t.Errorf("expected %s to be %s, got %s", u.Name, expected[i], u.Level)
+		}
+	}
+	if health.WarningPercent != 80 || health.CriticalPercent != 90 {
+		t.Errorf("thresholds were not recorded: %+v", health)
+	}
+	if s := health.String(); s != "warning (docker thin pool metadata 85%)" {
+		t.Errorf("unexpected summary %q", s)
+	}
+
+	health.Usage[0].Used = 95
+	health.SetLevels(80, 90)
+	if health.Level != StorageCritical {
+		t.Errorf("expected level %s, got %s", StorageCritical, health.Level)
+	}
+	if s := health.String(); s != "critical (docker thin pool data 95%)" {
+		t.Errorf("unexpected summary %q", s)
+	}
+}
+
+func TestStorageHealth_NoUsage(t *testing.T) {
+	health := &StorageHealth{}
+	health.SetLevels(80, 90)
+	if health.Level != StorageOK {
+		t.Errorf("expected level %s, got %s", StorageOK, health.Level)
+	}
+	if health.Worst() != nil {
+		t.Errorf("expected no usage")
+	}
+	if s := health.String(); s != StorageOK {
+		t.Errorf("unexpected summary %q", s)
+	}
+}
+
+func TestStorageHealth_BelowMinFree(t *testing.T) {
+	health := &StorageHealth{
+		Usage: []StorageUsage{
+			{Name: "docker thin pool data", Total: 100, Available: 20},
+			{Name: "docker root", Total: 100, Available: 1},
+			{Name: "application thin pool data", Total: 100, Available: 15},
+			{Name: "application thin pool metadata", Total: 100, Available: 40},
+			{Name: "application /opt/serviced/var/volumes", Total: 100, Available: 30},
+		},
+	}
+	if low := health.BelowMinFree(0, 0); low != nil {
+		t.Errorf("expected no minimum to be checked, got %s", low.Name)
+	}
+	if low := health.BelowMinFree(10, 10); low != nil {
+		t.Errorf("expected no storage below the minimum, got %s", low.Name)
+	}
+	if low := health.BelowMinFree(0, 25); low == nil || low.Name != "application thin pool data" {
+		t.Errorf("expected the application thin pool data, got %+v", low)
+	}
+	if low := health.BelowMinFree(35, 0); low == nil || low.Name != "application /opt/serviced/var/volumes" {
+		t.Errorf("expected the application volumes, got %+v", low)
+	}
+}
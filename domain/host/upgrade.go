@@ -0,0 +1,41 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host
+
+import "time"
+
+// Statuses of a delegate upgrade
+const (
+	UpgradeUpgraded = "upgraded"
+	UpgradeSkipped  = "skipped"
+	UpgradeFailed   = "failed"
+)
+
+// UpgradeRequest describes a rolling upgrade of the serviced delegates
+type UpgradeRequest struct {
+	Version string        // Release of serviced to install
+	PoolIDs []string      // Pools to upgrade, in order; all pools if empty
+	Drain   bool          // Move instances off of each host before upgrading it
+	Timeout time.Duration // How long to wait for each host to return after upgrading
+}
+
+// UpgradeResult is the outcome of upgrading serviced on a delegate
+type UpgradeResult struct {
+	HostID          string
+	PoolID          string
+	PreviousVersion string
+	Version         string // Version running after the upgrade
+	Status          string
+	Message         string // Why the host was skipped or failed
+}
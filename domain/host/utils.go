@@ -17,7 +17,6 @@ import (
 	"github.com/control-center/serviced/servicedversion"
 	"github.com/control-center/serviced/utils"
 	"github.com/kr/pretty"
-	"github.com/zenoss/glog"
 
 	"fmt"
 	"net"
@@ -57,7 +56,7 @@ func currentHost(ip string, rpcPort int, poolID string) (host *Host, err error)
 	host.Name = hostname
 	hostidStr, err := utils.HostID()
 	if err != nil {
-		glog.V(2).Infof("HostID failed: %s", err)
+		plog.Debugf("HostID failed: %s", err)
 		return nil, err
 	}
 
@@ -81,6 +80,7 @@ func currentHost(ip string, rpcPort int, poolID string) (host *Host, err error)
 	host.ID = hostidStr
 	host.Cores = cpus
 	host.Memory = memory
+	host.Arch = runtime.GOARCH
 
 	// get embedded host information
 	host.ServiceD.Version = servicedversion.Version
@@ -97,7 +97,7 @@ func currentHost(ip string, rpcPort int, poolID string) (host *Host, err error)
 
 	routes, err := utils.RouteCmd()
 	if err != nil {
-		glog.V(2).Infof("RouteCmd failed: %s", err)
+		plog.Debugf("RouteCmd failed: %s", err)
 		return nil, err
 	}
 	for _, route := range routes {
@@ -133,7 +133,7 @@ func getIPResources(hostID string, hostIP string, staticIPs ...string) ([]HostIP
 	if err != nil {
 		return nil, err
 	}
-	glog.V(3).Infof("Interfaces on this host %s: %+v", hostID, ifacemap)
+	plog.Debugf("Interfaces on this host %s: %+v", hostID, ifacemap)
 
 	// Get a unique list of ips from staticIPs and hostIP.
 	ips := func() []string {
@@ -147,7 +147,7 @@ func getIPResources(hostID string, hostIP string, staticIPs ...string) ([]HostIP
 
 	hostIPResources := make([]HostIPResource, len(ips))
 	for i, ip := range ips {
-		glog.Infof("Checking IP '%s'", ip)
+		plog.Infof("Checking IP '%s'", ip)
 		if iface, ok := ifacemap[ip]; ok {
 			if isLoopBack(ip) {
 				return nil, IsLoopbackError(ip)
@@ -171,7 +171,7 @@ func getIPResources(hostID string, hostIP string, staticIPs ...string) ([]HostIP
 func getInterfaceMap() (map[string]net.Interface, error) {
 	interfaces, err := net.Interfaces()
 	if err != nil {
-		glog.Error("Problem reading interfaces: ", err)
+		plog.Error("Problem reading interfaces: ", err)
 		return nil, err
 	}
 	//make a  of all ipaddresses to interface
@@ -179,7 +179,7 @@ func getInterfaceMap() (map[string]net.Interface, error) {
 	for _, iface := range interfaces {
 		addrs, err := iface.Addrs()
 		if err != nil {
-			glog.Error("Problem reading interfaces: ", err)
+			plog.Error("Problem reading interfaces: ", err)
 			return nil, err
 		}
 		for _, ip := range addrs {
@@ -198,9 +198,9 @@ func normalizeIP(ip string) string {
 
 func ipExists(ip string) bool {
 	interfaces, err := getInterfaceMap()
-	glog.V(5).Infof("looking for %s in %#", ip, pretty.Formatter(interfaces))
+	plog.Debugf("looking for %s in %#", ip, pretty.Formatter(interfaces))
 	if err != nil {
-		glog.Error("Problem reading interfaces: ", err)
+		plog.Error("Problem reading interfaces: ", err)
 		return false
 	}
 	normalIP := normalizeIP(ip)
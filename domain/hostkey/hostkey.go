@@ -14,11 +14,16 @@
 package hostkey
 
 import (
+	"time"
+
 	"github.com/control-center/serviced/datastore"
 )
 
 // Entity containing a PEM-encoded RSA public key
 type HostKey struct {
-	PEM string
+	PEM         string
+	Fingerprint string    // SHA256 fingerprint of the public key
+	CreatedAt   time.Time // When the key was generated
+	VerifiedAt  time.Time // When the host confirmed that it installed the key
 	datastore.VersionedEntity
 }
@@ -19,7 +19,6 @@ import (
 
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/datastore/elastic"
-	"github.com/zenoss/glog"
 )
 
 const kind = "keyregistry"
@@ -29,7 +28,10 @@ var (
 {
     "%s": {
         "properties": {
-            "PEM": {"type": "string", "index": "no"}
+            "PEM":         {"type": "string", "index": "no"},
+            "Fingerprint": {"type": "string", "index": "not_analyzed"},
+            "CreatedAt":   {"type": "date",   "format": "dateOptionalTime"},
+            "VerifiedAt":  {"type": "date",   "format": "dateOptionalTime"}
         }
     }
 }
@@ -40,7 +42,7 @@ var (
 
 func init() {
 	if mappingError != nil {
-		glog.Fatalf("error creating rsa key registry mapping: %s", mappingError)
+		plog.Fatalf("error creating rsa key registry mapping: %s", mappingError)
 	}
 }
 
@@ -0,0 +1,56 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+)
+
+const kind = "maintenancewindow"
+
+var (
+	mappingString = fmt.Sprintf(`
+{
+    "%s": {
+        "properties": {
+            "ID":          {"type": "string", "index": "not_analyzed"},
+            "Description": {"type": "string", "index": "not_analyzed"},
+            "Scope":       {"type": "string", "index": "not_analyzed"},
+            "Target":      {"type": "string", "index": "not_analyzed"},
+            "Start":       {"type": "date",   "format": "dateOptionalTime"},
+            "Duration":    {"type": "long",   "index": "not_analyzed"},
+            "Repeat":      {"type": "string", "index": "not_analyzed"}
+        }
+    }
+}
+`, kind)
+	// MAPPING is the elastic mapping for maintenance windows
+	MAPPING, mappingError = elastic.NewMapping(mappingString)
+)
+
+func init() {
+	if mappingError != nil {
+		plog.Fatalf("error creating maintenance window mapping: %s", mappingError)
+	}
+}
+
+// Key returns the datastore key of a maintenance window
+func Key(id string) datastore.Key {
+	id = strings.TrimSpace(id)
+	return datastore.NewKey(kind, id)
+}
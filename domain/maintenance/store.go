@@ -0,0 +1,80 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// NewStore creates a new maintenance window store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Store is the database for maintenance windows
+type Store interface {
+	// Get a maintenance window by id.  Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, id string) (*Window, error)
+
+	// Put adds/updates a maintenance window
+	Put(ctx datastore.Context, val *Window) error
+
+	// Delete removes a maintenance window
+	Delete(ctx datastore.Context, id string) error
+
+	// GetWindows returns all of the maintenance windows
+	GetWindows(ctx datastore.Context) ([]Window, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// Get a maintenance window by id.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, id string) (*Window, error) {
+	val := &Window{}
+	if err := s.ds.Get(ctx, Key(id), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Put adds/updates a maintenance window
+func (s *storeImpl) Put(ctx datastore.Context, val *Window) error {
+	return s.ds.Put(ctx, Key(val.ID), val)
+}
+
+// Delete removes a maintenance window
+func (s *storeImpl) Delete(ctx datastore.Context, id string) error {
+	return s.ds.Delete(ctx, Key(id))
+}
+
+// GetWindows returns all of the maintenance windows
+func (s *storeImpl) GetWindows(ctx datastore.Context) ([]Window, error) {
+	query := search.Query().Search("_exists_:ID")
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	q := datastore.NewQuery(ctx)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	windows := make([]Window, results.Len())
+	for i := range windows {
+		if err := results.Get(i, &windows[i]); err != nil {
+			return nil, err
+		}
+	}
+	return windows, nil
+}
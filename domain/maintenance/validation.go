@@ -0,0 +1,46 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates Window fields
+func (w *Window) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Window.ID", w.ID))
+	violations.Add(validation.StringsEqual(w.ID, strings.TrimSpace(w.ID), "leading and trailing spaces not allowed for maintenance window id"))
+	violations.Add(validation.StringIn(w.Scope, Scopes...))
+	violations.Add(validation.NotEmpty("Window.Target", w.Target))
+	if w.Start.IsZero() {
+		violations.Add(validation.NewViolation("maintenance window start time is required"))
+	}
+	if w.Duration <= 0 {
+		violations.Add(validation.NewViolation("maintenance window duration must be greater than 0"))
+	}
+	if w.Repeat != "" {
+		if err := validation.StringIn(w.Repeat, RepeatDaily, RepeatWeekly); err != nil {
+			violations.Add(err)
+		} else if w.Duration >= w.period() {
+			violations.Add(validation.NewViolation("maintenance window duration must be shorter than its repeat interval"))
+		}
+	}
+	if violations.HasError() {
+		return violations
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+const (
+	// ScopeDeployment applies a window to all of the services of a deployment
+	ScopeDeployment = "deployment"
+	// ScopeService applies a window to a service and its children
+	ScopeService = "service"
+	// ScopeHost applies a window to all of the instances running on a host
+	ScopeHost = "host"
+
+	// RepeatDaily repeats a window every day
+	RepeatDaily = "daily"
+	// RepeatWeekly repeats a window every week
+	RepeatWeekly = "weekly"
+)
+
+// Scopes are the valid scopes of a maintenance window
+var Scopes = []string{ScopeDeployment, ScopeService, ScopeHost}
+
+// Window is a scheduled period during which automatic restarts and
+// rescheduling of service instances are suppressed
+type Window struct {
+	ID          string
+	Description string
+	Scope       string        // deployment, service, or host
+	Target      string        // ID of the deployment, service, or host
+	Start       time.Time     // When the first occurrence of the window starts
+	Duration    time.Duration // How long each occurrence lasts
+	Repeat      string        // Empty for a single occurrence, daily, or weekly
+	datastore.VersionedEntity
+}
+
+// period returns the time between occurrences of a repeating window
+func (w Window) period() time.Duration {
+	switch w.Repeat {
+	case RepeatDaily:
+		return 24 * time.Hour
+	case RepeatWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// ActiveUntil returns the end of the occurrence of the window that is in
+// progress at the given time, or the zero time if the window is not active.
+func (w Window) ActiveUntil(at time.Time) time.Time {
+	if at.Before(w.Start) {
+		return time.Time{}
+	}
+	start := w.Start
+	if p := w.period(); p > 0 {
+		start = start.Add(at.Sub(w.Start) / p * p)
+	}
+	if end := start.Add(w.Duration); at.Before(end) {
+		return end
+	}
+	return time.Time{}
+}
+
+// Active returns true if the window is in progress at the given time
+func (w Window) Active(at time.Time) bool {
+	return !w.ActiveUntil(at).IsZero()
+}
+
+// Expired returns true if the window will not occur again after the given
+// time
+func (w Window) Expired(at time.Time) bool {
+	return w.period() == 0 && !at.Before(w.Start.Add(w.Duration))
+}
+
+// Covers returns true if the window applies to an instance of a service on a
+// host.  The service ids are the id of the service followed by the ids of its
+// parents.  Pass an empty host id to ignore host windows.
+func (w Window) Covers(deploymentID string, serviceIDs []string, hostID string) bool {
+	switch w.Scope {
+	case ScopeDeployment:
+		return deploymentID != "" && w.Target == deploymentID
+	case ScopeService:
+		for _, id := range serviceIDs {
+			if id == w.Target {
+				return true
+			}
+		}
+	case ScopeHost:
+		return hostID != "" && w.Target == hostID
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func TestWindow(t *testing.T) { TestingT(t) }
+
+type WindowSuite struct{}
+
+var _ = Suite(&WindowSuite{})
+
+func (s *WindowSuite) TestActiveUntil(c *C) {
+	start := time.Date(2016, 6, 1, 2, 0, 0, 0, time.UTC)
+	w := Window{Start: start, Duration: time.Hour}
+
+	c.Assert(w.Active(start.Add(-time.Minute)), Equals, false)
+	c.Assert(w.ActiveUntil(start), Equals, start.Add(time.Hour))
+	c.Assert(w.ActiveUntil(start.Add(59*time.Minute)), Equals, start.Add(time.Hour))
+	c.Assert(w.Active(start.Add(time.Hour)), Equals, false)
+	c.Assert(w.Active(start.Add(24*time.Hour)), Equals, false)
+	c.Assert(w.Expired(start.Add(30*time.Minute)), Equals, false)
+	c.Assert(w.Expired(start.Add(time.Hour)), Equals, true)
+
+	w.Repeat = RepeatDaily
+	c.Assert(w.ActiveUntil(start.Add(24*time.Hour+time.Minute)), Equals, start.Add(25*time.Hour))
+	c.Assert(w.Active(start.Add(26*time.Hour)), Equals, false)
+	c.Assert(w.Expired(start.Add(26*time.Hour)), Equals, false)
+
+	w.Repeat = RepeatWeekly
+	c.Assert(w.Active(start.Add(24*time.Hour+time.Minute)), Equals, false)
+	c.Assert(w.ActiveUntil(start.Add(7*24*time.Hour)), Equals, start.Add(7*24*time.Hour+time.Hour))
+}
+
+func (s *WindowSuite) TestCovers(c *C) {
+	w := Window{Scope: ScopeDeployment, Target: "dep1"}
+	c.Assert(w.Covers("dep1", []string{"child", "parent"}, ""), Equals, true)
+	c.Assert(w.Covers("dep2", []string{"child", "parent"}, "host1"), Equals, false)
+
+	w = Window{Scope: ScopeService, Target: "parent"}
+	c.Assert(w.Covers("dep1", []string{"child", "parent"}, ""), Equals, true)
+	c.Assert(w.Covers("dep1", []string{"parent"}, ""), Equals, true)
+	c.Assert(w.Covers("dep1", []string{"other"}, ""), Equals, false)
+
+	w = Window{Scope: ScopeHost, Target: "host1"}
+	c.Assert(w.Covers("dep1", []string{"child"}, "host1"), Equals, true)
+	c.Assert(w.Covers("dep1", []string{"child"}, ""), Equals, false)
+}
+
+func (s *WindowSuite) TestValidEntity(c *C) {
+	w := &Window{
+		ID:       "window-1",
+		Scope:    ScopeService,
+		Target:   "service-1",
+		Start:    time.Now(),
+		Duration: time.Hour,
+	}
+	c.Assert(w.ValidEntity(), IsNil)
+
+	w.Repeat = RepeatDaily
+	c.Assert(w.ValidEntity(), IsNil)
+
+	w.Duration = 24 * time.Hour
+	c.Assert(w.ValidEntity(), NotNil)
+
+	w.Duration = time.Hour
+	w.Repeat = "hourly"
+	c.Assert(w.ValidEntity(), NotNil)
+
+	w.Repeat = ""
+	w.Scope = "pool"
+	c.Assert(w.ValidEntity(), NotNil)
+}
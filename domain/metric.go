@@ -19,7 +19,6 @@ import (
 	"fmt"
 
 	"github.com/control-center/serviced/validation"
-	"github.com/zenoss/glog"
 
 	"encoding/json"
 	"errors"
@@ -175,7 +174,7 @@ func (builder *MetricBuilder) Config(ID, Name, Description, Start string) (*Metr
 	//build the query body object
 	bodyBytes, err := json.Marshal(request)
 	if err != nil {
-		glog.Errorf("Failed to marshal query body: %+v", err)
+		plog.Errorf("Failed to marshal query body: %+v", err)
 		return nil, err
 	}
 
@@ -195,7 +194,7 @@ func NewMetricConfigBuilder(RequestURI, Method string) (*MetricBuilder, error) {
 	//use url.Parse to ensure proper RequestURI. 'http://localhost' is removed when Config is built
 	url, err := url.Parse("http://localhost/" + requestURI)
 	if err != nil {
-		glog.Errorf("Invalid Url: RequestURI=%s, method=%s, err=%+v", RequestURI, Method, err)
+		plog.Errorf("Invalid Url: RequestURI=%s, method=%s, err=%+v", RequestURI, Method, err)
 		return nil, err
 	}
 
@@ -205,7 +204,7 @@ func NewMetricConfigBuilder(RequestURI, Method string) (*MetricBuilder, error) {
 	case "PUT":
 	case "POST":
 	default:
-		glog.Errorf("Invalid http method: RequestURI=%s, method=%s", RequestURI, Method)
+		plog.Errorf("Invalid http method: RequestURI=%s, method=%s", RequestURI, Method)
 		return nil, errors.New("invalid method")
 	}
 
@@ -37,6 +37,12 @@ const (
 	DFSAccess
 )
 
+// Schedulers of the services in a pool
+const (
+	SchedulerMaster   = "master"   // the master schedules the pool
+	SchedulerDelegate = "delegate" // a delegate elected from the pool schedules the pool
+)
+
 // ResourcePool A collection of computing resources with optional quotas.
 type ResourcePool struct {
 	ID                string      // Unique identifier for resource pool, eg "default"
@@ -53,9 +59,44 @@ type ResourcePool struct {
 	UpdatedAt         time.Time
 	MonitoringProfile domain.MonitorProfile
 	Permissions       Permission
+	SharedStorage     SharedStorage // How the dfs volumes are shared with the hosts in the pool
+	Macvlan           Macvlan       // Network that macvlan services in the pool attach to
+	Scheduler         string        // Who schedules the services in the pool; empty is the master
 	datastore.VersionedEntity
 }
 
+// SharedStorage describes how the application volumes of the distributed
+// filesystem are mounted on the hosts of a resource pool.
+type SharedStorage struct {
+	Transport string // Name of the storage transport, eg "cephfs"; empty uses nfs
+	Source    string // Remote location of the volumes; ignored by nfs, which mounts the master's export
+	Options   string // Comma separated mount options passed to the transport
+}
+
+// IsNFS returns true if the volumes are mounted from the master's nfs export
+func (s SharedStorage) IsNFS() bool {
+	return s.Transport == "" || s.Transport == "nfs"
+}
+
+// Macvlan describes the network that attaches containers of macvlan services
+// directly to the network of the hosts in a resource pool.
+type Macvlan struct {
+	Parent  string // Host interface that the network is attached to, eg "eth0"; empty disables macvlan
+	Subnet  string // Subnet of the parent's network in CIDR notation; docker's default if empty
+	Gateway string // Gateway of the parent's network
+}
+
+// Enabled returns true if services in the pool may use macvlan networking
+func (m Macvlan) Enabled() bool {
+	return m.Parent != ""
+}
+
+// DelegatesScheduling returns true if a delegate in the pool, rather than the
+// master, schedules the services in the pool
+func (p ResourcePool) DelegatesScheduling() bool {
+	return p.Scheduler == SchedulerDelegate
+}
+
 func (p ResourcePool) GetConnectionTimeout() time.Duration {
 	return time.Duration(p.ConnectionTimeout) * time.Millisecond
 }
@@ -74,6 +115,27 @@ type PoolHost struct {
 	HostIP string
 }
 
+// PortClaim is a host port claimed by a service endpoint in a ResourcePool,
+// either through an address assignment or an enabled public port.
+type PortClaim struct {
+	IPAddr       string // Empty if the port is claimed on every address
+	Port         uint16
+	Protocol     string
+	ServiceID    string
+	ServiceName  string
+	EndpointName string
+	Public       bool // Whether the port is claimed by a public port
+	Conflict     bool // Whether another endpoint claims the same port
+}
+
+// Overlaps returns true if both claims bind the same port on the same address
+func (c PortClaim) Overlaps(b PortClaim) bool {
+	if c.Port != b.Port || c.Protocol != b.Protocol {
+		return false
+	}
+	return c.IPAddr == "" || b.IPAddr == "" || c.IPAddr == b.IPAddr
+}
+
 type ByIP []VirtualIP
 
 func (b ByIP) Len() int           { return len(b) }
@@ -130,6 +192,15 @@ func (a *ResourcePool) Equals(b *ResourcePool) bool {
 	if !a.MonitoringProfile.Equals(&b.MonitoringProfile) {
 		return false
 	}
+	if a.SharedStorage != b.SharedStorage {
+		return false
+	}
+	if a.Macvlan != b.Macvlan {
+		return false
+	}
+	if a.Scheduler != b.Scheduler {
+		return false
+	}
 
 	return true
 }
@@ -19,7 +19,6 @@ import (
 
 	"github.com/control-center/serviced/datastore"
 	"github.com/zenoss/elastigo/search"
-	"github.com/zenoss/glog"
 )
 
 //NewStore creates a ResourcePool store
@@ -47,13 +46,13 @@ type storeImpl struct {
 
 //GetResourcePools Get a list of all the resource pools
 func (ps *storeImpl) GetResourcePools(ctx datastore.Context) ([]ResourcePool, error) {
-	glog.V(3).Infof("Pool Store.GetResourcePools")
+	plog.Debugf("Pool Store.GetResourcePools")
 	return query(ctx, "_exists_:ID")
 }
 
 // GetResourcePoolsByRealm gets a list of resource pools for a given realm
 func (s *storeImpl) GetResourcePoolsByRealm(ctx datastore.Context, realm string) ([]ResourcePool, error) {
-	glog.V(3).Infof("Pool Store.GetResourcePoolsByRealm")
+	plog.Debugf("Pool Store.GetResourcePoolsByRealm")
 	id := strings.TrimSpace(realm)
 	if id == "" {
 		return nil, errors.New("empty realm not allowed")
@@ -107,6 +107,24 @@ func (s *S) Test_ValidateConnectionTimeout(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *S) Test_ValidateMacvlan(c *C) {
+	defer s.ps.Delete(s.ctx, Key("Test_ValidateMacvlan"))
+	pool := New("Test_ValidateMacvlan")
+	pool.Realm = "test_realm1"
+
+	pool.Macvlan = Macvlan{Subnet: "10.1.0.0/16"}
+	err := s.ps.Put(s.ctx, Key(pool.ID), pool)
+	c.Assert(err, ErrorMatches, "(?s).*macvlan parent interface is required.*")
+
+	pool.Macvlan = Macvlan{Parent: "eth0", Subnet: "10.1.0.0/16", Gateway: "10.2.0.1"}
+	err = s.ps.Put(s.ctx, Key(pool.ID), pool)
+	c.Assert(err, ErrorMatches, "(?s).*macvlan gateway 10.2.0.1 is not in subnet 10.1.0.0/16.*")
+
+	pool.Macvlan = Macvlan{Parent: "eth0", Subnet: "10.1.0.0/16", Gateway: "10.1.0.1"}
+	err = s.ps.Put(s.ctx, Key(pool.ID), pool)
+	c.Assert(err, IsNil)
+}
+
 func (s *S) Test_GetPools(t *C) {
 	defer s.ps.Delete(s.ctx, Key("Test_GetPools1"))
 	defer s.ps.Delete(s.ctx, Key("Test_GetPools2"))
@@ -15,16 +15,16 @@ package pool
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/control-center/serviced/validation"
-	"github.com/zenoss/glog"
 
 	"strings"
 )
 
 //ValidEntity validates Host fields
 func (p *ResourcePool) ValidEntity() error {
-	glog.V(4).Info("Validating ResourcePool")
+	plog.Debug("Validating ResourcePool")
 
 	trimmedID := strings.TrimSpace(p.ID)
 	violations := validation.NewValidationError()
@@ -40,6 +40,26 @@ func (p *ResourcePool) ValidEntity() error {
 		violations.Add(validation.NewViolation(fmt.Sprintf("connection timeout cannot be less than 0")))
 	}
 
+	if !p.SharedStorage.IsNFS() && strings.TrimSpace(p.SharedStorage.Source) == "" {
+		violations.Add(validation.NewViolation(fmt.Sprintf("shared storage source is required for the %s transport", p.SharedStorage.Transport)))
+	}
+
+	if m := p.Macvlan; !m.Enabled() && (m.Subnet != "" || m.Gateway != "") {
+		violations.Add(validation.NewViolation("macvlan parent interface is required"))
+	} else if m.Subnet != "" {
+		if _, subnet, err := net.ParseCIDR(m.Subnet); err != nil {
+			violations.Add(validation.NewViolation(fmt.Sprintf("macvlan subnet %s is not valid", m.Subnet)))
+		} else if m.Gateway != "" && !subnet.Contains(net.ParseIP(m.Gateway)) {
+			violations.Add(validation.NewViolation(fmt.Sprintf("macvlan gateway %s is not in subnet %s", m.Gateway, m.Subnet)))
+		}
+	} else if m.Gateway != "" {
+		violations.Add(validation.NewViolation("macvlan gateway requires a subnet"))
+	}
+
+	if p.Scheduler != "" && p.Scheduler != SchedulerMaster && p.Scheduler != SchedulerDelegate {
+		violations.Add(validation.NewViolation(fmt.Sprintf("scheduler %s is not one of %s or %s", p.Scheduler, SchedulerMaster, SchedulerDelegate)))
+	}
+
 	if len(violations.Errors) > 0 {
 		return violations
 	}
@@ -15,19 +15,30 @@ package registry
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain"
 )
 
 type Image struct {
-	Library string
-	Repo    string
-	Tag     string
-	UUID    string
-	Hash    string
+	Library  string
+	Repo     string
+	Tag      string
+	UUID     string
+	Hash     string
+	Archs    []string `json:",omitempty"` // CPU architectures of the image's manifests
+	PushedAt time.Time
 	datastore.VersionedEntity
 }
 
+// ImageDetails is an image in the registry index along with the services
+// that use it
+type ImageDetails struct {
+	Image
+	Services []string // IDs of the services that reference the image
+}
+
 func (image *Image) String() string {
 	imageStr := fmt.Sprintf("%s/%s", image.Library, image.Repo)
 
@@ -38,6 +49,26 @@ func (image *Image) String() string {
 	return imageStr
 }
 
+// Architectures returns the CPU architectures the image has manifests for.
+// Images indexed before architectures were recorded are assumed to be
+// domain.DefaultArch.
+func (image *Image) Architectures() []string {
+	if len(image.Archs) == 0 {
+		return []string{domain.DefaultArch}
+	}
+	return image.Archs
+}
+
+// SupportsArch returns true if the image has a manifest for the architecture
+func (image *Image) SupportsArch(arch string) bool {
+	for _, a := range image.Architectures() {
+		if a == arch {
+			return true
+		}
+	}
+	return false
+}
+
 func (image *Image) ID() string {
 	return image.key().ID()
 }
@@ -19,7 +19,6 @@ import (
 
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/datastore/elastic"
-	"github.com/zenoss/glog"
 )
 
 const kind = "imageregistry"
@@ -32,7 +31,9 @@ var (
             "Library":  {"type": "string", "index": "not_analyzed"},
             "Repo":     {"type": "string", "index": "not_analyzed"},
             "Tag":      {"type": "string", "index": "not_analyzed"},
-            "UUID":     {"type": "string", "index": "not_analyzed"}
+            "UUID":     {"type": "string", "index": "not_analyzed"},
+            "Archs":    {"type": "string", "index": "not_analyzed"},
+            "PushedAt": {"type": "date",   "format": "dateOptionalTime"}
         }
     }
 }
@@ -43,7 +44,7 @@ var (
 
 func init() {
 	if mappingError != nil {
-		glog.Fatalf("error creating image registry mapping: %s", mappingError)
+		plog.Fatalf("error creating image registry mapping: %s", mappingError)
 	}
 }
 
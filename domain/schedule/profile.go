@@ -0,0 +1,101 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+const (
+	// RepeatDaily repeats a window every day
+	RepeatDaily = "daily"
+	// RepeatWeekly repeats a window every week
+	RepeatWeekly = "weekly"
+
+	// StateRun is the state of a service during its windows
+	StateRun = "run"
+	// StateStop is the state of a service outside of its windows
+	StateStop = "stop"
+)
+
+// Profile restricts a service and its children to run only during scheduled
+// windows.  The master starts the service when a window opens and stops it
+// when the window closes.
+type Profile struct {
+	ID           string
+	Description  string
+	ServiceID    string        // ID of the service that runs during the windows
+	Start        time.Time     // When the first window opens
+	Duration     time.Duration // How long each window stays open
+	Repeat       string        // Empty for a single window, daily, or weekly
+	AppliedState string        // State last applied to the service by the master
+	datastore.VersionedEntity
+}
+
+// Transition is a scheduled change in the desired state of a service
+type Transition struct {
+	At    time.Time
+	State string // run or stop
+}
+
+// period returns the time between windows of a repeating profile
+func (p Profile) period() time.Duration {
+	switch p.Repeat {
+	case RepeatDaily:
+		return 24 * time.Hour
+	case RepeatWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// windowStart returns when the latest window that opened at or before the
+// given time opened, or the zero time if the first window has not opened.
+func (p Profile) windowStart(at time.Time) time.Time {
+	if at.Before(p.Start) {
+		return time.Time{}
+	}
+	start := p.Start
+	if period := p.period(); period > 0 {
+		start = start.Add(at.Sub(p.Start) / period * period)
+	}
+	return start
+}
+
+// State returns the desired state of the service at the given time
+func (p Profile) State(at time.Time) string {
+	if start := p.windowStart(at); !start.IsZero() && at.Before(start.Add(p.Duration)) {
+		return StateRun
+	}
+	return StateStop
+}
+
+// NextTransition returns the first change in the desired state of the
+// service after the given time, or false if the state will not change again.
+func (p Profile) NextTransition(at time.Time) (Transition, bool) {
+	start := p.windowStart(at)
+	if start.IsZero() {
+		return Transition{At: p.Start, State: StateRun}, true
+	}
+	if end := start.Add(p.Duration); at.Before(end) {
+		return Transition{At: end, State: StateStop}, true
+	}
+	if period := p.period(); period > 0 {
+		return Transition{At: start.Add(period), State: StateRun}, true
+	}
+	return Transition{}, false
+}
@@ -0,0 +1,92 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func TestProfile(t *testing.T) { TestingT(t) }
+
+type ProfileSuite struct{}
+
+var _ = Suite(&ProfileSuite{})
+
+func (s *ProfileSuite) TestState(c *C) {
+	start := time.Date(2016, 6, 1, 22, 0, 0, 0, time.UTC)
+	p := Profile{Start: start, Duration: 4 * time.Hour}
+
+	c.Assert(p.State(start.Add(-time.Minute)), Equals, StateStop)
+	c.Assert(p.State(start), Equals, StateRun)
+	c.Assert(p.State(start.Add(4*time.Hour-time.Minute)), Equals, StateRun)
+	c.Assert(p.State(start.Add(4*time.Hour)), Equals, StateStop)
+	c.Assert(p.State(start.Add(24*time.Hour)), Equals, StateStop)
+
+	p.Repeat = RepeatDaily
+	c.Assert(p.State(start.Add(24*time.Hour+time.Minute)), Equals, StateRun)
+	c.Assert(p.State(start.Add(29*time.Hour)), Equals, StateStop)
+
+	p.Repeat = RepeatWeekly
+	c.Assert(p.State(start.Add(24*time.Hour+time.Minute)), Equals, StateStop)
+	c.Assert(p.State(start.Add(7*24*time.Hour)), Equals, StateRun)
+}
+
+func (s *ProfileSuite) TestNextTransition(c *C) {
+	start := time.Date(2016, 6, 1, 22, 0, 0, 0, time.UTC)
+	p := Profile{Start: start, Duration: 4 * time.Hour}
+
+	t, ok := p.NextTransition(start.Add(-time.Hour))
+	c.Assert(ok, Equals, true)
+	c.Assert(t, Equals, Transition{At: start, State: StateRun})
+
+	t, ok = p.NextTransition(start.Add(time.Hour))
+	c.Assert(ok, Equals, true)
+	c.Assert(t, Equals, Transition{At: start.Add(4 * time.Hour), State: StateStop})
+
+	_, ok = p.NextTransition(start.Add(5 * time.Hour))
+	c.Assert(ok, Equals, false)
+
+	p.Repeat = RepeatDaily
+	t, ok = p.NextTransition(start.Add(5 * time.Hour))
+	c.Assert(ok, Equals, true)
+	c.Assert(t, Equals, Transition{At: start.Add(24 * time.Hour), State: StateRun})
+}
+
+func (s *ProfileSuite) TestValidEntity(c *C) {
+	p := &Profile{
+		ID:        "profile-1",
+		ServiceID: "service-1",
+		Start:     time.Now(),
+		Duration:  time.Hour,
+	}
+	c.Assert(p.ValidEntity(), IsNil)
+
+	p.Repeat = RepeatWeekly
+	c.Assert(p.ValidEntity(), IsNil)
+
+	p.Duration = 7 * 24 * time.Hour
+	c.Assert(p.ValidEntity(), NotNil)
+
+	p.Duration = time.Hour
+	p.Repeat = "monthly"
+	c.Assert(p.ValidEntity(), NotNil)
+
+	p.Repeat = ""
+	p.ServiceID = ""
+	c.Assert(p.ValidEntity(), NotNil)
+}
@@ -0,0 +1,80 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// NewStore creates a new schedule profile store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Store is the database for schedule profiles
+type Store interface {
+	// Get a schedule profile by id.  Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, id string) (*Profile, error)
+
+	// Put adds/updates a schedule profile
+	Put(ctx datastore.Context, val *Profile) error
+
+	// Delete removes a schedule profile
+	Delete(ctx datastore.Context, id string) error
+
+	// GetProfiles returns all of the schedule profiles
+	GetProfiles(ctx datastore.Context) ([]Profile, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// Get a schedule profile by id.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, id string) (*Profile, error) {
+	val := &Profile{}
+	if err := s.ds.Get(ctx, Key(id), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Put adds/updates a schedule profile
+func (s *storeImpl) Put(ctx datastore.Context, val *Profile) error {
+	return s.ds.Put(ctx, Key(val.ID), val)
+}
+
+// Delete removes a schedule profile
+func (s *storeImpl) Delete(ctx datastore.Context, id string) error {
+	return s.ds.Delete(ctx, Key(id))
+}
+
+// GetProfiles returns all of the schedule profiles
+func (s *storeImpl) GetProfiles(ctx datastore.Context) ([]Profile, error) {
+	query := search.Query().Search("_exists_:ID")
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	q := datastore.NewQuery(ctx)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]Profile, results.Len())
+	for i := range profiles {
+		if err := results.Get(i, &profiles[i]); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build integration
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&S{
+	ElasticTest: elastic.ElasticTest{
+		Index:    "controlplane",
+		Mappings: []elastic.Mapping{MAPPING},
+	}})
+
+type S struct {
+	elastic.ElasticTest
+	ctx   datastore.Context
+	store Store
+}
+
+func (s *S) SetUpTest(c *C) {
+	s.ElasticTest.SetUpTest(c)
+	datastore.Register(s.Driver())
+	s.ctx = datastore.Get()
+	s.store = NewStore()
+}
+
+func (s *S) Test_ProfileCRUD(c *C) {
+	expected := &Profile{
+		ID:        "profile-1",
+		ServiceID: "service-1",
+		Start:     time.Date(2016, 6, 1, 22, 0, 0, 0, time.UTC),
+		Duration:  4 * time.Hour,
+		Repeat:    RepeatDaily,
+	}
+	_, err := s.store.Get(s.ctx, expected.ID)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+
+	err = s.store.Put(s.ctx, expected)
+	c.Assert(err, IsNil)
+	actual, err := s.store.Get(s.ctx, expected.ID)
+	c.Assert(err, IsNil)
+	c.Assert(actual.ServiceID, Equals, expected.ServiceID)
+	c.Assert(actual.Duration, Equals, expected.Duration)
+
+	profiles, err := s.store.GetProfiles(s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(profiles, HasLen, 1)
+
+	err = s.store.Delete(s.ctx, expected.ID)
+	c.Assert(err, IsNil)
+	_, err = s.store.Get(s.ctx, expected.ID)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+}
+
+func (s *S) Test_ValidEntity(c *C) {
+	err := s.store.Put(s.ctx, &Profile{ID: "profile-2", ServiceID: "service-1"})
+	c.Assert(err, NotNil)
+}
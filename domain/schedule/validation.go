@@ -0,0 +1,48 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates Profile fields
+func (p *Profile) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Profile.ID", p.ID))
+	violations.Add(validation.StringsEqual(p.ID, strings.TrimSpace(p.ID), "leading and trailing spaces not allowed for schedule profile id"))
+	violations.Add(validation.NotEmpty("Profile.ServiceID", p.ServiceID))
+	if p.Start.IsZero() {
+		violations.Add(validation.NewViolation("schedule profile start time is required"))
+	}
+	if p.Duration <= 0 {
+		violations.Add(validation.NewViolation("schedule profile duration must be greater than 0"))
+	}
+	if p.Repeat != "" {
+		if err := validation.StringIn(p.Repeat, RepeatDaily, RepeatWeekly); err != nil {
+			violations.Add(err)
+		} else if p.Duration >= p.period() {
+			violations.Add(validation.NewViolation("schedule profile duration must be shorter than its repeat interval"))
+		}
+	}
+	if p.AppliedState != "" {
+		violations.Add(validation.StringIn(p.AppliedState, StateRun, StateStop))
+	}
+	if violations.HasError() {
+		return violations
+	}
+	return nil
+}
@@ -62,3 +62,43 @@ type Config struct {
 	ID       string
 	Filename string
 }
+
+// ConfigConflict describes a config file that was changed by both the user
+// and a redeploy of its template
+type ConfigConflict struct {
+	ID       string
+	Filename string
+	Base     string // content in the previous template
+	Mine     string // current content
+	Theirs   string // content in the new template
+	Merged   string // three-way merge, with conflict markers unless Clean
+	Clean    bool
+}
+
+// DeploymentStatus summarizes the health of all of the services in a
+// deployment
+type DeploymentStatus struct {
+	DeploymentID        string
+	Services            int
+	Running             int // Services that should be running
+	Stopped             int // Services that should be stopped
+	Paused              int // Services that should be paused
+	Instances           int // Instances that are scheduled
+	ExpectedInstances   int // Instances that should be scheduled
+	FailingHealthChecks []DeploymentIssue
+	UnassignedEndpoints []DeploymentIssue
+	MissingImages       []DeploymentIssue
+	PendingScheduling   []DeploymentIssue
+}
+
+// Healthy returns true if no issues were found in the deployment
+func (s DeploymentStatus) Healthy() bool {
+	return len(s.FailingHealthChecks)+len(s.UnassignedEndpoints)+len(s.MissingImages)+len(s.PendingScheduling) == 0
+}
+
+// DeploymentIssue describes a problem with a service in a deployment
+type DeploymentIssue struct {
+	ServiceID   string
+	ServiceName string
+	Detail      string
+}
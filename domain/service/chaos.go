@@ -0,0 +1,42 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import "time"
+
+// ChaosKill records a service instance that was killed by chaos mode and how
+// long it took for the service to recover
+type ChaosKill struct {
+	ServiceID   string
+	ServiceName string
+	InstanceID  int
+	HostID      string
+	KilledAt    time.Time
+	RecoveredAt time.Time     // zero if the service has not recovered
+	Recovery    time.Duration // time from the kill until the service recovered
+}
+
+// Recovered returns true if the service came back after the kill
+func (k ChaosKill) Recovered() bool {
+	return !k.RecoveredAt.IsZero()
+}
+
+// ChaosStatus describes the chaos mode settings of a resource pool
+type ChaosStatus struct {
+	PoolID   string
+	Enabled  bool
+	Interval time.Duration
+	Since    time.Time
+	Kills    []ChaosKill
+}
@@ -0,0 +1,51 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"sort"
+	"time"
+)
+
+// DiagnosticActions are built-in actions that can be run on every service
+// instance, in addition to the actions defined by the service's template.  An
+// action defined by the template with the same name takes precedence.
+var DiagnosticActions = map[string]string{
+	"diag-threaddump": `pids=$(pgrep java 2>/dev/null); [ -n "$pids" ] || echo "no java processes"; for pid in $pids; do echo "== java process $pid"; jstack $pid 2>&1; done`,
+	"diag-heap":       `pids=$(pgrep java 2>/dev/null); [ -n "$pids" ] || echo "no java processes"; for pid in $pids; do echo "== java process $pid"; jmap -histo:live $pid 2>&1; done`,
+	"diag-lsof":       `lsof -n -P 2>&1 || ls -l /proc/[0-9]*/fd 2>&1`,
+	"diag-netstat":    `netstat -anp 2>&1 || ss -anp 2>&1`,
+}
+
+// DiagnosticActionNames returns the names of the built-in diagnostic actions
+func DiagnosticActionNames() []string {
+	names := make([]string, 0, len(DiagnosticActions))
+	for name := range DiagnosticActions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DiagnosticOutput is the output of a built-in diagnostic action that was run
+// on a service instance, as saved by the host that ran it
+type DiagnosticOutput struct {
+	HostID     string
+	ServiceID  string
+	InstanceID int
+	Action     string
+	Ran        time.Time
+	Output     []byte
+	Error      string
+}
@@ -0,0 +1,250 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Operations of a DiffChange
+const (
+	DiffAdd     = "add"
+	DiffRemove  = "remove"
+	DiffReplace = "replace"
+)
+
+// DiffChange is a difference between two service definitions, as a JSON
+// patch (RFC 6902) operation that changes the first definition into the
+// second.
+type DiffChange struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// diffContext is the number of unchanged lines around each hunk of a unified
+// diff
+const diffContext = 3
+
+// definition returns a copy of the service without the fields that change
+// while it runs, so that only the definitions are compared
+func definition(svc *Service) Service {
+	def := *svc
+	def.DesiredState = 0
+	def.CreatedAt = time.Time{}
+	def.UpdatedAt = time.Time{}
+	def.DatabaseVersion = 0
+	return def
+}
+
+// Diff returns the changes that turn the definition of service a into the
+// definition of service b.  The state and timestamps of the services are not
+// compared.
+func Diff(a, b *Service) ([]DiffChange, error) {
+	var va, vb interface{}
+	for _, s := range []struct {
+		svc *Service
+		v   *interface{}
+	}{{a, &va}, {b, &vb}} {
+		data, err := json.Marshal(definition(s.svc))
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, s.v); err != nil {
+			return nil, err
+		}
+	}
+	changes := []DiffChange{}
+	diffValues("", va, vb, &changes)
+	return changes, nil
+}
+
+// diffValues appends the changes between two decoded json values
+func diffValues(path string, a, b interface{}, changes *[]DiffChange) {
+	switch ta := a.(type) {
+	case map[string]interface{}:
+		if tb, ok := b.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(ta)+len(tb))
+			for key := range ta {
+				keys = append(keys, key)
+			}
+			for key := range tb {
+				if _, ok := ta[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				keyPath := path + "/" + escapePointer(key)
+				va, inA := ta[key]
+				vb, inB := tb[key]
+				switch {
+				case !inB:
+					*changes = append(*changes, DiffChange{Op: DiffRemove, Path: keyPath})
+				case !inA:
+					*changes = append(*changes, DiffChange{Op: DiffAdd, Path: keyPath, Value: vb})
+				default:
+					diffValues(keyPath, va, vb, changes)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if tb, ok := b.([]interface{}); ok {
+			for i := 0; i < len(ta) && i < len(tb); i++ {
+				diffValues(path+"/"+strconv.Itoa(i), ta[i], tb[i], changes)
+			}
+			for i := len(ta); i < len(tb); i++ {
+				*changes = append(*changes, DiffChange{Op: DiffAdd, Path: path + "/" + strconv.Itoa(i), Value: tb[i]})
+			}
+			// remove from the end so that the indexes stay valid
+			for i := len(ta) - 1; i >= len(tb); i-- {
+				*changes = append(*changes, DiffChange{Op: DiffRemove, Path: path + "/" + strconv.Itoa(i)})
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, DiffChange{Op: DiffReplace, Path: path, Value: b})
+	}
+}
+
+// escapePointer escapes a key for use in a JSON pointer
+func escapePointer(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
+
+// UnifiedDiff renders the difference between the definitions of services a
+// and b as a unified diff of their json, labelled with the names of the
+// services.  It returns an empty string if the definitions are the same.
+func UnifiedDiff(nameA, nameB string, a, b *Service) (string, error) {
+	var lines [2][]string
+	for i, svc := range []*Service{a, b} {
+		data, err := json.MarshalIndent(definition(svc), "", "  ")
+		if err != nil {
+			return "", err
+		}
+		lines[i] = strings.Split(string(data), "\n")
+	}
+	return unifiedDiff(nameA, nameB, lines[0], lines[1]), nil
+}
+
+// diffLine is a line of a unified diff: ' ' for context, '-' for a line of
+// the first text and '+' for a line of the second
+type diffLine struct {
+	op   byte
+	text string
+	a, b int // line numbers in the texts, from 0
+}
+
+// unifiedDiff renders the difference between two texts as a unified diff
+func unifiedDiff(nameA, nameB string, a, b []string) string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var script []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			script = append(script, diffLine{op: ' ', text: a[i], a: i, b: j})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			script = append(script, diffLine{op: '+', text: b[j], a: i, b: j})
+			j++
+		default:
+			script = append(script, diffLine{op: '-', text: a[i], a: i, b: j})
+			i++
+		}
+	}
+
+	var buf bytes.Buffer
+	for start := 0; start < len(script); {
+		// find the next change
+		for start < len(script) && script[start].op == ' ' {
+			start++
+		}
+		if start == len(script) {
+			break
+		}
+
+		// extend the hunk until there are more than twice the context of
+		// unchanged lines between changes
+		end := start
+		for k := start; k < len(script); k++ {
+			if script[k].op != ' ' {
+				end = k + 1
+			} else if k-end >= 2*diffContext {
+				break
+			}
+		}
+		from, to := start-diffContext, end+diffContext
+		if from < 0 {
+			from = 0
+		}
+		if to > len(script) {
+			to = len(script)
+		}
+
+		if buf.Len() == 0 {
+			fmt.Fprintf(&buf, "--- %s\n+++ %s\n", nameA, nameB)
+		}
+		countA, countB := 0, 0
+		for _, line := range script[from:to] {
+			if line.op != '+' {
+				countA++
+			}
+			if line.op != '-' {
+				countB++
+			}
+		}
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n", hunkRange(script[from].a, countA), hunkRange(script[from].b, countB))
+		for _, line := range script[from:to] {
+			fmt.Fprintf(&buf, "%c%s\n", line.op, line.text)
+		}
+		start = to
+	}
+	return buf.String()
+}
+
+// hunkRange formats the start line and number of lines of a hunk
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,82 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "gopkg.in/check.v1"
+)
+
+func (s *ServiceDomainUnitTestSuite) TestDiff(c *C) {
+	a := &service.Service{
+		ID:           "svc",
+		Name:         "zope",
+		Instances:    1,
+		Tags:         []string{"a", "b", "c"},
+		DesiredState: int(service.SVCRun),
+		ConfigFiles: map[string]servicedefinition.ConfigFile{
+			"/etc/zope.conf": {Filename: "/etc/zope.conf", Content: "old"},
+		},
+	}
+	b := *a
+	b.Instances = 2
+	b.Tags = []string{"a", "d"}
+	b.DesiredState = int(service.SVCStop)
+	b.UpdatedAt = time.Now()
+	b.ConfigFiles = map[string]servicedefinition.ConfigFile{
+		"/etc/zope.conf": {Filename: "/etc/zope.conf", Content: "new"},
+	}
+	b.Environment = []string{"FOO=bar"}
+
+	changes, err := service.Diff(a, &b)
+	c.Assert(err, IsNil)
+	c.Assert(changes, DeepEquals, []service.DiffChange{
+		{Op: service.DiffReplace, Path: "/ConfigFiles/~1etc~1zope.conf/Content", Value: "new"},
+		{Op: service.DiffReplace, Path: "/Environment", Value: []interface{}{"FOO=bar"}},
+		{Op: service.DiffReplace, Path: "/Instances", Value: float64(2)},
+		{Op: service.DiffReplace, Path: "/Tags/1", Value: "d"},
+		{Op: service.DiffRemove, Path: "/Tags/2"},
+	})
+
+	changes, err = service.Diff(a, a)
+	c.Assert(err, IsNil)
+	c.Assert(changes, HasLen, 0)
+}
+
+func (s *ServiceDomainUnitTestSuite) TestUnifiedDiff(c *C) {
+	a := &service.Service{ID: "svc", Name: "zope", Instances: 1}
+	b := *a
+	b.Instances = 2
+	b.DesiredState = int(service.SVCRun)
+
+	diff, err := service.UnifiedDiff("snapshot", "service", a, &b)
+	c.Assert(err, IsNil)
+	lines := strings.Split(diff, "\n")
+	c.Assert(len(lines) > 4, Equals, true)
+	c.Check(lines[0], Equals, "--- snapshot")
+	c.Check(lines[1], Equals, "+++ service")
+	c.Check(lines[2], Matches, `@@ -\d+,7 \+\d+,7 @@`)
+	c.Check(lines[6], Equals, `-  "Instances": 1,`)
+	c.Check(lines[7], Equals, `+  "Instances": 2,`)
+
+	diff, err = service.UnifiedDiff("snapshot", "service", a, a)
+	c.Assert(err, IsNil)
+	c.Check(diff, Equals, "")
+}
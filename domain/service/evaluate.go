@@ -17,8 +17,6 @@ import (
 	"fmt"
 	"strconv"
 
-	"github.com/zenoss/glog"
-
 	"bytes"
 	"runtime"
 	"strings"
@@ -73,7 +71,7 @@ func getContext(gs GetService) func(s *runtimeContext, key string) (interface{},
 		ctx := make(map[string]interface{})
 		err := flattenContext(s.Service, gs, "", &ctx)
 		if err != nil {
-			glog.Errorf("Flattening context for %s (%s): %s", s.Name, s.ID, err)
+			plog.Errorf("Flattening context for %s (%s): %s", s.Name, s.ID, err)
 		}
 
 		return ctx[key], err
@@ -85,7 +83,7 @@ func context(gs GetService) func(s *runtimeContext) (map[string]interface{}, err
 		ctx := make(map[string]interface{})
 		err := flattenContext(s.Service, gs, "", &ctx)
 		if err != nil {
-			glog.Errorf("Flattening context for %s (%s): %s", s.Name, s.ID, err)
+			plog.Errorf("Flattening context for %s (%s): %s", s.Name, s.ID, err)
 		}
 		return ctx, err
 	}
@@ -96,12 +94,26 @@ func contextFilter(gs GetService) func(s *runtimeContext, prefix string) (map[st
 		ctx := make(map[string]interface{})
 		err := flattenContext(s.Service, gs, prefix, &ctx)
 		if err != nil {
-			glog.Errorf("Flattening context for %s (%s prefix:%s): %s", s.Name, s.ID, prefix, err)
+			plog.Errorf("Flattening context for %s (%s prefix:%s): %s", s.Name, s.ID, prefix, err)
 		}
 		return ctx, err
 	}
 }
 
+// assignedIP returns the ip address assigned to the named endpoint of the
+// service.
+func assignedIP(s *runtimeContext, endpointName string) (string, error) {
+	for _, ep := range s.Endpoints {
+		if ep.Name == endpointName {
+			if ep.AddressAssignment.IPAddr == "" {
+				return "", fmt.Errorf("endpoint %s of service %s has no address assignment", endpointName, s.Name)
+			}
+			return ep.AddressAssignment.IPAddr, nil
+		}
+	}
+	return "", fmt.Errorf("service %s has no endpoint %s", s.Name, endpointName)
+}
+
 // EvaluateActionsTemplate parses and evaluates the Actions string of a service.
 func (service *Service) EvaluateActionsTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
 	for key, value := range service.Actions {
@@ -201,6 +213,7 @@ func (service *Service) evaluateTemplate(gs GetService, fc FindChildService, ins
 		"plus":          plus,
 		"uintToInt":     uintToInt,
 		"each":          each,
+		"assignedIP":    assignedIP,
 	}
 
 	// parse the template
@@ -216,7 +229,7 @@ func (service *Service) evaluateTemplate(gs GetService, fc FindChildService, ins
 	}
 
 	// something went wrong, warn them
-	glog.Warningf("Evaluating template %v produced the following error %v ", serviceTemplate, err)
+	plog.Warningf("Evaluating template %v produced the following error %v ", serviceTemplate, err)
 	return
 }
 
@@ -260,7 +273,7 @@ func (service *Service) EvaluateLogConfigTemplate(gs GetService, fc FindChildSer
 // EvaluateConfigFilesTemplate parses and evals the Filename and Content. This happens for each
 // ConfigFile on the service.
 func (service *Service) EvaluateConfigFilesTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
-	glog.V(3).Infof("Evaluating Config Files for %s:%d", service.ID, instanceID)
+	plog.Debugf("Evaluating Config Files for %s:%d", service.ID, instanceID)
 	for key, configFile := range service.ConfigFiles {
 		// Filename
 		err, result := service.evaluateTemplate(gs, fc, instanceID, configFile.Filename)
@@ -285,7 +298,7 @@ func (service *Service) EvaluateConfigFilesTemplate(gs GetService, fc FindChildS
 
 // EvaluatePrereqsTemplate parses and evals the Script field for each Prereq.
 func (service *Service) EvaluatePrereqsTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
-	glog.V(3).Infof("Evaluating Prereq scripts for %s:%d", service.ID, instanceID)
+	plog.Debugf("Evaluating Prereq scripts for %s:%d", service.ID, instanceID)
 	for i, prereq := range service.Prereqs {
 		err, result := service.evaluateTemplate(gs, fc, instanceID, prereq.Script)
 		if err != nil {
@@ -301,7 +314,7 @@ func (service *Service) EvaluatePrereqsTemplate(gs GetService, fc FindChildServi
 
 // EvaluateHealthCheckTemplate parses and evals the Script field for each HealthCheck.
 func (service *Service) EvaluateHealthCheckTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
-	glog.V(3).Infof("Evaluating HealthCheck scripts for %s:%d", service.ID, instanceID)
+	plog.Debugf("Evaluating HealthCheck scripts for %s:%d", service.ID, instanceID)
 	for key, healthcheck := range service.HealthChecks {
 		err, result := service.evaluateTemplate(gs, fc, instanceID, healthcheck.Script)
 		if err != nil {
@@ -405,6 +418,21 @@ func (service *Service) EvaluateEnvironmentTemplate(gs GetService, fc FindChildS
 	return
 }
 
+// EvaluateNetworkTemplate parses and evaluates the DNS, DNSSearch and
+// ExtraHosts properties of this service.
+func (service *Service) EvaluateNetworkTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
+	for _, values := range [][]string{service.DNS, service.DNSSearch, service.ExtraHosts} {
+		for i, value := range values {
+			err, result := service.evaluateTemplate(gs, fc, instanceID, value)
+			if err != nil {
+				return err
+			}
+			values[i] = result
+		}
+	}
+	return
+}
+
 // runtimeContext wraps a service and adds extra fields for template evaluation.
 type runtimeContext struct {
 	Service
@@ -430,47 +458,51 @@ func newRuntimeContext(svc *Service, instanceID int) *runtimeContext {
 // as an extra attribute.
 func (service *Service) Evaluate(getSvc GetService, findChild FindChildService, instanceID int) (err error) {
 	if err = service.EvaluateEndpointTemplates(getSvc, findChild, instanceID); err != nil {
-		glog.Errorf("%+v", err)
+		plog.Errorf("%+v", err)
 		return err
 	}
 	if err = service.EvaluateLogConfigTemplate(getSvc, findChild, instanceID); err != nil {
-		glog.Errorf("%+v", err)
+		plog.Errorf("%+v", err)
 		return err
 	}
 	if err = service.EvaluateConfigFilesTemplate(getSvc, findChild, instanceID); err != nil {
-		glog.Errorf("%+v", err)
+		plog.Errorf("%+v", err)
 		return err
 	}
 	if err = service.EvaluateStartupTemplate(getSvc, findChild, instanceID); err != nil {
-		glog.Errorf("%+v", err)
+		plog.Errorf("%+v", err)
 		return err
 	}
 	if err = service.EvaluateRunsTemplate(getSvc, findChild); err != nil {
-		glog.Errorf("%+v", err)
+		plog.Errorf("%+v", err)
 		return err
 	}
 	if err = service.EvaluateActionsTemplate(getSvc, findChild, instanceID); err != nil {
-		glog.Errorf("%+v", err)
+		plog.Errorf("%+v", err)
 		return err
 	}
 	if err = service.EvaluateHostnameTemplate(getSvc, findChild, instanceID); err != nil {
-		glog.Errorf("%+v", err)
+		plog.Errorf("%+v", err)
 		return err
 	}
 	if err = service.EvaluateVolumesTemplate(getSvc, findChild, instanceID); err != nil {
-		glog.Errorf("%+v", err)
+		plog.Errorf("%+v", err)
 		return err
 	}
 	if err = service.EvaluatePrereqsTemplate(getSvc, findChild, instanceID); err != nil {
-		glog.Errorf("%+v", err)
+		plog.Errorf("%+v", err)
 		return err
 	}
 	if err = service.EvaluateHealthCheckTemplate(getSvc, findChild, instanceID); err != nil {
-		glog.Errorf("%+v", err)
+		plog.Errorf("%+v", err)
 		return err
 	}
 	if err = service.EvaluateEnvironmentTemplate(getSvc, findChild, instanceID); err != nil {
-		glog.Errorf("%+v", err)
+		plog.Errorf("%+v", err)
+		return err
+	}
+	if err = service.EvaluateNetworkTemplate(getSvc, findChild, instanceID); err != nil {
+		plog.Errorf("%+v", err)
 		return err
 	}
 	return nil
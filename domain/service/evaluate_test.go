@@ -16,6 +16,7 @@
 package service_test
 
 import (
+	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/service"
 	. "gopkg.in/check.v1"
 )
@@ -40,3 +41,28 @@ func (s *ServiceDomainUnitTestSuite) TestRound(c *C) {
 		c.Assert(service.Round(test.value), Equals, test.expected)
 	}
 }
+
+func (s *ServiceDomainUnitTestSuite) TestEvaluateNetworkTemplate(c *C) {
+	svc := service.Service{
+		ID:   "svcnetwork",
+		Name: "svcnetwork",
+		Endpoints: []service.ServiceEndpoint{
+			{
+				Name:              "ldap",
+				AddressAssignment: addressassignment.AddressAssignment{IPAddr: "10.20.30.40"},
+			},
+		},
+		DNS:        []string{"10.0.0.2"},
+		DNSSearch:  []string{"corp.example.com"},
+		ExtraHosts: []string{"ldap.corp.example.com:{{assignedIP . \"ldap\"}}"},
+	}
+	err := svc.EvaluateNetworkTemplate(nil, nil, 0)
+	c.Assert(err, IsNil)
+	c.Assert(svc.DNS, DeepEquals, []string{"10.0.0.2"})
+	c.Assert(svc.DNSSearch, DeepEquals, []string{"corp.example.com"})
+	c.Assert(svc.ExtraHosts, DeepEquals, []string{"ldap.corp.example.com:10.20.30.40"})
+
+	svc.ExtraHosts = []string{"db:{{assignedIP . \"db\"}}"}
+	err = svc.EvaluateNetworkTemplate(nil, nil, 0)
+	c.Assert(err, NotNil)
+}
@@ -14,6 +14,7 @@
 package service
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/control-center/serviced/domain/servicedefinition"
@@ -39,6 +40,19 @@ type Usage struct {
 	Avg int64
 }
 
+// InstanceUsage is the resource usage of an instance as last reported by the
+// host that runs it
+type InstanceUsage struct {
+	Memory     Usage
+	CPUPercent float64
+	Updated    time.Time
+}
+
+// InstanceUsageKey returns the key of an instance in a host's usage report
+func InstanceUsageKey(serviceID string, instanceID int) string {
+	return fmt.Sprintf("%s-%d", serviceID, instanceID)
+}
+
 // Instance describes an instance of a service
 type Instance struct {
 	InstanceID    int
@@ -53,9 +67,44 @@ type Instance struct {
 	HealthStatus  map[string]health.Status
 	RAMCommitment int64
 	MemoryUsage   Usage
+	CPUUsage      float64   // Percent of a single cpu
+	UsageUpdated  time.Time // When the usage was last reported; zero if never
 	Scheduled     time.Time
 	Started       time.Time
 	Terminated    time.Time
+	Exits         []InstanceExit // most recent container exits, oldest first
+}
+
+// InstanceExit describes how a container of a service instance exited
+type InstanceExit struct {
+	Terminated time.Time
+	ExitCode   int
+	OOMKilled  bool
+	Output     []string // final lines of the container output
+}
+
+// MaxInstanceExits is the number of container exits kept in the history of an
+// instance
+const MaxInstanceExits = 5
+
+// AppendInstanceExit adds an exit to the history of an instance, dropping the
+// oldest exits beyond MaxInstanceExits.
+func AppendInstanceExit(exits []InstanceExit, exit InstanceExit) []InstanceExit {
+	exits = append(exits, exit)
+	if len(exits) > MaxInstanceExits {
+		exits = exits[len(exits)-MaxInstanceExits:]
+	}
+	return exits
+}
+
+// Reason returns a short description of why the container exited
+func (e InstanceExit) Reason() string {
+	if e.OOMKilled {
+		return fmt.Sprintf("out of memory (exit %d)", e.ExitCode)
+	} else if e.ExitCode != 0 {
+		return fmt.Sprintf("exit %d", e.ExitCode)
+	}
+	return "exited"
 }
 
 // StrategyInstance collects service strategy information about a service
@@ -75,6 +124,23 @@ type LocationInstance struct {
 	ContainerID string
 }
 
+// InstanceOutput is the stdout and stderr of the most recently exited
+// container of a service instance, as buffered by the host that ran it
+type InstanceOutput struct {
+	HostID     string
+	ServiceID  string
+	InstanceID int
+	Exited     time.Time
+	Output     []byte
+}
+
+// LogLine is a line written by the container of a service instance
+type LogLine struct {
+	Time   time.Time
+	Stderr bool
+	Text   string
+}
+
 // StatusInstance is an abbreviated version of the above instance data,
 // designed to be polled at a high frequency and attached to a service
 type StatusInstance struct {
@@ -0,0 +1,37 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+func (s *ServiceDomainUnitTestSuite) TestInstanceExitReason(c *C) {
+	c.Assert(service.InstanceExit{}.Reason(), Equals, "exited")
+	c.Assert(service.InstanceExit{ExitCode: 1}.Reason(), Equals, "exit 1")
+	c.Assert(service.InstanceExit{ExitCode: 137, OOMKilled: true}.Reason(), Equals, "out of memory (exit 137)")
+}
+
+func (s *ServiceDomainUnitTestSuite) TestAppendInstanceExit(c *C) {
+	var exits []service.InstanceExit
+	for i := 0; i < service.MaxInstanceExits+2; i++ {
+		exits = service.AppendInstanceExit(exits, service.InstanceExit{ExitCode: i})
+	}
+	c.Assert(exits, HasLen, service.MaxInstanceExits)
+	c.Assert(exits[0].ExitCode, Equals, 2)
+	c.Assert(exits[service.MaxInstanceExits-1].ExitCode, Equals, service.MaxInstanceExits+1)
+}
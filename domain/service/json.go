@@ -18,7 +18,6 @@ import (
 
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/servicedefinition"
-	"github.com/zenoss/glog"
 )
 
 type service Service
@@ -58,7 +57,7 @@ func (e *ServiceEndpoint) UnmarshalJSON(b []byte) error {
 	} else {
 		return err
 	}
-	glog.V(4).Infof("ServiceEndpoint UnmarshalJSON %#v", e)
+	plog.Debugf("ServiceEndpoint UnmarshalJSON %#v", e)
 	if len(e.VHostList) > 0 {
 		//VHostList is defined, keep it and unset deprecated field if set
 		e.VHosts = nil
@@ -66,13 +65,11 @@ func (e *ServiceEndpoint) UnmarshalJSON(b []byte) error {
 	}
 	if len(e.VHosts) > 0 {
 		// no VHostsList but vhosts is defined. Convert to VHostsList
-		if glog.V(2) {
-			glog.Warningf("ServiceEndpoint VHosts field is deprecated, see VHostList: %#v", e.VHosts)
-		}
+		plog.Debugf("ServiceEndpoint VHosts field is deprecated, see VHostList: %#v", e.VHosts)
 		for _, vhost := range e.VHosts {
 			e.VHostList = append(e.VHostList, servicedefinition.VHost{Name: vhost, Enabled: true})
 		}
-		glog.V(2).Infof("VHostList %#v converted from VHosts %#v", e.VHostList, e.VHosts)
+		plog.Debugf("VHostList %#v converted from VHosts %#v", e.VHostList, e.VHosts)
 		e.VHosts = nil
 	}
 	return nil
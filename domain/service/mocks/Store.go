@@ -153,6 +153,48 @@ func (_m *Store) GetServicesByPool(ctx datastore.Context, poolID string) ([]serv
 
 	return r0, r1
 }
+func (_m *Store) GetServicesWithPublicPorts(ctx datastore.Context, poolID string) ([]service.Service, error) {
+	ret := _m.Called(ctx, poolID)
+
+	var r0 []service.Service
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []service.Service); ok {
+		r0 = rf(ctx, poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *Store) GetServicesByIDs(ctx datastore.Context, ids ...string) ([]service.Service, error) {
+	ret := _m.Called(ctx, ids)
+
+	var r0 []service.Service
+	if rf, ok := ret.Get(0).(func(datastore.Context, ...string) []service.Service); ok {
+		r0 = rf(ctx, ids...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, ...string) error); ok {
+		r1 = rf(ctx, ids...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *Store) GetServicesByDeployment(ctx datastore.Context, deploymentID string) ([]service.Service, error) {
 	ret := _m.Called(ctx, deploymentID)
 
@@ -258,6 +300,27 @@ func (_m *Store) GetAllServiceDetails(ctx datastore.Context) ([]service.ServiceD
 
 	return r0, r1
 }
+func (_m *Store) GetServiceSummaries(ctx datastore.Context) ([]service.ServiceSummary, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []service.ServiceSummary
+	if rf, ok := ret.Get(0).(func(datastore.Context) []service.ServiceSummary); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceSummary)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *Store) GetServiceDetails(ctx datastore.Context, serviceID string) (*service.ServiceDetails, error) {
 	ret := _m.Called(ctx, serviceID)
 
@@ -0,0 +1,50 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import "strings"
+
+// Priority classes of services, from highest to lowest.  Higher priority
+// services start first after the master restarts and stop last when a host is
+// drained or storage runs low.  A service without a priority is normal.
+const (
+	PriorityCritical = "critical"
+	PriorityHigh     = "high"
+	PriorityNormal   = "normal"
+	PriorityLow      = "low"
+)
+
+// Priorities lists the priority classes from highest to lowest
+var Priorities = []string{PriorityCritical, PriorityHigh, PriorityNormal, PriorityLow}
+
+// PriorityRank returns the rank of a priority class, where 0 is the highest,
+// and false if the priority is not a known class.
+func PriorityRank(priority string) (int, bool) {
+	if priority == "" {
+		priority = PriorityNormal
+	}
+	for i, p := range Priorities {
+		if strings.EqualFold(priority, p) {
+			return i, true
+		}
+	}
+	return len(Priorities), false
+}
+
+// PriorityRank returns the rank of the service's priority class; an unknown
+// priority ranks below the lowest class.
+func (s *Service) PriorityRank() int {
+	rank, _ := PriorityRank(s.Priority)
+	return rank
+}
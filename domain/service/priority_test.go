@@ -0,0 +1,44 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+func (s *ServiceDomainUnitTestSuite) TestPriorityRank(c *C) {
+	rank, ok := service.PriorityRank(service.PriorityCritical)
+	c.Assert(ok, Equals, true)
+	c.Assert(rank, Equals, 0)
+
+	rank, ok = service.PriorityRank("LOW")
+	c.Assert(ok, Equals, true)
+	c.Assert(rank, Equals, 3)
+
+	normal, ok := service.PriorityRank(service.PriorityNormal)
+	c.Assert(ok, Equals, true)
+	rank, ok = service.PriorityRank("")
+	c.Assert(ok, Equals, true)
+	c.Assert(rank, Equals, normal)
+
+	rank, ok = service.PriorityRank("urgent")
+	c.Assert(ok, Equals, false)
+	c.Assert(rank, Equals, len(service.Priorities))
+
+	svc := service.Service{Priority: service.PriorityHigh}
+	c.Assert(svc.PriorityRank(), Equals, 1)
+}
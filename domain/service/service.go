@@ -27,7 +27,6 @@ import (
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 )
 
 // Desired states of services.
@@ -83,6 +82,7 @@ type Service struct {
 	Endpoints         []ServiceEndpoint
 	ParentServiceID   string
 	Volumes           []servicedefinition.Volume
+	BackupExcludes    []string // resource paths or path globs in the tenant volume to leave out of backups
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	DeploymentID      string
@@ -101,6 +101,44 @@ type Service struct {
 	MemoryLimit       float64
 	CPUShares         int64
 	PIDFile           string
+	Priority          string // Priority class: critical, high, normal (the default), or low
+	// EmergencyShutdownLevel orders services in an emergency shutdown; lower
+	// levels are stopped first and level 0 is stopped last.
+	EmergencyShutdownLevel int
+	// EmergencyShutdown is set when the service was stopped because storage
+	// ran low; the service cannot be started until the flag is cleared.
+	EmergencyShutdown bool
+	// AddressBlocked explains why the service cannot be started after the ip
+	// of its address assignment was lost and no other ip was available.  It
+	// is cleared when the service is assigned an ip.
+	AddressBlocked string
+	// AutoRollback rolls the tenant back to the snapshot taken before a
+	// commit or image upgrade if the service fails its health checks while
+	// the change is being verified.
+	AutoRollback bool
+	// Ulimits, Sysctls, CapAdd, CapDrop and SeccompProfile are applied to
+	// the service's containers in place of docker's defaults.
+	Ulimits        []servicedefinition.Ulimit
+	Sysctls        map[string]string
+	CapAdd         []string
+	CapDrop        []string
+	SeccompProfile string
+	// DNS, DNSSearch and ExtraHosts configure name resolution in the
+	// service's containers and may be templated.
+	DNS        []string
+	DNSSearch  []string
+	ExtraHosts []string
+	// NetworkMode is bridge (the default), host, or macvlan
+	NetworkMode string
+	ShmSize     utils.EngNotation
+	Tmpfs       []servicedefinition.TmpfsMount
+	// ReadOnlyRootfs mounts the root filesystem of the service's containers
+	// read-only.  UsernsMode is "host" to opt out of the docker daemon's
+	// user namespace remapping.
+	ReadOnlyRootfs bool
+	UsernsMode     string
+	// Placement pins the service's instances to hosts
+	Placement servicedefinition.Placement
 	datastore.VersionedEntity
 }
 
@@ -120,6 +158,7 @@ type ServiceEndpoint struct {
 	VHostList         []servicedefinition.VHost // VHost is used to request named vhost(s) for this endpoint.
 	AddressAssignment addressassignment.AddressAssignment
 	PortList          []servicedefinition.Port // The list of enabled/disabled ports to assign to this endpoint.
+	CircuitBreaker    servicedefinition.CircuitBreaker
 }
 
 // IsConfigurable returns true if the endpoint is configurable
@@ -164,6 +203,7 @@ func BuildServiceEndpoint(epd servicedefinition.EndpointDefinition) ServiceEndpo
 	sep.VHosts = epd.VHosts
 	sep.VHostList = epd.VHostList
 	sep.PortList = epd.PortList
+	sep.CircuitBreaker = epd.CircuitBreaker
 
 	// run public ports through scrubber to allow for "almost correct" port addresses
 	for index, port := range sep.PortList {
@@ -211,6 +251,7 @@ func BuildService(sd servicedefinition.ServiceDefinition, parentServiceID string
 	svc.CreatedAt = now
 	svc.UpdatedAt = now
 	svc.Volumes = sd.Volumes
+	svc.BackupExcludes = sd.BackupExcludes
 	svc.DeploymentID = deploymentID
 	svc.LogConfigs = sd.LogConfigs
 	svc.Snapshot = sd.Snapshot
@@ -223,6 +264,23 @@ func BuildService(sd servicedefinition.ServiceDefinition, parentServiceID string
 	svc.HealthChecks = sd.HealthChecks
 	svc.Prereqs = sd.Prereqs
 	svc.PIDFile = sd.PIDFile
+	svc.Priority = sd.Priority
+	svc.EmergencyShutdownLevel = sd.EmergencyShutdownLevel
+	svc.AutoRollback = sd.AutoRollback
+	svc.Ulimits = sd.Ulimits
+	svc.Sysctls = sd.Sysctls
+	svc.CapAdd = sd.CapAdd
+	svc.CapDrop = sd.CapDrop
+	svc.SeccompProfile = sd.SeccompProfile
+	svc.DNS = sd.DNS
+	svc.DNSSearch = sd.DNSSearch
+	svc.ExtraHosts = sd.ExtraHosts
+	svc.NetworkMode = sd.NetworkMode
+	svc.ShmSize = sd.ShmSize
+	svc.Tmpfs = sd.Tmpfs
+	svc.ReadOnlyRootfs = sd.ReadOnlyRootfs
+	svc.UsernsMode = sd.UsernsMode
+	svc.Placement = sd.Placement
 
 	svc.Endpoints = make([]ServiceEndpoint, 0)
 	for _, ep := range sd.Endpoints {
@@ -253,6 +311,7 @@ func CloneService(fromSvc *Service, suffix string) (*Service, error) {
 	svc := *fromSvc
 	svc.ID = svcuuid
 	svc.DesiredState = int(SVCStop)
+	svc.EmergencyShutdown = false
 
 	now := time.Now()
 	svc.CreatedAt = now
@@ -271,6 +330,17 @@ func CloneService(fromSvc *Service, suffix string) (*Service, error) {
 			svc.Endpoints[idx].ApplicationTemplate += suffix
 		}
 	}
+	// keep excluding the clone's volumes from backups
+	svc.BackupExcludes = make([]string, len(fromSvc.BackupExcludes))
+	for idx, pattern := range fromSvc.BackupExcludes {
+		for _, vol := range fromSvc.Volumes {
+			if pattern == vol.ResourcePath {
+				pattern += suffix
+				break
+			}
+		}
+		svc.BackupExcludes[idx] = pattern
+	}
 	for idx := range svc.Volumes {
 		svc.Volumes[idx].ResourcePath += suffix
 	}
@@ -486,7 +556,7 @@ func (s *Service) EnablePort(application string, portAddr string, enable bool) e
 				if port.PortAddr == portAddr {
 					portFound = true
 					ep.PortList[i].Enabled = enable
-					glog.V(1).Infof("Enable port %s for %s %s set to %v", portAddr, s.ID, application, enable)
+					plog.Debugf("Enable port %s for %s %s set to %v", portAddr, s.ID, application, enable)
 				}
 			}
 		}
@@ -525,7 +595,7 @@ func (s *Service) EnableVirtualHost(application, vhostName string, enable bool)
 				if vhost.Name == vhostName {
 					vhostFound = true
 					ep.VHostList[i].Enabled = enable
-					glog.V(1).Infof("enable vhost %s for %s %s set to %v", vhostName, s.ID, application, enable)
+					plog.Debugf("enable vhost %s for %s %s set to %v", vhostName, s.ID, application, enable)
 				}
 			}
 		}
@@ -672,3 +742,13 @@ func (s *Service) Equals(b *Service) bool {
 	}
 	return true
 }
+
+// MemoryMounts returns the number of bytes of memory that the shared memory
+// and tmpfs mounts of the service's containers may use
+func (s *Service) MemoryMounts() uint64 {
+	total := s.ShmSize.Value
+	for _, mount := range s.Tmpfs {
+		total += mount.Size.Value
+	}
+	return total
+}
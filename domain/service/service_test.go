@@ -252,3 +252,29 @@ func TestScrubPortString(t *testing.T) {
 		}
 	}
 }
+
+func TestCloneServiceBackupExcludes(t *testing.T) {
+	sd := servicedefinition.ServiceDefinition{
+		Name:           "svc",
+		Volumes:        []servicedefinition.Volume{{ResourcePath: "cache", ContainerPath: "/cache"}},
+		BackupExcludes: []string{"cache", "logs/*.log"},
+	}
+	svc, err := BuildService(sd, "", "default", 0, "")
+	if err != nil {
+		t.Fatalf("BuildService Failed w/err=%s", err)
+	}
+	if len(svc.BackupExcludes) != 2 {
+		t.Fatalf("Expected backup excludes to be copied, got %v", svc.BackupExcludes)
+	}
+
+	clone, err := CloneService(svc, "-clone")
+	if err != nil {
+		t.Fatalf("CloneService Failed w/err=%s", err)
+	}
+	if clone.BackupExcludes[0] != "cache-clone" || clone.BackupExcludes[1] != "logs/*.log" {
+		t.Errorf("Unexpected backup excludes for clone: %v", clone.BackupExcludes)
+	}
+	if svc.BackupExcludes[0] != "cache" {
+		t.Errorf("Cloning modified the backup excludes of the original: %v", svc.BackupExcludes)
+	}
+}
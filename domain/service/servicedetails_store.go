@@ -19,6 +19,7 @@ import (
 
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/datastore/elastic"
+	"github.com/control-center/serviced/validation"
 )
 
 // GetAllServiceDetails returns service details for an id
@@ -29,8 +30,9 @@ func (s *storeImpl) GetAllServiceDetails(ctx datastore.Context) ([]ServiceDetail
 				"query": "_exists_:ID",
 			},
 		},
-		"fields": serviceDetailsFields,
-		"size":   serviceDetailsLimit,
+		"fields":  serviceDetailsFields,
+		"size":    serviceDetailsLimit,
+		"version": true,
 	})
 
 	results, err := datastore.NewQuery(ctx).Execute(searchRequest)
@@ -60,6 +62,35 @@ func (s *storeImpl) GetAllServiceDetails(ctx datastore.Context) ([]ServiceDetail
 	return details, nil
 }
 
+// GetServiceSummaries returns the summaries of all services, with their paths
+func (s *storeImpl) GetServiceSummaries(ctx datastore.Context) ([]ServiceSummary, error) {
+	searchRequest := newServiceDetailsElasticRequest(map[string]interface{}{
+		"query": map[string]interface{}{
+			"query_string": map[string]string{
+				"query": "_exists_:ID",
+			},
+		},
+		"fields": serviceSummaryFields,
+		"size":   serviceDetailsLimit,
+	})
+
+	results, err := datastore.NewQuery(ctx).Execute(searchRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []ServiceSummary{}
+	for results.HasNext() {
+		var entity serviceSummaryEntity
+		if err := results.Next(&entity); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, entity.ServiceSummary)
+	}
+	SetSummaryPaths(summaries)
+	return summaries, nil
+}
+
 // GetServiceDetails returns service details for an id
 func (s *storeImpl) GetServiceDetails(ctx datastore.Context, serviceID string) (*ServiceDetails, error) {
 	id := strings.TrimSpace(serviceID)
@@ -108,8 +139,9 @@ func (s *storeImpl) GetServiceDetailsByParentID(ctx datastore.Context, parentID
 		"query": map[string]interface{}{
 			"term": map[string]string{"ParentServiceID": parentID},
 		},
-		"fields": serviceDetailsFields,
-		"size":   serviceDetailsLimit,
+		"fields":  serviceDetailsFields,
+		"size":    serviceDetailsLimit,
+		"version": true,
 	})
 
 	results, err := datastore.NewQuery(ctx).Execute(searchRequest)
@@ -179,3 +211,27 @@ var serviceDetailsFields = []string{
 	"RAMCommitment",
 	"Startup",
 }
+
+// serviceSummaryEntity wraps a summary so it can be read from the datastore
+type serviceSummaryEntity struct {
+	ServiceSummary
+	datastore.VersionedEntity
+}
+
+// ValidEntity validates the summary read from the datastore
+func (e *serviceSummaryEntity) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("ID", e.ID))
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
+
+var serviceSummaryFields = []string{
+	"ID",
+	"Name",
+	"DeploymentID",
+	"PoolID",
+	"ParentServiceID",
+}
@@ -15,7 +15,6 @@ package service
 
 import (
 	"github.com/control-center/serviced/datastore/elastic"
-	"github.com/zenoss/glog"
 )
 
 var (
@@ -89,6 +88,6 @@ var (
 
 func init() {
 	if mappingError != nil {
-		glog.Fatalf("error creating service mapping: %v", mappingError)
+		plog.Fatalf("error creating service mapping: %v", mappingError)
 	}
 }
@@ -64,6 +64,12 @@ type Store interface {
 	// GetServicesByPool returns services with the given pool id
 	GetServicesByPool(ctx datastore.Context, poolID string) ([]Service, error)
 
+	// GetServicesWithPublicPorts returns the services in the given pool that have an enabled public port
+	GetServicesWithPublicPorts(ctx datastore.Context, poolID string) ([]Service, error)
+
+	// GetServicesByIDs returns the services with the given ids; ids that are not found are skipped
+	GetServicesByIDs(ctx datastore.Context, ids ...string) ([]Service, error)
+
 	// GetServicesByDeployment returns services with the given deployment id
 	GetServicesByDeployment(ctx datastore.Context, deploymentID string) ([]Service, error)
 
@@ -83,6 +89,9 @@ type Store interface {
 
 	// GetChildServiceDetails returns the details for the child service of the given parent
 	GetServiceDetailsByParentID(ctx datastore.Context, parentID string) ([]ServiceDetails, error)
+
+	// GetServiceSummaries returns the summaries of all services
+	GetServiceSummaries(ctx datastore.Context) ([]ServiceSummary, error)
 }
 
 // NewStore creates a Service store
@@ -207,6 +216,47 @@ func (s *storeImpl) GetServicesByPool(ctx datastore.Context, poolID string) ([]S
 	return s.convert(results)
 }
 
+// GetServicesWithPublicPorts returns the services in the given pool that have an enabled public port
+func (s *storeImpl) GetServicesWithPublicPorts(ctx datastore.Context, poolID string) ([]Service, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("storeImpl.GetServicesWithPublicPorts"))
+	id := strings.TrimSpace(poolID)
+	if id == "" {
+		return nil, errors.New("empty poolID not allowed")
+	}
+	q := datastore.NewQuery(ctx)
+	search := search.Search("controlplane").Type(kind).Size("50000").Filter(
+		"and",
+		search.Filter().Terms("PoolID", id),
+		search.Filter().Terms("Endpoints.PortList.Enabled", true),
+	)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return s.convert(results)
+}
+
+// GetServicesByIDs returns the services with the given ids; ids that are not found are skipped
+func (s *storeImpl) GetServicesByIDs(ctx datastore.Context, ids ...string) ([]Service, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("storeImpl.GetServicesByIDs"))
+	if len(ids) == 0 {
+		return []Service{}, nil
+	}
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	q := datastore.NewQuery(ctx)
+	search := search.Search("controlplane").Type(kind).Size("50000").Filter(
+		search.Filter().Terms("ID", values...),
+	)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return s.convert(results)
+}
+
 // GetServicesByDeployment returns services with the given deployment id
 func (s *storeImpl) GetServicesByDeployment(ctx datastore.Context, deploymentID string) ([]Service, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("storeImpl.GetServicesByDeployment"))
@@ -20,6 +20,7 @@ import (
 	"github.com/control-center/serviced/datastore/elastic"
 	. "gopkg.in/check.v1"
 
+	"sort"
 	"time"
 
 	"github.com/control-center/serviced/domain/servicedefinition"
@@ -181,6 +182,67 @@ func (s *S) Test_GetServices(t *C) {
 
 }
 
+func (s *S) Test_GetServicesWithPublicPorts(t *C) {
+	public := &Service{ID: "public_id", PoolID: "testPool", Name: "public", Launch: "auto",
+		Endpoints: []ServiceEndpoint{{Name: "web", PortList: []servicedefinition.Port{{PortAddr: ":1234", Enabled: true}}}}}
+	t.Assert(s.store.Put(s.ctx, public), IsNil)
+	disabled := &Service{ID: "disabled_id", PoolID: "testPool", Name: "disabled", Launch: "auto",
+		Endpoints: []ServiceEndpoint{{Name: "web", PortList: []servicedefinition.Port{{PortAddr: ":1235", Enabled: false}}}}}
+	t.Assert(s.store.Put(s.ctx, disabled), IsNil)
+	other := &Service{ID: "other_id", PoolID: "otherPool", Name: "other", Launch: "auto",
+		Endpoints: []ServiceEndpoint{{Name: "web", PortList: []servicedefinition.Port{{PortAddr: ":1236", Enabled: true}}}}}
+	t.Assert(s.store.Put(s.ctx, other), IsNil)
+
+	svcs, err := s.store.GetServicesWithPublicPorts(s.ctx, "testPool")
+	t.Assert(err, IsNil)
+	t.Assert(svcs, HasLen, 1)
+	t.Assert(svcs[0].ID, Equals, "public_id")
+}
+
+func (s *S) Test_GetServicesByIDs(t *C) {
+	for _, id := range []string{"svc_a", "svc_b", "svc_c"} {
+		svc := &Service{ID: id, PoolID: "testPool", Name: id, Launch: "auto"}
+		t.Assert(s.store.Put(s.ctx, svc), IsNil)
+	}
+
+	svcs, err := s.store.GetServicesByIDs(s.ctx, "svc_a", "svc_c", "svc_missing")
+	t.Assert(err, IsNil)
+	t.Assert(svcs, HasLen, 2)
+	ids := []string{svcs[0].ID, svcs[1].ID}
+	sort.Strings(ids)
+	t.Assert(ids, DeepEquals, []string{"svc_a", "svc_c"})
+
+	svcs, err = s.store.GetServicesByIDs(s.ctx)
+	t.Assert(err, IsNil)
+	t.Assert(svcs, HasLen, 0)
+}
+
+func (s *S) Test_GetServiceSummaries(t *C) {
+	tenant := &Service{ID: "tenant_id", PoolID: "testPool", Name: "tenant", Launch: "auto", DeploymentID: "deployment"}
+	err := s.store.Put(s.ctx, tenant)
+	t.Assert(err, IsNil)
+
+	child := &Service{ID: "child_id", PoolID: "testPool", Name: "child", Launch: "auto", DeploymentID: "deployment", ParentServiceID: tenant.ID}
+	err = s.store.Put(s.ctx, child)
+	t.Assert(err, IsNil)
+
+	summaries, err := s.store.GetServiceSummaries(s.ctx)
+	t.Assert(err, IsNil)
+	t.Assert(summaries, HasLen, 2)
+	for _, summary := range summaries {
+		if summary.ID == child.ID {
+			t.Assert(summary, Equals, ServiceSummary{
+				ID:              child.ID,
+				Name:            child.Name,
+				DeploymentID:    child.DeploymentID,
+				PoolID:          child.PoolID,
+				ParentServiceID: tenant.ID,
+				Path:            "tenant/child",
+			})
+		}
+	}
+}
+
 func (s *S) Test_GetUpdatedServices(t *C) {
 	svcs, err := s.store.GetUpdatedServices(s.ctx, time.Duration(1)*time.Hour)
 	t.Assert(err, IsNil)
@@ -0,0 +1,72 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"sort"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+// bySnapshotOrder sorts the members of a consistency group by group, then by
+// their order in the group, then by id.
+type bySnapshotOrder []Service
+
+func (s bySnapshotOrder) Len() int      { return len(s) }
+func (s bySnapshotOrder) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s bySnapshotOrder) Less(i, j int) bool {
+	a, b := s[i].Snapshot, s[j].Snapshot
+	if a.Group != b.Group {
+		return a.Group < b.Group
+	}
+	if a.Order != b.Order {
+		return a.Order < b.Order
+	}
+	return s[i].ID < s[j].ID
+}
+
+// SnapshotSteps returns the steps in which a snapshot pauses the services of
+// a tenant.  The services outside of any consistency group are paused in the
+// first step; each group then follows by name, with one step for each order
+// in the group, so that services with a lower order are quiesced before the
+// services that depend on them.  The groups list their members in that
+// order.
+func SnapshotSteps(svcs []Service) ([][]Service, []servicedefinition.SnapshotGroup) {
+	var steps [][]Service
+	var groups []servicedefinition.SnapshotGroup
+	var ungrouped, grouped []Service
+	for _, svc := range svcs {
+		if svc.Snapshot.Group == "" {
+			ungrouped = append(ungrouped, svc)
+		} else {
+			grouped = append(grouped, svc)
+		}
+	}
+	if len(ungrouped) > 0 {
+		steps = append(steps, ungrouped)
+	}
+	sort.Sort(bySnapshotOrder(grouped))
+	for i, svc := range grouped {
+		if i == 0 || svc.Snapshot.Group != grouped[i-1].Snapshot.Group {
+			groups = append(groups, servicedefinition.SnapshotGroup{Name: svc.Snapshot.Group})
+			steps = append(steps, nil)
+		} else if svc.Snapshot.Order != grouped[i-1].Snapshot.Order {
+			steps = append(steps, nil)
+		}
+		group := &groups[len(groups)-1]
+		group.ServiceIDs = append(group.ServiceIDs, svc.ID)
+		steps[len(steps)-1] = append(steps[len(steps)-1], svc)
+	}
+	return steps, groups
+}
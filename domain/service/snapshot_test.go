@@ -0,0 +1,58 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "gopkg.in/check.v1"
+)
+
+func (s *ServiceDomainUnitTestSuite) TestSnapshotSteps(c *C) {
+	grouped := func(id, group string, order int) service.Service {
+		return service.Service{ID: id, Snapshot: servicedefinition.SnapshotCommands{Group: group, Order: order}}
+	}
+	svcs := []service.Service{
+		grouped("db", "app", 1),
+		{ID: "web"},
+		grouped("app", "app", 0),
+		grouped("worker", "app", 0),
+		grouped("cache", "aaa", 0),
+		{ID: "proxy"},
+	}
+
+	steps, groups := service.SnapshotSteps(svcs)
+	ids := make([][]string, len(steps))
+	for i, step := range steps {
+		for _, svc := range step {
+			ids[i] = append(ids[i], svc.ID)
+		}
+	}
+	c.Assert(ids, DeepEquals, [][]string{
+		{"web", "proxy"},
+		{"cache"},
+		{"app", "worker"},
+		{"db"},
+	})
+	c.Assert(groups, DeepEquals, []servicedefinition.SnapshotGroup{
+		{Name: "aaa", ServiceIDs: []string{"cache"}},
+		{Name: "app", ServiceIDs: []string{"app", "worker", "db"}},
+	})
+
+	steps, groups = service.SnapshotSteps([]service.Service{{ID: "web"}})
+	c.Assert(steps, HasLen, 1)
+	c.Assert(groups, HasLen, 0)
+}
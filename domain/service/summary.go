@@ -0,0 +1,190 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// ServiceSummary is a lightweight projection of a service, used to look up
+// services without loading their full definitions.
+type ServiceSummary struct {
+	ID              string
+	Name            string
+	DeploymentID    string
+	PoolID          string
+	ParentServiceID string
+	Path            string // names from the tenant down to the service, joined by "/"
+}
+
+// Summary returns the summary of the service.  The path is not set.
+func (s *Service) Summary() ServiceSummary {
+	return ServiceSummary{
+		ID:              s.ID,
+		Name:            s.Name,
+		DeploymentID:    s.DeploymentID,
+		PoolID:          s.PoolID,
+		ParentServiceID: s.ParentServiceID,
+	}
+}
+
+// DeploymentPath returns the path of the service prefixed by its deployment
+// id.
+func (s ServiceSummary) DeploymentPath() string {
+	return path.Join(s.DeploymentID, s.Path)
+}
+
+// ServiceQuery selects service summaries.  Empty filters match every
+// service; name and path filters are case insensitive.
+type ServiceQuery struct {
+	Name         string // name of the service
+	PathSuffix   string // suffix of DEPLOYMENTID/TENANT/.../NAME
+	PoolID       string
+	DeploymentID string
+	Offset       int // number of matches to skip
+	Limit        int // maximum number of matches to return, 0 for no limit
+}
+
+// Matches returns true if the summary satisfies every filter of the query.
+func (q ServiceQuery) Matches(s ServiceSummary) bool {
+	if q.Name != "" && !strings.EqualFold(q.Name, s.Name) {
+		return false
+	}
+	if q.PathSuffix != "" && !strings.HasSuffix(strings.ToLower(s.DeploymentPath()), strings.ToLower(q.PathSuffix)) {
+		return false
+	}
+	if q.PoolID != "" && q.PoolID != s.PoolID {
+		return false
+	}
+	if q.DeploymentID != "" && q.DeploymentID != s.DeploymentID {
+		return false
+	}
+	return true
+}
+
+// ServiceSummaryPage is a page of the summaries matching a query
+type ServiceSummaryPage struct {
+	Services []ServiceSummary
+	Total    int // number of summaries that matched the query
+}
+
+// SetSummaryPaths sets the path of each summary from the names of its
+// ancestors within the list.
+func SetSummaryPaths(summaries []ServiceSummary) {
+	byID := make(map[string]*ServiceSummary)
+	for i := range summaries {
+		byID[summaries[i].ID] = &summaries[i]
+	}
+
+	paths := make(map[string]string)
+	var getPath func(s *ServiceSummary) string
+	getPath = func(s *ServiceSummary) string {
+		if p, ok := paths[s.ID]; ok {
+			return p
+		}
+		p := s.Name
+		if parent, ok := byID[s.ParentServiceID]; ok && parent.ID != s.ID {
+			// mark the service as visited to guard against cycles
+			paths[s.ID] = p
+			p = path.Join(getPath(parent), s.Name)
+		}
+		paths[s.ID] = p
+		return p
+	}
+
+	for i := range summaries {
+		summaries[i].Path = getPath(&summaries[i])
+	}
+}
+
+// QueryServiceSummaries returns the page of summaries matching the query,
+// ordered by deployment path and service id.
+func QueryServiceSummaries(summaries []ServiceSummary, query ServiceQuery) ServiceSummaryPage {
+	matches := []ServiceSummary{}
+	for _, s := range summaries {
+		if query.Matches(s) {
+			matches = append(matches, s)
+		}
+	}
+	sort.Sort(byDeploymentPath(matches))
+
+	page := ServiceSummaryPage{Services: []ServiceSummary{}, Total: len(matches)}
+	if query.Offset < 0 || query.Offset >= len(matches) {
+		return page
+	}
+	matches = matches[query.Offset:]
+	if query.Limit > 0 && query.Limit < len(matches) {
+		matches = matches[:query.Limit]
+	}
+	page.Services = matches
+	return page
+}
+
+// ServiceMatch is a service that a service path resolved to
+type ServiceMatch struct {
+	ServiceSummary
+	Fuzzy bool // the path matched only part of the name of the service
+}
+
+// ResolveServicePath returns the services that the path names, which is a
+// service id, DEPLOYMENTID/TENANT/.../NAME, POOL/TENANT/.../NAME, or the end
+// of either.  Services whose whole names match are preferred over those
+// that only match the end of their name.  Matching is case insensitive.
+func ResolveServicePath(summaries []ServiceSummary, servicePath string) []ServiceMatch {
+	for _, s := range summaries {
+		if s.ID == servicePath {
+			return []ServiceMatch{{ServiceSummary: s}}
+		}
+	}
+
+	servicePath = strings.ToLower(strings.Trim(servicePath, "/"))
+	if servicePath == "" {
+		return []ServiceMatch{}
+	}
+	var exact, fuzzy []ServiceSummary
+	for _, s := range summaries {
+		deploymentPath := strings.ToLower(s.DeploymentPath())
+		poolPath := strings.ToLower(path.Join(s.PoolID, s.Path))
+		if deploymentPath == servicePath || poolPath == servicePath ||
+			strings.HasSuffix(deploymentPath, "/"+servicePath) {
+			exact = append(exact, s)
+		} else if strings.HasSuffix(deploymentPath, servicePath) {
+			fuzzy = append(fuzzy, s)
+		}
+	}
+
+	summaries, isFuzzy := exact, false
+	if len(exact) == 0 {
+		summaries, isFuzzy = fuzzy, true
+	}
+	sort.Sort(byDeploymentPath(summaries))
+	matches := make([]ServiceMatch, len(summaries))
+	for i, s := range summaries {
+		matches[i] = ServiceMatch{ServiceSummary: s, Fuzzy: isFuzzy}
+	}
+	return matches
+}
+
+type byDeploymentPath []ServiceSummary
+
+func (s byDeploymentPath) Len() int      { return len(s) }
+func (s byDeploymentPath) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byDeploymentPath) Less(i, j int) bool {
+	if pi, pj := s[i].DeploymentPath(), s[j].DeploymentPath(); pi != pj {
+		return pi < pj
+	}
+	return s[i].ID < s[j].ID
+}
@@ -0,0 +1,107 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+func (s *ServiceDomainUnitTestSuite) TestQueryServiceSummaries(c *C) {
+	summaries := []service.ServiceSummary{
+		{ID: "zope", Name: "Zope", DeploymentID: "dep1", PoolID: "default", ParentServiceID: "tenant1"},
+		{ID: "tenant1", Name: "Zenoss", DeploymentID: "dep1", PoolID: "default"},
+		{ID: "tenant2", Name: "Zenoss", DeploymentID: "dep2", PoolID: "other"},
+		{ID: "zope2", Name: "zope", DeploymentID: "dep2", PoolID: "other", ParentServiceID: "tenant2"},
+		{ID: "zproxy", Name: "zproxy", DeploymentID: "dep2", PoolID: "other", ParentServiceID: "zope2"},
+	}
+	service.SetSummaryPaths(summaries)
+	c.Assert(summaries[0].Path, Equals, "Zenoss/Zope")
+	c.Assert(summaries[4].Path, Equals, "Zenoss/zope/zproxy")
+	c.Assert(summaries[4].DeploymentPath(), Equals, "dep2/Zenoss/zope/zproxy")
+
+	ids := func(page service.ServiceSummaryPage) []string {
+		result := []string{}
+		for _, s := range page.Services {
+			result = append(result, s.ID)
+		}
+		return result
+	}
+
+	page := service.QueryServiceSummaries(summaries, service.ServiceQuery{})
+	c.Assert(page.Total, Equals, 5)
+	c.Assert(ids(page), DeepEquals, []string{"tenant1", "zope", "tenant2", "zope2", "zproxy"})
+
+	page = service.QueryServiceSummaries(summaries, service.ServiceQuery{Name: "ZOPE"})
+	c.Assert(ids(page), DeepEquals, []string{"zope", "zope2"})
+
+	page = service.QueryServiceSummaries(summaries, service.ServiceQuery{PathSuffix: "dep2/zenoss/zope"})
+	c.Assert(ids(page), DeepEquals, []string{"zope2"})
+
+	page = service.QueryServiceSummaries(summaries, service.ServiceQuery{PathSuffix: "ope", PoolID: "default"})
+	c.Assert(ids(page), DeepEquals, []string{"zope"})
+
+	page = service.QueryServiceSummaries(summaries, service.ServiceQuery{DeploymentID: "dep2", Offset: 1, Limit: 1})
+	c.Assert(page.Total, Equals, 3)
+	c.Assert(ids(page), DeepEquals, []string{"zope2"})
+
+	page = service.QueryServiceSummaries(summaries, service.ServiceQuery{Offset: 5})
+	c.Assert(page.Total, Equals, 5)
+	c.Assert(page.Services, HasLen, 0)
+}
+
+func (s *ServiceDomainUnitTestSuite) TestResolveServicePath(c *C) {
+	summaries := []service.ServiceSummary{
+		{ID: "tenant1", Name: "Zenoss", DeploymentID: "dep1", PoolID: "default"},
+		{ID: "zope1", Name: "Zope", DeploymentID: "dep1", PoolID: "default", ParentServiceID: "tenant1"},
+		{ID: "tenant2", Name: "Zenoss", DeploymentID: "dep2", PoolID: "other"},
+		{ID: "zope2", Name: "zope", DeploymentID: "dep2", PoolID: "other", ParentServiceID: "tenant2"},
+		{ID: "mzope", Name: "mzope", DeploymentID: "dep2", PoolID: "other", ParentServiceID: "tenant2"},
+	}
+	service.SetSummaryPaths(summaries)
+
+	resolve := func(servicePath string) ([]string, bool) {
+		ids := []string{}
+		fuzzy := false
+		for _, match := range service.ResolveServicePath(summaries, servicePath) {
+			ids = append(ids, match.ID)
+			fuzzy = match.Fuzzy
+		}
+		return ids, fuzzy
+	}
+
+	ids, fuzzy := resolve("zope2")
+	c.Assert(ids, DeepEquals, []string{"zope2"})
+	c.Assert(fuzzy, Equals, false)
+
+	// whole names are preferred over the ends of names
+	ids, fuzzy = resolve("ZOPE")
+	c.Assert(ids, DeepEquals, []string{"zope1", "zope2"})
+	c.Assert(fuzzy, Equals, false)
+
+	ids, _ = resolve("dep1/zenoss/zope")
+	c.Assert(ids, DeepEquals, []string{"zope1"})
+
+	ids, _ = resolve("other/Zenoss/zope/")
+	c.Assert(ids, DeepEquals, []string{"zope2"})
+
+	ids, fuzzy = resolve("ope")
+	c.Assert(ids, DeepEquals, []string{"zope1", "mzope", "zope2"})
+	c.Assert(fuzzy, Equals, true)
+
+	ids, _ = resolve("missing")
+	c.Assert(ids, HasLen, 0)
+}
@@ -0,0 +1,75 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TagSelector selects services by their tags.  Each term of the selector must
+// match one of the tags of a service.  A term is either a whole tag, such as
+// "env=staging", or a key, such as "env", that matches any tag with that key.
+type TagSelector []string
+
+// ParseTagSelector parses a comma-delimited list of tags, for example
+// "env=staging,team=db".
+func ParseTagSelector(selector string) (TagSelector, error) {
+	var terms TagSelector
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if err := ValidTag(term); err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %s", selector, err)
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// Matches returns true if every term of the selector matches one of the tags.
+func (s TagSelector) Matches(tags []string) bool {
+	for _, term := range s {
+		found := false
+		for _, tag := range tags {
+			if tag == term || (!strings.Contains(term, "=") && strings.HasPrefix(tag, term+"=")) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the selector in the form accepted by ParseTagSelector.
+func (s TagSelector) String() string {
+	return strings.Join(s, ",")
+}
+
+// ValidTag returns an error if the tag cannot be used in a selector.
+func ValidTag(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("tag is empty")
+	} else if strings.Contains(tag, ",") {
+		return fmt.Errorf("tag %q contains a comma", tag)
+	} else if strings.IndexFunc(tag, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("tag %q contains whitespace", tag)
+	} else if strings.HasPrefix(tag, "=") {
+		return fmt.Errorf("tag %q has an empty key", tag)
+	}
+	return nil
+}
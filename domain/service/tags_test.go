@@ -0,0 +1,53 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+func (s *ServiceDomainUnitTestSuite) TestParseTagSelector(c *C) {
+	selector, err := service.ParseTagSelector("env=staging, team=db")
+	c.Assert(err, IsNil)
+	c.Assert(selector, DeepEquals, service.TagSelector{"env=staging", "team=db"})
+	c.Assert(selector.String(), Equals, "env=staging,team=db")
+
+	for _, bad := range []string{"", "env=staging,", "=staging", "env=sta ging"} {
+		_, err = service.ParseTagSelector(bad)
+		c.Assert(err, NotNil, Commentf("selector %q", bad))
+	}
+}
+
+func (s *ServiceDomainUnitTestSuite) TestTagSelectorMatches(c *C) {
+	tags := []string{"daemon", "env=staging", "team=db"}
+
+	selector, _ := service.ParseTagSelector("env=staging,team=db")
+	c.Assert(selector.Matches(tags), Equals, true)
+
+	selector, _ = service.ParseTagSelector("env=prod,team=db")
+	c.Assert(selector.Matches(tags), Equals, false)
+
+	// a key matches any value
+	selector, _ = service.ParseTagSelector("env")
+	c.Assert(selector.Matches(tags), Equals, true)
+	selector, _ = service.ParseTagSelector("daemon")
+	c.Assert(selector.Matches(tags), Equals, true)
+	selector, _ = service.ParseTagSelector("env=")
+	c.Assert(selector.Matches(tags), Equals, false)
+
+	c.Assert(selector.Matches(nil), Equals, false)
+}
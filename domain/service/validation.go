@@ -17,6 +17,7 @@ import (
 	"fmt"
 
 	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/validation"
 )
 
@@ -65,8 +66,30 @@ func (s *Service) ValidEntity() error {
 	// validate the monitoring profile
 	vErr.Add(s.MonitoringProfile.ValidEntity())
 
+	for _, pattern := range s.BackupExcludes {
+		vErr.Add(servicedefinition.ValidBackupExclude(pattern))
+	}
+	vErr.Add(s.ValidContainerOptions())
+	vErr.Add(servicedefinition.ValidNetworkMode(s.NetworkMode))
+	vErr.Add(servicedefinition.ValidTmpfsMounts(s.Tmpfs))
+	vErr.Add(servicedefinition.ValidUsernsMode(s.UsernsMode))
+	vErr.Add(s.Placement.ValidEntity())
+	if s.ReadOnlyRootfs {
+		vErr.Add(servicedefinition.ValidReadOnlyRootfs(s.Volumes, s.Tmpfs, s.ConfigFiles, s.LogConfigs))
+	}
+	for _, extraHost := range s.ExtraHosts {
+		vErr.Add(servicedefinition.ValidExtraHost(extraHost))
+	}
+	vErr.Add(s.Snapshot.ValidEntity())
+
 	if vErr.HasError() {
 		return vErr
 	}
 	return nil
 }
+
+// ValidContainerOptions verifies the ulimits, sysctls, capabilities and
+// seccomp profile of the service's containers
+func (s *Service) ValidContainerOptions() error {
+	return servicedefinition.ValidContainerOptions(s.Ulimits, s.Sysctls, s.CapAdd, s.CapDrop, s.SeccompProfile)
+}
@@ -25,9 +25,18 @@ type SvcConfigFile struct {
 	ServiceTenantID string
 	ServicePath     string
 	ConfFile        servicedefinition.ConfigFile
+	Conflict        *Conflict // set when a redeploy could not reconcile user changes
 	datastore.VersionedEntity
 }
 
+// Conflict describes a config file that was modified by the user and also
+// changed by a new version of the template.  The user's version is kept
+// until the conflict is resolved.
+type Conflict struct {
+	Base   string                       // content of the file in the previous template
+	Theirs servicedefinition.ConfigFile // file in the new template
+}
+
 //New creates a SvcConfigFile
 func New(tenantID string, svcPath string, conf servicedefinition.ConfigFile) (*SvcConfigFile, error) {
 	uuid, err := utils.NewUUID()
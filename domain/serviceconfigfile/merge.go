@@ -0,0 +1,190 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceconfigfile
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+const (
+	// ResolveMine keeps the user's version of a conflicting config file
+	ResolveMine = "mine"
+	// ResolveTheirs replaces a conflicting config file with the new template
+	ResolveTheirs = "theirs"
+	// ResolveMerged replaces a conflicting config file with the three-way
+	// merge of both versions, if the merge is clean
+	ResolveMerged = "merged"
+)
+
+// Markers that delimit the sections of a conflicting merge
+const (
+	MarkerMine   = "<<<<<<< current"
+	MarkerBase   = "||||||| previous template"
+	MarkerSep    = "======="
+	MarkerTheirs = ">>>>>>> new template"
+)
+
+// Upgrade decides which version of a config file to keep when its template
+// changes from base to theirs.  base and mine are nil if the file was not in
+// the previous template or was never stored for the service.  If both the
+// user and the template changed the file, the user's version is kept and a
+// conflict is returned.
+func Upgrade(base, mine *servicedefinition.ConfigFile, theirs servicedefinition.ConfigFile) (servicedefinition.ConfigFile, *Conflict) {
+	if mine == nil || reflect.DeepEqual(*mine, theirs) {
+		return theirs, nil
+	}
+	if base != nil {
+		if reflect.DeepEqual(*mine, *base) {
+			// the user did not modify the file
+			return theirs, nil
+		} else if reflect.DeepEqual(*base, theirs) {
+			// the template did not modify the file
+			return *mine, nil
+		}
+	}
+	conflict := &Conflict{Theirs: theirs}
+	if base != nil {
+		conflict.Base = base.Content
+	}
+	return *mine, conflict
+}
+
+// Merge performs a line-based three-way merge of the changes from base to
+// mine and from base to theirs.  Returns false if the changes overlap, in
+// which case the overlapping sections are delimited by conflict markers.
+func Merge(base, mine, theirs string) (string, bool) {
+	b, m, t := splitLines(base), splitLines(mine), splitLines(theirs)
+	bm, bt := matchLines(b, m), matchLines(b, t)
+
+	var buf bytes.Buffer
+	clean := true
+	pb, pm, pt := 0, 0, 0
+	for {
+		// find the next base line that is unchanged in both versions
+		i := pb
+		for ; i < len(b); i++ {
+			if bm[i] >= 0 && bt[i] >= 0 {
+				break
+			}
+		}
+		em, et := len(m), len(t)
+		if i < len(b) {
+			em, et = bm[i], bt[i]
+		}
+		if !mergeChunk(&buf, b[pb:i], m[pm:em], t[pt:et]) {
+			clean = false
+		}
+		if i == len(b) {
+			break
+		}
+		buf.WriteString(b[i])
+		pb, pm, pt = i+1, em+1, et+1
+	}
+	return buf.String(), clean
+}
+
+// mergeChunk writes the merge of a section that changed in either version
+func mergeChunk(buf *bytes.Buffer, base, mine, theirs []string) bool {
+	switch {
+	case equalLines(mine, base):
+		writeLines(buf, theirs)
+	case equalLines(theirs, base), equalLines(mine, theirs):
+		writeLines(buf, mine)
+	default:
+		buf.WriteString(MarkerMine + "\n")
+		writeLines(buf, terminate(mine))
+		buf.WriteString(MarkerBase + "\n")
+		writeLines(buf, terminate(base))
+		buf.WriteString(MarkerSep + "\n")
+		writeLines(buf, terminate(theirs))
+		buf.WriteString(MarkerTheirs + "\n")
+		return false
+	}
+	return true
+}
+
+// matchLines returns, for each line of a, the index of the matching line of
+// b in their longest common subsequence, or -1 if the line was changed.
+func matchLines(a, b []string) []int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	match := make([]int, len(a))
+	i, j := 0, 0
+	for i < len(a) {
+		if j < len(b) && a[i] == b[j] {
+			match[i] = j
+			i++
+			j++
+		} else if j < len(b) && lcs[i][j+1] > lcs[i+1][j] {
+			j++
+		} else {
+			match[i] = -1
+			i++
+		}
+	}
+	return match
+}
+
+// splitLines splits a string after each newline
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeLines(buf *bytes.Buffer, lines []string) {
+	for _, line := range lines {
+		buf.WriteString(line)
+	}
+}
+
+// terminate makes sure the last line ends with a newline, so that conflict
+// markers start on their own line
+func terminate(lines []string) []string {
+	if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+		lines = append(lines[:n-1:n-1], lines[n-1]+"\n")
+	}
+	return lines
+}
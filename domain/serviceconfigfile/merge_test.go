@@ -0,0 +1,87 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package serviceconfigfile
+
+import (
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "gopkg.in/check.v1"
+)
+
+type MergeSuite struct{}
+
+var _ = Suite(&MergeSuite{})
+
+func (s *MergeSuite) TestUpgrade(c *C) {
+	base := servicedefinition.ConfigFile{Filename: "/etc/app.conf", Content: "port=8080\n"}
+	theirs := servicedefinition.ConfigFile{Filename: "/etc/app.conf", Content: "port=8080\nworkers=4\n"}
+	mine := servicedefinition.ConfigFile{Filename: "/etc/app.conf", Content: "port=9090\n"}
+
+	// the file was never stored
+	file, conflict := Upgrade(&base, nil, theirs)
+	c.Check(file, DeepEquals, theirs)
+	c.Check(conflict, IsNil)
+
+	// the user did not modify the file
+	file, conflict = Upgrade(&base, &base, theirs)
+	c.Check(file, DeepEquals, theirs)
+	c.Check(conflict, IsNil)
+
+	// the template did not modify the file
+	file, conflict = Upgrade(&base, &mine, base)
+	c.Check(file, DeepEquals, mine)
+	c.Check(conflict, IsNil)
+
+	// both modified the file
+	file, conflict = Upgrade(&base, &mine, theirs)
+	c.Check(file, DeepEquals, mine)
+	c.Check(conflict, DeepEquals, &Conflict{Base: base.Content, Theirs: theirs})
+
+	// the user added a file that the template now provides
+	file, conflict = Upgrade(nil, &mine, theirs)
+	c.Check(file, DeepEquals, mine)
+	c.Check(conflict, DeepEquals, &Conflict{Theirs: theirs})
+}
+
+func (s *MergeSuite) TestMerge_Clean(c *C) {
+	base := "a\nb\nc\nd\n"
+	mine := "a\nB\nc\nd\n"
+	theirs := "a\nb\nc\nd\ne\n"
+	merged, ok := Merge(base, mine, theirs)
+	c.Check(ok, Equals, true)
+	c.Check(merged, Equals, "a\nB\nc\nd\ne\n")
+
+	// identical changes do not conflict
+	merged, ok = Merge(base, mine, mine)
+	c.Check(ok, Equals, true)
+	c.Check(merged, Equals, mine)
+
+	merged, ok = Merge("", "", "a\n")
+	c.Check(ok, Equals, true)
+	c.Check(merged, Equals, "a\n")
+}
+
+func (s *MergeSuite) TestMerge_Conflict(c *C) {
+	base := "a\nb\nc"
+	mine := "a\nB\nc"
+	theirs := "a\nbb\nc"
+	merged, ok := Merge(base, mine, theirs)
+	c.Check(ok, Equals, false)
+	c.Check(merged, Equals, "a\n"+
+		MarkerMine+"\nB\n"+
+		MarkerBase+"\nb\n"+
+		MarkerSep+"\nbb\n"+
+		MarkerTheirs+"\nc")
+}
@@ -20,15 +20,8 @@ import (
 	"github.com/control-center/serviced/datastore/elastic"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	. "gopkg.in/check.v1"
-
-	"testing"
 )
 
-// This plumbs gocheck into testing
-func Test(t *testing.T) {
-	TestingT(t)
-}
-
 var _ = Suite(&S{
 	ElasticTest: elastic.ElasticTest{
 		Index:    "controlplane",
@@ -14,7 +14,6 @@
 package serviceconfigfile
 
 import (
-	"github.com/zenoss/glog"
 	"github.com/control-center/serviced/datastore/elastic"
 )
 
@@ -36,6 +35,6 @@ var (
 
 func init() {
 	if mappingError != nil {
-		glog.Fatalf("error creating svcconfigfile mapping: %v", mappingError)
+		plog.Fatalf("error creating svcconfigfile mapping: %v", mappingError)
 	}
 }
@@ -17,11 +17,11 @@ import (
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 
 	"encoding/json"
 	"errors"
 	"strings"
+	"time"
 )
 
 //ServiceDefinition is the definition of a service hierarchy.
@@ -46,6 +46,7 @@ type ServiceDefinition struct {
 	Services          []ServiceDefinition    // Supporting subservices
 	LogFilters        map[string]string      // map of log filter name to log filter definitions
 	Volumes           []Volume               // list of volumes to bind into containers
+	BackupExcludes    []string               // resource paths or path globs in the tenant volume to leave out of backups
 	LogConfigs        []LogConfig
 	Snapshot          SnapshotCommands              // Snapshot quiesce info for the service: Pause/Resume bash commands
 	RAMCommitment     utils.EngNotation             // expected RAM commitment to use for scheduling
@@ -60,12 +61,118 @@ type ServiceDefinition struct {
 	MemoryLimit       float64
 	CPUShares         int64
 	PIDFile           string // An optional path or command to generate a path for a PID file to which signals are relayed.
+	Priority          string // Priority class: critical, high, normal (the default), or low
+
+	EmergencyShutdownLevel int  // Order in which the service is stopped when storage runs low; lower levels first, 0 last
+	AutoRollback           bool // Roll back commits and image upgrades that fail health checks
+
+	Ulimits        []Ulimit          // Resource limits of the container, i.e. nofile and nproc
+	Sysctls        map[string]string // Namespaced kernel parameters to set in the container
+	CapAdd         []string          // Linux capabilities to add to the container
+	CapDrop        []string          // Linux capabilities to drop from the container
+	SeccompProfile string            // "unconfined" or the path of a seccomp profile on the host; docker's default if empty
+
+	DNS        []string // DNS servers of the container, ahead of the host's
+	DNSSearch  []string // DNS search domains of the container
+	ExtraHosts []string // Additional /etc/hosts entries of the form hostname:address
+
+	NetworkMode string // Network of the container: bridge (the default), host, or macvlan
+
+	ShmSize utils.EngNotation // Size of /dev/shm in the container; docker's default of 64M if empty
+	Tmpfs   []TmpfsMount      // In-memory filesystems to mount in the container
+
+	ReadOnlyRootfs bool   // Mount the root filesystem of the container read-only; files must be written to volumes or tmpfs mounts
+	UsernsMode     string // "host" to opt out of the docker daemon's user namespace remapping
+
+	Placement Placement // Hosts to which the instances of the service are pinned
+}
+
+// Placement pins the instances of a service to hosts, named by their id or
+// their name.  An instance that is pinned by its instance id may only run on
+// that host, and any other instance may only run on one of Hosts, if any.
+type Placement struct {
+	Hosts     []string            // Hosts on which the instances of the service may run
+	Instances []InstancePlacement // Hosts of specific instances of the service
+}
+
+// InstancePlacement pins an instance of a service to a host
+type InstancePlacement struct {
+	InstanceID int
+	Host       string
+}
+
+// HostsFor returns the hosts to which the instance is pinned, or nil if the
+// instance may run on any host in the pool.
+func (p Placement) HostsFor(instanceID int) []string {
+	for _, instance := range p.Instances {
+		if instance.InstanceID == instanceID {
+			return []string{instance.Host}
+		}
+	}
+	return p.Hosts
+}
+
+// TmpfsMount is an in-memory filesystem mounted in the containers of a service
+type TmpfsMount struct {
+	ContainerPath string            // Where the filesystem is mounted in the container
+	Size          utils.EngNotation // Maximum size of the filesystem; half of the host's memory if empty
+	Options       string            // Additional comma separated mount options, eg "noexec,mode=1777"
+}
+
+// Network modes of the containers of a service.  Containers are attached to
+// the docker bridge by default.  Host containers share the network of the
+// host, so their ports must not conflict with any other on the host.  Macvlan
+// containers are also attached to the network of the pool's hosts through the
+// macvlan network configured on the pool.
+const (
+	NetworkBridge  = "bridge"
+	NetworkHost    = "host"
+	NetworkMacvlan = "macvlan"
+)
+
+// Ulimit is a resource limit set on the containers of a service
+type Ulimit struct {
+	Name string // nofile or nproc
+	Soft int64
+	Hard int64
 }
 
 // SnapshotCommands commands to be called during and after a snapshot
 type SnapshotCommands struct {
-	Pause  string // bash command to pause the volume  (quiesce)
-	Resume string // bash command to resume the volume (unquiesce)
+	Pause     string // bash command to pause the volume  (quiesce)
+	Resume    string // bash command to resume the volume (unquiesce)
+	Timeout   int    // seconds to wait for each command; 0 waits until it exits
+	OnFailure string // what to do when a command fails: SnapshotAbort (default) or SnapshotContinue
+	Group     string // consistency group whose services are quiesced together
+	Order     int    // position in the group; lower orders are paused first
+}
+
+// SnapshotGroup is a consistency group of a tenant snapshot: services that
+// are paused together, in order, after the ungrouped services are paused and
+// before the volume is captured.
+type SnapshotGroup struct {
+	Name       string
+	ServiceIDs []string // ids of the member services in the order they were paused
+}
+
+// Failure policies of the snapshot commands.  SnapshotAbort fails the
+// snapshot if the pause command fails or times out; SnapshotContinue logs the
+// failure and takes a crash-consistent snapshot of the instance instead.
+const (
+	SnapshotAbort    = "abort"
+	SnapshotContinue = "continue"
+)
+
+// CommandTimeout returns how long to wait for a snapshot command, or 0 if
+// there is no limit
+func (s SnapshotCommands) CommandTimeout() time.Duration {
+	return time.Duration(s.Timeout) * time.Second
+}
+
+// ContinueOnFailure returns true if a snapshot should proceed when a command
+// fails
+func (s SnapshotCommands) ContinueOnFailure() bool {
+	return s.OnFailure == SnapshotContinue
 }
 
 // EndpointDefinition An endpoint that a Service exposes.
@@ -81,8 +188,23 @@ type EndpointDefinition struct {
 	AddressConfig       AddressResourceConfig
 	VHosts              []string // VHost is used to request named vhost for this endpoint. Should be the name of a
 	// subdomain, i.e "myapplication"  not "myapplication.host.com"
-	VHostList []VHost // VHost is used to request named vhost(s) for this endpoint.
-	PortList  []Port
+	VHostList      []VHost // VHost is used to request named vhost(s) for this endpoint.
+	PortList       []Port
+	CircuitBreaker CircuitBreaker // Fail fast when the backends of an imported endpoint are down
+}
+
+// CircuitBreaker configures the proxy for an imported endpoint to refuse
+// connections once its backends stop accepting them, rather than leaving
+// clients to hang.  The breaker is disabled if Failures is zero.
+type CircuitBreaker struct {
+	Failures     int // consecutive failed connections that trip the breaker
+	ResetTimeout int // seconds to wait before letting a probe connection through
+	Retries      int // additional backends to try before a connection fails
+}
+
+// Enabled returns true if the breaker trips on failures
+func (cb CircuitBreaker) Enabled() bool {
+	return cb.Failures > 0
 }
 
 // VHost is the configuration for an application endpoint that wants an http VHost endpoint provided by Control Center
@@ -209,7 +331,7 @@ func (e *EndpointDefinition) UnmarshalJSON(b []byte) error {
 	} else {
 		return err
 	}
-	glog.V(4).Infof("EndpointDefintion UnmarshalJSON %#v", e)
+	plog.Debugf("EndpointDefintion UnmarshalJSON %#v", e)
 	if len(e.VHostList) > 0 {
 		//VHostList is defined, keep it and unset deprecated field if set
 		e.VHosts = nil
@@ -217,13 +339,11 @@ func (e *EndpointDefinition) UnmarshalJSON(b []byte) error {
 	}
 	if len(e.VHosts) > 0 {
 		// no VHostsList but vhosts is defined. Convert to VHostsList
-		if glog.V(2) {
-			glog.Warningf("EndpointDefinition VHosts field is deprecated, see VHostList: %#v", e.VHosts)
-		}
+		plog.Debugf("EndpointDefinition VHosts field is deprecated, see VHostList: %#v", e.VHosts)
 		for _, vhost := range e.VHosts {
 			e.VHostList = append(e.VHostList, VHost{Name: vhost, Enabled: true})
 		}
-		glog.V(2).Infof("VHostList %#v converted from VHosts %#v", e.VHostList, e.VHosts)
+		plog.Debugf("VHostList %#v converted from VHosts %#v", e.VHostList, e.VHosts)
 		e.VHosts = nil
 	}
 	return nil
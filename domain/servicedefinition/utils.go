@@ -14,8 +14,6 @@
 package servicedefinition
 
 import (
-	"github.com/zenoss/glog"
-
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -55,7 +53,7 @@ func getServiceDefinition(path string) (serviceDef *ServiceDefinition, err error
 	svc := ServiceDefinition{}
 	err = json.Unmarshal(blob, &svc)
 	if err != nil {
-		glog.Errorf("Could not unmarshal service at %s", path)
+		plog.Errorf("Could not unmarshal service at %s", path)
 		return nil, err
 	}
 	//Launch isn't usually in a file but it is required, this sets it to a default value if not set
@@ -116,7 +114,7 @@ func getServiceDefinition(path string) (serviceDef *ServiceDefinition, err error
 			}
 			filters, err := getFiltersFromDirectory(path + "/" + subpath.Name())
 			if err != nil {
-				glog.Errorf("Error fetching filters at "+path, err)
+				plog.Errorf("Error fetching filters at "+path, err)
 			} else {
 				svc.LogFilters = filters
 			}
@@ -130,7 +128,7 @@ func getServiceDefinition(path string) (serviceDef *ServiceDefinition, err error
 			// else just skip this subdirectory
 
 		default:
-			glog.V(4).Infof("Unrecognized file %s at %s", subpath.Name(), path)
+			plog.Debugf("Unrecognized file %s at %s", subpath.Name(), path)
 		}
 	}
 	svc.Services = make([]ServiceDefinition, len(subServices))
@@ -157,18 +155,18 @@ func getFiltersFromDirectory(path string) (filters map[string]string, err error)
 
 		// make sure it is a valid filter
 		if !strings.HasSuffix(filterName, ".conf") {
-			glog.Warning("Skipping %s because it doesn't have a .conf extension", filterName)
+			plog.Warningf("Skipping %s because it doesn't have a .conf extension", filterName)
 			continue
 		}
 		// read the contents and add it to our map
 		contents, err := ioutil.ReadFile(path + "/" + filterName)
 		if err != nil {
-			glog.Errorf("Unable to read the file %s, skipping", path+"/"+filterName)
+			plog.Errorf("Unable to read the file %s, skipping", path+"/"+filterName)
 			continue
 		}
 		filterName = strings.TrimSuffix(filterName, ".conf")
 		filters[filterName] = string(contents)
 	}
-	glog.V(2).Infof("Here are the filters %v from path %s", filters, path)
+	plog.Debugf("Here are the filters %v from path %s", filters, path)
 	return filters, nil
 }
@@ -16,16 +16,17 @@ package servicedefinition
 import (
 	"github.com/control-center/serviced/commons"
 	"github.com/control-center/serviced/validation"
-	"github.com/zenoss/glog"
 
+	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
 
 //ValidEntity validates Host fields
 func (sd *ServiceDefinition) ValidEntity() error {
-	glog.V(4).Info("Validating ServiceDefinition")
+	plog.Debug("Validating ServiceDefinition")
 
 	context := validationContext{make(map[string]EndpointDefinition)}
 	//TODO: do servicedefinition names need to be unique?
@@ -62,6 +63,48 @@ func (sd *ServiceDefinition) validate(context *validationContext) error {
 		}
 		names[trimName] = struct{}{}
 	}
+	for _, pattern := range sd.BackupExcludes {
+		if err := ValidBackupExclude(pattern); err != nil {
+			return fmt.Errorf("service definition %v: %v", sd.Name, err)
+		}
+	}
+
+	if err := ValidContainerOptions(sd.Ulimits, sd.Sysctls, sd.CapAdd, sd.CapDrop, sd.SeccompProfile); err != nil {
+		return fmt.Errorf("service definition %v: %v", sd.Name, err)
+	}
+
+	if err := ValidNetworkMode(sd.NetworkMode); err != nil {
+		return fmt.Errorf("service definition %v: %v", sd.Name, err)
+	}
+
+	if err := ValidTmpfsMounts(sd.Tmpfs); err != nil {
+		return fmt.Errorf("service definition %v: %v", sd.Name, err)
+	}
+
+	if err := ValidUsernsMode(sd.UsernsMode); err != nil {
+		return fmt.Errorf("service definition %v: %v", sd.Name, err)
+	}
+
+	if sd.ReadOnlyRootfs {
+		if err := ValidReadOnlyRootfs(sd.Volumes, sd.Tmpfs, sd.ConfigFiles, sd.LogConfigs); err != nil {
+			return fmt.Errorf("service definition %v: %v", sd.Name, err)
+		}
+	}
+
+	if err := sd.Placement.ValidEntity(); err != nil {
+		return fmt.Errorf("service definition %v: %v", sd.Name, err)
+	}
+
+	for _, extraHost := range sd.ExtraHosts {
+		if err := ValidExtraHost(extraHost); err != nil {
+			return fmt.Errorf("service definition %v: %v", sd.Name, err)
+		}
+	}
+
+	if err := sd.Snapshot.ValidEntity(); err != nil {
+		return fmt.Errorf("service definition %v: %v", sd.Name, err)
+	}
+
 	//TODO: validate LogConfigs
 
 	// validate Monitoring Profile
@@ -82,6 +125,232 @@ func validServiceDefinitions(ds *[]ServiceDefinition, context *validationContext
 	return nil
 }
 
+// ValidBackupExclude verifies that a backup exclusion is a valid glob of
+// paths within the tenant volume.  Exclusions follow the rules of tar
+// --exclude rather than filepath.Match: they match from any directory down
+// and their wildcards match '/'.
+func ValidBackupExclude(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("backup exclude cannot be empty")
+	}
+	if filepath.IsAbs(pattern) || strings.HasPrefix(filepath.Clean(pattern), "..") {
+		return fmt.Errorf("backup exclude %s must be a path within the tenant volume", pattern)
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("backup exclude %s is not a valid glob: %s", pattern, err)
+	}
+	return nil
+}
+
+// ulimitNames are the resource limits a service may set on its containers
+var ulimitNames = map[string]struct{}{
+	"nofile": struct{}{},
+	"nproc":  struct{}{},
+}
+
+// namespacedSysctls are the kernel parameters that are isolated by the
+// container's namespaces, and so may be set without affecting the host
+var namespacedSysctls = map[string]struct{}{
+	"kernel.msgmax":          struct{}{},
+	"kernel.msgmnb":          struct{}{},
+	"kernel.msgmni":          struct{}{},
+	"kernel.sem":             struct{}{},
+	"kernel.shmall":          struct{}{},
+	"kernel.shmmax":          struct{}{},
+	"kernel.shmmni":          struct{}{},
+	"kernel.shm_rmid_forced": struct{}{},
+}
+
+var capabilityRegexp = regexp.MustCompile(`^(CAP_)?[A-Z][A-Z_]*$`)
+
+// ValidContainerOptions verifies the ulimits, sysctls, capabilities and
+// seccomp profile requested for the containers of a service
+func ValidContainerOptions(ulimits []Ulimit, sysctls map[string]string, capAdd, capDrop []string, seccompProfile string) error {
+	names := make(map[string]struct{})
+	for _, ulimit := range ulimits {
+		if err := ulimit.ValidEntity(); err != nil {
+			return err
+		}
+		if _, ok := names[ulimit.Name]; ok {
+			return fmt.Errorf("ulimit %s is set more than once", ulimit.Name)
+		}
+		names[ulimit.Name] = struct{}{}
+	}
+	for name := range sysctls {
+		if err := ValidSysctl(name); err != nil {
+			return err
+		}
+	}
+	for _, caps := range [][]string{capAdd, capDrop} {
+		for _, capability := range caps {
+			if capability != "ALL" && !capabilityRegexp.MatchString(capability) {
+				return fmt.Errorf("capability %q is not valid", capability)
+			}
+		}
+	}
+	if seccompProfile != "" && seccompProfile != "unconfined" && !filepath.IsAbs(seccompProfile) {
+		return fmt.Errorf("seccomp profile %s must be unconfined or an absolute path", seccompProfile)
+	}
+	return nil
+}
+
+// ValidEntity makes sure the ulimit is supported and its soft limit does not
+// exceed its hard limit
+func (u Ulimit) ValidEntity() error {
+	if _, ok := ulimitNames[u.Name]; !ok {
+		return fmt.Errorf("ulimit %q is not supported; must be nofile or nproc", u.Name)
+	}
+	if u.Soft < 0 || u.Hard < 0 {
+		return fmt.Errorf("ulimit %s cannot be negative", u.Name)
+	}
+	if u.Soft > u.Hard {
+		return fmt.Errorf("ulimit %s soft limit %d exceeds its hard limit %d", u.Name, u.Soft, u.Hard)
+	}
+	return nil
+}
+
+// ValidSysctl verifies that the kernel parameter is namespaced, since
+// docker will not set parameters that are shared with the host
+func ValidSysctl(name string) error {
+	if _, ok := namespacedSysctls[name]; ok {
+		return nil
+	}
+	if strings.HasPrefix(name, "fs.mqueue.") || strings.HasPrefix(name, "net.") {
+		return nil
+	}
+	return fmt.Errorf("sysctl %s is not namespaced and cannot be set in a container", name)
+}
+
+// ValidExtraHost verifies that an /etc/hosts entry maps a hostname to an
+// address.  The address may be a template, so it is not parsed.
+func ValidExtraHost(extraHost string) error {
+	parts := strings.SplitN(extraHost, ":", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return fmt.Errorf("extra host %q must be of the form hostname:address", extraHost)
+	}
+	return nil
+}
+
+// ValidNetworkMode verifies the network mode of a service's containers.  An
+// empty mode is the same as the bridge mode.
+func ValidNetworkMode(mode string) error {
+	switch mode {
+	case "", NetworkBridge, NetworkHost, NetworkMacvlan:
+		return nil
+	}
+	return fmt.Errorf("network mode %q is not valid; must be %s, %s, or %s", mode, NetworkBridge, NetworkHost, NetworkMacvlan)
+}
+
+// ValidTmpfsMounts verifies that each tmpfs mount has a unique absolute path
+// in the container and sets its size only through its Size.
+func ValidTmpfsMounts(mounts []TmpfsMount) error {
+	paths := make(map[string]struct{})
+	for _, mount := range mounts {
+		if !filepath.IsAbs(mount.ContainerPath) {
+			return fmt.Errorf("tmpfs container path %q must be absolute", mount.ContainerPath)
+		}
+		path := filepath.Clean(mount.ContainerPath)
+		if _, ok := paths[path]; ok {
+			return fmt.Errorf("tmpfs container path %s is mounted more than once", path)
+		}
+		paths[path] = struct{}{}
+		for _, option := range strings.Split(mount.Options, ",") {
+			if strings.HasPrefix(strings.TrimSpace(option), "size=") {
+				return fmt.Errorf("tmpfs %s must set its size with Size rather than its options", path)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidUsernsMode verifies the user namespace mode of a service's containers.
+// An empty mode uses the remapping of the docker daemon, if any.
+func ValidUsernsMode(mode string) error {
+	if mode != "" && mode != "host" {
+		return fmt.Errorf("user namespace mode %q is not valid; must be empty or host", mode)
+	}
+	return nil
+}
+
+// ValidReadOnlyRootfs verifies that the config files and logs of a service
+// whose root filesystem is read-only are written to a volume, a tmpfs mount,
+// or /tmp.
+func ValidReadOnlyRootfs(volumes []Volume, mounts []TmpfsMount, configFiles map[string]ConfigFile, logConfigs []LogConfig) error {
+	writable := []string{"/tmp"}
+	for _, volume := range volumes {
+		writable = append(writable, volume.ContainerPath)
+	}
+	for _, mount := range mounts {
+		writable = append(writable, mount.ContainerPath)
+	}
+
+	isWritable := func(path string) bool {
+		path = filepath.Clean(path)
+		for _, dir := range writable {
+			dir = filepath.Clean(dir)
+			if path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, configFile := range configFiles {
+		if !isWritable(configFile.Filename) {
+			return fmt.Errorf("config file %s is not on a writable path of the read-only root filesystem", configFile.Filename)
+		}
+	}
+	for _, logConfig := range logConfigs {
+		if !isWritable(logConfig.Path) {
+			return fmt.Errorf("log %s is not on a writable path of the read-only root filesystem", logConfig.Path)
+		}
+	}
+	return nil
+}
+
+// ValidEntity verifies that the placement names its hosts and pins each
+// instance at most once
+func (p Placement) ValidEntity() error {
+	for _, host := range p.Hosts {
+		if strings.TrimSpace(host) == "" {
+			return errors.New("placement host must not be empty")
+		}
+	}
+	instances := make(map[int]struct{})
+	for _, instance := range p.Instances {
+		if instance.InstanceID < 0 {
+			return fmt.Errorf("placement instance id %d must not be negative", instance.InstanceID)
+		}
+		if strings.TrimSpace(instance.Host) == "" {
+			return fmt.Errorf("placement host of instance %d must not be empty", instance.InstanceID)
+		}
+		if _, ok := instances[instance.InstanceID]; ok {
+			return fmt.Errorf("instance %d is placed more than once", instance.InstanceID)
+		}
+		instances[instance.InstanceID] = struct{}{}
+	}
+	return nil
+}
+
+// ValidEntity verifies the timeout and failure policy of the snapshot commands
+func (s SnapshotCommands) ValidEntity() error {
+	if s.Timeout < 0 {
+		return fmt.Errorf("snapshot command timeout %d must not be negative", s.Timeout)
+	}
+	if s.OnFailure != "" {
+		if err := validation.StringIn(s.OnFailure, SnapshotAbort, SnapshotContinue); err != nil {
+			return fmt.Errorf("invalid snapshot failure policy %v", err)
+		}
+	}
+	if s.Order < 0 {
+		return fmt.Errorf("snapshot group order %d must not be negative", s.Order)
+	}
+	if s.Order > 0 && s.Group == "" {
+		return fmt.Errorf("snapshot group order %d requires a group", s.Order)
+	}
+	return nil
+}
+
 //NormalizeLaunch normalizes the launch string. Sets to commons.AUTO if empty otherwise just trims and lower cases. Does
 //not check if value is valid
 func (sd *ServiceDefinition) NormalizeLaunch() {
@@ -124,9 +393,23 @@ func (se EndpointDefinition) ValidEntity() error {
 			return fmt.Errorf("endpoint '%s': %s", se.Name, err)
 		}
 	}
+	if err := se.CircuitBreaker.ValidEntity(); err != nil {
+		return fmt.Errorf("endpoint '%s': %s", se.Name, err)
+	}
 	return se.AddressConfig.ValidEntity()
 }
 
+// ValidEntity used to make sure CircuitBreaker is in a valid state
+func (cb CircuitBreaker) ValidEntity() error {
+	if cb.Failures < 0 || cb.ResetTimeout < 0 || cb.Retries < 0 {
+		return fmt.Errorf("circuit breaker settings cannot be negative")
+	}
+	if cb.Enabled() && cb.ResetTimeout == 0 {
+		return fmt.Errorf("circuit breaker requires a reset timeout")
+	}
+	return nil
+}
+
 func applicationValidation(application string) error {
 	_, err := regexp.Compile(application)
 	if err != nil {
@@ -137,3 +137,206 @@ func TestServiceDefinitionEmptyEndpointName(t *testing.T) {
 		t.Errorf("Unexpected Error %v", err)
 	}
 }
+
+func TestServiceDefinitionBackupExcludes(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].BackupExcludes = []string{"hbase-cache", "zeneventserver/tmp/*"}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, pattern := range []string{"", "/var/cache", "../other", "cache/[", " "} {
+		sd.Services[0].BackupExcludes = []string{pattern}
+		if err := sd.ValidEntity(); err == nil {
+			t.Errorf("Expected error for backup exclude %q", pattern)
+		} else if !strings.Contains(err.Error(), "backup exclude") {
+			t.Errorf("Unexpected error for backup exclude %q: %v", pattern, err)
+		}
+	}
+}
+
+func TestServiceDefinitionCircuitBreaker(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].Endpoints[0].CircuitBreaker = CircuitBreaker{Failures: 3, ResetTimeout: 30, Retries: 1}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, cb := range []CircuitBreaker{{Failures: -1}, {Retries: -1}, {Failures: 3}} {
+		sd.Services[0].Endpoints[0].CircuitBreaker = cb
+		if err := sd.ValidEntity(); err == nil {
+			t.Errorf("Expected error for circuit breaker %+v", cb)
+		} else if !strings.Contains(err.Error(), "circuit breaker") {
+			t.Errorf("Unexpected error for circuit breaker %+v: %v", cb, err)
+		}
+	}
+}
+
+func TestServiceDefinitionContainerOptions(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].Ulimits = []Ulimit{{Name: "nofile", Soft: 1024, Hard: 65536}, {Name: "nproc", Soft: 4096, Hard: 4096}}
+	sd.Services[0].Sysctls = map[string]string{"net.core.somaxconn": "1024", "kernel.shmmax": "68719476736"}
+	sd.Services[0].CapAdd = []string{"SYS_PTRACE", "CAP_NET_ADMIN"}
+	sd.Services[0].CapDrop = []string{"ALL"}
+	sd.Services[0].SeccompProfile = "unconfined"
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, u := range [][]Ulimit{{{Name: "core"}}, {{Name: "nofile", Soft: 2, Hard: 1}}, {{Name: "nproc", Soft: -1}}, {{Name: "nproc"}, {Name: "nproc"}}} {
+		sd.Services[0].Ulimits = u
+		if err := sd.ValidEntity(); err == nil {
+			t.Errorf("Expected error for ulimits %+v", u)
+		} else if !strings.Contains(err.Error(), "ulimit") {
+			t.Errorf("Unexpected error for ulimits %+v: %v", u, err)
+		}
+	}
+	sd.Services[0].Ulimits = nil
+
+	sd.Services[0].Sysctls = map[string]string{"vm.swappiness": "0"}
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "sysctl") {
+		t.Errorf("Expected sysctl error, got %v", err)
+	}
+	sd.Services[0].Sysctls = nil
+
+	sd.Services[0].CapAdd = []string{"sys_admin"}
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "capability") {
+		t.Errorf("Expected capability error, got %v", err)
+	}
+	sd.Services[0].CapAdd = nil
+
+	sd.Services[0].SeccompProfile = "profiles/strict.json"
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "seccomp") {
+		t.Errorf("Expected seccomp error, got %v", err)
+	}
+}
+
+func TestServiceDefinitionExtraHosts(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].ExtraHosts = []string{"ldap.corp.example.com:10.20.30.40", "db:{{assignedIP . \"db\"}}"}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, extraHost := range []string{"ldap.corp.example.com", ":10.20.30.40", "ldap:", ""} {
+		sd.Services[0].ExtraHosts = []string{extraHost}
+		if err := sd.ValidEntity(); err == nil {
+			t.Errorf("Expected error for extra host %q", extraHost)
+		} else if !strings.Contains(err.Error(), "extra host") {
+			t.Errorf("Unexpected error for extra host %q: %v", extraHost, err)
+		}
+	}
+}
+
+func TestServiceDefinitionNetworkMode(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	for _, mode := range []string{"", NetworkBridge, NetworkHost, NetworkMacvlan} {
+		sd.Services[0].NetworkMode = mode
+		if err := sd.ValidEntity(); err != nil {
+			t.Errorf("Unexpected error for network mode %q: %v", mode, err)
+		}
+	}
+
+	sd.Services[0].NetworkMode = "overlay"
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "network mode") {
+		t.Errorf("Expected network mode error, got %v", err)
+	}
+}
+
+func TestServiceDefinitionTmpfs(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].Tmpfs = []TmpfsMount{{ContainerPath: "/var/cache", Options: "noexec"}, {ContainerPath: "/run"}}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, mounts := range [][]TmpfsMount{
+		{{ContainerPath: "var/cache"}},
+		{{ContainerPath: "/run"}, {ContainerPath: "/run/"}},
+		{{ContainerPath: "/run", Options: "noexec,size=64m"}},
+	} {
+		sd.Services[0].Tmpfs = mounts
+		if err := sd.ValidEntity(); err == nil {
+			t.Errorf("Expected error for tmpfs mounts %+v", mounts)
+		} else if !strings.Contains(err.Error(), "tmpfs") {
+			t.Errorf("Unexpected error for tmpfs mounts %+v: %v", mounts, err)
+		}
+	}
+}
+
+func TestServiceDefinitionReadOnlyRootfs(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	svc := &sd.Services[0]
+	svc.ReadOnlyRootfs = true
+	svc.Volumes = []Volume{{ResourcePath: "data", ContainerPath: "/opt/data", Type: "dfs"}}
+	svc.Tmpfs = []TmpfsMount{{ContainerPath: "/var/run"}}
+	svc.ConfigFiles = map[string]ConfigFile{
+		"/opt/data/app.conf": {Filename: "/opt/data/app.conf"},
+		"/tmp/app.conf":      {Filename: "/tmp/app.conf"},
+	}
+	svc.LogConfigs = []LogConfig{{Path: "/var/run/app.log", Type: "app"}}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	svc.LogConfigs = []LogConfig{{Path: "/opt/database/app.log", Type: "app"}}
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("Expected read-only error, got %v", err)
+	}
+	svc.ReadOnlyRootfs = false
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	svc.UsernsMode = "private"
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "user namespace") {
+		t.Errorf("Expected user namespace error, got %v", err)
+	}
+}
+
+func TestServiceDefinitionPlacement(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].Placement = Placement{
+		Hosts:     []string{"host1", "host2"},
+		Instances: []InstancePlacement{{InstanceID: 0, Host: "host1"}, {InstanceID: 2, Host: "host3"}},
+	}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, placement := range []Placement{
+		{Hosts: []string{" "}},
+		{Instances: []InstancePlacement{{InstanceID: -1, Host: "host1"}}},
+		{Instances: []InstancePlacement{{InstanceID: 1}}},
+		{Instances: []InstancePlacement{{InstanceID: 1, Host: "host1"}, {InstanceID: 1, Host: "host2"}}},
+	} {
+		sd.Services[0].Placement = placement
+		if err := sd.ValidEntity(); err == nil {
+			t.Errorf("Expected error for placement %+v", placement)
+		} else if !strings.Contains(err.Error(), "place") {
+			t.Errorf("Unexpected error for placement %+v: %v", placement, err)
+		}
+	}
+}
+
+func TestServiceDefinitionSnapshotCommands(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].Snapshot = SnapshotCommands{Pause: "flush", Resume: "unflush", Timeout: 30, OnFailure: SnapshotContinue, Group: "app", Order: 2}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, snapshot := range []SnapshotCommands{
+		{Pause: "flush", Timeout: -1},
+		{Pause: "flush", OnFailure: "retry"},
+		{Pause: "flush", Group: "app", Order: -1},
+		{Pause: "flush", Order: 1},
+	} {
+		sd.Services[0].Snapshot = snapshot
+		if err := sd.ValidEntity(); err == nil {
+			t.Errorf("Expected error for snapshot commands %+v", snapshot)
+		} else if !strings.Contains(err.Error(), "snapshot") {
+			t.Errorf("Unexpected error for snapshot commands %+v: %v", snapshot, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicetemplate
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+// Severity describes how serious a lint issue is
+type Severity string
+
+const (
+	// LintError is an issue that will break the deployed application
+	LintError Severity = "error"
+	// LintWarning is an issue that is likely a mistake; warnings are only
+	// treated as failures in strict mode
+	LintWarning Severity = "warning"
+)
+
+// LintIssue is a problem found by a lint rule
+type LintIssue struct {
+	Rule     string
+	Severity Severity
+	Service  string // Path of the service definition within the template
+	Message  string
+}
+
+func (issue LintIssue) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", issue.Severity, issue.Rule, issue.Service, issue.Message)
+}
+
+// LintService is a service definition along with its path in the template
+type LintService struct {
+	Path       string
+	Definition *servicedefinition.ServiceDefinition
+}
+
+// LintRule inspects a service template and reports any issues it finds.  The
+// services are listed in the order they appear in the template.
+type LintRule interface {
+	Name() string
+	Lint(st *ServiceTemplate, svcs []LintService) []LintIssue
+}
+
+// LintRuleFunc adapts a function into a LintRule
+type LintRuleFunc struct {
+	RuleName string
+	Func     func(st *ServiceTemplate, svcs []LintService) []LintIssue
+}
+
+// Name returns the name of the rule
+func (r LintRuleFunc) Name() string {
+	return r.RuleName
+}
+
+// Lint runs the rule
+func (r LintRuleFunc) Lint(st *ServiceTemplate, svcs []LintService) []LintIssue {
+	return r.Func(st, svcs)
+}
+
+var (
+	lintRulesLock sync.RWMutex
+	lintRules     = make(map[string]LintRule)
+)
+
+// RegisterLintRule adds a rule to the set of rules applied by Lint.  A rule
+// with the same name as an existing rule replaces it.
+func RegisterLintRule(rule LintRule) {
+	lintRulesLock.Lock()
+	defer lintRulesLock.Unlock()
+	lintRules[rule.Name()] = rule
+}
+
+// LintRules returns the names of all registered lint rules
+func LintRules() []string {
+	lintRulesLock.RLock()
+	defer lintRulesLock.RUnlock()
+	return lintRuleNames()
+}
+
+// lintRuleNames returns the sorted rule names; the caller must hold the lock.
+func lintRuleNames() []string {
+	names := make([]string, 0, len(lintRules))
+	for name := range lintRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lint applies all of the registered rules to the template.  Issues are
+// reported in rule name order.
+func Lint(st *ServiceTemplate) []LintIssue {
+	var svcs []LintService
+	var walk func(parent string, defs []servicedefinition.ServiceDefinition)
+	walk = func(parent string, defs []servicedefinition.ServiceDefinition) {
+		for i := range defs {
+			p := path.Join(parent, defs[i].Name)
+			svcs = append(svcs, LintService{Path: p, Definition: &defs[i]})
+			walk(p, defs[i].Services)
+		}
+	}
+	walk("/", st.Services)
+
+	var issues []LintIssue
+	lintRulesLock.RLock()
+	defer lintRulesLock.RUnlock()
+	for _, name := range lintRuleNames() {
+		for _, issue := range lintRules[name].Lint(st, svcs) {
+			issue.Rule = name
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// LintFailed returns true if any of the issues should fail validation
+func LintFailed(issues []LintIssue, strict bool) bool {
+	for _, issue := range issues {
+		if issue.Severity == LintError || strict {
+			return true
+		}
+	}
+	return false
+}
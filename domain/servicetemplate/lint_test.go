@@ -0,0 +1,126 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package servicetemplate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+func TestLint_Clean(t *testing.T) {
+	st := ServiceTemplate{
+		Services: []servicedefinition.ServiceDefinition{
+			{
+				Name:    "svc",
+				ImageID: "zenoss/core:5.2",
+				Endpoints: []servicedefinition.EndpointDefinition{
+					{Name: "http", Application: "http", Purpose: "export"},
+					{Name: "db", Application: "mysql", Purpose: "import"},
+				},
+				Commands: map[string]domain.Command{"upgrade": {Command: "upgrade.sh"}},
+			},
+		},
+	}
+	if issues := Lint(&st); len(issues) > 0 {
+		t.Errorf("Expected no issues, got %v", issues)
+	}
+}
+
+func TestLint_DuplicateApplications(t *testing.T) {
+	st := ServiceTemplate{
+		Services: []servicedefinition.ServiceDefinition{
+			{
+				Name: "a",
+				Endpoints: []servicedefinition.EndpointDefinition{
+					{Name: "http", Application: "http", Purpose: "export"},
+					{Name: "http2", Application: "http", Purpose: "import"},
+				},
+			}, {
+				Name: "b",
+				Endpoints: []servicedefinition.EndpointDefinition{
+					{Name: "http", Application: "http", Purpose: "export"},
+				},
+			},
+		},
+	}
+	expected := []LintIssue{
+		{Rule: "duplicate-application", Severity: LintError, Service: "/a", Message: "endpoints http and http2 both use application http"},
+		{Rule: "duplicate-application", Severity: LintWarning, Service: "/b", Message: "application http is also exported by /a"},
+	}
+	if issues := Lint(&st); !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected %v, got %v", expected, issues)
+	}
+}
+
+func TestLint_RegisterRule(t *testing.T) {
+	RegisterLintRule(LintRuleFunc{"test-rule", func(st *ServiceTemplate, svcs []LintService) []LintIssue {
+		var issues []LintIssue
+		for _, svc := range svcs {
+			if svc.Definition.Description == "" {
+				issues = append(issues, LintIssue{Severity: LintWarning, Service: svc.Path, Message: "no description"})
+			}
+		}
+		return issues
+	}})
+	defer func() {
+		lintRulesLock.Lock()
+		delete(lintRules, "test-rule")
+		lintRulesLock.Unlock()
+	}()
+
+	st := ServiceTemplate{
+		Services: []servicedefinition.ServiceDefinition{
+			{
+				Name:        "parent",
+				Description: "parent service",
+				Services: []servicedefinition.ServiceDefinition{
+					{Name: "child"},
+				},
+			},
+		},
+	}
+	expected := []LintIssue{
+		{Rule: "test-rule", Severity: LintWarning, Service: "/parent/child", Message: "no description"},
+	}
+	issues := Lint(&st)
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected %v, got %v", expected, issues)
+	}
+	if LintFailed(issues, false) {
+		t.Errorf("Expected warnings to pass when not strict")
+	}
+	if !LintFailed(issues, true) {
+		t.Errorf("Expected warnings to fail when strict")
+	}
+}
+
+func TestBalancedQuotes(t *testing.T) {
+	for script, expected := range map[string]bool{
+		`curl -f http://localhost`:    true,
+		`echo "it's ok"`:              true,
+		`echo 'say "hi"'`:             true,
+		`echo \"escaped`:              true,
+		`curl 'http://localhost`:      false,
+		`echo "unterminated \" quote`: false,
+	} {
+		if actual := balancedQuotes(script); actual != expected {
+			t.Errorf("balancedQuotes(%q): expected %v, got %v", script, expected, actual)
+		}
+	}
+}
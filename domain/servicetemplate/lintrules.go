@@ -0,0 +1,240 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicetemplate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/control-center/serviced/commons"
+)
+
+func init() {
+	RegisterLintRule(LintRuleFunc{"duplicate-application", lintDuplicateApplications})
+	RegisterLintRule(LintRuleFunc{"image-tag", lintImageTags})
+	RegisterLintRule(LintRuleFunc{"healthcheck", lintHealthChecks})
+	RegisterLintRule(LintRuleFunc{"unreachable-command", lintUnreachableCommands})
+	RegisterLintRule(LintRuleFunc{"config-file-path", lintConfigFilePaths})
+}
+
+// lintDuplicateApplications reports services that define more than one
+// endpoint for the same application, and applications that are exported by
+// more than one service.
+func lintDuplicateApplications(st *ServiceTemplate, svcs []LintService) []LintIssue {
+	var issues []LintIssue
+	exporters := make(map[string]string)
+	for _, svc := range svcs {
+		apps := make(map[string]string)
+		for _, ep := range svc.Definition.Endpoints {
+			if ep.Application == "" {
+				continue
+			}
+			if other, ok := apps[ep.Application]; ok {
+				issues = append(issues, LintIssue{
+					Severity: LintError,
+					Service:  svc.Path,
+					Message:  fmt.Sprintf("endpoints %s and %s both use application %s", other, ep.Name, ep.Application),
+				})
+				continue
+			}
+			apps[ep.Application] = ep.Name
+
+			if ep.Purpose != "export" {
+				continue
+			}
+			if other, ok := exporters[ep.Application]; ok {
+				issues = append(issues, LintIssue{
+					Severity: LintWarning,
+					Service:  svc.Path,
+					Message:  fmt.Sprintf("application %s is also exported by %s", ep.Application, other),
+				})
+			} else {
+				exporters[ep.Application] = svc.Path
+			}
+		}
+	}
+	return issues
+}
+
+// lintImageTags reports images that cannot be parsed or do not specify a tag.
+func lintImageTags(st *ServiceTemplate, svcs []LintService) []LintIssue {
+	var issues []LintIssue
+	for _, svc := range svcs {
+		if svc.Definition.ImageID == "" {
+			continue
+		}
+		imageID, err := commons.ParseImageID(svc.Definition.ImageID)
+		if err != nil {
+			issues = append(issues, LintIssue{
+				Severity: LintError,
+				Service:  svc.Path,
+				Message:  fmt.Sprintf("could not parse image %s: %s", svc.Definition.ImageID, err),
+			})
+		} else if imageID.Tag == "" {
+			issues = append(issues, LintIssue{
+				Severity: LintWarning,
+				Service:  svc.Path,
+				Message:  fmt.Sprintf("image %s does not specify a tag", svc.Definition.ImageID),
+			})
+		}
+	}
+	return issues
+}
+
+// lintHealthChecks reports health checks without a runnable script and
+// health checks that time out after their next scheduled run.
+func lintHealthChecks(st *ServiceTemplate, svcs []LintService) []LintIssue {
+	var issues []LintIssue
+	for _, svc := range svcs {
+		var names []string
+		for name := range svc.Definition.HealthChecks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			hc := svc.Definition.HealthChecks[name]
+			script := strings.TrimSpace(hc.Script)
+			switch {
+			case script == "":
+				issues = append(issues, LintIssue{
+					Severity: LintError,
+					Service:  svc.Path,
+					Message:  fmt.Sprintf("health check %s has no script", name),
+				})
+			case !balancedQuotes(script):
+				issues = append(issues, LintIssue{
+					Severity: LintError,
+					Service:  svc.Path,
+					Message:  fmt.Sprintf("health check %s has an unterminated quote", name),
+				})
+			}
+			if hc.Interval < 0 || hc.Timeout < 0 {
+				issues = append(issues, LintIssue{
+					Severity: LintError,
+					Service:  svc.Path,
+					Message:  fmt.Sprintf("health check %s has a negative interval or timeout", name),
+				})
+			} else if hc.Interval > 0 && hc.Timeout > hc.Interval {
+				issues = append(issues, LintIssue{
+					Severity: LintWarning,
+					Service:  svc.Path,
+					Message:  fmt.Sprintf("health check %s timeout %s is longer than its interval %s", name, hc.Timeout, hc.Interval),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// lintUnreachableCommands reports runs, commands, and actions that can never
+// be executed, either because they are empty or because the service has no
+// image to run them in.
+func lintUnreachableCommands(st *ServiceTemplate, svcs []LintService) []LintIssue {
+	var issues []LintIssue
+	for _, svc := range svcs {
+		sd := svc.Definition
+		commands := make(map[string]string)
+		for name, cmd := range sd.Runs {
+			commands["run "+name] = cmd
+		}
+		for name, cmd := range sd.Commands {
+			commands["command "+name] = cmd.Command
+		}
+		for name, cmd := range sd.Actions {
+			commands["action "+name] = cmd
+		}
+
+		var names []string
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if sd.ImageID == "" {
+				issues = append(issues, LintIssue{
+					Severity: LintError,
+					Service:  svc.Path,
+					Message:  fmt.Sprintf("%s cannot be run because the service has no image", name),
+				})
+			} else if strings.TrimSpace(commands[name]) == "" {
+				issues = append(issues, LintIssue{
+					Severity: LintError,
+					Service:  svc.Path,
+					Message:  fmt.Sprintf("%s has no command", name),
+				})
+			} else if sd.DisableShell && strings.HasPrefix(name, "run ") {
+				issues = append(issues, LintIssue{
+					Severity: LintWarning,
+					Service:  svc.Path,
+					Message:  fmt.Sprintf("%s cannot be run because the shell is disabled", name),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// lintConfigFilePaths reports config files of a service that write to the
+// same path.
+func lintConfigFilePaths(st *ServiceTemplate, svcs []LintService) []LintIssue {
+	var issues []LintIssue
+	for _, svc := range svcs {
+		files := svc.Definition.ConfigFiles
+		paths := make(map[string]string)
+		var keys []string
+		for key := range files {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			filename := files[key].Filename
+			if filename == "" {
+				filename = key
+			}
+			if other, ok := paths[filename]; ok {
+				issues = append(issues, LintIssue{
+					Severity: LintError,
+					Service:  svc.Path,
+					Message:  fmt.Sprintf("config files %s and %s both write to %s", other, key, filename),
+				})
+				continue
+			}
+			paths[filename] = key
+		}
+	}
+	return issues
+}
+
+// balancedQuotes returns true if every single and double quote in the script
+// is terminated.
+func balancedQuotes(script string) bool {
+	var quote rune
+	escaped := false
+	for _, c := range script {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		}
+	}
+	return quote == 0
+}
@@ -14,7 +14,6 @@
 package servicetemplate
 
 import (
-	"github.com/zenoss/glog"
 	"github.com/control-center/serviced/datastore/elastic"
 )
 
@@ -61,6 +60,6 @@ var (
 
 func init() {
 	if mappingError != nil {
-		glog.Fatalf("error creating host mapping: %v", mappingError)
+		plog.Fatalf("error creating host mapping: %v", mappingError)
 	}
 }
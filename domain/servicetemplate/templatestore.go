@@ -16,7 +16,6 @@ package servicetemplate
 import (
 	"github.com/control-center/serviced/datastore"
 	"github.com/zenoss/elastigo/search"
-	"github.com/zenoss/glog"
 
 	"fmt"
 )
@@ -76,7 +75,7 @@ func (s *storeImpl) Delete(ctx datastore.Context, id string) error {
 
 // GetServiceTemplates returns all ServiceTemplates
 func (s *storeImpl) GetServiceTemplates(ctx datastore.Context) ([]*ServiceTemplate, error) {
-	glog.V(3).Infof("Store.GetServiceTemplates")
+	plog.Debugf("Store.GetServiceTemplates")
 	q := datastore.NewQuery(ctx)
 	query := search.Query().Search("_exists_:ID")
 	search := search.Search("controlplane").Type(kind).Query(query)
@@ -0,0 +1,62 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statehistory
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// Sources of a desired state change
+const (
+	SourceCLI       = "cli"       // a user ran a serviced command
+	SourceUI        = "ui"        // a user logged into the web ui
+	SourceAPI       = "api"       // a request authenticated with an api token
+	SourceScheduler = "scheduler" // a schedule profile was applied
+	SourceEmergency = "emergency" // an emergency shutdown stopped the service
+	SourceSnapshot  = "snapshot"  // a snapshot paused and resumed the service
+	SourceRollback  = "rollback"  // a rollback stopped and restarted the service
+	SourceSystem    = "system"    // serviced changed the state on its own
+)
+
+// MaxChanges is the number of changes kept in the history of a service; the
+// oldest changes are dropped first.
+const MaxChanges = 200
+
+// Change is a change of the desired state of a service
+type Change struct {
+	Time   time.Time
+	From   service.DesiredState // desired state before the change
+	To     service.DesiredState // desired state that was requested
+	Source string               // one of the Source* values
+	User   string               // user that requested the change, if known
+}
+
+// History is the append-only log of the desired state changes of a service
+type History struct {
+	ServiceID string
+	Changes   []Change // oldest first
+	datastore.VersionedEntity
+}
+
+// Add appends a change to the history, dropping the oldest changes when there
+// are more than MaxChanges.
+func (h *History) Add(change Change) {
+	h.Changes = append(h.Changes, change)
+	if n := len(h.Changes) - MaxChanges; n > 0 {
+		h.Changes = append([]Change{}, h.Changes[n:]...)
+	}
+}
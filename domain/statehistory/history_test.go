@@ -0,0 +1,52 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package statehistory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+)
+
+func TestHistoryAdd(t *testing.T) {
+	h := &History{ServiceID: "service-1"}
+	start := time.Date(2016, 6, 1, 3, 0, 0, 0, time.UTC)
+	for i := 0; i < MaxChanges+5; i++ {
+		h.Add(Change{Time: start.Add(time.Duration(i) * time.Minute), From: service.SVCRun, To: service.SVCStop, Source: SourceCLI})
+	}
+	if len(h.Changes) != MaxChanges {
+		t.Fatalf("Expected %d changes, got %d", MaxChanges, len(h.Changes))
+	}
+	if first := h.Changes[0].Time; !first.Equal(start.Add(5 * time.Minute)) {
+		t.Errorf("Expected the oldest changes to be dropped, first change is at %s", first)
+	}
+	if last := h.Changes[MaxChanges-1].Time; !last.Equal(start.Add(time.Duration(MaxChanges+4) * time.Minute)) {
+		t.Errorf("Expected the newest change last, got %s", last)
+	}
+}
+
+func TestHistoryValidEntity(t *testing.T) {
+	if err := (&History{ServiceID: "service-1"}).ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if err := (&History{}).ValidEntity(); err == nil {
+		t.Error("Expected error for empty service id")
+	}
+	if err := (&History{ServiceID: " service-1"}).ValidEntity(); err == nil {
+		t.Error("Expected error for service id with spaces")
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statehistory
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+)
+
+const kind = "servicestatehistory"
+
+var (
+	mappingString = fmt.Sprintf(`
+{
+    "%s": {
+        "properties": {
+            "ServiceID": {"type": "string", "index": "not_analyzed"},
+            "Changes": {
+                "properties": {
+                    "Time":   {"type": "date",   "format": "dateOptionalTime"},
+                    "From":   {"type": "long",   "index": "not_analyzed"},
+                    "To":     {"type": "long",   "index": "not_analyzed"},
+                    "Source": {"type": "string", "index": "not_analyzed"},
+                    "User":   {"type": "string", "index": "not_analyzed"}
+                }
+            }
+        }
+    }
+}
+`, kind)
+	// MAPPING is the elastic mapping for the state histories of services
+	MAPPING, mappingError = elastic.NewMapping(mappingString)
+)
+
+func init() {
+	if mappingError != nil {
+		plog.Fatalf("error creating service state history mapping: %s", mappingError)
+	}
+}
+
+// Key returns the datastore key of the state history of a service
+func Key(serviceID string) datastore.Key {
+	serviceID = strings.TrimSpace(serviceID)
+	return datastore.NewKey(kind, serviceID)
+}
@@ -0,0 +1,56 @@
+package mocks
+
+import "github.com/control-center/serviced/domain/statehistory"
+import "github.com/stretchr/testify/mock"
+
+import "github.com/control-center/serviced/datastore"
+
+type Store struct {
+	mock.Mock
+}
+
+func (_m *Store) Get(ctx datastore.Context, serviceID string) (*statehistory.History, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 *statehistory.History
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *statehistory.History); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*statehistory.History)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *Store) Append(ctx datastore.Context, serviceID string, change statehistory.Change) error {
+	ret := _m.Called(ctx, serviceID, change)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, statehistory.Change) error); ok {
+		r0 = rf(ctx, serviceID, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *Store) Delete(ctx datastore.Context, serviceID string) error {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
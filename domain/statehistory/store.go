@@ -0,0 +1,70 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statehistory
+
+import (
+	"sync"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// NewStore creates a new service state history store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Store is the database for the state histories of services
+type Store interface {
+	// Get the state history of a service.  Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, serviceID string) (*History, error)
+
+	// Append adds a change to the state history of a service
+	Append(ctx datastore.Context, serviceID string, change Change) error
+
+	// Delete removes the state history of a service
+	Delete(ctx datastore.Context, serviceID string) error
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+	mu sync.Mutex // serializes appends, so that concurrent changes are not lost
+}
+
+// Get the state history of a service.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, serviceID string) (*History, error) {
+	val := &History{}
+	if err := s.ds.Get(ctx, Key(serviceID), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Append adds a change to the state history of a service
+func (s *storeImpl) Append(ctx datastore.Context, serviceID string, change Change) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	val, err := s.Get(ctx, serviceID)
+	if datastore.IsErrNoSuchEntity(err) {
+		val = &History{ServiceID: serviceID}
+	} else if err != nil {
+		return err
+	}
+	val.Add(change)
+	return s.ds.Put(ctx, Key(serviceID), val)
+}
+
+// Delete removes the state history of a service
+func (s *storeImpl) Delete(ctx datastore.Context, serviceID string) error {
+	return s.ds.Delete(ctx, Key(serviceID))
+}
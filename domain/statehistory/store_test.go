@@ -0,0 +1,82 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package statehistory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&S{
+	ElasticTest: elastic.ElasticTest{
+		Index:    "controlplane",
+		Mappings: []elastic.Mapping{MAPPING},
+	}})
+
+type S struct {
+	elastic.ElasticTest
+	ctx   datastore.Context
+	store Store
+}
+
+func (s *S) SetUpTest(c *C) {
+	s.ElasticTest.SetUpTest(c)
+	datastore.Register(s.Driver())
+	s.ctx = datastore.Get()
+	s.store = NewStore()
+}
+
+func (s *S) Test_HistoryCRUD(c *C) {
+	_, err := s.store.Get(s.ctx, "service-1")
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+
+	stop := Change{
+		Time:   time.Date(2016, 6, 1, 3, 0, 0, 0, time.UTC),
+		From:   service.SVCRun,
+		To:     service.SVCStop,
+		Source: SourceCLI,
+		User:   "zenoss",
+	}
+	c.Assert(s.store.Append(s.ctx, "service-1", stop), IsNil)
+	start := Change{
+		Time:   stop.Time.Add(time.Hour),
+		From:   service.SVCStop,
+		To:     service.SVCRun,
+		Source: SourceScheduler,
+	}
+	c.Assert(s.store.Append(s.ctx, "service-1", start), IsNil)
+
+	actual, err := s.store.Get(s.ctx, "service-1")
+	c.Assert(err, IsNil)
+	c.Assert(actual.Changes, HasLen, 2)
+	c.Assert(actual.Changes[0].User, Equals, "zenoss")
+	c.Assert(actual.Changes[0].To, Equals, service.SVCStop)
+	c.Assert(actual.Changes[1].Source, Equals, SourceScheduler)
+
+	c.Assert(s.store.Delete(s.ctx, "service-1"), IsNil)
+	_, err = s.store.Get(s.ctx, "service-1")
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+}
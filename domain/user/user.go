@@ -17,7 +17,22 @@ import "github.com/control-center/serviced/datastore"
 
 // User for the system???
 type User struct {
-	Name     string // the unique identifier for a user
-	Password string // no requirements on passwords yet
+	Name     string   // the unique identifier for a user
+	Password string   // no requirements on passwords yet
+	Tenants  []string // tenant ids the user may access; empty means all tenants
 	datastore.VersionedEntity
 }
+
+// CanAccessTenant returns true if the user is allowed to see and operate on
+// services in the given tenant.
+func (u User) CanAccessTenant(tenantID string) bool {
+	if len(u.Tenants) == 0 {
+		return true
+	}
+	for _, id := range u.Tenants {
+		if id == tenantID {
+			return true
+		}
+	}
+	return false
+}
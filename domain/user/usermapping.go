@@ -14,7 +14,6 @@
 package user
 
 import (
-	"github.com/zenoss/glog"
 	"github.com/control-center/serviced/datastore/elastic"
 )
 
@@ -24,7 +23,8 @@ var (
      "user": {
       "properties":{
         "Name":           {"type": "string", "index":"not_analyzed"},
-        "Password":       {"type": "string", "index":"not_analyzed"}
+        "Password":       {"type": "string", "index":"not_analyzed"},
+        "Tenants":        {"type": "string", "index":"not_analyzed"}
       }
     }
 }
@@ -35,6 +35,6 @@ var (
 
 func init() {
 	if mappingError != nil {
-		glog.Fatalf("error creating user mapping: %v", mappingError)
+		plog.Fatalf("error creating user mapping: %v", mappingError)
 	}
 }
@@ -14,7 +14,6 @@
 package user
 
 import (
-	"github.com/zenoss/glog"
 	"github.com/control-center/serviced/validation"
 
 	"strings"
@@ -22,7 +21,7 @@ import (
 
 //ValidEntity validates Host fields
 func (u *User) ValidEntity() error {
-	glog.V(4).Info("Validating User")
+	plog.Debug("Validating User")
 
 	trimmed := strings.TrimSpace(u.Name)
 	violations := validation.NewValidationError()
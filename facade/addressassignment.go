@@ -15,11 +15,16 @@ package facade
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/commons"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/utils"
 )
@@ -182,6 +187,26 @@ func (f *Facade) assign(ctx datastore.Context, assignment addressassignment.Addr
 		return "", fmt.Errorf("found assignment for port %d at %s", assignment.Port, assignment.IPAddr)
 	}
 
+	// Do not add if the port is claimed by another service in the pool
+	protocol := commons.TCP
+	if svc, err := f.serviceStore.Get(ctx, assignment.ServiceID); err == nil {
+		for _, ep := range svc.Endpoints {
+			if ep.Name == assignment.EndpointName {
+				protocol = portProtocol(ep.AddressConfig.Protocol)
+			}
+		}
+	}
+	claim := pool.PortClaim{
+		IPAddr:       assignment.IPAddr,
+		Port:         assignment.Port,
+		Protocol:     protocol,
+		ServiceID:    assignment.ServiceID,
+		EndpointName: assignment.EndpointName,
+	}
+	if err := f.checkPortClaims(ctx, assignment.PoolID, []pool.PortClaim{claim}); err != nil {
+		return "", err
+	}
+
 	var err error
 	if assignment.ID, err = utils.NewUUID36(); err != nil {
 		return "", err
@@ -194,3 +219,419 @@ func (f *Facade) assign(ctx datastore.Context, assignment addressassignment.Addr
 
 	return assignment.ID, nil
 }
+
+// RebalanceAddressAssignments moves the address assignments in a pool that
+// point to hosts that have been removed or are not active, or to virtual ips
+// that no longer exist, onto other eligible ips in the pool.
+func (f *Facade) RebalanceAddressAssignments(ctx datastore.Context, poolID string) ([]addressassignment.Reassignment, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("RebalanceAddressAssignments"))
+	logger := plog.WithField("poolid", poolID)
+
+	pool, err := f.GetResourcePool(ctx, poolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up pool")
+		return nil, err
+	} else if pool == nil {
+		return nil, ErrPoolNotExists
+	}
+
+	hosts, err := f.FindHostsInPool(ctx, poolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up hosts in pool")
+		return nil, err
+	}
+
+	var active []string
+	if err := f.zzk.GetActiveHosts(poolID, &active); err != nil {
+		logger.WithError(err).Debug("Could not look up active hosts in pool")
+		return nil, err
+	}
+	activeHosts := make(map[string]struct{})
+	for _, hostID := range active {
+		activeHosts[hostID] = struct{}{}
+	}
+
+	// hosts that are registered but not running cannot receive assignments
+	excludeHosts := make(map[string]struct{})
+	for _, h := range hosts {
+		if _, ok := activeHosts[h.ID]; !ok {
+			excludeHosts[h.ID] = struct{}{}
+		}
+	}
+
+	vips := make(map[string]struct{})
+	for _, vip := range pool.VirtualIPs {
+		vips[vip.IP] = struct{}{}
+	}
+
+	store := addressassignment.NewStore()
+	assignments, err := store.GetPoolAddressAssignments(ctx, poolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up address assignments for pool")
+		return nil, err
+	}
+
+	stale := make(map[string][]addressassignment.AddressAssignment)
+	var serviceIDs []string
+	for _, assignment := range assignments {
+		if assignment.AssignmentType == commons.VIRTUAL {
+			if _, ok := vips[assignment.IPAddr]; ok {
+				continue
+			}
+		} else if _, ok := activeHosts[assignment.HostID]; ok {
+			continue
+		}
+		if _, ok := stale[assignment.ServiceID]; !ok {
+			serviceIDs = append(serviceIDs, assignment.ServiceID)
+		}
+		stale[assignment.ServiceID] = append(stale[assignment.ServiceID], assignment)
+	}
+
+	var report []addressassignment.Reassignment
+	for _, serviceID := range serviceIDs {
+		report = append(report, f.reassignServiceIPs(ctx, serviceID, stale[serviceID], excludeHosts))
+	}
+	logger.WithField("reassigned", len(report)).Info("Rebalanced address assignments")
+	return report, nil
+}
+
+// reassignHostIPs moves all address assignments from the given host onto
+// other eligible ips in the host's pool.
+func (f *Facade) reassignHostIPs(ctx datastore.Context, hostID string, excludeHosts map[string]struct{}) ([]addressassignment.Reassignment, error) {
+	store := addressassignment.NewStore()
+	assignments, err := store.GetHostAddressAssignments(ctx, hostID)
+	if err != nil {
+		return nil, err
+	}
+
+	stale := make(map[string][]addressassignment.AddressAssignment)
+	var serviceIDs []string
+	for _, assignment := range assignments {
+		if _, ok := stale[assignment.ServiceID]; !ok {
+			serviceIDs = append(serviceIDs, assignment.ServiceID)
+		}
+		stale[assignment.ServiceID] = append(stale[assignment.ServiceID], assignment)
+	}
+
+	var report []addressassignment.Reassignment
+	for _, serviceID := range serviceIDs {
+		report = append(report, f.reassignServiceIPs(ctx, serviceID, stale[serviceID], excludeHosts))
+	}
+	return report, nil
+}
+
+// reassignServiceIPs removes the stale address assignments of a service and
+// auto-assigns it a new ip.  If no ip is available, the service is left
+// without an assignment and flagged as blocked, and the scheduler will not
+// start it until an ip is assigned.
+func (f *Facade) reassignServiceIPs(ctx datastore.Context, serviceID string, stale []addressassignment.AddressAssignment, excludeHosts map[string]struct{}) addressassignment.Reassignment {
+	logger := plog.WithField("serviceid", serviceID)
+	result := addressassignment.Reassignment{ServiceID: serviceID}
+	oldIPs := make(map[string]struct{})
+	for _, assignment := range stale {
+		if _, ok := oldIPs[assignment.IPAddr]; !ok {
+			oldIPs[assignment.IPAddr] = struct{}{}
+			result.OldIPAddrs = append(result.OldIPAddrs, assignment.IPAddr)
+		}
+	}
+
+	for _, assignment := range stale {
+		if err := f.RemoveAddressAssignment(ctx, assignment.ID); err != nil {
+			logger.WithField("endpoint", assignment.EndpointName).WithError(err).Warn("Could not remove stale address assignment")
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	request := addressassignment.AssignmentRequest{
+		ServiceID:      serviceID,
+		AutoAssignment: true,
+	}
+	if err := f.assignIPs(ctx, request, false, excludeHosts); err != nil {
+		logger.WithError(err).Warn("Could not reassign ip to service; service is blocked until an ip is assigned")
+		result.Error = err.Error()
+		if err := f.setAddressBlocked(ctx, serviceID, fmt.Sprintf("lost ip %s: %s", strings.Join(result.OldIPAddrs, ","), err)); err != nil {
+			logger.WithError(err).Warn("Could not flag service as blocked")
+		}
+		return result
+	}
+
+	assignments, err := f.GetServiceAddressAssignments(ctx, serviceID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if len(assignments) > 0 {
+		result.NewIPAddr = assignments[0].IPAddr
+	}
+	logger.WithFields(log.Fields{
+		"oldipaddrs": result.OldIPAddrs,
+		"newipaddr":  result.NewIPAddr,
+	}).Info("Reassigned ip to service")
+	return result
+}
+
+// setAddressBlocked records why a service cannot be started without an
+// address assignment, or clears it if the reason is empty.
+func (f *Facade) setAddressBlocked(ctx datastore.Context, serviceID, reason string) error {
+	svc, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		return err
+	} else if svc.AddressBlocked == reason {
+		return nil
+	}
+	svc.AddressBlocked = reason
+	svc.UpdatedAt = time.Now()
+	return f.serviceStore.Put(ctx, svc)
+}
+
+// DefaultLostHostTimeout is how long a host may be disconnected from its pool
+// before its address assignments are moved to other hosts
+const DefaultLostHostTimeout = 5 * time.Minute
+
+// ReassignLostHostIPs moves the address assignments of hosts that have been
+// disconnected from their pool for longer than the timeout onto other
+// eligible ips in the pool.  Returns the outcome for each service that had
+// an assignment on a lost host.
+func (f *Facade) ReassignLostHostIPs(ctx datastore.Context, timeout time.Duration) ([]addressassignment.Reassignment, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ReassignLostHostIPs"))
+	pools, err := f.GetResourcePools(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up resource pools")
+		return nil, err
+	}
+
+	f.lostLock.Lock()
+	defer f.lostLock.Unlock()
+	if f.lostHosts == nil {
+		f.lostHosts = make(map[string]time.Time)
+	}
+
+	now := time.Now()
+	known := make(map[string]struct{})
+	var report []addressassignment.Reassignment
+	for _, p := range pools {
+		logger := plog.WithField("poolid", p.ID)
+
+		hosts, err := f.FindHostsInPool(ctx, p.ID)
+		if err != nil {
+			logger.WithError(err).Debug("Could not look up hosts in pool")
+			return nil, err
+		}
+
+		var active []string
+		if err := f.zzk.GetActiveHosts(p.ID, &active); err != nil {
+			logger.WithError(err).Debug("Could not look up active hosts in pool")
+			return nil, err
+		}
+		activeHosts := make(map[string]struct{})
+		for _, hostID := range active {
+			activeHosts[hostID] = struct{}{}
+		}
+
+		excludeHosts := make(map[string]struct{})
+		for _, h := range hosts {
+			if _, ok := activeHosts[h.ID]; !ok {
+				excludeHosts[h.ID] = struct{}{}
+			}
+		}
+
+		for _, h := range hosts {
+			known[h.ID] = struct{}{}
+			if _, ok := activeHosts[h.ID]; ok {
+				delete(f.lostHosts, h.ID)
+				continue
+			}
+			since, ok := f.lostHosts[h.ID]
+			if !ok {
+				f.lostHosts[h.ID] = now
+				continue
+			} else if now.Sub(since) < timeout {
+				continue
+			}
+
+			hostReport, err := f.reassignHostIPs(ctx, h.ID, excludeHosts)
+			if err != nil {
+				logger.WithField("hostid", h.ID).WithError(err).Warn("Could not reassign address assignments from lost host")
+				continue
+			} else if len(hostReport) > 0 {
+				logger.WithFields(log.Fields{
+					"hostid":     h.ID,
+					"lostsince":  since,
+					"reassigned": len(hostReport),
+				}).Warn("Moved address assignments off of lost host")
+			}
+			report = append(report, hostReport...)
+		}
+	}
+
+	// forget hosts that were removed
+	for hostID := range f.lostHosts {
+		if _, ok := known[hostID]; !ok {
+			delete(f.lostHosts, hostID)
+		}
+	}
+	return report, nil
+}
+
+// AssignDeploymentIPs plans an address assignment for every configurable
+// endpoint of the services in a deployment.  Endpoints that are already
+// consistently assigned keep their current ip.  If dryRun is false, the
+// proposed assignments are applied to each service that needs them.
+func (f *Facade) AssignDeploymentIPs(ctx datastore.Context, deploymentID string, dryRun bool) ([]addressassignment.PlannedAssignment, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AssignDeploymentIPs"))
+	logger := plog.WithFields(log.Fields{
+		"deploymentid": deploymentID,
+		"dryrun":       dryRun,
+	})
+
+	svcs, err := f.serviceStore.GetServicesByDeployment(ctx, deploymentID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up services for deployment")
+		return nil, err
+	}
+	sort.Sort(servicesByID(svcs))
+
+	// ip:port pairs that have been proposed as part of this plan
+	reserved := make(map[string]struct{})
+	var plan []addressassignment.PlannedAssignment
+	for _, svc := range svcs {
+		rows, err := f.planServiceIPs(ctx, svc, reserved)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		// only apply the plan if an endpoint's assignment changes
+		update := false
+		for _, row := range rows {
+			update = update || (row.NeedsAssignment() && row.ProposedIPAddr != "")
+		}
+		if update && !dryRun {
+			request := addressassignment.AssignmentRequest{
+				ServiceID: svc.ID,
+				IPAddress: rows[0].ProposedIPAddr,
+			}
+			if err := f.assignIPs(ctx, request, false, nil); err != nil {
+				logger.WithField("serviceid", svc.ID).WithError(err).Warn("Could not assign ip to service")
+				for i := range rows {
+					rows[i].Error = err.Error()
+				}
+			}
+		}
+		plan = append(plan, rows...)
+	}
+	logger.WithField("endpoints", len(plan)).Info("Planned address assignments for deployment")
+	return plan, nil
+}
+
+// planServiceIPs returns the current and proposed address assignment for each
+// configurable endpoint of the service, skipping any ip:port that is already
+// reserved.
+func (f *Facade) planServiceIPs(ctx datastore.Context, svc service.Service, reserved map[string]struct{}) ([]addressassignment.PlannedAssignment, error) {
+	var rows []addressassignment.PlannedAssignment
+	addRows := func(current map[string]string, proposed, reason string) {
+		for _, ep := range svc.Endpoints {
+			if !ep.IsConfigurable() {
+				continue
+			}
+			rows = append(rows, addressassignment.PlannedAssignment{
+				ServiceID:      svc.ID,
+				ServiceName:    svc.Name,
+				EndpointName:   ep.Name,
+				Port:           ep.AddressConfig.Port,
+				CurrentIPAddr:  current[ep.Name],
+				ProposedIPAddr: proposed,
+				Error:          reason,
+			})
+		}
+	}
+
+	// a service with conflicting endpoints cannot be assigned
+	portmap, err := GetPorts(svc.Endpoints)
+	if err != nil {
+		addRows(nil, "", err.Error())
+		return rows, nil
+	} else if len(portmap) == 0 {
+		return nil, nil
+	}
+
+	assignments, err := f.GetServiceAddressAssignments(ctx, svc.ID)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]string)
+	var ipaddr string
+	consistent := true
+	for _, a := range assignments {
+		current[a.EndpointName] = a.IPAddr
+		if ipaddr == "" {
+			ipaddr = a.IPAddr
+		} else if ipaddr != a.IPAddr {
+			consistent = false
+		}
+	}
+
+	isReserved := func(ip string) bool {
+		for _, port := range portmap.List() {
+			if _, ok := reserved[fmt.Sprintf("%s:%d", ip, port)]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	// keep the current ip if the remaining endpoints can be assigned to it
+	var proposed, reason string
+	if ipaddr != "" && consistent && !isReserved(ipaddr) {
+		remaining := make(Ports)
+		for port, eps := range portmap {
+			remaining[port] = eps
+		}
+		for _, a := range assignments {
+			delete(remaining, a.Port)
+		}
+		if _, err := f.getManualAssignment(ctx, svc.PoolID, ipaddr, remaining.List()...); err == nil {
+			proposed = ipaddr
+		}
+	}
+
+	// otherwise, pick the first available ip
+	if proposed == "" {
+		ips, err := f.getAssignableIPs(ctx, svc.PoolID, nil, portmap.List()...)
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			candidates = append(candidates, ip.IP)
+		}
+		sort.Strings(candidates)
+		for _, ip := range candidates {
+			if !isReserved(ip) {
+				proposed = ip
+				break
+			}
+		}
+		if proposed == "" {
+			reason = "No IPs are available to be assigned"
+		}
+	}
+
+	if proposed != "" {
+		for _, port := range portmap.List() {
+			reserved[fmt.Sprintf("%s:%d", proposed, port)] = struct{}{}
+		}
+	}
+
+	addRows(current, proposed, reason)
+	return rows, nil
+}
+
+type servicesByID []service.Service
+
+func (s servicesByID) Len() int           { return len(s) }
+func (s servicesByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+func (s servicesByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -16,11 +16,14 @@
 package facade
 
 import (
+	"time"
+
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
 )
 
@@ -152,3 +155,233 @@ func (ft *FacadeIntegrationTest) TestGetServiceAddressAssignmentDetails(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(addrs, HasLen, 0)
 }
+
+func (ft *FacadeIntegrationTest) setupReassignmentTest(c *C) {
+	p := &pool.ResourcePool{
+		ID: "poolid",
+	}
+	c.Assert(ft.Facade.AddResourcePool(ft.CTX, p), IsNil)
+
+	for _, h := range []*host.Host{
+		{
+			ID:      "deadb11f",
+			PoolID:  "poolid",
+			Name:    "h1",
+			IPAddr:  "12.27.36.45",
+			RPCPort: 65535,
+			IPs: []host.HostIPResource{
+				{HostID: "deadb11f", IPAddress: "12.27.36.45"},
+			},
+		}, {
+			ID:      "deadb22f",
+			PoolID:  "poolid",
+			Name:    "h2",
+			IPAddr:  "12.27.36.46",
+			RPCPort: 65535,
+			IPs: []host.HostIPResource{
+				{HostID: "deadb22f", IPAddress: "12.27.36.46"},
+			},
+		},
+	} {
+		_, err := ft.Facade.AddHost(ft.CTX, h)
+		c.Assert(err, IsNil)
+	}
+
+	svc := service.Service{
+		ID:           "serviceid1",
+		Name:         "svcA",
+		DeploymentID: "depid",
+		PoolID:       "poolid",
+		Launch:       "auto",
+		DesiredState: 0,
+		Endpoints: []service.ServiceEndpoint{
+			{
+				Name:        "ep1",
+				Application: "ep1",
+				Purpose:     "export",
+				AddressConfig: servicedefinition.AddressResourceConfig{
+					Port:     1234,
+					Protocol: "tcp",
+				},
+			},
+		},
+	}
+	c.Assert(ft.Facade.AddService(ft.CTX, svc), IsNil)
+
+	req := addressassignment.AssignmentRequest{
+		ServiceID:      "serviceid1",
+		IPAddress:      "12.27.36.45",
+		AutoAssignment: false,
+	}
+	c.Assert(ft.Facade.AssignIPs(ft.CTX, req), IsNil)
+}
+
+func (ft *FacadeIntegrationTest) TestRemoveHost_ReassignsAddressAssignments(c *C) {
+	ft.setupReassignmentTest(c)
+
+	c.Assert(ft.Facade.RemoveHost(ft.CTX, "deadb11f"), IsNil)
+
+	assignments, err := ft.Facade.GetServiceAddressAssignments(ft.CTX, "serviceid1")
+	c.Assert(err, IsNil)
+	c.Assert(assignments, HasLen, 1)
+	c.Assert(assignments[0].HostID, Equals, "deadb22f")
+	c.Assert(assignments[0].IPAddr, Equals, "12.27.36.46")
+}
+
+func (ft *FacadeIntegrationTest) TestRemoveHost_BlocksUnassignableService(c *C) {
+	ft.setupReassignmentTest(c)
+
+	c.Assert(ft.Facade.RemoveHost(ft.CTX, "deadb22f"), IsNil)
+	c.Assert(ft.Facade.RemoveHost(ft.CTX, "deadb11f"), IsNil)
+
+	assignments, err := ft.Facade.GetServiceAddressAssignments(ft.CTX, "serviceid1")
+	c.Assert(err, IsNil)
+	c.Assert(assignments, HasLen, 0)
+	svc, err := ft.Facade.GetService(ft.CTX, "serviceid1")
+	c.Assert(err, IsNil)
+	c.Assert(svc.AddressBlocked, Matches, "lost ip 12.27.36.45: .*")
+
+	// assigning an ip unblocks the service
+	_, err = ft.Facade.AddHost(ft.CTX, &host.Host{
+		ID:      "deadb33f",
+		PoolID:  "poolid",
+		Name:    "h3",
+		IPAddr:  "12.27.36.47",
+		RPCPort: 65535,
+		IPs: []host.HostIPResource{
+			{HostID: "deadb33f", IPAddress: "12.27.36.47"},
+		},
+	})
+	c.Assert(err, IsNil)
+	req := addressassignment.AssignmentRequest{ServiceID: "serviceid1", AutoAssignment: true}
+	c.Assert(ft.Facade.AssignIPs(ft.CTX, req), IsNil)
+	svc, err = ft.Facade.GetService(ft.CTX, "serviceid1")
+	c.Assert(err, IsNil)
+	c.Assert(svc.AddressBlocked, Equals, "")
+}
+
+func (ft *FacadeIntegrationTest) TestReassignLostHostIPs(c *C) {
+	ft.setupReassignmentTest(c)
+	ft.zzk.On("GetActiveHosts", mock.AnythingOfType("string"), mock.AnythingOfType("*[]string")).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]string) = []string{"deadb22f"}
+	})
+
+	// the host is only lost once it has been disconnected for the timeout
+	report, err := ft.Facade.ReassignLostHostIPs(ft.CTX, time.Hour)
+	c.Assert(err, IsNil)
+	c.Assert(report, HasLen, 0)
+	report, err = ft.Facade.ReassignLostHostIPs(ft.CTX, time.Hour)
+	c.Assert(err, IsNil)
+	c.Assert(report, HasLen, 0)
+
+	report, err = ft.Facade.ReassignLostHostIPs(ft.CTX, 0)
+	c.Assert(err, IsNil)
+	c.Assert(report, DeepEquals, []addressassignment.Reassignment{
+		{ServiceID: "serviceid1", OldIPAddrs: []string{"12.27.36.45"}, NewIPAddr: "12.27.36.46"},
+	})
+}
+
+func (ft *FacadeIntegrationTest) TestRebalanceAddressAssignments(c *C) {
+	ft.setupReassignmentTest(c)
+	ft.zzk.On("GetActiveHosts", "poolid", mock.AnythingOfType("*[]string")).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]string) = []string{"deadb22f"}
+	})
+
+	report, err := ft.Facade.RebalanceAddressAssignments(ft.CTX, "poolid")
+	c.Assert(err, IsNil)
+	c.Assert(report, DeepEquals, []addressassignment.Reassignment{
+		{ServiceID: "serviceid1", OldIPAddrs: []string{"12.27.36.45"}, NewIPAddr: "12.27.36.46"},
+	})
+
+	// nothing left to move
+	report, err = ft.Facade.RebalanceAddressAssignments(ft.CTX, "poolid")
+	c.Assert(err, IsNil)
+	c.Assert(report, HasLen, 0)
+}
+
+func (ft *FacadeIntegrationTest) TestAssignDeploymentIPs(c *C) {
+	ft.setupReassignmentTest(c)
+	svc := service.Service{
+		ID:           "serviceid2",
+		Name:         "svcB",
+		DeploymentID: "depid",
+		PoolID:       "poolid",
+		Launch:       "auto",
+		DesiredState: 0,
+		Endpoints: []service.ServiceEndpoint{
+			{
+				Name:        "ep2",
+				Application: "ep2",
+				Purpose:     "export",
+				AddressConfig: servicedefinition.AddressResourceConfig{
+					Port:     1234,
+					Protocol: "tcp",
+				},
+			},
+		},
+	}
+	c.Assert(ft.Facade.AddService(ft.CTX, svc), IsNil)
+
+	expected := []addressassignment.PlannedAssignment{
+		{ServiceID: "serviceid1", ServiceName: "svcA", EndpointName: "ep1", Port: 1234, CurrentIPAddr: "12.27.36.45", ProposedIPAddr: "12.27.36.45"},
+		{ServiceID: "serviceid2", ServiceName: "svcB", EndpointName: "ep2", Port: 1234, ProposedIPAddr: "12.27.36.46"},
+	}
+
+	// dry run does not assign anything
+	plan, err := ft.Facade.AssignDeploymentIPs(ft.CTX, "depid", true)
+	c.Assert(err, IsNil)
+	c.Assert(plan, DeepEquals, expected)
+	assignments, err := ft.Facade.GetServiceAddressAssignments(ft.CTX, "serviceid2")
+	c.Assert(err, IsNil)
+	c.Assert(assignments, HasLen, 0)
+
+	plan, err = ft.Facade.AssignDeploymentIPs(ft.CTX, "depid", false)
+	c.Assert(err, IsNil)
+	c.Assert(plan, DeepEquals, expected)
+	assignments, err = ft.Facade.GetServiceAddressAssignments(ft.CTX, "serviceid2")
+	c.Assert(err, IsNil)
+	c.Assert(assignments, HasLen, 1)
+	c.Assert(assignments[0].IPAddr, Equals, "12.27.36.46")
+}
+
+func (ft *FacadeIntegrationTest) TestGetPoolPortClaims(c *C) {
+	ft.setupReassignmentTest(c)
+	ft.zzk.On("GetPublicPort", mock.AnythingOfType("string")).Return("", "", nil)
+
+	svc := service.Service{
+		ID:           "serviceid2",
+		Name:         "svcB",
+		DeploymentID: "depid",
+		PoolID:       "poolid",
+		Launch:       "auto",
+		DesiredState: 0,
+		Endpoints: []service.ServiceEndpoint{
+			{
+				Name:        "ep2",
+				Application: "ep2",
+				Purpose:     "export",
+				PortList: []servicedefinition.Port{
+					{PortAddr: ":1234", Enabled: true},
+				},
+			},
+		},
+	}
+
+	// the public port collides with the address assignment of serviceid1
+	err := ft.Facade.AddService(ft.CTX, svc)
+	c.Assert(err, NotNil)
+
+	svc.Endpoints[0].PortList[0].PortAddr = ":1235"
+	c.Assert(ft.Facade.AddService(ft.CTX, svc), IsNil)
+
+	claims, err := ft.Facade.GetPoolPortClaims(ft.CTX, "poolid")
+	c.Assert(err, IsNil)
+	c.Assert(claims, DeepEquals, []pool.PortClaim{
+		{IPAddr: "12.27.36.45", Port: 1234, Protocol: "tcp", ServiceID: "serviceid1", ServiceName: "svcA", EndpointName: "ep1"},
+		{IPAddr: "", Port: 1235, Protocol: "tcp", ServiceID: "serviceid2", ServiceName: "svcB", EndpointName: "ep2", Public: true},
+	})
+
+	// enabling a colliding port on update is rejected
+	svc.Endpoints[0].PortList[0].PortAddr = ":1234"
+	c.Assert(ft.Facade.UpdateService(ft.CTX, svc), NotNil)
+}
@@ -0,0 +1,76 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backup"
+)
+
+// AddBackup records a completed backup in the backup catalog, along with the
+// contents of its metadata.
+func (f *Facade) AddBackup(ctx datastore.Context, entry backup.Backup, info *dfs.BackupInfo) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AddBackup"))
+	if info != nil {
+		entry.Templates = make([]string, len(info.Templates))
+		for i, template := range info.Templates {
+			entry.Templates[i] = template.Name
+		}
+		entry.BaseImages = info.BaseImages
+		entry.Pools = make([]string, len(info.Pools))
+		for i, pool := range info.Pools {
+			entry.Pools[i] = pool.ID
+		}
+		entry.Snapshots = info.Snapshots
+		entry.BackupVersion = info.BackupVersion
+	}
+	if err := f.backupStore.Put(ctx, &entry); err != nil {
+		plog.Errorf("Could not add backup %s to the catalog: %s", entry.ID, err)
+		return err
+	}
+	return nil
+}
+
+// GetBackups returns the catalog of completed backups, most recent first.
+// Backups stored on the local filesystem are marked as unavailable if their
+// file no longer exists.
+func (f *Facade) GetBackups(ctx datastore.Context) ([]backup.Backup, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetBackups"))
+	backups, err := f.backupStore.GetBackups(ctx)
+	if err != nil {
+		plog.Errorf("Could not get the backup catalog: %s", err)
+		return nil, err
+	}
+	for i := range backups {
+		backups[i].Available = true
+		if filepath.IsAbs(backups[i].Location) {
+			if _, err := os.Stat(backups[i].Location); err != nil {
+				backups[i].Available = false
+			}
+		}
+	}
+	sort.Sort(backupsByStart(backups))
+	return backups, nil
+}
+
+type backupsByStart []backup.Backup
+
+func (b backupsByStart) Len() int           { return len(b) }
+func (b backupsByStart) Less(i, j int) bool { return b[i].StartedAt.After(b[j].StartedAt) }
+func (b backupsByStart) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
@@ -0,0 +1,85 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backup"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_AddBackup(c *C) {
+	ft.backupStore.On("Put", ft.ctx, mock.AnythingOfType("*backup.Backup")).Return(nil)
+
+	info := &dfs.BackupInfo{
+		Templates:     []servicetemplate.ServiceTemplate{{Name: "tmpl"}},
+		BaseImages:    []string{"base:latest"},
+		Pools:         []pool.ResourcePool{{ID: "default"}},
+		Snapshots:     []string{"snap1"},
+		BackupVersion: 1,
+	}
+	err := ft.Facade.AddBackup(ft.ctx, backup.Backup{ID: "backup.tgz", Location: "/tmp/backup.tgz"}, info)
+	c.Assert(err, IsNil)
+	entry := ft.backupStore.Calls[0].Arguments.Get(1).(*backup.Backup)
+	c.Assert(entry.ID, Equals, "backup.tgz")
+	c.Assert(entry.Templates, DeepEquals, []string{"tmpl"})
+	c.Assert(entry.BaseImages, DeepEquals, []string{"base:latest"})
+	c.Assert(entry.Pools, DeepEquals, []string{"default"})
+	c.Assert(entry.Snapshots, DeepEquals, []string{"snap1"})
+	c.Assert(entry.BackupVersion, Equals, 1)
+}
+
+func (ft *FacadeUnitTest) Test_AddBackup_StoreFails(c *C) {
+	expected := errors.New("put failed")
+	ft.backupStore.On("Put", ft.ctx, mock.AnythingOfType("*backup.Backup")).Return(expected)
+
+	err := ft.Facade.AddBackup(ft.ctx, backup.Backup{ID: "backup.tgz", Location: "/tmp/backup.tgz"}, nil)
+	c.Assert(err, Equals, expected)
+}
+
+func (ft *FacadeUnitTest) Test_GetBackups(c *C) {
+	tmpdir, err := ioutil.TempDir("", "facade-backup-")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(tmpdir)
+	existing := filepath.Join(tmpdir, "exists.tgz")
+	c.Assert(ioutil.WriteFile(existing, []byte("data"), 0644), IsNil)
+
+	now := time.Now()
+	ft.backupStore.On("GetBackups", ft.ctx).Return([]backup.Backup{
+		{ID: "old", Location: existing, StartedAt: now.Add(-time.Hour)},
+		{ID: "missing", Location: filepath.Join(tmpdir, "missing.tgz"), StartedAt: now},
+		{ID: "remote", Location: "s3://bucket/remote.tgz", StartedAt: now.Add(-time.Minute)},
+	}, nil)
+
+	backups, err := ft.Facade.GetBackups(ft.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(backups, HasLen, 3)
+	c.Assert(backups[0].ID, Equals, "missing")
+	c.Assert(backups[0].Available, Equals, false)
+	c.Assert(backups[1].ID, Equals, "remote")
+	c.Assert(backups[1].Available, Equals, true)
+	c.Assert(backups[2].ID, Equals, "old")
+	c.Assert(backups[2].Available, Equals, true)
+}
@@ -0,0 +1,293 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+const (
+	// ChaosCriticalTag marks a service whose instances are never killed by
+	// chaos mode
+	ChaosCriticalTag = "critical"
+
+	// MinChaosInterval is the shortest interval allowed between kills
+	MinChaosInterval = 10 * time.Second
+
+	// maxChaosKills is the number of kills kept in the chaos history of a pool
+	maxChaosKills = 100
+
+	chaosPollInterval    = 5 * time.Second
+	chaosRecoveryTimeout = 30 * time.Minute
+)
+
+var (
+	// ErrChaosNotAllowed is returned when chaos mode is enabled on a master
+	// that was not started with chaos mode allowed
+	ErrChaosNotAllowed = errors.New("chaos mode is not allowed; restart serviced with --allow-chaos")
+
+	// ErrChaosInterval is returned when the chaos interval is too short
+	ErrChaosInterval = fmt.Errorf("chaos interval must be at least %s", MinChaosInterval)
+)
+
+// chaosMonkey periodically kills a random instance in a resource pool
+type chaosMonkey struct {
+	mu       sync.Mutex
+	status   service.ChaosStatus
+	shutdown chan struct{}
+}
+
+// addKill adds a kill to the history, dropping the oldest kills as needed
+func (m *chaosMonkey) addKill(kill service.ChaosKill) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status.Kills = append(m.status.Kills, kill)
+	if n := len(m.status.Kills) - maxChaosKills; n > 0 {
+		m.status.Kills = m.status.Kills[n:]
+	}
+}
+
+// setRecovered records the recovery time of a kill that is still in the
+// history
+func (m *chaosMonkey) setRecovered(kill service.ChaosKill, recoveredAt time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.status.Kills {
+		k := &m.status.Kills[i]
+		if k.ServiceID == kill.ServiceID && k.InstanceID == kill.InstanceID && k.KilledAt.Equal(kill.KilledAt) {
+			k.RecoveredAt = recoveredAt
+			k.Recovery = recoveredAt.Sub(k.KilledAt)
+			return
+		}
+	}
+}
+
+// EnableChaos starts killing a random non-critical service instance in the
+// pool at every interval.  If chaos mode is already enabled on the pool, the
+// interval is updated and the kill history is preserved.
+func (f *Facade) EnableChaos(ctx datastore.Context, poolID string, interval time.Duration) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("EnableChaos"))
+	logger := plog.WithFields(log.Fields{
+		"poolid":   poolID,
+		"interval": interval,
+	})
+
+	if !f.allowChaos {
+		return ErrChaosNotAllowed
+	}
+	if interval < MinChaosInterval {
+		return ErrChaosInterval
+	}
+
+	p, err := f.GetResourcePool(ctx, poolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up resource pool")
+		return err
+	} else if p == nil {
+		return fmt.Errorf("pool %s not found", poolID)
+	}
+
+	f.chaosLock.Lock()
+	defer f.chaosLock.Unlock()
+	if f.chaos == nil {
+		f.chaos = make(map[string]*chaosMonkey)
+	}
+
+	m, ok := f.chaos[poolID]
+	if !ok {
+		m = &chaosMonkey{status: service.ChaosStatus{PoolID: poolID}}
+		f.chaos[poolID] = m
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.status.Enabled {
+		close(m.shutdown)
+	}
+	m.status.Enabled = true
+	m.status.Interval = interval
+	m.status.Since = time.Now()
+	m.shutdown = make(chan struct{})
+	go f.runChaos(m.shutdown, m, m.status.PoolID, interval)
+
+	logger.Warn("Enabled chaos mode")
+	return nil
+}
+
+// DisableChaos stops killing instances in the pool.  The kill history is
+// kept until chaos mode is enabled again.
+func (f *Facade) DisableChaos(ctx datastore.Context, poolID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("DisableChaos"))
+
+	f.chaosLock.Lock()
+	defer f.chaosLock.Unlock()
+	m, ok := f.chaos[poolID]
+	if !ok {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.status.Enabled {
+		close(m.shutdown)
+		m.status.Enabled = false
+		plog.WithField("poolid", poolID).Info("Disabled chaos mode")
+	}
+	return nil
+}
+
+// GetChaosStatus returns the chaos mode settings and kill history of a pool
+func (f *Facade) GetChaosStatus(ctx datastore.Context, poolID string) (*service.ChaosStatus, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetChaosStatus"))
+
+	f.chaosLock.Lock()
+	defer f.chaosLock.Unlock()
+	m, ok := f.chaos[poolID]
+	if !ok {
+		return &service.ChaosStatus{PoolID: poolID}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status := m.status
+	status.Kills = make([]service.ChaosKill, len(m.status.Kills))
+	copy(status.Kills, m.status.Kills)
+	return &status, nil
+}
+
+// runChaos kills an instance at every interval until chaos mode is disabled
+func (f *Facade) runChaos(shutdown <-chan struct{}, m *chaosMonkey, poolID string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-shutdown:
+			return
+		case <-ticker.C:
+			f.chaosKill(m, poolID)
+		}
+	}
+}
+
+// chaosKill stops a random running instance of a non-critical service in the
+// pool and waits in the background for it to be rescheduled
+func (f *Facade) chaosKill(m *chaosMonkey, poolID string) {
+	ctx := datastore.Get()
+	logger := plog.WithField("poolid", poolID)
+
+	svcs, err := f.GetServicesByPool(ctx, poolID)
+	if err != nil {
+		logger.WithError(err).Warn("Could not look up services for chaos mode")
+		return
+	}
+
+	var candidates []service.ChaosKill
+	for _, svc := range svcs {
+		if service.DesiredState(svc.DesiredState) != service.SVCRun || isChaosCritical(&svc) {
+			continue
+		}
+		states, err := f.zzk.GetServiceStates(poolID, svc.ID)
+		if err != nil {
+			logger.WithError(err).WithField("serviceid", svc.ID).Debug("Could not look up service states")
+			continue
+		}
+		for _, state := range states {
+			if state.DesiredState != service.SVCRun || state.Paused || state.Started.IsZero() {
+				continue
+			}
+			candidates = append(candidates, service.ChaosKill{
+				ServiceID:   svc.ID,
+				ServiceName: svc.Name,
+				InstanceID:  state.InstanceID,
+				HostID:      state.HostID,
+			})
+		}
+	}
+	if len(candidates) == 0 {
+		logger.Debug("No running instances available for chaos mode")
+		return
+	}
+
+	kill := candidates[rand.Intn(len(candidates))]
+	logger = logger.WithFields(log.Fields{
+		"serviceid":   kill.ServiceID,
+		"servicename": kill.ServiceName,
+		"instanceid":  kill.InstanceID,
+		"hostid":      kill.HostID,
+	})
+	kill.KilledAt = time.Now()
+	if err := f.zzk.StopServiceInstance(poolID, kill.ServiceID, kill.InstanceID); err != nil {
+		logger.WithError(err).Warn("Could not kill service instance")
+		return
+	}
+	m.addKill(kill)
+	logger.Warn("Chaos mode killed service instance")
+
+	go f.waitChaosRecovery(m, poolID, kill, logger)
+}
+
+// waitChaosRecovery records when the killed instance is running again
+func (f *Facade) waitChaosRecovery(m *chaosMonkey, poolID string, kill service.ChaosKill, logger *log.Entry) {
+	timeout := time.After(chaosRecoveryTimeout)
+	ticker := time.NewTicker(chaosPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-timeout:
+			logger.Warn("Service instance did not recover from chaos kill")
+			return
+		case <-ticker.C:
+			states, err := f.zzk.GetServiceStates(poolID, kill.ServiceID)
+			if err != nil {
+				logger.WithError(err).Debug("Could not look up service states")
+				continue
+			}
+			if recoveredAt, ok := chaosRecovered(states, kill); ok {
+				m.setRecovered(kill, recoveredAt)
+				logger.WithField("recovery", recoveredAt.Sub(kill.KilledAt)).Info("Service instance recovered from chaos kill")
+				return
+			}
+		}
+	}
+}
+
+// chaosRecovered returns the start time of the instance that replaced the
+// killed instance
+func chaosRecovered(states []zkservice.State, kill service.ChaosKill) (time.Time, bool) {
+	for _, state := range states {
+		if state.InstanceID == kill.InstanceID && state.DesiredState == service.SVCRun && state.Started.After(kill.KilledAt) {
+			return state.Started, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isChaosCritical returns true if the service must not be killed by chaos mode
+func isChaosCritical(svc *service.Service) bool {
+	for _, tag := range svc.Tags {
+		if tag == ChaosCriticalTag {
+			return true
+		}
+	}
+	return false
+}
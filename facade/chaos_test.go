@@ -0,0 +1,76 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) TestEnableChaos_NotAllowed(c *C) {
+	ft.Facade.SetAllowChaos(false)
+	err := ft.Facade.EnableChaos(ft.ctx, "testpool", time.Hour)
+	c.Assert(err, Equals, facade.ErrChaosNotAllowed)
+
+	status, err := ft.Facade.GetChaosStatus(ft.ctx, "testpool")
+	c.Assert(err, IsNil)
+	c.Assert(status.Enabled, Equals, false)
+}
+
+func (ft *FacadeUnitTest) TestEnableChaos_ShortInterval(c *C) {
+	ft.Facade.SetAllowChaos(true)
+	err := ft.Facade.EnableChaos(ft.ctx, "testpool", time.Second)
+	c.Assert(err, Equals, facade.ErrChaosInterval)
+}
+
+func (ft *FacadeUnitTest) TestEnableChaos(c *C) {
+	ft.Facade.SetAllowChaos(true)
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, "testpool").Return([]host.Host{}, nil)
+	ft.poolStore.On("Get", ft.ctx, pool.Key("testpool"), mock.AnythingOfType("*pool.ResourcePool")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*pool.ResourcePool) = pool.ResourcePool{ID: "testpool"}
+		})
+
+	err := ft.Facade.EnableChaos(ft.ctx, "testpool", time.Hour)
+	c.Assert(err, IsNil)
+	status, err := ft.Facade.GetChaosStatus(ft.ctx, "testpool")
+	c.Assert(err, IsNil)
+	c.Assert(status.Enabled, Equals, true)
+	c.Assert(status.Interval, Equals, time.Hour)
+
+	// enabling again updates the interval
+	err = ft.Facade.EnableChaos(ft.ctx, "testpool", 2*time.Hour)
+	c.Assert(err, IsNil)
+	status, err = ft.Facade.GetChaosStatus(ft.ctx, "testpool")
+	c.Assert(err, IsNil)
+	c.Assert(status.Interval, Equals, 2*time.Hour)
+
+	err = ft.Facade.DisableChaos(ft.ctx, "testpool")
+	c.Assert(err, IsNil)
+	status, err = ft.Facade.GetChaosStatus(ft.ctx, "testpool")
+	c.Assert(err, IsNil)
+	c.Assert(status.Enabled, Equals, false)
+
+	// disabling twice is harmless
+	err = ft.Facade.DisableChaos(ft.ctx, "testpool")
+	c.Assert(err, IsNil)
+}
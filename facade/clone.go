@@ -0,0 +1,164 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/utils"
+)
+
+var (
+	ErrCloneNotTenant        = errors.New("facade: only tenant services can be cloned into a new deployment")
+	ErrCloneDeploymentExists = errors.New("facade: deployment ID is already in use")
+	ErrCloneSnapshotTenant   = errors.New("facade: snapshot does not belong to the tenant being cloned")
+)
+
+// CloneDeployment copies a tenant and all of its services, including their
+// configuration overrides, into a pool under a new deployment ID.  The clone
+// is stopped, its public endpoints are disabled unless requested otherwise,
+// and it gets a fresh volume that is optionally seeded with the data of a
+// snapshot of the original tenant.  Returns the tenant ID of the clone.
+func (f *Facade) CloneDeployment(ctx datastore.Context, request dao.DeploymentCloneRequest) (string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CloneDeployment"))
+
+	tenant, err := f.serviceStore.Get(ctx, request.TenantID)
+	if err != nil {
+		plog.Errorf("Could not get tenant %s: %s", request.TenantID, err)
+		return "", err
+	}
+	if tenant.ParentServiceID != "" {
+		plog.Errorf("Could not clone service %s (%s): %s", tenant.Name, tenant.ID, ErrCloneNotTenant)
+		return "", ErrCloneNotTenant
+	}
+
+	if request.DeploymentID == "" {
+		return "", fmt.Errorf("deployment ID is required")
+	}
+	if svcs, err := f.serviceStore.GetServicesByDeployment(ctx, request.DeploymentID); err != nil {
+		plog.Errorf("Could not validate deployment ID %s: %s", request.DeploymentID, err)
+		return "", err
+	} else if len(svcs) > 0 {
+		plog.Errorf("Could not clone tenant %s to deployment %s: %s", request.TenantID, request.DeploymentID, ErrCloneDeploymentExists)
+		return "", ErrCloneDeploymentExists
+	}
+
+	if pool, err := f.GetResourcePool(ctx, request.PoolID); err != nil {
+		plog.Errorf("Could not look up resource pool %s: %s", request.PoolID, err)
+		return "", err
+	} else if pool == nil {
+		return "", fmt.Errorf("poolid %s not found", request.PoolID)
+	}
+
+	if request.SnapshotID != "" {
+		info, err := f.dfs.Info(request.SnapshotID)
+		if err != nil {
+			plog.Errorf("Could not get info for snapshot %s: %s", request.SnapshotID, err)
+			return "", err
+		}
+		if info.TenantID != request.TenantID {
+			plog.Errorf("Could not seed clone of tenant %s from snapshot %s: %s", request.TenantID, request.SnapshotID, ErrCloneSnapshotTenant)
+			return "", ErrCloneSnapshotTenant
+		}
+	}
+
+	// services are walked children first, so they are added in reverse to
+	// make sure that every parent exists before its children.
+	var svcs []service.Service
+	if err := f.walkServices(ctx, request.TenantID, true, func(svc *service.Service) error {
+		if err := f.fillServiceConfigs(ctx, svc); err != nil {
+			return err
+		}
+		svcs = append(svcs, *svc)
+		return nil
+	}, "CloneDeployment"); err != nil {
+		plog.Errorf("Could not load the services of tenant %s: %s", request.TenantID, err)
+		return "", err
+	}
+
+	ids := make(map[string]string)
+	for _, svc := range svcs {
+		if ids[svc.ID], err = utils.NewUUID36(); err != nil {
+			return "", err
+		}
+	}
+	tenantID := ids[request.TenantID]
+
+	images := make(map[string]string)
+	for i := len(svcs) - 1; i >= 0; i-- {
+		svc := cloneDeploymentService(svcs[i], ids, request)
+		if svc.ImageID != "" {
+			image, ok := images[svc.ImageID]
+			if !ok {
+				if image, err = f.dfs.CopyImage(svc.ImageID, tenantID, ""); err != nil {
+					plog.Errorf("Could not copy image %s to tenant %s: %s", svc.ImageID, tenantID, err)
+					return "", err
+				}
+				images[svc.ImageID] = image
+			}
+			svc.ImageID = image
+		}
+		if err := f.AddService(ctx, svc); err != nil {
+			plog.Errorf("Could not add clone %s of service %s (%s): %s", svc.ID, svc.Name, svcs[i].ID, err)
+			return "", err
+		}
+	}
+
+	if err := f.dfs.Create(tenantID); err != nil {
+		plog.Errorf("Could not initialize volume for tenant %s: %s", tenantID, err)
+		return "", err
+	}
+	if request.SnapshotID != "" {
+		if err := f.dfs.Seed(request.SnapshotID, tenantID); err != nil {
+			plog.Errorf("Could not seed volume for tenant %s from snapshot %s: %s", tenantID, request.SnapshotID, err)
+			return "", err
+		}
+	}
+	plog.Infof("Cloned tenant %s to %s in pool %s as deployment %s", request.TenantID, tenantID, request.PoolID, request.DeploymentID)
+	return tenantID, nil
+}
+
+// cloneDeploymentService returns a stopped copy of a service for a cloned
+// deployment, with its ids remapped.
+func cloneDeploymentService(svc service.Service, ids map[string]string, request dao.DeploymentCloneRequest) service.Service {
+	svc.ID = ids[svc.ID]
+	if svc.ParentServiceID != "" {
+		svc.ParentServiceID = ids[svc.ParentServiceID]
+	}
+	svc.PoolID = request.PoolID
+	svc.DeploymentID = request.DeploymentID
+	svc.DesiredState = int(service.SVCStop)
+	svc.EmergencyShutdown = false
+	svc.DatabaseVersion = 0
+	// hosts of the original pool are not available to the clone
+	svc.Placement = servicedefinition.Placement{}
+	for i := range svc.Endpoints {
+		ep := &svc.Endpoints[i]
+		ep.RemoveAssignment()
+		if !request.EnablePublicEndpoints {
+			for j := range ep.VHostList {
+				ep.VHostList[j].Enabled = false
+			}
+			for j := range ep.PortList {
+				ep.PortList[j].Enabled = false
+			}
+		}
+	}
+	return svc
+}
@@ -0,0 +1,72 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/volume"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_CloneDeployment_NotTenant(c *C) {
+	ft.serviceStore.On("Get", ft.ctx, "child").Return(&service.Service{ID: "child", ParentServiceID: "tenant"}, nil)
+
+	request := dao.DeploymentCloneRequest{TenantID: "child", PoolID: "staging", DeploymentID: "staging"}
+	_, err := ft.Facade.CloneDeployment(ft.ctx, request)
+	c.Assert(err, Equals, facade.ErrCloneNotTenant)
+}
+
+func (ft *FacadeUnitTest) Test_CloneDeployment_DeploymentExists(c *C) {
+	ft.serviceStore.On("Get", ft.ctx, "tenant").Return(&service.Service{ID: "tenant", DeploymentID: "production"}, nil)
+	ft.serviceStore.On("GetServicesByDeployment", ft.ctx, "production").Return([]service.Service{{ID: "tenant"}}, nil)
+
+	request := dao.DeploymentCloneRequest{TenantID: "tenant", PoolID: "staging", DeploymentID: "production"}
+	_, err := ft.Facade.CloneDeployment(ft.ctx, request)
+	c.Assert(err, Equals, facade.ErrCloneDeploymentExists)
+}
+
+func (ft *FacadeUnitTest) Test_CloneDeployment_PoolNotFound(c *C) {
+	ft.serviceStore.On("Get", ft.ctx, "tenant").Return(&service.Service{ID: "tenant", DeploymentID: "production"}, nil)
+	ft.serviceStore.On("GetServicesByDeployment", ft.ctx, "staging").Return([]service.Service{}, nil)
+	ft.poolStore.On("Get", ft.ctx, pool.Key("missing"), mock.AnythingOfType("*pool.ResourcePool")).Return(datastore.ErrNoSuchEntity{})
+
+	request := dao.DeploymentCloneRequest{TenantID: "tenant", PoolID: "missing", DeploymentID: "staging"}
+	_, err := ft.Facade.CloneDeployment(ft.ctx, request)
+	c.Assert(err, ErrorMatches, "poolid missing not found")
+}
+
+func (ft *FacadeUnitTest) Test_CloneDeployment_SnapshotOfOtherTenant(c *C) {
+	ft.serviceStore.On("Get", ft.ctx, "tenant").Return(&service.Service{ID: "tenant", DeploymentID: "production"}, nil)
+	ft.serviceStore.On("GetServicesByDeployment", ft.ctx, "staging").Return([]service.Service{}, nil)
+	ft.poolStore.On("Get", ft.ctx, pool.Key("staging"), mock.AnythingOfType("*pool.ResourcePool")).Return(nil).Run(
+		func(args mock.Arguments) {
+			args.Get(2).(*pool.ResourcePool).ID = "staging"
+		})
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, "staging").Return(nil, nil)
+	info := &dfs.SnapshotInfo{SnapshotInfo: &volume.SnapshotInfo{Name: "other_LABEL", TenantID: "other"}}
+	ft.dfs.On("Info", "other_LABEL").Return(info, nil)
+
+	request := dao.DeploymentCloneRequest{TenantID: "tenant", PoolID: "staging", DeploymentID: "staging", SnapshotID: "other_LABEL"}
+	_, err := ft.Facade.CloneDeployment(ft.ctx, request)
+	c.Assert(err, Equals, facade.ErrCloneSnapshotTenant)
+	ft.serviceStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+	ft.dfs.AssertNotCalled(c, "Create", mock.Anything)
+}
@@ -0,0 +1,101 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/utils"
+)
+
+// ErrRemoteServiceInstance is returned when committing a service instance that
+// is not running on the master's host
+var ErrRemoteServiceInstance = errors.New("facade: service instance is not running on the master's host")
+
+// CommitServiceInstance commits the container of a service instance to the
+// docker registry and takes a snapshot of the tenant.  The instance must be
+// running on the master's host.  user is recorded as the user that requested
+// the snapshot.  Returns the id of the snapshot.
+func (f *Facade) CommitServiceInstance(ctx datastore.Context, serviceID string, instanceID int, message, user string, snapshotSpacePercent int) (string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CommitServiceInstance"))
+	logger := plog.WithFields(log.Fields{
+		"serviceid":  serviceID,
+		"instanceid": instanceID,
+	})
+
+	if err := f.checkDFSFrozen(); err != nil {
+		return "", err
+	}
+	if err := f.DFSLock(ctx).LockWithTimeout("commit service instance", userLockTimeout); err != nil {
+		logger.WithError(err).Debug("Could not lock the dfs")
+		return "", err
+	}
+	defer f.DFSLock(ctx).Unlock()
+
+	location, err := f.LocateServiceInstance(ctx, serviceID, instanceID)
+	if err != nil {
+		return "", err
+	}
+	logger = logger.WithFields(log.Fields{
+		"hostid":      location.HostID,
+		"containerid": location.ContainerID,
+	})
+
+	masterID, err := utils.HostID()
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up the id of the master's host")
+		return "", err
+	} else if location.HostID != masterID {
+		return "", ErrRemoteServiceInstance
+	}
+
+	// snapshot the tenant first if the commit needs to be verified
+	reason := fmt.Sprintf("commit of service %s instance %d", serviceID, instanceID)
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up tenant")
+		return "", err
+	}
+	priorID, verifyIDs, err := f.prepareRollback(ctx, tenantID, reason, snapshotSpacePercent, true)
+	if err != nil {
+		logger.WithError(err).Debug("Could not snapshot tenant before commit")
+		return "", err
+	}
+
+	if tenantID, err = f.dfs.CommitInstance(location.ContainerID); err != nil {
+		logger.WithError(err).Debug("Could not commit container")
+		return "", err
+	}
+	snapshotID, err := f.Snapshot(ctx, tenantID, message, []string{}, snapshotSpacePercent, dfs.SnapshotProvenance{User: user, Source: dfs.SnapshotSourceCommit})
+	if err != nil {
+		logger.WithError(err).WithField("tenantid", tenantID).Debug("Could not snapshot tenant")
+		return "", err
+	}
+
+	// record the change in the audit trail
+	logger.WithFields(log.Fields{
+		"tenantid":   tenantID,
+		"snapshotid": snapshotID,
+		"message":    message,
+		"user":       user,
+	}).Info("Committed service instance")
+	if priorID != "" {
+		go f.verifyChange(priorID, reason, verifyIDs)
+	}
+	return snapshotID, nil
+}
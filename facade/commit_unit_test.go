@@ -0,0 +1,65 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/coordinator/storage"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/utils"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupCommitServiceInstance(hostID string) {
+	ft.setupMockDFSLocking()
+	ft.zzk.On("GetDFSFreezeStatus").Return(&storage.FreezeStatus{}, nil)
+	svc := service.Service{ID: "serviceID", PoolID: "default"}
+	ft.serviceStore.On("Get", ft.ctx, "serviceID").Return(&svc, nil)
+	state := &zkservice.State{HostID: hostID, ServiceID: "serviceID", InstanceID: 1}
+	state.ContainerID = "containerID"
+	ft.zzk.On("GetServiceState", "default", "serviceID", 1).Return(state, nil)
+}
+
+func (ft *FacadeUnitTest) Test_CommitServiceInstance_RemoteHost(c *C) {
+	ft.setupCommitServiceInstance("remotehost")
+
+	_, err := ft.Facade.CommitServiceInstance(ft.ctx, "serviceID", 1, "message", "zenoss", 0)
+	c.Assert(err, Equals, facade.ErrRemoteServiceInstance)
+	ft.dfs.AssertNotCalled(c, "CommitInstance", "containerID")
+}
+
+func (ft *FacadeUnitTest) Test_CommitServiceInstance_CommitFails(c *C) {
+	hostID, err := utils.HostID()
+	c.Assert(err, IsNil)
+	ft.setupCommitServiceInstance(hostID)
+	expected := errors.New("commit failed")
+	ft.dfs.On("CommitInstance", "containerID").Return("", expected)
+
+	_, err = ft.Facade.CommitServiceInstance(ft.ctx, "serviceID", 1, "message", "zenoss", 0)
+	c.Assert(err, Equals, expected)
+	ft.dfs.AssertNotCalled(c, "Snapshot")
+}
+
+func (ft *FacadeUnitTest) Test_CommitServiceInstance_Frozen(c *C) {
+	ft.zzk.On("GetDFSFreezeStatus").Return(&storage.FreezeStatus{Frozen: true, Reason: "maintenance"}, nil)
+
+	_, err := ft.Facade.CommitServiceInstance(ft.ctx, "serviceID", 1, "message", "zenoss", 0)
+	c.Assert(err, Equals, storage.ErrDFSFrozen)
+	ft.dfs.AssertNotCalled(c, "CommitInstance", "containerID")
+}
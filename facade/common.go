@@ -17,7 +17,6 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/datastore"
-	"github.com/zenoss/glog"
 )
 
 const (
@@ -44,7 +43,7 @@ type afterEvent string
 
 // delete common code for removing an entity and publishes events
 func (f *Facade) delete(ctx datastore.Context, ds datastore.EntityStore, key datastore.Key, be beforeEvent, ae afterEvent) error {
-	glog.V(2).Infof("Facade.delete: %s:%s", key.Kind(), key.ID())
+	plog.Debugf("Facade.delete: %s:%s", key.Kind(), key.ID())
 	ec := newEventCtx()
 	err := f.beforeEvent(be, ec, key.ID())
 	if err == nil {
@@ -0,0 +1,165 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// CheckConsistency compares the services and hosts in the datastore with
+// their scheduling state in zookeeper.  If repair is set, the datastore is
+// treated as the source of truth: orphaned service and host nodes are
+// removed, desired states are rewritten, and missing hosts are added.
+func (f *Facade) CheckConsistency(ctx datastore.Context, repair bool) (*zkservice.ConsistencyReport, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CheckConsistency"))
+
+	svcs, err := f.serviceStore.GetServices(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up services")
+		return nil, err
+	}
+	hosts, err := f.hostStore.GetN(ctx, 10000)
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up hosts")
+		return nil, err
+	}
+	pools, err := f.zzk.GetPoolScheduling()
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up the scheduling state of the resource pools")
+		return nil, err
+	}
+
+	report := compareScheduling(svcs, hosts, pools)
+	if !repair || report.Consistent() {
+		return report, nil
+	}
+
+	svcmap := make(map[string]service.Service)
+	for _, svc := range svcs {
+		svcmap[svc.ID] = svc
+	}
+	hostmap := make(map[string]host.Host)
+	for _, h := range hosts {
+		hostmap[h.ID] = h
+	}
+
+	for _, s := range report.OrphanedServices {
+		if err := f.zzk.RemoveService(s.PoolID, s.ServiceID); err != nil {
+			plog.WithFields(log.Fields{
+				"serviceid": s.ServiceID,
+				"poolid":    s.PoolID,
+			}).WithError(err).Error("Could not remove orphaned service from zookeeper")
+			return report, err
+		}
+	}
+	for _, s := range report.DesiredStateMismatches {
+		svc := svcmap[s.ServiceID]
+		if err := f.syncScheduledService(ctx, &svc); err != nil {
+			plog.WithFields(log.Fields{
+				"serviceid": s.ServiceID,
+				"poolid":    s.PoolID,
+			}).WithError(err).Error("Could not update desired state of service in zookeeper")
+			return report, err
+		}
+	}
+	for _, h := range report.DatastoreOnlyHosts {
+		hst := hostmap[h.HostID]
+		if err := f.zzk.AddHost(&hst); err != nil {
+			plog.WithFields(log.Fields{
+				"hostid": h.HostID,
+				"poolid": h.PoolID,
+			}).WithError(err).Error("Could not add host to zookeeper")
+			return report, err
+		}
+	}
+	for _, h := range report.ZKOnlyHosts {
+		if err := f.zzk.RemoveHost(&host.Host{ID: h.HostID, PoolID: h.PoolID}); err != nil {
+			plog.WithFields(log.Fields{
+				"hostid": h.HostID,
+				"poolid": h.PoolID,
+			}).WithError(err).Error("Could not remove orphaned host from zookeeper")
+			return report, err
+		}
+	}
+	report.Repaired = true
+	plog.Info("Repaired differences between the datastore and zookeeper")
+	return report, nil
+}
+
+// syncScheduledService writes the service as it is in the datastore to
+// zookeeper
+func (f *Facade) syncScheduledService(ctx datastore.Context, svc *service.Service) error {
+	tenantID, err := f.GetTenantID(ctx, svc.ID)
+	if err != nil {
+		return err
+	}
+	if err := f.fillServiceAddr(ctx, svc); err != nil {
+		return err
+	}
+	return f.zzk.UpdateService(ctx, tenantID, svc, false, false)
+}
+
+// compareScheduling returns the differences between the services and hosts
+// in the datastore and the scheduling state of the resource pools
+func compareScheduling(svcs []service.Service, hosts []host.Host, pools []zkservice.PoolScheduling) *zkservice.ConsistencyReport {
+	report := &zkservice.ConsistencyReport{}
+
+	svcmap := make(map[string]service.Service)
+	for _, svc := range svcs {
+		svcmap[svc.ID] = svc
+	}
+	hostmap := make(map[string]host.Host)
+	for _, h := range hosts {
+		hostmap[h.ID] = h
+	}
+
+	zkhosts := make(map[string]struct{})
+	for _, p := range pools {
+		for _, node := range p.Services {
+			s := zkservice.ServiceInconsistency{
+				ServiceID:      node.ID,
+				Name:           node.Name,
+				PoolID:         p.PoolID,
+				ZKDesiredState: node.DesiredState,
+			}
+			svc, ok := svcmap[node.ID]
+			if !ok || svc.PoolID != p.PoolID {
+				report.OrphanedServices = append(report.OrphanedServices, s)
+				continue
+			}
+			s.Name = svc.Name
+			s.DesiredState = svc.DesiredState
+			if svc.DesiredState != node.DesiredState {
+				report.DesiredStateMismatches = append(report.DesiredStateMismatches, s)
+			}
+		}
+		for _, hostID := range p.HostIDs {
+			if h, ok := hostmap[hostID]; ok && h.PoolID == p.PoolID {
+				zkhosts[hostID] = struct{}{}
+				continue
+			}
+			report.ZKOnlyHosts = append(report.ZKOnlyHosts, zkservice.HostInconsistency{HostID: hostID, PoolID: p.PoolID})
+		}
+	}
+	for _, h := range hosts {
+		if _, ok := zkhosts[h.ID]; !ok {
+			report.DatastoreOnlyHosts = append(report.DatastoreOnlyHosts, zkservice.HostInconsistency{HostID: h.ID, PoolID: h.PoolID})
+		}
+	}
+	return report
+}
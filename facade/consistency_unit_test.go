@@ -0,0 +1,108 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupConsistency() {
+	svcs := []service.Service{
+		{ID: "tenant", Name: "tenant", PoolID: "default", DesiredState: int(service.SVCRun)},
+		{ID: "web", Name: "web", PoolID: "default", ParentServiceID: "tenant", DesiredState: int(service.SVCStop)},
+	}
+	ft.serviceStore.On("GetServices", ft.ctx).Return(svcs, nil)
+	for i := range svcs {
+		svc := svcs[i]
+		ft.serviceStore.On("Get", ft.ctx, svc.ID).Return(&svc, nil)
+	}
+	ft.configStore.On("GetConfigFiles", ft.ctx, "tenant", mock.AnythingOfType("string")).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+	ft.hostStore.On("GetN", ft.ctx, uint64(10000)).Return([]host.Host{
+		{ID: "host1", PoolID: "default"},
+		{ID: "host2", PoolID: "default"},
+	}, nil)
+	ft.zzk.On("GetPoolScheduling").Return([]zkservice.PoolScheduling{
+		{
+			PoolID: "default",
+			Services: []zkservice.ServiceNode{
+				{ID: "tenant", Name: "tenant", DesiredState: int(service.SVCRun)},
+				{ID: "web", Name: "web", DesiredState: int(service.SVCRun)},
+				{ID: "deleted", Name: "deleted", DesiredState: int(service.SVCRun)},
+			},
+			HostIDs: []string{"host1", "host3"},
+		},
+	}, nil)
+}
+
+func (ft *FacadeUnitTest) Test_CheckConsistency_Consistent(c *C) {
+	ft.serviceStore.On("GetServices", ft.ctx).Return([]service.Service{
+		{ID: "tenant", PoolID: "default", DesiredState: int(service.SVCRun)},
+	}, nil)
+	ft.hostStore.On("GetN", ft.ctx, uint64(10000)).Return([]host.Host{{ID: "host1", PoolID: "default"}}, nil)
+	ft.zzk.On("GetPoolScheduling").Return([]zkservice.PoolScheduling{
+		{
+			PoolID:   "default",
+			Services: []zkservice.ServiceNode{{ID: "tenant", DesiredState: int(service.SVCRun)}},
+			HostIDs:  []string{"host1"},
+		},
+	}, nil)
+
+	report, err := ft.Facade.CheckConsistency(ft.ctx, true)
+	c.Assert(err, IsNil)
+	c.Assert(report.Consistent(), Equals, true)
+	c.Assert(report.Repaired, Equals, false)
+	ft.zzk.AssertNotCalled(c, "RemoveService", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_CheckConsistency_Report(c *C) {
+	ft.setupConsistency()
+
+	report, err := ft.Facade.CheckConsistency(ft.ctx, false)
+	c.Assert(err, IsNil)
+	c.Assert(report.Consistent(), Equals, false)
+	c.Assert(report.Repaired, Equals, false)
+	c.Assert(report.OrphanedServices, DeepEquals, []zkservice.ServiceInconsistency{
+		{ServiceID: "deleted", Name: "deleted", PoolID: "default", ZKDesiredState: int(service.SVCRun)},
+	})
+	c.Assert(report.DesiredStateMismatches, DeepEquals, []zkservice.ServiceInconsistency{
+		{ServiceID: "web", Name: "web", PoolID: "default", DesiredState: int(service.SVCStop), ZKDesiredState: int(service.SVCRun)},
+	})
+	c.Assert(report.DatastoreOnlyHosts, DeepEquals, []zkservice.HostInconsistency{{HostID: "host2", PoolID: "default"}})
+	c.Assert(report.ZKOnlyHosts, DeepEquals, []zkservice.HostInconsistency{{HostID: "host3", PoolID: "default"}})
+	ft.zzk.AssertNotCalled(c, "RemoveService", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_CheckConsistency_Repair(c *C) {
+	ft.setupConsistency()
+	ft.zzk.On("RemoveService", "default", "deleted").Return(nil)
+	ft.zzk.On("UpdateService", ft.ctx, "tenant", mock.AnythingOfType("*service.Service"), false, false).Return(nil).Run(func(args mock.Arguments) {
+		svc := args.Get(2).(*service.Service)
+		c.Assert(svc.ID, Equals, "web")
+		c.Assert(svc.DesiredState, Equals, int(service.SVCStop))
+	})
+	ft.zzk.On("AddHost", &host.Host{ID: "host2", PoolID: "default"}).Return(nil)
+	ft.zzk.On("RemoveHost", &host.Host{ID: "host3", PoolID: "default"}).Return(nil)
+
+	report, err := ft.Facade.CheckConsistency(ft.ctx, true)
+	c.Assert(err, IsNil)
+	c.Assert(report.Repaired, Equals, true)
+	ft.zzk.AssertExpectations(c)
+}
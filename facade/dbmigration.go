@@ -0,0 +1,152 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/dbmigration"
+)
+
+func init() {
+	// The baseline migration marks the datastore schema that existed before
+	// migrations were tracked; it has nothing to change.
+	dbmigration.Register(dbmigration.Migration{
+		Version:     1,
+		Description: "Baseline datastore schema",
+		Kinds:       []string{"host", "pool", "service", "servicetemplate"},
+	})
+}
+
+// MigrateDatastore applies the registered datastore migrations that have not
+// been applied yet, in version order.  Each migration is recorded as soon as
+// it succeeds, so a failed migration is retried the next time the master
+// starts.  Masters hold a lock in zookeeper while migrating, so a master that
+// starts during a migration waits for it and then finds it applied.
+func (f *Facade) MigrateDatastore(ctx datastore.Context) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("MigrateDatastore"))
+	mu, err := f.zzk.NewDatastoreMigrationLock()
+	if err != nil {
+		plog.Errorf("Could not initialize the datastore migration lock: %s", err)
+		return err
+	}
+	if err := mu.Lock(); err != nil {
+		plog.Errorf("Could not lock the datastore for migration: %s", err)
+		return err
+	}
+	defer mu.Unlock()
+
+	if err := f.DFSLock(ctx).LockWithTimeout("migrate datastore", userLockTimeout); err != nil {
+		plog.Warningf("Cannot migrate datastore: %s", err)
+		return err
+	}
+	defer f.DFSLock(ctx).Unlock()
+
+	records, err := f.getMigrationRecords(ctx)
+	if err != nil {
+		return err
+	}
+	var pending []dbmigration.Migration
+	for _, m := range dbmigration.Migrations() {
+		if _, ok := records[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		plog.Debugf("Datastore is up to date")
+		return nil
+	}
+
+	for i, m := range pending {
+		plog.Infof("Applying datastore migration %d (%d of %d): %s", m.Version, i+1, len(pending), m.Description)
+		start := time.Now()
+		if m.Apply != nil {
+			if err := m.Apply(ctx); err != nil {
+				plog.Errorf("Could not apply datastore migration %d: %s", m.Version, err)
+				return err
+			}
+		}
+		record := &dbmigration.Record{
+			ID:          strconv.Itoa(m.Version),
+			Version:     m.Version,
+			Description: m.Description,
+			AppliedAt:   start.UTC(),
+			Duration:    time.Since(start),
+		}
+		if err := f.migrationStore.Put(ctx, record); err != nil {
+			plog.Errorf("Could not record datastore migration %d: %s", m.Version, err)
+			return err
+		}
+	}
+	plog.Infof("Applied %d datastore migrations", len(pending))
+	return nil
+}
+
+// GetDatastoreMigrations returns the status of each registered datastore
+// migration, along with any applied migrations that are not registered in
+// this version of serviced, in version order.
+func (f *Facade) GetDatastoreMigrations(ctx datastore.Context) ([]dbmigration.Status, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetDatastoreMigrations"))
+	records, err := f.getMigrationRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []dbmigration.Status
+	for _, m := range dbmigration.Migrations() {
+		status := dbmigration.Status{
+			Version:     m.Version,
+			Description: m.Description,
+			Kinds:       m.Kinds,
+		}
+		if record, ok := records[m.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+			delete(records, m.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, record := range records {
+		statuses = append(statuses, dbmigration.Status{
+			Version:     record.Version,
+			Description: record.Description,
+			Applied:     true,
+			AppliedAt:   record.AppliedAt,
+		})
+	}
+	sort.Sort(migrationStatusByVersion(statuses))
+	return statuses, nil
+}
+
+// getMigrationRecords returns the applied migrations by version
+func (f *Facade) getMigrationRecords(ctx datastore.Context) (map[int]dbmigration.Record, error) {
+	records, err := f.migrationStore.GetRecords(ctx)
+	if err != nil {
+		plog.Errorf("Could not get applied datastore migrations: %s", err)
+		return nil, err
+	}
+	result := make(map[int]dbmigration.Record)
+	for _, record := range records {
+		result[record.Version] = record
+	}
+	return result, nil
+}
+
+type migrationStatusByVersion []dbmigration.Status
+
+func (s migrationStatusByVersion) Len() int           { return len(s) }
+func (s migrationStatusByVersion) Less(i, j int) bool { return s[i].Version < s[j].Version }
+func (s migrationStatusByVersion) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,116 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+	"time"
+
+	clientmocks "github.com/control-center/serviced/coordinator/client/mocks"
+	"github.com/control-center/serviced/domain/dbmigration"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupMigrationLock() *clientmocks.Lock {
+	mu := &clientmocks.Lock{}
+	mu.On("Lock").Return(nil)
+	mu.On("Unlock").Return(nil)
+	ft.zzk.On("NewDatastoreMigrationLock").Return(mu, nil)
+	return mu
+}
+
+func (ft *FacadeUnitTest) Test_MigrateDatastore(c *C) {
+	ft.setupMockDFSLocking()
+	mu := ft.setupMigrationLock()
+	ft.migrationStore.On("GetRecords", ft.ctx).Return([]dbmigration.Record{}, nil)
+	ft.migrationStore.On("Put", ft.ctx, mock.AnythingOfType("*dbmigration.Record")).Return(nil)
+
+	err := ft.Facade.MigrateDatastore(ft.ctx)
+	c.Assert(err, IsNil)
+	ft.migrationStore.AssertNumberOfCalls(c, "Put", len(dbmigration.Migrations()))
+	record := ft.migrationStore.Calls[1].Arguments.Get(1).(*dbmigration.Record)
+	c.Assert(record.ID, Equals, "1")
+	c.Assert(record.Version, Equals, 1)
+	c.Assert(record.AppliedAt.IsZero(), Equals, false)
+	mu.AssertExpectations(c)
+}
+
+func (ft *FacadeUnitTest) Test_MigrateDatastore_LockFails(c *C) {
+	expected := errors.New("lock failed")
+	mu := &clientmocks.Lock{}
+	mu.On("Lock").Return(expected)
+	ft.zzk.On("NewDatastoreMigrationLock").Return(mu, nil)
+
+	err := ft.Facade.MigrateDatastore(ft.ctx)
+	c.Assert(err, Equals, expected)
+	ft.migrationStore.AssertNotCalled(c, "GetRecords", ft.ctx)
+	mu.AssertNotCalled(c, "Unlock")
+}
+
+func (ft *FacadeUnitTest) Test_MigrateDatastore_UpToDate(c *C) {
+	ft.setupMockDFSLocking()
+	ft.setupMigrationLock()
+	var records []dbmigration.Record
+	for _, m := range dbmigration.Migrations() {
+		records = append(records, dbmigration.Record{Version: m.Version})
+	}
+	ft.migrationStore.On("GetRecords", ft.ctx).Return(records, nil)
+
+	err := ft.Facade.MigrateDatastore(ft.ctx)
+	c.Assert(err, IsNil)
+	ft.migrationStore.AssertNotCalled(c, "Put", ft.ctx, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_MigrateDatastore_StoreFails(c *C) {
+	ft.setupMockDFSLocking()
+	mu := ft.setupMigrationLock()
+	expected := errors.New("put failed")
+	ft.migrationStore.On("GetRecords", ft.ctx).Return([]dbmigration.Record{}, nil)
+	ft.migrationStore.On("Put", ft.ctx, mock.AnythingOfType("*dbmigration.Record")).Return(expected)
+
+	err := ft.Facade.MigrateDatastore(ft.ctx)
+	c.Assert(err, Equals, expected)
+	ft.migrationStore.AssertNumberOfCalls(c, "Put", 1)
+	mu.AssertCalled(c, "Unlock")
+}
+
+func (ft *FacadeUnitTest) Test_GetDatastoreMigrations(c *C) {
+	appliedAt := time.Date(2016, 5, 4, 10, 30, 0, 0, time.UTC)
+	ft.migrationStore.On("GetRecords", ft.ctx).Return([]dbmigration.Record{
+		{ID: "9999", Version: 9999, Description: "From a newer version", AppliedAt: appliedAt},
+		{ID: "1", Version: 1, Description: "Baseline datastore schema", AppliedAt: appliedAt},
+	}, nil)
+
+	statuses, err := ft.Facade.GetDatastoreMigrations(ft.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, len(dbmigration.Migrations())+1)
+	c.Assert(statuses[0].Version, Equals, 1)
+	c.Assert(statuses[0].Applied, Equals, true)
+	c.Assert(statuses[0].AppliedAt, Equals, appliedAt)
+	last := statuses[len(statuses)-1]
+	c.Assert(last.Version, Equals, 9999)
+	c.Assert(last.Description, Equals, "From a newer version")
+	c.Assert(last.Applied, Equals, true)
+}
+
+func (ft *FacadeUnitTest) Test_GetDatastoreMigrations_StoreFails(c *C) {
+	expected := errors.New("search failed")
+	ft.migrationStore.On("GetRecords", ft.ctx).Return(nil, expected)
+
+	_, err := ft.Facade.GetDatastoreMigrations(ft.ctx)
+	c.Assert(err, Equals, expected)
+}
@@ -0,0 +1,275 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/proxy"
+	"github.com/control-center/serviced/servicedversion"
+	"github.com/control-center/serviced/utils"
+)
+
+var (
+	ErrNoUpgradeVersion = errors.New("facade: no version to upgrade to")
+	ErrNoDelegateClient = errors.New("facade: delegate upgrades are not supported")
+)
+
+// DefaultDelegateTimeout is how long to wait for an upgraded delegate to
+// come back online
+const DefaultDelegateTimeout = 10 * time.Minute
+
+// delegatePollInterval is how often an upgraded delegate is checked
+var delegatePollInterval = 5 * time.Second
+
+// DelegateClient connects to the agents running on delegate hosts
+type DelegateClient interface {
+	UpgradeServiced(address, version string) error
+	GetServicedVersion(address string) (*servicedversion.ServicedVersion, error)
+	ProbeEndpoint(address, target, muxAddress string, timeout time.Duration) error
+	GetMuxStats(address string) (map[string]proxy.ConnectionStats, error)
+	GetPreviousOutput(address, serviceID string, instanceID int) (*service.InstanceOutput, error)
+	GetDiagnostics(address, serviceID string, instanceID int) ([]service.DiagnosticOutput, error)
+	GetContainerLogs(address, dockerID string, since time.Time, tail int) ([]service.LogLine, error)
+}
+
+// UpgradeDelegates upgrades serviced on the delegates of each pool, one host
+// at a time, and halts at the first host that fails to upgrade or does not
+// come back online running the new version.  The host running the master is
+// skipped; it is upgraded with the master.
+func (f *Facade) UpgradeDelegates(ctx datastore.Context, req host.UpgradeRequest) ([]host.UpgradeResult, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("UpgradeDelegates"))
+	logger := plog.WithField("version", req.Version)
+
+	if req.Version == "" {
+		return nil, ErrNoUpgradeVersion
+	} else if f.delegates == nil {
+		return nil, ErrNoDelegateClient
+	}
+	if req.Timeout <= 0 {
+		req.Timeout = DefaultDelegateTimeout
+	}
+
+	poolIDs, err := f.getUpgradePoolIDs(ctx, req.PoolIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	masterID, err := utils.HostID()
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up the id of the master's host")
+		return nil, err
+	}
+
+	results := []host.UpgradeResult{}
+	for _, poolID := range poolIDs {
+		hosts, err := f.hostStore.FindHostsWithPoolID(ctx, poolID)
+		if err != nil {
+			logger.WithError(err).WithField("poolid", poolID).Debug("Could not look up hosts in pool")
+			return nil, err
+		}
+
+		byID := make(map[string]host.Host)
+		hostIDs := make([]string, len(hosts))
+		for i, h := range hosts {
+			byID[h.ID] = h
+			hostIDs[i] = h.ID
+		}
+		sort.Strings(hostIDs)
+
+		for _, hostID := range hostIDs {
+			result := f.upgradeDelegate(ctx, byID[hostID], masterID, req)
+			results = append(results, result)
+			if result.Status == host.UpgradeFailed {
+				logger.WithField("hostid", hostID).Warn("Halting delegate upgrade")
+				return results, nil
+			}
+		}
+	}
+
+	logger.WithField("hosts", len(results)).Info("Upgraded delegates")
+	return results, nil
+}
+
+// getUpgradePoolIDs verifies the pools requested for an upgrade, or returns
+// all of the pools sorted by id if none were requested.
+func (f *Facade) getUpgradePoolIDs(ctx datastore.Context, poolIDs []string) ([]string, error) {
+	if len(poolIDs) > 0 {
+		for _, poolID := range poolIDs {
+			if p, err := f.GetResourcePool(ctx, poolID); err != nil {
+				return nil, err
+			} else if p == nil {
+				return nil, fmt.Errorf("pool %s does not exist", poolID)
+			}
+		}
+		return poolIDs, nil
+	}
+
+	pools, err := f.poolStore.GetResourcePools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	poolIDs = make([]string, len(pools))
+	for i, p := range pools {
+		poolIDs[i] = p.ID
+	}
+	sort.Strings(poolIDs)
+	return poolIDs, nil
+}
+
+// upgradeDelegate upgrades serviced on a single delegate and waits for it to
+// come back online
+func (f *Facade) upgradeDelegate(ctx datastore.Context, h host.Host, masterID string, req host.UpgradeRequest) host.UpgradeResult {
+	logger := plog.WithFields(log.Fields{
+		"hostid":  h.ID,
+		"poolid":  h.PoolID,
+		"version": req.Version,
+	})
+	result := host.UpgradeResult{
+		HostID:          h.ID,
+		PoolID:          h.PoolID,
+		PreviousVersion: h.ServiceD.Version,
+	}
+	failed := func(err error) host.UpgradeResult {
+		logger.WithError(err).Warn("Could not upgrade delegate")
+		result.Status = host.UpgradeFailed
+		result.Message = err.Error()
+		return result
+	}
+
+	if h.ID == masterID {
+		result.Status = host.UpgradeSkipped
+		result.Message = "host runs the master"
+		return result
+	}
+
+	address := fmt.Sprintf("%s:%d", h.IPAddr, h.RPCPort)
+	current, err := f.delegates.GetServicedVersion(address)
+	if err != nil {
+		return failed(fmt.Errorf("could not reach delegate: %s", err))
+	}
+	result.PreviousVersion = current.Version
+	if current.Version == req.Version {
+		result.Version = current.Version
+		result.Status = host.UpgradeSkipped
+		result.Message = "already running " + req.Version
+		return result
+	}
+
+	// only expect a dfs health report after the upgrade if the delegate
+	// reported one before it
+	dfsHealth, err := f.zzk.GetHostDFSHealth(h.PoolID, h.ID)
+	if err != nil {
+		return failed(fmt.Errorf("could not look up the dfs health of the delegate: %s", err))
+	}
+	checkDFS := dfsHealth != nil
+	since := time.Now()
+
+	if req.Drain {
+		logger.Info("Draining delegate")
+		if err := f.zzk.DrainHost(&h, req.Timeout); err != nil {
+			return failed(fmt.Errorf("could not drain delegate: %s", err))
+		}
+	}
+
+	logger.Info("Upgrading delegate")
+	if err := f.delegates.UpgradeServiced(address, req.Version); err != nil {
+		return failed(err)
+	}
+
+	version, err := f.waitForDelegate(h, address, req.Version, req.Timeout, since, checkDFS)
+	if err != nil {
+		return failed(err)
+	}
+	result.Version = version.Version
+
+	// record the release running on the host
+	h.ServiceD.Version = version.Version
+	h.ServiceD.Date = version.Date
+	h.ServiceD.Gitcommit = version.Gitcommit
+	h.ServiceD.Gitbranch = version.Gitbranch
+	h.ServiceD.Buildtag = version.Buildtag
+	h.ServiceD.Release = version.Release
+	h.UpdatedAt = time.Now()
+	if err := f.hostStore.Put(ctx, host.HostKey(h.ID), &h); err != nil {
+		logger.WithError(err).Warn("Could not record the upgraded version of the delegate")
+	}
+
+	logger.Info("Upgraded delegate")
+	result.Status = host.UpgradeUpgraded
+	return result
+}
+
+// waitForDelegate waits for an upgraded delegate to come back online running
+// the expected version and healthy
+func (f *Facade) waitForDelegate(h host.Host, address, version string, timeout time.Duration, since time.Time, checkDFS bool) (*servicedversion.ServicedVersion, error) {
+	deadline := time.Now().Add(timeout)
+	running, unhealthy := "", ""
+	for {
+		if active, err := f.zzk.IsHostActive(h.PoolID, h.ID); err == nil && active {
+			if current, err := f.delegates.GetServicedVersion(address); err == nil {
+				if current.Version == version {
+					if unhealthy = f.delegateHealth(h, since, checkDFS); unhealthy == "" {
+						return current, nil
+					}
+				}
+				running = current.Version
+			}
+		}
+
+		if time.Now().After(deadline) {
+			if unhealthy != "" {
+				return nil, fmt.Errorf("delegate is not healthy after %s: %s", timeout, unhealthy)
+			} else if running != "" {
+				return nil, fmt.Errorf("delegate is still running %s after %s", running, timeout)
+			}
+			return nil, fmt.Errorf("delegate did not come back online within %s", timeout)
+		}
+		wait := deadline.Sub(time.Now())
+		if wait > delegatePollInterval {
+			wait = delegatePollInterval
+		}
+		time.Sleep(wait)
+	}
+}
+
+// delegateHealth returns why an upgraded delegate is not healthy, or an empty
+// string if it is.  Only health reported since the upgrade is considered.
+func (f *Facade) delegateHealth(h host.Host, since time.Time, checkDFS bool) string {
+	if checkDFS {
+		health, err := f.zzk.GetHostDFSHealth(h.PoolID, h.ID)
+		if err != nil {
+			return fmt.Sprintf("could not look up dfs health: %s", err)
+		} else if health == nil || health.Updated.Before(since) {
+			return "dfs health has not been reported since the upgrade"
+		} else if !health.Healthy {
+			return "dfs mounts are unavailable"
+		}
+	}
+
+	health, err := f.zzk.GetHostStorageHealth(h.PoolID, h.ID)
+	if err != nil {
+		return fmt.Sprintf("could not look up storage health: %s", err)
+	} else if health != nil && !health.Updated.Before(since) && health.Level == host.StorageCritical {
+		return "storage usage is critical"
+	}
+	return ""
+}
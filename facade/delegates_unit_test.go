@@ -0,0 +1,203 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/servicedversion"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupDelegateUpgrade(hosts ...host.Host) {
+	pools := []pool.ResourcePool{{ID: "pool1"}}
+	ft.poolStore.On("GetResourcePools", ft.ctx).Return(pools, nil)
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, "pool1").Return(hosts, nil)
+	ft.hostStore.On("Put", ft.ctx, mock.AnythingOfType("*datastore.key"), mock.AnythingOfType("*host.Host")).Return(nil)
+	for _, h := range hosts {
+		ft.zzk.On("IsHostActive", h.PoolID, h.ID).Return(true, nil)
+		ft.zzk.On("GetHostStorageHealth", h.PoolID, h.ID).Return(nil, nil)
+	}
+}
+
+func (ft *FacadeUnitTest) Test_UpgradeDelegates(c *C) {
+	h1 := host.Host{ID: "host1", PoolID: "pool1", IPAddr: "192.168.0.1", RPCPort: 4979}
+	h2 := host.Host{ID: "host2", PoolID: "pool1", IPAddr: "192.168.0.2", RPCPort: 4979}
+	ft.setupDelegateUpgrade(h2, h1)
+	ft.zzk.On("GetHostDFSHealth", "pool1", "host1").Return(nil, nil)
+
+	ft.delegates.On("GetServicedVersion", "192.168.0.1:4979").Return(&servicedversion.ServicedVersion{Version: "1.2.0"}, nil).Once()
+	ft.delegates.On("UpgradeServiced", "192.168.0.1:4979", "1.3.0").Return(nil)
+	ft.delegates.On("GetServicedVersion", "192.168.0.1:4979").Return(&servicedversion.ServicedVersion{Version: "1.3.0", Release: "1"}, nil)
+	ft.delegates.On("GetServicedVersion", "192.168.0.2:4979").Return(&servicedversion.ServicedVersion{Version: "1.3.0"}, nil)
+
+	results, err := ft.Facade.UpgradeDelegates(ft.ctx, host.UpgradeRequest{Version: "1.3.0", Timeout: time.Minute})
+	c.Assert(err, IsNil)
+	c.Assert(results, DeepEquals, []host.UpgradeResult{
+		{HostID: "host1", PoolID: "pool1", PreviousVersion: "1.2.0", Version: "1.3.0", Status: host.UpgradeUpgraded},
+		{HostID: "host2", PoolID: "pool1", PreviousVersion: "1.3.0", Version: "1.3.0", Status: host.UpgradeSkipped, Message: "already running 1.3.0"},
+	})
+	ft.hostStore.AssertCalled(c, "Put", ft.ctx, mock.AnythingOfType("*datastore.key"), mock.MatchedBy(func(h *host.Host) bool {
+		return h.ID == "host1" && h.ServiceD.Version == "1.3.0" && h.ServiceD.Release == "1"
+	}))
+	ft.delegates.AssertNotCalled(c, "UpgradeServiced", "192.168.0.2:4979", "1.3.0")
+}
+
+func (ft *FacadeUnitTest) Test_UpgradeDelegates_Drain(c *C) {
+	h1 := host.Host{ID: "host1", PoolID: "pool1", IPAddr: "192.168.0.1", RPCPort: 4979}
+	ft.setupDelegateUpgrade(h1)
+	ft.zzk.On("GetHostDFSHealth", "pool1", "host1").Return(nil, nil)
+
+	ft.delegates.On("GetServicedVersion", "192.168.0.1:4979").Return(&servicedversion.ServicedVersion{Version: "1.2.0"}, nil).Once()
+	ft.zzk.On("DrainHost", mock.MatchedBy(func(h *host.Host) bool { return h.ID == "host1" }), time.Minute).Return(nil)
+	ft.delegates.On("UpgradeServiced", "192.168.0.1:4979", "1.3.0").Return(nil)
+	ft.delegates.On("GetServicedVersion", "192.168.0.1:4979").Return(&servicedversion.ServicedVersion{Version: "1.3.0"}, nil)
+
+	results, err := ft.Facade.UpgradeDelegates(ft.ctx, host.UpgradeRequest{Version: "1.3.0", Drain: true, Timeout: time.Minute})
+	c.Assert(err, IsNil)
+	c.Assert(results, HasLen, 1)
+	c.Assert(results[0].Status, Equals, host.UpgradeUpgraded)
+	ft.zzk.AssertExpectations(c)
+}
+
+func (ft *FacadeUnitTest) Test_UpgradeDelegates_HaltsOnFailure(c *C) {
+	h1 := host.Host{ID: "host1", PoolID: "pool1", IPAddr: "192.168.0.1", RPCPort: 4979}
+	h2 := host.Host{ID: "host2", PoolID: "pool1", IPAddr: "192.168.0.2", RPCPort: 4979}
+	ft.setupDelegateUpgrade(h1, h2)
+	ft.zzk.On("GetHostDFSHealth", "pool1", "host1").Return(nil, nil)
+
+	ft.delegates.On("GetServicedVersion", "192.168.0.1:4979").Return(&servicedversion.ServicedVersion{Version: "1.2.0"}, nil)
+	ft.delegates.On("UpgradeServiced", "192.168.0.1:4979", "1.3.0").Return(errors.New("package not found"))
+
+	results, err := ft.Facade.UpgradeDelegates(ft.ctx, host.UpgradeRequest{Version: "1.3.0", Timeout: time.Minute})
+	c.Assert(err, IsNil)
+	c.Assert(results, DeepEquals, []host.UpgradeResult{
+		{HostID: "host1", PoolID: "pool1", PreviousVersion: "1.2.0", Status: host.UpgradeFailed, Message: "package not found"},
+	})
+	ft.delegates.AssertNotCalled(c, "GetServicedVersion", "192.168.0.2:4979")
+}
+
+func (ft *FacadeUnitTest) Test_UpgradeDelegates_Unhealthy(c *C) {
+	h1 := host.Host{ID: "host1", PoolID: "pool1", IPAddr: "192.168.0.1", RPCPort: 4979}
+	h2 := host.Host{ID: "host2", PoolID: "pool1", IPAddr: "192.168.0.2", RPCPort: 4979}
+	ft.setupDelegateUpgrade(h1, h2)
+
+	// the delegate comes back on the new version, but its dfs mounts do not
+	ft.zzk.On("GetHostDFSHealth", "pool1", "host1").Return(&host.DFSHealth{Healthy: true, Updated: time.Now().Add(-time.Minute)}, nil).Once()
+	ft.zzk.On("GetHostDFSHealth", "pool1", "host1").Return(&host.DFSHealth{Healthy: false, Updated: time.Now().Add(time.Minute)}, nil)
+	ft.delegates.On("GetServicedVersion", "192.168.0.1:4979").Return(&servicedversion.ServicedVersion{Version: "1.2.0"}, nil).Once()
+	ft.delegates.On("UpgradeServiced", "192.168.0.1:4979", "1.3.0").Return(nil)
+	ft.delegates.On("GetServicedVersion", "192.168.0.1:4979").Return(&servicedversion.ServicedVersion{Version: "1.3.0"}, nil)
+
+	results, err := ft.Facade.UpgradeDelegates(ft.ctx, host.UpgradeRequest{Version: "1.3.0", Timeout: 10 * time.Millisecond})
+	c.Assert(err, IsNil)
+	c.Assert(results, DeepEquals, []host.UpgradeResult{
+		{HostID: "host1", PoolID: "pool1", PreviousVersion: "1.2.0", Status: host.UpgradeFailed, Message: "delegate is not healthy after 10ms: dfs mounts are unavailable"},
+	})
+	ft.delegates.AssertNotCalled(c, "GetServicedVersion", "192.168.0.2:4979")
+}
+
+func (ft *FacadeUnitTest) Test_UpgradeDelegates_NoVersion(c *C) {
+	_, err := ft.Facade.UpgradeDelegates(ft.ctx, host.UpgradeRequest{})
+	c.Assert(err, Equals, facade.ErrNoUpgradeVersion)
+}
+
+func (ft *FacadeUnitTest) Test_GetPreviousInstanceOutput(c *C) {
+	h1 := host.Host{ID: "host1", PoolID: "pool1", IPAddr: "192.168.0.1", RPCPort: 4979}
+	h2 := host.Host{ID: "host2", PoolID: "pool1", IPAddr: "192.168.0.2", RPCPort: 4979}
+	h3 := host.Host{ID: "host3", PoolID: "pool1", IPAddr: "192.168.0.3", RPCPort: 4979}
+	ft.serviceStore.On("Get", ft.ctx, "svc1").Return(&service.Service{ID: "svc1", PoolID: "pool1"}, nil)
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, "pool1").Return([]host.Host{h1, h2, h3}, nil)
+
+	exited := time.Now()
+	ft.delegates.On("GetPreviousOutput", "192.168.0.1:4979", "svc1", 0).Return(&service.InstanceOutput{ServiceID: "svc1", Exited: exited.Add(-time.Hour), Output: []byte("old")}, nil)
+	ft.delegates.On("GetPreviousOutput", "192.168.0.2:4979", "svc1", 0).Return(&service.InstanceOutput{ServiceID: "svc1", Exited: exited, Output: []byte("new")}, nil)
+	ft.delegates.On("GetPreviousOutput", "192.168.0.3:4979", "svc1", 0).Return(nil, errors.New("connection refused"))
+	ft.delegates.On("GetPreviousOutput", mock.AnythingOfType("string"), "svc1", 1).Return(nil, nil)
+
+	output, err := ft.Facade.GetPreviousInstanceOutput(ft.ctx, "svc1", 0)
+	c.Assert(err, IsNil)
+	c.Assert(output.HostID, Equals, "host2")
+	c.Assert(string(output.Output), Equals, "new")
+
+	_, err = ft.Facade.GetPreviousInstanceOutput(ft.ctx, "svc1", 1)
+	c.Assert(err, Equals, facade.ErrNoPreviousOutput)
+}
+
+func (ft *FacadeUnitTest) Test_GetInstanceDiagnostics(c *C) {
+	h1 := host.Host{ID: "host1", PoolID: "pool1", IPAddr: "192.168.0.1", RPCPort: 4979}
+	h2 := host.Host{ID: "host2", PoolID: "pool1", IPAddr: "192.168.0.2", RPCPort: 4979}
+	ft.serviceStore.On("Get", ft.ctx, "svc1").Return(&service.Service{ID: "svc1", PoolID: "pool1"}, nil)
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, "pool1").Return([]host.Host{h1, h2}, nil)
+	ft.delegates.On("GetDiagnostics", "192.168.0.1:4979", "svc1", 0).Return([]service.DiagnosticOutput{
+		{ServiceID: "svc1", Action: "diag-lsof", Output: []byte("files")},
+		{ServiceID: "svc1", Action: "diag-netstat", Output: []byte("sockets")},
+	}, nil)
+	ft.delegates.On("GetDiagnostics", "192.168.0.2:4979", "svc1", 0).Return(nil, errors.New("connection refused"))
+
+	results, err := ft.Facade.GetInstanceDiagnostics(ft.ctx, "svc1", 0)
+	c.Assert(err, IsNil)
+	c.Assert(results, HasLen, 2)
+	c.Assert(results[0].HostID, Equals, "host1")
+	c.Assert(results[1].Action, Equals, "diag-netstat")
+}
+
+func (ft *FacadeUnitTest) Test_GetInstanceLogs(c *C) {
+	ft.serviceStore.On("Get", ft.ctx, "svc1").Return(&service.Service{ID: "svc1", PoolID: "pool1"}, nil)
+	state := &zkservice.State{HostID: "host1", ServiceID: "svc1", InstanceID: 0}
+	state.ContainerID = "containerID"
+	ft.zzk.On("GetServiceState", "pool1", "svc1", 0).Return(state, nil)
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("host1"), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = host.Host{ID: "host1", IPAddr: "192.168.0.1", RPCPort: 4979}
+		})
+
+	since := time.Now()
+	lines := []service.LogLine{{Time: since.Add(time.Second), Text: "starting zope"}}
+	ft.delegates.On("GetContainerLogs", "192.168.0.1:4979", "containerID", since, 10).Return(lines, nil)
+
+	result, err := ft.Facade.GetInstanceLogs(ft.ctx, "svc1", 0, since, 10)
+	c.Assert(err, IsNil)
+	c.Assert(result, DeepEquals, lines)
+}
+
+func (ft *FacadeUnitTest) Test_SendDockerAction_Diagnostic(c *C) {
+	svc := &service.Service{ID: "svc1", PoolID: "pool1", Actions: map[string]string{"diag-lsof": "custom-lsof"}}
+	ft.serviceStore.On("Get", ft.ctx, "svc1").Return(svc, nil)
+	ft.zzk.On("SendDiagnosticAction", "pool1", "svc1", 0, "diag-netstat", service.DiagnosticActions["diag-netstat"]).Return(nil)
+	ft.zzk.On("SendDockerAction", "pool1", "svc1", 0, "custom-lsof", []string{}).Return(nil)
+
+	// built-in diagnostics are available on every service
+	err := ft.Facade.SendDockerAction(ft.ctx, "svc1", 0, "diag-netstat", []string{})
+	c.Assert(err, IsNil)
+	ft.zzk.AssertCalled(c, "SendDiagnosticAction", "pool1", "svc1", 0, "diag-netstat", service.DiagnosticActions["diag-netstat"])
+
+	// unless the template defines an action with the same name
+	err = ft.Facade.SendDockerAction(ft.ctx, "svc1", 0, "diag-lsof", []string{})
+	c.Assert(err, IsNil)
+	ft.zzk.AssertCalled(c, "SendDockerAction", "pool1", "svc1", 0, "custom-lsof", []string{})
+
+	err = ft.Facade.SendDockerAction(ft.ctx, "svc1", 0, "not-an-action", []string{})
+	c.Assert(err, ErrorMatches, "command not found for action")
+}
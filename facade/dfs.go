@@ -14,23 +14,29 @@
 package facade
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"time"
 
+	"github.com/control-center/serviced/commons/atomicfile"
 	"github.com/control-center/serviced/commons/docker"
+	"github.com/control-center/serviced/coordinator/storage"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/statehistory"
 	"github.com/control-center/serviced/volume"
 	dockerclient "github.com/fsouza/go-dockerclient"
-	"github.com/zenoss/glog"
 )
 
 const (
@@ -39,8 +45,13 @@ const (
 	oldLocalRegistryContainerNameBase = "cc-temp-registry-v%d"
 	registryRootSubdir                = "docker-registry"
 	upgradedMarkerFile                = "cc-upgraded"
+	migrateProgressFile               = "cc-migrate-progress"
 )
 
+// ErrQuarantinedTenantInUse is returned when a volume quarantined by a failed
+// restore belongs to an application that has since been deployed.
+var ErrQuarantinedTenantInUse = errors.New("facade: quarantined volume belongs to a deployed application")
+
 type registryVersionInfo struct {
 	version int
 	rootDir string
@@ -67,39 +78,39 @@ func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, s
 
 	stime := time.Now()
 	message := fmt.Sprintf("started backup at %s", stime.UTC())
-	glog.Infof("Starting backup")
+	plog.Infof("Starting backup")
 	templates, images, err := f.GetServiceTemplatesAndImages(ctx)
 	if err != nil {
-		glog.Errorf("Could not get service templates and images: %s", err)
+		plog.Errorf("Could not get service templates and images: %s", err)
 		return err
 	}
-	glog.Infof("Loaded templates and their images")
+	plog.Infof("Loaded templates and their images")
 	pools, err := f.GetResourcePools(ctx)
 	if err != nil {
-		glog.Errorf("Could not get resource pools: %s", err)
+		plog.Errorf("Could not get resource pools: %s", err)
 		return err
 	}
-	glog.Infof("Loaded resource pools")
+	plog.Infof("Loaded resource pools")
 	tenants, err := f.getTenantIDs(ctx)
 	if err != nil {
-		glog.Errorf("Could not get tenants: %s", err)
+		plog.Errorf("Could not get tenants: %s", err)
 		return err
 	}
 	snapshots := make([]string, len(tenants))
 	snapshotExcludes := map[string][]string{}
 	for i, tenant := range tenants {
 		tag := fmt.Sprintf("backup-%s-%s", tenant, stime)
-		snapshot, err := f.Snapshot(ctx, tenant, message, []string{tag}, snapshotSpacePercent)
+		snapshot, err := f.Snapshot(ctx, tenant, message, []string{tag}, snapshotSpacePercent, dfs.SnapshotProvenance{Source: dfs.SnapshotSourceScheduled})
 		if err != nil {
-			glog.Errorf("Could not snapshot %s: %s", tenant, err)
+			plog.Errorf("Could not snapshot %s: %s", tenant, err)
 			return err
 		}
 		defer f.DeleteSnapshot(ctx, snapshot)
 		snapshots[i] = snapshot
 		snapshotExcludes[snapshot] = append(excludes, f.getExcludedVolumes(ctx, tenant)...)
-		glog.Infof("Created a snapshot for tenant %s at %s", tenant, snapshot)
+		plog.Infof("Created a snapshot for tenant %s at %s", tenant, snapshot)
 	}
-	glog.Infof("Loaded tenants")
+	plog.Infof("Loaded tenants")
 	data := dfs.BackupInfo{
 		Templates:        templates,
 		BaseImages:       images,
@@ -107,38 +118,115 @@ func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, s
 		Snapshots:        snapshots,
 		SnapshotExcludes: snapshotExcludes,
 		Timestamp:        stime,
-		BackupVersion:    1,
+		BackupVersion:    dfs.CurrentBackupVersion,
 	}
 	if err := f.dfs.Backup(data, w); err != nil {
-		glog.Errorf("Could not backup: %s", err)
+		plog.Errorf("Could not backup: %s", err)
 		return err
 	}
-	glog.Infof("Completed backup in %s", time.Since(stime))
+	plog.Infof("Completed backup in %s", time.Since(stime))
 	return nil
 }
 
+// EstimateBackup returns the estimated size and duration of a backup,
+// broken down by image and tenant volume, without taking any snapshots.
+func (f *Facade) EstimateBackup(ctx datastore.Context, excludes []string) (*dfs.BackupEstimate, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("EstimateBackup"))
+	_, images, err := f.GetServiceTemplatesAndImages(ctx)
+	if err != nil {
+		plog.Errorf("Could not get service templates and images: %s", err)
+		return nil, err
+	}
+	tenants, err := f.getTenantIDs(ctx)
+	if err != nil {
+		plog.Errorf("Could not get tenants: %s", err)
+		return nil, err
+	}
+	req := dfs.BackupEstimateRequest{
+		BaseImages: images,
+		Tenants:    tenants,
+		Excludes:   make(map[string][]string),
+	}
+	for _, tenant := range tenants {
+		tenantExcludes := append([]string{}, excludes...)
+		req.Excludes[tenant] = append(tenantExcludes, f.getExcludedVolumes(ctx, tenant)...)
+	}
+	estimate, err := f.dfs.EstimateBackup(req)
+	if err != nil {
+		plog.Errorf("Could not estimate backup: %s", err)
+		return nil, err
+	}
+	return estimate, nil
+}
+
+// CleanupFailedRestore removes the volumes, snapshots, and registry images
+// imported by restores that did not complete.  If dryRun is set, it only
+// returns what would be removed.
+func (f *Facade) CleanupFailedRestore(ctx datastore.Context, dryRun bool) (*dfs.RestoreQuarantine, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CleanupFailedRestore"))
+	if dryRun {
+		return f.dfs.RestoreQuarantine()
+	}
+	if err := f.checkDFSFrozen(); err != nil {
+		return nil, err
+	}
+	if err := f.DFSLock(ctx).LockWithTimeout("clean up failed restore", userLockTimeout); err != nil {
+		plog.Warningf("Cannot clean up failed restore: %s", err)
+		return nil, err
+	}
+	defer f.DFSLock(ctx).Unlock()
+	quarantine, err := f.dfs.RestoreQuarantine()
+	if err != nil {
+		plog.Errorf("Could not get restore quarantine: %s", err)
+		return nil, err
+	}
+	tenants, err := f.getTenantIDs(ctx)
+	if err != nil {
+		plog.Errorf("Could not get tenants: %s", err)
+		return nil, err
+	}
+	for _, tenantID := range tenants {
+		for _, volumeName := range quarantine.Volumes {
+			if tenantID == volumeName {
+				plog.Errorf("Quarantined volume %s belongs to a deployed application", volumeName)
+				return nil, ErrQuarantinedTenantInUse
+			}
+		}
+	}
+	removed, err := f.dfs.CleanupFailedRestore()
+	if err != nil {
+		plog.Errorf("Could not clean up failed restore: %s", err)
+		return nil, err
+	}
+	plog.Infof("Cleaned up failed restore")
+	return removed, nil
+}
+
 // BackupInfo returns metadata info about a backup
 func (f *Facade) BackupInfo(ctx datastore.Context, r io.Reader) (*dfs.BackupInfo, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("BackupInfo"))
 	info, err := f.dfs.BackupInfo(r)
 	if err != nil {
-		glog.Errorf("Could not get info for backup: %s", err)
+		plog.Errorf("Could not get info for backup: %s", err)
 		return nil, err
 	}
 	return info, nil
 }
 
 // Commit commits a container to the docker registry and takes a snapshot.
-func (f *Facade) Commit(ctx datastore.Context, ctrID, message string, tags []string, snapshotSpacePercent int) (string, error) {
+func (f *Facade) Commit(ctx datastore.Context, ctrID, message string, tags []string, snapshotSpacePercent int, provenance dfs.SnapshotProvenance) (string, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Commit"))
+	if err := f.checkDFSFrozen(); err != nil {
+		return "", err
+	}
 	tenantID, err := f.dfs.Commit(ctrID)
 	if err != nil {
-		glog.Errorf("Could not commit container %s: %s", ctrID, err)
+		plog.Errorf("Could not commit container %s: %s", ctrID, err)
 		return "", err
 	}
-	snapshotID, err := f.Snapshot(ctx, tenantID, message, tags, snapshotSpacePercent)
+	snapshotID, err := f.Snapshot(ctx, tenantID, message, tags, snapshotSpacePercent, provenance)
 	if err != nil {
-		glog.Errorf("Could not snapshot %s: %s", tenantID, err)
+		plog.Errorf("Could not snapshot %s: %s", tenantID, err)
 		return "", err
 	}
 	return snapshotID, nil
@@ -149,7 +237,7 @@ func (f *Facade) DeleteSnapshot(ctx datastore.Context, snapshotID string) error
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("DeleteSnapshot"))
 	// Do not DFSLock here, ControlPlaneDao does that
 	if err := f.dfs.Delete(snapshotID); err != nil {
-		glog.Errorf("Could not delete snapshot %s: %s", snapshotID, err)
+		plog.Errorf("Could not delete snapshot %s: %s", snapshotID, err)
 		return err
 	}
 	return nil
@@ -177,29 +265,97 @@ func (f *Facade) DFSLock(ctx datastore.Context) dfs.DFSLocker {
 	return f.dfs
 }
 
+// FreezeDFS makes the distributed filesystem read-only for maintenance.  It
+// waits for running dfs operations to complete, then blocks snapshots,
+// commits, and restores and has the delegates remount their volumes
+// read-only until the dfs is thawed.
+func (f *Facade) FreezeDFS(ctx datastore.Context, reason string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("FreezeDFS"))
+	if err := f.DFSLock(ctx).LockWithTimeout("freeze dfs", userLockTimeout); err != nil {
+		plog.Warningf("Cannot freeze dfs: %s", err)
+		return err
+	}
+	defer f.DFSLock(ctx).Unlock()
+	status := storage.FreezeStatus{Frozen: true, Reason: reason, Since: time.Now().UTC()}
+	if err := f.zzk.SetDFSFreezeStatus(status); err != nil {
+		plog.Errorf("Could not freeze dfs: %s", err)
+		return err
+	}
+	plog.Infof("Distributed filesystem is read-only: %s", reason)
+	return nil
+}
+
+// ThawDFS makes the distributed filesystem writable again after maintenance.
+func (f *Facade) ThawDFS(ctx datastore.Context) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ThawDFS"))
+	status := storage.FreezeStatus{Frozen: false, Since: time.Now().UTC()}
+	if err := f.zzk.SetDFSFreezeStatus(status); err != nil {
+		plog.Errorf("Could not thaw dfs: %s", err)
+		return err
+	}
+	plog.Infof("Distributed filesystem is writable")
+	return nil
+}
+
+// GetDFSFreezeStatus returns whether the distributed filesystem is read-only
+// for maintenance.
+func (f *Facade) GetDFSFreezeStatus(ctx datastore.Context) (*storage.FreezeStatus, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetDFSFreezeStatus"))
+	return f.zzk.GetDFSFreezeStatus()
+}
+
+// checkDFSFrozen returns an error if the distributed filesystem is read-only
+func (f *Facade) checkDFSFrozen() error {
+	status, err := f.zzk.GetDFSFreezeStatus()
+	if err != nil {
+		plog.Errorf("Could not check whether the dfs is read-only: %s", err)
+		return err
+	} else if status.Frozen {
+		plog.Warningf("Distributed filesystem is read-only since %s: %s", status.Since, status.Reason)
+		return storage.ErrDFSFrozen
+	}
+	return nil
+}
+
 // GetSnapshotInfo returns information about a snapshot.
 func (f *Facade) GetSnapshotInfo(ctx datastore.Context, snapshotID string) (*dfs.SnapshotInfo, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetSnapshotInfo"))
 	info, err := f.dfs.Info(snapshotID)
 	if err != nil {
-		glog.Errorf("Could not get info for snapshot %s: %s", snapshotID, err)
+		plog.Errorf("Could not get info for snapshot %s: %s", snapshotID, err)
 		return nil, err
 	}
 	return info, nil
 }
 
+// GetSnapshotService returns the definition of a service stored in a
+// snapshot.
+func (f *Facade) GetSnapshotService(ctx datastore.Context, snapshotID, serviceID string) (*service.Service, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetSnapshotService"))
+	info, err := f.GetSnapshotInfo(ctx, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range info.Services {
+		if info.Services[i].ID == serviceID {
+			return &info.Services[i], nil
+		}
+	}
+	return nil, fmt.Errorf("service %s is not in snapshot %s", serviceID, snapshotID)
+}
+
 // ListSnapshots returns a list of strings that describes the snapshots for the
 // given application.
 func (f *Facade) ListSnapshots(ctx datastore.Context, serviceID string) ([]string, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("ListSnapshots"))
 	tenantID, err := f.GetTenantID(ctx, serviceID)
 	if err != nil {
-		glog.Errorf("Could not find tenant for service %s: %s", serviceID, err)
+		plog.Errorf("Could not find tenant for service %s: %s", serviceID, err)
 		return nil, err
 	}
 	snapshots, err := f.dfs.List(tenantID)
 	if err != nil {
-		glog.Errorf("Could not list snapshots for tenant %s: %s", tenantID, err)
+		plog.Errorf("Could not list snapshots for tenant %s: %s", tenantID, err)
 		return nil, err
 	}
 	return snapshots, nil
@@ -208,7 +364,7 @@ func (f *Facade) ListSnapshots(ctx datastore.Context, serviceID string) ([]strin
 // TagSnapshot adds tags to an existing snapshot
 func (f *Facade) TagSnapshot(snapshotID string, tagName string) error {
 	if err := f.dfs.Tag(snapshotID, tagName); err != nil {
-		glog.Errorf("Could not add tag to snapshot %s: %s", snapshotID, err)
+		plog.Errorf("Could not add tag to snapshot %s: %s", snapshotID, err)
 		return err
 	}
 	return nil
@@ -219,12 +375,12 @@ func (f *Facade) RemoveSnapshotTag(ctx datastore.Context, serviceID, tagName str
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("RemoveSnapshotTag"))
 	tenantID, err := f.GetTenantID(ctx, serviceID)
 	if err != nil {
-		glog.Errorf("Could not find tenant for service %s: %s", serviceID, err)
+		plog.Errorf("Could not find tenant for service %s: %s", serviceID, err)
 		return "", err
 	}
 	snapshotID, err := f.dfs.Untag(tenantID, tagName)
 	if err != nil {
-		glog.Errorf("Could not remove tag %s from tenant %s: %s", tagName, snapshotID, err)
+		plog.Errorf("Could not remove tag %s from tenant %s: %s", tagName, snapshotID, err)
 		return "", err
 	}
 	return snapshotID, nil
@@ -235,12 +391,12 @@ func (f *Facade) GetSnapshotByServiceIDAndTag(ctx datastore.Context, serviceID,
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetSnapshotByServiceIDAndTag"))
 	tenantID, err := f.GetTenantID(ctx, serviceID)
 	if err != nil {
-		glog.Errorf("Could not find tenant for service %s: %s", serviceID, err)
+		plog.Errorf("Could not find tenant for service %s: %s", serviceID, err)
 		return nil, err
 	}
 	info, err := f.dfs.TagInfo(tenantID, tagName)
 	if err != nil {
-		glog.Errorf("Could not get info for snapshot tag %s: %s", tagName, err)
+		plog.Errorf("Could not get info for snapshot tag %s: %s", tagName, err)
 		return nil, err
 	}
 	return info, nil
@@ -251,7 +407,7 @@ func (f *Facade) ResetLock(ctx datastore.Context, serviceID string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("ResetLock"))
 	tenantID, err := f.GetTenantID(ctx, serviceID)
 	if err != nil {
-		glog.Errorf("Could not find tenant for service %s: %s", serviceID, err)
+		plog.Errorf("Could not find tenant for service %s: %s", serviceID, err)
 		return err
 	}
 	mutex := getTenantLock(tenantID)
@@ -267,7 +423,7 @@ func (f *Facade) ResetLocks(ctx datastore.Context) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("ResetLocks"))
 	tenantIDs, err := f.getTenantIDs(ctx)
 	if err != nil {
-		glog.Errorf("Could not get tenants: %s", err)
+		plog.Errorf("Could not get tenants: %s", err)
 		return err
 	}
 	for _, tenantID := range tenantIDs {
@@ -292,7 +448,7 @@ func (f *Facade) Download(imageID, tenantID string) error {
 func (f *Facade) RepairRegistry(ctx datastore.Context) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("RepairRegistry"))
 	if err := f.DFSLock(ctx).LockWithTimeout("reset registry", userLockTimeout); err != nil {
-		glog.Warningf("Cannot reset registry: %s", err)
+		plog.Warningf("Cannot reset registry: %s", err)
 		return err
 	}
 	defer f.DFSLock(ctx).Unlock()
@@ -310,7 +466,7 @@ func (f *Facade) RepairRegistry(ctx datastore.Context) error {
 		for _, svc := range svcs {
 			if _, ok := imagesMap[svc.ImageID]; !ok {
 				if _, err := f.dfs.Download(svc.ImageID, tenantID, true); err != nil {
-					glog.Errorf("Could not download image %s from registry: %s", svc.ImageID, err)
+					plog.Errorf("Could not download image %s from registry: %s", svc.ImageID, err)
 					return err
 				}
 				imagesMap[svc.ImageID] = struct{}{}
@@ -327,44 +483,30 @@ func (f *Facade) RepairRegistry(ctx datastore.Context) error {
 func (f *Facade) UpgradeRegistry(ctx datastore.Context, fromRegistryHost string, force bool) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("UpgradeRegistry"))
 	if err := f.DFSLock(ctx).LockWithTimeout("migrate registry", userLockTimeout); err != nil {
-		glog.Warningf("Cannot migrate registry: %s", err)
+		plog.Warningf("Cannot migrate registry: %s", err)
 		return err
 	}
 	defer f.DFSLock(ctx).Unlock()
 
-	success := true // indicates a successful migration
 	if fromRegistryHost == "" {
 		// check if a local docker migration is needed
 		isMigrated, previousVersion, err := f.getPreviousRegistryVersion()
 		if err != nil {
-			glog.Errorf("Could not determine the previous docker registry to migrate: %s", err)
+			plog.Errorf("Could not determine the previous docker registry to migrate: %s", err)
 			return err
 		}
 		if previousVersion == currentRegistryVersion {
-			glog.Infof("No previous version of the docker registry exists; nothing to migrate")
+			plog.Infof("No previous version of the docker registry exists; nothing to migrate")
 			return nil
 		}
-		if !isMigrated || force {
-			glog.Infof("Starting local docker registry v%d", previousVersion)
-			oldRegistryCtr, err := f.startDockerRegistry(previousVersion, oldLocalRegistryPort)
-			if err != nil {
-				glog.Errorf("Could not start v%d docker registry at port %s: %s", previousVersion, oldLocalRegistryPort, err)
-				return err
-			}
-			defer func() {
-				if success {
-					f.markLocalDockerRegistryUpgraded(previousVersion)
-				}
-				glog.Infof("Stopping docker registry v%d container %s", previousVersion, oldRegistryCtr.Name)
-				if err := oldRegistryCtr.Stop(5 * time.Minute); err != nil {
-					glog.Errorf("Could not stop docker registry v%d container %s: %s", previousVersion, oldRegistryCtr.Name, err)
-				}
-			}()
-			fromRegistryHost = fmt.Sprintf("localhost:%s", oldLocalRegistryPort)
-		} else {
-			glog.Infof("Registry already migrated from v%d; no action required", previousVersion)
+		if isMigrated && !force {
+			plog.Infof("Registry already migrated from v%d; no action required", previousVersion)
 			return nil
 		}
+		if err := f.migrateLocalRegistry(ctx, previousVersion, force); err != nil {
+			plog.Warningf("Could not upgrade registry from v%d: %s", previousVersion, err)
+		}
+		return nil
 	}
 	tenantIDs, err := f.getTenantIDs(ctx)
 	if err != nil {
@@ -376,13 +518,194 @@ func (f *Facade) UpgradeRegistry(ctx datastore.Context, fromRegistryHost string,
 			return err
 		}
 		if err := f.dfs.UpgradeRegistry(svcs, tenantID, fromRegistryHost, force); err != nil {
-			success = false
-			glog.Warningf("Could not upgrade registry for tenant %s: %s", tenantID, err)
+			plog.Warningf("Could not upgrade registry for tenant %s: %s", tenantID, err)
 		}
 	}
 	return nil
 }
 
+// MigrateRegistry moves the images referenced by every service from the local
+// docker registry at version fromVersion into the registry at version
+// toVersion.  If fromVersion is 0, the most recent previous registry is used;
+// if toVersion is 0, the current registry is used.
+// Progress is saved after each tenant, so a migration that is interrupted or
+// that fails to move some images resumes where it left off when run again.
+// The old registry is only marked as migrated once every image has been
+// moved.  If force is true, all images are migrated again.
+func (f *Facade) MigrateRegistry(ctx datastore.Context, fromVersion, toVersion int, force bool) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("MigrateRegistry"))
+	if toVersion == 0 {
+		toVersion = currentRegistryVersion
+	}
+	if toVersion != currentRegistryVersion {
+		return fmt.Errorf("cannot migrate to registry v%d; only v%d is supported", toVersion, currentRegistryVersion)
+	}
+	if fromVersion != 0 {
+		if _, ok := registryVersionInfos[fromVersion]; !ok || fromVersion >= toVersion {
+			return fmt.Errorf("cannot migrate from registry v%d to v%d", fromVersion, toVersion)
+		}
+	}
+	if err := f.DFSLock(ctx).LockWithTimeout("migrate registry", userLockTimeout); err != nil {
+		plog.Warningf("Cannot migrate registry: %s", err)
+		return err
+	}
+	defer f.DFSLock(ctx).Unlock()
+
+	if fromVersion == 0 {
+		isMigrated, previousVersion, err := f.getPreviousRegistryVersion()
+		if err != nil {
+			plog.Errorf("Could not determine the previous docker registry to migrate: %s", err)
+			return err
+		}
+		if previousVersion == currentRegistryVersion {
+			plog.Infof("No previous version of the docker registry exists; nothing to migrate")
+			return nil
+		}
+		if isMigrated && !force {
+			plog.Infof("Registry already migrated from v%d; no action required", previousVersion)
+			return nil
+		}
+		return f.migrateLocalRegistry(ctx, previousVersion, force)
+	}
+
+	versionInfo := registryVersionInfos[fromVersion]
+	registryPath := versionInfo.getStoragePath(f.isvcsPath)
+	if _, err := os.Stat(registryPath); os.IsNotExist(err) {
+		return fmt.Errorf("no v%d docker registry found at %s", fromVersion, registryPath)
+	} else if err != nil {
+		plog.Errorf("Could not stat v%d registry path at %s: %s", fromVersion, registryPath, err)
+		return err
+	}
+	if _, err := os.Stat(versionInfo.getUpgradedMarkerPath(f.isvcsPath)); err == nil && !force {
+		plog.Infof("Registry already migrated from v%d; no action required", fromVersion)
+		return nil
+	}
+	return f.migrateLocalRegistry(ctx, fromVersion, force)
+}
+
+// migrateLocalRegistry starts the local docker registry at the given version
+// and re-pushes every image referenced by a service into the current
+// registry, recording its progress after each tenant.  The caller must hold the dfs
+// lock.
+func (f *Facade) migrateLocalRegistry(ctx datastore.Context, version int, force bool) error {
+	versionInfo := registryVersionInfos[version]
+	progressPath := versionInfo.getProgressPath(f.isvcsPath)
+	migrated := make(map[string]bool)
+	if force {
+		os.Remove(progressPath)
+	} else if data, err := ioutil.ReadFile(progressPath); err == nil {
+		var images []string
+		if err := json.Unmarshal(data, &images); err != nil {
+			plog.Warningf("Ignoring unreadable registry migration progress file %s: %s", progressPath, err)
+		}
+		for _, image := range images {
+			migrated[image] = true
+		}
+	} else if !os.IsNotExist(err) {
+		plog.Errorf("Could not read registry migration progress file %s: %s", progressPath, err)
+		return err
+	}
+
+	// find the images referenced by each tenant's services that still need
+	// to be migrated
+	tenantIDs, err := f.getTenantIDs(ctx)
+	if err != nil {
+		return err
+	}
+	total := 0
+	pending := make(map[string][]service.Service)
+	for _, tenantID := range tenantIDs {
+		svcs, err := f.GetServices(ctx, dao.ServiceRequest{TenantID: tenantID})
+		if err != nil {
+			return err
+		}
+		images := make(map[string]struct{})
+		for _, svc := range svcs {
+			if svc.ImageID == "" {
+				continue
+			}
+			if _, ok := images[svc.ImageID]; ok {
+				continue
+			}
+			images[svc.ImageID] = struct{}{}
+			total++
+			if migrated[path.Join(tenantID, svc.ImageID)] {
+				plog.Infof("Image %s for tenant %s was already migrated", svc.ImageID, tenantID)
+				continue
+			}
+			pending[tenantID] = append(pending[tenantID], svc)
+		}
+	}
+
+	plog.Infof("Starting local docker registry v%d", version)
+	oldRegistryCtr, err := f.startDockerRegistry(version, oldLocalRegistryPort)
+	if err != nil {
+		plog.Errorf("Could not start v%d docker registry at port %s: %s", version, oldLocalRegistryPort, err)
+		return err
+	}
+	defer func() {
+		plog.Infof("Stopping docker registry v%d container %s", version, oldRegistryCtr.Name)
+		if err := oldRegistryCtr.Stop(5 * time.Minute); err != nil {
+			plog.Errorf("Could not stop docker registry v%d container %s: %s", version, oldRegistryCtr.Name, err)
+		}
+	}()
+	fromRegistryHost := fmt.Sprintf("localhost:%s", oldLocalRegistryPort)
+
+	// the images of a tenant are upgraded in parallel, so progress is saved
+	// once all of the tenant's images have been migrated; images that were
+	// migrated before a failure are found in the registry and skipped quickly
+	// when the migration is resumed.
+	done := total
+	for _, svcs := range pending {
+		done -= len(svcs)
+	}
+	failed := 0
+	for _, tenantID := range tenantIDs {
+		svcs := pending[tenantID]
+		if len(svcs) == 0 {
+			continue
+		}
+		if err := f.dfs.UpgradeRegistry(svcs, tenantID, fromRegistryHost, force); err != nil {
+			plog.Warningf("Could not migrate images for tenant %s: %s", tenantID, err)
+			failed++
+			continue
+		}
+		for _, svc := range svcs {
+			migrated[path.Join(tenantID, svc.ImageID)] = true
+		}
+		if err := saveRegistryMigrationProgress(progressPath, migrated); err != nil {
+			plog.Warningf("Could not save registry migration progress to %s: %s", progressPath, err)
+		}
+		done += len(svcs)
+		plog.Infof("Migrated %d images for tenant %s (%d of %d images migrated)", len(svcs), tenantID, done, total)
+	}
+	if failed > 0 {
+		return fmt.Errorf("could not migrate the images of %d tenants from the v%d registry; run the migration again to resume", failed, version)
+	}
+
+	// all images are in the current registry, so switch over from the old one
+	if err := f.markLocalDockerRegistryUpgraded(version); err != nil {
+		return err
+	}
+	os.Remove(progressPath)
+	plog.Infof("Completed migration of %d images from the v%d docker registry", total, version)
+	return nil
+}
+
+// saveRegistryMigrationProgress records the images that have been migrated
+func saveRegistryMigrationProgress(filename string, migrated map[string]bool) error {
+	images := make([]string, 0, len(migrated))
+	for image := range migrated {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	data, err := json.Marshal(images)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(filename, data, 0644)
+}
+
 // startDockerRegistry returns the old docker registry container.
 func (f *Facade) startDockerRegistry(version int, port string) (*docker.Container, error) {
 	versionInfo := registryVersionInfos[version]
@@ -393,20 +716,20 @@ func (f *Facade) startDockerRegistry(version int, port string) (*docker.Containe
 // registry that needs migration and whether it has been previously migrated.
 func (f *Facade) getPreviousRegistryVersion() (isMigrated bool, version int, err error) {
 	for i := currentRegistryVersion - 1; i > 0; i-- {
-		glog.Infof("Checking v%d docker registry", i)
+		plog.Infof("Checking v%d docker registry", i)
 		versionInfo := registryVersionInfos[i]
 		registryPath := versionInfo.getStoragePath(f.isvcsPath)
 		if _, err := os.Stat(registryPath); os.IsNotExist(err) {
 			continue
 		} else if err != nil {
-			glog.Errorf("Could not stat v%d registry path at %s: %s", i, registryPath, err)
+			plog.Errorf("Could not stat v%d registry path at %s: %s", i, registryPath, err)
 			return false, 0, err
 		}
 		markerFilePath := versionInfo.getUpgradedMarkerPath(f.isvcsPath)
 		if _, err := os.Stat(markerFilePath); os.IsNotExist(err) {
 			return false, i, nil
 		} else if err != nil {
-			glog.Errorf("Could not stat v%d registry marker file at %s: %s", i, markerFilePath, err)
+			plog.Errorf("Could not stat v%d registry marker file at %s: %s", i, markerFilePath, err)
 			return false, 0, err
 		}
 		return true, i, nil
@@ -419,40 +742,62 @@ func (f *Facade) getPreviousRegistryVersion() (isMigrated bool, version int, err
 func (f *Facade) markLocalDockerRegistryUpgraded(version int) error {
 	versionInfo := registryVersionInfos[version]
 	markerFilePath := versionInfo.getUpgradedMarkerPath(f.isvcsPath)
-	if err := ioutil.WriteFile(markerFilePath, []byte{}, 0644); err != nil {
-		glog.Errorf("Could not write marker file %s: %s", markerFilePath, err)
+	if err := atomicfile.WriteFile(markerFilePath, []byte{}, 0644); err != nil {
+		plog.Errorf("Could not write marker file %s: %s", markerFilePath, err)
 		return err
 	}
 	return nil
 }
 
-// Restore restores application data from a backup.
-func (f *Facade) Restore(ctx datastore.Context, r io.Reader, backupInfo *dfs.BackupInfo) error {
+// Restore restores application data from a backup, after verifying that the
+// backup is compatible with this system.  Failed compatibility checks listed
+// in force are ignored.
+func (f *Facade) Restore(ctx datastore.Context, r io.Reader, backupInfo *dfs.BackupInfo, force []string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Restore"))
 	// Do not DFSLock here, ControlPlaneDao does that
-	glog.Infof("Beginning restore from backup")
+	if err := f.checkDFSFrozen(); err != nil {
+		return err
+	}
+	templates, err := f.templateStore.GetServiceTemplates(ctx)
+	if err != nil {
+		plog.Errorf("Could not get service templates: %s", err)
+		return err
+	}
+	opts := dfs.RestoreOptions{
+		Templates: make([]servicetemplate.ServiceTemplate, len(templates)),
+		Force:     force,
+	}
+	for i, template := range templates {
+		opts.Templates[i] = *template
+	}
+	if err := f.dfs.CheckRestore(*backupInfo, opts); err != nil {
+		plog.Errorf("Could not restore from backup: %s", err)
+		return err
+	}
+	plog.Infof("Beginning restore from backup")
 	if err := f.dfs.Restore(r, backupInfo.BackupVersion); err != nil {
-		glog.Errorf("Could not restore from backup: %s", err)
+		plog.Errorf("Could not restore from backup: %s", err)
+		plog.Warningf("Remove the data imported by the failed restore with `serviced backup cleanup-failed`")
 		return err
 	}
 	if err := f.RestoreServiceTemplates(ctx, backupInfo.Templates); err != nil {
-		glog.Errorf("Could not restore service templates from backup: %s", err)
+		plog.Errorf("Could not restore service templates from backup: %s", err)
 		return err
 	}
-	glog.Infof("Restored service templates")
+	plog.Infof("Restored service templates")
 	if err := f.RestoreResourcePools(ctx, backupInfo.Pools); err != nil {
-		glog.Errorf("Could not restore resource pools from backup: %s", err)
+		plog.Errorf("Could not restore resource pools from backup: %s", err)
 		return err
 	}
-	glog.Infof("Restored resource pools")
+	plog.Infof("Restored resource pools")
 	for _, snapshot := range backupInfo.Snapshots {
 		if err := f.Rollback(ctx, snapshot, false); err != nil {
-			glog.Errorf("Could not rollback snapshot %s: %s", snapshot, err)
+			plog.Errorf("Could not rollback snapshot %s: %s", snapshot, err)
 			return err
 		}
-		glog.Infof("Rolled back snapshot %s", snapshot)
+		plog.Infof("Rolled back snapshot %s", snapshot)
 	}
-	glog.Infof("Completed restore from backup")
+	plog.Infof("Completed restore from backup")
 	return nil
 }
 
@@ -460,22 +805,26 @@ func (f *Facade) Restore(ctx datastore.Context, r io.Reader, backupInfo *dfs.Bac
 // snapshot.
 func (f *Facade) Rollback(ctx datastore.Context, snapshotID string, force bool) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Rollback"))
+	ctx = datastore.WithActor(ctx, statehistory.SourceRollback, datastore.GetActor(ctx).User)
 	// Do not DFSLock here, ControlPlaneDao does that
-	glog.Infof("Beginning rollback of snapshot %s", snapshotID)
+	if err := f.checkDFSFrozen(); err != nil {
+		return err
+	}
+	plog.Infof("Beginning rollback of snapshot %s", snapshotID)
 	info, err := f.dfs.Info(snapshotID)
 	if err != nil {
-		glog.Errorf("Could not get info for snapshot %s: %s", snapshotID, err)
+		plog.Errorf("Could not get info for snapshot %s: %s", snapshotID, err)
 		return err
 	}
 	if err := f.lockTenant(ctx, info.TenantID); err != nil {
-		glog.Errorf("Could not lock tenant %s: %s", info.TenantID, err)
+		plog.Errorf("Could not lock tenant %s: %s", info.TenantID, err)
 		return err
 	}
 	defer f.retryUnlockTenant(ctx, info.TenantID, nil, time.Second)
-	glog.Infof("Checking states for services under %s", info.TenantID)
+	plog.Infof("Checking states for services under %s", info.TenantID)
 	svcs, err := f.GetServices(ctx, dao.ServiceRequest{TenantID: info.TenantID})
 	if err != nil {
-		glog.Errorf("Could not get services under %s: %s", info.TenantID, err)
+		plog.Errorf("Could not get services under %s: %s", info.TenantID, err)
 		return err
 	}
 	serviceids := make([]string, len(svcs))
@@ -484,66 +833,63 @@ func (f *Facade) Rollback(ctx datastore.Context, snapshotID string, force bool)
 			if force {
 				defer f.scheduleService(ctx, info.TenantID, svc.ID, false, service.DesiredState(svc.DesiredState), true)
 				if _, err := f.scheduleService(ctx, info.TenantID, svc.ID, false, service.SVCStop, true); err != nil {
-					glog.Errorf("Could not %s service %s (%s): %s", service.SVCStop, svc.Name, svc.ID, err)
+					plog.Errorf("Could not %s service %s (%s): %s", service.SVCStop, svc.Name, svc.ID, err)
 					return err
 				}
 			} else {
-				glog.Errorf("Could not rollback to snapshot %s: service %s (%s) is running", snapshotID, svc.Name, svc.ID)
+				plog.Errorf("Could not rollback to snapshot %s: service %s (%s) is running", snapshotID, svc.Name, svc.ID)
 				return errors.New("service is running")
 			}
 		}
 		serviceids[i] = svc.ID
 	}
 	if err := f.WaitService(ctx, service.SVCStop, f.dfs.Timeout(), false, serviceids...); err != nil {
-		glog.Errorf("Could not wait for services to %s during rollback of snapshot %s: %s", service.SVCStop, snapshotID, err)
+		plog.Errorf("Could not wait for services to %s during rollback of snapshot %s: %s", service.SVCStop, snapshotID, err)
 		return err
 	}
-	glog.Infof("Services are all stopped, reverting service data")
+	plog.Infof("Services are all stopped, reverting service data")
 	if err := f.RestoreServices(ctx, info.TenantID, info.Services); err != nil {
-		glog.Errorf("Could not restore services: %s", err)
+		plog.Errorf("Could not restore services: %s", err)
 		return err
 	}
-	glog.Infof("Service data is rolled back, now restoring disk and images")
+	plog.Infof("Service data is rolled back, now restoring disk and images")
 	if err := f.dfs.Rollback(snapshotID); err != nil {
-		glog.Errorf("Could not rollback snapshot %s: %s", snapshotID, err)
+		plog.Errorf("Could not rollback snapshot %s: %s", snapshotID, err)
 		return err
 	}
-	glog.Infof("Successfully restored application data from snapshot %s", snapshotID)
+	plog.Infof("Successfully restored application data from snapshot %s", snapshotID)
 	return nil
 }
 
-// Snapshot takes a snapshot for a particular application.
-func (f *Facade) Snapshot(ctx datastore.Context, serviceID, message string, tags []string, snapshotSpacePercent int) (string, error) {
+// Snapshot takes a snapshot for a particular application.  The provenance
+// records who and what requested the snapshot; the ids of the snapshotted
+// images are filled in by the dfs.
+func (f *Facade) Snapshot(ctx datastore.Context, serviceID, message string, tags []string, snapshotSpacePercent int, provenance dfs.SnapshotProvenance) (string, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Snapshot"))
+	ctx = datastore.WithActor(ctx, statehistory.SourceSnapshot, provenance.User)
 	// Do not DFSLock here, ControlPlaneDao does that
+	if err := f.checkDFSFrozen(); err != nil {
+		return "", err
+	}
 	tenantID, err := f.GetTenantID(ctx, serviceID)
 	if err != nil {
-		glog.Errorf("Could not get tenant id of service %s: %s", serviceID, err)
+		plog.Errorf("Could not get tenant id of service %s: %s", serviceID, err)
 		return "", err
 	}
 	if err := f.lockTenant(ctx, tenantID); err != nil {
-		glog.Errorf("Could not lock tenant %s: %s", tenantID, err)
+		plog.Errorf("Could not lock tenant %s: %s", tenantID, err)
 		return "", err
 	}
 	defer f.retryUnlockTenant(ctx, tenantID, nil, time.Second)
-	glog.Infof("Checking states for services under %s", tenantID)
+	plog.Infof("Checking states for services under %s", tenantID)
 	svcs, err := f.GetServices(ctx, dao.ServiceRequest{TenantID: tenantID})
 	if err != nil {
-		glog.Errorf("Could not get services under %s: %s", tenantID, err)
+		plog.Errorf("Could not get services under %s: %s", tenantID, err)
 		return "", err
 	}
 	imagesMap := make(map[string]struct{})
 	images := make([]string, 0)
-	serviceids := make([]string, len(svcs))
-	for i, svc := range svcs {
-		if svc.DesiredState == int(service.SVCRun) {
-			defer f.scheduleService(ctx, tenantID, svc.ID, false, service.DesiredState(svc.DesiredState), true)
-			if _, err := f.scheduleService(ctx, tenantID, svc.ID, false, service.SVCPause, true); err != nil {
-				glog.Errorf("Could not %s service %s (%s): %s", service.SVCPause, svc.Name, svc.ID, err)
-				return "", err
-			}
-		}
-		serviceids[i] = svc.ID
+	for _, svc := range svcs {
 		if svc.ImageID != "" {
 			if _, ok := imagesMap[svc.ImageID]; !ok {
 				imagesMap[svc.ImageID] = struct{}{}
@@ -551,26 +897,45 @@ func (f *Facade) Snapshot(ctx datastore.Context, serviceID, message string, tags
 			}
 		}
 	}
-	if err := f.WaitService(ctx, service.SVCPause, f.dfs.Timeout(), false, serviceids...); err != nil {
-		glog.Errorf("Could not wait for services to %s during snapshot of %s: %s", service.SVCStop, tenantID, err)
-		return "", err
+	// Pause the services one step at a time, so that the members of a
+	// consistency group are quiesced in order.  The deferred calls resume
+	// them in the reverse order.
+	steps, groups := service.SnapshotSteps(svcs)
+	for _, step := range steps {
+		serviceids := make([]string, len(step))
+		for i, svc := range step {
+			if svc.DesiredState == int(service.SVCRun) {
+				defer f.scheduleService(ctx, tenantID, svc.ID, false, service.DesiredState(svc.DesiredState), true)
+				if _, err := f.scheduleService(ctx, tenantID, svc.ID, false, service.SVCPause, true); err != nil {
+					plog.Errorf("Could not %s service %s (%s): %s", service.SVCPause, svc.Name, svc.ID, err)
+					return "", err
+				}
+			}
+			serviceids[i] = svc.ID
+		}
+		if err := f.WaitService(ctx, service.SVCPause, f.dfs.Timeout(), false, serviceids...); err != nil {
+			plog.Errorf("Could not wait for services to %s during snapshot of %s: %s", service.SVCPause, tenantID, err)
+			return "", err
+		}
 	}
-	glog.Infof("Services are now paused, capturing state")
+	provenance.Groups = groups
+	plog.Infof("Services are now paused, capturing state")
 	data := dfs.SnapshotInfo{
 		SnapshotInfo: &volume.SnapshotInfo{
 			TenantID: tenantID,
 			Message:  message,
 			Tags:     tags,
 		},
-		Services: svcs,
-		Images:   images,
+		Services:   svcs,
+		Images:     images,
+		Provenance: provenance,
 	}
 	snapshotID, err := f.dfs.Snapshot(data, snapshotSpacePercent)
 	if err != nil {
-		glog.Errorf("Could not snapshot disk and images for tenant %s: %s", tenantID, err)
+		plog.Errorf("Could not snapshot disk and images for tenant %s: %s", tenantID, err)
 		return "", err
 	}
-	glog.Infof("Successfully captured application data from tenant %s and created snapshot %s", tenantID, snapshotID)
+	plog.Infof("Successfully captured application data from tenant %s and created snapshot %s", tenantID, snapshotID)
 	return snapshotID, nil
 }
 
@@ -582,6 +947,10 @@ func (info *registryVersionInfo) getUpgradedMarkerPath(isvcsRoot string) string
 	return filepath.Join(info.getStoragePath(isvcsRoot), upgradedMarkerFile)
 }
 
+func (info *registryVersionInfo) getProgressPath(isvcsRoot string) string {
+	return filepath.Join(info.getStoragePath(isvcsRoot), migrateProgressFile)
+}
+
 func (info *registryVersionInfo) start(isvcsRoot string, hostPort string) (*docker.Container, error) {
 	var err error
 
@@ -596,7 +965,7 @@ func (info *registryVersionInfo) start(isvcsRoot string, hostPort string) (*dock
 	container, err := docker.FindContainer(containerName)
 	if err != nil {
 		if err != docker.ErrNoSuchContainer {
-			glog.Errorf("Could not look up container %s: %s", containerName, err)
+			plog.Errorf("Could not look up container %s: %s", containerName, err)
 			return nil, err
 		}
 
@@ -615,10 +984,10 @@ func (info *registryVersionInfo) start(isvcsRoot string, hostPort string) (*dock
 			},
 		}
 
-		glog.Infof("Creating container %s from image %s", containerDefinition.Name, containerDefinition.Config.Image)
+		plog.Infof("Creating container %s from image %s", containerDefinition.Name, containerDefinition.Config.Image)
 		container, err = docker.NewContainer(containerDefinition, false, 0, nil, nil)
 		if err != nil {
-			glog.Errorf("Error trying to create container %s: %s", containerDefinition.Name, err)
+			plog.Errorf("Error trying to create container %s: %s", containerDefinition.Name, err)
 			return nil, err
 		}
 	}
@@ -626,9 +995,9 @@ func (info *registryVersionInfo) start(isvcsRoot string, hostPort string) (*dock
 	os.MkdirAll(storagePath, 0755)
 
 	// Make sure container is running
-	glog.Infof("Starting container %s for Docker registry v%d at %s", container.Name, info.version, url)
+	plog.Infof("Starting container %s for Docker registry v%d at %s", container.Name, info.version, url)
 	if err = container.Start(); err != nil {
-		glog.Errorf("Could not start container %s: %s", container.Name, err)
+		plog.Errorf("Could not start container %s: %s", container.Name, err)
 		return nil, err
 	}
 
@@ -642,14 +1011,14 @@ func (info *registryVersionInfo) start(isvcsRoot string, hostPort string) (*dock
 		if err == nil {
 			break
 		} else {
-			glog.V(1).Infof("Waiting for Docker registry v%d to accept connections...", info.version)
+			plog.Debugf("Waiting for Docker registry v%d to accept connections...", info.version)
 		}
 
 		select {
 		case <-timeout:
-			glog.Warningf("Timed out waiting for Docker registry v%d to accept connections", info.version)
+			plog.Warningf("Timed out waiting for Docker registry v%d to accept connections", info.version)
 			if err := container.Stop(5 * time.Minute); err != nil {
-				glog.Errorf("After timeout, could not stop Docker registry v%d container %s: %s", info.version, container.Name, err)
+				plog.Errorf("After timeout, could not stop Docker registry v%d container %s: %s", info.version, container.Name, err)
 			}
 			return nil, errors.New(fmt.Sprintf("Timed out waiting for Docker registry v%d to accept connections", info.version))
 		case <-time.After(time.Second):
@@ -665,6 +1034,42 @@ func (f *Facade) DockerOverride(ctx datastore.Context, newImageName, oldImageNam
 	return f.dfs.Override(newImageName, oldImageName)
 }
 
+// PromoteImage copies an image in the docker registry into the library of
+// another tenant, e.g. to promote an image tested in a staging deployment to
+// production.  If tag is not set, the tag of the original image is used.
+// Returns the name of the new image in the registry.
+func (f *Facade) PromoteImage(ctx datastore.Context, image, tenantID, tag string) (string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("PromoteImage"))
+	tenantIDs, err := f.getTenantIDs(ctx)
+	if err != nil {
+		return "", err
+	}
+	found := false
+	for _, id := range tenantIDs {
+		if id == tenantID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("tenant %s not found", tenantID)
+	}
+
+	if err := f.DFSLock(ctx).LockWithTimeout("promote image", userLockTimeout); err != nil {
+		plog.Warningf("Cannot promote image %s: %s", image, err)
+		return "", err
+	}
+	defer f.DFSLock(ctx).Unlock()
+
+	newImage, err := f.dfs.CopyImage(image, tenantID, tag)
+	if err != nil {
+		plog.Errorf("Could not promote image %s to tenant %s: %s", image, tenantID, err)
+		return "", err
+	}
+	plog.Infof("Promoted image %s to %s for tenant %s", image, newImage, tenantID)
+	return newImage, nil
+}
+
 // Interface to allow filtering DFS clients
 type DfsClientValidator interface {
 	ValidateClient(string) bool
@@ -682,12 +1087,12 @@ func NewDfsClientValidator(fac *Facade, ctx datastore.Context) DfsClientValidato
 func (val *clientValidator) ValidateClient(hostIP string) bool {
 	host, err := val.facade.GetHostByIP(val.context, hostIP)
 	if err != nil || host == nil {
-		glog.Warningf("Unable to load host with ip %s", hostIP)
+		plog.Warningf("Unable to load host with ip %s", hostIP)
 		return false
 	}
 	pool, err := val.facade.GetResourcePool(val.context, host.PoolID)
 	if err != nil || pool == nil {
-		glog.Warningf("Unable to load pool %s", host.PoolID)
+		plog.Warningf("Unable to load pool %s", host.PoolID)
 		return false
 	}
 	return pool.HasDfsAccess()
@@ -325,3 +325,34 @@ func (fdrt *FacadeDfsRegistryTest) TestUpgradeRegistry_UpgradeRemoteForce(c *goc
 	c.Assert(err, gocheck.IsNil)
 	fdrt.verifyOldRegistryContainerExists(c, false)
 }
+
+func (fdrt *FacadeDfsRegistryTest) TestMigrateRegistry_Resume(c *gocheck.C) {
+	progressFile := filepath.Join(fdrt.getRegistryPath(c), migrateProgressFile)
+	if err := ioutil.WriteFile(progressFile, []byte(`["dfsrgysvc1/zenoss/testimage1"]`), 0644); err != nil {
+		c.Fatalf("Could not create progress file %s: %s", progressFile, err)
+	}
+	fdrt.dfs.On("UpgradeRegistry", mock.AnythingOfType("[]service.Service"), dfsRegistrySvcDefs[1].ID, getOldRegistryEndpoint(), false).Return(nil)
+
+	err := fdrt.Facade.MigrateRegistry(fdrt.CTX, 1, 2, false)
+
+	c.Assert(err, gocheck.IsNil)
+	fdrt.dfs.AssertNotCalled(c, "UpgradeRegistry", mock.AnythingOfType("[]service.Service"), dfsRegistrySvcDefs[0].ID, getOldRegistryEndpoint(), false)
+	_, err = os.Stat(filepath.Join(fdrt.getRegistryPath(c), upgradedMarkerFile))
+	c.Assert(err, gocheck.IsNil)
+	_, err = os.Stat(progressFile)
+	c.Assert(os.IsNotExist(err), gocheck.Equals, true)
+}
+
+func (fdrt *FacadeDfsRegistryTest) TestMigrateRegistry_PartialFailure(c *gocheck.C) {
+	fdrt.dfs.On("UpgradeRegistry", mock.AnythingOfType("[]service.Service"), dfsRegistrySvcDefs[0].ID, getOldRegistryEndpoint(), false).Return(fmt.Errorf("push failed"))
+	fdrt.dfs.On("UpgradeRegistry", mock.AnythingOfType("[]service.Service"), dfsRegistrySvcDefs[1].ID, getOldRegistryEndpoint(), false).Return(nil)
+
+	err := fdrt.Facade.MigrateRegistry(fdrt.CTX, 1, 2, false)
+
+	c.Assert(err, gocheck.NotNil)
+	_, err = os.Stat(filepath.Join(fdrt.getRegistryPath(c), upgradedMarkerFile))
+	c.Assert(os.IsNotExist(err), gocheck.Equals, true)
+	data, err := ioutil.ReadFile(filepath.Join(fdrt.getRegistryPath(c), migrateProgressFile))
+	c.Assert(err, gocheck.IsNil)
+	c.Assert(string(data), gocheck.Equals, `["dfsrgysvc2/zenoss/testimage2"]`)
+}
@@ -0,0 +1,165 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/coordinator/storage"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_FreezeDFS(c *C) {
+	ft.setupMockDFSLocking()
+	ft.zzk.On("SetDFSFreezeStatus", mock.AnythingOfType("storage.FreezeStatus")).Return(nil)
+
+	err := ft.Facade.FreezeDFS(ft.ctx, "maintenance")
+	c.Assert(err, IsNil)
+	status := ft.zzk.Calls[0].Arguments.Get(0).(storage.FreezeStatus)
+	c.Assert(status.Frozen, Equals, true)
+	c.Assert(status.Reason, Equals, "maintenance")
+}
+
+func (ft *FacadeUnitTest) Test_ThawDFS(c *C) {
+	ft.zzk.On("SetDFSFreezeStatus", mock.AnythingOfType("storage.FreezeStatus")).Return(nil)
+
+	err := ft.Facade.ThawDFS(ft.ctx)
+	c.Assert(err, IsNil)
+	status := ft.zzk.Calls[0].Arguments.Get(0).(storage.FreezeStatus)
+	c.Assert(status.Frozen, Equals, false)
+}
+
+func (ft *FacadeUnitTest) Test_DFSOperationsFailWhenFrozen(c *C) {
+	ft.zzk.On("GetDFSFreezeStatus").Return(&storage.FreezeStatus{Frozen: true, Reason: "maintenance"}, nil)
+
+	_, err := ft.Facade.Snapshot(ft.ctx, "serviceID", "message", nil, 0, dfs.SnapshotProvenance{})
+	c.Assert(err, Equals, storage.ErrDFSFrozen)
+	_, err = ft.Facade.Commit(ft.ctx, "containerID", "message", nil, 0, dfs.SnapshotProvenance{})
+	c.Assert(err, Equals, storage.ErrDFSFrozen)
+	err = ft.Facade.Rollback(ft.ctx, "snapshotID", false)
+	c.Assert(err, Equals, storage.ErrDFSFrozen)
+	err = ft.Facade.Restore(ft.ctx, nil, nil, nil)
+	c.Assert(err, Equals, storage.ErrDFSFrozen)
+	ft.dfs.AssertNotCalled(c, "Commit", mock.AnythingOfType("string"))
+}
+
+func (ft *FacadeUnitTest) Test_RestoreFailsCompatibilityCheck(c *C) {
+	ft.zzk.On("GetDFSFreezeStatus").Return(&storage.FreezeStatus{}, nil)
+	ft.templateStore.On("GetServiceTemplates", ft.ctx).Return([]*servicetemplate.ServiceTemplate{}, nil)
+	backupInfo := &dfs.BackupInfo{BackupVersion: 1, DockerVersion: "99.0.0"}
+	checkErr := &dfs.CompatibilityError{Issues: []dfs.CompatibilityIssue{{Check: dfs.CheckDockerVersion, Message: "too new"}}}
+	opts := dfs.RestoreOptions{Templates: []servicetemplate.ServiceTemplate{}, Force: []string{"driver-type"}}
+	ft.dfs.On("CheckRestore", *backupInfo, opts).Return(checkErr)
+
+	err := ft.Facade.Restore(ft.ctx, nil, backupInfo, []string{"driver-type"})
+	c.Assert(err, Equals, checkErr)
+	ft.dfs.AssertNotCalled(c, "Restore", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_EstimateBackup(c *C) {
+	templates := []*servicetemplate.ServiceTemplate{
+		{
+			ID: "template1",
+			Services: []servicedefinition.ServiceDefinition{
+				{Name: "svc", ImageID: "base:latest"},
+			},
+		},
+	}
+	ft.templateStore.On("GetServiceTemplates", ft.ctx).Return(templates, nil)
+	tenant := service.Service{
+		ID:             "tenant",
+		Volumes:        []servicedefinition.Volume{{ResourcePath: "cache", ExcludeFromBackups: true}},
+		BackupExcludes: []string{"tmp/*"},
+	}
+	ft.serviceStore.On("GetServices", ft.ctx).Return([]service.Service{tenant, {ID: "child", ParentServiceID: "tenant"}}, nil)
+	ft.serviceStore.On("Get", ft.ctx, "tenant").Return(&tenant, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, "tenant").Return([]service.Service{}, nil)
+	expected := &dfs.BackupEstimate{Size: 100}
+	ft.dfs.On("EstimateBackup", mock.AnythingOfType("dfs.BackupEstimateRequest")).Return(expected, nil)
+
+	estimate, err := ft.Facade.EstimateBackup(ft.ctx, []string{"logs"})
+	c.Assert(err, IsNil)
+	c.Assert(estimate, Equals, expected)
+	req := ft.dfs.Calls[0].Arguments.Get(0).(dfs.BackupEstimateRequest)
+	c.Assert(req.BaseImages, DeepEquals, []string{"base:latest"})
+	c.Assert(req.Tenants, DeepEquals, []string{"tenant"})
+	c.Assert(req.Excludes, DeepEquals, map[string][]string{"tenant": {"logs", "cache", "tmp/*"}})
+}
+
+func (ft *FacadeUnitTest) Test_CleanupFailedRestore(c *C) {
+	ft.setupMockDFSLocking()
+	ft.zzk.On("GetDFSFreezeStatus").Return(&storage.FreezeStatus{}, nil)
+	ft.serviceStore.On("GetServices", ft.ctx).Return([]service.Service{{ID: "tenant"}}, nil)
+	ft.dfs.On("RestoreQuarantine").Return(&dfs.RestoreQuarantine{Volumes: []string{"restored"}}, nil)
+	expected := &dfs.RestoreQuarantine{Volumes: []string{"restored"}}
+	ft.dfs.On("CleanupFailedRestore").Return(expected, nil)
+
+	removed, err := ft.Facade.CleanupFailedRestore(ft.ctx, false)
+	c.Assert(err, IsNil)
+	c.Assert(removed, Equals, expected)
+}
+
+func (ft *FacadeUnitTest) Test_CleanupFailedRestoreDryRun(c *C) {
+	expected := &dfs.RestoreQuarantine{Volumes: []string{"restored"}}
+	ft.dfs.On("RestoreQuarantine").Return(expected, nil)
+
+	quarantine, err := ft.Facade.CleanupFailedRestore(ft.ctx, true)
+	c.Assert(err, IsNil)
+	c.Assert(quarantine, Equals, expected)
+	ft.dfs.AssertNotCalled(c, "CleanupFailedRestore")
+}
+
+func (ft *FacadeUnitTest) Test_CleanupFailedRestoreTenantInUse(c *C) {
+	ft.setupMockDFSLocking()
+	ft.zzk.On("GetDFSFreezeStatus").Return(&storage.FreezeStatus{}, nil)
+	ft.serviceStore.On("GetServices", ft.ctx).Return([]service.Service{{ID: "tenant"}}, nil)
+	ft.dfs.On("RestoreQuarantine").Return(&dfs.RestoreQuarantine{Volumes: []string{"tenant"}}, nil)
+
+	_, err := ft.Facade.CleanupFailedRestore(ft.ctx, false)
+	c.Assert(err, Equals, facade.ErrQuarantinedTenantInUse)
+	ft.dfs.AssertNotCalled(c, "CleanupFailedRestore")
+}
+
+func (ft *FacadeUnitTest) Test_MigrateRegistryInvalidVersion(c *C) {
+	err := ft.Facade.MigrateRegistry(ft.ctx, 1, 3, false)
+	c.Assert(err, ErrorMatches, "cannot migrate to registry v3; only v2 is supported")
+	err = ft.Facade.MigrateRegistry(ft.ctx, 2, 2, false)
+	c.Assert(err, ErrorMatches, "cannot migrate from registry v2 to v2")
+	err = ft.Facade.MigrateRegistry(ft.ctx, 7, 0, false)
+	c.Assert(err, ErrorMatches, "cannot migrate from registry v7 to v2")
+}
+
+func (ft *FacadeUnitTest) Test_PromoteImage(c *C) {
+	ft.setupMockDFSLocking()
+	ft.serviceStore.On("GetServices", ft.ctx).Return([]service.Service{{ID: "staging"}, {ID: "production"}, {ID: "child", ParentServiceID: "staging"}}, nil)
+	ft.dfs.On("CopyImage", "staging/repo:latest", "production", "").Return("production/repo:latest", nil)
+
+	image, err := ft.Facade.PromoteImage(ft.ctx, "staging/repo:latest", "production", "")
+	c.Assert(err, IsNil)
+	c.Assert(image, Equals, "production/repo:latest")
+}
+
+func (ft *FacadeUnitTest) Test_PromoteImageTenantNotFound(c *C) {
+	ft.serviceStore.On("GetServices", ft.ctx).Return([]service.Service{{ID: "staging"}, {ID: "child", ParentServiceID: "staging"}}, nil)
+
+	_, err := ft.Facade.PromoteImage(ft.ctx, "staging/repo:latest", "child", "")
+	c.Assert(err, ErrorMatches, "tenant child not found")
+	ft.dfs.AssertNotCalled(c, "CopyImage", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,260 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/statehistory"
+)
+
+// emergencyStopTimeout is how long an emergency shutdown waits for the
+// services at one level to stop before it moves on to the next level
+const emergencyStopTimeout = 2 * time.Minute
+
+var (
+	// ErrEmergencyShutdown is returned when starting a service that was
+	// stopped because storage ran low
+	ErrEmergencyShutdown = errors.New("facade: service was stopped by an emergency shutdown; clear the emergency flag to start it")
+
+	// ErrStorageLow is returned when clearing the emergency flag of a service
+	// while storage is still below its minimum free space
+	ErrStorageLow = errors.New("facade: storage is below its minimum free space")
+)
+
+// lowStorage is storage on a host that is below its minimum free space
+type lowStorage struct {
+	HostID string
+	PoolID string
+	Usage  *host.StorageUsage
+}
+
+// affects returns true if the low storage affects the services in a pool.
+// The docker thin pool of a host only affects the pool of the host, but the
+// storage backing the application volumes is shared by every pool.
+func (l lowStorage) affects(poolID string) bool {
+	return l.Usage.IsDFS() || l.PoolID == poolID
+}
+
+// getLowStorage returns the storage on every host that is below the minimum
+// free space.
+func (f *Facade) getLowStorage(ctx datastore.Context) ([]lowStorage, error) {
+	if f.minFreeDFS == 0 && f.minFreeThinPool == 0 {
+		return nil, nil
+	}
+	hosts, err := f.GetHosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var lows []lowStorage
+	for i := range hosts {
+		storage, err := f.getHostStorage(&hosts[i])
+		if err != nil {
+			log.WithField("hostid", hosts[i].ID).WithError(err).Warn("Unable to look up storage of host")
+			continue
+		} else if storage == nil {
+			continue
+		}
+		// check each threshold on its own, so that a low thin pool does not
+		// hide low application storage that affects more pools
+		for _, low := range []*host.StorageUsage{
+			storage.BelowMinFree(f.minFreeDFS, 0),
+			storage.BelowMinFree(0, f.minFreeThinPool),
+		} {
+			if low != nil {
+				lows = append(lows, lowStorage{HostID: hosts[i].ID, PoolID: hosts[i].PoolID, Usage: low})
+			}
+		}
+	}
+	return lows, nil
+}
+
+// CheckStorageEmergency stops the services affected by storage that is below
+// its minimum free space, and returns the number of services it stopped.  Low
+// docker storage stops the services in the pool of its host, and low
+// application storage stops the services in every pool.  Services are stopped
+// in order of their emergency shutdown level and priority, and are flagged so
+// that they cannot be started until the flag is cleared.
+func (f *Facade) CheckStorageEmergency(ctx datastore.Context) (int, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CheckStorageEmergency"))
+	lows, err := f.getLowStorage(ctx)
+	if err != nil || len(lows) == 0 {
+		return 0, err
+	}
+
+	svcs, err := f.serviceStore.GetServices(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var affected []service.Service
+	for _, svc := range svcs {
+		for _, low := range lows {
+			if low.affects(svc.PoolID) {
+				affected = append(affected, svc)
+				break
+			}
+		}
+	}
+	levels := emergencyShutdownLevels(affected)
+	if len(levels) == 0 {
+		return 0, nil
+	}
+	for _, low := range lows {
+		log.WithFields(log.Fields{
+			"hostid":    low.HostID,
+			"poolid":    low.PoolID,
+			"storage":   low.Usage.Name,
+			"available": low.Usage.Available,
+		}).Error("Storage is below its minimum free space; stopping the affected services")
+	}
+
+	stopped := 0
+	for _, level := range levels {
+		var serviceIDs []string
+		for _, svc := range level {
+			if err := f.emergencyStopService(ctx, svc.ID); err != nil {
+				log.WithField("serviceid", svc.ID).WithError(err).Error("Unable to stop service")
+				continue
+			}
+			serviceIDs = append(serviceIDs, svc.ID)
+		}
+		stopped += len(serviceIDs)
+		if len(serviceIDs) == 0 {
+			continue
+		}
+		if err := f.WaitService(ctx, service.SVCStop, emergencyStopTimeout, false, serviceIDs...); err != nil {
+			log.WithField("level", level[0].EmergencyShutdownLevel).WithError(err).Warn("Services did not stop; continuing the emergency shutdown")
+		}
+	}
+	return stopped, nil
+}
+
+// emergencyShutdownLevels groups the services that are not stopped in the
+// order they are stopped: by their emergency shutdown level, and within each
+// level from the lowest to the highest priority.
+func emergencyShutdownLevels(svcs []service.Service) [][]service.Service {
+	var running []service.Service
+	for _, svc := range svcs {
+		if svc.DesiredState != int(service.SVCStop) {
+			running = append(running, svc)
+		}
+	}
+	sort.Stable(emergencyShutdownOrder(running))
+
+	var levels [][]service.Service
+	for i, svc := range running {
+		if i == 0 || emergencyShutdownOrder(running).Less(i-1, i) {
+			levels = append(levels, nil)
+		}
+		levels[len(levels)-1] = append(levels[len(levels)-1], svc)
+	}
+	return levels
+}
+
+// emergencyShutdownOrder sorts services in the order they are stopped by an
+// emergency shutdown
+type emergencyShutdownOrder []service.Service
+
+func (o emergencyShutdownOrder) Len() int      { return len(o) }
+func (o emergencyShutdownOrder) Swap(i, j int) { o[i], o[j] = o[j], o[i] }
+func (o emergencyShutdownOrder) Less(i, j int) bool {
+	// level 0 is the default and is stopped last
+	li, lj := o[i].EmergencyShutdownLevel, o[j].EmergencyShutdownLevel
+	if li != lj {
+		return lj == 0 || (li != 0 && li < lj)
+	}
+	return o[i].PriorityRank() > o[j].PriorityRank()
+}
+
+// emergencyStopService flags a service as emergency stopped and schedules it
+// to stop.
+func (f *Facade) emergencyStopService(ctx datastore.Context, serviceID string) error {
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	mutex := getTenantLock(tenantID)
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	svc, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	svc.EmergencyShutdown = true
+	svc.UpdatedAt = time.Now()
+	if err := f.serviceStore.Put(ctx, svc); err != nil {
+		return err
+	}
+	ctx = datastore.WithActor(ctx, statehistory.SourceEmergency, "")
+	return f.scheduleOneService(ctx, tenantID, svc, service.SVCStop)
+}
+
+// ClearEmergencyShutdown clears the emergency flag of a service and its
+// children so that they can be started again.  The flag cannot be cleared
+// while storage that affects the pool of any of the services is below its
+// minimum free space.  Returns the number of services that were cleared.
+func (f *Facade) ClearEmergencyShutdown(ctx datastore.Context, serviceID string) (int, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ClearEmergencyShutdown"))
+	lows, err := f.getLowStorage(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		return 0, err
+	}
+	mutex := getTenantLock(tenantID)
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	svcs := []service.Service{}
+	visitor := func(svc *service.Service) error {
+		if svc.EmergencyShutdown {
+			svcs = append(svcs, *svc)
+		}
+		return nil
+	}
+	if err := f.walkServices(ctx, serviceID, true, visitor, "ClearEmergencyShutdown"); err != nil {
+		return 0, err
+	}
+
+	for _, svc := range svcs {
+		for _, low := range lows {
+			if low.affects(svc.PoolID) {
+				return 0, fmt.Errorf("%s: %s on host %s has %d bytes available", ErrStorageLow, low.Usage.Name, low.HostID, low.Usage.Available)
+			}
+		}
+	}
+
+	cleared := 0
+	for _, svc := range svcs {
+		svc.EmergencyShutdown = false
+		svc.UpdatedAt = time.Now()
+		if err := f.serviceStore.Put(ctx, &svc); err != nil {
+			return cleared, err
+		}
+		log.WithField("serviceid", svc.ID).Info("Cleared emergency shutdown of service")
+		cleared++
+	}
+	return cleared, nil
+}
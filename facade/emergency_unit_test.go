@@ -0,0 +1,209 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/domain/statehistory"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupEmergencyStorage(name string, available uint64) {
+	h := host.Host{ID: "emergencyhost", PoolID: "default"}
+	ft.hostStore.On("GetN", ft.ctx, uint64(10000)).Return([]host.Host{h}, nil)
+	ft.zzk.On("GetHostStorageHealth", h.PoolID, h.ID).Return(&host.StorageHealth{
+		Usage: []host.StorageUsage{
+			{Name: name, Total: 100, Used: 100 - available, Available: available},
+		},
+	}, nil)
+}
+
+// setupEmergencyStop records the services that are stopped and flagged by an
+// emergency shutdown of the tenant.
+func (ft *FacadeUnitTest) setupEmergencyStop(c *C, tenantID string, svcs []service.Service) (stopped *[]string, flagged map[string]bool) {
+	ft.serviceStore.On("GetServices", ft.ctx).Return(svcs, nil)
+	for i := range svcs {
+		svc := svcs[i]
+		ft.serviceStore.On("Get", ft.ctx, svc.ID).Return(&svc, nil)
+	}
+	ft.configStore.On("GetConfigFiles", ft.ctx, tenantID, mock.AnythingOfType("string")).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+
+	flagged = make(map[string]bool)
+	ft.serviceStore.On("Put", ft.ctx, mock.AnythingOfType("*service.Service")).Return(nil).Run(func(args mock.Arguments) {
+		svc := args.Get(1).(*service.Service)
+		flagged[svc.ID] = svc.EmergencyShutdown
+	})
+	// the services are stopped on behalf of the emergency shutdown
+	emergencyCtx := mock.MatchedBy(func(ctx datastore.Context) bool {
+		return datastore.GetActor(ctx).Source == statehistory.SourceEmergency
+	})
+	ft.serviceStore.On("UpdateDesiredState", emergencyCtx, mock.AnythingOfType("string"), int(service.SVCStop)).Return(nil)
+
+	stopped = &[]string{}
+	ft.zzk.On("UpdateService", emergencyCtx, tenantID, mock.AnythingOfType("*service.Service"), false, false).Return(nil).Run(func(args mock.Arguments) {
+		svc := args.Get(2).(*service.Service)
+		c.Assert(svc.DesiredState, Equals, int(service.SVCStop))
+		*stopped = append(*stopped, svc.ID)
+	})
+	ft.zzk.On("WaitService", mock.AnythingOfType("*service.Service"), service.SVCStop, mock.Anything).Return(nil)
+	return stopped, flagged
+}
+
+func (ft *FacadeUnitTest) Test_CheckStorageEmergency_Disabled(c *C) {
+	// the facade is shared by the suite, so reset thresholds set by other tests
+	ft.Facade.SetEmergencyThresholds(0, 0)
+	count, err := ft.Facade.CheckStorageEmergency(ft.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 0)
+	ft.hostStore.AssertNotCalled(c, "GetN", ft.ctx, uint64(10000))
+}
+
+func (ft *FacadeUnitTest) Test_CheckStorageEmergency_StorageOK(c *C) {
+	ft.setupEmergencyStorage("docker thin pool data", 50)
+	ft.Facade.SetEmergencyThresholds(0, 10)
+
+	count, err := ft.Facade.CheckStorageEmergency(ft.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 0)
+	ft.serviceStore.AssertNotCalled(c, "GetServices", ft.ctx)
+}
+
+func (ft *FacadeUnitTest) Test_CheckStorageEmergency_StopsInOrder(c *C) {
+	ft.setupEmergencyStorage("docker thin pool data", 5)
+	ft.Facade.SetEmergencyThresholds(0, 10)
+
+	// the docker thin pool of the host only affects the services in its pool
+	svcs := []service.Service{
+		{ID: "tenant", PoolID: "default", DesiredState: int(service.SVCRun)},
+		{ID: "db", PoolID: "default", ParentServiceID: "tenant", DesiredState: int(service.SVCRun), EmergencyShutdownLevel: 2, Priority: service.PriorityHigh},
+		{ID: "cache", PoolID: "default", ParentServiceID: "tenant", DesiredState: int(service.SVCRun), EmergencyShutdownLevel: 2, Priority: service.PriorityLow},
+		{ID: "web", PoolID: "default", ParentServiceID: "tenant", DesiredState: int(service.SVCPause), EmergencyShutdownLevel: 1},
+		{ID: "stopped", PoolID: "default", ParentServiceID: "tenant", DesiredState: int(service.SVCStop), EmergencyShutdownLevel: 1},
+		{ID: "remote", PoolID: "other", ParentServiceID: "tenant", DesiredState: int(service.SVCRun), EmergencyShutdownLevel: 1},
+	}
+	stopped, flagged := ft.setupEmergencyStop(c, "tenant", svcs)
+
+	count, err := ft.Facade.CheckStorageEmergency(ft.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 4)
+	c.Assert(*stopped, DeepEquals, []string{"web", "cache", "db", "tenant"})
+	c.Assert(flagged, DeepEquals, map[string]bool{"web": true, "cache": true, "db": true, "tenant": true})
+	for _, call := range ft.historyStore.Calls {
+		change := call.Arguments.Get(2).(statehistory.Change)
+		c.Assert(change.Source, Equals, statehistory.SourceEmergency)
+		c.Assert(change.To, Equals, service.SVCStop)
+	}
+	c.Assert(ft.historyStore.Calls, HasLen, 4)
+}
+
+func (ft *FacadeUnitTest) Test_CheckStorageEmergency_ApplicationStorage(c *C) {
+	ft.setupEmergencyStorage("application thin pool data", 5)
+	ft.Facade.SetEmergencyThresholds(0, 10)
+
+	// the application storage is shared, so it affects the services in every
+	// pool
+	svcs := []service.Service{
+		{ID: "tenant", PoolID: "default", DesiredState: int(service.SVCRun)},
+		{ID: "remote", PoolID: "other", ParentServiceID: "tenant", DesiredState: int(service.SVCRun), EmergencyShutdownLevel: 1},
+	}
+	stopped, flagged := ft.setupEmergencyStop(c, "tenant", svcs)
+
+	count, err := ft.Facade.CheckStorageEmergency(ft.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 2)
+	c.Assert(*stopped, DeepEquals, []string{"remote", "tenant"})
+	c.Assert(flagged, DeepEquals, map[string]bool{"remote": true, "tenant": true})
+}
+
+func (ft *FacadeUnitTest) Test_ClearEmergencyShutdown_StorageLow(c *C) {
+	ft.setupEmergencyStorage("docker thin pool data", 5)
+	ft.Facade.SetEmergencyThresholds(0, 10)
+
+	tenant := service.Service{ID: "tenant", PoolID: "default", EmergencyShutdown: true}
+	ft.serviceStore.On("Get", ft.ctx, tenant.ID).Return(&tenant, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, tenant.ID).Return([]service.Service{}, nil)
+
+	count, err := ft.Facade.ClearEmergencyShutdown(ft.ctx, tenant.ID)
+	c.Assert(err, ErrorMatches, facade.ErrStorageLow.Error()+".*")
+	c.Assert(count, Equals, 0)
+	ft.serviceStore.AssertNotCalled(c, "Put", ft.ctx, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_ClearEmergencyShutdown_OtherPoolStorageLow(c *C) {
+	ft.setupEmergencyStorage("docker thin pool data", 5)
+	ft.Facade.SetEmergencyThresholds(0, 10)
+
+	// the low docker thin pool is on a host in another pool
+	tenant := service.Service{ID: "tenant", PoolID: "other", EmergencyShutdown: true}
+	ft.serviceStore.On("Get", ft.ctx, tenant.ID).Return(&tenant, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, tenant.ID).Return([]service.Service{}, nil)
+	ft.serviceStore.On("Put", ft.ctx, mock.AnythingOfType("*service.Service")).Return(nil)
+
+	count, err := ft.Facade.ClearEmergencyShutdown(ft.ctx, tenant.ID)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 1)
+}
+
+func (ft *FacadeUnitTest) Test_ClearEmergencyShutdown(c *C) {
+	ft.setupEmergencyStorage("docker thin pool data", 50)
+	ft.Facade.SetEmergencyThresholds(0, 10)
+
+	tenant := service.Service{ID: "tenant", PoolID: "default", EmergencyShutdown: true}
+	child := service.Service{ID: "child", PoolID: "default", ParentServiceID: "tenant"}
+	ft.serviceStore.On("Get", ft.ctx, tenant.ID).Return(&tenant, nil)
+	ft.serviceStore.On("Get", ft.ctx, child.ID).Return(&child, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, tenant.ID).Return([]service.Service{child}, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, child.ID).Return([]service.Service{}, nil)
+	ft.serviceStore.On("Put", ft.ctx, mock.AnythingOfType("*service.Service")).Return(nil).Run(func(args mock.Arguments) {
+		svc := args.Get(1).(*service.Service)
+		c.Assert(svc.ID, Equals, tenant.ID)
+		c.Assert(svc.EmergencyShutdown, Equals, false)
+	})
+
+	count, err := ft.Facade.ClearEmergencyShutdown(ft.ctx, tenant.ID)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 1)
+}
+
+func (ft *FacadeUnitTest) Test_ClearEmergencyShutdown_CanceledWhileVisiting(c *C) {
+	ft.Facade.SetEmergencyThresholds(0, 10)
+	ft.ctx.On("Done").Return(nil)
+	ctx, cancel := datastore.WithCancel(ft.ctx)
+	defer cancel()
+
+	h := host.Host{ID: "emergencyhost", PoolID: "default"}
+	ft.hostStore.On("GetN", ctx, uint64(10000)).Return([]host.Host{h}, nil)
+	ft.zzk.On("GetHostStorageHealth", h.PoolID, h.ID).Return(&host.StorageHealth{}, nil)
+
+	// the tree is loaded before it is visited, so canceling while visiting
+	// the first service does not stop the walk part way through the tree
+	tenant := service.Service{ID: "tenant", PoolID: "default", EmergencyShutdown: true}
+	child := service.Service{ID: "child", PoolID: "default", ParentServiceID: "tenant", EmergencyShutdown: true}
+	ft.serviceStore.On("Get", ctx, tenant.ID).Return(&tenant, nil)
+	ft.serviceStore.On("Get", ctx, child.ID).Return(&child, nil).Run(func(mock.Arguments) { cancel() })
+	ft.serviceStore.On("GetChildServices", ctx, tenant.ID).Return([]service.Service{child}, nil)
+	ft.serviceStore.On("GetChildServices", ctx, child.ID).Return([]service.Service{}, nil)
+	ft.serviceStore.On("Put", ctx, mock.AnythingOfType("*service.Service")).Return(nil)
+
+	count, err := ft.Facade.ClearEmergencyShutdown(ctx, tenant.ID)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 2)
+}
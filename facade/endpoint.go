@@ -0,0 +1,259 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/proxy"
+	zkr "github.com/control-center/serviced/zzk/registry"
+)
+
+// endpointProbeTimeout is how long an agent waits to connect to an endpoint
+// while verifying it
+var endpointProbeTimeout = 5 * time.Second
+
+// endpointVerifier runs the verification checks against the endpoints of a
+// single service.  Hosts are looked up once and shared between checks.
+type endpointVerifier struct {
+	f       *Facade
+	ctx     datastore.Context
+	exports map[string][]zkr.ExportDetails
+	hosts   map[string]*host.Host
+}
+
+// verifyEndpoints checks that the exports of each running instance are
+// listening and registered, and that each import resolves to an export that
+// can be reached through the mux.  The results are recorded on the reports.
+func (f *Facade) verifyEndpoints(ctx datastore.Context, tenantID string, reports []applicationendpoint.EndpointReport) error {
+	logger := plog.WithField("tenantid", tenantID)
+
+	running := false
+	for _, report := range reports {
+		if report.Endpoint.ContainerID != "" {
+			running = true
+			break
+		}
+	}
+	if !running {
+		logger.Debug("No running instances to verify")
+		return nil
+	}
+
+	exports, err := f.zzk.GetTenantExports(tenantID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up exports for tenant")
+		return err
+	}
+
+	v := &endpointVerifier{f: f, ctx: ctx, exports: exports, hosts: make(map[string]*host.Host)}
+	for i := range reports {
+		r := &reports[i]
+		if r.Endpoint.ContainerID == "" {
+			continue
+		}
+		if strings.HasPrefix(r.Endpoint.Purpose, "import") {
+			v.verifyImport(r)
+		} else {
+			v.verifyExport(r)
+		}
+	}
+	return nil
+}
+
+// verifyExport checks that the exported port is registered and that the
+// container is listening on it.
+func (v *endpointVerifier) verifyExport(r *applicationendpoint.EndpointReport) {
+	ep := r.Endpoint
+
+	registered := false
+	for _, export := range v.exports[ep.Application] {
+		if export.InstanceID == ep.InstanceID && export.PrivateIP == ep.ContainerIP {
+			registered = true
+			break
+		}
+	}
+	if registered {
+		r.AddCheck("registered", applicationendpoint.CheckOK, "")
+	} else {
+		r.AddCheck("registered", applicationendpoint.CheckFailed, "export is not registered")
+	}
+
+	target := fmt.Sprintf("%s:%d", ep.ContainerIP, ep.ContainerPort)
+	v.probe(r, "listening", ep.HostID, target, "")
+}
+
+// verifyImport checks that the import matches at least one export and that
+// each matching export can be reached from the importing host.
+func (v *endpointVerifier) verifyImport(r *applicationendpoint.EndpointReport) {
+	ep := r.Endpoint
+
+	rgx, err := regexp.Compile(fmt.Sprintf("^%s$", ep.Application))
+	if err != nil {
+		r.AddCheck("resolves", applicationendpoint.CheckFailed, fmt.Sprintf("invalid application %s: %s", ep.Application, err))
+		return
+	}
+
+	var matches []zkr.ExportDetails
+	for app, exports := range v.exports {
+		if rgx.MatchString(app) {
+			matches = append(matches, exports...)
+		}
+	}
+	if len(matches) == 0 {
+		r.AddCheck("resolves", applicationendpoint.CheckFailed, "no exports match the import")
+		return
+	}
+	r.AddCheck("resolves", applicationendpoint.CheckOK, fmt.Sprintf("%d export(s)", len(matches)))
+
+	importHost, err := v.getHost(ep.HostID)
+	if err != nil {
+		r.AddCheck("mux", applicationendpoint.CheckWarning, fmt.Sprintf("could not look up host %s: %s", ep.HostID, err))
+		return
+	}
+	for _, export := range matches {
+		target := fmt.Sprintf("%s:%d", export.PrivateIP, export.PortNumber)
+
+		// proxies connect to exports on the same host directly
+		muxAddress := ""
+		if export.HostIP != importHost.IPAddr {
+			muxAddress = fmt.Sprintf("%s:%d", export.HostIP, export.MuxPort)
+		}
+		v.probe(r, "mux", ep.HostID, target, muxAddress)
+	}
+}
+
+// probe connects to the target from the agent on the given host and records
+// the outcome as a check.
+func (v *endpointVerifier) probe(r *applicationendpoint.EndpointReport, name, hostID, target, muxAddress string) {
+	logger := plog.WithFields(log.Fields{
+		"hostid":     hostID,
+		"target":     target,
+		"muxaddress": muxAddress,
+	})
+
+	if v.f.delegates == nil {
+		r.AddCheck(name, applicationendpoint.CheckWarning, "endpoint probes are not supported")
+		return
+	}
+	h, err := v.getHost(hostID)
+	if err != nil {
+		r.AddCheck(name, applicationendpoint.CheckWarning, fmt.Sprintf("could not look up host %s: %s", hostID, err))
+		return
+	}
+	address := fmt.Sprintf("%s:%d", h.IPAddr, h.RPCPort)
+	if err := v.f.delegates.ProbeEndpoint(address, target, muxAddress, endpointProbeTimeout); err != nil {
+		logger.WithError(err).Debug("Endpoint probe failed")
+		r.AddCheck(name, applicationendpoint.CheckFailed, fmt.Sprintf("could not connect to %s: %s", target, err))
+		return
+	}
+	r.AddCheck(name, applicationendpoint.CheckOK, target)
+}
+
+// getHost returns the host with the given id
+func (v *endpointVerifier) getHost(hostID string) (*host.Host, error) {
+	if h, ok := v.hosts[hostID]; ok {
+		return h, nil
+	}
+	h, err := v.f.GetHost(v.ctx, hostID)
+	if err != nil {
+		return nil, err
+	} else if h == nil {
+		return nil, fmt.Errorf("host not found")
+	}
+	v.hosts[hostID] = h
+	return h, nil
+}
+
+// GetServiceEndpointStats returns the connections the mux has forwarded to
+// each exported endpoint of the running instances of a service.  Stats are
+// collected from the agent on each host running an instance; if a host cannot
+// be reached, the error is reported on its endpoints.
+func (f *Facade) GetServiceEndpointStats(ctx datastore.Context, serviceID string) ([]applicationendpoint.EndpointStats, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetServiceEndpointStats"))
+	logger := plog.WithField("serviceid", serviceID)
+
+	if f.delegates == nil {
+		return nil, ErrNoDelegateClient
+	}
+
+	svc, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up service")
+		return nil, err
+	}
+	states, err := f.zzk.GetServiceStates(svc.PoolID, svc.ID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up service states")
+		return nil, err
+	}
+
+	type hostStats struct {
+		stats map[string]proxy.ConnectionStats
+		err   error
+	}
+	hosts := make(map[string]hostStats)
+
+	result := []applicationendpoint.EndpointStats{}
+	for _, state := range states {
+		if state.ContainerID == "" {
+			continue
+		}
+
+		hs, ok := hosts[state.HostID]
+		if !ok {
+			if h, err := f.GetHost(ctx, state.HostID); err != nil {
+				hs.err = err
+			} else if h == nil {
+				hs.err = fmt.Errorf("host %s not found", state.HostID)
+			} else {
+				hs.stats, hs.err = f.delegates.GetMuxStats(fmt.Sprintf("%s:%d", h.IPAddr, h.RPCPort))
+			}
+			if hs.err != nil {
+				logger.WithError(hs.err).WithField("hostid", state.HostID).Debug("Could not get mux stats from host")
+			}
+			hosts[state.HostID] = hs
+		}
+
+		for _, export := range state.Exports {
+			s := applicationendpoint.EndpointStats{
+				ServiceID:     state.ServiceID,
+				InstanceID:    state.InstanceID,
+				Application:   export.Application,
+				HostID:        state.HostID,
+				ContainerIP:   state.PrivateIP,
+				ContainerPort: export.PortNumber,
+			}
+			if hs.err != nil {
+				s.Error = hs.err.Error()
+			} else {
+				cs := hs.stats[fmt.Sprintf("%s:%d", state.PrivateIP, export.PortNumber)]
+				s.ActiveConnections = cs.Active
+				s.TotalConnections = cs.Total
+				s.ConnectErrors = cs.ConnectErrors
+				s.BytesReceived = cs.BytesReceived
+				s.BytesSent = cs.BytesSent
+			}
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/proxy"
+	zkr "github.com/control-center/serviced/zzk/registry"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupVerifyEndpoints(serviceID string, state zkservice.State) {
+	svc := service.Service{ID: serviceID, PoolID: "default"}
+	ft.serviceStore.On("Get", ft.ctx, serviceID).Return(&svc, nil)
+	ft.configStore.On("GetConfigFiles", ft.ctx, serviceID, "/"+serviceID).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+	ft.zzk.On("GetServiceStates", "default", serviceID).Return([]zkservice.State{state}, nil)
+
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("host1"), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = host.Host{ID: "host1", IPAddr: "10.0.0.1", RPCPort: 4979}
+		})
+}
+
+func (ft *FacadeUnitTest) Test_GetServiceEndpoints_Verify(c *C) {
+	state := zkservice.State{HostID: "host1", ServiceID: "verifysvc", InstanceID: 0}
+	state.ContainerID = "containerID"
+	state.PrivateIP = "172.17.0.2"
+	state.HostIP = "10.0.0.1"
+	state.Exports = []zkservice.ExportBinding{{Application: "app", PortNumber: 8080}}
+	state.Imports = []zkservice.ImportBinding{{Application: "db.*", Purpose: "import", PortNumber: 3306}}
+	ft.setupVerifyEndpoints("verifysvc", state)
+
+	ft.zzk.On("GetTenantExports", "verifysvc").Return(map[string][]zkr.ExportDetails{
+		"app": {{
+			ExportBinding: zkservice.ExportBinding{Application: "app", PortNumber: 8080},
+			PrivateIP:     "172.17.0.2",
+			HostIP:        "10.0.0.1",
+			MuxPort:       22250,
+			InstanceID:    0,
+		}},
+		"db1": {{
+			ExportBinding: zkservice.ExportBinding{Application: "db1", PortNumber: 3306},
+			PrivateIP:     "172.17.0.3",
+			HostIP:        "10.0.0.2",
+			MuxPort:       22250,
+			InstanceID:    0,
+		}},
+	}, nil)
+	ft.delegates.On("ProbeEndpoint", "10.0.0.1:4979", "172.17.0.2:8080", "", mock.AnythingOfType("time.Duration")).Return(nil)
+	ft.delegates.On("ProbeEndpoint", "10.0.0.1:4979", "172.17.0.3:3306", "10.0.0.2:22250", mock.AnythingOfType("time.Duration")).Return(errors.New("connection refused"))
+
+	reports, err := ft.Facade.GetServiceEndpoints(ft.ctx, "verifysvc", true, true, true)
+	c.Assert(err, IsNil)
+	c.Assert(reports, HasLen, 2)
+
+	export, imp := reports[0], reports[1]
+	if export.Endpoint.Purpose != "export" {
+		export, imp = imp, export
+	}
+	c.Check(export.Status(), Equals, applicationendpoint.CheckOK)
+	c.Check(export.Checks, HasLen, 2)
+	c.Check(export.Messages, HasLen, 0)
+
+	c.Check(imp.Status(), Equals, applicationendpoint.CheckFailed)
+	c.Assert(imp.Checks, HasLen, 2)
+	c.Check(imp.Checks[0].Name, Equals, "resolves")
+	c.Check(imp.Checks[0].Status, Equals, applicationendpoint.CheckOK)
+	c.Check(imp.Checks[1].Name, Equals, "mux")
+	c.Check(imp.Checks[1].Status, Equals, applicationendpoint.CheckFailed)
+	c.Check(imp.Messages, HasLen, 1)
+}
+
+func (ft *FacadeUnitTest) Test_GetServiceEndpoints_VerifyUnresolved(c *C) {
+	state := zkservice.State{HostID: "host1", ServiceID: "unresolvedsvc", InstanceID: 0}
+	state.ContainerID = "containerID"
+	state.PrivateIP = "172.17.0.2"
+	state.HostIP = "10.0.0.1"
+	state.Exports = []zkservice.ExportBinding{{Application: "app", PortNumber: 8080}}
+	state.Imports = []zkservice.ImportBinding{{Application: "db", Purpose: "import", PortNumber: 3306}}
+	ft.setupVerifyEndpoints("unresolvedsvc", state)
+
+	ft.zzk.On("GetTenantExports", "unresolvedsvc").Return(map[string][]zkr.ExportDetails{}, nil)
+	ft.delegates.On("ProbeEndpoint", "10.0.0.1:4979", "172.17.0.2:8080", "", mock.AnythingOfType("time.Duration")).Return(errors.New("connection refused"))
+
+	reports, err := ft.Facade.GetServiceEndpoints(ft.ctx, "unresolvedsvc", true, true, true)
+	c.Assert(err, IsNil)
+	c.Assert(reports, HasLen, 2)
+	for _, report := range reports {
+		c.Check(report.Status(), Equals, applicationendpoint.CheckFailed)
+		if report.Endpoint.Purpose == "export" {
+			c.Check(report.Messages, HasLen, 2)
+		} else {
+			c.Check(report.Messages, DeepEquals, []string{"resolves: no exports match the import"})
+		}
+	}
+}
+
+func (ft *FacadeUnitTest) Test_GetServiceEndpoints_VerifyNotRunning(c *C) {
+	state := zkservice.State{HostID: "host1", ServiceID: "stoppedsvc", InstanceID: 0}
+	state.Exports = []zkservice.ExportBinding{{Application: "app", PortNumber: 8080}}
+	ft.setupVerifyEndpoints("stoppedsvc", state)
+
+	reports, err := ft.Facade.GetServiceEndpoints(ft.ctx, "stoppedsvc", true, true, true)
+	c.Assert(err, IsNil)
+	c.Assert(reports, HasLen, 1)
+	c.Check(reports[0].Checks, HasLen, 0)
+	c.Check(reports[0].Status(), Equals, applicationendpoint.CheckStatus(""))
+	ft.zzk.AssertNotCalled(c, "GetTenantExports", "stoppedsvc")
+	ft.delegates.AssertNotCalled(c, "ProbeEndpoint", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_GetServiceEndpoints_VerifyExportsFail(c *C) {
+	state := zkservice.State{HostID: "host1", ServiceID: "exportsfailsvc", InstanceID: 0}
+	state.ContainerID = "containerID"
+	state.Exports = []zkservice.ExportBinding{{Application: "app", PortNumber: 8080}}
+	ft.setupVerifyEndpoints("exportsfailsvc", state)
+	ft.zzk.On("GetTenantExports", "exportsfailsvc").Return(nil, errors.New("zk down"))
+
+	_, err := ft.Facade.GetServiceEndpoints(ft.ctx, "exportsfailsvc", true, true, true)
+	c.Assert(err, ErrorMatches, "Could not verify endpoints .*zk down")
+}
+
+func (ft *FacadeUnitTest) Test_GetServiceEndpointStats(c *C) {
+	running := zkservice.State{HostID: "host1", ServiceID: "statssvc", InstanceID: 0}
+	running.ContainerID = "containerID"
+	running.PrivateIP = "172.17.0.2"
+	running.Exports = []zkservice.ExportBinding{{Application: "app", PortNumber: 8080}, {Application: "admin", PortNumber: 9090}}
+	remote := zkservice.State{HostID: "host2", ServiceID: "statssvc", InstanceID: 1}
+	remote.ContainerID = "containerID2"
+	remote.PrivateIP = "172.17.0.3"
+	remote.Exports = []zkservice.ExportBinding{{Application: "app", PortNumber: 8080}}
+	stopped := zkservice.State{HostID: "host1", ServiceID: "statssvc", InstanceID: 2}
+	stopped.Exports = []zkservice.ExportBinding{{Application: "app", PortNumber: 8080}}
+
+	ft.serviceStore.On("Get", ft.ctx, "statssvc").Return(&service.Service{ID: "statssvc", PoolID: "default"}, nil)
+	ft.zzk.On("GetServiceStates", "default", "statssvc").Return([]zkservice.State{running, remote, stopped}, nil)
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("host1"), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = host.Host{ID: "host1", IPAddr: "10.0.0.1", RPCPort: 4979}
+		})
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("host2"), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = host.Host{ID: "host2", IPAddr: "10.0.0.2", RPCPort: 4979}
+		})
+	ft.delegates.On("GetMuxStats", "10.0.0.1:4979").Return(map[string]proxy.ConnectionStats{
+		"172.17.0.2:8080": {Address: "172.17.0.2:8080", Active: 2, Total: 10, ConnectErrors: 1, BytesReceived: 100, BytesSent: 200},
+	}, nil)
+	ft.delegates.On("GetMuxStats", "10.0.0.2:4979").Return(nil, errors.New("connection refused"))
+
+	stats, err := ft.Facade.GetServiceEndpointStats(ft.ctx, "statssvc")
+	c.Assert(err, IsNil)
+	c.Assert(stats, HasLen, 3)
+
+	c.Check(stats[0].Application, Equals, "app")
+	c.Check(stats[0].ActiveConnections, Equals, int64(2))
+	c.Check(stats[0].TotalConnections, Equals, int64(10))
+	c.Check(stats[0].ConnectErrors, Equals, int64(1))
+	c.Check(stats[0].BytesReceived, Equals, int64(100))
+	c.Check(stats[0].BytesSent, Equals, int64(200))
+	c.Check(stats[0].Error, Equals, "")
+
+	c.Check(stats[1].Application, Equals, "admin")
+	c.Check(stats[1].TotalConnections, Equals, int64(0))
+	c.Check(stats[1].Error, Equals, "")
+
+	c.Check(stats[2].InstanceID, Equals, 1)
+	c.Check(stats[2].Error, Equals, "connection refused")
+}
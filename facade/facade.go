@@ -14,17 +14,23 @@
 package facade
 
 import (
+	"sync"
 	"time"
 
 	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backup"
+	"github.com/control-center/serviced/domain/dbmigration"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/hostkey"
+	"github.com/control-center/serviced/domain/maintenance"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/schedule"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/statehistory"
 	"github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/logging"
@@ -44,39 +50,75 @@ var _ FacadeInterface = &Facade{}
 // New creates an initialized Facade instance
 func New() *Facade {
 	return &Facade{
-		hostStore:     host.NewStore(),
-		hostkeyStore:  hostkey.NewStore(),
-		registryStore: registry.NewStore(),
-		poolStore:     pool.NewStore(),
-		serviceStore:  service.NewStore(),
-		configStore:   serviceconfigfile.NewStore(),
-		templateStore: servicetemplate.NewStore(),
-		userStore:     user.NewStore(),
-		serviceCache:  NewServiceCache(),
-		hostRegistry:  auth.NewHostExpirationRegistry(),
-		zzk:           getZZK(),
+		hostStore:       host.NewStore(),
+		hostkeyStore:    hostkey.NewStore(),
+		registryStore:   registry.NewStore(),
+		poolStore:       pool.NewStore(),
+		serviceStore:    service.NewStore(),
+		configStore:     serviceconfigfile.NewStore(),
+		templateStore:   servicetemplate.NewStore(),
+		userStore:       user.NewStore(),
+		backupStore:     backup.NewStore(),
+		migrationStore:  dbmigration.NewStore(),
+		windowStore:     maintenance.NewStore(),
+		scheduleStore:   schedule.NewStore(),
+		historyStore:    statehistory.NewStore(),
+		serviceCache:    NewServiceCache(),
+		hostRegistry:    auth.NewHostExpirationRegistry(),
+		clockSkew:       auth.NewHostClockSkewRegistry(),
+		maxClockSkew:    DefaultMaxClockSkew,
+		storageWarning:  DefaultStorageWarningPercent,
+		storageCritical: DefaultStorageCriticalPercent,
+		zzk:             getZZK(),
 	}
 }
 
 // Facade is an entrypoint to available controlplane methods
 type Facade struct {
-	hostStore     host.Store
-	hostkeyStore  hostkey.Store
-	registryStore registry.ImageRegistryStore
-	poolStore     pool.Store
-	templateStore servicetemplate.Store
-	serviceStore  service.Store
-	configStore   serviceconfigfile.Store
-	userStore     user.Store
+	hostStore      host.Store
+	hostkeyStore   hostkey.Store
+	registryStore  registry.ImageRegistryStore
+	poolStore      pool.Store
+	templateStore  servicetemplate.Store
+	serviceStore   service.Store
+	configStore    serviceconfigfile.Store
+	userStore      user.Store
+	backupStore    backup.Store
+	migrationStore dbmigration.Store
+	windowStore    maintenance.Store
+	scheduleStore  schedule.Store
+	historyStore   statehistory.Store
 
 	zzk           ZZK
 	dfs           dfs.DFS
 	hcache        *health.HealthStatusCache
 	metricsClient MetricsClient
+	delegates     DelegateClient
 	serviceCache  *serviceCache
 	hostRegistry  *auth.HostExpirationRegistry
+	clockSkew     *auth.HostClockSkewRegistry
+	maxClockSkew  time.Duration
+
+	muxPort        int                  // port of the mux on every host
+	networkTargets []host.NetworkTarget // ports on the master that every delegate must reach
+
+	storageWarning  int // percent of a storage pool used before it is a warning
+	storageCritical int // percent of a storage pool used before it is critical
+
+	minFreeDFS      uint64 // bytes free on the application storage before services are emergency stopped
+	minFreeThinPool uint64 // bytes free on a thin pool before services are emergency stopped
+
+	rollbackWindow       time.Duration // how long changes are verified before they are kept
+	snapshotSpacePercent int           // snapshot space used for automatic snapshots
 
 	isvcsPath string
+
+	allowChaos bool
+	chaosLock  sync.Mutex
+	chaos      map[string]*chaosMonkey
+
+	lostLock  sync.Mutex
+	lostHosts map[string]time.Time // when each disconnected host was first seen disconnected
 }
 
 func (f *Facade) SetZZK(zzk ZZK) { f.zzk = zzk }
@@ -99,8 +141,40 @@ func (f *Facade) SetUserStore(store user.Store) { f.userStore = store }
 
 func (f *Facade) SetTemplateStore(store servicetemplate.Store) { f.templateStore = store }
 
+func (f *Facade) SetBackupStore(store backup.Store) { f.backupStore = store }
+
+func (f *Facade) SetMigrationStore(store dbmigration.Store) { f.migrationStore = store }
+
+func (f *Facade) SetMaintenanceStore(store maintenance.Store) { f.windowStore = store }
+
+func (f *Facade) SetScheduleStore(store schedule.Store) { f.scheduleStore = store }
+
+func (f *Facade) SetStateHistoryStore(store statehistory.Store) { f.historyStore = store }
+
 func (f *Facade) SetHealthCache(hcache *health.HealthStatusCache) { f.hcache = hcache }
 
 func (f *Facade) SetMetricsClient(client MetricsClient) { f.metricsClient = client }
 
+func (f *Facade) SetDelegateClient(client DelegateClient) { f.delegates = client }
+
 func (f *Facade) SetIsvcsPath(path string) { f.isvcsPath = path }
+
+func (f *Facade) SetAllowChaos(allow bool) { f.allowChaos = allow }
+
+func (f *Facade) SetMaxClockSkew(max time.Duration) { f.maxClockSkew = max }
+
+func (f *Facade) SetNetworkTargets(muxPort int, targets []host.NetworkTarget) {
+	f.muxPort, f.networkTargets = muxPort, targets
+}
+
+func (f *Facade) SetStorageThresholds(warning, critical int) {
+	f.storageWarning, f.storageCritical = warning, critical
+}
+
+func (f *Facade) SetRollbackWindow(window time.Duration) { f.rollbackWindow = window }
+
+func (f *Facade) SetSnapshotSpacePercent(percent int) { f.snapshotSpacePercent = percent }
+
+func (f *Facade) SetEmergencyThresholds(minFreeDFS, minFreeThinPool uint64) {
+	f.minFreeDFS, f.minFreeThinPool = minFreeDFS, minFreeThinPool
+}
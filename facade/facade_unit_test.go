@@ -21,13 +21,18 @@ import (
 	"github.com/control-center/serviced/auth"
 	datastoremocks "github.com/control-center/serviced/datastore/mocks"
 	dfsmocks "github.com/control-center/serviced/dfs/mocks"
+	backupmocks "github.com/control-center/serviced/domain/backup/mocks"
+	dbmigrationmocks "github.com/control-center/serviced/domain/dbmigration/mocks"
 	hostmocks "github.com/control-center/serviced/domain/host/mocks"
 	keymocks "github.com/control-center/serviced/domain/hostkey/mocks"
+	maintenancemocks "github.com/control-center/serviced/domain/maintenance/mocks"
 	poolmocks "github.com/control-center/serviced/domain/pool/mocks"
 	registrymocks "github.com/control-center/serviced/domain/registry/mocks"
+	schedulemocks "github.com/control-center/serviced/domain/schedule/mocks"
 	servicemocks "github.com/control-center/serviced/domain/service/mocks"
 	configmocks "github.com/control-center/serviced/domain/serviceconfigfile/mocks"
 	templatemocks "github.com/control-center/serviced/domain/servicetemplate/mocks"
+	historymocks "github.com/control-center/serviced/domain/statehistory/mocks"
 	"github.com/control-center/serviced/facade"
 	zzkmocks "github.com/control-center/serviced/facade/mocks"
 	"github.com/control-center/serviced/metrics"
@@ -38,18 +43,24 @@ import (
 var _ = Suite(&FacadeUnitTest{})
 
 type FacadeUnitTest struct {
-	Facade        *facade.Facade
-	ctx           *datastoremocks.Context
-	zzk           *zzkmocks.ZZK
-	dfs           *dfsmocks.DFS
-	hostStore     *hostmocks.Store
-	poolStore     *poolmocks.Store
-	hostkeyStore  *keymocks.Store
-	registryStore *registrymocks.ImageRegistryStore
-	serviceStore  *servicemocks.Store
-	configStore   *configmocks.Store
-	templateStore *templatemocks.Store
-	metricsClient *zzkmocks.MetricsClient
+	Facade         *facade.Facade
+	ctx            *datastoremocks.Context
+	zzk            *zzkmocks.ZZK
+	dfs            *dfsmocks.DFS
+	backupStore    *backupmocks.Store
+	migrationStore *dbmigrationmocks.Store
+	windowStore    *maintenancemocks.Store
+	scheduleStore  *schedulemocks.Store
+	historyStore   *historymocks.Store
+	hostStore      *hostmocks.Store
+	poolStore      *poolmocks.Store
+	hostkeyStore   *keymocks.Store
+	registryStore  *registrymocks.ImageRegistryStore
+	serviceStore   *servicemocks.Store
+	configStore    *configmocks.Store
+	templateStore  *templatemocks.Store
+	metricsClient  *zzkmocks.MetricsClient
+	delegates      *zzkmocks.DelegateClient
 }
 
 func (ft *FacadeUnitTest) SetUpSuite(c *C) {
@@ -66,6 +77,23 @@ func (ft *FacadeUnitTest) SetUpTest(c *C) {
 	ft.dfs = &dfsmocks.DFS{}
 	ft.Facade.SetDFS(ft.dfs)
 
+	ft.backupStore = &backupmocks.Store{}
+	ft.Facade.SetBackupStore(ft.backupStore)
+
+	ft.migrationStore = &dbmigrationmocks.Store{}
+	ft.Facade.SetMigrationStore(ft.migrationStore)
+
+	ft.windowStore = &maintenancemocks.Store{}
+	ft.Facade.SetMaintenanceStore(ft.windowStore)
+
+	ft.scheduleStore = &schedulemocks.Store{}
+	ft.Facade.SetScheduleStore(ft.scheduleStore)
+
+	ft.historyStore = &historymocks.Store{}
+	ft.Facade.SetStateHistoryStore(ft.historyStore)
+	ft.historyStore.On("Append", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("statehistory.Change")).Return(nil)
+	ft.historyStore.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+
 	ft.hostStore = &hostmocks.Store{}
 	ft.Facade.SetHostStore(ft.hostStore)
 
@@ -93,7 +121,13 @@ func (ft *FacadeUnitTest) SetUpTest(c *C) {
 	ft.metricsClient = &zzkmocks.MetricsClient{}
 	ft.Facade.SetMetricsClient(ft.metricsClient)
 
+	ft.delegates = &zzkmocks.DelegateClient{}
+	ft.Facade.SetDelegateClient(ft.delegates)
+
 	ft.ctx.On("Metrics").Return(metrics.NewMetrics())
+	ft.ctx.On("Err").Return(nil)
+	ft.ctx.On("Done").Return(nil)
+	ft.ctx.On("Value", mock.Anything).Return(nil)
 }
 
 // Mock all DFS locking operations into no-ops
@@ -20,7 +20,6 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/health"
 	zkservice "github.com/control-center/serviced/zzk/service"
-	"github.com/zenoss/glog"
 )
 
 // ReportHealthStatus writes the status of a health check to the cache.
@@ -40,7 +39,10 @@ func (f *Facade) GetServicesHealth(ctx datastore.Context) (map[string]map[int]ma
 	store := f.serviceStore
 	svcs, err := store.GetServices(ctx)
 	if err != nil {
-		glog.Errorf("Could not look up services: %s", err)
+		plog.Errorf("Could not look up services: %s", err)
+		return nil, err
+	}
+	if svcs, err = f.filterServicesInScope(ctx, svcs); err != nil {
 		return nil, err
 	}
 	stats := make(map[string]map[int]map[string]health.HealthStatus)
@@ -58,7 +60,7 @@ func (f *Facade) GetServiceHealth(ctx datastore.Context, serviceID string) (map[
 	store := f.serviceStore
 	svc, err := store.Get(ctx, serviceID)
 	if err != nil {
-		glog.Errorf("Could not look up service %s: %s", serviceID, err)
+		plog.Errorf("Could not look up service %s: %s", serviceID, err)
 		return nil, err
 	}
 	return f.getServiceHealth(*svc)
@@ -67,7 +69,7 @@ func (f *Facade) GetServiceHealth(ctx datastore.Context, serviceID string) (map[
 func (f *Facade) getServiceHealth(svc service.Service) (map[int]map[string]health.HealthStatus, error) {
 	states, err := f.zzk.GetServiceStates(svc.PoolID, svc.ID)
 	if err != nil {
-		glog.Errorf("Could not get service states for service %s (%s): %s", svc.Name, svc.ID, err)
+		plog.Errorf("Could not get service states for service %s (%s): %s", svc.Name, svc.ID, err)
 		return nil, err
 	}
 	stateMap := make(map[int]zkservice.State)
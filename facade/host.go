@@ -16,15 +16,16 @@ package facade
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/hostkey"
 	"github.com/control-center/serviced/domain/service"
-	"github.com/zenoss/glog"
 )
 
 const (
@@ -38,6 +39,18 @@ const (
 
 var (
 	ErrHostDoesNotExist = errors.New("facade: host does not exist")
+	ErrHostKeyMismatch  = errors.New("facade: host key fingerprint does not match")
+)
+
+// DefaultMaxClockSkew is how far a host's clock may differ from the master's
+// before the host is flagged
+const DefaultMaxClockSkew = 10 * time.Second
+
+// Default percent of a host's storage pool that may be used before the host
+// is flagged
+const (
+	DefaultStorageWarningPercent  = 80
+	DefaultStorageCriticalPercent = 90
 )
 
 //---------------------------------------------------------------------------
@@ -47,9 +60,9 @@ var (
 // Returns an error if host already exists or if the host's IP is a virtual IP.
 func (f *Facade) AddHost(ctx datastore.Context, entity *host.Host) ([]byte, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("AddHost"))
-	glog.V(2).Infof("Facade.AddHost: %v", entity)
+	plog.Debugf("Facade.AddHost: %v", entity)
 	if err := f.DFSLock(ctx).LockWithTimeout("add host", userLockTimeout); err != nil {
-		glog.Warningf("Cannot add host: %s", err)
+		plog.Warningf("Cannot add host: %s", err)
 		return nil, err
 	}
 	defer f.DFSLock(ctx).Unlock()
@@ -132,7 +145,15 @@ func (f *Facade) generateDelegateKey(ctx datastore.Context, entity *host.Host) (
 	}
 
 	// Store the key
-	hostkeyEntity := hostkey.HostKey{PEM: string(publicPEM[:])}
+	fingerprint, err := auth.RSAFingerprintFromPEM(publicPEM)
+	if err != nil {
+		return nil, err
+	}
+	hostkeyEntity := hostkey.HostKey{
+		PEM:         string(publicPEM[:]),
+		Fingerprint: fingerprint,
+		CreatedAt:   time.Now(),
+	}
 	err = f.hostkeyStore.Put(ctx, entity.ID, &hostkeyEntity)
 	if err != nil {
 		return nil, err
@@ -146,9 +167,9 @@ func (f *Facade) generateDelegateKey(ctx datastore.Context, entity *host.Host) (
 // UpdateHost information for a registered host
 func (f *Facade) UpdateHost(ctx datastore.Context, entity *host.Host) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("UpdateHost"))
-	glog.V(2).Infof("Facade.UpdateHost: %+v", entity)
+	plog.Debugf("Facade.UpdateHost: %+v", entity)
 	if err := f.DFSLock(ctx).LockWithTimeout("update host", userLockTimeout); err != nil {
-		glog.Warningf("Cannot update host: %s", err)
+		plog.Warningf("Cannot update host: %s", err)
 		return err
 	}
 	defer f.DFSLock(ctx).Unlock()
@@ -187,9 +208,9 @@ func (f *Facade) UpdateHost(ctx datastore.Context, entity *host.Host) error {
 // RemoveHost removes a Host from serviced
 func (f *Facade) RemoveHost(ctx datastore.Context, hostID string) (err error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("RemoveHost"))
-	glog.V(2).Infof("Facade.RemoveHost: %s", hostID)
+	plog.Debugf("Facade.RemoveHost: %s", hostID)
 	if err := f.DFSLock(ctx).LockWithTimeout("remove host", userLockTimeout); err != nil {
-		glog.Warningf("Cannot remove host: %s", err)
+		plog.Warningf("Cannot remove host: %s", err)
 		return err
 	}
 	defer f.DFSLock(ctx).Unlock()
@@ -222,28 +243,21 @@ func (f *Facade) RemoveHost(ctx datastore.Context, hostID string) (err error) {
 	if err = f.hostStore.Delete(ctx, host.HostKey(hostID)); err != nil {
 		return err
 	}
+	f.clockSkew.Remove(hostID)
 
-	//grab all services that are address assigned the host's IPs
-	var services []service.Service
-	for _, ip := range _host.IPs {
-		query := []string{fmt.Sprintf("Endpoints.AddressAssignment.IPAddr:%s", ip.IPAddress)}
-		svcs, err := f.GetTaggedServices(ctx, query)
-		if err != nil {
-			glog.Errorf("Failed to grab services with endpoints assigned to ip %s on host %s: %s", ip.IPAddress, _host.Name, err)
+	// move the host's address assignments to other ips in the pool
+	if len(_host.IPs) > 0 {
+		var report []addressassignment.Reassignment
+		if report, err = f.reassignHostIPs(ctx, hostID, nil); err != nil {
+			plog.Errorf("Failed to reassign address assignments from host %s: %s", _host.Name, err)
 			return err
 		}
-		services = append(services, svcs...)
-	}
-
-	// update address assignments
-	for _, svc := range services {
-		request := addressassignment.AssignmentRequest{
-			ServiceID:      svc.ID,
-			IPAddress:      "",
-			AutoAssignment: true,
-		}
-		if err = f.AssignIPs(ctx, request); err != nil {
-			glog.Warningf("Failed assigning another ip to service %s: %s", svc.ID, err)
+		for _, r := range report {
+			if r.Error != "" {
+				plog.Warningf("Service %s is blocked; could not reassign ips %s from removed host %s: %s", r.ServiceID, strings.Join(r.OldIPAddrs, ","), _host.Name, r.Error)
+			} else {
+				plog.Infof("Reassigned service %s from ips %s on removed host %s to %s", r.ServiceID, strings.Join(r.OldIPAddrs, ","), _host.Name, r.NewIPAddr)
+			}
 		}
 	}
 
@@ -253,11 +267,11 @@ func (f *Facade) RemoveHost(ctx datastore.Context, hostID string) (err error) {
 // GetHost gets a host by id. Returns nil if host not found
 func (f *Facade) GetHost(ctx datastore.Context, hostID string) (*host.Host, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetHost"))
-	glog.V(2).Infof("Facade.GetHost: id=%s", hostID)
+	plog.Debugf("Facade.GetHost: id=%s", hostID)
 
 	var value host.Host
 	err := f.hostStore.Get(ctx, host.HostKey(hostID), &value)
-	glog.V(4).Infof("Facade.GetHost: get error %v", err)
+	plog.Debugf("Facade.GetHost: get error %v", err)
 	if datastore.IsErrNoSuchEntity(err) {
 		return nil, nil
 	}
@@ -270,7 +284,7 @@ func (f *Facade) GetHost(ctx datastore.Context, hostID string) (*host.Host, erro
 // GetHostKey gets a host key by id. Returns nil if host not found
 func (f *Facade) GetHostKey(ctx datastore.Context, hostID string) ([]byte, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetHostKey"))
-	glog.V(2).Infof("Facade.GetHostKey: id=%s", hostID)
+	plog.Debugf("Facade.GetHostKey: id=%s", hostID)
 
 	if key, err := f.hostkeyStore.Get(ctx, hostID); err != nil {
 		return nil, err
@@ -282,7 +296,7 @@ func (f *Facade) GetHostKey(ctx datastore.Context, hostID string) ([]byte, error
 // ResetHostKey generates and returns a host key by id. Returns nil if host not found
 func (f *Facade) ResetHostKey(ctx datastore.Context, hostID string) ([]byte, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("ResetHostKey"))
-	glog.V(2).Infof("Facade.ResetHostKey: id=%s", hostID)
+	plog.Debugf("Facade.ResetHostKey: id=%s", hostID)
 
 	var value host.Host
 	if err := f.hostStore.Get(ctx, host.HostKey(hostID), &value); err != nil {
@@ -291,6 +305,42 @@ func (f *Facade) ResetHostKey(ctx datastore.Context, hostID string) ([]byte, err
 	return f.generateDelegateKey(ctx, &value)
 }
 
+// VerifyHostKey checks the fingerprint of the delegate key that a host
+// reports it has installed against the key generated for the host, and
+// records when the key was verified.
+func (f *Facade) VerifyHostKey(ctx datastore.Context, hostID, fingerprint string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("VerifyHostKey"))
+	logger := plog.WithField("hostid", hostID)
+
+	key, err := f.hostkeyStore.Get(ctx, hostID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up host key")
+		return err
+	}
+	if key.Fingerprint == "" {
+		// keys generated before fingerprints were recorded
+		if key.Fingerprint, err = auth.RSAFingerprintFromPEM([]byte(key.PEM)); err != nil {
+			logger.WithError(err).Debug("Could not fingerprint host key")
+			return err
+		}
+	}
+	if key.Fingerprint != fingerprint {
+		logger.WithFields(log.Fields{
+			"expected": key.Fingerprint,
+			"actual":   fingerprint,
+		}).Warn("Host installed a delegate key that does not match its host key")
+		return ErrHostKeyMismatch
+	}
+
+	key.VerifiedAt = time.Now()
+	if err := f.hostkeyStore.Put(ctx, hostID, key); err != nil {
+		logger.WithError(err).Debug("Could not record host key verification")
+		return err
+	}
+	logger.WithField("fingerprint", fingerprint).Info("Verified delegate key on host")
+	return nil
+}
+
 // SetHostExpiration sets a host's auth token
 // expiration time in the HostExpirationRegistry
 func (f *Facade) SetHostExpiration(ctx datastore.Context, hostid string, expiration int64) {
@@ -303,6 +353,36 @@ func (f *Facade) RemoveHostExpiration(ctx datastore.Context, hostid string) {
 	f.hostRegistry.Remove(hostid)
 }
 
+// SetHostClockSkew records how far a host's clock is behind the master's
+// clock and warns when the skew crosses the configured threshold, since auth
+// token expiration and snapshot ordering break when clocks drift.
+func (f *Facade) SetHostClockSkew(ctx datastore.Context, hostid string, skew time.Duration) {
+	logger := plog.WithFields(log.Fields{
+		"hostid":       hostid,
+		"skew":         skew,
+		"maxclockskew": f.maxClockSkew,
+	})
+	current := auth.HostClockSkew{Skew: skew}
+	prev, ok := f.clockSkew.Set(hostid, skew)
+	if current.Exceeds(f.maxClockSkew) {
+		if !ok || !prev.Exceeds(f.maxClockSkew) {
+			logger.Warn("Host clock is out of sync with the master; check the host's time synchronization")
+		}
+	} else if ok && prev.Exceeds(f.maxClockSkew) {
+		logger.Info("Host clock is back in sync with the master")
+	}
+}
+
+// GetClockSkewedHosts returns the clock skew of each host whose clock is
+// further from the master's than the configured threshold
+func (f *Facade) GetClockSkewedHosts(ctx datastore.Context) map[string]time.Duration {
+	skewed := make(map[string]time.Duration)
+	for hostid, skew := range f.clockSkew.Exceeding(f.maxClockSkew) {
+		skewed[hostid] = skew.Skew
+	}
+	return skewed
+}
+
 // GetHosts returns a list of all registered hosts
 func (f *Facade) GetHosts(ctx datastore.Context) ([]host.Host, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetHosts"))
@@ -315,13 +395,13 @@ func (f *Facade) GetActiveHostIDs(ctx datastore.Context) ([]string, error) {
 	hostids := []string{}
 	pools, err := f.GetResourcePools(ctx)
 	if err != nil {
-		glog.Errorf("Could not get resource pools: %v", err)
+		plog.Errorf("Could not get resource pools: %v", err)
 		return nil, err
 	}
 	for _, p := range pools {
 		var active []string
 		if err := f.zzk.GetActiveHosts(p.ID, &active); err != nil {
-			glog.Errorf("Could not get active host ids for pool: %v", err)
+			plog.Errorf("Could not get active host ids for pool: %v", err)
 			return nil, err
 		}
 		hostids = append(hostids, active...)
@@ -363,6 +443,40 @@ func (f *Facade) FindReadHostsInPool(ctx datastore.Context, poolID string) ([]ho
 	return toReadHosts(hosts), nil
 }
 
+// GetHostStorage returns the usage of a host's storage pools, flagged by the
+// storage thresholds, or nil if the host has not reported its storage.
+func (f *Facade) GetHostStorage(ctx datastore.Context, hostID string) (*host.StorageHealth, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetHostStorage"))
+	h, err := f.GetHost(ctx, hostID)
+	if err != nil {
+		return nil, err
+	} else if h == nil {
+		return nil, ErrHostDoesNotExist
+	}
+	return f.getHostStorage(h)
+}
+
+// GetHostEvents returns the event history of a host, oldest first
+func (f *Facade) GetHostEvents(ctx datastore.Context, hostID string) ([]host.Event, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetHostEvents"))
+	h, err := f.GetHost(ctx, hostID)
+	if err != nil {
+		return nil, err
+	} else if h == nil {
+		return nil, ErrHostDoesNotExist
+	}
+	return f.zzk.GetHostEvents(h.PoolID, h.ID)
+}
+
+func (f *Facade) getHostStorage(h *host.Host) (*host.StorageHealth, error) {
+	storage, err := f.zzk.GetHostStorageHealth(h.PoolID, h.ID)
+	if err != nil || storage == nil {
+		return nil, err
+	}
+	storage.SetLevels(f.storageWarning, f.storageCritical)
+	return storage, nil
+}
+
 // GetHostStatuses returns the memory usage and whether or not a host is active
 func (f *Facade) GetHostStatuses(ctx datastore.Context, hostIDs []string, since time.Time) ([]host.HostStatus, error) {
 	if hostIDs == nil {
@@ -382,10 +496,26 @@ func (f *Facade) GetHostStatuses(ctx datastore.Context, hostIDs []string, since
 			continue
 		}
 		status.Active = active
+		if !active {
+			status.Restarting, _ = f.zzk.IsHostRestarting(h.PoolID, h.ID)
+		}
 
 		expired, _ := f.hostRegistry.IsExpired(h.ID)
 		status.Authenticated = !expired
 
+		if health, err := f.zzk.GetHostDFSHealth(h.PoolID, h.ID); err == nil {
+			status.DFS = health
+		}
+
+		if storage, err := f.getHostStorage(h); err == nil {
+			status.Storage = storage
+		}
+
+		if skew, ok := f.clockSkew.Get(h.ID); ok {
+			status.ClockSkew = skew.Skew
+			status.ClockSkewed = skew.Exceeds(f.maxClockSkew)
+		}
+
 		instances, err := f.GetHostInstances(ctx, since, id)
 		if err != nil {
 			continue
@@ -422,6 +552,7 @@ func toReadHost(h host.Host) host.ReadHost {
 		RAMLimit:      h.RAMLimit,
 		KernelVersion: h.KernelVersion,
 		KernelRelease: h.KernelRelease,
+		Arch:          h.Architecture(),
 		ServiceD: host.ReadServiced{
 			Version: h.ServiceD.Version,
 			Date:    h.ServiceD.Date,
@@ -24,7 +24,6 @@ import (
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
-	"github.com/zenoss/glog"
 	. "gopkg.in/check.v1"
 )
 
@@ -39,7 +38,7 @@ func (s *FacadeIntegrationTest) Test_HostCRUD(t *C) {
 	if err != nil {
 		t.Fatalf("Unexpected error building host: %v", err)
 	}
-	glog.Infof("Facade test add host %v", h)
+	plog.Infof("Facade test add host %v", h)
 	_, err = s.Facade.AddHost(s.CTX, h)
 	//should fail since pool doesn't exist
 	if err == nil {
@@ -23,6 +23,7 @@ import (
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/hostkey"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/facade"
 	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
 )
@@ -140,6 +141,12 @@ func (ft *FacadeUnitTest) Test_AddHost_HappyPath(c *C) {
 			hostkeyPEM := args.Get(2).(*hostkey.HostKey).PEM
 			_, err := auth.RSAPublicKeyFromPEM([]byte(hostkeyPEM))
 			c.Assert(err, IsNil)
+
+			// The fingerprint is recorded for audits
+			fingerprint, err := auth.RSAFingerprintFromPEM([]byte(hostkeyPEM))
+			c.Assert(err, IsNil)
+			c.Assert(args.Get(2).(*hostkey.HostKey).Fingerprint, Equals, fingerprint)
+			c.Assert(args.Get(2).(*hostkey.HostKey).CreatedAt.IsZero(), Equals, false)
 		})
 	ft.hostStore.On("Put", ft.ctx, host.HostKey(h.ID), &h).Return(nil)
 	ft.zzk.On("AddHost", &h).Return(nil)
@@ -159,6 +166,46 @@ func (ft *FacadeUnitTest) Test_AddHost_HappyPath(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (ft *FacadeUnitTest) Test_VerifyHostKey(c *C) {
+	fingerprint, err := auth.RSAFingerprintFromPEM(auth.DevPubKeyPEM)
+	c.Assert(err, IsNil)
+
+	key := &hostkey.HostKey{PEM: string(auth.DevPubKeyPEM), Fingerprint: fingerprint}
+	ft.hostkeyStore.On("Get", ft.ctx, "test-host").Return(key, nil)
+	ft.hostkeyStore.On("Put", ft.ctx, "test-host", mock.MatchedBy(func(k *hostkey.HostKey) bool {
+		return k.Fingerprint == fingerprint && !k.VerifiedAt.IsZero()
+	})).Return(nil)
+
+	err = ft.Facade.VerifyHostKey(ft.ctx, "test-host", fingerprint)
+	c.Assert(err, IsNil)
+	ft.hostkeyStore.AssertExpectations(c)
+}
+
+func (ft *FacadeUnitTest) Test_VerifyHostKey_NoFingerprint(c *C) {
+	fingerprint, err := auth.RSAFingerprintFromPEM(auth.DevPubKeyPEM)
+	c.Assert(err, IsNil)
+
+	// keys from before fingerprints were recorded are fingerprinted on demand
+	key := &hostkey.HostKey{PEM: string(auth.DevPubKeyPEM)}
+	ft.hostkeyStore.On("Get", ft.ctx, "test-host").Return(key, nil)
+	ft.hostkeyStore.On("Put", ft.ctx, "test-host", mock.MatchedBy(func(k *hostkey.HostKey) bool {
+		return k.Fingerprint == fingerprint && !k.VerifiedAt.IsZero()
+	})).Return(nil)
+
+	err = ft.Facade.VerifyHostKey(ft.ctx, "test-host", fingerprint)
+	c.Assert(err, IsNil)
+	ft.hostkeyStore.AssertExpectations(c)
+}
+
+func (ft *FacadeUnitTest) Test_VerifyHostKey_Mismatch(c *C) {
+	key := &hostkey.HostKey{PEM: string(auth.DevPubKeyPEM), Fingerprint: "SHA256:expected"}
+	ft.hostkeyStore.On("Get", ft.ctx, "test-host").Return(key, nil)
+
+	err := ft.Facade.VerifyHostKey(ft.ctx, "test-host", "SHA256:actual")
+	c.Assert(err, Equals, facade.ErrHostKeyMismatch)
+	ft.hostkeyStore.AssertNotCalled(c, "Put", ft.ctx, "test-host", mock.Anything)
+}
+
 func (ft *FacadeUnitTest) Test_RemoveHost_HappyPath(c *C) {
 	h := getTestHost()
 
@@ -235,3 +282,108 @@ func getTestHost() host.Host {
 		UpdatedAt: time.Now(),
 	}
 }
+
+func (ft *FacadeUnitTest) Test_GetHostStatuses_ClockSkew(c *C) {
+	h := host.Host{ID: "skewhost", PoolID: "default"}
+	ft.hostStore.On("Get", ft.ctx, host.HostKey(h.ID), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = h
+		})
+	ft.zzk.On("IsHostActive", h.PoolID, h.ID).Return(true, nil)
+	ft.zzk.On("GetHostDFSHealth", h.PoolID, h.ID).Return(nil, nil)
+	ft.zzk.On("GetHostStorageHealth", h.PoolID, h.ID).Return(nil, nil)
+	ft.zzk.On("GetHostStates", h.PoolID, h.ID).Return(nil, nil)
+
+	ft.Facade.SetMaxClockSkew(10 * time.Second)
+	ft.Facade.SetHostClockSkew(ft.ctx, h.ID, -2*time.Second)
+	statuses, err := ft.Facade.GetHostStatuses(ft.ctx, []string{h.ID}, time.Now())
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 1)
+	c.Assert(statuses[0].ClockSkew, Equals, -2*time.Second)
+	c.Assert(statuses[0].ClockSkewed, Equals, false)
+
+	ft.Facade.SetHostClockSkew(ft.ctx, h.ID, -time.Minute)
+	statuses, err = ft.Facade.GetHostStatuses(ft.ctx, []string{h.ID}, time.Now())
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 1)
+	c.Assert(statuses[0].ClockSkew, Equals, -time.Minute)
+	c.Assert(statuses[0].ClockSkewed, Equals, true)
+	c.Assert(ft.Facade.GetClockSkewedHosts(ft.ctx), DeepEquals, map[string]time.Duration{h.ID: -time.Minute})
+}
+
+func (ft *FacadeUnitTest) Test_GetHostStatuses_Restarting(c *C) {
+	h := host.Host{ID: "restartinghost", PoolID: "default"}
+	ft.hostStore.On("Get", ft.ctx, host.HostKey(h.ID), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = h
+		})
+	ft.zzk.On("IsHostActive", h.PoolID, h.ID).Return(false, nil)
+	ft.zzk.On("IsHostRestarting", h.PoolID, h.ID).Return(true, nil)
+	ft.zzk.On("GetHostDFSHealth", h.PoolID, h.ID).Return(nil, nil)
+	ft.zzk.On("GetHostStorageHealth", h.PoolID, h.ID).Return(nil, nil)
+	ft.zzk.On("GetHostStates", h.PoolID, h.ID).Return(nil, nil)
+
+	statuses, err := ft.Facade.GetHostStatuses(ft.ctx, []string{h.ID}, time.Now())
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 1)
+	c.Assert(statuses[0].Active, Equals, false)
+	c.Assert(statuses[0].Restarting, Equals, true)
+}
+
+func (ft *FacadeUnitTest) Test_GetHostStatuses_Storage(c *C) {
+	h := host.Host{ID: "storagehost", PoolID: "default"}
+	ft.hostStore.On("Get", ft.ctx, host.HostKey(h.ID), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = h
+		})
+	ft.zzk.On("IsHostActive", h.PoolID, h.ID).Return(true, nil)
+	ft.zzk.On("GetHostDFSHealth", h.PoolID, h.ID).Return(nil, nil)
+	ft.zzk.On("GetHostStates", h.PoolID, h.ID).Return(nil, nil)
+	ft.zzk.On("GetHostStorageHealth", h.PoolID, h.ID).Return(&host.StorageHealth{
+		Usage: []host.StorageUsage{
+			{Name: "docker thin pool data", Driver: "devicemapper", Total: 100, Used: 50, Available: 50},
+			{Name: "docker thin pool metadata", Driver: "devicemapper", Total: 100, Used: 85, Available: 15},
+		},
+	}, nil)
+
+	ft.Facade.SetStorageThresholds(80, 90)
+	statuses, err := ft.Facade.GetHostStatuses(ft.ctx, []string{h.ID}, time.Now())
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 1)
+	c.Assert(statuses[0].Storage, NotNil)
+	c.Assert(statuses[0].Storage.Level, Equals, host.StorageWarning)
+	c.Assert(statuses[0].Storage.Usage[0].Level, Equals, host.StorageOK)
+	c.Assert(statuses[0].Storage.Usage[1].Level, Equals, host.StorageWarning)
+}
+
+func (ft *FacadeUnitTest) Test_GetHostStorage_NotReported(c *C) {
+	h := host.Host{ID: "storagehost", PoolID: "default"}
+	ft.hostStore.On("Get", ft.ctx, host.HostKey(h.ID), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = h
+		})
+	ft.zzk.On("GetHostStorageHealth", h.PoolID, h.ID).Return(nil, nil)
+
+	storage, err := ft.Facade.GetHostStorage(ft.ctx, h.ID)
+	c.Assert(err, IsNil)
+	c.Assert(storage, IsNil)
+}
+
+func (ft *FacadeUnitTest) Test_GetHostEvents(c *C) {
+	h := host.Host{ID: "eventhost", PoolID: "default"}
+	ft.hostStore.On("Get", ft.ctx, host.HostKey(h.ID), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = h
+		})
+	expected := []host.Event{{Type: host.EventReconcile, Message: "offline for 5m0s"}}
+	ft.zzk.On("GetHostEvents", h.PoolID, h.ID).Return(expected, nil)
+
+	events, err := ft.Facade.GetHostEvents(ft.ctx, h.ID)
+	c.Assert(err, IsNil)
+	c.Assert(events, DeepEquals, expected)
+}
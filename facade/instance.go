@@ -23,6 +23,7 @@ import (
 	"github.com/control-center/serviced/commons"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/dfs/docker"
+	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/health"
@@ -31,16 +32,17 @@ import (
 )
 
 // GetServiceInstances returns the state of all instances for a particular
-// service.
+// service.  The resource usage of each instance is the usage last reported by
+// its host; usage reported before the given time is considered stale and is
+// left out.
 func (f *Facade) GetServiceInstances(ctx datastore.Context, since time.Time, serviceID string) ([]service.Instance, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetServiceInstances"))
 	logger := plog.WithField("serviceid", serviceID)
 
-	// create an instance map to map instances to their memory usage
-	instanceMap := make(map[string]*service.Usage)
-
-	// keep track of the hosts previously looked up
+	// keep track of the hosts previously looked up, and the resource usage
+	// that they last reported for their instances
 	hostMap := make(map[string]host.Host)
+	usageMap := make(map[string]map[string]service.InstanceUsage)
 
 	svc, err := f.serviceStore.Get(ctx, serviceID)
 	if err != nil {
@@ -74,7 +76,6 @@ func (f *Facade) GetServiceInstances(ctx datastore.Context, since time.Time, ser
 	logger = logger.WithField("instances", len(states))
 	logger.Debug("Found running instances for service")
 
-	metricsreq := make([]metrics.ServiceInstance, len(states))
 	insts := make([]service.Instance, len(states))
 	for i, state := range states {
 		hst, ok := hostMap[state.HostID]
@@ -91,33 +92,52 @@ func (f *Facade) GetServiceInstances(ctx datastore.Context, since time.Time, ser
 			hostMap[state.HostID] = hst
 		}
 
+		usage, ok := usageMap[state.HostID]
+		if !ok {
+			// usage is only informational, so don't fail if it can't be
+			// looked up
+			var err error
+			if usage, err = f.zzk.GetHostInstanceUsage(svc.PoolID, state.HostID); err != nil {
+				logger.WithField("hostid", state.HostID).WithError(err).Warn("Could not look up resource usage for instances on host")
+			}
+			usageMap[state.HostID] = usage
+		}
+
 		inst, err := f.getInstance(ctx, hst, *svc, imageUUID, state)
 		if err != nil {
 			return nil, err
 		}
-		metricsreq[i] = metrics.ServiceInstance{ServiceID: inst.ServiceID, InstanceID: inst.InstanceID}
+		if u, ok := usage[service.InstanceUsageKey(inst.ServiceID, inst.InstanceID)]; ok && !u.Updated.Before(since) {
+			inst.MemoryUsage = u.Memory
+			inst.CPUUsage = u.CPUPercent
+			inst.UsageUpdated = u.Updated
+		}
 		insts[i] = *inst
-		instanceMap[fmt.Sprintf("%s-%d", inst.ServiceID, inst.InstanceID)] = &insts[i].MemoryUsage
 	}
 
-	// look up the metrics of all the instances
-	if len(metricsreq) > 0 {
-		metricsres, err := f.metricsClient.GetInstanceMemoryStats(since, metricsreq...)
+	logger.Debug("Loaded instances for service")
+	return insts, nil
+}
+
+// WaitServiceInstances blocks until an instance of any of the services is
+// added, removed, or updated, or until the timeout, and returns the ids of
+// the services that changed.
+func (f *Facade) WaitServiceInstances(ctx datastore.Context, serviceIDs []string, timeout time.Duration) ([]string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("WaitServiceInstances"))
+	services := make(map[string]string)
+	for _, serviceID := range serviceIDs {
+		svc, err := f.serviceStore.Get(ctx, serviceID)
 		if err != nil {
-			logger.WithError(err).Warn("Could not look up memory metrics for instances on service")
-		} else {
-			for _, metric := range metricsres {
-				*instanceMap[fmt.Sprintf("%s-%s", metric.ServiceID, metric.InstanceID)] = service.Usage{
-					Cur: metric.Last,
-					Max: metric.Max,
-					Avg: metric.Average,
-				}
-			}
+			plog.WithField("serviceid", serviceID).WithError(err).Debug("Could not look up service")
+			return nil, err
 		}
+		services[svc.ID] = svc.PoolID
 	}
 
-	logger.Debug("Loaded instances for service")
-	return insts, nil
+	cancel := make(chan struct{})
+	timer := time.AfterFunc(timeout, func() { close(cancel) })
+	defer timer.Stop()
+	return f.zzk.WaitServiceStateChange(services, cancel)
 }
 
 // GetHostInstances returns the state of all instances for a particular host.
@@ -154,6 +174,22 @@ func (f *Facade) GetHostInstances(ctx datastore.Context, since time.Time, hostID
 		return nil, err
 	}
 
+	// only report the instances of services that the caller may see
+	inScope, err := f.tenantFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	visible := []zkservice.State{}
+	for _, state := range states {
+		if ok, err := inScope(state.ServiceID); err != nil {
+			logger.WithError(err).WithField("serviceid", state.ServiceID).Debug("Could not look up tenant for instance")
+			return nil, err
+		} else if ok {
+			visible = append(visible, state)
+		}
+	}
+	states = visible
+
 	logger = logger.WithField("instances", len(states))
 	logger.Debug("Found running instances for services")
 
@@ -268,6 +304,7 @@ func (f *Facade) getInstance(ctx datastore.Context, hst host.Host, svc service.S
 		Scheduled:     state.Scheduled,
 		Started:       state.Started,
 		Terminated:    state.Terminated,
+		Exits:         state.Exits,
 	}
 	logger.Debug("Loaded service instance")
 
@@ -319,11 +356,23 @@ func (f *Facade) GetAggregateServices(ctx datastore.Context, since time.Time, se
 	// caller.
 	results := make([]service.AggregateService, len(serviceIDs))
 
+	// services that the caller may not see are reported as not found
+	inScope, err := f.tenantFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	for i, serviceID := range serviceIDs {
 		svclog := logger.WithField("serviceid", serviceID)
 
 		svc, err := f.serviceStore.Get(ctx, serviceID)
-		if datastore.IsErrNoSuchEntity(err) {
+		if err == nil {
+			var ok bool
+			if ok, err = inScope(serviceID); err == nil && !ok {
+				svc = nil
+			}
+		}
+		if datastore.IsErrNoSuchEntity(err) || err == nil && svc == nil {
 
 			// If the service is not found, set the NotFound boolean to true
 			// and continue
@@ -391,6 +440,114 @@ func (f *Facade) GetAggregateServices(ctx datastore.Context, since time.Time, se
 	return results, nil
 }
 
+// GetDeploymentStatus returns an aggregated summary of the state of all of the
+// services in a deployment.
+func (f *Facade) GetDeploymentStatus(ctx datastore.Context, deploymentID string) (*service.DeploymentStatus, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetDeploymentStatus"))
+	logger := plog.WithField("deploymentid", deploymentID)
+
+	svcs, err := f.serviceStore.GetServicesByDeployment(ctx, deploymentID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up services for deployment")
+		return nil, err
+	} else if len(svcs) == 0 {
+		return nil, fmt.Errorf("no services found for deployment %s", deploymentID)
+	}
+
+	// load the address assignments of every pool in the deployment
+	store := addressassignment.NewStore()
+	pools := make(map[string]struct{})
+	assigned := make(map[string]struct{})
+	for _, svc := range svcs {
+		if _, ok := pools[svc.PoolID]; ok {
+			continue
+		}
+		pools[svc.PoolID] = struct{}{}
+		assignments, err := store.GetPoolAddressAssignments(ctx, svc.PoolID)
+		if err != nil {
+			logger.WithField("poolid", svc.PoolID).WithError(err).Debug("Could not look up address assignments for pool")
+			return nil, err
+		}
+		for _, a := range assignments {
+			assigned[a.ServiceID+"-"+a.EndpointName] = struct{}{}
+		}
+	}
+
+	status := &service.DeploymentStatus{
+		DeploymentID: deploymentID,
+		Services:     len(svcs),
+	}
+	missingImages := make(map[string]bool)
+	for i := range svcs {
+		svc := &svcs[i]
+		svclog := logger.WithField("serviceid", svc.ID)
+		issue := func(format string, args ...interface{}) service.DeploymentIssue {
+			return service.DeploymentIssue{
+				ServiceID:   svc.ID,
+				ServiceName: svc.Name,
+				Detail:      fmt.Sprintf(format, args...),
+			}
+		}
+
+		switch service.DesiredState(svc.DesiredState) {
+		case service.SVCRun:
+			status.Running++
+			status.ExpectedInstances += svc.Instances
+		case service.SVCPause:
+			status.Paused++
+		default:
+			status.Stopped++
+		}
+
+		// check the address assignments
+		for _, ep := range svc.Endpoints {
+			if ep.IsConfigurable() {
+				if _, ok := assigned[svc.ID+"-"+ep.Name]; !ok {
+					status.UnassignedEndpoints = append(status.UnassignedEndpoints, issue("endpoint %s requires an address assignment on port %d", ep.Name, ep.AddressConfig.Port))
+				}
+			}
+		}
+
+		// check the image
+		if svc.ImageID != "" {
+			missing, ok := missingImages[svc.ImageID]
+			if !ok {
+				if _, err := f.getImageUUID(ctx, svc.ImageID); datastore.IsErrNoSuchEntity(err) {
+					missing = true
+				} else if err != nil {
+					svclog.WithError(err).Debug("Could not look up service image")
+					return nil, err
+				}
+				missingImages[svc.ImageID] = missing
+			}
+			if missing {
+				status.MissingImages = append(status.MissingImages, issue("image %s not found in the registry", svc.ImageID))
+			}
+		}
+
+		// check the running instances
+		states, err := f.zzk.GetServiceStates(svc.PoolID, svc.ID)
+		if err != nil {
+			svclog.WithError(err).Debug("Could not look up running instances")
+			return nil, err
+		}
+		status.Instances += len(states)
+		if svc.DesiredState == int(service.SVCRun) && len(states) < svc.Instances {
+			status.PendingScheduling = append(status.PendingScheduling, issue("%d of %d instances scheduled", len(states), svc.Instances))
+		}
+		for _, state := range states {
+			for name, hstat := range f.getInstanceHealth(svc, state.InstanceID) {
+				if hstat == health.Failed || hstat == health.Timeout {
+					status.FailingHealthChecks = append(status.FailingHealthChecks, issue("instance %d failing health check %s", state.InstanceID, name))
+				}
+			}
+		}
+	}
+
+	logger.Debug("Loaded deployment status")
+	return status, nil
+}
+
 // getInstanceHealth returns the health of the instance of a given service
 func (f *Facade) getInstanceHealth(svc *service.Service, instanceID int) map[string]health.Status {
 	hstats := make(map[string]health.Status)
@@ -528,6 +685,134 @@ func (f *Facade) LocateServiceInstance(ctx datastore.Context, serviceID string,
 	}, nil
 }
 
+// ErrNoPreviousOutput is returned when no host has buffered the output of an
+// exited container of the service instance
+var ErrNoPreviousOutput = errors.New("facade: no output was buffered for an exited container of the service instance")
+
+// GetPreviousInstanceOutput returns the output of the most recently exited
+// container of a service instance.  Each host in the service's pool is asked
+// for the output it buffered, and the most recent output is returned; hosts
+// that cannot be reached are skipped.
+func (f *Facade) GetPreviousInstanceOutput(ctx datastore.Context, serviceID string, instanceID int) (*service.InstanceOutput, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetPreviousInstanceOutput"))
+	logger := plog.WithFields(log.Fields{
+		"serviceid":  serviceID,
+		"instanceid": instanceID,
+	})
+
+	if f.delegates == nil {
+		return nil, ErrNoDelegateClient
+	}
+
+	svc, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up service")
+		return nil, err
+	}
+	hosts, err := f.FindHostsInPool(ctx, svc.PoolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up hosts in pool")
+		return nil, err
+	}
+
+	var result *service.InstanceOutput
+	for _, h := range hosts {
+		output, err := f.delegates.GetPreviousOutput(fmt.Sprintf("%s:%d", h.IPAddr, h.RPCPort), serviceID, instanceID)
+		if err != nil {
+			logger.WithError(err).WithField("hostid", h.ID).Debug("Could not get previous output from host")
+			continue
+		} else if output == nil {
+			continue
+		}
+		if result == nil || output.Exited.After(result.Exited) {
+			output.HostID = h.ID
+			result = output
+		}
+	}
+	if result == nil {
+		return nil, ErrNoPreviousOutput
+	}
+	logger.WithField("hostid", result.HostID).Debug("Found previous output")
+	return result, nil
+}
+
+// GetInstanceDiagnostics returns the output of the built-in diagnostic actions
+// that were run on a service instance.  Each host in the service's pool is
+// asked for the output it saved; hosts that cannot be reached are skipped.
+func (f *Facade) GetInstanceDiagnostics(ctx datastore.Context, serviceID string, instanceID int) ([]service.DiagnosticOutput, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetInstanceDiagnostics"))
+	logger := plog.WithFields(log.Fields{
+		"serviceid":  serviceID,
+		"instanceid": instanceID,
+	})
+
+	if f.delegates == nil {
+		return nil, ErrNoDelegateClient
+	}
+
+	svc, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up service")
+		return nil, err
+	}
+	hosts, err := f.FindHostsInPool(ctx, svc.PoolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up hosts in pool")
+		return nil, err
+	}
+
+	results := []service.DiagnosticOutput{}
+	for _, h := range hosts {
+		diagnostics, err := f.delegates.GetDiagnostics(fmt.Sprintf("%s:%d", h.IPAddr, h.RPCPort), serviceID, instanceID)
+		if err != nil {
+			logger.WithError(err).WithField("hostid", h.ID).Debug("Could not get diagnostics from host")
+			continue
+		}
+		for _, diagnostic := range diagnostics {
+			diagnostic.HostID = h.ID
+			results = append(results, diagnostic)
+		}
+	}
+	logger.WithField("count", len(results)).Debug("Found diagnostics")
+	return results, nil
+}
+
+// GetInstanceLogs returns the lines that the running container of a service
+// instance wrote to stdout and stderr after since, limited to the last tail
+// lines if tail is set.  The lines are read by the agent on the host running
+// the instance.
+func (f *Facade) GetInstanceLogs(ctx datastore.Context, serviceID string, instanceID int, since time.Time, tail int) ([]service.LogLine, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetInstanceLogs"))
+	logger := plog.WithFields(log.Fields{
+		"serviceid":  serviceID,
+		"instanceid": instanceID,
+	})
+
+	if f.delegates == nil {
+		return nil, ErrNoDelegateClient
+	}
+
+	location, err := f.LocateServiceInstance(ctx, serviceID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	h, err := f.GetHost(ctx, location.HostID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up host")
+		return nil, err
+	} else if h == nil {
+		logger.WithField("hostid", location.HostID).Debug("Host not found")
+		return nil, ErrHostDoesNotExist
+	}
+
+	lines, err := f.delegates.GetContainerLogs(fmt.Sprintf("%s:%d", h.IPAddr, h.RPCPort), location.ContainerID, since, tail)
+	if err != nil {
+		logger.WithError(err).WithField("hostid", h.ID).Debug("Could not get container logs from host")
+		return nil, err
+	}
+	return lines, nil
+}
+
 // SendDockerAction locates a service instance and sends an action to it
 func (f *Facade) SendDockerAction(ctx datastore.Context, serviceID string, instanceID int, action string, args []string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("SendDockerAction"))
@@ -567,11 +852,20 @@ func (f *Facade) SendDockerAction(ctx datastore.Context, serviceID string, insta
 		return err
 	}
 
-	// find the service action
+	// find the service action, falling back to the built-in diagnostics
 	command, ok := svc.Actions[action]
 	if !ok {
-		logger.Debug("Command not found for action")
-		return errors.New("command not found for action")
+		diagnostic, ok := service.DiagnosticActions[action]
+		if !ok {
+			logger.Debug("Command not found for action")
+			return errors.New("command not found for action")
+		}
+		if err := f.zzk.SendDiagnosticAction(svc.PoolID, serviceID, instanceID, action, diagnostic); err != nil {
+			logger.WithError(err).Debug("Unable to send diagnostic action")
+			return err
+		}
+		logger.Debug("Submitted diagnostic action")
+		return nil
 	}
 
 	// send the command
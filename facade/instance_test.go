@@ -217,20 +217,82 @@ func (ft *FacadeUnitTest) TestGetServiceInstances_Success(c *C) {
 			CurrentState: service.Running,
 			HealthStatus: make(map[string]health.Status),
 			MemoryUsage:  service.Usage{Cur: 5, Max: 10, Avg: 7},
+			CPUUsage:     12.5,
+			UsageUpdated: testStartTime,
 			Scheduled:    states[0].Scheduled,
 			Started:      states[0].Started,
 			Terminated:   states[0].Terminated,
 		},
 	}
 
-	ft.metricsClient.On("GetInstanceMemoryStats", testStartTime, []metrics.ServiceInstance{{ServiceID: "testservice", InstanceID: 1}}).Return(
-		[]metrics.MemoryUsageStats{
-			{HostID: "testhost", ServiceID: "testservice", InstanceID: "1", Last: 5, Max: 10, Average: 7},
-		}, nil)
+	ft.zzk.On("GetHostInstanceUsage", "default", "testhost").Return(map[string]service.InstanceUsage{
+		"testservice-1":  {Memory: service.Usage{Cur: 5, Max: 10, Avg: 7}, CPUPercent: 12.5, Updated: testStartTime},
+		"otherservice-1": {Memory: service.Usage{Cur: 1, Max: 1, Avg: 1}, CPUPercent: 1, Updated: testStartTime},
+	}, nil)
 
 	actual, err := ft.Facade.GetServiceInstances(ft.ctx, testStartTime, "testservice")
 	c.Assert(err, IsNil)
 	c.Assert(actual, DeepEquals, expected)
+	ft.metricsClient.AssertNotCalled(c, "GetInstanceMemoryStats", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) TestGetServiceInstances_UsageNotReported(c *C) {
+	svc := &service.Service{
+		ID:           "testservice",
+		PoolID:       "default",
+		Name:         "serviceA",
+		ImageID:      "testtenant/image",
+		DesiredState: int(service.SVCRun),
+	}
+	ft.serviceStore.On("Get", ft.ctx, "testservice").Return(svc, nil)
+
+	states := []zkservice.State{
+		{HostID: "testhost", ServiceID: "testservice", InstanceID: 0},
+		{HostID: "testhost", ServiceID: "testservice", InstanceID: 1},
+	}
+	ft.zzk.On("GetServiceStates", "default", "testservice").Return(states, nil)
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("testhost"), mock.AnythingOfType("*host.Host")).Return(nil)
+	ft.registryStore.On("Get", ft.ctx, "testtenant/image:latest").Return(&registry.Image{UUID: "someimageuuid"}, nil)
+	ft.zzk.On("GetHostInstanceUsage", "default", "testhost").Return(nil, ErrTestZK).Once()
+
+	actual, err := ft.Facade.GetServiceInstances(ft.ctx, testStartTime, "testservice")
+	c.Assert(err, IsNil)
+	c.Assert(actual, HasLen, 2)
+	for _, inst := range actual {
+		c.Check(inst.MemoryUsage, Equals, service.Usage{})
+		c.Check(inst.UsageUpdated.IsZero(), Equals, true)
+	}
+}
+
+func (ft *FacadeUnitTest) TestGetServiceInstances_UsageStale(c *C) {
+	svc := &service.Service{
+		ID:           "testservice",
+		PoolID:       "default",
+		Name:         "serviceA",
+		ImageID:      "testtenant/image",
+		DesiredState: int(service.SVCRun),
+	}
+	ft.serviceStore.On("Get", ft.ctx, "testservice").Return(svc, nil)
+
+	states := []zkservice.State{
+		{HostID: "testhost", ServiceID: "testservice", InstanceID: 0},
+		{HostID: "testhost", ServiceID: "testservice", InstanceID: 1},
+	}
+	ft.zzk.On("GetServiceStates", "default", "testservice").Return(states, nil)
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("testhost"), mock.AnythingOfType("*host.Host")).Return(nil)
+	ft.registryStore.On("Get", ft.ctx, "testtenant/image:latest").Return(&registry.Image{UUID: "someimageuuid"}, nil)
+	ft.zzk.On("GetHostInstanceUsage", "default", "testhost").Return(map[string]service.InstanceUsage{
+		"testservice-0": {Memory: service.Usage{Cur: 5, Max: 10, Avg: 7}, CPUPercent: 12.5, Updated: testStartTime.Add(-time.Second)},
+		"testservice-1": {Memory: service.Usage{Cur: 5, Max: 10, Avg: 7}, CPUPercent: 12.5, Updated: testStartTime},
+	}, nil)
+
+	actual, err := ft.Facade.GetServiceInstances(ft.ctx, testStartTime, "testservice")
+	c.Assert(err, IsNil)
+	c.Assert(actual, HasLen, 2)
+	c.Check(actual[0].MemoryUsage, Equals, service.Usage{})
+	c.Check(actual[0].UsageUpdated.IsZero(), Equals, true)
+	c.Check(actual[1].MemoryUsage, Equals, service.Usage{Cur: 5, Max: 10, Avg: 7})
+	c.Check(actual[1].UsageUpdated, Equals, testStartTime)
 }
 
 func (ft *FacadeUnitTest) TestGetHostInstances_HostNotFound(c *C) {
@@ -453,3 +515,17 @@ func (ft *FacadeUnitTest) TestGetHostStrategyInstances(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(actual, DeepEquals, expected)
 }
+
+func (ft *FacadeUnitTest) TestGetDeploymentStatus_StoreError(c *C) {
+	ft.serviceStore.On("GetServicesByDeployment", ft.ctx, "testdeployment").Return(nil, ErrTestZK)
+	status, err := ft.Facade.GetDeploymentStatus(ft.ctx, "testdeployment")
+	c.Assert(err, Equals, ErrTestZK)
+	c.Assert(status, IsNil)
+}
+
+func (ft *FacadeUnitTest) TestGetDeploymentStatus_NoServices(c *C) {
+	ft.serviceStore.On("GetServicesByDeployment", ft.ctx, "testdeployment").Return([]service.Service{}, nil)
+	status, err := ft.Facade.GetDeploymentStatus(ft.ctx, "testdeployment")
+	c.Assert(err, NotNil)
+	c.Assert(status, IsNil)
+}
@@ -16,18 +16,24 @@ package facade
 import (
 	"time"
 
+	"github.com/control-center/serviced/coordinator/storage"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/health"
 
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/backup"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/maintenance"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/schedule"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
 	"github.com/control-center/serviced/domain/user"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 // The FacadeInterface is the API for a Facade
@@ -41,6 +47,12 @@ type FacadeInterface interface {
 
 	GetServices(ctx datastore.Context, request dao.EntityRequest) ([]service.Service, error)
 
+	GetTaggedServices(ctx datastore.Context, request dao.EntityRequest) ([]service.Service, error)
+
+	FindChildService(ctx datastore.Context, parentServiceID string, childName string) (*service.Service, error)
+
+	DeployService(ctx datastore.Context, poolID, parentID string, overwrite bool, svcDef servicedefinition.ServiceDefinition) (string, error)
+
 	GetServicesByImage(ctx datastore.Context, imageID string) ([]service.Service, error)
 
 	GetTenantID(ctx datastore.Context, serviceID string) (string, error)
@@ -57,6 +69,8 @@ type FacadeInterface interface {
 
 	WaitService(ctx datastore.Context, dstate service.DesiredState, timeout time.Duration, recursive bool, serviceIDs ...string) error
 
+	ClearEmergencyShutdown(ctx datastore.Context, serviceID string) (int, error)
+
 	AssignIPs(ctx datastore.Context, assignmentRequest addressassignment.AssignmentRequest) (err error)
 
 	AddServiceTemplate(ctx datastore.Context, serviceTemplate servicetemplate.ServiceTemplate) (string, error)
@@ -69,6 +83,8 @@ type FacadeInterface interface {
 
 	DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string) ([]string, error)
 
+	CloneDeployment(ctx datastore.Context, request dao.DeploymentCloneRequest) (string, error)
+
 	DeployTemplateActive() (active []map[string]string, err error)
 
 	DeployTemplateStatus(deploymentID string) (status string, err error)
@@ -83,10 +99,18 @@ type FacadeInterface interface {
 
 	ResetHostKey(ctx datastore.Context, hostID string) ([]byte, error)
 
+	VerifyHostKey(ctx datastore.Context, hostID, fingerprint string) error
+
+	GetHostStorage(ctx datastore.Context, hostID string) (*host.StorageHealth, error)
+
+	GetHostEvents(ctx datastore.Context, hostID string) ([]host.Event, error)
+
 	SetHostExpiration(ctx datastore.Context, hostID string, expiration int64)
 
 	RemoveHostExpiration(ctx datastore.Context, hostID string)
 
+	SetHostClockSkew(ctx datastore.Context, hostID string, skew time.Duration)
+
 	GetActiveHostIDs(ctx datastore.Context) ([]string, error)
 
 	UpdateHost(ctx datastore.Context, entity *host.Host) error
@@ -127,6 +151,10 @@ type FacadeInterface interface {
 
 	GetServiceInstances(ctx datastore.Context, since time.Time, serviceid string) ([]service.Instance, error)
 
+	StopServiceInstance(ctx datastore.Context, serviceID string, instanceID int) error
+
+	WaitServiceInstances(ctx datastore.Context, serviceIDs []string, timeout time.Duration) ([]string, error)
+
 	GetAggregateServices(ctx datastore.Context, since time.Time, serviceids []string) ([]service.AggregateService, error)
 
 	GetReadPools(ctx datastore.Context) ([]pool.ReadPool, error)
@@ -163,6 +191,16 @@ type FacadeInterface interface {
 
 	ValidateCredentials(ctx datastore.Context, u user.User) (bool, error)
 
+	GetUserTenants(ctx datastore.Context, userName string) ([]string, error)
+
+	SetUserTenants(ctx datastore.Context, userName string, tenantIDs []string) error
+
+	CheckTenantAccess(ctx datastore.Context, userName, serviceID string) error
+
+	CheckServiceAccess(ctx datastore.Context, serviceID string) error
+
+	FilterServiceDetailsByUser(ctx datastore.Context, userName string, details []service.ServiceDetails) ([]service.ServiceDetails, error)
+
 	GetServicesHealth(ctx datastore.Context) (map[string]map[int]map[string]health.HealthStatus, error)
 
 	ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration)
@@ -179,7 +217,39 @@ type FacadeInterface interface {
 
 	DeleteServiceConfig(ctx datastore.Context, fileID string) error
 
+	GetServiceConfigConflicts(ctx datastore.Context, serviceID string) ([]service.ConfigConflict, error)
+
+	ResolveServiceConfigConflict(ctx datastore.Context, fileID, resolution, content string) error
+
 	GetHostStatuses(ctx datastore.Context, hostIDs []string, since time.Time) ([]host.HostStatus, error)
 
+	UpgradeDelegates(ctx datastore.Context, req host.UpgradeRequest) ([]host.UpgradeResult, error)
+
 	UpdateServiceCache(ctx datastore.Context) error
+
+	CheckConsistency(ctx datastore.Context, repair bool) (*zkservice.ConsistencyReport, error)
+
+	CheckNetwork(ctx datastore.Context, poolID string) (*host.NetworkReport, error)
+
+	GetDFSFreezeStatus(ctx datastore.Context) (*storage.FreezeStatus, error)
+
+	GetBackups(ctx datastore.Context) ([]backup.Backup, error)
+
+	DFSLock(ctx datastore.Context) dfs.DFSLocker
+
+	EstimateBackup(ctx datastore.Context, excludes []string) (*dfs.BackupEstimate, error)
+
+	CleanupFailedRestore(ctx datastore.Context, dryRun bool) (*dfs.RestoreQuarantine, error)
+
+	AddMaintenanceWindow(ctx datastore.Context, window *maintenance.Window) error
+
+	RemoveMaintenanceWindow(ctx datastore.Context, windowID string) error
+
+	GetMaintenanceWindows(ctx datastore.Context) ([]maintenance.Window, error)
+
+	AddScheduleProfile(ctx datastore.Context, profile *schedule.Profile) error
+
+	RemoveScheduleProfile(ctx datastore.Context, profileID string) error
+
+	GetScheduleProfiles(ctx datastore.Context) ([]schedule.Profile, error)
 }
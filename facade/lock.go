@@ -21,7 +21,6 @@ import (
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/service"
-	"github.com/zenoss/glog"
 )
 
 // TenantLocker keeps track of locks per tenant
@@ -57,11 +56,11 @@ func (f *Facade) lockTenant(ctx datastore.Context, tenantID string) (err error)
 	}()
 	var svcs []service.Service
 	if svcs, err = f.GetServices(ctx, dao.ServiceRequest{TenantID: tenantID}); err != nil {
-		glog.Errorf("Could not get services for tenant %s: %s", tenantID, err)
+		plog.Errorf("Could not get services for tenant %s: %s", tenantID, err)
 		return
 	}
 	if err = f.zzk.LockServices(svcs); err != nil {
-		glog.Errorf("Could not lock services for tenant %s: %s", tenantID, err)
+		plog.Errorf("Could not lock services for tenant %s: %s", tenantID, err)
 		return
 	}
 	return
@@ -73,11 +72,11 @@ func (f *Facade) unlockTenant(ctx datastore.Context, tenantID string) (err error
 	mutex := getTenantLock(tenantID)
 	var svcs []service.Service
 	if svcs, err = f.GetServices(ctx, dao.ServiceRequest{TenantID: tenantID}); err != nil {
-		glog.Errorf("Could not get services for tenant %s: %s", tenantID, err)
+		plog.Errorf("Could not get services for tenant %s: %s", tenantID, err)
 		return
 	}
 	if err = f.zzk.UnlockServices(svcs); err != nil {
-		glog.Errorf("Could not unlock services for tenant %s: %s", tenantID, err)
+		plog.Errorf("Could not unlock services for tenant %s: %s", tenantID, err)
 		return
 	}
 	mutex.Unlock()
@@ -90,7 +89,7 @@ func (f *Facade) retryUnlockTenant(ctx datastore.Context, tenantID string, cance
 		if err := f.unlockTenant(ctx, tenantID); err == nil {
 			return nil
 		}
-		glog.Warningf("Could not unlock, retrying in %s", interval)
+		plog.Warningf("Could not unlock, retrying in %s", interval)
 		select {
 		case <-time.After(interval):
 		case <-cancel:
@@ -0,0 +1,212 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/maintenance"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/utils"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// AddMaintenanceWindow schedules a maintenance window for a deployment,
+// service, or host.  While the window is active, instances that exit are not
+// restarted and instances on hosts that go offline are not rescheduled.
+func (f *Facade) AddMaintenanceWindow(ctx datastore.Context, window *maintenance.Window) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AddMaintenanceWindow"))
+	logger := plog.WithFields(log.Fields{
+		"scope":  window.Scope,
+		"target": window.Target,
+	})
+
+	if window.ID == "" {
+		var err error
+		if window.ID, err = utils.NewUUID36(); err != nil {
+			return err
+		}
+	}
+	if window.Start.IsZero() {
+		window.Start = time.Now()
+	}
+	if err := window.ValidEntity(); err != nil {
+		return err
+	}
+	if err := f.validateMaintenanceTarget(ctx, window); err != nil {
+		logger.WithError(err).Debug("Could not validate the target of the maintenance window")
+		return err
+	}
+
+	if err := f.windowStore.Put(ctx, window); err != nil {
+		logger.WithError(err).Debug("Could not add maintenance window")
+		return err
+	}
+	logger.WithField("windowid", window.ID).Info("Added maintenance window")
+	return f.SyncMaintenanceWindows(ctx)
+}
+
+// validateMaintenanceTarget returns an error if the deployment, service, or
+// host of the maintenance window does not exist
+func (f *Facade) validateMaintenanceTarget(ctx datastore.Context, window *maintenance.Window) error {
+	switch window.Scope {
+	case maintenance.ScopeHost:
+		if h, err := f.GetHost(ctx, window.Target); err != nil {
+			return err
+		} else if h == nil {
+			return fmt.Errorf("host %s not found", window.Target)
+		}
+	case maintenance.ScopeService:
+		if _, err := f.serviceStore.Get(ctx, window.Target); datastore.IsErrNoSuchEntity(err) {
+			return fmt.Errorf("service %s not found", window.Target)
+		} else if err != nil {
+			return err
+		}
+	case maintenance.ScopeDeployment:
+		svcs, err := f.serviceStore.GetServices(ctx)
+		if err != nil {
+			return err
+		}
+		for _, svc := range svcs {
+			if svc.DeploymentID == window.Target {
+				return nil
+			}
+		}
+		return fmt.Errorf("deployment %s not found", window.Target)
+	}
+	return nil
+}
+
+// RemoveMaintenanceWindow deletes a maintenance window
+func (f *Facade) RemoveMaintenanceWindow(ctx datastore.Context, windowID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("RemoveMaintenanceWindow"))
+	logger := plog.WithField("windowid", windowID)
+
+	if _, err := f.windowStore.Get(ctx, windowID); datastore.IsErrNoSuchEntity(err) {
+		return fmt.Errorf("maintenance window %s not found", windowID)
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not look up maintenance window")
+		return err
+	}
+	if err := f.windowStore.Delete(ctx, windowID); err != nil {
+		logger.WithError(err).Debug("Could not remove maintenance window")
+		return err
+	}
+	logger.Info("Removed maintenance window")
+	return f.SyncMaintenanceWindows(ctx)
+}
+
+// GetMaintenanceWindows returns all of the maintenance windows, ordered by
+// their start time
+func (f *Facade) GetMaintenanceWindows(ctx datastore.Context) ([]maintenance.Window, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetMaintenanceWindows"))
+	windows, err := f.windowStore.GetWindows(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up maintenance windows")
+		return nil, err
+	}
+	sort.Sort(windowsByStart(windows))
+	return windows, nil
+}
+
+type windowsByStart []maintenance.Window
+
+func (w windowsByStart) Len() int           { return len(w) }
+func (w windowsByStart) Less(i, j int) bool { return w[i].Start.Before(w[j].Start) }
+func (w windowsByStart) Swap(i, j int)      { w[i], w[j] = w[j], w[i] }
+
+// SyncMaintenanceWindows publishes the maintenance windows to the resource
+// pools that they cover, so that the hosts of each pool know which instances
+// to leave alone.  Deployment and service windows are resolved to the
+// services they cover at the time of the sync, so the master syncs
+// periodically to pick up new services.
+func (f *Facade) SyncMaintenanceWindows(ctx datastore.Context) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SyncMaintenanceWindows"))
+	windows, err := f.windowStore.GetWindows(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up maintenance windows")
+		return err
+	}
+	pools, err := f.poolStore.GetResourcePools(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up resource pools")
+		return err
+	}
+	svcs, err := f.serviceStore.GetServices(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up services")
+		return err
+	}
+
+	parents := make(map[string]string)
+	for _, svc := range svcs {
+		parents[svc.ID] = svc.ParentServiceID
+	}
+
+	now := time.Now()
+	poolWindows := make(map[string][]zkservice.MaintenanceWindow)
+	for _, window := range windows {
+		if window.Expired(now) {
+			continue
+		}
+		if window.Scope == maintenance.ScopeHost {
+			h, err := f.GetHost(ctx, window.Target)
+			if err != nil {
+				return err
+			} else if h != nil {
+				poolWindows[h.PoolID] = append(poolWindows[h.PoolID], zkservice.MaintenanceWindow{Window: window})
+			}
+			continue
+		}
+
+		// resolve the services that the window covers in each pool
+		covered := make(map[string]*zkservice.MaintenanceWindow)
+		for _, svc := range svcs {
+			if !window.Covers(svc.DeploymentID, serviceLineage(parents, svc), "") {
+				continue
+			}
+			w, ok := covered[svc.PoolID]
+			if !ok {
+				w = &zkservice.MaintenanceWindow{Window: window}
+				covered[svc.PoolID] = w
+			}
+			w.ServiceIDs = append(w.ServiceIDs, svc.ID)
+		}
+		for poolID, w := range covered {
+			poolWindows[poolID] = append(poolWindows[poolID], *w)
+		}
+	}
+
+	for _, p := range pools {
+		if err := f.zzk.SetMaintenanceWindows(p.ID, poolWindows[p.ID]); err != nil {
+			plog.WithField("poolid", p.ID).WithError(err).Debug("Could not publish maintenance windows")
+			return err
+		}
+	}
+	return nil
+}
+
+// serviceLineage returns the id of the service followed by the ids of its
+// parents
+func serviceLineage(parents map[string]string, svc service.Service) []string {
+	lineage := []string{svc.ID}
+	for id := svc.ParentServiceID; id != "" && len(lineage) <= len(parents); id = parents[id] {
+		lineage = append(lineage, id)
+	}
+	return lineage
+}
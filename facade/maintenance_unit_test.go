@@ -0,0 +1,88 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package facade_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/maintenance"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_AddMaintenanceWindow_Invalid(c *C) {
+	window := &maintenance.Window{Scope: "pool", Target: "default", Duration: time.Hour}
+	err := ft.Facade.AddMaintenanceWindow(ft.ctx, window)
+	c.Assert(err, NotNil)
+	ft.windowStore.AssertNotCalled(c, "Put", ft.ctx, window)
+}
+
+func (ft *FacadeUnitTest) Test_AddMaintenanceWindow_NoService(c *C) {
+	ft.serviceStore.On("Get", ft.ctx, "missing").Return(nil, datastore.ErrNoSuchEntity{})
+
+	window := &maintenance.Window{Scope: maintenance.ScopeService, Target: "missing", Duration: time.Hour}
+	err := ft.Facade.AddMaintenanceWindow(ft.ctx, window)
+	c.Assert(err, ErrorMatches, "service missing not found")
+	ft.windowStore.AssertNotCalled(c, "Put", ft.ctx, window)
+}
+
+func (ft *FacadeUnitTest) Test_AddMaintenanceWindow(c *C) {
+	svcs := []service.Service{
+		{ID: "tenant", PoolID: "default", DeploymentID: "dep"},
+		{ID: "parent", PoolID: "default", ParentServiceID: "tenant", DeploymentID: "dep"},
+		{ID: "child", PoolID: "remote", ParentServiceID: "parent", DeploymentID: "dep"},
+		{ID: "other", PoolID: "default", ParentServiceID: "tenant", DeploymentID: "dep"},
+	}
+	ft.serviceStore.On("Get", ft.ctx, "parent").Return(&svcs[1], nil)
+	ft.serviceStore.On("GetServices", ft.ctx).Return(svcs, nil)
+	ft.poolStore.On("GetResourcePools", ft.ctx).Return([]pool.ResourcePool{{ID: "default"}, {ID: "remote"}, {ID: "empty"}}, nil)
+
+	var window *maintenance.Window
+	ft.windowStore.On("Put", ft.ctx, mock.AnythingOfType("*maintenance.Window")).Return(nil).Run(func(args mock.Arguments) {
+		window = args.Get(1).(*maintenance.Window)
+	})
+	ft.windowStore.On("GetWindows", ft.ctx).Return(func(datastore.Context) []maintenance.Window {
+		return []maintenance.Window{*window}
+	}, nil)
+
+	published := make(map[string][]zkservice.MaintenanceWindow)
+	ft.zzk.On("SetMaintenanceWindows", mock.AnythingOfType("string"), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		published[args.String(0)] = args.Get(1).([]zkservice.MaintenanceWindow)
+	})
+
+	err := ft.Facade.AddMaintenanceWindow(ft.ctx, &maintenance.Window{Scope: maintenance.ScopeService, Target: "parent", Duration: time.Hour})
+	c.Assert(err, IsNil)
+	c.Assert(window.ID, Not(Equals), "")
+	c.Assert(window.Start.IsZero(), Equals, false)
+
+	c.Assert(published, HasLen, 3)
+	c.Assert(published["default"], HasLen, 1)
+	c.Assert(published["default"][0].ServiceIDs, DeepEquals, []string{"parent"})
+	c.Assert(published["remote"], HasLen, 1)
+	c.Assert(published["remote"][0].ServiceIDs, DeepEquals, []string{"child"})
+	c.Assert(published["empty"], HasLen, 0)
+}
+
+func (ft *FacadeUnitTest) Test_RemoveMaintenanceWindow_NotFound(c *C) {
+	ft.windowStore.On("Get", ft.ctx, "missing").Return(nil, datastore.ErrNoSuchEntity{})
+
+	err := ft.Facade.RemoveMaintenanceWindow(ft.ctx, "missing")
+	c.Assert(err, ErrorMatches, "maintenance window missing not found")
+	ft.windowStore.AssertNotCalled(c, "Delete", ft.ctx, "missing")
+}
@@ -0,0 +1,143 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+import "time"
+
+import "github.com/control-center/serviced/domain/service"
+import "github.com/control-center/serviced/proxy"
+import "github.com/control-center/serviced/servicedversion"
+
+type DelegateClient struct {
+	mock.Mock
+}
+
+func (_m *DelegateClient) UpgradeServiced(address string, version string) error {
+	ret := _m.Called(address, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(address, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *DelegateClient) GetServicedVersion(address string) (*servicedversion.ServicedVersion, error) {
+	ret := _m.Called(address)
+
+	var r0 *servicedversion.ServicedVersion
+	if rf, ok := ret.Get(0).(func(string) *servicedversion.ServicedVersion); ok {
+		r0 = rf(address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*servicedversion.ServicedVersion)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *DelegateClient) ProbeEndpoint(address string, target string, muxAddress string, timeout time.Duration) error {
+	ret := _m.Called(address, target, muxAddress, timeout)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, time.Duration) error); ok {
+		r0 = rf(address, target, muxAddress, timeout)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *DelegateClient) GetMuxStats(address string) (map[string]proxy.ConnectionStats, error) {
+	ret := _m.Called(address)
+
+	var r0 map[string]proxy.ConnectionStats
+	if rf, ok := ret.Get(0).(func(string) map[string]proxy.ConnectionStats); ok {
+		r0 = rf(address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]proxy.ConnectionStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *DelegateClient) GetPreviousOutput(address string, serviceID string, instanceID int) (*service.InstanceOutput, error) {
+	ret := _m.Called(address, serviceID, instanceID)
+
+	var r0 *service.InstanceOutput
+	if rf, ok := ret.Get(0).(func(string, string, int) *service.InstanceOutput); ok {
+		r0 = rf(address, serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.InstanceOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, int) error); ok {
+		r1 = rf(address, serviceID, instanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *DelegateClient) GetDiagnostics(address string, serviceID string, instanceID int) ([]service.DiagnosticOutput, error) {
+	ret := _m.Called(address, serviceID, instanceID)
+
+	var r0 []service.DiagnosticOutput
+	if rf, ok := ret.Get(0).(func(string, string, int) []service.DiagnosticOutput); ok {
+		r0 = rf(address, serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.DiagnosticOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, int) error); ok {
+		r1 = rf(address, serviceID, instanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *DelegateClient) GetContainerLogs(address string, dockerID string, since time.Time, tail int) ([]service.LogLine, error) {
+	ret := _m.Called(address, dockerID, since, tail)
+
+	var r0 []service.LogLine
+	if rf, ok := ret.Get(0).(func(string, string, time.Time, int) []service.LogLine); ok {
+		r0 = rf(address, dockerID, since, tail)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.LogLine)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, time.Time, int) error); ok {
+		r1 = rf(address, dockerID, since, tail)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -3,17 +3,23 @@ package mocks
 import "github.com/stretchr/testify/mock"
 
 import "time"
+import "github.com/control-center/serviced/coordinator/storage"
 import "github.com/control-center/serviced/dao"
 import "github.com/control-center/serviced/datastore"
+import "github.com/control-center/serviced/dfs"
 import "github.com/control-center/serviced/domain"
 import "github.com/control-center/serviced/health"
 import "github.com/control-center/serviced/domain/addressassignment"
+import "github.com/control-center/serviced/domain/backup"
 import "github.com/control-center/serviced/domain/host"
+import "github.com/control-center/serviced/domain/maintenance"
+import "github.com/control-center/serviced/domain/schedule"
 import "github.com/control-center/serviced/domain/pool"
 import "github.com/control-center/serviced/domain/service"
 import "github.com/control-center/serviced/domain/servicedefinition"
 import "github.com/control-center/serviced/domain/servicetemplate"
 import "github.com/control-center/serviced/domain/user"
+import zkservice "github.com/control-center/serviced/zzk/service"
 
 type FacadeInterface struct {
 	mock.Mock
@@ -94,6 +100,67 @@ func (_m *FacadeInterface) GetServices(ctx datastore.Context, request dao.Entity
 
 	return r0, r1
 }
+func (_m *FacadeInterface) GetTaggedServices(ctx datastore.Context, request dao.EntityRequest) ([]service.Service, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 []service.Service
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.EntityRequest) []service.Service); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, dao.EntityRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *FacadeInterface) FindChildService(ctx datastore.Context, parentServiceID string, childName string) (*service.Service, error) {
+	ret := _m.Called(ctx, parentServiceID, childName)
+
+	var r0 *service.Service
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) *service.Service); ok {
+		r0 = rf(ctx, parentServiceID, childName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string) error); ok {
+		r1 = rf(ctx, parentServiceID, childName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *FacadeInterface) DeployService(ctx datastore.Context, poolID string, parentID string, overwrite bool, svcDef servicedefinition.ServiceDefinition) (string, error) {
+	ret := _m.Called(ctx, poolID, parentID, overwrite, svcDef)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, bool, servicedefinition.ServiceDefinition) string); ok {
+		r0 = rf(ctx, poolID, parentID, overwrite, svcDef)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string, bool, servicedefinition.ServiceDefinition) error); ok {
+		r1 = rf(ctx, poolID, parentID, overwrite, svcDef)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *FacadeInterface) GetServicesByImage(ctx datastore.Context, imageID string) ([]service.Service, error) {
 	ret := _m.Called(ctx, imageID)
 
@@ -213,6 +280,25 @@ func (_m *FacadeInterface) WaitService(ctx datastore.Context, dstate service.Des
 
 	return r0
 }
+func (_m *FacadeInterface) ClearEmergencyShutdown(ctx datastore.Context, serviceID string) (int, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) int); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *FacadeInterface) AssignIPs(ctx datastore.Context, assignmentRequest addressassignment.AssignmentRequest) error {
 	ret := _m.Called(ctx, assignmentRequest)
 
@@ -310,6 +396,25 @@ func (_m *FacadeInterface) DeployTemplate(ctx datastore.Context, poolID string,
 
 	return r0, r1
 }
+func (_m *FacadeInterface) CloneDeployment(ctx datastore.Context, request dao.DeploymentCloneRequest) (string, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.DeploymentCloneRequest) string); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, dao.DeploymentCloneRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *FacadeInterface) DeployTemplateActive() ([]map[string]string, error) {
 	ret := _m.Called()
 
@@ -455,12 +560,69 @@ func (_m *FacadeInterface) ResetHostKey(ctx datastore.Context, hostID string) ([
 
 	return r0, r1
 }
+func (_m *FacadeInterface) VerifyHostKey(ctx datastore.Context, hostID string, fingerprint string) error {
+	ret := _m.Called(ctx, hostID, fingerprint)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) error); ok {
+		r0 = rf(ctx, hostID, fingerprint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *FacadeInterface) GetHostStorage(ctx datastore.Context, hostID string) (*host.StorageHealth, error) {
+	ret := _m.Called(ctx, hostID)
+
+	var r0 *host.StorageHealth
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *host.StorageHealth); ok {
+		r0 = rf(ctx, hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.StorageHealth)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *FacadeInterface) GetHostEvents(ctx datastore.Context, hostID string) ([]host.Event, error) {
+	ret := _m.Called(ctx, hostID)
+
+	var r0 []host.Event
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []host.Event); ok {
+		r0 = rf(ctx, hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]host.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *FacadeInterface) SetHostExpiration(ctx datastore.Context, hostID string, expiration int64) {
 	_m.Called(ctx, hostID, expiration)
 }
 func (_m *FacadeInterface) RemoveHostExpiration(ctx datastore.Context, hostID string) {
 	_m.Called(ctx, hostID)
 }
+func (_m *FacadeInterface) SetHostClockSkew(ctx datastore.Context, hostID string, skew time.Duration) {
+	_m.Called(ctx, hostID, skew)
+}
 func (_m *FacadeInterface) GetActiveHostIDs(ctx datastore.Context) ([]string, error) {
 	ret := _m.Called(ctx)
 
@@ -798,6 +960,39 @@ func (_m *FacadeInterface) GetServiceInstances(ctx datastore.Context, since time
 
 	return r0, r1
 }
+func (_m *FacadeInterface) StopServiceInstance(ctx datastore.Context, serviceID string, instanceID int) error {
+	ret := _m.Called(ctx, serviceID, instanceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, int) error); ok {
+		r0 = rf(ctx, serviceID, instanceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *FacadeInterface) WaitServiceInstances(ctx datastore.Context, serviceIDs []string, timeout time.Duration) ([]string, error) {
+	ret := _m.Called(ctx, serviceIDs, timeout)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(datastore.Context, []string, time.Duration) []string); ok {
+		r0 = rf(ctx, serviceIDs, timeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, []string, time.Duration) error); ok {
+		r1 = rf(ctx, serviceIDs, timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *FacadeInterface) GetAggregateServices(ctx datastore.Context, since time.Time, serviceids []string) ([]service.AggregateService, error) {
 	ret := _m.Called(ctx, since, serviceids)
 
@@ -1143,6 +1338,84 @@ func (_m *FacadeInterface) ValidateCredentials(ctx datastore.Context, u user.Use
 
 	return r0, r1
 }
+func (_m *FacadeInterface) GetUserTenants(ctx datastore.Context, userName string) ([]string, error) {
+	ret := _m.Called(ctx, userName)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []string); ok {
+		r0 = rf(ctx, userName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, userName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *FacadeInterface) SetUserTenants(ctx datastore.Context, userName string, tenantIDs []string) error {
+	ret := _m.Called(ctx, userName, tenantIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, []string) error); ok {
+		r0 = rf(ctx, userName, tenantIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *FacadeInterface) CheckTenantAccess(ctx datastore.Context, userName string, serviceID string) error {
+	ret := _m.Called(ctx, userName, serviceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) error); ok {
+		r0 = rf(ctx, userName, serviceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *FacadeInterface) CheckServiceAccess(ctx datastore.Context, serviceID string) error {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *FacadeInterface) FilterServiceDetailsByUser(ctx datastore.Context, userName string, details []service.ServiceDetails) ([]service.ServiceDetails, error) {
+	ret := _m.Called(ctx, userName, details)
+
+	var r0 []service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, []service.ServiceDetails) []service.ServiceDetails); ok {
+		r0 = rf(ctx, userName, details)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, []service.ServiceDetails) error); ok {
+		r1 = rf(ctx, userName, details)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *FacadeInterface) GetServicesHealth(ctx datastore.Context) (map[string]map[int]map[string]health.HealthStatus, error) {
 	ret := _m.Called(ctx)
 
@@ -1248,6 +1521,39 @@ func (_m *FacadeInterface) DeleteServiceConfig(ctx datastore.Context, fileID str
 
 	return r0
 }
+func (_m *FacadeInterface) GetServiceConfigConflicts(ctx datastore.Context, serviceID string) ([]service.ConfigConflict, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 []service.ConfigConflict
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []service.ConfigConflict); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ConfigConflict)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *FacadeInterface) ResolveServiceConfigConflict(ctx datastore.Context, fileID string, resolution string, content string) error {
+	ret := _m.Called(ctx, fileID, resolution, content)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) error); ok {
+		r0 = rf(ctx, fileID, resolution, content)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
 func (_m *FacadeInterface) GetHostStatuses(ctx datastore.Context, hostIDs []string, since time.Time) ([]host.HostStatus, error) {
 	ret := _m.Called(ctx, hostIDs, since)
 
@@ -1269,6 +1575,27 @@ func (_m *FacadeInterface) GetHostStatuses(ctx datastore.Context, hostIDs []stri
 
 	return r0, r1
 }
+func (_m *FacadeInterface) UpgradeDelegates(ctx datastore.Context, req host.UpgradeRequest) ([]host.UpgradeResult, error) {
+	ret := _m.Called(ctx, req)
+
+	var r0 []host.UpgradeResult
+	if rf, ok := ret.Get(0).(func(datastore.Context, host.UpgradeRequest) []host.UpgradeResult); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]host.UpgradeResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, host.UpgradeRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *FacadeInterface) UpdateServiceCache(ctx datastore.Context) error {
 	ret := _m.Called(ctx)
 
@@ -1281,3 +1608,233 @@ func (_m *FacadeInterface) UpdateServiceCache(ctx datastore.Context) error {
 
 	return r0
 }
+func (_m *FacadeInterface) GetDFSFreezeStatus(ctx datastore.Context) (*storage.FreezeStatus, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *storage.FreezeStatus
+	if rf, ok := ret.Get(0).(func(datastore.Context) *storage.FreezeStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.FreezeStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *FacadeInterface) GetBackups(ctx datastore.Context) ([]backup.Backup, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []backup.Backup
+	if rf, ok := ret.Get(0).(func(datastore.Context) []backup.Backup); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]backup.Backup)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *FacadeInterface) DFSLock(ctx datastore.Context) dfs.DFSLocker {
+	ret := _m.Called(ctx)
+
+	var r0 dfs.DFSLocker
+	if rf, ok := ret.Get(0).(func(datastore.Context) dfs.DFSLocker); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dfs.DFSLocker)
+		}
+	}
+
+	return r0
+}
+func (_m *FacadeInterface) EstimateBackup(ctx datastore.Context, excludes []string) (*dfs.BackupEstimate, error) {
+	ret := _m.Called(ctx, excludes)
+
+	var r0 *dfs.BackupEstimate
+	if rf, ok := ret.Get(0).(func(datastore.Context, []string) *dfs.BackupEstimate); ok {
+		r0 = rf(ctx, excludes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.BackupEstimate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, []string) error); ok {
+		r1 = rf(ctx, excludes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *FacadeInterface) CleanupFailedRestore(ctx datastore.Context, dryRun bool) (*dfs.RestoreQuarantine, error) {
+	ret := _m.Called(ctx, dryRun)
+
+	var r0 *dfs.RestoreQuarantine
+	if rf, ok := ret.Get(0).(func(datastore.Context, bool) *dfs.RestoreQuarantine); ok {
+		r0 = rf(ctx, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.RestoreQuarantine)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, bool) error); ok {
+		r1 = rf(ctx, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *FacadeInterface) AddMaintenanceWindow(ctx datastore.Context, window *maintenance.Window) error {
+	ret := _m.Called(ctx, window)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, *maintenance.Window) error); ok {
+		r0 = rf(ctx, window)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *FacadeInterface) RemoveMaintenanceWindow(ctx datastore.Context, windowID string) error {
+	ret := _m.Called(ctx, windowID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, windowID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *FacadeInterface) GetMaintenanceWindows(ctx datastore.Context) ([]maintenance.Window, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []maintenance.Window
+	if rf, ok := ret.Get(0).(func(datastore.Context) []maintenance.Window); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]maintenance.Window)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *FacadeInterface) AddScheduleProfile(ctx datastore.Context, profile *schedule.Profile) error {
+	ret := _m.Called(ctx, profile)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, *schedule.Profile) error); ok {
+		r0 = rf(ctx, profile)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *FacadeInterface) RemoveScheduleProfile(ctx datastore.Context, profileID string) error {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *FacadeInterface) GetScheduleProfiles(ctx datastore.Context) ([]schedule.Profile, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []schedule.Profile
+	if rf, ok := ret.Get(0).(func(datastore.Context) []schedule.Profile); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schedule.Profile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *FacadeInterface) CheckConsistency(ctx datastore.Context, repair bool) (*zkservice.ConsistencyReport, error) {
+	ret := _m.Called(ctx, repair)
+
+	var r0 *zkservice.ConsistencyReport
+	if rf, ok := ret.Get(0).(func(datastore.Context, bool) *zkservice.ConsistencyReport); ok {
+		r0 = rf(ctx, repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*zkservice.ConsistencyReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, bool) error); ok {
+		r1 = rf(ctx, repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *FacadeInterface) CheckNetwork(ctx datastore.Context, poolID string) (*host.NetworkReport, error) {
+	ret := _m.Called(ctx, poolID)
+
+	var r0 *host.NetworkReport
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *host.NetworkReport); ok {
+		r0 = rf(ctx, poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.NetworkReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
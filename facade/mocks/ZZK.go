@@ -2,11 +2,18 @@ package mocks
 
 import "github.com/stretchr/testify/mock"
 
+import "time"
+
+import "github.com/control-center/serviced/coordinator/client"
+import "github.com/control-center/serviced/coordinator/storage"
 import "github.com/control-center/serviced/datastore"
 import "github.com/control-center/serviced/domain/host"
 import "github.com/control-center/serviced/domain/pool"
 import "github.com/control-center/serviced/domain/registry"
 import "github.com/control-center/serviced/domain/service"
+import "github.com/control-center/serviced/zzk/ensemble"
+import "github.com/control-center/serviced/zzk/ha"
+import zkregistry "github.com/control-center/serviced/zzk/registry"
 import zkservice "github.com/control-center/serviced/zzk/service"
 
 type ZZK struct {
@@ -73,6 +80,27 @@ func (_m *ZZK) RemoveTenantExports(tenantID string) error {
 
 	return r0
 }
+func (_m *ZZK) GetTenantExports(tenantID string) (map[string][]zkregistry.ExportDetails, error) {
+	ret := _m.Called(tenantID)
+
+	var r0 map[string][]zkregistry.ExportDetails
+	if rf, ok := ret.Get(0).(func(string) map[string][]zkregistry.ExportDetails); ok {
+		r0 = rf(tenantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]zkregistry.ExportDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ZZK) WaitService(svc *service.Service, state service.DesiredState, cancel <-chan interface{}) error {
 	ret := _m.Called(svc, state, cancel)
 
@@ -173,6 +201,18 @@ func (_m *ZZK) RemoveHost(_host *host.Host) error {
 
 	return r0
 }
+func (_m *ZZK) DrainHost(_host *host.Host, timeout time.Duration) error {
+	ret := _m.Called(_host, timeout)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*host.Host, time.Duration) error); ok {
+		r0 = rf(_host, timeout)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
 func (_m *ZZK) GetActiveHosts(poolID string, hosts *[]string) error {
 	ret := _m.Called(poolID, hosts)
 
@@ -204,6 +244,121 @@ func (_m *ZZK) IsHostActive(poolID string, hostId string) (bool, error) {
 
 	return r0, r1
 }
+func (_m *ZZK) IsHostRestarting(poolID string, hostID string) (bool, error) {
+	ret := _m.Called(poolID, hostID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
+		r0 = rf(poolID, hostID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(poolID, hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) GetHostDFSHealth(poolID string, hostID string) (*host.DFSHealth, error) {
+	ret := _m.Called(poolID, hostID)
+
+	var r0 *host.DFSHealth
+	if rf, ok := ret.Get(0).(func(string, string) *host.DFSHealth); ok {
+		r0 = rf(poolID, hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.DFSHealth)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(poolID, hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) GetHostEvents(poolID string, hostID string) ([]host.Event, error) {
+	ret := _m.Called(poolID, hostID)
+
+	var r0 []host.Event
+	if rf, ok := ret.Get(0).(func(string, string) []host.Event); ok {
+		r0 = rf(poolID, hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]host.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(poolID, hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) GetHostStorageHealth(poolID string, hostID string) (*host.StorageHealth, error) {
+	ret := _m.Called(poolID, hostID)
+
+	var r0 *host.StorageHealth
+	if rf, ok := ret.Get(0).(func(string, string) *host.StorageHealth); ok {
+		r0 = rf(poolID, hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.StorageHealth)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(poolID, hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) SetMaintenanceWindows(poolID string, windows []zkservice.MaintenanceWindow) error {
+	ret := _m.Called(poolID, windows)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []zkservice.MaintenanceWindow) error); ok {
+		r0 = rf(poolID, windows)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ZZK) GetHostInstanceUsage(poolID string, hostID string) (map[string]service.InstanceUsage, error) {
+	ret := _m.Called(poolID, hostID)
+
+	var r0 map[string]service.InstanceUsage
+	if rf, ok := ret.Get(0).(func(string, string) map[string]service.InstanceUsage); ok {
+		r0 = rf(poolID, hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]service.InstanceUsage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(poolID, hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ZZK) UpdateResourcePool(_pool *pool.ResourcePool) error {
 	ret := _m.Called(_pool)
 
@@ -373,6 +528,27 @@ func (_m *ZZK) GetServiceStates(poolID string, serviceID string) ([]zkservice.St
 
 	return r0, r1
 }
+func (_m *ZZK) WaitServiceStateChange(services map[string]string, cancel <-chan struct{}) ([]string, error) {
+	ret := _m.Called(services, cancel)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(map[string]string, <-chan struct{}) []string); ok {
+		r0 = rf(services, cancel)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(map[string]string, <-chan struct{}) error); ok {
+		r1 = rf(services, cancel)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ZZK) GetHostStates(poolID string, hostID string) ([]zkservice.State, error) {
 	ret := _m.Called(poolID, hostID)
 
@@ -451,6 +627,18 @@ func (_m *ZZK) SendDockerAction(poolID string, serviceID string, instanceID int,
 
 	return r0
 }
+func (_m *ZZK) SendDiagnosticAction(poolID string, serviceID string, instanceID int, action string, command string) error {
+	ret := _m.Called(poolID, serviceID, instanceID, action, command)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int, string, string) error); ok {
+		r0 = rf(poolID, serviceID, instanceID, action, command)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
 func (_m *ZZK) GetServiceStateIDs(poolID string, serviceID string) ([]zkservice.StateRequest, error) {
 	ret := _m.Called(poolID, serviceID)
 
@@ -493,3 +681,162 @@ func (_m *ZZK) GetServiceNodes() ([]zkservice.ServiceNode, error) {
 
 	return r0, r1
 }
+func (_m *ZZK) GetPoolScheduling() ([]zkservice.PoolScheduling, error) {
+	ret := _m.Called()
+
+	var r0 []zkservice.PoolScheduling
+	if rf, ok := ret.Get(0).(func() []zkservice.PoolScheduling); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]zkservice.PoolScheduling)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) GetMasterHAStatus() (*ha.ClusterStatus, error) {
+	ret := _m.Called()
+
+	var r0 *ha.ClusterStatus
+	if rf, ok := ret.Get(0).(func() *ha.ClusterStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ha.ClusterStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) GetZKEnsembleStatus() (*ensemble.Status, error) {
+	ret := _m.Called()
+
+	var r0 *ensemble.Status
+	if rf, ok := ret.Get(0).(func() *ensemble.Status); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ensemble.Status)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) AddZKEnsembleServer(server ensemble.Server, force bool) (*ensemble.Status, error) {
+	ret := _m.Called(server, force)
+
+	var r0 *ensemble.Status
+	if rf, ok := ret.Get(0).(func(ensemble.Server, bool) *ensemble.Status); ok {
+		r0 = rf(server, force)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ensemble.Status)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ensemble.Server, bool) error); ok {
+		r1 = rf(server, force)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) RemoveZKEnsembleServer(id string, force bool) (*ensemble.Status, error) {
+	ret := _m.Called(id, force)
+
+	var r0 *ensemble.Status
+	if rf, ok := ret.Get(0).(func(string, bool) *ensemble.Status); ok {
+		r0 = rf(id, force)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ensemble.Status)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(id, force)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) GetDFSFreezeStatus() (*storage.FreezeStatus, error) {
+	ret := _m.Called()
+
+	var r0 *storage.FreezeStatus
+	if rf, ok := ret.Get(0).(func() *storage.FreezeStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.FreezeStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) SetDFSFreezeStatus(status storage.FreezeStatus) error {
+	ret := _m.Called(status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(storage.FreezeStatus) error); ok {
+		r0 = rf(status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ZZK) NewDatastoreMigrationLock() (client.Lock, error) {
+	ret := _m.Called()
+
+	var r0 client.Lock
+	if rf, ok := ret.Get(0).(func() client.Lock); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(client.Lock)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
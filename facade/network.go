@@ -0,0 +1,110 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+)
+
+// CheckNetwork has the agent on each host of a pool probe the ports that it
+// needs to reach on the other hosts of the pool (mux, rpc) and on the master
+// (rpc, nfs, zookeeper), and returns the connectivity matrix.
+func (f *Facade) CheckNetwork(ctx datastore.Context, poolID string) (*host.NetworkReport, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CheckNetwork"))
+	logger := plog.WithField("poolid", poolID)
+
+	if f.delegates == nil {
+		return nil, ErrNoDelegateClient
+	}
+	if err := f.poolStore.Get(ctx, pool.Key(poolID), &pool.ResourcePool{}); err != nil {
+		logger.WithError(err).Debug("Could not look up resource pool")
+		return nil, err
+	}
+	hosts, err := f.FindHostsInPool(ctx, poolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up hosts in resource pool")
+		return nil, err
+	}
+	sort.Sort(hostsByID(hosts))
+
+	report := &host.NetworkReport{PoolID: poolID, HostIDs: make([]string, len(hosts))}
+	paths := make([][]host.NetworkPath, len(hosts))
+	var wg sync.WaitGroup
+	for i := range hosts {
+		report.HostIDs[i] = hosts[i].ID
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			paths[i] = f.probeNetworkPaths(&hosts[i], hosts)
+		}(i)
+	}
+	wg.Wait()
+	for _, p := range paths {
+		report.Paths = append(report.Paths, p...)
+	}
+	logger.WithFields(log.Fields{
+		"hosts":   len(hosts),
+		"paths":   len(report.Paths),
+		"blocked": len(report.Blocked()),
+	}).Info("Checked network connectivity of resource pool")
+	return report, nil
+}
+
+// probeNetworkPaths probes the ports that a host needs to reach on the other
+// hosts of its pool and on the master
+func (f *Facade) probeNetworkPaths(from *host.Host, hosts []host.Host) []host.NetworkPath {
+	var paths []host.NetworkPath
+	for _, to := range hosts {
+		if to.ID == from.ID {
+			continue
+		}
+		if f.muxPort > 0 {
+			paths = append(paths, host.NetworkPath{ToHostID: to.ID, Name: "mux", Address: fmt.Sprintf("%s:%d", to.IPAddr, f.muxPort)})
+		}
+		paths = append(paths, host.NetworkPath{ToHostID: to.ID, Name: "rpc", Address: fmt.Sprintf("%s:%d", to.IPAddr, to.RPCPort)})
+	}
+	for _, target := range f.networkTargets {
+		paths = append(paths, host.NetworkPath{ToHostID: host.NetworkMaster, Name: target.Name, Address: target.Address})
+	}
+
+	address := fmt.Sprintf("%s:%d", from.IPAddr, from.RPCPort)
+	for i := range paths {
+		path := &paths[i]
+		path.FromHostID = from.ID
+		if err := f.delegates.ProbeEndpoint(address, path.Address, "", endpointProbeTimeout); err != nil {
+			plog.WithFields(log.Fields{
+				"fromhostid": from.ID,
+				"tohostid":   path.ToHostID,
+				"target":     path.Address,
+			}).WithError(err).Debug("Network probe failed")
+			path.Error = err.Error()
+			continue
+		}
+		path.Open = true
+	}
+	return paths
+}
+
+type hostsByID []host.Host
+
+func (s hostsByID) Len() int           { return len(s) }
+func (s hostsByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+func (s hostsByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,59 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_CheckNetwork(c *C) {
+	h1 := host.Host{ID: "host1", PoolID: "pool1", IPAddr: "10.0.0.1", RPCPort: 4979}
+	h2 := host.Host{ID: "host2", PoolID: "pool1", IPAddr: "10.0.0.2", RPCPort: 4979}
+	ft.poolStore.On("Get", ft.ctx, pool.Key("pool1"), mock.AnythingOfType("*pool.ResourcePool")).Return(nil)
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, "pool1").Return([]host.Host{h2, h1}, nil)
+	ft.Facade.SetNetworkTargets(22250, []host.NetworkTarget{{Name: "nfs", Address: "10.0.0.9:2049"}})
+
+	ft.delegates.On("ProbeEndpoint", "10.0.0.1:4979", mock.AnythingOfType("string"), "", mock.AnythingOfType("time.Duration")).Return(nil)
+	ft.delegates.On("ProbeEndpoint", "10.0.0.2:4979", "10.0.0.1:22250", "", mock.AnythingOfType("time.Duration")).Return(errors.New("connection refused"))
+	ft.delegates.On("ProbeEndpoint", "10.0.0.2:4979", mock.AnythingOfType("string"), "", mock.AnythingOfType("time.Duration")).Return(nil)
+
+	report, err := ft.Facade.CheckNetwork(ft.ctx, "pool1")
+	c.Assert(err, IsNil)
+	c.Assert(report.HostIDs, DeepEquals, []string{"host1", "host2"})
+	c.Assert(report.Paths, DeepEquals, []host.NetworkPath{
+		{FromHostID: "host1", ToHostID: "host2", Name: "mux", Address: "10.0.0.2:22250", Open: true},
+		{FromHostID: "host1", ToHostID: "host2", Name: "rpc", Address: "10.0.0.2:4979", Open: true},
+		{FromHostID: "host1", ToHostID: host.NetworkMaster, Name: "nfs", Address: "10.0.0.9:2049", Open: true},
+		{FromHostID: "host2", ToHostID: "host1", Name: "mux", Address: "10.0.0.1:22250", Error: "connection refused"},
+		{FromHostID: "host2", ToHostID: "host1", Name: "rpc", Address: "10.0.0.1:4979", Open: true},
+		{FromHostID: "host2", ToHostID: host.NetworkMaster, Name: "nfs", Address: "10.0.0.9:2049", Open: true},
+	})
+	c.Assert(report.Blocked(), HasLen, 1)
+}
+
+func (ft *FacadeUnitTest) Test_CheckNetwork_NoPool(c *C) {
+	ft.poolStore.On("Get", ft.ctx, pool.Key("missing"), mock.AnythingOfType("*pool.ResourcePool")).Return(datastore.ErrNoSuchEntity{})
+
+	_, err := ft.Facade.CheckNetwork(ft.ctx, "missing")
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+	ft.delegates.AssertNotCalled(c, "ProbeEndpoint", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
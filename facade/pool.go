@@ -14,16 +14,21 @@
 package facade
 
 import (
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/coordinator/storage"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/validation"
 
-	"github.com/zenoss/glog"
-
 	"errors"
 	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -48,7 +53,7 @@ var (
 func (f *Facade) AddResourcePool(ctx datastore.Context, entity *pool.ResourcePool) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("AddResourcePool"))
 	if err := f.DFSLock(ctx).LockWithTimeout("add resource pool", userLockTimeout); err != nil {
-		glog.Warningf("Cannot add resource pool: %s", err)
+		plog.Warningf("Cannot add resource pool: %s", err)
 		return err
 	}
 	defer f.DFSLock(ctx).Unlock()
@@ -62,6 +67,10 @@ func (f *Facade) addResourcePool(ctx datastore.Context, entity *pool.ResourcePoo
 		return ErrPoolExists
 	}
 
+	if _, err := storage.GetTransport(entity.SharedStorage.Transport); err != nil {
+		return err
+	}
+
 	vips := entity.VirtualIPs
 	entity.VirtualIPs = []pool.VirtualIP{}
 	// TODO: Get rid of me when we have front-end functionality of pool realms
@@ -95,7 +104,7 @@ func (f *Facade) addResourcePool(ctx datastore.Context, entity *pool.ResourcePoo
 func (f *Facade) UpdateResourcePool(ctx datastore.Context, entity *pool.ResourcePool) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("UpdateResourcePool"))
 	if err := f.DFSLock(ctx).LockWithTimeout("update resource pool", userLockTimeout); err != nil {
-		glog.Warningf("Cannot update resource pool: %s", err)
+		plog.Warningf("Cannot update resource pool: %s", err)
 		return err
 	}
 	defer f.DFSLock(ctx).Unlock()
@@ -111,6 +120,10 @@ func (f *Facade) updateResourcePool(ctx datastore.Context, entity *pool.Resource
 		return ErrPoolNotExists
 	}
 
+	if _, err := storage.GetTransport(entity.SharedStorage.Transport); err != nil {
+		return err
+	}
+
 	currentVIPs := make(map[string]pool.VirtualIP)
 	for _, vip := range current.VirtualIPs {
 		currentVIPs[vip.IP] = vip
@@ -122,7 +135,7 @@ func (f *Facade) updateResourcePool(ctx datastore.Context, entity *pool.Resource
 	for _, vip := range entity.VirtualIPs {
 		if _, ok := currentVIPs[vip.IP]; !ok {
 			if err := f.addVirtualIP(ctx, &vip); err != nil {
-				glog.Warningf("Could not add virtual ip %s: %s", vip.IP, err)
+				plog.Warningf("Could not add virtual ip %s: %s", vip.IP, err)
 				continue
 			}
 		} else {
@@ -134,7 +147,7 @@ func (f *Facade) updateResourcePool(ctx datastore.Context, entity *pool.Resource
 	// Delete the remaining virtual ips
 	for _, vip := range currentVIPs {
 		if err := f.removeVirtualIP(ctx, vip.PoolID, vip.IP); err != nil {
-			glog.Warningf("Could not remove virtual ip %s: %s", vip.IP, err)
+			plog.Warningf("Could not remove virtual ip %s: %s", vip.IP, err)
 			vips = append(vips, vip)
 		}
 	}
@@ -165,11 +178,11 @@ func (f *Facade) RestoreResourcePools(ctx datastore.Context, pools []pool.Resour
 		if err := f.addResourcePool(ctx, &pool); err != nil {
 			if err == ErrPoolExists {
 				if err := f.updateResourcePool(ctx, &pool); err != nil {
-					glog.Errorf("Could not restore resource pool %s via update: %s", pool.ID, err)
+					plog.Errorf("Could not restore resource pool %s via update: %s", pool.ID, err)
 					return err
 				}
 			} else {
-				glog.Errorf("Could not restore resource pool %s via add: %s", pool.ID, err)
+				plog.Errorf("Could not restore resource pool %s via add: %s", pool.ID, err)
 				return err
 			}
 		}
@@ -181,14 +194,14 @@ func (f *Facade) RestoreResourcePools(ctx datastore.Context, pools []pool.Resour
 func (f *Facade) HasIP(ctx datastore.Context, poolID string, ipAddr string) (bool, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("HasIP"))
 	if exists, err := f.poolStore.HasVirtualIP(ctx, poolID, ipAddr); err != nil {
-		glog.Errorf("Could not look up ip %s for pool %s: %s", ipAddr, poolID, err)
+		plog.Errorf("Could not look up ip %s for pool %s: %s", ipAddr, poolID, err)
 		return false, err
 	} else if exists {
 		return true, nil
 	}
 
 	if host, err := f.GetHostByIP(ctx, ipAddr); err != nil {
-		glog.Errorf("Could not look up static host by ip %s: %s", ipAddr, err)
+		plog.Errorf("Could not look up static host by ip %s: %s", ipAddr, err)
 		return false, err
 	} else if host != nil && host.PoolID == poolID {
 		return true, nil
@@ -279,7 +292,7 @@ func (f *Facade) RemoveVirtualIP(ctx datastore.Context, vip pool.VirtualIP) erro
 	query := []string{fmt.Sprintf("Endpoints.AddressAssignment.IPAddr:%s", vip.IP)}
 	services, err := f.GetTaggedServices(ctx, query)
 	if err != nil {
-		glog.Errorf("Failed to grab services with endpoints assigned to ip %s: %s", vip.IP, err)
+		plog.Errorf("Failed to grab services with endpoints assigned to ip %s: %s", vip.IP, err)
 		return err
 	}
 
@@ -302,7 +315,7 @@ func (f *Facade) RemoveVirtualIP(ctx datastore.Context, vip pool.VirtualIP) erro
 			AutoAssignment: true,
 		}
 		if err = f.AssignIPs(ctx, request); err != nil {
-			glog.Warningf("Failed assigning another ip to service %s: %s", svc.ID, err)
+			plog.Warningf("Failed assigning another ip to service %s: %s", svc.ID, err)
 		}
 	}
 
@@ -322,16 +335,16 @@ func (f *Facade) removeVirtualIP(ctx datastore.Context, poolID, ipAddr string) e
 // RemoveResourcePool removes a resource pool
 func (f *Facade) RemoveResourcePool(ctx datastore.Context, id string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("RemoveResourcePool"))
-	glog.V(2).Infof("Facade.RemoveResourcePool: %s", id)
+	plog.Debugf("Facade.RemoveResourcePool: %s", id)
 	if err := f.DFSLock(ctx).LockWithTimeout("remove resource pool", userLockTimeout); err != nil {
-		glog.Warningf("Cannot remove resource pool: %s", err)
+		plog.Warningf("Cannot remove resource pool: %s", err)
 		return err
 	}
 	defer f.DFSLock(ctx).Unlock()
 
 	// CC-2024: do not delete the default resource pool
 	if id == "default" {
-		glog.Errorf("Cannot delete default resource pool")
+		plog.Errorf("Cannot delete default resource pool")
 		return ErrDefaultPool
 	}
 
@@ -391,7 +404,7 @@ func (f *Facade) GetResourcePoolsByRealm(ctx datastore.Context, realm string) ([
 // GetResourcePool returns a resource pool, or nil if not found
 func (f *Facade) GetResourcePool(ctx datastore.Context, id string) (*pool.ResourcePool, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetResourcePool"))
-	glog.V(2).Infof("Facade.GetResourcePool: id=%s", id)
+	plog.Debugf("Facade.GetResourcePool: id=%s", id)
 	var entity pool.ResourcePool
 	err := f.poolStore.Get(ctx, pool.Key(id), &entity)
 	if datastore.IsErrNoSuchEntity(err) {
@@ -414,11 +427,11 @@ func (f *Facade) CreateDefaultPool(ctx datastore.Context, id string) error {
 		return fmt.Errorf("could not create default pool %s: %v", id, err)
 	}
 	if entity != nil {
-		glog.V(4).Infof("'%s' resource pool already exists", id)
+		plog.Debugf("'%s' resource pool already exists", id)
 		return nil
 	}
 
-	glog.V(4).Infof("'%s' resource pool not found; creating...", id)
+	plog.Debugf("'%s' resource pool not found; creating...", id)
 	entity = pool.New(id)
 	entity.Realm = defaultRealm
 	entity.Description = "Default Pool"
@@ -469,12 +482,12 @@ func (f *Facade) calcPoolCommitment(ctx datastore.Context, pool *pool.ResourcePo
 // GetPoolIPs gets all IPs available to a resource pool
 func (f *Facade) GetPoolIPs(ctx datastore.Context, poolID string) (*pool.PoolIPs, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetPoolIPs"))
-	glog.V(0).Infof("Facade.GetPoolIPs: %+v", poolID)
+	plog.Infof("Facade.GetPoolIPs: %+v", poolID)
 	hosts, err := f.FindHostsInPool(ctx, poolID)
 	if err != nil {
 		return nil, err
 	}
-	glog.V(0).Infof("Facade.GetPoolIPs: found hosts %v", hosts)
+	plog.Infof("Facade.GetPoolIPs: found hosts %v", hosts)
 
 	// save off the static IP addresses
 	hostIPs := make([]host.HostIPResource, 0)
@@ -485,7 +498,7 @@ func (f *Facade) GetPoolIPs(ctx datastore.Context, poolID string) (*pool.PoolIPs
 	// save off the virtual IP addresses
 	myPool, err := f.GetResourcePool(ctx, poolID)
 	if err != nil {
-		glog.Errorf("Unable to load resource pool: %v", poolID)
+		plog.Errorf("Unable to load resource pool: %v", poolID)
 		return nil, err
 	} else if myPool == nil {
 		msg := fmt.Sprintf("Pool ID: %v could not be found", poolID)
@@ -529,3 +542,188 @@ func (f *Facade) GetReadPools(ctx datastore.Context) ([]pool.ReadPool, error) {
 
 	return readPools, err
 }
+
+// GetPoolPortClaims returns the host ports claimed by the services in a
+// resource pool, flagging the claims that conflict with another service.
+func (f *Facade) GetPoolPortClaims(ctx datastore.Context, poolID string) ([]pool.PortClaim, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetPoolPortClaims"))
+	claims, err := f.getPoolPortClaims(ctx, poolID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range claims {
+		for j := range claims {
+			if claims[i].ServiceID != claims[j].ServiceID && claims[i].Overlaps(claims[j]) {
+				claims[i].Conflict = true
+				break
+			}
+		}
+	}
+	sort.Sort(portClaimsByPort(claims))
+	return claims, nil
+}
+
+// getPoolPortClaims returns the address assignments and enabled public ports
+// of the services in a pool.  Only the services that claim a host port are
+// looked up.
+func (f *Facade) getPoolPortClaims(ctx datastore.Context, poolID string) ([]pool.PortClaim, error) {
+	assignments, err := addressassignment.NewStore().GetPoolAddressAssignments(ctx, poolID)
+	if err != nil {
+		plog.Errorf("Could not look up address assignments in pool %s: %s", poolID, err)
+		return nil, err
+	}
+	svcs, err := f.serviceStore.GetServicesWithPublicPorts(ctx, poolID)
+	if err != nil {
+		plog.Errorf("Could not look up services with public ports in pool %s: %s", poolID, err)
+		return nil, err
+	}
+	found := make(map[string]struct{})
+	for _, svc := range svcs {
+		found[svc.ID] = struct{}{}
+	}
+	assignmentsByService := make(map[string][]addressassignment.AddressAssignment)
+	var missing []string
+	for _, a := range assignments {
+		if _, ok := assignmentsByService[a.ServiceID]; !ok {
+			if _, ok := found[a.ServiceID]; !ok {
+				missing = append(missing, a.ServiceID)
+			}
+		}
+		assignmentsByService[a.ServiceID] = append(assignmentsByService[a.ServiceID], a)
+	}
+	if len(missing) > 0 {
+		assigned, err := f.serviceStore.GetServicesByIDs(ctx, missing...)
+		if err != nil {
+			plog.Errorf("Could not look up services with address assignments in pool %s: %s", poolID, err)
+			return nil, err
+		}
+		for _, svc := range assigned {
+			found[svc.ID] = struct{}{}
+		}
+		for _, serviceID := range missing {
+			if _, ok := found[serviceID]; !ok {
+				plog.Warningf("Skipping address assignments of missing service %s", serviceID)
+			}
+		}
+		svcs = append(svcs, assigned...)
+	}
+
+	var claims []pool.PortClaim
+	for i := range svcs {
+		claims = append(claims, getServicePortClaims(&svcs[i], assignmentsByService[svcs[i].ID])...)
+	}
+	return claims, nil
+}
+
+// getServicePortClaims returns the host ports claimed by the given address
+// assignments and the enabled public ports of the service.
+func getServicePortClaims(svc *service.Service, assignments []addressassignment.AddressAssignment) []pool.PortClaim {
+	var claims []pool.PortClaim
+	for _, ep := range svc.Endpoints {
+		for _, a := range assignments {
+			if a.EndpointName != ep.Name {
+				continue
+			}
+			claims = append(claims, pool.PortClaim{
+				IPAddr:       a.IPAddr,
+				Port:         a.Port,
+				Protocol:     portProtocol(ep.AddressConfig.Protocol),
+				ServiceID:    svc.ID,
+				ServiceName:  svc.Name,
+				EndpointName: ep.Name,
+			})
+		}
+		for _, p := range ep.PortList {
+			if !p.Enabled {
+				continue
+			}
+			ipAddr, port, err := parsePortAddr(p.PortAddr)
+			if err != nil {
+				plog.Warningf("Skipping public port %s of service %s (%s): %s", p.PortAddr, svc.Name, svc.ID, err)
+				continue
+			}
+			claims = append(claims, pool.PortClaim{
+				IPAddr:       ipAddr,
+				Port:         port,
+				Protocol:     commons.TCP,
+				ServiceID:    svc.ID,
+				ServiceName:  svc.Name,
+				EndpointName: ep.Name,
+				Public:       true,
+			})
+		}
+	}
+	return claims
+}
+
+// validatePortClaims verifies that none of the host ports claimed by the
+// service are claimed by another service in the same pool.
+func (f *Facade) validatePortClaims(ctx datastore.Context, svc *service.Service) error {
+	assignments, err := f.GetServiceAddressAssignments(ctx, svc.ID)
+	if err != nil {
+		plog.Errorf("Could not look up address assignments for service %s (%s): %s", svc.Name, svc.ID, err)
+		return err
+	}
+	return f.checkPortClaims(ctx, svc.PoolID, getServicePortClaims(svc, assignments))
+}
+
+// checkPortClaims returns an error if any of the claims overlaps with a claim
+// of another service in the pool.
+func (f *Facade) checkPortClaims(ctx datastore.Context, poolID string, claims []pool.PortClaim) error {
+	if len(claims) == 0 {
+		return nil
+	}
+	existing, err := f.getPoolPortClaims(ctx, poolID)
+	if err != nil {
+		return err
+	}
+	for _, claim := range claims {
+		for _, other := range existing {
+			if claim.ServiceID != other.ServiceID && claim.Overlaps(other) {
+				addr := other.IPAddr
+				if addr == "" || claim.IPAddr == "" {
+					addr = "*"
+				}
+				return fmt.Errorf("port %s:%d/%s for endpoint %s is already claimed by endpoint %s of service %s (%s)", addr, claim.Port, claim.Protocol, claim.EndpointName, other.EndpointName, other.ServiceName, other.ServiceID)
+			}
+		}
+	}
+	return nil
+}
+
+// parsePortAddr returns the address and port number of a public port.  The
+// address is empty if the port listens on every address.
+func parsePortAddr(portAddr string) (string, uint16, error) {
+	ipAddr, portStr, err := net.SplitHostPort(portAddr)
+	if err != nil {
+		return "", 0, err
+	}
+	if ip := net.ParseIP(ipAddr); ip != nil && ip.IsUnspecified() {
+		ipAddr = ""
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return "", 0, err
+	}
+	return ipAddr, uint16(port), nil
+}
+
+func portProtocol(protocol string) string {
+	if strings.ToLower(protocol) == commons.UDP {
+		return commons.UDP
+	}
+	return commons.TCP
+}
+
+type portClaimsByPort []pool.PortClaim
+
+func (c portClaimsByPort) Len() int      { return len(c) }
+func (c portClaimsByPort) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c portClaimsByPort) Less(i, j int) bool {
+	if c[i].Port != c[j].Port {
+		return c[i].Port < c[j].Port
+	} else if c[i].IPAddr != c[j].IPAddr {
+		return c[i].IPAddr < c[j].IPAddr
+	}
+	return c[i].ServiceID < c[j].ServiceID
+}
@@ -0,0 +1,73 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade
+
+import (
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&PortClaimTest{})
+
+type PortClaimTest struct{}
+
+func (t *PortClaimTest) TestParsePortAddr(c *C) {
+	for _, tc := range []struct {
+		portAddr string
+		ipAddr   string
+		port     uint16
+	}{
+		{":1234", "", 1234},
+		{"0.0.0.0:1234", "", 1234},
+		{"[::]:1234", "", 1234},
+		{"10.0.0.1:1234", "10.0.0.1", 1234},
+		{"[fe80::1]:1234", "fe80::1", 1234},
+	} {
+		ipAddr, port, err := parsePortAddr(tc.portAddr)
+		c.Assert(err, IsNil, Commentf(tc.portAddr))
+		c.Assert(ipAddr, Equals, tc.ipAddr, Commentf(tc.portAddr))
+		c.Assert(port, Equals, tc.port, Commentf(tc.portAddr))
+	}
+
+	for _, portAddr := range []string{"1234", "10.0.0.1:http", "10.0.0.1:65536"} {
+		_, _, err := parsePortAddr(portAddr)
+		c.Assert(err, NotNil, Commentf(portAddr))
+	}
+}
+
+func (t *PortClaimTest) TestGetServicePortClaims_Wildcard(c *C) {
+	svc := &service.Service{
+		ID:   "svcid",
+		Name: "svc",
+		Endpoints: []service.ServiceEndpoint{
+			{
+				Name: "web",
+				PortList: []servicedefinition.Port{
+					{PortAddr: "0.0.0.0:1234", Enabled: true},
+				},
+			},
+		},
+	}
+	claims := getServicePortClaims(svc, nil)
+	c.Assert(claims, HasLen, 1)
+	c.Assert(claims[0].IPAddr, Equals, "")
+
+	// a wildcard port overlaps with an assignment on any ip
+	other := pool.PortClaim{IPAddr: "10.0.0.1", Port: 1234, Protocol: "tcp", ServiceID: "otherid"}
+	c.Assert(claims[0].Overlaps(other), Equals, true)
+}
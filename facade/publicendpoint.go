@@ -22,7 +22,6 @@ import (
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
-	"github.com/zenoss/glog"
 )
 
 var vhostNameRegex = regexp.MustCompile("^(([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]).)*([A-Za-z0-9]|[A-Za-z0-9][A-Za-z0-9-]*[A-Za-z0-9])$")
@@ -39,13 +38,13 @@ func (f *Facade) AddPublicEndpointPort(ctx datastore.Context, serviceID, endpoin
 	portParts := strings.Split(portAddr, ":")
 	if len(portParts) < 2 {
 		err := fmt.Errorf("Invalid port address. Port address must be \":[PORT NUMBER]\" or \"[IP ADDRESS]:[PORT NUMBER]\"")
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
 	if portAddr == "0" || strings.HasSuffix(portAddr, ":0") {
 		err := fmt.Errorf("Invalid port address. Port 0 is invalid.")
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
@@ -53,7 +52,7 @@ func (f *Facade) AddPublicEndpointPort(ctx datastore.Context, serviceID, endpoin
 	// This has the added benefit of validating the port address before it gets added to the service
 	// definition.
 	if err := checkPort("tcp", fmt.Sprintf("%s", portAddr)); err != nil {
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
@@ -61,7 +60,7 @@ func (f *Facade) AddPublicEndpointPort(ctx datastore.Context, serviceID, endpoin
 	svc, err := f.GetService(ctx, serviceID)
 	if err != nil {
 		err = fmt.Errorf("Could not find service %s: %s", serviceID, err)
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
@@ -69,7 +68,7 @@ func (f *Facade) AddPublicEndpointPort(ctx datastore.Context, serviceID, endpoin
 	services, err := f.GetAllServices(ctx)
 	if err != nil {
 		err = fmt.Errorf("Could not get the list of services: %s", err)
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
@@ -82,7 +81,7 @@ func (f *Facade) AddPublicEndpointPort(ctx datastore.Context, serviceID, endpoin
 			for _, epPort := range endpoint.PortList {
 				if portAddr == epPort.PortAddr {
 					err := fmt.Errorf("Port %s already defined for service: %s", epPort.PortAddr, service.Name)
-					glog.Error(err)
+					plog.Error(err)
 					return nil, err
 				}
 			}
@@ -92,7 +91,7 @@ func (f *Facade) AddPublicEndpointPort(ctx datastore.Context, serviceID, endpoin
 	// Add the port to the service definition.
 	port, err := svc.AddPort(endpointName, portAddr, usetls, protocol, isEnabled)
 	if err != nil {
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
@@ -100,32 +99,32 @@ func (f *Facade) AddPublicEndpointPort(ctx datastore.Context, serviceID, endpoin
 	// in the service getting turned off during restart but not being able to start again.
 	if err := f.validateServiceStart(ctx, svc); err != nil {
 		// We don't call UpdateService() service here; effectively unwinding the svc.AddPort() call above.
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
-	glog.V(2).Infof("Added port public endpoint %s to service %s", portAddr, svc.Name)
+	plog.Debugf("Added port public endpoint %s to service %s", portAddr, svc.Name)
 
 	if err = f.UpdateService(ctx, *svc); err != nil {
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
-	glog.V(2).Infof("Service (%s) updated", svc.Name)
+	plog.Debugf("Service (%s) updated", svc.Name)
 	return port, nil
 }
 
 // Try to open the port.  If the port opens, we're good. Otherwise return the error.
 func checkPort(network string, laddr string) error {
-	glog.V(2).Infof("Checking %s port %s", network, laddr)
+	plog.Debugf("Checking %s port %s", network, laddr)
 	listener, err := net.Listen(network, laddr)
 	if err != nil {
 		// Port isn't available.
-		glog.V(2).Infof("Port Listen failed; something else is using this port.")
+		plog.Debugf("Port Listen failed; something else is using this port.")
 		return err
 	} else {
 		// Port was opened. Make sure we close it.
-		glog.V(2).Infof("Port Listen succeeded. Closing the listener.")
+		plog.Debugf("Port Listen succeeded. Closing the listener.")
 		listener.Close()
 	}
 	return nil
@@ -142,25 +141,25 @@ func (f *Facade) RemovePublicEndpointPort(ctx datastore.Context, serviceid, endp
 	svc, err := f.GetService(ctx, serviceid)
 	if err != nil {
 		err = fmt.Errorf("Could not find service %s: %s", serviceid, err)
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
 	err = svc.RemovePort(endpointName, portAddr)
 	if err != nil {
 		err = fmt.Errorf("Error removing port %s from service (%s): %v", portAddr, svc.Name, err)
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
-	glog.V(2).Infof("Removed port public endpoint %s from service %s", portAddr, svc.Name)
+	plog.Debugf("Removed port public endpoint %s from service %s", portAddr, svc.Name)
 
 	if err = f.UpdateService(ctx, *svc); err != nil {
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
-	glog.V(2).Infof("Service (%s) updated", svc.Name)
+	plog.Debugf("Service (%s) updated", svc.Name)
 	return nil
 }
 
@@ -175,7 +174,7 @@ func (f *Facade) EnablePublicEndpointPort(ctx datastore.Context, serviceid, endp
 	svc, err := f.GetService(ctx, serviceid)
 	if err != nil {
 		err = fmt.Errorf("Could not find service %s: %s", serviceid, err)
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
@@ -183,18 +182,18 @@ func (f *Facade) EnablePublicEndpointPort(ctx datastore.Context, serviceid, endp
 	port := svc.GetPort(endpointName, portAddr)
 	if port == nil {
 		err = fmt.Errorf("Port %s not found in service %s:%s", portAddr, svc.ID, svc.Name)
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
 	// If the port is already in the same state, don't do anything.
 	if port.Enabled == isEnabled {
 		err = fmt.Errorf("Port %s enabled state is already set to %t for service (%s).", portAddr, isEnabled, svc.Name)
-		glog.Warning(err)
+		plog.Warning(err)
 		return err
 	}
 
-	glog.V(0).Infof("Setting enabled=%t for service (%s) port %s", isEnabled, svc.Name, portAddr)
+	plog.Infof("Setting enabled=%t for service (%s) port %s", isEnabled, svc.Name, portAddr)
 
 	// If they're trying to enable the port, check to make sure the port is valid and available.
 	if isEnabled {
@@ -202,18 +201,18 @@ func (f *Facade) EnablePublicEndpointPort(ctx datastore.Context, serviceid, endp
 		portParts := strings.Split(portAddr, ":")
 		if len(portParts) < 2 {
 			err = fmt.Errorf("Invalid port address. Port address must be \":[PORT NUMBER]\" or \"[IP ADDRESS]:[PORT NUMBER]\"")
-			glog.Error(err)
+			plog.Error(err)
 			return err
 		}
 
 		if portAddr == "0" || strings.HasSuffix(portAddr, ":0") {
 			err = fmt.Errorf("Invalid port address. Port 0 is invalid.")
-			glog.Error(err)
+			plog.Error(err)
 			return err
 		}
 
 		if err = checkPort("tcp", fmt.Sprintf("%s", portAddr)); err != nil {
-			glog.Error(err)
+			plog.Error(err)
 			return err
 		}
 	}
@@ -221,18 +220,18 @@ func (f *Facade) EnablePublicEndpointPort(ctx datastore.Context, serviceid, endp
 	err = svc.EnablePort(endpointName, portAddr, isEnabled)
 	if err != nil {
 		err = fmt.Errorf("Error setting enabled=%t for port %s, service (%s): %v", isEnabled, portAddr, svc.Name, err)
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
-	glog.V(2).Infof("Port (%s) enable state set to %t for service (%s)", portAddr, isEnabled, svc.Name)
+	plog.Debugf("Port (%s) enable state set to %t for service (%s)", portAddr, isEnabled, svc.Name)
 
 	if err = f.UpdateService(ctx, *svc); err != nil {
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
-	glog.V(2).Infof("Service (%s) updated", svc.Name)
+	plog.Debugf("Service (%s) updated", svc.Name)
 	return nil
 }
 
@@ -243,14 +242,14 @@ func (f *Facade) AddPublicEndpointVHost(ctx datastore.Context, serviceid, endpoi
 	svc, err := f.GetService(ctx, serviceid)
 	if err != nil {
 		err = fmt.Errorf("Could not find service %s: %s", serviceid, err)
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
 	// Make sure the name doesn't contain invalid characters.
 	if !vhostNameRegex.MatchString(vhostName) {
 		err = fmt.Errorf("Invalid virtual host name: %s", vhostName)
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
@@ -259,7 +258,7 @@ func (f *Facade) AddPublicEndpointVHost(ctx datastore.Context, serviceid, endpoi
 	services, err := f.GetAllServices(ctx)
 	if err != nil {
 		err = fmt.Errorf("Could not get the list of services: %s", err)
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
@@ -271,7 +270,7 @@ func (f *Facade) AddPublicEndpointVHost(ctx datastore.Context, serviceid, endpoi
 			for _, vhost := range endpoint.VHostList {
 				if strings.ToLower(vhost.Name) == vhostLowerName {
 					err := fmt.Errorf("vhost %s already defined for service: %s", vhostName, otherService.Name)
-					glog.Error(err)
+					plog.Error(err)
 					return nil, err
 				}
 			}
@@ -281,7 +280,7 @@ func (f *Facade) AddPublicEndpointVHost(ctx datastore.Context, serviceid, endpoi
 	vhost, err := svc.AddVirtualHost(endpointName, vhostName, isEnabled)
 	if err != nil {
 		err := fmt.Errorf("Error adding vhost (%s) to service (%s): %v", vhostName, svc.Name, err)
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
@@ -289,18 +288,18 @@ func (f *Facade) AddPublicEndpointVHost(ctx datastore.Context, serviceid, endpoi
 	// in the service getting turned off during restart but not being able to start again.
 	if err := f.validateServiceStart(ctx, svc); err != nil {
 		// We don't call UpdateService() service here; effectively unwinding the svc.AddVirtualHost() call above.
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
-	glog.V(2).Infof("Added vhost public endpoint %s to service %s", vhost.Name, svc.Name)
+	plog.Debugf("Added vhost public endpoint %s to service %s", vhost.Name, svc.Name)
 
 	if err = f.UpdateService(ctx, *svc); err != nil {
-		glog.Error(err)
+		plog.Error(err)
 		return nil, err
 	}
 
-	glog.V(2).Infof("Service (%s) updated", svc.Name)
+	plog.Debugf("Service (%s) updated", svc.Name)
 	return vhost, nil
 }
 
@@ -311,25 +310,25 @@ func (f *Facade) RemovePublicEndpointVHost(ctx datastore.Context, serviceid, end
 	svc, err := f.GetService(ctx, serviceid)
 	if err != nil {
 		err = fmt.Errorf("Could not find service %s: %s", serviceid, err)
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
 	err = svc.RemoveVirtualHost(endpointName, vhost)
 	if err != nil {
 		err = fmt.Errorf("Error removing vhost %s from service (%s): %v", vhost, svc.Name, err)
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
-	glog.V(2).Infof("Removed vhost public endpoint %s from service %s", vhost, svc.Name)
+	plog.Debugf("Removed vhost public endpoint %s from service %s", vhost, svc.Name)
 
 	if err = f.UpdateService(ctx, *svc); err != nil {
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
-	glog.V(2).Infof("Service (%s) updated", svc.Name)
+	plog.Debugf("Service (%s) updated", svc.Name)
 	return nil
 }
 
@@ -339,7 +338,7 @@ func (f *Facade) EnablePublicEndpointVHost(ctx datastore.Context, serviceid, end
 	svc, err := f.GetService(ctx, serviceid)
 	if err != nil {
 		err = fmt.Errorf("Could not find service %s: %s", serviceid, err)
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
@@ -347,33 +346,33 @@ func (f *Facade) EnablePublicEndpointVHost(ctx datastore.Context, serviceid, end
 	existingVHost := svc.GetVirtualHost(endpointName, vhost)
 	if existingVHost == nil {
 		err = fmt.Errorf("VHost %s not found in service %s:%s", vhost, svc.ID, svc.Name)
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
 	// If the vhost is already in the same state, don't do anything.
 	if existingVHost.Enabled == isEnabled {
 		err = fmt.Errorf("VHost %s enabled state is already set to %t for service (%s).", vhost, isEnabled, svc.Name)
-		glog.Warning(err)
+		plog.Warning(err)
 		return err
 	}
 
-	glog.V(0).Infof("Setting enabled=%t for service (%s) vhost %s", isEnabled, svc.Name, vhost)
+	plog.Infof("Setting enabled=%t for service (%s) vhost %s", isEnabled, svc.Name, vhost)
 
 	err = svc.EnableVirtualHost(endpointName, vhost, isEnabled)
 	if err != nil {
 		err = fmt.Errorf("Error setting vhost (%s) enable state to %t for service (%s): %v", vhost, isEnabled, svc.Name, err)
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
-	glog.V(2).Infof("VHost (%s) enable state set to %t for service (%s)", vhost, isEnabled, svc.Name)
+	plog.Debugf("VHost (%s) enable state set to %t for service (%s)", vhost, isEnabled, svc.Name)
 
 	if err = f.UpdateService(ctx, *svc); err != nil {
-		glog.Error(err)
+		plog.Error(err)
 		return err
 	}
 
-	glog.V(2).Infof("Service (%s) updated", svc.Name)
+	plog.Debugf("Service (%s) updated", svc.Name)
 	return nil
 }
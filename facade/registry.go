@@ -14,10 +14,15 @@
 package facade
 
 import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/commons/docker"
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/registry"
-	"github.com/zenoss/glog"
 )
 
 // GetRegistryImage returns information about an image that is stored in the
@@ -32,9 +37,13 @@ func (f *Facade) GetRegistryImage(ctx datastore.Context, image string) (*registr
 	return rImage, nil
 }
 
-// SetRegistryImage creates/updates an image in the docker registry index.
+// SetRegistryImage creates/updates an image in the docker registry index.  If
+// the image does not have a push time, it is set to the current time.
 func (f *Facade) SetRegistryImage(ctx datastore.Context, rImage *registry.Image) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("SetRegistryImage"))
+	if rImage.PushedAt.IsZero() {
+		rImage.PushedAt = time.Now().UTC()
+	}
 	if err := f.registryStore.Put(ctx, rImage); err != nil {
 		return err
 	}
@@ -81,12 +90,105 @@ func (f *Facade) SearchRegistryLibraryByTag(ctx datastore.Context, library, tagn
 	return rImages, nil
 }
 
+// ListRegistryImages returns the images in the docker registry index along with
+// the services that use them, sorted by image name.  If tenantID is set, only
+// the images in that tenant's library are returned.
+func (f *Facade) ListRegistryImages(ctx datastore.Context, tenantID string) ([]registry.ImageDetails, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ListRegistryImages"))
+	rImages, err := f.registryStore.GetImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	imageServices, err := f.getRegistryImageServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	details := []registry.ImageDetails{}
+	for _, rImage := range rImages {
+		if tenantID != "" && rImage.Library != tenantID {
+			continue
+		}
+		details = append(details, registry.ImageDetails{
+			Image:    rImage,
+			Services: imageServices[registryImageName(rImage.Library, rImage.Repo, rImage.Tag)],
+		})
+	}
+	sort.Sort(imageDetailsByName(details))
+	return details, nil
+}
+
+// InspectRegistryImage returns an image in the docker registry index along
+// with the services that use it.  The registry host and port are ignored, and
+// an image without a tag is assumed to be latest.
+// e.g. InspectRegistryImage(ctx, "localhost:5000/library/reponame:tagname")
+func (f *Facade) InspectRegistryImage(ctx datastore.Context, image string) (*registry.ImageDetails, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("InspectRegistryImage"))
+	imageID, err := commons.ParseImageID(image)
+	if err != nil {
+		return nil, err
+	}
+	name := registryImageName(imageID.User, imageID.Repo, imageID.Tag)
+	rImage, err := f.registryStore.Get(ctx, name)
+	if datastore.IsErrNoSuchEntity(err) {
+		return nil, fmt.Errorf("image %s not found in the registry index", name)
+	} else if err != nil {
+		return nil, err
+	}
+	imageServices, err := f.getRegistryImageServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &registry.ImageDetails{Image: *rImage, Services: imageServices[name]}, nil
+}
+
+// getRegistryImageServices returns the sorted ids of the services that use
+// each image, keyed by the image's name in the registry index.
+func (f *Facade) getRegistryImageServices(ctx datastore.Context) (map[string][]string, error) {
+	svcs, err := f.getServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	imageServices := make(map[string][]string)
+	for _, svc := range svcs {
+		if svc.ImageID == "" {
+			continue
+		}
+		imageID, err := commons.ParseImageID(svc.ImageID)
+		if err != nil {
+			plog.Warningf("Could not parse image %s for service %s (%s): %s", svc.ImageID, svc.Name, svc.ID, err)
+			continue
+		}
+		name := registryImageName(imageID.User, imageID.Repo, imageID.Tag)
+		imageServices[name] = append(imageServices[name], svc.ID)
+	}
+	for _, ids := range imageServices {
+		sort.Strings(ids)
+	}
+	return imageServices, nil
+}
+
+// registryImageName returns the name of an image in the registry index
+func registryImageName(library, repo, tag string) string {
+	if tag == "" {
+		tag = docker.DockerLatest
+	}
+	return commons.ImageID{User: library, Repo: repo, Tag: tag}.String()
+}
+
+type imageDetailsByName []registry.ImageDetails
+
+func (d imageDetailsByName) Len() int      { return len(d) }
+func (d imageDetailsByName) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d imageDetailsByName) Less(i, j int) bool {
+	return d[i].Image.String() < d[j].Image.String()
+}
+
 // SyncRegistryImages makes sure images on es are in sync with zk.  If force is
 // enabled, all images are reset.
 func (f *Facade) SyncRegistryImages(ctx datastore.Context, force bool) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("SyncRegistryImages"))
 	if err := f.DFSLock(ctx).LockWithTimeout("sync registry images", userLockTimeout); err != nil {
-		glog.Warningf("Cannot sync registry images: %s", err)
+		plog.Warningf("Cannot sync registry images: %s", err)
 		return err
 	}
 	defer f.DFSLock(ctx).Unlock()
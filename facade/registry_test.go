@@ -52,6 +52,7 @@ func (ft *FacadeIntegrationTest) TestSetRegistryImage(c *C) {
 	}
 	err := ft.Facade.SetRegistryImage(ft.CTX, expected)
 	c.Assert(err, IsNil)
+	c.Assert(expected.PushedAt.IsZero(), Equals, false)
 	expected.DatabaseVersion++
 	actual, err := ft.Facade.registryStore.Get(ft.CTX, "library/reponame:tagname")
 	c.Assert(err, IsNil)
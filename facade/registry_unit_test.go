@@ -18,6 +18,7 @@ package facade_test
 import (
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/service"
 	. "gopkg.in/check.v1"
 )
 
@@ -55,3 +56,53 @@ func (ft *FacadeUnitTest) Test_GetRegistryImageFailsForOtherDBError(c *C) {
 	c.Assert(result, IsNil)
 	c.Assert(err, Equals, expectedError)
 }
+
+func (ft *FacadeUnitTest) Test_ListRegistryImages(c *C) {
+	images := []registry.Image{
+		{Library: "tenant2", Repo: "repo", Tag: "latest", UUID: "uuid3"},
+		{Library: "tenant1", Repo: "repo", Tag: "latest", UUID: "uuid1"},
+		{Library: "tenant1", Repo: "other", Tag: "1.0", UUID: "uuid2"},
+	}
+	ft.registryStore.On("GetImages", ft.ctx).Return(images, nil)
+	svcs := []service.Service{
+		{ID: "svc2", ImageID: "localhost:5000/tenant1/repo"},
+		{ID: "svc1", ImageID: "localhost:5000/tenant1/repo:latest"},
+		{ID: "svc3", ImageID: "localhost:5000/tenant2/repo:2.0"},
+		{ID: "svc4"},
+	}
+	ft.serviceStore.On("GetServices", ft.ctx).Return(svcs, nil)
+
+	result, err := ft.Facade.ListRegistryImages(ft.ctx, "")
+	c.Assert(err, IsNil)
+	c.Assert(result, HasLen, 3)
+	c.Assert(result[0].UUID, Equals, "uuid2")
+	c.Assert(result[0].Services, IsNil)
+	c.Assert(result[1].UUID, Equals, "uuid1")
+	c.Assert(result[1].Services, DeepEquals, []string{"svc1", "svc2"})
+	c.Assert(result[2].UUID, Equals, "uuid3")
+	c.Assert(result[2].Services, IsNil)
+
+	result, err = ft.Facade.ListRegistryImages(ft.ctx, "tenant2")
+	c.Assert(err, IsNil)
+	c.Assert(result, HasLen, 1)
+	c.Assert(result[0].UUID, Equals, "uuid3")
+}
+
+func (ft *FacadeUnitTest) Test_InspectRegistryImage(c *C) {
+	image := registry.Image{Library: "tenant1", Repo: "repo", Tag: "latest", UUID: "uuid1"}
+	ft.registryStore.On("Get", ft.ctx, "tenant1/repo:latest").Return(&image, nil)
+	ft.serviceStore.On("GetServices", ft.ctx).Return([]service.Service{{ID: "svc1", ImageID: "tenant1/repo"}}, nil)
+
+	result, err := ft.Facade.InspectRegistryImage(ft.ctx, "localhost:5000/tenant1/repo")
+	c.Assert(err, IsNil)
+	c.Assert(result.Image, DeepEquals, image)
+	c.Assert(result.Services, DeepEquals, []string{"svc1"})
+}
+
+func (ft *FacadeUnitTest) Test_InspectRegistryImageNotFound(c *C) {
+	ft.registryStore.On("Get", ft.ctx, "tenant1/repo:1.0").Return(nil, datastore.ErrNoSuchEntity{})
+
+	result, err := ft.Facade.InspectRegistryImage(ft.ctx, "tenant1/repo:1.0")
+	c.Assert(result, IsNil)
+	c.Assert(err, ErrorMatches, "image tenant1/repo:1.0 not found in the registry index")
+}
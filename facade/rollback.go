@@ -0,0 +1,147 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
+)
+
+// afterServiceRollback is published when a change is rolled back because its
+// services failed their health checks
+var afterServiceRollback = afterEvent("AfterServiceRollback")
+
+// rollbackPollInterval is how often the health of a change is checked
+var rollbackPollInterval = 10 * time.Second
+
+// prepareRollback snapshots the tenant before a change when any of its
+// services opt into automatic rollback, and returns the snapshot along with
+// the services to verify after the change.  Returns an empty snapshot id if
+// the change does not need to be verified.  The dfs is locked for the
+// snapshot unless the caller already holds the lock.
+func (f *Facade) prepareRollback(ctx datastore.Context, tenantID, reason string, snapshotSpacePercent int, locked bool) (string, []string, error) {
+	if f.rollbackWindow <= 0 {
+		return "", nil, nil
+	}
+	var serviceIDs []string
+	err := f.walkServices(ctx, tenantID, true, func(svc *service.Service) error {
+		if svc.AutoRollback {
+			serviceIDs = append(serviceIDs, svc.ID)
+		}
+		return nil
+	}, "prepareRollback")
+	if err != nil {
+		return "", nil, err
+	}
+	if len(serviceIDs) == 0 {
+		return "", nil, nil
+	}
+	if !locked {
+		if err := f.DFSLock(ctx).LockWithTimeout("snapshot before "+reason, userLockTimeout); err != nil {
+			return "", nil, err
+		}
+		defer f.DFSLock(ctx).Unlock()
+	}
+	snapshotID, err := f.Snapshot(ctx, tenantID, "before "+reason, []string{}, snapshotSpacePercent, dfs.SnapshotProvenance{Source: dfs.SnapshotSourceScheduled})
+	if err != nil {
+		return "", nil, err
+	}
+	return snapshotID, serviceIDs, nil
+}
+
+// verifyChange watches the health of the services after a change for the
+// duration of the rollback window, and rolls the tenant back to the snapshot
+// taken before the change as soon as any instance fails a health check.
+func (f *Facade) verifyChange(snapshotID, reason string, serviceIDs []string) {
+	ctx := datastore.Get()
+	logger := plog.WithFields(log.Fields{
+		"snapshotid": snapshotID,
+		"reason":     reason,
+	})
+
+	ticker := time.NewTicker(rollbackPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(f.rollbackWindow)
+	for {
+		select {
+		case <-deadline:
+			logger.Info("Verified change; services are healthy")
+			return
+		case <-ticker.C:
+		}
+		if svc, name := f.findFailingService(ctx, serviceIDs); svc != nil {
+			f.rollbackChange(ctx, snapshotID, reason, svc, name)
+			return
+		}
+	}
+}
+
+// findFailingService returns the first service with an instance that is
+// failing a health check, along with the name of the failing check.
+func (f *Facade) findFailingService(ctx datastore.Context, serviceIDs []string) (*service.Service, string) {
+	for _, serviceID := range serviceIDs {
+		svc, err := f.serviceStore.Get(ctx, serviceID)
+		if err != nil {
+			plog.WithError(err).WithField("serviceid", serviceID).Debug("Could not look up service to verify")
+			continue
+		}
+		states, err := f.zzk.GetServiceStates(svc.PoolID, svc.ID)
+		if err != nil {
+			plog.WithError(err).WithField("serviceid", serviceID).Debug("Could not look up service states to verify")
+			continue
+		}
+		for _, state := range states {
+			for name, status := range f.getInstanceHealth(svc, state.InstanceID) {
+				if status == health.Failed || status == health.Timeout {
+					return svc, name
+				}
+			}
+		}
+	}
+	return nil, ""
+}
+
+// rollbackChange rolls the tenant back to the snapshot taken before a failed
+// change and publishes the rollback.
+func (f *Facade) rollbackChange(ctx datastore.Context, snapshotID, reason string, svc *service.Service, healthCheck string) {
+	logger := plog.WithFields(log.Fields{
+		"snapshotid":  snapshotID,
+		"reason":      reason,
+		"serviceid":   svc.ID,
+		"servicename": svc.Name,
+		"healthcheck": healthCheck,
+	})
+	logger.Warn("Service failed a health check after a change; rolling back")
+
+	evtctx := newEventCtx()
+	evtctx["snapshotid"] = snapshotID
+	evtctx["reason"] = reason
+	evtctx["healthcheck"] = healthCheck
+
+	var err error
+	defer func() { f.afterEvent(afterServiceRollback, evtctx, svc, err) }()
+
+	f.DFSLock(ctx).Lock("automatic rollback")
+	defer f.DFSLock(ctx).Unlock()
+	if err = f.Rollback(ctx, snapshotID, true); err != nil {
+		logger.WithError(err).Error("Could not roll back change")
+		return
+	}
+	logger.Warn("Rolled back change")
+}
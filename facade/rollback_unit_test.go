@@ -0,0 +1,55 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/coordinator/storage"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_ServiceUse_AutoRollbackSnapshotFails(c *C) {
+	ft.Facade.SetRollbackWindow(time.Minute)
+	defer ft.Facade.SetRollbackWindow(0)
+	tenant := service.Service{ID: "tenant"}
+	ft.serviceStore.On("Get", ft.ctx, "tenant").Return(&tenant, nil)
+	child := service.Service{ID: "child", ParentServiceID: "tenant", AutoRollback: true}
+	ft.serviceStore.On("Get", ft.ctx, "child").Return(&child, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, "tenant").Return([]service.Service{child}, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, "child").Return([]service.Service{}, nil)
+	ft.zzk.On("GetDFSFreezeStatus").Return(&storage.FreezeStatus{Frozen: true, Reason: "maintenance"}, nil)
+
+	err := ft.Facade.ServiceUse(ft.ctx, "tenant", "tenant/repo:2.0", "", nil, false)
+	c.Assert(err, Equals, storage.ErrDFSFrozen)
+	ft.dfs.AssertNotCalled(c, "Download", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_ServiceUse_NoAutoRollback(c *C) {
+	ft.Facade.SetRollbackWindow(time.Minute)
+	defer ft.Facade.SetRollbackWindow(0)
+	tenant := service.Service{ID: "tenant"}
+	ft.serviceStore.On("Get", ft.ctx, "tenant").Return(&tenant, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, "tenant").Return([]service.Service{}, nil)
+	ft.dfs.On("Download", "tenant/repo:2.0", "tenant", true).Return("localhost:5000/tenant/repo:latest", nil)
+
+	err := ft.Facade.ServiceUse(ft.ctx, "tenant", "tenant/repo:2.0", "", nil, false)
+	c.Assert(err, IsNil)
+	ft.zzk.AssertNotCalled(c, "GetDFSFreezeStatus")
+	ft.dfs.AssertNotCalled(c, "Snapshot", mock.Anything, mock.Anything)
+}
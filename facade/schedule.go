@@ -0,0 +1,163 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/schedule"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/statehistory"
+	"github.com/control-center/serviced/utils"
+)
+
+// AddScheduleProfile restricts a service and its children to run only during
+// the windows of the profile.  The master starts the service when a window
+// opens and stops it when the window closes.
+func (f *Facade) AddScheduleProfile(ctx datastore.Context, profile *schedule.Profile) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AddScheduleProfile"))
+	logger := plog.WithField("serviceid", profile.ServiceID)
+
+	if profile.ID == "" {
+		var err error
+		if profile.ID, err = utils.NewUUID36(); err != nil {
+			return err
+		}
+	}
+	if profile.Start.IsZero() {
+		profile.Start = time.Now()
+	}
+	profile.AppliedState = ""
+	if err := profile.ValidEntity(); err != nil {
+		return err
+	}
+	if _, err := f.serviceStore.Get(ctx, profile.ServiceID); datastore.IsErrNoSuchEntity(err) {
+		return fmt.Errorf("service %s not found", profile.ServiceID)
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not look up the service of the schedule profile")
+		return err
+	}
+
+	if err := f.scheduleStore.Put(ctx, profile); err != nil {
+		logger.WithError(err).Debug("Could not add schedule profile")
+		return err
+	}
+	logger.WithField("profileid", profile.ID).Info("Added schedule profile")
+	return f.ApplyScheduleProfiles(ctx)
+}
+
+// RemoveScheduleProfile deletes a schedule profile.  The service is left in
+// the state that the profile last applied.
+func (f *Facade) RemoveScheduleProfile(ctx datastore.Context, profileID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("RemoveScheduleProfile"))
+	logger := plog.WithField("profileid", profileID)
+
+	if _, err := f.scheduleStore.Get(ctx, profileID); datastore.IsErrNoSuchEntity(err) {
+		return fmt.Errorf("schedule profile %s not found", profileID)
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not look up schedule profile")
+		return err
+	}
+	if err := f.scheduleStore.Delete(ctx, profileID); err != nil {
+		logger.WithError(err).Debug("Could not remove schedule profile")
+		return err
+	}
+	logger.Info("Removed schedule profile")
+	return nil
+}
+
+// GetScheduleProfiles returns all of the schedule profiles, ordered by their
+// start time
+func (f *Facade) GetScheduleProfiles(ctx datastore.Context) ([]schedule.Profile, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetScheduleProfiles"))
+	profiles, err := f.scheduleStore.GetProfiles(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up schedule profiles")
+		return nil, err
+	}
+	sort.Sort(profilesByStart(profiles))
+	return profiles, nil
+}
+
+type profilesByStart []schedule.Profile
+
+func (p profilesByStart) Len() int           { return len(p) }
+func (p profilesByStart) Less(i, j int) bool { return p[i].Start.Before(p[j].Start) }
+func (p profilesByStart) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// ApplyScheduleProfiles starts or stops the service of each schedule profile
+// whose window opened or closed since the profile was last applied.  The
+// service is only scheduled on a transition, so a user may still start or
+// stop it by hand until the next transition.  Profiles of services that no
+// longer exist are removed.
+func (f *Facade) ApplyScheduleProfiles(ctx datastore.Context) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ApplyScheduleProfiles"))
+	profiles, err := f.scheduleStore.GetProfiles(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up schedule profiles")
+		return err
+	}
+
+	now := time.Now()
+	for i := range profiles {
+		profile := &profiles[i]
+		logger := plog.WithFields(log.Fields{
+			"profileid": profile.ID,
+			"serviceid": profile.ServiceID,
+		})
+
+		state := profile.State(now)
+		if state == profile.AppliedState {
+			continue
+		}
+
+		if _, err := f.serviceStore.Get(ctx, profile.ServiceID); datastore.IsErrNoSuchEntity(err) {
+			if err := f.scheduleStore.Delete(ctx, profile.ID); err != nil {
+				logger.WithError(err).Warn("Could not remove schedule profile of deleted service")
+			} else {
+				logger.Info("Removed schedule profile of deleted service")
+			}
+			continue
+		} else if err != nil {
+			logger.WithError(err).Warn("Could not look up the service of the schedule profile")
+			continue
+		}
+
+		desiredState := service.SVCStop
+		if state == schedule.StateRun {
+			desiredState = service.SVCRun
+		}
+		actorCtx := datastore.WithActor(ctx, statehistory.SourceScheduler, "")
+		count, err := f.ScheduleService(actorCtx, profile.ServiceID, true, desiredState)
+		if err != nil {
+			logger.WithError(err).Warn("Could not apply schedule profile")
+			continue
+		}
+
+		profile.AppliedState = state
+		if err := f.scheduleStore.Put(ctx, profile); err != nil {
+			logger.WithError(err).Warn("Could not update schedule profile")
+			continue
+		}
+		logger.WithFields(log.Fields{
+			"state":    state,
+			"services": count,
+		}).Info("Applied schedule profile")
+	}
+	return nil
+}
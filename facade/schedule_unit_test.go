@@ -0,0 +1,66 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package facade_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/schedule"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_AddScheduleProfile_Invalid(c *C) {
+	profile := &schedule.Profile{ServiceID: "svc", Duration: 24 * time.Hour, Repeat: schedule.RepeatDaily}
+	err := ft.Facade.AddScheduleProfile(ft.ctx, profile)
+	c.Assert(err, NotNil)
+	ft.scheduleStore.AssertNotCalled(c, "Put", ft.ctx, profile)
+}
+
+func (ft *FacadeUnitTest) Test_AddScheduleProfile_NoService(c *C) {
+	ft.serviceStore.On("Get", ft.ctx, "missing").Return(nil, datastore.ErrNoSuchEntity{})
+
+	profile := &schedule.Profile{ServiceID: "missing", Duration: time.Hour}
+	err := ft.Facade.AddScheduleProfile(ft.ctx, profile)
+	c.Assert(err, ErrorMatches, "service missing not found")
+	ft.scheduleStore.AssertNotCalled(c, "Put", ft.ctx, profile)
+}
+
+func (ft *FacadeUnitTest) Test_RemoveScheduleProfile_NotFound(c *C) {
+	ft.scheduleStore.On("Get", ft.ctx, "missing").Return(nil, datastore.ErrNoSuchEntity{})
+
+	err := ft.Facade.RemoveScheduleProfile(ft.ctx, "missing")
+	c.Assert(err, ErrorMatches, "schedule profile missing not found")
+	ft.scheduleStore.AssertNotCalled(c, "Delete", ft.ctx, "missing")
+}
+
+func (ft *FacadeUnitTest) Test_ApplyScheduleProfiles(c *C) {
+	now := time.Now()
+	profiles := []schedule.Profile{
+		// the window is open and the service was already started
+		{ID: "applied", ServiceID: "svc1", Start: now.Add(-time.Hour), Duration: 2 * time.Hour, AppliedState: schedule.StateRun},
+		// the service was deleted
+		{ID: "deleted", ServiceID: "svc2", Start: now.Add(-time.Hour), Duration: 2 * time.Hour, AppliedState: schedule.StateStop},
+	}
+	ft.scheduleStore.On("GetProfiles", ft.ctx).Return(profiles, nil)
+	ft.serviceStore.On("Get", ft.ctx, "svc2").Return(nil, datastore.ErrNoSuchEntity{})
+	ft.scheduleStore.On("Delete", ft.ctx, "deleted").Return(nil)
+
+	err := ft.Facade.ApplyScheduleProfiles(ft.ctx)
+	c.Assert(err, IsNil)
+	ft.serviceStore.AssertNotCalled(c, "Get", ft.ctx, "svc1")
+	ft.scheduleStore.AssertCalled(c, "Delete", ft.ctx, "deleted")
+	ft.scheduleStore.AssertNotCalled(c, "Put", ft.ctx, &profiles[0])
+}
@@ -14,6 +14,7 @@
 package facade
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -23,16 +24,19 @@ import (
 	"time"
 
 	log "github.com/Sirupsen/logrus"
-	"github.com/zenoss/glog"
 
 	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/commons/jsonpatch"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/applicationendpoint"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/domain/statehistory"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/validation"
@@ -58,11 +62,18 @@ var (
 	ErrTenantDoesNotMatch       = errors.New("facade: service tenants do not match")
 	ErrServiceMissingAssignment = errors.New("facade: service is missing an address assignment")
 	ErrServiceDuplicateEndpoint = errors.New("facade: duplicate endpoint found")
+	ErrServiceInvalidPriority   = errors.New("facade: service priority must be critical, high, normal, or low")
+	ErrServiceNoMacvlan         = errors.New("facade: service pool has no macvlan network")
+	ErrServiceMemoryMounts      = errors.New("facade: shared memory and tmpfs mounts exceed the memory of the hosts in the pool")
+	ErrServicePlacementHost     = errors.New("facade: service is pinned to a host that is not in its pool")
 )
 
 // AddService adds a service; return error if service already exists
 func (f *Facade) AddService(ctx datastore.Context, svc service.Service) (err error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("AddService"))
+	if err := f.checkNewServiceScope(ctx, svc.ParentServiceID); err != nil {
+		return err
+	}
 	var tenantID string
 	if svc.ParentServiceID == "" {
 		tenantID = svc.ID
@@ -79,7 +90,7 @@ func (f *Facade) addService(ctx datastore.Context, tenantID string, svc service.
 	store := f.serviceStore
 	// service add validation
 	if err := f.validateServiceAdd(ctx, &svc); err != nil {
-		glog.Errorf("Could not validate service %s (%s) for adding: %s", svc.Name, svc.ID, err)
+		plog.Errorf("Could not validate service %s (%s) for adding: %s", svc.Name, svc.ID, err)
 		return err
 	}
 	var configFiles []servicedefinition.ConfigFile
@@ -97,21 +108,21 @@ func (f *Facade) addService(ctx datastore.Context, tenantID string, svc service.
 	svc.UpdatedAt = time.Now()
 	svc.CreatedAt = svc.UpdatedAt
 	if err := store.Put(ctx, &svc); err != nil {
-		glog.Errorf("Could not create service %s (%s): %s", svc.Name, svc.ID, err)
+		plog.Errorf("Could not create service %s (%s): %s", svc.Name, svc.ID, err)
 		return err
 	}
-	glog.Infof("Created service %s (%s)", svc.Name, svc.ID)
+	plog.Infof("Created service %s (%s)", svc.Name, svc.ID)
 	// add the service configurations to the database
 	if err := f.updateServiceConfigs(ctx, svc.ID, configFiles, true); err != nil {
-		glog.Warningf("Could not set configurations to service %s (%s): %s", svc.Name, svc.ID, err)
+		plog.Warningf("Could not set configurations to service %s (%s): %s", svc.Name, svc.ID, err)
 	}
-	glog.Infof("Set configuration information for service %s (%s)", svc.Name, svc.ID)
+	plog.Infof("Set configuration information for service %s (%s)", svc.Name, svc.ID)
 	// sync the service with the coordinator
 	if err := f.syncService(ctx, tenantID, svc.ID, setLockOnCreate, setLockOnCreate); err != nil {
-		glog.Errorf("Could not sync service %s (%s): %s", svc.Name, svc.ID, err)
+		plog.Errorf("Could not sync service %s (%s): %s", svc.Name, svc.ID, err)
 		return err
 	}
-	glog.Infof("Synced service %s (%s) to the coordinator", svc.Name, svc.ID)
+	plog.Infof("Synced service %s (%s) to the coordinator", svc.Name, svc.ID)
 	return nil
 }
 
@@ -120,16 +131,36 @@ func (f *Facade) validateServiceAdd(ctx datastore.Context, svc *service.Service)
 	// verify that the service does not already exist
 	if _, err := store.Get(ctx, svc.ID); !datastore.IsErrNoSuchEntity(err) {
 		if err != nil {
-			glog.Errorf("Could not check the existance of service %s (%s): %s", svc.Name, svc.ID, err)
+			plog.Errorf("Could not check the existance of service %s (%s): %s", svc.Name, svc.ID, err)
 			return err
 		} else {
-			glog.Errorf("Could not add service %s (%s): %s", svc.Name, svc.ID, ErrServiceExists)
+			plog.Errorf("Could not add service %s (%s): %s", svc.Name, svc.ID, ErrServiceExists)
 			return ErrServiceExists
 		}
 	}
+	if _, ok := service.PriorityRank(svc.Priority); !ok {
+		plog.Errorf("Could not add service %s (%s) with priority %q: %s", svc.Name, svc.ID, svc.Priority, ErrServiceInvalidPriority)
+		return ErrServiceInvalidPriority
+	}
+	if err := svc.ValidContainerOptions(); err != nil {
+		plog.Errorf("Could not add service %s (%s) with invalid container options: %s", svc.Name, svc.ID, err)
+		return err
+	}
+	if err := f.validateServiceNetwork(ctx, svc); err != nil {
+		plog.Errorf("Could not add service %s (%s) with network mode %s: %s", svc.Name, svc.ID, svc.NetworkMode, err)
+		return err
+	}
+	if err := f.validateServiceMemoryMounts(ctx, svc); err != nil {
+		plog.Errorf("Could not add service %s (%s) with %d bytes of memory mounts: %s", svc.Name, svc.ID, svc.MemoryMounts(), err)
+		return err
+	}
+	if err := f.validateServicePlacement(ctx, svc); err != nil {
+		plog.Errorf("Could not add service %s (%s) with placement %+v: %s", svc.Name, svc.ID, svc.Placement, err)
+		return err
+	}
 	// verify no collision with the service name
 	if err := f.validateServiceName(ctx, svc); err != nil {
-		glog.Errorf("Could not add service %s to parent %s: %s", svc.Name, svc.ParentServiceID, err)
+		plog.Errorf("Could not add service %s to parent %s: %s", svc.Name, svc.ParentServiceID, err)
 		return err
 	}
 
@@ -139,11 +170,11 @@ func (f *Facade) validateServiceAdd(ctx datastore.Context, svc *service.Service)
 			if vhost.Enabled {
 				serviceID, application, err := f.zzk.GetVHost(vhost.Name)
 				if err != nil {
-					glog.Errorf("Could not check public endpoint for virtual host %s: %s", vhost.Name, err)
+					plog.Errorf("Could not check public endpoint for virtual host %s: %s", vhost.Name, err)
 					return err
 				}
 				if serviceID != "" || application != "" {
-					glog.Warningf("VHost %s already in use by another application %s (%s)", vhost.Name, serviceID, application)
+					plog.Warningf("VHost %s already in use by another application %s (%s)", vhost.Name, serviceID, application)
 					svc.Endpoints[i].VHostList[j].Enabled = false
 				}
 			}
@@ -153,17 +184,24 @@ func (f *Facade) validateServiceAdd(ctx datastore.Context, svc *service.Service)
 			if port.Enabled {
 				serviceID, application, err := f.zzk.GetPublicPort(port.PortAddr)
 				if err != nil {
-					glog.Errorf("Could not check public endpoint for port %s: %s", port.PortAddr, err)
+					plog.Errorf("Could not check public endpoint for port %s: %s", port.PortAddr, err)
 					return err
 				}
 				if serviceID != "" || application != "" {
-					glog.Warningf("Public port %s already in use by another application %s (%s)", port.PortAddr, serviceID, application)
+					plog.Warningf("Public port %s already in use by another application %s (%s)", port.PortAddr, serviceID, application)
 					svc.Endpoints[i].PortList[j].Enabled = false
 				}
 			}
 		}
 	}
 
+	// verify that the public ports do not collide with the host ports of other
+	// services in the pool
+	if err := f.validatePortClaims(ctx, svc); err != nil {
+		plog.Errorf("Could not add service %s (%s): %s", svc.Name, svc.ID, err)
+		return err
+	}
+
 	// set service defaults
 	svc.DesiredState = int(service.SVCStop) // new services must always be stopped
 	svc.DatabaseVersion = 0                 // create service set database version to 0
@@ -211,7 +249,7 @@ func (f *Facade) updateService(ctx datastore.Context, tenantID string, svc servi
 	store := f.serviceStore
 	cursvc, err := f.validateServiceUpdate(ctx, &svc)
 	if err != nil {
-		glog.Errorf("Could not validate service %s (%s) for update: %s", svc.Name, svc.ID, err)
+		plog.Errorf("Could not validate service %s (%s) for update: %s", svc.Name, svc.ID, err)
 		return err
 	}
 
@@ -228,7 +266,16 @@ func (f *Facade) updateService(ctx datastore.Context, tenantID string, svc servi
 		svc.OriginalConfigs = cursvc.OriginalConfigs
 	}
 	var configFiles []servicedefinition.ConfigFile
+	var conflicts map[string]*serviceconfigfile.Conflict
 	if svc.ConfigFiles != nil {
+		if migrate {
+			// keep the changes the user made to the previous version of the
+			// template
+			if conflicts, err = f.upgradeServiceConfigs(ctx, cursvc, &svc); err != nil {
+				plog.Errorf("Could not reconcile configurations of service %s (%s): %s", svc.Name, svc.ID, err)
+				return err
+			}
+		}
 		for _, configFile := range svc.ConfigFiles {
 			configFiles = append(configFiles, configFile)
 		}
@@ -238,21 +285,25 @@ func (f *Facade) updateService(ctx datastore.Context, tenantID string, svc servi
 	// write the service into the database
 	svc.UpdatedAt = time.Now()
 	if err := store.Put(ctx, &svc); err != nil {
-		glog.Errorf("Could not create service %s (%s): %s", svc.Name, svc.ID, err)
+		plog.Errorf("Could not create service %s (%s): %s", svc.Name, svc.ID, err)
 		return err
 	}
-	glog.Infof("Updated service %s (%s)", svc.Name, svc.ID)
+	plog.Infof("Updated service %s (%s)", svc.Name, svc.ID)
 
 	if err := f.updateServiceConfigs(ctx, svc.ID, configFiles, true); err != nil {
-		glog.Warningf("Could not set configurations to service %s (%s): %s", svc.Name, svc.ID, err)
+		plog.Warningf("Could not set configurations to service %s (%s): %s", svc.Name, svc.ID, err)
+	} else if conflicts != nil {
+		if err := f.setServiceConfigConflicts(ctx, svc.ID, conflicts); err != nil {
+			plog.Warningf("Could not record configuration conflicts of service %s (%s): %s", svc.Name, svc.ID, err)
+		}
 	}
-	glog.Infof("Set configuration information for service %s (%s)", svc.Name, svc.ID)
+	plog.Infof("Set configuration information for service %s (%s)", svc.Name, svc.ID)
 
 	// remove the service from coordinator if the pool has changed
 	if cursvc.PoolID != svc.PoolID {
 		if err := f.zzk.RemoveService(cursvc.PoolID, cursvc.ID); err != nil {
 			// synchronizer will eventually clean this service up
-			glog.Warningf("COORD: Could not delete service %s from pool %s: %s", cursvc.ID, cursvc.PoolID, err)
+			plog.Warningf("COORD: Could not delete service %s from pool %s: %s", cursvc.ID, cursvc.PoolID, err)
 			cursvc.DesiredState = int(service.SVCStop)
 			f.zzk.UpdateService(ctx, tenantID, cursvc, false, false)
 		}
@@ -260,10 +311,10 @@ func (f *Facade) updateService(ctx datastore.Context, tenantID string, svc servi
 
 	// sync the service with the coordinator
 	if err := f.syncService(ctx, tenantID, svc.ID, setLockOnUpdate, setLockOnUpdate); err != nil {
-		glog.Errorf("Could not sync service %s (%s): %s", svc.Name, svc.ID, err)
+		plog.Errorf("Could not sync service %s (%s): %s", svc.Name, svc.ID, err)
 		return err
 	}
-	glog.Infof("Synced service %s (%s) to the coordinator", svc.Name, svc.ID)
+	plog.Infof("Synced service %s (%s) to the coordinator", svc.Name, svc.ID)
 	return nil
 }
 
@@ -273,7 +324,27 @@ func (f *Facade) validateServiceUpdate(ctx datastore.Context, svc *service.Servi
 	// verify that the service exists
 	cursvc, err := store.Get(ctx, svc.ID)
 	if err != nil {
-		glog.Errorf("Could not load service %s (%s) from database: %s", svc.Name, svc.ID, err)
+		plog.Errorf("Could not load service %s (%s) from database: %s", svc.Name, svc.ID, err)
+		return nil, err
+	}
+	if _, ok := service.PriorityRank(svc.Priority); !ok {
+		plog.Errorf("Could not update service %s (%s) with priority %q: %s", svc.Name, svc.ID, svc.Priority, ErrServiceInvalidPriority)
+		return nil, ErrServiceInvalidPriority
+	}
+	if err := svc.ValidContainerOptions(); err != nil {
+		plog.Errorf("Could not update service %s (%s) with invalid container options: %s", svc.Name, svc.ID, err)
+		return nil, err
+	}
+	if err := f.validateServiceNetwork(ctx, svc); err != nil {
+		plog.Errorf("Could not update service %s (%s) with network mode %s: %s", svc.Name, svc.ID, svc.NetworkMode, err)
+		return nil, err
+	}
+	if err := f.validateServiceMemoryMounts(ctx, svc); err != nil {
+		plog.Errorf("Could not update service %s (%s) with %d bytes of memory mounts: %s", svc.Name, svc.ID, svc.MemoryMounts(), err)
+		return nil, err
+	}
+	if err := f.validateServicePlacement(ctx, svc); err != nil {
+		plog.Errorf("Could not update service %s (%s) with placement %+v: %s", svc.Name, svc.ID, svc.Placement, err)
 		return nil, err
 	}
 	// verify no collision with the service name
@@ -281,12 +352,12 @@ func (f *Facade) validateServiceUpdate(ctx datastore.Context, svc *service.Servi
 		// if the parent changed, make sure it shares the same tenant
 		if svc.ParentServiceID != cursvc.ParentServiceID {
 			if err := f.validateServiceTenant(ctx, svc.ParentServiceID, svc.ID); err != nil {
-				glog.Errorf("Could not validate tenant for updated service %s: %s", svc.ID, err)
+				plog.Errorf("Could not validate tenant for updated service %s: %s", svc.ID, err)
 				return nil, err
 			}
 		}
 		if err := f.validateServiceName(ctx, svc); err != nil {
-			glog.Errorf("Could not validate service name for updated service %s: %s", svc.ID, err)
+			plog.Errorf("Could not validate service name for updated service %s: %s", svc.ID, err)
 			return nil, err
 		}
 
@@ -302,11 +373,11 @@ func (f *Facade) validateServiceUpdate(ctx datastore.Context, svc *service.Servi
 			if vhost.Enabled {
 				serviceID, application, err := f.zzk.GetVHost(vhost.Name)
 				if err != nil {
-					glog.Errorf("Could not check public endpoint for virtual host %s: %s", vhost.Name, err)
+					plog.Errorf("Could not check public endpoint for virtual host %s: %s", vhost.Name, err)
 					return nil, err
 				}
 				if (serviceID != "" && serviceID != svc.ID) || (application != "" && application != ep.Application) {
-					glog.Errorf("VHost %s already in use by another application %s (%s)", vhost.Name, serviceID, application)
+					plog.Errorf("VHost %s already in use by another application %s (%s)", vhost.Name, serviceID, application)
 					return nil, fmt.Errorf("vhost %s is already in use", vhost.Name)
 				}
 			}
@@ -316,17 +387,23 @@ func (f *Facade) validateServiceUpdate(ctx datastore.Context, svc *service.Servi
 			if port.Enabled {
 				serviceID, application, err := f.zzk.GetPublicPort(port.PortAddr)
 				if err != nil {
-					glog.Errorf("Could not check public endpoint for port %s: %s", port.PortAddr, err)
+					plog.Errorf("Could not check public endpoint for port %s: %s", port.PortAddr, err)
 					return nil, err
 				}
 				if (serviceID != "" && serviceID != svc.ID) || (application != "" && application != ep.Application) {
-					glog.Errorf("Public port %s already in use by another application %s (%s)", port.PortAddr, serviceID, application)
+					plog.Errorf("Public port %s already in use by another application %s (%s)", port.PortAddr, serviceID, application)
 					return nil, fmt.Errorf("port %s is already in use", port.PortAddr)
 				}
 			}
 		}
 	}
 
+	// disallow claiming host ports that collide with other services in the pool
+	if err := f.validatePortClaims(ctx, svc); err != nil {
+		plog.Errorf("Could not validate host ports for updated service %s: %s", svc.ID, err)
+		return nil, err
+	}
+
 	// set read-only fields
 	svc.CreatedAt = cursvc.CreatedAt
 	svc.DeploymentID = cursvc.DeploymentID
@@ -360,7 +437,7 @@ func (f *Facade) validateServiceUpdate(ctx datastore.Context, svc *service.Servi
 	// verify the desired state of the service
 	if svc.DesiredState != int(service.SVCStop) {
 		if err := f.validateServiceStart(ctx, svc); err != nil {
-			glog.Warningf("Could not validate %s for starting: %s", svc.ID, err)
+			plog.Warningf("Could not validate %s for starting: %s", svc.ID, err)
 			svc.DesiredState = int(service.SVCStop)
 		}
 	}
@@ -375,14 +452,14 @@ func (f *Facade) validateServiceName(ctx datastore.Context, svc *service.Service
 	if svc.ParentServiceID != "" {
 		psvc, err := store.Get(ctx, svc.ParentServiceID)
 		if err != nil {
-			glog.Errorf("Could not verify the existance of parent %s for service %s: %s", svc.ParentServiceID, svc.Name, err)
+			plog.Errorf("Could not verify the existance of parent %s for service %s: %s", svc.ParentServiceID, svc.Name, err)
 			return err
 		}
 		svc.DeploymentID = psvc.DeploymentID
 	}
 	cursvc, err := store.FindChildService(ctx, svc.DeploymentID, svc.ParentServiceID, svc.Name)
 	if err != nil {
-		glog.Errorf("Could not check the service name %s for parent %s: %s", svc.Name, svc.ParentServiceID, err)
+		plog.Errorf("Could not check the service name %s for parent %s: %s", svc.Name, svc.ParentServiceID, err)
 		return err
 	}
 	if cursvc != nil {
@@ -392,6 +469,74 @@ func (f *Facade) validateServiceName(ctx datastore.Context, svc *service.Service
 }
 
 // validateServiceTenant ensures the services are on the same tenant
+// validateServiceNetwork verifies that the pool of a macvlan service has a
+// macvlan network to attach to.
+func (f *Facade) validateServiceNetwork(ctx datastore.Context, svc *service.Service) error {
+	if svc.NetworkMode != servicedefinition.NetworkMacvlan {
+		return nil
+	}
+	var p pool.ResourcePool
+	if err := f.poolStore.Get(ctx, pool.Key(svc.PoolID), &p); err != nil {
+		return err
+	}
+	if !p.Macvlan.Enabled() {
+		return ErrServiceNoMacvlan
+	}
+	return nil
+}
+
+// validateServiceMemoryMounts verifies that the shared memory and tmpfs
+// mounts of a service fit in the memory of at least one host in its pool.
+func (f *Facade) validateServiceMemoryMounts(ctx datastore.Context, svc *service.Service) error {
+	size := svc.MemoryMounts()
+	if size == 0 {
+		return nil
+	}
+	hosts, err := f.hostStore.FindHostsWithPoolID(ctx, svc.PoolID)
+	if err != nil {
+		return err
+	} else if len(hosts) == 0 {
+		return nil
+	}
+	for _, h := range hosts {
+		if size < h.Memory {
+			return nil
+		}
+	}
+	return ErrServiceMemoryMounts
+}
+
+// validateServicePlacement verifies that the hosts to which the service is
+// pinned are in the pool of the service
+func (f *Facade) validateServicePlacement(ctx datastore.Context, svc *service.Service) error {
+	if len(svc.Placement.Hosts) == 0 && len(svc.Placement.Instances) == 0 {
+		return nil
+	}
+	hosts, err := f.hostStore.FindHostsWithPoolID(ctx, svc.PoolID)
+	if err != nil {
+		return err
+	}
+	isPoolHost := func(name string) bool {
+		for _, h := range hosts {
+			if h.ID == name || h.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range svc.Placement.Hosts {
+		if !isPoolHost(name) {
+			return ErrServicePlacementHost
+		}
+	}
+	for _, instance := range svc.Placement.Instances {
+		if !isPoolHost(instance.Host) {
+			return ErrServicePlacementHost
+		}
+	}
+	return nil
+}
+
 func (f *Facade) validateServiceTenant(ctx datastore.Context, serviceA, serviceB string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("validateServiceTenant"))
 	if serviceA == "" || serviceB == "" {
@@ -399,12 +544,12 @@ func (f *Facade) validateServiceTenant(ctx datastore.Context, serviceA, serviceB
 	}
 	tenantA, err := f.GetTenantID(ctx, serviceA)
 	if err != nil {
-		glog.Errorf("Could not look up tenant for service %s: %s", serviceA, err)
+		plog.Errorf("Could not look up tenant for service %s: %s", serviceA, err)
 		return err
 	}
 	tenantB, err := f.GetTenantID(ctx, serviceB)
 	if err != nil {
-		glog.Errorf("Could not look up tenant for service %s: %s", serviceB, err)
+		plog.Errorf("Could not look up tenant for service %s: %s", serviceB, err)
 		return err
 	}
 	if tenantA != tenantB {
@@ -417,12 +562,15 @@ func (f *Facade) validateServiceTenant(ctx datastore.Context, serviceA, serviceB
 // start.
 func (f *Facade) validateServiceStart(ctx datastore.Context, svc *service.Service) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("validateServiceStart"))
+	if svc.EmergencyShutdown {
+		return ErrEmergencyShutdown
+	}
 	// ensure that all endpoints are available
 	for _, ep := range svc.Endpoints {
 		if ep.IsConfigurable() {
 			as, err := f.FindAssignmentByServiceEndpoint(ctx, svc.ID, ep.Name)
 			if err != nil {
-				glog.Errorf("Could not look up assignment %s for service %s: %s", ep.Name, svc.ID, err)
+				plog.Errorf("Could not look up assignment %s for service %s: %s", ep.Name, svc.ID, err)
 				return err
 			}
 			if as == nil {
@@ -438,11 +586,11 @@ func (f *Facade) syncService(ctx datastore.Context, tenantID, serviceID string,
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("syncService"))
 	svc, err := f.GetService(ctx, serviceID)
 	if err != nil {
-		glog.Errorf("Could not get service %s to sync: %s", serviceID, err)
+		plog.Errorf("Could not get service %s to sync: %s", serviceID, err)
 		return err
 	}
 	if err := f.zzk.UpdateService(ctx, tenantID, svc, setLockOnCreate, setLockOnUpdate); err != nil {
-		glog.Errorf("Could not sync service %s to the coordinator: %s", serviceID, err)
+		plog.Errorf("Could not sync service %s to the coordinator: %s", serviceID, err)
 		return err
 	}
 	return nil
@@ -454,7 +602,7 @@ func (f *Facade) RestoreServices(ctx datastore.Context, tenantID string, svcs []
 	// get pools
 	pools, err := f.GetResourcePools(ctx)
 	if err != nil {
-		glog.Errorf("Could not look up resource pools: %s", err)
+		plog.Errorf("Could not look up resource pools: %s", err)
 		return err
 	}
 	poolsmap := make(map[string]struct{})
@@ -479,25 +627,25 @@ func (f *Facade) RestoreServices(ctx datastore.Context, tenantID string, svcs []
 			svc.DatabaseVersion = 0
 			svc.DesiredState = int(service.SVCStop)
 			if _, ok := poolsmap[svc.PoolID]; !ok {
-				glog.Warningf("Could not find pool %s for service %s (%s).  Setting pool to default.", svc.PoolID, svc.Name, svc.ID)
+				plog.Warningf("Could not find pool %s for service %s (%s).  Setting pool to default.", svc.PoolID, svc.Name, svc.ID)
 				svc.PoolID = "default"
 			}
 			if err := f.addService(ctx, tenantID, svc, true); err != nil {
-				glog.Errorf("Could not restore service %s (%s): %s", svc.Name, svc.ID, err)
+				plog.Errorf("Could not restore service %s (%s): %s", svc.Name, svc.ID, err)
 				return err
 			}
 			if err := f.restoreIPs(ctx, &svc); err != nil {
-				glog.Warningf("Could not restore address assignments for service %s (%s): %s", svc.Name, svc.ID, err)
+				plog.Warningf("Could not restore address assignments for service %s (%s): %s", svc.Name, svc.ID, err)
 			}
 			if err := traverse(svc.ID); err != nil {
 				return err
 			}
-			glog.Infof("Restored service %s (%s)", svc.Name, svc.ID)
+			plog.Infof("Restored service %s (%s)", svc.Name, svc.ID)
 		}
 		return nil
 	}
 	if err := traverse(""); err != nil {
-		glog.Errorf("Error while rolling back services: %s", err)
+		plog.Errorf("Error while rolling back services: %s", err)
 		return err
 	}
 	return nil
@@ -507,10 +655,16 @@ func (f *Facade) RestoreServices(ctx datastore.Context, tenantID string, svcs []
 func (f *Facade) MigrateServices(ctx datastore.Context, req dao.ServiceMigrationRequest) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("MigrateServices"))
 	var svcAll []service.Service
+	// apply service patches
+	modified, err := f.applyServicePatches(ctx, req.Modified, req.Patches)
+	if err != nil {
+		return err
+	}
+	req.Modified = modified
 	// validate service updates
 	for _, svc := range req.Modified {
 		if _, err := f.validateServiceUpdate(ctx, svc); err != nil {
-			glog.Errorf("Could not validate service %s (%s) for update: %s", svc.Name, svc.ID, err)
+			plog.Errorf("Could not validate service %s (%s) for update: %s", svc.Name, svc.ID, err)
 			return err
 		}
 		svcAll = append(svcAll, *svc)
@@ -518,10 +672,10 @@ func (f *Facade) MigrateServices(ctx datastore.Context, req dao.ServiceMigration
 	// validate service adds
 	for _, svc := range req.Added {
 		if err := f.validateServiceAdd(ctx, svc); err != nil {
-			glog.Errorf("Could not validate service %s (%s) for add: %s", svc.Name, svc.ID, err)
+			plog.Errorf("Could not validate service %s (%s) for add: %s", svc.Name, svc.ID, err)
 			return err
 		} else if svc.ID, err = utils.NewUUID36(); err != nil {
-			glog.Errorf("Could not generate id for service %s: %s", svc.ID, err)
+			plog.Errorf("Could not generate id for service %s: %s", svc.ID, err)
 			return err
 		}
 		svcAll = append(svcAll, *svc)
@@ -530,17 +684,21 @@ func (f *Facade) MigrateServices(ctx datastore.Context, req dao.ServiceMigration
 	for _, sdreq := range req.Deploy {
 		svcs, err := f.validateServiceDeployment(ctx, sdreq.ParentID, &sdreq.Service)
 		if err != nil {
-			glog.Errorf("Could not validate service %s for deployment: %s", sdreq.Service.Name, err)
+			plog.Errorf("Could not validate service %s for deployment: %s", sdreq.Service.Name, err)
 			return err
 		}
 		svcAll = append(svcAll, svcs...)
 	}
 	// validate service migration
 	if err := f.validateServiceMigration(ctx, svcAll); err != nil {
-		glog.Errorf("Could not validate migration of services: %s", err)
+		plog.Errorf("Could not validate migration of services: %s", err)
 		return err
 	}
-	glog.Infof("Validation checks passed for service migration")
+	plog.Infof("Validation checks passed for service migration")
+	if req.DryRun {
+		plog.Infof("Dry run of service migration: %d modified, %d added, %d deployed", len(req.Modified), len(req.Added), len(req.Deploy))
+		return nil
+	}
 
 	// Do migration
 	for _, svc := range req.Modified {
@@ -555,24 +713,67 @@ func (f *Facade) MigrateServices(ctx datastore.Context, req dao.ServiceMigration
 	}
 	for _, sdreq := range req.Deploy {
 		if _, err := f.DeployService(ctx, "", sdreq.ParentID, false, sdreq.Service); err != nil {
-			glog.Errorf("Could not deploy service definition {%+v}: %s", sdreq.Service, err)
+			plog.Errorf("Could not deploy service definition {%+v}: %s", sdreq.Service, err)
 			return err
 		}
 	}
-	glog.Infof("Service migration completed successfully")
+	plog.Infof("Service migration completed successfully")
 	return nil
 }
 
+// applyServicePatches applies each patch to the current copy of its service
+// and returns the modified services along with the patched services.
+func (f *Facade) applyServicePatches(ctx datastore.Context, modified []*service.Service, patches []*dao.ServicePatch) ([]*service.Service, error) {
+	ids := make(map[string]struct{})
+	for _, svc := range modified {
+		ids[svc.ID] = struct{}{}
+	}
+	for _, p := range patches {
+		if _, ok := ids[p.ServiceID]; ok {
+			err := fmt.Errorf("service %s is modified more than once", p.ServiceID)
+			plog.Errorf("Could not patch service %s: %s", p.ServiceID, err)
+			return nil, err
+		}
+		ids[p.ServiceID] = struct{}{}
+		svc, err := f.serviceStore.Get(ctx, p.ServiceID)
+		if err != nil {
+			plog.Errorf("Could not get service %s to patch: %s", p.ServiceID, err)
+			return nil, err
+		}
+		doc, err := json.Marshal(svc)
+		if err != nil {
+			plog.Errorf("Could not marshal service %s (%s): %s", svc.Name, svc.ID, err)
+			return nil, err
+		}
+		if doc, err = jsonpatch.Apply(doc, p.Patch); err != nil {
+			plog.Errorf("Could not patch service %s (%s): %s", svc.Name, svc.ID, err)
+			return nil, err
+		}
+		patched := &service.Service{}
+		if err := json.Unmarshal(doc, patched); err != nil {
+			plog.Errorf("Could not unmarshal patched service %s (%s): %s", svc.Name, svc.ID, err)
+			return nil, err
+		}
+		if patched.ID != svc.ID {
+			err := fmt.Errorf("patch cannot change the id of service %s", svc.ID)
+			plog.Errorf("Could not patch service %s (%s): %s", svc.Name, svc.ID, err)
+			return nil, err
+		}
+		modified = append(modified, patched)
+	}
+	return modified, nil
+}
+
 func (f *Facade) SyncServiceRegistry(ctx datastore.Context, svc *service.Service) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("SyncServiceRegistry"))
 	tenantID, err := f.GetTenantID(datastore.Get(), svc.ID)
 	if err != nil {
-		glog.Errorf("Could not check tenant of service %s (%s): %s", svc.Name, svc.ID, err)
+		plog.Errorf("Could not check tenant of service %s (%s): %s", svc.Name, svc.ID, err)
 		return err
 	}
 	err = f.zzk.SyncServiceRegistry(ctx, tenantID, svc)
 	if err != nil {
-		glog.Errorf("Could not sync public endpoints for service %s (%s): %s", svc.Name, svc.ID, err)
+		plog.Errorf("Could not sync public endpoints for service %s (%s): %s", svc.Name, svc.ID, err)
 		return err
 	}
 	return nil
@@ -583,7 +784,7 @@ func (f *Facade) validateServiceDeployment(ctx datastore.Context, parentID strin
 	store := f.serviceStore
 	parent, err := store.Get(ctx, parentID)
 	if err != nil {
-		glog.Errorf("Could not get parent service %s: %s", parentID, err)
+		plog.Errorf("Could not get parent service %s: %s", parentID, err)
 		return nil, err
 	}
 	// recursively create services
@@ -592,7 +793,7 @@ func (f *Facade) validateServiceDeployment(ctx datastore.Context, parentID strin
 		var svcs []service.Service
 		svc, err := service.BuildService(*sd, parent.ID, parent.PoolID, int(service.SVCStop), parent.DeploymentID)
 		if err != nil {
-			glog.Errorf("Could not create service %s: %s", sd.Name, err)
+			plog.Errorf("Could not create service %s: %s", sd.Name, err)
 			return nil, err
 		}
 		svcs = append(svcs, *svc)
@@ -617,7 +818,7 @@ func (f *Facade) validateServiceMigration(ctx datastore.Context, svcs []service.
 		// check for name uniqueness within the set of new/modified/deployed services
 		if svcNameMap, ok := svcParentMapNameMap[svc.ParentServiceID]; ok {
 			if _, ok := svcNameMap[svc.Name]; ok {
-				glog.Errorf("Found a collision for service name %s and parent %s", svc.Name, svc.ParentServiceID)
+				plog.Errorf("Found a collision for service name %s and parent %s", svc.Name, svc.ParentServiceID)
 				return ErrServiceCollision
 			}
 			svcParentMapNameMap[svc.ParentServiceID][svc.Name] = struct{}{}
@@ -629,7 +830,7 @@ func (f *Facade) validateServiceMigration(ctx datastore.Context, svcs []service.
 		for _, ep := range svc.Endpoints {
 			if ep.Purpose == "export" {
 				if _, ok := endpointMap[ep.Application]; ok {
-					glog.Errorf("Endpoint %s in migrated service %s is a duplicate of an endpoint in one of the other migrated services", svc.Name, ep.Application)
+					plog.Errorf("Endpoint %s in migrated service %s is a duplicate of an endpoint in one of the other migrated services", svc.Name, ep.Application)
 					return ErrServiceDuplicateEndpoint
 				}
 				endpointMap[ep.Application] = struct{}{}
@@ -642,7 +843,7 @@ func (f *Facade) validateServiceMigration(ctx datastore.Context, svcs []service.
 		// Note - this is not the most performant way to do this, but migration is not a
 		// performance-critical operation, so no-harm/no-foul.
 		if err := f.validateServiceEndpoints(ctx, &svc); err != nil {
-			glog.Errorf("Migrated service %s has a duplicate endpoint: %s", svc.Name, err)
+			plog.Errorf("Migrated service %s has a duplicate endpoint: %s", svc.Name, err)
 			return ErrServiceDuplicateEndpoint
 		}
 	}
@@ -653,7 +854,7 @@ func (f *Facade) RemoveService(ctx datastore.Context, id string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("RemoveService"))
 	tenantID, err := f.GetTenantID(ctx, id)
 	if err != nil {
-		glog.Errorf("Could not get tenant of service %s: %s", id, err)
+		plog.Errorf("Could not get tenant of service %s: %s", id, err)
 		return err
 	}
 	if err := f.lockTenant(ctx, tenantID); err != nil {
@@ -661,12 +862,12 @@ func (f *Facade) RemoveService(ctx datastore.Context, id string) error {
 	}
 	defer f.retryUnlockTenant(ctx, tenantID, nil, time.Second)
 	if err := f.removeService(ctx, id); err != nil {
-		glog.Errorf("Could not remove service %s: %s", id, err)
+		plog.Errorf("Could not remove service %s: %s", id, err)
 		return err
 	}
 	if tenantID == id {
 		if err := f.dfs.Destroy(tenantID); err != nil {
-			glog.Errorf("Could not destroy volume for tenant %s: %s", tenantID, err)
+			plog.Errorf("Could not destroy volume for tenant %s: %s", tenantID, err)
 			return err
 		}
 		f.zzk.RemoveTenantExports(tenantID)
@@ -682,29 +883,32 @@ func (f *Facade) removeService(ctx datastore.Context, id string) error {
 		// remove all address assignments
 		for _, endpoint := range svc.Endpoints {
 			if assignment, err := f.FindAssignmentByServiceEndpoint(ctx, svc.ID, endpoint.Name); err != nil {
-				glog.Errorf("Could not find address assignment %s for service %s (%s): %s", endpoint.Name, svc.Name, svc.ID, err)
+				plog.Errorf("Could not find address assignment %s for service %s (%s): %s", endpoint.Name, svc.Name, svc.ID, err)
 				return err
 			} else if assignment != nil {
 				if err := f.RemoveAddressAssignment(ctx, assignment.ID); err != nil {
-					glog.Errorf("Could not remove address assignment %s from service %s (%s): %s", endpoint.Name, svc.Name, svc.ID, err)
+					plog.Errorf("Could not remove address assignment %s from service %s (%s): %s", endpoint.Name, svc.Name, svc.ID, err)
 					return err
 				}
 			}
 			endpoint.RemoveAssignment()
 		}
 		if err := f.zzk.RemoveServiceEndpoints(svc.ID); err != nil {
-			glog.Errorf("Could not remove public endpoints for service %s (%s) from zookeeper: %s", svc.Name, svc.ID, err)
+			plog.Errorf("Could not remove public endpoints for service %s (%s) from zookeeper: %s", svc.Name, svc.ID, err)
 			return err
 		}
 		if err := f.zzk.RemoveService(svc.PoolID, svc.ID); err != nil {
-			glog.Errorf("Could not remove service %s (%s) from zookeeper: %s", svc.Name, svc.ID, err)
+			plog.Errorf("Could not remove service %s (%s) from zookeeper: %s", svc.Name, svc.ID, err)
 			return err
 		}
 
 		if err := store.Delete(ctx, svc.ID); err != nil {
-			glog.Errorf("Error while removing service %s (%s): %s", svc.Name, svc.ID, err)
+			plog.Errorf("Error while removing service %s (%s): %s", svc.Name, svc.ID, err)
 			return err
 		}
+		if err := f.historyStore.Delete(ctx, svc.ID); err != nil && !datastore.IsErrNoSuchEntity(err) {
+			plog.Warningf("Could not remove the state history of service %s (%s): %s", svc.Name, svc.ID, err)
+		}
 
 		f.serviceCache.RemoveIfParentChanged(svc.ID, svc.ParentServiceID)
 		return nil
@@ -713,7 +917,7 @@ func (f *Facade) removeService(ctx datastore.Context, id string) error {
 
 func (f *Facade) GetPoolForService(ctx datastore.Context, id string) (string, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetPoolForService"))
-	glog.V(3).Infof("Facade.GetPoolForService: id=%s", id)
+	plog.Debugf("Facade.GetPoolForService: id=%s", id)
 	store := f.serviceStore
 	svc, err := store.Get(ctx, id)
 	if err != nil {
@@ -746,7 +950,7 @@ func (f *Facade) GetImageIDs(ctx datastore.Context) ([]string, error) {
 
 func (f *Facade) GetHealthChecksForService(ctx datastore.Context, serviceID string) (map[string]health.HealthCheck, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetHealthChecksForService"))
-	glog.V(3).Infof("Facade.GetHealthChecksForService: id=%s", serviceID)
+	plog.Debugf("Facade.GetHealthChecksForService: id=%s", serviceID)
 	store := f.serviceStore
 	svc, err := store.Get(ctx, serviceID)
 	if err != nil {
@@ -789,7 +993,7 @@ func (f *Facade) GetServicesByImage(ctx datastore.Context, imageID string) ([]se
 
 func (f *Facade) GetService(ctx datastore.Context, id string) (*service.Service, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetService"))
-	glog.V(3).Infof("Facade.GetService: id=%s", id)
+	plog.Debugf("Facade.GetService: id=%s", id)
 	store := f.serviceStore
 	svc, err := store.Get(ctx, id)
 	if err != nil {
@@ -798,7 +1002,7 @@ func (f *Facade) GetService(ctx datastore.Context, id string) (*service.Service,
 	if err = f.fillOutService(ctx, svc); err != nil {
 		return nil, err
 	}
-	glog.V(3).Infof("Facade.GetService: id=%s, service=%+v, err=%s", id, svc, err)
+	plog.Debugf("Facade.GetService: id=%s, service=%+v, err=%s", id, svc, err)
 	return svc, nil
 }
 
@@ -852,20 +1056,20 @@ func (f *Facade) evaluateService(ctx datastore.Context, svc *service.Service, in
 // GetServices looks up all services. Allows filtering by tenant ID, name (regular expression), and/or update time.
 func (f *Facade) GetServices(ctx datastore.Context, request dao.EntityRequest) ([]service.Service, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetServices"))
-	glog.V(3).Infof("Facade.GetServices")
+	plog.Debugf("Facade.GetServices")
 	store := f.serviceStore
 	var services []service.Service
 	var err error
 	if request.(dao.ServiceRequest).UpdatedSince != 0 {
 		services, err = store.GetUpdatedServices(ctx, request.(dao.ServiceRequest).UpdatedSince)
 		if err != nil {
-			glog.Error("Facade.GetServices: err=", err)
+			plog.Error("Facade.GetServices: err=", err)
 			return nil, err
 		}
 	} else {
 		services, err = store.GetServices(ctx)
 		if err != nil {
-			glog.Error("Facade.GetServices: err=", err)
+			plog.Error("Facade.GetServices: err=", err)
 			return nil, err
 		}
 	}
@@ -875,13 +1079,13 @@ func (f *Facade) GetServices(ctx datastore.Context, request dao.EntityRequest) (
 
 	switch v := request.(type) {
 	case dao.ServiceRequest:
-		glog.V(3).Infof("request: %+v", request)
+		plog.Debugf("request: %+v", request)
 
 		// filter by the name provided
 		if request.(dao.ServiceRequest).NameRegex != "" {
 			services, err = filterByNameRegex(request.(dao.ServiceRequest).NameRegex, services)
 			if err != nil {
-				glog.Error("Facade.GetServices: err=", err)
+				plog.Error("Facade.GetServices: err=", err)
 				return nil, err
 			}
 		}
@@ -890,15 +1094,15 @@ func (f *Facade) GetServices(ctx datastore.Context, request dao.EntityRequest) (
 		if request.(dao.ServiceRequest).TenantID != "" {
 			services, err = f.filterByTenantID(ctx, request.(dao.ServiceRequest).TenantID, services)
 			if err != nil {
-				glog.Error("Facade.GetServices: err=", err)
+				plog.Error("Facade.GetServices: err=", err)
 				return nil, err
 			}
 		}
 
-		return services, nil
+		return f.filterServicesInScope(ctx, services)
 	default:
 		err := fmt.Errorf("Bad request type %v: %+v", v, request)
-		glog.V(2).Info("Facade.GetServices: err=", err)
+		plog.Debug("Facade.GetServices: err=", err)
 		return nil, err
 	}
 }
@@ -916,11 +1120,11 @@ func (f *Facade) GetAllServices(ctx datastore.Context) ([]service.Service, error
 // GetServicesByPool looks up all services in a particular pool
 func (f *Facade) GetServicesByPool(ctx datastore.Context, poolID string) ([]service.Service, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetServicesByPool"))
-	glog.V(3).Infof("Facade.GetServicesByPool")
+	plog.Debugf("Facade.GetServicesByPool")
 	store := f.serviceStore
 	results, err := store.GetServicesByPool(ctx, poolID)
 	if err != nil {
-		glog.Error("Facade.GetServicesByPool: err=", err)
+		plog.Error("Facade.GetServicesByPool: err=", err)
 		return results, err
 	}
 
@@ -937,28 +1141,28 @@ func (f *Facade) GetServicesByPool(ctx datastore.Context, poolID string) ([]serv
 // GetTaggedServices looks up all services with the specified tags. Allows filtering by tenant ID and/or name (regular expression).
 func (f *Facade) GetTaggedServices(ctx datastore.Context, request dao.EntityRequest) ([]service.Service, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetTaggedServices"))
-	glog.V(3).Infof("Facade.GetTaggedServices")
+	plog.Debugf("Facade.GetTaggedServices")
 
 	store := f.serviceStore
 	switch v := request.(type) {
 	case []string:
 		results, err := store.GetTaggedServices(ctx, v...)
 		if err != nil {
-			glog.Error("Facade.GetTaggedServices: err=", err)
+			plog.Error("Facade.GetTaggedServices: err=", err)
 			return nil, err
 		}
 		if err = f.fillOutServices(ctx, results); err != nil {
 			return nil, err
 		}
-		glog.V(2).Infof("Facade.GetTaggedServices: services=%v", results)
-		return results, nil
+		plog.Debugf("Facade.GetTaggedServices: services=%v", results)
+		return f.filterServicesInScope(ctx, results)
 	case dao.ServiceRequest:
-		glog.V(3).Infof("request: %+v", request)
+		plog.Debugf("request: %+v", request)
 
 		// Get the tagged services
 		services, err := store.GetTaggedServices(ctx, request.(dao.ServiceRequest).Tags...)
 		if err != nil {
-			glog.Error("Facade.GetTaggedServices: err=", err)
+			plog.Error("Facade.GetTaggedServices: err=", err)
 			return nil, err
 		}
 		if err = f.fillOutServices(ctx, services); err != nil {
@@ -969,7 +1173,7 @@ func (f *Facade) GetTaggedServices(ctx datastore.Context, request dao.EntityRequ
 		if request.(dao.ServiceRequest).NameRegex != "" {
 			services, err = filterByNameRegex(request.(dao.ServiceRequest).NameRegex, services)
 			if err != nil {
-				glog.Error("Facade.GetTaggedServices: err=", err)
+				plog.Error("Facade.GetTaggedServices: err=", err)
 				return nil, err
 			}
 		}
@@ -978,15 +1182,15 @@ func (f *Facade) GetTaggedServices(ctx datastore.Context, request dao.EntityRequ
 		if request.(dao.ServiceRequest).TenantID != "" {
 			services, err = f.filterByTenantID(ctx, request.(dao.ServiceRequest).TenantID, services)
 			if err != nil {
-				glog.Error("Facade.GetTaggedServices: err=", err)
+				plog.Error("Facade.GetTaggedServices: err=", err)
 				return nil, err
 			}
 		}
 
-		return services, nil
+		return f.filterServicesInScope(ctx, services)
 	default:
 		err := fmt.Errorf("Bad request type: %v", v)
-		glog.V(2).Info("Facade.GetTaggedServices: err=", err)
+		plog.Debug("Facade.GetTaggedServices: err=", err)
 		return nil, err
 	}
 }
@@ -994,7 +1198,7 @@ func (f *Facade) GetTaggedServices(ctx datastore.Context, request dao.EntityRequ
 // The tenant id is the root service uuid. Walk the service tree to root to find the tenant id.
 func (f *Facade) GetTenantID(ctx datastore.Context, serviceID string) (string, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetTenantID"))
-	glog.V(3).Infof("Facade.GetTenantId: %s", serviceID)
+	plog.Debugf("Facade.GetTenantId: %s", serviceID)
 	gs := func(id string) (service.Service, error) {
 		return f.getService(ctx, id)
 	}
@@ -1027,7 +1231,19 @@ func (f *Facade) GetServiceEndpoints(ctx datastore.Context, serviceID string, re
 	}
 
 	sort.Sort(applicationendpoint.ApplicationEndpointSlice(appEndpoints))
-	return applicationendpoint.BuildEndpointReports(appEndpoints), nil
+	reports := applicationendpoint.BuildEndpointReports(appEndpoints)
+	if validate && len(states) > 0 {
+		tenantID, err := f.GetTenantID(ctx, svc.ID)
+		if err != nil {
+			err = fmt.Errorf("Could not look up tenant for service %s (%s): %s", svc.Name, svc.ID, err)
+			return nil, err
+		}
+		if err := f.verifyEndpoints(ctx, tenantID, reports); err != nil {
+			err = fmt.Errorf("Could not verify endpoints for service %s (%s): %s", svc.Name, svc.ID, err)
+			return nil, err
+		}
+	}
+	return reports, nil
 }
 
 // Get a list of exported endpoints defined for the service
@@ -1097,11 +1313,11 @@ func getEndpointsFromState(state zkservice.State, reportImports, reportExports b
 // if childName matches the service's name. If so, it returns it.
 func (f *Facade) FindChildService(ctx datastore.Context, parentServiceID string, childName string) (*service.Service, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("FindChildService"))
-	glog.V(3).Infof("Facade.FindChildService")
+	plog.Debugf("Facade.FindChildService")
 	store := f.serviceStore
 	parentService, err := store.Get(ctx, parentServiceID)
 	if err != nil {
-		glog.Errorf("Could not look up service %s: %s", parentServiceID, err)
+		plog.Errorf("Could not look up service %s: %s", parentServiceID, err)
 		return nil, err
 	} else if parentService == nil {
 		err := fmt.Errorf("parent does not exist")
@@ -1114,30 +1330,73 @@ func (f *Facade) FindChildService(ctx datastore.Context, parentServiceID string,
 // ScheduleService changes a service's desired state and returns the number of affected services
 func (f *Facade) ScheduleService(ctx datastore.Context, serviceID string, autoLaunch bool, desiredState service.DesiredState) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("ScheduleService"))
-	tenantID, err := f.GetTenantID(ctx, serviceID)
-	if err != nil {
-		return 0, err
+	return f.ScheduleServices(ctx, []string{serviceID}, autoLaunch, desiredState)
+}
+
+// ScheduleServices changes the desired state of several services at once and
+// returns the number of affected services.  Each tenant is locked only once,
+// and every service is validated before any of them is scheduled.
+func (f *Facade) ScheduleServices(ctx datastore.Context, serviceIDs []string, autoLaunch bool, desiredState service.DesiredState) (int, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ScheduleServices"))
+	tenantIDs := make(map[string]string)
+	tenants := []string{}
+	for _, serviceID := range serviceIDs {
+		if _, ok := tenantIDs[serviceID]; ok {
+			continue
+		}
+		tenantID, err := f.GetTenantID(ctx, serviceID)
+		if err != nil {
+			return 0, err
+		}
+		tenantIDs[serviceID] = tenantID
+		if !utils.StringInSlice(tenantID, tenants) {
+			tenants = append(tenants, tenantID)
+		}
 	}
-	mutex := getTenantLock(tenantID)
-	mutex.RLock()
-	defer mutex.RUnlock()
-	return f.scheduleService(ctx, tenantID, serviceID, autoLaunch, desiredState, false)
+
+	// lock the tenants in a consistent order
+	sort.Strings(tenants)
+	for _, tenantID := range tenants {
+		mutex := getTenantLock(tenantID)
+		mutex.RLock()
+		defer mutex.RUnlock()
+	}
+	return f.scheduleServices(ctx, tenantIDs, serviceIDs, autoLaunch, desiredState)
 }
 
 func (f *Facade) scheduleService(ctx datastore.Context, tenantID, serviceID string, autoLaunch bool, desiredState service.DesiredState, locked bool) (int, error) {
-	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade_scheduleService"))
-	glog.V(4).Infof("Facade.ScheduleService %s (%s)", serviceID, desiredState)
+	return f.scheduleServices(ctx, map[string]string{serviceID: tenantID}, []string{serviceID}, autoLaunch, desiredState)
+}
 
-	// Build a list of services to be scheduled
-	svcs := []service.Service{}
-	visitor := func(svc *service.Service) error {
-		svcs = append(svcs, *svc)
-		return nil
-	}
-	err := f.walkServices(ctx, serviceID, autoLaunch, visitor, "scheduleService")
-	if err != nil {
-		glog.Errorf("Could not retrieve service(s) for scheduling %s: %s", serviceID, err)
-		return 0, err
+// scheduleServices schedules the services, and their children if autoLaunch
+// is set, to the desired state.  tenantIDs maps each of the requested service
+// ids to its tenant.  The caller must hold the tenant locks.
+func (f *Facade) scheduleServices(ctx datastore.Context, tenantIDs map[string]string, serviceIDs []string, autoLaunch bool, desiredState service.DesiredState) (int, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade_scheduleService"))
+	plog.Debugf("Facade.ScheduleService %s (%s)", serviceIDs, desiredState)
+
+	// Build a list of services to be scheduled, visiting each service once
+	// even if the requested services overlap.
+	type scheduledService struct {
+		tenantID string
+		svc      service.Service
+	}
+	svcs := []scheduledService{}
+	visited := make(map[string]struct{})
+	for _, serviceID := range serviceIDs {
+		tenantID := tenantIDs[serviceID]
+		visitor := func(svc *service.Service) error {
+			if _, ok := visited[svc.ID]; !ok {
+				visited[svc.ID] = struct{}{}
+				svcs = append(svcs, scheduledService{tenantID: tenantID, svc: *svc})
+			}
+			return nil
+		}
+		err := f.walkServices(ctx, serviceID, autoLaunch, visitor, "scheduleService")
+		if err != nil {
+			plog.Errorf("Could not retrieve service(s) for scheduling %s: %s", serviceID, err)
+			return 0, err
+		}
 	}
 
 	if desiredState != service.SVCStop {
@@ -1145,9 +1404,9 @@ func (f *Facade) scheduleService(ctx datastore.Context, tenantID, serviceID stri
 		if desiredState.String() == "unknown" {
 			return 0, fmt.Errorf("desired state unknown")
 		}
-		for _, svc := range svcs {
-			if err := f.validateServiceStart(ctx, &svc); err != nil {
-				glog.Errorf("Service %s (%s) failed validation for start: %s", svc.Name, svc.ID, err)
+		for _, s := range svcs {
+			if err := f.validateServiceStart(ctx, &s.svc); err != nil {
+				plog.Errorf("Service %s (%s) failed validation for start: %s", s.svc.Name, s.svc.ID, err)
 				return 0, err
 			}
 		}
@@ -1155,14 +1414,18 @@ func (f *Facade) scheduleService(ctx datastore.Context, tenantID, serviceID stri
 
 	// Schedule the services, calculating the number of affected services as we go
 	affected := 0
-	for _, svc := range svcs {
-		if svc.ID != serviceID && svc.Launch == commons.MANUAL {
+	for _, s := range svcs {
+		svc := s.svc
+		if _, requested := tenantIDs[svc.ID]; !requested && svc.Launch == commons.MANUAL {
 			continue
 		} else if svc.DesiredState == int(desiredState) {
 			continue
+		} else if desiredState == service.SVCPause && svc.DesiredState != int(service.SVCRun) {
+			// only running services can be paused
+			continue
 		}
 
-		err := f.scheduleOneService(ctx, tenantID, &svc, desiredState)
+		err := f.scheduleOneService(ctx, s.tenantID, &svc, desiredState)
 		if err != nil {
 			return affected, err
 		}
@@ -1174,6 +1437,7 @@ func (f *Facade) scheduleService(ctx datastore.Context, tenantID, serviceID stri
 
 func (f *Facade) scheduleOneService(ctx datastore.Context, tenantID string, svc *service.Service, desiredState service.DesiredState) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("scheduleOneService"))
+	priorState := service.DesiredState(svc.DesiredState)
 	switch desiredState {
 	case service.SVCRestart:
 		// shutdown all service instances
@@ -1187,21 +1451,62 @@ func (f *Facade) scheduleOneService(ctx datastore.Context, tenantID string, svc
 
 	// write the service into the database
 	if err := f.serviceStore.UpdateDesiredState(ctx, svc.ID, svc.DesiredState); err != nil {
-		glog.Errorf("Facade.scheduleService: Could not create service %s (%s): %s", svc.Name, svc.ID, err)
+		plog.Errorf("Facade.scheduleService: Could not create service %s (%s): %s", svc.Name, svc.ID, err)
 		return err
 	}
-	glog.Infof("Scheduled service %s (%s) to %s", svc.Name, svc.ID, service.DesiredState(svc.DesiredState).String())
+	plog.Infof("Scheduled service %s (%s) to %s", svc.Name, svc.ID, service.DesiredState(svc.DesiredState).String())
 
 	if err := f.fillServiceAddr(ctx, svc); err != nil {
 		return err
 	}
 	if err := f.zzk.UpdateService(ctx, tenantID, svc, false, false); err != nil {
-		glog.Errorf("Facade.scheduleService: Could not sync service %s to the coordinator: %s", svc.ID, err)
+		plog.Errorf("Facade.scheduleService: Could not sync service %s to the coordinator: %s", svc.ID, err)
 		return err
 	}
+	f.recordStateChange(ctx, svc.ID, priorState, desiredState)
 	return nil
 }
 
+// recordStateChange appends a change of the desired state of a service to its
+// history, attributed to the actor of the context or to serviced if there is
+// none.  The change has already been made, so failures are only logged.
+func (f *Facade) recordStateChange(ctx datastore.Context, serviceID string, from, to service.DesiredState) {
+	actor := datastore.GetActor(ctx)
+	if actor.Source == "" {
+		actor.Source = statehistory.SourceSystem
+	}
+	change := statehistory.Change{
+		Time:   time.Now(),
+		From:   from,
+		To:     to,
+		Source: actor.Source,
+		User:   actor.User,
+	}
+	if err := f.historyStore.Append(ctx, serviceID, change); err != nil {
+		plog.WithError(err).WithFields(log.Fields{
+			"serviceid": serviceID,
+			"state":     to,
+			"source":    actor.Source,
+		}).Warn("Could not record desired state change")
+	}
+}
+
+// GetServiceStateHistory returns the changes of the desired state of a
+// service, oldest first.
+func (f *Facade) GetServiceStateHistory(ctx datastore.Context, serviceID string) ([]statehistory.Change, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetServiceStateHistory"))
+	if _, err := f.serviceStore.Get(ctx, serviceID); err != nil {
+		return nil, err
+	}
+	history, err := f.historyStore.Get(ctx, serviceID)
+	if datastore.IsErrNoSuchEntity(err) {
+		return []statehistory.Change{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return history.Changes, nil
+}
+
 // Update the serviceCache with values from ZK.
 func (f *Facade) UpdateServiceCache(ctx datastore.Context) error {
 	svcNodes, err := f.zzk.GetServiceNodes()
@@ -1217,7 +1522,7 @@ func (f *Facade) UpdateServiceCache(ctx datastore.Context) error {
 // WaitService waits for service/s to reach a particular desired state within the designated timeout
 func (f *Facade) WaitService(ctx datastore.Context, dstate service.DesiredState, timeout time.Duration, recursive bool, serviceIDs ...string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("WaitService"))
-	glog.V(4).Infof("Facade.WaitService (%s)", dstate)
+	plog.Debugf("Facade.WaitService (%s)", dstate)
 
 	// error out if the desired state is invalid
 	if dstate.String() == "unknown" {
@@ -1253,7 +1558,7 @@ func (f *Facade) WaitService(ctx datastore.Context, dstate service.DesiredState,
 		// spawn a goroutine to wait for each particular service
 		svc, err := f.GetService(ctx, serviceID)
 		if err != nil {
-			glog.Errorf("Error while getting service %s: %s", serviceID, err)
+			plog.Errorf("Error while getting service %s: %s", serviceID, err)
 			return err
 		}
 		processing[svc.ID] = struct{}{}
@@ -1264,7 +1569,7 @@ func (f *Facade) WaitService(ctx datastore.Context, dstate service.DesiredState,
 			case done <- waitstatus{s.ID, err}:
 			case <-cancel:
 			}
-			glog.V(1).Infof("Finished waiting for %s (%s) to %s: %s", s.Name, s.ID, dstate, err)
+			plog.Debugf("Finished waiting for %s (%s) to %s: %s", s.Name, s.ID, dstate, err)
 		}(svc)
 	}
 
@@ -1275,11 +1580,19 @@ func (f *Facade) WaitService(ctx datastore.Context, dstate service.DesiredState,
 		case result := <-done:
 			delete(processing, result.ServiceID)
 			if result.Err != nil {
-				glog.Errorf("Error while waiting for service %s to %s: %s", result.ServiceID, dstate, result.Err)
+				plog.Errorf("Error while waiting for service %s to %s: %s", result.ServiceID, dstate, result.Err)
 				return result.Err
 			}
 		case <-timeoutC:
-			return fmt.Errorf("timeout")
+			pending := make([]string, 0, len(processing))
+			for serviceID := range processing {
+				pending = append(pending, serviceID)
+			}
+			sort.Strings(pending)
+			return fmt.Errorf("timeout waiting for service(s) %s to %s", strings.Join(pending, ", "), dstate)
+		case <-ctx.Done():
+			// the caller went away
+			return ctx.Err()
 		}
 	}
 
@@ -1288,22 +1601,83 @@ func (f *Facade) WaitService(ctx datastore.Context, dstate service.DesiredState,
 
 func (f *Facade) StartService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("StartService"))
-	return f.ScheduleService(ctx, request.ServiceID, request.AutoLaunch, service.SVCRun)
+	ctx = scheduleRequestActor(ctx, request)
+	return f.ScheduleServices(ctx, scheduleRequestIDs(request), request.AutoLaunch, service.SVCRun)
 }
 
 func (f *Facade) RestartService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("RestartService"))
-	return f.ScheduleService(ctx, request.ServiceID, request.AutoLaunch, service.SVCRestart)
+	ctx = scheduleRequestActor(ctx, request)
+	return f.ScheduleServices(ctx, scheduleRequestIDs(request), request.AutoLaunch, service.SVCRestart)
 }
 
 func (f *Facade) PauseService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("PauseService"))
-	return f.ScheduleService(ctx, request.ServiceID, request.AutoLaunch, service.SVCPause)
+	ctx = scheduleRequestActor(ctx, request)
+	return f.ScheduleServices(ctx, scheduleRequestIDs(request), request.AutoLaunch, service.SVCPause)
+}
+
+// ResumeService schedules the paused services at and below the given service
+// to run and returns the number of affected services.  Services that are
+// stopped are left stopped.
+func (f *Facade) ResumeService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ResumeService"))
+	ctx = scheduleRequestActor(ctx, request)
+	tenantID, err := f.GetTenantID(ctx, request.ServiceID)
+	if err != nil {
+		return 0, err
+	}
+	mutex := getTenantLock(tenantID)
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	svcs := []service.Service{}
+	visitor := func(svc *service.Service) error {
+		if svc.DesiredState == int(service.SVCPause) {
+			svcs = append(svcs, *svc)
+		}
+		return nil
+	}
+	if err := f.walkServices(ctx, request.ServiceID, request.AutoLaunch, visitor, "ResumeService"); err != nil {
+		plog.Errorf("Could not retrieve service(s) for resuming %s: %s", request.ServiceID, err)
+		return 0, err
+	}
+
+	affected := 0
+	for _, svc := range svcs {
+		if err := f.scheduleOneService(ctx, tenantID, &svc, service.SVCRun); err != nil {
+			return affected, err
+		}
+		affected++
+	}
+	return affected, nil
 }
 
 func (f *Facade) StopService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("StopService"))
-	return f.ScheduleService(ctx, request.ServiceID, request.AutoLaunch, service.SVCStop)
+	ctx = scheduleRequestActor(ctx, request)
+	return f.ScheduleServices(ctx, scheduleRequestIDs(request), request.AutoLaunch, service.SVCStop)
+}
+
+// scheduleRequestIDs returns the ids of the services named by a schedule
+// request.
+func scheduleRequestIDs(request dao.ScheduleServiceRequest) []string {
+	if len(request.ServiceIDs) == 0 {
+		return []string{request.ServiceID}
+	}
+	if request.ServiceID != "" && !utils.StringInSlice(request.ServiceID, request.ServiceIDs) {
+		return append([]string{request.ServiceID}, request.ServiceIDs...)
+	}
+	return request.ServiceIDs
+}
+
+// scheduleRequestActor returns a context that attributes the changes made for
+// a schedule request to the user and source of the request, if it has one.
+func scheduleRequestActor(ctx datastore.Context, request dao.ScheduleServiceRequest) datastore.Context {
+	if request.Source == "" {
+		return ctx
+	}
+	return datastore.WithActor(ctx, request.Source, request.User)
 }
 
 type ipinfo struct {
@@ -1338,10 +1712,10 @@ func (m Ports) List() (ports []uint16) {
 func (f *Facade) restoreIPs(ctx datastore.Context, svc *service.Service) error {
 	for _, ep := range svc.Endpoints {
 		if addrAssign := ep.AddressAssignment; addrAssign.IPAddr != "" {
-			glog.Infof("Found an address assignment at %s:%d to endpoint %s for service %s (%s)", addrAssign.IPAddr, ep.AddressConfig.Port, ep.Name, svc.Name, svc.ID)
+			plog.Infof("Found an address assignment at %s:%d to endpoint %s for service %s (%s)", addrAssign.IPAddr, ep.AddressConfig.Port, ep.Name, svc.Name, svc.ID)
 			ip, err := f.getManualAssignment(ctx, svc.PoolID, addrAssign.IPAddr, ep.AddressConfig.Port)
 			if err != nil {
-				glog.Warningf("Could not assign ip %s:%d to endpoint %s for service %s (%s): %s", addrAssign.IPAddr, ep.AddressConfig.Port, ep.Name, svc.Name, svc.ID, err)
+				plog.Warningf("Could not assign ip %s:%d to endpoint %s for service %s (%s): %s", addrAssign.IPAddr, ep.AddressConfig.Port, ep.Name, svc.Name, svc.ID, err)
 				continue
 			}
 			newAddrAssign := addressassignment.AddressAssignment{
@@ -1354,10 +1728,10 @@ func (f *Facade) restoreIPs(ctx datastore.Context, svc *service.Service) error {
 				EndpointName:   ep.Name,
 			}
 			if _, err := f.assign(ctx, newAddrAssign); err != nil {
-				glog.Errorf("Could not restore address assignment for service %s (%s) at %s:%d for endpoint %s: %s", svc.Name, svc.ID, addrAssign.IPAddr, ep.AddressConfig.Port, ep.Name, err)
+				plog.Errorf("Could not restore address assignment for service %s (%s) at %s:%d for endpoint %s: %s", svc.Name, svc.ID, addrAssign.IPAddr, ep.AddressConfig.Port, ep.Name, err)
 				return err
 			}
-			glog.Infof("Restored address assignment for service %s (%s) at %s:%d for endpoint %s", svc.Name, svc.ID, addrAssign.IPAddr, ep.AddressConfig.Port, ep.Name)
+			plog.Infof("Restored address assignment for service %s (%s) at %s:%d for endpoint %s", svc.Name, svc.ID, addrAssign.IPAddr, ep.AddressConfig.Port, ep.Name)
 		}
 	}
 	return nil
@@ -1365,22 +1739,29 @@ func (f *Facade) restoreIPs(ctx datastore.Context, svc *service.Service) error {
 
 func (f *Facade) AssignIPs(ctx datastore.Context, request addressassignment.AssignmentRequest) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("AssignIPs"))
+	return f.assignIPs(ctx, request, true, nil)
+}
+
+// assignIPs assigns ips to the endpoints of the service (and its children if
+// traverse is set), never auto-assigning an ip that belongs to one of the
+// excluded hosts.
+func (f *Facade) assignIPs(ctx datastore.Context, request addressassignment.AssignmentRequest, traverse bool, excludeHosts map[string]struct{}) error {
 	visitor := func(svc *service.Service) error {
 		// get all of the ports for the service
 		portmap, err := GetPorts(svc.Endpoints)
 		if err != nil {
-			glog.Errorf("Could not get ports for service %s (%s): %s", svc.Name, svc.ID, err)
+			plog.Errorf("Could not get ports for service %s (%s): %s", svc.Name, svc.ID, err)
 			return err
 		} else if len(portmap) == 0 {
 			return nil
 		}
 
-		glog.V(1).Infof("Found %+v ports for service %s (%s)", portmap.List(), svc.Name, svc.ID)
+		plog.Debugf("Found %+v ports for service %s (%s)", portmap.List(), svc.Name, svc.ID)
 
 		// get all of the address assignments for the service
 		assignments, err := f.GetServiceAddressAssignments(ctx, svc.ID)
 		if err != nil {
-			glog.Errorf("Could not get address assignments for service %s (%s): %s", svc.Name, svc.ID, err)
+			plog.Errorf("Could not get address assignments for service %s (%s): %s", svc.Name, svc.ID, err)
 			return err
 		}
 
@@ -1408,8 +1789,8 @@ func (f *Facade) AssignIPs(ctx datastore.Context, request addressassignment.Assi
 			// if the remaining endpoints cannot be reassigned, find an ip for all endpoints
 			if ip.IP == "" {
 				var err error
-				if ip, err = f.getAutoAssignment(ctx, svc.PoolID, allports...); err != nil {
-					glog.Errorf("Could not get an ip to assign to service %s (%s): %s", svc.Name, svc.ID, err)
+				if ip, err = f.getAutoAssignment(ctx, svc.PoolID, excludeHosts, allports...); err != nil {
+					plog.Errorf("Could not get an ip to assign to service %s (%s): %s", svc.Name, svc.ID, err)
 					return err
 				}
 			}
@@ -1424,7 +1805,7 @@ func (f *Facade) AssignIPs(ctx datastore.Context, request addressassignment.Assi
 			// try to find an assignment for the remaining endpoints
 			var err error
 			if ip, err = f.getManualAssignment(ctx, svc.PoolID, request.IPAddress, portmap.List()...); err != nil {
-				glog.Errorf("Could not get an ip to assign to service %s (%s): %s", svc.Name, svc.ID, err)
+				plog.Errorf("Could not get an ip to assign to service %s (%s): %s", svc.Name, svc.ID, err)
 				return err
 			}
 		}
@@ -1435,7 +1816,7 @@ func (f *Facade) AssignIPs(ctx datastore.Context, request addressassignment.Assi
 			if assignment.IPAddr == ip.IP {
 				exclude[assignment.EndpointName] = struct{}{}
 			} else if err := f.RemoveAddressAssignment(ctx, assignment.ID); err != nil {
-				glog.Errorf("Error removing address assignment %s for %s (%s): %s", assignment.EndpointName, svc.Name, svc.ID, err)
+				plog.Errorf("Error removing address assignment %s for %s (%s): %s", assignment.EndpointName, svc.Name, svc.ID, err)
 				return err
 			}
 		}
@@ -1454,38 +1835,67 @@ func (f *Facade) AssignIPs(ctx datastore.Context, request addressassignment.Assi
 				}
 
 				if _, err := f.assign(ctx, newassign); err != nil {
-					glog.Errorf("Error creating address assignment for %s of service %s at %s:%d: %s", newassign.EndpointName, newassign.ServiceID, newassign.IPAddr, newassign.Port, err)
+					plog.Errorf("Error creating address assignment for %s of service %s at %s:%d: %s", newassign.EndpointName, newassign.ServiceID, newassign.IPAddr, newassign.Port, err)
 					return err
 				}
-				glog.Infof("Created address assignment for endpoint %s of service %s at %s:%d", newassign.EndpointName, newassign.ServiceID, newassign.IPAddr, newassign.Port)
+				plog.Infof("Created address assignment for endpoint %s of service %s at %s:%d", newassign.EndpointName, newassign.ServiceID, newassign.IPAddr, newassign.Port)
 				restart = true
 			}
 		}
 
+		// the service can be started again now that it has an ip
+		if svc.AddressBlocked != "" {
+			svc.AddressBlocked = ""
+			svc.UpdatedAt = time.Now()
+			if err := f.serviceStore.Put(ctx, svc); err != nil {
+				plog.Errorf("Could not clear the blocked address of service %s (%s): %s", svc.Name, svc.ID, err)
+				return err
+			}
+			plog.Infof("Service %s (%s) is no longer blocked", svc.Name, svc.ID)
+		}
+
 		// Restart the service if it is running and new address assignments are made
 		if restart && svc.DesiredState == int(service.SVCRun) {
-			f.RestartService(ctx, dao.ScheduleServiceRequest{svc.ID, false})
+			f.RestartService(ctx, dao.ScheduleServiceRequest{ServiceID: svc.ID, AutoLaunch: false})
 		}
 
 		return nil
 	}
 
 	// traverse all the services
-	return f.walkServices(ctx, request.ServiceID, true, visitor, "AssignIPs")
+	return f.walkServices(ctx, request.ServiceID, traverse, visitor, "AssignIPs")
 }
 
 // ServiceUse will tag a new image (imageName) in a given registry for a given tenant
 // to latest, making sure to push changes to the registry
-func (f *Facade) ServiceUse(ctx datastore.Context, serviceID, imageName, registryName string, replaceImgs []string, noOp bool) error {
+func (f *Facade) ServiceUse(ctx datastore.Context, serviceID, imageName, registryName string, replaceImgs []string, noOp bool) (err error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("ServiceUse"))
-	glog.Infof("Pushing image %s for tenant %s into elastic", imageName, serviceID)
+	// snapshot the tenant first if the upgrade needs to be verified
+	var priorID string
+	var verifyIDs []string
+	reason := fmt.Sprintf("upgrade to image %s", imageName)
+	if !noOp {
+		if priorID, verifyIDs, err = f.prepareRollback(ctx, serviceID, reason, f.snapshotSpacePercent, false); err != nil {
+			plog.Errorf("Could not snapshot tenant %s before upgrading to image %s: %s", serviceID, imageName, err)
+			return err
+		}
+	}
+	if priorID != "" {
+		defer func() {
+			if err == nil {
+				go f.verifyChange(priorID, reason, verifyIDs)
+			}
+		}()
+	}
+
+	plog.Infof("Pushing image %s for tenant %s into elastic", imageName, serviceID)
 	// Push into elastic
 	if err := f.Download(imageName, serviceID); err != nil {
 		return err
 	}
 
 	if len(replaceImgs) > 0 {
-		glog.Infof("Replacing references to images %s with %s for tenant %s", strings.Join(replaceImgs, ", "), imageName, serviceID)
+		plog.Infof("Replacing references to images %s with %s for tenant %s", strings.Join(replaceImgs, ", "), imageName, serviceID)
 		// Determine what services need to be updated
 		svcs, err := f.GetServiceList(ctx, serviceID)
 		if err != nil {
@@ -1514,12 +1924,12 @@ func (f *Facade) ServiceUse(ctx datastore.Context, serviceID, imageName, registr
 			}
 			// Only match on repo names
 			if _, ok := srchImgs[origImg.Repo]; !ok {
-				glog.V(1).Infof("Skipping image replace for service %s due to mismatch: targetImages => %s existingImg => %s", svc.Name, srchImgs, origImg.String())
+				plog.Debugf("Skipping image replace for service %s due to mismatch: targetImages => %s existingImg => %s", svc.Name, srchImgs, origImg.String())
 				continue
 			}
 			// Change the image in the affected svc to point to our new image
 			origImg.Merge(&commons.ImageID{Repo: newImg.Repo})
-			glog.Infof("Updating image in service %s to %s", svc.Name, origImg.String())
+			plog.Infof("Updating image in service %s to %s", svc.Name, origImg.String())
 			svc.ImageID = origImg.String()
 			svcsToUpdate = append(svcsToUpdate, svc)
 		}
@@ -1534,20 +1944,40 @@ func (f *Facade) ServiceUse(ctx datastore.Context, serviceID, imageName, registr
 	return nil
 }
 
-func (f *Facade) getAutoAssignment(ctx datastore.Context, poolID string, ports ...uint16) (ipinfo, error) {
-	pool, err := f.GetResourcePool(ctx, poolID)
+func (f *Facade) getAutoAssignment(ctx datastore.Context, poolID string, excludeHosts map[string]struct{}, ports ...uint16) (ipinfo, error) {
+	ips, err := f.getAssignableIPs(ctx, poolID, excludeHosts, ports...)
 	if err != nil {
-		glog.Errorf("Error while looking up pool %s: %s", poolID, err)
 		return ipinfo{}, err
 	}
 
+	// Pick an ip
+	total := len(ips)
+	if total == 0 {
+		err := fmt.Errorf("No IPs are available to be assigned")
+		plog.Errorf("Error acquiring IP assignment: %s", err)
+		return ipinfo{}, err
+	}
+
+	rand.Seed(time.Now().UTC().UnixNano())
+	return ips[rand.Intn(total)], nil
+}
+
+// getAssignableIPs returns the virtual and static ips of the pool that are
+// free on all of the given ports, skipping the ips of the excluded hosts.
+func (f *Facade) getAssignableIPs(ctx datastore.Context, poolID string, excludeHosts map[string]struct{}, ports ...uint16) ([]ipinfo, error) {
+	pool, err := f.GetResourcePool(ctx, poolID)
+	if err != nil {
+		plog.Errorf("Error while looking up pool %s: %s", poolID, err)
+		return nil, err
+	}
+
 	ignoreips := make(map[string]struct{})
 	for _, port := range ports {
 		// Get all of the address assignments for port
 		assignments, err := f.GetServiceAddressAssignmentsByPort(ctx, port)
 		if err != nil {
-			glog.Errorf("Error while looking up address assignments for port %d: %s", port, err)
-			return ipinfo{}, err
+			plog.Errorf("Error while looking up address assignments for port %d: %s", port, err)
+			return nil, err
 		}
 
 		// Find out all of the host ips that cannot be used
@@ -1566,11 +1996,14 @@ func (f *Facade) getAutoAssignment(ctx datastore.Context, poolID string, ports .
 
 	hosts, err := f.FindHostsInPool(ctx, poolID)
 	if err != nil {
-		glog.Errorf("Error while looking up hosts in pool %s: %s", poolID, err)
-		return ipinfo{}, err
+		plog.Errorf("Error while looking up hosts in pool %s: %s", poolID, err)
+		return nil, err
 	}
 	var resources []host.HostIPResource
 	for _, host := range hosts {
+		if _, ok := excludeHosts[host.ID]; ok {
+			continue
+		}
 		if host.IPs != nil {
 			resources = append(resources, host.IPs...)
 		}
@@ -1581,35 +2014,25 @@ func (f *Facade) getAutoAssignment(ctx datastore.Context, poolID string, ports .
 			ips = append(ips, ipinfo{hostIP.IPAddress, commons.STATIC, hostIP.HostID})
 		}
 	}
-
-	// Pick an ip
-	total := len(ips)
-	if total == 0 {
-		err := fmt.Errorf("No IPs are available to be assigned")
-		glog.Errorf("Error acquiring IP assignment: %s", err)
-		return ipinfo{}, err
-	}
-
-	rand.Seed(time.Now().UTC().UnixNano())
-	return ips[rand.Intn(total)], nil
+	return ips, nil
 }
 
 func (f *Facade) getManualAssignment(ctx datastore.Context, poolID, ipAddr string, ports ...uint16) (ipinfo, error) {
 	// Check if the assignment is already there
 	for _, port := range ports {
 		if exists, err := f.FindAssignmentByHostPort(ctx, ipAddr, port); err != nil {
-			glog.Errorf("Error while looking for assignment for (%s:%d): %s", ipAddr, port, err)
+			plog.Errorf("Error while looking for assignment for (%s:%d): %s", ipAddr, port, err)
 			return ipinfo{}, err
 		} else if exists != nil {
 			err := fmt.Errorf("assignment exists for %s:%d", ipAddr, port)
-			glog.Errorf("Assignment found for endpoint %s on service %s: %s", exists.EndpointName, exists.ServiceID, err)
+			plog.Errorf("Assignment found for endpoint %s on service %s: %s", exists.EndpointName, exists.ServiceID, err)
 			return ipinfo{}, err
 		}
 	}
 
 	pool, err := f.GetResourcePool(ctx, poolID)
 	if err != nil {
-		glog.Errorf("Error while looking up pool %s: %s", poolID, err)
+		plog.Errorf("Error while looking up pool %s: %s", poolID, err)
 		return ipinfo{}, err
 	}
 
@@ -1621,15 +2044,15 @@ func (f *Facade) getManualAssignment(ctx datastore.Context, poolID, ipAddr strin
 
 	host, err := f.GetHostByIP(ctx, ipAddr)
 	if err != nil {
-		glog.Errorf("Error while looking for host with IP %s: %s", ipAddr, err)
+		plog.Errorf("Error while looking for host with IP %s: %s", ipAddr, err)
 		return ipinfo{}, err
 	} else if host == nil {
 		err := fmt.Errorf("host not found")
-		glog.Errorf("Could not find IP %s", ipAddr)
+		plog.Errorf("Could not find IP %s", ipAddr)
 		return ipinfo{}, err
 	} else if host.PoolID != poolID {
 		err := fmt.Errorf("host not found in pool")
-		glog.Errorf("Host %s (%s) not found in pool %s", host.ID, ipAddr, err)
+		plog.Errorf("Host %s (%s) not found in pool %s", host.ID, ipAddr, err)
 		return ipinfo{}, err
 	}
 
@@ -1652,36 +2075,36 @@ func (f *Facade) filterByTenantID(ctx datastore.Context, matchTenantID string, s
 		}
 
 		if localTenantID == matchTenantID {
-			glog.V(5).Infof("    Keeping service ID: %v (tenant ID: %v)", service.ID, localTenantID)
+			plog.Debugf("    Keeping service ID: %v (tenant ID: %v)", service.ID, localTenantID)
 			matches = append(matches, service)
 		}
 	}
-	glog.V(2).Infof("Returning %d services from tenantID: %v", len(matches), matchTenantID)
+	plog.Debugf("Returning %d services from tenantID: %v", len(matches), matchTenantID)
 	return matches, nil
 }
 
 func filterByNameRegex(nmregex string, services []service.Service) ([]service.Service, error) {
 	r, err := regexp.Compile(nmregex)
 	if err != nil {
-		glog.Errorf("Bad name regexp :%s", nmregex)
+		plog.Errorf("Bad name regexp :%s", nmregex)
 		return nil, err
 	}
 
 	matches := []service.Service{}
 	for _, service := range services {
 		if r.MatchString(service.Name) {
-			glog.V(5).Infof("    Keeping service ID: %v (service name: %v)", service.ID, service.Name)
+			plog.Debugf("    Keeping service ID: %v (service name: %v)", service.ID, service.Name)
 			matches = append(matches, service)
 		}
 	}
-	glog.V(2).Infof("Returning %d services from %v", len(matches), nmregex)
+	plog.Debugf("Returning %d services from %v", len(matches), nmregex)
 	return matches, nil
 }
 
 //getService is an internal method that returns a Service without filling in all related service data like address assignments
 //and modified config files
 func (f *Facade) getService(ctx datastore.Context, id string) (service.Service, error) {
-	glog.V(3).Infof("Facade.getService: id=%s", id)
+	plog.Debugf("Facade.getService: id=%s", id)
 	store := f.serviceStore
 	svc, err := store.Get(ctx, id)
 	if err != nil || svc == nil {
@@ -1693,11 +2116,11 @@ func (f *Facade) getService(ctx datastore.Context, id string) (service.Service,
 //getServices is an internal method that returns all Services without filling in all related service data like address assignments
 //and modified config files
 func (f *Facade) getServices(ctx datastore.Context) ([]service.Service, error) {
-	glog.V(3).Infof("Facade.GetServices")
+	plog.Debugf("Facade.GetServices")
 	store := f.serviceStore
 	results, err := store.GetServices(ctx)
 	if err != nil {
-		glog.Error("Facade.GetServices: err=", err)
+		plog.Error("Facade.GetServices: err=", err)
 		return results, err
 	}
 	return results, nil
@@ -1708,7 +2131,7 @@ func (f *Facade) getTenantIDs(ctx datastore.Context) ([]string, error) {
 	store := f.serviceStore
 	results, err := store.GetServices(ctx)
 	if err != nil {
-		glog.Errorf("Facade.GetServices: %s", err)
+		plog.Errorf("Facade.GetServices: %s", err)
 		return nil, err
 	}
 	var svcids []string
@@ -1724,11 +2147,38 @@ func (f *Facade) getTenantIDs(ctx datastore.Context) ([]string, error) {
 func (f *Facade) walkServices(ctx datastore.Context, serviceID string, traverse bool, visitFn service.Visit, callerLabel string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start(fmt.Sprintf("walkServices_%s", callerLabel)))
 	store := f.serviceStore
-	getChildren := func(parentID string) ([]service.Service, error) {
+
+	// load the tree before visiting any of it, so that a caller that goes
+	// away stops the walk before the first change instead of leaving the
+	// tree partly changed
+	children := make(map[string][]service.Service)
+	var load func(parentID string) error
+	load = func(parentID string) error {
+		if err := ctx.Err(); err != nil {
+			plog.Warningf("Stopped walking services at %s for %s: %s", parentID, callerLabel, err)
+			return err
+		}
 		if !traverse {
-			return []service.Service{}, nil
+			return nil
+		}
+		svcs, err := store.GetChildServices(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		children[parentID] = svcs
+		for _, svc := range svcs {
+			if err := load(svc.ID); err != nil {
+				return err
+			}
 		}
-		return store.GetChildServices(ctx, parentID)
+		return nil
+	}
+	if err := load(serviceID); err != nil {
+		return err
+	}
+
+	getChildren := func(parentID string) ([]service.Service, error) {
+		return children[parentID], nil
 	}
 	getService := func(svcID string) (service.Service, error) {
 		svc, err := store.Get(ctx, svcID)
@@ -1755,6 +2205,10 @@ func (f *Facade) fillOutService(ctx datastore.Context, svc *service.Service) err
 func (f *Facade) fillOutServices(ctx datastore.Context, svcs []service.Service) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("fillOutServices"))
 	for i := range svcs {
+		if err := ctx.Err(); err != nil {
+			plog.Warningf("Stopped loading %d services: %s", len(svcs), err)
+			return err
+		}
 		if err := f.fillOutService(ctx, &svcs[i]); err != nil {
 			return err
 		}
@@ -1773,14 +2227,14 @@ func (f *Facade) fillServiceAddr(ctx datastore.Context, svc *service.Service) er
 		//only lookup if there is a possibility for an address assignment. i.e. AddressConfig has port and protocol
 		if svc.Endpoints[idx].IsConfigurable() {
 			if assignment, err := f.FindAssignmentByServiceEndpoint(ctx, svc.ID, endpointName); err != nil {
-				glog.Errorf("Error searching for address assignments for endpoint %s of service %s (%s): %s", endpointName, svc.Name, svc.ID, err)
+				plog.Errorf("Error searching for address assignments for endpoint %s of service %s (%s): %s", endpointName, svc.Name, svc.ID, err)
 				return err
 			} else if assignment != nil {
 				// verify the ports match
 				if port := svc.Endpoints[idx].AddressConfig.Port; assignment.Port != port {
-					glog.Infof("Removing address assignment for endpoint %s of service %s (%s)", endpointName, svc.Name, svc.ID)
+					plog.Infof("Removing address assignment for endpoint %s of service %s (%s)", endpointName, svc.Name, svc.ID)
 					if err := store.Delete(ctx, addressassignment.Key(assignment.ID)); err != nil {
-						glog.Errorf("Error removing address assignment for endpoint %s of service %s (%s): %s", endpointName, svc.Name, svc.ID, err)
+						plog.Errorf("Error removing address assignment for endpoint %s of service %s (%s): %s", endpointName, svc.Name, svc.ID, err)
 						return err
 					}
 					continue
@@ -1788,12 +2242,12 @@ func (f *Facade) fillServiceAddr(ctx datastore.Context, svc *service.Service) er
 
 				// verify the ip exists
 				if exists, err := f.HasIP(ctx, svc.PoolID, assignment.IPAddr); err != nil {
-					glog.Errorf("Error validating address assignment for endpoint %s of service %s (%s): %s", endpointName, svc.Name, svc.ID, err)
+					plog.Errorf("Error validating address assignment for endpoint %s of service %s (%s): %s", endpointName, svc.Name, svc.ID, err)
 					return err
 				} else if !exists {
-					glog.Infof("Removing address assignment for endpoint %s of service %s (%s)", endpointName, svc.Name, svc.ID)
+					plog.Infof("Removing address assignment for endpoint %s of service %s (%s)", endpointName, svc.Name, svc.ID)
 					if err := store.Delete(ctx, addressassignment.Key(assignment.ID)); err != nil {
-						glog.Errorf("Error removing address assignment for endpoint %s of service %s (%s): %s", endpointName, svc.Name, svc.ID, err)
+						plog.Errorf("Error removing address assignment for endpoint %s of service %s (%s): %s", endpointName, svc.Name, svc.ID, err)
 						return err
 					}
 					continue
@@ -1815,7 +2269,7 @@ func (f *Facade) validateServiceEndpoints(ctx datastore.Context, svc *service.Se
 
 	epValidator.IsValid(vErr, svc)
 	if vErr.HasError() {
-		glog.Errorf("Service %s (%s) has duplicate endpoints: %s", svc.Name, svc.ID, vErr)
+		plog.Errorf("Service %s (%s) has duplicate endpoints: %s", svc.Name, svc.ID, vErr)
 		return vErr
 	}
 
@@ -1826,14 +2280,14 @@ func (f *Facade) validateServiceEndpoints(ctx datastore.Context, svc *service.Se
 		if _, err := f.serviceStore.Get(ctx, svc.ID); datastore.IsErrNoSuchEntity(err) {
 			return nil
 		} else if err != nil {
-			glog.Errorf("Could not look up service %s (%s): %s", svc.Name, svc.ID, err)
+			plog.Errorf("Could not look up service %s (%s): %s", svc.Name, svc.ID, err)
 			return err
 		}
 		tenantID = svc.ID
 	} else {
 		var err error
 		if tenantID, err = f.GetTenantID(ctx, svc.ParentServiceID); err != nil {
-			glog.Errorf("Could not look up tenantID for service %s (%s): %s", svc.Name, svc.ID, err)
+			plog.Errorf("Could not look up tenantID for service %s (%s): %s", svc.Name, svc.ID, err)
 			return err
 		}
 	}
@@ -1845,7 +2299,7 @@ func (f *Facade) validateServiceEndpoints(ctx datastore.Context, svc *service.Se
 		}
 		return nil
 	}, "validateServiceEndpoints"); err != nil {
-		glog.Errorf("Could not walk service tree of %s (%s) with tenant %s: %s", svc.Name, svc.ID, tenantID, err)
+		plog.Errorf("Could not walk service tree of %s (%s) with tenant %s: %s", svc.Name, svc.ID, tenantID, err)
 		return err
 	}
 	if vErr.HasError() {
@@ -1888,11 +2342,15 @@ func (f *Facade) getExcludedVolumes(ctx datastore.Context, serviceID string) []s
 				volmap[vol.ResourcePath] = struct{}{}
 			}
 		}
+		for _, pattern := range childService.BackupExcludes {
+			volmap[pattern] = struct{}{}
+		}
 		return nil
 	}, "getExcludedVolumes")
 	for vol := range volmap {
 		volumes = append(volumes, vol)
 	}
+	sort.Strings(volumes)
 	return volumes
 
 }
@@ -1904,7 +2362,35 @@ func (f *Facade) GetInstanceMemoryStats(startTime time.Time, instances ...metric
 // Get all the service details
 func (f *Facade) GetAllServiceDetails(ctx datastore.Context) ([]service.ServiceDetails, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetAllServiceDetails"))
-	return f.serviceStore.GetAllServiceDetails(ctx)
+	details, err := f.serviceStore.GetAllServiceDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return f.filterServiceDetailsInScope(ctx, details)
+}
+
+// QueryServices returns a page of the summaries of the services that match
+// the query, without loading the full service definitions.
+func (f *Facade) QueryServices(ctx datastore.Context, query service.ServiceQuery) (*service.ServiceSummaryPage, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("QueryServices"))
+	summaries, err := f.serviceStore.GetServiceSummaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page := service.QueryServiceSummaries(summaries, query)
+	return &page, nil
+}
+
+// ResolveServicePath returns the services that a service id or a deployment,
+// pool, or name path resolves to.  More than one match means that the path
+// is ambiguous.
+func (f *Facade) ResolveServicePath(ctx datastore.Context, servicePath string) ([]service.ServiceMatch, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ResolveServicePath"))
+	summaries, err := f.serviceStore.GetServiceSummaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return service.ResolveServicePath(summaries, servicePath), nil
 }
 
 // GetServiceDetails returns the details of a particular service
@@ -1935,7 +2421,11 @@ func (f *Facade) GetServiceDetailsAncestry(ctx datastore.Context, serviceID stri
 // Get the details of the child services for the given parent
 func (f *Facade) GetServiceDetailsByParentID(ctx datastore.Context, parentID string) ([]service.ServiceDetails, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetServiceDetailsByParentID"))
-	return f.serviceStore.GetServiceDetailsByParentID(ctx, parentID)
+	details, err := f.serviceStore.GetServiceDetailsByParentID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return f.filterServiceDetailsInScope(ctx, details)
 }
 
 // Get the monitoring profile of a given service
@@ -16,8 +16,10 @@
 package facade
 
 import (
+	"encoding/json"
 	"strings"
 
+	"github.com/control-center/serviced/commons/jsonpatch"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/addressassignment"
@@ -689,6 +691,96 @@ func (ft *FacadeIntegrationTest) TestFacade_MigrateServices_Modify_FailDupNew(t
 	t.Assert(err, Equals, ErrServiceCollision)
 }
 
+func (ft *FacadeIntegrationTest) TestFacade_MigrateServices_Patch_Success(t *C) {
+	err := ft.setupMigrationTestWithoutEndpoints(t)
+	t.Assert(err, IsNil)
+
+	request := dao.ServiceMigrationRequest{
+		ServiceID: "original_service_id_tenant",
+		Patches: []*dao.ServicePatch{
+			{
+				ServiceID: "original_service_id_child_0",
+				Patch: jsonpatch.Patch{
+					{Op: "replace", Path: "/Description", Value: json.RawMessage(`"migrated_service"`)},
+				},
+			},
+		},
+	}
+
+	err = ft.Facade.MigrateServices(ft.CTX, request)
+	t.Assert(err, IsNil)
+
+	out, err := ft.Facade.GetService(ft.CTX, "original_service_id_child_0")
+	t.Assert(err, IsNil)
+	t.Assert(out.Description, Equals, "migrated_service")
+}
+
+func (ft *FacadeIntegrationTest) TestFacade_MigrateServices_Patch_Fail(t *C) {
+	err := ft.setupMigrationTestWithoutEndpoints(t)
+	t.Assert(err, IsNil)
+
+	// Make sure we fail if the patch does not apply
+	request := dao.ServiceMigrationRequest{
+		ServiceID: "original_service_id_tenant",
+		Patches: []*dao.ServicePatch{
+			{
+				ServiceID: "original_service_id_child_0",
+				Patch: jsonpatch.Patch{
+					{Op: "remove", Path: "/NoSuchField"},
+				},
+			},
+		},
+	}
+	err = ft.Facade.MigrateServices(ft.CTX, request)
+	t.Assert(err, ErrorMatches, ".*path not found")
+
+	// Make sure we fail if the patch changes the service id
+	request.Patches[0].Patch = jsonpatch.Patch{
+		{Op: "replace", Path: "/ID", Value: json.RawMessage(`"some_unknown_id"`)},
+	}
+	err = ft.Facade.MigrateServices(ft.CTX, request)
+	t.Assert(err, ErrorMatches, "patch cannot change the id of service original_service_id_child_0")
+
+	// Make sure we fail if the service is also modified
+	oldSvc, err := ft.Facade.GetService(ft.CTX, "original_service_id_child_0")
+	t.Assert(err, IsNil)
+	request.Modified = []*service.Service{oldSvc}
+	request.Patches[0].Patch = jsonpatch.Patch{
+		{Op: "replace", Path: "/Description", Value: json.RawMessage(`"migrated_service"`)},
+	}
+	err = ft.Facade.MigrateServices(ft.CTX, request)
+	t.Assert(err, ErrorMatches, "service original_service_id_child_0 is modified more than once")
+}
+
+func (ft *FacadeIntegrationTest) TestFacade_MigrateServices_DryRun(t *C) {
+	err := ft.setupMigrationTestWithoutEndpoints(t)
+	t.Assert(err, IsNil)
+
+	newSvc := ft.createNewChildService(t)
+	request := dao.ServiceMigrationRequest{
+		ServiceID: "original_service_id_tenant",
+		Added:     []*service.Service{newSvc},
+		Patches: []*dao.ServicePatch{
+			{
+				ServiceID: "original_service_id_child_0",
+				Patch: jsonpatch.Patch{
+					{Op: "replace", Path: "/Description", Value: json.RawMessage(`"migrated_service"`)},
+				},
+			},
+		},
+		DryRun: true,
+	}
+
+	err = ft.Facade.MigrateServices(ft.CTX, request)
+	t.Assert(err, IsNil)
+
+	out, err := ft.Facade.GetService(ft.CTX, "original_service_id_child_0")
+	t.Assert(err, IsNil)
+	t.Assert(out.Description, Not(Equals), "migrated_service")
+	_, err = ft.Facade.GetService(ft.CTX, newSvc.ID)
+	t.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+}
+
 func (ft *FacadeIntegrationTest) TestFacade_MigrateServices_Added_Success(t *C) {
 	err := ft.setupMigrationTestWithoutEndpoints(t)
 	t.Assert(err, IsNil)
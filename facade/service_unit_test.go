@@ -18,15 +18,43 @@ package facade_test
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/domain/statehistory"
+	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/utils"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/context"
 	. "gopkg.in/check.v1"
 )
 
+func (ft *FacadeUnitTest) Test_WaitServiceInstances(c *C) {
+	svc := service.Service{ID: "svcwait", PoolID: "default"}
+	ft.serviceStore.On("Get", ft.ctx, svc.ID).Return(&svc, nil)
+	ft.zzk.On("WaitServiceStateChange", map[string]string{svc.ID: svc.PoolID}, mock.Anything).Return([]string{svc.ID}, nil)
+
+	changed, err := ft.Facade.WaitServiceInstances(ft.ctx, []string{svc.ID}, time.Minute)
+	c.Assert(err, IsNil)
+	c.Assert(changed, DeepEquals, []string{svc.ID})
+}
+
+func (ft *FacadeUnitTest) Test_WaitServiceInstances_NoService(c *C) {
+	expectedError := datastore.ErrNoSuchEntity{}
+	ft.serviceStore.On("Get", ft.ctx, "svcmissing").Return(nil, expectedError)
+
+	changed, err := ft.Facade.WaitServiceInstances(ft.ctx, []string{"svcmissing"}, time.Minute)
+	c.Assert(err, Equals, expectedError)
+	c.Assert(changed, IsNil)
+	ft.zzk.AssertNotCalled(c, "WaitServiceStateChange", mock.Anything, mock.Anything)
+}
+
 func (ft *FacadeUnitTest) Test_GetTenantIDForRootApp(c *C) {
 	serviceID := getRandomServiceID(c)
 	expectedService := service.Service{ID: serviceID}
@@ -381,3 +409,238 @@ func getRandomServiceID(c *C) string {
 //service store returned not-found
 //service store returned other error
 //service store return err=nil and svc=nil
+
+func (ft *FacadeUnitTest) Test_UpdateService_InvalidPriority(c *C) {
+	svc := service.Service{ID: "svcpriority", Name: "svcpriority", PoolID: "default"}
+	ft.serviceStore.On("Get", ft.ctx, svc.ID).Return(&svc, nil)
+
+	update := svc
+	update.Priority = "urgent"
+	err := ft.Facade.UpdateService(ft.ctx, update)
+	c.Assert(err, Equals, facade.ErrServiceInvalidPriority)
+	ft.serviceStore.AssertNotCalled(c, "Put", ft.ctx, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_UpdateService_InvalidContainerOptions(c *C) {
+	svc := service.Service{ID: "svcoptions", Name: "svcoptions", PoolID: "default"}
+	ft.serviceStore.On("Get", ft.ctx, svc.ID).Return(&svc, nil)
+
+	update := svc
+	update.Ulimits = []servicedefinition.Ulimit{{Name: "nofile", Soft: 65536, Hard: 1024}}
+	err := ft.Facade.UpdateService(ft.ctx, update)
+	c.Assert(err, ErrorMatches, "ulimit nofile soft limit .*")
+	ft.serviceStore.AssertNotCalled(c, "Put", ft.ctx, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_UpdateService_MacvlanWithoutPoolNetwork(c *C) {
+	svc := service.Service{ID: "svcmacvlan", Name: "svcmacvlan", PoolID: "default"}
+	ft.serviceStore.On("Get", ft.ctx, svc.ID).Return(&svc, nil)
+	ft.poolStore.On("Get", ft.ctx, pool.Key(svc.PoolID), mock.AnythingOfType("*pool.ResourcePool")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*pool.ResourcePool) = pool.ResourcePool{ID: svc.PoolID}
+		})
+
+	update := svc
+	update.NetworkMode = servicedefinition.NetworkMacvlan
+	err := ft.Facade.UpdateService(ft.ctx, update)
+	c.Assert(err, Equals, facade.ErrServiceNoMacvlan)
+	ft.serviceStore.AssertNotCalled(c, "Put", ft.ctx, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_UpdateService_MemoryMountsExceedHostMemory(c *C) {
+	svc := service.Service{ID: "svcshm", Name: "svcshm", PoolID: "default"}
+	ft.serviceStore.On("Get", ft.ctx, svc.ID).Return(&svc, nil)
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, svc.PoolID).
+		Return([]host.Host{{ID: "host1", Memory: 1 << 30}, {ID: "host2", Memory: 2 << 30}}, nil)
+
+	update := svc
+	update.ShmSize = utils.NewEngNotation(1 << 30)
+	update.Tmpfs = []servicedefinition.TmpfsMount{
+		{ContainerPath: "/var/cache", Size: utils.NewEngNotation(1 << 30)},
+	}
+	err := ft.Facade.UpdateService(ft.ctx, update)
+	c.Assert(err, Equals, facade.ErrServiceMemoryMounts)
+	ft.serviceStore.AssertNotCalled(c, "Put", ft.ctx, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_UpdateService_PlacementHostNotInPool(c *C) {
+	svc := service.Service{ID: "svcpinned", Name: "svcpinned", PoolID: "default"}
+	ft.serviceStore.On("Get", ft.ctx, svc.ID).Return(&svc, nil)
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, svc.PoolID).
+		Return([]host.Host{{ID: "host1", Name: "alpha"}, {ID: "host2", Name: "beta"}}, nil)
+
+	update := svc
+	update.Placement = servicedefinition.Placement{
+		Hosts:     []string{"alpha", "host2"},
+		Instances: []servicedefinition.InstancePlacement{{InstanceID: 0, Host: "gamma"}},
+	}
+	err := ft.Facade.UpdateService(ft.ctx, update)
+	c.Assert(err, Equals, facade.ErrServicePlacementHost)
+	ft.serviceStore.AssertNotCalled(c, "Put", ft.ctx, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_GetServices_Canceled(c *C) {
+	ft.ctx.On("Done").Return(nil)
+	ctx, cancel := datastore.WithCancel(ft.ctx)
+	cancel()
+
+	ft.serviceStore.On("GetServices", ctx).Return([]service.Service{{ID: "svc1"}, {ID: "svc2"}}, nil)
+
+	svcs, err := ft.Facade.GetServices(ctx, dao.ServiceRequest{})
+	c.Assert(err, Equals, ctx.Err())
+	c.Assert(svcs, IsNil)
+	ft.configStore.AssertNotCalled(c, "GetConfigFiles", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_GetServices_CallerDisconnected(c *C) {
+	// the rpc codec cancels the call context when the client disconnects
+	call, disconnect := context.WithCancel(context.Background())
+	ctx, cancel := datastore.WithCallContext(ft.ctx, call)
+	defer cancel()
+
+	ft.serviceStore.On("GetServices", ctx).Return([]service.Service{{ID: "svc1"}, {ID: "svc2"}}, nil).Run(func(mock.Arguments) {
+		disconnect()
+		<-ctx.Done()
+	})
+
+	svcs, err := ft.Facade.GetServices(ctx, dao.ServiceRequest{})
+	c.Assert(err, Equals, context.Canceled)
+	c.Assert(svcs, IsNil)
+	ft.configStore.AssertNotCalled(c, "GetConfigFiles", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_GetServiceList_Canceled(c *C) {
+	ft.ctx.On("Done").Return(nil)
+	ctx, cancel := datastore.WithCancel(ft.ctx)
+	cancel()
+
+	_, err := ft.Facade.GetServiceList(ctx, "tenant")
+	c.Assert(err, ErrorMatches, ".*"+ctx.Err().Error())
+	ft.serviceStore.AssertNotCalled(c, "Get", mock.Anything, mock.Anything)
+	ft.serviceStore.AssertNotCalled(c, "GetChildServices", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_StopService_Multiple(c *C) {
+	svcs := []service.Service{
+		{ID: "bulk-tenant1", PoolID: "default", DesiredState: int(service.SVCRun)},
+		{ID: "bulk-web", PoolID: "default", ParentServiceID: "bulk-tenant1", DesiredState: int(service.SVCRun)},
+		{ID: "bulk-tenant2", PoolID: "default", DesiredState: int(service.SVCRun)},
+		{ID: "bulk-db", PoolID: "default", ParentServiceID: "bulk-tenant2", DesiredState: int(service.SVCStop)},
+	}
+	for i := range svcs {
+		svc := svcs[i]
+		ft.serviceStore.On("Get", ft.ctx, svc.ID).Return(&svc, nil)
+	}
+	ft.serviceStore.On("GetChildServices", ft.ctx, "bulk-web").Return([]service.Service{}, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, "bulk-tenant2").Return([]service.Service{svcs[3]}, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, "bulk-db").Return([]service.Service{}, nil)
+	ft.serviceStore.On("UpdateDesiredState", ft.ctx, mock.AnythingOfType("string"), int(service.SVCStop)).Return(nil)
+
+	stopped := make(map[string]string)
+	ft.zzk.On("UpdateService", ft.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("*service.Service"), false, false).Return(nil).Run(func(args mock.Arguments) {
+		svc := args.Get(2).(*service.Service)
+		stopped[svc.ID] = args.String(1)
+	})
+
+	// overlapping services are only scheduled once
+	request := dao.ScheduleServiceRequest{ServiceID: "bulk-web", ServiceIDs: []string{"bulk-tenant2", "bulk-db", "bulk-web"}, AutoLaunch: true}
+	count, err := ft.Facade.StopService(ft.ctx, request)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 2)
+	c.Assert(stopped, DeepEquals, map[string]string{"bulk-web": "bulk-tenant1", "bulk-tenant2": "bulk-tenant2"})
+}
+
+func (ft *FacadeUnitTest) Test_StopService_RecordsHistory(c *C) {
+	svc := service.Service{ID: "history-web", PoolID: "default", DesiredState: int(service.SVCRun)}
+	ft.serviceStore.On("Get", mock.Anything, svc.ID).Return(&svc, nil)
+	ft.serviceStore.On("UpdateDesiredState", mock.Anything, svc.ID, int(service.SVCStop)).Return(nil)
+	ft.zzk.On("UpdateService", mock.Anything, svc.ID, mock.AnythingOfType("*service.Service"), false, false).Return(nil)
+
+	request := dao.ScheduleServiceRequest{ServiceID: svc.ID, Source: statehistory.SourceCLI, User: "zenoss"}
+	count, err := ft.Facade.StopService(ft.ctx, request)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 1)
+	c.Assert(ft.historyStore.Calls, HasLen, 1)
+	c.Assert(ft.historyStore.Calls[0].Arguments.String(1), Equals, svc.ID)
+	change := ft.historyStore.Calls[0].Arguments.Get(2).(statehistory.Change)
+	c.Assert(change.From, Equals, service.SVCRun)
+	c.Assert(change.To, Equals, service.SVCStop)
+	c.Assert(change.Source, Equals, statehistory.SourceCLI)
+	c.Assert(change.User, Equals, "zenoss")
+}
+
+func (ft *FacadeUnitTest) Test_GetServiceStateHistory(c *C) {
+	svc := service.Service{ID: "history-web"}
+	ft.serviceStore.On("Get", ft.ctx, svc.ID).Return(&svc, nil)
+	ft.serviceStore.On("Get", ft.ctx, "history-missing").Return(nil, datastore.ErrNoSuchEntity{})
+	ft.serviceStore.On("Get", ft.ctx, "history-new").Return(&service.Service{ID: "history-new"}, nil)
+	changes := []statehistory.Change{{From: service.SVCStop, To: service.SVCRun, Source: statehistory.SourceScheduler}}
+	ft.historyStore.On("Get", ft.ctx, svc.ID).Return(&statehistory.History{ServiceID: svc.ID, Changes: changes}, nil)
+	ft.historyStore.On("Get", ft.ctx, "history-new").Return(nil, datastore.ErrNoSuchEntity{})
+
+	actual, err := ft.Facade.GetServiceStateHistory(ft.ctx, svc.ID)
+	c.Assert(err, IsNil)
+	c.Assert(actual, DeepEquals, changes)
+
+	actual, err = ft.Facade.GetServiceStateHistory(ft.ctx, "history-new")
+	c.Assert(err, IsNil)
+	c.Assert(actual, HasLen, 0)
+
+	_, err = ft.Facade.GetServiceStateHistory(ft.ctx, "history-missing")
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+}
+
+func (ft *FacadeUnitTest) Test_QueryServices(c *C) {
+	summaries := []service.ServiceSummary{
+		{ID: "query-tenant", Name: "Zenoss", DeploymentID: "dep", PoolID: "default", Path: "Zenoss"},
+		{ID: "query-zope", Name: "Zope", DeploymentID: "dep", PoolID: "default", ParentServiceID: "query-tenant", Path: "Zenoss/Zope"},
+		{ID: "query-zproxy", Name: "zproxy", DeploymentID: "dep", PoolID: "default", ParentServiceID: "query-zope", Path: "Zenoss/Zope/zproxy"},
+	}
+	ft.serviceStore.On("GetServiceSummaries", ft.ctx).Return(summaries, nil)
+
+	page, err := ft.Facade.QueryServices(ft.ctx, service.ServiceQuery{PathSuffix: "zenoss/zope"})
+	c.Assert(err, IsNil)
+	c.Assert(page.Total, Equals, 1)
+	c.Assert(page.Services, DeepEquals, summaries[1:2])
+
+	page, err = ft.Facade.QueryServices(ft.ctx, service.ServiceQuery{DeploymentID: "dep", Limit: 2})
+	c.Assert(err, IsNil)
+	c.Assert(page.Total, Equals, 3)
+	c.Assert(page.Services, DeepEquals, summaries[:2])
+}
+
+func (ft *FacadeUnitTest) Test_QueryServices_StoreError(c *C) {
+	ft.serviceStore.On("GetServiceSummaries", ft.ctx).Return(nil, fmt.Errorf("elastic is down"))
+
+	_, err := ft.Facade.QueryServices(ft.ctx, service.ServiceQuery{})
+	c.Assert(err, ErrorMatches, "elastic is down")
+}
+
+func (ft *FacadeUnitTest) Test_ResolveServicePath(c *C) {
+	summaries := []service.ServiceSummary{
+		{ID: "resolve-tenant", Name: "Zenoss", DeploymentID: "dep", PoolID: "default", Path: "Zenoss"},
+		{ID: "resolve-zope", Name: "Zope", DeploymentID: "dep", PoolID: "default", ParentServiceID: "resolve-tenant", Path: "Zenoss/Zope"},
+	}
+	ft.serviceStore.On("GetServiceSummaries", ft.ctx).Return(summaries, nil)
+
+	matches, err := ft.Facade.ResolveServicePath(ft.ctx, "default/zenoss/zope")
+	c.Assert(err, IsNil)
+	c.Assert(matches, DeepEquals, []service.ServiceMatch{{ServiceSummary: summaries[1]}})
+
+	matches, err = ft.Facade.ResolveServicePath(ft.ctx, "missing")
+	c.Assert(err, IsNil)
+	c.Assert(matches, HasLen, 0)
+}
+
+func (ft *FacadeUnitTest) Test_StopService_MultipleNotFound(c *C) {
+	web := service.Service{ID: "bulk-web", PoolID: "default", DesiredState: int(service.SVCRun)}
+	ft.serviceStore.On("Get", ft.ctx, "bulk-web").Return(&web, nil)
+	ft.serviceStore.On("Get", ft.ctx, "bulk-missing").Return(nil, fmt.Errorf("service not found"))
+
+	request := dao.ScheduleServiceRequest{ServiceIDs: []string{"bulk-web", "bulk-missing"}}
+	count, err := ft.Facade.StopService(ft.ctx, request)
+	c.Assert(err, ErrorMatches, "service not found")
+	c.Assert(count, Equals, 0)
+	ft.serviceStore.AssertNotCalled(c, "UpdateDesiredState", mock.Anything, mock.Anything, mock.Anything)
+}
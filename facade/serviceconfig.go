@@ -22,7 +22,12 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicedefinition"
-	"github.com/zenoss/glog"
+)
+
+var (
+	ErrNoConfigConflict        = errors.New("facade: config file has no conflict")
+	ErrConfigMergeConflict     = errors.New("facade: config file versions do not merge cleanly")
+	ErrInvalidConfigResolution = errors.New("facade: config conflict resolution must be mine, theirs, or merged")
 )
 
 // GetServiceConfigs returns the config files for a service
@@ -64,8 +69,8 @@ func (f *Facade) GetServiceConfig(ctx datastore.Context, fileID string) (*servic
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetServiceConfig"))
 	logger := plog.WithField("fileid", fileID)
 
-	file := &serviceconfigfile.SvcConfigFile{}
-	if err := f.configStore.Get(ctx, serviceconfigfile.Key(fileID), file); err != nil {
+	file, err := f.getServiceConfigInScope(ctx, fileID)
+	if err != nil {
 		logger.WithError(err).Debug("Could not get service config file")
 		return nil, err
 	}
@@ -129,8 +134,8 @@ func (f *Facade) UpdateServiceConfig(ctx datastore.Context, fileID string, conf
 		"filename": conf.Filename,
 	})
 
-	file := &serviceconfigfile.SvcConfigFile{}
-	if err := f.configStore.Get(ctx, serviceconfigfile.Key(fileID), file); err != nil {
+	file, err := f.getServiceConfigInScope(ctx, fileID)
+	if err != nil {
 		logger.WithError(err).Debug("Could not get service config file")
 		return err
 	}
@@ -153,6 +158,11 @@ func (f *Facade) DeleteServiceConfig(ctx datastore.Context, fileID string) error
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("DeleteServiceConfig"))
 	logger := plog.WithField("fileid", fileID)
 
+	if _, err := f.getServiceConfigInScope(ctx, fileID); err != nil {
+		logger.WithError(err).Debug("Could not get service config file")
+		return err
+	}
+
 	if err := f.configStore.Delete(ctx, serviceconfigfile.Key(fileID)); err != nil {
 		logger.WithError(err).Debug("Could not delete service config file")
 		return err
@@ -162,6 +172,96 @@ func (f *Facade) DeleteServiceConfig(ctx datastore.Context, fileID string) error
 	return nil
 }
 
+// GetServiceConfigConflicts returns the config files of a service that were
+// modified by the user and also changed by a redeploy of its template
+func (f *Facade) GetServiceConfigConflicts(ctx datastore.Context, serviceID string) ([]service.ConfigConflict, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetServiceConfigConflicts"))
+	logger := plog.WithField("serviceid", serviceID)
+
+	tenantID, servicePath, err := f.getServicePath(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not trace service path")
+		return nil, err
+	}
+
+	files, err := f.configStore.GetConfigFiles(ctx, tenantID, servicePath)
+	if err != nil {
+		logger.WithError(err).Debug("Could not load existing configs for service")
+		return nil, err
+	}
+
+	conflicts := []service.ConfigConflict{}
+	for _, file := range files {
+		if file.Conflict == nil {
+			continue
+		}
+		merged, clean := serviceconfigfile.Merge(file.Conflict.Base, file.ConfFile.Content, file.Conflict.Theirs.Content)
+		conflicts = append(conflicts, service.ConfigConflict{
+			ID:       file.ID,
+			Filename: file.ConfFile.Filename,
+			Base:     file.Conflict.Base,
+			Mine:     file.ConfFile.Content,
+			Theirs:   file.Conflict.Theirs.Content,
+			Merged:   merged,
+			Clean:    clean,
+		})
+	}
+
+	logger.WithField("count", len(conflicts)).Debug("Loaded config conflicts for service")
+	return conflicts, nil
+}
+
+// ResolveServiceConfigConflict resolves a conflict between the user's version
+// of a config file and the new version of its template.  The resolution keeps
+// the user's version (mine), takes the template's version (theirs), or takes
+// their clean three-way merge (merged).  If content is set, it replaces the
+// file instead, e.g. after the user merged the versions by hand.
+func (f *Facade) ResolveServiceConfigConflict(ctx datastore.Context, fileID, resolution, content string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ResolveServiceConfigConflict"))
+	logger := plog.WithFields(log.Fields{
+		"fileid":     fileID,
+		"resolution": resolution,
+	})
+
+	file, err := f.getServiceConfigInScope(ctx, fileID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not get service config file")
+		return err
+	}
+
+	if file.Conflict == nil {
+		logger.Debug("Service config file has no conflict")
+		return ErrNoConfigConflict
+	}
+
+	switch {
+	case content != "":
+		file.ConfFile.Content = content
+	case resolution == serviceconfigfile.ResolveMine:
+	case resolution == serviceconfigfile.ResolveTheirs:
+		file.ConfFile = file.Conflict.Theirs
+	case resolution == serviceconfigfile.ResolveMerged:
+		merged, clean := serviceconfigfile.Merge(file.Conflict.Base, file.ConfFile.Content, file.Conflict.Theirs.Content)
+		if !clean {
+			logger.Debug("Service config file does not merge cleanly")
+			return ErrConfigMergeConflict
+		}
+		file.ConfFile.Content = merged
+	default:
+		logger.Debug("Invalid resolution for service config file")
+		return ErrInvalidConfigResolution
+	}
+	file.Conflict = nil
+
+	if err := f.configStore.Put(ctx, serviceconfigfile.Key(fileID), file); err != nil {
+		logger.WithError(err).Debug("Could not update record in database")
+		return err
+	}
+
+	logger.Info("Resolved service config file conflict")
+	return nil
+}
+
 // getServicePath returns the tenantID and the full path of the service
 // TODO: update function to include deploymentID in the service path
 func (f *Facade) getServicePath(ctx datastore.Context, serviceID string) (tenantID string, servicePath string, err error) {
@@ -180,7 +280,7 @@ func (f *Facade) updateServiceConfigs(ctx datastore.Context, serviceID string, c
 	}
 	svcConfigFiles, err := f.configStore.GetConfigFiles(ctx, tenantID, servicePath)
 	if err != nil {
-		glog.Errorf("Could not load existing configs for service %s: %s", serviceID, err)
+		plog.Errorf("Could not load existing configs for service %s: %s", serviceID, err)
 		return err
 	}
 	svcConfigFileMap := make(map[string]*serviceconfigfile.SvcConfigFile)
@@ -193,21 +293,21 @@ func (f *Facade) updateServiceConfigs(ctx datastore.Context, serviceID string, c
 			delete(svcConfigFileMap, configFile.Filename)
 			// do not update database if there are no configuration changes
 			if reflect.DeepEqual(svcConfigFile.ConfFile, configFile) {
-				glog.V(1).Infof("Skipping config file %s", configFile.Filename)
+				plog.Debugf("Skipping config file %s", configFile.Filename)
 				continue
 			}
 			svcConfigFile.ConfFile = configFile
-			glog.Infof("Updating config file %s for service %s", configFile.Filename, serviceID)
+			plog.Infof("Updating config file %s for service %s", configFile.Filename, serviceID)
 		} else {
 			svcConfigFile, err = serviceconfigfile.New(tenantID, servicePath, configFile)
 			if err != nil {
-				glog.Errorf("Could not create new service config file %s for service %s: %s", configFile.Filename, serviceID, err)
+				plog.Errorf("Could not create new service config file %s for service %s: %s", configFile.Filename, serviceID, err)
 				return err
 			}
-			glog.Infof("Adding config file %s for service %s", configFile.Filename, serviceID)
+			plog.Infof("Adding config file %s for service %s", configFile.Filename, serviceID)
 		}
 		if err := f.configStore.Put(ctx, serviceconfigfile.Key(svcConfigFile.ID), svcConfigFile); err != nil {
-			glog.Errorf("Could not update service config file %s for service %s: %s", configFile.Filename, serviceID, err)
+			plog.Errorf("Could not update service config file %s for service %s: %s", configFile.Filename, serviceID, err)
 			return err
 		}
 	}
@@ -215,10 +315,92 @@ func (f *Facade) updateServiceConfigs(ctx datastore.Context, serviceID string, c
 	if forceDelete {
 		for filename, svcConfigFile := range svcConfigFileMap {
 			if err := f.configStore.Delete(ctx, serviceconfigfile.Key(svcConfigFile.ID)); err != nil {
-				glog.Errorf("Could not delete service config file %s for service %s: %s", filename, serviceID, err)
+				plog.Errorf("Could not delete service config file %s for service %s: %s", filename, serviceID, err)
 				return err
 			}
-			glog.Infof("Deleting config file %s from service %s", filename, serviceID)
+			plog.Infof("Deleting config file %s from service %s", filename, serviceID)
+		}
+	}
+	return nil
+}
+
+// upgradeServiceConfigs reconciles the config files of a service with a new
+// version of its template, which is set as the original configs of the
+// service.  Files the user did not modify take the new version, and files
+// the template did not change keep the user's version.  Files that were
+// changed by both keep the user's version and are returned as conflicts.
+func (f *Facade) upgradeServiceConfigs(ctx datastore.Context, cursvc, svc *service.Service) (map[string]*serviceconfigfile.Conflict, error) {
+	tenantID, servicePath, err := f.getServicePath(ctx, svc.ID)
+	if err != nil {
+		return nil, err
+	}
+	svcConfigFiles, err := f.configStore.GetConfigFiles(ctx, tenantID, servicePath)
+	if err != nil {
+		plog.Errorf("Could not load existing configs for service %s: %s", svc.ID, err)
+		return nil, err
+	}
+	current := make(map[string]servicedefinition.ConfigFile)
+	for _, svcConfigFile := range svcConfigFiles {
+		current[svcConfigFile.ConfFile.Filename] = svcConfigFile.ConfFile
+	}
+
+	configFiles := make(map[string]servicedefinition.ConfigFile)
+	for filename, configFile := range svc.ConfigFiles {
+		configFiles[filename] = configFile
+	}
+	conflicts := make(map[string]*serviceconfigfile.Conflict)
+	for filename, theirs := range svc.OriginalConfigs {
+		var base, mine *servicedefinition.ConfigFile
+		if configFile, ok := cursvc.OriginalConfigs[filename]; ok {
+			base = &configFile
+		}
+		if configFile, ok := current[filename]; ok {
+			mine = &configFile
+		}
+		// respect files that were explicitly changed by the update
+		if configFile, ok := configFiles[filename]; ok && !reflect.DeepEqual(configFile, theirs) && (mine == nil || !reflect.DeepEqual(configFile, *mine)) {
+			continue
+		}
+		configFile, conflict := serviceconfigfile.Upgrade(base, mine, theirs)
+		configFiles[filename] = configFile
+		if conflict != nil {
+			plog.Warningf("Config file %s of service %s was changed by both the user and the template; keeping the user's version until the conflict is resolved", filename, svc.ID)
+			conflicts[filename] = conflict
+		}
+	}
+	// keep the files that the user added to the service
+	for filename, configFile := range current {
+		if _, ok := configFiles[filename]; !ok {
+			if _, ok := cursvc.OriginalConfigs[filename]; !ok {
+				configFiles[filename] = configFile
+			}
+		}
+	}
+	svc.ConfigFiles = configFiles
+	return conflicts, nil
+}
+
+// setServiceConfigConflicts records the conflicts of the config files of a
+// service, clearing any conflicts that no longer apply.
+func (f *Facade) setServiceConfigConflicts(ctx datastore.Context, serviceID string, conflicts map[string]*serviceconfigfile.Conflict) error {
+	tenantID, servicePath, err := f.getServicePath(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	svcConfigFiles, err := f.configStore.GetConfigFiles(ctx, tenantID, servicePath)
+	if err != nil {
+		plog.Errorf("Could not load existing configs for service %s: %s", serviceID, err)
+		return err
+	}
+	for _, svcConfigFile := range svcConfigFiles {
+		conflict := conflicts[svcConfigFile.ConfFile.Filename]
+		if reflect.DeepEqual(svcConfigFile.Conflict, conflict) {
+			continue
+		}
+		svcConfigFile.Conflict = conflict
+		if err := f.configStore.Put(ctx, serviceconfigfile.Key(svcConfigFile.ID), svcConfigFile); err != nil {
+			plog.Errorf("Could not update service config file %s for service %s: %s", svcConfigFile.ConfFile.Filename, serviceID, err)
+			return err
 		}
 	}
 	return nil
@@ -232,18 +414,35 @@ func (f *Facade) fillServiceConfigs(ctx datastore.Context, svc *service.Service)
 	}
 	svcConfigFiles, err := f.configStore.GetConfigFiles(ctx, tenantID, servicePath)
 	if err != nil {
-		glog.Errorf("Could not load existing configs for service %s (%s): %s", svc.Name, svc.ID, err)
+		plog.Errorf("Could not load existing configs for service %s (%s): %s", svc.Name, svc.ID, err)
 		return err
 	}
 	svc.ConfigFiles = make(map[string]servicedefinition.ConfigFile)
 	for _, configFile := range svc.OriginalConfigs {
 		svc.ConfigFiles[configFile.Filename] = configFile
-		glog.V(1).Infof("Copying original config file %s from service %s (%s)", configFile.Filename, svc.Name, svc.ID)
+		plog.Debugf("Copying original config file %s from service %s (%s)", configFile.Filename, svc.Name, svc.ID)
 	}
 	for _, svcConfigFile := range svcConfigFiles {
 		filename, configFile := svcConfigFile.ConfFile.Filename, svcConfigFile.ConfFile
 		svc.ConfigFiles[filename] = configFile
-		glog.V(1).Infof("Loading config file %s for service %s (%s)", filename, svc.Name, svc.ID)
+		plog.Debugf("Loading config file %s for service %s (%s)", filename, svc.Name, svc.ID)
 	}
 	return nil
 }
+
+// getServiceConfigInScope returns a config file, or ErrTenantAccessDenied if
+// it belongs to a tenant that the actor of the context may not access.
+func (f *Facade) getServiceConfigInScope(ctx datastore.Context, fileID string) (*serviceconfigfile.SvcConfigFile, error) {
+	file := &serviceconfigfile.SvcConfigFile{}
+	if err := f.configStore.Get(ctx, serviceconfigfile.Key(fileID), file); err != nil {
+		return nil, err
+	}
+	scope, err := f.tenantScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTenantScope(scope, file.ServiceTenantID, file.ServiceTenantID); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
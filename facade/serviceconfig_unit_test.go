@@ -0,0 +1,99 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupConfigConflict(conflict *serviceconfigfile.Conflict, content string) {
+	file := serviceconfigfile.SvcConfigFile{
+		ID:              "file",
+		ServiceTenantID: "tenant",
+		ServicePath:     "/tenant",
+		ConfFile:        servicedefinition.ConfigFile{Filename: "/etc/app.conf", Content: content},
+		Conflict:        conflict,
+	}
+	ft.serviceStore.On("Get", ft.ctx, "tenant").Return(&service.Service{ID: "tenant"}, nil)
+	ft.configStore.On("GetConfigFiles", ft.ctx, "tenant", "/tenant").Return([]*serviceconfigfile.SvcConfigFile{&file}, nil)
+	ft.configStore.On("Get", ft.ctx, serviceconfigfile.Key("file"), mock.AnythingOfType("*serviceconfigfile.SvcConfigFile")).Return(nil).Run(
+		func(args mock.Arguments) {
+			*args.Get(2).(*serviceconfigfile.SvcConfigFile) = file
+		})
+}
+
+func (ft *FacadeUnitTest) Test_GetServiceConfigConflicts(c *C) {
+	ft.setupConfigConflict(&serviceconfigfile.Conflict{
+		Base:   "a\nb\n",
+		Theirs: servicedefinition.ConfigFile{Filename: "/etc/app.conf", Content: "a\nb\nc\n"},
+	}, "A\nb\n")
+
+	conflicts, err := ft.Facade.GetServiceConfigConflicts(ft.ctx, "tenant")
+	c.Assert(err, IsNil)
+	c.Assert(conflicts, DeepEquals, []service.ConfigConflict{
+		{
+			ID:       "file",
+			Filename: "/etc/app.conf",
+			Base:     "a\nb\n",
+			Mine:     "A\nb\n",
+			Theirs:   "a\nb\nc\n",
+			Merged:   "A\nb\nc\n",
+			Clean:    true,
+		},
+	})
+}
+
+func (ft *FacadeUnitTest) Test_ResolveServiceConfigConflict_NoConflict(c *C) {
+	ft.setupConfigConflict(nil, "a\n")
+
+	err := ft.Facade.ResolveServiceConfigConflict(ft.ctx, "file", serviceconfigfile.ResolveTheirs, "")
+	c.Assert(err, Equals, facade.ErrNoConfigConflict)
+	ft.configStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_ResolveServiceConfigConflict_Merged(c *C) {
+	ft.setupConfigConflict(&serviceconfigfile.Conflict{
+		Base:   "a\nb\n",
+		Theirs: servicedefinition.ConfigFile{Filename: "/etc/app.conf", Content: "a\nb\nc\n"},
+	}, "A\nb\n")
+	ft.configStore.On("Put", ft.ctx, serviceconfigfile.Key("file"), mock.AnythingOfType("*serviceconfigfile.SvcConfigFile")).Return(nil).Run(
+		func(args mock.Arguments) {
+			file := args.Get(2).(*serviceconfigfile.SvcConfigFile)
+			c.Assert(file.ConfFile.Content, Equals, "A\nb\nc\n")
+			c.Assert(file.Conflict, IsNil)
+		})
+
+	err := ft.Facade.ResolveServiceConfigConflict(ft.ctx, "file", serviceconfigfile.ResolveMerged, "")
+	c.Assert(err, IsNil)
+	ft.configStore.AssertCalled(c, "Put", ft.ctx, serviceconfigfile.Key("file"), mock.AnythingOfType("*serviceconfigfile.SvcConfigFile"))
+}
+
+func (ft *FacadeUnitTest) Test_ResolveServiceConfigConflict_NotMerged(c *C) {
+	ft.setupConfigConflict(&serviceconfigfile.Conflict{
+		Base:   "a\n",
+		Theirs: servicedefinition.ConfigFile{Filename: "/etc/app.conf", Content: "c\n"},
+	}, "b\n")
+
+	err := ft.Facade.ResolveServiceConfigConflict(ft.ctx, "file", serviceconfigfile.ResolveMerged, "")
+	c.Assert(err, Equals, facade.ErrConfigMergeConflict)
+	err = ft.Facade.ResolveServiceConfigConflict(ft.ctx, "file", "ours", "")
+	c.Assert(err, Equals, facade.ErrInvalidConfigResolution)
+	ft.configStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything, mock.Anything)
+}
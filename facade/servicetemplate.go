@@ -18,7 +18,6 @@ import (
 	"time"
 
 	dockerclient "github.com/fsouza/go-dockerclient"
-	"github.com/zenoss/glog"
 
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
@@ -45,23 +44,23 @@ func (f *Facade) AddServiceTemplate(ctx datastore.Context, serviceTemplate servi
 	serviceTemplate.ID = hash
 	// Look up the template by ID
 	if st, err := store.Get(ctx, hash); err != nil && !datastore.IsErrNoSuchEntity(err) {
-		glog.Errorf("Could not look up service template by hash %s: %s", hash, err)
+		plog.Errorf("Could not look up service template by hash %s: %s", hash, err)
 		return "", err
 	} else if st != nil {
-		glog.Infof("Not replacing existing template %s", hash)
+		plog.Infof("Not replacing existing template %s", hash)
 		return hash, nil
 	}
 	// Look up the template by md5 hash
 	if tid, err := f.getServiceTemplateByMD5Sum(ctx, hash); err != nil {
-		glog.Errorf("Could not verify existance of template by md5sum %s: %s", hash, err)
+		plog.Errorf("Could not verify existance of template by md5sum %s: %s", hash, err)
 		return "", nil
 	} else if tid != "" {
-		glog.Infof("Not replacing existing template %s", tid)
+		plog.Infof("Not replacing existing template %s", tid)
 		return tid, nil
 	}
 	// Add the template to the database
 	if err := store.Put(ctx, serviceTemplate); err != nil {
-		glog.Errorf("Could not add template at %s: %s", hash, err)
+		plog.Errorf("Could not add template at %s: %s", hash, err)
 		return "", err
 	}
 	// This takes a while so don't block the main thread
@@ -86,7 +85,7 @@ func (f *Facade) RemoveServiceTemplate(ctx datastore.Context, id string) error {
 		return fmt.Errorf("Unable to find template: %s", id)
 	}
 
-	glog.V(2).Infof("Facade.RemoveServiceTemplate: %s", id)
+	plog.Debugf("Facade.RemoveServiceTemplate: %s", id)
 	if err := f.templateStore.Delete(ctx, id); err != nil {
 		return err
 	}
@@ -100,7 +99,7 @@ func (f *Facade) RestoreServiceTemplates(ctx datastore.Context, templates []serv
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("RestoreServiceTemplates"))
 	curtemplates, err := f.GetServiceTemplates(ctx)
 	if err != nil {
-		glog.Errorf("Could not look up service templates: %s", err)
+		plog.Errorf("Could not look up service templates: %s", err)
 		return err
 	}
 
@@ -108,13 +107,13 @@ func (f *Facade) RestoreServiceTemplates(ctx datastore.Context, templates []serv
 		template.DatabaseVersion = 0
 		if _, ok := curtemplates[template.ID]; ok {
 			if err := f.UpdateServiceTemplate(ctx, template); err != nil {
-				glog.Errorf("Could not update service template %s: %s", template.ID, err)
+				plog.Errorf("Could not update service template %s: %s", template.ID, err)
 				return err
 			}
 		} else {
 			template.ID = ""
 			if _, err := f.AddServiceTemplate(ctx, template); err != nil {
-				glog.Errorf("Could not add service template %s: %s", template.ID, err)
+				plog.Errorf("Could not add service template %s: %s", template.ID, err)
 				return err
 			}
 		}
@@ -128,13 +127,13 @@ func (f *Facade) getServiceTemplateByMD5Sum(ctx datastore.Context, md5Sum string
 	store := f.templateStore
 	templates, err := store.GetServiceTemplates(ctx)
 	if err != nil {
-		glog.Errorf("Could not get service templates: %s", err)
+		plog.Errorf("Could not get service templates: %s", err)
 		return "", err
 	}
 	for _, t := range templates {
 		hash, err := t.Hash()
 		if err != nil {
-			glog.Errorf("Could not get md5sum for template %s: %s", t.ID, err)
+			plog.Errorf("Could not get md5sum for template %s: %s", t.ID, err)
 			return "", err
 		}
 		if md5Sum == hash {
@@ -146,11 +145,11 @@ func (f *Facade) getServiceTemplateByMD5Sum(ctx datastore.Context, md5Sum string
 
 func (f *Facade) GetServiceTemplates(ctx datastore.Context) (map[string]servicetemplate.ServiceTemplate, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetServiceTemplates"))
-	glog.V(2).Infof("Facade.GetServiceTemplates")
+	plog.Debugf("Facade.GetServiceTemplates")
 	results, err := f.templateStore.GetServiceTemplates(ctx)
 	templateMap := make(map[string]servicetemplate.ServiceTemplate)
 	if err != nil {
-		glog.V(2).Infof("Facade.GetServiceTemplates: err=%s", err)
+		plog.Debugf("Facade.GetServiceTemplates: err=%s", err)
 		return templateMap, err
 	}
 	for _, st := range results {
@@ -161,10 +160,10 @@ func (f *Facade) GetServiceTemplates(ctx datastore.Context) (map[string]servicet
 
 func (f *Facade) GetServiceTemplatesAndImages(ctx datastore.Context) ([]servicetemplate.ServiceTemplate, []string, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetServiceTemplatesAndImages"))
-	glog.V(2).Infof("Facade.GetServiceTemplateImages")
+	plog.Debugf("Facade.GetServiceTemplateImages")
 	results, err := f.templateStore.GetServiceTemplates(ctx)
 	if err != nil {
-		glog.Errorf("Could not get service templates: %s", err)
+		plog.Errorf("Could not get service templates: %s", err)
 		return nil, nil, err
 	}
 	var imagesMap = make(map[string]struct{})
@@ -247,13 +246,13 @@ func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID
 	UpdateDeployTemplateStatus(deploymentID, "deploy_loading_template|"+templateID)
 	template, err := f.templateStore.Get(ctx, templateID)
 	if err != nil {
-		glog.Errorf("unable to load template: %s", templateID)
+		plog.Errorf("unable to load template: %s", templateID)
 		return nil, err
 	}
 
 	//check that deployment id does not already exist
 	if svcs, err := f.serviceStore.GetServicesByDeployment(ctx, deploymentID); err != nil {
-		glog.Errorf("unable to validate deploymentID %v while deploying %v", deploymentID, templateID)
+		plog.Errorf("unable to validate deploymentID %v while deploying %v", deploymentID, templateID)
 		return nil, err
 	} else if len(svcs) > 0 {
 		return nil, fmt.Errorf("deployment ID %s is already in use", deploymentID)
@@ -265,7 +264,7 @@ func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID
 	UpdateDeployTemplateStatus(deploymentID, "deploy_loading_resource_pool|"+poolID)
 	pool, err := f.GetResourcePool(ctx, poolID)
 	if err != nil {
-		glog.Errorf("Unable to load resource pool: %s", poolID)
+		plog.Errorf("Unable to load resource pool: %s", poolID)
 		return nil, err
 	}
 	if pool == nil {
@@ -274,14 +273,14 @@ func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID
 
 	tenantIDs := make([]string, len(template.Services))
 	for i, sd := range template.Services {
-		glog.Infof("Deploying application %s to %s", sd.Name, deploymentID)
+		plog.Infof("Deploying application %s to %s", sd.Name, deploymentID)
 		tenantID, err := f.deployService(ctx, "", "", deploymentID, poolID, false, sd)
 		if err != nil {
-			glog.Errorf("Could not deploy application %s to %s: %s", sd.Name, deploymentID, err)
+			plog.Errorf("Could not deploy application %s to %s: %s", sd.Name, deploymentID, err)
 			return nil, err
 		}
 		if err := f.dfs.Create(tenantID); err != nil {
-			glog.Errorf("Could not initialize volume for tenant %s: %s", tenantID, err)
+			plog.Errorf("Could not initialize volume for tenant %s: %s", tenantID, err)
 			return nil, err
 		}
 		tenantIDs[i] = tenantID
@@ -294,12 +293,15 @@ func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID
 // with the same name will be overwritten, otherwise services may only be added.
 func (f *Facade) DeployService(ctx datastore.Context, poolID, parentID string, overwrite bool, svcDef servicedefinition.ServiceDefinition) (string, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("DeployService"))
+	if err := f.checkNewServiceScope(ctx, parentID); err != nil {
+		return "", err
+	}
 	store := f.serviceStore
 
 	// get the parent service
 	svc, err := store.Get(ctx, parentID)
 	if err != nil {
-		glog.Errorf("Could not get parent service %s: %s", parentID, err)
+		plog.Errorf("Could not get parent service %s: %s", parentID, err)
 		return "", err
 	}
 
@@ -307,11 +309,11 @@ func (f *Facade) DeployService(ctx datastore.Context, poolID, parentID string, o
 	if poolID != "" {
 		// check the pool ID
 		if pool, err := f.GetResourcePool(ctx, poolID); err != nil {
-			glog.Errorf("Could not look up resource pool %s: %s", poolID, err)
+			plog.Errorf("Could not look up resource pool %s: %s", poolID, err)
 			return "", err
 		} else if pool == nil {
 			err := fmt.Errorf("pool not found")
-			glog.Errorf("Could not look up resource pool %s: %s", poolID, err)
+			plog.Errorf("Could not look up resource pool %s: %s", poolID, err)
 			return "", err
 		}
 	} else {
@@ -323,7 +325,7 @@ func (f *Facade) DeployService(ctx datastore.Context, poolID, parentID string, o
 	tenantID := svc.ID
 	if svc.ParentServiceID != "" {
 		if tenantID, err = f.GetTenantID(ctx, svc.ParentServiceID); err != nil { // make this call a little cheaper
-			glog.Errorf("Could not get tenant for %s (%s): %s", svc.Name, svc.ID, err)
+			plog.Errorf("Could not get tenant for %s (%s): %s", svc.Name, svc.ID, err)
 			return "", err
 		}
 	}
@@ -335,7 +337,7 @@ func (f *Facade) deployService(ctx datastore.Context, tenantID string, parentSer
 	// create the new service object
 	newsvc, err := service.BuildService(svcDef, parentServiceID, poolID, int(service.SVCStop), deploymentID)
 	if err != nil {
-		glog.Errorf("Could not create service: %s", err)
+		plog.Errorf("Could not create service: %s", err)
 		return "", err
 	}
 
@@ -356,7 +358,7 @@ func (f *Facade) deployService(ctx datastore.Context, tenantID string, parentSer
 		return *s, err
 	}
 	if err = newsvc.EvaluateEndpointTemplates(getService, findChildService, 0); err != nil {
-		glog.Errorf("Could not evaluate endpoint templates for service %s with parent %s: %s", newsvc.Name, newsvc.ParentServiceID, err)
+		plog.Errorf("Could not evaluate endpoint templates for service %s with parent %s: %s", newsvc.Name, newsvc.ParentServiceID, err)
 		return "", err
 	}
 	if tenantID == "" {
@@ -366,7 +368,7 @@ func (f *Facade) deployService(ctx datastore.Context, tenantID string, parentSer
 		UpdateDeployTemplateStatus(newsvc.DeploymentID, "deploy_loading_image|"+newsvc.Name)
 		image, err := f.dfs.Download(svcDef.ImageID, tenantID, false)
 		if err != nil {
-			glog.Errorf("Could not download image %s: %s", svcDef.ImageID, err)
+			plog.Errorf("Could not download image %s: %s", svcDef.ImageID, err)
 			return "", err
 		}
 		newsvc.ImageID = image
@@ -374,24 +376,26 @@ func (f *Facade) deployService(ctx datastore.Context, tenantID string, parentSer
 	// find the service
 	store := f.serviceStore
 	if svc, err := store.FindChildService(ctx, newsvc.DeploymentID, newsvc.ParentServiceID, newsvc.Name); err != nil {
-		glog.Errorf("Could not look up child service for %s with parent %s: %s", newsvc.Name, newsvc.ParentServiceID, err)
+		plog.Errorf("Could not look up child service for %s with parent %s: %s", newsvc.Name, newsvc.ParentServiceID, err)
 		return "", err
 	} else if svc != nil {
 		if overwrite {
 			newsvc.ID = svc.ID
 			newsvc.CreatedAt = svc.CreatedAt
-			if err := f.UpdateService(ctx, *newsvc); err != nil {
-				glog.Errorf("Could not overwrite service %s (%s): %s", newsvc.Name, newsvc.ID, err)
+			// migrate the service so that its config files are reconciled
+			// with the new version of the template
+			if err := f.MigrateService(ctx, *newsvc); err != nil {
+				plog.Errorf("Could not overwrite service %s (%s): %s", newsvc.Name, newsvc.ID, err)
 				return "", err
 			}
 		} else {
 			err := fmt.Errorf("service exists")
-			glog.Errorf("Service %s found at %s", newsvc.Name, newsvc.ID)
+			plog.Errorf("Service %s found at %s", newsvc.Name, newsvc.ID)
 			return "", err
 		}
 	} else {
 		if err := f.AddService(ctx, *newsvc); err != nil {
-			glog.Errorf("Could not add service %s (%s) at %s: %s", newsvc.Name, newsvc.ID, parentServiceID, err)
+			plog.Errorf("Could not add service %s (%s) at %s: %s", newsvc.Name, newsvc.ID, parentServiceID, err)
 			return "", err
 		}
 	}
@@ -399,7 +403,7 @@ func (f *Facade) deployService(ctx datastore.Context, tenantID string, parentSer
 	// walk child services
 	for _, sd := range svcDef.Services {
 		if _, err := f.deployService(ctx, tenantID, newsvc.ID, deploymentID, poolID, overwrite, sd); err != nil {
-			glog.Errorf("Error while trying to deploy %s at %s (%s): %s", sd.Name, newsvc.Name, newsvc.ID, err)
+			plog.Errorf("Error while trying to deploy %s at %s (%s): %s", sd.Name, newsvc.Name, newsvc.ID, err)
 			return newsvc.ID, err
 		}
 	}
@@ -425,16 +429,16 @@ func writeLogstashConfiguration(templates map[string]servicetemplate.ServiceTemp
 func reloadLogstashContainerImpl(ctx datastore.Context, f FacadeInterface) error {
 	templates, err := f.GetServiceTemplates(ctx)
 	if err != nil {
-		glog.Errorf("Could not write logstash configuration: %s", err)
+		plog.Errorf("Could not write logstash configuration: %s", err)
 		return err
 	}
 	if err := writeLogstashConfiguration(templates); err != nil {
-		glog.Errorf("Could not write logstash configuration: %s", err)
+		plog.Errorf("Could not write logstash configuration: %s", err)
 		return err
 	}
-	glog.V(2).Infof("Starting logstash container")
+	plog.Debugf("Starting logstash container")
 	if err := isvcs.Mgr.Notify("restart logstash"); err != nil {
-		glog.Errorf("Could not start logstash container: %s", err)
+		plog.Errorf("Could not start logstash container: %s", err)
 		return err
 	}
 	return nil
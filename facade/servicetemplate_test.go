@@ -23,13 +23,12 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
-	"github.com/zenoss/glog"
 	. "gopkg.in/check.v1"
 )
 
 func (ft *FacadeIntegrationTest) TestFacadeServiceTemplate(t *C) {
-	glog.V(0).Infof("TestFacadeServiceTemplate started")
-	defer glog.V(0).Infof("TestFacadeServiceTemplate finished")
+	plog.Infof("TestFacadeServiceTemplate started")
+	defer plog.Infof("TestFacadeServiceTemplate finished")
 
 	var (
 		templateId string
@@ -17,6 +17,7 @@ package facade
 
 import (
 	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/coordinator/storage"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/datastore/elastic"
 	dfsmocks "github.com/control-center/serviced/dfs/mocks"
@@ -27,6 +28,7 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/statehistory"
 	"github.com/control-center/serviced/domain/user"
 	zzkmocks "github.com/control-center/serviced/facade/mocks"
 	"github.com/stretchr/testify/mock"
@@ -60,6 +62,7 @@ func (ft *FacadeIntegrationTest) SetUpSuite(c *gocheck.C) {
 	ft.Mappings = append(ft.Mappings, serviceconfigfile.MAPPING)
 	ft.Mappings = append(ft.Mappings, user.MAPPING)
 	ft.Mappings = append(ft.Mappings, registry.MAPPING)
+	ft.Mappings = append(ft.Mappings, statehistory.MAPPING)
 
 	ft.ElasticTest.SetUpSuite(c)
 	datastore.Register(ft.Driver())
@@ -104,6 +107,7 @@ func (ft *FacadeIntegrationTest) setupMockZZK() {
 	ft.zzk.On("DeleteRegistryLibrary", mock.AnythingOfType("string")).Return(nil)
 	ft.zzk.On("LockServices", mock.AnythingOfType("[]service.Service")).Return(nil)
 	ft.zzk.On("UnlockServices", mock.AnythingOfType("[]service.Service")).Return(nil)
+	ft.zzk.On("GetDFSFreezeStatus").Return(&storage.FreezeStatus{}, nil)
 
 }
 
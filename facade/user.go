@@ -21,6 +21,7 @@ package facade
 import (
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
 	userdomain "github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/utils"
 
@@ -36,6 +37,10 @@ import (
 var SYSTEM_USER_NAME = "system_user"
 var INSTANCE_PASSWORD string
 
+// ErrTenantAccessDenied is returned when a user tries to operate on a service
+// outside of the tenants assigned to it.
+var ErrTenantAccessDenied = errors.New("facade: user does not have access to this tenant")
+
 //hashPassword returns the sha-1 of a password
 func hashPassword(password string) string {
 	h := sha1.New()
@@ -174,3 +179,241 @@ func (f *Facade) CreateSystemUser(ctx datastore.Context) error {
 	INSTANCE_PASSWORD = password
 	return f.UpdateUser(ctx, user)
 }
+
+// GetUserTenants returns the ids of the tenants the user is allowed to access.
+// An empty list means the user is not restricted to any tenant.
+func (f *Facade) GetUserTenants(ctx datastore.Context, userName string) ([]string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetUserTenants"))
+	user, err := f.GetUser(ctx, userName)
+	if datastore.IsErrNoSuchEntity(err) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if user.Tenants == nil {
+		return []string{}, nil
+	}
+	return user.Tenants, nil
+}
+
+// SetUserTenants restricts the user to the given tenants.  An empty list
+// removes the restriction.
+func (f *Facade) SetUserTenants(ctx datastore.Context, userName string, tenantIDs []string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SetUserTenants"))
+	logger := plog.WithFields(log.Fields{
+		"userName":  userName,
+		"tenantIDs": tenantIDs,
+	})
+
+	name := strings.TrimSpace(userName)
+	if name == "" {
+		return errors.New("empty User.Name not allowed")
+	}
+
+	// make sure all of the tenants exist
+	allTenants, err := f.getTenantIDs(ctx)
+	if err != nil {
+		return err
+	}
+	tenantMap := make(map[string]struct{})
+	for _, tenantID := range allTenants {
+		tenantMap[tenantID] = struct{}{}
+	}
+	for _, tenantID := range tenantIDs {
+		if _, ok := tenantMap[tenantID]; !ok {
+			return fmt.Errorf("tenant %s not found", tenantID)
+		}
+	}
+
+	user, err := f.GetUser(ctx, name)
+	if datastore.IsErrNoSuchEntity(err) {
+		// users that log in through the system do not have a record, so
+		// create one with a password that nobody knows.
+		password, err := utils.NewUUID36()
+		if err != nil {
+			return err
+		}
+		user = userdomain.User{
+			Name:     name,
+			Password: hashPassword(password),
+		}
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not look up user")
+		return err
+	}
+
+	user.Tenants = tenantIDs
+	if err := f.userStore.Put(ctx, userdomain.Key(name), &user); err != nil {
+		logger.WithError(err).Debug("Could not update user tenants")
+		return err
+	}
+	logger.Info("Updated user tenants")
+	return nil
+}
+
+// CheckTenantAccess returns ErrTenantAccessDenied if the user is not allowed
+// to operate on the given service.
+func (f *Facade) CheckTenantAccess(ctx datastore.Context, userName, serviceID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CheckTenantAccess"))
+	tenants, err := f.GetUserTenants(ctx, userName)
+	if err != nil {
+		return err
+	}
+	return f.checkServiceScope(ctx, userdomain.User{Name: userName, Tenants: tenants}, serviceID)
+}
+
+// CheckServiceAccess returns ErrTenantAccessDenied if the actor of the context
+// is not allowed to operate on the given service.
+func (f *Facade) CheckServiceAccess(ctx datastore.Context, serviceID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CheckServiceAccess"))
+	scope, err := f.tenantScope(ctx)
+	if err != nil {
+		return err
+	}
+	return f.checkServiceScope(ctx, scope, serviceID)
+}
+
+// FilterServiceDetailsByUser returns only the service details that belong to
+// tenants the user is allowed to access.
+func (f *Facade) FilterServiceDetailsByUser(ctx datastore.Context, userName string, details []service.ServiceDetails) ([]service.ServiceDetails, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("FilterServiceDetailsByUser"))
+	tenants, err := f.GetUserTenants(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+	return f.filterServiceDetails(ctx, userdomain.User{Name: userName, Tenants: tenants}, details)
+}
+
+// tenantScope returns the tenants that the actor of the context may access.
+// Requests that a service made with a rest token may only reach the tenant of
+// that service.  Requests without an actor are made by serviced itself and
+// may access every tenant.
+func (f *Facade) tenantScope(ctx datastore.Context) (userdomain.User, error) {
+	actor := datastore.GetActor(ctx)
+	if actor.Tenant != "" {
+		return userdomain.User{Tenants: []string{actor.Tenant}}, nil
+	} else if actor.User == "" {
+		return userdomain.User{}, nil
+	}
+	tenants, err := f.GetUserTenants(ctx, actor.User)
+	if err != nil {
+		return userdomain.User{}, err
+	}
+	return userdomain.User{Name: actor.User, Tenants: tenants}, nil
+}
+
+// checkServiceScope returns ErrTenantAccessDenied if the service is outside
+// of the tenants in scope.
+func (f *Facade) checkServiceScope(ctx datastore.Context, scope userdomain.User, serviceID string) error {
+	if len(scope.Tenants) == 0 {
+		return nil
+	}
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	return checkTenantScope(scope, serviceID, tenantID)
+}
+
+// checkTenantScope returns ErrTenantAccessDenied if the tenant is outside of
+// the tenants in scope.
+func checkTenantScope(scope userdomain.User, serviceID, tenantID string) error {
+	if !scope.CanAccessTenant(tenantID) {
+		plog.WithFields(log.Fields{
+			"userName":  scope.Name,
+			"serviceID": serviceID,
+			"tenantID":  tenantID,
+		}).Warn("User denied access to service")
+		return ErrTenantAccessDenied
+	}
+	return nil
+}
+
+// checkNewServiceScope returns ErrTenantAccessDenied if the actor of the
+// context may not add a service under the given parent.  Adding a service
+// without a parent creates a new tenant, which only unrestricted actors may
+// do.
+func (f *Facade) checkNewServiceScope(ctx datastore.Context, parentID string) error {
+	scope, err := f.tenantScope(ctx)
+	if err != nil {
+		return err
+	}
+	if parentID == "" && len(scope.Tenants) > 0 {
+		plog.WithField("userName", scope.Name).Warn("User denied adding a tenant")
+		return ErrTenantAccessDenied
+	} else if parentID == "" {
+		return nil
+	}
+	return f.checkServiceScope(ctx, scope, parentID)
+}
+
+// tenantFilter returns a function that reports whether the actor of the
+// context may see the service with the given id.
+func (f *Facade) tenantFilter(ctx datastore.Context) (func(serviceID string) (bool, error), error) {
+	scope, err := f.tenantScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(scope.Tenants) == 0 {
+		return func(string) (bool, error) { return true, nil }, nil
+	}
+	tenants := make(map[string]string)
+	return func(serviceID string) (bool, error) {
+		tenantID, ok := tenants[serviceID]
+		if !ok {
+			var err error
+			if tenantID, err = f.GetTenantID(ctx, serviceID); err != nil {
+				return false, err
+			}
+			tenants[serviceID] = tenantID
+		}
+		return scope.CanAccessTenant(tenantID), nil
+	}, nil
+}
+
+// filterServicesInScope returns only the services that the actor of the
+// context may see.
+func (f *Facade) filterServicesInScope(ctx datastore.Context, svcs []service.Service) ([]service.Service, error) {
+	inScope, err := f.tenantFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filtered := []service.Service{}
+	for _, svc := range svcs {
+		if ok, err := inScope(svc.ID); err != nil {
+			return nil, err
+		} else if ok {
+			filtered = append(filtered, svc)
+		}
+	}
+	return filtered, nil
+}
+
+// filterServiceDetailsInScope returns only the service details that the actor
+// of the context may see.
+func (f *Facade) filterServiceDetailsInScope(ctx datastore.Context, details []service.ServiceDetails) ([]service.ServiceDetails, error) {
+	scope, err := f.tenantScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return f.filterServiceDetails(ctx, scope, details)
+}
+
+// filterServiceDetails returns only the service details that belong to the
+// tenants in scope.
+func (f *Facade) filterServiceDetails(ctx datastore.Context, scope userdomain.User, details []service.ServiceDetails) ([]service.ServiceDetails, error) {
+	if len(scope.Tenants) == 0 {
+		return details, nil
+	}
+	filtered := []service.ServiceDetails{}
+	for _, detail := range details {
+		tenantID, err := f.GetTenantID(ctx, detail.ID)
+		if err != nil {
+			return nil, err
+		}
+		if scope.CanAccessTenant(tenantID) {
+			filtered = append(filtered, detail)
+		}
+	}
+	return filtered, nil
+}
@@ -16,6 +16,10 @@
 package facade
 
 import (
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/statehistory"
 	userdomain "github.com/control-center/serviced/domain/user"
 	. "gopkg.in/check.v1"
 )
@@ -95,3 +99,55 @@ func (ft *FacadeIntegrationTest) TestUser_ValidateCredentials(t *C) {
 		t.Fatalf("Failure authenticating credentials %s", err)
 	}
 }
+
+func (ft *FacadeIntegrationTest) TestUser_TenantAccess(c *C) {
+	for _, svc := range []service.Service{
+		{ID: "tenant-a", Name: "TenantA", DeploymentID: "deployment-id", PoolID: "pool-id", Launch: "auto", DesiredState: int(service.SVCStop)},
+		{ID: "tenant-a-child", ParentServiceID: "tenant-a", Name: "Child", DeploymentID: "deployment-id", PoolID: "pool-id", Launch: "auto", DesiredState: int(service.SVCStop)},
+		{ID: "tenant-b", Name: "TenantB", DeploymentID: "deployment-id", PoolID: "pool-id", Launch: "auto", DesiredState: int(service.SVCStop)},
+	} {
+		c.Assert(ft.Facade.AddService(ft.CTX, svc), IsNil)
+	}
+
+	// users without a record can access everything
+	tenants, err := ft.Facade.GetUserTenants(ft.CTX, "Pepe")
+	c.Assert(err, IsNil)
+	c.Assert(tenants, HasLen, 0)
+	c.Assert(ft.Facade.CheckTenantAccess(ft.CTX, "Pepe", "tenant-b"), IsNil)
+
+	// unknown tenants are rejected
+	c.Assert(ft.Facade.SetUserTenants(ft.CTX, "Pepe", []string{"tenant-c"}), NotNil)
+
+	c.Assert(ft.Facade.SetUserTenants(ft.CTX, "Pepe", []string{"tenant-a"}), IsNil)
+	tenants, err = ft.Facade.GetUserTenants(ft.CTX, "Pepe")
+	c.Assert(err, IsNil)
+	c.Assert(tenants, DeepEquals, []string{"tenant-a"})
+	c.Assert(ft.Facade.CheckTenantAccess(ft.CTX, "Pepe", "tenant-a-child"), IsNil)
+	c.Assert(ft.Facade.CheckTenantAccess(ft.CTX, "Pepe", "tenant-b"), Equals, ErrTenantAccessDenied)
+
+	details, err := ft.Facade.FilterServiceDetailsByUser(ft.CTX, "Pepe", []service.ServiceDetails{
+		{ID: "tenant-a"}, {ID: "tenant-a-child"}, {ID: "tenant-b"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(details, HasLen, 2)
+	c.Assert(details[0].ID, Equals, "tenant-a")
+	c.Assert(details[1].ID, Equals, "tenant-a-child")
+
+	// requests made on behalf of the user are limited to its tenants
+	userCtx := datastore.WithActor(ft.CTX, statehistory.SourceUI, "Pepe")
+	err = ft.Facade.AddService(userCtx, service.Service{ID: "tenant-b-child", ParentServiceID: "tenant-b", Name: "Child", DeploymentID: "deployment-id", PoolID: "pool-id", Launch: "auto", DesiredState: int(service.SVCStop)})
+	c.Assert(err, Equals, ErrTenantAccessDenied)
+	svcs, err := ft.Facade.GetServices(userCtx, dao.ServiceRequest{})
+	c.Assert(err, IsNil)
+	c.Assert(svcs, HasLen, 2)
+
+	// the generated record must not allow logging in with an empty password
+	valid, err := ft.Facade.ValidateCredentials(ft.CTX, userdomain.User{Name: "Pepe"})
+	c.Assert(err, IsNil)
+	c.Assert(valid, Equals, false)
+
+	// clearing the tenants removes the restriction
+	c.Assert(ft.Facade.SetUserTenants(ft.CTX, "Pepe", []string{}), IsNil)
+	c.Assert(ft.Facade.CheckTenantAccess(ft.CTX, "Pepe", "tenant-b"), IsNil)
+	c.Assert(ft.Facade.RemoveUser(ft.CTX, "Pepe"), IsNil)
+}
@@ -0,0 +1,75 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/domain/statehistory"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupScopedConfigFile(ctx datastore.Context) {
+	file := serviceconfigfile.SvcConfigFile{
+		ID:              "file",
+		ServiceTenantID: "tenant",
+		ServicePath:     "/tenant",
+		ConfFile:        servicedefinition.ConfigFile{Filename: "/etc/app.conf", Content: "a\n"},
+	}
+	ft.configStore.On("Get", ctx, serviceconfigfile.Key("file"), mock.AnythingOfType("*serviceconfigfile.SvcConfigFile")).Return(nil).Run(
+		func(args mock.Arguments) {
+			*args.Get(2).(*serviceconfigfile.SvcConfigFile) = file
+		})
+}
+
+func (ft *FacadeUnitTest) Test_UpdateServiceConfig_OtherTenantDenied(c *C) {
+	ctx := datastore.WithTenantActor(ft.ctx, statehistory.SourceAPI, "othertenant")
+	ft.setupScopedConfigFile(ctx)
+
+	err := ft.Facade.UpdateServiceConfig(ctx, "file", servicedefinition.ConfigFile{Filename: "/etc/app.conf", Content: "b\n"})
+	c.Assert(err, Equals, facade.ErrTenantAccessDenied)
+	ft.configStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_UpdateServiceConfig_SameTenantAllowed(c *C) {
+	ctx := datastore.WithTenantActor(ft.ctx, statehistory.SourceAPI, "tenant")
+	ft.setupScopedConfigFile(ctx)
+	ft.configStore.On("Put", ctx, serviceconfigfile.Key("file"), mock.AnythingOfType("*serviceconfigfile.SvcConfigFile")).Return(nil)
+
+	err := ft.Facade.UpdateServiceConfig(ctx, "file", servicedefinition.ConfigFile{Filename: "/etc/app.conf", Content: "b\n"})
+	c.Assert(err, IsNil)
+}
+
+func (ft *FacadeUnitTest) Test_DeleteServiceConfig_OtherTenantDenied(c *C) {
+	ctx := datastore.WithTenantActor(ft.ctx, statehistory.SourceAPI, "othertenant")
+	ft.setupScopedConfigFile(ctx)
+
+	err := ft.Facade.DeleteServiceConfig(ctx, "file")
+	c.Assert(err, Equals, facade.ErrTenantAccessDenied)
+	ft.configStore.AssertNotCalled(c, "Delete", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_AddService_NewTenantDenied(c *C) {
+	ctx := datastore.WithTenantActor(ft.ctx, statehistory.SourceAPI, "tenant")
+
+	err := ft.Facade.AddService(ctx, service.Service{ID: "newtenant", Name: "New Tenant", PoolID: "default"})
+	c.Assert(err, Equals, facade.ErrTenantAccessDenied)
+	ft.serviceStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
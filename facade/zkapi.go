@@ -21,6 +21,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/coordinator/storage"
 	"github.com/control-center/serviced/datastore"
 	zkimgregistry "github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/host"
@@ -29,10 +30,11 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/zzk"
 	zkd "github.com/control-center/serviced/zzk/docker"
+	"github.com/control-center/serviced/zzk/ensemble"
+	"github.com/control-center/serviced/zzk/ha"
 	zkr "github.com/control-center/serviced/zzk/registry"
 	zks "github.com/control-center/serviced/zzk/service"
 	zkvirtualip "github.com/control-center/serviced/zzk/virtualips"
-	"github.com/zenoss/glog"
 )
 
 func getZZK() ZZK {
@@ -189,6 +191,26 @@ func (zk *zkf) RemoveTenantExports(tenantID string) error {
 	return nil
 }
 
+// GetTenantExports returns the exported endpoints for a given tenant that are
+// registered in zookeeper, keyed by application.
+func (zk *zkf) GetTenantExports(tenantID string) (map[string][]zkr.ExportDetails, error) {
+	logger := plog.WithField("tenantid", tenantID)
+
+	// get the root-based connection to look up the exports
+	rootconn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		logger.WithError(err).Debug("Could not acquire a root-based connection to look up the tenant's exported endpoints in zookeeper")
+		return nil, err
+	}
+
+	exports, err := zkr.GetExports(rootconn, tenantID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up the tenant's exported endpoints in zookeeper")
+		return nil, err
+	}
+	return exports, nil
+}
+
 // WaitService waits for all instances of a service to achieve a uniform state
 // by monitoring zookeeper.
 func (zk *zkf) WaitService(svc *service.Service, state service.DesiredState, cancel <-chan interface{}) error {
@@ -209,6 +231,9 @@ func (zk *zkf) WaitService(svc *service.Service, state service.DesiredState, can
 	var checkCount func(int) bool
 	var checkState func(*zks.State, bool) bool
 
+	// pauseErr is set if an instance reports that it could not be paused
+	var pauseErr error
+
 	// set up the check calls
 	switch state {
 	case service.SVCStop:
@@ -232,7 +257,10 @@ func (zk *zkf) WaitService(svc *service.Service, state service.DesiredState, can
 		checkState = func(s *zks.State, exists bool) bool {
 			if exists {
 				if s.DesiredState != service.SVCRun {
-					return s.Paused || s.Terminated.After(s.Started)
+					if !s.Paused && s.PauseError != "" && pauseErr == nil {
+						pauseErr = fmt.Errorf("could not pause instance %d of service %s: %s", s.InstanceID, svc.Name, s.PauseError)
+					}
+					return s.Paused || s.PauseError != "" || s.Terminated.After(s.Started)
 				} else {
 					return false
 				}
@@ -257,7 +285,7 @@ func (zk *zkf) WaitService(svc *service.Service, state service.DesiredState, can
 			logger.WithError(err).Debug("Could not monitor the service's states in zookeeper")
 			return err
 		}
-		return err
+		return pauseErr
 	case <-cancel:
 		close(stop)
 		return <-errC
@@ -341,11 +369,11 @@ func (z *zkf) RemoveHost(host *host.Host) error {
 	}
 	locker, err := zks.ServiceLock(conn)
 	if err != nil {
-		glog.Errorf("Could not initialize service lock: %s", err)
+		plog.Errorf("Could not initialize service lock: %s", err)
 		return err
 	}
 	if err := locker.Lock(); err != nil {
-		glog.Errorf("Could not disable service scheduling for pool %s: %s", host.PoolID, err)
+		plog.Errorf("Could not disable service scheduling for pool %s: %s", host.PoolID, err)
 		return err
 	}
 	defer locker.Unlock()
@@ -357,6 +385,17 @@ func (z *zkf) RemoveHost(host *host.Host) error {
 	return zks.RemoveHost(cancel, conn, "", host.ID)
 }
 
+func (z *zkf) DrainHost(host *host.Host, timeout time.Duration) error {
+	conn, err := zzk.GetLocalConnection(zzk.GeneratePoolPath(host.PoolID))
+	if err != nil {
+		return err
+	}
+	cancel := make(chan struct{})
+	timer := time.AfterFunc(timeout, func() { close(cancel) })
+	defer timer.Stop()
+	return zks.DrainHost(cancel, conn, "", host.ID)
+}
+
 func (z *zkf) GetActiveHosts(poolID string, hosts *[]string) error {
 	conn, err := zzk.GetLocalConnection("/")
 	if err != nil {
@@ -374,6 +413,120 @@ func (z *zkf) IsHostActive(poolID string, hostID string) (bool, error) {
 	return zks.IsHostOnline(conn, poolID, hostID)
 }
 
+func (z *zkf) IsHostRestarting(poolID, hostID string) (bool, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return false, err
+	}
+	return zks.IsHostRestarting(conn, poolID, hostID)
+}
+
+func (z *zkf) GetHostDFSHealth(poolID, hostID string) (*host.DFSHealth, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return zks.GetDFSHealth(conn, poolID, hostID)
+}
+
+// GetHostInstanceUsage returns the resource usage last reported by a host for
+// the instances it runs
+func (z *zkf) GetHostInstanceUsage(poolID, hostID string) (map[string]service.InstanceUsage, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return zks.GetInstanceUsage(conn, poolID, hostID)
+}
+
+func (z *zkf) GetHostStorageHealth(poolID, hostID string) (*host.StorageHealth, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return zks.GetStorageHealth(conn, poolID, hostID)
+}
+
+func (z *zkf) GetHostEvents(poolID, hostID string) ([]host.Event, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return zks.GetHostEvents(conn, poolID, hostID)
+}
+
+// SetMaintenanceWindows replaces the maintenance windows that the hosts of a
+// resource pool consult before restarting or rescheduling instances
+func (z *zkf) SetMaintenanceWindows(poolID string, windows []zks.MaintenanceWindow) error {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return err
+	}
+	return zks.SetMaintenanceWindows(conn, poolID, windows)
+}
+
+func (z *zkf) GetMasterHAStatus() (*ha.ClusterStatus, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return ha.GetClusterStatus(conn)
+}
+
+func (z *zkf) GetZKEnsembleStatus() (*ensemble.Status, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return ensemble.GetStatus(conn)
+}
+
+func (z *zkf) AddZKEnsembleServer(server ensemble.Server, force bool) (*ensemble.Status, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return ensemble.AddServer(conn, server, force)
+}
+
+func (z *zkf) RemoveZKEnsembleServer(id string, force bool) (*ensemble.Status, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return ensemble.RemoveServer(conn, id, force)
+}
+
+func (z *zkf) GetDFSFreezeStatus() (*storage.FreezeStatus, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return storage.GetFreezeStatus(conn)
+}
+
+func (z *zkf) SetDFSFreezeStatus(status storage.FreezeStatus) error {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return err
+	}
+	return storage.SetFreezeStatus(conn, status)
+}
+
+// zkDatastoreMigrationLock is the lock that masters hold while migrating the
+// datastore
+const zkDatastoreMigrationLock = "/locks/dbmigration"
+
+// NewDatastoreMigrationLock returns the lock that keeps masters from migrating
+// the datastore at the same time
+func (z *zkf) NewDatastoreMigrationLock() (client.Lock, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return conn.NewLock(zkDatastoreMigrationLock)
+}
+
 func (z *zkf) UpdateResourcePool(pool *pool.ResourcePool) error {
 	conn, err := zzk.GetLocalConnection("/")
 	if err != nil {
@@ -481,18 +634,31 @@ func (z *zkf) UnlockServices(svcs []service.Service) error {
 func (zk *zkf) GetServiceStates(poolID, serviceID string) ([]zks.State, error) {
 	conn, err := zzk.GetLocalConnection("/")
 	if err != nil {
-		glog.Errorf("Could not get connection to zookeeper: %s", err)
+		plog.Errorf("Could not get connection to zookeeper: %s", err)
 		return nil, err
 	}
 
 	return zks.GetServiceStates(conn, poolID, serviceID)
 }
 
+// WaitServiceStateChange blocks until an instance of any of the services
+// (mapped to their pool ids) changes, and returns the ids of the services
+// that changed.
+func (zk *zkf) WaitServiceStateChange(services map[string]string, cancel <-chan struct{}) ([]string, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		plog.Errorf("Could not get connection to zookeeper: %s", err)
+		return nil, err
+	}
+
+	return zks.WaitStateChange(cancel, conn, services)
+}
+
 // GetHostStates returns all running instances for a host
 func (zk *zkf) GetHostStates(poolID, hostID string) ([]zks.State, error) {
 	conn, err := zzk.GetLocalConnection("/")
 	if err != nil {
-		glog.Errorf("Could not get connection to zookeeper: %s", err)
+		plog.Errorf("Could not get connection to zookeeper: %s", err)
 		return nil, err
 	}
 
@@ -674,6 +840,16 @@ func (zk *zkf) StopServiceInstances(ctx datastore.Context, poolID, serviceID str
 
 // SendDockerAction submits an action to the docker queue
 func (zk *zkf) SendDockerAction(poolID, serviceID string, instanceID int, command string, args []string) error {
+	return zk.sendAction(poolID, serviceID, instanceID, append([]string{command}, args...), "")
+}
+
+// SendDiagnosticAction submits a built-in diagnostic action to the docker
+// queue, so that the host that runs it saves its output
+func (zk *zkf) SendDiagnosticAction(poolID, serviceID string, instanceID int, action, command string) error {
+	return zk.sendAction(poolID, serviceID, instanceID, []string{command}, action)
+}
+
+func (zk *zkf) sendAction(poolID, serviceID string, instanceID int, command []string, diagnostic string) error {
 	logger := plog.WithFields(log.Fields{
 		"poolid":     poolID,
 		"serviceid":  serviceID,
@@ -698,9 +874,10 @@ func (zk *zkf) SendDockerAction(poolID, serviceID string, instanceID int, comman
 
 	// set up the action
 	req := zkd.Action{
-		HostID:   hostID,
-		DockerID: fmt.Sprintf("%s-%d", serviceID, instanceID),
-		Command:  append([]string{command}, args...),
+		HostID:     hostID,
+		DockerID:   fmt.Sprintf("%s-%d", serviceID, instanceID),
+		Command:    command,
+		Diagnostic: diagnostic,
 	}
 
 	// send the action
@@ -742,6 +919,17 @@ func (ck *zkf) GetServiceNodes() ([]zks.ServiceNode, error) {
 	return zks.GetServiceNodes(conn)
 }
 
+func (ck *zkf) GetPoolScheduling() ([]zks.PoolScheduling, error) {
+	// get the root-based connection to look up the pools
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		plog.WithError(err).Debug("Could not acquire root-based connection")
+		return nil, err
+	}
+
+	return zks.GetPoolScheduling(conn)
+}
+
 func (zk *zkf) SyncServiceRegistry(ctx datastore.Context, tenantID string, svc *service.Service) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start(fmt.Sprintf("zk.SyncServiceRegistry")))
 	logger := plog.WithFields(log.Fields{
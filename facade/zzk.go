@@ -14,11 +14,18 @@
 package facade
 
 import (
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/coordinator/storage"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/registry"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/zzk/ensemble"
+	"github.com/control-center/serviced/zzk/ha"
+	zkr "github.com/control-center/serviced/zzk/registry"
 	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
@@ -28,14 +35,22 @@ type ZZK interface {
 	RemoveService(poolID, serviceID string) error
 	RemoveServiceEndpoints(serviceID string) error
 	RemoveTenantExports(tenantID string) error
+	GetTenantExports(tenantID string) (map[string][]zkr.ExportDetails, error)
 	WaitService(svc *service.Service, state service.DesiredState, cancel <-chan interface{}) error
 	GetPublicPort(portAddress string) (string, string, error)
 	GetVHost(subdomain string) (string, string, error)
 	AddHost(_host *host.Host) error
 	UpdateHost(_host *host.Host) error
 	RemoveHost(_host *host.Host) error
+	DrainHost(_host *host.Host, timeout time.Duration) error
 	GetActiveHosts(poolID string, hosts *[]string) error
 	IsHostActive(poolID string, hostId string) (bool, error)
+	IsHostRestarting(poolID, hostID string) (bool, error)
+	GetHostDFSHealth(poolID, hostID string) (*host.DFSHealth, error)
+	GetHostInstanceUsage(poolID, hostID string) (map[string]service.InstanceUsage, error)
+	GetHostStorageHealth(poolID, hostID string) (*host.StorageHealth, error)
+	GetHostEvents(poolID, hostID string) ([]host.Event, error)
+	SetMaintenanceWindows(poolID string, windows []zkservice.MaintenanceWindow) error
 	UpdateResourcePool(_pool *pool.ResourcePool) error
 	RemoveResourcePool(poolID string) error
 	AddVirtualIP(vip *pool.VirtualIP) error
@@ -48,11 +63,21 @@ type ZZK interface {
 	LockServices(svcs []service.Service) error
 	UnlockServices(svcs []service.Service) error
 	GetServiceStates(poolID, serviceID string) ([]zkservice.State, error)
+	WaitServiceStateChange(services map[string]string, cancel <-chan struct{}) ([]string, error)
 	GetHostStates(poolID, hostID string) ([]zkservice.State, error)
 	GetServiceState(poolID, serviceID string, instanceID int) (*zkservice.State, error)
 	StopServiceInstance(poolID, serviceID string, instanceID int) error
 	StopServiceInstances(ctx datastore.Context, poolID, serviceID string) error
 	SendDockerAction(poolID, serviceID string, instanceID int, command string, args []string) error
+	SendDiagnosticAction(poolID, serviceID string, instanceID int, action, command string) error
 	GetServiceStateIDs(poolID, serviceID string) ([]zkservice.StateRequest, error)
 	GetServiceNodes() ([]zkservice.ServiceNode, error)
+	GetPoolScheduling() ([]zkservice.PoolScheduling, error)
+	GetMasterHAStatus() (*ha.ClusterStatus, error)
+	GetZKEnsembleStatus() (*ensemble.Status, error)
+	AddZKEnsembleServer(server ensemble.Server, force bool) (*ensemble.Status, error)
+	RemoveZKEnsembleServer(id string, force bool) (*ensemble.Status, error)
+	GetDFSFreezeStatus() (*storage.FreezeStatus, error)
+	SetDFSFreezeStatus(status storage.FreezeStatus) error
+	NewDatastoreMigrationLock() (client.Lock, error)
 }
@@ -98,24 +98,27 @@ type HealthStatus struct {
 
 // HealthCheck is the health check object.
 type HealthCheck struct {
-	Script    string
-	Timeout   time.Duration
-	Interval  time.Duration
-	Tolerance int
+	Script           string
+	Timeout          time.Duration
+	Interval         time.Duration
+	Tolerance        int
+	FailureThreshold int // Consecutive failures before the check is reported as failing
 }
 
 // MarshalJSON implements json.Marshaller
 func (hc HealthCheck) MarshalJSON() ([]byte, error) {
 	jhc := struct {
-		Script    string
-		Timeout   float64
-		Interval  float64
-		Tolerance int
+		Script           string
+		Timeout          float64
+		Interval         float64
+		Tolerance        int
+		FailureThreshold int
 	}{
-		Script:    hc.Script,
-		Timeout:   hc.Timeout.Seconds(),
-		Interval:  hc.Interval.Seconds(),
-		Tolerance: hc.Tolerance,
+		Script:           hc.Script,
+		Timeout:          hc.Timeout.Seconds(),
+		Interval:         hc.Interval.Seconds(),
+		Tolerance:        hc.Tolerance,
+		FailureThreshold: hc.FailureThreshold,
 	}
 	return json.Marshal(jhc)
 }
@@ -123,19 +126,21 @@ func (hc HealthCheck) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON implements json.Unmarshaller
 func (hc *HealthCheck) UnmarshalJSON(data []byte) error {
 	jhc := struct {
-		Script    string
-		Timeout   float64
-		Interval  float64
-		Tolerance int
+		Script           string
+		Timeout          float64
+		Interval         float64
+		Tolerance        int
+		FailureThreshold int
 	}{}
 	if err := json.Unmarshal(data, &jhc); err != nil {
 		return err
 	}
 	*hc = HealthCheck{
-		Script:    jhc.Script,
-		Timeout:   time.Duration(jhc.Timeout) * time.Second,
-		Interval:  time.Duration(jhc.Interval) * time.Second,
-		Tolerance: jhc.Tolerance,
+		Script:           jhc.Script,
+		Timeout:          time.Duration(jhc.Timeout) * time.Second,
+		Interval:         time.Duration(jhc.Interval) * time.Second,
+		Tolerance:        jhc.Tolerance,
+		FailureThreshold: jhc.FailureThreshold,
 	}
 	return nil
 }
@@ -202,15 +207,28 @@ func (hc *HealthCheck) Run() (stat HealthStatus) {
 	return
 }
 
-// Ping performs the health check on the specified interval.
+// Ping performs the health check on the specified interval.  Failures are not
+// reported until they reach the failure threshold; until then, the last
+// reported status is reported again.
 func (hc *HealthCheck) Ping(cancel <-chan struct{}, report func(HealthStatus)) {
 	timer := time.NewTimer(0)
 	defer timer.Stop()
+	var last *HealthStatus
+	failures := 0
 	for {
 		select {
 		case <-timer.C:
 			stat := hc.Run()
 			timer.Reset(hc.Interval)
+			if stat.Status == OK {
+				failures = 0
+			} else if failures++; failures < hc.FailureThreshold {
+				if last != nil {
+					report(*last)
+				}
+				continue
+			}
+			last = &stat
 			report(stat)
 		case <-cancel:
 			return
@@ -254,3 +254,27 @@ func (s *HealthCheckTestSuite) TestPing(c *C) {
 		interval++
 	})
 }
+
+func (s *HealthCheckTestSuite) TestPing_FailureThreshold(c *C) {
+	// Verify failures are not reported until they reach the threshold
+	check := HealthCheck{
+		Script:           "exit 1",
+		Timeout:          time.Second,
+		Interval:         100 * time.Millisecond,
+		FailureThreshold: 3,
+	}
+	cancel := make(chan struct{})
+	startTime := time.Now()
+	reported := false
+	check.Ping(cancel, func(stat HealthStatus) {
+		if reported {
+			c.Errorf("Ping reported after it was cancelled")
+			return
+		}
+		reported = true
+		close(cancel)
+		c.Check(stat.Status, Equals, Status(Failed))
+		c.Check(time.Since(startTime) >= 2*check.Interval, Equals, true)
+	})
+	c.Check(reported, Equals, true)
+}
@@ -197,6 +197,7 @@ func NewIService(sd IServiceDefinition) (*IService, error) {
 
 	envPerService[sd.Name] = make(map[string]string)
 	envPerService[sd.Name]["CONTROLPLANE_SERVICE_ID"] = svc.ID
+	registerResources(sd)
 
 	if sd.PreStart != nil {
 		if err := sd.PreStart(&svc); err != nil {
@@ -261,6 +262,15 @@ func (svc *IService) getResourcePath(p string) string {
 	return filepath.Join(svc.root, svc.Name, p)
 }
 
+// getVolumePath returns the path on the host for the named volume, honoring
+// any path configured in the service's resources.
+func (svc *IService) getVolumePath(volume string, res Resources) string {
+	if p, ok := res.Volumes[volume]; ok {
+		return p
+	}
+	return svc.getResourcePath(volume)
+}
+
 func (svc *IService) setExitedChannel(newChan <-chan int) {
 	svc.channelLock.Lock()
 	defer svc.channelLock.Unlock()
@@ -285,6 +295,11 @@ func (svc *IService) create() (*docker.Container, error) {
 		},
 	}
 
+	// apply configured resource limits
+	res := GetResources(svc.Name)
+	hostConfig.CPUShares = res.CPUShares
+	hostConfig.Memory = res.Memory
+
 	log := log.WithFields(logrus.Fields{
 		"isvc":    svc.name(),
 		"logtype": hostConfig.LogConfig.Type,
@@ -351,7 +366,7 @@ func (svc *IService) create() (*docker.Container, error) {
 	// service-specific volumes
 	if svc.Volumes != nil && len(svc.Volumes) > 0 {
 		for src, dest := range svc.Volumes {
-			hostpath := svc.getResourcePath(src)
+			hostpath := svc.getVolumePath(src, res)
 			log := log.WithFields(logrus.Fields{
 				"hostpath":      hostpath,
 				"containerpath": dest,
@@ -384,7 +399,14 @@ func (svc *IService) create() (*docker.Container, error) {
 	}
 
 	// attach environment variables
+	env := make(map[string]string)
 	for key, val := range envPerService[svc.Name] {
+		env[key] = val
+	}
+	if opt, ok := heapOptions[svc.Name]; ok && res.Heap != "" {
+		env[opt.env] = fmt.Sprintf(opt.format, res.Heap)
+	}
+	for key, val := range env {
 		config.Env = append(config.Env, fmt.Sprintf("%s=%s", key, val))
 	}
 
@@ -404,7 +426,7 @@ func (svc *IService) attach() (*docker.Container, error) {
 		if !ctr.IsRunning() {
 			log.Warn("Internal service container found but not running. Removing.")
 			go svc.remove(notify)
-		} else if !svc.checkVolumes(ctr) {
+		} else if !svc.checkVolumes(ctr) || !svc.checkResources(ctr) {
 			// CC-1550: A reload causes CC to re-read its configuration, which means that the host volumes
 			//          mounted into the isvcs containers might change. If that happens, we cannot simply
 			//          attach to the existing containers. Instead, we need to stop them and create new ones
@@ -684,6 +706,41 @@ func (svc *IService) isFlapping() bool {
 	return svc.restartCount >= FLAPPING_THRESHOLD
 }
 
+// checkResources returns false if the container was created with resource
+// settings that differ from the service's configured resources.
+func (svc *IService) checkResources(ctr *docker.Container) bool {
+	dctr, err := ctr.Inspect()
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"containerid": ctr.ID,
+		}).WithError(err).Error("Unable to inspect container")
+		return false
+	}
+
+	log := log.WithFields(logrus.Fields{
+		"isvc":        svc.Name,
+		"containerid": ctr.ID,
+	})
+
+	res := GetResources(svc.Name)
+	if dctr.HostConfig != nil && (dctr.HostConfig.CPUShares != res.CPUShares || dctr.HostConfig.Memory != res.Memory) {
+		log.Debug("Container resource limits have changed")
+		return false
+	}
+
+	if opt, ok := heapOptions[svc.Name]; ok && res.Heap != "" && dctr.Config != nil {
+		expected := fmt.Sprintf("%s=%s", opt.env, fmt.Sprintf(opt.format, res.Heap))
+		for _, env := range dctr.Config.Env {
+			if env == expected {
+				return true
+			}
+		}
+		log.Debug("Container heap size has changed")
+		return false
+	}
+	return true
+}
+
 func (svc *IService) checkVolumes(ctr *docker.Container) bool {
 	dctr, err := ctr.Inspect()
 	if err != nil {
@@ -694,6 +751,7 @@ func (svc *IService) checkVolumes(ctr *docker.Container) bool {
 	}
 
 	if svc.Volumes != nil {
+		res := GetResources(svc.Name)
 		for src, dest := range svc.Volumes {
 			var mount *dockerclient.Mount
 			if mount = findContainerMount(dctr, dest); mount == nil {
@@ -705,7 +763,7 @@ func (svc *IService) checkVolumes(ctr *docker.Container) bool {
 				return false
 			}
 
-			expectedSrc, _ := filepath.EvalSymlinks(svc.getResourcePath(src))
+			expectedSrc, _ := filepath.EvalSymlinks(svc.getVolumePath(src, res))
 			if rel, _ := filepath.Rel(filepath.Clean(expectedSrc), mount.Source); rel != "." {
 				log.WithFields(logrus.Fields{
 					"isvc":        svc.Name,
@@ -1055,27 +1113,26 @@ func (svc *IService) stats(halt <-chan struct{}) {
 				log.WithError(err).Warn("Unable to serialize stats to JSON")
 				break
 			}
-			url := "http://127.0.0.1:4242/api/put"
-			req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
+			req, err := http.NewRequest("POST", statsURL, bytes.NewBuffer(data))
 			if err != nil {
 				log.WithFields(logrus.Fields{
-					"url": url,
+					"url": statsAddress,
 				}).WithError(err).Warn("Unable to create stats request")
 				break
 			}
-			resp, err := http.DefaultClient.Do(req)
+			resp, err := statsClient.Do(req)
 			if err != nil {
 				// FIXME: This should be a warning, but it happens alot at startup, so let's keep it
 				// on the DL until we have some better startup coordination and/or logging mechanisms.
 				log.WithFields(logrus.Fields{
-					"url": url,
+					"url": statsAddress,
 				}).WithError(err).Debug("Unable to POST container stats")
 				break
 			}
 			defer resp.Body.Close()
 			if strings.Contains(resp.Status, "204 No Content") == false {
 				log.WithFields(logrus.Fields{
-					"url":    url,
+					"url":    statsAddress,
 					"status": resp.Status,
 				}).WithError(err).Debug("POST of container stats failed")
 				break
@@ -0,0 +1,229 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isvcs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/zzk/ensemble"
+)
+
+// DefaultExternalCheckInterval is how often external endpoints are checked
+const DefaultExternalCheckInterval = 30 * time.Second
+
+// ExternalService is an internal service that is provided by an endpoint
+// outside of serviced.  If Replace is set, the internal service container
+// with the same name is not started.
+type ExternalService struct {
+	Name     string
+	Address  string        // address of the endpoint, without credentials
+	Replace  bool          // do not run the internal service container
+	Check    func() error  // returns an error if the endpoint is unhealthy
+	Interval time.Duration // how often the endpoint is checked
+
+	lock   sync.RWMutex
+	status domain.HealthCheckStatus
+}
+
+var (
+	externalLock     sync.RWMutex
+	externalServices = make(map[string]*ExternalService)
+)
+
+// AddExternal registers an external endpoint.  An endpoint that replaces an
+// internal service must be named after an existing internal service.
+func AddExternal(svc *ExternalService) error {
+	if _, ok := envPerService[svc.Name]; svc.Replace && !ok {
+		svcNames := []string{}
+		for key := range envPerService {
+			svcNames = append(svcNames, key)
+		}
+		sort.Strings(svcNames)
+		return fmt.Errorf("Error setting external internal service: Service '%s' must be one of [%s]", svc.Name, strings.Join(svcNames, ", "))
+	}
+	if svc.Interval <= 0 {
+		svc.Interval = DefaultExternalCheckInterval
+	}
+	svc.status = domain.HealthCheckStatus{
+		Name:     DEFAULT_HEALTHCHECK_NAME,
+		Status:   "unknown",
+		Interval: svc.Interval.Seconds(),
+	}
+
+	externalLock.Lock()
+	defer externalLock.Unlock()
+	externalServices[svc.Name] = svc
+	return nil
+}
+
+// IsExternal returns true if the internal service is replaced by an external
+// endpoint
+func IsExternal(name string) bool {
+	svc := getExternal(name)
+	return svc != nil && svc.Replace
+}
+
+func getExternal(name string) *ExternalService {
+	externalLock.RLock()
+	defer externalLock.RUnlock()
+	return externalServices[name]
+}
+
+func getExternalNames() []string {
+	externalLock.RLock()
+	defer externalLock.RUnlock()
+	names := []string{}
+	for name := range externalServices {
+		names = append(names, name)
+	}
+	return names
+}
+
+// WaitForExternal waits for every external endpoint to pass its check and
+// returns an error if any endpoint is unhealthy when the timeout expires.
+func WaitForExternal(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for _, name := range getExternalNames() {
+		svc := getExternal(name)
+		for {
+			err := svc.Check()
+			svc.setHealthStatus(err, time.Now().Unix())
+			if err == nil {
+				break
+			} else if time.Now().After(deadline) {
+				return fmt.Errorf("external %s at %s is unreachable: %s", svc.Name, svc.Address, err)
+			}
+			log.WithFields(logrus.Fields{
+				"isvc":    svc.Name,
+				"address": svc.Address,
+			}).WithError(err).Debug("Waiting for external endpoint")
+			time.Sleep(time.Second)
+		}
+	}
+	return nil
+}
+
+// startExternalMonitors periodically checks the health of every external
+// endpoint
+func startExternalMonitors() {
+	for _, name := range getExternalNames() {
+		go getExternal(name).monitor()
+	}
+}
+
+func (svc *ExternalService) monitor() {
+	log := log.WithFields(logrus.Fields{
+		"isvc":    svc.Name,
+		"address": svc.Address,
+	})
+	for currentTime := range time.Tick(svc.Interval) {
+		err := svc.Check()
+		svc.setHealthStatus(err, currentTime.Unix())
+		if err != nil {
+			log.WithError(err).Warn("External endpoint health check failed")
+		}
+	}
+}
+
+func (svc *ExternalService) setHealthStatus(result error, currentTime int64) {
+	svc.lock.Lock()
+	defer svc.lock.Unlock()
+	if result == nil {
+		if svc.status.Status != "passed" {
+			svc.status.StartedAt = currentTime
+		}
+		svc.status.Status = "passed"
+		svc.status.Failure = ""
+	} else {
+		svc.status.Status = "failed"
+		svc.status.Failure = result.Error()
+	}
+	svc.status.Timestamp = currentTime
+}
+
+func (svc *ExternalService) healthResult() IServiceHealthResult {
+	svc.lock.RLock()
+	defer svc.lock.RUnlock()
+	return IServiceHealthResult{
+		ServiceName:    svc.Name,
+		ContainerName:  "external:" + svc.Address,
+		HealthStatuses: []domain.HealthCheckStatus{svc.status},
+	}
+}
+
+// HTTPCheck returns a check that expects a successful response from a GET
+// of the url
+func HTTPCheck(client *http.Client, url string) func() error {
+	return func() error {
+		resp, err := client.Get(url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		ioutil.ReadAll(resp.Body)
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("http status: %s", resp.Status)
+		}
+		return nil
+	}
+}
+
+// ZKCheck returns a check that expects at least one server of the ZooKeeper
+// ensemble to answer "imok" to "ruok"
+func ZKCheck(servers []string, timeout time.Duration) func() error {
+	return func() error {
+		var err error
+		for _, server := range servers {
+			if err = zkRUOK(server, timeout); err == nil {
+				return nil
+			}
+		}
+		return err
+	}
+}
+
+func zkRUOK(server string, timeout time.Duration) error {
+	reply, err := ensemble.FourLetterWord(server, "ruok", timeout)
+	if err != nil {
+		return err
+	} else if string(reply) != "imok" {
+		return fmt.Errorf("zookeeper %s is not ok: %q", server, reply)
+	}
+	return nil
+}
+
+var (
+	statsURL     = "http://127.0.0.1:4242/api/put"
+	statsAddress = statsURL // statsURL without credentials, for logging
+	statsClient  = http.DefaultClient
+)
+
+// SetStatsEndpoint sends the stats of the internal services to the
+// OpenTSDB put api at the url instead of the opentsdb internal service.
+func SetStatsEndpoint(u *url.URL, client *http.Client) {
+	statsURL = u.String()
+	redacted := *u
+	redacted.User = nil
+	statsAddress = redacted.String()
+	statsClient = client
+}
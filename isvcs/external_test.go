@@ -0,0 +1,116 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package isvcs
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExternalHealthStatus(t *testing.T) {
+	defer delete(externalServices, "zookeeper")
+
+	if err := AddExternal(&ExternalService{Name: "unknown", Replace: true}); err == nil {
+		t.Errorf("Expected error replacing an unknown internal service")
+	}
+
+	checkErr := errors.New("connection refused")
+	svc := &ExternalService{
+		Name:    "zookeeper",
+		Address: "zk1:2181",
+		Replace: true,
+		Check:   func() error { return checkErr },
+	}
+	if err := AddExternal(svc); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !IsExternal("zookeeper") {
+		t.Errorf("Expected zookeeper to be external")
+	}
+	if err := WaitForExternal(0); err == nil {
+		t.Errorf("Expected error waiting for an unreachable endpoint")
+	}
+
+	m := &Manager{services: make(map[string]*IService)}
+	result, err := m.GetHealthStatus("zookeeper")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if result.ContainerName != "external:zk1:2181" {
+		t.Errorf("Unexpected container name %s", result.ContainerName)
+	}
+	if status := result.HealthStatuses[0]; status.Status != "failed" || status.Failure != checkErr.Error() {
+		t.Errorf("Expected failed health status, got %+v", status)
+	}
+
+	checkErr = nil
+	if err := WaitForExternal(time.Second); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	result, _ = m.GetHealthStatus("zookeeper")
+	if status := result.HealthStatuses[0]; status.Status != "passed" {
+		t.Errorf("Expected passed health status, got %+v", status)
+	}
+}
+
+func TestHTTPCheck(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	check := HTTPCheck(http.DefaultClient, server.URL)
+	if err := check(); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	status = http.StatusUnauthorized
+	if err := check(); err == nil {
+		t.Errorf("Expected error for unauthorized response")
+	}
+}
+
+func TestZKCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			buf := make([]byte, 4)
+			if _, err := conn.Read(buf); err == nil && string(buf) == "ruok" {
+				conn.Write([]byte("imok"))
+			}
+			conn.Close()
+		}
+	}()
+
+	if err := ZKCheck([]string{"127.0.0.1:1", listener.Addr().String()}, time.Second)(); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if err := ZKCheck([]string{"127.0.0.1:1"}, time.Second)(); err == nil {
+		t.Errorf("Expected error for unreachable ensemble")
+	}
+}
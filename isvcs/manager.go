@@ -24,6 +24,7 @@ import (
 	"os"
 	"path"
 	"sort"
+	"strings"
 	"sync"
 )
 
@@ -129,10 +130,54 @@ func (m *Manager) GetServiceNames() []string {
 	for name := range m.services {
 		names = append(names, name)
 	}
+	names = append(names, getExternalNames()...)
 	sort.Strings(names)
 	return names
 }
 
+// ResolveServiceNames returns the internal services matching the name.  A
+// name matches an internal service with the same name or, if there is none,
+// every internal service named with it as a prefix (e.g. elasticsearch).
+func (m *Manager) ResolveServiceNames(name string) ([]string, error) {
+	if _, ok := m.services[name]; ok {
+		return []string{name}, nil
+	}
+	var names []string
+	for _, svcName := range m.GetServiceNames() {
+		if _, ok := m.services[svcName]; ok && strings.HasPrefix(svcName, name+"-") {
+			names = append(names, svcName)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("could not find isvc %q", name)
+	}
+	return names, nil
+}
+
+// RestartService restarts the internal services matching the name, applying
+// the resource settings that are passed in over their current resources.
+// Only the matching internal services are affected.
+func (m *Manager) RestartService(name string, resources []string) ([]string, error) {
+	names, err := m.ResolveServiceNames(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, svcName := range names {
+		if err := MergeResources(svcName, resources); err != nil {
+			return nil, err
+		}
+	}
+	for _, svcName := range names {
+		log.WithFields(logrus.Fields{
+			"isvc": svcName,
+		}).Info("Restarting internal service with revised resources")
+		if err := m.services[svcName].Restart(); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
 func (m *Manager) GetHealthStatus(name string) (IServiceHealthResult, error) {
 	result := IServiceHealthResult{
 		ServiceName:    name,
@@ -141,6 +186,10 @@ func (m *Manager) GetHealthStatus(name string) (IServiceHealthResult, error) {
 		HealthStatuses: make([]domain.HealthCheckStatus, 0),
 	}
 
+	if ext := getExternal(name); ext != nil {
+		return ext.healthResult(), nil
+	}
+
 	svc, found := m.services[name]
 	if !found {
 		log.WithFields(logrus.Fields{
@@ -405,6 +454,14 @@ func (m *Manager) makeRequest(op managerOp) error {
 
 // Register() registers a container to be managed by the *Manager
 func (m *Manager) Register(svc *IService) error {
+	if IsExternal(svc.Name) {
+		log.WithFields(logrus.Fields{
+			"isvc":    svc.Name,
+			"address": getExternal(svc.Name).Address,
+		}).Info("Using external endpoint instead of internal service container")
+		return nil
+	}
+
 	svc.dockerLogDriver = m.dockerLogDriver
 	svc.dockerLogConfig = m.dockerLogConfig
 
@@ -0,0 +1,193 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isvcs
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/control-center/serviced/utils"
+)
+
+// Resources are the container resource settings of an internal service that
+// may be tuned through configuration.
+type Resources struct {
+	Heap      string            // maximum jvm heap size (e.g. 4g)
+	CPUShares int64             // relative cpu weight of the container
+	Memory    int64             // memory limit of the container in bytes
+	Volumes   map[string]string // maps volume name to a path on the host
+}
+
+// heapOptions describes how the heap size is passed to each internal service
+// that runs a jvm.
+var heapOptions = map[string]struct{ env, format string }{
+	"elasticsearch-serviced": {"ES_JAVA_OPTS", "-Xmx%s"},
+	"elasticsearch-logstash": {"ES_JAVA_OPTS", "-Xmx%s"},
+}
+
+var (
+	resourcesLock       sync.RWMutex
+	resourcesPerService = make(map[string]*Resources)
+	volumesPerService   = make(map[string]map[string]string)
+)
+
+var resourceRegexp = regexp.MustCompile("([^:]+):([^=]+)=(.+)")
+
+// registerResources makes the resources of the internal service configurable
+func registerResources(sd IServiceDefinition) {
+	resourcesLock.Lock()
+	defer resourcesLock.Unlock()
+	resourcesPerService[sd.Name] = &Resources{}
+	volumesPerService[sd.Name] = sd.Volumes
+}
+
+// AddResource parses a string of the form SERVICE:KEY=VAL and sets the
+// resource on the internal service.  Valid keys are heap, cpu_shares, memory,
+// and volume.<name>.
+func AddResource(resStr string) error {
+	resourcesLock.Lock()
+	defer resourcesLock.Unlock()
+
+	service, key, val, err := parseResource(resStr)
+	if err != nil {
+		return err
+	}
+	return resourcesPerService[service].set(service, key, val)
+}
+
+// MergeResources validates the resource strings and applies the settings that
+// apply to the named internal service over its current resources.  Settings
+// for other internal services are validated but otherwise ignored, and
+// settings that are not given keep their current value.
+func MergeResources(name string, resStrs []string) error {
+	resourcesLock.Lock()
+	defer resourcesLock.Unlock()
+
+	current, ok := resourcesPerService[name]
+	if !ok {
+		return fmt.Errorf("Error setting internal service resources: Service '%s' must be one of [%s]", name, strings.Join(resourceServiceNames(), ", "))
+	}
+
+	res := *current
+	res.Volumes = nil
+	for k, v := range current.Volumes {
+		if res.Volumes == nil {
+			res.Volumes = make(map[string]string)
+		}
+		res.Volumes[k] = v
+	}
+	for _, resStr := range resStrs {
+		service, key, val, err := parseResource(resStr)
+		if err != nil {
+			return err
+		}
+		target := &res
+		if service != name {
+			target = &Resources{}
+		}
+		if err := target.set(service, key, val); err != nil {
+			return err
+		}
+	}
+	resourcesPerService[name] = &res
+	return nil
+}
+
+// GetResources returns a copy of the resources of the internal service
+func GetResources(name string) Resources {
+	resourcesLock.RLock()
+	defer resourcesLock.RUnlock()
+
+	res := Resources{}
+	if r, ok := resourcesPerService[name]; ok {
+		res = *r
+		res.Volumes = make(map[string]string)
+		for k, v := range r.Volumes {
+			res.Volumes[k] = v
+		}
+	}
+	return res
+}
+
+// parseResource splits the resource string and verifies the service exists;
+// the caller must hold the lock.
+func parseResource(resStr string) (service, key, val string, err error) {
+	result := resourceRegexp.FindStringSubmatch(resStr)
+	if result == nil {
+		return "", "", "", fmt.Errorf("Error parsing internal service resource: %s", resStr)
+	}
+	service, key, val = result[1], strings.TrimSpace(result[2]), strings.TrimSpace(result[3])
+	if _, ok := resourcesPerService[service]; !ok {
+		return "", "", "", fmt.Errorf("Error setting internal service resource:'%s'  Service '%s' must be one of [%s]",
+			resStr, service, strings.Join(resourceServiceNames(), ", "))
+	}
+	return service, key, val, nil
+}
+
+// set validates and applies a single resource setting
+func (r *Resources) set(service, key, val string) error {
+	switch {
+	case key == "heap":
+		if _, ok := heapOptions[service]; !ok {
+			return fmt.Errorf("Internal service '%s' does not support setting the heap size", service)
+		}
+		if _, err := utils.ParseEngineeringNotation(val); err != nil {
+			return fmt.Errorf("Invalid heap size '%s' for internal service '%s'", val, service)
+		}
+		r.Heap = val
+	case key == "cpu_shares":
+		shares, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || shares < 0 {
+			return fmt.Errorf("Invalid cpu shares '%s' for internal service '%s'", val, service)
+		}
+		r.CPUShares = shares
+	case key == "memory":
+		memory, err := utils.ParseEngineeringNotation(val)
+		if err != nil {
+			return fmt.Errorf("Invalid memory limit '%s' for internal service '%s'", val, service)
+		}
+		r.Memory = int64(memory)
+	case strings.HasPrefix(key, "volume."):
+		volume := strings.TrimPrefix(key, "volume.")
+		if _, ok := volumesPerService[service][volume]; !ok {
+			return fmt.Errorf("Internal service '%s' has no volume named '%s'", service, volume)
+		}
+		if !filepath.IsAbs(val) {
+			return fmt.Errorf("Volume path '%s' for internal service '%s' must be absolute", val, service)
+		}
+		if r.Volumes == nil {
+			r.Volumes = make(map[string]string)
+		}
+		r.Volumes[volume] = filepath.Clean(val)
+	default:
+		return fmt.Errorf("Unknown resource '%s' for internal service '%s'", key, service)
+	}
+	return nil
+}
+
+// resourceServiceNames returns the names of the internal services with
+// configurable resources; the caller must hold the lock.
+func resourceServiceNames() []string {
+	names := []string{}
+	for name := range resourcesPerService {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
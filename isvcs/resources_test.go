@@ -0,0 +1,99 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package isvcs
+
+import (
+	"testing"
+)
+
+func TestAddResource(t *testing.T) {
+	registerResources(IServiceDefinition{Name: "elasticsearch-serviced", Volumes: map[string]string{"data": "/opt/data"}})
+	registerResources(IServiceDefinition{Name: "zookeeper", Volumes: map[string]string{"data": "/var/zookeeper"}})
+
+	for _, res := range []string{
+		"elasticsearch-serviced:heap=2g",
+		"elasticsearch-serviced:cpu_shares=512",
+		"elasticsearch-serviced:memory=4G",
+		"elasticsearch-serviced:volume.data=/mnt/es/",
+	} {
+		if err := AddResource(res); err != nil {
+			t.Fatalf("Unexpected error adding resource %s: %s", res, err)
+		}
+	}
+	res := GetResources("elasticsearch-serviced")
+	if res.Heap != "2g" {
+		t.Errorf("Expected heap 2g, got %s", res.Heap)
+	}
+	if res.CPUShares != 512 {
+		t.Errorf("Expected cpu shares 512, got %d", res.CPUShares)
+	}
+	if res.Memory != 4<<30 {
+		t.Errorf("Expected memory %d, got %d", 4<<30, res.Memory)
+	}
+	if res.Volumes["data"] != "/mnt/es" {
+		t.Errorf("Expected volume path /mnt/es, got %s", res.Volumes["data"])
+	}
+
+	for _, res := range []string{
+		"elasticsearch-serviced",
+		"unknown:memory=1g",
+		"zookeeper:heap=1g",
+		"zookeeper:cpu_shares=-1",
+		"zookeeper:memory=lots",
+		"zookeeper:volume.logs=/var/log",
+		"zookeeper:volume.data=relative/path",
+		"zookeeper:swap=1g",
+	} {
+		if err := AddResource(res); err == nil {
+			t.Errorf("Expected error adding resource %s", res)
+		}
+	}
+}
+
+func TestMergeResources(t *testing.T) {
+	registerResources(IServiceDefinition{Name: "elasticsearch-serviced", Volumes: map[string]string{"data": "/opt/data"}})
+	registerResources(IServiceDefinition{Name: "zookeeper", Volumes: map[string]string{"data": "/var/zookeeper"}})
+
+	if err := AddResource("zookeeper:memory=1g"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := AddResource("elasticsearch-serviced:heap=2g"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// invalid settings leave the resources untouched
+	if err := MergeResources("elasticsearch-serviced", []string{"elasticsearch-serviced:cpu_shares=256", "zookeeper:heap=1g"}); err == nil {
+		t.Errorf("Expected error merging resources")
+	}
+	if res := GetResources("elasticsearch-serviced"); res.Heap != "2g" || res.CPUShares != 0 {
+		t.Errorf("Expected only heap 2g, got %+v", res)
+	}
+
+	// settings that are not given keep their current value
+	if err := MergeResources("elasticsearch-serviced", []string{"zookeeper:memory=2g", "elasticsearch-serviced:cpu_shares=256"}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if res := GetResources("elasticsearch-serviced"); res.Heap != "2g" || res.CPUShares != 256 {
+		t.Errorf("Expected heap 2g and cpu shares 256, got %+v", res)
+	}
+	if res := GetResources("zookeeper"); res.Memory != 1<<30 {
+		t.Errorf("Expected zookeeper resources to be untouched, got %+v", res)
+	}
+
+	if err := MergeResources("unknown", nil); err == nil {
+		t.Errorf("Expected error merging resources of an unknown service")
+	}
+}
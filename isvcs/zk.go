@@ -15,9 +15,8 @@ package isvcs
 
 import (
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/zzk/ensemble"
 
-	"io/ioutil"
-	"net"
 	"time"
 )
 
@@ -92,7 +91,7 @@ func zkHealthCheck(halt <-chan struct{}) error {
 		default:
 			// Try ruok.
 			times++
-			ruok, err := zkFourLetterWord("127.0.0.1:2181", "ruok", time.Second*10)
+			ruok, err := ensemble.FourLetterWord("127.0.0.1:2181", "ruok", time.Second*10)
 			if err != nil {
 				log.WithError(err).Debug("No response to ruok from ZooKeeper")
 				healthy = false
@@ -112,7 +111,7 @@ func zkHealthCheck(halt <-chan struct{}) error {
 			}
 
 			// Since ruok works, try stat next.
-			stat, err := zkFourLetterWord("127.0.0.1:2181", "stat", time.Second*10)
+			stat, err := ensemble.FourLetterWord("127.0.0.1:2181", "stat", time.Second*10)
 			if err != nil {
 				log.WithError(err).Debug("No response to stat from ZooKeeper")
 				healthy = false
@@ -138,29 +137,3 @@ func zkHealthCheck(halt <-chan struct{}) error {
 	}
 
 }
-
-// transcribed from upstream samuel/go-zookeeper
-func zkFourLetterWord(server, command string, timeout time.Duration) ([]byte, error) {
-	conn, err := net.DialTimeout("tcp", server, timeout)
-	if err != nil {
-		return nil, err
-	}
-
-	defer conn.Close()
-
-	conn.SetWriteDeadline(time.Now().Add(timeout))
-
-	_, err = conn.Write([]byte(command))
-	if err != nil {
-		return nil, err
-	}
-
-	conn.SetReadDeadline(time.Now().Add(timeout))
-
-	resp, err := ioutil.ReadAll(conn)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
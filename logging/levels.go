@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/zenoss/logri"
+)
+
+// ErrNoComponent is returned when a level is set without naming a component
+var ErrNoComponent = errors.New("no component specified")
+
+var (
+	levelsLock sync.Mutex
+	levels     = make(map[string]logrus.Level)
+)
+
+// SetComponentLevel overrides the log level of a component and the
+// components beneath it.  Components are named by package, relative to
+// serviced and separated by dots (e.g. zzk or zzk.service).
+func SetComponentLevel(component, level string) error {
+	if component == "" {
+		return ErrNoComponent
+	}
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	levelsLock.Lock()
+	defer levelsLock.Unlock()
+	levels[component] = lvl
+	return applyLevels()
+}
+
+// ResetComponentLevel removes the level override of a component, so that it
+// logs at the level of its parent again.
+func ResetComponentLevel(component string) error {
+	if component == "" {
+		return ErrNoComponent
+	}
+	levelsLock.Lock()
+	defer levelsLock.Unlock()
+	delete(levels, component)
+	return applyLevels()
+}
+
+// ComponentLevels returns the level overrides by component
+func ComponentLevels() map[string]string {
+	levelsLock.Lock()
+	defer levelsLock.Unlock()
+	result := make(map[string]string)
+	for component, level := range levels {
+		result[component] = level.String()
+	}
+	return result
+}
+
+// applyLevels configures the logger tree with the level overrides.  The root
+// logger is left out so that it keeps the level set by the verbosity.
+func applyLevels() error {
+	cfg := logri.LogriConfig{}
+	for component, level := range levels {
+		cfg = append(cfg, logri.LoggerConfig{Logger: component, Level: level.String()})
+	}
+	sort.Sort(cfg)
+	return logri.ApplyConfig(cfg)
+}
@@ -4,10 +4,17 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/zenoss/logri"
 )
 
 func init() {
+	// Root the logger tree at a logger whose output and format can be changed
+	// after the package loggers have been created.
+	base := logrus.New()
+	base.Out = output
+	base.Formatter = formatter
+	logri.RootLogger = logri.NewLoggerFromLogrus(base)
 	logri.AddHook(ContextHook{})
 }
 
@@ -0,0 +1,152 @@
+// +build unit
+
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/zenoss/logri"
+)
+
+func TestComponentLevels(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(os.Stderr)
+	logri.SetLevel(logrus.InfoLevel)
+
+	parent := logri.GetLogger("testing")
+	child := logri.GetLogger("testing.child")
+	if err := SetComponentLevel("testing", "debug"); err != nil {
+		t.Fatalf("could not set level: %s", err)
+	}
+	defer ResetComponentLevel("testing")
+	child.Debug("child debug message")
+	if !strings.Contains(buf.String(), "child debug message") {
+		t.Errorf("expected the child to log debug messages")
+	}
+	if err := SetComponentLevel("testing.child", "warn"); err != nil {
+		t.Fatalf("could not set level: %s", err)
+	}
+	buf.Reset()
+	child.Info("child info message")
+	parent.Debug("parent debug message")
+	if strings.Contains(buf.String(), "child info message") || !strings.Contains(buf.String(), "parent debug message") {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	levels := ComponentLevels()
+	if len(levels) != 2 || levels["testing"] != "debug" || levels["testing.child"] != "warning" {
+		t.Errorf("unexpected levels %v", levels)
+	}
+
+	if err := ResetComponentLevel("testing.child"); err != nil {
+		t.Fatalf("could not reset level: %s", err)
+	}
+	if level := child.GetEffectiveLevel(); level != logrus.DebugLevel {
+		t.Errorf("expected the child to inherit debug, got %s", level)
+	}
+	if err := SetComponentLevel("testing", "loud"); err == nil {
+		t.Errorf("expected an error for an invalid level")
+	}
+	if err := SetComponentLevel("", "debug"); err != ErrNoComponent {
+		t.Errorf("expected ErrNoComponent, got %v", err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(os.Stderr)
+	if err := SetFormat("json"); err != nil {
+		t.Fatalf("could not set format: %s", err)
+	}
+	defer SetFormat("text")
+
+	logri.GetLogger("testing.format").WithField("key", "value").Warn("json message")
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("could not decode %q: %s", buf.String(), err)
+	}
+	if entry["msg"] != "json message" || entry["key"] != "value" || entry["logger"] != "testing.format" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if err := SetFormat("xml"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logging-test-")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "serviced.log")
+
+	f, err := NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("could not open log file: %s", err)
+	}
+	defer f.Close()
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("could not write: %s", err)
+		}
+	}
+	for suffix, expected := range map[string]string{"": "fourth\n", ".1": "third\n", ".2": "second\n"} {
+		data, err := ioutil.ReadFile(path + suffix)
+		if err != nil {
+			t.Errorf("could not read %s: %s", path+suffix, err)
+		} else if string(data) != expected {
+			t.Errorf("expected %s to contain %q, got %q", path+suffix, expected, string(data))
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups")
+	}
+}
+
+func TestLogstashHook(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %s", err)
+	}
+	defer l.Close()
+
+	hook := &logstashHook{logType: "serviced-test", address: l.Addr().String(), messages: make(chan string, 100)}
+	defer close(hook.messages)
+	go hook.run()
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	logger.Hooks.Add(hook)
+	logger.Info("shipped message")
+
+	l.(*net.TCPListener).SetDeadline(time.Now().Add(5 * time.Second))
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatalf("logstash was not contacted: %s", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("could not read message: %s", err)
+	}
+	var msg logstashMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		t.Fatalf("could not decode message %q: %s", line, err)
+	}
+	if msg.Type != "serviced-test" || !strings.Contains(msg.Message, "shipped message") {
+		t.Errorf("unexpected message %+v", msg)
+	}
+}
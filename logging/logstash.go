@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/zenoss/logri"
+)
+
+var logstashOnce sync.Once
+
+// SetLogstash ships all log messages to the logstash tcp input at address,
+// tagged with the type.  Messages are dropped while logstash is unreachable.
+// Only the first call has an effect.
+func SetLogstash(logType, address string) {
+	if logType == "" || address == "" {
+		return
+	}
+	logstashOnce.Do(func() {
+		hook := &logstashHook{
+			logType:  logType,
+			address:  address,
+			messages: make(chan string, 100),
+		}
+		go hook.run()
+		logri.AddHook(hook)
+	})
+}
+
+type logstashMessage struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// logstashHook queues log messages for logstash
+type logstashHook struct {
+	logType  string
+	address  string
+	messages chan string
+}
+
+// Levels satisfies the logrus.Hook interface. This hook applies to all levels.
+func (hook *logstashHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire satisfies the logrus.Hook interface.  Messages are dropped rather than
+// blocking the caller if the queue is full.
+func (hook *logstashHook) Fire(entry *logrus.Entry) error {
+	msg, err := entry.String()
+	if err != nil {
+		return err
+	}
+	select {
+	case hook.messages <- strings.TrimSpace(msg):
+	default:
+	}
+	return nil
+}
+
+// run sends the queued messages to logstash, reconnecting at most once a
+// second.
+func (hook *logstashHook) run() {
+	var conn net.Conn
+	var lastDial time.Time
+	for msg := range hook.messages {
+		if conn == nil {
+			if time.Since(lastDial) < time.Second {
+				continue
+			}
+			lastDial = time.Now()
+			var err error
+			if conn, err = net.DialTimeout("tcp", hook.address, time.Second); err != nil {
+				conn = nil
+				continue
+			}
+		}
+		packet, err := json.Marshal(logstashMessage{Type: hook.logType, Message: msg})
+		if err != nil {
+			continue
+		}
+		if _, err := fmt.Fprintln(conn, string(packet)); err != nil {
+			conn.Close()
+			conn = nil
+		}
+	}
+}
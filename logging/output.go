@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+)
+
+var (
+	output    = &switchWriter{w: os.Stderr}
+	formatter = &switchFormatter{f: &logrus.TextFormatter{}}
+)
+
+// SetOutput sends the output of all loggers to w
+func SetOutput(w io.Writer) {
+	output.set(w)
+}
+
+// SetFormat sets the format of all log messages to text or json.  The
+// default format is text.
+func SetFormat(format string) error {
+	switch format {
+	case "", "text":
+		formatter.set(&logrus.TextFormatter{})
+	case "json":
+		formatter.set(&logrus.JSONFormatter{})
+	default:
+		return fmt.Errorf("unknown log format %s", format)
+	}
+	return nil
+}
+
+// switchWriter is a writer whose destination can be changed
+type switchWriter struct {
+	mu sync.RWMutex
+	w  io.Writer
+}
+
+func (s *switchWriter) set(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.w = w
+}
+
+func (s *switchWriter) Write(p []byte) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.w.Write(p)
+}
+
+// switchFormatter is a formatter whose format can be changed
+type switchFormatter struct {
+	mu sync.RWMutex
+	f  logrus.Formatter
+}
+
+func (s *switchFormatter) set(f logrus.Formatter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.f = f
+}
+
+func (s *switchFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.f.Format(entry)
+}
+
+// RotatingFile is a log file that is rotated when it grows past a maximum
+// size.  Rotated files are renamed with a numeric suffix, the most recent
+// being .1, and only the given number of them are kept.
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewRotatingFile opens a log file for appending that is rotated once it is
+// larger than maxSize bytes
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+// Write implements io.Writer
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the backups of the log file and starts a new one
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	if r.maxBackups > 0 {
+		for i := r.maxBackups - 1; i > 0; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// Close closes the log file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
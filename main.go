@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/cli/api/fake"
 	"github.com/control-center/serviced/cli/cmd"
 	"github.com/control-center/serviced/servicedversion"
 	"github.com/control-center/serviced/utils"
@@ -48,7 +49,14 @@ func main() {
 		fmt.Fprintf(os.Stderr, "WARNING: could not read default configs: %s\n", err)
 	}
 
-	cmd.New(api.New(), config).Run(os.Args)
+	// SERVICED_MOCK=1 runs the command line against in-memory sample data
+	// instead of a master
+	driver := api.New()
+	if os.Getenv("SERVICED_MOCK") == "1" {
+		driver = fake.New()
+	}
+
+	cmd.New(driver, config).Run(os.Args)
 }
 
 func getConfigs(args []string) (*utils.EnvironConfigReader, error) {
@@ -18,8 +18,6 @@ import (
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/zenoss/glog"
 )
 
 var (
@@ -140,7 +138,7 @@ func convertMemoryUsage(data *PerformanceData) []MemoryUsageStats {
 
 func (c *Client) GetHostMemoryStats(startDate time.Time, hostID string) (*MemoryUsageStats, error) {
 	getter := func() ([]MemoryUsageStats, error) {
-		glog.V(2).Infof("Requesting memory stats for host %s", hostID)
+		log.Debugf("Requesting memory stats for host %s", hostID)
 		options := PerformanceOptions{
 			Start:     startDate.Format(timeFormat),
 			End:       "now",
@@ -159,7 +157,7 @@ func (c *Client) GetHostMemoryStats(startDate time.Time, hostID string) (*Memory
 
 		result, err := c.performanceQuery(options)
 		if err != nil {
-			glog.Errorf("Could not get performance data for host %s: %s", hostID, err)
+			log.Errorf("Could not get performance data for host %s: %s", hostID, err)
 			return nil, err
 		}
 
@@ -180,7 +178,7 @@ func (c *Client) GetHostMemoryStats(startDate time.Time, hostID string) (*Memory
 
 func (c *Client) GetServiceMemoryStats(startDate time.Time, serviceID string) (*MemoryUsageStats, error) {
 	getter := func() ([]MemoryUsageStats, error) {
-		glog.V(2).Infof("Requesting memory stats for service %s", serviceID)
+		log.Debugf("Requesting memory stats for service %s", serviceID)
 		options := PerformanceOptions{
 			Start:     startDate.Format(timeFormat),
 			End:       "now",
@@ -199,7 +197,7 @@ func (c *Client) GetServiceMemoryStats(startDate time.Time, serviceID string) (*
 
 		result, err := c.performanceQuery(options)
 		if err != nil {
-			glog.V(2).Infof("Could not get performance data for service %s: %s", serviceID, err)
+			log.Debugf("Could not get performance data for service %s: %s", serviceID, err)
 			return nil, err
 		}
 
@@ -219,7 +217,7 @@ func (c *Client) GetServiceMemoryStats(startDate time.Time, serviceID string) (*
 }
 
 func (c *Client) GetInstanceMemoryStats(startDate time.Time, instances ...ServiceInstance) ([]MemoryUsageStats, error) {
-	glog.V(2).Infof("Requesting memory stats for %d instances", len(instances))
+	log.Debugf("Requesting memory stats for %d instances", len(instances))
 	secsAgo := time.Now().Sub(startDate).Seconds()
 	options := V2PerformanceOptions{
 		Start: fmt.Sprintf("%ds-ago", int(secsAgo)),
@@ -263,7 +261,7 @@ func (c *Client) GetInstanceMemoryStats(startDate time.Time, instances ...Servic
 
 			result, err := c.v2performanceQuery(options)
 			if err != nil {
-				glog.V(2).Infof("Could not get performance data for instances %+v: %s", instances, err)
+				log.Debugf("Could not get performance data for instances %+v: %s", instances, err)
 				return nil, err
 			}
 			perfDataMap[agg] = result
@@ -276,7 +274,7 @@ func (c *Client) GetInstanceMemoryStats(startDate time.Time, instances ...Servic
 		options.Start= "10m-ago" //Reduce the time frame to search for last value to reduce memory usage in CentralQuery
 		result, err := c.v2performanceQuery(options)
 		if err != nil {
-			glog.V(2).Infof("Could not get performance data for instances %+v: %s", instances, err)
+			log.Debugf("Could not get performance data for instances %+v: %s", instances, err)
 			return nil, err
 		}
 		perfDataMap["last"] = result
@@ -2,11 +2,13 @@ package metrics
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/logging"
+	"github.com/control-center/serviced/tracing"
 	gometrics "github.com/rcrowley/go-metrics"
 	"github.com/zenoss/logri"
 )
@@ -29,6 +31,11 @@ type Metrics struct {
 	Registry  gometrics.Registry
 	Timers    map[string]gometrics.Timer
 	GroupName string
+
+	traced bool
+	rooted bool            // outermost timers are children of parent
+	parent *tracing.Span   // nil if the parent trace is not sampled
+	spans  []*tracing.Span // spans of the timers that are running, innermost last
 }
 
 func NewMetrics() *Metrics {
@@ -38,35 +45,104 @@ func NewMetrics() *Metrics {
 	}
 }
 
+// NewTracedMetrics returns metrics that also record each timer as a trace
+// span.  The outermost timer starts a new trace and the timers started while
+// it is running are recorded as its children.  Because spans are nested in
+// the order the timers start, traced metrics should only be used for a single
+// request.
+func NewTracedMetrics() *Metrics {
+	m := NewMetrics()
+	m.traced = true
+	return m
+}
+
+// NewChildTracedMetrics returns traced metrics whose outermost timers are
+// recorded as children of the parent span instead of starting traces of their
+// own.  If the parent is nil, its trace was not sampled and the timers are not
+// recorded.
+func NewChildTracedMetrics(parent *tracing.Span) *Metrics {
+	m := NewTracedMetrics()
+	m.rooted = true
+	m.parent = parent
+	return m
+}
+
 type MetricTimer struct {
 	Name  string
 	Timer gometrics.Timer
 	Time  time.Time
+	Span  *tracing.Span
+	depth int
 }
 
 // Returns a new timing object.  This will be used as an
 // argument to Stop() to record the duration/count.
 func (m *Metrics) Start(name string) *MetricTimer {
-	if !m.Enabled {
+	if !m.Enabled && !m.traced {
 		return nil
 	}
 	m.Lock()
 	defer m.Unlock()
 
-	timer, found := m.Timers[name]
-	if !found {
-		timer = gometrics.NewTimer()
-		m.Timers[name] = timer
-		m.Registry.Register(name, timer)
+	result := &MetricTimer{Name: name, Time: time.Now()}
+	if m.Enabled {
+		timer, found := m.Timers[name]
+		if !found {
+			timer = gometrics.NewTimer()
+			m.Timers[name] = timer
+			m.Registry.Register(name, timer)
+		}
+		result.Timer = timer
+	}
+	if m.traced {
+		// an unsampled trace is kept on the stack as a nil span so that its
+		// inner timers are not sampled as traces of their own.
+		if len(m.spans) == 0 && m.rooted {
+			result.Span = m.parent.Child(spanComponent(name), name)
+		} else if len(m.spans) == 0 {
+			result.Span = tracing.StartSpan(spanComponent(name), name)
+		} else {
+			result.Span = m.spans[len(m.spans)-1].Child(spanComponent(name), name)
+		}
+		m.spans = append(m.spans, result.Span)
+		result.depth = len(m.spans)
 	}
-	return &MetricTimer{Name: name, Timer: timer, Time: time.Now()}
+	return result
 }
 
 // When stop is called, calculate the duration.
 func (m *Metrics) Stop(timer *MetricTimer) {
-	if timer != nil {
+	if timer == nil {
+		return
+	}
+	if timer.Timer != nil {
 		timer.Timer.UpdateSince(timer.Time)
 	}
+	if timer.depth > 0 {
+		timer.Span.Finish()
+		m.Lock()
+		if len(m.spans) >= timer.depth {
+			m.spans = m.spans[:timer.depth-1]
+		}
+		m.Unlock()
+	}
+}
+
+// spanComponent returns the component that does the work of a timer, which
+// is named by the prefix of the timer's name.
+func spanComponent(name string) string {
+	i := strings.Index(name, ".")
+	if i < 0 {
+		return "facade"
+	}
+	switch prefix := name[:i]; prefix {
+	case "zk", "zkr", "zks", "zzk":
+		return "zookeeper"
+	case "storeImpl", "datastore":
+		return "elasticsearch"
+	default:
+		return prefix
+	}
 }
 
 // Pads the value with units to a given width.
@@ -17,6 +17,8 @@ package metrics
 
 import (
 	"testing"
+
+	"github.com/control-center/serviced/tracing"
 )
 
 var rr bool
@@ -60,3 +62,85 @@ func functionWithLogging(m *Metrics) bool {
 func functionWithoutLogging(_ *Metrics) bool {
 	return true
 }
+
+type spanRecorder struct {
+	spans []*tracing.Span
+}
+
+func (r *spanRecorder) Export(spans []*tracing.Span) error {
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+func TestTracedMetrics(t *testing.T) {
+	r := &spanRecorder{}
+	tracer := tracing.NewTracer(1, r)
+	tracing.SetTracer(tracer)
+	defer tracing.SetTracer(nil)
+	shutdown := make(chan interface{})
+	done := make(chan struct{})
+	go func() {
+		tracer.Run(shutdown)
+		close(done)
+	}()
+
+	m := NewTracedMetrics()
+	outer := m.Start("GetService")
+	m.Stop(m.Start("storeImpl.Get"))
+	m.Stop(m.Start("zzk.UpdateService"))
+	m.Stop(outer)
+	m.Stop(m.Start("GetHost"))
+	close(shutdown)
+	<-done
+
+	if len(r.spans) != 4 {
+		t.Fatalf("expected 4 spans, got %d", len(r.spans))
+	}
+	store, zk, service, host := r.spans[0], r.spans[1], r.spans[2], r.spans[3]
+	if service.ParentID != "" || store.ParentID != service.ID || zk.ParentID != service.ID {
+		t.Errorf("spans are not nested in GetService")
+	}
+	if host.ParentID != "" || host.TraceID == service.TraceID {
+		t.Errorf("expected GetHost to start a new trace")
+	}
+	if service.Component != "facade" || store.Component != "elasticsearch" || zk.Component != "zookeeper" {
+		t.Errorf("unexpected components %s, %s, %s", service.Component, store.Component, zk.Component)
+	}
+	if len(m.Timers) != 0 {
+		t.Errorf("expected no timers while metrics are disabled")
+	}
+}
+
+func TestChildTracedMetrics(t *testing.T) {
+	r := &spanRecorder{}
+	tracer := tracing.NewTracer(1, r)
+	tracing.SetTracer(tracer)
+	defer tracing.SetTracer(nil)
+	shutdown := make(chan interface{})
+	done := make(chan struct{})
+	go func() {
+		tracer.Run(shutdown)
+		close(done)
+	}()
+
+	call := tracing.StartSpan("rpc", "ControlCenter.GetServices")
+	m := NewChildTracedMetrics(call)
+	m.Stop(m.Start("GetServices"))
+	m.Stop(m.Start("GetHost"))
+	call.Finish()
+
+	// the outermost timers of an unsampled call are not sampled on their own
+	unsampled := NewChildTracedMetrics(nil)
+	unsampled.Stop(unsampled.Start("GetServices"))
+	close(shutdown)
+	<-done
+
+	if len(r.spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(r.spans))
+	}
+	for _, span := range r.spans[:2] {
+		if span.TraceID != call.TraceID || span.ParentID != call.ID {
+			t.Errorf("expected %s to be a child of the rpc call", span.Name)
+		}
+	}
+}
@@ -31,8 +31,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/zenoss/glog"
-
 	"github.com/control-center/serviced/commons/docker"
 	"github.com/control-center/serviced/commons/iptables"
 	coordclient "github.com/control-center/serviced/coordinator/client"
@@ -43,6 +41,7 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/proxy"
+	"github.com/control-center/serviced/scheduler"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/volume"
 	"github.com/control-center/serviced/zzk"
@@ -92,16 +91,25 @@ type HostAgent struct {
 	zkSessionTimeout     int
 	delegateKeyFile      string
 	tokenFile            string
+	heartbeat            zkservice.HeartbeatConfig
 	serviceCache         *ServiceCache
+	hostPoolCache        *HostPoolCache
+	preserveTimeout      time.Duration // how long instances are preserved while serviced restarts
+	outputPath           string        // where the output of containers is buffered
+	outputSize           int64         // bytes of output buffered for each container
+	outputMu             sync.Mutex
+	following            map[string]struct{} // containers whose output is being buffered
+	instances            *instanceCache      // assigned instances cached for offline operation; nil if disabled
 }
 
-func getZkDSN(zookeepers []string, timeout int) string {
+func getZkDSN(zookeepers []string, timeout int, auth string) string {
 	if len(zookeepers) == 0 {
 		zookeepers = []string{"127.0.0.1:2181"}
 	}
 	dsn := coordzk.DSN{
 		Servers: zookeepers,
 		Timeout: time.Duration(timeout) * time.Second,
+		Auth:    auth,
 	}
 	return dsn.String()
 }
@@ -127,8 +135,14 @@ type AgentOptions struct {
 	DockerLogDriver      string
 	DockerLogConfig      map[string]string
 	ZKSessionTimeout     int
+	ZKAuth               string
 	DelegateKeyFile      string
 	TokenFile            string
+	Heartbeat            zkservice.HeartbeatConfig
+	PreserveTimeout      time.Duration // Leave containers running on shutdown for this long; 0 stops them
+	OutputPath           string        // Path where the output of containers is buffered
+	OutputSize           int64         // Bytes of output buffered for each container; 0 to disable
+	InstanceCachePath    string        // File where assigned instances are cached for offline operation; empty to disable
 }
 
 // NewHostAgent creates a new HostAgent given a connection string
@@ -154,15 +168,23 @@ func NewHostAgent(options AgentOptions, reg registry.Registry) (*HostAgent, erro
 	agent.zkSessionTimeout = options.ZKSessionTimeout
 	agent.delegateKeyFile = options.DelegateKeyFile
 	agent.tokenFile = options.TokenFile
+	agent.heartbeat = options.Heartbeat
+	agent.preserveTimeout = options.PreserveTimeout
+	agent.outputPath = options.OutputPath
+	agent.outputSize = options.OutputSize
 	agent.serviceCache = NewServiceCache(options.Master)
+	agent.hostPoolCache = NewHostPoolCache(options.Master)
+	if options.InstanceCachePath != "" {
+		agent.instances = newInstanceCache(options.InstanceCachePath)
+	}
 
 	var err error
-	dsn := getZkDSN(options.Zookeepers, agent.zkSessionTimeout)
+	dsn := getZkDSN(options.Zookeepers, agent.zkSessionTimeout, options.ZKAuth)
 	if agent.zkClient, err = coordclient.New("zookeeper", dsn, "", nil); err != nil {
 		return nil, err
 	}
 	if agent.storage, err = volume.GetDriver(options.VolumesPath); err != nil {
-		glog.Errorf("Could not load storage driver at %s: %s", options.VolumesPath, err)
+		plog.Errorf("Could not load storage driver at %s: %s", options.VolumesPath, err)
 		return nil, err
 	}
 	if agent.hostID, err = utils.HostID(); err != nil {
@@ -184,11 +206,31 @@ func attachAndRun(dockerID, command string) error {
 	output, err := utils.AttachAndRun(dockerID, []string{command})
 	if err != nil {
 		err = fmt.Errorf("%s (%s)", string(output), err)
-		glog.Errorf("Could not pause container %s: %s", dockerID, err)
+		plog.Errorf("Could not pause container %s: %s", dockerID, err)
 	}
 	return err
 }
 
+// runSnapshotCommand runs a snapshot command in the container, and returns
+// an error if it fails or does not finish within the timeout.  A timeout of 0
+// waits until the command exits.
+func runSnapshotCommand(dockerID, command string, timeout time.Duration) error {
+	if timeout <= 0 {
+		return attachAndRun(dockerID, command)
+	}
+	errC := make(chan error, 1)
+	go func() {
+		errC <- attachAndRun(dockerID, command)
+	}()
+	select {
+	case err := <-errC:
+		return err
+	case <-time.After(timeout):
+		plog.Errorf("Snapshot command on container %s did not finish within %s", dockerID, timeout)
+		return fmt.Errorf("command %q timed out after %s", command, timeout)
+	}
+}
+
 /*
 writeConfFile is responsible for writing contents out to a file
 Input string prefix	 : cp_cd67c62b-e462-5137-2cd8-38732db4abd9_zenmodeler_logstash_forwarder_conf_
@@ -201,12 +243,12 @@ Example name of file that is written: /tmp/cp_cd67c62b-e462-5137-2cd8-38732db4ab
 func writeConfFile(prefix string, id string, filename string, content string) (*os.File, error) {
 	f, err := ioutil.TempFile("", prefix)
 	if err != nil {
-		glog.Errorf("Could not generate tempfile for config %s %s", id, filename)
+		plog.Errorf("Could not generate tempfile for config %s %s", id, filename)
 		return f, err
 	}
 	_, err = f.WriteString(content)
 	if err != nil {
-		glog.Errorf("Could not write out config file %s %s", id, filename)
+		plog.Errorf("Could not write out config file %s %s", id, filename)
 		return f, err
 	}
 
@@ -262,7 +304,7 @@ func manageTransparentProxy(endpoint *service.ServiceEndpoint, addressConfig *ad
 
 // setupVolume
 func (a *HostAgent) setupVolume(tenantID string, service *service.Service, volume servicedefinition.Volume) (string, error) {
-	glog.V(4).Infof("setupVolume for service Name:%s ID:%s", service.Name, service.ID)
+	plog.Debugf("setupVolume for service Name:%s ID:%s", service.Name, service.ID)
 	vol, err := a.storage.Get(tenantID)
 	if err != nil {
 		return "", fmt.Errorf("could not get subvolume %s: %s", tenantID, err)
@@ -285,21 +327,21 @@ func (a *HostAgent) setupVolume(tenantID string, service *service.Service, volum
 	}
 	image, err := a.pullreg.ImagePath(service.ImageID)
 	if err != nil {
-		glog.Errorf("Could not get registry image for %s: %s", service.ImageID, err)
+		plog.Errorf("Could not get registry image for %s: %s", service.ImageID, err)
 		return "", err
 	}
 	if err := createVolumeDir(conn, resourcePath, containerPath, image, volume.Owner, volume.Permission); err != nil {
-		glog.Errorf("Error populating resource path: %s with container path: %s, %v", resourcePath, containerPath, err)
+		plog.Errorf("Error populating resource path: %s with container path: %s, %v", resourcePath, containerPath, err)
 		return "", err
 	}
 
-	glog.V(4).Infof("resourcePath: %s  containerPath: %s", resourcePath, containerPath)
+	plog.Debugf("resourcePath: %s  containerPath: %s", resourcePath, containerPath)
 	return resourcePath, nil
 }
 
 // main loop of the HostAgent
 func (a *HostAgent) Start(shutdown <-chan interface{}) {
-	glog.Info("Starting HostAgent")
+	plog.Info("Starting HostAgent")
 
 	// CC-1991: Unmount NFS on agent shutdown
 	if a.storage.DriverType() == volume.DriverTypeNFS {
@@ -308,16 +350,16 @@ func (a *HostAgent) Start(shutdown <-chan interface{}) {
 
 	var wg sync.WaitGroup
 	defer func() {
-		glog.Info("Waiting for agent routines...")
+		plog.Info("Waiting for agent routines...")
 		wg.Wait()
-		glog.Info("Agent routines ended")
+		plog.Info("Agent routines ended")
 	}()
 
 	wg.Add(1)
 	go func() {
-		glog.Infof("Starting TTL for old Docker containers")
+		plog.Infof("Starting TTL for old Docker containers")
 		docker.RunTTL(shutdown, time.Minute, a.maxContainerAge)
-		glog.Info("Docker TTL done")
+		plog.Info("Docker TTL done")
 		wg.Done()
 	}()
 
@@ -326,14 +368,14 @@ func (a *HostAgent) Start(shutdown <-chan interface{}) {
 	if cnxns := strings.TrimSpace(os.Getenv("SERVICED_IPTABLES_MAX_CONNECTIONS")); cnxns != "" {
 		maxConnections = cnxns
 	}
-	glog.Infof("Set sysctl maximum tracked connections for iptables to %s", maxConnections)
+	plog.Infof("Set sysctl maximum tracked connections for iptables to %s", maxConnections)
 	utils.SetSysctl("net.netfilter.nf_conntrack_max", maxConnections)
 
 	// Clean up any extant iptables chain, just in case
 	a.servicedChain.Remove()
 	// Add our chain for assigned IP rules
 	if err := a.servicedChain.Inject(); err != nil {
-		glog.Errorf("Error creating SERVICED iptables chain (%v)", err)
+		plog.Errorf("Error creating SERVICED iptables chain (%v)", err)
 	}
 	// Clean up when we're done
 	defer a.servicedChain.Remove()
@@ -341,6 +383,19 @@ func (a *HostAgent) Start(shutdown <-chan interface{}) {
 	unregister := make(chan interface{})
 	stop := make(chan interface{})
 
+	// keep the assigned instances running while disconnected
+	var offline chan interface{}
+	var offlineDone chan struct{}
+	var disconnected time.Time
+	stopOffline := func() {
+		if offline != nil {
+			close(offline)
+			<-offlineDone
+			offline = nil
+		}
+	}
+	defer stopOffline()
+
 	for {
 		// handle shutdown if we are waiting for a zk connection
 		var conn coordclient.Connection
@@ -353,7 +408,13 @@ func (a *HostAgent) Start(shutdown <-chan interface{}) {
 			continue
 		}
 
-		glog.Info("Got a connected client")
+		plog.Info("Got a connected client")
+
+		if a.instances != nil {
+			stopOffline()
+			a.reconcile(conn, disconnected)
+			disconnected = time.Time{}
+		}
 
 		rwg := &sync.WaitGroup{}
 		rwg.Add(1)
@@ -377,6 +438,42 @@ func (a *HostAgent) Start(shutdown <-chan interface{}) {
 			}
 		}()
 
+		// report to the master at adaptive intervals
+		rwg.Add(1)
+		go func() {
+			defer rwg.Done()
+			zkservice.RunHeartbeat(unregister, conn, a.hostID, a.heartbeat)
+		}()
+
+		// drop cached pool and host records when they change
+		rwg.Add(1)
+		go func() {
+			defer rwg.Done()
+			a.hostPoolCache.Watch(unregister, conn, a.poolID, a.hostID)
+		}()
+
+		// schedule the services of the pool if the pool delegates its
+		// scheduling to its hosts
+		rwg.Add(1)
+		go func() {
+			defer rwg.Done()
+			rootConn, err := zzk.GetLocalConnection("/")
+			if err != nil {
+				plog.WithError(err).Warn("Could not get root zookeeper connection, not contending for the scheduling of the pool")
+				return
+			}
+			scheduler.NewPoolScheduler(a.hostID, a.poolID, true, scheduler.NewMasterBackend(a.master)).Run(unregister, rootConn)
+		}()
+
+		// monitor the nfs mounts of the application volumes
+		if a.storage.DriverType() == volume.DriverTypeNFS {
+			rwg.Add(1)
+			go func() {
+				defer rwg.Done()
+				newDFSMonitor(a.storage.Root()).run(unregister, conn, a.hostID)
+			}()
+		}
+
 		// watch virtual IP zookeeper nodes
 		virtualIPListener := virtualips.NewVirtualIPListener(a, a.hostID)
 
@@ -389,22 +486,46 @@ func (a *HostAgent) Start(shutdown <-chan interface{}) {
 		// 2) its node is registered
 		// 3) receives signal to shutdown or breaks
 		hsListener := zkservice.NewHostStateListener(a, a.hostID)
+		if a.instances != nil {
+			hsListener.RunOffline()
+		}
 
 		startExit := make(chan struct{})
 		go func() {
 			defer close(startExit)
-			glog.Infof("Host Agent successfully started")
+			plog.Infof("Host Agent successfully started")
 			zzk.Start(stop, conn, hsListener, virtualIPListener, actionListener)
 		}()
 
 		select {
 		case <-startExit:
-			glog.Infof("Host Agent restarting")
+			plog.Infof("Host Agent restarting")
 			close(unregister)
 			unregister = make(chan interface{})
 			rwg.Wait()
+			if a.instances != nil {
+				plog.Infof("Running service instances offline until the master is reachable")
+				disconnected = time.Now()
+				offline, offlineDone = make(chan interface{}), make(chan struct{})
+				go func(stop <-chan interface{}, done chan<- struct{}) {
+					defer close(done)
+					a.runOffline(stop)
+				}(offline, offlineDone)
+			}
 		case <-shutdown:
-			glog.Infof("Host Agent shutting down")
+			plog.Infof("Host Agent shutting down")
+
+			// leave the containers running and tell the master not to
+			// reschedule them while serviced restarts
+			if a.preserveTimeout > 0 {
+				deadline := time.Now().Add(a.preserveTimeout)
+				if err := zkservice.MarkHostRestarting(conn, a.hostID, deadline); err != nil {
+					plog.Errorf("Could not mark host %s as restarting; stopping service instances: %s", a.hostID, err)
+				} else {
+					plog.Infof("Preserving service instances until %s while serviced restarts", deadline)
+					hsListener.Detach()
+				}
+			}
 
 			lockpth := path.Join("/hosts", a.hostID, "locked")
 			err := conn.CreateIfExists(lockpth, &coordclient.Dir{})
@@ -421,6 +542,7 @@ func (a *HostAgent) Start(shutdown <-chan interface{}) {
 			close(unregister)
 			rwg.Wait()
 			conn.Delete(path.Join("/hosts", a.hostID, "online"))
+			conn.Delete(path.Join("/hosts", a.hostID, "heartbeat"))
 			return
 		}
 	}
@@ -436,7 +558,7 @@ func (a *HostAgent) AttachAndRun(dockerID string, command []string) ([]byte, err
 
 // BindVirtualIP implements virtualip.VirtualIPHandler
 func (a *HostAgent) BindVirtualIP(virtualIP *pool.VirtualIP, name string) error {
-	glog.Infof("Adding: %v", virtualIP)
+	plog.Infof("Adding: %v", virtualIP)
 	// ensure that the Bind Address is reported by ifconfig ... ?
 	if err := exec.Command("ifconfig", virtualIP.BindInterface).Run(); err != nil {
 		return fmt.Errorf("Problem with BindInterface %s", virtualIP.BindInterface)
@@ -452,12 +574,12 @@ func (a *HostAgent) BindVirtualIP(virtualIP *pool.VirtualIP, name string) error
 		return fmt.Errorf("Problem with creating virtual interface %s", name)
 	}
 
-	glog.Infof("Added virtual interface/IP: %v (%+v)", name, virtualIP)
+	plog.Infof("Added virtual interface/IP: %v (%+v)", name, virtualIP)
 	return nil
 }
 
 func (a *HostAgent) UnbindVirtualIP(virtualIP *pool.VirtualIP) error {
-	glog.Infof("Removing: %v", virtualIP.IP)
+	plog.Infof("Removing: %v", virtualIP.IP)
 
 	binaryNetmask := net.IPMask(net.ParseIP(virtualIP.Netmask).To4())
 	cidr, _ := binaryNetmask.Size()
@@ -467,7 +589,7 @@ func (a *HostAgent) UnbindVirtualIP(virtualIP *pool.VirtualIP) error {
 		return fmt.Errorf("Problem with removing virtual interface %+v: %v", virtualIP, err)
 	}
 
-	glog.Infof("Removed virtual interface: %+v", virtualIP)
+	plog.Infof("Removed virtual interface: %+v", virtualIP)
 	return nil
 }
 
@@ -477,10 +599,10 @@ func (a *HostAgent) VirtualInterfaceMap(prefix string) (map[string]*pool.Virtual
 	//ip addr show | awk '/zvip/{print $NF}'
 	virtualInterfaceNames, err := exec.Command("bash", "-c", "ip addr show | awk '/"+prefix+"/{print $NF}'").CombinedOutput()
 	if err != nil {
-		glog.Warningf("Determining virtual interfaces failed: %v", err)
+		plog.Warningf("Determining virtual interfaces failed: %v", err)
 		return interfaceMap, err
 	}
-	glog.V(2).Infof("Control center virtual interfaces: %v", string(virtualInterfaceNames))
+	plog.Debugf("Control center virtual interfaces: %v", string(virtualInterfaceNames))
 
 	for _, virtualInterfaceName := range strings.Fields(string(virtualInterfaceNames)) {
 		bindInterfaceAndIndex := strings.Split(virtualInterfaceName, prefix)
@@ -493,7 +615,7 @@ func (a *HostAgent) VirtualInterfaceMap(prefix string) (map[string]*pool.Virtual
 		//ip addr show | awk '/virtualInterfaceName/ {print $2}'
 		virtualIPAddressAndCIDR, err := exec.Command("bash", "-c", "ip addr show | awk '/"+virtualInterfaceName+"/ {print $2}'").CombinedOutput()
 		if err != nil {
-			glog.Warningf("Determining IP address of interface %v failed: %v", virtualInterfaceName, err)
+			plog.Warningf("Determining IP address of interface %v failed: %v", virtualInterfaceName, err)
 			return interfaceMap, err
 		}
 
@@ -523,7 +645,7 @@ func (a *HostAgent) addStorageTenant(tenantID string) {
 func (a *HostAgent) releaseStorageTenants() {
 	for _, tenantID := range a.storageTenants {
 		if err := a.storage.Release(tenantID); err != nil {
-			glog.Warningf("Could not release tenant %s: %s", tenantID, err)
+			plog.Warningf("Could not release tenant %s: %s", tenantID, err)
 		}
 	}
 }
@@ -535,7 +657,7 @@ type stateResult struct {
 
 func waitForSsNodes(processing map[string]chan int, ssResultChan chan stateResult) (err error) {
 	for key, shutdown := range processing {
-		glog.V(1).Infof("Agent signaling for %s to shutdown.", key)
+		plog.Debugf("Agent signaling for %s to shutdown.", key)
 		shutdown <- 1
 	}
 
@@ -543,13 +665,13 @@ func waitForSsNodes(processing map[string]chan int, ssResultChan chan stateResul
 	for len(processing) > 0 {
 		select {
 		case ssResult := <-ssResultChan:
-			glog.V(1).Infof("Goroutine finished %s", ssResult.id)
+			plog.Debugf("Goroutine finished %s", ssResult.id)
 			if err == nil && ssResult.err != nil {
 				err = ssResult.err
 			}
 			delete(processing, ssResult.id)
 		}
 	}
-	glog.V(0).Info("All service state nodes are shut down")
+	plog.Info("All service state nodes are shut down")
 	return
 }
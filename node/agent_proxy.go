@@ -27,8 +27,10 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/rpc/master"
-	"github.com/zenoss/glog"
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 const (
@@ -50,7 +52,7 @@ type ZkInfo struct {
 }
 
 func (a *HostAgent) SendLogMessage(serviceLogInfo ServiceLogInfo, _ *struct{}) (err error) {
-	glog.Infof("Service: %v message: %v", serviceLogInfo.ServiceID, serviceLogInfo.Message)
+	plog.Infof("Service: %v message: %v", serviceLogInfo.ServiceID, serviceLogInfo.Message)
 	return nil
 }
 
@@ -87,16 +89,41 @@ func (a *HostAgent) GetEvaluatedService(request EvaluateServiceRequest, response
 	return nil
 }
 
+// WaitHealthChecks watches the service in zookeeper until the interval,
+// timeout, tolerance, or failure threshold of any of the instance's health
+// checks changes, or the timeout expires.
+func (a *HostAgent) WaitHealthChecks(request WaitHealthChecksRequest, response *map[string]health.HealthCheck) error {
+	logger := plog.WithField("serviceid", request.ServiceID)
+
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		logger.WithError(err).Error("Could not connect to zookeeper")
+		return err
+	}
+
+	cancel := make(chan struct{})
+	timer := time.AfterFunc(request.Timeout, func() { close(cancel) })
+	defer timer.Stop()
+
+	checks, err := zkservice.WaitHealthChecks(cancel, conn, a.poolID, request.ServiceID, request.HealthChecks)
+	if err != nil {
+		logger.WithError(err).Debug("Could not watch health checks")
+		return err
+	}
+	*response = checks
+	return nil
+}
+
 // GetProxySnapshotQuiece blocks until there is a snapshot request to the service
 func (a *HostAgent) GetProxySnapshotQuiece(serviceId string, snapshotId *string) error {
-	glog.Errorf("GetProxySnapshotQuiece() Unimplemented")
+	plog.Errorf("GetProxySnapshotQuiece() Unimplemented")
 	return errors.New("unimplemented")
 }
 
 // AckProxySnapshotQuiece is called by clients when the snapshot command has
 // shown the service is quieced; the agent returns a response when the snapshot is complete
 func (a *HostAgent) AckProxySnapshotQuiece(snapshotId string, unused *interface{}) error {
-	glog.Errorf("AckProxySnapshotQuiece() Unimplemented")
+	plog.Errorf("AckProxySnapshotQuiece() Unimplemented")
 	return errors.New("unimplemented")
 }
 
@@ -104,7 +131,7 @@ func (a *HostAgent) AckProxySnapshotQuiece(snapshotId string, unused *interface{
 func (a *HostAgent) ReportHealthStatus(req master.HealthStatusRequest, unused *int) error {
 	masterClient, err := master.NewClient(a.master)
 	if err != nil {
-		glog.Errorf("Could not start Control Center client: %s", err)
+		plog.Errorf("Could not start Control Center client: %s", err)
 		return err
 	}
 	defer masterClient.Close()
@@ -115,7 +142,7 @@ func (a *HostAgent) ReportHealthStatus(req master.HealthStatusRequest, unused *i
 func (a *HostAgent) ReportInstanceDead(req master.ServiceInstanceRequest, unused *int) error {
 	masterClient, err := master.NewClient(a.master)
 	if err != nil {
-		glog.Errorf("Could not start Control Center client; %s", err)
+		plog.Errorf("Could not start Control Center client; %s", err)
 		return err
 	}
 	defer masterClient.Close()
@@ -131,7 +158,7 @@ func (a *HostAgent) addControlPlaneEndpoint(endpoints map[string][]applicationen
 	endpoint.ContainerIP = "127.0.0.1"
 	port, err := strconv.Atoi(a.uiport[1:])
 	if err != nil {
-		glog.Errorf("Unable to interpret ui port.")
+		plog.Errorf("Unable to interpret ui port.")
 		return
 	}
 	endpoint.ContainerPort = uint16(port)
@@ -208,9 +235,9 @@ func (a *HostAgent) addEndpoint(key string, endpoint applicationendpoint.Applica
 		endpoints[key] = make([]applicationendpoint.ApplicationEndpoint, 0)
 	} else {
 		if len(endpoints[key]) > 0 {
-			glog.Warningf("Service %s has duplicate internal endpoint for key %s len(endpointList)=%d", endpoint.ServiceID, key, len(endpoints[key]))
+			plog.Warningf("Service %s has duplicate internal endpoint for key %s len(endpointList)=%d", endpoint.ServiceID, key, len(endpoints[key]))
 			for _, ep := range endpoints[key] {
-				glog.Warningf(" %+v", ep)
+				plog.Warningf(" %+v", ep)
 			}
 		}
 	}
@@ -219,7 +246,7 @@ func (a *HostAgent) addEndpoint(key string, endpoint applicationendpoint.Applica
 
 // GetHostID returns the agent's host id
 func (a *HostAgent) GetHostID(_ string, hostID *string) error {
-	glog.V(4).Infof("ControlCenterAgent.GetHostID(): %s", a.hostID)
+	plog.Debugf("ControlCenterAgent.GetHostID(): %s", a.hostID)
 	*hostID = a.hostID
 	return nil
 }
@@ -229,13 +256,13 @@ func (a *HostAgent) GetZkInfo(_ string, zkInfo *ZkInfo) error {
 	localDSN := a.zkClient.ConnectionString()
 	zkInfo.ZkDSN = strings.Replace(localDSN, "127.0.0.1", strings.Split(a.master, ":")[0], -1)
 	zkInfo.PoolID = a.poolID
-	glog.V(4).Infof("ControlCenterAgent.GetZkInfo(): %+v", zkInfo)
+	plog.Debugf("ControlCenterAgent.GetZkInfo(): pool %s", zkInfo.PoolID)
 	return nil
 }
 
 // GetServiceBindMounts returns the service bindmounts
 func (a *HostAgent) GetServiceBindMounts(serviceID string, bindmounts *map[string]string) error {
-	glog.V(4).Infof("ControlCenterAgent.GetServiceBindMounts(serviceID:%s)", serviceID)
+	plog.Debugf("ControlCenterAgent.GetServiceBindMounts(serviceID:%s)", serviceID)
 	*bindmounts = make(map[string]string, 0)
 
 	var evaluatedServiceResponse EvaluateServiceResponse
@@ -256,7 +283,7 @@ func (a *HostAgent) GetServiceBindMounts(serviceID string, bindmounts *map[strin
 			return err
 		}
 
-		glog.V(4).Infof("retrieved bindmount resourcePath:%s containerPath:%s", resourcePath, volume.ContainerPath)
+		plog.Debugf("retrieved bindmount resourcePath:%s containerPath:%s", resourcePath, volume.ContainerPath)
 		response[resourcePath] = volume.ContainerPath
 	}
 	*bindmounts = response
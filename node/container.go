@@ -15,12 +15,14 @@ package node
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
@@ -29,6 +31,7 @@ import (
 	"github.com/control-center/serviced/commons/iptables"
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/servicedversion"
 	"github.com/control-center/serviced/utils"
@@ -45,6 +48,11 @@ func (a *HostAgent) StopContainer(serviceID string, instanceID int) error {
 		"instanceid": instanceID,
 	})
 
+	// the instance is no longer assigned to this host
+	if a.instances != nil {
+		a.instances.remove(serviceID, instanceID)
+	}
+
 	// find the container by name
 	ctrName := fmt.Sprintf("%s-%d", serviceID, instanceID)
 	ctr, err := docker.FindContainer(ctrName)
@@ -70,7 +78,7 @@ func (a *HostAgent) StopContainer(serviceID string, instanceID int) error {
 
 // AttachContainer returns a channel that monitors the run state of a given
 // container.
-func (a *HostAgent) AttachContainer(state *zkservice.ServiceState, serviceID string, instanceID int) (<-chan time.Time, error) {
+func (a *HostAgent) AttachContainer(state *zkservice.ServiceState, serviceID string, instanceID int) (<-chan service.InstanceExit, error) {
 	logger := plog.WithFields(log.Fields{
 		"serviceid":   serviceID,
 		"instanceid":  instanceID,
@@ -100,22 +108,29 @@ func (a *HostAgent) AttachContainer(state *zkservice.ServiceState, serviceID str
 	}
 
 	// monitor the container
-	ev := a.monitorContainer(logger, ctr)
+	buffered := make(chan struct{})
+	ev := a.monitorContainer(logger, ctr, buffered)
 
 	// make sure the container is running at the time this event is set
 	if !ctr.IsRunning() {
 		logger.Debug("Could not capture event, container not running")
 		ctr.CancelOnEvent(docker.Die)
+		ctr.CancelOnEvent(docker.OOM)
+		close(buffered)
 		return nil, nil
 	}
+	go a.bufferOutput(logger, ctr, serviceID, instanceID, time.Now(), buffered)
 	go a.exposeAssignedIPs(state, ctr)
+	if a.instances != nil {
+		a.cacheAttachedInstance(logger, state, ctr, serviceID, instanceID)
+	}
 	return ev, nil
 }
 
 // StartContainer creates a new container and starts.  It returns info about
 // the container, and an event monitor to track the running state of the
 // service.
-func (a *HostAgent) StartContainer(cancel <-chan interface{}, serviceID string, instanceID int) (*zkservice.ServiceState, <-chan time.Time, error) {
+func (a *HostAgent) StartContainer(cancel <-chan interface{}, serviceID string, instanceID int) (*zkservice.ServiceState, <-chan service.InstanceExit, error) {
 	logger := plog.WithFields(log.Fields{
 		"serviceid":  serviceID,
 		"instanceid": instanceID,
@@ -136,32 +151,65 @@ func (a *HostAgent) StartContainer(cancel <-chan interface{}, serviceID string,
 	// Update the service with the complete image name
 	evaluatedService.ImageID = imageName
 
+	state, ev, err := a.runContainer(logger, tenantID, evaluatedService, instanceID, imageUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// cache the assignment so the instance can be restarted offline
+	if a.instances != nil {
+		svc := *evaluatedService
+		a.instances.set(cachedInstance{
+			ServiceID:    serviceID,
+			InstanceID:   instanceID,
+			DesiredState: service.SVCRun,
+			State:        *state,
+			Service:      &svc,
+			TenantID:     tenantID,
+		})
+	}
+	return state, ev, nil
+}
+
+// runContainer creates and starts a container for the service instance from
+// an image that is already on the host.
+func (a *HostAgent) runContainer(logger *log.Entry, tenantID string, svc *service.Service, instanceID int, imageUUID string) (*zkservice.ServiceState, <-chan service.InstanceExit, error) {
+	serviceID := svc.ID
+
 	// get the container configs
-	ctr, state, err := a.setupContainer(tenantID, evaluatedService, instanceID, imageUUID)
+	ctr, state, err := a.setupContainer(tenantID, svc, instanceID, imageUUID)
 	if err != nil {
 		logger.WithError(err).Debug("Could not setup container")
 		return nil, nil, err
 	}
 
 	// start the container
-	ev := a.monitorContainer(logger, ctr)
+	buffered := make(chan struct{})
+	ev := a.monitorContainer(logger, ctr, buffered)
 
 	if err := ctr.Start(); err != nil {
 		logger.WithError(err).Debug("Could not start container")
 		ctr.CancelOnEvent(docker.Die)
+		ctr.CancelOnEvent(docker.OOM)
+		close(buffered)
 		return nil, nil, err
 	}
 	logger.Debug("Started container")
+	go a.bufferOutput(logger, ctr, serviceID, instanceID, time.Time{}, buffered)
 
 	dctr, err := ctr.Inspect()
 	if err != nil {
 		logger.WithError(err).Debug("Could not inspect container")
 		ctr.CancelOnEvent(docker.Die)
+		ctr.CancelOnEvent(docker.OOM)
 		return nil, nil, err
 	}
 
 	state.HostIP = a.ipaddress
 	state.PrivateIP = ctr.NetworkSettings.IPAddress
+	if svc.NetworkMode == servicedefinition.NetworkHost {
+		state.PrivateIP = a.ipaddress
+	}
 	state.Started = dctr.State.StartedAt
 
 	go a.exposeAssignedIPs(state, ctr)
@@ -197,11 +245,17 @@ func (a *HostAgent) ResumeContainer(serviceID string, instanceID int) error {
 	}
 
 	// resume the paused container
-	if err := attachAndRun(ctrName, svc.Snapshot.Resume); err != nil {
-		logger.WithError(err).Debug("Could not resume paused container")
-		return err
+	if err := runSnapshotCommand(ctrName, svc.Snapshot.Resume, svc.Snapshot.CommandTimeout()); err != nil {
+		if !svc.Snapshot.ContinueOnFailure() {
+			logger.WithError(err).Debug("Could not resume paused container")
+			return err
+		}
+		logger.WithError(err).Warn("Resume command failed, continuing")
 	}
 	logger.Debug("Resumed paused container")
+	if a.instances != nil {
+		a.instances.setDesiredState(serviceID, instanceID, service.SVCRun)
+	}
 
 	return nil
 }
@@ -235,11 +289,17 @@ func (a *HostAgent) PauseContainer(serviceID string, instanceID int) error {
 	}
 
 	// pause the running container
-	if err := attachAndRun(ctrName, svc.Snapshot.Pause); err != nil {
-		logger.WithError(err).Debug("Could not pause running container")
-		return err
+	if err := runSnapshotCommand(ctrName, svc.Snapshot.Pause, svc.Snapshot.CommandTimeout()); err != nil {
+		if !svc.Snapshot.ContinueOnFailure() {
+			logger.WithError(err).Debug("Could not pause running container")
+			return err
+		}
+		logger.WithError(err).Warn("Pause command failed, continuing without quiescing the container")
 	}
 	logger.Debug("Paused running container")
+	if a.instances != nil {
+		a.instances.setDesiredState(serviceID, instanceID, service.SVCPause)
+	}
 	return nil
 }
 
@@ -297,40 +357,72 @@ func (a *HostAgent) pullImage(logger *log.Entry, cancel <-chan interface{}, imag
 	return uuid, name, nil
 }
 
-// monitorContainer tracks the running state of the container.
-func (a *HostAgent) monitorContainer(logger *log.Entry, ctr *docker.Container) <-chan time.Time {
-	ev := make(chan time.Time, 1)
+// monitorContainer tracks the running state of the container and reports how
+// it exited.  The container is not deleted after it exits until its output is
+// buffered.
+func (a *HostAgent) monitorContainer(logger *log.Entry, ctr *docker.Container, buffered <-chan struct{}) <-chan service.InstanceExit {
+	ev := make(chan service.InstanceExit, 1)
+	ctr.OnEvent(docker.OOM, func(_ string) {
+		logger.Warn("Container ran out of memory")
+	})
+
+	// die may be reported both by the event stream and by a resync after the
+	// stream reconnects.
+	var once sync.Once
 	ctr.OnEvent(docker.Die, func(_ string) {
-		defer close(ev)
-		dctr, err := ctr.Inspect()
-		if err != nil {
-			logger.WithError(err).Error("Could not look up container")
-			ev <- time.Now()
-			return
-		}
+		once.Do(func() {
+			ctr.CancelOnEvent(docker.Die)
+			ctr.CancelOnEvent(docker.OOM)
+			ev <- containerExit(logger, ctr, buffered)
+			close(ev)
+		})
+	})
+	return ev
+}
 
-		logger.WithFields(log.Fields{
-			"terminated": dctr.State.FinishedAt,
-			"exitcode":   dctr.State.ExitCode,
-		}).Debug("Container exited")
+// containerExit describes how the container exited and then deletes it.
+func containerExit(logger *log.Entry, ctr *docker.Container, buffered <-chan struct{}) service.InstanceExit {
+	dctr, err := ctr.Inspect()
+	if err != nil {
+		logger.WithError(err).Error("Could not look up container")
+		return service.InstanceExit{Terminated: time.Now()}
+	}
 
-		if dctr.State.ExitCode != 0 || log.GetLevel() == log.DebugLevel {
-			dockerLogsToFile(ctr.ID, 1000)
-		}
+	exit := service.InstanceExit{
+		Terminated: dctr.State.FinishedAt,
+		ExitCode:   dctr.State.ExitCode,
+		OOMKilled:  dctr.State.OOMKilled,
+	}
 
-		if err := ctr.Delete(true); err != nil {
-			logger.WithError(err).Warn("Could not delete container")
-		}
+	logger.WithFields(log.Fields{
+		"terminated": dctr.State.FinishedAt,
+		"exitcode":   dctr.State.ExitCode,
+		"oomkilled":  dctr.State.OOMKilled,
+	}).Debug("Container exited")
 
-		// just in case something unusual happened
-		if !dctr.State.FinishedAt.IsZero() {
-			ev <- dctr.State.FinishedAt
-		} else {
-			ev <- time.Now()
-		}
-		return
-	})
-	return ev
+	if dctr.State.ExitCode != 0 || log.GetLevel() == log.DebugLevel {
+		dockerLogsToFile(ctr.ID, 1000)
+	}
+
+	if exit.Output, err = ctr.TailOutput(exitOutputLines); err != nil {
+		logger.WithError(err).Debug("Could not get the final output of the container")
+	}
+
+	select {
+	case <-buffered:
+	case <-time.After(outputTimeout):
+		logger.Debug("Timed out waiting for container output to be buffered")
+	}
+
+	if err := ctr.Delete(true); err != nil {
+		logger.WithError(err).Warn("Could not delete container")
+	}
+
+	// just in case something unusual happened
+	if exit.Terminated.IsZero() {
+		exit.Terminated = time.Now()
+	}
+	return exit
 }
 
 // exposeAssignedIPs sets up iptables forwarding rules for endpoints with
@@ -437,9 +529,54 @@ func (a *HostAgent) setupContainer(tenantID string, svc *service.Service, instan
 	}
 	state.ContainerID = ctr.ID
 
+	if svc.NetworkMode == servicedefinition.NetworkMacvlan {
+		if err := a.connectMacvlan(svc.PoolID, ctr); err != nil {
+			logger.WithError(err).Error("Could not attach container to the macvlan network")
+			ctr.Delete(true)
+			return nil, nil, err
+		}
+	}
+
 	return ctr, state, nil
 }
 
+// connectMacvlan attaches the container to the macvlan network of the pool,
+// in addition to the bridge, so that the container can still reach the host.
+// The network is created from the pool's configuration the first time it is
+// needed on the host.
+func (a *HostAgent) connectMacvlan(poolID string, ctr *docker.Container) error {
+	name := "serviced-macvlan-" + poolID
+	if err := ctr.ConnectNetwork(name); err != docker.ErrNoSuchNetwork {
+		return err
+	}
+
+	p, err := a.hostPoolCache.GetResourcePool(poolID)
+	if err != nil {
+		return err
+	} else if p == nil || !p.Macvlan.Enabled() {
+		return fmt.Errorf("pool %s has no macvlan network", poolID)
+	}
+
+	opts := dockerclient.CreateNetworkOptions{
+		Name:    name,
+		Driver:  "macvlan",
+		Options: map[string]interface{}{"parent": p.Macvlan.Parent},
+	}
+	if p.Macvlan.Subnet != "" {
+		opts.IPAM.Config = []dockerclient.IPAMConfig{
+			{Subnet: p.Macvlan.Subnet, Gateway: p.Macvlan.Gateway},
+		}
+	}
+	if err := docker.CreateNetwork(opts); err != nil {
+		return err
+	}
+	plog.WithFields(log.Fields{
+		"network": name,
+		"parent":  p.Macvlan.Parent,
+	}).Info("Created macvlan network")
+	return ctr.ConnectNetwork(name)
+}
+
 func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service, instanceID int, imageUUID string) (*dockerclient.Config, *dockerclient.HostConfig, *zkservice.ServiceState, error) {
 	logger := plog.WithFields(log.Fields{
 		"tenantid":    tenantID,
@@ -500,6 +637,7 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 					PortNumber:     endpoint.PortNumber,
 					PortTemplate:   endpoint.PortTemplate,
 					VirtualAddress: endpoint.VirtualAddress,
+					CircuitBreaker: endpoint.CircuitBreaker,
 				})
 			}
 		}
@@ -658,11 +796,30 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 		}
 	}
 
+	// the service's dns servers take precedence over the host's
+	if len(svc.DNS) > 0 {
+		hcfg.DNS = append(append([]string{}, svc.DNS...), cfg.DNS...)
+	}
+	hcfg.DNSSearch = svc.DNSSearch
+	hcfg.ExtraHosts = svc.ExtraHosts
+
 	// Add hostname if set
 	if svc.Hostname != "" {
 		cfg.Hostname = svc.Hostname
 	}
 
+	if svc.NetworkMode == servicedefinition.NetworkHost {
+		// ports are not published, and the container uses the name and the
+		// resolver of the host
+		hcfg.NetworkMode = "host"
+		cfg.ExposedPorts = nil
+		hcfg.PortBindings = nil
+		cfg.Hostname = ""
+		cfg.DNS = nil
+		hcfg.DNS = nil
+		hcfg.DNSSearch = nil
+	}
+
 	cmd := []string{filepath.Join("/serviced", binary)}
 
 	// Flag TLS for the mux if it's disabled
@@ -703,6 +860,64 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 			Hard: 0,
 		},
 	}
+	for _, ulimit := range svc.Ulimits {
+		hcfg.Ulimits = append(hcfg.Ulimits, dockerclient.ULimit{
+			Name: ulimit.Name,
+			Soft: ulimit.Soft,
+			Hard: ulimit.Hard,
+		})
+	}
+	if svc.ShmSize.Value > 0 {
+		hcfg.ShmSize = int64(svc.ShmSize.Value)
+	}
+	if len(svc.Tmpfs) > 0 {
+		hcfg.Tmpfs = make(map[string]string)
+		for _, mount := range svc.Tmpfs {
+			var opts []string
+			if mount.Size.Value > 0 {
+				opts = append(opts, fmt.Sprintf("size=%d", mount.Size.Value))
+			}
+			if mount.Options != "" {
+				opts = append(opts, mount.Options)
+			}
+			hcfg.Tmpfs[mount.ContainerPath] = strings.Join(opts, ",")
+		}
+	}
+	if svc.ReadOnlyRootfs {
+		// the controller writes its files to a tmpfs instead
+		hcfg.ReadonlyRootfs = true
+		if hcfg.Tmpfs == nil {
+			hcfg.Tmpfs = make(map[string]string)
+		}
+		if _, ok := hcfg.Tmpfs["/run/serviced"]; !ok {
+			hcfg.Tmpfs["/run/serviced"] = ""
+		}
+		cfg.Env = append(cfg.Env, "SERVICED_READONLY_ROOTFS=true")
+	}
+
+	// docker does not allow privileged or host network containers to be
+	// remapped
+	hcfg.UsernsMode = svc.UsernsMode
+	if svc.Privileged || svc.NetworkMode == servicedefinition.NetworkHost {
+		hcfg.UsernsMode = "host"
+	}
+	hcfg.Sysctls = svc.Sysctls
+	hcfg.CapAdd = svc.CapAdd
+	hcfg.CapDrop = svc.CapDrop
+
+	switch svc.SeccompProfile {
+	case "":
+	case "unconfined":
+		hcfg.SecurityOpt = append(hcfg.SecurityOpt, "seccomp=unconfined")
+	default:
+		// docker expects the profile itself rather than its path
+		profile, err := ioutil.ReadFile(svc.SeccompProfile)
+		if err != nil {
+			logger.WithError(err).WithField("seccompprofile", svc.SeccompProfile).Debug("Could not read seccomp profile")
+			return nil, nil, nil, err
+		}
+		hcfg.SecurityOpt = append(hcfg.SecurityOpt, "seccomp="+string(profile))
+	}
 	return cfg, hcfg, state, nil
 }
 
@@ -22,6 +22,9 @@ import (
 
 	regmocks "github.com/control-center/serviced/dfs/registry/mocks"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/utils"
+	dockerclient "github.com/fsouza/go-dockerclient"
 )
 
 func TestSetupContainer_DockerLog(t *testing.T) {
@@ -61,3 +64,159 @@ func TestSetupContainer_DockerLog(t *testing.T) {
 	assert.Equal(hcfg.LogConfig.Config["bravo"], "two")
 	assert.Equal(hcfg.LogConfig.Config["charlie"], "three")
 }
+
+func TestSetupContainer_ContainerOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	fakeHostAgent := &HostAgent{
+		uiport:               ":443",
+		virtualAddressSubnet: "0.0.0.0",
+		pullreg:              &regmocks.Registry{},
+	}
+
+	fakeService := &service.Service{
+		ImageID:        "busybox:latest",
+		ID:             "faketestService",
+		Name:           "fakeTestServiceName",
+		Ulimits:        []servicedefinition.Ulimit{{Name: "nofile", Soft: 1024, Hard: 65536}},
+		Sysctls:        map[string]string{"net.core.somaxconn": "1024"},
+		CapAdd:         []string{"SYS_PTRACE"},
+		CapDrop:        []string{"MKNOD"},
+		SeccompProfile: "unconfined",
+	}
+
+	_, hcfg, _, err := fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.Nil(err)
+	assert.Equal([]dockerclient.ULimit{
+		{Name: "core", Soft: 0, Hard: 0},
+		{Name: "nofile", Soft: 1024, Hard: 65536},
+	}, hcfg.Ulimits)
+	assert.Equal(map[string]string{"net.core.somaxconn": "1024"}, hcfg.Sysctls)
+	assert.Equal([]string{"SYS_PTRACE"}, hcfg.CapAdd)
+	assert.Equal([]string{"MKNOD"}, hcfg.CapDrop)
+	assert.Equal([]string{"seccomp=unconfined"}, hcfg.SecurityOpt)
+
+	fakeService.SeccompProfile = "/nonexistent/seccomp.json"
+	_, _, _, err = fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.NotNil(err)
+}
+
+func TestSetupContainer_DNS(t *testing.T) {
+	assert := assert.New(t)
+
+	fakeHostAgent := &HostAgent{
+		uiport:               ":443",
+		virtualAddressSubnet: "0.0.0.0",
+		pullreg:              &regmocks.Registry{},
+		dockerDNS:            []string{"192.168.0.1"},
+	}
+
+	fakeService := &service.Service{
+		ImageID:    "busybox:latest",
+		ID:         "faketestService",
+		Name:       "fakeTestServiceName",
+		DNS:        []string{"10.0.0.2"},
+		DNSSearch:  []string{"corp.example.com"},
+		ExtraHosts: []string{"ldap.corp.example.com:10.20.30.40"},
+	}
+
+	cfg, hcfg, _, err := fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.Nil(err)
+	assert.Equal([]string{"192.168.0.1"}, cfg.DNS)
+	assert.Equal([]string{"10.0.0.2", "192.168.0.1"}, hcfg.DNS)
+	assert.Equal([]string{"corp.example.com"}, hcfg.DNSSearch)
+	assert.Equal([]string{"ldap.corp.example.com:10.20.30.40"}, hcfg.ExtraHosts)
+}
+
+func TestSetupContainer_HostNetwork(t *testing.T) {
+	assert := assert.New(t)
+
+	fakeHostAgent := &HostAgent{
+		uiport:               ":443",
+		virtualAddressSubnet: "0.0.0.0",
+		pullreg:              &regmocks.Registry{},
+		dockerDNS:            []string{"192.168.0.1"},
+	}
+
+	fakeService := &service.Service{
+		ImageID:     "busybox:latest",
+		ID:          "faketestService",
+		Name:        "fakeTestServiceName",
+		Hostname:    "fakehost",
+		NetworkMode: servicedefinition.NetworkHost,
+		Endpoints: []service.ServiceEndpoint{
+			{Name: "multicast", Purpose: "export", Protocol: "udp", PortNumber: 5353},
+		},
+	}
+
+	cfg, hcfg, state, err := fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.Nil(err)
+	assert.Equal("host", hcfg.NetworkMode)
+	assert.Empty(cfg.ExposedPorts)
+	assert.Empty(hcfg.PortBindings)
+	assert.Empty(cfg.Hostname)
+	assert.Empty(cfg.DNS)
+	assert.Len(state.Exports, 1)
+}
+
+func TestSetupContainer_MemoryMounts(t *testing.T) {
+	assert := assert.New(t)
+
+	fakeHostAgent := &HostAgent{
+		uiport:               ":443",
+		virtualAddressSubnet: "0.0.0.0",
+		pullreg:              &regmocks.Registry{},
+	}
+
+	fakeService := &service.Service{
+		ImageID: "busybox:latest",
+		ID:      "faketestService",
+		Name:    "fakeTestServiceName",
+		ShmSize: utils.NewEngNotation(1 << 30),
+		Tmpfs: []servicedefinition.TmpfsMount{
+			{ContainerPath: "/var/cache", Size: utils.NewEngNotation(256 << 20), Options: "noexec"},
+			{ContainerPath: "/run"},
+		},
+	}
+
+	_, hcfg, _, err := fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.Nil(err)
+	assert.Equal(int64(1<<30), hcfg.ShmSize)
+	assert.Equal(map[string]string{
+		"/var/cache": "size=268435456,noexec",
+		"/run":       "",
+	}, hcfg.Tmpfs)
+}
+
+func TestSetupContainer_ReadOnlyRootfs(t *testing.T) {
+	assert := assert.New(t)
+
+	fakeHostAgent := &HostAgent{
+		uiport:               ":443",
+		virtualAddressSubnet: "0.0.0.0",
+		pullreg:              &regmocks.Registry{},
+	}
+
+	fakeService := &service.Service{
+		ImageID:        "busybox:latest",
+		ID:             "faketestService",
+		Name:           "fakeTestServiceName",
+		ReadOnlyRootfs: true,
+		Tmpfs:          []servicedefinition.TmpfsMount{{ContainerPath: "/var/cache"}},
+	}
+
+	cfg, hcfg, _, err := fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.Nil(err)
+	assert.True(hcfg.ReadonlyRootfs)
+	assert.Equal(map[string]string{
+		"/var/cache":    "",
+		"/run/serviced": "",
+	}, hcfg.Tmpfs)
+	assert.Contains(cfg.Env, "SERVICED_READONLY_ROOTFS=true")
+	assert.Equal("", hcfg.UsernsMode)
+
+	fakeService.Privileged = true
+	_, hcfg, _, err = fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.Nil(err)
+	assert.Equal("host", hcfg.UsernsMode)
+}
@@ -0,0 +1,296 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/coordinator/storage"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/utils"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+const (
+	// dfsMonitorInterval is the time between checks of the dfs mounts
+	dfsMonitorInterval = 30 * time.Second
+
+	// dfsProbeTimeout is how long a mount point may take to respond before
+	// it is considered unavailable
+	dfsProbeTimeout = 5 * time.Second
+
+	// dfsMaxEvents is the number of availability changes reported
+	dfsMaxEvents = 20
+)
+
+var (
+	// ErrStaleMount is returned when a mount has a stale file handle
+	ErrStaleMount = errors.New("stale nfs file handle")
+
+	// ErrMountTimeout is returned when a mount does not respond in time
+	ErrMountTimeout = errors.New("timed out waiting for the mount to respond")
+)
+
+// dfsMonitor checks the health of the shared storage mounts of the application
+// volumes on a delegate and tries to remount those that become unavailable.
+type dfsMonitor struct {
+	root    string // path where the application volumes are mounted
+	timeout time.Duration
+	mounts  func() ([]utils.MountInfo, error)
+	probe   func(path string, timeout time.Duration) (time.Duration, error)
+	remount func(volume, local string) error
+	health  host.DFSHealth
+}
+
+func newDFSMonitor(root string) *dfsMonitor {
+	return &dfsMonitor{
+		root:    root,
+		timeout: dfsProbeTimeout,
+		mounts:  utils.GetDefaultMountProc().ListAll,
+		probe:   newMountProber().probe,
+		remount: remountVolume,
+	}
+}
+
+// run reports the health of the dfs mounts until cancelled.  The connection
+// is expected to be pre-loaded with the path to the resource pool.
+func (m *dfsMonitor) run(cancel <-chan interface{}, conn client.Connection, hostid string) {
+	logger := plog.WithField("hostid", hostid)
+	for {
+		health := m.check(time.Now())
+		if err := zkservice.UpdateDFSHealth(conn, hostid, health); err == client.ErrNoNode {
+			logger.Debug("Host is not registered; skipping dfs health report")
+		} else if err != nil {
+			logger.WithError(err).Warn("Could not report dfs health")
+		}
+
+		select {
+		case <-time.After(dfsMonitorInterval):
+		case <-cancel:
+			return
+		}
+	}
+}
+
+// check probes every shared storage mount under the root, remounts those that are
+// unavailable, and records changes in availability as events.
+func (m *dfsMonitor) check(now time.Time) host.DFSHealth {
+	health := host.DFSHealth{
+		Healthy: true,
+		Mounts:  []host.DFSMount{},
+		Events:  m.health.Events,
+		Updated: now.UTC(),
+	}
+	mounts, err := m.mounts()
+	if err != nil {
+		plog.WithError(err).Warn("Could not list mounts")
+		health.Healthy = false
+		m.health = health
+		return health
+	}
+
+	previous := make(map[string]host.DFSMount)
+	for _, mount := range m.health.Mounts {
+		previous[mount.Volume] = mount
+	}
+	for _, info := range mounts {
+		if !storage.IsSharedFSType(info.FSType) {
+			continue
+		}
+		volume, err := filepath.Rel(m.root, info.MountPoint)
+		if err != nil || volume == "." || strings.HasPrefix(volume, "..") {
+			continue
+		}
+		prev, ok := previous[volume]
+		if !ok {
+			prev = host.DFSMount{Available: true}
+		}
+		delete(previous, volume)
+		mount := m.checkMount(host.DFSMount{
+			Volume:    volume,
+			Remote:    info.Device,
+			LocalPath: info.MountPoint,
+		}, prev, true)
+		health.Mounts = append(health.Mounts, mount)
+	}
+
+	// volumes that dropped out of the mount table are unavailable; keep
+	// remounting them
+	for _, prev := range previous {
+		mount := m.checkMount(host.DFSMount{
+			Volume:    prev.Volume,
+			Remote:    prev.Remote,
+			LocalPath: prev.LocalPath,
+		}, prev, false)
+		health.Mounts = append(health.Mounts, mount)
+	}
+	sort.Sort(dfsMountsByVolume(health.Mounts))
+
+	for i, mount := range health.Mounts {
+		if !mount.Available {
+			health.Healthy = false
+		}
+		prev, ok := m.previousMount(mount.Volume)
+		if !ok {
+			prev.Available = true
+		}
+		if mount.Available == prev.Available {
+			continue
+		}
+		event := host.DFSEvent{Time: now.UTC(), Volume: mount.Volume, Available: mount.Available}
+		logger := plog.WithFields(log.Fields{
+			"volume": mount.Volume,
+			"remote": mount.Remote,
+			"path":   mount.LocalPath,
+		})
+		if mount.Available {
+			event.Message = fmt.Sprintf("Shared volume for application %s is available", mount.Volume)
+			logger.Info(event.Message)
+		} else {
+			event.Message = fmt.Sprintf("Shared volume for application %s is unavailable: %s", mount.Volume, health.Mounts[i].Error)
+			logger.Error(event.Message)
+		}
+		health.Events = append(health.Events, event)
+	}
+	if n := len(health.Events); n > dfsMaxEvents {
+		health.Events = append([]host.DFSEvent{}, health.Events[n-dfsMaxEvents:]...)
+	}
+	m.health = health
+	return health
+}
+
+// previousMount returns the mount of the volume from the last check
+func (m *dfsMonitor) previousMount(volume string) (host.DFSMount, bool) {
+	for _, mount := range m.health.Mounts {
+		if mount.Volume == volume {
+			return mount, true
+		}
+	}
+	return host.DFSMount{}, false
+}
+
+// checkMount probes a mount and tries to remount it if it is unavailable
+func (m *dfsMonitor) checkMount(mount, prev host.DFSMount, mounted bool) host.DFSMount {
+	logger := plog.WithFields(log.Fields{
+		"volume": mount.Volume,
+		"remote": mount.Remote,
+		"path":   mount.LocalPath,
+	})
+
+	var latency time.Duration
+	err := errors.New("not mounted")
+	if mounted {
+		latency, err = m.probe(mount.LocalPath, m.timeout)
+	}
+	if err != nil {
+		mount.Remounts = prev.Remounts + 1
+		logger = logger.WithField("attempt", mount.Remounts)
+		logger.WithError(err).Warn("Distributed filesystem mount is unavailable; remounting")
+		if rerr := m.remount(mount.Volume, mount.LocalPath); rerr != nil {
+			logger.WithError(rerr).Warn("Could not remount distributed filesystem")
+		} else {
+			latency, err = m.probe(mount.LocalPath, m.timeout)
+		}
+	}
+	mount.Latency = latency
+	if err == nil {
+		mount.Available = true
+		mount.Remounts = 0
+	} else {
+		mount.Stale = err == ErrStaleMount
+		mount.Error = err.Error()
+	}
+	return mount
+}
+
+type dfsMountsByVolume []host.DFSMount
+
+func (m dfsMountsByVolume) Len() int           { return len(m) }
+func (m dfsMountsByVolume) Less(i, j int) bool { return m[i].Volume < m[j].Volume }
+func (m dfsMountsByVolume) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+
+// mountProbe is a stat of a mount point that may still be running
+type mountProbe struct {
+	done    chan struct{}
+	latency time.Duration
+	err     error
+}
+
+// mountProber stats mount points, allowing at most one stat per mount point
+// at a time so that hung mounts do not pile up goroutines.
+type mountProber struct {
+	mu      sync.Mutex
+	pending map[string]*mountProbe
+}
+
+func newMountProber() *mountProber {
+	return &mountProber{pending: make(map[string]*mountProbe)}
+}
+
+// probe measures how long it takes to stat the mount point.  If the stat from
+// an earlier probe has not returned yet, it waits on that one instead.
+func (p *mountProber) probe(path string, timeout time.Duration) (time.Duration, error) {
+	p.mu.Lock()
+	pr, ok := p.pending[path]
+	if !ok {
+		pr = &mountProbe{done: make(chan struct{})}
+		p.pending[path] = pr
+		go p.stat(path, pr)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-pr.done:
+		return pr.latency, pr.err
+	case <-time.After(timeout):
+		return timeout, ErrMountTimeout
+	}
+}
+
+func (p *mountProber) stat(path string, pr *mountProbe) {
+	start := time.Now()
+	_, err := os.Stat(path)
+	if perr, ok := err.(*os.PathError); ok && perr.Err == syscall.ESTALE {
+		err = ErrStaleMount
+	}
+	pr.latency, pr.err = time.Since(start), err
+
+	p.mu.Lock()
+	delete(p.pending, path)
+	p.mu.Unlock()
+	close(pr.done)
+}
+
+// remountVolume unmounts the local path and mounts the volume again using the
+// transport of the pool
+func remountVolume(volume, local string) error {
+	c, err := storage.GetClient()
+	if err != nil {
+		return err
+	}
+	if err := c.Unmount(local); err != nil {
+		plog.WithField("path", local).WithError(err).Debug("Could not unmount distributed filesystem")
+	}
+	return c.Mount(volume, local)
+}
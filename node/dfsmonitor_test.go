@@ -0,0 +1,176 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package node
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/utils"
+)
+
+type testMounts struct {
+	mounts   []utils.MountInfo
+	errs     map[string]error // probe errors by local path
+	remounts []string
+	remount  error // error returned by remount
+}
+
+func (t *testMounts) monitor() *dfsMonitor {
+	return &dfsMonitor{
+		root:    "/opt/serviced/var/volumes",
+		timeout: time.Second,
+		mounts:  func() ([]utils.MountInfo, error) { return t.mounts, nil },
+		probe: func(path string, timeout time.Duration) (time.Duration, error) {
+			return 10 * time.Millisecond, t.errs[path]
+		},
+		remount: func(volume, local string) error {
+			t.remounts = append(t.remounts, local)
+			if t.remount == nil {
+				delete(t.errs, local)
+			}
+			return t.remount
+		},
+	}
+}
+
+func TestDFSMonitorHealthy(t *testing.T) {
+	mounts := &testMounts{
+		mounts: []utils.MountInfo{
+			{Device: "10.0.0.1:/serviced_volumes_v2/tenant2", MountPoint: "/opt/serviced/var/volumes/tenant2", FSType: "nfs4"},
+			{Device: "10.0.0.1:/serviced_volumes_v2/tenant1", MountPoint: "/opt/serviced/var/volumes/tenant1", FSType: "nfs4"},
+			{Device: "10.0.0.2:6789:/serviced/tenant3", MountPoint: "/opt/serviced/var/volumes/tenant3", FSType: "ceph"},
+			{Device: "/dev/sda1", MountPoint: "/opt/serviced/var/volumes/local", FSType: "ext4"},
+			{Device: "10.0.0.1:/other", MountPoint: "/mnt/other", FSType: "nfs4"},
+		},
+	}
+	health := mounts.monitor().check(time.Now())
+	if !health.Healthy {
+		t.Errorf("Expected healthy mounts, got %+v", health)
+	}
+	if len(health.Mounts) != 3 || health.Mounts[0].Volume != "tenant1" || health.Mounts[1].Volume != "tenant2" || health.Mounts[2].Volume != "tenant3" {
+		t.Fatalf("Expected the tenant mounts, got %+v", health.Mounts)
+	}
+	if m := health.Mounts[0]; !m.Available || m.Latency != 10*time.Millisecond || m.Remote != "10.0.0.1:/serviced_volumes_v2/tenant1" {
+		t.Errorf("Unexpected mount %+v", m)
+	}
+	if len(health.Events) != 0 || len(mounts.remounts) != 0 {
+		t.Errorf("Expected no events or remounts, got %+v and %v", health.Events, mounts.remounts)
+	}
+}
+
+func TestDFSMonitorRemount(t *testing.T) {
+	local := "/opt/serviced/var/volumes/tenant1"
+	mounts := &testMounts{
+		mounts: []utils.MountInfo{
+			{Device: "10.0.0.1:/serviced_volumes_v2/tenant1", MountPoint: local, FSType: "nfs4"},
+		},
+		errs:    map[string]error{local: ErrStaleMount},
+		remount: errors.New("mount failed"),
+	}
+	m := mounts.monitor()
+
+	// the remount fails and the volume is lost
+	health := m.check(time.Now())
+	if health.Healthy {
+		t.Errorf("Expected unhealthy mounts")
+	}
+	if mount := health.Mounts[0]; mount.Available || !mount.Stale || mount.Remounts != 1 {
+		t.Errorf("Unexpected mount %+v", mount)
+	}
+	if len(health.Events) != 1 || health.Events[0].Available || health.Events[0].Volume != "tenant1" {
+		t.Fatalf("Expected an unavailable event, got %+v", health.Events)
+	}
+
+	mounts.mounts = nil
+	health = m.check(time.Now())
+	if mount := health.Mounts[0]; mount.Available || mount.Remounts != 2 || mount.Error != "not mounted" {
+		t.Errorf("Unexpected mount %+v", mount)
+	}
+	if len(health.Events) != 1 {
+		t.Errorf("Expected no new events, got %+v", health.Events)
+	}
+
+	// the remount succeeds
+	mounts.remount = nil
+	health = m.check(time.Now())
+	if !health.Healthy {
+		t.Errorf("Expected healthy mounts, got %+v", health)
+	}
+	if mount := health.Mounts[0]; !mount.Available || mount.Remounts != 0 {
+		t.Errorf("Unexpected mount %+v", mount)
+	}
+	if len(health.Events) != 2 || !health.Events[1].Available {
+		t.Errorf("Expected an available event, got %+v", health.Events)
+	}
+	if len(mounts.remounts) != 3 {
+		t.Errorf("Expected 3 remount attempts, got %v", mounts.remounts)
+	}
+}
+
+func TestDFSMonitorMissingMount(t *testing.T) {
+	local := "/opt/serviced/var/volumes/tenant1"
+	mounts := &testMounts{
+		mounts: []utils.MountInfo{
+			{Device: "10.0.0.1:/serviced_volumes_v2/tenant1", MountPoint: local, FSType: "nfs4"},
+		},
+		remount: errors.New("mount failed"),
+	}
+	m := mounts.monitor()
+	if health := m.check(time.Now()); !health.Healthy {
+		t.Fatalf("Expected healthy mounts, got %+v", health)
+	}
+
+	// the mount disappears from the mount table
+	mounts.mounts = nil
+	health := m.check(time.Now())
+	if health.Healthy {
+		t.Errorf("Expected unhealthy mounts")
+	}
+	if len(health.Mounts) != 1 {
+		t.Fatalf("Expected the missing mount, got %+v", health.Mounts)
+	}
+	if mount := health.Mounts[0]; mount.Available || mount.Remounts != 1 || mount.Error != "not mounted" {
+		t.Errorf("Unexpected mount %+v", mount)
+	}
+	if len(health.Events) != 1 || health.Events[0].Available {
+		t.Errorf("Expected an unavailable event, got %+v", health.Events)
+	}
+	if len(mounts.remounts) != 1 || mounts.remounts[0] != local {
+		t.Errorf("Expected a remount of %s, got %v", local, mounts.remounts)
+	}
+}
+
+func TestMountProberSharesPendingProbe(t *testing.T) {
+	p := newMountProber()
+	pr := &mountProbe{done: make(chan struct{})}
+	p.pending["/mnt/hung"] = pr
+
+	// the hung stat is still running, so no new one is started
+	if _, err := p.probe("/mnt/hung", 10*time.Millisecond); err != ErrMountTimeout {
+		t.Errorf("Expected %s, got %v", ErrMountTimeout, err)
+	}
+	if p.pending["/mnt/hung"] != pr {
+		t.Errorf("Expected the pending probe to be reused")
+	}
+
+	pr.latency = time.Millisecond
+	close(pr.done)
+	if latency, err := p.probe("/mnt/hung", time.Second); err != nil || latency != time.Millisecond {
+		t.Errorf("Expected the pending result, got %s, %v", latency, err)
+	}
+}
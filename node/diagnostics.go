@@ -0,0 +1,113 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/commons/atomicfile"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// diagnosticFile returns the path of the saved output of a diagnostic action
+// run on a service instance
+func diagnosticFile(dir, serviceID string, instanceID int, action string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%d.%s.diag", serviceID, instanceID, action))
+}
+
+// SaveDiagnostic implements zkdocker.DiagnosticHandler; it saves the output of
+// a built-in diagnostic action so that it can be gathered by the master.
+func (a *HostAgent) SaveDiagnostic(dockerID, action string, output []byte, err error) {
+	logger := plog.WithFields(log.Fields{
+		"dockerid":   dockerID,
+		"action":     action,
+		"outputpath": a.outputPath,
+	})
+	if a.outputPath == "" {
+		return
+	}
+
+	// docker ids of service instances are SERVICEID-INSTANCEID
+	i := strings.LastIndex(dockerID, "-")
+	if i < 0 {
+		logger.Debug("Not saving diagnostic output of a container that is not a service instance")
+		return
+	}
+	instanceID, convErr := strconv.Atoi(dockerID[i+1:])
+	if convErr != nil {
+		logger.Debug("Not saving diagnostic output of a container that is not a service instance")
+		return
+	}
+	result := service.DiagnosticOutput{
+		ServiceID:  dockerID[:i],
+		InstanceID: instanceID,
+		Action:     action,
+		Ran:        time.Now().UTC(),
+		Output:     output,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if err := saveDiagnostic(a.outputPath, result); err != nil {
+		logger.WithError(err).Warn("Could not save diagnostic output")
+		return
+	}
+	logger.Debug("Saved diagnostic output")
+}
+
+// saveDiagnostic replaces the saved output of a diagnostic action
+func saveDiagnostic(dir string, result service.DiagnosticOutput) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(diagnosticFile(dir, result.ServiceID, result.InstanceID, result.Action), data, 0644)
+}
+
+// ReadDiagnostics returns the saved output of the diagnostic actions that were
+// run on a service instance, sorted by action.
+func ReadDiagnostics(dir, serviceID string, instanceID int) ([]service.DiagnosticOutput, error) {
+	filenames, err := filepath.Glob(diagnosticFile(dir, serviceID, instanceID, "*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(filenames)
+	results := []service.DiagnosticOutput{}
+	for _, filename := range filenames {
+		data, err := ioutil.ReadFile(filename)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		var result service.DiagnosticOutput
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
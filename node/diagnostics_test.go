@@ -0,0 +1,68 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package node
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnostics(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "diagnostics")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	a := &HostAgent{outputPath: dir}
+
+	// nothing saved
+	results, err := ReadDiagnostics(dir, "svc", 1)
+	assert.NoError(err)
+	assert.Empty(results)
+
+	a.SaveDiagnostic("svc-1", "diag-netstat", []byte("tcp 0 0 0.0.0.0:8080 LISTEN\n"), nil)
+	a.SaveDiagnostic("svc-1", "diag-lsof", []byte("bash: lsof: command not found\n"), errors.New("exit status 127"))
+	a.SaveDiagnostic("svc-10", "diag-lsof", []byte("other instance\n"), nil)
+	a.SaveDiagnostic("not-a-service", "diag-lsof", []byte("ignored\n"), nil)
+
+	results, err = ReadDiagnostics(dir, "svc", 1)
+	assert.NoError(err)
+	if assert.Len(results, 2) {
+		assert.Equal("diag-lsof", results[0].Action)
+		assert.Equal("exit status 127", results[0].Error)
+		assert.Equal("diag-netstat", results[1].Action)
+		assert.Equal("svc", results[1].ServiceID)
+		assert.Equal(1, results[1].InstanceID)
+		assert.Equal("tcp 0 0 0.0.0.0:8080 LISTEN\n", string(results[1].Output))
+		assert.Empty(results[1].Error)
+		assert.False(results[1].Ran.IsZero())
+	}
+
+	// running the action again replaces its output
+	a.SaveDiagnostic("svc-1", "diag-netstat", []byte("none\n"), nil)
+	results, err = ReadDiagnostics(dir, "svc", 1)
+	assert.NoError(err)
+	if assert.Len(results, 2) {
+		assert.Equal("none\n", string(results[1].Output))
+	}
+
+	results, err = ReadDiagnostics(dir, "not", 0)
+	assert.NoError(err)
+	assert.Empty(results)
+}
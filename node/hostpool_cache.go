@@ -0,0 +1,195 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"path"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	coordclient "github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/rpc/master"
+)
+
+// hostPoolCacheTTL is how long a pool or host record is used before it is
+// looked up from the master again, in case a change notification was missed.
+const hostPoolCacheTTL = 10 * time.Minute
+
+// HostPoolCache keeps the pool and host records that the agent looks up from
+// the master, since they rarely change.  Records expire after a ttl, and the
+// records of the agent's own pool and host are dropped as soon as zookeeper
+// reports that they changed.
+type HostPoolCache struct {
+	ttl   time.Duration
+	mu    sync.Mutex
+	pools map[string]cachedRecord
+	hosts map[string]cachedRecord
+
+	// lookups against the master, replaced for unit testing
+	getPool func(poolID string) (*pool.ResourcePool, error)
+	getHost func(hostID string) (*host.Host, error)
+}
+
+type cachedRecord struct {
+	value   interface{}
+	expires time.Time
+}
+
+// NewHostPoolCache returns a cache of the pool and host records of the master
+func NewHostPoolCache(masterAddr string) *HostPoolCache {
+	return &HostPoolCache{
+		ttl:   hostPoolCacheTTL,
+		pools: make(map[string]cachedRecord),
+		hosts: make(map[string]cachedRecord),
+		getPool: func(poolID string) (*pool.ResourcePool, error) {
+			masterClient, err := master.NewClient(masterAddr)
+			if err != nil {
+				return nil, err
+			}
+			defer masterClient.Close()
+			return masterClient.GetResourcePool(poolID)
+		},
+		getHost: func(hostID string) (*host.Host, error) {
+			masterClient, err := master.NewClient(masterAddr)
+			if err != nil {
+				return nil, err
+			}
+			defer masterClient.Close()
+			return masterClient.GetHost(hostID)
+		},
+	}
+}
+
+// GetResourcePool returns the pool, looking it up from the master if it is
+// not cached
+func (c *HostPoolCache) GetResourcePool(poolID string) (*pool.ResourcePool, error) {
+	if value, ok := c.get(c.pools, poolID); ok {
+		return value.(*pool.ResourcePool), nil
+	}
+	p, err := c.getPool(poolID)
+	if err != nil {
+		return nil, err
+	}
+	if p != nil {
+		c.set(c.pools, poolID, p)
+	}
+	return p, nil
+}
+
+// GetHost returns the host, looking it up from the master if it is not
+// cached
+func (c *HostPoolCache) GetHost(hostID string) (*host.Host, error) {
+	if value, ok := c.get(c.hosts, hostID); ok {
+		return value.(*host.Host), nil
+	}
+	h, err := c.getHost(hostID)
+	if err != nil {
+		return nil, err
+	}
+	if h != nil {
+		c.set(c.hosts, hostID, h)
+	}
+	return h, nil
+}
+
+// InvalidatePool drops the cached record of the pool
+func (c *HostPoolCache) InvalidatePool(poolID string) {
+	c.mu.Lock()
+	delete(c.pools, poolID)
+	c.mu.Unlock()
+}
+
+// InvalidateHost drops the cached record of the host
+func (c *HostPoolCache) InvalidateHost(hostID string) {
+	c.mu.Lock()
+	delete(c.hosts, hostID)
+	c.mu.Unlock()
+}
+
+// Purge drops all of the cached records
+func (c *HostPoolCache) Purge() {
+	c.mu.Lock()
+	for id := range c.pools {
+		delete(c.pools, id)
+	}
+	for id := range c.hosts {
+		delete(c.hosts, id)
+	}
+	c.mu.Unlock()
+}
+
+// Watch drops the cached records of the pool and host whenever their nodes
+// change in zookeeper, until cancel is closed.  conn is a pool-based
+// connection.
+func (c *HostPoolCache) Watch(cancel <-chan interface{}, conn coordclient.Connection, poolID, hostID string) {
+	logger := plog.WithFields(log.Fields{
+		"poolid": poolID,
+		"hostid": hostID,
+	})
+
+	// changes may have been missed while the connection was down
+	c.Purge()
+
+	done := make(chan struct{})
+	defer func() { close(done) }()
+	for {
+		_, poolEv, err := conn.ExistsW("/", done)
+		if err != nil {
+			logger.WithError(err).Warn("Could not watch the pool for changes, cached records will expire instead")
+			return
+		}
+		_, hostEv, err := conn.ExistsW(path.Join("/hosts", hostID), done)
+		if err != nil {
+			logger.WithError(err).Warn("Could not watch the host for changes, cached records will expire instead")
+			return
+		}
+
+		select {
+		case <-poolEv:
+			logger.Debug("Pool changed, dropping cached record")
+			c.InvalidatePool(poolID)
+		case <-hostEv:
+			logger.Debug("Host changed, dropping cached record")
+			c.InvalidateHost(hostID)
+		case <-cancel:
+			return
+		}
+
+		close(done)
+		done = make(chan struct{})
+	}
+}
+
+func (c *HostPoolCache) get(records map[string]cachedRecord, id string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	record, ok := records[id]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(record.expires) {
+		delete(records, id)
+		return nil, false
+	}
+	return record.value, true
+}
+
+func (c *HostPoolCache) set(records map[string]cachedRecord, id string, value interface{}) {
+	c.mu.Lock()
+	records[id] = cachedRecord{value: value, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+}
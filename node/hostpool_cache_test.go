@@ -0,0 +1,124 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package node
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+)
+
+type testLookups struct {
+	pools, hosts int // lookups made against the master
+	err          error
+}
+
+func (t *testLookups) cache(ttl time.Duration) *HostPoolCache {
+	return &HostPoolCache{
+		ttl:   ttl,
+		pools: make(map[string]cachedRecord),
+		hosts: make(map[string]cachedRecord),
+		getPool: func(poolID string) (*pool.ResourcePool, error) {
+			t.pools++
+			if t.err != nil {
+				return nil, t.err
+			}
+			return &pool.ResourcePool{ID: poolID}, nil
+		},
+		getHost: func(hostID string) (*host.Host, error) {
+			t.hosts++
+			if t.err != nil {
+				return nil, t.err
+			}
+			return &host.Host{ID: hostID, PoolID: "default"}, nil
+		},
+	}
+}
+
+func TestHostPoolCacheHit(t *testing.T) {
+	lookups := &testLookups{}
+	c := lookups.cache(time.Minute)
+	for i := 0; i < 3; i++ {
+		p, err := c.GetResourcePool("default")
+		if err != nil || p.ID != "default" {
+			t.Fatalf("Expected pool default, got %+v (%v)", p, err)
+		}
+		h, err := c.GetHost("host1")
+		if err != nil || h.ID != "host1" {
+			t.Fatalf("Expected host host1, got %+v (%v)", h, err)
+		}
+	}
+	if lookups.pools != 1 || lookups.hosts != 1 {
+		t.Errorf("Expected one lookup of each record, got %d pool and %d host lookups", lookups.pools, lookups.hosts)
+	}
+}
+
+func TestHostPoolCacheExpired(t *testing.T) {
+	lookups := &testLookups{}
+	c := lookups.cache(-time.Second)
+	c.GetResourcePool("default")
+	c.GetResourcePool("default")
+	if lookups.pools != 2 {
+		t.Errorf("Expected expired pool to be looked up again, got %d lookups", lookups.pools)
+	}
+}
+
+func TestHostPoolCacheInvalidate(t *testing.T) {
+	lookups := &testLookups{}
+	c := lookups.cache(time.Minute)
+	c.GetResourcePool("default")
+	c.GetResourcePool("other")
+	c.GetHost("host1")
+
+	c.InvalidatePool("default")
+	c.GetResourcePool("default")
+	c.GetResourcePool("other")
+	if lookups.pools != 3 {
+		t.Errorf("Expected only the invalidated pool to be looked up again, got %d lookups", lookups.pools)
+	}
+
+	c.InvalidateHost("host1")
+	c.GetHost("host1")
+	if lookups.hosts != 2 {
+		t.Errorf("Expected invalidated host to be looked up again, got %d lookups", lookups.hosts)
+	}
+
+	c.Purge()
+	c.GetResourcePool("other")
+	c.GetHost("host1")
+	if lookups.pools != 4 || lookups.hosts != 3 {
+		t.Errorf("Expected purged records to be looked up again, got %d pool and %d host lookups", lookups.pools, lookups.hosts)
+	}
+}
+
+func TestHostPoolCacheError(t *testing.T) {
+	lookups := &testLookups{err: errors.New("master unavailable")}
+	c := lookups.cache(time.Minute)
+	if _, err := c.GetResourcePool("default"); err != lookups.err {
+		t.Fatalf("Expected error %s, got %v", lookups.err, err)
+	}
+
+	lookups.err = nil
+	if p, err := c.GetResourcePool("default"); err != nil || p == nil {
+		t.Fatalf("Expected pool after a failed lookup, got %+v (%v)", p, err)
+	}
+	if lookups.pools != 2 {
+		t.Errorf("Expected failed lookup not to be cached, got %d lookups", lookups.pools)
+	}
+}
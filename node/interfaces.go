@@ -26,6 +26,7 @@ import (
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/applicationendpoint"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/rpc/master"
 )
 
@@ -107,6 +108,14 @@ type EvaluateServiceResponse struct {
 	TenantID string
 }
 
+// WaitHealthChecksRequest is the request to wait for the settings of an
+// instance's health checks to change
+type WaitHealthChecksRequest struct {
+	ServiceID    string
+	HealthChecks map[string]health.HealthCheck // The health checks run by the instance
+	Timeout      time.Duration
+}
+
 // The API for a service proxy.
 type LoadBalancer interface {
 	// SendLogMessage allows the proxy to send messages/logs to the master (to be displayed on the serviced master)
@@ -131,6 +140,11 @@ type LoadBalancer interface {
 	// GetEvaluatedService returns a service where an evaluation has been executed against all templated properties.
 	GetEvaluatedService(request EvaluateServiceRequest, response *EvaluateServiceResponse) error
 
+	// WaitHealthChecks blocks until the interval, timeout, tolerance, or
+	// failure threshold of any of the instance's health checks changes, or
+	// the timeout expires, and returns the updated health checks.
+	WaitHealthChecks(request WaitHealthChecksRequest, response *map[string]health.HealthCheck) error
+
 	// Ping waits for the specified time then returns the server time
 	Ping(waitFor time.Duration, timestamp *time.Time) error
 }
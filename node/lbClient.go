@@ -15,9 +15,9 @@ package node
 
 import (
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/rpc/rpcutils"
-	"github.com/zenoss/glog"
 
 	"time"
 )
@@ -49,67 +49,74 @@ func (a *LBClient) Close() error {
 
 // Ping waits for the specified time then returns the server time
 func (a *LBClient) Ping(waitFor time.Duration, timestamp *time.Time) error {
-	glog.V(4).Infof("ControlCenterAgent.Ping()")
+	plog.Debugf("ControlCenterAgent.Ping()")
 	return a.rpcClient.Call("ControlCenterAgent.Ping", waitFor, timestamp, 0)
 }
 
 // SendLogMessage simply outputs the ServiceLogInfo on the serviced master
 func (a *LBClient) SendLogMessage(serviceLogInfo ServiceLogInfo, _ *struct{}) error {
-	glog.V(4).Infof("ControlCenterAgent.SendLogMessage()")
+	plog.Debugf("ControlCenterAgent.SendLogMessage()")
 	return a.rpcClient.Call("ControlCenterAgent.SendLogMessage", serviceLogInfo, nil, 0)
 }
 
 // GetISvcEndpoints returns a list of controlplane endpoints for the given service endpoint request.
 func (a *LBClient) GetISvcEndpoints(serviceId string, endpoints *map[string][]applicationendpoint.ApplicationEndpoint) error {
-	glog.V(4).Infof("ControlCenterAgent.GetISvcEndpoints()")
+	plog.Debugf("ControlCenterAgent.GetISvcEndpoints()")
 	return a.rpcClient.Call("ControlCenterAgent.GetISvcEndpoints", serviceId, endpoints, 0)
 }
 
 // GetEvaluatedService returns a service where an evaluation has been executed against all templated properties.
 func (a *LBClient) GetEvaluatedService(request EvaluateServiceRequest, response *EvaluateServiceResponse) error {
-	glog.V(4).Infof("ControlCenterAgent.GetEvaluatedService()")
+	plog.Debugf("ControlCenterAgent.GetEvaluatedService()")
 	return a.rpcClient.Call("ControlCenterAgent.GetEvaluatedService", request, response, 0)
 }
 
+// WaitHealthChecks blocks until the settings of the instance's health checks
+// change or the timeout expires.
+func (a *LBClient) WaitHealthChecks(request WaitHealthChecksRequest, response *map[string]health.HealthCheck) error {
+	plog.Debugf("ControlCenterAgent.WaitHealthChecks()")
+	return a.rpcClient.Call("ControlCenterAgent.WaitHealthChecks", request, response, 0)
+}
+
 // GetProxySnapshotQuiece blocks until there is a snapshot request to the service
 func (a *LBClient) GetProxySnapshotQuiece(serviceId string, snapshotId *string) error {
-	glog.V(4).Infof("ControlCenterAgent.GetProxySnapshotQuiece()")
+	plog.Debugf("ControlCenterAgent.GetProxySnapshotQuiece()")
 	return a.rpcClient.Call("ControlCenterAgent.GetProxySnapshotQuiece", serviceId, snapshotId, 0)
 }
 
 // AckProxySnapshotQuiece is called by clients when the snapshot command has
 // shown the service is quieced; the agent returns a response when the snapshot is complete
 func (a *LBClient) AckProxySnapshotQuiece(snapshotId string, unused *interface{}) error {
-	glog.V(4).Infof("ControlCenterAgent.AckProxySnapshotQuiece()")
+	plog.Debugf("ControlCenterAgent.AckProxySnapshotQuiece()")
 	return a.rpcClient.Call("ControlCenterAgent.AckProxySnapshotQuiece", snapshotId, unused, 0)
 }
 
 // ReportHealthStatus stores a health check result.
 func (a *LBClient) ReportHealthStatus(req master.HealthStatusRequest, unused *int) error {
-	glog.V(4).Infof("ControlCenterAgent.ReportHealthStatus()")
+	plog.Debugf("ControlCenterAgent.ReportHealthStatus()")
 	return a.rpcClient.Call("ControlCenterAgent.ReportHealthStatus", req, unused, 0)
 }
 
 // ReportInstanceDead removes health check results for an instance.
 func (a *LBClient) ReportInstanceDead(req master.ServiceInstanceRequest, unused *int) error {
-	glog.V(4).Infof("ControlCenterAgent.ReportInstanceDead()")
+	plog.Debugf("ControlCenterAgent.ReportInstanceDead()")
 	return a.rpcClient.Call("ControlCenterAgent.ReportInstanceDead", req, unused, 0)
 }
 
 // GetHostID returns the agent's host id
 func (a *LBClient) GetHostID(hostID *string) error {
-	glog.V(4).Infof("ControlCenterAgent.GetHostID()")
+	plog.Debugf("ControlCenterAgent.GetHostID()")
 	return a.rpcClient.Call("ControlCenterAgent.GetHostID", "na", hostID, 0)
 }
 
 // GetZkInfo returns the agent's zookeeper connection string
 func (a *LBClient) GetZkInfo(zkInfo *ZkInfo) error {
-	glog.V(4).Infof("ControlCenterAgent.GetZkInfo()")
+	plog.Debugf("ControlCenterAgent.GetZkInfo()")
 	return a.rpcClient.Call("ControlCenterAgent.GetZkInfo", "na", zkInfo, 0)
 }
 
 // GetServiceBindMounts returns the service
 func (a *LBClient) GetServiceBindMounts(serviceID string, bindmounts *map[string]string) error {
-	glog.V(4).Infof("ControlCenterAgent.GetServiceBindMounts(serviceID:%s)", serviceID)
+	plog.Debugf("ControlCenterAgent.GetServiceBindMounts(serviceID:%s)", serviceID)
 	return a.rpcClient.Call("ControlCenterAgent.GetServiceBindMounts", serviceID, bindmounts, 0)
 }
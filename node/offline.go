@@ -0,0 +1,398 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/commons/docker"
+	coordclient "github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// offlineRetry is how long the agent waits before it retries starting an
+// instance while it is offline
+const offlineRetry = 10 * time.Second
+
+// cachedInstance is an instance assigned to this host, with everything the
+// agent needs to restart it without the master
+type cachedInstance struct {
+	ServiceID    string
+	InstanceID   int
+	DesiredState service.DesiredState
+	State        zkservice.ServiceState
+	Service      *service.Service
+	TenantID     string
+	Exits        []service.InstanceExit // exits while the host was offline
+}
+
+// key returns the name of the container of the instance
+func (inst cachedInstance) key() string {
+	return fmt.Sprintf("%s-%d", inst.ServiceID, inst.InstanceID)
+}
+
+// instanceCache keeps a local copy of the instances assigned to the host on
+// disk, so that the agent can keep running them while it is disconnected from
+// the master.
+type instanceCache struct {
+	mu        sync.Mutex
+	path      string
+	instances map[string]cachedInstance
+}
+
+// newInstanceCache loads the instance cache at the given path
+func newInstanceCache(filename string) *instanceCache {
+	c := &instanceCache{
+		path:      filename,
+		instances: make(map[string]cachedInstance),
+	}
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return c
+	} else if err != nil {
+		plog.WithField("path", filename).WithError(err).Warn("Could not read the instance cache")
+		return c
+	}
+	if err := json.Unmarshal(data, &c.instances); err != nil {
+		plog.WithField("path", filename).WithError(err).Warn("Could not load the instance cache")
+		c.instances = make(map[string]cachedInstance)
+	}
+	return c
+}
+
+// get returns the cached instance
+func (c *instanceCache) get(serviceID string, instanceID int) (cachedInstance, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	inst, ok := c.instances[cachedInstance{ServiceID: serviceID, InstanceID: instanceID}.key()]
+	return inst, ok
+}
+
+// list returns all of the cached instances
+func (c *instanceCache) list() []cachedInstance {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	insts := make([]cachedInstance, 0, len(c.instances))
+	for _, inst := range c.instances {
+		insts = append(insts, inst)
+	}
+	return insts
+}
+
+// set adds or replaces an instance in the cache
+func (c *instanceCache) set(inst cachedInstance) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.instances[inst.key()] = inst
+	c.save()
+}
+
+// setDesiredState updates the desired state of a cached instance
+func (c *instanceCache) setDesiredState(serviceID string, instanceID int, desiredState service.DesiredState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := cachedInstance{ServiceID: serviceID, InstanceID: instanceID}.key()
+	if inst, ok := c.instances[key]; ok {
+		inst.DesiredState = desiredState
+		c.instances[key] = inst
+		c.save()
+	}
+}
+
+// remove deletes an instance from the cache
+func (c *instanceCache) remove(serviceID string, instanceID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := cachedInstance{ServiceID: serviceID, InstanceID: instanceID}.key()
+	if _, ok := c.instances[key]; ok {
+		delete(c.instances, key)
+		c.save()
+	}
+}
+
+// save writes the cache to disk.  The caller must hold the lock.
+func (c *instanceCache) save() {
+	logger := plog.WithField("path", c.path)
+	data, err := json.Marshal(c.instances)
+	if err != nil {
+		logger.WithError(err).Warn("Could not encode the instance cache")
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		logger.WithError(err).Warn("Could not create the directory of the instance cache")
+		return
+	}
+	tmp := c.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		logger.WithError(err).Warn("Could not write the instance cache")
+		return
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		logger.WithError(err).Warn("Could not replace the instance cache")
+	}
+}
+
+// cacheAttachedInstance caches an instance that the agent attached to, if it
+// was not already cached with the same container.
+func (a *HostAgent) cacheAttachedInstance(logger *log.Entry, state *zkservice.ServiceState, ctr *docker.Container, serviceID string, instanceID int) {
+	inst, ok := a.instances.get(serviceID, instanceID)
+	if ok && inst.State.ContainerID == state.ContainerID {
+		return
+	} else if !ok {
+		svc, tenantID, err := a.serviceCache.GetEvaluatedService(serviceID, instanceID)
+		if err != nil {
+			logger.WithError(err).Warn("Could not cache instance for offline operation")
+			return
+		}
+
+		// the service only has the complete image name after it is pulled
+		cpy := *svc
+		cpy.ImageID = ctr.Config.Image
+		inst = cachedInstance{
+			ServiceID:    serviceID,
+			InstanceID:   instanceID,
+			DesiredState: service.SVCRun,
+			Service:      &cpy,
+			TenantID:     tenantID,
+		}
+	}
+	inst.State = *state
+	a.instances.set(inst)
+}
+
+// runOffline supervises the cached instances while the host is disconnected
+// from the coordinator, restarting the containers of running instances when
+// they exit.  It returns when stop is closed.
+func (a *HostAgent) runOffline(stop <-chan interface{}) {
+	var wg sync.WaitGroup
+	for _, inst := range a.instances.list() {
+		if inst.DesiredState != service.SVCRun {
+			continue
+		}
+		wg.Add(1)
+		go func(inst cachedInstance) {
+			defer wg.Done()
+			a.superviseOffline(stop, inst)
+		}(inst)
+	}
+	wg.Wait()
+}
+
+// superviseOffline keeps the container of an instance running while the host
+// is offline.
+func (a *HostAgent) superviseOffline(stop <-chan interface{}, inst cachedInstance) {
+	logger := plog.WithFields(log.Fields{
+		"serviceid":  inst.ServiceID,
+		"instanceid": inst.InstanceID,
+	})
+
+	for {
+		ev, err := a.AttachContainer(&inst.State, inst.ServiceID, inst.InstanceID)
+		if err != nil {
+			logger.WithError(err).Warn("Could not attach to container while offline")
+		} else if ev == nil {
+			var state *zkservice.ServiceState
+			state, ev, err = a.runContainer(logger, inst.TenantID, inst.Service, inst.InstanceID, inst.State.ImageUUID)
+			if err != nil {
+				logger.WithError(err).Warn("Could not restart container while offline")
+			} else {
+				inst.State = *state
+				a.instances.set(inst)
+				logger.WithField("containerid", state.ContainerID).Info("Restarted container while offline")
+			}
+		}
+
+		if ev == nil {
+			select {
+			case <-time.After(offlineRetry):
+				continue
+			case <-stop:
+				return
+			}
+		}
+
+		select {
+		case exit := <-ev:
+			logger.WithField("exitreason", exit.Reason()).Warn("Container exited while offline, restarting")
+			inst.State.Terminated = exit.Terminated
+			inst.Exits = service.AppendInstanceExit(inst.Exits, exit)
+			a.instances.set(inst)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// reconcile reconciles the cached instances after the agent connects, and
+// records what changed in the event history of the host if it had been
+// disconnected since the given time.
+func (a *HostAgent) reconcile(conn coordclient.Connection, disconnected time.Time) {
+	if disconnected.IsZero() {
+		if err := a.reconcileInstances(conn, nil); err != nil {
+			plog.WithError(err).Error("Could not reconcile instances with the master")
+		}
+		return
+	}
+
+	report := &host.ReconcileReport{Disconnected: disconnected}
+	if err := a.reconcileInstances(conn, report); err != nil {
+		plog.WithError(err).Error("Could not reconcile instances with the master")
+	}
+	report.Reconnected = time.Now()
+	event := host.Event{
+		Time:      report.Reconnected,
+		Type:      host.EventReconcile,
+		Message:   report.Summary(),
+		Reconcile: report,
+	}
+	if err := zkservice.AddHostEvent(conn, a.hostID, event); err != nil {
+		plog.WithError(err).Warn("Could not record the reconciliation report")
+		return
+	}
+	plog.WithField("summary", event.Message).Info("Reconciled instances after reconnecting")
+}
+
+// reconcileInstances brings the coordinator and the cached instances back in
+// line after the host reconnects.  The master decides which instances are
+// assigned to the host, so containers of instances that were removed or
+// rescheduled while the host was offline are stopped.  The host knows which
+// containers are running, so instances that were restarted offline keep
+// their new containers and report the exits that happened meanwhile.  If a
+// report is given, the host was offline and what changed is recorded in it,
+// and instance containers that the master does not know about are removed.
+func (a *HostAgent) reconcileInstances(conn coordclient.Connection, report *host.ReconcileReport) error {
+	for _, inst := range a.instances.list() {
+		logger := plog.WithFields(log.Fields{
+			"serviceid":  inst.ServiceID,
+			"instanceid": inst.InstanceID,
+		})
+		name := fmt.Sprintf("%s/%d", inst.ServiceID, inst.InstanceID)
+		req := zkservice.StateRequest{
+			HostID:     a.hostID,
+			ServiceID:  inst.ServiceID,
+			InstanceID: inst.InstanceID,
+		}
+
+		ok, err := conn.Exists(path.Join("/hosts", a.hostID, "instances", req.StateID()))
+		if err != nil {
+			logger.WithError(err).Debug("Could not look up instance")
+			return err
+		} else if !ok {
+			if err := a.StopContainer(inst.ServiceID, inst.InstanceID); err != nil {
+				logger.WithError(err).Warn("Could not stop container of instance that was reassigned")
+			} else {
+				logger.Info("Stopped container of instance that was reassigned while offline")
+				if report != nil {
+					report.Killed = append(report.Killed, name)
+				}
+			}
+			continue
+		}
+
+		changed := false
+		if err := zkservice.UpdateState(conn, req, func(s *zkservice.State) bool {
+			changed = s.ContainerID != inst.State.ContainerID
+			if !changed && len(inst.Exits) == 0 {
+				return false
+			}
+			exits := s.Exits
+			for _, exit := range inst.Exits {
+				exits = service.AppendInstanceExit(exits, exit)
+			}
+			if changed {
+				s.ServiceState = inst.State
+			}
+			s.Exits = exits
+			inst.State = s.ServiceState
+			return true
+		}); err != nil {
+			logger.WithError(err).Warn("Could not reconcile instance")
+			return err
+		}
+		if report != nil {
+			if len(inst.Exits) > 0 {
+				report.Restarted = append(report.Restarted, name)
+			}
+			if changed {
+				report.Adopted = append(report.Adopted, name)
+			}
+		}
+		inst.Exits = nil
+		a.instances.set(inst)
+		logger.WithField("containerid", inst.State.ContainerID).Debug("Reconciled instance")
+	}
+
+	if report != nil {
+		return a.removeOrphanedContainers(conn, report)
+	}
+	return nil
+}
+
+// removeOrphanedContainers removes the running instance containers on the host
+// that are not assigned to it.
+func (a *HostAgent) removeOrphanedContainers(conn coordclient.Connection, report *host.ReconcileReport) error {
+	reqs, err := zkservice.GetHostStateIDs(conn, "", a.hostID)
+	if err != nil {
+		return err
+	}
+	assigned := make(map[string]struct{})
+	for _, req := range reqs {
+		assigned[fmt.Sprintf("%s-%d", req.ServiceID, req.InstanceID)] = struct{}{}
+	}
+
+	ctrs, err := docker.Containers()
+	if err != nil {
+		return err
+	}
+	for _, ctr := range ctrs {
+		name := strings.TrimPrefix(ctr.Name, "/")
+		if _, ok := assigned[name]; ok || !ctr.IsRunning() || !isInstanceContainer(ctr) {
+			continue
+		}
+		logger := plog.WithFields(log.Fields{
+			"containername": name,
+			"containerid":   ctr.ID,
+		})
+		ctr.Kill()
+		if err := ctr.Delete(true); err != nil {
+			logger.WithError(err).Warn("Could not remove orphaned container")
+			continue
+		}
+		logger.Info("Removed orphaned container")
+		report.Removed = append(report.Removed, fmt.Sprintf("%s (%.12s)", name, ctr.ID))
+	}
+	return nil
+}
+
+// isInstanceContainer returns true if the container runs a service instance,
+// rather than a service shell or a container that serviced does not manage.
+func isInstanceContainer(ctr *docker.Container) bool {
+	for _, env := range ctr.Config.Env {
+		if env == "SERVICED_IS_SERVICE_SHELL=false" {
+			return true
+		}
+	}
+	return false
+}
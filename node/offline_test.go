@@ -0,0 +1,75 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/control-center/serviced/domain/service"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstanceCache(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "instances")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "var", "instances.json")
+
+	// nothing cached
+	cache := newInstanceCache(filename)
+	assert.Empty(cache.list())
+
+	cache.set(cachedInstance{
+		ServiceID:    "svc",
+		InstanceID:   1,
+		DesiredState: service.SVCRun,
+		State:        zkservice.ServiceState{ContainerID: "ctr1", ImageUUID: "uuid"},
+		Service:      &service.Service{ID: "svc", ImageID: "localhost:5000/tenant/image:latest"},
+		TenantID:     "tenant",
+	})
+	cache.set(cachedInstance{ServiceID: "svc", InstanceID: 2, DesiredState: service.SVCRun})
+	cache.setDesiredState("svc", 2, service.SVCPause)
+	cache.remove("svc", 3)
+
+	// reload from disk
+	cache = newInstanceCache(filename)
+	assert.Len(cache.list(), 2)
+	inst, ok := cache.get("svc", 1)
+	assert.True(ok)
+	assert.Equal("ctr1", inst.State.ContainerID)
+	assert.Equal("uuid", inst.State.ImageUUID)
+	assert.Equal("localhost:5000/tenant/image:latest", inst.Service.ImageID)
+	assert.Equal("tenant", inst.TenantID)
+	inst, ok = cache.get("svc", 2)
+	assert.True(ok)
+	assert.Equal(service.SVCPause, inst.DesiredState)
+
+	cache.remove("svc", 2)
+	cache = newInstanceCache(filename)
+	_, ok = cache.get("svc", 2)
+	assert.False(ok)
+	assert.Len(cache.list(), 1)
+
+	// a corrupt cache is ignored
+	assert.NoError(ioutil.WriteFile(filename, []byte("{"), 0600))
+	cache = newInstanceCache(filename)
+	assert.Empty(cache.list())
+}
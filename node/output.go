@@ -0,0 +1,149 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/commons/circular"
+	"github.com/control-center/serviced/commons/docker"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// outputTimeout is how long an exited container is kept while its output is
+// buffered
+const outputTimeout = 10 * time.Second
+
+// exitOutputLines is the number of lines of container output that are kept
+// with the exit of an instance
+const exitOutputLines = 20
+
+// outputFile returns the path of the buffer for the output of the running
+// container of a service instance
+func outputFile(dir, serviceID string, instanceID int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%d.log", serviceID, instanceID))
+}
+
+// previousOutputFile returns the path of the output of the most recently
+// exited container of a service instance
+func previousOutputFile(dir, serviceID string, instanceID int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%d.previous", serviceID, instanceID))
+}
+
+// bufferOutput copies the stdout and stderr of a container, starting at
+// since, into a ring buffer until the container exits.  The buffer is then
+// saved as the previous output of the service instance, so that it can be
+// retrieved after the container is deleted.  Closes done when finished.
+func (a *HostAgent) bufferOutput(logger *log.Entry, ctr *docker.Container, serviceID string, instanceID int, since time.Time, done chan<- struct{}) {
+	defer close(done)
+	if a.outputSize <= 0 {
+		return
+	}
+	logger = logger.WithField("outputpath", a.outputPath)
+
+	// the container may be re-attached while its output is still buffered
+	if !a.followOutput(ctr.ID) {
+		logger.Debug("Container output is already buffered")
+		return
+	}
+	defer a.unfollowOutput(ctr.ID)
+
+	if err := os.MkdirAll(a.outputPath, 0755); err != nil {
+		logger.WithError(err).Warn("Could not create directory for container output")
+		return
+	}
+	filename := outputFile(a.outputPath, serviceID, instanceID)
+	buf, err := circular.NewFileBuffer(filename, a.outputSize)
+	if err != nil {
+		logger.WithError(err).Warn("Could not buffer container output")
+		return
+	}
+	if err := ctr.FollowOutput(since, buf, buf); err != nil {
+		logger.WithError(err).Debug("Stopped following container output")
+	}
+	buf.Close()
+
+	// the agent may have lost its connection to docker
+	if ctr.IsRunning() {
+		return
+	}
+	if err := savePreviousOutput(a.outputPath, serviceID, instanceID); err != nil {
+		logger.WithError(err).Warn("Could not save the output of the exited container")
+		return
+	}
+	logger.Debug("Saved the output of the exited container")
+}
+
+// followOutput marks the output of a container as buffered.  Returns false if
+// it is already buffered.
+func (a *HostAgent) followOutput(containerID string) bool {
+	a.outputMu.Lock()
+	defer a.outputMu.Unlock()
+	if a.following == nil {
+		a.following = make(map[string]struct{})
+	}
+	if _, ok := a.following[containerID]; ok {
+		return false
+	}
+	a.following[containerID] = struct{}{}
+	return true
+}
+
+// unfollowOutput marks the output of a container as no longer buffered
+func (a *HostAgent) unfollowOutput(containerID string) {
+	a.outputMu.Lock()
+	defer a.outputMu.Unlock()
+	delete(a.following, containerID)
+}
+
+// savePreviousOutput replaces the previous output of a service instance with
+// the output buffered for its exited container
+func savePreviousOutput(dir, serviceID string, instanceID int) error {
+	filename := outputFile(dir, serviceID, instanceID)
+	data, err := circular.ReadFileBuffer(filename)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(previousOutputFile(dir, serviceID, instanceID), data, 0644); err != nil {
+		return err
+	}
+	return circular.RemoveFileBuffer(filename)
+}
+
+// ReadPreviousOutput returns the output of the most recently exited container
+// of a service instance, or nil if no output was buffered for the instance.
+func ReadPreviousOutput(dir, serviceID string, instanceID int) (*service.InstanceOutput, error) {
+	filename := previousOutputFile(dir, serviceID, instanceID)
+	info, err := os.Stat(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &service.InstanceOutput{
+		ServiceID:  serviceID,
+		InstanceID: instanceID,
+		Exited:     info.ModTime(),
+		Output:     data,
+	}, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/control-center/serviced/commons/circular"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviousOutput(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "output")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// nothing buffered
+	output, err := ReadPreviousOutput(dir, "svc", 1)
+	assert.NoError(err)
+	assert.Nil(output)
+
+	buf, err := circular.NewFileBuffer(outputFile(dir, "svc", 1), 1024)
+	assert.NoError(err)
+	buf.Write([]byte("starting\n"))
+	buf.Write([]byte("panic: oops\n"))
+	buf.Close()
+
+	// the running container has no previous output
+	output, err = ReadPreviousOutput(dir, "svc", 1)
+	assert.NoError(err)
+	assert.Nil(output)
+
+	// the exited container's output becomes the previous output
+	assert.NoError(savePreviousOutput(dir, "svc", 1))
+	output, err = ReadPreviousOutput(dir, "svc", 1)
+	assert.NoError(err)
+	if assert.NotNil(output) {
+		assert.Equal("svc", output.ServiceID)
+		assert.Equal(1, output.InstanceID)
+		assert.Equal("starting\npanic: oops\n", string(output.Output))
+		assert.False(output.Exited.IsZero())
+	}
+	_, err = os.Stat(outputFile(dir, "svc", 1))
+	assert.True(os.IsNotExist(err))
+
+	// other instances are separate
+	output, err = ReadPreviousOutput(dir, "svc", 0)
+	assert.NoError(err)
+	assert.Nil(output)
+}
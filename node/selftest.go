@@ -0,0 +1,254 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/storage"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/rpc/master"
+	"github.com/control-center/serviced/utils"
+	dockerclient "github.com/fsouza/go-dockerclient"
+)
+
+// Names of the checks of a host's self-test
+const (
+	SelfTestDocker     = "docker"
+	SelfTestRegistry   = "registry"
+	SelfTestDFS        = "dfs"
+	SelfTestMux        = "mux"
+	SelfTestZooKeeper  = "zookeeper"
+	SelfTestClock      = "clock"
+	SelfTestMasterAuth = "master-auth"
+)
+
+// selfTestTimeout is how long each network check of the self-test may take
+var selfTestTimeout = 5 * time.Second
+
+// SelfTestConfig describes what the self-test of a host validates
+type SelfTestConfig struct {
+	HostID         string
+	DockerRegistry string        // host:port of the docker registry
+	VolumesPath    string        // where the application volumes are mounted
+	MuxPort        int           // port of the mux on this host
+	Zookeepers     []string      // host:port of the zookeeper servers
+	MasterAddress  string        // host:port of the master's rpc server
+	MaxClockSkew   time.Duration // how far the clock may differ from the master's
+}
+
+// selfTestMaster is the part of the master client used by the self-test
+type selfTestMaster interface {
+	AuthenticateHost(hostID string) (string, int64, error)
+	GetServerTime() (time.Time, error)
+	Close() error
+}
+
+// selfTest runs the checks of a host's self-test
+type selfTest struct {
+	cfg        SelfTestConfig
+	pingDocker func() error
+	dial       func(address string, timeout time.Duration) (net.Conn, error)
+	listen     func(address string) (net.Listener, error)
+	get        func(url string) (*http.Response, error)
+	mounts     func() ([]utils.MountInfo, error)
+	probe      func(path string, timeout time.Duration) (time.Duration, error)
+	master     func(address string) (selfTestMaster, error)
+}
+
+func newSelfTest(cfg SelfTestConfig) *selfTest {
+	return &selfTest{
+		cfg: cfg,
+		pingDocker: func() error {
+			client, err := dockerclient.NewClient("unix:///var/run/docker.sock")
+			if err != nil {
+				return err
+			}
+			return client.Ping()
+		},
+		dial: func(address string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("tcp", address, timeout)
+		},
+		listen: func(address string) (net.Listener, error) {
+			return net.Listen("tcp", address)
+		},
+		get:    (&http.Client{Timeout: selfTestTimeout}).Get,
+		mounts: utils.GetDefaultMountProc().ListAll,
+		probe:  newMountProber().probe,
+		master: func(address string) (selfTestMaster, error) {
+			return master.NewClient(address)
+		},
+	}
+}
+
+// RunSelfTest validates that this host can reach the services it needs to
+// run as a delegate.
+func RunSelfTest(cfg SelfTestConfig) host.SelfTestReport {
+	return newSelfTest(cfg).run(time.Now())
+}
+
+func (t *selfTest) run(now time.Time) host.SelfTestReport {
+	report := host.SelfTestReport{HostID: t.cfg.HostID, Ran: now.UTC()}
+	add := func(name string, check func() (string, error)) {
+		detail, err := check()
+		if err != nil {
+			detail = err.Error()
+		}
+		report.Results = append(report.Results, host.SelfTestResult{Name: name, Passed: err == nil, Detail: detail})
+	}
+
+	add(SelfTestDocker, t.checkDocker)
+	add(SelfTestRegistry, t.checkRegistry)
+	add(SelfTestDFS, t.checkDFS)
+	add(SelfTestMux, t.checkMux)
+	add(SelfTestZooKeeper, t.checkZooKeeper)
+
+	client, err := t.master(t.cfg.MasterAddress)
+	if err != nil {
+		failed := func() (string, error) {
+			return "", fmt.Errorf("could not connect to the master at %s: %s", t.cfg.MasterAddress, err)
+		}
+		add(SelfTestMasterAuth, failed)
+		add(SelfTestClock, failed)
+		return report
+	}
+	defer client.Close()
+	add(SelfTestMasterAuth, func() (string, error) { return t.checkMasterAuth(client) })
+	add(SelfTestClock, func() (string, error) { return t.checkClock(client) })
+	return report
+}
+
+// checkDocker verifies that the docker daemon responds
+func (t *selfTest) checkDocker() (string, error) {
+	if err := t.pingDocker(); err != nil {
+		return "", fmt.Errorf("could not reach the docker daemon: %s", err)
+	}
+	return "docker daemon responded", nil
+}
+
+// checkRegistry verifies that the docker registry answers http requests
+func (t *selfTest) checkRegistry() (string, error) {
+	resp, err := t.get(fmt.Sprintf("http://%s/v2/", t.cfg.DockerRegistry))
+	if err != nil {
+		return "", fmt.Errorf("could not reach the registry at %s: %s", t.cfg.DockerRegistry, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return "", fmt.Errorf("registry at %s responded with %s", t.cfg.DockerRegistry, resp.Status)
+	}
+	return fmt.Sprintf("registry at %s responded with %s", t.cfg.DockerRegistry, resp.Status), nil
+}
+
+// checkDFS verifies that the path of the application volumes exists and that
+// the shared storage mounted under it responds
+func (t *selfTest) checkDFS() (string, error) {
+	if _, err := t.probe(t.cfg.VolumesPath, selfTestTimeout); err != nil {
+		return "", fmt.Errorf("could not access %s: %s", t.cfg.VolumesPath, err)
+	}
+	mounts, err := t.mounts()
+	if err != nil {
+		return "", fmt.Errorf("could not list mounts: %s", err)
+	}
+	count := 0
+	for _, info := range mounts {
+		if !storage.IsSharedFSType(info.FSType) {
+			continue
+		}
+		volume, err := filepath.Rel(t.cfg.VolumesPath, info.MountPoint)
+		if err != nil || volume == "." || strings.HasPrefix(volume, "..") {
+			continue
+		}
+		if _, err := t.probe(info.MountPoint, selfTestTimeout); err != nil {
+			return "", fmt.Errorf("shared volume for application %s is unavailable: %s", volume, err)
+		}
+		count++
+	}
+	return fmt.Sprintf("%s is accessible with %d shared volumes mounted", t.cfg.VolumesPath, count), nil
+}
+
+// checkMux verifies that the mux is listening on its port, or that the port is
+// free for the mux to listen on
+func (t *selfTest) checkMux() (string, error) {
+	if conn, err := t.dial(fmt.Sprintf("127.0.0.1:%d", t.cfg.MuxPort), selfTestTimeout); err == nil {
+		conn.Close()
+		return fmt.Sprintf("mux is listening on port %d", t.cfg.MuxPort), nil
+	}
+	l, err := t.listen(fmt.Sprintf(":%d", t.cfg.MuxPort))
+	if err != nil {
+		return "", fmt.Errorf("mux port %d is unavailable: %s", t.cfg.MuxPort, err)
+	}
+	l.Close()
+	return fmt.Sprintf("mux port %d is free", t.cfg.MuxPort), nil
+}
+
+// checkZooKeeper verifies that at least one zookeeper server is reachable
+func (t *selfTest) checkZooKeeper() (string, error) {
+	servers := t.cfg.Zookeepers
+	if len(servers) == 0 {
+		servers = []string{"127.0.0.1:2181"}
+	}
+	var reached, failed []string
+	for _, server := range servers {
+		conn, err := t.dial(server, selfTestTimeout)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", server, err))
+			continue
+		}
+		conn.Close()
+		reached = append(reached, server)
+	}
+	if len(reached) == 0 {
+		return "", fmt.Errorf("could not reach any zookeeper server: %s", strings.Join(failed, ", "))
+	}
+	detail := fmt.Sprintf("reached %s", strings.Join(reached, ", "))
+	if len(failed) > 0 {
+		detail += fmt.Sprintf("; could not reach %s", strings.Join(failed, ", "))
+	}
+	return detail, nil
+}
+
+// checkMasterAuth verifies that the master issues this host a token
+func (t *selfTest) checkMasterAuth(client selfTestMaster) (string, error) {
+	token, _, err := client.AuthenticateHost(t.cfg.HostID)
+	if err != nil {
+		return "", fmt.Errorf("master at %s did not authenticate host %s: %s", t.cfg.MasterAddress, t.cfg.HostID, err)
+	} else if token == "" {
+		return "", fmt.Errorf("master at %s issued host %s an empty token", t.cfg.MasterAddress, t.cfg.HostID)
+	}
+	return fmt.Sprintf("master at %s authenticated host %s", t.cfg.MasterAddress, t.cfg.HostID), nil
+}
+
+// checkClock verifies that the clock of this host is in sync with the master's
+func (t *selfTest) checkClock(client selfTestMaster) (string, error) {
+	start := time.Now()
+	masterTime, err := client.GetServerTime()
+	if err != nil {
+		return "", fmt.Errorf("could not get the time on the master: %s", err)
+	}
+	end := time.Now()
+	skew := masterTime.Sub(start.Add(end.Sub(start) / 2))
+	if skew < 0 {
+		skew = -skew
+	}
+	skew = skew - skew%time.Millisecond
+	if skew > t.cfg.MaxClockSkew {
+		return "", fmt.Errorf("clock differs from the master's by %s, more than %s", skew, t.cfg.MaxClockSkew)
+	}
+	return fmt.Sprintf("clock differs from the master's by %s", skew), nil
+}
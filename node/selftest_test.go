@@ -0,0 +1,198 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package node
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/utils"
+)
+
+type testSelfTestMaster struct {
+	token string
+	err   error
+	now   time.Time
+}
+
+func (m *testSelfTestMaster) AuthenticateHost(hostID string) (string, int64, error) {
+	return m.token, 0, m.err
+}
+
+func (m *testSelfTestMaster) GetServerTime() (time.Time, error) {
+	return m.now, m.err
+}
+
+func (m *testSelfTestMaster) Close() error {
+	return nil
+}
+
+type testConn struct {
+	net.Conn
+}
+
+func (testConn) Close() error {
+	return nil
+}
+
+type testListener struct {
+	net.Listener
+}
+
+func (testListener) Close() error {
+	return nil
+}
+
+// testSelfTest returns a self-test where every check passes
+func testSelfTest(m *testSelfTestMaster) *selfTest {
+	return &selfTest{
+		cfg: SelfTestConfig{
+			HostID:         "deadb10f",
+			DockerRegistry: "localhost:5000",
+			VolumesPath:    "/opt/serviced/var/volumes",
+			MuxPort:        22250,
+			Zookeepers:     []string{"10.0.0.1:2181", "10.0.0.2:2181"},
+			MasterAddress:  "10.0.0.1:4979",
+			MaxClockSkew:   time.Second,
+		},
+		pingDocker: func() error { return nil },
+		dial: func(address string, timeout time.Duration) (net.Conn, error) {
+			return testConn{}, nil
+		},
+		listen: func(address string) (net.Listener, error) {
+			return testListener{}, nil
+		},
+		get: func(url string) (*http.Response, error) {
+			return &http.Response{Status: "200 OK", StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		},
+		mounts: func() ([]utils.MountInfo, error) {
+			return []utils.MountInfo{
+				{Device: "10.0.0.1:/serviced_volumes_v2/tenant1", MountPoint: "/opt/serviced/var/volumes/tenant1", FSType: "nfs4"},
+				{Device: "10.0.0.1:/other", MountPoint: "/mnt/other", FSType: "nfs4"},
+			}, nil
+		},
+		probe: func(path string, timeout time.Duration) (time.Duration, error) {
+			return time.Millisecond, nil
+		},
+		master: func(address string) (selfTestMaster, error) {
+			return m, nil
+		},
+	}
+}
+
+// results returns the results of a self-test report by check
+func results(report host.SelfTestReport) map[string]host.SelfTestResult {
+	byName := make(map[string]host.SelfTestResult)
+	for _, result := range report.Results {
+		byName[result.Name] = result
+	}
+	return byName
+}
+
+func TestSelfTestPassed(t *testing.T) {
+	now := time.Now()
+	report := testSelfTest(&testSelfTestMaster{token: "token", now: now}).run(now)
+	if !report.Passed() {
+		t.Fatalf("expected self-test to pass: %+v", report.Results)
+	}
+	if report.HostID != "deadb10f" {
+		t.Errorf("expected host deadb10f, got %s", report.HostID)
+	}
+	names := []string{SelfTestDocker, SelfTestRegistry, SelfTestDFS, SelfTestMux, SelfTestZooKeeper, SelfTestMasterAuth, SelfTestClock}
+	if len(report.Results) != len(names) {
+		t.Fatalf("expected %d results, got %+v", len(names), report.Results)
+	}
+	for i, name := range names {
+		if report.Results[i].Name != name {
+			t.Errorf("expected check %d to be %s, got %s", i, name, report.Results[i].Name)
+		}
+	}
+	if detail := results(report)[SelfTestDFS].Detail; !strings.Contains(detail, "1 shared volumes") {
+		t.Errorf("expected 1 shared volume to be probed, got %q", detail)
+	}
+}
+
+func TestSelfTestFailures(t *testing.T) {
+	now := time.Now()
+	test := testSelfTest(&testSelfTestMaster{token: "", now: now.Add(time.Minute)})
+	test.pingDocker = func() error { return errors.New("docker is down") }
+	test.get = func(url string) (*http.Response, error) {
+		return &http.Response{Status: "503 Service Unavailable", StatusCode: http.StatusServiceUnavailable, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+	}
+	test.probe = func(path string, timeout time.Duration) (time.Duration, error) {
+		if path == "/opt/serviced/var/volumes/tenant1" {
+			return 0, errors.New("timed out")
+		}
+		return time.Millisecond, nil
+	}
+	test.dial = func(address string, timeout time.Duration) (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	}
+	test.listen = func(address string) (net.Listener, error) {
+		return nil, errors.New("address already in use")
+	}
+
+	report := test.run(now)
+	if report.Passed() {
+		t.Fatalf("expected self-test to fail")
+	}
+	for name, result := range results(report) {
+		if result.Passed {
+			t.Errorf("expected check %s to fail: %s", name, result.Detail)
+		} else if result.Detail == "" {
+			t.Errorf("expected check %s to explain its failure", name)
+		}
+	}
+}
+
+func TestSelfTestZooKeeperPartial(t *testing.T) {
+	test := testSelfTest(&testSelfTestMaster{token: "token", now: time.Now()})
+	test.dial = func(address string, timeout time.Duration) (net.Conn, error) {
+		if address == "10.0.0.2:2181" {
+			return nil, errors.New("connection refused")
+		}
+		return testConn{}, nil
+	}
+	result := results(test.run(time.Now()))[SelfTestZooKeeper]
+	if !result.Passed {
+		t.Fatalf("expected zookeeper check to pass with one server reachable: %s", result.Detail)
+	}
+	if !strings.Contains(result.Detail, "could not reach 10.0.0.2:2181") {
+		t.Errorf("expected the unreachable server to be reported, got %q", result.Detail)
+	}
+}
+
+func TestSelfTestMasterUnreachable(t *testing.T) {
+	test := testSelfTest(nil)
+	test.master = func(address string) (selfTestMaster, error) {
+		return nil, errors.New("connection refused")
+	}
+	byName := results(test.run(time.Now()))
+	for _, name := range []string{SelfTestMasterAuth, SelfTestClock} {
+		if result := byName[name]; result.Passed {
+			t.Errorf("expected check %s to fail", name)
+		}
+	}
+	if result := byName[SelfTestDocker]; !result.Passed {
+		t.Errorf("expected docker check to pass: %s", result.Detail)
+	}
+}
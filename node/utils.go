@@ -21,7 +21,6 @@ package node
 import (
 	"github.com/control-center/serviced/commons/docker"
 	"github.com/control-center/serviced/coordinator/client"
-	"github.com/zenoss/glog"
 
 	"fmt"
 	"io/ioutil"
@@ -261,7 +260,7 @@ func createVolumeDir(conn client.Connection, hostPath, containerSpec, imageSpec,
 		for _, dotfile := range dotfiles {
 			_, err := os.Stat(dotfile)
 			if err == nil {
-				glog.V(2).Infof("DFS volume initialized earlier for src:%s dst:%s image:%s user:%s perm:%s", hostPath, containerSpec, imageSpec, userSpec, permissionSpec)
+				plog.Debugf("DFS volume initialized earlier for src:%s dst:%s image:%s user:%s perm:%s", hostPath, containerSpec, imageSpec, userSpec, permissionSpec)
 				return true
 			}
 		}
@@ -276,16 +275,16 @@ func createVolumeDir(conn client.Connection, hostPath, containerSpec, imageSpec,
 	zkVolumeInitLock := path.Join("/locks/volumeinit", filepath.Base(hostPath))
 	lock, err := conn.NewLock(zkVolumeInitLock)
 	if err != nil {
-		glog.Errorf("Could not initialize lock for %s: %s", zkVolumeInitLock, err)
+		plog.Errorf("Could not initialize lock for %s: %s", zkVolumeInitLock, err)
 		return err
 	}
 	if err := lock.Lock(); err != nil {
-		glog.Errorf("Could not acquire lock for %s: %s", zkVolumeInitLock, err)
+		plog.Errorf("Could not acquire lock for %s: %s", zkVolumeInitLock, err)
 		return err
 	}
 	defer func() {
 		if err := lock.Unlock(); err != nil {
-			glog.Errorf("Could not unlock %s: %s", zkVolumeInitLock, err)
+			plog.Errorf("Could not unlock %s: %s", zkVolumeInitLock, err)
 		}
 	}()
 	//double check to make sure that it wasn't initialized while waiting for loc
@@ -321,23 +320,23 @@ sync
 		if err == nil {
 			duration := time.Now().Sub(starttime)
 			if strings.Contains(string(output), "WARNING:") {
-				glog.Warning(string(output))
+				plog.Warning(string(output))
 			} else {
-				glog.Info(string(output))
+				plog.Info(string(output))
 			}
-			glog.Infof("DFS volume init #%d took %s for src:%s dst:%s image:%s user:%s perm:%s", i, duration, hostPath, containerSpec, imageSpec, userSpec, permissionSpec)
+			plog.Infof("DFS volume init #%d took %s for src:%s dst:%s image:%s user:%s perm:%s", i, duration, hostPath, containerSpec, imageSpec, userSpec, permissionSpec)
 
 			if e := ioutil.WriteFile(dotfileHostPath, []byte(""), 0664); e != nil {
-				glog.Errorf("unable to create DFS volume initialized dotfile %s: %s", dotfileHostPath, e)
+				plog.Errorf("unable to create DFS volume initialized dotfile %s: %s", dotfileHostPath, e)
 				return e
 			}
 			return nil
 		}
 		time.Sleep(time.Second)
-		glog.Warningf("retrying due to error creating DFS volume %+v: %s", hostPath, string(output))
+		plog.Warningf("retrying due to error creating DFS volume %+v: %s", hostPath, string(output))
 	}
 
-	glog.Errorf("could not create DFS volume %+v: %s", hostPath, string(output))
+	plog.Errorf("could not create DFS volume %+v: %s", hostPath, string(output))
 	return err
 }
 
@@ -32,8 +32,6 @@ import (
 	"strings"
 	"syscall"
 
-	"github.com/zenoss/glog"
-
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/zzk"
 	. "gopkg.in/check.v1"
@@ -136,7 +134,7 @@ func (ts *ZZKTest) TestCreateVolumeDir(t *C) {
 		// "bash", "-c", fmt.Sprintf("apt-get -y install rsync; rsync -a %s/ %s/\n", v.containerPath, containerMount),
 	}
 
-	glog.V(2).Infof("copy command: %s", copyCommand)
+	plog.Debugf("copy command: %s", copyCommand)
 	docker := exec.Command(copyCommand[0], copyCommand[1:]...)
 	if output, err := docker.CombinedOutput(); err != nil {
 		t.Fatalf("could not create host volume: %+v, %s", copyCommand, string(output))
@@ -144,7 +142,7 @@ func (ts *ZZKTest) TestCreateVolumeDir(t *C) {
 
 	// compare rsync'ed path from image against DFS volume
 	compareCmd := [...]string{"diff", "-qr", v.hostPath, expectedPath}
-	glog.V(2).Infof("compare command: %s", compareCmd)
+	plog.Debugf("compare command: %s", compareCmd)
 	docker = exec.Command(compareCmd[0], compareCmd[1:]...)
 	if output, err := docker.CombinedOutput(); err != nil {
 		t.Fatalf("could not compare paths: %+v, %s", compareCmd, string(output))
@@ -152,7 +150,7 @@ func (ts *ZZKTest) TestCreateVolumeDir(t *C) {
 
 	// compare user:group perms
 	getUidGidCmd := [...]string{"docker", "run", "--rm", v.image, "getent", "passwd", "games"}
-	glog.V(2).Infof("get command: %s", getUidGidCmd)
+	plog.Debugf("get command: %s", getUidGidCmd)
 	docker = exec.Command(getUidGidCmd[0], getUidGidCmd[1:]...)
 	if output, err := docker.CombinedOutput(); err != nil {
 		t.Fatalf("could not get uid/gid: %+v, %s", getUidGidCmd, string(output))
@@ -165,13 +165,18 @@ func (mux *TCPMux) muxConnection(conn net.Conn) {
 	svc, err := net.Dial("tcp4", address)
 	if err != nil {
 		log.Debug("Unable to dial container address. Perhaps the container is still starting?")
+		countConnectError(address)
 		conn.Close()
 		return
 	}
 
 	// Wire up the incoming connection to the one we just dialed
+	backend, closed := countConnection(address, svc)
 	quit := make(chan bool)
-	go ProxyLoop(conn, svc, quit)
+	go func() {
+		defer closed()
+		ProxyLoop(conn, backend, quit)
+	}()
 }
 
 func ProxyLoop(client net.Conn, backend net.Conn, quit chan bool) {
@@ -0,0 +1,128 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// Metrics is the registry of connections forwarded by the mux on this host,
+// keyed by container address.  Container addresses are allocated from the
+// docker bridge, so the number of addresses tracked stays bounded.
+var Metrics = metrics.NewRegistry()
+
+// Names of the metrics tracked for each container address
+const (
+	muxActive        = "active"
+	muxTotal         = "total"
+	muxConnectErrors = "connecterrors"
+	muxBytesReceived = "bytesreceived"
+	muxBytesSent     = "bytessent"
+)
+
+// ConnectionStats counts the connections the mux has forwarded to a container
+// address since serviced started
+type ConnectionStats struct {
+	Address       string
+	Active        int64 // connections currently open
+	Total         int64 // connections forwarded
+	ConnectErrors int64 // connections that could not be forwarded
+	BytesReceived int64 // bytes forwarded from clients to the container
+	BytesSent     int64 // bytes forwarded from the container to clients
+}
+
+func muxMetricName(address, metric string) string {
+	return fmt.Sprintf("mux.%s.%s", address, metric)
+}
+
+func muxCounter(address, metric string) metrics.Counter {
+	return metrics.GetOrRegisterCounter(muxMetricName(address, metric), Metrics)
+}
+
+// GetMuxStats returns the connection stats of each container address the mux
+// has forwarded to, keyed by address
+func GetMuxStats() map[string]ConnectionStats {
+	stats := make(map[string]ConnectionStats)
+	Metrics.Each(func(name string, i interface{}) {
+		counter, ok := i.(metrics.Counter)
+		if !ok || !strings.HasPrefix(name, "mux.") {
+			return
+		}
+		idx := strings.LastIndex(name, ".")
+		address, metric := name[len("mux."):idx], name[idx+1:]
+
+		s := stats[address]
+		s.Address = address
+		switch metric {
+		case muxActive:
+			s.Active = counter.Count()
+		case muxTotal:
+			s.Total = counter.Count()
+		case muxConnectErrors:
+			s.ConnectErrors = counter.Count()
+		case muxBytesReceived:
+			s.BytesReceived = counter.Count()
+		case muxBytesSent:
+			s.BytesSent = counter.Count()
+		}
+		stats[address] = s
+	})
+	return stats
+}
+
+// countConnectError records a connection that the mux could not forward to
+// the address
+func countConnectError(address string) {
+	muxCounter(address, muxConnectErrors).Inc(1)
+}
+
+// countedConn counts the bytes forwarded over a connection to a container
+type countedConn struct {
+	net.Conn
+	received metrics.Counter
+	sent     metrics.Counter
+}
+
+// Read counts the bytes sent from the container
+func (c *countedConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.sent.Inc(int64(n))
+	return n, err
+}
+
+// Write counts the bytes received by the container
+func (c *countedConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.received.Inc(int64(n))
+	return n, err
+}
+
+// countConnection marks a connection to the address as open and counts the
+// bytes forwarded over it.  The returned function marks the connection as
+// closed.
+func countConnection(address string, conn net.Conn) (net.Conn, func()) {
+	active := muxCounter(address, muxActive)
+	active.Inc(1)
+	muxCounter(address, muxTotal).Inc(1)
+	counted := &countedConn{
+		Conn:     conn,
+		received: muxCounter(address, muxBytesReceived),
+		sent:     muxCounter(address, muxBytesSent),
+	}
+	return counted, func() { active.Dec(1) }
+}
@@ -0,0 +1,60 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package proxy
+
+import (
+	"net"
+	"testing"
+)
+
+func TestMuxStats(t *testing.T) {
+	address := "172.17.0.5:8080"
+	client, server := net.Pipe()
+	defer server.Close()
+
+	conn, closed := countConnection(address, client)
+	countConnectError(address)
+
+	go func() {
+		buf := make([]byte, 5)
+		server.Read(buf)
+		server.Write([]byte("hi"))
+	}()
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Fatalf("Could not write to connection: %s", err)
+	}
+	if _, err := conn.Read(make([]byte, 2)); err != nil {
+		t.Fatalf("Could not read from connection: %s", err)
+	}
+
+	s := GetMuxStats()[address]
+	expected := ConnectionStats{
+		Address:       address,
+		Active:        1,
+		Total:         1,
+		ConnectErrors: 1,
+		BytesReceived: 5,
+		BytesSent:     2,
+	}
+	if s != expected {
+		t.Errorf("Expected %+v, got %+v", expected, s)
+	}
+
+	closed()
+	if s := GetMuxStats()[address]; s.Active != 0 || s.Total != 1 {
+		t.Errorf("Expected connection to be closed, got %+v", s)
+	}
+}
@@ -19,11 +19,16 @@ import (
 	"github.com/control-center/serviced/dfs/docker"
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/logging"
+	"github.com/control-center/serviced/node"
+	"github.com/control-center/serviced/servicedversion"
 	"github.com/control-center/serviced/zzk"
-	"github.com/zenoss/glog"
 
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"strings"
 )
 
 // NewServer returns a new AgentServer
@@ -38,7 +43,13 @@ func NewServer(staticIPs []string) *AgentServer {
 
 // AgentServer The type is the API for a serviced agent. Get the host information from an agent.
 type AgentServer struct {
-	staticIPs []string
+	staticIPs      []string
+	upgradeCommand string // shell command that installs a serviced release
+	restart        func() // restarts serviced after an upgrade
+	shellImages    shellImageCache
+	muxTLS         bool   // whether connections to the mux use TLS
+	outputPath     string // where the host agent buffers container output
+	selfTest       *node.SelfTestConfig // what the self-test of the host validates
 }
 
 //BuildHostRequest request to build a new host. IP and IPResources will be validated to ensure they exist
@@ -54,7 +65,7 @@ type BuildHostRequest struct {
 func (a *AgentServer) BuildHost(request BuildHostRequest, hostResponse *host.Host) error {
 	*hostResponse = host.Host{}
 
-	glog.Infof("local static ips %v [%d]", a.staticIPs, len(a.staticIPs))
+	plog.Infof("local static ips %v [%d]", a.staticIPs, len(a.staticIPs))
 	h, err := host.Build(request.IP, fmt.Sprintf("%d", request.Port), request.PoolID, request.Memory, a.staticIPs...)
 	if err != nil {
 		return err
@@ -70,7 +81,7 @@ func (a *AgentServer) GetDockerLogs(dockerID string, logs *string) error {
 	cmd := exec.Command("docker", "logs", "--tail=2000", dockerID)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		glog.Errorf("Unable to return logs because: %v", err)
+		plog.Errorf("Unable to return logs because: %v", err)
 		return err
 	}
 	*logs = string(output)
@@ -91,12 +102,12 @@ func (a *AgentServer) PullImage(req PullImageRequest, image *string) error {
 	// set up the connections
 	docker, err := docker.NewDockerClient()
 	if err != nil {
-		glog.Errorf("Could not connect to docker client: %s", err)
+		plog.Errorf("Could not connect to docker client: %s", err)
 		return err
 	}
 	conn, err := zzk.GetLocalConnection("/")
 	if err != nil {
-		glog.Errorf("Could not acquire coordinator connection: %s", err)
+		plog.Errorf("Could not acquire coordinator connection: %s", err)
 		return err
 	}
 
@@ -106,16 +117,79 @@ func (a *AgentServer) PullImage(req PullImageRequest, image *string) error {
 	timer := time.NewTimer(req.Timeout)
 	defer timer.Stop()
 	if err := reg.PullImage(timer.C, req.Image); err != nil {
-		glog.Errorf("Could not pull image %s from registry %s: %s", req.Image, req.Registry, err)
+		plog.Errorf("Could not pull image %s from registry %s: %s", req.Image, req.Registry, err)
 		return err
 	}
 
 	// get the tag of the image pulled
 	*image, err = reg.ImagePath(req.Image)
 	if err != nil {
-		glog.Errorf("Could not get image id for %s from registry %s: %s", req.Image, req.Registry, err)
+		plog.Errorf("Could not get image id for %s from registry %s: %s", req.Image, req.Registry, err)
 		return err
 	}
 
 	return nil
 }
+
+// LogLevelRequest sets the log level of a component on the agent
+type LogLevelRequest struct {
+	Component string // Package relative to serviced, separated by dots (e.g. zzk.service)
+	Level     string // Level to log at; empty to reset the component's level
+}
+
+// SetLogLevel overrides, or resets, the log level of a component
+func (a *AgentServer) SetLogLevel(req LogLevelRequest, unused *int) error {
+	if req.Level == "" {
+		return logging.ResetComponentLevel(req.Component)
+	}
+	return logging.SetComponentLevel(req.Component, req.Level)
+}
+
+// GetLogLevels returns the log level overrides by component
+func (a *AgentServer) GetLogLevels(unused struct{}, levels *map[string]string) error {
+	*levels = logging.ComponentLevels()
+	return nil
+}
+
+// ErrNoUpgradeCommand is returned when a host is asked to upgrade serviced
+// but has no upgrade command configured
+var ErrNoUpgradeCommand = errors.New("no upgrade command is configured on the host")
+
+// restartDelay gives the upgrade response time to reach the caller before
+// serviced restarts
+var restartDelay = time.Second
+
+// SetUpgradeCommand sets the shell command that installs a release of
+// serviced on this host, and the function that restarts serviced once it has
+// been installed.  The release is passed to the command in the
+// SERVICED_UPGRADE_VERSION environment variable.
+func (a *AgentServer) SetUpgradeCommand(command string, restart func()) {
+	a.upgradeCommand = command
+	a.restart = restart
+}
+
+// UpgradeServiced installs a release of serviced on this host and then
+// restarts serviced
+func (a *AgentServer) UpgradeServiced(version string, unused *int) error {
+	if a.upgradeCommand == "" {
+		return ErrNoUpgradeCommand
+	}
+	plog.Infof("Upgrading serviced to %s", version)
+	cmd := exec.Command("/bin/sh", "-c", a.upgradeCommand)
+	cmd.Env = append(os.Environ(), "SERVICED_UPGRADE_VERSION="+version)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		plog.Errorf("Could not upgrade serviced to %s: %s\n%s", version, err, output)
+		return fmt.Errorf("could not upgrade serviced to %s: %s: %s", version, err, strings.TrimSpace(string(output)))
+	}
+	plog.Infof("Installed serviced %s; restarting", version)
+	if a.restart != nil {
+		time.AfterFunc(restartDelay, a.restart)
+	}
+	return nil
+}
+
+// GetServicedVersion returns the version of serviced running on this host
+func (a *AgentServer) GetServicedVersion(unused struct{}, version *servicedversion.ServicedVersion) error {
+	*version = servicedversion.GetVersion()
+	return nil
+}
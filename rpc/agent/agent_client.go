@@ -17,7 +17,10 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/proxy"
 	"github.com/control-center/serviced/rpc/rpcutils"
+	"github.com/control-center/serviced/servicedversion"
 )
 
 // Client rpc client to interact with agent
@@ -71,3 +74,202 @@ func (c *Client) PullImage(registry, image string, timeout time.Duration) (strin
 	err := c.rpcClient.Call("Agent.PullImage", req, &imageTag, 0)
 	return imageTag, err
 }
+
+// PrepareShellImage returns the local image tag for running a service shell
+// with the given mounts, reusing the image from an earlier shell when it is
+// still current.
+func (c *Client) PrepareShellImage(registry, image string, mounts []string, timeout time.Duration) (string, error) {
+	req := ShellImageRequest{
+		Registry: registry,
+		Image:    image,
+		Mounts:   mounts,
+		Timeout:  timeout,
+	}
+	imageTag := ""
+	err := c.rpcClient.Call("Agent.PrepareShellImage", req, &imageTag, 0)
+	return imageTag, err
+}
+
+// SetLogLevel overrides the log level of a component on the agent.  An empty
+// level resets the component to the level of its parent.
+func (c *Client) SetLogLevel(component, level string) error {
+	req := LogLevelRequest{
+		Component: component,
+		Level:     level,
+	}
+	return c.rpcClient.Call("Agent.SetLogLevel", req, nil, 0)
+}
+
+// GetLogLevels returns the log level overrides by component on the agent
+func (c *Client) GetLogLevels() (map[string]string, error) {
+	var levels map[string]string
+	err := c.rpcClient.Call("Agent.GetLogLevels", struct{}{}, &levels, 0)
+	return levels, err
+}
+
+// UpgradeServiced installs a release of serviced on the host and restarts it
+func (c *Client) UpgradeServiced(version string) error {
+	return c.rpcClient.Call("Agent.UpgradeServiced", version, nil, 0)
+}
+
+// GetServicedVersion returns the version of serviced running on the host
+func (c *Client) GetServicedVersion() (*servicedversion.ServicedVersion, error) {
+	version := &servicedversion.ServicedVersion{}
+	if err := c.rpcClient.Call("Agent.GetServicedVersion", struct{}{}, version, 0); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// ProbeEndpoint checks that an endpoint is reachable from the host.  If
+// muxAddress is set, the endpoint is reached through the mux at that address.
+func (c *Client) ProbeEndpoint(target, muxAddress string, timeout time.Duration) error {
+	req := ProbeEndpointRequest{
+		Address:    target,
+		MuxAddress: muxAddress,
+		Timeout:    timeout,
+	}
+	return c.rpcClient.Call("Agent.ProbeEndpoint", req, nil, 0)
+}
+
+// SelfTest validates that the host can reach the services it needs to run as
+// a delegate
+func (c *Client) SelfTest() (*host.SelfTestReport, error) {
+	report := &host.SelfTestReport{}
+	if err := c.rpcClient.Call("Agent.SelfTest", struct{}{}, report, 0); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetMuxStats returns the connections forwarded by the mux on the host, keyed
+// by container address
+func (c *Client) GetMuxStats() (map[string]proxy.ConnectionStats, error) {
+	var stats map[string]proxy.ConnectionStats
+	err := c.rpcClient.Call("Agent.GetMuxStats", struct{}{}, &stats, 0)
+	return stats, err
+}
+
+// GetPreviousOutput returns the output of the most recently exited container
+// of a service instance on the host, or nil if none was buffered
+func (c *Client) GetPreviousOutput(serviceID string, instanceID int) (*service.InstanceOutput, error) {
+	req := PreviousOutputRequest{
+		ServiceID:  serviceID,
+		InstanceID: instanceID,
+	}
+	var output service.InstanceOutput
+	if err := c.rpcClient.Call("Agent.GetPreviousOutput", req, &output, 0); err != nil {
+		return nil, err
+	} else if output.Output == nil && output.Exited.IsZero() {
+		return nil, nil
+	}
+	return &output, nil
+}
+
+// GetDiagnostics returns the saved output of the built-in diagnostic actions
+// that were run on a service instance on the host
+func (c *Client) GetDiagnostics(serviceID string, instanceID int) ([]service.DiagnosticOutput, error) {
+	req := PreviousOutputRequest{
+		ServiceID:  serviceID,
+		InstanceID: instanceID,
+	}
+	var results []service.DiagnosticOutput
+	if err := c.rpcClient.Call("Agent.GetDiagnostics", req, &results, 0); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetContainerLogs returns the lines that a container on the host wrote to
+// stdout and stderr after since, limited to the last tail lines if tail is set
+func (c *Client) GetContainerLogs(dockerID string, since time.Time, tail int) ([]service.LogLine, error) {
+	req := ContainerLogsRequest{
+		DockerID: dockerID,
+		Since:    since,
+		Tail:     tail,
+	}
+	var lines []service.LogLine
+	if err := c.rpcClient.Call("Agent.GetContainerLogs", req, &lines, 0); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// Delegates connects to the agents running on delegate hosts by address
+type Delegates struct{}
+
+// UpgradeServiced installs a release of serviced on the delegate at address
+func (Delegates) UpgradeServiced(address, version string) error {
+	client, err := NewClient(address)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.UpgradeServiced(version)
+}
+
+// GetServicedVersion returns the version of serviced running on the
+// delegate at address
+func (Delegates) GetServicedVersion(address string) (*servicedversion.ServicedVersion, error) {
+	client, err := NewClient(address)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return client.GetServicedVersion()
+}
+
+// ProbeEndpoint checks that an endpoint is reachable from the delegate at
+// address
+func (Delegates) ProbeEndpoint(address, target, muxAddress string, timeout time.Duration) error {
+	client, err := NewClient(address)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.ProbeEndpoint(target, muxAddress, timeout)
+}
+
+// GetMuxStats returns the connections forwarded by the mux on the delegate at
+// address
+func (Delegates) GetMuxStats(address string) (map[string]proxy.ConnectionStats, error) {
+	client, err := NewClient(address)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return client.GetMuxStats()
+}
+
+// GetPreviousOutput returns the output of the most recently exited container
+// of a service instance on the delegate at address
+func (Delegates) GetPreviousOutput(address, serviceID string, instanceID int) (*service.InstanceOutput, error) {
+	client, err := NewClient(address)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return client.GetPreviousOutput(serviceID, instanceID)
+}
+
+// GetDiagnostics returns the saved output of the built-in diagnostic actions
+// that were run on a service instance on the delegate at address
+func (Delegates) GetDiagnostics(address, serviceID string, instanceID int) ([]service.DiagnosticOutput, error) {
+	client, err := NewClient(address)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return client.GetDiagnostics(serviceID, instanceID)
+}
+
+// GetContainerLogs returns the lines that a container on the delegate at
+// address wrote to stdout and stderr after since
+func (Delegates) GetContainerLogs(address, dockerID string, since time.Time, tail int) ([]service.LogLine, error) {
+	client, err := NewClient(address)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return client.GetContainerLogs(dockerID, since, tail)
+}
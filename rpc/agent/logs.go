@@ -0,0 +1,101 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+)
+
+// ContainerLogsRequest selects the log lines of a container
+type ContainerLogsRequest struct {
+	DockerID string
+	Since    time.Time // only lines written after this time; all if zero
+	Tail     int       // only the last lines; all if zero
+}
+
+// GetContainerLogs returns the timestamped lines that a container wrote to
+// stdout and stderr, in the order they were written.
+func (a *AgentServer) GetContainerLogs(req ContainerLogsRequest, lines *[]service.LogLine) error {
+	args := []string{"logs", "--timestamps"}
+	if !req.Since.IsZero() {
+		args = append(args, "--since="+req.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if req.Tail > 0 {
+		args = append(args, fmt.Sprintf("--tail=%d", req.Tail))
+	}
+	args = append(args, req.DockerID)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		plog.Errorf("Unable to return logs of container %s: %s", req.DockerID, err)
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	*lines = mergeLogLines(req.Since, req.Tail, parseLogLines(&stdout, false), parseLogLines(&stderr, true))
+	return nil
+}
+
+// parseLogLines parses the output of docker logs --timestamps.  Lines
+// without a timestamp are attributed to the time of the line before them.
+func parseLogLines(r io.Reader, stderr bool) []service.LogLine {
+	var lines []service.LogLine
+	var last time.Time
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := service.LogLine{Stderr: stderr, Time: last, Text: scanner.Text()}
+		if i := strings.IndexByte(line.Text, ' '); i > 0 {
+			if t, err := time.Parse(time.RFC3339Nano, line.Text[:i]); err == nil {
+				line.Time, line.Text = t, line.Text[i+1:]
+			}
+		}
+		last = line.Time
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// mergeLogLines orders the lines of each stream by the time they were
+// written, drops the lines that were not written after since, and keeps the
+// last tail lines.
+func mergeLogLines(since time.Time, tail int, streams ...[]service.LogLine) []service.LogLine {
+	lines := []service.LogLine{}
+	for _, stream := range streams {
+		for _, line := range stream {
+			if since.IsZero() || line.Time.After(since) {
+				lines = append(lines, line)
+			}
+		}
+	}
+	sort.Stable(logLinesByTime(lines))
+	if tail > 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	return lines
+}
+
+type logLinesByTime []service.LogLine
+
+func (s logLinesByTime) Len() int           { return len(s) }
+func (s logLinesByTime) Less(i, j int) bool { return s[i].Time.Before(s[j].Time) }
+func (s logLinesByTime) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
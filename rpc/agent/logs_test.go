@@ -0,0 +1,70 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package agent
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+)
+
+func TestParseLogLines(t *testing.T) {
+	output := "2016-05-04T03:02:01.5Z starting zope\n" +
+		"continued without a timestamp\n" +
+		"2016-05-04T03:02:03Z ready\n"
+	t0 := time.Date(2016, 5, 4, 3, 2, 1, 500000000, time.UTC)
+	expected := []service.LogLine{
+		{Time: t0, Stderr: true, Text: "starting zope"},
+		{Time: t0, Stderr: true, Text: "continued without a timestamp"},
+		{Time: t0.Add(1500 * time.Millisecond), Stderr: true, Text: "ready"},
+	}
+	if lines := parseLogLines(strings.NewReader(output), true); !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, lines)
+	}
+}
+
+func TestMergeLogLines(t *testing.T) {
+	t0 := time.Date(2016, 5, 4, 3, 2, 1, 0, time.UTC)
+	stdout := []service.LogLine{
+		{Time: t0, Text: "out zero"},
+		{Time: t0.Add(2 * time.Second), Text: "out two"},
+		{Time: t0.Add(4 * time.Second), Text: "out four"},
+	}
+	stderr := []service.LogLine{
+		{Time: t0.Add(time.Second), Stderr: true, Text: "err one"},
+		{Time: t0.Add(3 * time.Second), Stderr: true, Text: "err three"},
+	}
+
+	lines := mergeLogLines(time.Time{}, 0, stdout, stderr)
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 lines, got %+v", lines)
+	}
+	for i, line := range lines {
+		if expected := t0.Add(time.Duration(i) * time.Second); !line.Time.Equal(expected) {
+			t.Errorf("Expected line %d at %s, got %+v", i, expected, line)
+		}
+	}
+
+	// lines written at the time of the last line read are not repeated
+	lines = mergeLogLines(t0.Add(time.Second), 2, stdout, stderr)
+	expected := []service.LogLine{stderr[1], stdout[2]}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, lines)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/node"
+)
+
+// PreviousOutputRequest identifies a service instance whose previous output
+// is requested
+type PreviousOutputRequest struct {
+	ServiceID  string
+	InstanceID int
+}
+
+// SetContainerOutputPath sets the path where the host agent buffers the
+// output of its containers
+func (a *AgentServer) SetContainerOutputPath(path string) {
+	a.outputPath = path
+}
+
+// GetPreviousOutput returns the output of the most recently exited container
+// of a service instance on this host.  The output is empty if none was
+// buffered.
+func (a *AgentServer) GetPreviousOutput(req PreviousOutputRequest, output *service.InstanceOutput) error {
+	*output = service.InstanceOutput{}
+	if a.outputPath == "" {
+		return nil
+	}
+	result, err := node.ReadPreviousOutput(a.outputPath, req.ServiceID, req.InstanceID)
+	if err != nil {
+		return err
+	} else if result != nil {
+		*output = *result
+	}
+	return nil
+}
+
+// GetDiagnostics returns the saved output of the built-in diagnostic actions
+// that were run on a service instance on this host.
+func (a *AgentServer) GetDiagnostics(req PreviousOutputRequest, results *[]service.DiagnosticOutput) error {
+	*results = []service.DiagnosticOutput{}
+	if a.outputPath == "" {
+		return nil
+	}
+	diagnostics, err := node.ReadDiagnostics(a.outputPath, req.ServiceID, req.InstanceID)
+	if err != nil {
+		return err
+	}
+	*results = diagnostics
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/proxy"
+	"github.com/control-center/serviced/utils"
+)
+
+// muxProbeWait is how long to wait for the mux to close a connection it could
+// not forward
+var muxProbeWait = time.Second
+
+// ProbeEndpointRequest describes an endpoint to be probed from an agent
+type ProbeEndpointRequest struct {
+	Address    string // ip:port of the endpoint
+	MuxAddress string // if set, the endpoint is reached through the mux at this ip:port
+	Timeout    time.Duration
+}
+
+// SetMuxTLS sets whether connections to the mux use TLS
+func (a *AgentServer) SetMuxTLS(useTLS bool) {
+	a.muxTLS = useTLS
+}
+
+// ProbeEndpoint verifies that an endpoint is accepting tcp connections from
+// this host, either directly or by way of the mux.
+func (a *AgentServer) ProbeEndpoint(req ProbeEndpointRequest, unused *int) error {
+	if req.MuxAddress == "" {
+		conn, err := net.DialTimeout("tcp4", req.Address, req.Timeout)
+		if err != nil {
+			return err
+		}
+		conn.Close()
+		return nil
+	}
+	return a.probeMux(req)
+}
+
+// probeMux sends a connection to the endpoint through the mux.  The mux closes
+// the connection if it cannot reach the endpoint, so a connection that stays
+// open is taken as success.
+func (a *AgentServer) probeMux(req ProbeEndpointRequest) error {
+	address, err := utils.PackTCPAddressString(req.Address)
+	if err != nil {
+		return err
+	}
+	token, err := auth.AuthTokenNonBlocking()
+	if err != nil {
+		return err
+	}
+	header, err := auth.BuildAuthMuxHeader(address, token)
+	if err != nil {
+		return err
+	}
+
+	var conn net.Conn
+	dialer := &net.Dialer{Timeout: req.Timeout}
+	if a.muxTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp4", req.MuxAddress, &tls.Config{InsecureSkipVerify: true})
+	} else {
+		conn, err = dialer.Dial("tcp4", req.MuxAddress)
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(header); err != nil {
+		return err
+	}
+	conn.SetReadDeadline(time.Now().Add(muxProbeWait))
+	if _, err := conn.Read(make([]byte, 1)); err == io.EOF {
+		return fmt.Errorf("mux at %s closed the connection to %s", req.MuxAddress, req.Address)
+	} else if nerr, ok := err.(net.Error); err != nil && !(ok && nerr.Timeout()) {
+		return err
+	}
+	return nil
+}
+
+// GetMuxStats returns the connections forwarded by the mux on this host, keyed
+// by container address
+func (a *AgentServer) GetMuxStats(unused struct{}, stats *map[string]proxy.ConnectionStats) error {
+	*stats = proxy.GetMuxStats()
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package agent
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestProbeEndpoint(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Could not start listener: %s", err)
+	}
+	address := listener.Addr().String()
+
+	a := &AgentServer{}
+	req := ProbeEndpointRequest{Address: address, Timeout: time.Second}
+	if err := a.ProbeEndpoint(req, nil); err != nil {
+		t.Errorf("Expected probe of %s to succeed: %s", address, err)
+	}
+
+	listener.Close()
+	if err := a.ProbeEndpoint(req, nil); err == nil {
+		t.Errorf("Expected probe of closed port %s to fail", address)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/node"
+)
+
+// ErrNoSelfTest is returned when the host agent was not configured to run
+// its self-test
+var ErrNoSelfTest = errors.New("self-test is not configured on this host")
+
+// SetSelfTestConfig sets what the self-test of this host validates
+func (a *AgentServer) SetSelfTestConfig(cfg node.SelfTestConfig) {
+	a.selfTest = &cfg
+}
+
+// SelfTest validates that this host can reach the services it needs to run
+// as a delegate.
+func (a *AgentServer) SelfTest(unused struct{}, report *host.SelfTestReport) error {
+	if a.selfTest == nil {
+		return ErrNoSelfTest
+	}
+	*report = node.RunSelfTest(*a.selfTest)
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/dfs/docker"
+	"github.com/control-center/serviced/dfs/registry"
+	"github.com/control-center/serviced/zzk"
+)
+
+// ShellImageRequest requests an image for running a service shell with the
+// given bind mounts.
+type ShellImageRequest struct {
+	Registry string
+	Image    string
+	Mounts   []string
+	Timeout  time.Duration
+}
+
+// shellImage is an image that has already been pulled and verified for a
+// service shell.
+type shellImage struct {
+	Image   string // local tag of the image
+	UUID    string // id of the image in the registry index when it was pulled
+	LocalID string // id of the local image when it was pulled
+}
+
+// shellImageCache tracks verified shell images by image and mount
+// configuration.
+type shellImageCache struct {
+	mu     sync.Mutex
+	images map[string]shellImage
+}
+
+// shellImageKey returns the cache key for an image and its mounts.  The order
+// of the mounts does not matter.
+func shellImageKey(image string, mounts []string) string {
+	sorted := make([]string, len(mounts))
+	copy(sorted, mounts)
+	sort.Strings(sorted)
+
+	h := sha1.New()
+	io.WriteString(h, image)
+	for _, mount := range sorted {
+		io.WriteString(h, "\x00"+mount)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+func (c *shellImageCache) get(key string) (shellImage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	img, ok := c.images[key]
+	return img, ok
+}
+
+func (c *shellImageCache) set(key string, img shellImage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.images == nil {
+		c.images = make(map[string]shellImage)
+	}
+	c.images[key] = img
+}
+
+func (c *shellImageCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.images, key)
+}
+
+// PrepareShellImage returns the local tag of an image for a service shell.
+// Images that were verified by an earlier shell with the same mounts are
+// reused as long as neither the registry index nor the local image has
+// changed; otherwise the image is pulled as with PullImage.
+func (a *AgentServer) PrepareShellImage(req ShellImageRequest, image *string) error {
+	key := shellImageKey(req.Image, req.Mounts)
+
+	// set up the connections
+	dc, err := docker.NewDockerClient()
+	if err != nil {
+		plog.Errorf("Could not connect to docker client: %s", err)
+		return err
+	}
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		plog.Errorf("Could not acquire coordinator connection: %s", err)
+		return err
+	}
+	reg := registry.NewRegistryListener(dc, req.Registry, "")
+	reg.SetConnection(conn)
+
+	if cached, ok := a.shellImages.get(key); ok {
+		if uuid, err := reg.ImageUUID(req.Image); err != nil {
+			plog.Warningf("Could not look up image %s in the registry index: %s", req.Image, err)
+		} else if uuid != cached.UUID {
+			plog.Infof("Image %s was updated in the registry, refreshing shell image", req.Image)
+		} else if img, err := dc.FindImage(cached.Image); err != nil || img.ID != cached.LocalID {
+			plog.Infof("Local image %s has changed, refreshing shell image", cached.Image)
+		} else {
+			plog.Debugf("Using cached shell image %s", cached.Image)
+			*image = cached.Image
+			return nil
+		}
+		a.shellImages.remove(key)
+	}
+
+	// pull the image from the registry
+	var imagePath string
+	if err := a.PullImage(PullImageRequest{Registry: req.Registry, Image: req.Image, Timeout: req.Timeout}, &imagePath); err != nil {
+		return err
+	}
+	*image = imagePath
+
+	// only cache images that can be verified on the next shell
+	uuid, err := reg.ImageUUID(req.Image)
+	if err != nil {
+		plog.Warningf("Could not look up image %s in the registry index: %s", req.Image, err)
+		return nil
+	}
+	img, err := dc.FindImage(imagePath)
+	if err != nil {
+		plog.Warningf("Could not inspect image %s: %s", imagePath, err)
+		return nil
+	}
+	a.shellImages.set(key, shellImage{Image: imagePath, UUID: uuid, LocalID: img.ID})
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package agent
+
+import "testing"
+
+func TestShellImageKey(t *testing.T) {
+	key := shellImageKey("tenant/repo:latest", []string{"/a,/mnt/a", "/b,/mnt/b"})
+	if other := shellImageKey("tenant/repo:latest", []string{"/b,/mnt/b", "/a,/mnt/a"}); key != other {
+		t.Errorf("Expected mount order to be ignored: %s != %s", key, other)
+	}
+	if other := shellImageKey("tenant/repo:latest", []string{"/a,/mnt/a"}); key == other {
+		t.Errorf("Expected different mounts to produce a different key")
+	}
+	if other := shellImageKey("tenant/other:latest", []string{"/a,/mnt/a", "/b,/mnt/b"}); key == other {
+		t.Errorf("Expected different images to produce a different key")
+	}
+}
+
+func TestShellImageCache(t *testing.T) {
+	var cache shellImageCache
+	if _, ok := cache.get("key"); ok {
+		t.Fatalf("Expected empty cache")
+	}
+	img := shellImage{Image: "localhost:5000/tenant/repo:latest", UUID: "uuid", LocalID: "local"}
+	cache.set("key", img)
+	if actual, ok := cache.get("key"); !ok || actual != img {
+		t.Errorf("Expected %+v, got %+v (%t)", img, actual, ok)
+	}
+	cache.remove("key")
+	if _, ok := cache.get("key"); ok {
+		t.Errorf("Expected image to be removed from the cache")
+	}
+}
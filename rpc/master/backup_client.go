@@ -0,0 +1,48 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backup"
+)
+
+// ListBackups returns the catalog of completed backups
+func (c *Client) ListBackups() ([]backup.Backup, error) {
+	response := make([]backup.Backup, 0)
+	if err := c.call("ListBackups", empty, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// EstimateBackup returns the estimated size and duration of a backup,
+// excluding the given subdirectories of the tenant volumes
+func (c *Client) EstimateBackup(excludes []string) (*dfs.BackupEstimate, error) {
+	response := &dfs.BackupEstimate{}
+	if err := c.call("EstimateBackup", excludes, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// CleanupFailedRestore removes the data imported by restores that did not
+// complete, or only lists it if dryRun is set
+func (c *Client) CleanupFailedRestore(dryRun bool) (*dfs.RestoreQuarantine, error) {
+	response := &dfs.RestoreQuarantine{}
+	if err := c.call("CleanupFailedRestore", dryRun, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backup"
+)
+
+// ListBackups returns the catalog of completed backups
+func (s *Server) ListBackups(empty struct{}, reply *[]backup.Backup) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	backups, err := s.f.GetBackups(ctx)
+	if err != nil {
+		return err
+	}
+	*reply = backups
+	return nil
+}
+
+// EstimateBackup returns the estimated size and duration of a backup
+func (s *Server) EstimateBackup(excludes []string, reply *dfs.BackupEstimate) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	estimate, err := s.f.EstimateBackup(ctx, excludes)
+	if err != nil {
+		return err
+	}
+	*reply = *estimate
+	return nil
+}
+
+// CleanupFailedRestore removes the data imported by restores that did not
+// complete
+func (s *Server) CleanupFailedRestore(dryRun bool, reply *dfs.RestoreQuarantine) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	removed, err := s.f.CleanupFailedRestore(ctx, dryRun)
+	if err != nil {
+		return err
+	}
+	*reply = *removed
+	return nil
+}
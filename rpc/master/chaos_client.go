@@ -0,0 +1,43 @@
+// Copyright 2014 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+)
+
+// EnableChaos starts killing random instances in a pool at every interval
+func (c *Client) EnableChaos(poolID string, interval time.Duration) error {
+	req := EnableChaosRequest{
+		PoolID:   poolID,
+		Interval: interval,
+	}
+	return c.call("EnableChaos", req, new(string))
+}
+
+// DisableChaos stops killing instances in a pool
+func (c *Client) DisableChaos(poolID string) error {
+	return c.call("DisableChaos", poolID, new(string))
+}
+
+// GetChaosStatus returns the chaos mode settings and kill history of a pool
+func (c *Client) GetChaosStatus(poolID string) (*service.ChaosStatus, error) {
+	status := &service.ChaosStatus{}
+	if err := c.call("GetChaosStatus", poolID, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2014 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+)
+
+// EnableChaosRequest is the request to turn on chaos mode for a pool
+type EnableChaosRequest struct {
+	PoolID   string
+	Interval time.Duration
+}
+
+// EnableChaos starts killing random instances in a pool at every interval
+func (s *Server) EnableChaos(req EnableChaosRequest, unused *string) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.EnableChaos(ctx, req.PoolID, req.Interval)
+}
+
+// DisableChaos stops killing instances in a pool
+func (s *Server) DisableChaos(poolID string, unused *string) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.DisableChaos(ctx, poolID)
+}
+
+// GetChaosStatus returns the chaos mode settings and kill history of a pool
+func (s *Server) GetChaosStatus(poolID string, reply *service.ChaosStatus) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	status, err := s.f.GetChaosStatus(ctx, poolID)
+	if err != nil {
+		return err
+	}
+	*reply = *status
+	return nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// CheckConsistency compares the datastore with the scheduling state in
+// zookeeper, repairing the differences if requested
+func (c *Client) CheckConsistency(repair bool) (*zkservice.ConsistencyReport, error) {
+	report := &zkservice.ConsistencyReport{}
+	if err := c.call("CheckConsistency", repair, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -13,6 +13,8 @@
 
 package master
 
+import "github.com/control-center/serviced/domain/registry"
+
 // ResetRegistry pulls latest from the running docker registry and updates the
 // index.
 func (c *Client) ResetRegistry() error {
@@ -32,6 +34,51 @@ func (c *Client) UpgradeRegistry(endpoint string, override bool) error {
 	return c.call("UpgradeRegistry", req, new(int))
 }
 
+// MigrateRegistry moves images from one version of the local docker registry
+// to another.
+func (c *Client) MigrateRegistry(fromVersion, toVersion int, override bool) error {
+	req := MigrateRegistryRequest{
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Override:    override,
+	}
+	return c.call("MigrateRegistry", req, new(int))
+}
+
+// ListRegistryImages returns the images in the docker registry index, limited
+// to a tenant's library if tenantID is set.
+func (c *Client) ListRegistryImages(tenantID string) ([]registry.ImageDetails, error) {
+	response := []registry.ImageDetails{}
+	if err := c.call("ListRegistryImages", tenantID, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// InspectRegistryImage returns an image in the docker registry index.
+func (c *Client) InspectRegistryImage(image string) (*registry.ImageDetails, error) {
+	response := &registry.ImageDetails{}
+	if err := c.call("InspectRegistryImage", image, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// PromoteImage copies an image in the docker registry to another tenant and
+// returns the name of the new image.
+func (c *Client) PromoteImage(image, tenantID, tag string) (string, error) {
+	req := PromoteImageRequest{
+		Image:    image,
+		TenantID: tenantID,
+		Tag:      tag,
+	}
+	var response string
+	if err := c.call("PromoteImage", req, &response); err != nil {
+		return "", err
+	}
+	return response, nil
+}
+
 // DockerOverride replaces an image in the registry with a new image
 func (c *Client) DockerOverride(newImage, oldImage string) error {
 	req := DockerOverrideRequest{
@@ -13,12 +13,30 @@
 
 package master
 
+import "github.com/control-center/serviced/domain/registry"
+
 // UpgradeDockerRequest are options for upgrading/migrating the docker registry.
 type UpgradeDockerRequest struct {
 	Endpoint string
 	Override bool
 }
 
+// MigrateRegistryRequest are options for migrating images between versions of
+// the local docker registry.
+type MigrateRegistryRequest struct {
+	FromVersion int
+	ToVersion   int
+	Override    bool
+}
+
+// PromoteImageRequest are options for copying an image in the docker registry
+// to another tenant.
+type PromoteImageRequest struct {
+	Image    string
+	TenantID string
+	Tag      string
+}
+
 // DockerOverrideRequest are options for replacing an image in the docker registry
 type DockerOverrideRequest struct {
 	OldImage string
@@ -28,22 +46,76 @@ type DockerOverrideRequest struct {
 // ResetRegistry pulls from the configured docker registry and updates the
 // index.
 func (s *Server) ResetRegistry(req struct{}, reply *int) error {
-	return s.f.RepairRegistry(s.context())
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.RepairRegistry(ctx)
 }
 
 // SyncRegistry prompts the master to repush all images in the index into the
 // docker registry.
 func (s *Server) SyncRegistry(req struct{}, reply *int) error {
-	return s.f.SyncRegistryImages(s.context(), true)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.SyncRegistryImages(ctx, true)
 }
 
 // UpgradeRegistry migrates docker registry images from an older or remote
 // docker registry.
 func (s *Server) UpgradeRegistry(req UpgradeDockerRequest, reply *int) error {
-	return s.f.UpgradeRegistry(s.context(), req.Endpoint, req.Override)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.UpgradeRegistry(ctx, req.Endpoint, req.Override)
+}
+
+// MigrateRegistry moves docker registry images from one version of the local
+// docker registry to another.
+func (s *Server) MigrateRegistry(req MigrateRegistryRequest, reply *int) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.MigrateRegistry(ctx, req.FromVersion, req.ToVersion, req.Override)
+}
+
+// ListRegistryImages returns the images in the docker registry index, limited
+// to a tenant's library if tenantID is set.
+func (s *Server) ListRegistryImages(tenantID string, reply *[]registry.ImageDetails) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	images, err := s.f.ListRegistryImages(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	*reply = images
+	return nil
+}
+
+// InspectRegistryImage returns an image in the docker registry index.
+func (s *Server) InspectRegistryImage(image string, reply *registry.ImageDetails) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	details, err := s.f.InspectRegistryImage(ctx, image)
+	if err != nil {
+		return err
+	}
+	*reply = *details
+	return nil
+}
+
+// PromoteImage copies an image in the docker registry to another tenant and
+// returns the name of the new image.
+func (s *Server) PromoteImage(req PromoteImageRequest, reply *string) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	image, err := s.f.PromoteImage(ctx, req.Image, req.TenantID, req.Tag)
+	if err != nil {
+		return err
+	}
+	*reply = image
+	return nil
 }
 
 // DockerOverride replaces an image in the registry with a new image
 func (s *Server) DockerOverride(overrideReq DockerOverrideRequest, _ *int) error {
-	return s.f.DockerOverride(s.context(), overrideReq.NewImage, overrideReq.OldImage)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.DockerOverride(ctx, overrideReq.NewImage, overrideReq.OldImage)
 }
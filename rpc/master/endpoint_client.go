@@ -32,3 +32,14 @@ func (c *Client) GetServiceEndpoints(serviceIDs []string, reportImports, reportE
 	}
 	return result, nil
 }
+
+// GetServiceEndpointStats gets the connections the mux has forwarded to the
+// exported endpoints of a service
+func (c *Client) GetServiceEndpointStats(serviceID string) ([]applicationendpoint.EndpointStats, error) {
+	result := make([]applicationendpoint.EndpointStats, 0)
+	err := c.call("GetServiceEndpointStats", serviceID, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
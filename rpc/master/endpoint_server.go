@@ -27,7 +27,9 @@ type EndpointRequest struct {
 
 // Get the endpoints for one or more services
 func (s *Server) GetServiceEndpoints(request *EndpointRequest, reply *[]applicationendpoint.EndpointReport) error {
-	endpoints, err := s.f.GetServiceEndpoints(s.context(), request.ServiceIDs[0], request.ReportImports, request.ReportExports, request.Validate)
+	ctx, cancel := s.context()
+	defer cancel()
+	endpoints, err := s.f.GetServiceEndpoints(ctx, request.ServiceIDs[0], request.ReportImports, request.ReportExports, request.Validate)
 	if err != nil {
 		return err
 	}
@@ -35,3 +37,17 @@ func (s *Server) GetServiceEndpoints(request *EndpointRequest, reply *[]applicat
 	*reply = endpoints
 	return nil
 }
+
+// Get the connections the mux has forwarded to the exported endpoints of a
+// service
+func (s *Server) GetServiceEndpointStats(serviceID string, reply *[]applicationendpoint.EndpointStats) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	stats, err := s.f.GetServiceEndpointStats(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+
+	*reply = stats
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/zzk/ensemble"
+)
+
+// GetZKEnsembleStatus returns the health of the quorum of the managed
+// ZooKeeper ensemble
+func (c *Client) GetZKEnsembleStatus() (*ensemble.Status, error) {
+	status := &ensemble.Status{}
+	if err := c.call("GetZKEnsembleStatus", struct{}{}, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// AddZKEnsembleServer adds a server to the managed ZooKeeper ensemble
+func (c *Client) AddZKEnsembleServer(server ensemble.Server, force bool) (*ensemble.Status, error) {
+	status := &ensemble.Status{}
+	if err := c.call("AddZKEnsembleServer", ZKEnsembleRequest{Server: server, Force: force}, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// RemoveZKEnsembleServer removes a server from the managed ZooKeeper ensemble
+func (c *Client) RemoveZKEnsembleServer(id string, force bool) (*ensemble.Status, error) {
+	status := &ensemble.Status{}
+	if err := c.call("RemoveZKEnsembleServer", ZKEnsembleRequest{ID: id, Force: force}, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/zzk/ensemble"
+)
+
+// ZKEnsembleRequest is a request to change the managed ZooKeeper ensemble
+type ZKEnsembleRequest struct {
+	Server ensemble.Server // server to add
+	ID     string          // id of the server to remove
+	Force  bool            // skip the quorum health checks
+}
+
+// GetZKEnsembleStatus returns the health of the quorum of the managed
+// ZooKeeper ensemble
+func (s *Server) GetZKEnsembleStatus(unused struct{}, reply *ensemble.Status) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	status, err := s.f.GetZKEnsembleStatus(ctx)
+	if err != nil {
+		return err
+	}
+	*reply = *status
+	return nil
+}
+
+// AddZKEnsembleServer adds a server to the managed ZooKeeper ensemble
+func (s *Server) AddZKEnsembleServer(request ZKEnsembleRequest, reply *ensemble.Status) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	status, err := s.f.AddZKEnsembleServer(ctx, request.Server, request.Force)
+	if err != nil {
+		return err
+	}
+	*reply = *status
+	return nil
+}
+
+// RemoveZKEnsembleServer removes a server from the managed ZooKeeper ensemble
+func (s *Server) RemoveZKEnsembleServer(request ZKEnsembleRequest, reply *ensemble.Status) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	status, err := s.f.RemoveZKEnsembleServer(ctx, request.ID, request.Force)
+	if err != nil {
+		return err
+	}
+	*reply = *status
+	return nil
+}
@@ -17,9 +17,16 @@ import (
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/isvcs"
 
+	"errors"
 	"time"
 )
 
+var (
+	// ErrNoISvcs is returned while this master has not started the internal
+	// services, such as when it is a standby waiting to take over.
+	ErrNoISvcs = errors.New("internal services are not running on this master")
+)
+
 // HealthStatusRequest sends health status data to the health status cache.
 type HealthStatusRequest struct {
 	Key     health.HealthStatusKey
@@ -34,6 +41,9 @@ type ReportDeadInstanceRequest struct {
 
 // GetISvcsHealth returns health status for a list of isvcs
 func (s *Server) GetISvcsHealth(IServiceNames []string, results *[]isvcs.IServiceHealthResult) error {
+	if isvcs.Mgr == nil {
+		return ErrNoISvcs
+	}
 	if len(IServiceNames) == 0 {
 		IServiceNames = isvcs.Mgr.GetServiceNames()
 	}
@@ -54,7 +64,9 @@ func (s *Server) GetISvcsHealth(IServiceNames []string, results *[]isvcs.IServic
 
 // GetServicesHealth returns health checks for all services.
 func (s *Server) GetServicesHealth(unused struct{}, results *map[string]map[int]map[string]health.HealthStatus) error {
-	if healthStatuses, err := s.f.GetServicesHealth(s.context()); err != nil {
+	ctx, cancel := s.context()
+	defer cancel()
+	if healthStatuses, err := s.f.GetServicesHealth(ctx); err != nil {
 		return err
 	} else {
 		*results = healthStatuses
@@ -77,9 +77,11 @@ func (c *Client) FindHostsInPool(poolID string) ([]host.Host, error) {
 
 // AuthenticateHost authenticates a host
 func (c *Client) AuthenticateHost(hostID string) (string, int64, error) {
+	now := time.Now()
 	req := HostAuthenticationRequest{
-		HostID:  hostID,
-		Expires: time.Now().Add(time.Duration(1 * time.Minute)).UTC().Unix(),
+		HostID:    hostID,
+		Expires:   now.Add(time.Duration(1 * time.Minute)).UTC().Unix(),
+		Timestamp: now.UnixNano(),
 	}
 	sig, err := auth.SignAsDelegate(req.toMessage())
 	if err != nil {
@@ -93,6 +95,13 @@ func (c *Client) AuthenticateHost(hostID string) (string, int64, error) {
 	return response.Token, response.Expires, nil
 }
 
+// GetServerTime returns the time on the master
+func (c *Client) GetServerTime() (time.Time, error) {
+	var now time.Time
+	err := c.call("GetServerTime", struct{}{}, &now)
+	return now, err
+}
+
 func (c *Client) GetHostPublicKey(hostID string) ([]byte, error) {
 	response := []byte{}
 	err := c.call("GetHostPublicKey", hostID, &response)
@@ -104,3 +113,40 @@ func (c *Client) ResetHostKey(hostID string) ([]byte, error) {
 	err := c.call("ResetHostKey", hostID, &response)
 	return response, err
 }
+
+// VerifyHostKey checks that a host installed the delegate key generated for it
+func (c *Client) VerifyHostKey(hostID, fingerprint string) error {
+	req := HostKeyVerificationRequest{
+		HostID:      hostID,
+		Fingerprint: fingerprint,
+	}
+	return c.call("VerifyHostKey", req, nil)
+}
+
+// GetHostStorage returns the usage of a host's storage pools, or nil if the
+// host has not reported its storage
+func (c *Client) GetHostStorage(hostID string) (*host.StorageHealth, error) {
+	response := &host.StorageHealth{}
+	if err := c.call("GetHostStorage", hostID, response); err != nil {
+		return nil, err
+	} else if response.Updated.IsZero() {
+		return nil, nil
+	}
+	return response, nil
+}
+
+// GetHostEvents returns the event history of a host, oldest first
+func (c *Client) GetHostEvents(hostID string) ([]host.Event, error) {
+	response := make([]host.Event, 0)
+	if err := c.call("GetHostEvents", hostID, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// UpgradeDelegates upgrades serviced on the delegates one host at a time
+func (c *Client) UpgradeDelegates(req host.UpgradeRequest) ([]host.UpgradeResult, error) {
+	response := []host.UpgradeResult{}
+	err := c.call("UpgradeDelegates", req, &response)
+	return response, err
+}
@@ -31,7 +31,9 @@ var (
 
 // GetHost gets the host
 func (s *Server) GetHost(hostID string, reply *host.Host) error {
-	response, err := s.f.GetHost(s.context(), hostID)
+	ctx, cancel := s.context()
+	defer cancel()
+	response, err := s.f.GetHost(ctx, hostID)
 	if err != nil {
 		return err
 	}
@@ -44,7 +46,9 @@ func (s *Server) GetHost(hostID string, reply *host.Host) error {
 
 // GetHosts returns all Hosts
 func (s *Server) GetHosts(empty struct{}, hostReply *[]host.Host) error {
-	hosts, err := s.f.GetHosts(s.context())
+	ctx, cancel := s.context()
+	defer cancel()
+	hosts, err := s.f.GetHosts(ctx)
 	if err != nil {
 		return err
 	}
@@ -54,7 +58,9 @@ func (s *Server) GetHosts(empty struct{}, hostReply *[]host.Host) error {
 
 // GetActiveHosts returns all active host ids
 func (s *Server) GetActiveHostIDs(empty struct{}, hostReply *[]string) error {
-	hosts, err := s.f.GetActiveHostIDs(s.context())
+	ctx, cancel := s.context()
+	defer cancel()
+	hosts, err := s.f.GetActiveHostIDs(ctx)
 	if err != nil {
 		return err
 	}
@@ -64,7 +70,9 @@ func (s *Server) GetActiveHostIDs(empty struct{}, hostReply *[]string) error {
 
 // AddHost adds the host
 func (s *Server) AddHost(host host.Host, hostReply *[]byte) error {
-	privateKey, err := s.f.AddHost(s.context(), &host)
+	ctx, cancel := s.context()
+	defer cancel()
+	privateKey, err := s.f.AddHost(ctx, &host)
 	if err != nil {
 		return err
 	}
@@ -74,17 +82,23 @@ func (s *Server) AddHost(host host.Host, hostReply *[]byte) error {
 
 // UpdateHost updates the host
 func (s *Server) UpdateHost(host host.Host, _ *struct{}) error {
-	return s.f.UpdateHost(s.context(), &host)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.UpdateHost(ctx, &host)
 }
 
 // RemoveHost removes the host
 func (s *Server) RemoveHost(hostID string, _ *struct{}) error {
-	return s.f.RemoveHost(s.context(), hostID)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.RemoveHost(ctx, hostID)
 }
 
 // FindHostsInPool  Returns all Hosts in a pool
 func (s *Server) FindHostsInPool(poolID string, hostReply *[]host.Host) error {
-	hosts, err := s.f.FindHostsInPool(s.context(), poolID)
+	ctx, cancel := s.context()
+	defer cancel()
+	hosts, err := s.f.FindHostsInPool(ctx, poolID)
 	if err != nil {
 		return err
 	}
@@ -96,6 +110,7 @@ type HostAuthenticationRequest struct {
 	HostID    string
 	Expires   int64
 	Signature []byte
+	Timestamp int64 // delegate clock in unix nanoseconds when the request was sent
 }
 
 type HostAuthenticationResponse struct {
@@ -103,7 +118,13 @@ type HostAuthenticationResponse struct {
 	Expires int64
 }
 
+// toMessage returns the signed part of the request.  The timestamp is signed
+// too, so that a replayed request cannot misreport the delegate's clock;
+// delegates that do not send a timestamp sign only the host and expiration.
 func (req HostAuthenticationRequest) toMessage() []byte {
+	if req.Timestamp > 0 {
+		return []byte(fmt.Sprintf("%s:%d:%d", req.HostID, req.Expires, req.Timestamp))
+	}
 	return []byte(fmt.Sprintf("%s:%d", req.HostID, req.Expires))
 }
 
@@ -115,30 +136,42 @@ func (req HostAuthenticationRequest) valid(publicKeyPEM []byte) error {
 	if err := verifier.Verify(req.toMessage(), req.Signature); err != nil {
 		return err
 	}
-	if time.Now().UTC().Unix() >= req.Expires {
+	if time.Now().UTC().Unix() >= req.Expires+int64(auth.ClockSkewTolerance()/time.Second) {
 		return ErrRequestExpired
 	}
 	return nil
 }
 
 func (s *Server) AuthenticateHost(req HostAuthenticationRequest, resp *HostAuthenticationResponse) error {
-	keypem, err := s.f.GetHostKey(s.context(), req.HostID)
+	ctx, cancel := s.context()
+	defer cancel()
+	keypem, err := s.f.GetHostKey(ctx, req.HostID)
 	if err != nil {
-		s.f.RemoveHostExpiration(s.context(), req.HostID)
+		s.f.RemoveHostExpiration(ctx, req.HostID)
 		return err
 	}
-	if err := req.valid(keypem); err != nil {
-		s.f.RemoveHostExpiration(s.context(), req.HostID)
+	err = req.valid(keypem)
+	if (err == nil || err == ErrRequestExpired) && req.Timestamp > 0 {
+		// the signature checks out, so the delegate's clock can be trusted
+		// even if the request looks expired because of that clock.
+		skew := time.Since(time.Unix(0, req.Timestamp))
+		s.f.SetHostClockSkew(ctx, req.HostID, skew)
+		if err == ErrRequestExpired && (auth.HostClockSkew{Skew: skew}).Exceeds(auth.ClockSkewThreshold()) {
+			err = auth.ErrRequestClockSkew
+		}
+	}
+	if err != nil {
+		s.f.RemoveHostExpiration(ctx, req.HostID)
 		return err
 	}
-	host, err := s.f.GetHost(s.context(), req.HostID)
+	host, err := s.f.GetHost(ctx, req.HostID)
 	if err != nil {
 		return err
 	}
 	if host == nil {
 		return facade.ErrHostDoesNotExist
 	}
-	p, err := s.f.GetResourcePool(s.context(), host.PoolID)
+	p, err := s.f.GetResourcePool(ctx, host.PoolID)
 	if err != nil {
 		return err
 	}
@@ -149,24 +182,82 @@ func (s *Server) AuthenticateHost(req HostAuthenticationRequest, resp *HostAuthe
 	dfsAccess := p.Permissions&pool.DFSAccess != 0
 	signed, expires, err := auth.CreateJWTIdentity(host.ID, host.PoolID, adminAccess, dfsAccess, keypem, s.expiration)
 	if err != nil {
-		s.f.RemoveHostExpiration(s.context(), host.ID)
+		s.f.RemoveHostExpiration(ctx, host.ID)
 		return err
 	}
-	s.f.SetHostExpiration(s.context(), host.ID, expires)
+	s.f.SetHostExpiration(ctx, host.ID, expires)
 	*resp = HostAuthenticationResponse{signed, expires}
 	return nil
 }
 
+// GetServerTime returns the time on the master
+func (s *Server) GetServerTime(unused struct{}, now *time.Time) error {
+	*now = time.Now().UTC()
+	return nil
+}
+
 // Return host's public key
 func (s *Server) GetHostPublicKey(hostID string, key *[]byte) error {
-	publicKey, err := s.f.GetHostKey(s.context(), hostID)
+	ctx, cancel := s.context()
+	defer cancel()
+	publicKey, err := s.f.GetHostKey(ctx, hostID)
 	*key = publicKey
 	return err
 }
 
 // Reset and return host's private key
 func (s *Server) ResetHostKey(hostID string, key *[]byte) error {
-	publicKey, err := s.f.ResetHostKey(s.context(), hostID)
+	ctx, cancel := s.context()
+	defer cancel()
+	publicKey, err := s.f.ResetHostKey(ctx, hostID)
 	*key = publicKey
 	return err
 }
+
+// HostKeyVerificationRequest is the fingerprint of the delegate key that a
+// host has installed
+type HostKeyVerificationRequest struct {
+	HostID      string
+	Fingerprint string
+}
+
+// VerifyHostKey checks that a host installed the delegate key generated for it
+func (s *Server) VerifyHostKey(req HostKeyVerificationRequest, unused *int) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.VerifyHostKey(ctx, req.HostID, req.Fingerprint)
+}
+
+// GetHostStorage returns the usage of a host's storage pools
+func (s *Server) GetHostStorage(hostID string, reply *host.StorageHealth) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	storage, err := s.f.GetHostStorage(ctx, hostID)
+	if err != nil {
+		return err
+	} else if storage != nil {
+		*reply = *storage
+	}
+	return nil
+}
+
+// GetHostEvents returns the event history of a host
+func (s *Server) GetHostEvents(hostID string, reply *[]host.Event) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	events, err := s.f.GetHostEvents(ctx, hostID)
+	if err != nil {
+		return err
+	}
+	*reply = events
+	return nil
+}
+
+// UpgradeDelegates upgrades serviced on the delegates one host at a time
+func (s *Server) UpgradeDelegates(req host.UpgradeRequest, results *[]host.UpgradeResult) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	upgraded, err := s.f.UpgradeDelegates(ctx, req)
+	*results = upgraded
+	return err
+}
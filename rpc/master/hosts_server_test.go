@@ -0,0 +1,80 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package master
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/auth"
+	. "gopkg.in/check.v1"
+)
+
+type HostAuthSuite struct{}
+
+var _ = Suite(&HostAuthSuite{})
+
+func (s *HostAuthSuite) TestHostAuthenticationRequestSignsTimestamp(c *C) {
+	public, private, err := auth.GenerateRSAKeyPairPEM(nil)
+	c.Assert(err, IsNil)
+	signer, err := auth.RSASignerFromPEM(private)
+	c.Assert(err, IsNil)
+
+	now := time.Now()
+	req := HostAuthenticationRequest{
+		HostID:    "host1",
+		Expires:   now.Add(time.Minute).UTC().Unix(),
+		Timestamp: now.UnixNano(),
+	}
+	req.Signature, err = signer.Sign(req.toMessage())
+	c.Assert(err, IsNil)
+	c.Assert(req.valid(public), IsNil)
+
+	// a request replayed with a different clock reading is rejected
+	forged := req
+	forged.Timestamp = now.Add(-time.Hour).UnixNano()
+	c.Assert(forged.valid(public), NotNil)
+
+	// older delegates do not send a timestamp
+	legacy := HostAuthenticationRequest{HostID: "host1", Expires: req.Expires}
+	legacy.Signature, err = signer.Sign(legacy.toMessage())
+	c.Assert(err, IsNil)
+	c.Assert(legacy.valid(public), IsNil)
+}
+
+func (s *HostAuthSuite) TestHostAuthenticationRequestExpiry(c *C) {
+	public, private, err := auth.GenerateRSAKeyPairPEM(nil)
+	c.Assert(err, IsNil)
+	signer, err := auth.RSASignerFromPEM(private)
+	c.Assert(err, IsNil)
+
+	sign := func(expires int64) HostAuthenticationRequest {
+		req := HostAuthenticationRequest{HostID: "host1", Expires: expires}
+		req.Signature, err = signer.Sign(req.toMessage())
+		c.Assert(err, IsNil)
+		return req
+	}
+
+	// without a tolerance, requests expire exactly at their expiration time
+	now := time.Now().UTC().Unix()
+	c.Assert(sign(now+2).valid(public), IsNil)
+	c.Assert(sign(now).valid(public), Equals, ErrRequestExpired)
+
+	defer auth.SetClockSkewTolerance(auth.DefaultClockSkewTolerance)
+	auth.SetClockSkewTolerance(30 * time.Second)
+	now = time.Now().UTC().Unix()
+	c.Assert(sign(now-28).valid(public), IsNil)
+	c.Assert(sign(now-30).valid(public), Equals, ErrRequestExpired)
+}
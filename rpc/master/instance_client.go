@@ -13,7 +13,11 @@
 
 package master
 
-import "github.com/control-center/serviced/domain/service"
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+)
 
 // GetServiceInstances returns all instances of a service
 func (c *Client) GetServiceInstances(serviceID string) ([]service.Instance, error) {
@@ -26,6 +30,40 @@ func (c *Client) GetServiceInstances(serviceID string) ([]service.Instance, erro
 	return insts, nil
 }
 
+// WaitServiceInstances waits until an instance of any of the services changes
+// or the timeout expires, and returns the ids of the services that changed.
+func (c *Client) WaitServiceInstances(serviceIDs []string, timeout time.Duration) ([]string, error) {
+	req := WaitServiceInstancesRequest{
+		ServiceIDs: serviceIDs,
+		Timeout:    timeout,
+	}
+	changed := []string{}
+	if err := c.call("WaitServiceInstances", req, &changed); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// GetDeploymentStatus returns a summary of the state of the services in a
+// deployment
+func (c *Client) GetDeploymentStatus(deploymentID string) (*service.DeploymentStatus, error) {
+	status := &service.DeploymentStatus{}
+	if err := c.call("GetDeploymentStatus", deploymentID, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// GetHostStrategyInstances returns the scheduling details of the instances
+// running on a set of hosts
+func (c *Client) GetHostStrategyInstances(hostIDs []string) ([]service.StrategyInstance, error) {
+	insts := []service.StrategyInstance{}
+	if err := c.call("GetHostStrategyInstances", hostIDs, &insts); err != nil {
+		return nil, err
+	}
+	return insts, nil
+}
+
 // StopServiceInstance stops a service instance.
 func (c *Client) StopServiceInstance(serviceID string, instanceID int) error {
 	req := ServiceInstanceRequest{
@@ -52,6 +90,73 @@ func (c *Client) LocateServiceInstance(serviceID string, instanceID int) (*servi
 	return resp, nil
 }
 
+// GetPreviousInstanceOutput returns the output of the most recently exited
+// container of a service instance
+func (c *Client) GetPreviousInstanceOutput(serviceID string, instanceID int) (*service.InstanceOutput, error) {
+	req := ServiceInstanceRequest{
+		ServiceID:  serviceID,
+		InstanceID: instanceID,
+	}
+	resp := &service.InstanceOutput{}
+
+	err := c.call("GetPreviousInstanceOutput", req, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetInstanceLogs returns the lines that the container of a running service
+// instance wrote to stdout and stderr
+func (c *Client) GetInstanceLogs(serviceID string, instanceID int, since time.Time, tail int) ([]service.LogLine, error) {
+	req := InstanceLogsRequest{
+		ServiceID:  serviceID,
+		InstanceID: instanceID,
+		Since:      since,
+		Tail:       tail,
+	}
+	resp := []service.LogLine{}
+
+	err := c.call("GetInstanceLogs", req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetInstanceDiagnostics returns the output of the built-in diagnostic
+// actions that were run on a service instance
+func (c *Client) GetInstanceDiagnostics(serviceID string, instanceID int) ([]service.DiagnosticOutput, error) {
+	req := ServiceInstanceRequest{
+		ServiceID:  serviceID,
+		InstanceID: instanceID,
+	}
+	resp := []service.DiagnosticOutput{}
+
+	err := c.call("GetInstanceDiagnostics", req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CommitServiceInstance commits the container of a service instance and
+// returns the id of the snapshot
+func (c *Client) CommitServiceInstance(serviceID string, instanceID int, message, user string, snapshotSpacePercent int) (string, error) {
+	req := CommitServiceInstanceRequest{
+		ServiceID:            serviceID,
+		InstanceID:           instanceID,
+		Message:              message,
+		User:                 user,
+		SnapshotSpacePercent: snapshotSpacePercent,
+	}
+	var snapshotID string
+	if err := c.call("CommitServiceInstance", req, &snapshotID); err != nil {
+		return "", err
+	}
+	return snapshotID, nil
+}
+
 // SendDockerAction submits an action to a docker container
 func (c *Client) SendDockerAction(serviceID string, instanceID int, action string, args []string) error {
 	req := DockerActionRequest{
@@ -21,7 +21,9 @@ import (
 
 // GetServiceInstances returns all instances of a service
 func (s *Server) GetServiceInstances(serviceID string, res *[]service.Instance) (err error) {
-	insts, err := s.f.GetServiceInstances(s.context(), time.Now().Add(-time.Hour), serviceID)
+	ctx, cancel := s.context()
+	defer cancel()
+	insts, err := s.f.GetServiceInstances(ctx, time.Now().Add(-time.Hour), serviceID)
 	if err != nil {
 		return
 	}
@@ -29,6 +31,52 @@ func (s *Server) GetServiceInstances(serviceID string, res *[]service.Instance)
 	return
 }
 
+// WaitServiceInstancesRequest is the request to wait for the instances of a
+// set of services to change
+type WaitServiceInstancesRequest struct {
+	ServiceIDs []string
+	Timeout    time.Duration
+}
+
+// WaitServiceInstances waits until an instance of any of the services changes
+// or the timeout expires, and returns the ids of the services that changed
+func (s *Server) WaitServiceInstances(req WaitServiceInstancesRequest, res *[]string) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	serviceIDs, err := s.f.WaitServiceInstances(ctx, req.ServiceIDs, req.Timeout)
+	if err != nil {
+		return err
+	}
+	*res = serviceIDs
+	return nil
+}
+
+// GetDeploymentStatus returns a summary of the state of the services in a
+// deployment
+func (s *Server) GetDeploymentStatus(deploymentID string, res *service.DeploymentStatus) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	status, err := s.f.GetDeploymentStatus(ctx, deploymentID)
+	if err != nil {
+		return err
+	}
+	*res = *status
+	return nil
+}
+
+// GetHostStrategyInstances returns the scheduling details of the instances
+// running on a set of hosts
+func (s *Server) GetHostStrategyInstances(hostIDs []string, res *[]service.StrategyInstance) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	insts, err := s.f.GetHostStrategyInstances(ctx, hostIDs...)
+	if err != nil {
+		return err
+	}
+	*res = insts
+	return nil
+}
+
 type ServiceInstanceRequest struct {
 	ServiceID  string
 	InstanceID int
@@ -36,13 +84,17 @@ type ServiceInstanceRequest struct {
 
 // StopServiceInstance stops a single service instance
 func (s *Server) StopServiceInstance(req ServiceInstanceRequest, unused *string) (err error) {
-	err = s.f.StopServiceInstance(s.context(), req.ServiceID, req.InstanceID)
+	ctx, cancel := s.context()
+	defer cancel()
+	err = s.f.StopServiceInstance(ctx, req.ServiceID, req.InstanceID)
 	return
 }
 
 // LocateServiceInstance locates a single service instance
 func (s *Server) LocateServiceInstance(req ServiceInstanceRequest, res *service.LocationInstance) (err error) {
-	location, err := s.f.LocateServiceInstance(s.context(), req.ServiceID, req.InstanceID)
+	ctx, cancel := s.context()
+	defer cancel()
+	location, err := s.f.LocateServiceInstance(ctx, req.ServiceID, req.InstanceID)
 	if err != nil {
 		return
 	}
@@ -50,6 +102,72 @@ func (s *Server) LocateServiceInstance(req ServiceInstanceRequest, res *service.
 	return
 }
 
+// GetPreviousInstanceOutput returns the output of the most recently exited
+// container of a service instance
+func (s *Server) GetPreviousInstanceOutput(req ServiceInstanceRequest, res *service.InstanceOutput) (err error) {
+	ctx, cancel := s.context()
+	defer cancel()
+	output, err := s.f.GetPreviousInstanceOutput(ctx, req.ServiceID, req.InstanceID)
+	if err != nil {
+		return
+	}
+	*res = *output
+	return
+}
+
+// InstanceLogsRequest selects the log lines of a running service instance
+type InstanceLogsRequest struct {
+	ServiceID  string
+	InstanceID int
+	Since      time.Time // only lines written after this time; all if zero
+	Tail       int       // only the last lines; all if zero
+}
+
+// GetInstanceLogs returns the lines that the container of a running service
+// instance wrote to stdout and stderr
+func (s *Server) GetInstanceLogs(req InstanceLogsRequest, res *[]service.LogLine) (err error) {
+	ctx, cancel := s.context()
+	defer cancel()
+	lines, err := s.f.GetInstanceLogs(ctx, req.ServiceID, req.InstanceID, req.Since, req.Tail)
+	if err != nil {
+		return
+	}
+	*res = lines
+	return
+}
+
+// GetInstanceDiagnostics returns the output of the built-in diagnostic
+// actions that were run on a service instance
+func (s *Server) GetInstanceDiagnostics(req ServiceInstanceRequest, res *[]service.DiagnosticOutput) (err error) {
+	ctx, cancel := s.context()
+	defer cancel()
+	results, err := s.f.GetInstanceDiagnostics(ctx, req.ServiceID, req.InstanceID)
+	if err != nil {
+		return
+	}
+	*res = results
+	return
+}
+
+// CommitServiceInstanceRequest is the request to commit the container of a
+// service instance
+type CommitServiceInstanceRequest struct {
+	ServiceID            string
+	InstanceID           int
+	Message              string
+	User                 string
+	SnapshotSpacePercent int
+}
+
+// CommitServiceInstance commits the container of a service instance and
+// returns the id of the snapshot
+func (s *Server) CommitServiceInstance(req CommitServiceInstanceRequest, snapshotID *string) (err error) {
+	ctx, cancel := s.context()
+	defer cancel()
+	*snapshotID, err = s.f.CommitServiceInstance(ctx, req.ServiceID, req.InstanceID, req.Message, req.User, req.SnapshotSpacePercent)
+	return
+}
+
 type DockerActionRequest struct {
 	ServiceID  string
 	InstanceID int
@@ -59,6 +177,8 @@ type DockerActionRequest struct {
 
 // SendDockerAction submits an action to a docker container
 func (s *Server) SendDockerAction(req DockerActionRequest, unused *string) (err error) {
-	err = s.f.SendDockerAction(s.context(), req.ServiceID, req.InstanceID, req.Action, req.Args)
+	ctx, cancel := s.context()
+	defer cancel()
+	err = s.f.SendDockerAction(ctx, req.ServiceID, req.InstanceID, req.Action, req.Args)
 	return
 }
@@ -16,16 +16,28 @@ package master
 import (
 	"time"
 
+	"github.com/control-center/serviced/coordinator/storage"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/backup"
+	"github.com/control-center/serviced/domain/dbmigration"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/maintenance"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/schedule"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/statehistory"
 	"github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/volume"
+	"github.com/control-center/serviced/zzk/ensemble"
+	"github.com/control-center/serviced/zzk/ha"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 // The RPC interface is the API for a serviced master.
@@ -62,12 +74,29 @@ type ClientInterface interface {
 	// Authenticate a host and receive an identity token and expiration
 	AuthenticateHost(hostID string) (string, int64, error)
 
+	// Get the time on the master, so that a host can check its clock
+	GetServerTime() (time.Time, error)
+
 	// Get hostID's public key
 	GetHostPublicKey(hostID string) ([]byte, error)
 
 	// Reset hostID's private key
 	ResetHostKey(hostID string) ([]byte, error)
 
+	// VerifyHostKey checks that a host installed the delegate key generated
+	// for it
+	VerifyHostKey(hostID, fingerprint string) error
+
+	// GetHostStorage returns the usage of a host's storage pools, or nil if
+	// the host has not reported its storage
+	GetHostStorage(hostID string) (*host.StorageHealth, error)
+
+	// GetHostEvents returns the event history of a host, oldest first
+	GetHostEvents(hostID string) ([]host.Event, error)
+
+	// UpgradeDelegates upgrades serviced on the delegates one host at a time
+	UpgradeDelegates(req host.UpgradeRequest) ([]host.UpgradeResult, error)
+
 	//--------------------------------------------------------------------------
 	// Pool Management Functions
 
@@ -95,27 +124,103 @@ type ClientInterface interface {
 	// RemoveVirtualIP removes a VirtualIP from a specific pool
 	RemoveVirtualIP(requestVirtualIP pool.VirtualIP) error
 
+	// GetPoolPortClaims returns the host ports claimed by services in a pool
+	GetPoolPortClaims(poolID string) ([]pool.PortClaim, error)
+
+	// RebalanceAddressAssignments moves address assignments off of
+	// unavailable ips in a pool
+	RebalanceAddressAssignments(poolID string) ([]addressassignment.Reassignment, error)
+
+	// EnableChaos starts killing random instances in a pool at every interval
+	EnableChaos(poolID string, interval time.Duration) error
+
+	// DisableChaos stops killing instances in a pool
+	DisableChaos(poolID string) error
+
+	// GetChaosStatus returns the chaos mode settings and kill history of a pool
+	GetChaosStatus(poolID string) (*service.ChaosStatus, error)
+
+	// GetMasterHAStatus returns the failover state of the masters
+	GetMasterHAStatus() (*ha.ClusterStatus, error)
+
+	// GetZKEnsembleStatus returns the health of the quorum of the managed
+	// ZooKeeper ensemble
+	GetZKEnsembleStatus() (*ensemble.Status, error)
+
+	// AddZKEnsembleServer adds a server to the managed ZooKeeper ensemble
+	AddZKEnsembleServer(server ensemble.Server, force bool) (*ensemble.Status, error)
+
+	// RemoveZKEnsembleServer removes a server from the managed ZooKeeper
+	// ensemble
+	RemoveZKEnsembleServer(id string, force bool) (*ensemble.Status, error)
+
+	// CheckConsistency compares the datastore with the scheduling state in
+	// zookeeper, repairing the differences if requested
+	CheckConsistency(repair bool) (*zkservice.ConsistencyReport, error)
+
+	// CheckNetwork has the hosts of a pool probe the ports they need to reach
+	// on each other and on the master
+	CheckNetwork(poolID string) (*host.NetworkReport, error)
+
 	//--------------------------------------------------------------------------
 	// Service Management Functions
 
 	// ServiceUse will use a new image for a given service - this will pull the image and tag it
 	ServiceUse(serviceID string, imageID string, registry string, replaceImgs []string, noOp bool) (string, error)
 
+	// AssignDeploymentIPs plans address assignments for all services in a
+	// deployment and applies them unless dryRun is set
+	AssignDeploymentIPs(deploymentID string, dryRun bool) ([]addressassignment.PlannedAssignment, error)
+
 	// WaitService will wait for the specified services to reach the specified state, within the given timeout
 	WaitService(serviceIDs []string, state service.DesiredState, timeout time.Duration, recursive bool) error
 
+	// GetServiceStateHistory returns the changes of the desired state of a
+	// service, oldest first
+	GetServiceStateHistory(serviceID string) ([]statehistory.Change, error)
+
+	// QueryServices returns a page of the summaries of the services matching
+	// the query
+	QueryServices(query service.ServiceQuery) (*service.ServiceSummaryPage, error)
+
+	// ResolveServicePath returns the services that a service id or a
+	// deployment, pool, or name path resolves to
+	ResolveServicePath(servicePath string) ([]service.ServiceMatch, error)
+
+	// ClearEmergencyShutdown clears the emergency flag of a service and its
+	// children so that they can be started again, and returns the number of
+	// services that were cleared
+	ClearEmergencyShutdown(serviceID string) (int, error)
+
 	//--------------------------------------------------------------------------
 	// Service Instance Management Functions
 
 	// GetServiceInstances returns all running instances of a service
 	GetServiceInstances(serviceID string) ([]service.Instance, error)
 
+	// WaitServiceInstances waits until an instance of any of the services
+	// changes or the timeout expires, and returns the ids of the services
+	// that changed
+	WaitServiceInstances(serviceIDs []string, timeout time.Duration) ([]string, error)
+
+	// GetDeploymentStatus returns a summary of the state of the services in a
+	// deployment
+	GetDeploymentStatus(deploymentID string) (*service.DeploymentStatus, error)
+
 	// Get a service from serviced where all templated properties have been evaluated
 	GetEvaluatedService(serviceID string, instanceID int) (*service.Service, string, error)
 
+	// GetSnapshotService returns the definition of a service stored in a
+	// snapshot
+	GetSnapshotService(snapshotID, serviceID string) (*service.Service, error)
+
 	// Get the tenant ID for a service
 	GetTenantID(serviceID string) (string, error)
 
+	// GetHostStrategyInstances returns the scheduling details of the
+	// instances running on a set of hosts
+	GetHostStrategyInstances(hostIDs []string) ([]service.StrategyInstance, error)
+
 	// StopServiceInstance stops a single service instance
 	StopServiceInstance(serviceID string, instanceID int) error
 
@@ -123,6 +228,23 @@ type ClientInterface interface {
 	// instance
 	LocateServiceInstance(serviceID string, instanceID int) (*service.LocationInstance, error)
 
+	// GetPreviousInstanceOutput returns the output of the most recently
+	// exited container of a service instance
+	GetPreviousInstanceOutput(serviceID string, instanceID int) (*service.InstanceOutput, error)
+
+	// GetInstanceLogs returns the lines that the container of a running
+	// service instance wrote to stdout and stderr
+	GetInstanceLogs(serviceID string, instanceID int, since time.Time, tail int) ([]service.LogLine, error)
+
+	// GetInstanceDiagnostics returns the output of the built-in diagnostic
+	// actions that were run on a service instance
+	GetInstanceDiagnostics(serviceID string, instanceID int) ([]service.DiagnosticOutput, error)
+
+	// CommitServiceInstance commits the container of a service instance and
+	// returns the id of the snapshot.  user is recorded as the user that
+	// requested the snapshot.
+	CommitServiceInstance(serviceID string, instanceID int, message, user string, snapshotSpacePercent int) (string, error)
+
 	// SendDockerAction submits a docker action to a running container
 	SendDockerAction(serviceID string, instanceID int, action string, args []string) error
 
@@ -147,12 +269,84 @@ type ClientInterface interface {
 	// GetVolumeStatus gets status information for the given volume or nil
 	GetVolumeStatus() (*volume.Statuses, error)
 
+	// GetDFSStats returns the latency, throughput, and concurrency of dfs
+	// operations on the master
+	GetDFSStats() (*dfs.Stats, error)
+
+	// FreezeDFS makes the distributed filesystem read-only for maintenance
+	FreezeDFS(reason string) error
+
+	// ThawDFS makes the distributed filesystem writable after maintenance
+	ThawDFS() error
+
+	// GetDFSFreezeStatus returns whether the distributed filesystem is
+	// read-only for maintenance
+	GetDFSFreezeStatus() (*storage.FreezeStatus, error)
+
+	//--------------------------------------------------------------------------
+	// Backup Management Functions
+
+	// ListBackups returns the catalog of completed backups
+	ListBackups() ([]backup.Backup, error)
+
+	// EstimateBackup returns the estimated size and duration of a backup,
+	// excluding the given subdirectories of the tenant volumes
+	EstimateBackup(excludes []string) (*dfs.BackupEstimate, error)
+
+	// CleanupFailedRestore removes the data imported by restores that did not
+	// complete, or only lists it if dryRun is set
+	CleanupFailedRestore(dryRun bool) (*dfs.RestoreQuarantine, error)
+
+	//--------------------------------------------------------------------------
+	// Maintenance Window Management Functions
+
+	// AddMaintenanceWindow schedules a maintenance window and returns its id
+	AddMaintenanceWindow(window maintenance.Window) (string, error)
+
+	// RemoveMaintenanceWindow deletes a maintenance window
+	RemoveMaintenanceWindow(windowID string) error
+
+	// ListMaintenanceWindows returns all of the maintenance windows
+	ListMaintenanceWindows() ([]maintenance.Window, error)
+
+	//--------------------------------------------------------------------------
+	// Schedule Profile Management Functions
+
+	// AddScheduleProfile adds a schedule profile for a service and returns its id
+	AddScheduleProfile(profile schedule.Profile) (string, error)
+
+	// RemoveScheduleProfile deletes a schedule profile
+	RemoveScheduleProfile(profileID string) error
+
+	// ListScheduleProfiles returns all of the schedule profiles
+	ListScheduleProfiles() ([]schedule.Profile, error)
+
+	//--------------------------------------------------------------------------
+	// Service Config Management Functions
+
+	// GetServiceConfigConflicts returns the config files of a service that
+	// conflict with a redeploy of its template
+	GetServiceConfigConflicts(serviceID string) ([]service.ConfigConflict, error)
+
+	// ResolveServiceConfigConflict resolves the conflict of a config file
+	ResolveServiceConfigConflict(fileID, resolution, content string) error
+
+	//--------------------------------------------------------------------------
+	// Datastore Management Functions
+
+	// GetDatastoreMigrations returns the status of each datastore migration
+	GetDatastoreMigrations() ([]dbmigration.Status, error)
+
 	//--------------------------------------------------------------------------
 	// Endpoint Management Functions
 
 	// GetServiceEndpoints gets the endpoints for one or more services
 	GetServiceEndpoints(serviceIDs []string, reportImports, reportExports bool, validate bool) ([]applicationendpoint.EndpointReport, error)
 
+	// GetServiceEndpointStats gets the connections the mux has forwarded to
+	// the exported endpoints of a service
+	GetServiceEndpointStats(serviceID string) ([]applicationendpoint.EndpointStats, error)
+
 	//--------------------------------------------------------------------------
 	// Docker Registry Management Functions
 
@@ -167,6 +361,21 @@ type ClientInterface interface {
 	// UpgradeRegistry migrates images from an older or remote docker registry.
 	UpgradeRegistry(endpoint string, override bool) error
 
+	// MigrateRegistry moves images from one version of the local docker
+	// registry to another.
+	MigrateRegistry(fromVersion, toVersion int, override bool) error
+
+	// ListRegistryImages returns the images in the docker registry index,
+	// limited to a tenant's library if tenantID is set.
+	ListRegistryImages(tenantID string) ([]registry.ImageDetails, error)
+
+	// InspectRegistryImage returns an image in the docker registry index.
+	InspectRegistryImage(image string) (*registry.ImageDetails, error)
+
+	// PromoteImage copies an image in the docker registry to another tenant
+	// and returns the name of the new image.
+	PromoteImage(image, tenantID, tag string) (string, error)
+
 	// DockerOverride replaces an image in the docker registry with a new image
 	DockerOverride(newImage, oldImage string) error
 
@@ -193,12 +402,22 @@ type ClientInterface interface {
 	// Validate the credentials of the specified user
 	ValidateCredentials(user user.User) (bool, error)
 
+	// Get the tenants the user is allowed to access
+	GetUserTenants(userName string) ([]string, error)
+
+	// Restrict the user to the given tenants
+	SetUserTenants(userName string, tenantIDs []string) error
+
 	//--------------------------------------------------------------------------
 	// Healthcheck Management Functions
 
 	// GetISvcsHealth returns health status for a list of isvcs
 	GetISvcsHealth(IServiceNames []string) ([]isvcs.IServiceHealthResult, error)
 
+	// RestartISvc restarts the internal services matching the name with the
+	// resource settings in the master's configuration
+	RestartISvc(name string) ([]string, error)
+
 	// GetServicesHealth returns health checks for all services.
 	GetServicesHealth() (map[string]map[int]map[string]health.HealthStatus, error)
 
@@ -0,0 +1,25 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+// RestartISvc restarts the internal services matching the name and returns
+// the names of the internal services that were restarted.
+func (c *Client) RestartISvc(name string) ([]string, error) {
+	names := []string{}
+	request := ISvcRestartRequest{Name: name}
+	if err := c.call("RestartISvc", request, &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
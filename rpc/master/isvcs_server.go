@@ -0,0 +1,67 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"fmt"
+
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/isvcs"
+	"github.com/control-center/serviced/utils"
+)
+
+// ISvcRestartRequest is the request to restart internal services with
+// revised resource settings.
+type ISvcRestartRequest struct {
+	Name string
+}
+
+// RestartISvc restarts the internal services matching the name with the
+// resource settings in the master's configuration file applied over their
+// current resources, and returns the names of the internal services that were
+// restarted.
+func (s *Server) RestartISvc(request ISvcRestartRequest, names *[]string) error {
+	if isvcs.Mgr == nil {
+		return ErrNoISvcs
+	}
+	resources, err := isvcsResources(config.GetOptions().ConfigFile)
+	if err != nil {
+		return err
+	}
+	restarted, err := isvcs.Mgr.RestartService(request.Name, resources)
+	if err != nil {
+		return err
+	}
+	*names = restarted
+	return nil
+}
+
+// isvcsResources reads the internal service resource settings from the
+// configuration file, so that changes made since the master started can be
+// applied.  The environment of the master is left untouched.
+func isvcsResources(filename string) ([]string, error) {
+	values, err := utils.ReadEnvironFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	resources := []string{}
+	for i := 0; ; i++ {
+		val := values[fmt.Sprintf("SERVICED_ISVCS_RESOURCE_%d", i)]
+		if val == "" {
+			break
+		}
+		resources = append(resources, val)
+	}
+	return resources, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+// +build unit
+
+package master
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+type ISvcsResourcesSuite struct{}
+
+var _ = Suite(&ISvcsResourcesSuite{})
+
+func (s *ISvcsResourcesSuite) TestISvcsResourcesReadsConfigFile(c *C) {
+	filename := filepath.Join(c.MkDir(), "serviced")
+	err := ioutil.WriteFile(filename, []byte("SERVICED_ISVCS_RESOURCE_0=zookeeper:cpu_shares=512\n"), 0644)
+	c.Assert(err, IsNil)
+
+	// settings from the environment are neither read nor changed
+	os.Setenv("SERVICED_ISVCS_RESOURCE_0", "elasticsearch-logstash:heap=6g")
+	os.Setenv("SERVICED_ISVCS_RESOURCE_1", "elasticsearch-serviced:heap=2g")
+	defer os.Unsetenv("SERVICED_ISVCS_RESOURCE_0")
+	defer os.Unsetenv("SERVICED_ISVCS_RESOURCE_1")
+
+	resources, err := isvcsResources(filename)
+	c.Assert(err, IsNil)
+	c.Assert(resources, DeepEquals, []string{"zookeeper:cpu_shares=512"})
+	c.Assert(os.Getenv("SERVICED_ISVCS_RESOURCE_0"), Equals, "elasticsearch-logstash:heap=6g")
+	c.Assert(os.Getenv("SERVICED_ISVCS_RESOURCE_1"), Equals, "elasticsearch-serviced:heap=2g")
+}
+
+func (s *ISvcsResourcesSuite) TestISvcsResourcesMissingConfigFile(c *C) {
+	_, err := isvcsResources(filepath.Join(c.MkDir(), "serviced"))
+	c.Assert(err, NotNil)
+}
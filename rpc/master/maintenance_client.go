@@ -0,0 +1,39 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import "github.com/control-center/serviced/domain/maintenance"
+
+// AddMaintenanceWindow schedules a maintenance window and returns its id
+func (c *Client) AddMaintenanceWindow(window maintenance.Window) (string, error) {
+	var windowID string
+	if err := c.call("AddMaintenanceWindow", window, &windowID); err != nil {
+		return "", err
+	}
+	return windowID, nil
+}
+
+// RemoveMaintenanceWindow deletes a maintenance window
+func (c *Client) RemoveMaintenanceWindow(windowID string) error {
+	return c.call("RemoveMaintenanceWindow", windowID, nil)
+}
+
+// ListMaintenanceWindows returns all of the maintenance windows
+func (c *Client) ListMaintenanceWindows() ([]maintenance.Window, error) {
+	response := make([]maintenance.Window, 0)
+	if err := c.call("ListMaintenanceWindows", empty, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import "github.com/control-center/serviced/domain/maintenance"
+
+// AddMaintenanceWindow schedules a maintenance window and returns its id
+func (s *Server) AddMaintenanceWindow(window maintenance.Window, reply *string) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	if err := s.f.AddMaintenanceWindow(ctx, &window); err != nil {
+		return err
+	}
+	*reply = window.ID
+	return nil
+}
+
+// RemoveMaintenanceWindow deletes a maintenance window
+func (s *Server) RemoveMaintenanceWindow(windowID string, _ *struct{}) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.RemoveMaintenanceWindow(ctx, windowID)
+}
+
+// ListMaintenanceWindows returns all of the maintenance windows
+func (s *Server) ListMaintenanceWindows(empty struct{}, reply *[]maintenance.Window) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	windows, err := s.f.GetMaintenanceWindows(ctx)
+	if err != nil {
+		return err
+	}
+	*reply = windows
+	return nil
+}
@@ -3,16 +3,28 @@ package mocks
 import "github.com/stretchr/testify/mock"
 
 import "time"
+import "github.com/control-center/serviced/coordinator/storage"
+import "github.com/control-center/serviced/dfs"
+import "github.com/control-center/serviced/domain/addressassignment"
 import "github.com/control-center/serviced/domain/applicationendpoint"
+import "github.com/control-center/serviced/domain/backup"
+import "github.com/control-center/serviced/domain/dbmigration"
 import "github.com/control-center/serviced/domain/host"
+import "github.com/control-center/serviced/domain/maintenance"
+import "github.com/control-center/serviced/domain/schedule"
 import "github.com/control-center/serviced/domain/pool"
+import "github.com/control-center/serviced/domain/registry"
 import "github.com/control-center/serviced/domain/service"
 import "github.com/control-center/serviced/domain/servicedefinition"
 import "github.com/control-center/serviced/domain/servicetemplate"
+import "github.com/control-center/serviced/domain/statehistory"
 import "github.com/control-center/serviced/domain/user"
 import "github.com/control-center/serviced/health"
 import "github.com/control-center/serviced/isvcs"
 import "github.com/control-center/serviced/volume"
+import "github.com/control-center/serviced/zzk/ensemble"
+import "github.com/control-center/serviced/zzk/ha"
+import zkservice "github.com/control-center/serviced/zzk/service"
 
 type ClientInterface struct {
 	mock.Mock
@@ -185,6 +197,25 @@ func (_m *ClientInterface) AuthenticateHost(hostID string) (string, int64, error
 
 	return r0, r1, r2
 }
+func (_m *ClientInterface) GetServerTime() (time.Time, error) {
+	ret := _m.Called()
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ClientInterface) GetHostPublicKey(hostID string) ([]byte, error) {
 	ret := _m.Called(hostID)
 
@@ -227,6 +258,81 @@ func (_m *ClientInterface) ResetHostKey(hostID string) ([]byte, error) {
 
 	return r0, r1
 }
+func (_m *ClientInterface) VerifyHostKey(hostID string, fingerprint string) error {
+	ret := _m.Called(hostID, fingerprint)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(hostID, fingerprint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ClientInterface) UpgradeDelegates(req host.UpgradeRequest) ([]host.UpgradeResult, error) {
+	ret := _m.Called(req)
+
+	var r0 []host.UpgradeResult
+	if rf, ok := ret.Get(0).(func(host.UpgradeRequest) []host.UpgradeResult); ok {
+		r0 = rf(req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]host.UpgradeResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(host.UpgradeRequest) error); ok {
+		r1 = rf(req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) GetHostEvents(hostID string) ([]host.Event, error) {
+	ret := _m.Called(hostID)
+
+	var r0 []host.Event
+	if rf, ok := ret.Get(0).(func(string) []host.Event); ok {
+		r0 = rf(hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]host.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) GetHostStorage(hostID string) (*host.StorageHealth, error) {
+	ret := _m.Called(hostID)
+
+	var r0 *host.StorageHealth
+	if rf, ok := ret.Get(0).(func(string) *host.StorageHealth); ok {
+		r0 = rf(hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.StorageHealth)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ClientInterface) GetResourcePool(poolID string) (*pool.ResourcePool, error) {
 	ret := _m.Called(poolID)
 
@@ -326,6 +432,27 @@ func (_m *ClientInterface) GetPoolIPs(poolID string) (*pool.PoolIPs, error) {
 
 	return r0, r1
 }
+func (_m *ClientInterface) GetPoolPortClaims(poolID string) ([]pool.PortClaim, error) {
+	ret := _m.Called(poolID)
+
+	var r0 []pool.PortClaim
+	if rf, ok := ret.Get(0).(func(string) []pool.PortClaim); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.PortClaim)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ClientInterface) AddVirtualIP(requestVirtualIP pool.VirtualIP) error {
 	ret := _m.Called(requestVirtualIP)
 
@@ -350,6 +477,177 @@ func (_m *ClientInterface) RemoveVirtualIP(requestVirtualIP pool.VirtualIP) erro
 
 	return r0
 }
+func (_m *ClientInterface) RebalanceAddressAssignments(poolID string) ([]addressassignment.Reassignment, error) {
+	ret := _m.Called(poolID)
+
+	var r0 []addressassignment.Reassignment
+	if rf, ok := ret.Get(0).(func(string) []addressassignment.Reassignment); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]addressassignment.Reassignment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) EnableChaos(poolID string, interval time.Duration) error {
+	ret := _m.Called(poolID, interval)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Duration) error); ok {
+		r0 = rf(poolID, interval)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ClientInterface) DisableChaos(poolID string) error {
+	ret := _m.Called(poolID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(poolID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ClientInterface) GetChaosStatus(poolID string) (*service.ChaosStatus, error) {
+	ret := _m.Called(poolID)
+
+	var r0 *service.ChaosStatus
+	if rf, ok := ret.Get(0).(func(string) *service.ChaosStatus); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ChaosStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) GetMasterHAStatus() (*ha.ClusterStatus, error) {
+	ret := _m.Called()
+
+	var r0 *ha.ClusterStatus
+	if rf, ok := ret.Get(0).(func() *ha.ClusterStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ha.ClusterStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) GetZKEnsembleStatus() (*ensemble.Status, error) {
+	ret := _m.Called()
+
+	var r0 *ensemble.Status
+	if rf, ok := ret.Get(0).(func() *ensemble.Status); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ensemble.Status)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) AddZKEnsembleServer(server ensemble.Server, force bool) (*ensemble.Status, error) {
+	ret := _m.Called(server, force)
+
+	var r0 *ensemble.Status
+	if rf, ok := ret.Get(0).(func(ensemble.Server, bool) *ensemble.Status); ok {
+		r0 = rf(server, force)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ensemble.Status)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ensemble.Server, bool) error); ok {
+		r1 = rf(server, force)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) RemoveZKEnsembleServer(id string, force bool) (*ensemble.Status, error) {
+	ret := _m.Called(id, force)
+
+	var r0 *ensemble.Status
+	if rf, ok := ret.Get(0).(func(string, bool) *ensemble.Status); ok {
+		r0 = rf(id, force)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ensemble.Status)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(id, force)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) AssignDeploymentIPs(deploymentID string, dryRun bool) ([]addressassignment.PlannedAssignment, error) {
+	ret := _m.Called(deploymentID, dryRun)
+
+	var r0 []addressassignment.PlannedAssignment
+	if rf, ok := ret.Get(0).(func(string, bool) []addressassignment.PlannedAssignment); ok {
+		r0 = rf(deploymentID, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]addressassignment.PlannedAssignment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(deploymentID, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ClientInterface) ServiceUse(serviceID string, imageID string, registry string, replaceImgs []string, noOp bool) (string, error) {
 	ret := _m.Called(serviceID, imageID, registry, replaceImgs, noOp)
 
@@ -381,16 +679,14 @@ func (_m *ClientInterface) WaitService(serviceIDs []string, state service.Desire
 
 	return r0
 }
-func (_m *ClientInterface) GetServiceInstances(serviceID string) ([]service.Instance, error) {
+func (_m *ClientInterface) ClearEmergencyShutdown(serviceID string) (int, error) {
 	ret := _m.Called(serviceID)
 
-	var r0 []service.Instance
-	if rf, ok := ret.Get(0).(func(string) []service.Instance); ok {
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
 		r0 = rf(serviceID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.Instance)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
@@ -402,40 +698,187 @@ func (_m *ClientInterface) GetServiceInstances(serviceID string) ([]service.Inst
 
 	return r0, r1
 }
+func (_m *ClientInterface) GetServiceStateHistory(serviceID string) ([]statehistory.Change, error) {
+	ret := _m.Called(serviceID)
 
-// GetEvaluatedService provides a mock function with given fields: serviceID, instanceID
-func (_m *ClientInterface) GetEvaluatedService(serviceID string, instanceID int) (*service.Service, string, error) {
-	ret := _m.Called(serviceID, instanceID)
-
-	var r0 *service.Service
-	if rf, ok := ret.Get(0).(func(string, int) *service.Service); ok {
-		r0 = rf(serviceID, instanceID)
+	var r0 []statehistory.Change
+	if rf, ok := ret.Get(0).(func(string) []statehistory.Change); ok {
+		r0 = rf(serviceID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*service.Service)
+			r0 = ret.Get(0).([]statehistory.Change)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(string, int) string); ok {
-		r1 = rf(serviceID, instanceID)
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
 	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(string)
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) QueryServices(query service.ServiceQuery) (*service.ServiceSummaryPage, error) {
+	ret := _m.Called(query)
+
+	var r0 *service.ServiceSummaryPage
+	if rf, ok := ret.Get(0).(func(service.ServiceQuery) *service.ServiceSummaryPage); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ServiceSummaryPage)
 		}
 	}
 
-	var r2 error
-	if rf, ok := ret.Get(2).(func(string, int) error); ok {
-		r2 = rf(serviceID, instanceID)
+	var r1 error
+	if rf, ok := ret.Get(1).(func(service.ServiceQuery) error); ok {
+		r1 = rf(query)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
-func (_m *ClientInterface) GetTenantID(serviceID string) (string, error) {
-	ret := _m.Called(serviceID)
+func (_m *ClientInterface) ResolveServicePath(servicePath string) ([]service.ServiceMatch, error) {
+	ret := _m.Called(servicePath)
+
+	var r0 []service.ServiceMatch
+	if rf, ok := ret.Get(0).(func(string) []service.ServiceMatch); ok {
+		r0 = rf(servicePath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceMatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(servicePath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) GetServiceInstances(serviceID string) ([]service.Instance, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.Instance
+	if rf, ok := ret.Get(0).(func(string) []service.Instance); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.Instance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) WaitServiceInstances(serviceIDs []string, timeout time.Duration) ([]string, error) {
+	ret := _m.Called(serviceIDs, timeout)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func([]string, time.Duration) []string); ok {
+		r0 = rf(serviceIDs, timeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, time.Duration) error); ok {
+		r1 = rf(serviceIDs, timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) GetDeploymentStatus(deploymentID string) (*service.DeploymentStatus, error) {
+	ret := _m.Called(deploymentID)
+
+	var r0 *service.DeploymentStatus
+	if rf, ok := ret.Get(0).(func(string) *service.DeploymentStatus); ok {
+		r0 = rf(deploymentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.DeploymentStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(deploymentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEvaluatedService provides a mock function with given fields: serviceID, instanceID
+func (_m *ClientInterface) GetEvaluatedService(serviceID string, instanceID int) (*service.Service, string, error) {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 *service.Service
+	if rf, ok := ret.Get(0).(func(string, int) *service.Service); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.Service)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, int) string); ok {
+		r1 = rf(serviceID, instanceID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(string)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, int) error); ok {
+		r2 = rf(serviceID, instanceID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+func (_m *ClientInterface) GetSnapshotService(snapshotID string, serviceID string) (*service.Service, error) {
+	ret := _m.Called(snapshotID, serviceID)
+
+	var r0 *service.Service
+	if rf, ok := ret.Get(0).(func(string, string) *service.Service); ok {
+		r0 = rf(snapshotID, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(snapshotID, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) GetTenantID(serviceID string) (string, error) {
+	ret := _m.Called(serviceID)
 
 	var r0 string
 	if rf, ok := ret.Get(0).(func(string) string); ok {
@@ -445,141 +888,482 @@ func (_m *ClientInterface) GetTenantID(serviceID string) (string, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(serviceID)
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) StopServiceInstance(serviceID string, instanceID int) error {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int) error); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ClientInterface) LocateServiceInstance(serviceID string, instanceID int) (*service.LocationInstance, error) {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 *service.LocationInstance
+	if rf, ok := ret.Get(0).(func(string, int) *service.LocationInstance); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.LocationInstance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(serviceID, instanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetInstanceDiagnostics provides a mock function with given fields: serviceID, instanceID
+func (_m *ClientInterface) GetInstanceLogs(serviceID string, instanceID int, since time.Time, tail int) ([]service.LogLine, error) {
+	ret := _m.Called(serviceID, instanceID, since, tail)
+
+	var r0 []service.LogLine
+	if rf, ok := ret.Get(0).(func(string, int, time.Time, int) []service.LogLine); ok {
+		r0 = rf(serviceID, instanceID, since, tail)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.LogLine)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int, time.Time, int) error); ok {
+		r1 = rf(serviceID, instanceID, since, tail)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) GetInstanceDiagnostics(serviceID string, instanceID int) ([]service.DiagnosticOutput, error) {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 []service.DiagnosticOutput
+	if rf, ok := ret.Get(0).(func(string, int) []service.DiagnosticOutput); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.DiagnosticOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(serviceID, instanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+// GetPreviousInstanceOutput provides a mock function with given fields: serviceID, instanceID
+func (_m *ClientInterface) GetPreviousInstanceOutput(serviceID string, instanceID int) (*service.InstanceOutput, error) {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 *service.InstanceOutput
+	if rf, ok := ret.Get(0).(func(string, int) *service.InstanceOutput); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.InstanceOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(serviceID, instanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CommitServiceInstance provides a mock function with given fields: serviceID, instanceID, message, user, snapshotSpacePercent
+func (_m *ClientInterface) CommitServiceInstance(serviceID string, instanceID int, message string, user string, snapshotSpacePercent int) (string, error) {
+	ret := _m.Called(serviceID, instanceID, message, user, snapshotSpacePercent)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, int, string, string, int) string); ok {
+		r0 = rf(serviceID, instanceID, message, user, snapshotSpacePercent)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int, string, string, int) error); ok {
+		r1 = rf(serviceID, instanceID, message, user, snapshotSpacePercent)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) SendDockerAction(serviceID string, instanceID int, action string, args []string) error {
+	ret := _m.Called(serviceID, instanceID, action, args)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int, string, []string) error); ok {
+		r0 = rf(serviceID, instanceID, action, args)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ClientInterface) AddServiceTemplate(serviceTemplate servicetemplate.ServiceTemplate) (string, error) {
+	ret := _m.Called(serviceTemplate)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(servicetemplate.ServiceTemplate) string); ok {
+		r0 = rf(serviceTemplate)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(servicetemplate.ServiceTemplate) error); ok {
+		r1 = rf(serviceTemplate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) GetServiceTemplates() (map[string]servicetemplate.ServiceTemplate, error) {
+	ret := _m.Called()
+
+	var r0 map[string]servicetemplate.ServiceTemplate
+	if rf, ok := ret.Get(0).(func() map[string]servicetemplate.ServiceTemplate); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]servicetemplate.ServiceTemplate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) RemoveServiceTemplate(serviceTemplateID string) error {
+	ret := _m.Called(serviceTemplateID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(serviceTemplateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ClientInterface) DeployTemplate(request servicetemplate.ServiceTemplateDeploymentRequest) ([]string, error) {
+	ret := _m.Called(request)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(servicetemplate.ServiceTemplateDeploymentRequest) []string); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(servicetemplate.ServiceTemplateDeploymentRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) GetVolumeStatus() (*volume.Statuses, error) {
+	ret := _m.Called()
+
+	var r0 *volume.Statuses
+	if rf, ok := ret.Get(0).(func() *volume.Statuses); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*volume.Statuses)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) GetDFSStats() (*dfs.Stats, error) {
+	ret := _m.Called()
+
+	var r0 *dfs.Stats
+	if rf, ok := ret.Get(0).(func() *dfs.Stats); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.Stats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) FreezeDFS(reason string) error {
+	ret := _m.Called(reason)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ClientInterface) ThawDFS() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ClientInterface) GetDFSFreezeStatus() (*storage.FreezeStatus, error) {
+	ret := _m.Called()
+
+	var r0 *storage.FreezeStatus
+	if rf, ok := ret.Get(0).(func() *storage.FreezeStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.FreezeStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) ListBackups() ([]backup.Backup, error) {
+	ret := _m.Called()
+
+	var r0 []backup.Backup
+	if rf, ok := ret.Get(0).(func() []backup.Backup); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]backup.Backup)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) EstimateBackup(excludes []string) (*dfs.BackupEstimate, error) {
+	ret := _m.Called(excludes)
+
+	var r0 *dfs.BackupEstimate
+	if rf, ok := ret.Get(0).(func([]string) *dfs.BackupEstimate); ok {
+		r0 = rf(excludes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.BackupEstimate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(excludes)
 	} else {
 		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
-func (_m *ClientInterface) StopServiceInstance(serviceID string, instanceID int) error {
-	ret := _m.Called(serviceID, instanceID)
+func (_m *ClientInterface) CleanupFailedRestore(dryRun bool) (*dfs.RestoreQuarantine, error) {
+	ret := _m.Called(dryRun)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int) error); ok {
-		r0 = rf(serviceID, instanceID)
+	var r0 *dfs.RestoreQuarantine
+	if rf, ok := ret.Get(0).(func(bool) *dfs.RestoreQuarantine); ok {
+		r0 = rf(dryRun)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.RestoreQuarantine)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
-func (_m *ClientInterface) LocateServiceInstance(serviceID string, instanceID int) (*service.LocationInstance, error) {
-	ret := _m.Called(serviceID, instanceID)
+func (_m *ClientInterface) AddMaintenanceWindow(window maintenance.Window) (string, error) {
+	ret := _m.Called(window)
 
-	var r0 *service.LocationInstance
-	if rf, ok := ret.Get(0).(func(string, int) *service.LocationInstance); ok {
-		r0 = rf(serviceID, instanceID)
+	var r0 string
+	if rf, ok := ret.Get(0).(func(maintenance.Window) string); ok {
+		r0 = rf(window)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*service.LocationInstance)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, int) error); ok {
-		r1 = rf(serviceID, instanceID)
+	if rf, ok := ret.Get(1).(func(maintenance.Window) error); ok {
+		r1 = rf(window)
 	} else {
 		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
-func (_m *ClientInterface) SendDockerAction(serviceID string, instanceID int, action string, args []string) error {
-	ret := _m.Called(serviceID, instanceID, action, args)
+func (_m *ClientInterface) RemoveMaintenanceWindow(windowID string) error {
+	ret := _m.Called(windowID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int, string, []string) error); ok {
-		r0 = rf(serviceID, instanceID, action, args)
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(windowID)
 	} else {
 		r0 = ret.Error(0)
 	}
 
 	return r0
 }
-func (_m *ClientInterface) AddServiceTemplate(serviceTemplate servicetemplate.ServiceTemplate) (string, error) {
-	ret := _m.Called(serviceTemplate)
+func (_m *ClientInterface) ListMaintenanceWindows() ([]maintenance.Window, error) {
+	ret := _m.Called()
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(servicetemplate.ServiceTemplate) string); ok {
-		r0 = rf(serviceTemplate)
+	var r0 []maintenance.Window
+	if rf, ok := ret.Get(0).(func() []maintenance.Window); ok {
+		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]maintenance.Window)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(servicetemplate.ServiceTemplate) error); ok {
-		r1 = rf(serviceTemplate)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
 		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
-func (_m *ClientInterface) GetServiceTemplates() (map[string]servicetemplate.ServiceTemplate, error) {
-	ret := _m.Called()
+func (_m *ClientInterface) AddScheduleProfile(profile schedule.Profile) (string, error) {
+	ret := _m.Called(profile)
 
-	var r0 map[string]servicetemplate.ServiceTemplate
-	if rf, ok := ret.Get(0).(func() map[string]servicetemplate.ServiceTemplate); ok {
-		r0 = rf()
+	var r0 string
+	if rf, ok := ret.Get(0).(func(schedule.Profile) string); ok {
+		r0 = rf(profile)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]servicetemplate.ServiceTemplate)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(schedule.Profile) error); ok {
+		r1 = rf(profile)
 	} else {
 		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
-func (_m *ClientInterface) RemoveServiceTemplate(serviceTemplateID string) error {
-	ret := _m.Called(serviceTemplateID)
+func (_m *ClientInterface) RemoveScheduleProfile(profileID string) error {
+	ret := _m.Called(profileID)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(serviceTemplateID)
+		r0 = rf(profileID)
 	} else {
 		r0 = ret.Error(0)
 	}
 
 	return r0
 }
-func (_m *ClientInterface) DeployTemplate(request servicetemplate.ServiceTemplateDeploymentRequest) ([]string, error) {
-	ret := _m.Called(request)
+func (_m *ClientInterface) ListScheduleProfiles() ([]schedule.Profile, error) {
+	ret := _m.Called()
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func(servicetemplate.ServiceTemplateDeploymentRequest) []string); ok {
-		r0 = rf(request)
+	var r0 []schedule.Profile
+	if rf, ok := ret.Get(0).(func() []schedule.Profile); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).([]schedule.Profile)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(servicetemplate.ServiceTemplateDeploymentRequest) error); ok {
-		r1 = rf(request)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
 		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
-func (_m *ClientInterface) GetVolumeStatus() (*volume.Statuses, error) {
+func (_m *ClientInterface) GetDatastoreMigrations() ([]dbmigration.Status, error) {
 	ret := _m.Called()
 
-	var r0 *volume.Statuses
-	if rf, ok := ret.Get(0).(func() *volume.Statuses); ok {
+	var r0 []dbmigration.Status
+	if rf, ok := ret.Get(0).(func() []dbmigration.Status); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*volume.Statuses)
+			r0 = ret.Get(0).([]dbmigration.Status)
 		}
 	}
 
@@ -613,6 +1397,27 @@ func (_m *ClientInterface) GetServiceEndpoints(serviceIDs []string, reportImport
 
 	return r0, r1
 }
+func (_m *ClientInterface) GetServiceEndpointStats(serviceID string) ([]applicationendpoint.EndpointStats, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []applicationendpoint.EndpointStats
+	if rf, ok := ret.Get(0).(func(string) []applicationendpoint.EndpointStats); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]applicationendpoint.EndpointStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ClientInterface) ResetRegistry() error {
 	ret := _m.Called()
 
@@ -649,6 +1454,79 @@ func (_m *ClientInterface) UpgradeRegistry(endpoint string, override bool) error
 
 	return r0
 }
+func (_m *ClientInterface) MigrateRegistry(fromVersion int, toVersion int, override bool) error {
+	ret := _m.Called(fromVersion, toVersion, override)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, bool) error); ok {
+		r0 = rf(fromVersion, toVersion, override)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ClientInterface) ListRegistryImages(tenantID string) ([]registry.ImageDetails, error) {
+	ret := _m.Called(tenantID)
+
+	var r0 []registry.ImageDetails
+	if rf, ok := ret.Get(0).(func(string) []registry.ImageDetails); ok {
+		r0 = rf(tenantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]registry.ImageDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) InspectRegistryImage(image string) (*registry.ImageDetails, error) {
+	ret := _m.Called(image)
+
+	var r0 *registry.ImageDetails
+	if rf, ok := ret.Get(0).(func(string) *registry.ImageDetails); ok {
+		r0 = rf(image)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*registry.ImageDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(image)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) PromoteImage(image string, tenantID string, tag string) (string, error) {
+	ret := _m.Called(image, tenantID, tag)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
+		r0 = rf(image, tenantID, tag)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(image, tenantID, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ClientInterface) DockerOverride(newImage string, oldImage string) error {
 	ret := _m.Called(newImage, oldImage)
 
@@ -789,6 +1667,39 @@ func (_m *ClientInterface) ValidateCredentials(u user.User) (bool, error) {
 
 	return r0, r1
 }
+func (_m *ClientInterface) GetUserTenants(userName string) ([]string, error) {
+	ret := _m.Called(userName)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(userName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) SetUserTenants(userName string, tenantIDs []string) error {
+	ret := _m.Called(userName, tenantIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []string) error); ok {
+		r0 = rf(userName, tenantIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
 func (_m *ClientInterface) GetISvcsHealth(IServiceNames []string) ([]isvcs.IServiceHealthResult, error) {
 	ret := _m.Called(IServiceNames)
 
@@ -810,6 +1721,27 @@ func (_m *ClientInterface) GetISvcsHealth(IServiceNames []string) ([]isvcs.IServ
 
 	return r0, r1
 }
+func (_m *ClientInterface) RestartISvc(name string) ([]string, error) {
+	ret := _m.Called(name)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ClientInterface) GetServicesHealth() (map[string]map[int]map[string]health.HealthStatus, error) {
 	ret := _m.Called()
 
@@ -855,3 +1787,99 @@ func (_m *ClientInterface) ReportInstanceDead(serviceID string, instanceID int)
 
 	return r0
 }
+func (_m *ClientInterface) GetServiceConfigConflicts(serviceID string) ([]service.ConfigConflict, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.ConfigConflict
+	if rf, ok := ret.Get(0).(func(string) []service.ConfigConflict); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ConfigConflict)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) ResolveServiceConfigConflict(fileID string, resolution string, content string) error {
+	ret := _m.Called(fileID, resolution, content)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(fileID, resolution, content)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ClientInterface) GetHostStrategyInstances(hostIDs []string) ([]service.StrategyInstance, error) {
+	ret := _m.Called(hostIDs)
+
+	var r0 []service.StrategyInstance
+	if rf, ok := ret.Get(0).(func([]string) []service.StrategyInstance); ok {
+		r0 = rf(hostIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.StrategyInstance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(hostIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) CheckConsistency(repair bool) (*zkservice.ConsistencyReport, error) {
+	ret := _m.Called(repair)
+
+	var r0 *zkservice.ConsistencyReport
+	if rf, ok := ret.Get(0).(func(bool) *zkservice.ConsistencyReport); ok {
+		r0 = rf(repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*zkservice.ConsistencyReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ClientInterface) CheckNetwork(poolID string) (*host.NetworkReport, error) {
+	ret := _m.Called(poolID)
+
+	var r0 *host.NetworkReport
+	if rf, ok := ret.Get(0).(func(string) *host.NetworkReport); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.NetworkReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,28 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/host"
+)
+
+// CheckNetwork has the hosts of a pool probe the ports they need to reach on
+// each other and on the master
+func (c *Client) CheckNetwork(poolID string) (*host.NetworkReport, error) {
+	report := &host.NetworkReport{}
+	if err := c.call("CheckNetwork", poolID, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -14,6 +14,7 @@
 package master
 
 import (
+	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/pool"
 )
 
@@ -68,3 +69,22 @@ func (c *Client) AddVirtualIP(requestVirtualIP pool.VirtualIP) error {
 func (c *Client) RemoveVirtualIP(requestVirtualIP pool.VirtualIP) error {
 	return c.call("RemoveVirtualIP", requestVirtualIP, nil)
 }
+
+// GetPoolPortClaims returns the host ports claimed by services in a pool
+func (c *Client) GetPoolPortClaims(poolID string) ([]pool.PortClaim, error) {
+	response := make([]pool.PortClaim, 0)
+	if err := c.call("GetPoolPortClaims", poolID, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// RebalanceAddressAssignments moves address assignments off of unavailable ips
+// in a pool
+func (c *Client) RebalanceAddressAssignments(poolID string) ([]addressassignment.Reassignment, error) {
+	response := make([]addressassignment.Reassignment, 0)
+	if err := c.call("RebalanceAddressAssignments", poolID, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -16,12 +16,15 @@ package master
 import (
 	"errors"
 
+	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/pool"
 )
 
 // GetResourcePools returns all ResourcePools
 func (s *Server) GetResourcePools(empty struct{}, poolsReply *[]pool.ResourcePool) error {
-	pools, err := s.f.GetResourcePools(s.context())
+	ctx, cancel := s.context()
+	defer cancel()
+	pools, err := s.f.GetResourcePools(ctx)
 	if err != nil {
 		return err
 	}
@@ -32,17 +35,23 @@ func (s *Server) GetResourcePools(empty struct{}, poolsReply *[]pool.ResourcePoo
 
 // AddResourcePool adds the pool
 func (s *Server) AddResourcePool(pool pool.ResourcePool, _ *struct{}) error {
-	return s.f.AddResourcePool(s.context(), &pool)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.AddResourcePool(ctx, &pool)
 }
 
 // UpdateResourcePool updates the pool
 func (s *Server) UpdateResourcePool(pool pool.ResourcePool, _ *struct{}) error {
-	return s.f.UpdateResourcePool(s.context(), &pool)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.UpdateResourcePool(ctx, &pool)
 }
 
 // GetResourcePool gets the pool
 func (s *Server) GetResourcePool(poolID string, reply *pool.ResourcePool) error {
-	response, err := s.f.GetResourcePool(s.context(), poolID)
+	ctx, cancel := s.context()
+	defer cancel()
+	response, err := s.f.GetResourcePool(ctx, poolID)
 	if err != nil {
 		return err
 	}
@@ -55,12 +64,16 @@ func (s *Server) GetResourcePool(poolID string, reply *pool.ResourcePool) error
 
 // RemoveResourcePool removes the pool
 func (s *Server) RemoveResourcePool(poolID string, _ *struct{}) error {
-	return s.f.RemoveResourcePool(s.context(), poolID)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.RemoveResourcePool(ctx, poolID)
 }
 
 // GetPoolIPs gets all ips available to a pool
 func (s *Server) GetPoolIPs(poolID string, reply *pool.PoolIPs) error {
-	response, err := s.f.GetPoolIPs(s.context(), poolID)
+	ctx, cancel := s.context()
+	defer cancel()
+	response, err := s.f.GetPoolIPs(ctx, poolID)
 	if err != nil {
 		return err
 	}
@@ -73,10 +86,39 @@ func (s *Server) GetPoolIPs(poolID string, reply *pool.PoolIPs) error {
 
 // AddVirtualIP adds a specific virtual IP to a pool
 func (s *Server) AddVirtualIP(requestVirtualIP pool.VirtualIP, _ *struct{}) error {
-	return s.f.AddVirtualIP(s.context(), requestVirtualIP)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.AddVirtualIP(ctx, requestVirtualIP)
 }
 
 // RemoveVirtualIP removes a specific virtual IP from a pool
 func (s *Server) RemoveVirtualIP(requestVirtualIP pool.VirtualIP, _ *struct{}) error {
-	return s.f.RemoveVirtualIP(s.context(), requestVirtualIP)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.RemoveVirtualIP(ctx, requestVirtualIP)
+}
+
+// GetPoolPortClaims returns the host ports claimed by services in a pool
+func (s *Server) GetPoolPortClaims(poolID string, reply *[]pool.PortClaim) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	response, err := s.f.GetPoolPortClaims(ctx, poolID)
+	if err != nil {
+		return err
+	}
+	*reply = response
+	return nil
+}
+
+// RebalanceAddressAssignments moves address assignments off of unavailable
+// ips in a pool
+func (s *Server) RebalanceAddressAssignments(poolID string, reply *[]addressassignment.Reassignment) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	response, err := s.f.RebalanceAddressAssignments(ctx, poolID)
+	if err != nil {
+		return err
+	}
+	*reply = response
+	return nil
 }
@@ -30,7 +30,9 @@ type PublicEndpointRequest struct {
 
 // Adds a port public endpoint to a service.
 func (s *Server) AddPublicEndpointPort(request *PublicEndpointRequest, reply *servicedefinition.Port) error {
-	port, err := s.f.AddPublicEndpointPort(s.context(), request.Serviceid, request.EndpointName, request.Name,
+	ctx, cancel := s.context()
+	defer cancel()
+	port, err := s.f.AddPublicEndpointPort(ctx, request.Serviceid, request.EndpointName, request.Name,
 		request.UseTLS, request.Protocol, request.IsEnabled, request.Restart)
 	if err != nil {
 		return err
@@ -41,17 +43,23 @@ func (s *Server) AddPublicEndpointPort(request *PublicEndpointRequest, reply *se
 
 // Remove a port public endpoint from a service.
 func (s *Server) RemovePublicEndpointPort(request *PublicEndpointRequest, _ *struct{}) error {
-	return s.f.RemovePublicEndpointPort(s.context(), request.Serviceid, request.EndpointName, request.Name)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.RemovePublicEndpointPort(ctx, request.Serviceid, request.EndpointName, request.Name)
 }
 
 // Enable/disable a port public endpoint for a service.
 func (s *Server) EnablePublicEndpointPort(request *PublicEndpointRequest, _ *struct{}) error {
-	return s.f.EnablePublicEndpointPort(s.context(), request.Serviceid, request.EndpointName, request.Name, request.IsEnabled)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.EnablePublicEndpointPort(ctx, request.Serviceid, request.EndpointName, request.Name, request.IsEnabled)
 }
 
 // Adds a vhost public endpoint to a service.
 func (s *Server) AddPublicEndpointVHost(request *PublicEndpointRequest, reply *servicedefinition.VHost) error {
-	vhost, err := s.f.AddPublicEndpointVHost(s.context(), request.Serviceid, request.EndpointName, request.Name,
+	ctx, cancel := s.context()
+	defer cancel()
+	vhost, err := s.f.AddPublicEndpointVHost(ctx, request.Serviceid, request.EndpointName, request.Name,
 		request.IsEnabled, request.Restart)
 	if err != nil {
 		return err
@@ -62,10 +70,14 @@ func (s *Server) AddPublicEndpointVHost(request *PublicEndpointRequest, reply *s
 
 // Remove a vhost public endpoint from a service.
 func (s *Server) RemovePublicEndpointVHost(request *PublicEndpointRequest, _ *struct{}) error {
-	return s.f.RemovePublicEndpointVHost(s.context(), request.Serviceid, request.EndpointName, request.Name)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.RemovePublicEndpointVHost(ctx, request.Serviceid, request.EndpointName, request.Name)
 }
 
 // Enable/disable a vhost public endpoint for a service.
 func (s *Server) EnablePublicEndpointVHost(request *PublicEndpointRequest, _ *struct{}) error {
-	return s.f.EnablePublicEndpointVHost(s.context(), request.Serviceid, request.EndpointName, request.Name, request.IsEnabled)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.EnablePublicEndpointVHost(ctx, request.Serviceid, request.EndpointName, request.Name, request.IsEnabled)
 }
@@ -0,0 +1,276 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/rpc"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/rpc/rpcutils"
+)
+
+// replicaReads are the leader's read methods that a read replica serves from
+// its cache, by the name of the replica method that serves them.  Every other
+// call to the leader's rpc services is forwarded to the leader.
+var replicaReads = map[string]string{
+	"Master.GetHost":            "GetHost",
+	"Master.GetHosts":           "GetHosts",
+	"Master.GetService":         "GetService",
+	"Master.GetServicesHealth":  "GetServicesHealth",
+	"ControlCenter.GetServices": "GetServices",
+}
+
+// replicaName is the name the replica server is registered under
+const replicaName = "Replica"
+
+// isLeaderMethod returns true if the method belongs to one of the rpc
+// services that only the leader can serve.
+func isLeaderMethod(serviceMethod string) bool {
+	return strings.HasPrefix(serviceMethod, "Master.") || strings.HasPrefix(serviceMethod, "ControlCenter.")
+}
+
+// ForwardRequest is a call that a read replica forwards to the leader.  The
+// call is forwarded as it was received, with the signed auth header of its
+// caller, so that the leader authenticates the original caller.
+type ForwardRequest struct {
+	Method  string
+	header  []byte
+	request []byte
+}
+
+// SetRawRequest implements rpcutils.RawRequest
+func (req *ForwardRequest) SetRawRequest(header, request []byte) {
+	req.header, req.request = header, request
+}
+
+// forwardFunc sends a raw request to the leader and returns its raw result
+type forwardFunc func(header, request []byte) (json.RawMessage, error)
+
+// ReplicaServer serves the leader's reads from a cache that is refreshed
+// from the leader, and forwards all other calls to the leader.  A read
+// returns the cached value only if it was refreshed within the max staleness;
+// otherwise the read is forwarded to the leader.
+type ReplicaServer struct {
+	leader       rpcutils.Client
+	forward      forwardFunc
+	maxStaleness time.Duration
+
+	mu        sync.RWMutex
+	updated   time.Time
+	hosts     []host.Host
+	services  []service.Service
+	health    map[string]map[int]map[string]health.HealthStatus
+	refreshed bool
+}
+
+// NewReplicaServer creates a read replica of the master at the given address
+func NewReplicaServer(leaderAddr string, maxStaleness time.Duration) (*ReplicaServer, error) {
+	client, err := rpcutils.GetCachedClient(leaderAddr)
+	if err != nil {
+		return nil, err
+	}
+	forward := func(header, request []byte) (json.RawMessage, error) {
+		return rpcutils.ForwardRawRequest(leaderAddr, header, request, 0)
+	}
+	return newReplicaServer(client, forward, maxStaleness), nil
+}
+
+func newReplicaServer(leader rpcutils.Client, forward forwardFunc, maxStaleness time.Duration) *ReplicaServer {
+	return &ReplicaServer{leader: leader, forward: forward, maxStaleness: maxStaleness}
+}
+
+// Run refreshes the cache from the leader until shutdown.  The cache is
+// refreshed twice per max staleness so that reads are normally served from
+// the cache.
+func (s *ReplicaServer) Run(shutdown <-chan interface{}) {
+	interval := s.maxStaleness / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	for {
+		if err := s.Refresh(); err != nil {
+			plog.Warningf("Could not refresh the read replica from the leader: %s", err)
+		}
+		select {
+		case <-time.After(interval):
+		case <-shutdown:
+			return
+		}
+	}
+}
+
+// Refresh reloads the cache from the leader
+func (s *ReplicaServer) Refresh() error {
+	start := time.Now()
+	var hosts []host.Host
+	if err := s.leader.Call("Master.GetHosts", empty, &hosts, 0); err != nil {
+		return err
+	}
+	var services []service.Service
+	if err := s.leader.Call("ControlCenter.GetServices", dao.ServiceRequest{}, &services, 0); err != nil {
+		return err
+	}
+	var statuses map[string]map[int]map[string]health.HealthStatus
+	if err := s.leader.Call("Master.GetServicesHealth", empty, &statuses, 0); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hosts, s.services, s.health = hosts, services, statuses
+	s.updated = start
+	s.refreshed = true
+	plog.Debugf("Refreshed read replica with %d hosts and %d services in %s", len(hosts), len(services), time.Since(start))
+	return nil
+}
+
+// fresh returns true if the cache is within the max staleness; the caller
+// must hold the lock.
+func (s *ReplicaServer) fresh() bool {
+	return s.refreshed && time.Since(s.updated) <= s.maxStaleness
+}
+
+// Forward sends the call to the leader as the original caller and returns
+// the leader's raw reply
+func (s *ReplicaServer) Forward(req ForwardRequest, reply *json.RawMessage) error {
+	if !isLeaderMethod(req.Method) {
+		return fmt.Errorf("rpc: can't find method %s", req.Method)
+	}
+	if req.request == nil {
+		return fmt.Errorf("rpc: no request to forward for method %s", req.Method)
+	}
+	result, err := s.forward(req.header, req.request)
+	if err != nil {
+		return err
+	}
+	*reply = result
+	return nil
+}
+
+// GetHost serves Master.GetHost
+func (s *ReplicaServer) GetHost(hostID string, reply *host.Host) error {
+	s.mu.RLock()
+	if s.fresh() {
+		for _, h := range s.hosts {
+			if h.ID == hostID {
+				*reply = h
+				s.mu.RUnlock()
+				return nil
+			}
+		}
+	}
+	s.mu.RUnlock()
+	return s.leader.Call("Master.GetHost", hostID, reply, 0)
+}
+
+// GetHosts serves Master.GetHosts
+func (s *ReplicaServer) GetHosts(unused struct{}, reply *[]host.Host) error {
+	s.mu.RLock()
+	if s.fresh() {
+		*reply = s.hosts
+		s.mu.RUnlock()
+		return nil
+	}
+	s.mu.RUnlock()
+	return s.leader.Call("Master.GetHosts", empty, reply, 0)
+}
+
+// GetService serves Master.GetService
+func (s *ReplicaServer) GetService(serviceID string, reply *service.Service) error {
+	s.mu.RLock()
+	if s.fresh() {
+		for _, svc := range s.services {
+			if svc.ID == serviceID {
+				*reply = svc
+				s.mu.RUnlock()
+				return nil
+			}
+		}
+	}
+	s.mu.RUnlock()
+	return s.leader.Call("Master.GetService", serviceID, reply, 0)
+}
+
+// GetServices serves ControlCenter.GetServices.  Only unfiltered requests
+// are served from the cache.
+func (s *ReplicaServer) GetServices(request dao.ServiceRequest, reply *[]service.Service) error {
+	s.mu.RLock()
+	if s.fresh() && request.Unfiltered() {
+		*reply = s.services
+		s.mu.RUnlock()
+		return nil
+	}
+	s.mu.RUnlock()
+	return s.leader.Call("ControlCenter.GetServices", request, reply, 0)
+}
+
+// GetServicesHealth serves Master.GetServicesHealth
+func (s *ReplicaServer) GetServicesHealth(unused struct{}, reply *map[string]map[int]map[string]health.HealthStatus) error {
+	s.mu.RLock()
+	if s.fresh() {
+		*reply = s.health
+		s.mu.RUnlock()
+		return nil
+	}
+	s.mu.RUnlock()
+	return s.leader.Call("Master.GetServicesHealth", empty, reply, 0)
+}
+
+// NewReplicaServerCodec wraps the codec of a read replica's rpc connection
+// so that calls to the leader's rpc services are routed to the replica
+// server.  The wrapped codec must include the auth codec, which checks
+// authentication against the original method and passes forwarded calls
+// through with the auth header of their caller.
+func NewReplicaServerCodec(codec rpc.ServerCodec) rpc.ServerCodec {
+	return &replicaServerCodec{ServerCodec: codec}
+}
+
+type replicaServerCodec struct {
+	rpc.ServerCodec
+	forward string // method of the current request, if it is forwarded
+}
+
+// ReadRequestHeader rewrites the method of calls to the leader's services.
+// The rpc server always reads the header and body of a request back-to-back.
+func (c *replicaServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	c.forward = ""
+	if err := c.ServerCodec.ReadRequestHeader(r); err != nil {
+		return err
+	}
+	if method, ok := replicaReads[r.ServiceMethod]; ok {
+		r.ServiceMethod = replicaName + "." + method
+	} else if isLeaderMethod(r.ServiceMethod) || strings.HasPrefix(r.ServiceMethod, replicaName+".") {
+		// calls made directly to the replica server are forwarded as-is,
+		// so that they are rejected rather than trusted
+		c.forward = r.ServiceMethod
+		r.ServiceMethod = replicaName + ".Forward"
+	}
+	return nil
+}
+
+// ReadRequestBody sets the original method of forwarded calls
+func (c *replicaServerCodec) ReadRequestBody(body interface{}) error {
+	if req, ok := body.(*ForwardRequest); ok && c.forward != "" {
+		req.Method = c.forward
+	}
+	return c.ServerCodec.ReadRequestBody(body)
+}
@@ -0,0 +1,253 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package master
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/auth"
+	authmocks "github.com/control-center/serviced/auth/mocks"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/rpc/rpcutils"
+	. "gopkg.in/check.v1"
+)
+
+func TestReplica(t *testing.T) { TestingT(t) }
+
+type ReplicaSuite struct {
+	leader     *testLeader
+	leaderAuth *testAuth
+	client     *testLeaderClient
+	replica    *ReplicaServer
+	caller     *rpc.Client
+}
+
+var _ = Suite(&ReplicaSuite{})
+
+// testLeader implements the leader's Master and ControlCenter methods used
+// by the tests
+type testLeader struct {
+	hosts    []host.Host
+	services []service.Service
+}
+
+func (l *testLeader) GetHost(hostID string, reply *host.Host) error {
+	for _, h := range l.hosts {
+		if h.ID == hostID {
+			*reply = h
+			return nil
+		}
+	}
+	return errors.New("host not found")
+}
+
+func (l *testLeader) GetHosts(unused struct{}, reply *[]host.Host) error {
+	*reply = l.hosts
+	return nil
+}
+
+func (l *testLeader) GetService(serviceID string, reply *service.Service) error {
+	return errors.New("service not found")
+}
+
+func (l *testLeader) GetServicesHealth(unused struct{}, reply *map[string]map[int]map[string]health.HealthStatus) error {
+	*reply = map[string]map[int]map[string]health.HealthStatus{}
+	return nil
+}
+
+func (l *testLeader) GetServices(request dao.ServiceRequest, reply *[]service.Service) error {
+	*reply = l.services
+	if request.NameRegex != "" {
+		*reply = l.services[:1]
+	}
+	return nil
+}
+
+func (l *testLeader) RemoveHost(hostID string, _ *struct{}) error {
+	for i, h := range l.hosts {
+		if h.ID == hostID {
+			l.hosts = append(l.hosts[:i], l.hosts[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("host not found")
+}
+
+// testLeaderClient records the calls made to the leader
+type testLeaderClient struct {
+	client *rpc.Client
+	mu     sync.Mutex
+	calls  []string
+}
+
+func (c *testLeaderClient) Call(method string, args interface{}, reply interface{}, timeout time.Duration) error {
+	c.mu.Lock()
+	c.calls = append(c.calls, method)
+	c.mu.Unlock()
+	return c.client.Call(method, args, reply)
+}
+
+func (c *testLeaderClient) Close() error {
+	return c.client.Close()
+}
+
+func (c *testLeaderClient) reset() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	calls := c.calls
+	c.calls = nil
+	return calls
+}
+
+// testAuth signs requests with the name of the caller and records the
+// callers of the requests it authenticates
+type testAuth struct {
+	caller  string
+	admin   bool
+	mu      sync.Mutex
+	callers []string
+}
+
+func (a *testAuth) BuildHeader([]byte) ([]byte, error) {
+	return []byte(a.caller), nil
+}
+
+func (a *testAuth) ParseHeader(header []byte, request []byte) (auth.Identity, error) {
+	a.mu.Lock()
+	a.callers = append(a.callers, string(header))
+	a.mu.Unlock()
+	ident := &authmocks.Identity{}
+	ident.On("HasAdminAccess").Return(a.admin)
+	return ident, nil
+}
+
+func (a *testAuth) reset() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	callers := a.callers
+	a.callers = nil
+	return callers
+}
+
+func (s *ReplicaSuite) SetUpTest(c *C) {
+	s.leader = &testLeader{
+		hosts:    []host.Host{{ID: "host1"}, {ID: "host2"}},
+		services: []service.Service{{ID: "svc1"}, {ID: "svc2"}},
+	}
+	leaderServer := rpc.NewServer()
+	c.Assert(leaderServer.RegisterName("Master", s.leader), IsNil)
+	c.Assert(leaderServer.RegisterName("ControlCenter", s.leader), IsNil)
+	leaderConn, conn := net.Pipe()
+	go leaderServer.ServeCodec(jsonrpc.NewServerCodec(leaderConn))
+	s.client = &testLeaderClient{client: jsonrpc.NewClient(conn)}
+
+	// forwarded calls are authenticated by the leader
+	s.leaderAuth = &testAuth{admin: true}
+	forward := func(header, request []byte) (json.RawMessage, error) {
+		leaderConn, conn := net.Pipe()
+		defer conn.Close()
+		go leaderServer.ServeCodec(rpcutils.NewAuthServerCodec(leaderConn, jsonrpc.NewServerCodec, s.leaderAuth))
+		return rpcutils.RelayRawRequest(conn, header, request)
+	}
+
+	s.replica = newReplicaServer(s.client, forward, time.Minute)
+	replicaServer := rpc.NewServer()
+	c.Assert(replicaServer.RegisterName(replicaName, s.replica), IsNil)
+	replicaConn, conn := net.Pipe()
+	callerAuth := &testAuth{caller: "caller", admin: true}
+	go replicaServer.ServeCodec(NewReplicaServerCodec(rpcutils.NewAuthServerCodec(replicaConn, jsonrpc.NewServerCodec, callerAuth)))
+	s.caller = rpc.NewClientWithCodec(rpcutils.NewAuthClientCodec(conn, jsonrpc.NewClientCodec, callerAuth))
+}
+
+func (s *ReplicaSuite) TearDownTest(c *C) {
+	s.caller.Close()
+	s.client.Close()
+}
+
+func (s *ReplicaSuite) TestReadsFromCache(c *C) {
+	c.Assert(s.replica.Refresh(), IsNil)
+	c.Assert(s.client.reset(), HasLen, 3)
+
+	var hosts []host.Host
+	c.Assert(s.caller.Call("Master.GetHosts", struct{}{}, &hosts), IsNil)
+	c.Assert(hosts, HasLen, 2)
+	var h host.Host
+	c.Assert(s.caller.Call("Master.GetHost", "host2", &h), IsNil)
+	c.Assert(h.ID, Equals, "host2")
+	var svcs []service.Service
+	c.Assert(s.caller.Call("ControlCenter.GetServices", dao.ServiceRequest{}, &svcs), IsNil)
+	c.Assert(svcs, HasLen, 2)
+	var svc service.Service
+	c.Assert(s.caller.Call("Master.GetService", "svc1", &svc), IsNil)
+	c.Assert(svc.ID, Equals, "svc1")
+	c.Assert(s.client.reset(), HasLen, 0)
+
+	// filtered and missing reads go to the leader
+	c.Assert(s.caller.Call("ControlCenter.GetServices", dao.ServiceRequest{NameRegex: "svc1"}, &svcs), IsNil)
+	c.Assert(svcs, HasLen, 1)
+	err := s.caller.Call("Master.GetService", "svc3", &svc)
+	c.Assert(err, ErrorMatches, "service not found")
+	c.Assert(s.client.reset(), DeepEquals, []string{"ControlCenter.GetServices", "Master.GetService"})
+}
+
+func (s *ReplicaSuite) TestStaleReadsGoToLeader(c *C) {
+	// never refreshed
+	var hosts []host.Host
+	c.Assert(s.caller.Call("Master.GetHosts", struct{}{}, &hosts), IsNil)
+	c.Assert(hosts, HasLen, 2)
+	c.Assert(s.client.reset(), DeepEquals, []string{"Master.GetHosts"})
+
+	// refreshed longer ago than the max staleness
+	c.Assert(s.replica.Refresh(), IsNil)
+	s.client.reset()
+	s.replica.mu.Lock()
+	s.replica.updated = time.Now().Add(-2 * time.Minute)
+	s.replica.mu.Unlock()
+	c.Assert(s.caller.Call("Master.GetHosts", struct{}{}, &hosts), IsNil)
+	c.Assert(s.client.reset(), DeepEquals, []string{"Master.GetHosts"})
+}
+
+func (s *ReplicaSuite) TestWritesAreForwarded(c *C) {
+	c.Assert(s.caller.Call("Master.RemoveHost", "host1", &struct{}{}), IsNil)
+	c.Assert(s.leader.hosts, HasLen, 1)
+	err := s.caller.Call("Master.RemoveHost", "host1", &struct{}{})
+	c.Assert(err, ErrorMatches, "host not found")
+
+	// the leader authenticates the original caller, not the replica
+	c.Assert(s.leaderAuth.reset(), DeepEquals, []string{"caller", "caller"})
+	c.Assert(s.client.reset(), HasLen, 0)
+
+	// the leader's checks apply to the original caller
+	s.leaderAuth.admin = false
+	err = s.caller.Call("Master.RemoveHost", "host2", &struct{}{})
+	c.Assert(err, ErrorMatches, rpcutils.ErrNoAdmin.Error())
+	c.Assert(s.leader.hosts, HasLen, 1)
+
+	// calls to the replica itself are not forwarded
+	err = s.caller.Call("Replica.Forward", ForwardRequest{Method: "Master.RemoveHost"}, &struct{}{})
+	c.Assert(err, ErrorMatches, "rpc: can't find method Replica.Forward")
+	c.Assert(s.client.reset(), HasLen, 0)
+}
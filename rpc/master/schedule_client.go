@@ -0,0 +1,39 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import "github.com/control-center/serviced/domain/schedule"
+
+// AddScheduleProfile adds a schedule profile for a service and returns its id
+func (c *Client) AddScheduleProfile(profile schedule.Profile) (string, error) {
+	var profileID string
+	if err := c.call("AddScheduleProfile", profile, &profileID); err != nil {
+		return "", err
+	}
+	return profileID, nil
+}
+
+// RemoveScheduleProfile deletes a schedule profile
+func (c *Client) RemoveScheduleProfile(profileID string) error {
+	return c.call("RemoveScheduleProfile", profileID, nil)
+}
+
+// ListScheduleProfiles returns all of the schedule profiles
+func (c *Client) ListScheduleProfiles() ([]schedule.Profile, error) {
+	response := make([]schedule.Profile, 0)
+	if err := c.call("ListScheduleProfiles", empty, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
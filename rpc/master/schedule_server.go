@@ -0,0 +1,46 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import "github.com/control-center/serviced/domain/schedule"
+
+// AddScheduleProfile adds a schedule profile for a service and returns its id
+func (s *Server) AddScheduleProfile(profile schedule.Profile, reply *string) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	if err := s.f.AddScheduleProfile(ctx, &profile); err != nil {
+		return err
+	}
+	*reply = profile.ID
+	return nil
+}
+
+// RemoveScheduleProfile deletes a schedule profile
+func (s *Server) RemoveScheduleProfile(profileID string, _ *struct{}) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.RemoveScheduleProfile(ctx, profileID)
+}
+
+// ListScheduleProfiles returns all of the schedule profiles
+func (s *Server) ListScheduleProfiles(empty struct{}, reply *[]schedule.Profile) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	profiles, err := s.f.GetScheduleProfiles(ctx)
+	if err != nil {
+		return err
+	}
+	*reply = profiles
+	return nil
+}
@@ -18,20 +18,38 @@ import (
 
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/facade"
+	"golang.org/x/net/context"
 )
 
-// NewServer creates a new serviced master rpc server
-func NewServer(f *facade.Facade, tokenExpiration time.Duration) *Server {
-	return &Server{f, tokenExpiration}
+// NewServer creates a new serviced master rpc server.  Each call is given
+// callTimeout to complete before its context is canceled; a callTimeout of
+// zero or less lets calls run until they finish.
+func NewServer(f *facade.Facade, tokenExpiration, callTimeout time.Duration) *Server {
+	return &Server{f, tokenExpiration, callTimeout}
 }
 
 // Server is the RPC type for the master(s)
 type Server struct {
 	f          *facade.Facade
 	expiration time.Duration
+	timeout    time.Duration
 }
 
-func (s *Server) context() datastore.Context {
-	//here in case we ever need to create a per request context
-	return datastore.Get()
+// context returns the context of a single call, which is canceled once the
+// call timeout elapses.  The CancelFunc must be called when the call returns.
+func (s *Server) context() (datastore.Context, context.CancelFunc) {
+	return datastore.WithTimeout(datastore.GetTraced(), s.timeout)
+}
+
+// callContext returns the context of a single call that is also canceled
+// once the context of the rpc call is done, i.e. when the client
+// disconnects, and that is traced as part of the rpc call.  The CancelFunc
+// must be called when the call returns.
+func (s *Server) callContext(call context.Context) (datastore.Context, context.CancelFunc) {
+	ctx, cancel := datastore.WithCallContext(datastore.GetTracedFrom(call), call)
+	ctx, cancelTimeout := datastore.WithTimeout(ctx, s.timeout)
+	return ctx, func() {
+		cancelTimeout()
+		cancel()
+	}
 }
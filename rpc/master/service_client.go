@@ -16,15 +16,16 @@ package master
 import (
 	"time"
 
+	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/service"
-	"github.com/zenoss/glog"
+	"github.com/control-center/serviced/domain/statehistory"
 )
 
 // ServiceUse will use a new image for a given service - this will pull the image and tag it
 func (c *Client) ServiceUse(serviceID string, imageID string, registry string, replaceImgs []string, noOp bool) (string, error) {
 	svcUseRequest := &ServiceUseRequest{ServiceID: serviceID, ImageID: imageID, ReplaceImgs: replaceImgs, Registry: registry, NoOp: noOp}
 	result := ""
-	glog.Infof("Pulling %s, tagging to latest, and pushing to registry %s - this may take a while", imageID, registry)
+	plog.Infof("Pulling %s, tagging to latest, and pushing to registry %s - this may take a while", imageID, registry)
 	err := c.call("ServiceUse", svcUseRequest, &result)
 	if err != nil {
 		return "", err
@@ -46,6 +47,44 @@ func (c *Client) WaitService(serviceIDs []string, state service.DesiredState, ti
 	return err
 }
 
+// GetServiceStateHistory returns the changes of the desired state of a
+// service, oldest first
+func (c *Client) GetServiceStateHistory(serviceID string) ([]statehistory.Change, error) {
+	changes := []statehistory.Change{}
+	if err := c.call("GetServiceStateHistory", serviceID, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// QueryServices returns a page of the summaries of the services matching the
+// query
+func (c *Client) QueryServices(query service.ServiceQuery) (*service.ServiceSummaryPage, error) {
+	page := &service.ServiceSummaryPage{}
+	if err := c.call("QueryServices", query, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// ResolveServicePath returns the services that a service id or a
+// deployment, pool, or name path resolves to
+func (c *Client) ResolveServicePath(servicePath string) ([]service.ServiceMatch, error) {
+	matches := []service.ServiceMatch{}
+	if err := c.call("ResolveServicePath", servicePath, &matches); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// ClearEmergencyShutdown clears the emergency flag of a service and its
+// children, and returns the number of services that were cleared.
+func (c *Client) ClearEmergencyShutdown(serviceID string) (int, error) {
+	affected := 0
+	err := c.call("ClearEmergencyShutdown", serviceID, &affected)
+	return affected, err
+}
+
 // GetService returns a service with a particular service id.
 func (c *Client) GetService(serviceID string) (*service.Service, error) {
 	svc := &service.Service{}
@@ -53,6 +92,19 @@ func (c *Client) GetService(serviceID string) (*service.Service, error) {
 	return svc, err
 }
 
+// GetSnapshotService returns the definition of a service stored in a snapshot
+func (c *Client) GetSnapshotService(snapshotID, serviceID string) (*service.Service, error) {
+	request := SnapshotServiceRequest{
+		SnapshotID: snapshotID,
+		ServiceID:  serviceID,
+	}
+	svc := &service.Service{}
+	if err := c.call("GetSnapshotService", request, svc); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
 // GetEvaluatedService returns a service where an evaluation has been executed against all templated properties.
 func (c *Client) GetEvaluatedService(serviceID string, instanceID int) (*service.Service, string, error) {
 	request := EvaluateServiceRequest{
@@ -73,3 +125,17 @@ func (c *Client) GetTenantID(serviceID string) (string, error) {
 	err := c.call("GetTenantID", serviceID, &tenantID)
 	return tenantID, err
 }
+
+// AssignDeploymentIPs plans address assignments for all services in a
+// deployment and applies them unless dryRun is set
+func (c *Client) AssignDeploymentIPs(deploymentID string, dryRun bool) ([]addressassignment.PlannedAssignment, error) {
+	request := AssignDeploymentIPsRequest{
+		DeploymentID: deploymentID,
+		DryRun:       dryRun,
+	}
+	response := make([]addressassignment.PlannedAssignment, 0)
+	if err := c.call("AssignDeploymentIPs", request, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -16,7 +16,10 @@ package master
 import (
 	"time"
 
+	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/statehistory"
+	"golang.org/x/net/context"
 )
 
 type ServiceUseRequest struct {
@@ -32,6 +35,17 @@ type WaitServiceRequest struct {
 	State      service.DesiredState
 	Timeout    time.Duration
 	Recursive  bool
+	ctx        context.Context // canceled when the client disconnects
+}
+
+// SetCallContext implements rpcutils.CallContext
+func (r *WaitServiceRequest) SetCallContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// CallContext implements rpcutils.CallContext
+func (r *WaitServiceRequest) CallContext() context.Context {
+	return r.ctx
 }
 
 type EvaluateServiceRequest struct {
@@ -39,6 +53,16 @@ type EvaluateServiceRequest struct {
 	InstanceID int
 }
 
+type SnapshotServiceRequest struct {
+	SnapshotID string
+	ServiceID  string
+}
+
+type AssignDeploymentIPsRequest struct {
+	DeploymentID string
+	DryRun       bool
+}
+
 type EvaluateServiceResponse struct {
 	Service  service.Service
 	TenantID string
@@ -46,22 +70,101 @@ type EvaluateServiceResponse struct {
 
 // Use a new image for a given service - this will pull the image and tag it
 func (s *Server) ServiceUse(request *ServiceUseRequest, response *string) error {
-	if err := s.f.ServiceUse(s.context(), request.ServiceID, request.ImageID, request.Registry, request.ReplaceImgs, request.NoOp); err != nil {
+	ctx, cancel := s.context()
+	defer cancel()
+	if err := s.f.ServiceUse(ctx, request.ServiceID, request.ImageID, request.Registry, request.ReplaceImgs, request.NoOp); err != nil {
 		return err
 	}
 	*response = ""
 	return nil
 }
 
+// AssignDeploymentIPs plans (and optionally applies) address assignments for
+// all services in a deployment
+func (s *Server) AssignDeploymentIPs(request AssignDeploymentIPsRequest, reply *[]addressassignment.PlannedAssignment) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	response, err := s.f.AssignDeploymentIPs(ctx, request.DeploymentID, request.DryRun)
+	if err != nil {
+		return err
+	}
+	*reply = response
+	return nil
+}
+
 // Wait on specified services to be in the given state
 func (s *Server) WaitService(request *WaitServiceRequest, throwaway *string) error {
-	err := s.f.WaitService(s.context(), request.State, request.Timeout, request.Recursive, request.ServiceIDs...)
+	ctx, cancel := s.callContext(request.ctx)
+	defer cancel()
+	err := s.f.WaitService(ctx, request.State, request.Timeout, request.Recursive, request.ServiceIDs...)
 	return err
 }
 
+// GetServiceStateHistory returns the desired state changes of a service
+func (s *Server) GetServiceStateHistory(serviceID string, changes *[]statehistory.Change) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	result, err := s.f.GetServiceStateHistory(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	*changes = result
+	return nil
+}
+
+// QueryServices returns a page of the summaries of the services matching the query
+func (s *Server) QueryServices(query service.ServiceQuery, page *service.ServiceSummaryPage) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	result, err := s.f.QueryServices(ctx, query)
+	if err != nil {
+		return err
+	}
+	*page = *result
+	return nil
+}
+
+// ResolveServicePath returns the services that a service path resolves to
+func (s *Server) ResolveServicePath(servicePath string, matches *[]service.ServiceMatch) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	result, err := s.f.ResolveServicePath(ctx, servicePath)
+	if err != nil {
+		return err
+	}
+	*matches = result
+	return nil
+}
+
+// ClearEmergencyShutdown clears the emergency flag of a service and its children
+func (s *Server) ClearEmergencyShutdown(serviceID string, affected *int) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	count, err := s.f.ClearEmergencyShutdown(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	*affected = count
+	return nil
+}
+
 // Get a specific service
 func (s *Server) GetService(serviceID string, svc *service.Service) error {
-	sv, err := s.f.GetService(s.context(), serviceID)
+	ctx, cancel := s.context()
+	defer cancel()
+	sv, err := s.f.GetService(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	*svc = *sv
+	return nil
+}
+
+// GetSnapshotService returns the definition of a service stored in a snapshot
+func (s *Server) GetSnapshotService(request SnapshotServiceRequest, svc *service.Service) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	sv, err := s.f.GetSnapshotService(ctx, request.SnapshotID, request.ServiceID)
 	if err != nil {
 		return err
 	}
@@ -71,12 +174,14 @@ func (s *Server) GetService(serviceID string, svc *service.Service) error {
 
 // GetEvaluatedService returns a service where an evaluation has been executed against all templated properties.
 func (s *Server) GetEvaluatedService(request EvaluateServiceRequest, response *EvaluateServiceResponse) error {
-	svc, err := s.f.GetEvaluatedService(s.context(), request.ServiceID, request.InstanceID)
+	ctx, cancel := s.context()
+	defer cancel()
+	svc, err := s.f.GetEvaluatedService(ctx, request.ServiceID, request.InstanceID)
 	if err != nil {
 		return err
 	}
 
-	tenantID, err := s.f.GetTenantID(s.context(), request.ServiceID)
+	tenantID, err := s.f.GetTenantID(ctx, request.ServiceID)
 	if err != nil {
 		return err
 	}
@@ -87,7 +192,9 @@ func (s *Server) GetEvaluatedService(request EvaluateServiceRequest, response *E
 
 // The tenant id is the root service uuid. Walk the service tree to root to find the tenant id.
 func (s *Server) GetTenantID(serviceID string, tenantId *string) error {
-	result, err := s.f.GetTenantID(s.context(), serviceID)
+	ctx, cancel := s.context()
+	defer cancel()
+	result, err := s.f.GetTenantID(ctx, serviceID)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,35 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package master
+
+import "github.com/control-center/serviced/domain/service"
+
+// GetServiceConfigConflicts returns the config files of a service that
+// conflict with a redeploy of its template
+func (c *Client) GetServiceConfigConflicts(serviceID string) ([]service.ConfigConflict, error) {
+	response := make([]service.ConfigConflict, 0)
+	if err := c.call("GetServiceConfigConflicts", serviceID, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// ResolveServiceConfigConflict resolves the conflict of a config file
+func (c *Client) ResolveServiceConfigConflict(fileID, resolution, content string) error {
+	req := ResolveConfigConflictRequest{
+		FileID:     fileID,
+		Resolution: resolution,
+		Content:    content,
+	}
+	return c.call("ResolveServiceConfigConflict", req, nil)
+}
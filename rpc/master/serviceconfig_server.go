@@ -0,0 +1,43 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package master
+
+import "github.com/control-center/serviced/domain/service"
+
+// ResolveConfigConflictRequest are options for resolving the conflict of a
+// config file
+type ResolveConfigConflictRequest struct {
+	FileID     string
+	Resolution string
+	Content    string
+}
+
+// GetServiceConfigConflicts returns the config files of a service that
+// conflict with a redeploy of its template
+func (s *Server) GetServiceConfigConflicts(serviceID string, reply *[]service.ConfigConflict) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	conflicts, err := s.f.GetServiceConfigConflicts(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	*reply = conflicts
+	return nil
+}
+
+// ResolveServiceConfigConflict resolves the conflict of a config file
+func (s *Server) ResolveServiceConfigConflict(req ResolveConfigConflictRequest, _ *struct{}) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.ResolveServiceConfigConflict(ctx, req.FileID, req.Resolution, req.Content)
+}
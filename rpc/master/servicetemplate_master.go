@@ -19,7 +19,9 @@ import (
 
 // Add a new service template
 func (s *Server) AddServiceTemplate(serviceTemplate servicetemplate.ServiceTemplate, response *string) error  {
-	templateID, err := s.f.AddServiceTemplate(s.context(), serviceTemplate)
+	ctx, cancel := s.context()
+	defer cancel()
+	templateID, err := s.f.AddServiceTemplate(ctx, serviceTemplate)
 	if err != nil {
 		return err
 	}
@@ -29,7 +31,9 @@ func (s *Server) AddServiceTemplate(serviceTemplate servicetemplate.ServiceTempl
 
 // Get a list of service templates
 func (s *Server) GetServiceTemplates(unused struct{}, response *map[string]servicetemplate.ServiceTemplate) error  {
-	templates, err := s.f.GetServiceTemplates(s.context())
+	ctx, cancel := s.context()
+	defer cancel()
+	templates, err := s.f.GetServiceTemplates(ctx)
 	if err != nil {
 		return err
 	}
@@ -39,12 +43,16 @@ func (s *Server) GetServiceTemplates(unused struct{}, response *map[string]servi
 
 // Remove a service template
 func (s *Server) RemoveServiceTemplate(templateID string,  _ *struct{}) error  {
-	return s.f.RemoveServiceTemplate(s.context(), templateID)
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.RemoveServiceTemplate(ctx, templateID)
 }
 
 // Deploy a service template
 func (s *Server) DeployTemplate(request servicetemplate.ServiceTemplateDeploymentRequest, response *[]string) error  {
-	tenantIDs, err := s.f.DeployTemplate(s.context(), request.PoolID, request.TemplateID, request.DeploymentID)
+	ctx, cancel := s.context()
+	defer cancel()
+	tenantIDs, err := s.f.DeployTemplate(ctx, request.PoolID, request.TemplateID, request.DeploymentID)
 	if err != nil {
 		return err
 	}
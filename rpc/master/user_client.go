@@ -30,3 +30,16 @@ func (c *Client) ValidateCredentials(user user.User) (bool, error) {
 	err := c.call("ValidateCredentials", user, &result)
 	return result, err
 }
+
+// Get the tenants the user is allowed to access
+func (c *Client) GetUserTenants(userName string) ([]string, error) {
+	tenantIDs := []string{}
+	err := c.call("GetUserTenants", userName, &tenantIDs)
+	return tenantIDs, err
+}
+
+// Restrict the user to the given tenants
+func (c *Client) SetUserTenants(userName string, tenantIDs []string) error {
+	request := UserTenantsRequest{UserName: userName, TenantIDs: tenantIDs}
+	return c.call("SetUserTenants", request, nil)
+}
@@ -17,9 +17,17 @@ import (
 	"github.com/control-center/serviced/domain/user"
 )
 
+// UserTenantsRequest assigns tenants to a user
+type UserTenantsRequest struct {
+	UserName  string
+	TenantIDs []string
+}
+
 // Get the system user
 func (s *Server) GetSystemUser(unused struct{}, systemUser *user.User) error {
-	result, err := s.f.GetSystemUser(s.context())
+	ctx, cancel := s.context()
+	defer cancel()
+	result, err := s.f.GetSystemUser(ctx)
 	if err != nil {
 		return err
 	}
@@ -29,10 +37,31 @@ func (s *Server) GetSystemUser(unused struct{}, systemUser *user.User) error {
 
 // Validate the credentials of the specified user
 func (s *Server) ValidateCredentials(someUser user.User, valid *bool) error {
-	result, err := s.f.ValidateCredentials(s.context(), someUser)
+	ctx, cancel := s.context()
+	defer cancel()
+	result, err := s.f.ValidateCredentials(ctx, someUser)
 	if err != nil {
 		return err
 	}
 	*valid = result
 	return nil
 }
+
+// Get the tenants the user is allowed to access
+func (s *Server) GetUserTenants(userName string, tenantIDs *[]string) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	result, err := s.f.GetUserTenants(ctx, userName)
+	if err != nil {
+		return err
+	}
+	*tenantIDs = result
+	return nil
+}
+
+// Restrict the user to the given tenants
+func (s *Server) SetUserTenants(request UserTenantsRequest, _ *struct{}) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.SetUserTenants(ctx, request.UserName, request.TenantIDs)
+}
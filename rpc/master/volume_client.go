@@ -14,15 +14,46 @@
 package master
 
 import (
+	"github.com/control-center/serviced/coordinator/storage"
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/volume"
-	"github.com/zenoss/glog"
 )
 
 // GetVolumeStatus gets status information for the given volume or nil
 func (c *Client) GetVolumeStatus() (*volume.Statuses, error) {
 	response := &volume.Statuses{}
 	if err := c.call("GetVolumeStatus", empty, response); err != nil {
-		glog.V(2).Infof("\tcall to GetVolumeStatus returned error: %v", err)
+		plog.Debugf("\tcall to GetVolumeStatus returned error: %v", err)
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetDFSStats returns the latency, throughput, and concurrency of dfs
+// operations on the master
+func (c *Client) GetDFSStats() (*dfs.Stats, error) {
+	response := &dfs.Stats{}
+	if err := c.call("GetDFSStats", empty, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// FreezeDFS makes the distributed filesystem read-only for maintenance
+func (c *Client) FreezeDFS(reason string) error {
+	return c.call("FreezeDFS", reason, nil)
+}
+
+// ThawDFS makes the distributed filesystem writable after maintenance
+func (c *Client) ThawDFS() error {
+	return c.call("ThawDFS", empty, nil)
+}
+
+// GetDFSFreezeStatus returns whether the distributed filesystem is read-only
+// for maintenance
+func (c *Client) GetDFSFreezeStatus() (*storage.FreezeStatus, error) {
+	response := &storage.FreezeStatus{}
+	if err := c.call("GetDFSFreezeStatus", empty, response); err != nil {
 		return nil, err
 	}
 	return response, nil
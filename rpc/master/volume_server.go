@@ -16,18 +16,52 @@ package master
 import (
 	"errors"
 
+	"github.com/control-center/serviced/coordinator/storage"
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/volume"
-	"github.com/zenoss/glog"
 )
 
 // GetVolumeStatus gets the volume status
 func (s *Server) GetVolumeStatus(empty struct{}, reply *volume.Statuses) error {
-	glog.V(2).Infof("[volume_server.go]master.GetVolumeStatus(empty, %v)\n", reply)
+	plog.Debugf("[volume_server.go]master.GetVolumeStatus(empty, %v)\n", reply)
 	response := volume.GetStatus()
 	if response == nil {
-		glog.V(2).Infof("\tCall to volume.getStatus failed: Returning error.")
+		plog.Debugf("\tCall to volume.getStatus failed: Returning error.")
 		return errors.New("volume_server.go GetStatus failed")
 	}
 	*reply = *response
 	return nil
 }
+
+// GetDFSStats returns the latency, throughput, and concurrency of dfs
+// operations
+func (s *Server) GetDFSStats(empty struct{}, reply *dfs.Stats) error {
+	*reply = dfs.GetStats()
+	return nil
+}
+
+// FreezeDFS makes the distributed filesystem read-only for maintenance
+func (s *Server) FreezeDFS(reason string, _ *struct{}) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.FreezeDFS(ctx, reason)
+}
+
+// ThawDFS makes the distributed filesystem writable after maintenance
+func (s *Server) ThawDFS(empty struct{}, _ *struct{}) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	return s.f.ThawDFS(ctx)
+}
+
+// GetDFSFreezeStatus returns whether the distributed filesystem is read-only
+func (s *Server) GetDFSFreezeStatus(empty struct{}, reply *storage.FreezeStatus) error {
+	ctx, cancel := s.context()
+	defer cancel()
+	status, err := s.f.GetDFSFreezeStatus(ctx)
+	if err != nil {
+		return err
+	}
+	*reply = *status
+	return nil
+}
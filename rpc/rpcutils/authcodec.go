@@ -28,13 +28,14 @@ import (
 
 var (
 	// RPC Calls that do not require authentication or who handle authentication separately:
-	NonAuthenticatingCalls = []string{"Master.AuthenticateHost", "Agent.BuildHost"}
+	NonAuthenticatingCalls = []string{"Master.AuthenticateHost", "Master.GetServerTime", "Agent.BuildHost"}
 	// RPC calls that do not require admin access:
 	NonAdminRequiredCalls = map[string]struct{}{
 		"Master.GetHost":                         struct{}{},
 		"Master.GetHosts":                        struct{}{},
 		"Master.GetEvaluatedService":             struct{}{},
 		"Master.GetSystemUser":                   struct{}{},
+		"Master.GetHostStrategyInstances":        struct{}{},
 		"Master.ReportHealthStatus":              struct{}{},
 		"Master.ReportInstanceDead":              struct{}{},
 		"ControlCenter.GetServices":              struct{}{},
@@ -157,6 +158,14 @@ func (b *ByteBufferReadWriteCloser) Close() error {
 
 type ServerCodecCreator func(io.ReadWriteCloser) rpc.ServerCodec
 
+// RawRequest is implemented by rpc request types that take the request as it
+// was received, with the caller's signed auth header, instead of its decoded
+// arguments.  A read replica uses it to forward the call to the leader under
+// the identity of the original caller.
+type RawRequest interface {
+	SetRawRequest(header, request []byte)
+}
+
 // Server Codec
 type AuthServerCodec struct {
 	conn         io.ReadWriteCloser
@@ -165,6 +174,8 @@ type AuthServerCodec struct {
 	parser       auth.RPCHeaderParser
 	wBuffMutex   sync.Mutex // Make sure we buffer one response at a time
 	lastError    error
+	header       []byte // auth header of the current request
+	request      []byte // current request, as it was signed
 }
 
 func NewDefaultAuthServerCodec(conn io.ReadWriteCloser) rpc.ServerCodec {
@@ -192,6 +203,7 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 
 	// Reset state
 	a.lastError = nil
+	a.header, a.request = nil, nil
 	a.buff.ReadBuff.Reset()
 
 	// Read the header
@@ -208,6 +220,8 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 		return err
 	}
 
+	a.header, a.request = header, body
+
 	// Now write the actual request to the buffer
 	if _, err = a.buff.ReadBuff.Write(body); err != nil {
 		return err
@@ -234,21 +248,22 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 			log.WithField("ServiceMethod", r.ServiceMethod).Debug("Received unauthorized RPC request")
 			a.lastError = ErrNoAdmin
 		}
-
-		//TODO: save the identity so we can inject it into the request body later
-
 	}
 	return nil
 }
 
 // Decodes the request and populates the body object with the body of the request
-//  We don't change anything here, just let the underlying codec handle it.
+//  Bodies that implement RawRequest get the request as it was received, with
+//  its auth header, and the underlying codec discards the decoded arguments.
 //  This always gets called after ReadRequestHeader
 func (a *AuthServerCodec) ReadRequestBody(body interface{}) error {
 	if a.lastError != nil {
 		return a.lastError
 	}
-	// TODO: Use reflection and add the identity to the body if necessary
+	if raw, ok := body.(RawRequest); ok {
+		raw.SetRawRequest(a.header, a.request)
+		return a.wrappedcodec.ReadRequestBody(nil)
+	}
 	return a.wrappedcodec.ReadRequestBody(body)
 }
 
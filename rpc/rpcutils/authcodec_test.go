@@ -63,6 +63,9 @@ var (
 	ErrTestCodec      = errors.New("Error calling codec method")
 	ErrTestConnection = errors.New("Error calling connection method")
 	codectest         = NewAuthCodecTest()
+
+	// The suite replaces NonAdminRequiredCalls, so keep the production set
+	defaultNonAdminRequiredCalls = NonAdminRequiredCalls
 )
 
 // AuthServerCodec Tests
@@ -214,6 +217,45 @@ func (s *MySuite) TestReadRequestBody(c *C) {
 	c.Assert(err, IsNil)
 }
 
+type rawRequest struct {
+	header, request []byte
+}
+
+func (r *rawRequest) SetRawRequest(header, request []byte) {
+	r.header, r.request = header, request
+}
+
+func (s *MySuite) TestReadRequestBody_RawRequest(c *C) {
+	req := &rpc.Request{ServiceMethod: "RPCTestType.NonAuthenticatingCall"}
+	header := []byte("Header1")
+	body := []byte("Body1")
+	emptyLenBuff := make([]byte, LEN_BYTES)
+	readLength := func(n int) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			endian.PutUint32(args[0].([]byte), uint32(n))
+		}
+	}
+	readBytes := func(b []byte) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			copy(args[0].([]byte), b)
+		}
+	}
+	codectest.conn.On("Read", emptyLenBuff).Return(LEN_BYTES, nil).Run(readLength(len(header))).Once()
+	codectest.conn.On("Read", make([]byte, len(header))).Return(len(header), nil).Run(readBytes(header)).Once()
+	codectest.conn.On("Read", emptyLenBuff).Return(LEN_BYTES, nil).Run(readLength(len(body))).Once()
+	codectest.conn.On("Read", make([]byte, len(body))).Return(len(body), nil).Run(readBytes(body)).Once()
+	codectest.wrappedServerCodec.On("ReadRequestHeader", req).Return(nil).Once()
+	c.Assert(codectest.authServerCodec.ReadRequestHeader(req), IsNil)
+
+	// the raw request is passed on and the decoded arguments are discarded
+	raw := &rawRequest{}
+	codectest.wrappedServerCodec.On("ReadRequestBody", nil).Return(nil).Once()
+	c.Assert(codectest.authServerCodec.ReadRequestBody(raw), IsNil)
+	c.Assert(raw.header, DeepEquals, header)
+	c.Assert(raw.request, DeepEquals, body)
+	codectest.wrappedServerCodec.AssertExpectations(c)
+}
+
 func (s *MySuite) TestWriteResponse(c *C) {
 	body := 0
 	resp := &rpc.Response{}
@@ -390,3 +432,36 @@ func (s *MySuite) TestRequiresAdmin(c *C) {
 	result = requiresAdmin("RPCTestType.AdminRequiredCall")
 	c.Assert(result, Equals, true)
 }
+
+func (s *MySuite) TestReadRequestHeader_NonAdminDelegate(c *C) {
+	calls := NonAdminRequiredCalls
+	NonAdminRequiredCalls = defaultNonAdminRequiredCalls
+	defer func() { NonAdminRequiredCalls = calls }()
+
+	header := []byte("Header1")
+	body := []byte("Body1")
+	emptyLenBuff := make([]byte, LEN_BYTES)
+	readLength := func(n int) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			endian.PutUint32(args[0].([]byte), uint32(n))
+		}
+	}
+
+	// Delegates schedule host strategy services through the master
+	req := &rpc.Request{ServiceMethod: "Master.GetHostStrategyInstances"}
+	ident := &authmocks.Identity{}
+	ident.On("HasAdminAccess").Return(false)
+	codectest.conn.On("Read", emptyLenBuff).Return(LEN_BYTES, nil).Run(readLength(len(header))).Once()
+	codectest.conn.On("Read", make([]byte, len(header))).Return(len(header), nil).Once()
+	codectest.conn.On("Read", emptyLenBuff).Return(LEN_BYTES, nil).Run(readLength(len(body))).Once()
+	codectest.conn.On("Read", make([]byte, len(body))).Return(len(body), nil).Once()
+	codectest.wrappedServerCodec.On("ReadRequestHeader", req).Return(nil).Once()
+	codectest.headerParser.On("ParseHeader", mock.Anything, mock.Anything).Return(ident, nil).Once()
+	err := codectest.authServerCodec.ReadRequestHeader(req)
+	c.Assert(err, IsNil)
+	b := struct{}{}
+	codectest.wrappedServerCodec.On("ReadRequestBody", &b).Return(nil).Once()
+	err = codectest.authServerCodec.ReadRequestBody(&b)
+	c.Assert(err, IsNil)
+	codectest.wrappedServerCodec.AssertExpectations(c)
+}
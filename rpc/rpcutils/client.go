@@ -15,7 +15,9 @@ package rpcutils
 
 import (
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/rpc"
 	"sync"
@@ -23,7 +25,6 @@ import (
 
 	"github.com/control-center/serviced/commons/pool"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 )
 
 var dialTimeoutSecs = 30
@@ -36,7 +37,7 @@ func SetDialTimeout(timeout int) {
 type connectRPCFn func(add string) (*rpc.Client, error)
 
 func connectRPC(addr string) (*rpc.Client, error) {
-	glog.V(2).Infof("Connecting to %s", addr)
+	log.Debugf("Connecting to %s", addr)
 	conn, err := net.DialTimeout("tcp", addr, time.Duration(dialTimeoutSecs)*time.Second)
 	if err != nil {
 		return nil, err
@@ -45,7 +46,7 @@ func connectRPC(addr string) (*rpc.Client, error) {
 }
 
 func connectRPCTLS(addr string) (*rpc.Client, error) {
-	glog.V(2).Infof("Connecting to %s", addr)
+	log.Debugf("Connecting to %s", addr)
 
 	config := tls.Config{InsecureSkipVerify: !RPCCertVerify}
 	timeoutDialer := net.Dialer{Timeout: time.Duration(dialTimeoutSecs) * time.Second}
@@ -54,11 +55,69 @@ func connectRPCTLS(addr string) (*rpc.Client, error) {
 		return nil, err
 	}
 	cipher := conn.ConnectionState().CipherSuite
-	glog.V(2).Infof("RPC client connected with TLS cipher=%s (%d)\n", utils.GetCipherName(cipher), cipher)
+	log.Debugf("RPC client connected with TLS cipher=%s (%d)\n", utils.GetCipherName(cipher), cipher)
 
 	return NewDefaultAuthClient(conn), nil
 }
 
+// ForwardRawRequest sends a request, as it was received with its auth header,
+// to the server at addr on a new connection and returns the raw result.  The
+// server authenticates the request against the identity of its original
+// caller.
+func ForwardRawRequest(addr string, header, request []byte, timeout time.Duration) (json.RawMessage, error) {
+	dialer := net.Dialer{Timeout: time.Duration(dialTimeoutSecs) * time.Second}
+	var conn net.Conn
+	var err error
+	if RPCDisableTLS {
+		conn, err = dialer.Dial("tcp", addr)
+	} else {
+		config := tls.Config{InsecureSkipVerify: !RPCCertVerify}
+		conn, err = tls.DialWithDialer(&dialer, "tcp4", addr, &config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+	return RelayRawRequest(conn, header, request)
+}
+
+// RelayRawRequest writes a request with its auth header to conn and reads
+// back the raw result.  Errors returned by the server are returned as an
+// rpc.ServerError, as by an rpc client.
+func RelayRawRequest(conn io.ReadWriter, header, request []byte) (json.RawMessage, error) {
+	if err := WriteLengthAndBytes(header, conn); err != nil {
+		return nil, err
+	}
+	if err := WriteLengthAndBytes(request, conn); err != nil {
+		return nil, err
+	}
+	response, err := ReadLengthAndBytes(conn)
+	if err != nil {
+		return nil, err
+	}
+	var reply struct {
+		Result json.RawMessage `json:"result"`
+		Error  interface{}     `json:"error"`
+	}
+	if err := json.Unmarshal(response, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Error != nil {
+		msg, ok := reply.Error.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid error %v", reply.Error)
+		}
+		if msg == "" {
+			msg = "unspecified error"
+		}
+		return nil, rpc.ServerError(msg)
+	}
+	return reply.Result, nil
+}
+
 // newClient that will create at most max active rpc connections at any given time. discardClientTimeout timeout for
 // discarding client from pool if a call takes too long, call will not be cancelled; assures liveliness of pool
 func newClient(addr string, max int, discardClientTimeout time.Duration, fn connectRPCFn) (Client, error) {
@@ -83,6 +142,13 @@ type reconnectingClient struct {
 }
 
 func (rc *reconnectingClient) Call(serviceMethod string, args interface{}, reply interface{}, timeout time.Duration) error {
+	start := time.Now()
+	err := rc.call(serviceMethod, args, reply, timeout)
+	recordCall(start, err)
+	return err
+}
+
+func (rc *reconnectingClient) call(serviceMethod string, args interface{}, reply interface{}, timeout time.Duration) error {
 	if timeout <= 0 {
 		timeout = 365 * 24 * time.Hour
 	}
@@ -114,7 +180,7 @@ func (rc *reconnectingClient) Call(serviceMethod string, args interface{}, reply
 	wg.Add(1)
 	go func() {
 		wg.Done()
-		glog.V(2).Infof("rpcClient: Call %s", serviceMethod)
+		log.Debugf("rpcClient: Call %s", serviceMethod)
 		rpcErr := rpcClient.Call(serviceMethod, args, reply)
 		errChan <- rpcErr
 	}()
@@ -147,9 +213,9 @@ func (rc *reconnectingClient) Call(serviceMethod string, args interface{}, reply
 			return err
 		case <-time.After(rc.discardClientTimeout):
 			//log long calls and remove from pool to prevent blocks
-			glog.V(2).Infof("Long Running Call %s %s", serviceMethod, time.Now().Sub(start))
+			log.Debugf("Long Running Call %s %s", serviceMethod, time.Now().Sub(start))
 			if !clientRemoved {
-				glog.V(2).Infof("Long Running Call removing client from pool %s after %s\n", serviceMethod, time.Now().Sub(start))
+				log.Debugf("Long Running Call removing client from pool %s after %s\n", serviceMethod, time.Now().Sub(start))
 				rc.pool.Remove(item)
 				clientRemoved = true
 			}
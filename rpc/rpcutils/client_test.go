@@ -378,3 +378,18 @@ func (s *MySuite) TestConcurrentClientCalls(c *C) {
 
 	wg.Wait()
 }
+
+func (s *MySuite) TestCallStats(c *C) {
+	before := GetCallStats()
+
+	var reply string
+	err := rpcClient.Call("RPCTestType.Echo", "hello", &reply, 0)
+	c.Assert(err, IsNil)
+	err = rpcClient.Call("RPCTestType.Missing", "hello", &reply, 0)
+	c.Assert(err, NotNil)
+
+	after := GetCallStats()
+	c.Assert(after.Calls-before.Calls, Equals, int64(2))
+	c.Assert(after.Errors-before.Errors, Equals, int64(1))
+	c.Assert(after.Duration > before.Duration, Equals, true)
+}
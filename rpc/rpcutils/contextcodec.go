@@ -0,0 +1,70 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcutils
+
+import (
+	"net/rpc"
+
+	"golang.org/x/net/context"
+)
+
+// CallContext is implemented by rpc request types that take the context of
+// the connection the call arrived on, which is canceled when the client
+// disconnects.
+type CallContext interface {
+	SetCallContext(ctx context.Context)
+	CallContext() context.Context
+}
+
+// NewContextServerCodec returns a codec that cancels the context of the
+// connection when the client disconnects, and passes that context to the
+// requests that implement CallContext.
+func NewContextServerCodec(codec rpc.ServerCodec) rpc.ServerCodec {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &contextServerCodec{ServerCodec: codec, ctx: ctx, cancel: cancel}
+}
+
+type contextServerCodec struct {
+	rpc.ServerCodec
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// ReadRequestHeader cancels the context once the connection stops delivering
+// requests.  The rpc server only closes the codec after the calls in flight
+// have returned, so the read error is the first sign that the client left.
+func (c *contextServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	err := c.ServerCodec.ReadRequestHeader(r)
+	if err != nil {
+		c.cancel()
+	}
+	return err
+}
+
+// ReadRequestBody passes the context of the connection to the request
+func (c *contextServerCodec) ReadRequestBody(body interface{}) error {
+	if err := c.ServerCodec.ReadRequestBody(body); err != nil {
+		return err
+	}
+	if call, ok := body.(CallContext); ok {
+		call.SetCallContext(c.ctx)
+	}
+	return nil
+}
+
+// Close cancels the context of the connection
+func (c *contextServerCodec) Close() error {
+	c.cancel()
+	return c.ServerCodec.Close()
+}
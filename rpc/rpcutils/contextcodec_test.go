@@ -0,0 +1,131 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package rpcutils
+
+import (
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"time"
+
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/rpc/rpcutils/mocks"
+	"golang.org/x/net/context"
+	. "gopkg.in/check.v1"
+)
+
+type ContextCodecSuite struct{}
+
+var _ = Suite(&ContextCodecSuite{})
+
+type contextRequest struct {
+	ctx context.Context
+}
+
+func (r *contextRequest) SetCallContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+func (r *contextRequest) CallContext() context.Context {
+	return r.ctx
+}
+
+func (s *ContextCodecSuite) TestContextServerCodec(c *C) {
+	wrapped := &mocks.ServerCodec{}
+	codec := NewContextServerCodec(wrapped)
+
+	req := &rpc.Request{ServiceMethod: "ControlCenter.GetServices"}
+	wrapped.On("ReadRequestHeader", req).Return(nil).Once()
+	c.Assert(codec.ReadRequestHeader(req), IsNil)
+	body := &contextRequest{}
+	wrapped.On("ReadRequestBody", body).Return(nil).Once()
+	c.Assert(codec.ReadRequestBody(body), IsNil)
+	c.Assert(body.ctx, NotNil)
+	c.Assert(body.ctx.Err(), IsNil)
+
+	// requests that do not take a context are left alone
+	var id string
+	wrapped.On("ReadRequestBody", &id).Return(nil).Once()
+	c.Assert(codec.ReadRequestBody(&id), IsNil)
+
+	// the client disconnects while the call is in flight
+	next := &rpc.Request{}
+	wrapped.On("ReadRequestHeader", next).Return(io.EOF).Once()
+	c.Assert(codec.ReadRequestHeader(next), Equals, io.EOF)
+	c.Assert(body.ctx.Err(), Equals, context.Canceled)
+
+	wrapped.On("Close").Return(nil).Once()
+	c.Assert(codec.Close(), IsNil)
+	wrapped.AssertExpectations(c)
+}
+
+// controlCenter blocks each call until the caller's context is done
+type controlCenter struct {
+	started chan string
+}
+
+func (cc *controlCenter) GetServices(request dao.ServiceRequest, reply *[]service.Service) error {
+	cc.started <- "GetServices"
+	<-request.CallContext().Done()
+	return request.CallContext().Err()
+}
+
+func (cc *controlCenter) RemoveService(request dao.RemoveServiceRequest, unused *int) error {
+	cc.started <- "RemoveService"
+	<-request.CallContext().Done()
+	return request.CallContext().Err()
+}
+
+func (s *ContextCodecSuite) TestContextServerCodec_Disconnect(c *C) {
+	for _, method := range []string{"GetServices", "RemoveService"} {
+		cc := &controlCenter{started: make(chan string, 1)}
+		server := rpc.NewServer()
+		c.Assert(server.RegisterName("ControlCenter", cc), IsNil)
+
+		serverConn, clientConn := net.Pipe()
+		done := make(chan struct{})
+		go func() {
+			server.ServeCodec(NewContextServerCodec(jsonrpc.NewServerCodec(serverConn)))
+			close(done)
+		}()
+		client := jsonrpc.NewClient(clientConn)
+
+		var call *rpc.Call
+		if method == "GetServices" {
+			call = client.Go("ControlCenter.GetServices", dao.ServiceRequest{}, &[]service.Service{}, nil)
+		} else {
+			call = client.Go("ControlCenter.RemoveService", dao.RemoveServiceRequest{ServiceID: "tenant"}, new(int), nil)
+		}
+		select {
+		case started := <-cc.started:
+			c.Assert(started, Equals, method)
+		case <-call.Done:
+			c.Fatalf("%s returned before the client disconnected: %v", method, call.Error)
+		case <-time.After(5 * time.Second):
+			c.Fatalf("%s was never called", method)
+		}
+
+		// ServeCodec only returns once the pending call has returned
+		client.Close()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			c.Fatalf("%s was not canceled when the client disconnected", method)
+		}
+	}
+}
@@ -19,8 +19,6 @@ import (
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/zenoss/glog"
 )
 
 type method struct {
@@ -109,7 +107,7 @@ func (l *localClient) lookup(serviceMethod string) (reflect.Value, reflect.Type,
 }
 
 func (l *localClient) Call(serviceMethod string, args interface{}, reply interface{}, timeout time.Duration) error {
-	glog.V(3).Infof("RPC service method %s", serviceMethod)
+	log.Debugf("RPC service method %s", serviceMethod)
 	callChan := make(chan error, 1)
 
 	go func() {
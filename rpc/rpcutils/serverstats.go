@@ -0,0 +1,192 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/rpc"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// ServerMetrics is the registry of the call counts, latencies, and errors of
+// the rpc methods served by this host.
+var ServerMetrics = metrics.NewRegistry()
+
+// servedMethods are the names of the rpc methods that have been called on
+// this host
+var servedMethods = struct {
+	sync.Mutex
+	names map[string]struct{}
+}{names: make(map[string]struct{})}
+
+// MethodStats summarizes the calls served by an rpc method
+type MethodStats struct {
+	Method string
+	Calls  int64 // completed calls
+	Errors int64 // completed calls that returned an error
+	Mean   time.Duration
+	P95    time.Duration
+	Max    time.Duration
+}
+
+func serverMetricName(method, metric string) string {
+	return fmt.Sprintf("rpc.%s.%s", method, metric)
+}
+
+// GetServerStats returns the metrics of the rpc methods served by this host,
+// ordered by method name
+func GetServerStats() []MethodStats {
+	servedMethods.Lock()
+	names := make([]string, 0, len(servedMethods.names))
+	for name := range servedMethods.names {
+		names = append(names, name)
+	}
+	servedMethods.Unlock()
+	sort.Strings(names)
+
+	stats := make([]MethodStats, len(names))
+	for i, name := range names {
+		timer := metrics.GetOrRegisterTimer(serverMetricName(name, "duration"), ServerMetrics).Snapshot()
+		stats[i] = MethodStats{
+			Method: name,
+			Calls:  timer.Count(),
+			Errors: metrics.GetOrRegisterCounter(serverMetricName(name, "errors"), ServerMetrics).Count(),
+			Mean:   time.Duration(timer.Mean()),
+			P95:    time.Duration(timer.Percentile(0.95)),
+			Max:    time.Duration(timer.Max()),
+		}
+	}
+	return stats
+}
+
+// WriteServerPrometheus writes the rpc method stats in the Prometheus text
+// exposition format
+func WriteServerPrometheus(w io.Writer, stats []MethodStats) error {
+	type metric struct {
+		name, help, kind string
+		value            func(s MethodStats) float64
+	}
+	methodMetrics := []metric{
+		{"serviced_rpc_calls_total", "Completed rpc calls.", "counter", func(s MethodStats) float64 { return float64(s.Calls) }},
+		{"serviced_rpc_call_errors_total", "Completed rpc calls that returned an error.", "counter", func(s MethodStats) float64 { return float64(s.Errors) }},
+		{"serviced_rpc_call_duration_mean_seconds", "Mean duration of rpc calls.", "gauge", func(s MethodStats) float64 { return s.Mean.Seconds() }},
+		{"serviced_rpc_call_duration_p95_seconds", "95th percentile duration of rpc calls.", "gauge", func(s MethodStats) float64 { return s.P95.Seconds() }},
+		{"serviced_rpc_call_duration_max_seconds", "Maximum duration of rpc calls.", "gauge", func(s MethodStats) float64 { return s.Max.Seconds() }},
+	}
+	for _, m := range methodMetrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+		for _, s := range stats {
+			if _, err := fmt.Fprintf(w, "%s{method=%q} %g\n", m.name, s.Method, m.value(s)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewStatsServerCodec returns a codec that records the duration and outcome
+// of each rpc call served with it.  Calls that take at least slowThreshold
+// are logged with the address of the caller; a slowThreshold of zero or less
+// disables slow call logging.
+func NewStatsServerCodec(codec rpc.ServerCodec, remoteAddr string, slowThreshold time.Duration) rpc.ServerCodec {
+	return &statsServerCodec{
+		ServerCodec:   codec,
+		remoteAddr:    remoteAddr,
+		slowThreshold: slowThreshold,
+		calls:         make(map[uint64]*servedCall),
+	}
+}
+
+type statsServerCodec struct {
+	rpc.ServerCodec
+	remoteAddr    string
+	slowThreshold time.Duration
+	mu            sync.Mutex
+	calls         map[uint64]*servedCall
+	last          *servedCall // call whose body is read next
+}
+
+// servedCall is an rpc call in progress
+type servedCall struct {
+	method string
+	start  time.Time
+	body   interface{}
+}
+
+// ReadRequestHeader starts timing the call
+func (c *statsServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	if err := c.ServerCodec.ReadRequestHeader(r); err != nil {
+		return err
+	}
+	call := &servedCall{method: r.ServiceMethod, start: time.Now()}
+	c.mu.Lock()
+	c.calls[r.Seq] = call
+	c.last = call
+	c.mu.Unlock()
+	return nil
+}
+
+// ReadRequestBody keeps the request of the call so its size can be reported
+// if the call is slow
+func (c *statsServerCodec) ReadRequestBody(body interface{}) error {
+	c.mu.Lock()
+	if c.last != nil {
+		c.last.body = body
+		c.last = nil
+	}
+	c.mu.Unlock()
+	return c.ServerCodec.ReadRequestBody(body)
+}
+
+// WriteResponse records the duration and outcome of the call
+func (c *statsServerCodec) WriteResponse(r *rpc.Response, body interface{}) error {
+	c.mu.Lock()
+	call := c.calls[r.Seq]
+	delete(c.calls, r.Seq)
+	c.mu.Unlock()
+	if call != nil {
+		c.record(call, r.Error)
+	}
+	return c.ServerCodec.WriteResponse(r, body)
+}
+
+func (c *statsServerCodec) record(call *servedCall, errmsg string) {
+	duration := time.Since(call.start)
+	servedMethods.Lock()
+	servedMethods.names[call.method] = struct{}{}
+	servedMethods.Unlock()
+	metrics.GetOrRegisterTimer(serverMetricName(call.method, "duration"), ServerMetrics).Update(duration)
+	if errmsg != "" {
+		metrics.GetOrRegisterCounter(serverMetricName(call.method, "errors"), ServerMetrics).Inc(1)
+	}
+
+	if c.slowThreshold > 0 && duration >= c.slowThreshold {
+		// only pay for sizing the request when the call is reported
+		size := 0
+		if call.body != nil {
+			if data, err := json.Marshal(call.body); err == nil {
+				size = len(data)
+			}
+		}
+		log.Warningf("Slow rpc call %s from %s took %s (request %d bytes)", call.method, c.remoteAddr, duration, size)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package rpcutils
+
+import (
+	"bytes"
+	"net/rpc"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/rpc/rpcutils/mocks"
+	. "gopkg.in/check.v1"
+)
+
+type StatsCodecSuite struct{}
+
+var _ = Suite(&StatsCodecSuite{})
+
+func getMethodStats(method string) MethodStats {
+	for _, s := range GetServerStats() {
+		if s.Method == method {
+			return s
+		}
+	}
+	return MethodStats{Method: method}
+}
+
+func (s *StatsCodecSuite) TestStatsServerCodec(c *C) {
+	getBefore := getMethodStats("StatsTest.Get")
+	removeBefore := getMethodStats("StatsTest.Remove")
+
+	wrapped := &mocks.ServerCodec{}
+	codec := NewStatsServerCodec(wrapped, "10.0.0.1:4979", time.Nanosecond)
+	for i, method := range []string{"StatsTest.Get", "StatsTest.Remove", "StatsTest.Get"} {
+		req := &rpc.Request{ServiceMethod: method, Seq: uint64(i)}
+		wrapped.On("ReadRequestHeader", req).Return(nil).Once()
+		c.Assert(codec.ReadRequestHeader(req), IsNil)
+		body := &struct{ ID string }{}
+		wrapped.On("ReadRequestBody", body).Return(nil).Once()
+		c.Assert(codec.ReadRequestBody(body), IsNil)
+	}
+	for i, errmsg := range []string{"", "not found", "timeout"} {
+		resp := &rpc.Response{Seq: uint64(i), Error: errmsg}
+		wrapped.On("WriteResponse", resp, nil).Return(nil).Once()
+		c.Assert(codec.WriteResponse(resp, nil), IsNil)
+	}
+	wrapped.AssertExpectations(c)
+
+	getAfter := getMethodStats("StatsTest.Get")
+	c.Check(getAfter.Calls, Equals, getBefore.Calls+2)
+	c.Check(getAfter.Errors, Equals, getBefore.Errors+1)
+	removeAfter := getMethodStats("StatsTest.Remove")
+	c.Check(removeAfter.Calls, Equals, removeBefore.Calls+1)
+	c.Check(removeAfter.Errors, Equals, removeBefore.Errors+1)
+	c.Check(removeAfter.Max >= removeAfter.Mean, Equals, true)
+}
+
+func (s *StatsCodecSuite) TestStatsServerCodec_ReadHeaderFails(c *C) {
+	before := getMethodStats("StatsTest.Broken")
+
+	wrapped := &mocks.ServerCodec{}
+	codec := NewStatsServerCodec(wrapped, "10.0.0.1:4979", 0)
+	req := &rpc.Request{ServiceMethod: "StatsTest.Broken", Seq: 1}
+	wrapped.On("ReadRequestHeader", req).Return(rpc.ErrShutdown)
+	c.Assert(codec.ReadRequestHeader(req), Equals, rpc.ErrShutdown)
+
+	c.Check(getMethodStats("StatsTest.Broken").Calls, Equals, before.Calls)
+}
+
+func (s *StatsCodecSuite) TestWriteServerPrometheus(c *C) {
+	stats := []MethodStats{
+		{Method: "Master.GetHost", Calls: 4, Errors: 1, Mean: time.Second, P95: 2 * time.Second, Max: 3 * time.Second},
+	}
+	buf := &bytes.Buffer{}
+	c.Assert(WriteServerPrometheus(buf, stats), IsNil)
+	out := buf.String()
+	for _, line := range []string{
+		"# TYPE serviced_rpc_calls_total counter",
+		`serviced_rpc_calls_total{method="Master.GetHost"} 4`,
+		`serviced_rpc_call_errors_total{method="Master.GetHost"} 1`,
+		`serviced_rpc_call_duration_mean_seconds{method="Master.GetHost"} 1`,
+		`serviced_rpc_call_duration_p95_seconds{method="Master.GetHost"} 2`,
+		`serviced_rpc_call_duration_max_seconds{method="Master.GetHost"} 3`,
+	} {
+		c.Check(strings.Contains(out, line+"\n"), Equals, true, Commentf("missing %q", line))
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcutils
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// CallStats summarizes the rpc calls made by this process
+type CallStats struct {
+	Calls    int64
+	Errors   int64
+	Duration time.Duration
+}
+
+var callStats struct {
+	calls    int64
+	errors   int64
+	duration int64
+}
+
+// GetCallStats returns the number of rpc calls made by clients in this
+// process, how many failed, and the total time spent waiting on them
+func GetCallStats() CallStats {
+	return CallStats{
+		Calls:    atomic.LoadInt64(&callStats.calls),
+		Errors:   atomic.LoadInt64(&callStats.errors),
+		Duration: time.Duration(atomic.LoadInt64(&callStats.duration)),
+	}
+}
+
+// recordCall adds a completed rpc call to the call stats
+func recordCall(start time.Time, err error) {
+	atomic.AddInt64(&callStats.calls, 1)
+	atomic.AddInt64(&callStats.duration, int64(time.Since(start)))
+	if err != nil {
+		atomic.AddInt64(&callStats.errors, 1)
+	}
+}
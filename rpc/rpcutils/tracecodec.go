@@ -0,0 +1,80 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcutils
+
+import (
+	"errors"
+	"net/rpc"
+	"sync"
+
+	"github.com/control-center/serviced/tracing"
+	"golang.org/x/net/context"
+)
+
+// NewTracingServerCodec returns a codec that records a trace span for each
+// rpc call served with it
+func NewTracingServerCodec(codec rpc.ServerCodec) rpc.ServerCodec {
+	return &tracingServerCodec{ServerCodec: codec, spans: make(map[uint64]*tracing.Span)}
+}
+
+type tracingServerCodec struct {
+	rpc.ServerCodec
+	mu      sync.Mutex
+	spans   map[uint64]*tracing.Span
+	reading *tracing.Span // span of the request being read
+}
+
+// ReadRequestHeader starts the span of the call
+func (c *tracingServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	if err := c.ServerCodec.ReadRequestHeader(r); err != nil {
+		return err
+	}
+	c.reading = tracing.StartSpan("rpc", r.ServiceMethod)
+	if c.reading != nil {
+		c.mu.Lock()
+		c.spans[r.Seq] = c.reading
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+// ReadRequestBody passes the span of the call to requests that take a call
+// context, so that the work done for the call is recorded in its trace.  The
+// rpc server always reads the body right after the header of a request.
+func (c *tracingServerCodec) ReadRequestBody(body interface{}) error {
+	if err := c.ServerCodec.ReadRequestBody(body); err != nil {
+		return err
+	}
+	if call, ok := body.(CallContext); ok {
+		ctx := call.CallContext()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		call.SetCallContext(tracing.ContextWithSpan(ctx, c.reading))
+	}
+	return nil
+}
+
+// WriteResponse finishes the span of the call
+func (c *tracingServerCodec) WriteResponse(r *rpc.Response, body interface{}) error {
+	c.mu.Lock()
+	span := c.spans[r.Seq]
+	delete(c.spans, r.Seq)
+	c.mu.Unlock()
+	if r.Error != "" {
+		span.SetError(errors.New(r.Error))
+	}
+	span.Finish()
+	return c.ServerCodec.WriteResponse(r, body)
+}
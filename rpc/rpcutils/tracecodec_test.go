@@ -0,0 +1,96 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package rpcutils
+
+import (
+	"net/rpc"
+
+	"github.com/control-center/serviced/rpc/rpcutils/mocks"
+	"github.com/control-center/serviced/tracing"
+	. "gopkg.in/check.v1"
+)
+
+type TracingCodecSuite struct{}
+
+var _ = Suite(&TracingCodecSuite{})
+
+type spanRecorder struct {
+	spans []*tracing.Span
+}
+
+func (r *spanRecorder) Export(spans []*tracing.Span) error {
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+func (s *TracingCodecSuite) TestTracingServerCodec(c *C) {
+	r := &spanRecorder{}
+	tracer := tracing.NewTracer(1, r)
+	tracing.SetTracer(tracer)
+	defer tracing.SetTracer(nil)
+	shutdown := make(chan interface{})
+	done := make(chan struct{})
+	go func() {
+		tracer.Run(shutdown)
+		close(done)
+	}()
+
+	wrapped := &mocks.ServerCodec{}
+	codec := NewTracingServerCodec(wrapped)
+	for i, method := range []string{"Master.GetHost", "Master.RemoveHost"} {
+		req := &rpc.Request{ServiceMethod: method, Seq: uint64(i)}
+		wrapped.On("ReadRequestHeader", req).Return(nil).Once()
+		c.Assert(codec.ReadRequestHeader(req), IsNil)
+	}
+	for i, errmsg := range []string{"", "host not found"} {
+		resp := &rpc.Response{Seq: uint64(i), Error: errmsg}
+		wrapped.On("WriteResponse", resp, nil).Return(nil).Once()
+		c.Assert(codec.WriteResponse(resp, nil), IsNil)
+	}
+	wrapped.AssertExpectations(c)
+	close(shutdown)
+	<-done
+
+	c.Assert(r.spans, HasLen, 2)
+	c.Assert(r.spans[0].Name, Equals, "Master.GetHost")
+	c.Assert(r.spans[0].Component, Equals, "rpc")
+	c.Assert(r.spans[0].Tags, HasLen, 0)
+	c.Assert(r.spans[1].Name, Equals, "Master.RemoveHost")
+	c.Assert(r.spans[1].Tags["error"], Equals, "host not found")
+}
+
+func (s *TracingCodecSuite) TestTracingServerCodec_CallContext(c *C) {
+	r := &spanRecorder{}
+	tracing.SetTracer(tracing.NewTracer(1, r))
+	defer tracing.SetTracer(nil)
+
+	wrapped := &mocks.ServerCodec{}
+	codec := NewTracingServerCodec(wrapped)
+	req := &rpc.Request{ServiceMethod: "Master.WaitService", Seq: 1}
+	wrapped.On("ReadRequestHeader", req).Return(nil).Once()
+	c.Assert(codec.ReadRequestHeader(req), IsNil)
+	body := &contextRequest{}
+	wrapped.On("ReadRequestBody", body).Return(nil).Once()
+	c.Assert(codec.ReadRequestBody(body), IsNil)
+	wrapped.AssertExpectations(c)
+
+	// the request carries the span of the call
+	c.Assert(body.ctx, NotNil)
+	span, ok := tracing.SpanFromContext(body.ctx)
+	c.Assert(ok, Equals, true)
+	c.Assert(span, NotNil)
+	c.Assert(span.Name, Equals, "Master.WaitService")
+}
@@ -15,63 +15,115 @@ package scheduler
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/commons"
 	coordclient "github.com/control-center/serviced/coordinator/client"
-	"github.com/control-center/serviced/dao"
-	"github.com/control-center/serviced/dfs/ttl"
+	"github.com/control-center/serviced/datastore"
+	zkimgregistry "github.com/control-center/serviced/dfs/registry"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/scheduler/strategy"
 	"github.com/control-center/serviced/zzk"
 	zkservice "github.com/control-center/serviced/zzk/service"
 	"github.com/control-center/serviced/zzk/virtualips"
 )
 
+// Backend is the store of record that the leader of a pool schedules
+// against.  The master uses the facade directly, while a delegate that
+// schedules its own pool goes through the master's rpc server.
+type Backend interface {
+	// GetHostStrategyInstances returns the instances running on the hosts
+	GetHostStrategyInstances(hostIDs ...string) ([]service.StrategyInstance, error)
+	// SetHostClockSkew records the clock skew reported by a host
+	SetHostClockSkew(hostID string, skew time.Duration)
+}
+
+type facadeBackend struct {
+	facade *facade.Facade
+}
+
+// NewFacadeBackend returns the backend used by the master
+func NewFacadeBackend(f *facade.Facade) Backend {
+	return &facadeBackend{facade: f}
+}
+
+func (b *facadeBackend) GetHostStrategyInstances(hostIDs ...string) ([]service.StrategyInstance, error) {
+	return b.facade.GetHostStrategyInstances(datastore.Get(), hostIDs...)
+}
+
+func (b *facadeBackend) SetHostClockSkew(hostID string, skew time.Duration) {
+	b.facade.SetHostClockSkew(datastore.Get(), hostID, skew)
+}
+
+type masterBackend struct {
+	masterAddr string
+}
+
+// NewMasterBackend returns the backend used by a delegate that schedules the
+// services of its pool
+func NewMasterBackend(masterAddr string) Backend {
+	return &masterBackend{masterAddr: masterAddr}
+}
+
+func (b *masterBackend) GetHostStrategyInstances(hostIDs ...string) ([]service.StrategyInstance, error) {
+	masterClient, err := master.NewClient(b.masterAddr)
+	if err != nil {
+		return nil, err
+	}
+	defer masterClient.Close()
+	return masterClient.GetHostStrategyInstances(hostIDs)
+}
+
+// SetHostClockSkew is a no-op; the master already records the clock skew of
+// each host when the host authenticates.
+func (b *masterBackend) SetHostClockSkew(hostID string, skew time.Duration) {}
+
 type leader struct {
 	shutdown <-chan interface{}
 	conn     coordclient.Connection
-	cpClient dao.ControlPlane
-	facade   *facade.Facade
+	backend  Backend
 	poolID   string
 
 	hreg *zkservice.HostRegistryListener
 }
 
-// Lead is executed by the "leader" of the control center cluster to handle its management responsibilities of:
+// Lead is executed by the "leader" of a resource pool to handle its management responsibilities of:
 //    services
-//    snapshots
 //    virtual IPs
-func Lead(shutdown <-chan interface{}, conn coordclient.Connection, cpClient dao.ControlPlane, facade *facade.Facade, poolID string, snapshotTTL int) {
+func Lead(shutdown <-chan interface{}, conn coordclient.Connection, backend Backend, poolID string) {
 
 	// creates a listener for the host registry
 	hreg := zkservice.NewHostRegistryListener(poolID)
+	hreg.SetClockSkewHandler(backend.SetHostClockSkew)
 
-	plog.Info("Processing leader duties")
-	leader := leader{shutdown, conn, cpClient, facade, poolID, hreg}
+	plog.WithField("poolid", poolID).Info("Processing leader duties")
+	leader := leader{shutdown, conn, backend, poolID, hreg}
 
 	// creates a listener for services
 	serviceListener := zkservice.NewServiceListener(poolID, &leader)
 
-	// kicks off the snapshot cleaning goroutine
-	if snapshotTTL > 0 {
-		go ttl.RunSnapshotTTL(cpClient, shutdown, time.Minute, time.Duration(snapshotTTL)*time.Hour)
-	}
-
 	// starts all of the listeners
 	zzk.Start(shutdown, conn, serviceListener, hreg)
 }
 
-// SelectHost chooses a host from the pool for the specified service. If the
+// SelectHost chooses a host from the pool for the specified service instance.
+// If the instance is pinned, only its placement hosts are considered.  If the
 // service has an address assignment the host will already be selected. If not
 // the host with the least amount of memory committed to running containers will
 // be chosen.  Returns the hostid, hostip (if it has an address assignment).
-func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
+func (l *leader) SelectHost(sn *zkservice.ServiceNode, instanceID int) (string, error) {
 	logger := plog.WithFields(log.Fields{
 		"serviceid":   sn.ID,
 		"servicename": sn.Name,
+		"instanceid":  instanceID,
 	})
 
 	plog.Debug("Looking for available hosts in resource pool")
@@ -87,6 +139,31 @@ func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
 		return "", errors.New("scheduler is shutting down")
 	}
 
+	if pinned := sn.Placement.HostsFor(instanceID); len(pinned) > 0 {
+		hosts = placementHosts(hosts, pinned)
+		if len(hosts) == 0 {
+			logger.WithField("placement", pinned).Warn("No pinned host of the service instance is available.  Check to see if the hosts are running or update the placement of the service")
+			return "", fmt.Errorf("pinned host %s of instance %d is not available", strings.Join(pinned, ", "), instanceID)
+		}
+	}
+
+	if sn.ImageID != "" {
+		if rImage, err := zkimgregistry.FindRegistryImage(l.conn, sn.ImageID); err != nil {
+			plog.WithFields(log.Fields{
+				"serviceid": sn.ID,
+				"imageid":   sn.ImageID,
+			}).WithError(err).Debug("Could not find the service image in the registry index; not filtering hosts by architecture")
+		} else if hosts = archHosts(hosts, rImage); len(hosts) == 0 {
+			archs := strings.Join(rImage.Architectures(), ", ")
+			plog.WithFields(log.Fields{
+				"serviceid": sn.ID,
+				"imageid":   sn.ImageID,
+				"archs":     archs,
+			}).Warn("No host with an architecture of the service image is available.  Add hosts of a matching architecture to the pool or push the image for more architectures")
+			return "", fmt.Errorf("no host is available for the %s architecture of image %s", archs, sn.ImageID)
+		}
+	}
+
 	assignment := sn.AddressAssignment
 	if sn.ShouldHaveAddressAssignment && assignment.IPAddr == "" {
 		plog.WithField("endpoint", sn.Name).Debug("Service is missing an address assignment")
@@ -130,5 +207,32 @@ func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
 		return "", err
 	}
 
-	return StrategySelectHost(sn, hosts, strat, l.facade)
+	return StrategySelectHost(sn, hosts, strat, l.backend)
+}
+
+// archHosts returns the hosts whose CPU architecture the image has a
+// manifest for
+func archHosts(hosts []host.Host, rImage *registry.Image) []host.Host {
+	var result []host.Host
+	for _, h := range hosts {
+		if rImage.SupportsArch(h.Architecture()) {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// placementHosts returns the hosts that are named by their id or their name
+// in the placement of a service instance
+func placementHosts(hosts []host.Host, pinned []string) []host.Host {
+	var result []host.Host
+	for _, h := range hosts {
+		for _, name := range pinned {
+			if h.ID == name || h.Name == name {
+				result = append(result, h)
+				break
+			}
+		}
+	}
+	return result
 }
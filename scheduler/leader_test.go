@@ -0,0 +1,144 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/scheduler/strategy"
+	"github.com/control-center/serviced/utils"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+type testBackend struct {
+	hostIDs   []string
+	instances []service.StrategyInstance
+}
+
+func (b *testBackend) GetHostStrategyInstances(hostIDs ...string) ([]service.StrategyInstance, error) {
+	b.hostIDs = hostIDs
+	return b.instances, nil
+}
+
+func (b *testBackend) SetHostClockSkew(hostID string, skew time.Duration) {}
+
+func TestPlacementHosts(t *testing.T) {
+	hosts := []host.Host{
+		{ID: "host1", Name: "alpha"},
+		{ID: "host2", Name: "beta"},
+		{ID: "host3", Name: "gamma"},
+	}
+	placement := servicedefinition.Placement{
+		Hosts:     []string{"alpha", "host3"},
+		Instances: []servicedefinition.InstancePlacement{{InstanceID: 1, Host: "beta"}},
+	}
+
+	result := placementHosts(hosts, placement.HostsFor(0))
+	if len(result) != 2 || result[0].ID != "host1" || result[1].ID != "host3" {
+		t.Errorf("Expected hosts host1 and host3 for instance 0, got %+v", result)
+	}
+
+	result = placementHosts(hosts, placement.HostsFor(1))
+	if len(result) != 1 || result[0].ID != "host2" {
+		t.Errorf("Expected host host2 for instance 1, got %+v", result)
+	}
+
+	result = placementHosts(hosts, []string{"delta"})
+	if len(result) != 0 {
+		t.Errorf("Expected no hosts, got %+v", result)
+	}
+}
+
+func TestArchHosts(t *testing.T) {
+	hosts := []host.Host{
+		{ID: "host1"},
+		{ID: "host2", Arch: "amd64"},
+		{ID: "host3", Arch: "arm64"},
+	}
+
+	// images indexed without architectures are amd64
+	result := archHosts(hosts, &registry.Image{})
+	if len(result) != 2 || result[0].ID != "host1" || result[1].ID != "host2" {
+		t.Errorf("Expected hosts host1 and host2 for an amd64 image, got %+v", result)
+	}
+
+	result = archHosts(hosts, &registry.Image{Archs: []string{"arm64"}})
+	if len(result) != 1 || result[0].ID != "host3" {
+		t.Errorf("Expected host host3 for an arm64 image, got %+v", result)
+	}
+
+	result = archHosts(hosts, &registry.Image{Archs: []string{"amd64", "arm64"}})
+	if len(result) != 3 {
+		t.Errorf("Expected all hosts for a multi-arch image, got %+v", result)
+	}
+
+	result = archHosts(hosts, &registry.Image{Archs: []string{"ppc64le"}})
+	if len(result) != 0 {
+		t.Errorf("Expected no hosts, got %+v", result)
+	}
+}
+
+func TestStrategySelectHostBackend(t *testing.T) {
+	hosts := []host.Host{
+		{ID: "host1", Cores: 4, Memory: 8 << 30, RAMCommitment: 4 << 30},
+		{ID: "host2", Cores: 4, Memory: 8 << 30, RAMCommitment: 4 << 30},
+	}
+	backend := &testBackend{
+		instances: []service.StrategyInstance{
+			{HostID: "host1", ServiceID: "svc1", CPUCommitment: 1, RAMCommitment: 2 << 30},
+		},
+	}
+	sn := &zkservice.ServiceNode{ID: "svc2", CPUCommitment: 1, RAMCommitment: utils.NewEngNotation(1 << 30)}
+	strat, err := strategy.Get(string(servicedefinition.Balance))
+	if err != nil {
+		t.Fatalf("Could not get balance strategy: %s", err)
+	}
+
+	hostID, err := StrategySelectHost(sn, hosts, strat, backend)
+	if err != nil {
+		t.Fatalf("Could not select host: %s", err)
+	}
+	if hostID != "host2" {
+		t.Errorf("Expected the least loaded host host2, got %s", hostID)
+	}
+	if !reflect.DeepEqual(backend.hostIDs, []string{"host1", "host2"}) {
+		t.Errorf("Expected instances of host1 and host2 to be looked up, got %v", backend.hostIDs)
+	}
+}
+
+func TestPoolSchedulerAssigned(t *testing.T) {
+	master := NewPoolScheduler("master", "default", false, &testBackend{})
+	delegate := NewPoolScheduler("host1", "default", true, &testBackend{})
+
+	for _, scheduler := range []string{"", pool.SchedulerMaster} {
+		rp := &pool.ResourcePool{ID: "default", Scheduler: scheduler}
+		if !master.assigned(rp) || delegate.assigned(rp) {
+			t.Errorf("Expected scheduler %q to assign the pool to the master", scheduler)
+		}
+	}
+
+	rp := &pool.ResourcePool{ID: "default", Scheduler: pool.SchedulerDelegate}
+	if master.assigned(rp) || !delegate.assigned(rp) {
+		t.Errorf("Expected scheduler %q to assign the pool to the delegates", rp.Scheduler)
+	}
+}
@@ -20,7 +20,6 @@ import (
 	"github.com/control-center/serviced/zzk"
 	zkservice "github.com/control-center/serviced/zzk/service"
 	zkvirtualips "github.com/control-center/serviced/zzk/virtualips"
-	"github.com/zenoss/glog"
 )
 
 const (
@@ -45,52 +44,52 @@ func (s *scheduler) localSync(shutdown <-chan interface{}, rootConn client.Conne
 func (s *scheduler) doSync(rootConn client.Connection) <-chan time.Time {
 	// SyncRegistryImages performs its own DFSLock, so run it before locking in here
 	if err := s.facade.SyncRegistryImages(datastore.Get(), false); err != nil {
-		glog.Errorf("%s", err)
+		plog.Errorf("%s", err)
 		return time.After(minWait)
 	}
 
 	if err := s.facade.DFSLock(datastore.Get()).LockWithTimeout("zookeeper sync", lockBlock); err != nil {
-		glog.Infof("Could not lock DFS (%s), will retry later", err)
+		plog.Infof("Could not lock DFS (%s), will retry later", err)
 		return time.After(noLockWait)
 	}
 	defer s.facade.DFSLock(datastore.Get()).Unlock()
 
 	pools, err := s.GetResourcePools()
 	if err != nil {
-		glog.Errorf("Could not get resource pools: %s", err)
+		plog.Errorf("Could not get resource pools: %s", err)
 		return time.After(minWait)
 	} else if err := zkservice.SyncResourcePools(rootConn, pools); err != nil {
-		glog.Errorf("Could not do a local sync of resource pools: %s", err)
+		plog.Errorf("Could not do a local sync of resource pools: %s", err)
 		return time.After(minWait)
 	}
 
 	for _, pool := range pools {
 		conn, err := zzk.GetLocalConnection(zzk.GeneratePoolPath(pool.ID))
 		if err != nil {
-			glog.Errorf("Could not get a pool-based connection for %s to zookeeper: %s", pool.ID, err)
+			plog.Errorf("Could not get a pool-based connection for %s to zookeeper: %s", pool.ID, err)
 			return time.After(minWait)
 		}
 
 		// Update the hosts
 		if hosts, err := s.GetHostsByPool(pool.ID); err != nil {
-			glog.Errorf("Could not get hosts in pool %s: %s", pool.ID, err)
+			plog.Errorf("Could not get hosts in pool %s: %s", pool.ID, err)
 			return time.After(minWait)
 		} else if err := zkservice.SyncHosts(conn, hosts); err != nil {
-			glog.Errorf("Could not do a local sync of hosts: %s", err)
+			plog.Errorf("Could not do a local sync of hosts: %s", err)
 			return time.After(minWait)
 		}
 
 		// Update the services
 		if svcs, err := s.GetServicesByPool(pool.ID); err != nil {
-			glog.Errorf("Could not get services: %s", err)
+			plog.Errorf("Could not get services: %s", err)
 			return time.After(minWait)
 		} else if err = zkservice.SyncServices(conn, svcs); err != nil {
-			glog.Errorf("Could not do a local sync of services: %s", err)
+			plog.Errorf("Could not do a local sync of services: %s", err)
 			return time.After(minWait)
 		} else {
 			for _, svc := range svcs {
 				if err := s.facade.SyncServiceRegistry(datastore.Get(), &svc); err != nil {
-					glog.Errorf("Could not sync public endpoints for service %s (%s): %s", svc.Name, svc.ID, err)
+					plog.Errorf("Could not sync public endpoints for service %s (%s): %s", svc.Name, svc.ID, err)
 					return time.After(minWait)
 				}
 			}
@@ -98,7 +97,7 @@ func (s *scheduler) doSync(rootConn client.Connection) <-chan time.Time {
 
 		// Update Virtual IPs
 		if err := zkvirtualips.SyncVirtualIPs(conn, pool.VirtualIPs); err != nil {
-			glog.Errorf("Could not sync virtual ips for %s: %s", pool.ID, err)
+			plog.Errorf("Could not sync virtual ips for %s: %s", pool.ID, err)
 			return time.After(minWait)
 		}
 	}
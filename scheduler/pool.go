@@ -0,0 +1,184 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"path"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	coordclient "github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// PoolScheduler runs the leader of a resource pool on the master, or on one
+// of the delegates in the pool if the pool delegates its scheduling.  Hosts
+// that may schedule the pool are elected under the same leader node, so the
+// pool is never scheduled by more than one host at a time.
+type PoolScheduler struct {
+	hostID   string
+	poolID   string
+	delegate bool // true if this host is a delegate in the pool
+	backend  Backend
+	lead     leaderFunc
+}
+
+// NewPoolScheduler returns the scheduler of a resource pool for a host
+func NewPoolScheduler(hostID, poolID string, delegate bool, backend Backend) *PoolScheduler {
+	return &PoolScheduler{
+		hostID:   hostID,
+		poolID:   poolID,
+		delegate: delegate,
+		backend:  backend,
+		lead:     Lead,
+	}
+}
+
+// assigned returns true if the pool is scheduled by hosts of this kind
+func (p *PoolScheduler) assigned(rp *pool.ResourcePool) bool {
+	return rp.DelegatesScheduling() == p.delegate
+}
+
+// Run contends for the lead of the pool while the pool is assigned to hosts
+// of this kind, until shutdown.  conn is a root-based connection.
+func (p *PoolScheduler) Run(shutdown <-chan interface{}, conn coordclient.Connection) {
+	logger := plog.WithFields(log.Fields{
+		"poolid":   p.poolID,
+		"delegate": p.delegate,
+	})
+	pth := path.Join("/pools", p.poolID)
+
+	var stop chan interface{}
+	var finished chan struct{}
+	defer func() {
+		if stop != nil {
+			close(stop)
+			<-finished
+		}
+	}()
+
+	done := make(chan struct{})
+	defer func() { close(done) }()
+	for {
+		var retry <-chan time.Time
+
+		node := &zkservice.PoolNode{ResourcePool: &pool.ResourcePool{}}
+		event, err := conn.GetW(pth, node, done)
+		if err != nil && err != coordclient.ErrEmptyNode {
+			logger.WithError(err).Warn("Could not watch resource pool, retrying")
+			retry = time.After(time.Second)
+		} else if assigned := p.assigned(node.ResourcePool); assigned && stop == nil {
+			logger.Info("Contending for the scheduling of the resource pool")
+			stop, finished = make(chan interface{}), make(chan struct{})
+			go func(stop <-chan interface{}, finished chan<- struct{}) {
+				defer close(finished)
+				p.elect(stop, conn)
+			}(stop, finished)
+		} else if !assigned && stop != nil {
+			// the pool node is updated often, so only stop when the
+			// scheduling of the pool moved to other hosts.
+			logger.Info("Scheduling of the resource pool was reassigned, stepping down")
+			close(stop)
+			<-finished
+			stop, finished = nil, nil
+		}
+
+		select {
+		case <-event:
+		case <-retry:
+		case <-finished:
+			logger.Warn("Scheduler of the resource pool exited, re-electing")
+			stop, finished = nil, nil
+			select {
+			case <-time.After(time.Second):
+			case <-shutdown:
+				return
+			}
+		case <-shutdown:
+			return
+		}
+
+		close(done)
+		done = make(chan struct{})
+	}
+}
+
+// elect takes the lead of the pool and runs its listeners until stop is
+// closed, the lead is lost, or the listeners exit.
+func (p *PoolScheduler) elect(stop <-chan interface{}, conn coordclient.Connection) {
+	logger := plog.WithFields(log.Fields{
+		"poolid":   p.poolID,
+		"delegate": p.delegate,
+	})
+
+	leader, err := conn.NewLeader(path.Join("/pools", p.poolID, "scheduler"))
+	if err != nil {
+		logger.WithError(err).Error("Could not initialize leader node for resource pool")
+		return
+	}
+	leaderDone := make(chan struct{})
+	defer close(leaderDone)
+
+	// waiting for the lead cannot be interrupted, so if this host is told to
+	// stop before it wins, it gives up the lead as soon as it gets it.
+	type result struct {
+		event <-chan coordclient.Event
+		err   error
+	}
+	resultC := make(chan result, 1)
+	go func() {
+		event, err := leader.TakeLead(&zzk.HostLeader{HostID: p.hostID}, leaderDone)
+		resultC <- result{event, err}
+	}()
+
+	var event <-chan coordclient.Event
+	select {
+	case r := <-resultC:
+		if r.err != nil {
+			logger.WithError(r.err).Error("Could not take the lead of resource pool")
+			return
+		}
+		event = r.event
+	case <-stop:
+		go func() {
+			if r := <-resultC; r.err == nil {
+				leader.ReleaseLead()
+			}
+		}()
+		return
+	}
+	defer leader.ReleaseLead()
+
+	logger.Info("Scheduling the services of the resource pool")
+	defer logger.Info("Stopped scheduling the services of the resource pool")
+
+	_shutdown := make(chan interface{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		p.lead(_shutdown, conn, p.backend, p.poolID)
+	}()
+
+	select {
+	case <-event:
+		logger.Warn("Lost the lead of resource pool")
+	case <-stopped:
+		logger.Warn("Listeners of resource pool exited")
+	case <-stop:
+	}
+	close(_shutdown)
+	<-stopped
+}
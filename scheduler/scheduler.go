@@ -15,23 +15,24 @@ package scheduler
 
 import (
 	"sync"
+	"time"
 
 	coordclient "github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/coordinator/storage"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	imgreg "github.com/control-center/serviced/dfs/registry"
+	"github.com/control-center/serviced/dfs/ttl"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/zzk"
-	"github.com/zenoss/glog"
 
 	"path"
 )
 
 var plog = logging.PackageLogger()
 
-type leaderFunc func(<-chan interface{}, coordclient.Connection, dao.ControlPlane, *facade.Facade, string, int)
+type leaderFunc func(<-chan interface{}, coordclient.Connection, Backend, string)
 
 type scheduler struct {
 	sync.Mutex                     // only one process can stop and start the scheduler at a time
@@ -80,7 +81,7 @@ func (s *scheduler) Start() {
 
 	pool, err := s.facade.GetResourcePool(datastore.Get(), s.poolID)
 	if err != nil {
-		glog.Errorf("Could not acquire resource pool %s: %s", s.poolID, err)
+		plog.Errorf("Could not acquire resource pool %s: %s", s.poolID, err)
 		return
 	}
 	s.realm = pool.Realm
@@ -119,14 +120,14 @@ func (s *scheduler) mainloop(conn coordclient.Connection) {
 	// become the leader
 	leader, err := conn.NewLeader("/scheduler")
 	if err != nil {
-		glog.Errorf("Could not initialize leader node for scheduler: %s", err)
+		plog.Errorf("Could not initialize leader node for scheduler: %s", err)
 		return
 	}
 	leaderDone := make(chan struct{})
 	defer close(leaderDone)
 	event, err := leader.TakeLead(&zzk.HostLeader{HostID: s.instance_id, Realm: s.realm}, leaderDone)
 	if err != nil {
-		glog.Errorf("Could not become the leader: %s", err)
+		plog.Errorf("Could not become the leader: %s", err)
 		return
 	}
 	defer leader.ReleaseLead()
@@ -147,23 +148,23 @@ func (s *scheduler) mainloop(conn coordclient.Connection) {
 	stopped := make(chan struct{}, 2)
 
 	// ensure all the services are unlocked
-	glog.Infof("Resetting service locks")
+	plog.Infof("Resetting service locks")
 	locker := s.facade.DFSLock(datastore.Get())
 	locker.Lock("reset service locks")
 	if err := s.facade.ResetLocks(datastore.Get()); err != nil {
-		glog.Errorf("Could not reset dfs locks: %s", err)
+		plog.Errorf("Could not reset dfs locks: %s", err)
 		return
 	}
 	locker.Unlock()
-	glog.Infof("DFS locks are all reset")
+	plog.Infof("DFS locks are all reset")
 
 	// start the storage server
 	wg.Add(1)
 	go func() {
-		defer glog.Infof("Stopping storage sync")
+		defer plog.Infof("Stopping storage sync")
 		defer wg.Done()
 		if err := s.storageServer.Run(_shutdown, conn); err != nil {
-			glog.Errorf("Could not maintain storage lead: %s", err)
+			plog.Errorf("Could not maintain storage lead: %s", err)
 			stopped <- struct{}{}
 		}
 	}()
@@ -171,14 +172,23 @@ func (s *scheduler) mainloop(conn coordclient.Connection) {
 	// synchronize locally
 	wg.Add(1)
 	go func() {
-		defer glog.Infof("Stopping local sync")
+		defer plog.Infof("Stopping local sync")
 		defer wg.Done()
 		s.localSync(_shutdown, conn)
 	}()
 
+	// kicks off the snapshot cleaning goroutine
+	if s.snapshotTTL > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ttl.RunSnapshotTTL(s.cpDao, _shutdown, time.Minute, time.Duration(s.snapshotTTL)*time.Hour)
+		}()
+	}
+
 	wg.Add(1)
 	go func() {
-		defer glog.Infof("Stopping pool listeners")
+		defer plog.Infof("Stopping pool listeners")
 		defer wg.Done()
 		zzk.Start(_shutdown, conn, s, s.pushreg)
 		stopped <- struct{}{}
@@ -188,10 +198,10 @@ func (s *scheduler) mainloop(conn coordclient.Connection) {
 	for {
 		select {
 		case <-event:
-			glog.Warningf("Coup d'etat, re-electing...")
+			plog.Warningf("Coup d'etat, re-electing...")
 			return
 		case <-stopped:
-			glog.Warningf("Leader died, re-electing...")
+			plog.Warningf("Leader died, re-electing...")
 			return
 		case <-s.shutdown:
 			return
@@ -224,19 +234,22 @@ func (s *scheduler) GetPath(nodes ...string) string {
 
 // Ready implements zzk.Listener
 func (s *scheduler) Ready() error {
-	glog.Infof("Entering lead for realm %s!", s.realm)
-	glog.Infof("Host Master successfully started")
+	plog.Infof("Entering lead for realm %s!", s.realm)
+	plog.Infof("Host Master successfully started")
 	return nil
 }
 
 // Done implements zzk.Listener
 func (s *scheduler) Done() {
-	glog.Infof("Exiting lead for realm %s!", s.realm)
-	glog.Infof("Host Master shutting down")
+	plog.Infof("Exiting lead for realm %s!", s.realm)
+	plog.Infof("Host Master shutting down")
 	return
 }
 
-// Spawn implements zzk.Listener
+// Spawn implements zzk.Listener.  The master schedules every pool that does
+// not delegate its scheduling to one of its own hosts.
 func (s *scheduler) Spawn(shutdown <-chan interface{}, poolID string) {
-	s.zkleaderFunc(shutdown, s.conn, s.cpDao, s.facade, poolID, s.snapshotTTL)
+	ps := NewPoolScheduler(s.instance_id, poolID, false, NewFacadeBackend(s.facade))
+	ps.lead = s.zkleaderFunc
+	ps.Run(shutdown, s.conn)
 }
@@ -14,14 +14,11 @@
 package scheduler
 
 import (
-	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
-	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/scheduler/strategy"
 	zkservice "github.com/control-center/serviced/zzk/service"
-	"github.com/zenoss/glog"
 )
 
 // Verify we implement all the interfaces
@@ -44,9 +41,9 @@ type StrategyService struct {
 	svc *zkservice.ServiceNode
 }
 
-func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat strategy.Strategy, facade *facade.Facade) (string, error) {
+func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat strategy.Strategy, backend Backend) (string, error) {
 
-	glog.V(2).Infof("Applying %s strategy for service %s", strat.Name(), sn.ID)
+	plog.Debugf("Applying %s strategy for service %s", strat.Name(), sn.ID)
 
 	hostmap := map[string]*StrategyHost{}
 	hostids := []string{}
@@ -59,8 +56,8 @@ func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat stra
 	}
 
 	// Look up all running services for the hosts
-	glog.V(2).Infof("Looking up instances for hosts: %+v", hostids)
-	svcs, err := facade.GetHostStrategyInstances(datastore.Get(), hostids...)
+	plog.Debugf("Looking up instances for hosts: %+v", hostids)
+	svcs, err := backend.GetHostStrategyInstances(hostids...)
 	if err != nil {
 		return "", err
 	}
@@ -72,14 +69,14 @@ func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat stra
 	}
 	shosts := []strategy.Host{}
 	for _, h := range hostmap {
-		glog.V(2).Infof("Host %s is running %d service instances", h.HostID(), len(h.services))
+		plog.Debugf("Host %s is running %d service instances", h.HostID(), len(h.services))
 		shosts = append(shosts, h)
 	}
 	if result, err := strat.SelectHost(&StrategyService{sn}, shosts); result == nil || err != nil {
 		return "", err
 	} else {
 		h := result.(*StrategyHost).host
-		glog.V(2).Infof("Deploying service %s to host %s", sn.ID, h.ID)
+		plog.Debugf("Deploying service %s to host %s", sn.ID, h.ID)
 		return h.ID, nil
 	}
 }
@@ -10,16 +10,14 @@ import (
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/zenoss/glog"
 )
 
 func evalEmpty(r *runner, n node) error {
-	glog.V(1).Infof("nothing to eval: %s", n.line)
+	plog.Debugf("nothing to eval: %s", n.line)
 	return nil
 }
 func evalSnapshot(r *runner, n node) error {
-	glog.V(0).Info("performing snapshot")
+	plog.Info("performing snapshot")
 
 	tagName := ""
 
@@ -44,13 +42,13 @@ func evalSnapshot(r *runner, n node) error {
 		return err
 	}
 	r.snapshotID = mySnapshotID //keep track of the latest snapshot to rollback to
-	glog.V(0).Infof("snapshot id: %s", mySnapshotID)
+	plog.Infof("snapshot id: %s", mySnapshotID)
 
 	exitFunc := func(failed bool) {
 		if failed && r.snapshotID == mySnapshotID {
-			glog.Infof("restoring snapshot %s", mySnapshotID)
+			plog.Infof("restoring snapshot %s", mySnapshotID)
 			if err := r.restore(mySnapshotID, true); err != nil {
-				glog.Errorf("failed restoring snapshot %s: %v", mySnapshotID, err)
+				plog.Errorf("failed restoring snapshot %s: %v", mySnapshotID, err)
 
 			}
 		}
@@ -67,7 +65,7 @@ func evalUSE(r *runner, n node) error {
 	if len(n.args) > 1 {
 		replaceImgs = n.args[1:]
 	}
-	glog.V(0).Infof("preparing to use image: %s", imageName)
+	plog.Infof("preparing to use image: %s", imageName)
 	svcID, found := r.env["TENANT_ID"]
 	if !found {
 		return fmt.Errorf("no service tenant id specified for %s", USE)
@@ -76,7 +74,7 @@ func evalUSE(r *runner, n node) error {
 	if err != nil {
 		return err
 	}
-	glog.Infof("Successfully pulled and tagged new image %s", imageName)
+	plog.Infof("Successfully pulled and tagged new image %s", imageName)
 	return nil
 }
 
@@ -142,7 +140,7 @@ func evalSvcWait(r *runner, n node) error {
 	if stateIdx > 1 {
 		plural = "s"
 	}
-	glog.Infof("waiting %d for service%s %s to be %s", timeout, plural, strings.Join(n.args[:stateIdx], ", "), state)
+	plog.Infof("waiting %d for service%s %s to be %s", timeout, plural, strings.Join(n.args[:stateIdx], ", "), state)
 	if err := r.svcWait(svcIDs, state, timeout, recursive); err != nil {
 		return err
 	}
@@ -178,7 +176,7 @@ func evalSvcStart(r *runner, n node) error {
 		recursive = true
 	}
 
-	glog.Infof("starting service %s %s", svcPath, svcID)
+	plog.Infof("starting service %s %s", svcPath, svcID)
 	if err := r.svcStart(svcID, recursive); err != nil {
 		return err
 	}
@@ -214,7 +212,7 @@ func evalSvcStop(r *runner, n node) error {
 		recursive = true
 	}
 
-	glog.Infof("stopping service %s %s", svcPath, svcID)
+	plog.Infof("stopping service %s %s", svcPath, svcID)
 	if err := r.svcStop(svcID, recursive); err != nil {
 		return err
 	}
@@ -250,7 +248,7 @@ func evalSvcRestart(r *runner, n node) error {
 		recursive = true
 	}
 
-	glog.Infof("restarting service %s %s", svcPath, svcID)
+	plog.Infof("restarting service %s %s", svcPath, svcID)
 	if err := r.svcRestart(svcID, recursive); err != nil {
 		return err
 	}
@@ -278,7 +276,7 @@ func evalSvcRun(r *runner, n node) error {
 
 	n.args[0] = svcID
 
-	glog.V(0).Infof("running: serviced service run %s", strings.Join(n.args, " "))
+	plog.Infof("running: serviced service run %s", strings.Join(n.args, " "))
 	args := []string{"service", "run"}
 	args = append(args, n.args...)
 	if err := r.execCommand("serviced", args...); err != nil {
@@ -310,7 +308,7 @@ func evalSvcExec(r *runner, n node) error {
 	// "HMS-YMD_svcID" will be the name of the container
 	containerName := time.Now().Format("150405-20060102") + "_" + svcID
 
-	glog.V(0).Infof("running: serviced service shell -s %s %s", containerName, strings.Join(n.args[1:], " "))
+	plog.Infof("running: serviced service shell -s %s %s", containerName, strings.Join(n.args[1:], " "))
 	args := []string{"service", "shell", "-s", containerName}
 	args = append(args, n.args[1:]...)
 	if err := r.execCommand("serviced", args...); err != nil {
@@ -320,16 +318,16 @@ func evalSvcExec(r *runner, n node) error {
 	// Now commit the container (if 'COMMIT' was specified)
 	switch n.args[0] {
 	case "COMMIT":
-		glog.V(0).Infof("committing container %s", containerName)
+		plog.Infof("committing container %s", containerName)
 		var snapshotID string
 		if snapshotID, err = r.commitContainer(containerName); err != nil {
 			return err
 		}
 		exitFunc := func(failed bool) {
 			if !failed {
-				glog.V(1).Infof("cleaning up snapshot %s", snapshotID)
+				plog.Debugf("cleaning up snapshot %s", snapshotID)
 				if err := r.execCommand("serviced", "snapshot", "remove", snapshotID); err != nil {
-					glog.Errorf("failed deleting snapshot %s: %v", snapshotID, err)
+					plog.Errorf("failed deleting snapshot %s: %v", snapshotID, err)
 				}
 			}
 		}
@@ -339,8 +337,8 @@ func evalSvcExec(r *runner, n node) error {
 }
 
 func evalDependency(r *runner, n node) error {
-	glog.V(0).Infof("checking serviced dependency: %s", n.args[0])
-	glog.V(0).Info("dependency check for serviced not implemented, skipping...")
+	plog.Infof("checking serviced dependency: %s", n.args[0])
+	plog.Info("dependency check for serviced not implemented, skipping...")
 	return nil
 }
 
@@ -348,17 +346,17 @@ func evalRequireSvc(r *runner, n node) error {
 	if r.tenantIDLookup == nil {
 		return fmt.Errorf("no tenant lookup function provided for %s", REQUIRE_SVC)
 	}
-	glog.V(0).Infof("checking service requirement")
+	plog.Infof("checking service requirement")
 	if r.config.ServiceID == "" {
 		return errors.New("no service id specified")
 	}
-	glog.V(0).Infof("verifying service %s", r.config.ServiceID)
+	plog.Infof("verifying service %s", r.config.ServiceID)
 	//lookup tenant id for service
 	tID, err := r.tenantIDLookup(r.config.ServiceID)
 	if err != nil {
 		return err
 	}
-	glog.V(0).Infof("found %s tenant id for service %s", tID, r.config.ServiceID)
+	plog.Infof("found %s tenant id for service %s", tID, r.config.ServiceID)
 	r.env["TENANT_ID"] = tID
 	return nil
 }
@@ -10,8 +10,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-
-	"github.com/zenoss/glog"
 )
 
 var (
@@ -91,7 +89,7 @@ func NewRunner(r io.Reader, config *Config) (Runner, error) {
 	}
 	if len(pctx.errors) > 0 {
 		for _, e := range pctx.errors {
-			glog.Errorf("%v", e)
+			plog.Errorf("%v", e)
 		}
 
 		return nil, errors.New("error parsing script")
@@ -121,7 +119,7 @@ func newRunner(config *Config, pctx *parseContext) *runner {
 		svcUse:          config.SvcUse,
 	}
 	if config.NoOp {
-		glog.Infof("creatng no op runner")
+		plog.Infof("creatng no op runner")
 		r.execCommand = noOpExec
 		r.restore = noOpRestore
 		r.snapshot = noOpSnapshot
@@ -148,7 +146,7 @@ func (r *runner) evalNodes(nodes []node, stop <-chan struct{}) error {
 
 	failed := true
 	defer func() {
-		glog.Infof("Executing exit functions")
+		plog.Infof("Executing exit functions")
 		for _, ef := range r.exitFunctions {
 			ef(failed)
 		}
@@ -156,18 +154,18 @@ func (r *runner) evalNodes(nodes []node, stop <-chan struct{}) error {
 
 	for i, n := range nodes {
 		if f, found := cmdEval[n.cmd]; found {
-			glog.Infof("executing step %d: %s", i, n.line)
+			plog.Infof("executing step %d: %s", i, n.line)
 			if err := f(r, n); err != nil {
-				glog.Errorf("error executing step %d: %s: %s", i, n.cmd, err)
+				plog.Errorf("error executing step %d: %s: %s", i, n.cmd, err)
 				return err
 			}
 		} else {
-			glog.Infof("skipping step %d unknown function: %s", i, n.line)
+			plog.Infof("skipping step %d unknown function: %s", i, n.line)
 		}
 
 		select {
 		case <-stop:
-			glog.Infof("Received signal, stopping script evaluation")
+			plog.Infof("Received signal, stopping script evaluation")
 			return fmt.Errorf("received stop signal, error executing step %d: %s", i, n.cmd)
 		default:
 		}
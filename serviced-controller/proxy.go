@@ -19,6 +19,7 @@ import (
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/container"
+	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/rpc/rpcutils"
 	"github.com/control-center/serviced/utils"
 	"github.com/zenoss/glog"
@@ -70,8 +71,7 @@ func CmdServiceProxy(ctx *cli.Context) {
 }
 
 func StartProxy(options ControllerOptions) error {
-	glog.SetLogstashType("controller-" + options.ServiceID + "-" + options.InstanceID)
-	glog.SetLogstashURL(options.LogstashURL)
+	logging.SetLogstash("controller-"+options.ServiceID+"-"+options.InstanceID, options.LogstashURL)
 
 	o, err := options.toContainerControllerOptions()
 	if err != nil {
@@ -20,7 +20,6 @@ package servicedversion
 
 import (
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 
 	"fmt"
 	"os/exec"
@@ -51,7 +50,7 @@ func init() {
 	var err error
 	Release, err = GetPackageRelease("serviced")
 	if err != nil {
-		glog.V(1).Infof("%s", err) // this should be Warningf or Infof, but zendev infinitely loops when seeing stderr
+		plog.Debugf("%s", err) // this should be Warningf or Infof, but zendev infinitely loops when seeing stderr
 	}
 }
 
@@ -81,7 +80,7 @@ func GetPackageRelease(pkg string) (string, error) {
 		return "", e
 	}
 
-	glog.V(1).Infof("Successfully ran command:'%s' output: %s\n", command, output)
+	plog.Debugf("Successfully ran command:'%s' output: %s\n", command, output)
 	release := strings.TrimSuffix(string(output), "\n")
 	return release, nil
 }
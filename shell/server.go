@@ -27,7 +27,6 @@ import (
 	"time"
 
 	"github.com/control-center/go-socket.io"
-	"github.com/zenoss/glog"
 
 	"github.com/control-center/serviced/domain/service"
 	worker "github.com/control-center/serviced/rpc/agent"
@@ -99,7 +98,7 @@ func (p *ProcessServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 func (s *ProcessServer) onProcess(ns *socketio.NameSpace, cfg *ProcessConfig) {
 	// Kick it off
-	glog.Infof("Received process packet")
+	plog.Infof("Received process packet")
 	proc := s.actor.Exec(cfg)
 	ns.Session.Values[PROCESSKEY] = proc
 
@@ -109,7 +108,7 @@ func (s *ProcessServer) onProcess(ns *socketio.NameSpace, cfg *ProcessConfig) {
 }
 
 func (s *ProcessServer) onConnect(ns *socketio.NameSpace) {
-	glog.Infof("Waiting for process packet")
+	plog.Infof("Waiting for process packet")
 }
 
 func onForwarderDisconnect(ns *socketio.NameSpace) {
@@ -144,16 +143,16 @@ func (p *ProcessInstance) Close() {
 
 func (p *ProcessInstance) ReadRequest(ns *socketio.NameSpace) {
 	ns.On("signal", func(n *socketio.NameSpace, signal int) {
-		glog.V(4).Infof("received signal %d", signal)
+		plog.Debugf("received signal %d", signal)
 		if p.disconnected {
-			glog.Warning("disconnected; cannot send signal: %s", signal)
+			plog.Warningf("disconnected; cannot send signal: %d", signal)
 		}
 	})
 
 	ns.On("stdin", func(n *socketio.NameSpace, stdin string) {
-		glog.V(4).Infof("Received stdin: %s", stdin)
+		plog.Debugf("Received stdin: %s", stdin)
 		if p.disconnected {
-			glog.Warning("disconnected; cannot send stdin: %s", stdin)
+			plog.Warningf("disconnected; cannot send stdin: %s", stdin)
 		} else {
 			for _, b := range []byte(stdin) {
 				p.Stdin <- b
@@ -161,11 +160,11 @@ func (p *ProcessInstance) ReadRequest(ns *socketio.NameSpace) {
 		}
 	})
 
-	glog.V(0).Info("Hooked up incoming events!")
+	plog.Info("Hooked up incoming events!")
 }
 
 func (p *ProcessInstance) WriteRequest(ns *socketio.NameSpace) {
-	glog.V(0).Info("Hooking up input channels!")
+	plog.Info("Hooking up input channels!")
 	for p.Stdin != nil {
 		select {
 		case m, ok := <-p.Stdin:
@@ -180,9 +179,9 @@ func (p *ProcessInstance) WriteRequest(ns *socketio.NameSpace) {
 
 func (p *ProcessInstance) ReadResponse(ns *socketio.NameSpace) {
 	ns.On("stdout", func(n *socketio.NameSpace, stdout string) {
-		glog.V(4).Infof("Process received stdout: %s", stdout)
+		plog.Debugf("Process received stdout: %s", stdout)
 		if p.closed {
-			glog.Warning("connection closed; cannot write stdout: %s", stdout)
+			plog.Warningf("connection closed; cannot write stdout: %s", stdout)
 		} else {
 			for _, b := range []byte(stdout) {
 				p.Stdout <- b
@@ -191,9 +190,9 @@ func (p *ProcessInstance) ReadResponse(ns *socketio.NameSpace) {
 	})
 
 	ns.On("stderr", func(n *socketio.NameSpace, stderr string) {
-		glog.V(4).Infof("Process received stderr: %s", stderr)
+		plog.Debugf("Process received stderr: %s", stderr)
 		if p.closed {
-			glog.Warning("connection closed; cannot write stderr: %s", stderr)
+			plog.Warningf("connection closed; cannot write stderr: %s", stderr)
 		} else {
 			for _, b := range []byte(stderr) {
 				p.Stderr <- b
@@ -202,14 +201,14 @@ func (p *ProcessInstance) ReadResponse(ns *socketio.NameSpace) {
 	})
 
 	ns.On("result", func(n *socketio.NameSpace, result Result) {
-		glog.V(0).Infof("Process received result: %s", result)
+		plog.Infof("Process received result: %s", result)
 		p.Result <- result
 	})
-	glog.V(0).Info("Hooked up outgoing events!")
+	plog.Info("Hooked up outgoing events!")
 }
 
 func (p *ProcessInstance) WriteResponse(ns *socketio.NameSpace) {
-	glog.V(0).Info("Hooking up output channels!")
+	plog.Info("Hooking up output channels!")
 
 	for p.Stdout != nil || p.Stderr != nil {
 		select {
@@ -217,14 +216,14 @@ func (p *ProcessInstance) WriteResponse(ns *socketio.NameSpace) {
 			if !ok {
 				p.Stdout = nil
 			} else {
-				glog.V(2).Infof("Emitting stdout: %3s %c", m, m)
+				plog.Debugf("Emitting stdout: %3s %c", m, m)
 				ns.Emit("stdout", m)
 			}
 		case m, ok := <-p.Stderr:
 			if !ok {
 				p.Stderr = nil
 			} else {
-				glog.V(2).Infof("Emitting stderr: %3s %c", m, m)
+				plog.Debugf("Emitting stderr: %3s %c", m, m)
 				ns.Emit("stderr", m)
 			}
 		}
@@ -237,7 +236,7 @@ func (f *Forwarder) Exec(cfg *ProcessConfig) *ProcessInstance {
 	// TODO: make me more extensible
 	urlAddr, err := url.Parse(f.addr)
 	if err != nil {
-		glog.Fatalf("Not a valid path: %s (%v)", f.addr, err)
+		plog.Fatalf("Not a valid path: %s (%v)", f.addr, err)
 	}
 
 	host := fmt.Sprintf("http://%s:50000/", strings.Split(urlAddr.Host, ":")[0])
@@ -246,14 +245,14 @@ func (f *Forwarder) Exec(cfg *ProcessConfig) *ProcessInstance {
 	client, err := socketio.Dial(host)
 
 	if err != nil {
-		glog.Fatalf("Unable to contact remote process server: %v", err)
+		plog.Fatalf("Unable to contact remote process server: %v", err)
 	}
 
 	client.On("connect", func(ns *socketio.NameSpace) {
 		if ns.Session.Values[PROCESSKEY] == nil {
 			ns.Emit("process", cfg)
 		} else {
-			glog.Fatalf("Trying to connect to a stale process!")
+			plog.Fatalf("Trying to connect to a stale process!")
 		}
 	})
 
@@ -266,7 +265,7 @@ func (f *Forwarder) Exec(cfg *ProcessConfig) *ProcessInstance {
 	}
 
 	client.On("disconnect", func(ns *socketio.NameSpace) {
-		glog.Infof("Disconnected!")
+		plog.Infof("Disconnected!")
 		proc.Disconnect()
 		proc.Close()
 	})
@@ -339,40 +338,40 @@ func StartDocker(cfg *ProcessConfig, masterAddress, workerAddress, dockerRegistr
 	// look up the service on the master
 	masterClient, err := master.NewClient(masterAddress)
 	if err != nil {
-		glog.Errorf("Could not connect to the master server at %s: %s", masterAddress, err)
+		plog.Errorf("Could not connect to the master server at %s: %s", masterAddress, err)
 		return nil, err
 	}
 	defer masterClient.Close()
-	glog.Infof("Connected to master at %s", masterAddress)
+	plog.Infof("Connected to master at %s", masterAddress)
 	svc := &service.Service{}
 	if svc, err = masterClient.GetService(cfg.ServiceID); err != nil {
-		glog.Errorf("Could not get service %s: %s", cfg.ServiceID, err)
+		plog.Errorf("Could not get service %s: %s", cfg.ServiceID, err)
 		return nil, err
 	}
 
 	// can we create a shell for this service?
 	if svc.DisableShell {
-		glog.Warningf("Shell commands are disabled for service %s (%s)", svc.Name, svc.ID)
+		plog.Warningf("Shell commands are disabled for service %s (%s)", svc.Name, svc.ID)
 		return nil, ErrShellDisabled
 	} else if svc.ImageID == "" {
-		glog.Warningf("No image is configured for service %s (%s)", svc.Name, svc.ID)
+		plog.Warningf("No image is configured for service %s (%s)", svc.Name, svc.ID)
 		return nil, ErrShellDisabled
 	}
 
 	// lets make sure the image exists locally and is the latest
 	workerClient, err := worker.NewClient(workerAddress)
 	if err != nil {
-		glog.Errorf("Could not connect to the worker server at %s: %s", workerAddress, err)
+		plog.Errorf("Could not connect to the worker server at %s: %s", workerAddress, err)
 		return nil, err
 	}
 	defer workerClient.Close()
-	glog.Infof("Connected to agent at %s; pulling image %s", workerAddress, svc.ImageID)
-	image, err := workerClient.PullImage(dockerRegistry, svc.ImageID, time.Minute)
+	plog.Infof("Connected to agent at %s; preparing image %s", workerAddress, svc.ImageID)
+	image, err := workerClient.PrepareShellImage(dockerRegistry, svc.ImageID, cfg.Mount, time.Minute)
 	if err != nil {
-		glog.Errorf("Could not pull image %s: %s", svc.ImageID, err)
+		plog.Errorf("Could not pull image %s: %s", svc.ImageID, err)
 		return nil, err
 	}
-	glog.Infof("Pulled image %s, setting up shell", image)
+	plog.Infof("Prepared image %s, setting up shell", image)
 
 	dir, binary := filepath.Split(controller)
 	servicedVolume := fmt.Sprintf("%s:/serviced", dir)
@@ -407,7 +406,7 @@ func StartDocker(cfg *ProcessConfig, masterAddress, workerAddress, dockerRegistr
 	// get the docker start command
 	docker, err := exec.LookPath("docker")
 	if err != nil {
-		glog.Errorf("Docker not found: %v", err)
+		plog.Errorf("Docker not found: %v", err)
 		return nil, err
 	}
 	argv := []string{"run", "-v", servicedVolume, "-v", pwdVolume, "-v", utils.ResourcesDir() + ":" + "/usr/local/serviced/resources", "-u", "root", "-w", "/"}
@@ -441,7 +440,7 @@ func StartDocker(cfg *ProcessConfig, masterAddress, workerAddress, dockerRegistr
 	argv = append(argv, image)
 	argv = append(argv, proxycmd...)
 
-	glog.V(1).Infof("command: docker %+v", argv)
-	glog.Infof("Shell initialized for service %s (%s), starting", svc.Name, svc.ID)
+	plog.Debugf("command: docker %+v", argv)
+	plog.Infof("Shell initialized for service %s (%s), starting", svc.Name, svc.ID)
 	return exec.Command(docker, argv...), nil
 }
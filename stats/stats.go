@@ -18,12 +18,15 @@ package stats
 import (
 	"github.com/control-center/go-procfs/linux"
 	coordclient "github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/dfs/docker"
+	"github.com/control-center/serviced/proxy"
+	"github.com/control-center/serviced/rpc/rpcutils"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/volume"
+	"github.com/control-center/serviced/zzk"
 	zkservice "github.com/control-center/serviced/zzk/service"
 	"github.com/rcrowley/go-metrics"
-	"github.com/zenoss/glog"
 
 	"bytes"
 	"encoding/json"
@@ -46,6 +49,8 @@ type StatsReporter struct {
 	isMasterHost        bool
 	docker              docker.Docker
 	previousStats       map[string]map[string]uint64 //holds some of the stats gathered in the previous sample, currently used for computing CPU %
+	usage               map[registryKey]*instanceUsage
+	usageSamples        int
 	sync.Mutex
 }
 
@@ -66,11 +71,11 @@ type registryKey struct {
 func NewStatsReporter(destination string, interval time.Duration, conn coordclient.Connection, isMasterHost bool, dockerClient docker.Docker) (*StatsReporter, error) {
 	hostID, err := utils.HostID()
 	if err != nil {
-		glog.Errorf("Could not determine host ID.")
+		plog.Errorf("Could not determine host ID.")
 		return nil, err
 	}
 	if conn == nil {
-		glog.Errorf("conn can not be nil")
+		plog.Errorf("conn can not be nil")
 		return nil, fmt.Errorf("conn can not be nil")
 	}
 	sr := StatsReporter{
@@ -82,6 +87,8 @@ func NewStatsReporter(destination string, interval time.Duration, conn coordclie
 		isMasterHost:        isMasterHost,
 		docker:              dockerClient,
 		previousStats:       make(map[string]map[string]uint64),
+		usage:               make(map[registryKey]*instanceUsage),
+		usageSamples:        usageSamples(interval),
 	}
 
 	sr.hostRegistry = metrics.NewRegistry()
@@ -135,6 +142,11 @@ func (sr *StatsReporter) removeStaleRegistries(states []zkservice.State) {
 			}
 		}
 	}
+	for key := range sr.usage {
+		if _, ok := sr.containerRegistries[key]; !ok {
+			delete(sr.usage, key)
+		}
+	}
 
 	// Now remove stale entries from our list of previous stats
 	for key, _ := range sr.previousStats {
@@ -155,20 +167,20 @@ func (sr *StatsReporter) Close() {
 // the data to the TSDB. Stops when close signal is received on closeChannel.
 func (sr *StatsReporter) report(d time.Duration) {
 	tc := time.Tick(d)
-	glog.Infof("collecting internal metrics at %s intervals", d)
+	plog.Infof("collecting internal metrics at %s intervals", d)
 	for {
 		select {
 		case _ = <-sr.closeChannel:
-			glog.V(3).Info("Ceasing stat reporting.")
+			plog.Debug("Ceasing stat reporting.")
 			sr.closeChannel <- true
 			return
 		case t := <-tc:
-			glog.V(1).Info("Reporting container stats at:", t)
+			plog.Debug("Reporting container stats at:", t)
 			sr.updateStats()
 			stats := sr.gatherStats(t)
 			err := Post(sr.destination, stats)
 			if err != nil {
-				glog.Errorf("Error reporting container stats: %v", err)
+				plog.Errorf("Error reporting container stats: %v", err)
 			}
 		}
 	}
@@ -178,7 +190,7 @@ func (sr *StatsReporter) updateHostStats() {
 
 	loadavg, err := linux.ReadLoadavg()
 	if err != nil {
-		glog.Errorf("could not read loadavg: %s", err)
+		plog.Errorf("could not read loadavg: %s", err)
 		return
 	}
 	metrics.GetOrRegisterGaugeFloat64("load.avg1m", sr.hostRegistry).Update(float64(loadavg.Avg1m))
@@ -189,7 +201,7 @@ func (sr *StatsReporter) updateHostStats() {
 
 	stat, err := linux.ReadStat()
 	if err != nil {
-		glog.Errorf("could not read stat: %s", err)
+		plog.Errorf("could not read stat: %s", err)
 		return
 	}
 	metrics.GetOrRegisterGauge("cpu.user", sr.hostRegistry).Update(int64(stat.Cpu.User()))
@@ -207,7 +219,7 @@ func (sr *StatsReporter) updateHostStats() {
 
 	meminfo, err := linux.ReadMeminfo()
 	if err != nil {
-		glog.Errorf("could not read meminfo: %s", err)
+		plog.Errorf("could not read meminfo: %s", err)
 		return
 	}
 	metrics.GetOrRegisterGauge("memory.total", sr.hostRegistry).Update(int64(meminfo.MemTotal))
@@ -222,7 +234,7 @@ func (sr *StatsReporter) updateHostStats() {
 
 	vmstat, err := linux.ReadVmstat()
 	if err != nil {
-		glog.Errorf("could not read vmstat: %s", err)
+		plog.Errorf("could not read vmstat: %s", err)
 		return
 	}
 	metrics.GetOrRegisterGauge("vmstat.pgfault", sr.hostRegistry).Update(int64(vmstat.Pgfault))
@@ -233,16 +245,55 @@ func (sr *StatsReporter) updateHostStats() {
 	metrics.GetOrRegisterGauge("vmstat.pswpin", sr.hostRegistry).Update(int64(vmstat.Pswpin) * 1024)
 
 	if openFileDescriptorCount, err := GetOpenFileDescriptorCount(); err != nil {
-		glog.Warningf("Couldn't get open file descriptor count", err)
+		plog.Warningf("Couldn't get open file descriptor count", err)
 	} else {
 		metrics.GetOrRegisterGauge("Serviced.OpenFileDescriptors", sr.hostRegistry).Update(openFileDescriptorCount)
 	}
 }
 
+// updateDFSStats publishes the latency, throughput, and concurrency of dfs
+// operations on the master.
+func (sr *StatsReporter) updateDFSStats() {
+	stats := dfs.GetStats()
+	for _, op := range stats.Operations {
+		prefix := "dfs." + op.Operation
+		metrics.GetOrRegisterGauge(prefix+".count", sr.hostRegistry).Update(op.Count)
+		metrics.GetOrRegisterGauge(prefix+".errors", sr.hostRegistry).Update(op.Errors)
+		metrics.GetOrRegisterGauge(prefix+".active", sr.hostRegistry).Update(op.Active)
+		metrics.GetOrRegisterGaugeFloat64(prefix+".duration.mean", sr.hostRegistry).Update(op.Mean.Seconds())
+		metrics.GetOrRegisterGaugeFloat64(prefix+".duration.p95", sr.hostRegistry).Update(op.P95.Seconds())
+		metrics.GetOrRegisterGaugeFloat64(prefix+".duration.max", sr.hostRegistry).Update(op.Max.Seconds())
+		metrics.GetOrRegisterGaugeFloat64(prefix+".throughput", sr.hostRegistry).Update(op.Throughput)
+	}
+	metrics.GetOrRegisterGauge("dfs.lock.waiting", sr.hostRegistry).Update(stats.LockWaiting)
+}
+
+// updateZZKStats publishes the listener, goroutine, and watch counts of the
+// zookeeper listeners on this host, as measured by the zzk watchdog.
+func (sr *StatsReporter) updateZZKStats() {
+	for _, name := range []string{"zzk.listeners", "zzk.goroutines", "zzk.watches", "zzk.leaks"} {
+		value := metrics.GetOrRegisterGauge(name, zzk.Metrics).Value()
+		metrics.GetOrRegisterGauge(name, sr.hostRegistry).Update(value)
+	}
+}
+
+// updateRPCStats publishes the call counts, latencies, and errors of the rpc
+// methods served by this host.
+func (sr *StatsReporter) updateRPCStats() {
+	for _, m := range rpcutils.GetServerStats() {
+		prefix := "rpc." + m.Method
+		metrics.GetOrRegisterGauge(prefix+".calls", sr.hostRegistry).Update(m.Calls)
+		metrics.GetOrRegisterGauge(prefix+".errors", sr.hostRegistry).Update(m.Errors)
+		metrics.GetOrRegisterGaugeFloat64(prefix+".duration.mean", sr.hostRegistry).Update(m.Mean.Seconds())
+		metrics.GetOrRegisterGaugeFloat64(prefix+".duration.p95", sr.hostRegistry).Update(m.P95.Seconds())
+		metrics.GetOrRegisterGaugeFloat64(prefix+".duration.max", sr.hostRegistry).Update(m.Max.Seconds())
+	}
+}
+
 func (sr *StatsReporter) updateStorageStats() {
 	volumeStatuses := volume.GetStatus()
 	if volumeStatuses == nil || len(volumeStatuses.GetAllStatuses()) == 0 {
-		glog.Errorf("Unexpected error getting volume status")
+		plog.Errorf("Unexpected error getting volume status")
 		return
 	}
 	for _, volumeStatus := range volumeStatuses.GetAllStatuses() {
@@ -253,7 +304,7 @@ func (sr *StatsReporter) updateStorageStats() {
 			}
 			fields := strings.Fields(volumeUsage.Type)
 			if len(fields) < 1 {
-				glog.Errorf("Error parsing volume usage %s", volumeUsage.Type)
+				plog.Errorf("Error parsing volume usage %s", volumeUsage.Type)
 				return
 			}
 			if fields[0] == "Total" {
@@ -269,18 +320,47 @@ func (sr *StatsReporter) updateStorageStats() {
 	}
 }
 
+// updateMuxStats publishes the connections forwarded by the mux to the exports
+// of each instance running on this host.
+func (sr *StatsReporter) updateMuxStats(states []zkservice.State) {
+	muxStats := proxy.GetMuxStats()
+	for _, rs := range states {
+		if rs.ContainerID == "" {
+			continue
+		}
+		for _, export := range rs.Exports {
+			s, ok := muxStats[fmt.Sprintf("%s:%d", rs.PrivateIP, export.PortNumber)]
+			if !ok {
+				continue
+			}
+			containerRegistry := sr.getOrCreateContainerRegistry(rs.ServiceID, rs.InstanceID)
+			prefix := "mux." + export.Application
+			metrics.GetOrRegisterGauge(prefix+".connections.active", containerRegistry).Update(s.Active)
+			metrics.GetOrRegisterGauge(prefix+".connections.total", containerRegistry).Update(s.Total)
+			metrics.GetOrRegisterGauge(prefix+".connect.errors", containerRegistry).Update(s.ConnectErrors)
+			metrics.GetOrRegisterGauge(prefix+".bytes.received", containerRegistry).Update(s.BytesReceived)
+			metrics.GetOrRegisterGauge(prefix+".bytes.sent", containerRegistry).Update(s.BytesSent)
+		}
+	}
+}
+
 // Updates the default registry.
 func (sr *StatsReporter) updateStats() {
 	// Stats for host.
 	sr.updateHostStats()
+	sr.updateZZKStats()
+	sr.updateRPCStats()
+	sr.updateStorageHealth()
 	if sr.isMasterHost {
 		sr.updateStorageStats()
+		sr.updateDFSStats()
 	}
 	// Stats for the containers.
 	states, err := zkservice.GetHostStates(sr.conn, "", sr.hostID)
 	if err != nil {
-		glog.Errorf("updateStats: zkservice.GetHostStates (conn: %+v hostID: %v) failed: %v", sr.conn, sr.hostID, err)
+		plog.Errorf("updateStats: zkservice.GetHostStates (conn: %+v hostID: %v) failed: %v", sr.conn, sr.hostID, err)
 	}
+	sr.updateMuxStats(states)
 
 	for _, rs := range states {
 		if rs.ContainerID != "" {
@@ -288,7 +368,7 @@ func (sr *StatsReporter) updateStats() {
 			containerRegistry := sr.getOrCreateContainerRegistry(rs.ServiceID, rs.InstanceID)
 			stats, err := sr.docker.GetContainerStats(rs.ContainerID, 30*time.Second)
 			if err != nil || stats == nil { //stats may be nil if service is shutting down
-				glog.Warningf("Couldn't get stats for service %s instance %d: %v", rs.ServiceID, rs.InstanceID, err)
+				plog.Warningf("Couldn't get stats for service %s instance %d: %v", rs.ServiceID, rs.InstanceID, err)
 				continue
 			}
 
@@ -316,7 +396,7 @@ func (sr *StatsReporter) updateStats() {
 			previousTotalCPU, found := sr.previousStats[key]["totalCPU"]
 			if found {
 				if totalCPU <= previousTotalCPU {
-					glog.Warningf("Change in total CPU usage was nonpositive, skipping CPU stats update.")
+					plog.Warningf("Change in total CPU usage was nonpositive, skipping CPU stats update.")
 					usePreviousStats = false
 				} else {
 					totalCPUChange = totalCPU - previousTotalCPU
@@ -349,7 +429,7 @@ func (sr *StatsReporter) updateStats() {
 				metrics.GetOrRegisterGaugeFloat64("docker.usageinkernelmode", containerRegistry).Update(kernelCPUPercent)
 				metrics.GetOrRegisterGaugeFloat64("docker.usageinusermode", containerRegistry).Update(userCPUPercent)
 			} else {
-				glog.V(4).Infof("Skipping CPU stats for %s (%d) , no previous values to compare to", rs.ServiceID, rs.InstanceID)
+				plog.Debugf("Skipping CPU stats for %s (%d) , no previous values to compare to", rs.ServiceID, rs.InstanceID)
 			}
 
 			// Memory Stats
@@ -357,18 +437,20 @@ func (sr *StatsReporter) updateStats() {
 			totalRSS := int64(stats.MemoryStats.Stats.TotalRss)
 			cache := int64(stats.MemoryStats.Stats.Cache)
 			if pgFault < 0 || totalRSS < 0 || cache < 0 {
-				glog.Warningf("Memory metric value for service %s instance %d too big for int64", rs.ServiceID, rs.InstanceID)
+				plog.Warningf("Memory metric value for service %s instance %d too big for int64", rs.ServiceID, rs.InstanceID)
 			}
 			metrics.GetOrRegisterGauge("cgroup.memory.pgmajfault", containerRegistry).Update(pgFault)
 			metrics.GetOrRegisterGauge("cgroup.memory.totalrss", containerRegistry).Update(totalRSS)
 			metrics.GetOrRegisterGauge("cgroup.memory.cache", containerRegistry).Update(cache)
+			sr.recordInstanceUsage(registryKey{rs.ServiceID, rs.InstanceID}, totalRSS, kernelCPUPercent+userCPUPercent, usePreviousStats)
 
 		} else {
-			glog.V(4).Infof("Skipping stats update for %s (%d), no container ID exists yet", rs.ServiceID, rs.InstanceID)
+			plog.Debugf("Skipping stats update for %s (%d), no container ID exists yet", rs.ServiceID, rs.InstanceID)
 		}
 	}
 	// Clean out old container registries
 	sr.removeStaleRegistries(states)
+	sr.updateInstanceUsage()
 }
 
 // Fills out the metric consumer format.
@@ -411,24 +493,24 @@ func Post(destination string, stats []Sample) error {
 	payload := map[string][]Sample{"metrics": stats}
 	data, err := json.Marshal(payload)
 	if err != nil {
-		glog.Warningf("Couldn't marshal stats: ", err)
+		plog.Warningf("Couldn't marshal stats: ", err)
 		return err
 	}
 	statsreq, err := http.NewRequest("POST", destination, bytes.NewBuffer(data))
 	if err != nil {
-		glog.Warningf("Couldn't create stats request: ", err)
+		plog.Warningf("Couldn't create stats request: ", err)
 		return err
 	}
 	statsreq.Header["User-Agent"] = []string{"Zenoss Metric Publisher"}
 	statsreq.Header["Content-Type"] = []string{"application/json"}
 	resp, reqerr := http.DefaultClient.Do(statsreq)
 	if reqerr != nil {
-		glog.Warningf("Couldn't post container stats: %s", reqerr)
+		plog.Warningf("Couldn't post container stats: %s", reqerr)
 		return reqerr
 	}
 	defer resp.Body.Close()
 	if !strings.Contains(resp.Status, "200 OK") {
-		glog.Warningf("Post for container stats failed: %s", resp.Status)
+		plog.Warningf("Post for container stats failed: %s", resp.Status)
 		return nil
 	}
 	return nil
@@ -0,0 +1,191 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/volume"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/docker/go-units"
+	dockerclient "github.com/fsouza/go-dockerclient"
+	"github.com/rcrowley/go-metrics"
+)
+
+// updateStorageHealth publishes the usage of the docker storage driver and,
+// on the master, the application volumes, so the master can flag hosts that
+// are running out of space.
+func (sr *StatsReporter) updateStorageHealth() {
+	health := host.StorageHealth{Updated: time.Now()}
+	if info, err := sr.docker.Info(); err != nil {
+		health.Errors = append(health.Errors, fmt.Sprintf("could not get docker info: %s", err))
+	} else if usage, err := dockerStorageUsage(info); err != nil {
+		health.Errors = append(health.Errors, err.Error())
+	} else {
+		health.Usage = append(health.Usage, usage...)
+	}
+	if sr.isMasterHost {
+		health.Usage = append(health.Usage, volumeStorageUsage(volume.GetStatus())...)
+	}
+
+	for _, u := range health.Usage {
+		prefix := "storage." + strings.Replace(u.Name, " ", ".", -1)
+		metrics.GetOrRegisterGauge(prefix+".total", sr.hostRegistry).Update(int64(u.Total))
+		metrics.GetOrRegisterGauge(prefix+".used", sr.hostRegistry).Update(int64(u.Used))
+	}
+	if err := zkservice.UpdateStorageHealth(sr.conn, sr.hostID, health); err != nil {
+		plog.Errorf("Could not update storage health for host %s: %s", sr.hostID, err)
+	}
+}
+
+// dockerStorageUsage returns the usage of the thin pool if docker uses
+// devicemapper, otherwise the usage of the filesystem of the docker root.
+func dockerStorageUsage(info *dockerclient.DockerInfo) ([]host.StorageUsage, error) {
+	if info.Driver != "devicemapper" {
+		var fs syscall.Statfs_t
+		if err := syscall.Statfs(info.DockerRootDir, &fs); err != nil {
+			return nil, fmt.Errorf("could not stat docker root %s: %s", info.DockerRootDir, err)
+		}
+		total := fs.Blocks * uint64(fs.Bsize)
+		available := fs.Bavail * uint64(fs.Bsize)
+		return []host.StorageUsage{{
+			Name:      "docker root",
+			Driver:    info.Driver,
+			Total:     total,
+			Used:      total - fs.Bfree*uint64(fs.Bsize),
+			Available: available,
+		}}, nil
+	}
+
+	status := make(map[string]uint64)
+	for _, kv := range info.DriverStatus {
+		if !strings.Contains(kv[0], "Space") {
+			continue
+		}
+		size, err := units.FromHumanSize(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("could not parse docker %s %q: %s", kv[0], kv[1], err)
+		}
+		status[kv[0]] = uint64(size)
+	}
+	var usage []host.StorageUsage
+	for _, space := range []string{"Data", "Metadata"} {
+		total, ok := status[space+" Space Total"]
+		if !ok {
+			return nil, fmt.Errorf("docker did not report its thin pool %s space", strings.ToLower(space))
+		}
+		usage = append(usage, host.StorageUsage{
+			Name:      "docker thin pool " + strings.ToLower(space),
+			Driver:    info.Driver,
+			Total:     total,
+			Used:      status[space+" Space Used"],
+			Available: status[space+" Space Available"],
+		})
+	}
+	return usage, nil
+}
+
+// volumeStorageUsage returns the usage of the thin pools and filesystems
+// backing the application volumes.
+func volumeStorageUsage(statuses *volume.Statuses) []host.StorageUsage {
+	var usage []host.StorageUsage
+	if statuses == nil {
+		return usage
+	}
+	var paths []string
+	for path := range statuses.DeviceMapperStatusMap {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		s := statuses.DeviceMapperStatusMap[path]
+		data := host.StorageUsage{
+			Name:      "application thin pool data",
+			Driver:    string(s.Driver),
+			Total:     s.PoolDataTotal,
+			Used:      s.PoolDataUsed,
+			Available: s.PoolDataAvailable,
+		}
+		// the filesystem of each tenant reports its logical usage, while
+		// its thin device reports the space it actually takes in the pool
+		var tenants []host.StorageUsage
+		for _, t := range s.Tenants {
+			u := host.StorageUsage{
+				Name:        fmt.Sprintf("application %s filesystem", t.TenantID),
+				Driver:      string(s.Driver),
+				Total:       t.FilesystemTotal,
+				Used:        t.FilesystemUsed,
+				Available:   t.FilesystemAvailable,
+				Allocated:   volume.BlocksToByteCount(t.DeviceAllocatedBlocks),
+				Reclaimable: volume.BlocksToByteCount(t.ReclaimableBlocks),
+			}
+			data.Allocated += u.Allocated
+			data.Reclaimable += u.Reclaimable
+			tenants = append(tenants, u)
+		}
+		usage = append(usage, data, host.StorageUsage{
+			Name:      "application thin pool metadata",
+			Driver:    string(s.Driver),
+			Total:     s.PoolMetadataTotal,
+			Used:      s.PoolMetadataUsed,
+			Available: s.PoolMetadataAvailable,
+		})
+		usage = append(usage, tenants...)
+	}
+
+	paths = paths[:0]
+	for path := range statuses.SimpleStatusMap {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		s := statuses.SimpleStatusMap[path]
+		byLabel := make(map[string]*host.StorageUsage)
+		var labels []string
+		for _, data := range s.UsageData {
+			fields := strings.Fields(data.Type)
+			if data.MetricName != "" || len(fields) == 0 {
+				continue
+			}
+			u, ok := byLabel[data.Label]
+			if !ok {
+				u = &host.StorageUsage{
+					Name:   strings.TrimSpace("application " + data.Label),
+					Driver: string(s.Driver),
+				}
+				byLabel[data.Label] = u
+				labels = append(labels, data.Label)
+			}
+			switch fields[0] {
+			case "Total":
+				u.Total = data.Value
+			case "Used":
+				u.Used = data.Value
+			case "Available":
+				u.Available = data.Value
+			}
+		}
+		for _, label := range labels {
+			if u := byLabel[label]; u.Total > 0 {
+				usage = append(usage, *u)
+			}
+		}
+	}
+	return usage
+}
@@ -0,0 +1,115 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package stats
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/volume"
+	dockerclient "github.com/fsouza/go-dockerclient"
+)
+
+func TestDockerStorageUsage_DeviceMapper(t *testing.T) {
+	info := &dockerclient.DockerInfo{
+		Driver: "devicemapper",
+		DriverStatus: [][2]string{
+			{"Pool Name", "docker-thinpool"},
+			{"Data Space Used", "80 GB"},
+			{"Data Space Total", "100 GB"},
+			{"Data Space Available", "20 GB"},
+			{"Metadata Space Used", "1 GB"},
+			{"Metadata Space Total", "2 GB"},
+			{"Metadata Space Available", "1 GB"},
+		},
+	}
+	usage, err := dockerStorageUsage(info)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(usage) != 2 {
+		t.Fatalf("expected 2 usages, got %d", len(usage))
+	}
+	if usage[0].Name != "docker thin pool data" || usage[0].PercentUsed() != 80 {
+		t.Errorf("unexpected data usage: %+v", usage[0])
+	}
+	if usage[1].Name != "docker thin pool metadata" || usage[1].PercentUsed() != 50 {
+		t.Errorf("unexpected metadata usage: %+v", usage[1])
+	}
+
+	info.DriverStatus = [][2]string{{"Data Space Used", "lots"}}
+	if _, err := dockerStorageUsage(info); err == nil {
+		t.Errorf("expected an error parsing the driver status")
+	}
+}
+
+func TestVolumeStorageUsage(t *testing.T) {
+	statuses := &volume.Statuses{
+		SimpleStatusMap: map[string]*volume.SimpleStatus{
+			"/opt/serviced/var/volumes": {
+				Driver: volume.DriverTypeBtrFS,
+				UsageData: []volume.Usage{
+					{Label: "Data single", Type: "Total", Value: 100},
+					{Label: "Data single", Type: "Used", Value: 25},
+					{Label: "Metadata DUP", Type: "Total", Value: 0},
+				},
+			},
+		},
+	}
+	usage := volumeStorageUsage(statuses)
+	if len(usage) != 1 {
+		t.Fatalf("expected 1 usage, got %d", len(usage))
+	}
+	if usage[0].Name != "application Data single" || usage[0].PercentUsed() != 25 {
+		t.Errorf("unexpected usage: %+v", usage[0])
+	}
+}
+
+func TestVolumeStorageUsage_DeviceMapper(t *testing.T) {
+	statuses := &volume.Statuses{
+		DeviceMapperStatusMap: map[string]*volume.DeviceMapperStatus{
+			"/opt/serviced/var/volumes": {
+				Driver:            volume.DriverTypeDeviceMapper,
+				PoolDataTotal:     1000,
+				PoolDataUsed:      400,
+				PoolDataAvailable: 600,
+				Tenants: []volume.TenantStorageStats{
+					{
+						TenantID:              "tenant",
+						FilesystemTotal:       volume.BlocksToByteCount(100),
+						FilesystemUsed:        volume.BlocksToByteCount(20),
+						FilesystemAvailable:   volume.BlocksToByteCount(80),
+						DeviceTotalBlocks:     100,
+						DeviceAllocatedBlocks: 50,
+						ReclaimableBlocks:     30,
+					},
+				},
+			},
+		},
+	}
+	usage := volumeStorageUsage(statuses)
+	if len(usage) != 3 {
+		t.Fatalf("expected 3 usages, got %d", len(usage))
+	}
+	if u := usage[0]; u.Name != "application thin pool data" || u.Allocated != volume.BlocksToByteCount(50) || u.Reclaimable != volume.BlocksToByteCount(30) {
+		t.Errorf("unexpected data usage: %+v", u)
+	}
+	if u := usage[2]; u.Name != "application tenant filesystem" || u.PercentUsed() != 20 || !u.IsDFS() || u.IsThinPool() {
+		t.Errorf("unexpected tenant usage: %+v", u)
+	}
+	if u := usage[2]; u.Allocated != volume.BlocksToByteCount(50) || u.Reclaimable != volume.BlocksToByteCount(30) {
+		t.Errorf("unexpected tenant allocation: %+v", u)
+	}
+}
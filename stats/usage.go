@@ -0,0 +1,96 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// usageWindow is the span of the samples used to compute the max and average
+// memory usage of an instance
+const usageWindow = time.Hour
+
+// instanceUsage holds the recent resource usage samples of an instance
+type instanceUsage struct {
+	memory []int64
+	cpu    float64
+}
+
+// usageSamples returns the number of samples taken within the usage window
+func usageSamples(interval time.Duration) int {
+	if interval <= 0 || interval >= usageWindow {
+		return 1
+	}
+	return int(usageWindow / interval)
+}
+
+// recordInstanceUsage adds a memory sample and the latest cpu usage of an
+// instance, keeping at most max memory samples.
+func (sr *StatsReporter) recordInstanceUsage(key registryKey, memory int64, cpu float64, hasCPU bool) {
+	sr.Lock()
+	defer sr.Unlock()
+	u, ok := sr.usage[key]
+	if !ok {
+		u = &instanceUsage{}
+		sr.usage[key] = u
+	}
+	u.memory = append(u.memory, memory)
+	if n := len(u.memory) - sr.usageSamples; n > 0 {
+		u.memory = u.memory[n:]
+	}
+	if hasCPU {
+		u.cpu = cpu
+	}
+}
+
+// instanceUsageReport summarizes the samples of each instance
+func (sr *StatsReporter) instanceUsageReport(now time.Time) map[string]service.InstanceUsage {
+	sr.Lock()
+	defer sr.Unlock()
+	report := make(map[string]service.InstanceUsage)
+	for key, u := range sr.usage {
+		if len(u.memory) == 0 {
+			continue
+		}
+		var max, total int64
+		for _, m := range u.memory {
+			if m > max {
+				max = m
+			}
+			total += m
+		}
+		report[service.InstanceUsageKey(key.serviceID, key.instanceID)] = service.InstanceUsage{
+			Memory: service.Usage{
+				Cur: u.memory[len(u.memory)-1],
+				Max: max,
+				Avg: total / int64(len(u.memory)),
+			},
+			CPUPercent: u.cpu,
+			Updated:    now,
+		}
+	}
+	return report
+}
+
+// updateInstanceUsage publishes the resource usage of the instances running
+// on this host, so that the master can report the status of services without
+// querying the metrics backend.
+func (sr *StatsReporter) updateInstanceUsage() {
+	if err := zkservice.UpdateInstanceUsage(sr.conn, sr.hostID, sr.instanceUsageReport(time.Now())); err != nil {
+		plog.Errorf("Could not update instance usage for host %s: %s", sr.hostID, err)
+	}
+}
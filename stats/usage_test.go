@@ -0,0 +1,59 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+)
+
+func TestInstanceUsageReport(t *testing.T) {
+	sr := &StatsReporter{
+		usage:        make(map[registryKey]*instanceUsage),
+		usageSamples: usageSamples(20 * time.Minute),
+	}
+	key := registryKey{"svc", 1}
+	sr.recordInstanceUsage(key, 100, 0, false)
+	sr.recordInstanceUsage(key, 400, 25, true)
+	sr.recordInstanceUsage(key, 200, 0, false)
+	sr.recordInstanceUsage(key, 300, 50, true)
+
+	now := time.Now()
+	report := sr.instanceUsageReport(now)
+	expected := service.InstanceUsage{
+		Memory:     service.Usage{Cur: 300, Max: 400, Avg: 300},
+		CPUPercent: 50,
+		Updated:    now,
+	}
+	if actual, ok := report["svc-1"]; !ok || actual != expected {
+		t.Errorf("expected usage %+v, got %+v", expected, report)
+	}
+}
+
+func TestUsageSamples(t *testing.T) {
+	for interval, expected := range map[time.Duration]int{
+		10 * time.Second: 360,
+		time.Minute:      60,
+		2 * time.Hour:    1,
+		0:                1,
+	} {
+		if actual := usageSamples(interval); actual != expected {
+			t.Errorf("interval %s: expected %d samples, got %d", interval, expected, actual)
+		}
+	}
+}
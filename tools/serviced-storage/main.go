@@ -36,7 +36,7 @@ import (
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/servicedversion"
 	"github.com/jessevdk/go-flags"
-	"github.com/zenoss/glog"
+	"github.com/zenoss/logri"
 )
 
 var Version string
@@ -118,11 +118,8 @@ func (s *ServicedStorage) initializeLogging() {
 	level := log.WarnLevel + log.Level(len(App.Options.Verbose))
 	log.SetLevel(level)
 
-	// Include glog output if verbosity is enabled
-	if len(App.Options.Verbose) > 0 {
-		glog.SetToStderr(true)
-		glog.SetVerbosity(len(App.Options.Verbose))
-	}
+	// Log the messages of the volume drivers at the same level
+	logri.SetLevel(level)
 }
 
 // Execute prints the application version to stdout and exits
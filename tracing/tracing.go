@@ -0,0 +1,256 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing records spans of work done on behalf of a request so that
+// slow requests can be followed across the facade, the datastore, zookeeper,
+// and the dfs.  Spans are sampled per trace and exported in batches.
+package tracing
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/logging"
+	"golang.org/x/net/context"
+)
+
+var (
+	log = logging.PackageLogger()
+
+	// batchSize is the most spans sent in one export
+	batchSize = 100
+	// flushInterval is the longest a finished span waits to be exported
+	flushInterval = 5 * time.Second
+)
+
+// Exporter sends finished spans to a trace collector
+type Exporter interface {
+	Export(spans []*Span) error
+}
+
+// Span is a timed operation that is part of a trace.  Methods on a nil span
+// do nothing, so callers do not need to check whether a trace was sampled.
+type Span struct {
+	TraceID   string
+	ID        string
+	ParentID  string
+	Name      string
+	Component string
+	Start     time.Time
+	Duration  time.Duration
+	Tags      map[string]string
+
+	tracer *Tracer
+	mu     sync.Mutex
+}
+
+// Child starts a span within this span's trace
+func (s *Span) Child(component, name string) *Span {
+	if s == nil {
+		return nil
+	}
+	child := s.tracer.newSpan(s.TraceID, component, name)
+	child.ParentID = s.ID
+	return child
+}
+
+// SetTag annotates the span
+func (s *Span) SetTag(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Tags[key] = value
+}
+
+// SetError marks the span as failed if err is not nil
+func (s *Span) SetError(err error) {
+	if err != nil {
+		s.SetTag("error", err.Error())
+	}
+}
+
+// Finish records the duration of the span and queues it for export
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.Duration = time.Since(s.Start)
+	s.mu.Unlock()
+	s.tracer.queue(s)
+}
+
+// Tracer samples traces and exports their spans
+type Tracer struct {
+	sampleRate float64
+	exporter   Exporter
+	spans      chan *Span
+	dropped    int
+	mu         sync.Mutex
+	rand       *rand.Rand
+}
+
+// NewTracer returns a tracer that records the given fraction of traces,
+// between 0 and 1, and sends them to the exporter.  Spans are not exported
+// until Run is called.
+func NewTracer(sampleRate float64, exporter Exporter) *Tracer {
+	return &Tracer{
+		sampleRate: sampleRate,
+		exporter:   exporter,
+		spans:      make(chan *Span, 10*batchSize),
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// StartSpan starts a new trace.  It returns nil if the trace is not sampled.
+func (t *Tracer) StartSpan(component, name string) *Span {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	sampled := t.rand.Float64() < t.sampleRate
+	traceID := fmt.Sprintf("%016x%016x", t.rand.Int63(), t.rand.Int63())
+	t.mu.Unlock()
+	if !sampled {
+		return nil
+	}
+	return t.newSpan(traceID, component, name)
+}
+
+func (t *Tracer) newSpan(traceID, component, name string) *Span {
+	t.mu.Lock()
+	id := fmt.Sprintf("%016x", t.rand.Int63())
+	t.mu.Unlock()
+	return &Span{
+		TraceID:   traceID,
+		ID:        id,
+		Name:      name,
+		Component: component,
+		Start:     time.Now(),
+		Tags:      make(map[string]string),
+		tracer:    t,
+	}
+}
+
+// queue adds a finished span to the next export, dropping it if the exporter
+// is falling behind.
+func (t *Tracer) queue(s *Span) {
+	select {
+	case t.spans <- s:
+	default:
+		t.mu.Lock()
+		t.dropped++
+		t.mu.Unlock()
+	}
+}
+
+// Run exports finished spans in batches until shutdown
+func (t *Tracer) Run(shutdown <-chan interface{}) {
+	var batch []*Span
+	timer := time.NewTimer(flushInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case s := <-t.spans:
+			if batch = append(batch, s); len(batch) < batchSize {
+				continue
+			}
+		case <-timer.C:
+			timer.Reset(flushInterval)
+		case <-shutdown:
+			// export the spans that finished before shutdown
+			for {
+				select {
+				case s := <-t.spans:
+					batch = append(batch, s)
+				default:
+					t.export(batch)
+					return
+				}
+			}
+		}
+		t.export(batch)
+		batch = nil
+	}
+}
+
+func (t *Tracer) export(batch []*Span) {
+	t.mu.Lock()
+	dropped := t.dropped
+	t.dropped = 0
+	t.mu.Unlock()
+	if dropped > 0 {
+		log.WithFields(logrus.Fields{
+			"dropped": dropped,
+		}).Warn("Dropped trace spans because the exporter is falling behind")
+	}
+	if len(batch) == 0 {
+		return
+	}
+	if err := t.exporter.Export(batch); err != nil {
+		log.WithError(err).WithFields(logrus.Fields{
+			"spans": len(batch),
+		}).Warn("Unable to export trace spans")
+	}
+}
+
+var (
+	tracerLock sync.RWMutex
+	tracer     *Tracer
+)
+
+// SetTracer sets the tracer used by StartSpan.  A nil tracer disables
+// tracing.
+func SetTracer(t *Tracer) {
+	tracerLock.Lock()
+	defer tracerLock.Unlock()
+	tracer = t
+}
+
+// Enabled returns true if a tracer has been set
+func Enabled() bool {
+	tracerLock.RLock()
+	defer tracerLock.RUnlock()
+	return tracer != nil
+}
+
+// StartSpan starts a new trace with the tracer set by SetTracer.  It returns
+// nil if tracing is disabled or the trace is not sampled.
+func StartSpan(component, name string) *Span {
+	tracerLock.RLock()
+	defer tracerLock.RUnlock()
+	return tracer.StartSpan(component, name)
+}
+
+// spanKey is the key of the span carried by a context
+type spanKey struct{}
+
+// ContextWithSpan returns a copy of the parent context that carries the span,
+// so that work done on behalf of the context is recorded in the span's trace.
+// A nil span is carried too, so that the work of an unsampled trace is not
+// sampled as a trace of its own.
+func ContextWithSpan(parent context.Context, s *Span) context.Context {
+	return context.WithValue(parent, spanKey{}, s)
+}
+
+// SpanFromContext returns the span carried by the context, and whether the
+// context carries one at all.
+func SpanFromContext(ctx context.Context) (*Span, bool) {
+	s, ok := ctx.Value(spanKey{}).(*Span)
+	return s, ok
+}
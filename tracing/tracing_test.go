@@ -0,0 +1,175 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+func (r *recorder) Export(spans []*Span) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+// record runs the tracer until f returns and returns the exported spans
+func record(t *Tracer, r *recorder, f func()) []*Span {
+	shutdown := make(chan interface{})
+	done := make(chan struct{})
+	go func() {
+		t.Run(shutdown)
+		close(done)
+	}()
+	f()
+	close(shutdown)
+	<-done
+	return r.spans
+}
+
+func TestSpans(t *testing.T) {
+	r := &recorder{}
+	tracer := NewTracer(1, r)
+	spans := record(tracer, r, func() {
+		root := tracer.StartSpan("rpc", "Master.GetHost")
+		child := root.Child("facade", "GetHost")
+		child.SetTag("hostid", "host1")
+		child.SetError(errors.New("host not found"))
+		child.Finish()
+		root.Finish()
+	})
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	child, root := spans[0], spans[1]
+	if root.ParentID != "" || child.ParentID != root.ID {
+		t.Errorf("child %s is not nested in root %s", child.ParentID, root.ID)
+	}
+	if child.TraceID != root.TraceID || len(root.TraceID) != 32 {
+		t.Errorf("spans have trace ids %s and %s", child.TraceID, root.TraceID)
+	}
+	if child.Component != "facade" || child.Name != "GetHost" {
+		t.Errorf("unexpected child span %s %s", child.Component, child.Name)
+	}
+	if child.Tags["hostid"] != "host1" || child.Tags["error"] != "host not found" {
+		t.Errorf("unexpected tags %v", child.Tags)
+	}
+}
+
+func TestUnsampledSpans(t *testing.T) {
+	r := &recorder{}
+	tracer := NewTracer(0, r)
+	spans := record(tracer, r, func() {
+		root := tracer.StartSpan("rpc", "Master.GetHost")
+		if root != nil {
+			t.Errorf("expected trace not to be sampled")
+		}
+		child := root.Child("facade", "GetHost")
+		child.SetTag("hostid", "host1")
+		child.SetError(errors.New("host not found"))
+		child.Finish()
+		root.Finish()
+	})
+	if len(spans) != 0 {
+		t.Errorf("expected no spans, got %d", len(spans))
+	}
+}
+
+func TestGlobalTracer(t *testing.T) {
+	defer SetTracer(nil)
+	if Enabled() || StartSpan("rpc", "Master.GetHost") != nil {
+		t.Errorf("expected tracing to be disabled")
+	}
+	SetTracer(NewTracer(1, &recorder{}))
+	if !Enabled() || StartSpan("rpc", "Master.GetHost") == nil {
+		t.Errorf("expected tracing to be enabled")
+	}
+}
+
+func TestZipkinExporter(t *testing.T) {
+	var received []zipkinSpan
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("could not decode spans: %s", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	start := time.Unix(1500000000, 0)
+	span := &Span{
+		TraceID:   "0123456789abcdef0123456789abcdef",
+		ID:        "0123456789abcdef",
+		ParentID:  "fedcba9876543210",
+		Name:      "GetHost",
+		Component: "facade",
+		Start:     start,
+		Duration:  1500 * time.Microsecond,
+		Tags:      map[string]string{"hostid": "host1"},
+	}
+	exporter := NewZipkinExporter(server.URL, "serviced")
+	if err := exporter.Export([]*Span{span}); err != nil {
+		t.Fatalf("could not export spans: %s", err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(received))
+	}
+	z := received[0]
+	if z.TraceID != span.TraceID || z.ID != span.ID || z.ParentID != span.ParentID || z.Name != "GetHost" {
+		t.Errorf("unexpected span %+v", z)
+	}
+	if z.Timestamp != 1500000000000000 || z.Duration != 1500 {
+		t.Errorf("unexpected timestamp %d or duration %d", z.Timestamp, z.Duration)
+	}
+	if z.LocalEndpoint.ServiceName != "serviced" || z.Tags["component"] != "facade" || z.Tags["hostid"] != "host1" {
+		t.Errorf("unexpected endpoint %v or tags %v", z.LocalEndpoint, z.Tags)
+	}
+
+	exporter.URL = server.URL + "/missing"
+	server.Config.Handler = http.NotFoundHandler()
+	if err := exporter.Export([]*Span{span}); err == nil {
+		t.Errorf("expected an error from the collector")
+	}
+}
+
+func TestContextWithSpan(t *testing.T) {
+	tracer := NewTracer(1, &recorder{})
+	span := tracer.StartSpan("rpc", "Master.GetHost")
+	if _, ok := SpanFromContext(context.Background()); ok {
+		t.Errorf("expected no span in the background context")
+	}
+	if s, ok := SpanFromContext(ContextWithSpan(context.Background(), span)); !ok || s != span {
+		t.Errorf("expected the span from the context, got %v", s)
+	}
+	if s, ok := SpanFromContext(ContextWithSpan(context.Background(), nil)); !ok || s != nil {
+		t.Errorf("expected an unsampled span from the context, got %v", s)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// zipkinSpan is a span in the Zipkin v2 JSON format, which is also accepted
+// by the Jaeger collector's Zipkin endpoint.
+type zipkinSpan struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint zipkinEndpoint    `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+// ZipkinExporter posts spans to a Zipkin v2 spans endpoint, such as
+// http://zipkin:9411/api/v2/spans or the Zipkin endpoint of a Jaeger
+// collector.
+type ZipkinExporter struct {
+	URL         string
+	ServiceName string
+	Client      *http.Client
+}
+
+// NewZipkinExporter returns an exporter that posts spans to url, reporting
+// them as coming from serviceName
+func NewZipkinExporter(url, serviceName string) *ZipkinExporter {
+	return &ZipkinExporter{
+		URL:         url,
+		ServiceName: serviceName,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Export implements Exporter
+func (e *ZipkinExporter) Export(spans []*Span) error {
+	body, err := json.Marshal(e.convert(spans))
+	if err != nil {
+		return err
+	}
+	resp, err := e.Client.Post(e.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("trace collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (e *ZipkinExporter) convert(spans []*Span) []zipkinSpan {
+	zspans := make([]zipkinSpan, len(spans))
+	for i, s := range spans {
+		s.mu.Lock()
+		tags := make(map[string]string, len(s.Tags)+1)
+		for k, v := range s.Tags {
+			tags[k] = v
+		}
+		if s.Component != "" {
+			tags["component"] = s.Component
+		}
+		zspans[i] = zipkinSpan{
+			TraceID:       s.TraceID,
+			ID:            s.ID,
+			ParentID:      s.ParentID,
+			Name:          s.Name,
+			Timestamp:     s.Start.UnixNano() / int64(time.Microsecond),
+			Duration:      int64(s.Duration / time.Microsecond),
+			LocalEndpoint: zipkinEndpoint{ServiceName: e.ServiceName},
+			Tags:          tags,
+		}
+		s.mu.Unlock()
+	}
+	return zspans
+}
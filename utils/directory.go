@@ -26,8 +26,6 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
-
-	"github.com/zenoss/glog"
 )
 
 //ServiceDHome gets the home location of serviced by looking at the enviornment
@@ -68,7 +66,7 @@ func TempDir(p string) string {
 		tmp = path.Join(os.TempDir(), fmt.Sprintf("serviced-%s", user.Username), p)
 	} else {
 		tmp = path.Join(os.TempDir(), fmt.Sprintf("serviced"), p)
-		glog.Warningf("Defaulting home to %s", tmp)
+		plog.Warningf("Defaulting home to %s", tmp)
 	}
 
 	return tmp
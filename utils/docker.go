@@ -14,8 +14,6 @@
 package utils
 
 import (
-	"github.com/zenoss/glog"
-
 	"github.com/docker/docker/cliconfig"
 	"github.com/docker/docker/registry"
 )
@@ -25,13 +23,13 @@ func DockerIsLoggedIn() bool {
 	// Load the user's ~/.docker/config.json file if it exists.
 	configFile, err := cliconfig.Load("")
 	if err != nil {
-		glog.Errorf("Error checking Docker Hub login: %s", err)
+		plog.Errorf("Error checking Docker Hub login: %s", err)
 		return false
 	}
 
 	// Make sure there is at least one AuthConfig (credential set).
 	if len(configFile.AuthConfigs) < 1 {
-		glog.Errorf("Error checking Docker Hub login: config.json is not populated")
+		plog.Errorf("Error checking Docker Hub login: config.json is not populated")
 		return false
 	}
 
@@ -44,6 +42,6 @@ func DockerIsLoggedIn() bool {
 		}
 	}
 
-	glog.Errorf("Error checking Docker Hub login: no credentials in config.json succeeded")
+	plog.Errorf("Error checking Docker Hub login: no credentials in config.json succeeded")
 	return false
 }
@@ -19,8 +19,6 @@ import (
 	"os/exec"
 	"strings"
 	"syscall"
-
-	"github.com/zenoss/glog"
 )
 
 // An error type for failed docker exec attempts.
@@ -39,7 +37,7 @@ func ExecDockerExec(containerID string, bashcmd []string) error {
 	if err != nil {
 		return err
 	}
-	glog.V(1).Infof("exec command for container:%v command: %v\n", containerID, command)
+	plog.Debugf("exec command for container:%v command: %v\n", containerID, command)
 	return syscall.Exec(command[0], command[0:], os.Environ())
 }
 
@@ -58,7 +56,7 @@ func RunDockerExec(containerID string, bashcmd []string) ([]byte, error) {
 		err = DockerExecError{strings.Join(command, " "), err}
 		return output, err
 	}
-	glog.V(1).Infof("Successfully ran command:'%s' output: %s\n", command, output)
+	plog.Debugf("Successfully ran command:'%s' output: %s\n", command, output)
 	return output, nil
 }
 
@@ -90,7 +88,7 @@ func generateDockerExecCommand(containerID string, bashcmd []string, prependBash
 	} else {
 		attachCmd = append(attachCmd, bashcmd...)
 	}
-	glog.V(1).Infof("attach command for container:%v command: %v\n", containerID, attachCmd)
+	plog.Debugf("attach command for container:%v command: %v\n", containerID, attachCmd)
 	return attachCmd, nil
 }
 
@@ -102,7 +100,7 @@ func hasFeatureDockerExec() bool {
 	output, err := thecmd.CombinedOutput()
 	// when docker exec is supported, we expect above 'docker exec' to fail,
 	// but provide usage
-	glog.V(1).Infof("Successfully ran command:'%s'  err: %s  output: %s\n", command, err, output)
+	plog.Debugf("Successfully ran command:'%s'  err: %s  output: %s\n", command, err, output)
 
 	return strings.Contains(string(output), "Usage: docker exec")
 }
@@ -124,7 +122,7 @@ func exePaths(exes []string) (map[string]string, error) {
 	for _, exe := range exes {
 		path, err := exec.LookPath(exe)
 		if err != nil {
-			glog.Errorf("exe:'%v' not found error:%v\n", exe, err)
+			plog.Errorf("exe:'%v' not found error:%v\n", exe, err)
 			return nil, err
 		}
 
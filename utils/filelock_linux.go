@@ -18,7 +18,6 @@ package utils
 //extern int fd_unlock(int fd, char* filepath);
 import "C"
 import (
-	"github.com/zenoss/glog"
 	//"golang.org/x/sys/unix"  // requires go 1.4
 
 	"fmt"
@@ -38,18 +37,18 @@ func LockFile(filelockpath string) (*os.File, error) {
 		// will prevent file lockfile cleanup even if there is a defer os.Remove(lockfile)
 	WAIT_FOR_LOCK:
 		for {
-			glog.V(2).Infof("locking file: %s", filelockpath)
+			plog.Debugf("locking file: %s", filelockpath)
 			fp, err := os.OpenFile(filelockpath, syscall.O_RDWR|syscall.O_CREAT|syscall.O_EXCL, 0600)
 			if err != nil {
 				if !strings.Contains(err.Error(), syscall.EEXIST.Error()) {
 					return nil, err
 				}
 			} else {
-				glog.V(2).Infof("locked file: %s", filelockpath)
+				plog.Debugf("locked file: %s", filelockpath)
 				return fp, nil
 			}
 
-			glog.Infof("waiting to lock file: %s", filelockpath)
+			plog.Infof("waiting to lock file: %s", filelockpath)
 			select {
 			case <-time.After(1 * time.Second):
 				continue WAIT_FOR_LOCK
@@ -65,7 +64,7 @@ func LockFile(filelockpath string) (*os.File, error) {
 			return nil, err
 		}
 
-		glog.V(2).Infof("locking file: %s", filelockpath)
+		plog.Debugf("locking file: %s", filelockpath)
 		if false {
 			// FIXME: this is not working from GO, but works from C - thread issue even when sync mutex is used
 			var flp = C.CString(filelockpath)
@@ -91,7 +90,7 @@ func LockFile(filelockpath string) (*os.File, error) {
 						Len:    0,                       // Number of bytes to lock
 						Pid:    int32(syscall.Getpid()), // PID of process blocking our lock (F_GETLK only)
 					}
-					glog.V(2).Infof("syscall.Flock_t %#v", ft)
+					plog.Debugf("syscall.Flock_t %#v", ft)
 
 					if err := unix.FcntlFlock(fp.Fd(), syscall.F_SETLKW, &ft); err != nil {
 						return nil, err
@@ -113,7 +112,7 @@ func LockFile(filelockpath string) (*os.File, error) {
 				Len:    0,                       // Number of bytes to lock
 				Pid:    int32(syscall.Getpid()), // PID of process blocking our lock (F_GETLK only)
 			}
-			glog.V(2).Infof("syscall.Flock_t %#v", ft)
+			plog.Debugf("syscall.Flock_t %#v", ft)
 
 			if err := syscall.FcntlFlock(fp.Fd(), syscall.F_SETLKW /* F_GETLK, F_SETLK, F_SETLKW */, &ft); err != nil {
 				return nil, err
@@ -135,7 +134,7 @@ func LockFile(filelockpath string) (*os.File, error) {
 				Pid:    uint32(os.Getpid()),
 			}
 
-			glog.V(2).Infof("mimic /usr/include/x86_64-linux-gnu/bits/fcntl.h syscall.Flock_t %#v", k)
+			plog.Debugf("mimic /usr/include/x86_64-linux-gnu/bits/fcntl.h syscall.Flock_t %#v", k)
 			_, _, errno := syscall.Syscall(syscall.SYS_FCNTL, fp.Fd(), uintptr(syscall.F_SETLK), uintptr(unsafe.Pointer(&k)))
 			if errno != 0 {
 				fp.Close()
@@ -145,7 +144,7 @@ func LockFile(filelockpath string) (*os.File, error) {
 			return nil, fmt.Errorf("all filelocking implementations fail")
 		}
 
-		glog.V(2).Infof("locked  file: %s", filelockpath)
+		plog.Debugf("locked  file: %s", filelockpath)
 		return fp, nil
 	}
 }
\ No newline at end of file
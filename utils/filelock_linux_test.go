@@ -24,8 +24,6 @@ import (
 	"path"
 	"reflect"
 	"time"
-
-	"github.com/zenoss/glog"
 )
 
 // TestFileLock
@@ -53,7 +51,7 @@ func TestFileLock(t *testing.T) {
 
 	filelock := path.Join(lockdir, "file.lck")
 	lockuser := func(id int, events chan string) {
-		glog.V(0).Infof("%s:%d locking  %s", hostid, id, lockdir)
+		plog.Infof("%s:%d locking  %s", hostid, id, lockdir)
 		fp, err := LockFile(filelock)
 		if err != nil {
 			t.Fatalf("unable to lock %+v: %s", filelock, err)
@@ -63,10 +61,10 @@ func TestFileLock(t *testing.T) {
 		fp.WriteString(fmt.Sprintf("%s:%d\n", hostid, id))
 		events <- "locked"
 		duration := 5 * time.Second
-		glog.V(0).Infof("%s:%d locked    %s - sleeping %s", hostid, id, lockdir, duration)
+		plog.Infof("%s:%d locked    %s - sleeping %s", hostid, id, lockdir, duration)
 		time.Sleep(duration)
 		events <- "done"
-		glog.V(0).Infof("%s:%d unlocking %s", hostid, id, lockdir)
+		plog.Infof("%s:%d unlocking %s", hostid, id, lockdir)
 	}
 
 	events := make(chan string)
@@ -79,14 +77,14 @@ func TestFileLock(t *testing.T) {
 	reported := []string{}
 WAIT_FOR_REPORTERS:
 	for {
-		glog.V(2).Infof("waiting for reporters - reported: %+v", reported)
+		plog.Debugf("waiting for reporters - reported: %+v", reported)
 		select {
 		case <-time.After(time.Duration(numClients*5+numClients) * time.Second):
 			break WAIT_FOR_REPORTERS
 
 		case ev := <-events:
 			reported = append(reported, ev)
-			glog.V(2).Infof("reported: %+v", reported)
+			plog.Debugf("reported: %+v", reported)
 		}
 	}
 
@@ -18,12 +18,10 @@ package utils
 import (
 	"fmt"
 	"os"
-
-	"github.com/zenoss/glog"
 )
 
 func LockFile(_ string) (*os.File, error) {
 	err := fmt.Errorf("LockFile is not supported on this platform")
-	glog.Errorf(err.Error())
+	plog.Errorf(err.Error())
 	return nil, err
 }
@@ -5,8 +5,6 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
-
-	"github.com/zenoss/glog"
 )
 
 // URL parses and handles URL typed options
@@ -42,8 +40,8 @@ func GetGateway(defaultRPCPort int) string {
 	localhost := URL{"127.0.0.1", defaultRPCPort}
 
 	if err != nil {
-		glog.V(2).Info("Error checking gateway: ", err)
-		glog.V(1).Info("Could not get gateway using ", localhost.Host)
+		plog.Debug("Error checking gateway: ", err)
+		plog.Debug("Could not get gateway using ", localhost.Host)
 		return localhost.String()
 	}
 
@@ -54,6 +52,6 @@ func GetGateway(defaultRPCPort int) string {
 			return endpoint.String()
 		}
 	}
-	glog.V(1).Info("No gateway found, using ", localhost.Host)
+	plog.Debug("No gateway found, using ", localhost.Host)
 	return localhost.String()
 }
@@ -72,10 +72,35 @@ func NewEnvironOnlyConfigReader(prefix string) *EnvironConfigReader {
 	return &EnvironConfigReader{prefix, map[string]ConfigValue{}}
 }
 
+// ReadEnvironFile reads the key=value pairs of a configuration file into a
+// map, without setting them in the environment.
+func ReadEnvironFile(filename string) (map[string]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	values := make(map[string]string)
+	err = readKeyValues(file, func(key, value string) error {
+		values[key] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
 // parse is a really dumb reader parser.  It maps only key values in the form
 // of key=value and strips whitespaces surrounding either field.  If the format
 // does not match, then and error will return.
 func (p *EnvironConfigReader) parse(reader io.Reader) error {
+	return readKeyValues(reader, p.keyvalue)
+}
+
+// readKeyValues calls f with each key=value pair of the reader, skipping
+// comments and blank lines.
+func readKeyValues(reader io.Reader, f func(key, value string) error) error {
 	var (
 		line string
 		err  error
@@ -88,9 +113,14 @@ func (p *EnvironConfigReader) parse(reader io.Reader) error {
 			return err
 		}
 
-		line = strings.TrimSpace(strings.Split(line, "#")[0])
-		if err := p.keyvalue([]byte(line)); err != nil {
-			return err
+		pair := strings.TrimSpace(strings.Split(line, "#")[0])
+		if idx := strings.Index(pair, "="); idx >= 0 {
+			key, value := strings.TrimSpace(pair[:idx]), translate(strings.TrimSpace(pair[idx+1:]))
+			if err := f(key, value); err != nil {
+				return err
+			}
+		} else if pair != "" {
+			return ParseError{pair}
 		}
 	}
 	return nil
@@ -194,24 +224,18 @@ func (p *EnvironConfigReader) GetConfigValues() map[string]ConfigValue {
 	return p.configValues
 }
 
-func (p *EnvironConfigReader) keyvalue(line []byte) error {
-	pair := string(line)
-	if idx := strings.Index(pair, "="); idx >= 0 {
-		key, value := strings.TrimSpace(pair[:idx]), translate(strings.TrimSpace(pair[idx+1:]))
-		if err := os.Setenv(key, value); err != nil {
-			return err
-		}
-		configValue := ConfigValue{
-			Name: key,
-			Value: value,
-		}
-		if strings.HasPrefix(key, p.prefix) {
-			key = strings.TrimPrefix(key, p.prefix)
-		}
-		p.configValues[key] = configValue
-	} else if pair != "" {
-		return ParseError{pair}
+func (p *EnvironConfigReader) keyvalue(key, value string) error {
+	if err := os.Setenv(key, value); err != nil {
+		return err
+	}
+	configValue := ConfigValue{
+		Name: key,
+		Value: value,
+	}
+	if strings.HasPrefix(key, p.prefix) {
+		key = strings.TrimPrefix(key, p.prefix)
 	}
+	p.configValues[key] = configValue
 	return nil
 }
 
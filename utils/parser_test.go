@@ -17,6 +17,7 @@ package utils
 
 import (
 	"bytes"
+	"io/ioutil"
 	"os"
 	"reflect"
 	"testing"
@@ -128,3 +129,22 @@ func verifyConfigValue(t *testing.T, key string, parsedValues map[string]ConfigV
 		t.Errorf("parsedValues[%s] incorrect; Expected %v; Got %v", key, expected, actual)
 	}
 }
+
+func TestReadEnvironFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "environ")
+	if err != nil {
+		t.Fatalf("Could not create temp file: %s", err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("# comment\nSERVICEDTEST_READ_A=apple # trailing\n\nSERVICEDTEST_READ_B = orange\n")
+	file.Close()
+
+	values, err := ReadEnvironFile(file.Name())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	verify(t, "values", values, map[string]string{"SERVICEDTEST_READ_A": "apple", "SERVICEDTEST_READ_B": "orange"})
+	if val, ok := os.LookupEnv("SERVICEDTEST_READ_A"); ok {
+		t.Errorf("Expected SERVICEDTEST_READ_A not to be set in the environment, got %s", val)
+	}
+}
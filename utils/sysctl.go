@@ -17,8 +17,6 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
-
-	"github.com/zenoss/glog"
 )
 
 // SetSysctl tries to set sysctl settings
@@ -27,13 +25,13 @@ func SetSysctl(key string, value string) ([]byte, error) {
 	thecmd := exec.Command(command[0], command[1:]...)
 	output, err := thecmd.CombinedOutput()
 	if err != nil {
-		glog.Errorf("Error running command:'%s' output: %s  error: %s\n", command, output, err)
+		plog.Errorf("Error running command:'%s' output: %s  error: %s\n", command, output, err)
 		return output, err
 	}
 	if strings.HasPrefix(string(output), "sysctl: ") {
-		glog.Errorf("Error running command:'%s' output: %s\n", command, output)
+		plog.Errorf("Error running command:'%s' output: %s\n", command, output)
 		return output, fmt.Errorf(string(output))
 	}
-	glog.V(1).Infof("Successfully ran command:'%s' output: %s\n", command, output)
+	plog.Debugf("Successfully ran command:'%s' output: %s\n", command, output)
 	return output, nil
 }
\ No newline at end of file
@@ -17,8 +17,6 @@ import (
 	"crypto/tls"
 	"fmt"
 	"strings"
-
-	"github.com/zenoss/glog"
 )
 
 //---------------------------------------------------------------------------
@@ -140,7 +138,7 @@ func init() {
 func GetDefaultCiphers(connectionType string) []string {
 	configInfo, ok := configMap[connectionType]
 	if !ok {
-		glog.Fatalf("connectionType %s is undefined", connectionType)
+		plog.Fatalf("connectionType %s is undefined", connectionType)
 	}
 	return configInfo.defaultCiphers
 }
@@ -149,7 +147,7 @@ func GetDefaultCiphers(connectionType string) []string {
 func SetCiphers(connectionType string, cipherNames []string) error {
 	configInfo, ok := configMap[connectionType]
 	if !ok {
-		glog.Fatalf("connectionType %s is undefined", connectionType)
+		plog.Fatalf("connectionType %s is undefined", connectionType)
 	}
 
 	newCiphers := make([]uint16, 0, len(cipherNames))
@@ -172,7 +170,7 @@ func SetCiphers(connectionType string, cipherNames []string) error {
 func SetMinTLS(connectionType string, version string) error {
 	configInfo, ok := configMap[connectionType]
 	if !ok {
-		glog.Fatalf("connectionType %s is undefined", connectionType)
+		plog.Fatalf("connectionType %s is undefined", connectionType)
 	}
 
 	tlsVersion, err := tlsVersionStringToUint(version)
@@ -202,7 +200,7 @@ func tlsVersionStringToUint(version string) (uint16, error) {
 func MinTLS(connectionType string) uint16 {
 	configInfo, ok := configMap[connectionType]
 	if !ok {
-		glog.Fatalf("connectionType %s is undefined", connectionType)
+		plog.Fatalf("connectionType %s is undefined", connectionType)
 	}
 	return configInfo.minTLSVersion
 }
@@ -211,7 +209,7 @@ func MinTLS(connectionType string) uint16 {
 func CipherSuites(connectionType string) []uint16 {
 	configInfo, ok := configMap[connectionType]
 	if !ok {
-		glog.Fatalf("connectionType %s is undefined", connectionType)
+		plog.Fatalf("connectionType %s is undefined", connectionType)
 	}
 	return configInfo.cipherSuite
 }
@@ -15,8 +15,6 @@ package utils
 
 import (
 	"time"
-
-	"github.com/zenoss/glog"
 )
 
 // TTL manages time-to-live data
@@ -30,11 +28,11 @@ func RunTTL(ttl TTL, cancel <-chan interface{}, min, max time.Duration) {
 	for {
 		wait, err := ttl.Purge(max)
 		if err != nil {
-			glog.Warningf("Could not purge: %s", err)
+			plog.Warningf("Could not purge: %s", err)
 			wait = min
 		}
 
-		glog.V(1).Info("Next purge in %s", wait)
+		plog.Debugf("Next purge in %s", wait)
 		select {
 		case <-time.After(wait):
 		case <-cancel:
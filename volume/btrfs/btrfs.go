@@ -31,7 +31,6 @@ import (
 
 	"github.com/control-center/serviced/volume"
 	"github.com/dustin/go-humanize"
-	"github.com/zenoss/glog"
 )
 
 var (
@@ -81,7 +80,7 @@ func Init(root string, _ []string) (volume.Driver, error) {
 	objectID := "5" // root driver is always 5 unless it is a subvolume
 	raw, err := volume.RunBtrFSCmd(volume.IsSudoer(), "subvolume", "show", root)
 	if err != nil {
-		glog.Errorf("Could not initialize btrfs driver for %s: %s (%s)", root, raw, err)
+		plog.Errorf("Could not initialize btrfs driver for %s: %s (%s)", root, raw, err)
 		return nil, volume.ErrBtrfsCommand
 	}
 	if obidmatch := regexp.MustCompile("Object ID:\\s+(\\w+)").FindSubmatch(raw); len(obidmatch) == 2 {
@@ -140,14 +139,14 @@ func (d *BtrfsDriver) Create(volumeName string) (volume.Volume, error) {
 	rootDir := d.root
 	if _, err := volume.RunBtrFSCmd(d.sudoer, "subvolume", "list", rootDir); err != nil {
 		if _, err := volume.RunBtrFSCmd(d.sudoer, "subvolume", "create", rootDir); err != nil {
-			glog.Errorf("Could not create subvolume at: %s", rootDir)
+			plog.Errorf("Could not create subvolume at: %s", rootDir)
 			return nil, ErrBtrfsCreatingSubvolume
 		}
 	}
 	vdir := path.Join(rootDir, volumeName)
 	if _, err := volume.RunBtrFSCmd(d.sudoer, "subvolume", "list", vdir); err != nil {
 		if _, err = volume.RunBtrFSCmd(d.sudoer, "subvolume", "create", vdir); err != nil {
-			glog.Errorf("Could not create volume at: %s", vdir)
+			plog.Errorf("Could not create volume at: %s", vdir)
 			return nil, ErrBtrfsCreatingSubvolume
 		}
 	}
@@ -168,7 +167,7 @@ func (d *BtrfsDriver) Remove(volumeName string) error {
 	d.Lock()
 	defer d.Unlock()
 	if !d.Exists(volumeName) {
-		glog.Warningf("Volume %s does not exist", volumeName)
+		plog.Warningf("Volume %s does not exist", volumeName)
 		return nil
 	}
 	v, err := d.Get(volumeName)
@@ -185,31 +184,31 @@ func (d *BtrfsDriver) Remove(volumeName string) error {
 		}
 	}
 	if output, err := volume.RunBtrFSCmd(d.sudoer, "subvolume", "delete", v.Path()); err != nil {
-		glog.Errorf("Could not remove volume %s: %s (%s)", v.Name(), output, err)
+		plog.Errorf("Could not remove volume %s: %s (%s)", v.Name(), output, err)
 		return volume.ErrRemovingVolume
 	}
 	return nil
 }
 
 func (d *BtrfsDriver) Status() (volume.Status, error) {
-	glog.V(2).Info("btrfs.Status()")
+	plog.Debug("btrfs.Status()")
 	rootDir := d.root
 	dfstatus, err := volume.RunBtrFSCmd(d.sudoer, "filesystem", "df", "-b", rootDir)
 	if err != nil {
-		glog.V(2).Infof("Error running df command with -b: %v. Trying without for older btrfs.", err)
+		plog.Debugf("Error running df command with -b: %v. Trying without for older btrfs.", err)
 		dfstatus, err = volume.RunBtrFSCmd(d.sudoer, "filesystem", "df", rootDir)
 		if err != nil {
-			glog.Errorf("Could not get status of filestystem at %s", rootDir)
+			plog.Errorf("Could not get status of filestystem at %s", rootDir)
 			return nil, err
 		}
 	}
-	glog.V(2).Infof("Output from btrfs filesystem df %s: %s", rootDir, dfstatus)
+	plog.Debugf("Output from btrfs filesystem df %s: %s", rootDir, dfstatus)
 	dfData, err := parseDF(strings.Split(string(dfstatus), "\n"))
 	if err != nil {
-		glog.Errorf("Could not parse df output: %s", err)
+		plog.Errorf("Could not parse df output: %s", err)
 		return nil, err
 	}
-	glog.V(2).Infof("dfData = %v", dfData)
+	plog.Debugf("dfData = %v", dfData)
 
 	usage := dfDataToUsageData(dfData)
 	response := &volume.SimpleStatus{
@@ -253,10 +252,10 @@ func (d *BtrfsDriver) Get(volumeName string) (volume.Volume, error) {
 
 // List implements volume.Driver.List
 func (d *BtrfsDriver) List() (result []string) {
-	glog.Infof("Checking volumes at %s", d.root)
+	plog.Infof("Checking volumes at %s", d.root)
 	raw, err := volume.RunBtrFSCmd(d.sudoer, "subvolume", "list", d.root)
 	if err != nil {
-		glog.Warningf("Could not list subvolumes at %s: %s (%s)", d.root, raw, err)
+		plog.Warningf("Could not list subvolumes at %s: %s (%s)", d.root, raw, err)
 		return
 	}
 	rows := regexp.MustCompile(fmt.Sprintf("top level %s path (\\w+)", d.objectID)).FindAllSubmatch(raw, -1)
@@ -291,7 +290,7 @@ func (v *BtrfsVolume) Tenant() string {
 func (v *BtrfsVolume) WriteMetadata(label, name string) (io.WriteCloser, error) {
 	filePath := filepath.Join(v.Path(), name)
 	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil && !os.IsExist(err) {
-		glog.Errorf("Could not create path for file %s: %s", name, err)
+		plog.Errorf("Could not create path for file %s: %s", name, err)
 		return nil, err
 	}
 
@@ -353,13 +352,13 @@ func (v *BtrfsVolume) isInvalidSnapshot(rawLabel string) bool {
 func (v *BtrfsVolume) writeSnapshotInfo(label string, info *volume.SnapshotInfo) error {
 	writer, err := v.WriteMetadata(label, ".SNAPSHOTINFO")
 	if err != nil {
-		glog.Errorf("Could not write meta info for snapshot %s: %s", label, err)
+		plog.Errorf("Could not write meta info for snapshot %s: %s", label, err)
 		return err
 	}
 	defer writer.Close()
 	encoder := json.NewEncoder(writer)
 	if err := encoder.Encode(info); err != nil {
-		glog.Errorf("Could not export meta info for snapshot %s: %s", label, err)
+		plog.Errorf("Could not export meta info for snapshot %s: %s", label, err)
 		return err
 	}
 	return nil
@@ -373,14 +372,14 @@ func (v *BtrfsVolume) SnapshotInfo(label string) (*volume.SnapshotInfo, error) {
 
 	reader, err := v.ReadMetadata(label, ".SNAPSHOTINFO")
 	if err != nil {
-		glog.Errorf("Could not get info for snapshot %s: %s", label, err)
+		plog.Errorf("Could not get info for snapshot %s: %s", label, err)
 		return nil, err
 	}
 	defer reader.Close()
 	decoder := json.NewDecoder(reader)
 	var info volume.SnapshotInfo
 	if err := decoder.Decode(&info); err != nil {
-		glog.Errorf("Could not decode snapshot info for %s: %s", label, err)
+		plog.Errorf("Could not decode snapshot info for %s: %s", label, err)
 		return nil, err
 	}
 	return &info, err
@@ -399,10 +398,10 @@ func (v *BtrfsVolume) Snapshot(label, message string, tags []string) error {
 	for _, tagName := range tags {
 		if tagInfo, err := v.GetSnapshotWithTag(tagName); err != volume.ErrSnapshotDoesNotExist {
 			if err != nil {
-				glog.Errorf("Could not look up snapshot with tag %s: %s", tagName, err)
+				plog.Errorf("Could not look up snapshot with tag %s: %s", tagName, err)
 				return err
 			} else {
-				glog.Errorf("Tag '%s' is already in use by snapshot %s", tagName, tagInfo.Name)
+				plog.Errorf("Tag '%s' is already in use by snapshot %s", tagName, tagInfo.Name)
 				return volume.ErrTagAlreadyExists
 			}
 		}
@@ -441,14 +440,14 @@ func (v *BtrfsVolume) GetSnapshotWithTag(tagName string) (*volume.SnapshotInfo,
 	// Get all the snapshots on the volume
 	snapshotLabels, err := v.Snapshots()
 	if err != nil {
-		glog.Errorf("Could not get current snapshot list: %s", err)
+		plog.Errorf("Could not get current snapshot list: %s", err)
 		return nil, err
 	}
 	// Get info for each snapshot and return if a matching tag is found
 	for _, snapshotLabel := range snapshotLabels {
 		if info, err := v.SnapshotInfo(snapshotLabel); err != volume.ErrInvalidSnapshot {
 			if err != nil {
-				glog.Errorf("Could not get info for snapshot %s: %s", snapshotLabel, err)
+				plog.Errorf("Could not get info for snapshot %s: %s", snapshotLabel, err)
 				return nil, err
 			}
 			for _, tag := range info.Tags {
@@ -466,16 +465,16 @@ func (v *BtrfsVolume) Snapshots() ([]string, error) {
 	v.Lock()
 	defer v.Unlock()
 
-	glog.V(2).Infof("listing snapshots of volume:%v and v.name:%s ", v.path, v.name)
+	plog.Debugf("listing snapshots of volume:%v and v.name:%s ", v.path, v.name)
 	output, err := volume.RunBtrFSCmd(v.sudoer, "subvolume", "list", "-s", v.path)
 	if err != nil {
-		glog.Errorf("Could not list subvolumes of %s: %s", v.path, err)
+		plog.Errorf("Could not list subvolumes of %s: %s", v.path, err)
 		return nil, err
 	}
 
 	var files []os.FileInfo
 	for _, line := range strings.Split(string(output), "\n") {
-		glog.V(0).Infof("line: %s", line)
+		plog.Infof("line: %s", line)
 		if parts := strings.Split(line, "path"); len(parts) == 2 {
 			rawLabel := strings.TrimSpace(parts[1])
 			rawLabel = strings.TrimPrefix(rawLabel, "volumes/")
@@ -483,11 +482,11 @@ func (v *BtrfsVolume) Snapshots() ([]string, error) {
 				label := v.prettySnapshotLabel(rawLabel)
 				file, err := os.Stat(filepath.Join(v.Driver().Root(), rawLabel))
 				if err != nil {
-					glog.Errorf("Could not stat snapshot %s: %s", label, err)
+					plog.Errorf("Could not stat snapshot %s: %s", label, err)
 					return nil, err
 				}
 				files = append(files, file)
-				glog.V(2).Infof("found snapshot:%s", label)
+				plog.Debugf("found snapshot:%s", label)
 			}
 		}
 	}
@@ -509,7 +508,7 @@ func (v *BtrfsVolume) RemoveSnapshot(label string) error {
 	defer v.Unlock()
 	_, err := volume.RunBtrFSCmd(v.sudoer, "subvolume", "delete", v.snapshotPath(label))
 	if err != nil {
-		glog.Errorf("could not remove snapshot: %s", err)
+		plog.Errorf("could not remove snapshot: %s", err)
 		return volume.ErrRemovingSnapshot
 	}
 	return nil
@@ -522,10 +521,10 @@ func getEnvMinDuration(envvar string, def, min int32) time.Duration {
 	if len(strings.TrimSpace(envval)) == 0 {
 		// ignore unset envvar
 	} else if intVal, intErr := strconv.ParseInt(envval, 0, 32); intErr != nil {
-		glog.Warningf("ignoring invalid %s of '%s': %s", envvar, envval, intErr)
+		plog.Warningf("ignoring invalid %s of '%s': %s", envvar, envval, intErr)
 		duration = min
 	} else if int32(intVal) < min {
-		glog.Warningf("ignoring invalid %s of '%s' < minimum:%v seconds", envvar, envval, min)
+		plog.Warningf("ignoring invalid %s of '%s' < minimum:%v seconds", envvar, envval, min)
 	} else {
 		duration = int32(intVal)
 	}
@@ -555,12 +554,12 @@ func (v *BtrfsVolume) Rollback(label string) error {
 		return err
 	}
 
-	glog.Infof("starting rollback of snapshot %s", label)
+	plog.Infof("starting rollback of snapshot %s", label)
 
 	start := time.Now()
 	if dirp {
 		timeout := getEnvMinDuration("SERVICED_BTRFS_ROLLBACK_TIMEOUT", 300, 120)
-		glog.Infof("rollback using env var SERVICED_BTRFS_ROLLBACK_TIMEOUT:%s", timeout)
+		plog.Infof("rollback using env var SERVICED_BTRFS_ROLLBACK_TIMEOUT:%s", timeout)
 
 		for {
 			cmd := []string{"subvolume", "delete", vd}
@@ -571,11 +570,11 @@ func (v *BtrfsVolume) Rollback(label string) error {
 
 			now := time.Now()
 			if now.Sub(start) > timeout {
-				glog.Errorf("rollback of snapshot %s failed - btrfs subvolume deletes took %s for cmd:%s", label, timeout, cmd)
+				plog.Errorf("rollback of snapshot %s failed - btrfs subvolume deletes took %s for cmd:%s", label, timeout, cmd)
 				return deleteError
 			} else if strings.Contains(string(output), "Device or resource busy") {
 				waitTime := time.Duration(5 * time.Second)
-				glog.Warningf("retrying rollback subvolume delete in %s - unable to run cmd:%s  output:%s  error:%s", waitTime, cmd, string(output), deleteError)
+				plog.Warningf("retrying rollback subvolume delete in %s - unable to run cmd:%s  output:%s  error:%s", waitTime, cmd, string(output), deleteError)
 				time.Sleep(waitTime)
 			} else {
 				return deleteError
@@ -586,10 +585,10 @@ func (v *BtrfsVolume) Rollback(label string) error {
 	cmd := []string{"subvolume", "snapshot", v.snapshotPath(label), vd}
 	_, err = volume.RunBtrFSCmd(v.sudoer, cmd...)
 	if err != nil {
-		glog.Errorf("rollback of snapshot %s failed for cmd:%s", label, cmd)
+		plog.Errorf("rollback of snapshot %s failed for cmd:%s", label, cmd)
 	} else {
 		duration := time.Now().Sub(start)
-		glog.Infof("rollback of snapshot %s took %s", label, duration)
+		plog.Infof("rollback of snapshot %s took %s", label, duration)
 	}
 	return err
 }
@@ -597,10 +596,10 @@ func (v *BtrfsVolume) Rollback(label string) error {
 // Export implements volume.Volume.Export
 func (v *BtrfsVolume) Export(label, parent string, writer io.Writer, excludes []string) error {
 	if len(excludes) > 0 {
-		glog.Warning("btrfs backups do not support excluding directories")
+		plog.Warning("btrfs backups do not support excluding directories")
 	}
 	if label = strings.TrimSpace(label); label == "" {
-		glog.Errorf("%s: label cannot be empty", volume.DriverTypeBtrFS)
+		plog.Errorf("%s: label cannot be empty", volume.DriverTypeBtrFS)
 		return ErrBtrfsInvalidLabel
 	} else if exists, err := v.snapshotExists(label); err != nil {
 		return err
@@ -609,7 +608,7 @@ func (v *BtrfsVolume) Export(label, parent string, writer io.Writer, excludes []
 	}
 	// TODO: add to tarfile and include metadata
 	if err := runBtrfsSend(writer, v.sudoer, parent, v.snapshotPath(label)); err != nil {
-		glog.Errorf("Could not export snapshot %s: %s", label, err)
+		plog.Errorf("Could not export snapshot %s: %s", label, err)
 		return err
 	}
 	return nil
@@ -624,12 +623,12 @@ func (v *BtrfsVolume) Import(label string, reader io.Reader) error {
 	}
 	importdir := filepath.Join(v.path, fmt.Sprintf("import-%s", label))
 	if _, err := volume.RunBtrFSCmd(v.sudoer, "subvolume", "create", importdir); err != nil {
-		glog.Errorf("Could not create import path for snapshot %s: %s", label, err)
+		plog.Errorf("Could not create import path for snapshot %s: %s", label, err)
 		return err
 	}
 	defer volume.RunBtrFSCmd(v.sudoer, "subvolume", "delete", importdir)
 	if err := runBtrfsRecv(reader, v.sudoer, importdir); err != nil {
-		glog.Errorf("Could not import snapshot %s: %s", label, err)
+		plog.Errorf("Could not import snapshot %s: %s", label, err)
 		return err
 	}
 	defer volume.RunBtrFSCmd(v.sudoer, "subvolume", "delete", filepath.Join(importdir, label))
@@ -644,7 +643,7 @@ func (v *BtrfsVolume) snapshotExists(label string) (exists bool, err error) {
 	rlabel := v.rawSnapshotLabel(label)
 	plabel := v.prettySnapshotLabel(label)
 	if snapshots, err := v.Snapshots(); err != nil {
-		glog.Errorf("Could not get current snapshot list: %v", err)
+		plog.Errorf("Could not get current snapshot list: %v", err)
 		return false, ErrBtrfsListingSnapshots
 	} else {
 		for _, snapLabel := range snapshots {
@@ -669,7 +668,7 @@ func runBtrfsSend(writer io.Writer, sudoer bool, parentpath, path string) error
 	cmd.Stdout = writer
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
-		glog.Errorf("Error while running command %+v: %s", cmdArgs, err)
+		plog.Errorf("Error while running command %+v: %s", cmdArgs, err)
 		return volume.ErrBtrfsCommand
 	}
 	return nil
@@ -686,7 +685,7 @@ func runBtrfsRecv(reader io.Reader, sudoer bool, path string) error {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
-		glog.Errorf("Error while running command %+v: %s", cmdArgs, err)
+		plog.Errorf("Error while running command %+v: %s", cmdArgs, err)
 		return volume.ErrBtrfsCommand
 	}
 	return nil
@@ -719,13 +718,13 @@ func parseDF(lines []string) ([]BtrfsDFData, error) {
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		fields := strings.FieldsFunc(line, func(c rune) bool { return unicode.IsSpace(c) || strings.ContainsRune(",:", c) })
-		glog.V(2).Infof("Fields from line %v: %v", line, fields)
+		plog.Debugf("Fields from line %v: %v", line, fields)
 		if len(fields) == 0 {
-			glog.Info("Skipping blank line in input.")
+			plog.Info("Skipping blank line in input.")
 			continue
 		}
 		if len(fields) != 4 {
-			glog.Errorf("Wrong number of fields (%d, expected 4) in line %q", len(fields), line)
+			plog.Errorf("Wrong number of fields (%d, expected 4) in line %q", len(fields), line)
 			return []BtrfsDFData{}, fmt.Errorf("Wrong number of fields (%d, expected 4) in line %q", len(fields), line)
 		}
 		switch fields[0] {
@@ -735,28 +734,28 @@ func parseDF(lines []string) ([]BtrfsDFData, error) {
 			if strings.HasPrefix(total, "total=") {
 				total = strings.TrimPrefix(total, "total=")
 				if totalBytes, err = parseSize(total); err != nil {
-					glog.Errorf("parseSize(%s) returned error: %s", total, err)
+					plog.Errorf("parseSize(%s) returned error: %s", total, err)
 					return []BtrfsDFData{}, err
 				}
 			} else {
-				glog.Errorf("total field not found in line %q", line)
+				plog.Errorf("total field not found in line %q", line)
 				return []BtrfsDFData{}, fmt.Errorf("expected total field, not found in line %q", line)
 			}
 			used := fields[3]
 			if strings.HasPrefix(used, "used=") {
 				used = strings.TrimPrefix(used, "used=")
 				if usedBytes, err = parseSize(used); err != nil {
-					glog.Errorf("parseSize(%s) returned error: %s", used, err)
+					plog.Errorf("parseSize(%s) returned error: %s", used, err)
 					return []BtrfsDFData{}, err
 				}
 			} else {
-				glog.Errorf("used field not found in line %q", line)
+				plog.Errorf("used field not found in line %q", line)
 				return []BtrfsDFData{}, fmt.Errorf("expected used field, not found in line %q", line)
 			}
 
 			df = append(df, BtrfsDFData{DataType: fields[0], Level: fields[1], Total: totalBytes, Used: usedBytes})
 		default:
-			glog.Errorf("Unrecognized field %q in line %q", fields[0], line)
+			plog.Errorf("Unrecognized field %q in line %q", fields[0], line)
 			return []BtrfsDFData{}, fmt.Errorf("Unrecognized field %q in line %q", fields[0], line)
 		}
 	}
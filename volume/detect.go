@@ -17,16 +17,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-
-	"github.com/zenoss/glog"
 )
 
 func DetectDriverType(root string) (DriverType, error) {
 	// Check to see if the directory even exists. If not, no driver has been initialized.
-	glog.V(2).Infof("Detecting driver type under %s", root)
+	plog.Debugf("Detecting driver type under %s", root)
 	if _, err := os.Stat(root); err != nil {
 		if os.IsNotExist(err) {
-			glog.V(2).Infof("Root does not exist; no driver has been initialized")
+			plog.Debugf("Root does not exist; no driver has been initialized")
 			return "", ErrDriverNotInit
 		}
 		return "", err
@@ -35,7 +33,7 @@ func DetectDriverType(root string) (DriverType, error) {
 		dirname := filepath.Join(root, fmt.Sprintf(".%s", drivertype))
 		flagfile := FlagFilePath(dirname)
 		if fi, err := os.Stat(flagfile); !os.IsNotExist(err) && fi != nil {
-			glog.V(2).Infof("Found %s file; returning %s", dirname, drivertype)
+			plog.Debugf("Found %s file; returning %s", dirname, drivertype)
 			return drivertype, nil
 		}
 	}
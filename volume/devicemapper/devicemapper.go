@@ -13,6 +13,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
@@ -24,7 +25,6 @@ import (
 	"github.com/control-center/serviced/volume/devicemapper/devmapper"
 	"github.com/docker/docker/pkg/devicemapper"
 	"github.com/docker/go-units"
-	"github.com/zenoss/glog"
 )
 
 var (
@@ -92,18 +92,18 @@ func (d *DeviceMapperDriver) cleanUpSnapshots() {
 		return
 	}
 
-	glog.V(2).Infof("Snapshots on disk: %v", snapshotsOnDisk)
+	plog.Debugf("Snapshots on disk: %v", snapshotsOnDisk)
 
 	snapshotsInMetadata, err := d.getSnapshotsFromMetadata()
 	if err != nil || snapshotsInMetadata == nil {
 		return
 	}
 
-	glog.V(2).Infof("Snapshots in metadata: %v", snapshotsInMetadata)
+	plog.Debugf("Snapshots in metadata: %v", snapshotsInMetadata)
 
 	for _, snapshotOnDisk := range snapshotsOnDisk {
 		if _, ok := snapshotsInMetadata[snapshotOnDisk]; !ok {
-			glog.V(2).Infof("Removing Snapshot: %v", snapshotOnDisk)
+			plog.Debugf("Removing Snapshot: %v", snapshotOnDisk)
 			os.RemoveAll(filepath.Join(d.MetadataDir(), snapshotOnDisk))
 		} else {
 			// Remove the snapshot on disk since it was found in the metadata map.  This
@@ -114,7 +114,7 @@ func (d *DeviceMapperDriver) cleanUpSnapshots() {
 	}
 
 	for snapshotInMetadata, volumeName := range snapshotsInMetadata {
-		glog.V(2).Infof("Removing Snapshot from metadata: %v", snapshotInMetadata)
+		plog.Debugf("Removing Snapshot from metadata: %v", snapshotInMetadata)
 		v, err := d.getVolume(volumeName, false)
 		if err != nil {
 			continue
@@ -125,25 +125,25 @@ func (d *DeviceMapperDriver) cleanUpSnapshots() {
 
 		deviceHash, err := v.Metadata.LookupSnapshotDevice(snapshotInMetadata)
 		if err != nil {
-			glog.Errorf("Error removing snapshot: %v", err)
+			plog.Errorf("Error removing snapshot: %v", err)
 			continue
 		}
 
 		// Remove the snapshot info from the volume metadata
 		if err := v.Metadata.RemoveSnapshot(snapshotInMetadata); err != nil {
-			glog.Errorf("Error removing snapshot: %v", err)
+			plog.Errorf("Error removing snapshot: %v", err)
 			continue
 		}
 
-		glog.V(2).Infof("Deactivating snapshot device %s", deviceHash)
+		plog.Debugf("Deactivating snapshot device %s", deviceHash)
 		v.driver.DeviceSet.Lock()
 		if err := v.driver.deactivateDevice(deviceHash); err != nil {
-			glog.Errorf("Error deactivating device (%s): %s", deviceHash, err)
+			plog.Errorf("Error deactivating device (%s): %s", deviceHash, err)
 			continue
 		}
 		v.driver.DeviceSet.Unlock()
 		if err := v.driver.deleteDevice(deviceHash, false); err != nil {
-			glog.Errorf("Error removing snapshot: %v", err)
+			plog.Errorf("Error removing snapshot: %v", err)
 			continue
 		}
 	}
@@ -222,8 +222,8 @@ func (d *DeviceMapperDriver) ListTenants() (result []string) {
 
 // Create implements volume.Driver.Create
 func (d *DeviceMapperDriver) Create(volumeName string) (volume.Volume, error) {
-	glog.V(2).Infof("Create() (%s) START", volumeName)
-	defer glog.V(2).Infof("Create() (%s) END", volumeName)
+	plog.Debugf("Create() (%s) START", volumeName)
+	defer plog.Debugf("Create() (%s) END", volumeName)
 
 	// Do not create if the device already exists
 	if d.Exists(volumeName) {
@@ -235,21 +235,21 @@ func (d *DeviceMapperDriver) Create(volumeName string) (volume.Volume, error) {
 	if err != nil {
 		return nil, err
 	}
-	glog.V(1).Infof("Allocated new device %s", deviceHash)
+	plog.Debugf("Allocated new device %s", deviceHash)
 
 	// Create the mount target directory if it doesn't exist
 	mountpoint := d.volumeDir(volumeName)
 	if err := os.MkdirAll(mountpoint, 0755); err != nil && !os.IsExist(err) {
 		return nil, err
 	}
-	glog.V(1).Infof("Ensured existence of mount point %s", mountpoint)
+	plog.Debugf("Ensured existence of mount point %s", mountpoint)
 
 	// Create the metadata directory if it doesn't exist.
 	md := d.MetadataDir()
 	if err := os.MkdirAll(md, 0755); err != nil && !os.IsExist(err) {
 		return nil, err
 	}
-	glog.V(1).Infof("Ensured existence of metadata dir %s", md)
+	plog.Debugf("Ensured existence of metadata dir %s", md)
 
 	// Instantiate the volume
 	vol, err := d.getVolume(volumeName, true)
@@ -261,13 +261,13 @@ func (d *DeviceMapperDriver) Create(volumeName string) (volume.Volume, error) {
 	if err := vol.Metadata.SetCurrentDevice(deviceHash); err != nil {
 		return nil, err
 	}
-	glog.V(1).Infof("Set current device to %s", deviceHash)
+	plog.Debugf("Set current device to %s", deviceHash)
 
 	// Mount the device
 	if err := d.DeviceSet.MountDevice(deviceHash, mountpoint, volumeName); err != nil {
 		return nil, err
 	}
-	glog.V(1).Infof("Mounted device %s to %s", deviceHash, mountpoint)
+	plog.Debugf("Mounted device %s to %s", deviceHash, mountpoint)
 
 	return vol, nil
 }
@@ -283,7 +283,7 @@ func (d *DeviceMapperDriver) addDevice(headDeviceHash string) (string, error) {
 
 	// Create the device.
 	if err := d.DeviceSet.AddDevice(deviceHash, headDeviceHash); err != nil {
-		glog.Errorf("Unable to create device %s: %s", deviceHash, err)
+		plog.Errorf("Unable to create device %s: %s", deviceHash, err)
 		return "", err
 	}
 
@@ -296,15 +296,15 @@ func (d *DeviceMapperDriver) addDevice(headDeviceHash string) (string, error) {
 		// configured dm.basesize.
 		size, err := d.deviceSize(deviceHash)
 		if err != nil {
-			glog.Errorf("Could not read the size of device %s: %s", deviceHash, err)
+			plog.Errorf("Could not read the size of device %s: %s", deviceHash, err)
 			return "", err
 		}
 		if baseSize := d.baseSize(); baseSize > size {
 
 			// Make the device to match dm.basesize
-			glog.V(2).Infof("Device size is smaller than dm.basesize; expanding")
+			plog.Debugf("Device size is smaller than dm.basesize; expanding")
 			if err := d.resize(deviceHash, baseSize); err != nil {
-				glog.Errorf("Could not update the size of the device %s: %s", deviceHash, err)
+				plog.Errorf("Could not update the size of the device %s: %s", deviceHash, err)
 				return "", err
 			}
 		}
@@ -386,7 +386,7 @@ func (d *DeviceMapperDriver) Resize(volumeName string, size uint64) error {
 	}
 	newSize := volume.FilesystemBytesSize(vol.Path())
 	human := units.BytesSize(float64(newSize))
-	glog.Infof("Resized filesystem. New size: %s", human)
+	plog.Infof("Resized filesystem. New size: %s", human)
 	return nil
 }
 
@@ -395,7 +395,7 @@ func (d *DeviceMapperDriver) resize(deviceHash string, size uint64) error {
 	// Get the current size of the device
 	curSize, err := d.deviceSize(deviceHash)
 	if err != nil {
-		glog.Errorf("Could not get the current size of the device %s: %s", deviceHash, err)
+		plog.Errorf("Could not get the current size of the device %s: %s", deviceHash, err)
 		return err
 	}
 
@@ -415,11 +415,11 @@ func (d *DeviceMapperDriver) resize(deviceHash string, size uint64) error {
 	// Create the new table description using the sectors computed
 	oldTable := fmt.Sprintf("%d %d %s %s", start, oldSectors, targetType, params)
 	newTable := fmt.Sprintf("%d %d %s %s", start, newSectors, targetType, params)
-	glog.V(2).Infof("Replacing old table (%s) with new table (%s)", oldTable, newTable)
+	plog.Debugf("Replacing old table (%s) with new table (%s)", oldTable, newTable)
 
 	// Update the device info
 	if err := d.resizeDevice(deviceHash, size); err != nil {
-		glog.Errorf("Could not reset size of device %s: %s", deviceName, err)
+		plog.Errorf("Could not reset size of device %s: %s", deviceName, err)
 		return err
 	}
 	if err := func() (err error) {
@@ -429,20 +429,20 @@ func (d *DeviceMapperDriver) resize(deviceHash string, size uint64) error {
 		dmsetupLoad := exec.Command("dmsetup", "load", deviceName)
 		dmsetupLoad.Stdin = strings.NewReader(newTable)
 		if output, err := dmsetupLoad.CombinedOutput(); err != nil {
-			glog.Errorf("Unable to load new table (%s)", string(output))
+			plog.Errorf("Unable to load new table (%s)", string(output))
 			return err
 		}
-		glog.V(2).Infof("Inactive table slot updated with new size")
+		plog.Debugf("Inactive table slot updated with new size")
 
 		// "Resume" the device to load the inactive table into the active slot
 		if err := devicemapper.ResumeDevice(deviceName); err != nil {
 			return err
 		}
-		glog.V(2).Infof("Loaded inactive table into the active slot")
+		plog.Debugf("Loaded inactive table into the active slot")
 
 		// Resize the filesystem to use the new space
 		if err := resize2fs(d.devicePath(deviceHash)); err != nil {
-			glog.Errorf("Unable to resize filesystem: %s", err)
+			plog.Errorf("Unable to resize filesystem: %s", err)
 			return err
 		}
 		return nil
@@ -455,12 +455,12 @@ func (d *DeviceMapperDriver) resize(deviceHash string, size uint64) error {
 
 // Get implements volume.Driver.Get
 func (d *DeviceMapperDriver) Get(volumeName string) (volume.Volume, error) {
-	glog.V(2).Infof("Get() (%s) START", volumeName)
-	defer glog.V(2).Infof("Get() (%s) END", volumeName)
-	glog.V(2).Infof("Getting devicemapper volume %s", volumeName)
+	plog.Debugf("Get() (%s) START", volumeName)
+	defer plog.Debugf("Get() (%s) END", volumeName)
+	plog.Debugf("Getting devicemapper volume %s", volumeName)
 	vol, err := d.getVolume(volumeName, false)
 	if err != nil {
-		glog.Errorf("Error getting devicemapper volume: %s", err)
+		plog.Errorf("Error getting devicemapper volume: %s", err)
 		return nil, err
 	}
 	if mounted, _ := devmapper.Mounted(vol.Path()); !mounted {
@@ -469,12 +469,12 @@ func (d *DeviceMapperDriver) Get(volumeName string) (volume.Volume, error) {
 		label := vol.Tenant()
 
 		if err := d.DeviceSet.MountDevice(deviceHash, mountpoint, label); err != nil {
-			glog.Errorf("Error mounting device %q on %q for volume %q: %s", deviceHash, mountpoint, volumeName, err)
+			plog.Errorf("Error mounting device %q on %q for volume %q: %s", deviceHash, mountpoint, volumeName, err)
 			return nil, err
 		}
-		glog.V(2).Infof("Mounted device %s to %s", deviceHash, mountpoint)
+		plog.Debugf("Mounted device %s to %s", deviceHash, mountpoint)
 	} else {
-		glog.V(2).Infof("%s is already a mount point", vol.Path())
+		plog.Debugf("%s is already a mount point", vol.Path())
 	}
 	return vol, nil
 }
@@ -483,7 +483,7 @@ func (d *DeviceMapperDriver) Get(volumeName string) (volume.Volume, error) {
 func (d *DeviceMapperDriver) List() (result []string) {
 	md := d.MetadataDir()
 	if files, err := ioutil.ReadDir(md); err != nil {
-		glog.Errorf("Error trying to read from metadata directory: %s", md)
+		plog.Errorf("Error trying to read from metadata directory: %s", md)
 		return
 	} else {
 		for _, fi := range files {
@@ -506,16 +506,16 @@ func (d *DeviceMapperDriver) Exists(volumeName string) bool {
 
 // Cleanup implements volume.Driver.Cleanup
 func (d *DeviceMapperDriver) Cleanup() error {
-	glog.V(2).Infof("Cleanup() START")
-	defer glog.V(2).Infof("Cleanup() END")
+	plog.Debugf("Cleanup() START")
+	defer plog.Debugf("Cleanup() END")
 	if d.DeviceSet == nil {
 		return nil
 	}
-	glog.V(1).Infof("Cleaning up devicemapper driver at %s", d.root)
+	plog.Debugf("Cleaning up devicemapper driver at %s", d.root)
 	for _, volname := range d.List() {
 		_, err := d.getVolume(volname, false)
 		if err != nil {
-			glog.V(1).Infof("Unable to get volume %s; skipping", volname)
+			plog.Debugf("Unable to get volume %s; skipping", volname)
 			continue
 		}
 		if err := d.Release(volname); err != nil {
@@ -527,16 +527,16 @@ func (d *DeviceMapperDriver) Cleanup() error {
 
 // Release implements volume.Driver.Release
 func (d *DeviceMapperDriver) Release(volumeName string) error {
-	glog.V(2).Infof("Release() (%s) START", volumeName)
-	defer glog.V(2).Infof("Release() (%s) END", volumeName)
+	plog.Debugf("Release() (%s) START", volumeName)
+	defer plog.Debugf("Release() (%s) END", volumeName)
 	vol, err := d.getVolume(volumeName, false)
 	if err != nil {
 		return err
 	}
 	if mounted, _ := devmapper.Mounted(vol.Path()); mounted {
-		glog.V(1).Infof("Unmounting %s", volumeName)
+		plog.Debugf("Unmounting %s", volumeName)
 		if err := vol.unmount(); err != nil {
-			glog.Errorf("Error whilst unmounting %s: %s", vol.path, err)
+			plog.Errorf("Error whilst unmounting %s: %s", vol.path, err)
 			return err
 		}
 	}
@@ -551,31 +551,31 @@ func (d *DeviceMapperDriver) Release(volumeName string) error {
 		// GetDeviceStatus() will both verify that device is valid, and it has the side-effect of activating
 		// the device if the device is not active.
 		if status, err := d.DeviceSet.GetDeviceStatus(deviceHash); err != nil {
-			glog.Errorf("For volume %s, unable to get status for device %q: %s", volumeName, deviceHash, err)
+			plog.Errorf("For volume %s, unable to get status for device %q: %s", volumeName, deviceHash, err)
 			continue
 		} else if status == nil {
-			glog.V(2).Infof("For volume %s, no status available for device %q", volumeName, deviceHash)
+			plog.Debugf("For volume %s, no status available for device %q", volumeName, deviceHash)
 		} else {
-			glog.V(2).Infof("For volume %s, status for device %q: %v", volumeName, deviceHash, status)
+			plog.Debugf("For volume %s, status for device %q: %v", volumeName, deviceHash, status)
 		}
 
-		glog.V(1).Infof("Deactivating device (%s)", deviceHash)
+		plog.Debugf("Deactivating device (%s)", deviceHash)
 		d.DeviceSet.Lock()
 		err := d.deactivateDevice(deviceHash)
 		d.DeviceSet.Unlock()
 		if err != nil {
-			glog.Errorf("Error removing device %q for volume %s: %s", deviceHash, volumeName, err)
+			plog.Errorf("Error removing device %q for volume %s: %s", deviceHash, volumeName, err)
 			return err
 		}
-		glog.V(2).Infof("Deactivated device")
+		plog.Debugf("Deactivated device")
 	}
 	return nil
 }
 
 // Remove implements volume.Driver.Remove
 func (d *DeviceMapperDriver) Remove(volumeName string) error {
-	glog.V(2).Infof("Remove() (%s) START", volumeName)
-	defer glog.V(2).Infof("Remove() (%s) END", volumeName)
+	plog.Debugf("Remove() (%s) START", volumeName)
+	defer plog.Debugf("Remove() (%s) END", volumeName)
 	if !d.Exists(volumeName) {
 		return nil
 	}
@@ -583,30 +583,30 @@ func (d *DeviceMapperDriver) Remove(volumeName string) error {
 	v, err := d.getVolume(volumeName, false)
 	if err != nil {
 		//log the error, but continue trying to remove things
-		glog.Errorf("Error loading volume %s: %s", volumeName, err)
+		plog.Errorf("Error loading volume %s: %s", volumeName, err)
 	} else {
 		// remove the snapshots
-		glog.V(1).Infof("Removing snapshots from %s", volumeName)
+		plog.Debugf("Removing snapshots from %s", volumeName)
 		snapshots, err := v.Snapshots()
 		if err != nil {
-			glog.Errorf("Error getting list of snapshots for volume %s: %s", volumeName, err)
+			plog.Errorf("Error getting list of snapshots for volume %s: %s", volumeName, err)
 		} else {
 			for _, snapshot := range snapshots {
 				if err := v.RemoveSnapshot(snapshot); err != nil {
-					glog.Errorf("Could not remove snapshot: %s", err)
+					plog.Errorf("Could not remove snapshot: %s", err)
 				}
 			}
 		}
 
 		// Release the device (requires another call to getVolume)
 		if err := d.Release(volumeName); err != nil {
-			glog.V(1).Infof("Error releasing device: %s", err)
+			plog.Debugf("Error releasing device: %s", err)
 		}
 
 		// Delete the device
-		glog.V(1).Infof("Removing volume %s", volumeName)
+		plog.Debugf("Removing volume %s", volumeName)
 		if err := d.deleteDevice(v.deviceHash(), false); err != nil {
-			glog.Errorf("Could not delete device %s: %s", volumeName, err)
+			plog.Errorf("Could not delete device %s: %s", volumeName, err)
 		}
 	}
 
@@ -619,8 +619,8 @@ func (d *DeviceMapperDriver) Remove(volumeName string) error {
 
 // Issues the underlying dm remove operation.
 func (d *DeviceMapperDriver) deactivateDevice(deviceHash string) error {
-	glog.V(2).Infof("deactivateDevice START(%s)", deviceHash)
-	defer glog.V(2).Infof("deactivateDevice END(%s)", deviceHash)
+	plog.Debugf("deactivateDevice START(%s)", deviceHash)
+	defer plog.Debugf("deactivateDevice END(%s)", deviceHash)
 
 	var err error
 
@@ -651,11 +651,11 @@ func (d *DeviceMapperDriver) unmountDevice(deviceHash, mountpoint string) {
 	// unmount(), because we DO care about Docker's internal bookkeeping
 	// here. Without this, DeviceSet.DeleteDevice will fail.
 	if err := d.DeviceSet.UnmountDevice(deviceHash, mountpoint); err != nil {
-		glog.V(2).Infof("Error unmounting %s (device: %s): %s", mountpoint, deviceHash, err)
+		plog.Debugf("Error unmounting %s (device: %s): %s", mountpoint, deviceHash, err)
 	}
 	d.DeviceSet.Lock()
 	if err := d.deactivateDevice(deviceHash); err != nil {
-		glog.V(2).Infof("Error deactivating device %s: %s", deviceHash, err)
+		plog.Debugf("Error deactivating device %s: %s", deviceHash, err)
 	}
 	d.DeviceSet.Unlock()
 }
@@ -664,12 +664,12 @@ func (d *DeviceMapperDriver) unmountDevice(deviceHash, mountpoint string) {
 func (d *DeviceMapperDriver) readDeviceInfo(deviceHash string, devInfo *devInfo) error {
 	fh, err := os.Open(d.deviceInfoPath(deviceHash))
 	if err != nil {
-		glog.Errorf("Could not read device info for %s: %s", deviceHash, err)
+		plog.Errorf("Could not read device info for %s: %s", deviceHash, err)
 		return err
 	}
 	defer fh.Close()
 	if err := json.NewDecoder(fh).Decode(devInfo); err != nil {
-		glog.Errorf("Could not decode device info for %s: %s", deviceHash, err)
+		plog.Errorf("Could not decode device info for %s: %s", deviceHash, err)
 		return err
 	}
 	return nil
@@ -680,16 +680,16 @@ func (d *DeviceMapperDriver) writeDeviceInfo(deviceHash string, devInfo devInfo)
 	devInfoPath := d.deviceInfoPath(deviceHash)
 	fs, err := os.Stat(devInfoPath)
 	if err != nil {
-		glog.Errorf("Could not stat file %s: %s", devInfoPath, err)
+		plog.Errorf("Could not stat file %s: %s", devInfoPath, err)
 		return err
 	}
 	data, err := json.Marshal(devInfo)
 	if err != nil {
-		glog.Errorf("Could not marshal info for device %s: %s", deviceHash, err)
+		plog.Errorf("Could not marshal info for device %s: %s", deviceHash, err)
 		return err
 	}
 	if err := atomicfile.WriteFile(devInfoPath, data, fs.Mode()); err != nil {
-		glog.Errorf("Could not update info for device %s: %s", deviceHash, err)
+		plog.Errorf("Could not update info for device %s: %s", deviceHash, err)
 		return err
 	}
 	return nil
@@ -699,12 +699,12 @@ func (d *DeviceMapperDriver) writeDeviceInfo(deviceHash string, devInfo devInfo)
 func (d *DeviceMapperDriver) resizeDevice(deviceHash string, size uint64) error {
 	var devInfo devInfo
 	if err := d.readDeviceInfo(deviceHash, &devInfo); err != nil {
-		glog.Errorf("Could not read info for device %s: %s", deviceHash, err)
+		plog.Errorf("Could not read info for device %s: %s", deviceHash, err)
 		return err
 	}
 	devInfo.Size = size
 	if err := d.writeDeviceInfo(deviceHash, devInfo); err != nil {
-		glog.Errorf("Could not write info for device %s: %s", deviceHash, err)
+		plog.Errorf("Could not write info for device %s: %s", deviceHash, err)
 		return err
 	}
 	return nil
@@ -759,7 +759,7 @@ func (d *DeviceMapperDriver) ensureInitialized() error {
 			} else if strings.HasPrefix(option, "enablelvmmonitoring=") {
 				enableLVMMonitoring = strings.TrimPrefix(option, "enablelvmmonitoring=")
 			} else {
-				glog.Errorf("Unable to parse option %s", option)
+				plog.Errorf("Unable to parse option %s", option)
 				return ErrInvalidOption
 			}
 		}
@@ -768,7 +768,7 @@ func (d *DeviceMapperDriver) ensureInitialized() error {
 		if err != nil {
 			if _, thinError := err.(devmapper.ThinpoolInitError); thinError {
 				//Try recreating the base image because sometimes something deletes it
-				glog.Errorf("Error intializing thin pool device, %s, attempting to create to new base device", err)
+				plog.Errorf("Error intializing thin pool device, %s, attempting to create to new base device", err)
 				deviceSet, err = devmapper.NewDeviceSet(poolPath, false, d.options, nil, nil)
 				if err != nil {
 					return err
@@ -790,30 +790,30 @@ func (d *DeviceMapperDriver) ensureInitialized() error {
 		switch enableLVMMonitoring {
 		case "y":
 			if thinPoolDev != "" {
-				glog.V(1).Infof("Enabling LVM Monitoring for thin pool device %s", thinPoolDev)
+				plog.Debugf("Enabling LVM Monitoring for thin pool device %s", thinPoolDev)
 				cmd := exec.Command("lvchange", "--monitor", "y", thinPoolDev)
 				output, err := cmd.CombinedOutput()
 				if err != nil {
-					glog.Errorf("Could not run command %v: %s (%s)", cmd, string(output), err)
+					plog.Errorf("Could not run command %v: %s (%s)", cmd, string(output), err)
 					return err
 				}
 			} else {
-				glog.Warningf("Ignoring option 'enablelvmmonitoring'; no thin pool device specified")
+				plog.Warningf("Ignoring option 'enablelvmmonitoring'; no thin pool device specified")
 			}
 		case "n", "":
 			if thinPoolDev != "" {
-				glog.V(1).Infof("Disabling LVM Monitoring for thin pool device %s", thinPoolDev)
+				plog.Debugf("Disabling LVM Monitoring for thin pool device %s", thinPoolDev)
 				cmd := exec.Command("lvchange", "--monitor", "n", thinPoolDev)
 				output, err := cmd.CombinedOutput()
 				if err != nil {
-					glog.Errorf("Could not run command %v: %s (%s)", cmd, string(output), err)
+					plog.Errorf("Could not run command %v: %s (%s)", cmd, string(output), err)
 					return err
 				}
 			} else if enableLVMMonitoring != "" {
-				glog.Warningf("Ignoring option 'enablelvmmonitoring'; no thin pool device specified")
+				plog.Warningf("Ignoring option 'enablelvmmonitoring'; no thin pool device specified")
 			}
 		default:
-			glog.Errorf("Attribute %s is not defined for enablelvmmonitoring", enableLVMMonitoring)
+			plog.Errorf("Attribute %s is not defined for enablelvmmonitoring", enableLVMMonitoring)
 			return ErrInvalidArg
 		}
 	}
@@ -825,7 +825,7 @@ func (d *DeviceMapperDriver) ensureInitialized() error {
 
 func (d *DeviceMapperDriver) deleteDevice(deviceName string, syncDelete bool) error {
 	if deviceName == "base" || deviceName == "" {
-		glog.Errorf("Request to delete base device '%s' will not be honored", deviceName)
+		plog.Errorf("Request to delete base device '%s' will not be honored", deviceName)
 		return ErrDeleteBaseDevice
 	}
 	return d.DeviceSet.DeleteDevice(deviceName, syncDelete)
@@ -855,7 +855,7 @@ func (v *DeviceMapperVolume) Tenant() string {
 func (v *DeviceMapperVolume) WriteMetadata(label, name string) (io.WriteCloser, error) {
 	filePath := filepath.Join(v.driver.MetadataDir(), v.rawSnapshotLabel(label), name)
 	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil && !os.IsExist(err) {
-		glog.Errorf("Could not create path for file %s: %s", name, err)
+		plog.Errorf("Could not create path for file %s: %s", name, err)
 		return nil, err
 	}
 	return os.Create(filePath)
@@ -871,18 +871,18 @@ func (v *DeviceMapperVolume) ReadMetadata(label, name string) (io.ReadCloser, er
 // code has a function for this, but it depends on some internal state that we
 // both can't count on and don't care about.
 func (v *DeviceMapperVolume) unmount() error {
-	glog.V(2).Infof("unmount() (%s) START", v.name)
-	defer glog.V(2).Infof("unmount() (%s) END", v.name)
+	plog.Debugf("unmount() (%s) START", v.name)
+	defer plog.Debugf("unmount() (%s) END", v.name)
 	v.driver.DeviceSet.Lock()
 	defer v.driver.DeviceSet.Unlock()
 
 	mountPath := v.Path()
-	glog.V(2).Infof("Unmounting from path %s START", mountPath)
+	plog.Debugf("Unmounting from path %s START", mountPath)
 	if err := unmount(mountPath); err != nil {
-		glog.Errorf("Got an error unmounting %s (%s)", mountPath, err)
+		plog.Errorf("Got an error unmounting %s (%s)", mountPath, err)
 		return err
 	}
-	glog.V(2).Infof("Unmounting from path %s END", mountPath)
+	plog.Debugf("Unmounting from path %s END", mountPath)
 	// pessimistically clean up the DeviceInfo object in memory. If it isn't
 	// there the next time the device is requested, it'll be recreated from
 	// disk.
@@ -893,7 +893,7 @@ func (v *DeviceMapperVolume) unmount() error {
 func unmount(mountpoint string) error {
 	if mounted, _ := devmapper.Mounted(mountpoint); mounted {
 		if err := syscall.Unmount(mountpoint, syscall.MNT_DETACH); err != nil {
-			glog.Errorf("Error unmounting %s: %s", mountpoint, err)
+			plog.Errorf("Error unmounting %s: %s", mountpoint, err)
 			return err
 		}
 	}
@@ -904,13 +904,13 @@ func unmount(mountpoint string) error {
 func (v *DeviceMapperVolume) writeSnapshotInfo(label string, info *volume.SnapshotInfo) error {
 	writer, err := v.WriteMetadata(label, ".SNAPSHOTINFO")
 	if err != nil {
-		glog.Errorf("Could not write meta info for snapshot %s: %s", label, err)
+		plog.Errorf("Could not write meta info for snapshot %s: %s", label, err)
 		return err
 	}
 	defer writer.Close()
 	encoder := json.NewEncoder(writer)
 	if err := encoder.Encode(info); err != nil {
-		glog.Errorf("Could not export meta info for snapshot %s: %s", label, err)
+		plog.Errorf("Could not export meta info for snapshot %s: %s", label, err)
 		return err
 	}
 	return nil
@@ -923,14 +923,14 @@ func (v *DeviceMapperVolume) SnapshotInfo(label string) (*volume.SnapshotInfo, e
 	}
 	reader, err := v.ReadMetadata(label, ".SNAPSHOTINFO")
 	if err != nil {
-		glog.Errorf("Could not get info for snapshot %s: %s", label, err)
+		plog.Errorf("Could not get info for snapshot %s: %s", label, err)
 		return nil, err
 	}
 	defer reader.Close()
 	decoder := json.NewDecoder(reader)
 	var info volume.SnapshotInfo
 	if err := decoder.Decode(&info); err != nil {
-		glog.Errorf("Could not decode snapshot info for %s: %s", label, err)
+		plog.Errorf("Could not decode snapshot info for %s: %s", label, err)
 		return nil, err
 	}
 	return &info, err
@@ -938,8 +938,8 @@ func (v *DeviceMapperVolume) SnapshotInfo(label string) (*volume.SnapshotInfo, e
 
 // Snapshot implements volume.Volume.Snapshot
 func (v *DeviceMapperVolume) Snapshot(label, message string, tags []string) error {
-	glog.V(2).Infof("Snapshot() (%s) START", v.name)
-	defer glog.V(2).Infof("Snapshot() (%s) END", v.name)
+	plog.Debugf("Snapshot() (%s) START", v.name)
+	defer plog.Debugf("Snapshot() (%s) END", v.name)
 	if v.snapshotExists(label) {
 		return volume.ErrSnapshotExists
 	}
@@ -947,10 +947,10 @@ func (v *DeviceMapperVolume) Snapshot(label, message string, tags []string) erro
 	for _, tagName := range tags {
 		if tagInfo, err := v.GetSnapshotWithTag(tagName); err != volume.ErrSnapshotDoesNotExist {
 			if err != nil {
-				glog.Errorf("Could not look up snapshot for tag %s: %s", tagName, err)
+				plog.Errorf("Could not look up snapshot for tag %s: %s", tagName, err)
 				return err
 			} else {
-				glog.Errorf("Tag '%s' is already in use by snapshot %s", tagName, tagInfo.Name)
+				plog.Errorf("Tag '%s' is already in use by snapshot %s", tagName, tagInfo.Name)
 				return volume.ErrTagAlreadyExists
 			}
 		}
@@ -962,7 +962,7 @@ func (v *DeviceMapperVolume) Snapshot(label, message string, tags []string) erro
 	activeDeviceHash := v.deviceHash()
 	snapshotDeviceHash, err := v.driver.addDevice(activeDeviceHash)
 	if err != nil {
-		glog.Errorf("Unable to add devicemapper device: %s", err)
+		plog.Errorf("Unable to add devicemapper device: %s", err)
 		return err
 	}
 	// write snapshot info
@@ -978,9 +978,9 @@ func (v *DeviceMapperVolume) Snapshot(label, message string, tags []string) erro
 		return err
 	}
 	// Save the new HEAD as the snapshot
-	glog.V(2).Infof("Saving device %s as snapshot %s", snapshotDeviceHash, label)
+	plog.Debugf("Saving device %s as snapshot %s", snapshotDeviceHash, label)
 	if err := v.Metadata.AddSnapshot(label, snapshotDeviceHash); err != nil {
-		glog.Errorf("Unable to save snapshot metadata: %s", err)
+		plog.Errorf("Unable to save snapshot metadata: %s", err)
 		return err
 	}
 	return nil
@@ -993,23 +993,23 @@ func (v *DeviceMapperVolume) TagSnapshot(label string, tagName string) error {
 	// get the snapshot
 	info, err := v.SnapshotInfo(label)
 	if err != nil {
-		glog.Errorf("Could not look up snapshot %s: %s", label, err)
+		plog.Errorf("Could not look up snapshot %s: %s", label, err)
 		return err
 	}
 	// verify the tag doesn't already exist
 	if tagInfo, err := v.GetSnapshotWithTag(tagName); err != volume.ErrSnapshotDoesNotExist {
 		if err != nil {
-			glog.Errorf("Could not look up snapshot for tag %s: %s", tagName, err)
+			plog.Errorf("Could not look up snapshot for tag %s: %s", tagName, err)
 			return err
 		} else {
-			glog.Errorf("Tag '%s' is already in use by snapshot %s", tagName, tagInfo.Name)
+			plog.Errorf("Tag '%s' is already in use by snapshot %s", tagName, tagInfo.Name)
 			return volume.ErrTagAlreadyExists
 		}
 	}
 	// add the tag and update the snapshot
 	info.Tags = append(info.Tags, tagName)
 	if err := v.writeSnapshotInfo(info.Label, info); err != nil {
-		glog.Errorf("Could not update tags for snapshot %s: %s", info.Label, err)
+		plog.Errorf("Could not update tags for snapshot %s: %s", info.Label, err)
 		return err
 	}
 	return nil
@@ -1022,7 +1022,7 @@ func (v *DeviceMapperVolume) UntagSnapshot(tagName string) (string, error) {
 	// find the snapshot with the provided tag
 	info, err := v.GetSnapshotWithTag(tagName)
 	if err != nil {
-		glog.Errorf("Could not find snapshot with tag %s: %s", tagName, err)
+		plog.Errorf("Could not find snapshot with tag %s: %s", tagName, err)
 		return "", err
 	}
 	// remove the tag and update the snapshot
@@ -1034,7 +1034,7 @@ func (v *DeviceMapperVolume) UntagSnapshot(tagName string) (string, error) {
 	}
 	info.Tags = tags
 	if err := v.writeSnapshotInfo(info.Label, info); err != nil {
-		glog.Errorf("Could not remove tag '%s' from snapshot %s: %s", tagName, info.Name, err)
+		plog.Errorf("Could not remove tag '%s' from snapshot %s: %s", tagName, info.Name, err)
 		return "", err
 	}
 	return info.Label, err
@@ -1045,14 +1045,14 @@ func (v *DeviceMapperVolume) GetSnapshotWithTag(tagName string) (*volume.Snapsho
 	// Get all the snapshots on the volume
 	snapshotLabels, err := v.Snapshots()
 	if err != nil {
-		glog.Errorf("Could not get current snapshot list: %s", err)
+		plog.Errorf("Could not get current snapshot list: %s", err)
 		return nil, err
 	}
 	// Get info for each snapshot and return if a matching tag is found
 	for _, snapshotLabel := range snapshotLabels {
 		if info, err := v.SnapshotInfo(snapshotLabel); err != volume.ErrInvalidSnapshot {
 			if err != nil {
-				glog.Errorf("Could not get info for snapshot %s: %s", snapshotLabel, err)
+				plog.Errorf("Could not get info for snapshot %s: %s", snapshotLabel, err)
 				return nil, err
 			}
 			for _, tag := range info.Tags {
@@ -1082,8 +1082,8 @@ func (v *DeviceMapperVolume) isInvalidSnapshot(rawLabel string) bool {
 
 // RemoveSnapshot implements volume.Volume.RemoveSnapshot
 func (v *DeviceMapperVolume) RemoveSnapshot(label string) error {
-	glog.V(2).Infof("RemoveSnapshot() (%s) START", v.name)
-	defer glog.V(2).Infof("RemoveSnapshot() (%s) END", v.name)
+	plog.Debugf("RemoveSnapshot() (%s) START", v.name)
+	defer plog.Debugf("RemoveSnapshot() (%s) END", v.name)
 	if !v.snapshotExists(label) {
 		return volume.ErrSnapshotDoesNotExist
 	}
@@ -1092,26 +1092,26 @@ func (v *DeviceMapperVolume) RemoveSnapshot(label string) error {
 	defer v.Unlock()
 	deviceHash, err := v.Metadata.LookupSnapshotDevice(rawLabel)
 	if err != nil {
-		glog.Errorf("Error removing snapshot: %v", err)
+		plog.Errorf("Error removing snapshot: %v", err)
 		return volume.ErrRemovingSnapshot
 	}
 
 	// Delete the device itself first, so we retain our internal snapshot entry
 	// (metadata.json) in the event of a failure on this step
-	glog.V(2).Infof("Deactivating snapshot device %s", deviceHash)
+	plog.Debugf("Deactivating snapshot device %s", deviceHash)
 	v.driver.DeviceSet.Lock()
 	if err := v.driver.deactivateDevice(deviceHash); err != nil {
-		glog.V(2).Infof("Error deactivating device (%s): %s", deviceHash, err)
+		plog.Debugf("Error deactivating device (%s): %s", deviceHash, err)
 	}
 	v.driver.DeviceSet.Unlock()
 	if err := v.driver.deleteDevice(deviceHash, false); err != nil {
-		glog.Errorf("Error removing snapshot: %v", err)
+		plog.Errorf("Error removing snapshot: %v", err)
 		return volume.ErrRemovingSnapshot
 	}
 
 	// Remove the snapshot info from the volume metadata
 	if err := v.Metadata.RemoveSnapshot(rawLabel); err != nil {
-		glog.Errorf("Error removing snapshot: %v", err)
+		plog.Errorf("Error removing snapshot: %v", err)
 		return volume.ErrRemovingSnapshot
 	}
 	// Remove the snapshot-specific metadata directory
@@ -1127,8 +1127,8 @@ func (v *DeviceMapperVolume) Rollback(label string) error {
 		return volume.ErrInvalidSnapshot
 	}
 
-	glog.V(2).Infof("Rollback() (%s) START", v.name)
-	defer glog.V(2).Infof("Rollback() (%s) END", v.name)
+	plog.Debugf("Rollback() (%s) START", v.name)
+	defer plog.Debugf("Rollback() (%s) END", v.name)
 	if !v.snapshotExists(label) {
 		return volume.ErrSnapshotDoesNotExist
 	}
@@ -1148,32 +1148,32 @@ func (v *DeviceMapperVolume) Rollback(label string) error {
 	if err != nil {
 		return err
 	}
-	glog.V(2).Infof("Created new head device %s based on snapshot %s", newDeviceHash, snapDeviceHash)
+	plog.Debugf("Created new head device %s based on snapshot %s", newDeviceHash, snapDeviceHash)
 
 	// Unmount the current device and mount the new one
 	if err := v.unmount(); err != nil {
 		return err
 	}
-	glog.V(2).Infof("Unmounted old head device %s", curDeviceHash)
+	plog.Debugf("Unmounted old head device %s", curDeviceHash)
 
 	// Rollback to the new device
 	if err := v.driver.DeviceSet.MountDevice(newDeviceHash, v.path, v.name); err != nil {
 		return err
 	}
-	glog.V(2).Infof("Rollback(): mounting new head device %s", newDeviceHash)
+	plog.Debugf("Rollback(): mounting new head device %s", newDeviceHash)
 
 	// Clean up the old device
 	v.driver.DeviceSet.Lock()
 	if err := v.driver.deactivateDevice(curDeviceHash); err != nil {
-		glog.V(2).Infof("Could not remove device: %s", err)
+		plog.Debugf("Could not remove device: %s", err)
 	} else {
-		glog.V(2).Infof("Deactivated old head device %s", curDeviceHash)
+		plog.Debugf("Deactivated old head device %s", curDeviceHash)
 	}
 	v.driver.DeviceSet.Unlock()
 	if err := v.driver.deleteDevice(curDeviceHash, false); err != nil {
-		glog.Warningf("Error cleaning up old head device %s: %s", curDeviceHash, err)
+		plog.Warningf("Error cleaning up old head device %s: %s", curDeviceHash, err)
 	} else {
-		glog.V(2).Infof("Deleted old head device %s", curDeviceHash)
+		plog.Debugf("Deleted old head device %s", curDeviceHash)
 	}
 
 	return v.Metadata.SetCurrentDevice(newDeviceHash)
@@ -1181,8 +1181,8 @@ func (v *DeviceMapperVolume) Rollback(label string) error {
 
 // Export implements volume.Volume.Export
 func (v *DeviceMapperVolume) Export(label, parent string, writer io.Writer, excludes []string) error {
-	glog.V(2).Infof("Export() (%s) START", v.name)
-	defer glog.V(2).Infof("Export() (%s) END", v.name)
+	plog.Debugf("Export() (%s) START", v.name)
+	defer plog.Debugf("Export() (%s) END", v.name)
 	if !v.snapshotExists(label) {
 		return volume.ErrSnapshotDoesNotExist
 	}
@@ -1196,7 +1196,7 @@ func (v *DeviceMapperVolume) Export(label, parent string, writer io.Writer, excl
 	if err != nil {
 		return err
 	}
-	glog.V(2).Infof("Mounting temporary export device %s", deviceHash)
+	plog.Debugf("Mounting temporary export device %s", deviceHash)
 	if err := v.driver.DeviceSet.MountDevice(deviceHash, mountpoint, label); err != nil {
 		return err
 	}
@@ -1205,11 +1205,11 @@ func (v *DeviceMapperVolume) Export(label, parent string, writer io.Writer, excl
 		// unmount(), because we DO care about Docker's internal bookkeeping
 		// here. Without this, DeviceSet.DeleteDevice will fail.
 		if err := d.DeviceSet.UnmountDevice(deviceHash, mountpoint); err != nil {
-			glog.V(2).Infof("Error unmounting %s (device: %s): %s", mountpoint, deviceHash, err)
+			plog.Debugf("Error unmounting %s (device: %s): %s", mountpoint, deviceHash, err)
 		}
 		d.DeviceSet.Lock()
 		if err := d.deactivateDevice(deviceHash); err != nil {
-			glog.V(2).Infof("Error deactivating device %s: %s", deviceHash, err)
+			plog.Debugf("Error deactivating device %s: %s", deviceHash, err)
 		}
 		d.DeviceSet.Unlock()
 	}(v.driver, deviceHash, mountpoint)
@@ -1220,32 +1220,32 @@ func (v *DeviceMapperVolume) Export(label, parent string, writer io.Writer, excl
 	drivertype := []byte(v.Driver().DriverType())
 	header := &tar.Header{Name: fmt.Sprintf("%s-driver", label), Size: int64(len(drivertype))}
 	if err := tarOut.WriteHeader(header); err != nil {
-		glog.Errorf("Could not export driver type header for snapshot %s: %s", label, err)
+		plog.Errorf("Could not export driver type header for snapshot %s: %s", label, err)
 		return err
 	}
 	if _, err := tarOut.Write(drivertype); err != nil {
-		glog.Errorf("Could not export driver type for snapshot %s: %s", label, err)
+		plog.Errorf("Could not export driver type for snapshot %s: %s", label, err)
 		return err
 	}
 
 	// Set the device metadata
 	size, err := v.SizeOf()
 	if err != nil {
-		glog.Errorf("Could not get the size of the device for snapshot %s: %s", label, err)
+		plog.Errorf("Could not get the size of the device for snapshot %s: %s", label, err)
 		return err
 	}
 	volInfo, err := json.Marshal(volumeInfo{Size: size})
 	if err != nil {
-		glog.Errorf("Could not marshal device info for snapshot %s: %s", label, err)
+		plog.Errorf("Could not marshal device info for snapshot %s: %s", label, err)
 		return err
 	}
 	header = &tar.Header{Name: fmt.Sprintf("%s-device", label), Size: int64(len(volInfo))}
 	if err := tarOut.WriteHeader(header); err != nil {
-		glog.Errorf("Could not export device info header for snapshot %s: %s", label, err)
+		plog.Errorf("Could not export device info header for snapshot %s: %s", label, err)
 		return err
 	}
 	if _, err := tarOut.Write(volInfo); err != nil {
-		glog.Errorf("Could not export device info for snapshot %s: %s", label, err)
+		plog.Errorf("Could not export device info for snapshot %s: %s", label, err)
 		return err
 	}
 
@@ -1263,7 +1263,7 @@ func (v *DeviceMapperVolume) Export(label, parent string, writer io.Writer, excl
 }
 
 func (d *DeviceMapperDriver) Status() (volume.Status, error) {
-	glog.V(2).Info("devicemapper.Status()")
+	plog.Debug("devicemapper.Status()")
 	dockerStatus := d.DeviceSet.Status()
 	tss, err := d.GetTenantStorageStats()
 	if err != nil {
@@ -1304,9 +1304,11 @@ func (d *DeviceMapperDriver) Status() (volume.Status, error) {
 				Value: tenant.FilesystemUsed},
 			{MetricName: fmt.Sprintf("storage.device.total.%s", tenant.TenantID),
 				Value: tenant.DeviceTotalBlocks},
-			/* Disabled due to CC-2417
 			{MetricName: fmt.Sprintf("storage.device.allocated.%s", tenant.TenantID),
 				Value: tenant.DeviceAllocatedBlocks},
+			{MetricName: fmt.Sprintf("storage.device.reclaimable.%s", tenant.TenantID),
+				Value: tenant.ReclaimableBlocks},
+			/* Disabled due to CC-2417
 			{MetricName: fmt.Sprintf("storage.snapshot.allocated.%s", tenant.TenantID),
 				Value: tenant.SnapshotAllocatedBlocks},
 			*/
@@ -1377,12 +1379,11 @@ func (d *DeviceMapperDriver) GetTenantStorageStats() ([]volume.TenantStorageStat
 		// CC-2417
 		//if stats, ok := blockstats[devInfo.DeviceID]; ok {
 		tss := volume.TenantStorageStats{TenantID: tenant, VolumePath: vol.Path()}
-		// CC-2417
-		//tss.DeviceAllocatedBlocks = stats.diet.Total()
 		tss.NumberSnapshots = len(vol.Metadata.snapshotMetadata.Snapshots)
 		dev := vol.Metadata.CurrentDevice()
 		// This will activate the device
-		if _, err := d.DeviceSet.GetDeviceStatus(dev); err != nil {
+		devstatus, err := d.DeviceSet.GetDeviceStatus(dev)
+		if err != nil {
 			return nil, err
 		}
 		devicename := fmt.Sprintf("/dev/mapper/%s-%s", d.DevicePrefix, dev)
@@ -1398,7 +1399,23 @@ func (d *DeviceMapperDriver) GetTenantStorageStats() ([]volume.TenantStorageStat
 		tss.FilesystemAvailable = free
 		tss.FilesystemUsed = total - free
 		tss.DeviceTotalBlocks = volume.BytesToBlocks(size)
-		//tss.DeviceUnallocatedBlocks = tss.DeviceTotalBlocks - tss.DeviceAllocatedBlocks
+		// the sectors mapped by the thin device are its actual usage of
+		// the pool, and unlike the block stats they are read from the
+		// kernel without dumping the pool metadata
+		tss.DeviceAllocatedBlocks = sectorsToBlocks(devstatus.MappedSectors)
+		if tss.DeviceAllocatedBlocks < tss.DeviceTotalBlocks {
+			tss.DeviceUnallocatedBlocks = tss.DeviceTotalBlocks - tss.DeviceAllocatedBlocks
+		}
+		if used := volume.BytesToBlocks(tss.FilesystemUsed); used < tss.DeviceAllocatedBlocks {
+			tss.ReclaimableBlocks = tss.DeviceAllocatedBlocks - used
+			tss.Errors = append(tss.Errors, fmt.Sprintf(` !	Note: %s of blocks are allocated to an application virtual device but
+		are unused by the filesystem. This is not a problem; however, if you want
+		the thin pool to reclaim the space for use by snapshots or another
+		application, run:
+
+			$ fstrim %s`, volume.BlocksToBytes(tss.ReclaimableBlocks), vol.Path()))
+		}
+		tss.Snapshots = d.getSnapshotStorageStats(vol, &tss)
 		/* CC-2417
 				last := stats
 				for _, device := range vol.Metadata.snapshotMetadata.Snapshots {
@@ -1410,15 +1427,6 @@ func (d *DeviceMapperDriver) GetTenantStorageStats() ([]volume.TenantStorageStat
 						last = snapstats
 					}
 				}
-				/*
-				if volume.BytesToBlocks(tss.FilesystemUsed) < tss.DeviceAllocatedBlocks {
-					tss.Errors = append(tss.Errors, fmt.Sprintf(` !	Note: %s of blocks are allocated to an application virtual device but
-		are unused by the filesystem. This is not a problem; however, if you want
-		the thin pool to reclaim the space for use by snapshots or another
-		application, run:
-
-			$ fstrim %s`, volume.BlocksToBytes(tss.DeviceAllocatedBlocks-volume.BytesToBlocks(tss.FilesystemUsed)), vol.Path()))
-				}
 		*/
 		result = append(result, tss)
 		/* CC-2417
@@ -1431,10 +1439,42 @@ func (d *DeviceMapperDriver) GetTenantStorageStats() ([]volume.TenantStorageStat
 	return result, nil
 }
 
+// getSnapshotStorageStats returns the size and allocation of the snapshot
+// devices of a volume.  Snapshots that cannot be checked are noted in the
+// tenant's errors.
+func (d *DeviceMapperDriver) getSnapshotStorageStats(vol *DeviceMapperVolume, tss *volume.TenantStorageStats) []volume.SnapshotStorageStats {
+	labels := make([]string, 0, len(vol.Metadata.snapshotMetadata.Snapshots))
+	for label := range vol.Metadata.snapshotMetadata.Snapshots {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	var result []volume.SnapshotStorageStats
+	for _, label := range labels {
+		// peek so that checking the snapshots leaves them inactive
+		status, err := d.DeviceSet.PeekDeviceStatus(vol.Metadata.snapshotMetadata.Snapshots[label])
+		if err != nil {
+			tss.Errors = append(tss.Errors, fmt.Sprintf("Could not get the status of snapshot %s: %s", label, err))
+			continue
+		}
+		result = append(result, volume.SnapshotStorageStats{
+			Label:                 label,
+			DeviceTotalBlocks:     volume.BytesToBlocks(status.Size),
+			DeviceAllocatedBlocks: sectorsToBlocks(status.MappedSectors),
+		})
+	}
+	return result
+}
+
+// sectorsToBlocks converts 512 byte device sectors to blocks
+func sectorsToBlocks(sectors uint64) uint64 {
+	return volume.BytesToBlocks(sectors * 512)
+}
+
 // Import implements volume.Volume.Import
 func (v *DeviceMapperVolume) Import(label string, reader io.Reader) (err error) {
-	glog.V(2).Infof("Import() (%s) START", v.name)
-	defer glog.V(2).Infof("Import() (%s) END", v.name)
+	plog.Debugf("Import() (%s) START", v.name)
+	defer plog.Debugf("Import() (%s) END", v.name)
 
 	// Do not try to import if the snapshot label exists on the volume.
 	if v.snapshotExists(label) {
@@ -1448,19 +1488,19 @@ func (v *DeviceMapperVolume) Import(label string, reader io.Reader) (err error)
 	// Set up the mountpoint to load the snapshot
 	mountpoint, err := ioutil.TempDir(v.driver.Root(), label+"_import-")
 	if err != nil {
-		glog.Errorf("Could not create mountpoint to import snapshot %s: %s", label, err)
+		plog.Errorf("Could not create mountpoint to import snapshot %s: %s", label, err)
 		return err
 	}
-	glog.V(2).Infof("Created mountpoint at %s for snapshot %s", mountpoint, label)
+	plog.Debugf("Created mountpoint at %s for snapshot %s", mountpoint, label)
 	defer os.RemoveAll(mountpoint)
 
 	// Set up the staging device for the snapshot
 	deviceHash, err := v.driver.addDevice("")
 	if err != nil {
-		glog.Errorf("Unable to create staging device at mountpoint %s for snapshot %s: %s", mountpoint, label, err)
+		plog.Errorf("Unable to create staging device at mountpoint %s for snapshot %s: %s", mountpoint, label, err)
 		return err
 	}
-	glog.V(2).Infof("Created a staging device %s for snapshot %s", deviceHash, label)
+	plog.Debugf("Created a staging device %s for snapshot %s", deviceHash, label)
 	defer func() {
 		if err != nil {
 			v.driver.deleteDevice(deviceHash, false)
@@ -1470,10 +1510,10 @@ func (v *DeviceMapperVolume) Import(label string, reader io.Reader) (err error)
 	// Set up the metadata directory
 	metaPath := filepath.Join(v.driver.MetadataDir(), filepath.Base(mountpoint))
 	if err = os.MkdirAll(metaPath, 0755); err != nil {
-		glog.Errorf("Could not create metadata path for snapshot %s at %s: %s", label, metaPath, err)
+		plog.Errorf("Could not create metadata path for snapshot %s at %s: %s", label, metaPath, err)
 		return err
 	}
-	glog.V(2).Infof("Created metadata path %s for snapshot %s", metaPath, label)
+	plog.Debugf("Created metadata path %s for snapshot %s", metaPath, label)
 	defer func() {
 		if err != nil {
 			os.RemoveAll(metaPath)
@@ -1483,7 +1523,7 @@ func (v *DeviceMapperVolume) Import(label string, reader io.Reader) (err error)
 	if err = v.loadSnapshotImport(reader, label, deviceHash, mountpoint, metaPath); err != nil {
 		return err
 	}
-	glog.V(2).Infof("Successfully loaded snapshot %s", label)
+	plog.Debugf("Successfully loaded snapshot %s", label)
 
 	return nil
 }
@@ -1493,20 +1533,20 @@ func (v *DeviceMapperVolume) loadSnapshotImport(reader io.Reader, label, deviceH
 
 	// Mount the staging device
 	if err := v.driver.DeviceSet.MountDevice(deviceHash, mountpoint, label+"_import"); err != nil {
-		glog.Errorf("Could not stage the device %s at mountpoint %s for snapshot %s: %s", deviceHash, mountpoint, label, err)
+		plog.Errorf("Could not stage the device %s at mountpoint %s for snapshot %s: %s", deviceHash, mountpoint, label, err)
 		return err
 	}
-	glog.V(2).Infof("Mounted staging device %s at mountpoint %s for snapshot %s", deviceHash, mountpoint, label)
+	plog.Debugf("Mounted staging device %s at mountpoint %s for snapshot %s", deviceHash, mountpoint, label)
 	defer func(d *DeviceMapperDriver, deviceHash, mountpoint string) {
 		// We use the provided UnmountDevice func here, rather than our own
 		// unmount(), because we DO care about Docker's internal bookkeeping
 		// here. Without this, DeviceSet.DeleteDevice will fail.
 		if err := d.DeviceSet.UnmountDevice(deviceHash, mountpoint); err != nil {
-			glog.V(2).Infof("Error unmounting %s (device: %s): %s", mountpoint, deviceHash, err)
+			plog.Debugf("Error unmounting %s (device: %s): %s", mountpoint, deviceHash, err)
 		}
 		d.DeviceSet.Lock()
 		if err := d.deactivateDevice(deviceHash); err != nil {
-			glog.V(2).Infof("Error deactivating device %s: %s", deviceHash, err)
+			plog.Debugf("Error deactivating device %s: %s", deviceHash, err)
 		}
 		d.DeviceSet.Unlock()
 	}(v.driver, deviceHash, mountpoint)
@@ -1528,7 +1568,7 @@ func (v *DeviceMapperVolume) loadSnapshotImport(reader io.Reader, label, deviceH
 			}
 			break
 		} else if err != nil {
-			glog.Errorf("Could not import archive for label %s: %s", label, err)
+			plog.Errorf("Could not import archive for label %s: %s", label, err)
 			return err
 		}
 		if header.Name == driverFile {
@@ -1537,17 +1577,17 @@ func (v *DeviceMapperVolume) loadSnapshotImport(reader io.Reader, label, deviceH
 			// valid driver.
 			bfr := &bytes.Buffer{}
 			if _, err := bfr.ReadFrom(tarfile); err != nil {
-				glog.Errorf("Could not read from %s: %s", driverFile, err)
+				plog.Errorf("Could not read from %s: %s", driverFile, err)
 				return err
 			}
 			driverType = bfr.String()
-			glog.V(2).Infof("Volume driver type is %s", driverType)
+			plog.Debugf("Volume driver type is %s", driverType)
 		} else if header.Name == deviceFile {
 
 			// Update the staging device to match the provided settings
 			volInfo := volumeInfo{}
 			if err := json.NewDecoder(tarfile).Decode(&volInfo); err != nil {
-				glog.Errorf("Could not read from %s: %s", deviceFile, err)
+				plog.Errorf("Could not read from %s: %s", deviceFile, err)
 				return err
 			}
 
@@ -1555,16 +1595,16 @@ func (v *DeviceMapperVolume) loadSnapshotImport(reader io.Reader, label, deviceH
 			// value specified in the settings.
 			size, err := v.driver.deviceSize(deviceHash)
 			if err != nil {
-				glog.Errorf("Could not determine the size of the staging device %s: %s", deviceHash, err)
+				plog.Errorf("Could not determine the size of the staging device %s: %s", deviceHash, err)
 				return err
 			}
 			if volInfo.Size > size {
-				glog.Warning("Device size from import %s is greater than size of staging device; expanding.", label, deviceHash)
+				plog.Warningf("Device size from import %s is greater than size of staging device %s; expanding.", label, deviceHash)
 				if err := v.driver.resize(deviceHash, volInfo.Size); err != nil {
-					glog.Errorf("Could not resize device %s; not importing snapshot %s: %s", deviceHash, label, err)
+					plog.Errorf("Could not resize device %s; not importing snapshot %s: %s", deviceHash, label, err)
 					return err
 				}
-				glog.V(2).Infof("Device %s is now %s", deviceHash, units.HumanSize(float64(volInfo.Size)))
+				plog.Debugf("Device %s is now %s", deviceHash, units.HumanSize(float64(volInfo.Size)))
 			}
 		} else if strings.HasPrefix(header.Name, volumeDir) {
 
@@ -1585,13 +1625,13 @@ func (v *DeviceMapperVolume) loadSnapshotImport(reader io.Reader, label, deviceH
 
 	// Add device as a snapshot of this volume.
 	if err := v.Metadata.AddSnapshot(label, deviceHash); err != nil {
-		glog.Errorf("Could not save device %s as snapshot %s: %s", deviceHash, label, err)
+		plog.Errorf("Could not save device %s as snapshot %s: %s", deviceHash, label, err)
 		return err
 	}
 
 	// Set the snapshot metadata path
 	if err := os.Rename(metaPath, filepath.Join(v.driver.MetadataDir(), label)); err != nil {
-		glog.Errorf("Could not set device metadata for snapshot %s: %s", label, err)
+		plog.Errorf("Could not set device metadata for snapshot %s: %s", label, err)
 		return err
 	}
 
@@ -1619,7 +1659,7 @@ func (v *DeviceMapperVolume) snapshotExists(label string) bool {
 func getfstype(dmDevice string) (fstype []byte, err error) {
 	output, err := exec.Command("blkid", "-s", "TYPE", dmDevice).CombinedOutput()
 	if err != nil {
-		glog.Errorf("Could not get the device type of %s: %s (%s)", dmDevice, string(output), err)
+		plog.Errorf("Could not get the device type of %s: %s (%s)", dmDevice, string(output), err)
 		return nil, err
 	}
 	if fields := bytes.Fields(output); len(fields) == 2 {
@@ -1641,15 +1681,15 @@ func resize2fs(dmDevice string) error {
 	var cmd *exec.Cmd
 	switch string(fstype) {
 	case "xfs":
-		glog.Infof("Device type: %s, Command: xfs_growfs %s", fstype, dmDevice)
+		plog.Infof("Device type: %s, Command: xfs_growfs %s", fstype, dmDevice)
 		cmd = exec.Command("xfs_growfs", dmDevice)
 	default:
-		glog.Infof("Device type: %s, Command: resize2fs %s", fstype, dmDevice)
+		plog.Infof("Device type: %s, Command: resize2fs %s", fstype, dmDevice)
 		cmd = exec.Command("resize2fs", dmDevice)
 	}
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		glog.Errorf("Could not resize filesystem for device %s: %s (%s)", dmDevice, string(output), err)
+		plog.Errorf("Could not resize filesystem for device %s: %s (%s)", dmDevice, string(output), err)
 		return err
 	}
 	return nil
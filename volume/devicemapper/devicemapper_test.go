@@ -29,12 +29,12 @@ import (
 	. "gopkg.in/check.v1"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/volume"
 	"github.com/control-center/serviced/volume/drivertest"
 	"github.com/docker/go-units"
 	// Register the devicemapper driver
 	. "github.com/control-center/serviced/volume/devicemapper"
-	"github.com/zenoss/glog"
 )
 
 var (
@@ -56,9 +56,9 @@ func init() {
 	// information if -v
 	logrus.SetLevel(logrus.DebugLevel)
 
-	// Also enable glog verbosity so we get other interesting information if tests run with -v
-	glog.SetToStderr(true)
-	glog.SetVerbosity(2)
+	// Also log the driver's debug messages so we get other interesting
+	// information if tests run with -v
+	logging.SetComponentLevel("volume.devicemapper", "debug")
 
 }
 
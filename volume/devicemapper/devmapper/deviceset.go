@@ -2362,6 +2362,45 @@ func (devices *DeviceSet) GetDeviceStatus(hash string) (*DevStatus, error) {
 	return status, nil
 }
 
+// PeekDeviceStatus provides size, mapped sectors like GetDeviceStatus, but
+// deactivates the device afterwards if it was not already active
+func (devices *DeviceSet) PeekDeviceStatus(hash string) (*DevStatus, error) {
+	info, err := devices.lookupDeviceWithLock(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	info.lock.Lock()
+	defer info.lock.Unlock()
+
+	devices.Lock()
+	defer devices.Unlock()
+
+	status := &DevStatus{
+		DeviceID:      info.DeviceID,
+		Size:          info.Size,
+		TransactionID: info.TransactionID,
+	}
+
+	if devinfo, _ := devicemapper.GetInfo(info.Name()); devinfo == nil || devinfo.Exists == 0 {
+		if err := devicemapper.ActivateDevice(devices.getPoolDevName(), info.Name(), info.DeviceID, info.Size); err != nil {
+			return nil, fmt.Errorf("devmapper: Error activating devmapper device for '%s': %s", hash, err)
+		}
+		defer devices.deactivateDevice(info)
+	}
+
+	sizeInSectors, mappedSectors, highestMappedSector, err := devices.deviceStatus(info.DevName())
+	if err != nil {
+		return nil, err
+	}
+
+	status.SizeInSectors = sizeInSectors
+	status.MappedSectors = mappedSectors
+	status.HighestMappedSector = highestMappedSector
+
+	return status, nil
+}
+
 func (devices *DeviceSet) poolStatus() (totalSizeInSectors, transactionID, dataUsed, dataTotal, metadataUsed, metadataTotal uint64, err error) {
 	var params string
 	if _, totalSizeInSectors, _, params, err = devicemapper.GetStatus(devices.getPoolName()); err == nil {
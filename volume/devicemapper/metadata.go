@@ -11,7 +11,6 @@ import (
 	"sync"
 
 	"github.com/control-center/serviced/volume"
-	"github.com/zenoss/glog"
 )
 
 var (
@@ -41,7 +40,7 @@ func NewMetadata(path string, create bool) (*SnapshotMetadata, error) {
 			return nil, ErrNoMetadata
 		}
 	} else if err != nil {
-		glog.Errorf("Could not stat dir %s: %s", dir, err)
+		plog.Errorf("Could not stat dir %s: %s", dir, err)
 		return nil, err
 	}
 
@@ -91,7 +90,7 @@ func (m *SnapshotMetadata) save() error {
 	// Exported methods will deal with locking.
 	jsonData, err := json.Marshal(m.snapshotMetadata)
 	if err != nil {
-		glog.Errorf("Error encoding metadata to json: %s", err)
+		plog.Errorf("Error encoding metadata to json: %s", err)
 		return ErrInvalidMetadata
 	}
 	return ioutil.WriteFile(m.path, jsonData, 0644)
@@ -29,7 +29,6 @@ import (
 
 	"github.com/control-center/serviced/commons/diet"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 )
 
 var storageStatsUpdateInterval time.Duration = 300
@@ -521,7 +520,7 @@ func (d *DeviceMapperDriver) getDeviceBlockStats(pool, metadatadev string) (map[
 	defer statscache.locks.UnlockKey(pool)
 	val, ok := statscache.cache[pool]
 	if !ok || time.Now().After(val.expiry) {
-		glog.Infof("Refreshing storage stats cache from thin pool metadata")
+		plog.Infof("Refreshing storage stats cache from thin pool metadata")
 		// Check for an existing snapshot of the metadata device.
 		hasSnap, err := hasMetadataSnap(pool)
 		if err != nil {
@@ -19,21 +19,19 @@ import (
 	"os"
 	"path/filepath"
 	"syscall"
-
-	"github.com/zenoss/glog"
 )
 
 // ExportDirectory recursively writes its contents into a tar Writer.
 func ExportDirectory(tarfile *tar.Writer, path, name string) error {
 	dir, err := os.Open(path)
 	if err != nil {
-		glog.Errorf("Could not open %s: %s", path, err)
+		plog.Errorf("Could not open %s: %s", path, err)
 		return err
 	}
 	defer dir.Close()
 	fstat, err := dir.Stat()
 	if err != nil {
-		glog.Errorf("Could not stat %s: %s", path, err)
+		plog.Errorf("Could not stat %s: %s", path, err)
 		return err
 	}
 	header, err := getHeader(name, "", fstat)
@@ -41,12 +39,12 @@ func ExportDirectory(tarfile *tar.Writer, path, name string) error {
 		return err
 	}
 	if err := tarfile.WriteHeader(header); err != nil {
-		glog.Errorf("Could not write header for directory %s: %s", path, err)
+		plog.Errorf("Could not write header for directory %s: %s", path, err)
 		return err
 	}
 	files, err := dir.Readdir(0)
 	if err != nil {
-		glog.Errorf("Could not list directory for %s: %s", path, err)
+		plog.Errorf("Could not list directory for %s: %s", path, err)
 		return err
 	}
 	for _, finfo := range files {
@@ -69,51 +67,51 @@ func ExportFile(tarfile *tar.Writer, path, name string) error {
 	// use Lstat so we don't dereference the symlinks.
 	fstat, err := os.Lstat(path)
 	if err != nil {
-		glog.Errorf("Could not stat %s", path)
+		plog.Errorf("Could not stat %s", path)
 		return err
 	}
 
 	if isSocket := fstat.Mode() & os.ModeSocket; isSocket == os.ModeSocket {
-		glog.Warningf("Cannot export Unix domain socket %s", path)
+		plog.Warningf("Cannot export Unix domain socket %s", path)
 		return nil
 	}
 	if isNamedPipe := fstat.Mode() & os.ModeNamedPipe; isNamedPipe == os.ModeNamedPipe {
-		glog.Warningf("Cannot export Named pipe %s", path)
+		plog.Warningf("Cannot export Named pipe %s", path)
 		return nil
 	}
 	if isDevice := fstat.Mode() & os.ModeDevice; isDevice == os.ModeDevice {
-		glog.Warningf("Cannot export Device %s", path)
+		plog.Warningf("Cannot export Device %s", path)
 		return nil
 	}
 
 	file, err := os.Open(path)
 	if err != nil {
-		glog.Errorf("Could not open %s: %s", path, err)
+		plog.Errorf("Could not open %s: %s", path, err)
 		return err
 	}
 	defer file.Close()
 
 	link, err := filepath.EvalSymlinks(path)
 	if err != nil {
-		glog.Errorf("Could not check link for %s: %s", path, err)
+		plog.Errorf("Could not check link for %s: %s", path, err)
 		return err
 	}
 
 	header, err := getHeader(name, link, fstat)
 	if err != nil {
-		glog.Errorf("Could not create file header %s: %s", path, err)
+		plog.Errorf("Could not create file header %s: %s", path, err)
 		return err
 	}
 
 	if err := tarfile.WriteHeader(header); err != nil {
-		glog.Errorf("Could not write file header %s: %s", path, err)
+		plog.Errorf("Could not write file header %s: %s", path, err)
 		return err
 	}
 
 	// Only write regular files; ignore symlinks/named pipes/sockets/devices.
 	if fstat.Mode().IsRegular() {
 		if _, err := io.Copy(tarfile, file); err != nil {
-			glog.Errorf("Could not write file %s: %s", path, err)
+			plog.Errorf("Could not write file %s: %s", path, err)
 			return err
 		}
 	}
@@ -128,7 +126,7 @@ func ImportArchive(tarfile *tar.Reader, path string) error {
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			glog.Errorf("Could not import archive to %s: %s", path, err)
+			plog.Errorf("Could not import archive to %s: %s", path, err)
 			return err
 		}
 		if err := ImportArchiveHeader(header, tarfile, path); err != nil {
@@ -144,24 +142,24 @@ func ImportArchiveHeader(header *tar.Header, reader io.Reader, path string) erro
 	switch header.Typeflag {
 	case tar.TypeDir:
 		if err := os.MkdirAll(filename, 0755); err != nil {
-			glog.Errorf("Could not create directory at %s: %s", filename, err)
+			plog.Errorf("Could not create directory at %s: %s", filename, err)
 			return err
 		}
 	case tar.TypeSymlink:
 		if err := os.Symlink(header.Linkname, filename); err != nil {
-			glog.Errorf("Could not create symlink at %s: %s", filename, err)
+			plog.Errorf("Could not create symlink at %s: %s", filename, err)
 			return err
 		}
 	case tar.TypeReg:
 		err := func() error {
 			writer, err := os.Create(filename)
 			if err != nil {
-				glog.Errorf("Could not create file at %s: %s", filename, err)
+				plog.Errorf("Could not create file at %s: %s", filename, err)
 				return err
 			}
 			defer writer.Close()
 			if _, err := io.Copy(writer, reader); err != nil {
-				glog.Errorf("Could not copy file %s: %s", filename, err)
+				plog.Errorf("Could not copy file %s: %s", filename, err)
 				return err
 			}
 			return nil
@@ -170,14 +168,14 @@ func ImportArchiveHeader(header *tar.Header, reader io.Reader, path string) erro
 			return err
 		}
 	default:
-		glog.Errorf("Found unxepected file type %b: will not import %s", header.Typeflag, filename)
+		plog.Errorf("Found unxepected file type %b: will not import %s", header.Typeflag, filename)
 		return nil
 	}
 	if err := os.Chown(filename, header.Uid, header.Gid); err != nil {
-		glog.Warningf("Could not change file ownership for %s: %s", filename, err)
+		plog.Warningf("Could not change file ownership for %s: %s", filename, err)
 	}
 	if err := os.Chmod(filename, header.FileInfo().Mode()); err != nil {
-		glog.Warningf("Could not set permissions for file %s: %s", filename, err)
+		plog.Warningf("Could not set permissions for file %s: %s", filename, err)
 	}
 	return nil
 }
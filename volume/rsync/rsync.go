@@ -31,9 +31,12 @@ import (
 
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/volume"
-	"github.com/zenoss/glog"
 )
 
+// BandwidthLimit is the storage option that limits the kilobytes per second
+// copied by snapshots, e.g. rsync.bwlimit=10240
+const BandwidthLimit = "rsync.bwlimit"
+
 var (
 	ErrDeletingVolume    = errors.New("could not delete volume")
 	ErrRsyncInvalidLabel = errors.New("invalid label")
@@ -43,7 +46,8 @@ var (
 // RsyncDriver is a driver for the rsync volume
 type RsyncDriver struct {
 	sync.Mutex
-	root string
+	root    string
+	bwlimit int // kilobytes per second copied by snapshots; 0 for no limit
 }
 
 // RsyncVolume is an rsync volume
@@ -68,10 +72,13 @@ func init() {
 }
 
 // Rsync driver intialization
-func Init(root string, _ []string) (volume.Driver, error) {
+func Init(root string, args []string) (volume.Driver, error) {
 	driver := &RsyncDriver{
 		root: root,
 	}
+	if err := driver.parseOptions(args); err != nil {
+		return nil, err
+	}
 	if err := os.MkdirAll(driver.MetadataDir(), 0755); err != nil && !os.IsExist(err) {
 		return nil, err
 	}
@@ -81,6 +88,22 @@ func Init(root string, _ []string) (volume.Driver, error) {
 	return driver, nil
 }
 
+// parseOptions sets the storage options of the driver
+func (d *RsyncDriver) parseOptions(args []string) error {
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] != BandwidthLimit {
+			continue
+		}
+		bwlimit, err := strconv.Atoi(parts[1])
+		if err != nil || bwlimit < 0 {
+			return fmt.Errorf("invalid value for %s: %s", BandwidthLimit, parts[1])
+		}
+		d.bwlimit = bwlimit
+	}
+	return nil
+}
+
 // Root implements volume.Driver.Root
 func (d *RsyncDriver) Root() string {
 	return d.root
@@ -131,7 +154,7 @@ func (d *RsyncDriver) Remove(volumeName string) error {
 	if err := os.RemoveAll(filepath.Join(d.MetadataDir(), volumeName)); err != nil {
 		return err
 	} else if err := os.RemoveAll(v.Path()); err != nil {
-		glog.Errorf("Could not delete volume %s: %s", volumeName, err)
+		plog.Errorf("Could not delete volume %s: %s", volumeName, err)
 		return ErrDeletingVolume
 	}
 	return nil
@@ -149,10 +172,10 @@ func (d *RsyncDriver) MetadataDir() string {
 // runcmd runs the command
 func runcmd(args ...string) ([]byte, error) {
 	cmd := append([]string{}, args...)
-	glog.V(4).Infof("Executing: %v", cmd)
+	plog.Debugf("Executing: %v", cmd)
 	output, err := exec.Command(cmd[0], cmd[1:]...).CombinedOutput()
 	if err != nil {
-		glog.Errorf("unable to run cmd:%s  output:%s  error:%s", cmd, string(output), err)
+		plog.Errorf("unable to run cmd:%s  output:%s  error:%s", cmd, string(output), err)
 		return output, ErrRsyncDfCommand
 	}
 	return output, err
@@ -176,7 +199,7 @@ func parseDFCommand(volname string, bytes []byte) ([]volume.Usage, error) {
 		}
 		fields := strings.Fields(line)
 		if len(fields) != 4 {
-			glog.Errorf("Error parsing DF output (%s). Expected %d fields, but got %d.", line, 4, len(fields))
+			plog.Errorf("Error parsing DF output (%s). Expected %d fields, but got %d.", line, 4, len(fields))
 			return nil, ErrRsyncDfCommand
 		}
 		label := fmt.Sprintf("%s on %s", volname, string(fields[0]))
@@ -190,7 +213,7 @@ func parseDFCommand(volname string, bytes []byte) ([]volume.Usage, error) {
 func appendIfConvertSucceeds(result []volume.Usage, field string, label string, usage string) []volume.Usage {
 	totalBytes, err := makeUint64(field)
 	if err != nil {
-		glog.Warningf("could not convert string %s to Uint64 for %s", field, usage)
+		plog.Warningf("could not convert string %s to Uint64 for %s", field, usage)
 	} else {
 		result = append(result, volume.Usage{Label: label, Type: usage, Value: totalBytes})
 	}
@@ -199,10 +222,10 @@ func appendIfConvertSucceeds(result []volume.Usage, field string, label string,
 
 // Status implements volume.Driver.Status
 func (d *RsyncDriver) Status() (volume.Status, error) {
-	glog.V(2).Info("rsync.Status()")
+	plog.Debug("rsync.Status()")
 
 	outBytes, err := runcmd("df", "--output=source,size,used,avail", "-B1", d.root)
-	glog.V(2).Infof("Result of running df command: (%q,%q)", outBytes, err)
+	plog.Debugf("Result of running df command: (%q,%q)", outBytes, err)
 
 	dfResult, err2 := parseDFCommand(d.root, outBytes)
 	if err2 != nil {
@@ -258,7 +281,7 @@ func (d *RsyncDriver) Release(volumeName string) error {
 // List implements volume.Driver.List
 func (d *RsyncDriver) List() (result []string) {
 	if files, err := ioutil.ReadDir(d.MetadataDir()); err != nil {
-		glog.Errorf("Error trying to read from root directory: %s", d.root)
+		plog.Errorf("Error trying to read from root directory: %s", d.root)
 		return
 	} else {
 		for _, fi := range files {
@@ -310,7 +333,7 @@ func (v *RsyncVolume) WriteMetadata(label, name string) (io.WriteCloser, error)
 	label = v.rawSnapshotLabel(label)
 	filePath := filepath.Join(v.driver.MetadataDir(), label, name)
 	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil && !os.IsExist(err) {
-		glog.Errorf("Could not create path for file %s: %s", name, err)
+		plog.Errorf("Could not create path for file %s: %s", name, err)
 		return nil, err
 	}
 	return os.Create(filePath)
@@ -373,13 +396,13 @@ func (v *RsyncVolume) isInvalidSnapshot(rawLabel string) bool {
 func (v *RsyncVolume) writeSnapshotInfo(label string, info *volume.SnapshotInfo) error {
 	writer, err := v.WriteMetadata(label, ".SNAPSHOTINFO")
 	if err != nil {
-		glog.Errorf("Could not write meta info for snapshot %s: %s", label, err)
+		plog.Errorf("Could not write meta info for snapshot %s: %s", label, err)
 		return err
 	}
 	defer writer.Close()
 	encoder := json.NewEncoder(writer)
 	if err := encoder.Encode(info); err != nil {
-		glog.Errorf("Could not export meta info for snapshot %s: %s", label, err)
+		plog.Errorf("Could not export meta info for snapshot %s: %s", label, err)
 		return err
 	}
 	return nil
@@ -393,14 +416,14 @@ func (v *RsyncVolume) SnapshotInfo(label string) (*volume.SnapshotInfo, error) {
 
 	reader, err := v.ReadMetadata(label, ".SNAPSHOTINFO")
 	if err != nil {
-		glog.Errorf("Could not get info for snapshot %s: %s", label, err)
+		plog.Errorf("Could not get info for snapshot %s: %s", label, err)
 		return nil, err
 	}
 	defer reader.Close()
 	decoder := json.NewDecoder(reader)
 	var info volume.SnapshotInfo
 	if err := decoder.Decode(&info); err != nil {
-		glog.Errorf("Could not decode snapshot info for %s: %s", label, err)
+		plog.Errorf("Could not decode snapshot info for %s: %s", label, err)
 		return nil, err
 	}
 	return &info, err
@@ -415,7 +438,7 @@ func (v *RsyncVolume) Snapshot(label, message string, tags []string) (err error)
 	dest := v.snapshotPath(label)
 	if exists, err := volume.IsDir(dest); exists || err != nil {
 		if exists {
-			glog.Errorf("Snapshot exists: %s", v.rawSnapshotLabel(label))
+			plog.Errorf("Snapshot exists: %s", v.rawSnapshotLabel(label))
 			return volume.ErrSnapshotExists
 		}
 		return err
@@ -424,10 +447,10 @@ func (v *RsyncVolume) Snapshot(label, message string, tags []string) (err error)
 	for _, tagName := range tags {
 		if tagInfo, err := v.getSnapshotWithTag(tagName, false); err != volume.ErrSnapshotDoesNotExist {
 			if err != nil {
-				glog.Errorf("Could not look up snapshot for tag %s: %s", tagName, err)
+				plog.Errorf("Could not look up snapshot for tag %s: %s", tagName, err)
 				return err
 			} else {
-				glog.Errorf("Tag '%s' is already in use by snapshot %s", tagName, tagInfo.Name)
+				plog.Errorf("Tag '%s' is already in use by snapshot %s", tagName, tagInfo.Name)
 				return volume.ErrTagAlreadyExists
 			}
 		}
@@ -449,7 +472,11 @@ func (v *RsyncVolume) Snapshot(label, message string, tags []string) (err error)
 		return err
 	}
 	argv := []string{"-a", v.Path() + "/", dest + "/"}
-	glog.Infof("Performing snapshot rsync command: %s %s", exe, argv)
+	if v.driver.bwlimit > 0 {
+		// keep the copy from starving the io of running services
+		argv = append([]string{fmt.Sprintf("--bwlimit=%d", v.driver.bwlimit)}, argv...)
+	}
+	plog.Infof("Performing snapshot rsync command: %s %s", exe, argv)
 	if err := os.MkdirAll(filepath.Join(v.driver.MetadataDir(), label), 0755); err != nil && !os.IsExist(err) {
 		return err
 	}
@@ -464,7 +491,7 @@ func (v *RsyncVolume) Snapshot(label, message string, tags []string) (err error)
 
 		select {
 		case <-time.After(v.timeout):
-			glog.V(2).Infof("Received signal to kill rsync")
+			plog.Debugf("Received signal to kill rsync")
 			rsync.Process.Kill()
 			<-done
 		case <-done:
@@ -473,16 +500,16 @@ func (v *RsyncVolume) Snapshot(label, message string, tags []string) (err error)
 			return nil
 		}
 		if exitStatus, ok := utils.GetExitStatus(err); !ok || exitStatus != 24 {
-			glog.Errorf("Could not perform rsync: %s", string(output))
+			plog.Errorf("Could not perform rsync: %s", string(output))
 			return err
 		}
-		glog.Infof("trying snapshot again: %s", label)
+		plog.Infof("trying snapshot again: %s", label)
 	}
 	if exitStatus, _ := utils.GetExitStatus(err); exitStatus == 24 {
-		glog.Warningf("snapshot completed with errors: Partial transfer due to vanished source files")
+		plog.Warningf("snapshot completed with errors: Partial transfer due to vanished source files")
 		return nil
 	}
-	glog.Errorf("Could not perform rsync: %s", string(output))
+	plog.Errorf("Could not perform rsync: %s", string(output))
 	return err
 }
 
@@ -493,23 +520,23 @@ func (v *RsyncVolume) TagSnapshot(label, tagName string) error {
 	// get the snapshot
 	info, err := v.SnapshotInfo(label)
 	if err != nil {
-		glog.Errorf("Could not look up snapshot %s: %s", label, err)
+		plog.Errorf("Could not look up snapshot %s: %s", label, err)
 		return err
 	}
 	// verify the tag doesn't already exist
 	if tagInfo, err := v.getSnapshotWithTag(tagName, false); err != volume.ErrSnapshotDoesNotExist {
 		if err != nil {
-			glog.Errorf("Could not look up snapshot for tag %s: %s", tagName, err)
+			plog.Errorf("Could not look up snapshot for tag %s: %s", tagName, err)
 			return err
 		} else {
-			glog.Errorf("Tag '%s' is already in use by snapshot %s", tagName, tagInfo.Name)
+			plog.Errorf("Tag '%s' is already in use by snapshot %s", tagName, tagInfo.Name)
 			return volume.ErrTagAlreadyExists
 		}
 	}
 	// add the tag and update the snapshot
 	info.Tags = append(info.Tags, tagName)
 	if err := v.writeSnapshotInfo(info.Label, info); err != nil {
-		glog.Errorf("Could not update tags for snapshot %s: %s", info.Label, err)
+		plog.Errorf("Could not update tags for snapshot %s: %s", info.Label, err)
 		return err
 	}
 	return nil
@@ -522,7 +549,7 @@ func (v *RsyncVolume) UntagSnapshot(tagName string) (string, error) {
 	// find the snapshot with the provided tag
 	info, err := v.getSnapshotWithTag(tagName, false)
 	if err != nil {
-		glog.Errorf("Could not find snapshot with tag %s: %s", tagName, err)
+		plog.Errorf("Could not find snapshot with tag %s: %s", tagName, err)
 		return "", err
 	}
 	// remove the tag and update the snapshot
@@ -534,7 +561,7 @@ func (v *RsyncVolume) UntagSnapshot(tagName string) (string, error) {
 	}
 	info.Tags = tags
 	if err := v.writeSnapshotInfo(info.Label, info); err != nil {
-		glog.Errorf("Could not remove tag '%s' from snapshot %s: %s", tagName, info.Name, err)
+		plog.Errorf("Could not remove tag '%s' from snapshot %s: %s", tagName, info.Name, err)
 		return "", err
 	}
 	return info.Label, err
@@ -547,7 +574,7 @@ func (v *RsyncVolume) GetSnapshotWithTag(tagName string) (*volume.SnapshotInfo,
 
 // Snapshots implements volume.Volume.Snapshots
 func (v *RsyncVolume) Snapshots() ([]string, error) {
-	glog.Infof("Snapshots()")
+	plog.Infof("Snapshots()")
 	v.Lock()
 	defer v.Unlock()
 	return v.getSnapshotList()
@@ -565,14 +592,14 @@ func (v *RsyncVolume) getSnapshotWithTag(tagName string, lock bool) (*volume.Sna
 	}
 
 	if err != nil {
-		glog.Errorf("Could not get current snapshot list: %s", err)
+		plog.Errorf("Could not get current snapshot list: %s", err)
 		return nil, err
 	}
 	// Get info for each snapshot and return if a matching tag is found
 	for _, snapshotLabel := range snapshotLabels {
 		if info, err := v.SnapshotInfo(snapshotLabel); err != volume.ErrInvalidSnapshot {
 			if err != nil {
-				glog.Errorf("Could not get info for snaphot %s: %s", snapshotLabel, err)
+				plog.Errorf("Could not get info for snaphot %s: %s", snapshotLabel, err)
 				return nil, err
 			}
 			for _, tag := range info.Tags {
@@ -595,7 +622,7 @@ func (v *RsyncVolume) getSnapshotList() ([]string, error) {
 	for _, file := range files {
 		fh, err := os.Stat(v.snapshotPath(file.Name()))
 		if err != nil {
-			glog.Info(err)
+			plog.Info(err)
 			continue
 		}
 		if file.IsDir() && v.isSnapshot(file.Name()) && fh.IsDir() {
@@ -618,7 +645,7 @@ func (v *RsyncVolume) RemoveSnapshot(label string) error {
 	if err := os.RemoveAll(filepath.Join(v.driver.MetadataDir(), label)); err != nil {
 		return err
 	} else if err := os.RemoveAll(dest); err != nil {
-		glog.Errorf("Could not remove snapshot %s: %s", label, err)
+		plog.Errorf("Could not remove snapshot %s: %s", label, err)
 		return volume.ErrRemovingSnapshot
 	}
 	return nil
@@ -640,9 +667,9 @@ func (v *RsyncVolume) Rollback(label string) (err error) {
 		return err
 	}
 	rsync := exec.Command("rsync", "-a", "--del", "--force", src+"/", v.Path()+"/")
-	glog.V(0).Infof("About to execute: %s", rsync)
+	plog.Infof("About to execute: %s", rsync)
 	if output, err := rsync.CombinedOutput(); err != nil {
-		glog.V(0).Infof("Could not perform rsync: %s", string(output))
+		plog.Infof("Could not perform rsync: %s", string(output))
 		return err
 	}
 	return nil
@@ -651,12 +678,12 @@ func (v *RsyncVolume) Rollback(label string) (err error) {
 // Export implements volume.Volume.Export
 func (v *RsyncVolume) Export(label, parent string, writer io.Writer, excludes []string) error {
 	if len(excludes) > 0 {
-		glog.Warning("rsync backups do not support excluding directories")
+		plog.Warning("rsync backups do not support excluding directories")
 	}
 	v.Lock()
 	defer v.Unlock()
 	if label = strings.TrimSpace(label); label == "" {
-		glog.Errorf("%s: label cannot be empty", volume.DriverTypeRsync)
+		plog.Errorf("%s: label cannot be empty", volume.DriverTypeRsync)
 		return ErrRsyncInvalidLabel
 	}
 	label = v.rawSnapshotLabel(label)
@@ -665,11 +692,11 @@ func (v *RsyncVolume) Export(label, parent string, writer io.Writer, excludes []
 	// Set the driver type
 	header := &tar.Header{Name: fmt.Sprintf("%s-driver", label), Size: int64(len([]byte(v.Driver().DriverType())))}
 	if err := tarfile.WriteHeader(header); err != nil {
-		glog.Errorf("Could not export driver type header: %s", err)
+		plog.Errorf("Could not export driver type header: %s", err)
 		return err
 	}
 	if _, err := fmt.Fprint(tarfile, v.Driver().DriverType()); err != nil {
-		glog.Errorf("Could not export driver type: %s", err)
+		plog.Errorf("Could not export driver type: %s", err)
 		return err
 	}
 	// write metadata
@@ -705,7 +732,7 @@ func (v *RsyncVolume) Import(label string, reader io.Reader) error {
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			glog.Errorf("Could not import archive: %s", err)
+			plog.Errorf("Could not import archive: %s", err)
 			return err
 		}
 		if header.Name == driverfile {
@@ -70,3 +70,15 @@ func TestParseDF(t *testing.T) {
 		assert.Equal(t, result, tc.out, fmt.Sprintf("%s: %s", tc.label, tc.outmsg))
 	}
 }
+
+func TestParseOptions(t *testing.T) {
+	d := &RsyncDriver{}
+	err := d.parseOptions([]string{"other.option=1", BandwidthLimit + "=10240"})
+	assert.Nil(t, err)
+	assert.Equal(t, 10240, d.bwlimit)
+
+	d = &RsyncDriver{}
+	err = d.parseOptions([]string{BandwidthLimit + "=fast"})
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, d.bwlimit)
+}
@@ -20,7 +20,6 @@ import (
 	"text/tabwriter"
 
 	"github.com/docker/go-units"
-	"github.com/zenoss/glog"
 )
 
 type Status interface {
@@ -67,7 +66,7 @@ func GetStatus() *Statuses {
 	for path, driver := range *driverMap {
 		status, err := driver.Status()
 		if err != nil {
-			glog.Warningf("Error getting driver status for path %s: %v", path, err)
+			plog.Warningf("Error getting driver status for path %s: %v", path, err)
 		}
 		if status != nil {
 			if driver.DriverType() == DriverTypeDeviceMapper {
@@ -76,7 +75,7 @@ func GetStatus() *Statuses {
 				result.SimpleStatusMap[path] = status.(*SimpleStatus)
 			}
 		} else {
-			glog.Warningf("nil status returned for path %s", path)
+			plog.Warningf("nil status returned for path %s", path)
 		}
 	}
 	return result
@@ -117,6 +116,20 @@ type TenantStorageStats struct {
 
 	NumberSnapshots         int
 	SnapshotAllocatedBlocks uint64
+
+	// Blocks allocated to the device that the filesystem no longer uses,
+	// which the thin pool reclaims when the filesystem is trimmed
+	ReclaimableBlocks uint64
+	Snapshots         []SnapshotStorageStats
+}
+
+// SnapshotStorageStats is the space used by a snapshot of an application.
+// The blocks allocated to a snapshot may be shared with the application and
+// its other snapshots, so they are not all freed when it is removed.
+type SnapshotStorageStats struct {
+	Label                 string
+	DeviceTotalBlocks     uint64 // logical size of the snapshot device
+	DeviceAllocatedBlocks uint64 // blocks of the thin pool mapped by the snapshot device
 }
 
 type DeviceMapperStatus struct {
@@ -160,6 +173,11 @@ Data (total/used/avail):	{{bytes .PoolDataTotal}}	/ {{bytes .PoolDataUsed}}	({{p
 Volume Mount Point:	{{.VolumePath}}
 Filesystem (total/used/avail):	{{bytes .FilesystemTotal}} / {{bytes .FilesystemUsed}}	({{percent .FilesystemUsed .FilesystemTotal}}) / {{bytes .FilesystemAvailable}}	({{percent .FilesystemAvailable .FilesystemTotal}})
 Virtual device size:	{{blocksToBytes .DeviceTotalBlocks}}
+Virtual device allocated:	{{blocksToBytes .DeviceAllocatedBlocks}}	({{percent .DeviceAllocatedBlocks .DeviceTotalBlocks}})
+Reclaimable by fstrim:	{{blocksToBytes .ReclaimableBlocks}}
+Snapshots:	{{.NumberSnapshots}}
+{{range .Snapshots}}Snapshot {{.Label}} (size/allocated):	{{blocksToBytes .DeviceTotalBlocks}} / {{blocksToBytes .DeviceAllocatedBlocks}}
+{{end -}}
 {{range .Errors}}
 {{.}}
 {{end -}}
@@ -0,0 +1,96 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package volume
+
+import (
+	"io"
+	"time"
+)
+
+// rateLimiter paces io to a number of bytes per second
+type rateLimiter struct {
+	rate  int64
+	start time.Time
+	total int64
+}
+
+// chunk returns the most bytes that should be transferred at once
+func (l *rateLimiter) chunk(n int) int {
+	if int64(n) > l.rate {
+		return int(l.rate)
+	}
+	return n
+}
+
+// wait records n transferred bytes and sleeps until the transfer is back
+// under the rate
+func (l *rateLimiter) wait(n int) {
+	if l.start.IsZero() {
+		l.start = time.Now()
+	}
+	l.total += int64(n)
+	due := l.start.Add(time.Duration(l.total * int64(time.Second) / l.rate))
+	if d := due.Sub(time.Now()); d > 0 {
+		time.Sleep(d)
+	}
+}
+
+type limitedWriter struct {
+	w io.Writer
+	rateLimiter
+}
+
+// NewLimitedWriter returns a writer that writes to w at no more than
+// bytesPerSecond.  w is returned if bytesPerSecond is not positive.
+func NewLimitedWriter(w io.Writer, bytesPerSecond int64) io.Writer {
+	if bytesPerSecond <= 0 {
+		return w
+	}
+	return &limitedWriter{w: w, rateLimiter: rateLimiter{rate: bytesPerSecond}}
+}
+
+// Write implements io.Writer
+func (w *limitedWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		m, err := w.w.Write(p[:w.chunk(len(p))])
+		n += m
+		w.wait(m)
+		if err != nil {
+			return n, err
+		}
+		p = p[m:]
+	}
+	return n, nil
+}
+
+type limitedReader struct {
+	r io.Reader
+	rateLimiter
+}
+
+// NewLimitedReader returns a reader that reads from r at no more than
+// bytesPerSecond.  r is returned if bytesPerSecond is not positive.
+func NewLimitedReader(r io.Reader, bytesPerSecond int64) io.Reader {
+	if bytesPerSecond <= 0 {
+		return r
+	}
+	return &limitedReader{r: r, rateLimiter: rateLimiter{rate: bytesPerSecond}}
+}
+
+// Read implements io.Reader
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p[:r.chunk(len(p))])
+	r.wait(n)
+	return n, err
+}
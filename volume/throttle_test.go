@@ -0,0 +1,50 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package volume_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"time"
+
+	. "github.com/control-center/serviced/volume"
+	. "gopkg.in/check.v1"
+)
+
+func (s *UtilsSuite) TestLimitedWriter(c *C) {
+	buf := &bytes.Buffer{}
+	c.Assert(NewLimitedWriter(buf, 0), Equals, buf)
+
+	w := NewLimitedWriter(buf, 100*1024)
+	start := time.Now()
+	n, err := w.Write(make([]byte, 50*1024))
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, 50*1024)
+	c.Assert(buf.Len(), Equals, 50*1024)
+	c.Assert(time.Since(start) >= 400*time.Millisecond, Equals, true)
+}
+
+func (s *UtilsSuite) TestLimitedReader(c *C) {
+	buf := bytes.NewBuffer(make([]byte, 50*1024))
+	c.Assert(NewLimitedReader(buf, 0), Equals, buf)
+
+	r := NewLimitedReader(buf, 100*1024)
+	start := time.Now()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, IsNil)
+	c.Assert(len(data), Equals, 50*1024)
+	c.Assert(time.Since(start) >= 400*time.Millisecond, Equals, true)
+}
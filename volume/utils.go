@@ -26,7 +26,6 @@ import (
 	"syscall"
 
 	"github.com/docker/go-units"
-	"github.com/zenoss/glog"
 )
 
 var (
@@ -82,7 +81,7 @@ func (p FileInfoSlice) Labels() []string {
 func IsRoot() bool {
 	user, err := user.Current()
 	if err != nil {
-		glog.Errorf("Unable to determine current user: %s", err)
+		plog.Errorf("Unable to determine current user: %s", err)
 		return false
 	}
 	return user.Uid == "0"
@@ -102,10 +101,10 @@ func RunBtrFSCmd(sudoer bool, args ...string) ([]byte, error) {
 	if sudoer {
 		cmd = append([]string{"sudo", "-n"}, cmd...)
 	}
-	glog.V(4).Infof("Executing: %v", cmd)
+	plog.Debugf("Executing: %v", cmd)
 	output, err := exec.Command(cmd[0], cmd[1:]...).CombinedOutput()
 	if err != nil {
-		glog.V(1).Infof("unable to run cmd:%s  output:%s  error:%s", cmd, string(output), err)
+		plog.Debugf("unable to run cmd:%s  output:%s  error:%s", cmd, string(output), err)
 		return output, ErrBtrfsCommand
 	}
 	return output, err
@@ -152,6 +151,9 @@ func ToBytes(from uint64) string {
 func BlocksToBytes(blocks uint64) string {
 	return ToBytes(blockSize * blocks)
 }
+func BlocksToByteCount(blocks uint64) uint64 {
+	return blockSize * blocks
+}
 func Percent(amt, total uint64) string {
 	return fmt.Sprintf("%.2g%%", 100*(float64(amt)/float64(total)))
 }
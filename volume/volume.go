@@ -20,8 +20,6 @@ import (
 	"path"
 	"path/filepath"
 	"time"
-
-	"github.com/zenoss/glog"
 )
 
 // DriverInit represents a function that can initialize a driver.
@@ -200,7 +198,7 @@ func InitDriver(name DriverType, root string, args []string) error {
 					return err
 				}
 			} else if t != name {
-				glog.Errorf("Unable to initialize %s driver. Path %s has an existing %s volume driver.", name, root, t)
+				plog.Errorf("Unable to initialize %s driver. Path %s has an existing %s volume driver.", name, root, t)
 				return ErrDriverAlreadyInit
 			}
 		}
@@ -250,7 +248,7 @@ func SplitPath(volumePath string) (string, string, error) {
 		} else {
 			// get the name of the volume
 			if volumeName, err := filepath.Rel(rootDir, volumePath); err != nil {
-				glog.Errorf("Unexpected error while looking up relpath of %s from %s: %s", volumePath, rootDir, err)
+				plog.Errorf("Unexpected error while looking up relpath of %s from %s: %s", volumePath, rootDir, err)
 				return "", "", err
 			} else {
 				return rootDir, volumeName, nil
@@ -277,28 +275,28 @@ func Mount(volumeName, rootDir string) (volume Volume, err error) {
 	if rDir, vName, err := SplitPath(filepath.Join(rootDir, volumeName)); err != nil {
 		return nil, err
 	} else if rDir != rootDir {
-		glog.Errorf("Cannot mount volume at %s; found root at %s", rootDir, rDir)
+		plog.Errorf("Cannot mount volume at %s; found root at %s", rootDir, rDir)
 		return nil, ErrBadMount
 	} else if vName == "" {
-		glog.Errorf("Volume '%s' at %s is a driver", volumeName, rootDir)
+		plog.Errorf("Volume '%s' at %s is a driver", volumeName, rootDir)
 		return nil, ErrPathIsDriver
 	}
-	glog.V(1).Infof("Mounting volume %s via %s", volumeName, rootDir)
+	plog.Debugf("Mounting volume %s via %s", volumeName, rootDir)
 	driver, err := GetDriver(rootDir)
 	if err != nil {
-		glog.Errorf("Could not get driver from root %s: %s", rootDir, err)
+		plog.Errorf("Could not get driver from root %s: %s", rootDir, err)
 		return nil, err
 	}
-	glog.V(2).Infof("Got %s driver for %s", driver.DriverType(), driver.Root())
+	plog.Debugf("Got %s driver for %s", driver.DriverType(), driver.Root())
 	if driver.Exists(volumeName) {
-		glog.V(2).Infof("Volume %s exists; remounting", volumeName)
+		plog.Debugf("Volume %s exists; remounting", volumeName)
 		volume, err = driver.Get(volumeName)
 	} else {
-		glog.V(2).Infof("Volume %s does not exist; creating", volumeName)
+		plog.Debugf("Volume %s does not exist; creating", volumeName)
 		volume, err = driver.Create(volumeName)
 	}
 	if err != nil {
-		glog.Errorf("Error mounting volume: %s", err)
+		plog.Errorf("Error mounting volume: %s", err)
 		return nil, err
 	}
 	return volume, nil
@@ -308,12 +306,12 @@ func Mount(volumeName, rootDir string) (volume Volume, err error) {
 func ShutdownDriver(rootDir string) error {
 	driver, ok := driversByRoot[rootDir]
 	if !ok {
-		glog.Errorf("Tried to shut down uninitialized driver: %s", rootDir)
+		plog.Errorf("Tried to shut down uninitialized driver: %s", rootDir)
 		return ErrDriverNotInit
 	}
-	glog.V(2).Infof("Shutting down %s driver for %s", driver.DriverType(), driver.Root())
+	plog.Debugf("Shutting down %s driver for %s", driver.DriverType(), driver.Root())
 	if err := driver.Cleanup(); err != nil {
-		glog.Errorf("Unable to clean up %s driver for %s: %s", driver.DriverType(), driver.Root(), err)
+		plog.Errorf("Unable to clean up %s driver for %s: %s", driver.DriverType(), driver.Root(), err)
 		return err
 	}
 	delete(driversByRoot, rootDir)
@@ -337,7 +335,7 @@ func ShutdownAll() error {
 // getDrivers retrieves the driver for each volumeName passed in.
 // if volumeNames is empty, the function returns all drivers, with their roots.
 func getDrivers() *map[string]Driver {
-	glog.V(2).Infof("getDrivers(): returning driversByRoot(%q)", driversByRoot)
+	plog.Debugf("getDrivers(): returning driversByRoot(%q)", driversByRoot)
 	return &driversByRoot
 }
 
@@ -0,0 +1,65 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/control-center/serviced/domain/backup"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestGetBackupsShouldReturnCatalog(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/api/v2/backups", "")
+	expected := []backup.Backup{
+		{
+			ID:        "backup-2016-08-01-120000.tgz",
+			Location:  "/opt/serviced/var/backups/backup-2016-08-01-120000.tgz",
+			Size:      1024,
+			Checksum:  "abc123",
+			StartedAt: time.Date(2016, 8, 1, 12, 0, 0, 0, time.UTC),
+			Duration:  time.Minute,
+			Templates: []string{"Zenoss.core"},
+			Available: true,
+		},
+	}
+
+	s.mockFacade.
+		On("GetBackups", s.ctx.getDatastoreContext()).
+		Return(expected, nil)
+
+	getBackups(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	var actual []backup.Backup
+	c.Assert(json.Unmarshal(s.recorder.Body.Bytes(), &actual), IsNil)
+	c.Assert(actual, DeepEquals, expected)
+}
+
+func (s *TestWebSuite) TestGetBackupsShouldReturnInternalServerError(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/api/v2/backups", "")
+
+	s.mockFacade.
+		On("GetBackups", s.ctx.getDatastoreContext()).
+		Return(nil, errors.New("elastic is down"))
+
+	getBackups(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusInternalServerError)
+}
@@ -0,0 +1,54 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/storage"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestGetDFSStatusShouldReturnFreezeStatus(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/api/v2/dfs/status", "")
+	expected := storage.FreezeStatus{Frozen: true, Reason: "maintenance", Since: time.Date(2016, 5, 4, 10, 30, 0, 0, time.UTC)}
+
+	s.mockFacade.
+		On("GetDFSFreezeStatus", s.ctx.getDatastoreContext()).
+		Return(&expected, nil)
+
+	getDFSStatus(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	var actual storage.FreezeStatus
+	c.Assert(json.Unmarshal(s.recorder.Body.Bytes(), &actual), IsNil)
+	c.Assert(actual, DeepEquals, expected)
+}
+
+func (s *TestWebSuite) TestGetDFSStatusShouldReturnInternalServerError(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/api/v2/dfs/status", "")
+
+	s.mockFacade.
+		On("GetDFSFreezeStatus", s.ctx.getDatastoreContext()).
+		Return(nil, errors.New("zookeeper is down"))
+
+	getDFSStatus(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusInternalServerError)
+}
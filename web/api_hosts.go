@@ -32,7 +32,7 @@ func getHosts(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 		return
 	}
 
-	w.WriteJson(hosts)
+	writeJSONIfModified(w, r, readHostsETag(hosts), hosts)
 }
 
 // getHostsForPool returns the list of hosts for a pool.
@@ -55,7 +55,7 @@ func getHostsForPool(w *rest.ResponseWriter, r *rest.Request, ctx *requestContex
 		return
 	}
 
-	w.WriteJson(hosts)
+	writeJSONIfModified(w, r, readHostsETag(hosts), hosts)
 }
 
 // getHostStatus return status information for hosts.  This includes the memory usage and
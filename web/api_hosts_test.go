@@ -17,9 +17,11 @@ package web
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"time"
 
 	"github.com/control-center/serviced/domain/host"
+	"github.com/zenoss/go-json-rest"
 	. "gopkg.in/check.v1"
 )
 
@@ -76,6 +78,40 @@ func (s *TestWebSuite) TestGetHostsShouldReturnStatusOK(c *C) {
 	c.Assert(s.recorder.Code, Equals, http.StatusOK)
 }
 
+func (s *TestWebSuite) TestGetHostsShouldReturnNotModifiedForMatchingETag(c *C) {
+	hosts := []host.ReadHost{apiHostsTestData.firstHost, apiHostsTestData.secondHost}
+	s.mockFacade.
+		On("GetReadHosts", s.ctx.getDatastoreContext()).
+		Return(hosts, nil)
+
+	request := s.buildRequest("GET", "http://www.example.com/hosts", "")
+	getHosts(&(s.writer), &request, s.ctx)
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	etag := s.recorder.Header().Get("ETag")
+	c.Assert(etag, Equals, readHostsETag(hosts))
+	c.Assert(etag, Not(Equals), "")
+
+	s.recorder = httptest.NewRecorder()
+	s.writer = rest.NewResponseWriter(s.recorder, false)
+	request = s.buildRequest("GET", "http://www.example.com/hosts", "")
+	request.Header.Set("If-None-Match", etag)
+	getHosts(&(s.writer), &request, s.ctx)
+	c.Assert(s.recorder.Code, Equals, http.StatusNotModified)
+	c.Assert(s.recorder.Body.Len(), Equals, 0)
+}
+
+func (s *TestWebSuite) TestReadHostsETagChangesWithUpdates(c *C) {
+	hosts := []host.ReadHost{apiHostsTestData.firstHost, apiHostsTestData.secondHost}
+	etag := readHostsETag(hosts)
+
+	hosts[1].UpdatedAt = hosts[1].UpdatedAt.Add(time.Second)
+	c.Assert(readHostsETag(hosts), Not(Equals), etag)
+	c.Assert(readHostsETag(hosts[:1]), Not(Equals), etag)
+
+	hosts[1].UpdatedAt = time.Time{}
+	c.Assert(readHostsETag(hosts), Equals, "")
+}
+
 func (s *TestWebSuite) TestGetHostsForPoolShouldReturnBadRequestForInvalidPoolId(c *C) {
 	request := s.buildRequest("GET", "http://www.example.com/pools/inv%ZZlid/hosts", "")
 	request.PathParams["poolId"] = "inv%ZZlid"
@@ -39,7 +39,7 @@ func getAllServiceDetails(w *rest.ResponseWriter, r *rest.Request, c *requestCon
 		return
 	}
 
-	w.WriteJson(details)
+	writeJSONIfModified(w, r, serviceDetailsETag(details), details)
 }
 
 func getServiceDetails(w *rest.ResponseWriter, r *rest.Request, c *requestContext) {
@@ -195,3 +195,68 @@ func putServiceContext(w *rest.ResponseWriter, r *rest.Request, c *requestContex
 
 	writeJSON(w, "Service Context Updated.", http.StatusOK)
 }
+
+func getServiceTags(w *rest.ResponseWriter, r *rest.Request, c *requestContext) {
+	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
+	if err != nil {
+		writeJSON(w, err, http.StatusBadRequest)
+		return
+	} else if len(serviceID) == 0 {
+		writeJSON(w, "serviceId must be specified", http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.getDatastoreContext()
+
+	svc, err := c.getFacade().GetService(ctx, serviceID)
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	tags := svc.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	w.WriteJson(tags)
+}
+
+func putServiceTags(w *rest.ResponseWriter, r *rest.Request, c *requestContext) {
+	ctx := c.getDatastoreContext()
+	f := c.getFacade()
+
+	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
+	if err != nil {
+		writeJSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	var payload []string
+	err = r.DecodeJsonPayload(&payload)
+	if err != nil {
+		writeJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	for _, tag := range payload {
+		if err := service.ValidTag(tag); err != nil {
+			writeJSON(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	svc, e := f.GetService(ctx, serviceID)
+	if e != nil {
+		restServerError(w, e)
+		return
+	}
+
+	svc.Tags = payload
+
+	err = f.UpdateService(ctx, *svc)
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	writeJSON(w, "Service Tags Updated.", http.StatusOK)
+}
@@ -18,8 +18,11 @@ package web
 import (
 	"net/http"
 
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
 	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
 )
@@ -216,6 +219,47 @@ func (s *TestWebSuite) TestRestGetAllServiceDetailsShouldReturnStatusOK(c *C) {
 	c.Assert(s.recorder.Code, Equals, http.StatusOK)
 }
 
+func (s *TestWebSuite) TestRestGetAllServiceDetailsShouldReturnNotModifiedForMatchingETag(c *C) {
+	details := []service.ServiceDetails{
+		serviceDetailsTestData.firstService,
+		serviceDetailsTestData.tenant,
+	}
+	details[0].DatabaseVersion = 3
+	details[1].DatabaseVersion = 1
+	etag := serviceDetailsETag(details)
+	c.Assert(etag, Not(Equals), "")
+
+	s.mockFacade.
+		On("GetAllServiceDetails", s.ctx.getDatastoreContext()).
+		Return(details, nil)
+
+	request := s.buildRequest("GET", "http://www.example.com/services", "")
+	request.Header.Set("If-None-Match", `"stale", `+etag)
+	getAllServiceDetails(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusNotModified)
+	c.Assert(s.recorder.Header().Get("ETag"), Equals, etag)
+}
+
+func (s *TestWebSuite) TestServiceDetailsETag(c *C) {
+	details := []service.ServiceDetails{
+		serviceDetailsTestData.firstService,
+		serviceDetailsTestData.tenant,
+	}
+	c.Assert(serviceDetailsETag(details), Equals, "")
+
+	details[0].DatabaseVersion = 3
+	details[1].DatabaseVersion = 1
+	etag := serviceDetailsETag(details)
+
+	details[0].DatabaseVersion = 4
+	c.Assert(serviceDetailsETag(details), Not(Equals), etag)
+
+	details[0].DatabaseVersion = 3
+	details[1].HasChildren = !details[1].HasChildren
+	c.Assert(serviceDetailsETag(details), Not(Equals), etag)
+}
+
 func (s *TestWebSuite) TestRestGetAllServiceDetailsShouldOnlyReturnTenants(c *C) {
 	request := s.buildRequest("GET", "http://www.example.com/services?tenants", "")
 
@@ -232,3 +276,156 @@ func (s *TestWebSuite) TestRestGetAllServiceDetailsShouldOnlyReturnTenants(c *C)
 
 	c.Assert(response[0].ID, Equals, "tenant")
 }
+
+func (s *TestWebSuite) TestRestGetAllServiceDetailsShouldScopeToSessionUser(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/services", "")
+	s.ctx.user = "alice"
+
+	s.mockFacade.
+		On("GetAllServiceDetails", mock.MatchedBy(func(ctx datastore.Context) bool {
+			return datastore.GetActor(ctx).User == "alice"
+		})).
+		Return([]service.ServiceDetails{}, nil)
+
+	getAllServiceDetails(&(s.writer), &request, s.ctx)
+
+	response := []service.ServiceDetails{}
+	s.getResult(c, &response)
+	c.Assert(response, HasLen, 0)
+}
+
+func (s *TestWebSuite) TestRestTenantScopedShouldReturnStatusForbidden(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/services/firstservice", "")
+	request.PathParams["serviceId"] = "firstservice"
+	s.ctx.user = "alice"
+
+	s.mockFacade.
+		On("CheckServiceAccess", s.ctx.getDatastoreContext(), "firstservice").
+		Return(facade.ErrTenantAccessDenied)
+
+	tenantScoped(getServiceDetails)(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusForbidden)
+	s.mockFacade.AssertNotCalled(c, "GetServiceDetails", s.ctx.getDatastoreContext(), "firstservice")
+}
+
+func (s *TestWebSuite) TestRestTenantScopedShouldScopeTokenRequests(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/services/firstservice", "")
+	request.PathParams["serviceId"] = "firstservice"
+	s.ctx.token = true
+	s.ctx.tenant = "tenant"
+
+	actor := datastore.GetActor(s.ctx.getDatastoreContext())
+	c.Assert(actor.Tenant, Equals, "tenant")
+	c.Assert(actor.User, Equals, "")
+
+	s.mockFacade.
+		On("CheckServiceAccess", s.ctx.getDatastoreContext(), "firstservice").
+		Return(facade.ErrTenantAccessDenied)
+
+	tenantScoped(getServiceDetails)(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusForbidden)
+}
+
+func (s *TestWebSuite) TestRestKillRunningShouldReturnStatusForbidden(c *C) {
+	request := s.buildRequest("DELETE", "http://www.example.com/hosts/host/host-firstservice-0", "")
+	request.PathParams["hostId"] = "host"
+	request.PathParams["serviceStateId"] = "host-firstservice-0"
+	s.ctx.token = true
+	s.ctx.tenant = "othertenant"
+
+	s.mockFacade.
+		On("CheckServiceAccess", s.ctx.getDatastoreContext(), "firstservice").
+		Return(facade.ErrTenantAccessDenied)
+
+	restKillRunning(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusForbidden)
+	s.mockFacade.AssertNotCalled(c, "StopServiceInstance", s.ctx.getDatastoreContext(), "firstservice", 0)
+}
+
+func (s *TestWebSuite) TestRestKillRunningShouldStopInstance(c *C) {
+	request := s.buildRequest("DELETE", "http://www.example.com/hosts/host/host-firstservice-0", "")
+	request.PathParams["hostId"] = "host"
+	request.PathParams["serviceStateId"] = "host-firstservice-0"
+	s.ctx.user = "alice"
+
+	s.mockFacade.
+		On("CheckServiceAccess", s.ctx.getDatastoreContext(), "firstservice").
+		Return(nil)
+	s.mockFacade.
+		On("StopServiceInstance", s.ctx.getDatastoreContext(), "firstservice", 0).
+		Return(nil)
+
+	restKillRunning(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	s.mockFacade.AssertCalled(c, "StopServiceInstance", s.ctx.getDatastoreContext(), "firstservice", 0)
+}
+
+func (s *TestWebSuite) TestIdentifyShouldRejectBadRestToken(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/top/services", "")
+	auth.AddRestTokenToRequest(request.Request, "notatoken")
+
+	ctx := newRequestContext(nil)
+	c.Assert(ctx.identify(&request), NotNil)
+	c.Assert(ctx.token, Equals, true)
+}
+
+func (s *TestWebSuite) TestRestDeployServiceShouldReturnStatusForbidden(c *C) {
+	request := s.buildRequest("POST", "http://www.example.com/services/deploy", `{"PoolID": "pool", "ParentID": "othertenant"}`)
+	s.ctx.user = "alice"
+
+	s.mockFacade.
+		On("DeployService", s.ctx.getDatastoreContext(), "pool", "othertenant", false, mock.AnythingOfType("servicedefinition.ServiceDefinition")).
+		Return("", facade.ErrTenantAccessDenied)
+
+	restDeployService(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusForbidden)
+}
+
+func (s *TestWebSuite) TestRestPutServiceTagsShouldSetTags(c *C) {
+	request := s.buildRequest("PUT", "http://www.example.com/services/1a2b3c/tags", `["env=staging", "team=db"]`)
+	request.PathParams["serviceId"] = "1a2b3c"
+
+	s.mockFacade.
+		On("GetService", s.ctx.getDatastoreContext(), "1a2b3c").
+		Return(&service.Service{Name: "Service", Tags: []string{"old"}}, nil)
+
+	s.mockFacade.
+		On("UpdateService", s.ctx.getDatastoreContext(), mock.AnythingOfType("service.Service")).
+		Return(nil)
+
+	putServiceTags(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	svc := s.mockFacade.Calls[1].Arguments.Get(1).(service.Service)
+	c.Assert(svc.Tags, DeepEquals, []string{"env=staging", "team=db"})
+}
+
+func (s *TestWebSuite) TestRestPutServiceTagsShouldRejectInvalidTags(c *C) {
+	request := s.buildRequest("PUT", "http://www.example.com/services/1a2b3c/tags", `["env=staging,team=db"]`)
+	request.PathParams["serviceId"] = "1a2b3c"
+
+	putServiceTags(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusBadRequest)
+	s.mockFacade.AssertNotCalled(c, "UpdateService", s.ctx.getDatastoreContext(), mock.AnythingOfType("service.Service"))
+}
+
+func (s *TestWebSuite) TestRestGetServiceTagsShouldReturnTags(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/services/1a2b3c/tags", "")
+	request.PathParams["serviceId"] = "1a2b3c"
+
+	s.mockFacade.
+		On("GetService", s.ctx.getDatastoreContext(), "1a2b3c").
+		Return(&service.Service{Name: "Service"}, nil)
+
+	getServiceTags(&(s.writer), &request, s.ctx)
+
+	response := []string{}
+	s.getResult(c, &response)
+	c.Assert(response, HasLen, 0)
+}
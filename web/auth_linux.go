@@ -19,7 +19,6 @@ package web
 import "C"
 import (
 	"github.com/msteinert/pam"
-	"github.com/zenoss/glog"
 
 	"errors"
 	"fmt"
@@ -44,7 +43,7 @@ func isGroupMember(username, group string) bool {
 	var cgroup = C.CString(group)
 	defer C.free(unsafe.Pointer(cgroup))
 	result := C.isGroupMember(cuser, cgroup)
-	glog.V(2).Infof("C.isGroupMember(%s, %s) returned %d.\n", username, group, result)
+	plog.Debugf("C.isGroupMember(%s, %s) returned %d.\n", username, group, result)
 	return (result != 0)
 }
 
@@ -58,13 +57,13 @@ func makePamConvHandler(creds *login) func(pam.Style, string) (string, error) {
 		case pam.PromptEchoOff:
 			return creds.Password, nil
 		case pam.PromptEchoOn:
-			glog.V(1).Infof("PAM Prompt: %s\n", msg)
+			plog.Debugf("PAM Prompt: %s\n", msg)
 			return creds.Username, nil
 		case pam.ErrorMsg:
-			glog.Errorf("PAM ERROR: %s\n", msg)
+			plog.Errorf("PAM ERROR: %s\n", msg)
 			return "", nil
 		case pam.TextInfo:
-			glog.V(1).Infof("PAM MESSAGE: %s\n", msg)
+			plog.Debugf("PAM MESSAGE: %s\n", msg)
 			return "", nil
 		}
 		return "", errors.New("Unrecognized message style")
@@ -74,17 +73,17 @@ func makePamConvHandler(creds *login) func(pam.Style, string) (string, error) {
 func pamValidateLoginOnly(creds *login, group string) bool {
 	t, err := pam.StartFunc("sudo", "", makePamConvHandler(creds))
 	if err != nil {
-		glog.Errorf("Start: %s", err.Error())
+		plog.Errorf("Start: %s", err.Error())
 		return false
 	}
 	err = t.Authenticate(0)
 	if err != nil {
-		glog.Errorf("Authentication failed for user %s: Authenticate error: %s", creds.Username, err.Error())
+		plog.Errorf("Authentication failed for user %s: Authenticate error: %s", creds.Username, err.Error())
 		return false
 	}
 	err = t.AcctMgmt(pam.Silent)
 	if err != nil {
-		glog.Errorf("Authentication failed for user %s: AcctMgmt error: %s", creds.Username, err.Error())
+		plog.Errorf("Authentication failed for user %s: AcctMgmt error: %s", creds.Username, err.Error())
 		return false
 	}
 
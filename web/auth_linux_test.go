@@ -17,7 +17,6 @@ package web
 
 import (
 	"errors"
-	"github.com/zenoss/glog"
 	"os"
 	"os/exec"
 	"os/user"
@@ -95,18 +94,18 @@ var testCases = []testCase{
 
 func TestMain(m *testing.M) {
 	if 0 != os.Geteuid() {
-		glog.Errorf("Must be root to run integration tests. Exiting (no tests run).")
+		plog.Errorf("Must be root to run integration tests. Exiting (no tests run).")
 		os.Exit(1)
 	}
 	err := createTestUsers()
 	if err != nil {
-		glog.Errorf("Error creating test user: %s\n", err)
+		plog.Errorf("Error creating test user: %s\n", err)
 	}
-	glog.Infof("Running tests - some errors expected from negative tests.\n")
+	plog.Infof("Running tests - some errors expected from negative tests.\n")
 	result := m.Run()
-	glog.Infof("Removing test users\n")
+	plog.Infof("Removing test users\n")
 	removeTestUsers()
-	glog.Infof("Exiting\n")
+	plog.Infof("Exiting\n")
 	os.Exit(result)
 }
 
@@ -114,10 +113,10 @@ func createTestUsers() error {
 	for name, u := range testUsers {
 		err := createTestUser(name, &u)
 		if err != nil {
-			glog.Errorf("Error creating user %s: %s\n", name, err)
+			plog.Errorf("Error creating user %s: %s\n", name, err)
 			return err
 		}
-		glog.V(2).Infof("Created user %s. user = %v\n", name, u)
+		plog.Debugf("Created user %s. user = %v\n", name, u)
 	}
 	return nil
 }
@@ -141,10 +140,10 @@ func createTestUser(name string, userobj *testUser) error {
 		args = append(args, "-e", "1970-01-01")
 	}
 	command := exec.Command(cmdName, args...)
-	glog.V(2).Infof("Creating test user %s: command is %v\n", testUserName, command)
+	plog.Debugf("Creating test user %s: command is %v\n", testUserName, command)
 	output, cmderr := command.CombinedOutput()
 	if nil != cmderr {
-		glog.Errorf("Error creating testuser: %s\n%s\n", cmderr, output)
+		plog.Errorf("Error creating testuser: %s\n%s\n", cmderr, output)
 	} else {
 		createdUsers[name] = *userobj
 	}
@@ -155,27 +154,27 @@ func removeTestUsers() {
 	for _, user := range createdUsers {
 		err := removeTestUser(user.username)
 		if err != nil {
-			glog.Errorf("Error deleting user %s: %s\n", user.username, err)
+			plog.Errorf("Error deleting user %s: %s\n", user.username, err)
 		} else {
-			glog.Infof("Successfully removed user %s\n", user.username)
+			plog.Infof("Successfully removed user %s\n", user.username)
 		}
 	}
 }
 
 func removeTestUser(testUserName string) error {
-	glog.V(2).Infof("removeTestUser(%s) invoked.\n", testUserName)
+	plog.Debugf("removeTestUser(%s) invoked.\n", testUserName)
 	command := exec.Command("sudo", "userdel", testUserName)
-	glog.V(2).Infof("Deleting test user %s\n", testUserName)
+	plog.Debugf("Deleting test user %s\n", testUserName)
 
 	output, cmderr := command.CombinedOutput()
 	if nil != cmderr {
-		glog.Errorf("Error deleting test user %s: %s\n%s\n", testUserName, cmderr, output)
+		plog.Errorf("Error deleting test user %s: %s\n%s\n", testUserName, cmderr, output)
 	}
 	return cmderr
 }
 
 func TestCrypt(t *testing.T) {
-	glog.V(2).Infof("TestCrypt()\n")
+	plog.Debugf("TestCrypt()\n")
 	cryptResult := crypt(testUserPassword, testUserSalt)
 	if cryptResult != testUserPasswordEncrypted {
 		t.Fatal("crypt() function validation failed.")
@@ -183,7 +182,7 @@ func TestCrypt(t *testing.T) {
 }
 
 func TestAuthentication(t *testing.T) {
-	glog.V(2).Infof("TestAuthentication()")
+	plog.Debugf("TestAuthentication()")
 	for _, tc := range testCases {
 		user := tc.user
 		creds := login{Username: user.username, Password: tc.testPassword}
@@ -15,11 +15,7 @@
 
 package web
 
-import (
-	"github.com/zenoss/glog"
-)
-
 func pamValidateLogin(_ *login, _ string) bool {
-	glog.Errorf("pamValidateLogin is not supported on this platform")
+	plog.Errorf("pamValidateLogin is not supported on this platform")
 	return false
 }
@@ -0,0 +1,106 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	corsAllowMethods = "GET, POST, PUT, DELETE, HEAD, OPTIONS"
+	corsAllowHeaders = "Accept, Authorization, Content-Type, X-Requested-With"
+	corsMaxAge       = "600"
+)
+
+// ValidCORSOrigin returns an error if origin is neither "*" nor an http or
+// https origin of the form scheme://host[:port].
+func ValidCORSOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("cors origin %s must use http or https", origin)
+	} else if u.Host == "" {
+		return fmt.Errorf("cors origin %s has no host", origin)
+	} else if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("cors origin %s must not have a path", origin)
+	}
+	return nil
+}
+
+// corsPolicy decides which cross-origin requests may read the responses of
+// the api.
+type corsPolicy struct {
+	any     bool
+	origins map[string]struct{}
+}
+
+func newCORSPolicy(origins []string) *corsPolicy {
+	policy := &corsPolicy{origins: make(map[string]struct{})}
+	for _, origin := range origins {
+		if origin == "*" {
+			policy.any = true
+		} else {
+			policy.origins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = struct{}{}
+		}
+	}
+	return policy
+}
+
+// enabled returns true if any origin is allowed
+func (p *corsPolicy) enabled() bool {
+	return p.any || len(p.origins) > 0
+}
+
+// allowed returns true if requests from origin may read the response
+func (p *corsPolicy) allowed(origin string) bool {
+	if origin == "" {
+		return false
+	} else if p.any {
+		return true
+	}
+	_, ok := p.origins[strings.ToLower(origin)]
+	return ok
+}
+
+// handle sets the cors headers of the response and returns true if the
+// request was a preflight request that has been answered.  Credentials are
+// allowed, so the origin is echoed back even when any origin is allowed.
+func (p *corsPolicy) handle(w http.ResponseWriter, r *http.Request) bool {
+	if !p.enabled() {
+		return false
+	}
+	w.Header().Add("Vary", "Origin")
+	origin := r.Header.Get("Origin")
+	if !p.allowed(origin) {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
+		w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+		w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+		w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zenoss/go-json-rest"
+)
+
+func TestValidCORSOrigin(t *testing.T) {
+	for _, origin := range []string{"*", "https://dashboard.example.com", "http://10.0.0.1:8080", "https://dashboard.example.com/"} {
+		if err := ValidCORSOrigin(origin); err != nil {
+			t.Errorf("expected %s to be valid, got %s", origin, err)
+		}
+	}
+	for _, origin := range []string{"dashboard.example.com", "ftp://dashboard.example.com", "https://", "https://dashboard.example.com/path"} {
+		if err := ValidCORSOrigin(origin); err == nil {
+			t.Errorf("expected %s to be invalid", origin)
+		}
+	}
+}
+
+func TestCORSDisabled(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/api/v2/pools", nil)
+	r.Header.Set("Origin", "https://dashboard.example.com")
+	w := httptest.NewRecorder()
+	if newCORSPolicy(nil).handle(w, r) {
+		t.Error("request was answered when cors is disabled")
+	}
+	if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != "" {
+		t.Errorf("expected no allowed origin, got %s", origin)
+	}
+}
+
+func TestCORSAllowedOrigin(t *testing.T) {
+	policy := newCORSPolicy([]string{"https://Dashboard.example.com/"})
+	r, _ := http.NewRequest("GET", "/api/v2/pools", nil)
+	r.Header.Set("Origin", "https://dashboard.example.com")
+	w := httptest.NewRecorder()
+	if policy.handle(w, r) {
+		t.Error("simple request was answered by the cors policy")
+	}
+	if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != "https://dashboard.example.com" {
+		t.Errorf("expected allowed origin https://dashboard.example.com, got %s", origin)
+	}
+	if creds := w.Header().Get("Access-Control-Allow-Credentials"); creds != "true" {
+		t.Errorf("expected credentials to be allowed, got %s", creds)
+	}
+
+	r.Header.Set("Origin", "https://evil.example.com")
+	w = httptest.NewRecorder()
+	policy.handle(w, r)
+	if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != "" {
+		t.Errorf("expected no allowed origin, got %s", origin)
+	}
+	if vary := w.Header().Get("Vary"); vary != "Origin" {
+		t.Errorf("expected response to vary by origin, got %s", vary)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	policy := newCORSPolicy([]string{"*"})
+	r, _ := http.NewRequest("OPTIONS", "/api/v2/services/abc", nil)
+	r.Header.Set("Origin", "https://dashboard.example.com")
+	r.Header.Set("Access-Control-Request-Method", "PUT")
+	w := httptest.NewRecorder()
+	if !policy.handle(w, r) {
+		t.Fatal("preflight request was not answered")
+	}
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != "https://dashboard.example.com" {
+		t.Errorf("expected allowed origin https://dashboard.example.com, got %s", origin)
+	}
+	if methods := w.Header().Get("Access-Control-Allow-Methods"); methods != corsAllowMethods {
+		t.Errorf("expected allowed methods %s, got %s", corsAllowMethods, methods)
+	}
+}
+
+func hasRoute(routes []rest.Route, method, path string) bool {
+	for _, route := range routes {
+		if route.HttpMethod == method && route.PathExp == path {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHeadlessRoutes(t *testing.T) {
+	sc := &ServiceConfig{cors: newCORSPolicy(nil), compress: true}
+	routes := sc.getRoutes()
+	if !hasRoute(routes, "GET", "/static/*resource") || !hasRoute(routes, "GET", "/") {
+		t.Error("expected the ui to serve static assets")
+	}
+
+	sc.headless = true
+	routes = sc.getRoutes()
+	for _, path := range []string{"/", "/favicon.ico", "/static/*resource", "/licenses.html", "/metrics/static"} {
+		if hasRoute(routes, "GET", path) {
+			t.Errorf("expected headless ui not to serve %s", path)
+		}
+	}
+	if !hasRoute(routes, "GET", "/api/v2/services") {
+		t.Error("expected headless ui to serve the json api")
+	}
+}
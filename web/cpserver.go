@@ -26,6 +26,7 @@ import (
 
 	daoclient "github.com/control-center/serviced/dao/client"
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/statehistory"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/node"
 	"github.com/control-center/serviced/rpc/master"
@@ -33,7 +34,6 @@ import (
 	"github.com/control-center/serviced/zzk"
 	"github.com/control-center/serviced/zzk/registry"
 	"github.com/gorilla/mux"
-	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 )
 
@@ -57,6 +57,10 @@ type ServiceConfig struct {
 	uiConfig    UIConfig
 	facade      facade.FacadeInterface
 	vhostmgr    *VHostManager
+	cors        *corsPolicy
+	compress    bool
+	headless    bool
+	probes      *probeRegistry
 }
 
 var defaultHostAlias string
@@ -81,11 +85,14 @@ func NewServiceConfig(bindPort string, agentPort string, stats bool, hostaliases
 		keyPEMFile:  keyPEMFile,
 		uiConfig:    uiCfg,
 		facade:      facade,
+		cors:        newCORSPolicy(nil),
+		compress:    true,
+		probes:      newProbeRegistry(),
 	}
 
 	hostAddrs, err := utils.GetIPv4Addresses()
 	if err != nil {
-		glog.Fatal(err)
+		plog.Fatal(err)
 	}
 	cfg.localAddrs = make(map[string]struct{})
 	for _, host := range hostAddrs {
@@ -99,12 +106,28 @@ func NewServiceConfig(bindPort string, agentPort string, stats bool, hostaliases
 	return &cfg
 }
 
+// SetCORSOrigins allows cross-origin requests from the given origins, so that
+// external dashboards can consume the api.  "*" allows any origin.
+func (sc *ServiceConfig) SetCORSOrigins(origins []string) {
+	sc.cors = newCORSPolicy(origins)
+}
+
+// SetCompression enables or disables the gzip compression of responses
+func (sc *ServiceConfig) SetCompression(compress bool) {
+	sc.compress = compress
+}
+
+// SetHeadless serves only the json api, without the static assets of the ui
+func (sc *ServiceConfig) SetHeadless(headless bool) {
+	sc.headless = headless
+}
+
 // Serve handles control center web UI requests and virtual host requests for zenoss web based services.
 // The UI server actually listens on port 7878, the uihandler defined here just reverse proxies to it.
 // Virtual host routing to zenoss web based services is done by the publicendpointhandler function.
 func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 
-	glog.V(1).Infof("starting vhost synching")
+	plog.Debugf("starting vhost synching")
 
 	// start public port listener
 	sc.startPublicPortListener(shutdown)
@@ -117,7 +140,7 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 
 	accessLogFile, err := os.OpenFile("/var/log/serviced.access.log", os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
 	if err != nil {
-		glog.Errorf("Could not create access log file.")
+		plog.Errorf("Could not create access log file.")
 	}
 
 	uiHandler := rest.ResourceHandler{
@@ -129,24 +152,27 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 	uiHandler.SetRoutes(routes...)
 
 	httphandler := func(w http.ResponseWriter, r *http.Request) {
-		glog.V(2).Infof("httphandler handling request: %+v", r)
+		plog.Debugf("httphandler handling request: %+v", r)
 
 		httphost := strings.Split(r.Host, ":")[0]
 		parts := strings.Split(httphost, ".")
 		subdomain := parts[0]
-		glog.V(2).Infof("httphost: '%s'  subdomain: '%s'", httphost, subdomain)
+		plog.Debugf("httphost: '%s'  subdomain: '%s'", httphost, subdomain)
 		if len(parts) > 1 {
 			if sc.vhostmgr.Handle(subdomain, w, r) {
 				return
 			}
 		}
 
-		glog.V(2).Infof("httphost: calling uiHandler")
+		plog.Debugf("httphost: calling uiHandler")
 		if r.TLS == nil {
 			// bindPort has already been validated, so the Split/access below won't break.
 			http.Redirect(w, r, fmt.Sprintf("https://%s:%s", r.Host, strings.Split(sc.bindPort, ":")[1]), http.StatusMovedPermanently)
 			return
 		}
+		if sc.cors.handle(w, r) {
+			return
+		}
 		uiHandler.ServeHTTP(w, r)
 	}
 
@@ -179,7 +205,7 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 		}
 		err := http.ListenAndServe(":80", http.HandlerFunc(redirect))
 		if err != nil {
-			glog.Errorf("could not setup HTTP webserver: %s", err)
+			plog.Errorf("could not setup HTTP webserver: %s", err)
 		}
 	}()
 	go func() {
@@ -192,10 +218,10 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 			CipherSuites:             utils.CipherSuites("http"),
 		}
 		server := &http.Server{Addr: sc.bindPort, TLSConfig: config}
-		glog.Infof("Creating HTTP server on port %s with CipherSuite: %v", sc.bindPort, utils.CipherSuitesByName(config))
+		plog.Infof("Creating HTTP server on port %s with CipherSuite: %v", sc.bindPort, utils.CipherSuitesByName(config))
 		err := server.ListenAndServeTLS(certFile, keyFile)
 		if err != nil {
-			glog.Fatalf("could not setup HTTPS webserver: %s", err)
+			plog.Fatalf("could not setup HTTPS webserver: %s", err)
 		}
 	}()
 	blockerChan := make(chan bool)
@@ -207,7 +233,7 @@ var methods = []string{"GET", "POST", "PUT", "DELETE", "HEAD"}
 func routeToInternalServiceProxy(path string, target string, requiresAuth bool, routes []rest.Route) []rest.Route {
 	targetURL, err := url.Parse(target)
 	if err != nil {
-		glog.Errorf("Unable to parse proxy target URL: %s", target)
+		plog.Errorf("Unable to parse proxy target URL: %s", target)
 		return routes
 	}
 	// Wrap the normal http.Handler in a rest.handlerFunc
@@ -233,7 +259,7 @@ func (sc *ServiceConfig) unAuthorizedClient(realfunc handlerClientFunc) handlerF
 	return func(w *rest.ResponseWriter, r *rest.Request) {
 		client, err := sc.getClient()
 		if err != nil {
-			glog.Errorf("Unable to acquire client: %v", err)
+			plog.Errorf("Unable to acquire client: %v", err)
 			restServerError(w, err)
 			return
 		}
@@ -250,7 +276,7 @@ func (sc *ServiceConfig) authorizedClient(realfunc handlerClientFunc) handlerFun
 		}
 		client, err := sc.getClient()
 		if err != nil {
-			glog.Errorf("Unable to acquire client: %v", err)
+			plog.Errorf("Unable to acquire client: %v", err)
 			restServerError(w, err)
 			return
 		}
@@ -273,19 +299,19 @@ func (sc *ServiceConfig) isCollectingStats() handlerFunc {
 func (sc *ServiceConfig) getClient() (c *daoclient.ControlClient, err error) {
 	// setup the client
 	if c, err = daoclient.NewControlClient(sc.agentPort); err != nil {
-		glog.Errorf("Could not create a control center client: %s", err)
+		plog.Errorf("Could not create a control center client: %s", err)
 	}
 	return
 }
 
 func (sc *ServiceConfig) getMasterClient() (master.ClientInterface, error) {
-	glog.V(2).Infof("start getMasterClient ... sc.agentPort: %+v", sc.agentPort)
+	plog.Debugf("start getMasterClient ... sc.agentPort: %+v", sc.agentPort)
 	c, err := master.NewClient(sc.agentPort)
 	if err != nil {
-		glog.Errorf("Could not create a control center client to %v: %v", sc.agentPort, err)
+		plog.Errorf("Could not create a control center client to %v: %v", sc.agentPort, err)
 		return nil, err
 	}
-	glog.V(2).Info("end getMasterClient")
+	plog.Debug("end getMasterClient")
 	return c, nil
 }
 
@@ -295,6 +321,10 @@ func (sc *ServiceConfig) newRequestHandler(check checkFunc, realfunc ctxhandlerF
 			return
 		}
 		reqCtx := newRequestContext(sc)
+		if err := reqCtx.identify(r); err != nil {
+			restUnauthorized(w)
+			return
+		}
 		defer reqCtx.end()
 		realfunc(w, r, reqCtx)
 	}
@@ -322,6 +352,9 @@ type requestContext struct {
 	sc      *ServiceConfig
 	master  master.ClientInterface
 	dataCtx datastore.Context
+	user    string // the session user; empty for token authenticated requests
+	token   bool   // true if the request was authenticated with a rest token
+	tenant  string // the tenant that the rest token was issued to
 }
 
 func newRequestContext(sc *ServiceConfig) *requestContext {
@@ -332,7 +365,7 @@ func (ctx *requestContext) getMasterClient() (master.ClientInterface, error) {
 	if ctx.master == nil {
 		c, err := ctx.sc.getMasterClient()
 		if err != nil {
-			glog.Errorf("Could not create a control center client: %v", err)
+			plog.Errorf("Could not create a control center client: %v", err)
 			return nil, err
 		}
 		ctx.master = c
@@ -344,10 +377,31 @@ func (ctx *requestContext) getFacade() facade.FacadeInterface {
 	return ctx.sc.facade
 }
 
+// identify records who made the request, so that the facade can limit the
+// request to the tenants that the session user or the rest token may access.
+// Returns an error if the rest token of the request cannot be parsed, so that
+// the request is not made without a scope.
+func (ctx *requestContext) identify(r *rest.Request) error {
+	var err error
+	if ctx.tenant, ctx.token, err = restTokenTenant(r); err != nil {
+		plog.WithError(err).WithField("url", r.URL.String()).Debug("Unable to identify rest token")
+		return err
+	}
+	if !ctx.token {
+		ctx.user = sessionUser(r)
+	}
+	return nil
+}
+
 func (ctx *requestContext) getDatastoreContext() datastore.Context {
-	//here in case we ever need to create a per request datastore context
 	if ctx.dataCtx == nil {
-		ctx.dataCtx = datastore.Get()
+		dataCtx := datastore.GetTraced()
+		if ctx.token {
+			dataCtx = datastore.WithTenantActor(dataCtx, statehistory.SourceAPI, ctx.tenant)
+		} else if ctx.user != "" {
+			dataCtx = datastore.WithActor(dataCtx, statehistory.SourceUI, ctx.user)
+		}
+		ctx.dataCtx = dataCtx
 	}
 	return ctx.dataCtx
 }
@@ -359,6 +413,51 @@ func (ctx *requestContext) end() error {
 	return nil
 }
 
+// checkTenantAccess writes an error response and returns false if the
+// service in the request path belongs to a tenant that the session user or
+// the rest token may not access.
+func (ctx *requestContext) checkTenantAccess(w *rest.ResponseWriter, r *rest.Request) bool {
+	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
+	if err != nil {
+		writeJSON(w, err, http.StatusBadRequest)
+		return false
+	}
+	err = ctx.getFacade().CheckServiceAccess(ctx.getDatastoreContext(), serviceID)
+	if err == facade.ErrTenantAccessDenied {
+		restForbidden(w)
+		return false
+	} else if err != nil {
+		restServerError(w, err)
+		return false
+	}
+	return true
+}
+
+// tenantScoped restricts a handler to the tenants of the session user.
+func tenantScoped(realfunc ctxhandlerFunc) ctxhandlerFunc {
+	return func(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+		if ctx.checkTenantAccess(w, r) {
+			realfunc(w, r, ctx)
+		}
+	}
+}
+
+// tenantScopedClient restricts a client handler to the tenants of the session
+// user.
+func (sc *ServiceConfig) tenantScopedClient(realfunc handlerClientFunc) handlerClientFunc {
+	return func(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
+		reqCtx := newRequestContext(sc)
+		if err := reqCtx.identify(r); err != nil {
+			restUnauthorized(w)
+			return
+		}
+		defer reqCtx.end()
+		if reqCtx.checkTenantAccess(w, r) {
+			realfunc(w, r, client)
+		}
+	}
+}
+
 type ctxhandlerFunc func(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext)
 type checkFunc func(w *rest.ResponseWriter, r *rest.Request) bool
 
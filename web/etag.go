@@ -0,0 +1,125 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/zenoss/go-json-rest"
+)
+
+// etagBuilder computes a weak etag of a list response from the ids and
+// versions of its entities, so that a polling client does not download a
+// list it already has.
+type etagBuilder struct {
+	hash  hash.Hash
+	valid bool
+}
+
+func newETagBuilder() *etagBuilder {
+	return &etagBuilder{hash: sha1.New(), valid: true}
+}
+
+// add adds an entity to the etag.  An entity without a version disables the
+// etag, because its changes could not be detected.
+func (b *etagBuilder) add(id, version string) {
+	if version == "" {
+		b.valid = false
+		return
+	}
+	fmt.Fprintf(b.hash, "%s\x00%s\x00", id, version)
+}
+
+// String returns the etag, or an empty string if it is disabled
+func (b *etagBuilder) String() string {
+	if !b.valid {
+		return ""
+	}
+	return fmt.Sprintf(`W/"%x"`, b.hash.Sum(nil))
+}
+
+// serviceDetailsETag returns the etag of a list of service details
+func serviceDetailsETag(details []service.ServiceDetails) string {
+	b := newETagBuilder()
+	for _, d := range details {
+		if d.DatabaseVersion == 0 {
+			b.add(d.ID, "")
+		} else {
+			b.add(d.ID, fmt.Sprintf("%d:%t", d.DatabaseVersion, d.HasChildren))
+		}
+	}
+	return b.String()
+}
+
+// hostsETag returns the etag of a list of hosts.  Every update of a host sets
+// its UpdatedAt, which stands in for its version.
+func hostsETag(hosts []host.Host) string {
+	b := newETagBuilder()
+	for _, h := range hosts {
+		b.add(h.ID, timeVersion(h.UpdatedAt))
+	}
+	return b.String()
+}
+
+// readHostsETag returns the etag of a list of read hosts
+func readHostsETag(hosts []host.ReadHost) string {
+	b := newETagBuilder()
+	for _, h := range hosts {
+		b.add(h.ID, timeVersion(h.UpdatedAt))
+	}
+	return b.String()
+}
+
+// timeVersion returns the version of an entity from its update time
+func timeVersion(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// etagMatches returns true if the If-None-Match header matches the etag,
+// using the weak comparison.
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSONIfModified writes v with its etag, or responds 304 Not Modified if
+// the client already has it.
+func writeJSONIfModified(w *rest.ResponseWriter, r *rest.Request, etag string, v interface{}) {
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "no-cache")
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.WriteJson(v)
+}
@@ -26,7 +26,6 @@ import (
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/rpc/agent"
-	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 )
 
@@ -36,12 +35,13 @@ func restGetHosts(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext)
 	dataCtx := ctx.getDatastoreContext()
 	hosts, err := facade.GetHosts(dataCtx)
 	if err != nil {
-		glog.Errorf("Could not get hosts: %v", err)
+		plog.Errorf("Could not get hosts: %v", err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(2).Infof("Returning %d hosts", len(hosts))
+	plog.Debugf("Returning %d hosts", len(hosts))
+	etag := hostsETag(hosts)
 	response := make(map[string]*host.Host)
 	for i, host := range hosts {
 		response[host.ID] = &hosts[i]
@@ -51,7 +51,7 @@ func restGetHosts(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext)
 		}
 	}
 
-	w.WriteJson(&response)
+	writeJSONIfModified(w, r, etag, &response)
 }
 
 func restGetHostInstances(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
@@ -81,12 +81,12 @@ func restGetHostInstances(w *rest.ResponseWriter, r *rest.Request, ctx *requestC
 	dataCtx := ctx.getDatastoreContext()
 	instances, err := facade.GetHostInstances(dataCtx, time.Now().Add(-tsince), hostID)
 	if err != nil {
-		glog.Error("Could not get host instances:", err)
+		plog.Error("Could not get host instances:", err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(4).Infof("restGetHostInstances: id %s, instances %#v", hostID, instances)
+	plog.Debugf("restGetHostInstances: id %s, instances %#v", hostID, instances)
 	w.WriteJson(&instances)
 }
 
@@ -117,12 +117,12 @@ func restGetServiceInstances(w *rest.ResponseWriter, r *rest.Request, ctx *reque
 	dataCtx := ctx.getDatastoreContext()
 	instances, err := facade.GetServiceInstances(dataCtx, time.Now().Add(-tsince), serviceID)
 	if err != nil {
-		glog.Error("Could not get service instances:", err)
+		plog.Error("Could not get service instances:", err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(4).Infof("restGetServiceInstances: id %s, instances %#v", serviceID, instances)
+	plog.Debugf("restGetServiceInstances: id %s, instances %#v", serviceID, instances)
 	w.WriteJson(&instances)
 }
 
@@ -140,7 +140,7 @@ func restGetServiceMonitoringProfile(w *rest.ResponseWriter, r *rest.Request, ct
 	dataCtx := ctx.getDatastoreContext()
 	mp, err := facade.GetServiceMonitoringProfile(dataCtx, serviceID)
 	if err != nil {
-		glog.Errorf("Could not get service monitoring profile: %s", err)
+		plog.Errorf("Could not get service monitoring profile: %s", err)
 		restServerError(w, err)
 		return
 	}
@@ -148,14 +148,14 @@ func restGetServiceMonitoringProfile(w *rest.ResponseWriter, r *rest.Request, ct
 	// load the internal monitoring data
 	config, err := getInternalMetrics()
 	if err != nil {
-		glog.Errorf("Could not get internal monitoring metrics: %s", err)
+		plog.Errorf("Could not get internal monitoring metrics: %s", err)
 		restServerError(w, err)
 		return
 	}
 	mp.MetricConfigs = append(mp.MetricConfigs, *config)
 	mp.GraphConfigs = append(mp.GraphConfigs, getInternalGraphConfigs(serviceID)...)
 
-	glog.V(4).Infof("restGetServiceMonitoringProfile: id %s, monitoring profile %#v", serviceID, mp)
+	plog.Debugf("restGetServiceMonitoringProfile: id %s, monitoring profile %#v", serviceID, mp)
 	w.WriteJson(&mp)
 }
 
@@ -173,12 +173,12 @@ func restGetServiceConfigFiles(w *rest.ResponseWriter, r *rest.Request, ctx *req
 	dataCtx := ctx.getDatastoreContext()
 	files, err := facade.GetServiceConfigs(dataCtx, serviceID)
 	if err != nil {
-		glog.Errorf("Could not get service config files: %s", err)
+		plog.Errorf("Could not get service config files: %s", err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(4).Infof("restGetServiceConfigFiles: id %s, config files: %#v", serviceID, files)
+	plog.Debugf("restGetServiceConfigFiles: id %s, config files: %#v", serviceID, files)
 	w.WriteJson(&files)
 }
 
@@ -196,7 +196,7 @@ func restGetServiceConfigFile(w *rest.ResponseWriter, r *rest.Request, ctx *requ
 	dataCtx := ctx.getDatastoreContext()
 	file, err := facade.GetServiceConfig(dataCtx, fileID)
 	if err != nil {
-		glog.Errorf("Could not get service config file: %s", err)
+		plog.Errorf("Could not get service config file: %s", err)
 		restServerError(w, err)
 		return
 	}
@@ -216,7 +216,7 @@ func restAddServiceConfigFile(w *rest.ResponseWriter, r *rest.Request, ctx *requ
 
 	var file servicedefinition.ConfigFile
 	if err := r.DecodeJsonPayload(&file); err != nil {
-		glog.V(1).Infof("Could not decode service config payload: %v", err)
+		plog.Debugf("Could not decode service config payload: %v", err)
 		restBadRequest(w, err)
 		return
 	}
@@ -224,7 +224,7 @@ func restAddServiceConfigFile(w *rest.ResponseWriter, r *rest.Request, ctx *requ
 	facade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
 	if err := facade.AddServiceConfig(dataCtx, serviceID, file); err != nil {
-		glog.Errorf("Could not add config file: %s", err)
+		plog.Errorf("Could not add config file: %s", err)
 		restServerError(w, err)
 		return
 	}
@@ -245,7 +245,7 @@ func restUpdateServiceConfigFile(w *rest.ResponseWriter, r *rest.Request, ctx *r
 
 	var file servicedefinition.ConfigFile
 	if err := r.DecodeJsonPayload(&file); err != nil {
-		glog.V(1).Infof("Could not decode service config payload: %v", err)
+		plog.Debugf("Could not decode service config payload: %v", err)
 		restBadRequest(w, err)
 		return
 	}
@@ -253,7 +253,7 @@ func restUpdateServiceConfigFile(w *rest.ResponseWriter, r *rest.Request, ctx *r
 	facade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
 	if err := facade.UpdateServiceConfig(dataCtx, fileID, file); err != nil {
-		glog.Errorf("Could not update config file: %s", err)
+		plog.Errorf("Could not update config file: %s", err)
 		restServerError(w, err)
 		return
 	}
@@ -275,7 +275,64 @@ func restDeleteServiceConfigFile(w *rest.ResponseWriter, r *rest.Request, ctx *r
 	facade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
 	if err := facade.DeleteServiceConfig(dataCtx, fileID); err != nil {
-		glog.Errorf("Could not delete config file: %s", err)
+		plog.Errorf("Could not delete config file: %s", err)
+		restServerError(w, err)
+		return
+	}
+
+	restSuccess(w)
+	return
+}
+
+func restGetServiceConfigConflicts(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
+	if err != nil {
+		restBadRequest(w, err)
+		return
+	} else if serviceID == "" {
+		restBadRequest(w, errors.New("serviceID must be specified for GET"))
+		return
+	}
+
+	facade := ctx.getFacade()
+	dataCtx := ctx.getDatastoreContext()
+	conflicts, err := facade.GetServiceConfigConflicts(dataCtx, serviceID)
+	if err != nil {
+		plog.Errorf("Could not get service config conflicts: %s", err)
+		restServerError(w, err)
+		return
+	}
+
+	w.WriteJson(&conflicts)
+}
+
+// configConflictResolution is the payload for resolving a config file conflict
+type configConflictResolution struct {
+	Resolution string // mine, theirs, or merged
+	Content    string // replaces the file, if set
+}
+
+func restResolveServiceConfigConflict(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	fileID, err := url.QueryUnescape(r.PathParam("fileId"))
+	if err != nil {
+		restBadRequest(w, err)
+		return
+	} else if fileID == "" {
+		restBadRequest(w, errors.New("fileID must be specified for PUT"))
+		return
+	}
+
+	var payload configConflictResolution
+	if err := r.DecodeJsonPayload(&payload); err != nil {
+		plog.Debugf("Could not decode config conflict resolution payload: %v", err)
+		restBadRequest(w, err)
+		return
+	}
+
+	facade := ctx.getFacade()
+	dataCtx := ctx.getDatastoreContext()
+	if err := facade.ResolveServiceConfigConflict(dataCtx, fileID, payload.Resolution, payload.Content); err != nil {
+		plog.Errorf("Could not resolve config file conflict: %s", err)
 		restServerError(w, err)
 		return
 	}
@@ -301,12 +358,12 @@ func restGetServicePublicEndpoints(w *rest.ResponseWriter, r *rest.Request, ctx
 	dataCtx := ctx.getDatastoreContext()
 	pubs, err := facade.GetServicePublicEndpoints(dataCtx, serviceID, includeChildren)
 	if err != nil {
-		glog.Errorf("Could not look up public endpoints: %s", err)
+		plog.Errorf("Could not look up public endpoints: %s", err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(4).Infof("restGetServicePublicEndpoints: id %s, publicEndpoints: %#v", serviceID, pubs)
+	plog.Debugf("restGetServicePublicEndpoints: id %s, publicEndpoints: %#v", serviceID, pubs)
 	w.WriteJson(&pubs)
 }
 
@@ -327,12 +384,12 @@ func restGetServiceIPAssignments(w *rest.ResponseWriter, r *rest.Request, ctx *r
 	dataCtx := ctx.getDatastoreContext()
 	addrs, err := facade.GetServiceAddressAssignmentDetails(dataCtx, serviceID, includeChildren)
 	if err != nil {
-		glog.Errorf("Could not look up address assignments: %s", err)
+		plog.Errorf("Could not look up address assignments: %s", err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(4).Infof("restGetServiceAddressAssignments: id %s, addressAssignmentsL %#v", serviceID, addrs)
+	plog.Debugf("restGetServiceAddressAssignments: id %s, addressAssignmentsL %#v", serviceID, addrs)
 	w.WriteJson(&addrs)
 }
 
@@ -353,12 +410,12 @@ func restGetServiceExportedEndpoints(w *rest.ResponseWriter, r *rest.Request, ct
 	dataCtx := ctx.getDatastoreContext()
 	addrs, err := facade.GetServiceExportedEndpoints(dataCtx, serviceID, includeChildren)
 	if err != nil {
-		glog.Errorf("Could not look up address assignments: %s", err)
+		plog.Errorf("Could not look up address assignments: %s", err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(4).Infof("restGetServiceAddressAssignments: id %s, addressAssignmentsL %#v", serviceID, addrs)
+	plog.Debugf("restGetServiceAddressAssignments: id %s, addressAssignmentsL %#v", serviceID, addrs)
 	w.WriteJson(&addrs)
 }
 
@@ -384,12 +441,12 @@ func restGetAggregateServices(w *rest.ResponseWriter, r *rest.Request, ctx *requ
 	dataCtx := ctx.getDatastoreContext()
 	aggServices, err := facade.GetAggregateServices(dataCtx, time.Now().Add(-tsince), serviceIDs)
 	if err != nil {
-		glog.Error("Could not look up aggregate service instances:", err)
+		plog.Error("Could not look up aggregate service instances:", err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(4).Infof("restGetServiceInstances: id %s, instances %#v", serviceIDs, aggServices)
+	plog.Debugf("restGetServiceInstances: id %s, instances %#v", serviceIDs, aggServices)
 	w.WriteJson(&aggServices)
 }
 
@@ -421,7 +478,7 @@ func restGetHost(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	dataCtx := ctx.getDatastoreContext()
 	host, err := facade.GetHost(dataCtx, hostID)
 	if err != nil {
-		glog.Error("Could not get host: ", err)
+		plog.Error("Could not get host: ", err)
 		restServerError(w, err)
 		return
 	}
@@ -431,7 +488,7 @@ func restGetHost(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 		return
 	}
 
-	glog.V(4).Infof("restGetHost: id %s, host %#v", hostID, host)
+	plog.Debugf("restGetHost: id %s, host %#v", hostID, host)
 	w.WriteJson(&host)
 }
 
@@ -455,7 +512,7 @@ func restAddHost(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	var payload addHostRequest
 	err := r.DecodeJsonPayload(&payload)
 	if err != nil {
-		glog.V(1).Infof("Could not decode host payload: %v", err)
+		plog.Debugf("Could not decode host payload: %v", err)
 		restBadRequest(w, err)
 		return
 	}
@@ -463,28 +520,28 @@ func restAddHost(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	parts := strings.Split(ipAddr, ":")
 	hostIPAddr, err := net.ResolveIPAddr("ip", parts[0])
 	if err != nil {
-		glog.Errorf("%s could not be resolved", parts[0])
+		plog.Errorf("%s could not be resolved", parts[0])
 		restBadRequest(w, err)
 		return
 	}
 	hostIP := hostIPAddr.IP.String()
 
 	if len(parts) < 2 {
-		glog.Errorf("rpcport needs to be specified")
+		plog.Errorf("rpcport needs to be specified")
 		restBadRequest(w, err)
 		return
 	}
 
 	rpcPort, err := strconv.Atoi(parts[1])
 	if err != nil {
-		glog.Errorf("could not convert rpcport %s to int", parts[1])
+		plog.Errorf("could not convert rpcport %s to int", parts[1])
 		restBadRequest(w, err)
 		return
 	}
 
 	agentClient, err := agent.NewClient(payload.IPAddr)
 	if err != nil {
-		glog.Errorf("Could not create connection to host %s: %v", payload.IPAddr, err)
+		plog.Errorf("Could not create connection to host %s: %v", payload.IPAddr, err)
 		restServerError(w, err)
 		return
 	}
@@ -497,7 +554,7 @@ func restAddHost(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	}
 	host, err := agentClient.BuildHost(buildRequest)
 	if err != nil {
-		glog.Errorf("Unable to get remote host info: %v", err)
+		plog.Errorf("Unable to get remote host info: %v", err)
 		restBadRequest(w, err)
 		return
 	}
@@ -506,11 +563,11 @@ func restAddHost(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	dataCtx := ctx.getDatastoreContext()
 	privateKey, err := facade.AddHost(dataCtx, host)
 	if err != nil {
-		glog.Errorf("Unable to add host: %v", err)
+		plog.Errorf("Unable to add host: %v", err)
 		restServerError(w, err)
 		return
 	}
-	glog.V(0).Info("Added host ", host.ID)
+	plog.Info("Added host ", host.ID)
 	w.WriteJson(&addHostResponse{simpleResponse{"Added host", hostLinks(host.ID)}, string(privateKey[:])})
 }
 
@@ -524,11 +581,11 @@ func restUpdateHost(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext
 		restBadRequest(w, fmt.Errorf("hostID must be specified for PUT"))
 		return
 	}
-	glog.V(3).Infof("Received update request for %s", hostID)
+	plog.Debugf("Received update request for %s", hostID)
 	var payload host.Host
 	err = r.DecodeJsonPayload(&payload)
 	if err != nil {
-		glog.V(1).Infof("Could not decode host payload: %v", err)
+		plog.Debugf("Could not decode host payload: %v", err)
 		restBadRequest(w, err)
 		return
 	}
@@ -537,11 +594,11 @@ func restUpdateHost(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext
 	dataCtx := ctx.getDatastoreContext()
 	err = facade.UpdateHost(dataCtx, &payload)
 	if err != nil {
-		glog.Errorf("Unable to update host: %v", err)
+		plog.Errorf("Unable to update host: %v", err)
 		restServerError(w, err)
 		return
 	}
-	glog.V(1).Info("Updated host ", hostID)
+	plog.Debug("Updated host ", hostID)
 	w.WriteJson(&simpleResponse{"Updated host", hostLinks(hostID)})
 }
 
@@ -560,11 +617,11 @@ func restRemoveHost(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext
 	dataCtx := ctx.getDatastoreContext()
 	err = facade.RemoveHost(dataCtx, hostID)
 	if err != nil {
-		glog.Errorf("Could not remove host: %v", err)
+		plog.Errorf("Could not remove host: %v", err)
 		restServerError(w, err)
 		return
 	}
-	glog.V(0).Info("Removed host ", hostID)
+	plog.Info("Removed host ", hostID)
 	w.WriteJson(&simpleResponse{"Removed host", hostsLinks()})
 }
 
@@ -583,11 +640,11 @@ func restResetHostKey(w *rest.ResponseWriter, r *rest.Request, ctx *requestConte
 	dataCtx := ctx.getDatastoreContext()
 	key, err := facade.ResetHostKey(dataCtx, hostID)
 	if err != nil {
-		glog.Errorf("Could not reset key for host %s: %v", hostID, err)
+		plog.Errorf("Could not reset key for host %s: %v", hostID, err)
 		restServerError(w, err)
 		return
 	}
-	glog.V(0).Info("Reset host key for ", hostID)
+	plog.Info("Reset host key for ", hostID)
 	w.WriteJson(&addHostResponse{simpleResponse{"Reset host-key", hostLinks(hostID)}, string(key[:])})
 }
 
@@ -595,7 +652,7 @@ func buildHostMonitoringProfile(host *host.Host) error {
 	tags := map[string][]string{"controlplane_host_id": []string{host.ID}}
 	profile, err := hostPoolProfile.ReBuild("1h-ago", tags)
 	if err != nil {
-		glog.Errorf("Failed to create host profile: %s", err)
+		plog.Errorf("Failed to create host profile: %s", err)
 		return err
 	}
 
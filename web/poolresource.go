@@ -7,7 +7,6 @@ import (
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/pool"
-	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 
 	"net/url"
@@ -23,7 +22,7 @@ func restGetPools(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext)
 	dataCtx := ctx.getDatastoreContext()
 	pools, err := facade.GetResourcePools(dataCtx)
 	if err != nil {
-		glog.Error("Could not get resource pools: ", err)
+		plog.Error("Could not get resource pools: ", err)
 		restServerError(w, err)
 		return
 	}
@@ -43,7 +42,7 @@ func restGetPools(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext)
 
 		poolsMap[pool.ID] = &pools[i]
 	}
-	glog.V(4).Infof("restGetPools: pools %#v", poolsMap)
+	plog.Debugf("restGetPools: pools %#v", poolsMap)
 	w.WriteJson(&poolsMap)
 }
 
@@ -62,7 +61,7 @@ func restGetPool(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	dataCtx := ctx.getDatastoreContext()
 	pool, err := facade.GetResourcePool(dataCtx, poolID)
 	if err != nil {
-		glog.Error("Could not get resource pool: ", err)
+		plog.Error("Could not get resource pool: ", err)
 		restServerError(w, err)
 		return
 	}
@@ -78,7 +77,7 @@ func restGetPool(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 		return
 	}
 
-	glog.V(4).Infof("restGetPool: id %s, pool %#v", poolID, pool)
+	plog.Debugf("restGetPool: id %s, pool %#v", poolID, pool)
 	w.WriteJson(&pool)
 }
 
@@ -87,7 +86,7 @@ func restAddPool(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	var payload pool.ResourcePool
 	err := r.DecodeJsonPayload(&payload)
 	if err != nil {
-		glog.V(1).Info("Could not decode pool payload: ", err)
+		plog.Debug("Could not decode pool payload: ", err)
 		restBadRequest(w, err)
 		return
 	}
@@ -95,11 +94,11 @@ func restAddPool(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	facade := ctx.getFacade()
 	err = facade.AddResourcePool(ctx.getDatastoreContext(), &payload)
 	if err != nil {
-		glog.Error("Unable to add pool: ", err)
+		plog.Error("Unable to add pool: ", err)
 		restServerError(w, err)
 		return
 	}
-	glog.V(0).Info("Added pool ", payload.ID)
+	plog.Info("Added pool ", payload.ID)
 	w.WriteJson(&simpleResponse{"Added resource pool", poolLinks(payload.ID)})
 }
 
@@ -117,7 +116,7 @@ func restUpdatePool(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext
 	var payload pool.ResourcePool
 	err = r.DecodeJsonPayload(&payload)
 	if err != nil {
-		glog.V(1).Info("Could not decode pool payload: ", err)
+		plog.Debug("Could not decode pool payload: ", err)
 		restBadRequest(w, err)
 		return
 	}
@@ -125,11 +124,11 @@ func restUpdatePool(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext
 	facade := ctx.getFacade()
 	err = facade.UpdateResourcePool(ctx.getDatastoreContext(), &payload)
 	if err != nil {
-		glog.Error("Unable to update pool: ", err)
+		plog.Error("Unable to update pool: ", err)
 		restServerError(w, err)
 		return
 	}
-	glog.V(1).Info("Updated pool ", poolID)
+	plog.Debug("Updated pool ", poolID)
 	w.WriteJson(&simpleResponse{"Updated resource pool", poolLinks(poolID)})
 }
 
@@ -147,11 +146,11 @@ func restRemovePool(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext
 	facade := ctx.getFacade()
 	err = facade.RemoveResourcePool(ctx.getDatastoreContext(), poolID)
 	if err != nil {
-		glog.Error("Could not remove resource pool: ", err)
+		plog.Error("Could not remove resource pool: ", err)
 		restServerError(w, err)
 		return
 	}
-	glog.V(0).Info("Removed pool ", poolID)
+	plog.Info("Removed pool ", poolID)
 	w.WriteJson(&simpleResponse{"Removed resource pool", poolsLinks()})
 }
 
@@ -160,7 +159,7 @@ func restGetHostsForResourcePool(w *rest.ResponseWriter, r *rest.Request, ctx *r
 	poolHosts := []*pool.PoolHost{}
 	poolID, err := url.QueryUnescape(r.PathParam("poolId"))
 	if err != nil {
-		glog.V(1).Infof("Unable to acquire pool ID: %v", err)
+		plog.Debugf("Unable to acquire pool ID: %v", err)
 		restBadRequest(w, err)
 		return
 	} else if len(poolID) == 0 {
@@ -171,7 +170,7 @@ func restGetHostsForResourcePool(w *rest.ResponseWriter, r *rest.Request, ctx *r
 	facade := ctx.getFacade()
 	hosts, err := facade.FindHostsInPool(ctx.getDatastoreContext(), poolID)
 	if err != nil {
-		glog.Errorf("Could not get hosts: %v", err)
+		plog.Errorf("Could not get hosts: %v", err)
 		restServerError(w, err)
 		return
 	}
@@ -183,7 +182,7 @@ func restGetHostsForResourcePool(w *rest.ResponseWriter, r *rest.Request, ctx *r
 		}
 		poolHosts = append(poolHosts, &ph)
 	}
-	glog.V(2).Infof("Returning %d hosts for pool %s", len(poolHosts), poolID)
+	plog.Debugf("Returning %d hosts for pool %s", len(poolHosts), poolID)
 	w.WriteJson(&poolHosts)
 }
 
@@ -201,19 +200,19 @@ func restGetPoolIps(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext
 	facade := ctx.getFacade()
 	ips, err := facade.GetPoolIPs(ctx.getDatastoreContext(), poolID)
 	if err != nil {
-		glog.Error("Could not get resource pool: ", err)
+		plog.Error("Could not get resource pool: ", err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(4).Infof("restGetPoolIps: id %s, pool %#v", poolID, ips)
+	plog.Debugf("restGetPoolIps: id %s, pool %#v", poolID, ips)
 	w.WriteJson(&ips)
 }
 
 func getPoolHostIds(poolID string, facade facade.FacadeInterface, dataCtx datastore.Context) ([]string, error) {
 	hosts, err := facade.FindHostsInPool(dataCtx, poolID)
 	if err != nil {
-		glog.Errorf("Could not get hosts: %v", err)
+		plog.Errorf("Could not get hosts: %v", err)
 		return nil, err
 	}
 
@@ -232,7 +231,7 @@ func buildPoolMonitoringProfile(pool *pool.ResourcePool, hostIDs []string, facad
 	for i := range hostIDs {
 		host, err := facade.GetHost(dataCtx, hostIDs[i])
 		if err != nil {
-			glog.Errorf("Failed to get host for id=%s -> %s", hostIDs[i], err)
+			plog.Errorf("Failed to get host for id=%s -> %s", hostIDs[i], err)
 			return err
 		}
 
@@ -243,7 +242,7 @@ func buildPoolMonitoringProfile(pool *pool.ResourcePool, hostIDs []string, facad
 	tags := map[string][]string{"controlplane_host_id": hostIDs}
 	profile, err := hostPoolProfile.ReBuild("1h-ago", tags)
 	if err != nil {
-		glog.Errorf("Failed to create pool profile: %s", err)
+		plog.Errorf("Failed to create pool profile: %s", err)
 		return err
 	}
 
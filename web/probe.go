@@ -0,0 +1,164 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/zenoss/go-json-rest"
+)
+
+// probeTimeout is the time a dependency check may take before it fails
+var probeTimeout = 5 * time.Second
+
+// ErrProbeTimeout is the error of a dependency check that took too long
+var ErrProbeTimeout = errors.New("check timed out")
+
+// ProbeCheck returns an error if a dependency of the master is unavailable
+type ProbeCheck func() error
+
+// ProbeResult is the outcome of a dependency check
+type ProbeResult struct {
+	Healthy  bool
+	Error    string `json:",omitempty"`
+	Duration string
+}
+
+// ProbeResponse is the body of the /healthz and /readyz endpoints.
+// ClockSkew lists the hosts whose clocks are out of sync with the master; it
+// is reported but does not fail the probe.
+type ProbeResponse struct {
+	Healthy   bool
+	Checks    map[string]ProbeResult
+	ClockSkew map[string]string `json:",omitempty"`
+}
+
+// ClockSkewReporter returns the clock skew of each host whose clock is out of
+// sync with the master
+type ClockSkewReporter func() map[string]time.Duration
+
+// probeRegistry holds the dependency checks of the health and readiness
+// endpoints.
+type probeRegistry struct {
+	mu        sync.RWMutex
+	health    map[string]ProbeCheck
+	readiness map[string]ProbeCheck
+	clockSkew ClockSkewReporter
+}
+
+func newProbeRegistry() *probeRegistry {
+	return &probeRegistry{
+		health:    make(map[string]ProbeCheck),
+		readiness: make(map[string]ProbeCheck),
+	}
+}
+
+// AddHealthCheck adds a check of a dependency without which the master cannot
+// run.  Health checks are also readiness checks.
+func (sc *ServiceConfig) AddHealthCheck(name string, check ProbeCheck) {
+	sc.probes.mu.Lock()
+	defer sc.probes.mu.Unlock()
+	sc.probes.health[name] = check
+	sc.probes.readiness[name] = check
+}
+
+// AddReadinessCheck adds a check of a dependency without which the master
+// should not receive requests.
+func (sc *ServiceConfig) AddReadinessCheck(name string, check ProbeCheck) {
+	sc.probes.mu.Lock()
+	defer sc.probes.mu.Unlock()
+	sc.probes.readiness[name] = check
+}
+
+// SetClockSkewReporter sets the function that lists the hosts whose clocks
+// are out of sync in the health and readiness responses.
+func (sc *ServiceConfig) SetClockSkewReporter(reporter ClockSkewReporter) {
+	sc.probes.mu.Lock()
+	defer sc.probes.mu.Unlock()
+	sc.probes.clockSkew = reporter
+}
+
+// run runs the checks and adds the hosts whose clocks are out of sync
+func (p *probeRegistry) run(checks map[string]ProbeCheck) ProbeResponse {
+	response := runProbes(checks)
+	if p.clockSkew != nil {
+		if skewed := p.clockSkew(); len(skewed) > 0 {
+			response.ClockSkew = make(map[string]string)
+			for hostid, skew := range skewed {
+				response.ClockSkew[hostid] = skew.String()
+			}
+		}
+	}
+	return response
+}
+
+// runProbes runs the checks concurrently and returns their results
+func runProbes(checks map[string]ProbeCheck) ProbeResponse {
+	response := ProbeResponse{Healthy: true, Checks: make(map[string]ProbeResult)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check ProbeCheck) {
+			defer wg.Done()
+			start := time.Now()
+			errc := make(chan error, 1)
+			go func() { errc <- check() }()
+			var err error
+			select {
+			case err = <-errc:
+			case <-time.After(probeTimeout):
+				err = ErrProbeTimeout
+			}
+			result := ProbeResult{Healthy: err == nil, Duration: time.Since(start).String()}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			response.Checks[name] = result
+			response.Healthy = response.Healthy && result.Healthy
+		}(name, check)
+	}
+	wg.Wait()
+	return response
+}
+
+// writeProbeResponse writes the results of the checks, with the status
+// 503 Service Unavailable if any of them failed.
+func writeProbeResponse(w *rest.ResponseWriter, response ProbeResponse) {
+	code := http.StatusOK
+	if !response.Healthy {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, response, code)
+}
+
+// restGetHealthz reports whether the master is alive
+func (sc *ServiceConfig) restGetHealthz(w *rest.ResponseWriter, r *rest.Request) {
+	sc.probes.mu.RLock()
+	defer sc.probes.mu.RUnlock()
+	writeProbeResponse(w, sc.probes.run(sc.probes.health))
+}
+
+// restGetReadyz reports whether the master is ready to receive requests
+func (sc *ServiceConfig) restGetReadyz(w *rest.ResponseWriter, r *rest.Request) {
+	sc.probes.mu.RLock()
+	defer sc.probes.mu.RUnlock()
+	writeProbeResponse(w, sc.probes.run(sc.probes.readiness))
+}
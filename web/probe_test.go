@@ -0,0 +1,129 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zenoss/go-json-rest"
+)
+
+func getProbe(t *testing.T, handler handlerFunc) (int, ProbeResponse) {
+	httpRequest, _ := http.NewRequest("GET", "/healthz", nil)
+	request := rest.Request{Request: httpRequest, PathParams: map[string]string{}}
+	recorder := httptest.NewRecorder()
+	writer := rest.NewResponseWriter(recorder, false)
+	handler(&writer, &request)
+
+	var response ProbeResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("could not decode probe response %q: %s", recorder.Body.String(), err)
+	}
+	return recorder.Code, response
+}
+
+func TestProbesHealthy(t *testing.T) {
+	sc := &ServiceConfig{probes: newProbeRegistry()}
+	sc.AddHealthCheck("datastore", func() error { return nil })
+	sc.AddReadinessCheck("dfs", func() error { return nil })
+
+	code, response := getProbe(t, sc.restGetHealthz)
+	if code != http.StatusOK || !response.Healthy {
+		t.Errorf("expected healthy response, got %d %+v", code, response)
+	}
+	if _, ok := response.Checks["dfs"]; ok {
+		t.Error("readiness check was run by the health endpoint")
+	}
+
+	code, response = getProbe(t, sc.restGetReadyz)
+	if code != http.StatusOK || !response.Healthy {
+		t.Errorf("expected ready response, got %d %+v", code, response)
+	}
+	if len(response.Checks) != 2 {
+		t.Errorf("expected the health and readiness checks, got %+v", response.Checks)
+	}
+}
+
+func TestProbesFailed(t *testing.T) {
+	sc := &ServiceConfig{probes: newProbeRegistry()}
+	sc.AddHealthCheck("datastore", func() error { return nil })
+	sc.AddReadinessCheck("isvcs", func() error { return errors.New("zookeeper/running is failed") })
+
+	code, response := getProbe(t, sc.restGetHealthz)
+	if code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, code)
+	}
+
+	code, response = getProbe(t, sc.restGetReadyz)
+	if code != http.StatusServiceUnavailable || response.Healthy {
+		t.Errorf("expected unavailable response, got %d %+v", code, response)
+	}
+	if result := response.Checks["isvcs"]; result.Healthy || result.Error != "zookeeper/running is failed" {
+		t.Errorf("expected isvcs check to fail, got %+v", result)
+	}
+	if result := response.Checks["datastore"]; !result.Healthy {
+		t.Errorf("expected datastore check to pass, got %+v", result)
+	}
+}
+
+func TestProbesTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { probeTimeout = timeout }(probeTimeout)
+	probeTimeout = 10 * time.Millisecond
+
+	block := make(chan struct{})
+	defer close(block)
+	sc := &ServiceConfig{probes: newProbeRegistry()}
+	sc.AddHealthCheck("zookeeper", func() error {
+		<-block
+		return nil
+	})
+
+	code, response := getProbe(t, sc.restGetHealthz)
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, code)
+	}
+	if result := response.Checks["zookeeper"]; result.Error != ErrProbeTimeout.Error() {
+		t.Errorf("expected zookeeper check to time out, got %+v", result)
+	}
+}
+
+func TestProbesClockSkew(t *testing.T) {
+	sc := &ServiceConfig{probes: newProbeRegistry()}
+	sc.AddHealthCheck("datastore", func() error { return nil })
+
+	code, response := getProbe(t, sc.restGetHealthz)
+	if code != http.StatusOK || response.ClockSkew != nil {
+		t.Errorf("expected no clock skew, got %d %+v", code, response)
+	}
+
+	sc.SetClockSkewReporter(func() map[string]time.Duration {
+		return map[string]time.Duration{"host1": -time.Minute}
+	})
+	for _, handler := range []handlerFunc{sc.restGetHealthz, sc.restGetReadyz} {
+		code, response = getProbe(t, handler)
+		if code != http.StatusOK || !response.Healthy {
+			t.Errorf("expected clock skew not to fail the probe, got %d %+v", code, response)
+		}
+		if skew := response.ClockSkew["host1"]; skew != "-1m0s" {
+			t.Errorf("expected clock skew of host1, got %+v", response.ClockSkew)
+		}
+	}
+}
@@ -18,9 +18,7 @@ package web
 
 import (
 	"github.com/control-center/serviced/dao"
-	daoclient "github.com/control-center/serviced/dao/client"
 	svc "github.com/control-center/serviced/domain/service"
-	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 
 	"fmt"
@@ -40,7 +38,7 @@ type endpointRequest struct {
 
 // restAddVirtualHost parses payload, adds the vhost to the service, then updates the service
 func restAddVirtualHost(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
-	glog.V(1).Infof("Add VHost with %s %#v", r.URL.Path, r)
+	plog.Debugf("Add VHost with %s %#v", r.URL.Path, r)
 
 	serviceid, application, vhostname, err := getVHostContext(r)
 	if err != nil {
@@ -60,12 +58,12 @@ func restAddVirtualHost(w *rest.ResponseWriter, r *rest.Request, ctx *requestCon
 
 	_, err = facade.AddPublicEndpointVHost(dataCtx, serviceid, application, vhostname, true, true)
 	if err != nil {
-		glog.Errorf("Error adding vhost to service (%s): %v", request.ServiceName, err)
+		plog.Errorf("Error adding vhost to service (%s): %v", request.ServiceName, err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(2).Infof("VHost (%s) added to service (%s)", vhostname, request.ServiceName)
+	plog.Debugf("VHost (%s) added to service (%s)", vhostname, request.ServiceName)
 	restSuccess(w)
 }
 
@@ -77,14 +75,14 @@ func restRemoveVirtualHost(w *rest.ResponseWriter, r *rest.Request, ctx *request
 		return
 	}
 
-	glog.V(2).Info("Removing vhost %d from service (%s)", vhost, serviceid)
+	plog.Debug("Removing vhost %d from service (%s)", vhost, serviceid)
 
 	facade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
 
 	err = facade.RemovePublicEndpointVHost(dataCtx, serviceid, application, vhost)
 	if err != nil {
-		glog.Error(err)
+		plog.Error(err)
 		restServerError(w, err)
 		return
 	}
@@ -96,19 +94,19 @@ func restRemoveVirtualHost(w *rest.ResponseWriter, r *rest.Request, ctx *request
 func getVHostContext(r *rest.Request) (string, string, string, error) {
 	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
 	if err != nil {
-		glog.Errorf("Failed getting serviceID: %v", err)
+		plog.Errorf("Failed getting serviceID: %v", err)
 		return "", "", "", err
 	}
 
 	application, err := url.QueryUnescape(r.PathParam("application"))
 	if err != nil {
-		glog.Errorf("Failed getting application: %v", err)
+		plog.Errorf("Failed getting application: %v", err)
 		return "", "", "", err
 	}
 
 	vhostname, err := url.QueryUnescape(r.PathParam("name"))
 	if err != nil {
-		glog.Errorf("Failed getting hostname: %v", err)
+		plog.Errorf("Failed getting hostname: %v", err)
 		return "", "", "", err
 	}
 	return serviceID, application, vhostname, nil
@@ -117,7 +115,7 @@ func getVHostContext(r *rest.Request) (string, string, string, error) {
 
 // restVirtualHostEnable enables or disables a virtual host endpoint
 func restVirtualHostEnable(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
-	glog.V(1).Infof("Enable/Disable VHOST with %s %#v", r.URL.Path, r)
+	plog.Debugf("Enable/Disable VHOST with %s %#v", r.URL.Path, r)
 
 	serviceid, application, vhostname, err := getVHostContext(r)
 	if err != nil {
@@ -137,7 +135,7 @@ func restVirtualHostEnable(w *rest.ResponseWriter, r *rest.Request, ctx *request
 
 	err = facade.EnablePublicEndpointVHost(dataCtx, serviceid, application, vhostname, request.IsEnabled)
 	if err != nil {
-		glog.Errorf("Error enabling/disabling vhost %s on service (%s): %v", vhostname, request.ServiceName, err)
+		plog.Errorf("Error enabling/disabling vhost %s on service (%s): %v", vhostname, request.ServiceName, err)
 		restServerError(w, err)
 		return
 	}
@@ -147,17 +145,17 @@ func restVirtualHostEnable(w *rest.ResponseWriter, r *rest.Request, ctx *request
 
 // Returns the service, application, and portnumber from the request
 func getPortContext(r *rest.Request) (string, string, string, error) {
-	glog.V(1).Infof("in getPortContext()")
+	plog.Debugf("in getPortContext()")
 
 	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
 	if err != nil {
-		glog.Errorf("Failed getting serviceID: %v", err)
+		plog.Errorf("Failed getting serviceID: %v", err)
 		return "", "", "", err
 	}
 
 	application, err := url.QueryUnescape(r.PathParam("application"))
 	if err != nil {
-		glog.Errorf("Failed getting application: %v", err)
+		plog.Errorf("Failed getting application: %v", err)
 		return "", "", "", err
 	}
 
@@ -180,12 +178,12 @@ func getPortContext(r *rest.Request) (string, string, string, error) {
 
 // restAddPort parses payload, adds the port to the service, then updates the service
 func restAddPort(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
-	glog.V(1).Infof("Add PORT with %s %#v", r.URL.Path, r)
+	plog.Debugf("Add PORT with %s %#v", r.URL.Path, r)
 
 	serviceid, application, port, err := getPortContext(r)
 	if err != nil {
 		err := fmt.Errorf("Error removing port from service (%s): %v", serviceid, err)
-		glog.Error(err)
+		plog.Error(err)
 		restServerError(w, err)
 		return
 	}
@@ -197,7 +195,7 @@ func restAddPort(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 		return
 	}
 
-	glog.V(0).Infof("Add PORT with %s %#v", r.URL.Path, r)
+	plog.Infof("Add PORT with %s %#v", r.URL.Path, r)
 
 	facade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
@@ -205,36 +203,36 @@ func restAddPort(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	_, err = facade.AddPublicEndpointPort(dataCtx, serviceid, application,
 		port, request.UseTLS, request.Protocol, true, true)
 	if err != nil {
-		glog.Errorf("Error adding port to service (%s): %v", request.ServiceName, err)
+		plog.Errorf("Error adding port to service (%s): %v", request.ServiceName, err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(2).Infof("Port (%s) added to service (%s), UseTLS=%s, protocol=%s",
+	plog.Debugf("Port (%s) added to service (%s), UseTLS=%s, protocol=%s",
 		port, request.ServiceName, request.UseTLS, request.Protocol)
 
 	restSuccess(w)
 }
 
 func restRemovePort(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
-	glog.V(1).Infof("Remove PORT with %s %#v", r.URL.Path, r)
+	plog.Debugf("Remove PORT with %s %#v", r.URL.Path, r)
 
 	serviceid, application, port, err := getPortContext(r)
 	if err != nil {
 		err := fmt.Errorf("Error removing port from service (%s): %v", serviceid, err)
-		glog.Error(err)
+		plog.Error(err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(2).Info("Removing port %d from service (%s)", port, serviceid)
+	plog.Debug("Removing port %d from service (%s)", port, serviceid)
 
 	facade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
 
 	err = facade.RemovePublicEndpointPort(dataCtx, serviceid, application, port)
 	if err != nil {
-		glog.Error(err)
+		plog.Error(err)
 		restServerError(w, err)
 		return
 	}
@@ -243,12 +241,12 @@ func restRemovePort(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext
 }
 
 func restPortEnable(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
-	glog.V(1).Infof("Enable/Disable PORT with %s %#v", r.URL.Path, r)
+	plog.Debugf("Enable/Disable PORT with %s %#v", r.URL.Path, r)
 
 	serviceid, application, port, err := getPortContext(r)
 	if err != nil {
 		err := fmt.Errorf("Error removing port from service (%s): %v", serviceid, err)
-		glog.Error(err)
+		plog.Error(err)
 		restServerError(w, err)
 		return
 	}
@@ -266,13 +264,13 @@ func restPortEnable(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext
 	err = facade.EnablePublicEndpointPort(dataCtx, serviceid, application,
 		port, request.IsEnabled)
 	if err != nil {
-		glog.Errorf("Error setting enabled=%t for service (%s) port %s: %v", request.IsEnabled,
+		plog.Errorf("Error setting enabled=%t for service (%s) port %s: %v", request.IsEnabled,
 			request.ServiceName, port, err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(2).Infof("Port (%s) enabled=%t set for service (%s)", port, request.IsEnabled,
+	plog.Debugf("Port (%s) enabled=%t set for service (%s)", port, request.IsEnabled,
 		request.ServiceName)
 
 	restSuccess(w)
@@ -288,12 +286,11 @@ type virtualHost struct {
 }
 
 // restGetVirtualHosts gets all services, then extracts all vhost information and returns it.
-func restGetVirtualHosts(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
-	var services []svc.Service
+func restGetVirtualHosts(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	var serviceRequest dao.ServiceRequest
-	err := client.GetServices(serviceRequest, &services)
+	services, err := ctx.getFacade().GetServices(ctx.getDatastoreContext(), serviceRequest)
 	if err != nil {
-		glog.Errorf("Unexpected error retrieving virtual hosts: %v", err)
+		plog.Errorf("Unexpected error retrieving virtual hosts: %v", err)
 		restServerError(w, err)
 		return
 	}
@@ -21,24 +21,31 @@ import (
 	"strings"
 	"time"
 
-	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 
 	"github.com/control-center/serviced/dao"
 	daoclient "github.com/control-center/serviced/dao/client"
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/statehistory"
 	"github.com/control-center/serviced/isvcs"
+	"github.com/control-center/serviced/rpc/rpcutils"
 	"github.com/control-center/serviced/servicedversion"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/volume"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 var empty interface{}
 
 var snapshotSpacePercent int
 
+// userLockTimeout is how long a request waits for the dfs lock before it
+// gives up, e.g. while a snapshot is in progress.
+var userLockTimeout = time.Second
+
 type handlerFunc func(w *rest.ResponseWriter, r *rest.Request)
 type handlerClientFunc func(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient)
 
@@ -46,41 +53,40 @@ func restDockerIsLoggedIn(w *rest.ResponseWriter, r *rest.Request, client *daocl
 	w.WriteJson(&map[string]bool{"dockerLoggedIn": utils.DockerIsLoggedIn()})
 }
 
-func getTaggedServices(client *daoclient.ControlClient, tags, nmregex string, tenantID string) ([]service.Service, error) {
-	services := []service.Service{}
+func getTaggedServices(ctx *requestContext, tags, nmregex string, tenantID string) ([]service.Service, error) {
 	tagsSlice := strings.Split(tags, ",")
 	serviceRequest := dao.ServiceRequest{
 		Tags:      tagsSlice,
 		TenantID:  tenantID,
 		NameRegex: nmregex,
 	}
-	if err := client.GetTaggedServices(serviceRequest, &services); err != nil {
-		glog.Errorf("Could not get tagged services: %v", err)
+	services, err := ctx.getFacade().GetTaggedServices(ctx.getDatastoreContext(), serviceRequest)
+	if err != nil {
+		plog.Errorf("Could not get tagged services: %v", err)
 		return nil, err
 	}
 
-	glog.V(2).Infof("Returning %d tagged services", len(services))
+	plog.Debugf("Returning %d tagged services", len(services))
 	return services, nil
 }
 
-func getNamedServices(client *daoclient.ControlClient, nmregex string, tenantID string) ([]service.Service, error) {
-	services := []service.Service{}
+func getNamedServices(ctx *requestContext, nmregex string, tenantID string) ([]service.Service, error) {
 	var emptySlice []string
 	serviceRequest := dao.ServiceRequest{
 		Tags:      emptySlice,
 		TenantID:  tenantID,
 		NameRegex: nmregex,
 	}
-	if err := client.GetServices(serviceRequest, &services); err != nil {
-		glog.Errorf("Could not get named services: %v", err)
+	services, err := ctx.getFacade().GetServices(ctx.getDatastoreContext(), serviceRequest)
+	if err != nil {
+		plog.Errorf("Could not get named services: %v", err)
 		return nil, err
 	}
 
 	return services, nil
 }
 
-func getServices(client *daoclient.ControlClient, tenantID string, since time.Duration) ([]service.Service, error) {
-	services := []service.Service{}
+func getServices(ctx *requestContext, tenantID string, since time.Duration) ([]service.Service, error) {
 	var emptySlice []string
 	serviceRequest := dao.ServiceRequest{
 		Tags:         emptySlice,
@@ -88,12 +94,13 @@ func getServices(client *daoclient.ControlClient, tenantID string, since time.Du
 		UpdatedSince: since,
 		NameRegex:    "",
 	}
-	if err := client.GetServices(serviceRequest, &services); err != nil {
-		glog.Errorf("Could not get services: %v", err)
+	services, err := ctx.getFacade().GetServices(ctx.getDatastoreContext(), serviceRequest)
+	if err != nil {
+		plog.Errorf("Could not get services: %v", err)
 		return nil, err
 	}
 
-	glog.V(2).Infof("Returning %d services", len(services))
+	plog.Debugf("Returning %d services", len(services))
 	return services, nil
 }
 
@@ -127,7 +134,7 @@ func restPostServicesForMigration(w *rest.ResponseWriter, r *rest.Request, clien
 	var migrationRequest dao.ServiceMigrationRequest
 	err := r.DecodeJsonPayload(&migrationRequest)
 	if err != nil {
-		glog.Errorf("Could not decode services for migration: %v", err)
+		plog.Errorf("Could not decode services for migration: %v", err)
 		restBadRequest(w, err)
 		return
 	}
@@ -140,12 +147,12 @@ func restPostServicesForMigration(w *rest.ResponseWriter, r *rest.Request, clien
 }
 
 // DEPRECATED
-func restGetAllServices(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
+func restGetAllServices(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 
 	// load the internal monitoring data
 	config, err := getInternalMetrics()
 	if err != nil {
-		glog.Errorf("Could not get internal monitoring metrics: %s", err)
+		plog.Errorf("Could not get internal monitoring metrics: %s", err)
 		restServerError(w, err)
 		return
 	}
@@ -153,7 +160,7 @@ func restGetAllServices(w *rest.ResponseWriter, r *rest.Request, client *daoclie
 	tenantID := r.URL.Query().Get("tenantID")
 	if tags := r.URL.Query().Get("tags"); tags != "" {
 		nmregex := r.URL.Query().Get("name")
-		result, err := getTaggedServices(client, tags, nmregex, tenantID)
+		result, err := getTaggedServices(ctx, tags, nmregex, tenantID)
 		if err != nil {
 			restServerError(w, err)
 			return
@@ -168,7 +175,7 @@ func restGetAllServices(w *rest.ResponseWriter, r *rest.Request, client *daoclie
 	}
 
 	if nmregex := r.URL.Query().Get("name"); nmregex != "" {
-		result, err := getNamedServices(client, nmregex, tenantID)
+		result, err := getNamedServices(ctx, nmregex, tenantID)
 		if err != nil {
 			restServerError(w, err)
 			return
@@ -194,7 +201,7 @@ func restGetAllServices(w *rest.ResponseWriter, r *rest.Request, client *daoclie
 		}
 		tsince = time.Duration(tint) * time.Millisecond
 	}
-	result, err := getServices(client, tenantID, tsince)
+	result, err := getServices(ctx, tenantID, tsince)
 	if err != nil {
 		restServerError(w, err)
 		return
@@ -223,24 +230,25 @@ func restGetAllServices(w *rest.ResponseWriter, r *rest.Request, client *daoclie
 	w.WriteJson(&result)
 }
 
-func restGetRunningForHost(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
+func restGetRunningForHost(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	hostID, err := url.QueryUnescape(r.PathParam("hostId"))
 	if err != nil {
 		restBadRequest(w, err)
 		return
 	}
-	var services []dao.RunningService
-	err = client.GetRunningServicesForHost(hostID, &services)
+	// only the instances of the tenants in scope are returned
+	since := time.Now().Add(-time.Hour)
+	insts, err := ctx.getFacade().GetHostInstances(ctx.getDatastoreContext(), since, hostID)
 	if err != nil {
-		glog.Errorf("Could not get services: %v", err)
+		plog.Errorf("Could not get services: %v", err)
 		restServerError(w, err)
 		return
 	}
-	if services == nil {
-		glog.V(3).Info("Running services was nil, returning empty list instead")
-		services = []dao.RunningService{}
+	services := make([]dao.RunningService, len(insts))
+	for i, inst := range insts {
+		services[i] = dao.NewRunningService(inst)
 	}
-	glog.V(2).Infof("Returning %d running services for host %s", len(services), hostID)
+	plog.Debugf("Returning %d running services for host %s", len(services), hostID)
 	w.WriteJson(&services)
 }
 
@@ -257,15 +265,15 @@ func restGetRunningForService(w *rest.ResponseWriter, r *rest.Request, client *d
 	var services []dao.RunningService
 	err = client.GetRunningServicesForService(serviceID, &services)
 	if err != nil {
-		glog.Errorf("Could not get running services for %s: %v", serviceID, err)
+		plog.Errorf("Could not get running services for %s: %v", serviceID, err)
 		restServerError(w, err)
 		return
 	}
 	if services == nil {
-		glog.V(3).Info("Running services was nil, returning empty list instead")
+		plog.Debug("Running services was nil, returning empty list instead")
 		services = []dao.RunningService{}
 	}
-	glog.V(2).Infof("Returning %d running services for service %s", len(services), serviceID)
+	plog.Debugf("Returning %d running services for service %s", len(services), serviceID)
 	w.WriteJson(&services)
 }
 
@@ -276,7 +284,7 @@ type uiRunningService struct {
 	RAMAverage int64
 }
 
-func restKillRunning(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
+func restKillRunning(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	serviceStateID, err := url.QueryUnescape(r.PathParam("serviceStateId"))
 	if err != nil {
 		restBadRequest(w, err)
@@ -287,13 +295,22 @@ func restKillRunning(w *rest.ResponseWriter, r *rest.Request, client *daoclient.
 		restBadRequest(w, err)
 		return
 	}
-	request := dao.HostServiceRequest{hostID, serviceStateID}
-	glog.V(1).Info("Received request to kill ", request)
-
-	var unused int
-	err = client.StopRunningInstance(request, &unused)
+	_, serviceID, instanceID, err := zkservice.ParseStateID(serviceStateID)
 	if err != nil {
-		glog.Errorf("Unable to stop service: %v", err)
+		restBadRequest(w, err)
+		return
+	}
+	plog.Debugf("Received request to kill %s on host %s", serviceStateID, hostID)
+
+	// the instance may only be stopped if its tenant is in scope
+	facade := ctx.getFacade()
+	dataCtx := ctx.getDatastoreContext()
+	if err := facade.CheckServiceAccess(dataCtx, serviceID); err != nil {
+		restServerError(w, err)
+		return
+	}
+	if err := facade.StopServiceInstance(dataCtx, serviceID, instanceID); err != nil {
+		plog.Errorf("Unable to stop service: %v", err)
 		restServerError(w, err)
 		return
 	}
@@ -301,13 +318,12 @@ func restKillRunning(w *rest.ResponseWriter, r *rest.Request, client *daoclient.
 	w.WriteJson(&simpleResponse{"Marked for death", servicesLinks()})
 }
 
-func restGetTopServices(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
-	var allServices []service.Service
+func restGetTopServices(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	topServices := []service.Service{}
 	var serviceRequest dao.ServiceRequest
-	err := client.GetServices(serviceRequest, &allServices)
+	allServices, err := ctx.getFacade().GetServices(ctx.getDatastoreContext(), serviceRequest)
 	if err != nil {
-		glog.Errorf("Could not get services: %v", err)
+		plog.Errorf("Could not get services: %v", err)
 		restServerError(w, err)
 		return
 	}
@@ -317,7 +333,7 @@ func restGetTopServices(w *rest.ResponseWriter, r *rest.Request, client *daoclie
 		}
 	}
 	topServices = append(topServices, isvcs.InternalServicesISVC)
-	glog.V(2).Infof("Returning %d services as top services", len(topServices))
+	plog.Debugf("Returning %d services as top services", len(topServices))
 	w.WriteJson(&topServices)
 }
 
@@ -326,7 +342,7 @@ func restGetService(w *rest.ResponseWriter, r *rest.Request, client *daoclient.C
 	// load the internal monitoring data
 	config, err := getInternalMetrics()
 	if err != nil {
-		glog.Errorf("Could not get internal monitoring metrics: %s", err)
+		plog.Errorf("Could not get internal monitoring metrics: %s", err)
 		restServerError(w, err)
 		return
 	}
@@ -342,7 +358,7 @@ func restGetService(w *rest.ResponseWriter, r *rest.Request, client *daoclient.C
 	if includeChildren == "true" {
 		services := []service.Service{}
 		if err := client.GetServiceList(serviceID, &services); err != nil {
-			glog.Errorf("Could not get services: %v", err)
+			plog.Errorf("Could not get services: %v", err)
 			restServerError(w, err)
 			return
 		}
@@ -356,7 +372,7 @@ func restGetService(w *rest.ResponseWriter, r *rest.Request, client *daoclient.C
 	}
 	svc := service.Service{}
 	if err := client.GetService(serviceID, &svc); err != nil {
-		glog.Errorf("Could not get service %v: %v", serviceID, err)
+		plog.Errorf("Could not get service %v: %v", serviceID, err)
 		restServerError(w, err)
 		return
 	}
@@ -368,16 +384,15 @@ func restGetService(w *rest.ResponseWriter, r *rest.Request, client *daoclient.C
 		return
 	}
 
-	glog.Errorf("No such service [%v]", serviceID)
+	plog.Errorf("No such service [%v]", serviceID)
 	restServerError(w, err)
 }
 
-func restAddService(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
+func restAddService(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	var svc service.Service
-	var serviceID string
 	err := r.DecodeJsonPayload(&svc)
 	if err != nil {
-		glog.V(1).Info("Could not decode service payload: ", err)
+		plog.Debug("Could not decode service payload: ", err)
 		restBadRequest(w, err)
 		return
 
@@ -393,18 +408,25 @@ func restAddService(w *rest.ResponseWriter, r *rest.Request, client *daoclient.C
 	svc.UpdatedAt = now
 
 	//for each endpoint, evaluate its EndpointTemplates
+	dataCtx := ctx.getDatastoreContext()
 	getSvc := func(svcID string) (service.Service, error) {
 		svc := service.Service{}
-		err := client.GetService(svcID, &svc)
+		result, err := ctx.getFacade().GetService(dataCtx, svcID)
+		if result != nil {
+			svc = *result
+		}
 		return svc, err
 	}
 	findChild := func(svcID, childName string) (service.Service, error) {
 		svc := service.Service{}
-		err := client.FindChildService(dao.FindChildRequest{svcID, childName}, &svc)
+		result, err := ctx.getFacade().FindChildService(dataCtx, svcID, childName)
+		if result != nil {
+			svc = *result
+		}
 		return svc, err
 	}
 	if err = svc.EvaluateEndpointTemplates(getSvc, findChild, 0); err != nil {
-		glog.Errorf("Unable to evaluate service endpoints: %v", err)
+		plog.Errorf("Unable to evaluate service endpoints: %v", err)
 		restServerError(w, err)
 		return
 	}
@@ -414,56 +436,62 @@ func restAddService(w *rest.ResponseWriter, r *rest.Request, client *daoclient.C
 	}
 	profile, err := svc.MonitoringProfile.ReBuild("1h-ago", tags)
 	if err != nil {
-		glog.Errorf("Unable to rebuild service monitoring profile: %v", err)
+		plog.Errorf("Unable to rebuild service monitoring profile: %v", err)
 		restServerError(w, err)
 		return
 	}
 	svc.MonitoringProfile = *profile
 
 	//add the service to the data store
-	err = client.AddService(svc, &serviceID)
+	if err := ctx.getFacade().DFSLock(dataCtx).LockWithTimeout("add service", userLockTimeout); err != nil {
+		plog.Warningf("Cannot add service: %s", err)
+		restServerError(w, err)
+		return
+	}
+	err = ctx.getFacade().AddService(dataCtx, svc)
+	ctx.getFacade().DFSLock(dataCtx).Unlock()
 	if err != nil {
-		glog.Errorf("Unable to add service: %v", err)
+		plog.Errorf("Unable to add service: %v", err)
 		restServerError(w, err)
 		return
 	}
+	serviceID := svc.ID
 
 	//automatically assign virtual ips to new service
 	request := addressassignment.AssignmentRequest{ServiceID: svc.ID, IPAddress: "", AutoAssignment: true}
-	if err := client.AssignIPs(request, nil); err != nil {
-		glog.Errorf("Failed to automatically assign IPs: %+v -> %v", request, err)
+	if err := ctx.getFacade().AssignIPs(dataCtx, request); err != nil {
+		plog.Errorf("Failed to automatically assign IPs: %+v -> %v", request, err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(0).Info("Added service ", serviceID)
+	plog.Info("Added service ", serviceID)
 	w.WriteJson(&simpleResponse{"Added service", serviceLinks(serviceID)})
 }
 
-func restDeployService(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
+func restDeployService(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	var payload dao.ServiceDeploymentRequest
 	err := r.DecodeJsonPayload(&payload)
 	if err != nil {
-		glog.V(1).Info("Could not decode service payload: ", err)
+		plog.Debug("Could not decode service payload: ", err)
 		restBadRequest(w, err)
 		return
 	}
 
-	var serviceID string
-	err = client.DeployService(payload, &serviceID)
+	serviceID, err := ctx.getFacade().DeployService(ctx.getDatastoreContext(), payload.PoolID, payload.ParentID, payload.Overwrite, payload.Service)
 	if err != nil {
-		glog.Errorf("Unable to deploy service: %v", err)
+		plog.Errorf("Unable to deploy service: %v", err)
 		restServerError(w, err)
 		return
 	}
 
-	glog.V(0).Info("Deployed service ", serviceID)
+	plog.Info("Deployed service ", serviceID)
 	w.WriteJson(&simpleResponse{"Deployed service", serviceLinks(serviceID)})
 }
 
 func restUpdateService(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
 	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
-	glog.V(3).Infof("Received update request for %s", serviceID)
+	plog.Debugf("Received update request for %s", serviceID)
 	if err != nil {
 		restBadRequest(w, err)
 		return
@@ -472,17 +500,17 @@ func restUpdateService(w *rest.ResponseWriter, r *rest.Request, client *daoclien
 	var unused int
 	err = r.DecodeJsonPayload(&payload)
 	if err != nil {
-		glog.V(1).Info("Could not decode service payload: ", err)
+		plog.Debug("Could not decode service payload: ", err)
 		restBadRequest(w, err)
 		return
 	}
 	err = client.UpdateService(payload, &unused)
 	if err != nil {
-		glog.Errorf("Unable to update service %s: %v", serviceID, err)
+		plog.Errorf("Unable to update service %s: %v", serviceID, err)
 		restServerError(w, err)
 		return
 	}
-	glog.V(1).Info("Updated service ", serviceID)
+	plog.Debug("Updated service ", serviceID)
 	w.WriteJson(&simpleResponse{"Updated service", serviceLinks(serviceID)})
 }
 
@@ -493,13 +521,13 @@ func restRemoveService(w *rest.ResponseWriter, r *rest.Request, client *daoclien
 		restBadRequest(w, err)
 		return
 	}
-	err = client.RemoveService(serviceID, &unused)
+	err = client.RemoveService(dao.RemoveServiceRequest{ServiceID: serviceID}, &unused)
 	if err != nil {
-		glog.Errorf("Could not remove service: %v", err)
+		plog.Errorf("Could not remove service: %v", err)
 		restServerError(w, err)
 		return
 	}
-	glog.V(0).Info("Removed service ", serviceID)
+	plog.Info("Removed service ", serviceID)
 	w.WriteJson(&simpleResponse{"Removed service", servicesLinks()})
 }
 
@@ -512,13 +540,28 @@ func restGetServiceLogs(w *rest.ResponseWriter, r *rest.Request, client *daoclie
 	var logs string
 	err = client.GetServiceLogs(serviceID, &logs)
 	if err != nil {
-		glog.Errorf("Unexpected error getting service logs: %v", err)
+		plog.Errorf("Unexpected error getting service logs: %v", err)
 		restServerError(w, err)
 		return
 	}
 	w.WriteJson(&simpleResponse{logs, serviceLinks(serviceID)})
 }
 
+// scheduleRequest returns the request to schedule a service, attributed to
+// the session user, or to an api token if the request has no session
+func scheduleRequest(r *rest.Request, serviceID string, autoLaunch bool) dao.ScheduleServiceRequest {
+	request := dao.ScheduleServiceRequest{
+		ServiceID:  serviceID,
+		AutoLaunch: autoLaunch,
+		Source:     statehistory.SourceUI,
+		User:       sessionUser(r),
+	}
+	if request.User == "" {
+		request.Source = statehistory.SourceAPI
+	}
+	return request
+}
+
 // restRestartService restarts the service with the given id and all of its children
 func restRestartService(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
 	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
@@ -538,8 +581,8 @@ func restRestartService(w *rest.ResponseWriter, r *rest.Request, client *daoclie
 	}
 
 	var affected int
-	if err := client.RestartService(dao.ScheduleServiceRequest{serviceID, autoLaunch}, &affected); err != nil {
-		glog.Errorf("Unexpected error restarting service: %s", err)
+	if err := client.RestartService(scheduleRequest(r, serviceID, autoLaunch), &affected); err != nil {
+		plog.Errorf("Unexpected error restarting service: %s", err)
 		restServerError(w, err)
 		return
 	}
@@ -565,8 +608,8 @@ func restStartService(w *rest.ResponseWriter, r *rest.Request, client *daoclient
 	}
 
 	var affected int
-	if err := client.StartService(dao.ScheduleServiceRequest{serviceID, autoLaunch}, &affected); err != nil {
-		glog.Errorf("Unexpected error starting service: %s", err)
+	if err := client.StartService(scheduleRequest(r, serviceID, autoLaunch), &affected); err != nil {
+		plog.Errorf("Unexpected error starting service: %s", err)
 		restServerError(w, err)
 		return
 	}
@@ -592,8 +635,8 @@ func restStopService(w *rest.ResponseWriter, r *rest.Request, client *daoclient.
 	}
 
 	var affected int
-	if err := client.StopService(dao.ScheduleServiceRequest{serviceID, autoLaunch}, &affected); err != nil {
-		glog.Errorf("Unexpected error stopping service: %s", err)
+	if err := client.StopService(scheduleRequest(r, serviceID, autoLaunch), &affected); err != nil {
+		plog.Errorf("Unexpected error stopping service: %s", err)
 		restServerError(w, err)
 		return
 	}
@@ -608,17 +651,35 @@ func restSnapshotService(w *rest.ResponseWriter, r *rest.Request, client *daocli
 	}
 	req := dao.SnapshotRequest{
 		ServiceID: serviceID,
+		User:      sessionUser(r),
 	}
 	var label string
 	err = client.Snapshot(req, &label)
 	if err != nil {
-		glog.Errorf("Unexpected error snapshotting service: %v", err)
+		plog.Errorf("Unexpected error snapshotting service: %v", err)
 		restServerError(w, err)
 		return
 	}
 	w.WriteJson(&simpleResponse{label, serviceLinks(serviceID)})
 }
 
+// restGetServiceSnapshots lists the snapshots of the service's tenant with
+// who and what created them
+func restGetServiceSnapshots(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
+	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
+	if err != nil {
+		restBadRequest(w, err)
+		return
+	}
+	var snapshots []dao.SnapshotInfo
+	if err := client.ListSnapshots(serviceID, &snapshots); err != nil {
+		plog.Errorf("Unexpected error listing snapshots of service: %v", err)
+		restServerError(w, err)
+		return
+	}
+	w.WriteJson(&snapshots)
+}
+
 func restGetServiceStateLogs(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
 	serviceStateID, err := url.QueryUnescape(r.PathParam("serviceStateId"))
 	if err != nil {
@@ -635,7 +696,7 @@ func restGetServiceStateLogs(w *rest.ResponseWriter, r *rest.Request, client *da
 	var logs string
 	err = client.GetServiceStateLogs(request, &logs)
 	if err != nil {
-		glog.Errorf("Unexpected error getting service state logs: %v", err)
+		plog.Errorf("Unexpected error getting service state logs: %v", err)
 		restServerError(w, err)
 		return
 	}
@@ -662,7 +723,7 @@ func downloadServiceStateLogs(w *rest.ResponseWriter, r *rest.Request, client *d
 	err = client.GetServiceStateLogs(request, &logs)
 
 	if err != nil {
-		glog.Errorf("Unexpected error getting service state logs: %v", err)
+		plog.Errorf("Unexpected error getting service state logs: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte(fmt.Sprintf("Internal Server Error: %v", err)))
 		return
@@ -678,11 +739,30 @@ func restGetServicedVersion(w *rest.ResponseWriter, r *rest.Request, client *dao
 	w.WriteJson(servicedversion.GetVersion())
 }
 
+// restGetDFSMetrics exposes the dfs operation metrics in the Prometheus text
+// format.  It does not require authentication so that it can be scraped.
+func restGetDFSMetrics(w *rest.ResponseWriter, r *rest.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := dfs.WritePrometheus(w.ResponseWriter, dfs.GetStats()); err != nil {
+		plog.Errorf("Could not write dfs metrics: %s", err)
+	}
+}
+
+// restGetRPCMetrics exposes the call metrics of the rpc methods served by this
+// host in the Prometheus text format.  It does not require authentication so
+// that it can be scraped.
+func restGetRPCMetrics(w *rest.ResponseWriter, r *rest.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := rpcutils.WriteServerPrometheus(w.ResponseWriter, rpcutils.GetServerStats()); err != nil {
+		plog.Errorf("Could not write rpc metrics: %s", err)
+	}
+}
+
 func restGetStorage(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
 	volumeStatuses := volume.GetStatus()
 	if volumeStatuses == nil || len(volumeStatuses.GetAllStatuses()) == 0 {
 		err := fmt.Errorf("Unexpected error getting volume status")
-		glog.Errorf("%s", err)
+		plog.Errorf("%s", err)
 		restServerError(w, err)
 		return
 	}
@@ -701,7 +781,7 @@ func restGetStorage(w *rest.ResponseWriter, r *rest.Request, client *daoclient.C
 		tags := map[string][]string{}
 		profile, err := volumeProfile.ReBuild("1h-ago", tags)
 		if err != nil {
-			glog.Errorf("Unexpected error getting volume statuses: %v", err)
+			plog.Errorf("Unexpected error getting volume statuses: %v", err)
 			restServerError(w, err)
 			return
 		}
@@ -728,7 +808,7 @@ func RestBackupCreate(w *rest.ResponseWriter, r *rest.Request, client *daoclient
 	}
 	err := client.AsyncBackup(req, &filePath)
 	if err != nil {
-		glog.Errorf("Unexpected error during backup: %v", err)
+		plog.Errorf("Unexpected error during backup: %v", err)
 		restServerError(w, err)
 		return
 	}
@@ -746,9 +826,9 @@ func RestBackupRestore(w *rest.ResponseWriter, r *rest.Request, client *daoclien
 
 	unused := 0
 
-	err = client.AsyncRestore(filePath, &unused)
+	err = client.AsyncRestore(dao.RestoreRequest{Filename: filePath, Force: r.Form["force"]}, &unused)
 	if err != nil {
-		glog.Errorf("Unexpected error during restore: %v", err)
+		plog.Errorf("Unexpected error during restore: %v", err)
 		restServerError(w, err)
 		return
 	}
@@ -770,7 +850,7 @@ func RestBackupStatus(w *rest.ResponseWriter, r *rest.Request, client *daoclient
 	backupStatus := ""
 	err := client.BackupStatus(0, &backupStatus)
 	if err != nil {
-		glog.Errorf("Unexpected error during backup status: %v", err)
+		plog.Errorf("Unexpected error during backup status: %v", err)
 		writeJSON(w, &simpleResponse{err.Error(), homeLink()}, http.StatusInternalServerError)
 		return
 	}
@@ -781,7 +861,7 @@ func RestRestoreStatus(w *rest.ResponseWriter, r *rest.Request, client *daoclien
 	restoreStatus := ""
 	err := client.BackupStatus(0, &restoreStatus)
 	if err != nil {
-		glog.Errorf("Unexpected error during restore status: %v", err)
+		plog.Errorf("Unexpected error during restore status: %v", err)
 		writeJSON(w, &simpleResponse{err.Error(), homeLink()}, http.StatusInternalServerError)
 		return
 	}
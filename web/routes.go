@@ -19,10 +19,11 @@ import "github.com/zenoss/go-json-rest"
 func (sc *ServiceConfig) getRoutes() []rest.Route {
 
 	gz := gzipHandler
+	if !sc.compress {
+		gz = func(h handlerFunc) handlerFunc { return h }
+	}
 
 	routes := []rest.Route{
-		rest.Route{"GET", "/", gz(mainPage)},
-
 		// Backups
 		rest.Route{"GET", "/backup/create", gz(sc.authorizedClient(RestBackupCreate))},
 		rest.Route{"GET", "/backup/restore", gz(sc.authorizedClient(RestBackupRestore))},
@@ -38,8 +39,8 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 		rest.Route{"POST", "/hosts/add", gz(sc.checkAuth(restAddHost))},
 		rest.Route{"DELETE", "/hosts/:hostId", gz(sc.checkAuth(restRemoveHost))},
 		rest.Route{"PUT", "/hosts/:hostId", gz(sc.checkAuth(restUpdateHost))},
-		rest.Route{"GET", "/hosts/:hostId/running", gz(sc.authorizedClient(restGetRunningForHost))},
-		rest.Route{"DELETE", "/hosts/:hostId/:serviceStateId", gz(sc.authorizedClient(restKillRunning))},
+		rest.Route{"GET", "/hosts/:hostId/running", gz(sc.checkAuth(restGetRunningForHost))},
+		rest.Route{"DELETE", "/hosts/:hostId/:serviceStateId", gz(sc.checkAuth(restKillRunning))},
 		rest.Route{"POST", "/hosts/:hostId/key", gz(sc.checkAuth(restResetHostKey))},
 
 		// Pools
@@ -58,37 +59,38 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 		rest.Route{"GET", "/pools/:poolId/ips", gz(sc.checkAuth(restGetPoolIps))},
 
 		// Services (Apps)
-		rest.Route{"GET", "/services", gz(sc.authorizedClient(restGetAllServices))},
+		rest.Route{"GET", "/services", gz(sc.checkAuth(restGetAllServices))},
 		rest.Route{"GET", "/servicehealth", gz(sc.checkAuth(restGetServicesHealth))},
-		rest.Route{"GET", "/services/:serviceId", gz(sc.authorizedClient(restGetService))},
-		rest.Route{"GET", "/services/:serviceId/running", gz(sc.authorizedClient(restGetRunningForService))},
-		rest.Route{"GET", "/services/:serviceId/:serviceStateId/logs", gz(sc.authorizedClient(restGetServiceStateLogs))},
-		rest.Route{"GET", "/services/:serviceId/:serviceStateId/logs/download", gz(sc.authorizedClient(downloadServiceStateLogs))},
-		rest.Route{"POST", "/services/add", gz(sc.authorizedClient(restAddService))},
-		rest.Route{"POST", "/services/deploy", gz(sc.authorizedClient(restDeployService))},
-		rest.Route{"DELETE", "/services/:serviceId", gz(sc.authorizedClient(restRemoveService))},
-		rest.Route{"GET", "/services/:serviceId/logs", gz(sc.authorizedClient(restGetServiceLogs))},
-		rest.Route{"PUT", "/services/:serviceId", gz(sc.authorizedClient(restUpdateService))},
-		rest.Route{"GET", "/services/:serviceId/snapshot", gz(sc.authorizedClient(restSnapshotService))},
-		rest.Route{"PUT", "/services/:serviceId/restartService", gz(sc.authorizedClient(restRestartService))},
-		rest.Route{"PUT", "/services/:serviceId/startService", gz(sc.authorizedClient(restStartService))},
-		rest.Route{"PUT", "/services/:serviceId/stopService", gz(sc.authorizedClient(restStopService))},
-		rest.Route{"POST", "/services/:serviceId/migrate", sc.authorizedClient(restPostServicesForMigration)},
+		rest.Route{"GET", "/services/:serviceId", gz(sc.authorizedClient(sc.tenantScopedClient(restGetService)))},
+		rest.Route{"GET", "/services/:serviceId/running", gz(sc.authorizedClient(sc.tenantScopedClient(restGetRunningForService)))},
+		rest.Route{"GET", "/services/:serviceId/:serviceStateId/logs", gz(sc.authorizedClient(sc.tenantScopedClient(restGetServiceStateLogs)))},
+		rest.Route{"GET", "/services/:serviceId/:serviceStateId/logs/download", gz(sc.authorizedClient(sc.tenantScopedClient(downloadServiceStateLogs)))},
+		rest.Route{"POST", "/services/add", gz(sc.checkAuth(restAddService))},
+		rest.Route{"POST", "/services/deploy", gz(sc.checkAuth(restDeployService))},
+		rest.Route{"DELETE", "/services/:serviceId", gz(sc.authorizedClient(sc.tenantScopedClient(restRemoveService)))},
+		rest.Route{"GET", "/services/:serviceId/logs", gz(sc.authorizedClient(sc.tenantScopedClient(restGetServiceLogs)))},
+		rest.Route{"PUT", "/services/:serviceId", gz(sc.authorizedClient(sc.tenantScopedClient(restUpdateService)))},
+		rest.Route{"GET", "/services/:serviceId/snapshot", gz(sc.authorizedClient(sc.tenantScopedClient(restSnapshotService)))},
+		rest.Route{"GET", "/services/:serviceId/snapshots", gz(sc.authorizedClient(sc.tenantScopedClient(restGetServiceSnapshots)))},
+		rest.Route{"PUT", "/services/:serviceId/restartService", gz(sc.authorizedClient(sc.tenantScopedClient(restRestartService)))},
+		rest.Route{"PUT", "/services/:serviceId/startService", gz(sc.authorizedClient(sc.tenantScopedClient(restStartService)))},
+		rest.Route{"PUT", "/services/:serviceId/stopService", gz(sc.authorizedClient(sc.tenantScopedClient(restStopService)))},
+		rest.Route{"POST", "/services/:serviceId/migrate", sc.authorizedClient(sc.tenantScopedClient(restPostServicesForMigration))},
 
 		// Services (Virtual Host)
-		rest.Route{"GET", "/services/vhosts", gz(sc.authorizedClient(restGetVirtualHosts))},
-		rest.Route{"PUT", "/services/:serviceId/endpoint/:application/vhosts/*name", gz(sc.checkAuth(restAddVirtualHost))},
-		rest.Route{"DELETE", "/services/:serviceId/endpoint/:application/vhosts/*name", gz(sc.checkAuth(restRemoveVirtualHost))},
-		rest.Route{"POST", "/services/:serviceId/endpoint/:application/vhosts/*name", gz(sc.checkAuth(restVirtualHostEnable))},
+		rest.Route{"GET", "/services/vhosts", gz(sc.checkAuth(restGetVirtualHosts))},
+		rest.Route{"PUT", "/services/:serviceId/endpoint/:application/vhosts/*name", gz(sc.checkAuth(tenantScoped(restAddVirtualHost)))},
+		rest.Route{"DELETE", "/services/:serviceId/endpoint/:application/vhosts/*name", gz(sc.checkAuth(tenantScoped(restRemoveVirtualHost)))},
+		rest.Route{"POST", "/services/:serviceId/endpoint/:application/vhosts/*name", gz(sc.checkAuth(tenantScoped(restVirtualHostEnable)))},
 
 		// Services (Endpoint Ports)
-		rest.Route{"PUT", "/services/:serviceId/endpoint/:application/ports/*portname", gz(sc.checkAuth(restAddPort))},
-		rest.Route{"DELETE", "/services/:serviceId/endpoint/:application/ports/*portname", gz(sc.checkAuth(restRemovePort))},
-		rest.Route{"POST", "/services/:serviceId/endpoint/:application/ports/*portname", gz(sc.checkAuth(restPortEnable))},
+		rest.Route{"PUT", "/services/:serviceId/endpoint/:application/ports/*portname", gz(sc.checkAuth(tenantScoped(restAddPort)))},
+		rest.Route{"DELETE", "/services/:serviceId/endpoint/:application/ports/*portname", gz(sc.checkAuth(tenantScoped(restRemovePort)))},
+		rest.Route{"POST", "/services/:serviceId/endpoint/:application/ports/*portname", gz(sc.checkAuth(tenantScoped(restPortEnable)))},
 
 		// Services (IP)
-		rest.Route{"PUT", "/services/:serviceId/ip", gz(sc.authorizedClient(restServiceAutomaticAssignIP))},
-		rest.Route{"PUT", "/services/:serviceId/ip/*ip", gz(sc.authorizedClient(restServiceManualAssignIP))},
+		rest.Route{"PUT", "/services/:serviceId/ip", gz(sc.authorizedClient(sc.tenantScopedClient(restServiceAutomaticAssignIP)))},
+		rest.Route{"PUT", "/services/:serviceId/ip/*ip", gz(sc.authorizedClient(sc.tenantScopedClient(restServiceManualAssignIP)))},
 
 		// Service templates (App templates)
 		rest.Route{"GET", "/templates", gz(sc.checkAuth(restGetAppTemplates))},
@@ -103,45 +105,54 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 		rest.Route{"DELETE", "/login", gz(restLogout)},
 
 		// "Misc" stuff
-		rest.Route{"GET", "/top/services", gz(sc.authorizedClient(restGetTopServices))},
+		rest.Route{"GET", "/top/services", gz(sc.checkAuth(restGetTopServices))},
 		rest.Route{"GET", "/config", gz(sc.authorizedClient(restGetUIConfig))},
 		rest.Route{"GET", "/servicestatus", gz(sc.checkAuth(restGetConciseServiceStatus))},
 
-		// Generic static data
-		rest.Route{"GET", "/favicon.ico", gz(favIcon)},
-		rest.Route{"GET", "/static/*resource", gz(staticData)},
-		rest.Route{"GET", "/licenses.html", gz(licenses)},
+		// Health and readiness probes
+		rest.Route{"GET", "/healthz", sc.restGetHealthz},
+		rest.Route{"HEAD", "/healthz", sc.restGetHealthz},
+		rest.Route{"GET", "/readyz", sc.restGetReadyz},
+		rest.Route{"HEAD", "/readyz", sc.restGetReadyz},
 
 		// Info about serviced itself
 		rest.Route{"GET", "/dockerIsLoggedIn", gz(sc.authorizedClient(restDockerIsLoggedIn))},
 		rest.Route{"GET", "/stats", gz(sc.isCollectingStats())},
 		rest.Route{"GET", "/version", gz(sc.authorizedClient(restGetServicedVersion))},
 		rest.Route{"GET", "/storage", gz(sc.authorizedClient(restGetStorage))},
+		rest.Route{"GET", "/dfs/metrics", gz(restGetDFSMetrics)},
+		rest.Route{"GET", "/rpc/metrics", gz(restGetRPCMetrics)},
 
 		// V2 API
 		rest.Route{"GET", "/api/v2/pools", gz(sc.checkAuth(getPools))},
 		rest.Route{"GET", "/api/v2/pools/:poolId/hosts", gz(sc.checkAuth(getHostsForPool))},
 		rest.Route{"GET", "/api/v2/hosts", gz(sc.checkAuth(getHosts))},
+		rest.Route{"GET", "/api/v2/dfs/status", gz(sc.checkAuth(getDFSStatus))},
+		rest.Route{"GET", "/api/v2/backups", gz(sc.checkAuth(getBackups))},
 		rest.Route{"GET", "/api/v2/hosts/:hostId/instances", gz(sc.checkAuth(restGetHostInstances))},
 		rest.Route{"GET", "/api/v2/services", gz(sc.checkAuth(getAllServiceDetails))},
-		rest.Route{"GET", "/api/v2/services/:serviceId", gz(sc.checkAuth(getServiceDetails))},
-		rest.Route{"PUT", "/api/v2/services/:serviceId", gz(sc.checkAuth(putServiceDetails))},
-		rest.Route{"GET", "/api/v2/services/:serviceId/services", gz(sc.checkAuth(getChildServiceDetails))},
-		rest.Route{"GET", "/api/v2/services/:serviceId/instances", gz(sc.checkAuth(restGetServiceInstances))},
-		rest.Route{"GET", "/api/v2/services/:serviceId/monitoringprofile", gz(sc.checkAuth(restGetServiceMonitoringProfile))},
-		rest.Route{"GET", "/api/v2/services/:serviceId/publicendpoints", gz(sc.checkAuth(restGetServicePublicEndpoints))},
-		rest.Route{"GET", "/api/v2/services/:serviceId/ipassignments", gz(sc.checkAuth(restGetServiceIPAssignments))},
-		rest.Route{"GET", "/api/v2/services/:serviceId/exportendpoints", gz(sc.checkAuth(restGetServiceExportedEndpoints))},
-		rest.Route{"GET", "/api/v2/services/:serviceId/context", gz(sc.checkAuth(getServiceContext))},
-		rest.Route{"PUT", "/api/v2/services/:serviceId/context", gz(sc.checkAuth(putServiceContext))},
+		rest.Route{"GET", "/api/v2/services/:serviceId", gz(sc.checkAuth(tenantScoped(getServiceDetails)))},
+		rest.Route{"PUT", "/api/v2/services/:serviceId", gz(sc.checkAuth(tenantScoped(putServiceDetails)))},
+		rest.Route{"GET", "/api/v2/services/:serviceId/services", gz(sc.checkAuth(tenantScoped(getChildServiceDetails)))},
+		rest.Route{"GET", "/api/v2/services/:serviceId/instances", gz(sc.checkAuth(tenantScoped(restGetServiceInstances)))},
+		rest.Route{"GET", "/api/v2/services/:serviceId/monitoringprofile", gz(sc.checkAuth(tenantScoped(restGetServiceMonitoringProfile)))},
+		rest.Route{"GET", "/api/v2/services/:serviceId/publicendpoints", gz(sc.checkAuth(tenantScoped(restGetServicePublicEndpoints)))},
+		rest.Route{"GET", "/api/v2/services/:serviceId/ipassignments", gz(sc.checkAuth(tenantScoped(restGetServiceIPAssignments)))},
+		rest.Route{"GET", "/api/v2/services/:serviceId/exportendpoints", gz(sc.checkAuth(tenantScoped(restGetServiceExportedEndpoints)))},
+		rest.Route{"GET", "/api/v2/services/:serviceId/context", gz(sc.checkAuth(tenantScoped(getServiceContext)))},
+		rest.Route{"PUT", "/api/v2/services/:serviceId/context", gz(sc.checkAuth(tenantScoped(putServiceContext)))},
+		rest.Route{"GET", "/api/v2/services/:serviceId/tags", gz(sc.checkAuth(tenantScoped(getServiceTags)))},
+		rest.Route{"PUT", "/api/v2/services/:serviceId/tags", gz(sc.checkAuth(tenantScoped(putServiceTags)))},
 		rest.Route{"GET", "/api/v2/statuses", gz(sc.checkAuth(restGetAggregateServices))},
 		rest.Route{"GET", "/api/v2/hoststatuses", gz(sc.checkAuth(getHostStatuses))},
 
-		rest.Route{"GET", "/api/v2/services/:serviceId/serviceconfigs", gz(sc.checkAuth(restGetServiceConfigFiles))},
-		rest.Route{"POST", "/api/v2/services/:serviceId/serviceconfigs", gz(sc.checkAuth(restAddServiceConfigFile))},
+		rest.Route{"GET", "/api/v2/services/:serviceId/serviceconfigs", gz(sc.checkAuth(tenantScoped(restGetServiceConfigFiles)))},
+		rest.Route{"POST", "/api/v2/services/:serviceId/serviceconfigs", gz(sc.checkAuth(tenantScoped(restAddServiceConfigFile)))},
 		rest.Route{"GET", "/api/v2/serviceconfigs/:fileId", gz(sc.checkAuth(restGetServiceConfigFile))},
 		rest.Route{"PUT", "/api/v2/serviceconfigs/:fileId", gz(sc.checkAuth(restUpdateServiceConfigFile))},
 		rest.Route{"DELETE", "/api/v2/serviceconfigs/:fileId", gz(sc.checkAuth(restDeleteServiceConfigFile))},
+		rest.Route{"GET", "/api/v2/services/:serviceId/serviceconfigconflicts", gz(sc.checkAuth(tenantScoped(restGetServiceConfigConflicts)))},
+		rest.Route{"PUT", "/api/v2/serviceconfigs/:fileId/conflict", gz(sc.checkAuth(restResolveServiceConfigConflict))},
 	}
 
 	// Hardcoding these target URLs for now.
@@ -151,6 +162,20 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 	routes = routeToInternalServiceProxy("/metrics/api", "http://127.0.0.1:8888/api", true, routes)
 	routes = routeToInternalServiceProxy("/api/controlplane/kibana", "http://127.0.0.1:5601", true, routes)
 
+	// A headless server only serves the json api
+	if sc.headless {
+		return routes
+	}
+
+	routes = append(routes,
+		rest.Route{"GET", "/", gz(mainPage)},
+
+		// Generic static data
+		rest.Route{"GET", "/favicon.ico", gz(favIcon)},
+		rest.Route{"GET", "/static/*resource", gz(staticData)},
+		rest.Route{"GET", "/licenses.html", gz(licenses)},
+	)
+
 	// Allow static assets for metrics data to be loaded without authentication since they are
 	// included in index.html by default.
 	routes = routeToInternalServiceProxy("/metrics/static", "http://127.0.0.1:8888/static", false, routes)
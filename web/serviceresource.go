@@ -19,7 +19,6 @@ import (
 	daoclient "github.com/control-center/serviced/dao/client"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/health"
-	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 
 	"net/url"
@@ -29,14 +28,14 @@ import (
 func restServiceAutomaticAssignIP(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
 	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
 	if err != nil {
-		glog.Errorf("Could not get serviceId: %v", err)
+		plog.Errorf("Could not get serviceId: %v", err)
 		restBadRequest(w, err)
 		return
 	}
 
 	request := addressassignment.AssignmentRequest{ServiceID: serviceID, IPAddress: "", AutoAssignment: true}
 	if err := client.AssignIPs(request, nil); err != nil {
-		glog.Errorf("Failed to automatically assign IPs: %+v -> %v", request, err)
+		plog.Errorf("Failed to automatically assign IPs: %+v -> %v", request, err)
 		restServerError(w, err)
 		return
 	}
@@ -48,21 +47,21 @@ func restServiceAutomaticAssignIP(w *rest.ResponseWriter, r *rest.Request, clien
 func restServiceManualAssignIP(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
 	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
 	if err != nil {
-		glog.Errorf("Could not get serviceId: %v", err)
+		plog.Errorf("Could not get serviceId: %v", err)
 		restBadRequest(w, err)
 		return
 	}
 
 	ip, err := url.QueryUnescape(r.PathParam("ip"))
 	if err != nil {
-		glog.Errorf("Could not get serviceId: %v", err)
+		plog.Errorf("Could not get serviceId: %v", err)
 		restBadRequest(w, err)
 		return
 	}
 
 	request := addressassignment.AssignmentRequest{ServiceID: serviceID, IPAddress: ip, AutoAssignment: false}
 	if err := client.AssignIPs(request, nil); err != nil {
-		glog.Errorf("Failed to manually assign IP: %+v -> %v", request, err)
+		plog.Errorf("Failed to manually assign IP: %+v -> %v", request, err)
 		restServerError(w, err)
 		return
 	}
@@ -74,7 +73,7 @@ func restServiceManualAssignIP(w *rest.ResponseWriter, r *rest.Request, client *
 func restGetServicesHealth(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	healthStatuses, err := ctx.getFacade().GetServicesHealth(ctx.getDatastoreContext())
 	if err != nil {
-		glog.Errorf("Could not get services health: %s", err)
+		plog.Errorf("Could not get services health: %s", err)
 		restServerError(w, err)
 		return
 	}
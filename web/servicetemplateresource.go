@@ -19,7 +19,6 @@ import (
 	"net/http"
 	"net/url"
 
-	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 
 	"github.com/control-center/serviced/domain/addressassignment"
@@ -83,17 +82,17 @@ func restDeployAppTemplate(w *rest.ResponseWriter, r *rest.Request, ctx *request
 	var payload servicetemplate.ServiceTemplateDeploymentRequest
 	err := r.DecodeJsonPayload(&payload)
 	if err != nil {
-		glog.V(1).Info("Could not decode deployment payload: ", err)
+		plog.Debug("Could not decode deployment payload: ", err)
 		restBadRequest(w, err)
 		return
 	}
 	tenantIDs, err := ctx.getFacade().DeployTemplate(ctx.getDatastoreContext(), payload.PoolID, payload.TemplateID, payload.DeploymentID)
 	if err != nil {
-		glog.Error("Could not deploy template: ", err)
+		plog.Error("Could not deploy template: ", err)
 		restServerError(w, err)
 		return
 	}
-	glog.V(0).Info("Deployed template ", payload)
+	plog.Info("Deployed template ", payload)
 
 	// FIXME: This REST implementation isn't compatible with the Facade implementation - if the Facade ever returns
 	//        more than one value, then the code below will only return a result for the first value.
@@ -104,10 +103,10 @@ func restDeployAppTemplate(w *rest.ResponseWriter, r *rest.Request, ctx *request
 		assignmentRequest := addressassignment.AssignmentRequest{tenantID, "", true}
 		if err := ctx.getFacade().AssignIPs(ctx.getDatastoreContext(), assignmentRequest); err != nil {
 			// FIXME: This error is never reported to the client
-			glog.Errorf("Could not automatically assign IPs: %v", err)
+			plog.Errorf("Could not automatically assign IPs: %v", err)
 			continue
 		}
-		glog.Infof("Automatically assigned IP addresses to service: %v", tenantID)
+		plog.Infof("Automatically assigned IP addresses to service: %v", tenantID)
 		// end of automatic IP assignment
 		w.WriteJson(&simpleResponse{tenantID, servicesLinks()})
 	}
@@ -117,14 +116,14 @@ func restDeployAppTemplateStatus(w *rest.ResponseWriter, r *rest.Request, ctx *r
 	var payload servicetemplate.ServiceTemplateDeploymentRequest
 	err := r.DecodeJsonPayload(&payload)
 	if err != nil {
-		glog.V(1).Info("Could not decode deployment payload: ", err)
+		plog.Debug("Could not decode deployment payload: ", err)
 		restBadRequest(w, err)
 		return
 	}
 
 	status, err := ctx.getFacade().DeployTemplateStatus(payload.DeploymentID)
 	if err != nil {
-		glog.Errorf("Unexpected error during template status: %v", err)
+		plog.Errorf("Unexpected error during template status: %v", err)
 		writeJSON(w, &simpleResponse{err.Error(), homeLink()}, http.StatusInternalServerError)
 		return
 	}
@@ -134,7 +133,7 @@ func restDeployAppTemplateStatus(w *rest.ResponseWriter, r *rest.Request, ctx *r
 func restDeployAppTemplateActive(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	active, err := ctx.getFacade().DeployTemplateActive()
 	if err != nil {
-		glog.Errorf("Unexpected error during template status: %v", err)
+		plog.Errorf("Unexpected error during template status: %v", err)
 		writeJSON(w, &simpleResponse{err.Error(), homeLink()}, http.StatusInternalServerError)
 		return
 	}
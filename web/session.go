@@ -18,7 +18,6 @@ import (
 	userdomain "github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 
 	"crypto/rand"
@@ -78,7 +77,7 @@ func purgeOldsessionTs() {
 			return
 		}
 
-		glog.V(1).Info("Searching for expired sessions")
+		plog.Debug("Searching for expired sessions")
 		cutoff := time.Now().UTC().Unix() - int64((30 * time.Minute).Seconds())
 		toDel := []string{}
 		for key, value := range sessions {
@@ -87,7 +86,7 @@ func purgeOldsessionTs() {
 			}
 		}
 		for _, key := range toDel {
-			glog.V(0).Infof("Deleting session %s (exceeded max age)", key)
+			plog.Infof("Deleting session %s (exceeded max age)", key)
 			delete(sessions, key)
 		}
 	}
@@ -105,26 +104,63 @@ func purgeOldsessionTs() {
 func loginWithBasicAuthOK(r *rest.Request) bool {
 	cookie, err := r.Request.Cookie(sessionCookie)
 	if err != nil {
-		glog.V(1).Info("Error getting cookie ", err)
+		plog.Debug("Error getting cookie ", err)
 		return false
 	}
 	sessionsLock.Lock()
 	defer sessionsLock.Unlock()
 	value, err := url.QueryUnescape(strings.Replace(cookie.Value, "+", url.QueryEscape("+"), -1))
 	if err != nil {
-		glog.Warning("Unable to decode session ", cookie.Value)
+		plog.Warning("Unable to decode session ", cookie.Value)
 		return false
 	}
 	session, err := findsessionT(value)
 	if err != nil {
-		glog.Info("Unable to find session ", value)
+		plog.Info("Unable to find session ", value)
 		return false
 	}
 	session.access = time.Now()
-	glog.V(2).Infof("sessionT %s used", session.ID)
+	plog.Debugf("sessionT %s used", session.ID)
 	return true
 }
 
+// sessionUser returns the name of the user logged in to the request's session,
+// or an empty string if the request was not made with a session.
+func sessionUser(r *rest.Request) string {
+	cookie, err := r.Request.Cookie(sessionCookie)
+	if err != nil {
+		return ""
+	}
+	value, err := url.QueryUnescape(strings.Replace(cookie.Value, "+", url.QueryEscape("+"), -1))
+	if err != nil {
+		return ""
+	}
+	sessionsLock.RLock()
+	defer sessionsLock.RUnlock()
+	session, err := findsessionT(value)
+	if err != nil {
+		return ""
+	}
+	return session.User
+}
+
+// restTokenTenant returns the tenant that the rest token of the request was
+// issued to, and whether the request was made with a rest token at all.
+// Returns an error if the request has a rest token that cannot be parsed.
+func restTokenTenant(r *rest.Request) (string, bool, error) {
+	token, err := auth.ExtractRestToken(r.Request)
+	if err != nil {
+		return "", true, err
+	} else if token == "" {
+		return "", false, nil
+	}
+	restToken, err := auth.ParseRestToken(token)
+	if err != nil {
+		return "", true, err
+	}
+	return restToken.TenantID(), true, nil
+}
+
 func loginWithTokenOK(r *rest.Request, token string) bool {
 	restToken, err := auth.ParseRestToken(token)
 	if err != nil {
@@ -141,6 +177,12 @@ func loginWithTokenOK(r *rest.Request, token string) bool {
 			plog.WithField("url", r.URL.String()).Debug(msg)
 			return false
 		} else {
+			if restToken.TenantID() == "" {
+				// tokens built by delegates that predate tenant scoping are
+				// still accepted, and reach every tenant as they did before
+				msg := "Rest token was not issued to a tenant; access is not limited to a tenant"
+				plog.WithField("url", r.URL.String()).Debug(msg)
+			}
 			return true
 		}
 	}
@@ -165,10 +207,10 @@ func loginOK(r *rest.Request) bool {
 func restLogout(w *rest.ResponseWriter, r *rest.Request) {
 	cookie, err := r.Request.Cookie(sessionCookie)
 	if err != nil {
-		glog.V(1).Info("Unable to read session cookie")
+		plog.Debug("Unable to read session cookie")
 	} else {
 		deleteSessionT(cookie.Value)
-		glog.V(1).Infof("Deleted session %s for explicit logout", cookie.Value)
+		plog.Debugf("Deleted session %s for explicit logout", cookie.Value)
 	}
 
 	http.SetCookie(
@@ -187,13 +229,13 @@ func restLoginWithBasicAuth(w *rest.ResponseWriter, r *rest.Request, ctx *reques
 	creds := login{}
 	err := r.DecodeJsonPayload(&creds)
 	if err != nil {
-		glog.V(1).Info("Unable to decode login payload ", err)
+		plog.Debug("Unable to decode login payload ", err)
 		restBadRequest(w, err)
 		return
 	}
 
 	if creds.Username == "root" && !allowRootLogin {
-		glog.V(1).Info("root login disabled")
+		plog.Debug("root login disabled")
 		writeJSON(w, &simpleResponse{"Root login disabled", loginLink()}, http.StatusUnauthorized)
 		return
 	}
@@ -215,7 +257,7 @@ func restLoginWithBasicAuth(w *rest.ResponseWriter, r *rest.Request, ctx *reques
 		}
 		sessions[session.ID] = session
 
-		glog.V(1).Info("Created authenticated session: ", session.ID)
+		plog.Debug("Created authenticated session: ", session.ID)
 		http.SetCookie(
 			w.ResponseWriter,
 			&http.Cookie{
@@ -273,7 +315,7 @@ func validateLogin(creds *login, client master.ClientInterface) bool {
 }
 
 func cpValidateLogin(creds *login, client master.ClientInterface) bool {
-	glog.V(0).Infof("Attempting to validate user %v against the control center api", creds.Username)
+	plog.Infof("Attempting to validate user %v against the control center api", creds.Username)
 	// create a client
 	user := userdomain.User{
 		Name:     creds.Username,
@@ -283,7 +325,7 @@ func cpValidateLogin(creds *login, client master.ClientInterface) bool {
 	var result bool
 	result, err := client.ValidateCredentials(user)
 	if err != nil {
-		glog.Errorf("Unable to validate credentials %s", err)
+		plog.Errorf("Unable to validate credentials %s", err)
 	}
 
 	return result
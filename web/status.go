@@ -26,7 +26,6 @@ import (
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 )
 
@@ -91,11 +90,11 @@ func convertInstancesToMetric(instances []dao.RunningService) []metrics.ServiceI
 
 func getAllServiceStatuses(ctx *requestContext) (statuses []*ConciseServiceStatus, err error) {
 	// Get all running service instances
-	glog.V(2).Info("Retrieving statuses, memory and health checks for running services")
+	plog.Debug("Retrieving statuses, memory and health checks for running services")
 
 	client, err := ctx.sc.getClient()
 	if err != nil {
-		glog.Errorf("Unable to acquire client: %v", err)
+		plog.Errorf("Unable to acquire client: %v", err)
 		return nil, err
 	}
 
@@ -104,7 +103,7 @@ func getAllServiceStatuses(ctx *requestContext) (statuses []*ConciseServiceStatu
 		return nil, err
 	}
 	if instances == nil {
-		glog.V(3).Info("Nil service list returned")
+		plog.Debug("Nil service list returned")
 		instances = []dao.RunningService{}
 	}
 
@@ -119,12 +118,12 @@ func getAllServiceStatuses(ctx *requestContext) (statuses []*ConciseServiceStatu
 			Instances: convertInstancesToMetric(instances),
 		}
 		if err := client.GetInstanceMemoryStats(query, &response); err != nil {
-			glog.Errorf("Unable to look up instance memory stats (%s)", err)
+			plog.Errorf("Unable to look up instance memory stats (%s)", err)
 		}
 		for _, mus := range response {
 			instID, err := strconv.Atoi(mus.InstanceID)
 			if err != nil {
-				glog.Errorf("Could not convert instance id %s", mus.InstanceID)
+				plog.Errorf("Could not convert instance id %s", mus.InstanceID)
 				continue
 			}
 			memoryStats[memorykey{ServiceID: mus.ServiceID, InstanceID: instID}] = mus
@@ -136,7 +135,7 @@ func getAllServiceStatuses(ctx *requestContext) (statuses []*ConciseServiceStatu
 	if len(instances) > 0 {
 		results, err := ctx.getFacade().GetServicesHealth(ctx.getDatastoreContext())
 		if err != nil {
-			glog.Errorf("Unable to look up health check results (%s)", err)
+			plog.Errorf("Unable to look up health check results (%s)", err)
 		}
 		for svcid, insts := range results {
 			for instid, checks := range insts {
@@ -155,7 +154,7 @@ func getAllServiceStatuses(ctx *requestContext) (statuses []*ConciseServiceStatu
 		} else {
 			results, err := isvcs.Mgr.GetHealthStatus(strings.TrimPrefix(isvc.ServiceID, "isvc-"))
 			if err != nil {
-				glog.Warningf("Error acquiring health status for %s (%s)", isvc.ServiceID, err)
+				plog.Warningf("Error acquiring health status for %s (%s)", isvc.ServiceID, err)
 				continue
 			}
 			checks = convertDomainHealthToNewHealth(results.HealthStatuses)
@@ -196,12 +195,12 @@ func restGetConciseServiceStatus(w *rest.ResponseWriter, r *rest.Request, ctx *r
 	f := func() ([]byte, error) {
 		statuses, err := getAllServiceStatuses(ctx)
 		if err != nil {
-			glog.V(2).Infof("Error retrieving service statuses: (%s)", err)
+			plog.Debugf("Error retrieving service statuses: (%s)", err)
 			return nil, err
 		}
 		bytes, err := json.Marshal(statuses)
 		if err != nil {
-			glog.V(2).Infof("Error serializing service statuses: (%s)", err)
+			plog.Debugf("Error serializing service statuses: (%s)", err)
 			return nil, err
 		}
 		return bytes, nil
@@ -209,7 +208,7 @@ func restGetConciseServiceStatus(w *rest.ResponseWriter, r *rest.Request, ctx *r
 	w.Header().Set("content-type", "application/json")
 	bytes, err := getCached(f)
 	if err != nil {
-		glog.Errorf("Error retrieving service statuses: %s", err)
+		plog.Errorf("Error retrieving service statuses: %s", err)
 		restServerError(w, err)
 	}
 	w.Write(bytes)
@@ -146,6 +146,10 @@
                 // TODO - something more clearly related to auth
                 return "unknown";
 
+            // restarting serviced with its instances still running
+            } else if(status.Restarting){
+                return "unknown";
+
             // not connected
             } else {
                 return "failed";
@@ -21,6 +21,7 @@ import (
 	"path"
 	"runtime"
 
+	"github.com/control-center/serviced/facade"
 	"github.com/zenoss/go-json-rest"
 )
 
@@ -77,9 +78,22 @@ func restUnauthorized(w *rest.ResponseWriter) {
 }
 
 /*
- * Provide a generic response for an oopsie.
+ * Inform the user that they may not access the requested resource
+ */
+func restForbidden(w *rest.ResponseWriter) {
+	writeJSON(w, &simpleResponse{"Forbidden", homeLink()}, http.StatusForbidden)
+	return
+}
+
+/*
+ * Provide a generic response for an oopsie, unless the facade denied the
+ * user access to the tenant of the service.
  */
 func restServerError(w *rest.ResponseWriter, err error) {
+	if err == facade.ErrTenantAccessDenied {
+		restForbidden(w)
+		return
+	}
 	writeJSON(w, &simpleResponse{fmt.Sprintf("Internal Server Error: %v", err), homeLink()}, http.StatusInternalServerError)
 	return
 }
@@ -14,7 +14,6 @@
 package web
 
 import (
-	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 	"github.com/control-center/serviced/domain/pool"
 
@@ -31,7 +30,7 @@ func restAddPoolVirtualIP(w *rest.ResponseWriter, r *rest.Request, ctx *requestC
 		return
 	}
 
-	glog.V(0).Infof("Add virtual ip: %+v", request)
+	plog.Infof("Add virtual ip: %+v", request)
 
 	client, err := ctx.getMasterClient()
 	if err != nil {
@@ -40,7 +39,7 @@ func restAddPoolVirtualIP(w *rest.ResponseWriter, r *rest.Request, ctx *requestC
 	}
 
 	if err := client.AddVirtualIP(request); err != nil {
-		glog.Errorf("Failed to add virtual IP(%+v): %v", request, err)
+		plog.Errorf("Failed to add virtual IP(%+v): %v", request, err)
 		restServerError(w, err)
 		return
 	}
@@ -52,19 +51,19 @@ func restAddPoolVirtualIP(w *rest.ResponseWriter, r *rest.Request, ctx *requestC
 func restRemovePoolVirtualIP(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	ip, err := url.QueryUnescape(r.PathParam("ip"))
 	if err != nil {
-		glog.Errorf("Could not get virtual ip (%v): %v", ip, err)
+		plog.Errorf("Could not get virtual ip (%v): %v", ip, err)
 		restBadRequest(w, err)
 		return
 	}
 
 	poolId, err := url.QueryUnescape(r.PathParam("poolId"))
 	if err != nil {
-		glog.Errorf("Could not get virtual ip poolId (%v): %v", poolId, err)
+		plog.Errorf("Could not get virtual ip poolId (%v): %v", poolId, err)
 		restBadRequest(w, err)
 		return
 	}
 
-	glog.V(0).Infof("Remove virtual ip=%v (in pool %v)", ip, poolId)
+	plog.Infof("Remove virtual ip=%v (in pool %v)", ip, poolId)
 
 	client, err := ctx.getMasterClient()
 	if err != nil {
@@ -74,7 +73,7 @@ func restRemovePoolVirtualIP(w *rest.ResponseWriter, r *rest.Request, ctx *reque
 
 	request := pool.VirtualIP{PoolID: poolId, IP: ip}
 	if err := client.RemoveVirtualIP(request); err != nil {
-		glog.Errorf("Failed to remove virtual IP(%+v): %v", request, err)
+		plog.Errorf("Failed to remove virtual IP(%+v): %v", request, err)
 		restServerError(w, err)
 		return
 	}
@@ -21,7 +21,6 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/coordinator/client"
-	"github.com/zenoss/glog"
 )
 
 const (
@@ -106,7 +105,7 @@ func GetLocalConnection(path string) (client.Connection, error) {
 	localclient, ok := manager[local]
 	managerLock.RUnlock()
 	if !ok || localclient.client == nil {
-		glog.Fatalf("zClient has not been initialized!")
+		plog.Fatalf("zClient has not been initialized!")
 	}
 	return localclient.GetConnection(path)
 }
@@ -222,10 +221,10 @@ func (zconn *zconn) monitor(path string) {
 		if conn == nil {
 			conn, err = zconn.client.GetCustomConnection(path)
 			if err != nil {
-				glog.Warningf("Could not obtain a connection to %s: %s", path, err)
+				plog.Warningf("Could not obtain a connection to %s: %s", path, err)
 			}
 		} else if _, err := conn.Children("/"); err == client.ErrConnectionClosed {
-			glog.Warningf("Could not ping connection to %s: %s", path, err)
+			plog.Warningf("Could not ping connection to %s: %s", path, err)
 			conn = nil
 		}
 
@@ -238,7 +237,7 @@ func (zconn *zconn) monitor(path string) {
 		// if conn is nil, try to create a new connection
 		select {
 		case <-time.After(d.GetDelay()):
-			glog.Infof("Refreshing connection to zookeeper")
+			plog.Infof("Refreshing connection to zookeeper")
 			goto retry
 		case <-zconn.shutdownC:
 			return
@@ -254,10 +253,10 @@ func (zconn *zconn) connect(timeout time.Duration) (client.Connection, error) {
 	case conn := <-connC:
 		return conn, nil
 	case <-time.After(timeout):
-		glog.Warningf("timed out waiting for connection")
+		plog.Warningf("timed out waiting for connection")
 		return nil, ErrTimeout
 	case <-zconn.shutdownC:
-		glog.Warningf("received signal to shutdown")
+		plog.Warningf("received signal to shutdown")
 		return nil, ErrShutdown
 	}
 }
@@ -17,10 +17,13 @@ import (
 	"path"
 
 	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/utils"
-	"github.com/zenoss/glog"
 )
 
+// initialize the package logger
+var plog = logging.PackageLogger()
+
 const (
 	zkAction = "/docker/action"
 )
@@ -33,10 +36,11 @@ func actionPath(nodes ...string) string {
 
 // Action is the request node for initialized a serviced action on a host
 type Action struct {
-	HostID   string
-	DockerID string
-	Command  []string
-	version  interface{}
+	HostID     string
+	DockerID   string
+	Command    []string
+	Diagnostic string // name of the built-in diagnostic action, if any
+	version    interface{}
 }
 
 // Version is an implementation of client.Node
@@ -50,6 +54,12 @@ type ActionHandler interface {
 	AttachAndRun(dockerID string, command []string) ([]byte, error)
 }
 
+// DiagnosticHandler is implemented by action handlers that save the output of
+// built-in diagnostic actions
+type DiagnosticHandler interface {
+	SaveDiagnostic(dockerID, action string, output []byte, err error)
+}
+
 // ActionListener is the listener object for /docker/actions
 type ActionListener struct {
 	conn    client.Connection
@@ -82,26 +92,29 @@ func (l *ActionListener) PostProcess(p map[string]struct{}) {}
 // Spawn attaches to a container and performs the requested action
 func (l *ActionListener) Spawn(shutdown <-chan interface{}, actionID string) {
 	defer func() {
-		glog.V(2).Infof("Action %s complete: ", actionID)
+		plog.Debugf("Action %s complete: ", actionID)
 		if err := l.conn.Delete(l.GetPath(actionID)); err != nil {
-			glog.Errorf("Could not delete %s: %s", l.GetPath(actionID), err)
+			plog.Errorf("Could not delete %s: %s", l.GetPath(actionID), err)
 		}
 	}()
 
 	var action Action
 	if err := l.conn.Get(l.GetPath(actionID), &action); err != nil {
-		glog.V(1).Infof("Could not get action %s: %s", l.GetPath(actionID), err)
+		plog.Debugf("Could not get action %s: %s", l.GetPath(actionID), err)
 		return
 	}
 
 	result, err := l.handler.AttachAndRun(action.DockerID, action.Command)
 	if result != nil && len(result) > 0 {
-		glog.Info(string(result))
+		plog.Info(string(result))
 	}
 	if err != nil {
-		glog.Warningf("Error running command `%s` on container %s: %s", action.Command, action.DockerID, err)
+		plog.Warningf("Error running command `%s` on container %s: %s", action.Command, action.DockerID, err)
 	} else {
-		glog.V(1).Infof("Successfully ran command `%s` on container %s", action.Command, action.DockerID)
+		plog.Debugf("Successfully ran command `%s` on container %s", action.Command, action.DockerID)
+	}
+	if handler, ok := l.handler.(DiagnosticHandler); ok && action.Diagnostic != "" {
+		handler.SaveDiagnostic(action.DockerID, action.Diagnostic, result, err)
 	}
 }
 
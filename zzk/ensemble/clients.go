@@ -0,0 +1,136 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ensemble
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// ClientFileName is the name of the file in the serviced etc path where a
+// host records the client addresses of the managed ensemble
+const ClientFileName = "zk-ensemble.json"
+
+// clientFile is the content of the client file
+type clientFile struct {
+	Configured []string // SERVICED_ZK when the file was written
+	Clients    []string // client addresses of the managed ensemble
+}
+
+// ReadClientFile returns the client addresses of the managed ensemble that
+// were last recorded by this host, which replace its SERVICED_ZK list.  The
+// file is ignored if SERVICED_ZK was changed since it was written, so that
+// manual changes take precedence.
+func ReadClientFile(filename string, configured []string) ([]string, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var f clientFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if !sameAddresses(f.Configured, configured) {
+		return nil, nil
+	}
+	return f.Clients, nil
+}
+
+// writeClientFile atomically replaces the client file
+func writeClientFile(filename string, configured, clients []string) error {
+	data, err := json.MarshalIndent(clientFile{Configured: configured, Clients: clients}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+// WatchEnsemble records the client addresses of the managed ensemble in the
+// client file whenever the ensemble changes.  The new addresses are used the
+// next time serviced starts.
+func WatchEnsemble(shutdown <-chan interface{}, conn client.Connection, filename string, configured []string) {
+	logger := plog.WithField("file", filename)
+	current, _ := ReadClientFile(filename, configured)
+	for {
+		done := make(chan struct{})
+		e := &Ensemble{}
+		evC, err := conn.GetW(ensemblePath, e, done)
+		if err == client.ErrNoNode {
+			var ok bool
+			if ok, evC, err = conn.ExistsW(ensemblePath, done); err == nil && ok {
+				// the ensemble was created while we were looking
+				close(done)
+				continue
+			}
+		} else if err == nil && len(e.Servers) > 0 {
+			if clients := e.ClientAddresses(); !sameAddresses(clients, current) {
+				if err := writeClientFile(filename, configured, clients); err != nil {
+					logger.WithError(err).Warn("Could not record the ZooKeeper ensemble")
+				} else {
+					current = clients
+					logger.WithFields(log.Fields{
+						"ensemble": clients,
+					}).Info("Recorded the ZooKeeper ensemble; it will be used when serviced restarts")
+				}
+			}
+		}
+		if err != nil {
+			logger.WithError(err).Warn("Could not watch the ZooKeeper ensemble")
+			close(done)
+			select {
+			case <-time.After(5 * time.Second):
+				continue
+			case <-shutdown:
+				return
+			}
+		}
+
+		select {
+		case <-evC:
+		case <-shutdown:
+			close(done)
+			return
+		}
+		close(done)
+	}
+}
+
+func sameAddresses(a, b []string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
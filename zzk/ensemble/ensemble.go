@@ -0,0 +1,429 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ensemble
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/logging"
+)
+
+// initialize the package logger
+var plog = logging.PackageLogger()
+
+const (
+	ensemblePath = "/ensemble"
+
+	// DefaultClientPort is the client port of a server if none is specified
+	DefaultClientPort = 2181
+
+	// DefaultCheckTimeout is how long to wait for a server to respond to a
+	// health check
+	DefaultCheckTimeout = 5 * time.Second
+)
+
+var (
+	// ErrServerExists is returned when adding a server that is already a
+	// member of the ensemble
+	ErrServerExists = errors.New("server is already a member of the ensemble")
+	// ErrServerNotFound is returned when removing a server that is not a
+	// member of the ensemble
+	ErrServerNotFound = errors.New("server is not a member of the ensemble")
+	// ErrLastServer is returned when removing the last server of the ensemble
+	ErrLastServer = errors.New("cannot remove the last server of the ensemble")
+)
+
+// Server is a member of the ZooKeeper ensemble
+type Server struct {
+	ID           string // id of the server in the quorum (e.g. zk1)
+	Host         string
+	PeerPort     int
+	ElectionPort int
+	ClientPort   int
+}
+
+// ParseServer parses a server of the form ID@HOST:PEERPORT:ELECTIONPORT with
+// an optional :CLIENTPORT, which is the format of the isvcs zookeeper quorum.
+func ParseServer(s string) (Server, error) {
+	parts := strings.SplitN(s, "@", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return Server{}, fmt.Errorf("server %q must be of the form ID@HOST:PEERPORT:ELECTIONPORT[:CLIENTPORT]", s)
+	}
+	fields := strings.Split(parts[1], ":")
+	if len(fields) != 3 && len(fields) != 4 || fields[0] == "" {
+		return Server{}, fmt.Errorf("server %q must be of the form ID@HOST:PEERPORT:ELECTIONPORT[:CLIENTPORT]", s)
+	}
+	if len(fields) == 3 {
+		fields = append(fields, strconv.Itoa(DefaultClientPort))
+	}
+	ports := make([]int, 3)
+	for i, field := range fields[1:] {
+		port, err := strconv.Atoi(field)
+		if err != nil || port <= 0 || port > 65535 {
+			return Server{}, fmt.Errorf("server %q has an invalid port %q", s, field)
+		}
+		ports[i] = port
+	}
+	return Server{
+		ID:           parts[0],
+		Host:         fields[0],
+		PeerPort:     ports[0],
+		ElectionPort: ports[1],
+		ClientPort:   ports[2],
+	}, nil
+}
+
+// String returns the server in the format of the isvcs zookeeper quorum
+func (s Server) String() string {
+	return fmt.Sprintf("%s@%s:%d:%d", s.ID, s.Host, s.PeerPort, s.ElectionPort)
+}
+
+// ClientAddress returns the address clients use to connect to the server
+func (s Server) ClientAddress() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.ClientPort))
+}
+
+// Ensemble is the managed membership of the ZooKeeper ensemble
+type Ensemble struct {
+	Servers []Server
+	Updated time.Time
+	version interface{}
+}
+
+// Version implements client.Node
+func (e *Ensemble) Version() interface{} { return e.version }
+
+// SetVersion implements client.Node
+func (e *Ensemble) SetVersion(version interface{}) { e.version = version }
+
+// ClientAddresses returns the client addresses of the servers, which is the
+// SERVICED_ZK list of the hosts
+func (e *Ensemble) ClientAddresses() []string {
+	addrs := make([]string, len(e.Servers))
+	for i, s := range e.Servers {
+		addrs[i] = s.ClientAddress()
+	}
+	return addrs
+}
+
+// Quorum returns the servers in the format of the isvcs zookeeper quorum,
+// which is the SERVICED_ISVCS_ZOOKEEPER_QUORUM list of the members
+func (e *Ensemble) Quorum() []string {
+	quorum := make([]string, len(e.Servers))
+	for i, s := range e.Servers {
+		quorum[i] = s.String()
+	}
+	return quorum
+}
+
+// index returns the position of the server with the id, or -1
+func (e *Ensemble) index(id string) int {
+	for i, s := range e.Servers {
+		if s.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// ServerStatus is the health of a single server of the ensemble
+type ServerStatus struct {
+	Server
+	Mode  string // leader, follower, standalone, or empty if unreachable
+	Zxid  string
+	Error string
+}
+
+// Serving returns true if the server is participating in the quorum
+func (s ServerStatus) Serving() bool {
+	return s.Error == "" && s.Mode != ""
+}
+
+// Status is the health of the quorum of the ensemble
+type Status struct {
+	Servers  []ServerStatus
+	Leader   string   // id of the leader, if any
+	Healthy  bool     // true if a majority of the servers are serving
+	Problems []string // problems found with the servers or the quorum
+	Quorum   []string // SERVICED_ISVCS_ZOOKEEPER_QUORUM for the members
+	Clients  []string // SERVICED_ZK for the hosts
+}
+
+// GetEnsemble returns the managed ensemble.  The ensemble has no servers if
+// it has not been configured.
+func GetEnsemble(conn client.Connection) (*Ensemble, error) {
+	e := &Ensemble{Servers: []Server{}}
+	if err := conn.Get(ensemblePath, e); err != nil && err != client.ErrNoNode {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Initialize sets up the managed ensemble with the servers if it has not been
+// configured yet
+func Initialize(conn client.Connection, servers []Server) error {
+	if len(servers) == 0 {
+		return nil
+	}
+	e := &Ensemble{Servers: servers, Updated: time.Now()}
+	if err := conn.Create(ensemblePath, e); err != nil && err != client.ErrNodeExists {
+		return err
+	}
+	return nil
+}
+
+// update applies the change to the managed ensemble, failing if the ensemble
+// was modified concurrently
+func update(conn client.Connection, change func(*Ensemble) error) (*Ensemble, error) {
+	e, err := GetEnsemble(conn)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(e); err != nil {
+		return nil, err
+	}
+	e.Updated = time.Now()
+	if e.Version() == nil {
+		err = conn.Create(ensemblePath, e)
+	} else {
+		err = conn.Set(ensemblePath, e)
+	}
+	if err == client.ErrBadVersion || err == client.ErrNodeExists {
+		return nil, errors.New("the ensemble was modified concurrently; try again")
+	} else if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// AddServer records a server that was added to the ensemble.  ZooKeeper
+// 3.4 cannot be reconfigured dynamically, so the server must already have
+// been started as a member of the quorum with every other member configured
+// to include it.  Unless force is set, the change is refused if the quorum is
+// not healthy before the change or would not be healthy after it.  Returns
+// the health of the quorum after the change.
+func AddServer(conn client.Connection, server Server, force bool) (*Status, error) {
+	logger := plog.WithField("server", server.String())
+	e, err := update(conn, func(e *Ensemble) error {
+		if e.index(server.ID) >= 0 {
+			return ErrServerExists
+		}
+		proposed := &Ensemble{Servers: append(append([]Server{}, e.Servers...), server)}
+		if !force {
+			if err := verifyAdd(e, proposed, server.ID); err != nil {
+				return err
+			}
+		}
+		e.Servers = proposed.Servers
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Added server to the ZooKeeper ensemble")
+	status := CheckQuorum(e, DefaultCheckTimeout)
+	return &status, nil
+}
+
+// RemoveServer records that the server with the id was removed from the
+// ensemble.  The server must already have been stopped and the remaining
+// members configured without it.  Unless force is set, the change is refused
+// if the quorum is not healthy before the change or would not be healthy
+// after it.  Returns the health of the quorum after the change.
+func RemoveServer(conn client.Connection, id string, force bool) (*Status, error) {
+	logger := plog.WithField("id", id)
+	e, err := update(conn, func(e *Ensemble) error {
+		i := e.index(id)
+		if i < 0 {
+			return ErrServerNotFound
+		} else if len(e.Servers) == 1 {
+			return ErrLastServer
+		}
+		proposed := &Ensemble{Servers: append(append([]Server{}, e.Servers[:i]...), e.Servers[i+1:]...)}
+		if !force {
+			if err := verifyRemove(e, proposed, id); err != nil {
+				return err
+			}
+		}
+		e.Servers = proposed.Servers
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Removed server from the ZooKeeper ensemble")
+	status := CheckQuorum(e, DefaultCheckTimeout)
+	return &status, nil
+}
+
+// verifyAdd checks that the quorum is healthy before and after the server
+// with the id is added, and that the server is participating in the quorum.
+func verifyAdd(current, proposed *Ensemble, id string) error {
+	if status := CheckQuorum(current, DefaultCheckTimeout); !status.Healthy {
+		return fmt.Errorf("quorum is not healthy: %s", strings.Join(status.Problems, "; "))
+	}
+	status := CheckQuorum(proposed, DefaultCheckTimeout)
+	if s := status.Servers[proposed.index(id)]; !isMember(s) {
+		return fmt.Errorf("server %s is not participating in the quorum; configure every member with SERVICED_ISVCS_ZOOKEEPER_QUORUM=%s and start it first", id, strings.Join(status.Quorum, ","))
+	}
+	if !status.Healthy {
+		return fmt.Errorf("quorum would not be healthy after adding server %s: %s", id, strings.Join(status.Problems, "; "))
+	}
+	return nil
+}
+
+// verifyRemove checks that the quorum is healthy before and after the server
+// with the id is removed, and that the server is no longer participating in
+// the quorum.
+func verifyRemove(current, proposed *Ensemble, id string) error {
+	status := CheckQuorum(current, DefaultCheckTimeout)
+	if !status.Healthy {
+		return fmt.Errorf("quorum is not healthy: %s", strings.Join(status.Problems, "; "))
+	}
+	if s := status.Servers[current.index(id)]; isMember(s) {
+		return fmt.Errorf("server %s is still participating in the quorum; configure the remaining members with SERVICED_ISVCS_ZOOKEEPER_QUORUM=%s and stop it first", id, strings.Join(proposed.Quorum(), ","))
+	}
+	if status := CheckQuorum(proposed, DefaultCheckTimeout); !status.Healthy {
+		return fmt.Errorf("quorum would not be healthy after removing server %s: %s", id, strings.Join(status.Problems, "; "))
+	}
+	return nil
+}
+
+// isMember returns true if the server is the leader or a follower of a quorum
+func isMember(s ServerStatus) bool {
+	return s.Serving() && (s.Mode == "leader" || s.Mode == "follower")
+}
+
+// GetStatus returns the health of the quorum of the managed ensemble
+func GetStatus(conn client.Connection) (*Status, error) {
+	e, err := GetEnsemble(conn)
+	if err != nil {
+		return nil, err
+	}
+	status := CheckQuorum(e, DefaultCheckTimeout)
+	return &status, nil
+}
+
+// CheckQuorum checks every server of the ensemble.  The quorum is healthy if
+// a majority of the servers are serving and they agree on a single leader.
+func CheckQuorum(e *Ensemble, timeout time.Duration) Status {
+	status := Status{
+		Servers:  make([]ServerStatus, len(e.Servers)),
+		Quorum:   e.Quorum(),
+		Clients:  e.ClientAddresses(),
+		Problems: []string{},
+	}
+	if len(e.Servers) == 0 {
+		status.Problems = append(status.Problems, "no servers are configured")
+		return status
+	}
+
+	serving, leaders := 0, 0
+	for i, server := range e.Servers {
+		s := CheckServer(server, timeout)
+		status.Servers[i] = s
+		if !s.Serving() {
+			status.Problems = append(status.Problems, fmt.Sprintf("server %s is not serving: %s", s.ID, s.Error))
+			continue
+		}
+		switch s.Mode {
+		case "leader":
+			leaders++
+			status.Leader = s.ID
+		case "standalone":
+			if len(e.Servers) > 1 {
+				// not part of the quorum
+				status.Problems = append(status.Problems, fmt.Sprintf("server %s is running standalone", s.ID))
+				continue
+			}
+			leaders++
+			status.Leader = s.ID
+		}
+		serving++
+	}
+
+	majority := len(e.Servers)/2 + 1
+	if serving < majority {
+		status.Problems = append(status.Problems, fmt.Sprintf("%d of %d servers are serving; a quorum requires %d", serving, len(e.Servers), majority))
+	}
+	if leaders == 0 {
+		status.Problems = append(status.Problems, "no server is the leader")
+	} else if leaders > 1 {
+		status.Leader = ""
+		status.Problems = append(status.Problems, fmt.Sprintf("%d servers claim to be the leader", leaders))
+	}
+	status.Healthy = serving >= majority && leaders == 1
+	if !status.Healthy {
+		plog.WithFields(log.Fields{
+			"problems": strings.Join(status.Problems, "; "),
+		}).Debug("ZooKeeper quorum is not healthy")
+	}
+	return status
+}
+
+// CheckServer asks the server for its mode in the quorum
+func CheckServer(server Server, timeout time.Duration) ServerStatus {
+	status := ServerStatus{Server: server}
+	reply, err := FourLetterWord(server.ClientAddress(), "srvr", timeout)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(reply))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		switch strings.TrimSpace(fields[0]) {
+		case "Mode":
+			status.Mode = strings.TrimSpace(fields[1])
+		case "Zxid":
+			status.Zxid = strings.TrimSpace(fields[1])
+		}
+	}
+	if status.Mode == "" {
+		// servers that are not part of a quorum reply with an error message
+		status.Error = strings.TrimSpace(string(reply))
+		if status.Error == "" {
+			status.Error = "no reply"
+		}
+	}
+	return status
+}
+
+// FourLetterWord sends a four letter command (ruok, srvr, stat, ...) to the
+// ZooKeeper server at address and returns its reply
+func FourLetterWord(address, command string, timeout time.Duration) ([]byte, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write([]byte(command)); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(conn)
+}
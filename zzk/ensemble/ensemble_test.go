@@ -0,0 +1,227 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package ensemble
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseServer(t *testing.T) {
+	s, err := ParseServer("2@zk2:2888:3888")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := Server{ID: "2", Host: "zk2", PeerPort: 2888, ElectionPort: 3888, ClientPort: DefaultClientPort}
+	if s != expected {
+		t.Errorf("Expected %+v, got %+v", expected, s)
+	}
+	if s.String() != "2@zk2:2888:3888" {
+		t.Errorf("Unexpected quorum member %s", s)
+	}
+
+	s, err = ParseServer("3@zk3:2888:3888:2182")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if s.ClientAddress() != "zk3:2182" {
+		t.Errorf("Unexpected client address %s", s.ClientAddress())
+	}
+
+	for _, bad := range []string{"zk2:2888:3888", "@zk2:2888:3888", "2@zk2:2888", "2@:2888:3888", "2@zk2:2888:abc", "2@zk2:2888:3888:0"} {
+		if _, err := ParseServer(bad); err == nil {
+			t.Errorf("Expected error parsing %q", bad)
+		}
+	}
+}
+
+// fakeServer replies to every four letter word with the given mode
+func fakeServer(t *testing.T, id, mode string) (Server, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Could not listen: %s", err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			buf := make([]byte, 4)
+			conn.Read(buf)
+			conn.Write([]byte("Zookeeper version: 3.4.5\nZxid: 0x100000002\nMode: " + mode + "\n"))
+			conn.Close()
+		}
+	}()
+	port := l.Addr().(*net.TCPAddr).Port
+	return Server{ID: id, Host: "127.0.0.1", PeerPort: 2888, ElectionPort: 3888, ClientPort: port}, func() { l.Close() }
+}
+
+// deadServer returns a server that refuses connections
+func deadServer(t *testing.T, id string) Server {
+	s, stop := fakeServer(t, id, "")
+	stop()
+	return s
+}
+
+func TestCheckQuorum(t *testing.T) {
+	leader, stop1 := fakeServer(t, "1", "leader")
+	defer stop1()
+	follower, stop2 := fakeServer(t, "2", "follower")
+	defer stop2()
+
+	status := CheckQuorum(&Ensemble{Servers: []Server{leader, follower, deadServer(t, "3")}}, time.Second)
+	if !status.Healthy {
+		t.Errorf("Expected a healthy quorum, got %v", status.Problems)
+	}
+	if status.Leader != "1" {
+		t.Errorf("Expected leader 1, got %q", status.Leader)
+	}
+	if s := status.Servers[1]; s.Mode != "follower" || s.Zxid != "0x100000002" {
+		t.Errorf("Unexpected server status %+v", s)
+	}
+	if s := status.Servers[2]; s.Serving() {
+		t.Errorf("Expected server 3 not to be serving")
+	}
+	if len(status.Clients) != 3 || len(status.Quorum) != 3 {
+		t.Errorf("Expected the settings of 3 servers, got %v and %v", status.Clients, status.Quorum)
+	}
+
+	status = CheckQuorum(&Ensemble{Servers: []Server{leader, deadServer(t, "2"), deadServer(t, "3")}}, time.Second)
+	if status.Healthy {
+		t.Errorf("Expected an unhealthy quorum with 1 of 3 servers serving")
+	}
+
+	status = CheckQuorum(&Ensemble{Servers: []Server{follower}}, time.Second)
+	if status.Healthy {
+		t.Errorf("Expected an unhealthy quorum without a leader")
+	}
+
+	standalone, stop3 := fakeServer(t, "1", "standalone")
+	defer stop3()
+	if status := CheckQuorum(&Ensemble{Servers: []Server{standalone}}, time.Second); !status.Healthy {
+		t.Errorf("Expected a single standalone server to be healthy, got %v", status.Problems)
+	}
+	if status := CheckQuorum(&Ensemble{Servers: []Server{standalone, follower}}, time.Second); status.Healthy {
+		t.Errorf("Expected a standalone server in a larger ensemble to be unhealthy")
+	}
+}
+
+func TestClientFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ensemble")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "etc", ClientFileName)
+
+	if _, err := ReadClientFile(filename, nil); err == nil {
+		t.Errorf("Expected error reading a missing file")
+	}
+
+	configured := []string{"zk1:2181"}
+	clients := []string{"zk1:2181", "zk2:2181", "zk3:2181"}
+	if err := writeClientFile(filename, configured, clients); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	actual, err := ReadClientFile(filename, configured)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(actual, clients) {
+		t.Errorf("Expected %v, got %v", clients, actual)
+	}
+
+	// a manual change to SERVICED_ZK takes precedence
+	actual, err = ReadClientFile(filename, []string{"zk4:2181"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if actual != nil {
+		t.Errorf("Expected the file to be ignored, got %v", actual)
+	}
+}
+
+func TestVerifyAdd(t *testing.T) {
+	leader, stop1 := fakeServer(t, "1", "leader")
+	defer stop1()
+	follower, stop2 := fakeServer(t, "2", "follower")
+	defer stop2()
+	current := &Ensemble{Servers: []Server{leader, follower}}
+
+	joined, stop3 := fakeServer(t, "3", "follower")
+	defer stop3()
+	if err := verifyAdd(current, &Ensemble{Servers: []Server{leader, follower, joined}}, "3"); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	// the server was not started as a member of the quorum
+	standalone, stop4 := fakeServer(t, "3", "standalone")
+	defer stop4()
+	for _, s := range []Server{deadServer(t, "3"), standalone} {
+		err := verifyAdd(current, &Ensemble{Servers: []Server{leader, follower, s}}, "3")
+		if err == nil || !strings.Contains(err.Error(), "not participating") {
+			t.Errorf("Expected an error adding a server outside of the quorum, got %v", err)
+		}
+	}
+
+	// the server formed a quorum of its own
+	split, stop5 := fakeServer(t, "3", "leader")
+	defer stop5()
+	if err := verifyAdd(current, &Ensemble{Servers: []Server{leader, follower, split}}, "3"); err == nil || !strings.Contains(err.Error(), "would not be healthy") {
+		t.Errorf("Expected an error adding a second leader, got %v", err)
+	}
+
+	// the quorum is already unhealthy
+	current = &Ensemble{Servers: []Server{leader, deadServer(t, "2")}}
+	if err := verifyAdd(current, &Ensemble{Servers: []Server{leader, deadServer(t, "2"), joined}}, "3"); err == nil || !strings.Contains(err.Error(), "is not healthy") {
+		t.Errorf("Expected an error adding to an unhealthy quorum, got %v", err)
+	}
+}
+
+func TestVerifyRemove(t *testing.T) {
+	leader, stop1 := fakeServer(t, "1", "leader")
+	defer stop1()
+	follower, stop2 := fakeServer(t, "2", "follower")
+	defer stop2()
+	follower3, stop3 := fakeServer(t, "3", "follower")
+	defer stop3()
+
+	// the server was stopped
+	stopped := deadServer(t, "4")
+	current := &Ensemble{Servers: []Server{leader, follower, follower3, stopped}}
+	if err := verifyRemove(current, &Ensemble{Servers: []Server{leader, follower, follower3}}, "4"); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	// the server is still following the leader
+	current = &Ensemble{Servers: []Server{leader, follower, follower3}}
+	if err := verifyRemove(current, &Ensemble{Servers: []Server{leader, follower}}, "3"); err == nil || !strings.Contains(err.Error(), "still participating") {
+		t.Errorf("Expected an error removing a server that is still serving, got %v", err)
+	}
+
+	// the quorum is already unhealthy
+	current = &Ensemble{Servers: []Server{leader, deadServer(t, "2"), stopped}}
+	if err := verifyRemove(current, &Ensemble{Servers: current.Servers[:2]}, "4"); err == nil || !strings.Contains(err.Error(), "is not healthy") {
+		t.Errorf("Expected an error removing from an unhealthy quorum, got %v", err)
+	}
+}
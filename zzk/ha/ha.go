@@ -0,0 +1,390 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ha
+
+import (
+	"errors"
+	"path"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/coordinator/client/zookeeper"
+	"github.com/control-center/serviced/logging"
+)
+
+// initialize the package logger
+var plog = logging.PackageLogger()
+
+const (
+	leaderPath  = "/ha/leader"
+	mastersPath = "/ha/masters"
+
+	// maxTransitions is the number of state transitions kept in a status
+	maxTransitions = 20
+)
+
+// ErrLostLead is returned when an active master loses the lead, at which
+// point it must stop writing to the shared storage
+var ErrLostLead = errors.New("master lost the lead")
+
+// State is a step in the failover of a master's internal services
+type State string
+
+const (
+	// StateStandby is a master waiting to take over for the active master
+	StateStandby State = "standby"
+	// StateMounting is a master mounting the shared storage
+	StateMounting State = "mounting"
+	// StateStarting is a master starting the internal services
+	StateStarting State = "starting"
+	// StateResuming is a master resuming its listeners
+	StateResuming State = "resuming"
+	// StateActive is the master that is running the internal services
+	StateActive State = "active"
+	// StateReleasing is a master that lost the lead stopping the internal
+	// services and unmounting the shared storage
+	StateReleasing State = "releasing"
+	// StateFailed is a master that could not complete a failover or lost
+	// the lead
+	StateFailed State = "failed"
+	// StateStopped is a master that has shut down
+	StateStopped State = "stopped"
+)
+
+// Transition is a change in the state of a master
+type Transition struct {
+	From  State
+	To    State
+	At    time.Time
+	Error string
+}
+
+// Status is the failover state of a master
+type Status struct {
+	HostID      string
+	State       State
+	Since       time.Time
+	Error       string
+	Transitions []Transition
+	version     interface{}
+}
+
+// Version implements client.Node
+func (s *Status) Version() interface{} { return s.version }
+
+// SetVersion implements client.Node
+func (s *Status) SetVersion(version interface{}) { s.version = version }
+
+// ClusterStatus is the failover state of all of the masters
+type ClusterStatus struct {
+	Leader  string // host id of the active master, if any
+	Masters []Status
+}
+
+// leaderNode is the node that holds the lead
+type leaderNode struct {
+	HostID  string
+	version interface{}
+}
+
+// Version implements client.Node
+func (n *leaderNode) Version() interface{} { return n.version }
+
+// SetVersion implements client.Node
+func (n *leaderNode) SetVersion(version interface{}) { n.version = version }
+
+// Handler performs the steps that bring a master's internal services online
+// once it takes the lead, and take them offline if it loses the lead
+type Handler interface {
+	// MountStorage mounts the storage that is shared between the masters
+	MountStorage() error
+	// StartServices starts the internal services
+	StartServices() error
+	// ResumeListeners starts the master's listeners
+	ResumeListeners() error
+	// StopServices stops the internal services
+	StopServices() error
+	// UnmountStorage unmounts the storage that is shared between the masters
+	UnmountStorage() error
+}
+
+// Manager runs the failover state machine of a master
+type Manager struct {
+	hostID  string
+	lease   time.Duration
+	handler Handler
+	conn    client.Connection
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// NewManager instantiates a new failover manager for a master.  The lease is
+// how long the active master may go without reaching the coordinator before
+// it gives up the shared storage, and how long a master that takes the lead
+// waits before it mounts the shared storage.  It should be at least the
+// session timeout of the coordinator.
+func NewManager(hostID string, lease time.Duration, handler Handler) *Manager {
+	return &Manager{
+		hostID:  hostID,
+		lease:   lease,
+		handler: handler,
+		status: Status{
+			HostID: hostID,
+			State:  StateStandby,
+			Since:  time.Now(),
+		},
+	}
+}
+
+// Status returns the current state of the master
+func (m *Manager) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	status := m.status
+	status.Transitions = append([]Transition{}, m.status.Transitions...)
+	return status
+}
+
+// setState moves the master to a new state and publishes it to the
+// coordinator
+func (m *Manager) setState(state State, err error) {
+	m.mu.Lock()
+	now := time.Now()
+	t := Transition{From: m.status.State, To: state, At: now}
+	if err != nil {
+		t.Error = err.Error()
+	}
+	m.status.State = state
+	m.status.Since = now
+	m.status.Error = t.Error
+	m.status.Transitions = append(m.status.Transitions, t)
+	if n := len(m.status.Transitions); n > maxTransitions {
+		m.status.Transitions = m.status.Transitions[n-maxTransitions:]
+	}
+	status := m.status
+	m.mu.Unlock()
+
+	logger := plog.WithFields(log.Fields{
+		"hostid": m.hostID,
+		"from":   t.From,
+		"to":     state,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Master failover state changed")
+	} else {
+		logger.Info("Master failover state changed")
+	}
+
+	if m.conn != nil {
+		if err := publishStatus(m.conn, &status); err != nil {
+			logger.WithError(err).Warn("Could not publish master failover state")
+		}
+	}
+}
+
+// Run waits for this master to take the lead, then mounts the shared storage,
+// starts the internal services and resumes the master's listeners.  If the
+// master loses the lead, or cannot reach the coordinator for the length of
+// the lease, while it is active, it stops the internal services, unmounts the
+// shared storage and returns ErrLostLead, in which case the caller must exit
+// without touching the shared storage again.
+func (m *Manager) Run(shutdown <-chan interface{}, conn client.Connection) error {
+	logger := plog.WithField("hostid", m.hostID)
+	m.conn = conn
+	m.setState(StateStandby, nil)
+
+	if err := conn.CreateDir(leaderPath); err != nil && err != client.ErrNodeExists {
+		return err
+	}
+	leader, err := conn.NewLeader(leaderPath)
+	if err != nil {
+		return err
+	}
+
+	// taking the lead blocks until the active master goes away, so wait for
+	// it in the background in case we are shut down first.
+	cancel := make(chan struct{})
+	defer close(cancel)
+	type result struct {
+		ev  <-chan client.Event
+		err error
+	}
+	resultC := make(chan result, 1)
+	go func() {
+		ev, err := leader.TakeLead(&leaderNode{HostID: m.hostID}, cancel)
+		resultC <- result{ev, err}
+	}()
+
+	logger.Info("Waiting to take the lead as the active master")
+	var leaderW <-chan client.Event
+	select {
+	case r := <-resultC:
+		if r.err != nil {
+			m.setState(StateFailed, r.err)
+			return r.err
+		}
+		leaderW = r.ev
+	case <-shutdown:
+		// the lead is released when the coordinator session closes
+		m.setState(StateStopped, nil)
+		return nil
+	}
+	defer leader.ReleaseLead()
+	logger.Info("Took the lead as the active master")
+
+	// the master that lost the lead may not know it yet, so give it the
+	// length of its lease to give up the shared storage before mounting it.
+	logger.WithField("lease", m.lease).Info("Waiting for the lease of the previous master to expire")
+	select {
+	case <-time.After(m.lease):
+	case <-leaderW:
+		m.setState(StateFailed, ErrLostLead)
+		return ErrLostLead
+	case <-shutdown:
+		m.setState(StateStopped, nil)
+		return nil
+	}
+
+	steps := []struct {
+		state State
+		do    func() error
+	}{
+		{StateMounting, m.handler.MountStorage},
+		{StateStarting, m.handler.StartServices},
+		{StateResuming, m.handler.ResumeListeners},
+	}
+	for _, step := range steps {
+		m.setState(step.state, nil)
+		if err := step.do(); err != nil {
+			m.setState(StateFailed, err)
+			return err
+		}
+	}
+	m.setState(StateActive, nil)
+
+	// confirm the lead with the coordinator in the background, so that a
+	// master that is cut off from the coordinator gives up the shared storage
+	// before another master may take it over.
+	confirmedC := make(chan struct{}, 1)
+	go m.confirmLead(cancel, confirmedC)
+	confirmed := time.Now()
+	ticker := time.NewTicker(m.lease / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-leaderW:
+			logger.Warn("Lost the lead as the active master")
+			m.release()
+			return ErrLostLead
+		case <-confirmedC:
+			confirmed = time.Now()
+		case <-ticker.C:
+			if time.Since(confirmed) >= m.lease {
+				logger.WithField("lease", m.lease).Warn("Could not confirm the lead as the active master before the lease expired")
+				m.release()
+				return ErrLostLead
+			}
+		case <-shutdown:
+			m.setState(StateStopped, nil)
+			return nil
+		}
+	}
+}
+
+// confirmLead reports each time that the master reaches the coordinator,
+// until it is cancelled.
+func (m *Manager) confirmLead(cancel <-chan struct{}, confirmedC chan<- struct{}) {
+	for {
+		if _, err := m.conn.Exists(leaderPath); err == nil {
+			select {
+			case confirmedC <- struct{}{}:
+			default:
+			}
+		}
+		select {
+		case <-time.After(m.lease / 4):
+		case <-cancel:
+			return
+		}
+	}
+}
+
+// release stops the internal services and unmounts the shared storage of a
+// master that lost the lead
+func (m *Manager) release() {
+	m.setState(StateReleasing, nil)
+	if err := m.handler.StopServices(); err != nil {
+		plog.WithField("hostid", m.hostID).WithError(err).Error("Could not stop the internal services")
+	}
+	if err := m.handler.UnmountStorage(); err != nil {
+		m.setState(StateFailed, err)
+		return
+	}
+	m.setState(StateFailed, ErrLostLead)
+}
+
+// publishStatus creates or updates the status node of a master
+func publishStatus(conn client.Connection, status *Status) error {
+	pth := path.Join(mastersPath, status.HostID)
+	existing := &Status{}
+	if err := conn.Get(pth, existing); err == client.ErrNoNode {
+		status.SetVersion(nil)
+		return conn.Create(pth, status)
+	} else if err != nil {
+		return err
+	}
+	status.SetVersion(existing.Version())
+	return conn.Set(pth, status)
+}
+
+// GetClusterStatus returns the failover state of all of the masters
+func GetClusterStatus(conn client.Connection) (*ClusterStatus, error) {
+	cs := &ClusterStatus{Masters: []Status{}}
+
+	if ok, err := conn.Exists(leaderPath); err != nil && err != client.ErrNoNode {
+		return nil, err
+	} else if ok {
+		leader, err := conn.NewLeader(leaderPath)
+		if err != nil {
+			return nil, err
+		}
+		node := &leaderNode{}
+		if err := leader.Current(node); err == nil {
+			cs.Leader = node.HostID
+		} else if err != zookeeper.ErrNoLeaderFound && err != client.ErrNoNode {
+			return nil, err
+		}
+	}
+
+	ch, err := conn.Children(mastersPath)
+	if err == client.ErrNoNode {
+		return cs, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, hostID := range ch {
+		status := Status{}
+		if err := conn.Get(path.Join(mastersPath, hostID), &status); err == client.ErrNoNode {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		cs.Masters = append(cs.Masters, status)
+	}
+	return cs, nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration,!quick
+
+package ha_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/zzk"
+	. "github.com/control-center/serviced/zzk/ha"
+	. "gopkg.in/check.v1"
+)
+
+// testHandler records the failover steps performed by a master
+type testHandler struct {
+	mu       sync.Mutex
+	steps    []string
+	fail     string
+	resumedC chan struct{}
+}
+
+func newTestHandler() *testHandler {
+	return &testHandler{resumedC: make(chan struct{})}
+}
+
+func (h *testHandler) step(name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.steps = append(h.steps, name)
+	if name == h.fail {
+		return errors.New("step failed")
+	}
+	return nil
+}
+
+func (h *testHandler) Steps() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string{}, h.steps...)
+}
+
+func (h *testHandler) MountStorage() error { return h.step("mount") }
+
+func (h *testHandler) StartServices() error { return h.step("start") }
+
+func (h *testHandler) ResumeListeners() error {
+	if err := h.step("resume"); err != nil {
+		return err
+	}
+	close(h.resumedC)
+	return nil
+}
+
+func (h *testHandler) StopServices() error { return h.step("stop") }
+
+func (h *testHandler) UnmountStorage() error { return h.step("unmount") }
+
+// testLease is the lease of the masters under test
+const testLease = 200 * time.Millisecond
+
+// waitForState waits for a master to publish the given state
+func waitForState(c *C, conn client.Connection, hostID string, state State) {
+	timer := time.NewTimer(zzk.ZKTestTimeout)
+	defer timer.Stop()
+	for {
+		cs, err := GetClusterStatus(conn)
+		c.Assert(err, IsNil)
+		for _, m := range cs.Masters {
+			if m.HostID == hostID && m.State == state {
+				return
+			}
+		}
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-timer.C:
+			c.Fatalf("Timed out waiting for master %s to be %s", hostID, state)
+		}
+	}
+}
+
+func (t *ZZKTest) TestManager_Failover(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	// the first master takes the lead
+	h1 := newTestHandler()
+	m1 := NewManager("master-1", testLease, h1)
+	shutdown1 := make(chan interface{})
+	errC1 := make(chan error, 1)
+	go func() { errC1 <- m1.Run(shutdown1, conn) }()
+
+	select {
+	case <-h1.resumedC:
+	case <-time.After(zzk.ZKTestTimeout):
+		c.Fatalf("Timed out waiting for master-1 to take the lead")
+	}
+	c.Check(h1.Steps(), DeepEquals, []string{"mount", "start", "resume"})
+	waitForState(c, conn, "master-1", StateActive)
+
+	// the second master waits on standby
+	conn2, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+	h2 := newTestHandler()
+	m2 := NewManager("master-2", testLease, h2)
+	shutdown2 := make(chan interface{})
+	errC2 := make(chan error, 1)
+	go func() { errC2 <- m2.Run(shutdown2, conn2) }()
+	waitForState(c, conn, "master-2", StateStandby)
+	c.Check(h2.Steps(), HasLen, 0)
+
+	cs, err := GetClusterStatus(conn)
+	c.Assert(err, IsNil)
+	c.Check(cs.Leader, Equals, "master-1")
+	c.Check(cs.Masters, HasLen, 2)
+
+	// the second master takes over when the first shuts down
+	close(shutdown1)
+	select {
+	case err := <-errC1:
+		c.Assert(err, IsNil)
+	case <-time.After(zzk.ZKTestTimeout):
+		c.Fatalf("Timed out waiting for master-1 to shut down")
+	}
+	c.Check(m1.Status().State, Equals, StateStopped)
+
+	select {
+	case <-h2.resumedC:
+	case <-time.After(zzk.ZKTestTimeout):
+		c.Fatalf("Timed out waiting for master-2 to take the lead")
+	}
+	waitForState(c, conn, "master-2", StateActive)
+	cs, err = GetClusterStatus(conn)
+	c.Assert(err, IsNil)
+	c.Check(cs.Leader, Equals, "master-2")
+
+	status := m2.Status()
+	c.Check(status.Transitions, HasLen, 5)
+	c.Check(status.Transitions[len(status.Transitions)-1].To, Equals, StateActive)
+
+	close(shutdown2)
+	select {
+	case err := <-errC2:
+		c.Assert(err, IsNil)
+	case <-time.After(zzk.ZKTestTimeout):
+		c.Fatalf("Timed out waiting for master-2 to shut down")
+	}
+}
+
+func (t *ZZKTest) TestManager_StepFails(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	h := newTestHandler()
+	h.fail = "start"
+	m := NewManager("master-3", testLease, h)
+	shutdown := make(chan interface{})
+	defer close(shutdown)
+
+	errC := make(chan error, 1)
+	go func() { errC <- m.Run(shutdown, conn) }()
+	select {
+	case err := <-errC:
+		c.Assert(err, NotNil)
+	case <-time.After(zzk.ZKTestTimeout):
+		c.Fatalf("Timed out waiting for master-3 to fail")
+	}
+	c.Check(h.Steps(), DeepEquals, []string{"mount", "start"})
+
+	status := m.Status()
+	c.Check(status.State, Equals, StateFailed)
+	c.Check(status.Error, Equals, "step failed")
+	waitForState(c, conn, "master-3", StateFailed)
+}
+
+func (t *ZZKTest) TestManager_LostLead(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	h := newTestHandler()
+	m := NewManager("master-4", testLease, h)
+	shutdown := make(chan interface{})
+	defer close(shutdown)
+
+	errC := make(chan error, 1)
+	go func() { errC <- m.Run(shutdown, conn) }()
+	select {
+	case <-h.resumedC:
+	case <-time.After(zzk.ZKTestTimeout):
+		c.Fatalf("Timed out waiting for master-4 to take the lead")
+	}
+
+	// another master takes the lead when the lead of this master goes away
+	ch, err := conn.Children("/ha/leader")
+	c.Assert(err, IsNil)
+	for _, name := range ch {
+		c.Assert(conn.Delete("/ha/leader/"+name), IsNil)
+	}
+
+	select {
+	case err := <-errC:
+		c.Assert(err, Equals, ErrLostLead)
+	case <-time.After(zzk.ZKTestTimeout):
+		c.Fatalf("Timed out waiting for master-4 to lose the lead")
+	}
+	c.Check(h.Steps(), DeepEquals, []string{"mount", "start", "resume", "stop", "unmount"})
+	c.Check(m.Status().State, Equals, StateFailed)
+	waitForState(c, conn, "master-4", StateFailed)
+}
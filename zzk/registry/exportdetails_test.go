@@ -181,3 +181,36 @@ func (t *ZZKTest) TestTrackExports(c *C) {
 		c.Fatalf("Timed out waiting for exports")
 	}
 }
+
+func (t *ZZKTest) TestGetExports(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	// no exports
+	exports, err := GetExports(conn, "tenantid")
+	c.Assert(err, IsNil)
+	c.Check(exports, HasLen, 0)
+
+	// add exports for two applications
+	err = conn.Create("/net/export/tenantid/app1/0", &ExportDetails{
+		ExportBinding: service.ExportBinding{Application: "app1"},
+		InstanceID:    0,
+	})
+	c.Assert(err, IsNil)
+	err = conn.Create("/net/export/tenantid/app1/1", &ExportDetails{
+		ExportBinding: service.ExportBinding{Application: "app1"},
+		InstanceID:    1,
+	})
+	c.Assert(err, IsNil)
+	err = conn.Create("/net/export/tenantid/app2/0", &ExportDetails{
+		ExportBinding: service.ExportBinding{Application: "app2"},
+		InstanceID:    0,
+	})
+	c.Assert(err, IsNil)
+
+	exports, err = GetExports(conn, "tenantid")
+	c.Assert(err, IsNil)
+	c.Check(exports, HasLen, 2)
+	c.Check(exports["app1"], HasLen, 2)
+	c.Check(exports["app2"], HasLen, 1)
+}
@@ -76,6 +76,51 @@ func DeleteExports(conn client.Connection, tenantID string) error {
 	return nil
 }
 
+// GetExports returns the exports registered for a tenant id, keyed by
+// application
+func GetExports(conn client.Connection, tenantID string) (map[string][]ExportDetails, error) {
+	pth := path.Join("/net/export", tenantID)
+	logger := plog.WithFields(log.Fields{
+		"tenantid": tenantID,
+		"zkpath":   pth,
+	})
+
+	exports := make(map[string][]ExportDetails)
+	apps, err := conn.Children(pth)
+	if err == client.ErrNoNode {
+		logger.Debug("No exports for tenant id")
+		return exports, nil
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not look up applications for tenant id")
+		return nil, err
+	}
+
+	for _, app := range apps {
+		apppth := path.Join(pth, app)
+		ch, err := conn.Children(apppth)
+		if err == client.ErrNoNode {
+			continue
+		} else if err != nil {
+			logger.WithError(err).WithField("application", app).Debug("Could not look up exports for application")
+			return nil, err
+		}
+
+		for _, name := range ch {
+			var export ExportDetails
+			if err := conn.Get(path.Join(apppth, name), &export); err == client.ErrNoNode {
+				continue
+			} else if err != nil {
+				logger.WithError(err).WithField("application", app).Debug("Could not look up export")
+				return nil, err
+			}
+			exports[app] = append(exports[app], export)
+		}
+	}
+
+	logger.Debug("Loaded exports for tenant id")
+	return exports, nil
+}
+
 // GetPublicPort returns the service id and application of the public port
 func GetPublicPort(conn client.Connection, key PublicPortKey) (string, string, error) {
 	pth := path.Join("/net/pub", key.HostID, key.PortAddress)
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"strconv"
 	"text/template"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
 // set up template function definitions
@@ -42,6 +44,7 @@ type ImportBinding struct {
 	PortNumber     uint16
 	PortTemplate   string
 	VirtualAddress string
+	CircuitBreaker servicedefinition.CircuitBreaker
 }
 
 // GetPortNumber retrieves a port number for a given instance ID
@@ -0,0 +1,106 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// PoolScheduling is the scheduling state of a resource pool in zookeeper
+type PoolScheduling struct {
+	PoolID   string
+	Services []ServiceNode
+	HostIDs  []string
+}
+
+// GetPoolScheduling returns the services and hosts that are scheduled in
+// each resource pool (uses a root-based connection)
+func GetPoolScheduling(conn client.Connection) ([]PoolScheduling, error) {
+	poolIDs, err := conn.Children("/pools")
+	if err == client.ErrNoNode {
+		return []PoolScheduling{}, nil
+	} else if err != nil {
+		plog.WithError(err).Debug("Could not look up resource pools")
+		return nil, err
+	}
+
+	pools := make([]PoolScheduling, len(poolIDs))
+	for i, poolID := range poolIDs {
+		logger := plog.WithField("poolid", poolID)
+		pools[i] = PoolScheduling{PoolID: poolID}
+
+		serviceIDs, err := conn.Children(path.Join("/pools", poolID, "services"))
+		if err != nil && err != client.ErrNoNode {
+			logger.WithError(err).Debug("Could not look up services in resource pool")
+			return nil, err
+		}
+		for _, serviceID := range serviceIDs {
+			node := &ServiceNode{}
+			if err := conn.Get(path.Join("/pools", poolID, "services", serviceID), node); err == client.ErrNoNode {
+				continue
+			} else if err != nil && err != client.ErrEmptyNode {
+				logger.WithField("serviceid", serviceID).WithError(err).Debug("Could not look up service in resource pool")
+				return nil, err
+			}
+			// an empty node still names the service it was created for
+			node.ID = serviceID
+			pools[i].Services = append(pools[i].Services, *node)
+		}
+
+		hostIDs, err := conn.Children(path.Join("/pools", poolID, "hosts"))
+		if err != nil && err != client.ErrNoNode {
+			logger.WithError(err).Debug("Could not look up hosts in resource pool")
+			return nil, err
+		}
+		pools[i].HostIDs = hostIDs
+	}
+	return pools, nil
+}
+
+// ServiceInconsistency is a service whose scheduling state in zookeeper does
+// not match its entity in the datastore
+type ServiceInconsistency struct {
+	ServiceID      string
+	Name           string
+	PoolID         string // pool of the zookeeper node
+	DesiredState   int    // desired state of the entity, if there is one
+	ZKDesiredState int    // desired state of the zookeeper node
+}
+
+// HostInconsistency is a host that is only found in one of the datastore or
+// zookeeper
+type HostInconsistency struct {
+	HostID string
+	PoolID string
+}
+
+// ConsistencyReport lists the differences between the entities in the
+// datastore and the scheduling state in zookeeper
+type ConsistencyReport struct {
+	OrphanedServices       []ServiceInconsistency // scheduled in zookeeper without an entity in the pool
+	DesiredStateMismatches []ServiceInconsistency // desired state in zookeeper differs from the entity
+	DatastoreOnlyHosts     []HostInconsistency    // hosts that are not in zookeeper
+	ZKOnlyHosts            []HostInconsistency    // hosts in zookeeper without an entity in the pool
+	Repaired               bool                   // the differences were repaired
+}
+
+// Consistent returns true if no differences were found
+func (r ConsistencyReport) Consistent() bool {
+	return len(r.OrphanedServices) == 0 &&
+		len(r.DesiredStateMismatches) == 0 &&
+		len(r.DatastoreOnlyHosts) == 0 &&
+		len(r.ZKOnlyHosts) == 0
+}
@@ -0,0 +1,71 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/host"
+)
+
+// DFSHealthNode is the most recent report of the health of a delegate's
+// distributed filesystem mounts
+type DFSHealthNode struct {
+	host.DFSHealth
+	version interface{}
+}
+
+// Version implements client.Node
+func (n *DFSHealthNode) Version() interface{} {
+	return n.version
+}
+
+// SetVersion implements client.Node
+func (n *DFSHealthNode) SetVersion(version interface{}) {
+	n.version = version
+}
+
+// UpdateDFSHealth reports the health of the host's dfs mounts.  This is
+// managed by the worker node, so it is expected that the connection will be
+// pre-loaded with the path to the resource pool.  Returns client.ErrNoNode if
+// the host is not registered.
+func UpdateDFSHealth(conn client.Connection, hostid string, health host.DFSHealth) error {
+	pth := path.Join("/hosts", hostid, "dfs")
+	node := &DFSHealthNode{DFSHealth: health}
+	existing := &DFSHealthNode{}
+	if err := conn.Get(pth, existing); err == client.ErrNoNode {
+		return conn.CreateIfExists(pth, node)
+	} else if err != nil {
+		return err
+	}
+	node.SetVersion(existing.Version())
+	return conn.Set(pth, node)
+}
+
+// GetDFSHealth returns the last reported health of the host's dfs mounts, or
+// nil if the host has not reported.
+func GetDFSHealth(conn client.Connection, poolid, hostid string) (*host.DFSHealth, error) {
+	basepth := "/"
+	if poolid != "" {
+		basepth = path.Join("/pools", poolid)
+	}
+	node := &DFSHealthNode{}
+	if err := conn.Get(path.Join(basepth, "/hosts", hostid, "dfs"), node); err == client.ErrNoNode {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &node.DFSHealth, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/health"
+)
+
+// WaitHealthChecks blocks until the interval, timeout, tolerance, or failure
+// threshold of any of a service's health checks changes, and returns the
+// health checks with their updated settings.  Scripts are never updated,
+// since they are evaluated for each instance.  It returns the health checks
+// unchanged if cancel is closed first.
+func WaitHealthChecks(cancel <-chan struct{}, conn client.Connection, poolID, serviceID string, checks map[string]health.HealthCheck) (map[string]health.HealthCheck, error) {
+	basepth := ""
+	if poolID != "" {
+		basepth = path.Join("/pools", poolID)
+	}
+	pth := path.Join(basepth, "/services", serviceID)
+	logger := plog.WithField("serviceid", serviceID).WithField("zkpath", pth)
+
+	done := make(chan struct{})
+	defer func() { close(done) }()
+	for {
+		node := &ServiceNode{}
+		ev, err := conn.GetW(pth, node, done)
+		if err != nil {
+			logger.WithError(err).Debug("Could not watch service")
+			return nil, err
+		}
+
+		if updated, ok := updateHealthChecks(checks, node.HealthChecks); ok {
+			logger.Debug("Health check settings changed")
+			return updated, nil
+		}
+
+		select {
+		case <-ev:
+		case <-cancel:
+			return checks, nil
+		}
+
+		close(done)
+		done = make(chan struct{})
+	}
+}
+
+// updateHealthChecks copies the settings of the updated health checks onto
+// the current health checks, and returns true if any of them changed.
+func updateHealthChecks(current, updated map[string]health.HealthCheck) (map[string]health.HealthCheck, bool) {
+	result := make(map[string]health.HealthCheck)
+	changed := false
+	for name, hc := range current {
+		if u, ok := updated[name]; ok {
+			if hc.Interval != u.Interval || hc.Timeout != u.Timeout || hc.Tolerance != u.Tolerance || hc.FailureThreshold != u.FailureThreshold {
+				hc.Interval = u.Interval
+				hc.Timeout = u.Timeout
+				hc.Tolerance = u.Tolerance
+				hc.FailureThreshold = u.FailureThreshold
+				changed = true
+			}
+		}
+		result[name] = hc
+	}
+	return result, changed
+}
@@ -0,0 +1,74 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration,!quick
+
+package service_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/zzk"
+	. "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+func (t *ZZKTest) TestWaitHealthChecks(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	svc := service.Service{
+		ID:     "serviceid",
+		PoolID: "poolid",
+		HealthChecks: map[string]health.HealthCheck{
+			"ping": {Script: "ping {{.Name}}", Interval: time.Second, Timeout: time.Second},
+		},
+	}
+	err = UpdateService(conn, svc, false, false)
+	c.Assert(err, IsNil)
+
+	// the instance has evaluated the script
+	checks := map[string]health.HealthCheck{
+		"ping": {Script: "ping serviceid", Interval: time.Second, Timeout: time.Second},
+	}
+
+	// times out without a change
+	cancel := make(chan struct{})
+	timer := time.AfterFunc(100*time.Millisecond, func() { close(cancel) })
+	actual, err := WaitHealthChecks(cancel, conn, "", svc.ID, checks)
+	c.Assert(err, IsNil)
+	c.Assert(actual, DeepEquals, checks)
+	timer.Stop()
+
+	// the interval is updated, but the evaluated script is kept
+	done := make(chan map[string]health.HealthCheck)
+	go func() {
+		actual, err := WaitHealthChecks(make(chan struct{}), conn, "", svc.ID, checks)
+		c.Check(err, IsNil)
+		done <- actual
+	}()
+	time.Sleep(100 * time.Millisecond)
+	svc.HealthChecks["ping"] = health.HealthCheck{Script: "ping {{.Name}}", Interval: 5 * time.Second, Timeout: time.Second, FailureThreshold: 3}
+	err = UpdateService(conn, svc, false, false)
+	c.Assert(err, IsNil)
+	select {
+	case actual := <-done:
+		c.Check(actual, DeepEquals, map[string]health.HealthCheck{
+			"ping": {Script: "ping serviceid", Interval: 5 * time.Second, Timeout: time.Second, FailureThreshold: 3},
+		})
+	case <-time.After(5 * time.Second):
+		c.Fatalf("Timed out waiting for the health check to be updated")
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"math/rand"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// HeartbeatConfig describes how often a delegate reports to the coordinator.
+// Delegates report at the minimum interval while the instances on the host
+// are changing and back off to the maximum interval while the host is idle.
+type HeartbeatConfig struct {
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	Jitter      float64 // fraction of the interval randomly added or removed
+}
+
+// DefaultHeartbeatConfig is the heartbeat configuration of a delegate if none
+// is specified
+var DefaultHeartbeatConfig = HeartbeatConfig{
+	MinInterval: 5 * time.Second,
+	MaxInterval: time.Minute,
+	Jitter:      0.2,
+}
+
+// Validate returns an error if the heartbeat configuration is not usable
+func (c HeartbeatConfig) Validate() error {
+	if c.MinInterval <= 0 {
+		return errors.New("heartbeat minimum interval must be positive")
+	} else if c.MaxInterval < c.MinInterval {
+		return errors.New("heartbeat maximum interval cannot be less than the minimum interval")
+	} else if c.Jitter < 0 || c.Jitter >= 1 {
+		return errors.New("heartbeat jitter must be at least 0 and less than 1")
+	}
+	return nil
+}
+
+// Next returns the interval until the next heartbeat.  The interval resets
+// to the minimum when the host is changing and doubles up to the maximum
+// while it is idle.
+func (c HeartbeatConfig) Next(prev time.Duration, changed bool) time.Duration {
+	if changed || prev < c.MinInterval {
+		return c.MinInterval
+	}
+	if next := 2 * prev; next < c.MaxInterval {
+		return next
+	}
+	return c.MaxInterval
+}
+
+// Jittered randomly spreads the interval by up to the configured jitter, so
+// that hosts in a large pool do not report at the same time.  r is a random
+// number in [0, 1).
+func (c HeartbeatConfig) Jittered(interval time.Duration, r float64) time.Duration {
+	return interval + time.Duration((2*r-1)*c.Jitter*float64(interval))
+}
+
+// Timeout is the longest a running delegate can go without reporting before
+// it is treated as offline.
+func (c HeartbeatConfig) Timeout() time.Duration {
+	return c.Jittered(c.MaxInterval, 1) + c.MinInterval
+}
+
+// HeartbeatNode is the most recent report of a delegate
+type HeartbeatNode struct {
+	HostID    string
+	Sequence  uint64
+	Instances int
+	Interval  time.Duration // time until the next heartbeat
+	Timeout   time.Duration // time after which the delegate is considered unresponsive
+	Timestamp time.Time     // delegate clock when the heartbeat was written
+	version   interface{}
+}
+
+// Version implements client.Node
+func (n *HeartbeatNode) Version() interface{} {
+	return n.version
+}
+
+// SetVersion implements client.Node
+func (n *HeartbeatNode) SetVersion(version interface{}) {
+	n.version = version
+}
+
+// RunHeartbeat reports the delegate to the coordinator at adaptive intervals
+// until cancelled.  This is managed by the worker node, so it is expected
+// that the connection will be pre-loaded with the path to the resource pool.
+func RunHeartbeat(cancel <-chan interface{}, conn client.Connection, hostid string, cfg HeartbeatConfig) {
+	logger := plog.WithField("hostid", hostid)
+
+	pth := path.Join("/hosts", hostid, "heartbeat")
+	node := &HeartbeatNode{HostID: hostid, Timeout: cfg.Timeout()}
+	var instances string
+	var interval time.Duration
+	for {
+
+		// speed up while the instances on the host are changing
+		ch, err := conn.Children(path.Join("/hosts", hostid, "instances"))
+		if err != nil && err != client.ErrNoNode {
+			logger.WithError(err).Debug("Could not look up instances on host")
+		}
+		sort.Strings(ch)
+		current := strings.Join(ch, ",")
+		interval = cfg.Next(interval, current != instances)
+		instances = current
+
+		node.Sequence++
+		node.Instances = len(ch)
+		node.Interval = interval
+		node.Timestamp = time.Now().UTC()
+		if err := writeHeartbeat(conn, pth, node); err == client.ErrNoNode {
+			logger.Debug("Host is not registered; skipping heartbeat")
+		} else if err != nil {
+			logger.WithError(err).Warn("Could not report heartbeat")
+		}
+
+		select {
+		case <-time.After(cfg.Jittered(interval, rand.Float64())):
+		case <-cancel:
+			return
+		}
+	}
+}
+
+// writeHeartbeat creates or updates the heartbeat node
+func writeHeartbeat(conn client.Connection, pth string, node *HeartbeatNode) error {
+	existing := &HeartbeatNode{}
+	if err := conn.Get(pth, existing); err == client.ErrNoNode {
+		node.SetVersion(nil)
+		return conn.CreateIfExists(pth, node)
+	} else if err != nil {
+		return err
+	}
+	node.SetVersion(existing.Version())
+	return conn.Set(pth, node)
+}
+
+// heartbeatStatus tracks whether a host has stopped reporting, and notifies
+// the host registry listener when that changes.
+type heartbeatStatus struct {
+	mu      sync.Mutex
+	stalled bool
+	changed chan struct{}
+}
+
+func newHeartbeatStatus() *heartbeatStatus {
+	return &heartbeatStatus{changed: make(chan struct{})}
+}
+
+// get returns whether the host has stopped reporting and a channel that is
+// closed when that changes.
+func (s *heartbeatStatus) get() (bool, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stalled, s.changed
+}
+
+func (s *heartbeatStatus) set(stalled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stalled != stalled {
+		s.stalled = stalled
+		close(s.changed)
+		s.changed = make(chan struct{})
+	}
+}
+
+// watchHeartbeat measures the host's clock skew each time it reports, and
+// marks the host's heartbeat as stalled if it stops reporting for longer than
+// the timeout the host advertises.  The host registry listener treats a
+// stalled host as offline, so failure detection is bounded by the heartbeat
+// timeout plus the pool's connection timeout, even while the host's
+// coordinator session stays open.  Hosts that never report are left to their
+// session.
+func (h *HostRegistryListener) watchHeartbeat(done <-chan struct{}, hostid string, status *heartbeatStatus) {
+	logger := plog.WithFields(log.Fields{
+		"poolid": h.poolid,
+		"hostid": hostid,
+	})
+
+	pth := h.GetPath(hostid, "heartbeat")
+	stop := make(chan struct{})
+	defer func() { close(stop) }()
+
+	// the first heartbeat may have been written long before we started
+	// watching, so it says nothing about the host's clock.
+	fresh := false
+	for {
+		node := &HeartbeatNode{}
+		ev, err := h.conn.GetW(pth, node, stop)
+		if err == client.ErrNoNode {
+
+			// wait for the delegate to start reporting
+			status.set(false)
+			ok, ev, err := h.conn.ExistsW(pth, stop)
+			if err != nil {
+				logger.WithError(err).Debug("Could not watch host heartbeat")
+				return
+			} else if !ok {
+				select {
+				case <-ev:
+				case <-done:
+					return
+				}
+			}
+			close(stop)
+			stop = make(chan struct{})
+			fresh = true
+			continue
+		} else if err != nil {
+			logger.WithError(err).Debug("Could not watch host heartbeat")
+			status.set(false)
+			return
+		}
+		if fresh && h.clockSkew != nil && !node.Timestamp.IsZero() {
+			h.clockSkew(hostid, time.Since(node.Timestamp))
+		}
+		if fresh {
+			status.set(false)
+		}
+		fresh = true
+
+		timeout := node.Timeout
+		if timeout <= 0 {
+			timeout = DefaultHeartbeatConfig.Timeout()
+		}
+		timer := time.NewTimer(timeout)
+		select {
+		case <-ev:
+		case <-timer.C:
+			logger.WithFields(log.Fields{
+				"sequence": node.Sequence,
+				"timeout":  timeout,
+			}).Warn("Host stopped reporting; treating it as offline")
+			status.set(true)
+
+			// wait for the host to report again
+			select {
+			case <-ev:
+			case <-done:
+				return
+			}
+		case <-done:
+			timer.Stop()
+			return
+		}
+		timer.Stop()
+		close(stop)
+		stop = make(chan struct{})
+	}
+}
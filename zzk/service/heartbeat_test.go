@@ -0,0 +1,99 @@
+// Copyright 2014 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration,!quick
+
+package service_test
+
+import (
+	"path"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/zzk"
+	. "github.com/control-center/serviced/zzk/service"
+
+	. "gopkg.in/check.v1"
+)
+
+func (t *ZZKTest) TestHeartbeatConfig(c *C) {
+	cfg := HeartbeatConfig{
+		MinInterval: 5 * time.Second,
+		MaxInterval: time.Minute,
+		Jitter:      0.2,
+	}
+	c.Assert(cfg.Validate(), IsNil)
+
+	// back off while idle and reset when changing
+	c.Check(cfg.Next(0, false), Equals, 5*time.Second)
+	c.Check(cfg.Next(5*time.Second, false), Equals, 10*time.Second)
+	c.Check(cfg.Next(40*time.Second, false), Equals, time.Minute)
+	c.Check(cfg.Next(time.Minute, false), Equals, time.Minute)
+	c.Check(cfg.Next(time.Minute, true), Equals, 5*time.Second)
+
+	c.Check(cfg.Jittered(10*time.Second, 0), Equals, 8*time.Second)
+	c.Check(cfg.Jittered(10*time.Second, 0.5), Equals, 10*time.Second)
+	c.Check(cfg.Timeout(), Equals, 77*time.Second)
+
+	c.Check(HeartbeatConfig{MinInterval: 0, MaxInterval: time.Minute}.Validate(), NotNil)
+	c.Check(HeartbeatConfig{MinInterval: time.Minute, MaxInterval: time.Second}.Validate(), NotNil)
+	c.Check(HeartbeatConfig{MinInterval: time.Second, MaxInterval: time.Minute, Jitter: 1}.Validate(), NotNil)
+}
+
+func (t *ZZKTest) TestRunHeartbeat(c *C) {
+	conn, err := zzk.GetLocalConnection("/pools/testpool")
+	c.Assert(err, IsNil)
+
+	err = conn.CreateDir("/hosts/h1/instances")
+	c.Assert(err, IsNil)
+
+	cfg := HeartbeatConfig{
+		MinInterval: 100 * time.Millisecond,
+		MaxInterval: time.Second,
+	}
+	cancel := make(chan interface{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RunHeartbeat(cancel, conn, "h1", cfg)
+	}()
+	defer func() {
+		close(cancel)
+		<-done
+	}()
+
+	// the heartbeat slows down while the host is idle
+	pth := path.Join("/hosts", "h1", "heartbeat")
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	for {
+		node := &HeartbeatNode{}
+		ev, err := conn.GetW(pth, node, done)
+		if err == client.ErrNoNode {
+			_, ev, err = conn.ExistsW(pth, done)
+		}
+		c.Assert(err, IsNil)
+		if node.Interval == cfg.MaxInterval {
+			c.Check(node.HostID, Equals, "h1")
+			c.Check(node.Instances, Equals, 0)
+			c.Check(node.Timeout, Equals, cfg.Timeout())
+			c.Check(node.Timestamp.IsZero(), Equals, false)
+			break
+		}
+		select {
+		case <-ev:
+		case <-timer.C:
+			c.Fatalf("Timed out waiting for heartbeat to back off")
+		}
+	}
+}
@@ -14,6 +14,7 @@
 package service
 
 import (
+	"errors"
 	"path"
 
 	log "github.com/Sirupsen/logrus"
@@ -91,6 +92,10 @@ func UpdateHost(conn client.Connection, h host.Host) error {
 	return nil
 }
 
+// ErrDrainCancelled is returned when a host drain is cancelled before all of
+// the instances on the host have stopped
+var ErrDrainCancelled = errors.New("host drain was cancelled")
+
 // RemoveHost removes an existing host, after waiting for existing states to
 // shutdown.
 func RemoveHost(cancel <-chan struct{}, conn client.Connection, poolID, hostID string) error {
@@ -118,23 +123,96 @@ func RemoveHost(cancel <-chan struct{}, conn client.Connection, poolID, hostID s
 	defer mu.Unlock()
 
 	// schedule all running states to stop
+	if ok, err := stopHostStates(cancel, conn, poolID, hostID); err != nil {
+		return err
+	} else if !ok {
+		logger.Debug("Delete was cancelled")
+		return nil
+	}
+
+	if err := removeHost(conn, poolID, hostID); err != nil {
+		logger.WithError(err).Debug("Could not delete host entry from zookeeper")
+		return err
+	}
+
+	logger.Debug("Deleted host entry from zookeeper")
+	return nil
+}
+
+// DrainHost stops all of the instances running on a host.  The host is locked
+// from scheduling only while the drain is in progress; once DrainHost returns
+// the scheduler may place instances on the host again.  Returns
+// ErrDrainCancelled if the drain is cancelled.
+func DrainHost(cancel <-chan struct{}, conn client.Connection, poolID, hostID string) error {
+	basepth := ""
+	if poolID != "" {
+		basepth = path.Join("/pools", poolID)
+	}
+	pth := path.Join(basepth, "/hosts", hostID)
+
+	logger := plog.WithFields(log.Fields{
+		"hostid": hostID,
+		"zkpath": pth,
+	})
+
+	// lock the host from scheduling
+	mu, err := conn.NewLock(path.Join(pth, "locked"))
+	if err != nil {
+		logger.WithError(err).Debug("Could not instantiate scheduling lock")
+		return err
+	}
+	if err := mu.Lock(); err != nil {
+		logger.WithError(err).Debug("Could not lock host from scheduling")
+		return err
+	}
+	defer mu.Unlock()
+
+	if ok, err := stopHostStates(cancel, conn, poolID, hostID); err != nil {
+		return err
+	} else if !ok {
+		logger.Debug("Drain was cancelled")
+		return ErrDrainCancelled
+	}
+
+	logger.Debug("Drained host")
+	return nil
+}
+
+// stopHostStates schedules all of the states on a host to stop and waits
+// for them to exit, stopping the states of lower priority services first.
+// Returns false if it is cancelled before the states have exited.
+func stopHostStates(cancel <-chan struct{}, conn client.Connection, poolID, hostID string) (bool, error) {
+	basepth := ""
+	if poolID != "" {
+		basepth = path.Join("/pools", poolID)
+	}
+	pth := path.Join(basepth, "/hosts", hostID)
+
+	logger := plog.WithFields(log.Fields{
+		"hostid": hostID,
+		"zkpath": pth,
+	})
+
+	ranks := make(map[string]int)
 	done := make(chan struct{})
 	defer func() { close(done) }()
 	for {
 
 		// clean any bad host states
 		if err := CleanHostStates(conn, poolID, hostID); err != nil {
-			return err
+			return false, err
 		}
 
 		// get the list of states
 		ch, ev, err := conn.ChildrenW(path.Join(pth, "instances"), done)
 		if err != nil && err != client.ErrNoNode {
 			logger.WithError(err).Debug("Could not watch instances for host")
-			return err
+			return false, err
 		}
 
-		for _, stateID := range ch {
+		reqs := make([]StateRequest, len(ch))
+		lowest := 0
+		for i, stateID := range ch {
 			st8log := logger.WithField("stateid", stateID)
 
 			_, serviceID, instanceID, err := ParseStateID(stateID)
@@ -142,16 +220,35 @@ func RemoveHost(cancel <-chan struct{}, conn client.Connection, poolID, hostID s
 
 				// This should never happen, but handle it
 				st8log.WithError(err).Error("Invalid state id while monitoring host")
-				return err
+				return false, err
 			}
 
-			req := StateRequest{
+			reqs[i] = StateRequest{
 				PoolID:     poolID,
 				HostID:     hostID,
 				ServiceID:  serviceID,
 				InstanceID: instanceID,
 			}
 
+			rank, ok := ranks[serviceID]
+			if !ok {
+				if rank, err = servicePriorityRank(conn, poolID, serviceID); err != nil {
+					st8log.WithError(err).Debug("Could not look up the priority of the service")
+					return false, err
+				}
+				ranks[serviceID] = rank
+			}
+			if rank > lowest {
+				lowest = rank
+			}
+		}
+
+		// only stop the states of the lowest priority services still running
+		for _, req := range reqs {
+			if ranks[req.ServiceID] != lowest {
+				continue
+			}
+
 			// set the state to stopped if not already stopped
 			if err := UpdateState(conn, req, func(s *State) bool {
 				if s.DesiredState != service.SVCStop {
@@ -160,33 +257,44 @@ func RemoveHost(cancel <-chan struct{}, conn client.Connection, poolID, hostID s
 				}
 				return false
 			}); err != nil {
-				return err
+				return false, err
 			}
 		}
 
 		// if all the states have died, exit loop
 		if len(ch) == 0 {
-			break
+			return true, nil
 		}
 
 		// otherwise, wait for the number of states to change
 		select {
 		case <-ev:
 		case <-cancel:
-			logger.Debug("Delete was cancelled")
-			return nil
+			return false, nil
 		}
 		close(done)
 		done = make(chan struct{})
 	}
+}
 
-	if err := removeHost(conn, poolID, hostID); err != nil {
-		logger.WithError(err).Debug("Could not delete host entry from zookeeper")
-		return err
+// servicePriorityRank returns the priority rank of a service; services that
+// no longer exist rank below the lowest priority.
+func servicePriorityRank(conn client.Connection, poolID, serviceID string) (int, error) {
+	basepth := ""
+	if poolID != "" {
+		basepth = path.Join("/pools", poolID)
 	}
-
-	logger.Debug("Deleted host entry from zookeeper")
-	return nil
+	sn, err := NewServiceNodeFromService(&service.Service{})
+	if err != nil {
+		return 0, err
+	}
+	if err := conn.Get(path.Join(basepth, "/services", serviceID), sn); err == client.ErrNoNode {
+		return len(service.Priorities), nil
+	} else if err != nil {
+		return 0, err
+	}
+	rank, _ := service.PriorityRank(sn.Priority)
+	return rank, nil
 }
 
 // removeHost deletes a host from zookeeper
@@ -0,0 +1,91 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration,!quick
+
+package service_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/zzk"
+	. "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+func (t *ZZKTest) TestDrainHost_Priority(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	err = conn.CreateDir("/pools/poolid/hosts/hostid")
+	c.Assert(err, IsNil)
+	var reqs []StateRequest
+	for _, priority := range []string{service.PriorityHigh, service.PriorityLow} {
+		sn, err := NewServiceNodeFromService(&service.Service{ID: priority, Priority: priority})
+		c.Assert(err, IsNil)
+		err = conn.Create("/pools/poolid/services/"+priority, sn)
+		c.Assert(err, IsNil)
+		req := StateRequest{
+			PoolID:     "poolid",
+			HostID:     "hostid",
+			ServiceID:  priority,
+			InstanceID: 0,
+		}
+		err = CreateState(conn, req)
+		c.Assert(err, IsNil)
+		reqs = append(reqs, req)
+	}
+	high, low := reqs[0], reqs[1]
+
+	done := make(chan error)
+	go func() {
+		done <- DrainHost(make(chan struct{}), conn, "poolid", "hostid")
+	}()
+
+	waitStop := func(req StateRequest) {
+		timeout := time.After(5 * time.Second)
+		for {
+			s, err := GetState(conn, req)
+			c.Assert(err, IsNil)
+			if s.DesiredState == service.SVCStop {
+				return
+			}
+			select {
+			case <-time.After(100 * time.Millisecond):
+			case <-timeout:
+				c.Fatalf("Timed out waiting for %s to stop", req.ServiceID)
+			}
+		}
+	}
+
+	// the low priority instance is stopped first
+	waitStop(low)
+	s, err := GetState(conn, high)
+	c.Assert(err, IsNil)
+	c.Check(s.DesiredState, Not(Equals), service.SVCStop)
+
+	// the high priority instance is stopped once the low priority instance exits
+	err = DeleteState(conn, low)
+	c.Assert(err, IsNil)
+	waitStop(high)
+	err = DeleteState(conn, high)
+	c.Assert(err, IsNil)
+
+	select {
+	case err := <-done:
+		c.Assert(err, IsNil)
+	case <-time.After(5 * time.Second):
+		c.Fatalf("Timed out waiting for the host to drain")
+	}
+}
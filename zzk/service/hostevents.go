@@ -0,0 +1,75 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/host"
+)
+
+// maxHostEvents is the number of events kept in the history of a host
+const maxHostEvents = 50
+
+// HostEventsNode is the event history of a delegate
+type HostEventsNode struct {
+	Events  []host.Event // oldest first
+	version interface{}
+}
+
+// Version implements client.Node
+func (n *HostEventsNode) Version() interface{} {
+	return n.version
+}
+
+// SetVersion implements client.Node
+func (n *HostEventsNode) SetVersion(version interface{}) {
+	n.version = version
+}
+
+// AddHostEvent appends an event to the history of the host, dropping the
+// oldest events beyond maxHostEvents.  This is managed by the worker node, so
+// it is expected that the connection will be pre-loaded with the path to the
+// resource pool.  Returns client.ErrNoNode if the host is not registered.
+func AddHostEvent(conn client.Connection, hostid string, event host.Event) error {
+	pth := path.Join("/hosts", hostid, "events")
+	node := &HostEventsNode{}
+	if err := conn.Get(pth, node); err == client.ErrNoNode {
+		node.Events = []host.Event{event}
+		return conn.CreateIfExists(pth, node)
+	} else if err != nil {
+		return err
+	}
+	node.Events = append(node.Events, event)
+	if n := len(node.Events); n > maxHostEvents {
+		node.Events = append([]host.Event{}, node.Events[n-maxHostEvents:]...)
+	}
+	return conn.Set(pth, node)
+}
+
+// GetHostEvents returns the event history of the host, oldest first
+func GetHostEvents(conn client.Connection, poolid, hostid string) ([]host.Event, error) {
+	basepth := "/"
+	if poolid != "" {
+		basepth = path.Join("/pools", poolid)
+	}
+	node := &HostEventsNode{}
+	if err := conn.Get(path.Join(basepth, "/hosts", hostid, "events"), node); err == client.ErrNoNode {
+		return []host.Event{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return node.Events, nil
+}
@@ -27,9 +27,10 @@ import (
 // by watching for children within the path
 // /pools/POOLID/hosts/HOSTID/online
 type HostRegistryListener struct {
-	conn     client.Connection
-	poolid   string
-	isOnline chan struct{}
+	conn      client.Connection
+	poolid    string
+	isOnline  chan struct{}
+	clockSkew func(hostid string, skew time.Duration)
 }
 
 // NewHostRegistryListener instantiates a new host registry listener
@@ -44,6 +45,12 @@ func (h *HostRegistryListener) SetConnection(conn client.Connection) {
 	h.conn = conn
 }
 
+// SetClockSkewHandler sets the function that is called with a host's clock
+// skew each time the host reports a heartbeat
+func (h *HostRegistryListener) SetClockSkewHandler(fn func(hostid string, skew time.Duration)) {
+	h.clockSkew = fn
+}
+
 func (h *HostRegistryListener) GetPath(nodes ...string) string {
 	base := append([]string{"/pools", h.poolid, "hosts"}, nodes...)
 	return path.Join(base...)
@@ -65,6 +72,12 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 		"hostid": hostid,
 	})
 
+	// measure the host's clock and find out if it stops reporting
+	hbdone := make(chan struct{})
+	defer close(hbdone)
+	hbstatus := newHeartbeatStatus()
+	go h.watchHeartbeat(hbdone, hostid, hbstatus)
+
 	// set up the connection timeout timer and track outage times.
 	isOnline := false
 	outage := time.Now()
@@ -81,6 +94,9 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 
 	for {
 
+		// has the host stopped reporting?
+		isStalled, hbEv := hbstatus.get()
+
 		// does the host exist?
 		// path: /pools/<poolid>/hosts/<hostid>
 		isAvailable, availEv, err := h.conn.ExistsW(h.GetPath(hostid), stop)
@@ -95,6 +111,39 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 			return
 		}
 
+		// find out if the host is restarting serviced and has left its
+		// instances running
+		// path: /pools/<poolid>/hosts/<hostid>/restarting
+		restartpth := h.GetPath(hostid, "restarting")
+		isRestarting, restartEv, err := h.conn.ExistsW(restartpth, stop)
+		if err != nil {
+
+			logger.WithError(err).Error("Could not check restart status of host")
+			return
+		}
+		var restartTimeout <-chan time.Time
+		if isRestarting {
+			restart := &HostRestartNode{}
+			if err := h.conn.Get(restartpth, restart); err == client.ErrNoNode {
+				isRestarting = false
+			} else if err != nil {
+
+				logger.WithError(err).Error("Could not look up restart status of host")
+				return
+			} else if wait := restart.Deadline.Sub(time.Now()); wait > 0 {
+				restartTimeout = time.After(wait)
+			} else {
+
+				// the host did not come back in time, so treat it as down
+				logger.WithField("deadline", restart.Deadline).Warn("Host did not return from restarting serviced")
+				if err := h.conn.Delete(restartpth); err != nil && err != client.ErrNoNode {
+					logger.WithError(err).Error("Could not clear restart status of host")
+					return
+				}
+				isRestarting = false
+			}
+		}
+
 		// check to see if the host is up
 		// path: /pools/<poolid>/hosts/<hostid>/online
 		var ch []string
@@ -114,6 +163,14 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 				return
 			}
 			isAvailable = len(ch) > 0
+			if isAvailable && isStalled {
+				// the host's session is open, but it stopped reporting
+				logger.Debug("Host is online but not reporting heartbeats")
+				isAvailable = false
+			}
+		} else if isRestarting {
+			// host is restarting serviced, so leave its instances in place
+			logger.Debug("Host is restarting serviced; preserving instances")
 		} else {
 			// host has shut down cleanly, ensure all nodes are cleaned up
 			count := DeleteHostStates(h.conn, h.poolid, hostid)
@@ -190,6 +247,7 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 				case <-lockev:
 				case <-availEv:
 				case <-onlineEv:
+				case <-hbEv:
 				case <-cancel:
 					return
 				}
@@ -203,11 +261,26 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 				case <-lockev:
 				case <-availEv:
 				case <-onlineEv:
+				case <-hbEv:
 				case <-cancel:
 					return
 				}
 			}
 
+		} else if isRestarting {
+
+			// The host is restarting serviced with its containers still
+			// running, so wait for it to return rather than rescheduling.
+			select {
+			case <-restartTimeout:
+			case <-restartEv:
+			case <-availEv:
+			case <-onlineEv:
+			case <-hbEv:
+			case <-cancel:
+				return
+			}
+
 		} else if !isRunning {
 
 			// I only care about an outage if I am running instances.  If I am
@@ -216,6 +289,7 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 			select {
 			case <-availEv:
 			case <-onlineEv:
+			case <-hbEv:
 			case <-cancel:
 				return
 			}
@@ -251,39 +325,52 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 						// soon as possible.
 						select {
 						case <-h.isOnline:
-							// Only reschedule services without address
-							// assignments.
-							count := DeleteHostStatesWhen(h.conn, h.poolid, hostid, func(s *State) bool {
-								return s.DesiredState == service.SVCStop || !s.Static
-							})
-							logger.WithField("unscheduled", count).Warn("Host is experiencing an outage.  Cleaned up orphaned nodes")
+							count, until := h.rescheduleHostStates(hostid)
+							if until.IsZero() {
+								logger.WithField("unscheduled", count).Warn("Host is experiencing an outage.  Cleaned up orphaned nodes")
+							} else {
+								logger.WithFields(log.Fields{
+									"unscheduled": count,
+									"until":       until,
+								}).Info("Host is experiencing an outage during a maintenance window.  Cleaned up orphaned nodes that are not under maintenance")
+							}
 
 							// To prevent a tight loop, wait for something to
 							// happen.
+							var maintenanceEv <-chan time.Time
+							if !until.IsZero() {
+								maintenanceEv = time.After(until.Sub(time.Now()))
+							}
 							select {
+							case <-maintenanceEv:
 							case <-availEv:
 							case <-onlineEv:
+							case <-hbEv:
 							case <-cancel:
 								return
 							}
 
 						case <-availEv:
 						case <-onlineEv:
+						case <-hbEv:
 						case <-cancel:
 							return
 						}
 					case <-availEv:
 					case <-onlineEv:
+					case <-hbEv:
 					case <-cancel:
 						return
 					}
 				case <-availEv:
 				case <-onlineEv:
+				case <-hbEv:
 				case <-cancel:
 					return
 				}
 			case <-availEv:
 			case <-onlineEv:
+			case <-hbEv:
 			case <-cancel:
 				return
 			}
@@ -294,6 +381,38 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 	}
 }
 
+// rescheduleHostStates deletes the states of a host that is experiencing an
+// outage so that they can be rescheduled.  Only services without address
+// assignments are rescheduled, and the instances of hosts and services that
+// are under maintenance are left in place.  Returns the number of states
+// deleted and when the latest maintenance window that kept an instance in
+// place ends.
+func (h *HostRegistryListener) rescheduleHostStates(hostid string) (int, time.Time) {
+	now := time.Now()
+	maint, err := GetMaintenanceWindows(h.conn, h.poolid)
+	if err != nil {
+		plog.WithField("poolid", h.poolid).WithError(err).Warn("Could not look up maintenance windows")
+		maint = &MaintenanceNode{}
+	}
+
+	until := maint.ActiveUntil(hostid, "", now)
+	count := DeleteHostStatesWhen(h.conn, h.poolid, hostid, func(s *State) bool {
+		if s.DesiredState == service.SVCStop {
+			return true
+		} else if s.Static {
+			return false
+		}
+		if end := maint.ActiveUntil(hostid, s.ServiceID, now); !end.IsZero() {
+			if end.After(until) {
+				until = end
+			}
+			return false
+		}
+		return true
+	})
+	return count, until
+}
+
 // getTimeout returns the pool connection timeout.  Returns 0 if data cannot be
 // acquired.
 func (h *HostRegistryListener) getTimeout() time.Duration {
@@ -462,6 +581,15 @@ func RegisterHost(cancel <-chan interface{}, conn client.Connection, hostid stri
 				// TODO: wrap error?
 				return err
 			}
+
+			// the host is back, so it is no longer restarting
+			if err := ClearHostRestarting(conn, hostid); err != nil {
+
+				logger.WithError(err).Debug("Could not clear restart status of host")
+
+				// TODO: wrap error?
+				return err
+			}
 		}
 
 		select {
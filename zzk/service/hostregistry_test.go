@@ -260,3 +260,86 @@ func (t *ZZKTest) TestHostRegistryListener_Spawn(c *C) {
 		c.Fatalf("Timed out waiting for listener to shutdown")
 	}
 }
+
+func (t *ZZKTest) TestHostRegistryListener_StalledHeartbeat(c *C) {
+	conn, err := zzk.GetLocalConnection("/TestHostRegistry_StalledHeartbeat")
+	c.Assert(err, IsNil)
+
+	p := &pool.ResourcePool{
+		ID:                "testpool",
+		ConnectionTimeout: 1000,
+	}
+	ppth := path.Join("/pools", p.ID)
+	err = conn.Create(ppth, &PoolNode{ResourcePool: p})
+	c.Assert(err, IsNil)
+
+	s := &ServiceNode{
+		ID: "testservice",
+	}
+	spth := path.Join(ppth, "/services", s.ID)
+	err = conn.Create(spth, s)
+	c.Assert(err, IsNil)
+
+	listener := NewHostRegistryListener(p.ID)
+	listener.SetConnection(conn)
+
+	// both hosts are online, but h1 reports a heartbeat only once
+	for _, id := range []string{"h1", "h2"} {
+		h := &host.Host{ID: id, CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}
+		hpth := path.Join(ppth, "/hosts", id)
+		err = conn.Create(hpth, &HostNode{Host: h})
+		c.Assert(err, IsNil)
+		_, err = conn.CreateEphemeral(path.Join(hpth, "online", id), &client.Dir{})
+		c.Assert(err, IsNil)
+	}
+	h1pth := path.Join(ppth, "/hosts", "h1")
+	err = conn.Create(path.Join(h1pth, "heartbeat"), &HeartbeatNode{
+		HostID:    "h1",
+		Sequence:  1,
+		Timeout:   500 * time.Millisecond,
+		Timestamp: time.Now().UTC(),
+	})
+	c.Assert(err, IsNil)
+
+	req := StateRequest{
+		PoolID:     p.ID,
+		HostID:     "h1",
+		ServiceID:  s.ID,
+		InstanceID: 0,
+	}
+	err = CreateState(conn, req)
+	c.Assert(err, IsNil)
+	done := make(chan struct{})
+	ok, hst8ev, err := conn.ExistsW(path.Join(h1pth, "instances", req.StateID()), done)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	stop := make(chan interface{})
+	done2 := make(chan struct{})
+	go func() {
+		defer close(done)
+		listener.Spawn(stop, "h1")
+	}()
+	go func() {
+		defer close(done2)
+		listener.Spawn(stop, "h2")
+	}()
+	defer func() {
+		close(stop)
+		<-done
+		<-done2
+	}()
+
+	// the instance is rescheduled even though h1 is still online
+	select {
+	case <-hst8ev:
+		ok, err := conn.Exists(path.Join(h1pth, "instances", req.StateID()))
+		c.Assert(err, IsNil)
+		c.Assert(ok, Equals, false)
+		ch, err := conn.Children(path.Join(h1pth, "online"))
+		c.Assert(err, IsNil)
+		c.Assert(ch, HasLen, 1)
+	case <-time.After(500*time.Millisecond + 3*p.GetConnectionTimeout()):
+		c.Fatalf("Timed out waiting for the instance of a stalled host to be rescheduled")
+	}
+}
@@ -15,6 +15,7 @@ package service
 
 import (
 	"path"
+	"sync/atomic"
 	"time"
 
 	"github.com/control-center/serviced/coordinator/client"
@@ -32,14 +33,14 @@ type HostStateHandler interface {
 
 	// AttachContainer attaches to an existing container for the service
 	// instance. Returns nil channel if the container id doesn't match or if
-	// the container has stopped. Channel reports the time that the container
+	// the container has stopped. Channel reports when and how the container
 	// has stopped.
-	AttachContainer(state *ServiceState, serviceID string, instanceID int) (<-chan time.Time, error)
+	AttachContainer(state *ServiceState, serviceID string, instanceID int) (<-chan service.InstanceExit, error)
 
 	// StartContainer creates and starts a new container for the given service
 	// instance.  It returns relevant information about the container and a
 	// channel that triggers when the container has stopped.
-	StartContainer(cancel <-chan interface{}, serviceID string, instanceID int) (*ServiceState, <-chan time.Time, error)
+	StartContainer(cancel <-chan interface{}, serviceID string, instanceID int) (*ServiceState, <-chan service.InstanceExit, error)
 
 	// ResumeContainer resumes a paused container.  Returns nil if the
 	// container has stopped or if it doesn't exist.
@@ -52,9 +53,11 @@ type HostStateHandler interface {
 
 // HostStateListener is the listener for monitoring service instances
 type HostStateListener struct {
-	conn    client.Connection
-	handler HostStateHandler
-	hostID  string
+	conn     client.Connection
+	handler  HostStateHandler
+	hostID   string
+	detached int32
+	offline  int32
 }
 
 // NewHostListener instantiates a HostListener object
@@ -86,6 +89,36 @@ func (l *HostStateListener) Done() {
 // PostProcess implements zzk.Listener
 func (l *HostStateListener) PostProcess(p map[string]struct{}) {}
 
+// Detach makes the listener leave its containers running and its states in
+// place when it shuts down, so that they can be re-attached when serviced
+// restarts.
+func (l *HostStateListener) Detach() {
+	atomic.StoreInt32(&l.detached, 1)
+}
+
+// isDetached returns true if the listener should leave its containers
+// running on shutdown
+func (l *HostStateListener) isDetached() bool {
+	return atomic.LoadInt32(&l.detached) == 1
+}
+
+// RunOffline makes the listener leave its containers running and its states
+// in place when it loses its connection to the coordinator, so that the
+// handler can supervise them until the host reconnects.
+func (l *HostStateListener) RunOffline() {
+	atomic.StoreInt32(&l.offline, 1)
+}
+
+// isDisconnected returns true if the listener runs offline and can no longer
+// reach the coordinator
+func (l *HostStateListener) isDisconnected(stateID string) bool {
+	if atomic.LoadInt32(&l.offline) != 1 {
+		return false
+	}
+	_, err := l.conn.Exists(l.GetPath(stateID))
+	return err != nil
+}
+
 // Spawn listens for changes in the host state and manages running instances
 func (l *HostStateListener) Spawn(shutdown <-chan interface{}, stateID string) {
 	logger := plog.WithFields(log.Fields{
@@ -119,16 +152,28 @@ func (l *HostStateListener) Spawn(shutdown <-chan interface{}, stateID string) {
 		InstanceID: instanceID,
 	}
 
-	var containerExit <-chan time.Time
+	var containerExit <-chan service.InstanceExit
 	defer func() {
 
+		// leave the container running if serviced is restarting
+		if l.isDetached() {
+			logger.Debug("Detached from container")
+			return
+		}
+
+		// or if the host is running its instances offline
+		if l.isDisconnected(stateID) {
+			logger.Warn("Lost connection to the coordinator, leaving container running")
+			return
+		}
+
 		// stop the container
 		if err := l.handler.StopContainer(serviceID, instanceID); err != nil {
 			logger.WithError(err).Error("Could not stop container")
 		} else if containerExit != nil {
 			// wait for the container to exit
-			time := <-containerExit
-			logger.WithField("terminated", time).Debug("Container exited")
+			exit := <-containerExit
+			logger.WithField("terminated", exit.Terminated).Debug("Container exited")
 		}
 
 		// delete the state from the coordinator
@@ -192,7 +237,9 @@ func (l *HostStateListener) Spawn(shutdown <-chan interface{}, stateID string) {
 
 				// set the service state in zookeeper
 				if err := UpdateState(l.conn, req, func(s *State) bool {
+					exits := s.Exits
 					s.ServiceState = *ssdat
+					s.Exits = exits
 					return true
 				}); err != nil {
 
@@ -222,6 +269,18 @@ func (l *HostStateListener) Spawn(shutdown <-chan interface{}, stateID string) {
 				}
 
 				logger.Debug("Resumed paused container")
+			} else if ssdat.PauseError != "" {
+
+				// the container was never paused; clear the failure
+				if err := UpdateState(l.conn, req, func(s *State) bool {
+					s.PauseError = ""
+					*ssdat = s.ServiceState
+					return true
+				}); err != nil {
+
+					logger.WithError(err).Error("Could not clear pause failure of running container")
+					return
+				}
 			}
 
 		case service.SVCPause:
@@ -230,13 +289,25 @@ func (l *HostStateListener) Spawn(shutdown <-chan interface{}, stateID string) {
 				// container is attached and not paused, so pause the container
 				if err := l.handler.PauseContainer(serviceID, instanceID); err != nil {
 
+					// leave the container running and report the failure,
+					// so that the snapshot waiting on it can be aborted
 					logger.WithError(err).Error("Could not pause container")
-					return
+					if err := UpdateState(l.conn, req, func(s *State) bool {
+						s.PauseError = err.Error()
+						*ssdat = s.ServiceState
+						return true
+					}); err != nil {
+
+						logger.WithError(err).Error("Could not set state for container that failed to pause")
+						return
+					}
+					break
 				}
 
 				// set the service state in zookeeper
 				if err := UpdateState(l.conn, req, func(s *State) bool {
 					s.Paused = true
+					s.PauseError = ""
 					*ssdat = s.ServiceState
 					return true
 				}); err != nil {
@@ -258,18 +329,37 @@ func (l *HostStateListener) Spawn(shutdown <-chan interface{}, stateID string) {
 
 		select {
 		case <-hsevt:
-		case time := <-containerExit:
-
-			logger.WithField("terminated", time).Warn("Container exited unexpectedly, restarting")
+		case exit := <-containerExit:
+			terminated := exit.Terminated
+
+			until := l.maintenanceUntil(serviceID)
+			if until.IsZero() {
+				logger.WithField("terminated", terminated).Warn("Container exited unexpectedly, restarting")
+			} else {
+				logger.WithFields(log.Fields{
+					"terminated": terminated,
+					"until":      until,
+				}).Info("Container exited during a maintenance window, restarting when the window ends")
+			}
 			containerExit = nil
 			if err := UpdateState(l.conn, req, func(s *State) bool {
-				s.Terminated = time
+				s.Terminated = terminated
+				s.Exits = service.AppendInstanceExit(s.Exits, exit)
 				*ssdat = s.ServiceState
 				return true
 			}); err != nil {
 				logger.WithError(err).Error("Could not update state for stopped container")
 				return
 			}
+
+			// hold off on restarting the container until the maintenance
+			// window ends or the desired state changes.  Updating the state
+			// also touched the host state, which fired hsevt, so watch the
+			// host state again rather than waiting on it.
+			if !until.IsZero() && !l.holdForMaintenance(hspth, hsdat.DesiredState, until, shutdown) {
+				logger.Debug("Host state listener received signal to shut down")
+				return
+			}
 		case <-shutdown:
 
 			logger.Debug("Host state listener received signal to shut down")
@@ -280,3 +370,43 @@ func (l *HostStateListener) Spawn(shutdown <-chan interface{}, stateID string) {
 		done = make(chan struct{})
 	}
 }
+
+// holdForMaintenance blocks until the maintenance window ends or the desired
+// state of the instance changes from the given state.  Returns false if the
+// listener is shutting down.
+func (l *HostStateListener) holdForMaintenance(hspth string, desiredState service.DesiredState, until time.Time, shutdown <-chan interface{}) bool {
+	timer := time.NewTimer(until.Sub(time.Now()))
+	defer timer.Stop()
+	for {
+		done := make(chan struct{})
+		hsdat := &HostState{}
+		hsevt, err := l.conn.GetW(hspth, hsdat, done)
+		if err != nil || hsdat.DesiredState != desiredState {
+			// let the caller deal with a missing host state
+			close(done)
+			return true
+		}
+		select {
+		case <-hsevt:
+			close(done)
+		case <-timer.C:
+			close(done)
+			return true
+		case <-shutdown:
+			close(done)
+			return false
+		}
+	}
+}
+
+// maintenanceUntil returns when the maintenance window that covers the
+// service instance on this host ends, or the zero time if it is not under
+// maintenance.
+func (l *HostStateListener) maintenanceUntil(serviceID string) time.Time {
+	node, err := GetMaintenanceWindows(l.conn, "")
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up maintenance windows")
+		return time.Time{}
+	}
+	return node.ActiveUntil(l.hostID, serviceID, time.Now())
+}
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/maintenance"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/zzk"
 	. "github.com/control-center/serviced/zzk/service"
@@ -287,10 +288,10 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachRun(c *C) {
 		return true
 	})
 	c.Assert(err, IsNil)
-	containerExit := make(chan time.Time, 1)
-	var retExit <-chan time.Time = containerExit
+	containerExit := make(chan service.InstanceExit, 1)
+	var retExit <-chan service.InstanceExit = containerExit
 	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(retExit, nil).Once()
-	handler.On("StopContainer", serviceId, 1).Return(nil).Run(func(_ mock.Arguments) { containerExit <- time.Now() })
+	handler.On("StopContainer", serviceId, 1).Return(nil).Run(func(_ mock.Arguments) { containerExit <- service.InstanceExit{Terminated: time.Now()} })
 	listener := NewHostStateListener(handler, hostId)
 	listener.SetConnection(conn)
 
@@ -325,6 +326,64 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachRun(c *C) {
 	handler.AssertExpectations(c)
 }
 
+// Test Case: Listener detaches from a running container on shutdown
+func (t *ZZKTest) TestHostStateListener_Spawn_AttachDetach(c *C) {
+
+	conn := setUpServiceAndHostPaths(c)
+	handler := &mocks.HostStateHandler{}
+
+	req := StateRequest{
+		HostID:     hostId,
+		ServiceID:  serviceId,
+		InstanceID: 1,
+	}
+	err := CreateState(conn, req)
+	c.Assert(err, IsNil)
+
+	ssdat := ServiceState{
+		ContainerID: containerId,
+		ImageUUID:   imageId,
+		Paused:      false,
+		Started:     time.Now(),
+	}
+	err = UpdateState(conn, req, func(s *State) bool {
+		s.ServiceState = ssdat
+		return true
+	})
+	c.Assert(err, IsNil)
+	containerExit := make(chan service.InstanceExit, 1)
+	var retExit <-chan service.InstanceExit = containerExit
+	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(retExit, nil).Once()
+	listener := NewHostStateListener(handler, hostId)
+	listener.SetConnection(conn)
+
+	shutdown := make(chan interface{})
+	done := make(chan struct{})
+	go func() {
+		listener.Spawn(shutdown, req.StateID())
+		close(done)
+	}()
+
+	time.Sleep(time.Second)
+	listener.Detach()
+	close(shutdown)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		c.Fatalf("listener did not shut down")
+	}
+
+	// the container was not stopped and the state is preserved
+	ok, err := conn.Exists("/services/serviceid/" + req.StateID())
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	ok, err = conn.Exists("/hosts/hostid/instances/" + req.StateID())
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	handler.AssertExpectations(c)
+	handler.AssertNotCalled(c, "StopContainer", serviceId, 1)
+}
+
 // Test Case: Listener attaches to a paused running container
 func (t *ZZKTest) TestHostStateListener_Spawn_AttachResume(c *C) {
 
@@ -358,8 +417,8 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachResume(c *C) {
 	})
 	c.Assert(err, IsNil)
 
-	containerExit := make(chan time.Time, 1)
-	var retExit <-chan time.Time = containerExit
+	containerExit := make(chan service.InstanceExit, 1)
+	var retExit <-chan service.InstanceExit = containerExit
 
 	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(retExit, nil).Once()
 
@@ -417,7 +476,7 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachResume(c *C) {
 
 	// shutdown
 	handler.On("StopContainer", serviceId, 1).Return(nil).Run(func(_ mock.Arguments) {
-		containerExit <- time.Now()
+		containerExit <- service.InstanceExit{Terminated: time.Now()}
 	})
 	close(shutdown)
 	timer.Reset(time.Second)
@@ -467,8 +526,8 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachRestart(c *C) {
 	})
 	c.Assert(err, IsNil)
 
-	containerExit := make(chan time.Time, 1)
-	var retExit <-chan time.Time = containerExit
+	containerExit := make(chan service.InstanceExit, 1)
+	var retExit <-chan service.InstanceExit = containerExit
 
 	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(retExit, nil).Once()
 
@@ -502,7 +561,7 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachRestart(c *C) {
 	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(nil, nil).Once()
 	handler.On("StartContainer", retShutdown, serviceId, 1).Return(ssdat, retExit, nil).Once()
 
-	containerExit <- time.Now()
+	containerExit <- service.InstanceExit{Terminated: time.Now()}
 	timer = time.NewTimer(time.Second)
 	select {
 	case <-ev:
@@ -529,6 +588,8 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachRestart(c *C) {
 		ssdat2.Terminated = ssdat.Terminated
 		c.Check(ssdat2.Started.Equal(ssdat.Started), Equals, true)
 		ssdat2.Started = ssdat.Started
+		c.Check(ssdat2.Exits, HasLen, 1)
+		ssdat2.Exits = ssdat.Exits
 		c.Check(ssdat2, DeepEquals, ssdat)
 	case <-done:
 		c.Fatalf("Listener shutdown")
@@ -536,7 +597,7 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachRestart(c *C) {
 		c.Fatalf("Listener took too long")
 	}
 
-	handler.On("StopContainer", serviceId, 1).Return(nil).Run(func(args mock.Arguments) { containerExit <- time.Now() }).Once()
+	handler.On("StopContainer", serviceId, 1).Return(nil).Run(func(args mock.Arguments) { containerExit <- service.InstanceExit{Terminated: time.Now()} }).Once()
 
 	close(shutdown)
 	timer = time.NewTimer(time.Second)
@@ -551,6 +612,91 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachRestart(c *C) {
 	handler.AssertExpectations(c)
 }
 
+// Test Case: Listener holds off on restarting a container that exits during
+// an active maintenance window until the window ends
+func (t *ZZKTest) TestHostStateListener_Spawn_AttachMaintenanceHold(c *C) {
+	conn := setUpServiceAndHostPaths(c)
+	handler := &mocks.HostStateHandler{}
+
+	req := StateRequest{
+		HostID:     hostId,
+		ServiceID:  serviceId,
+		InstanceID: 1,
+	}
+	err := CreateState(conn, req)
+	c.Assert(err, IsNil)
+
+	// cover the service with a window that ends shortly
+	window := MaintenanceWindow{
+		Window: maintenance.Window{
+			ID:       "window",
+			Scope:    maintenance.ScopeService,
+			Target:   serviceId,
+			Start:    time.Now().Add(-time.Minute),
+			Duration: time.Minute + 2*time.Second,
+		},
+		ServiceIDs: []string{serviceId},
+	}
+	err = conn.Create("/maintenance", &MaintenanceNode{Windows: []MaintenanceWindow{window}})
+	c.Assert(err, IsNil)
+	until := window.ActiveUntil(time.Now())
+	c.Assert(until.IsZero(), Equals, false)
+
+	shutdown := make(chan interface{})
+	listener := NewHostStateListener(handler, hostId)
+	listener.SetConnection(conn)
+
+	err = UpdateState(conn, req, func(s *State) bool {
+		s.ServiceState = ServiceState{
+			ContainerID: containerId,
+			ImageUUID:   imageId,
+			Started:     time.Now(),
+		}
+		return true
+	})
+	c.Assert(err, IsNil)
+
+	containerExit := make(chan service.InstanceExit, 1)
+	var retExit <-chan service.InstanceExit = containerExit
+	var retShutdown <-chan interface{} = shutdown
+
+	started := make(chan time.Time, 1)
+	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(retExit, nil).Once()
+	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(nil, nil).Once()
+	handler.On("StartContainer", retShutdown, serviceId, 1).Return(&ServiceState{ContainerID: containerId, ImageUUID: imageId, Started: time.Now()}, retExit, nil).Run(func(args mock.Arguments) { started <- time.Now() }).Once()
+
+	done := make(chan struct{})
+	go func() {
+		listener.Spawn(shutdown, req.StateID())
+		close(done)
+	}()
+
+	// the exit is recorded, but the container is not restarted until the
+	// window ends
+	time.Sleep(100 * time.Millisecond)
+	containerExit <- service.InstanceExit{Terminated: time.Now()}
+
+	timer := time.NewTimer(5 * time.Second)
+	select {
+	case at := <-started:
+		c.Check(at.Before(until), Equals, false)
+	case <-done:
+		c.Fatalf("Listener shutdown")
+	case <-timer.C:
+		c.Fatalf("Listener took too long")
+	}
+
+	handler.On("StopContainer", serviceId, 1).Return(nil).Run(func(args mock.Arguments) { containerExit <- service.InstanceExit{Terminated: time.Now()} }).Once()
+	close(shutdown)
+	timer = time.NewTimer(time.Second)
+	select {
+	case <-done:
+	case <-timer.C:
+		c.Fatalf("Listener took too long")
+	}
+	handler.AssertExpectations(c)
+}
+
 // Test Case: Listener attaches to a running container and pauses the state
 func (t *ZZKTest) TestHostStateListener_Spawn_AttachPause(c *C) {
 
@@ -582,8 +728,8 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachPause(c *C) {
 	})
 	c.Assert(err, IsNil)
 
-	containerExit := make(chan time.Time, 1)
-	var retExit <-chan time.Time = containerExit
+	containerExit := make(chan service.InstanceExit, 1)
+	var retExit <-chan service.InstanceExit = containerExit
 
 	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(retExit, nil).Once()
 
@@ -641,7 +787,7 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachPause(c *C) {
 	}
 
 	// shutdown
-	handler.On("StopContainer", serviceId, 1).Return(nil).Run(func(_ mock.Arguments) { containerExit <- time.Now() }).Once()
+	handler.On("StopContainer", serviceId, 1).Return(nil).Run(func(_ mock.Arguments) { containerExit <- service.InstanceExit{Terminated: time.Now()} }).Once()
 	close(shutdown)
 	timer.Reset(time.Second)
 	select {
@@ -656,6 +802,140 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachPause(c *C) {
 	handler.AssertExpectations(c)
 }
 
+// Test Case: Listener attaches to a running container that fails to pause
+func (t *ZZKTest) TestHostStateListener_Spawn_AttachPauseFailed(c *C) {
+
+	conn := setUpServiceAndHostPaths(c)
+	handler := &mocks.HostStateHandler{}
+
+	req := StateRequest{
+		HostID:     hostId,
+		ServiceID:  serviceId,
+		InstanceID: 1,
+	}
+	err := CreateState(conn, req)
+	c.Assert(err, IsNil)
+
+	shutdown := make(chan interface{})
+	listener := NewHostStateListener(handler, hostId)
+	listener.SetConnection(conn)
+
+	// set up a running container
+	ssdat := &ServiceState{
+		ContainerID: containerId,
+		ImageUUID:   imageId,
+		Paused:      false,
+		Started:     time.Now(),
+	}
+	err = UpdateState(conn, req, func(s *State) bool {
+		s.ServiceState = *ssdat
+		return true
+	})
+	c.Assert(err, IsNil)
+
+	containerExit := make(chan service.InstanceExit, 1)
+	var retExit <-chan service.InstanceExit = containerExit
+
+	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(retExit, nil).Once()
+
+	done := make(chan struct{})
+	ev, err := conn.GetW("/services/serviceid/"+req.StateID(), ssdat, done)
+	c.Assert(err, IsNil)
+	go func() {
+		listener.Spawn(shutdown, req.StateID())
+		close(done)
+	}()
+
+	timer := time.NewTimer(time.Second)
+	select {
+	case <-ev:
+		c.Fatalf("Unexpected event from service state")
+	case <-done:
+		c.Fatalf("Listener shutdown")
+	case <-timer.C:
+	}
+
+	// fail to pause the container
+	handler.On("PauseContainer", serviceId, 1).Return(errors.New("flush failed")).Once()
+	err = UpdateState(conn, req, func(s *State) bool {
+		s.DesiredState = service.SVCPause
+		return true
+	})
+	c.Assert(err, IsNil)
+	timer.Reset(time.Second)
+	select {
+	case <-ev:
+		ev, err = conn.GetW("/services/serviceid/"+req.StateID(), ssdat, done)
+		c.Assert(err, IsNil)
+
+		// may have been triggered by event to update desired state
+		if ssdat.PauseError == "" {
+			timer.Reset(time.Second)
+			select {
+			case <-ev:
+				ev, err = conn.GetW("/services/serviceid/"+req.StateID(), ssdat, done)
+				c.Assert(err, IsNil)
+			case <-done:
+				c.Fatalf("Listener shutdown")
+			case <-timer.C:
+				c.Fatalf("Listener took too long")
+			}
+		}
+
+		c.Check(ssdat.Paused, Equals, false)
+		c.Check(ssdat.PauseError, Equals, "flush failed")
+	case <-done:
+		c.Fatalf("Listener shutdown")
+	case <-timer.C:
+		c.Fatalf("Listener took too long")
+	}
+
+	// the container keeps running and the failure is cleared when the
+	// instance is set to run again
+	err = UpdateState(conn, req, func(s *State) bool {
+		s.DesiredState = service.SVCRun
+		return true
+	})
+	c.Assert(err, IsNil)
+	timer.Reset(time.Second)
+	select {
+	case <-ev:
+		ev, err = conn.GetW("/services/serviceid/"+req.StateID(), ssdat, done)
+		c.Assert(err, IsNil)
+		if ssdat.PauseError != "" {
+			timer.Reset(time.Second)
+			select {
+			case <-ev:
+				ev, err = conn.GetW("/services/serviceid/"+req.StateID(), ssdat, done)
+				c.Assert(err, IsNil)
+			case <-done:
+				c.Fatalf("Listener shutdown")
+			case <-timer.C:
+				c.Fatalf("Listener took too long")
+			}
+		}
+		c.Check(ssdat.PauseError, Equals, "")
+	case <-done:
+		c.Fatalf("Listener shutdown")
+	case <-timer.C:
+		c.Fatalf("Listener took too long")
+	}
+
+	// shutdown
+	handler.On("StopContainer", serviceId, 1).Return(nil).Run(func(_ mock.Arguments) { containerExit <- service.InstanceExit{Terminated: time.Now()} }).Once()
+	close(shutdown)
+	timer.Reset(time.Second)
+	select {
+	case e := <-ev:
+		c.Check(e.Type, Equals, client.EventNodeDeleted)
+	case <-done:
+		c.Logf("Listener shutdown")
+	case <-timer.C:
+		c.Fatalf("Listener took too long")
+	}
+	handler.AssertExpectations(c)
+}
+
 // Test Case: Listener attaches to a paused running container (no change)
 func (t *ZZKTest) TestHostStateListener_Spawn_AttachPausePaused(c *C) {
 
@@ -688,8 +968,8 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachPausePaused(c *C) {
 	})
 	c.Assert(err, IsNil)
 
-	containerExit := make(chan time.Time, 1)
-	var retExit <-chan time.Time = containerExit
+	containerExit := make(chan service.InstanceExit, 1)
+	var retExit <-chan service.InstanceExit = containerExit
 
 	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(retExit, nil).Once()
 
@@ -712,7 +992,7 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachPausePaused(c *C) {
 
 	// shutdown
 	handler.On("StopContainer", serviceId, 1).Return(nil).Run(func(_ mock.Arguments) {
-		containerExit <- time.Now()
+		containerExit <- service.InstanceExit{Terminated: time.Now()}
 	}).Once()
 	close(shutdown)
 	timer = time.NewTimer(time.Second)
@@ -817,8 +1097,8 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachStop(c *C) {
 	})
 	c.Assert(err, IsNil)
 
-	containerExit := make(chan time.Time, 1)
-	var retExit <-chan time.Time = containerExit
+	containerExit := make(chan service.InstanceExit, 1)
+	var retExit <-chan service.InstanceExit = containerExit
 
 	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(retExit, nil).Once()
 
@@ -832,7 +1112,7 @@ func (t *ZZKTest) TestHostStateListener_Spawn_AttachStop(c *C) {
 	}()
 
 	handler.On("StopContainer", serviceId, 1).Return(nil).Run(func(_ mock.Arguments) {
-		containerExit <- time.Now()
+		containerExit <- service.InstanceExit{Terminated: time.Now()}
 	})
 	timer := time.NewTimer(time.Second)
 	select {
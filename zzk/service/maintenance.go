@@ -0,0 +1,99 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/maintenance"
+)
+
+// MaintenanceWindow is a maintenance window that applies to the services or
+// hosts of a resource pool
+type MaintenanceWindow struct {
+	maintenance.Window
+	ServiceIDs []string // services covered by a deployment or service window
+}
+
+// MaintenanceNode is the set of maintenance windows of a resource pool
+type MaintenanceNode struct {
+	Windows []MaintenanceWindow
+	version interface{}
+}
+
+// Version implements client.Node
+func (n *MaintenanceNode) Version() interface{} {
+	return n.version
+}
+
+// SetVersion implements client.Node
+func (n *MaintenanceNode) SetVersion(version interface{}) {
+	n.version = version
+}
+
+// ActiveUntil returns when the latest active maintenance window that covers
+// the host or the service ends, or the zero time if neither is under
+// maintenance.  Pass an empty host or service id to ignore it.
+func (n *MaintenanceNode) ActiveUntil(hostID, serviceID string, at time.Time) time.Time {
+	var until time.Time
+	for _, w := range n.Windows {
+		covered := hostID != "" && w.Scope == maintenance.ScopeHost && w.Target == hostID
+		if !covered && serviceID != "" {
+			for _, id := range w.ServiceIDs {
+				if id == serviceID {
+					covered = true
+					break
+				}
+			}
+		}
+		if !covered {
+			continue
+		}
+		if end := w.ActiveUntil(at); end.After(until) {
+			until = end
+		}
+	}
+	return until
+}
+
+// SetMaintenanceWindows replaces the maintenance windows of a resource pool
+func SetMaintenanceWindows(conn client.Connection, poolID string, windows []MaintenanceWindow) error {
+	pth := path.Join("/pools", poolID, "maintenance")
+	node := &MaintenanceNode{Windows: windows}
+	existing := &MaintenanceNode{}
+	if err := conn.Get(pth, existing); err == client.ErrNoNode {
+		return conn.CreateIfExists(pth, node)
+	} else if err != nil {
+		return err
+	}
+	node.SetVersion(existing.Version())
+	return conn.Set(pth, node)
+}
+
+// GetMaintenanceWindows returns the maintenance windows of a resource pool.
+// If the pool id is empty, it is expected that the connection will be
+// pre-loaded with the path to the resource pool.
+func GetMaintenanceWindows(conn client.Connection, poolID string) (*MaintenanceNode, error) {
+	basepth := "/"
+	if poolID != "" {
+		basepth = path.Join("/pools", poolID)
+	}
+	node := &MaintenanceNode{}
+	if err := conn.Get(path.Join(basepth, "maintenance"), node); err != nil && err != client.ErrNoNode {
+		return nil, err
+	}
+	return node, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build integration,!quick
+
+package service_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/maintenance"
+	"github.com/control-center/serviced/zzk"
+	. "github.com/control-center/serviced/zzk/service"
+
+	. "gopkg.in/check.v1"
+)
+
+func (t *ZZKTest) TestMaintenanceWindows(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+	poolconn, err := zzk.GetLocalConnection("/pools/default")
+	c.Assert(err, IsNil)
+	err = conn.CreateDir("/pools/default")
+	c.Assert(err, IsNil)
+
+	// no windows have been set
+	node, err := GetMaintenanceWindows(conn, "default")
+	c.Assert(err, IsNil)
+	c.Assert(node.Windows, HasLen, 0)
+
+	now := time.Now()
+	windows := []MaintenanceWindow{
+		{
+			Window: maintenance.Window{
+				ID:       "hostwindow",
+				Scope:    maintenance.ScopeHost,
+				Target:   "host1",
+				Start:    now.Add(-time.Minute),
+				Duration: time.Hour,
+			},
+		}, {
+			Window: maintenance.Window{
+				ID:       "servicewindow",
+				Scope:    maintenance.ScopeService,
+				Target:   "service1",
+				Start:    now.Add(-time.Minute),
+				Duration: 2 * time.Hour,
+			},
+			ServiceIDs: []string{"service1", "service2"},
+		},
+	}
+	err = SetMaintenanceWindows(conn, "default", windows)
+	c.Assert(err, IsNil)
+	err = SetMaintenanceWindows(conn, "default", windows)
+	c.Assert(err, IsNil)
+
+	node, err = GetMaintenanceWindows(poolconn, "")
+	c.Assert(err, IsNil)
+	c.Assert(node.Windows, HasLen, 2)
+
+	hostEnd := windows[0].Start.Add(time.Hour)
+	serviceEnd := windows[1].Start.Add(2 * time.Hour)
+	c.Assert(node.ActiveUntil("host1", "", now).Equal(hostEnd), Equals, true)
+	c.Assert(node.ActiveUntil("host1", "service2", now).Equal(serviceEnd), Equals, true)
+	c.Assert(node.ActiveUntil("host2", "service2", now).Equal(serviceEnd), Equals, true)
+	c.Assert(node.ActiveUntil("host2", "service3", now).IsZero(), Equals, true)
+	c.Assert(node.ActiveUntil("", "", now).IsZero(), Equals, true)
+	c.Assert(node.ActiveUntil("host1", "service1", now.Add(3*time.Hour)).IsZero(), Equals, true)
+}
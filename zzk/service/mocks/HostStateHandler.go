@@ -3,7 +3,7 @@ package mocks
 import zk "github.com/control-center/serviced/zzk/service"
 import "github.com/stretchr/testify/mock"
 
-import "time"
+import "github.com/control-center/serviced/domain/service"
 
 type HostStateHandler struct {
 	mock.Mock
@@ -21,15 +21,15 @@ func (_m *HostStateHandler) StopContainer(serviceID string, instanceID int) erro
 
 	return r0
 }
-func (_m *HostStateHandler) AttachContainer(state *zk.ServiceState, serviceID string, instanceID int) (<-chan time.Time, error) {
+func (_m *HostStateHandler) AttachContainer(state *zk.ServiceState, serviceID string, instanceID int) (<-chan service.InstanceExit, error) {
 	ret := _m.Called(state, serviceID, instanceID)
 
-	var r0 <-chan time.Time
-	if rf, ok := ret.Get(0).(func(*zk.ServiceState, string, int) <-chan time.Time); ok {
+	var r0 <-chan service.InstanceExit
+	if rf, ok := ret.Get(0).(func(*zk.ServiceState, string, int) <-chan service.InstanceExit); ok {
 		r0 = rf(state, serviceID, instanceID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(<-chan time.Time)
+			r0 = ret.Get(0).(<-chan service.InstanceExit)
 		}
 	}
 
@@ -42,7 +42,7 @@ func (_m *HostStateHandler) AttachContainer(state *zk.ServiceState, serviceID st
 
 	return r0, r1
 }
-func (_m *HostStateHandler) StartContainer(cancel <-chan interface{}, serviceID string, instanceID int) (*zk.ServiceState, <-chan time.Time, error) {
+func (_m *HostStateHandler) StartContainer(cancel <-chan interface{}, serviceID string, instanceID int) (*zk.ServiceState, <-chan service.InstanceExit, error) {
 	ret := _m.Called(cancel, serviceID, instanceID)
 
 	var r0 *zk.ServiceState
@@ -54,12 +54,12 @@ func (_m *HostStateHandler) StartContainer(cancel <-chan interface{}, serviceID
 		}
 	}
 
-	var r1 <-chan time.Time
-	if rf, ok := ret.Get(1).(func(<-chan interface{}, string, int) <-chan time.Time); ok {
+	var r1 <-chan service.InstanceExit
+	if rf, ok := ret.Get(1).(func(<-chan interface{}, string, int) <-chan service.InstanceExit); ok {
 		r1 = rf(cancel, serviceID, instanceID)
 	} else {
 		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(<-chan time.Time)
+			r1 = ret.Get(1).(<-chan service.InstanceExit)
 		}
 	}
 
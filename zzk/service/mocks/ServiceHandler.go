@@ -7,19 +7,19 @@ type ServiceHandler struct {
 	mock.Mock
 }
 
-func (_m *ServiceHandler) SelectHost(_a0 *service.ServiceNode) (string, error) {
-	ret := _m.Called(_a0)
+func (_m *ServiceHandler) SelectHost(sn *service.ServiceNode, instanceID int) (string, error) {
+	ret := _m.Called(sn, instanceID)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*service.ServiceNode) string); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(*service.ServiceNode, int) string); ok {
+		r0 = rf(sn, instanceID)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*service.ServiceNode) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(*service.ServiceNode, int) error); ok {
+		r1 = rf(sn, instanceID)
 	} else {
 		r1 = ret.Error(1)
 	}
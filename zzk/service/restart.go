@@ -0,0 +1,81 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// HostRestartNode marks a host whose delegate is restarting serviced and
+// has left its service instances running
+type HostRestartNode struct {
+	Deadline time.Time // when the master stops waiting for the host to return
+	version  interface{}
+}
+
+// Version implements client.Node
+func (n *HostRestartNode) Version() interface{} {
+	return n.version
+}
+
+// SetVersion implements client.Node
+func (n *HostRestartNode) SetVersion(version interface{}) {
+	n.version = version
+}
+
+// MarkHostRestarting tells the master that the host is restarting serviced
+// and that its instances should be preserved until the deadline.  This is
+// managed by the worker node, so it is expected that the connection will be
+// pre-loaded with the path to the resource pool.
+func MarkHostRestarting(conn client.Connection, hostid string, deadline time.Time) error {
+	pth := path.Join("/hosts", hostid, "restarting")
+	node := &HostRestartNode{Deadline: deadline}
+	existing := &HostRestartNode{}
+	if err := conn.Get(pth, existing); err == client.ErrNoNode {
+		return conn.CreateIfExists(pth, node)
+	} else if err != nil {
+		return err
+	}
+	node.SetVersion(existing.Version())
+	return conn.Set(pth, node)
+}
+
+// ClearHostRestarting removes the restart marker of the host.  This is
+// managed by the worker node, so it is expected that the connection will be
+// pre-loaded with the path to the resource pool.
+func ClearHostRestarting(conn client.Connection, hostid string) error {
+	if err := conn.Delete(path.Join("/hosts", hostid, "restarting")); err != nil && err != client.ErrNoNode {
+		return err
+	}
+	return nil
+}
+
+// IsHostRestarting returns true if the host is restarting serviced and has
+// not exceeded its restart deadline.
+func IsHostRestarting(conn client.Connection, poolid, hostid string) (bool, error) {
+	basepth := "/"
+	if poolid != "" {
+		basepth = path.Join("/pools", poolid)
+	}
+	node := &HostRestartNode{}
+	if err := conn.Get(path.Join(basepth, "/hosts", hostid, "restarting"), node); err == client.ErrNoNode {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return time.Now().Before(node.Deadline), nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration,!quick
+
+package service_test
+
+import (
+	"path"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/zzk"
+	. "github.com/control-center/serviced/zzk/service"
+
+	. "gopkg.in/check.v1"
+)
+
+func (t *ZZKTest) TestHostRestarting(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+	poolconn, err := zzk.GetLocalConnection("/pools/default")
+	c.Assert(err, IsNil)
+
+	// host is not registered
+	err = MarkHostRestarting(poolconn, "restarthost", time.Now().Add(time.Minute))
+	c.Assert(err, Equals, client.ErrNoNode)
+
+	err = conn.CreateDir("/pools/default/hosts/restarthost")
+	c.Assert(err, IsNil)
+	ok, err := IsHostRestarting(conn, "default", "restarthost")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	// host is restarting
+	err = MarkHostRestarting(poolconn, "restarthost", time.Now().Add(time.Minute))
+	c.Assert(err, IsNil)
+	ok, err = IsHostRestarting(conn, "default", "restarthost")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	// host exceeded its deadline
+	err = MarkHostRestarting(poolconn, "restarthost", time.Now().Add(-time.Minute))
+	c.Assert(err, IsNil)
+	ok, err = IsHostRestarting(conn, "default", "restarthost")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	// host came back
+	err = ClearHostRestarting(poolconn, "restarthost")
+	c.Assert(err, IsNil)
+	ok, err = conn.Exists("/pools/default/hosts/restarthost/restarting")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+	err = ClearHostRestarting(poolconn, "restarthost")
+	c.Assert(err, IsNil)
+}
+
+func (t *ZZKTest) TestHostRegistryListener_Spawn_Restarting(c *C) {
+	conn, err := zzk.GetLocalConnection("/TestHostRegistry_Spawn_Restarting")
+	c.Assert(err, IsNil)
+
+	// set up the resource pool
+	p := &pool.ResourcePool{
+		ID:                "testpool",
+		ConnectionTimeout: 2000,
+	}
+	ppth := path.Join("/pools", p.ID)
+	err = conn.Create(ppth, &PoolNode{ResourcePool: p})
+	c.Assert(err, IsNil)
+
+	s := &ServiceNode{
+		ID: "testservice",
+	}
+	spth := path.Join(ppth, "/services", s.ID)
+	err = conn.Create(spth, s)
+	c.Assert(err, IsNil)
+
+	// the host shut down while restarting serviced with an instance running
+	h1 := &host.Host{
+		ID:        "h1",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	h1pth := path.Join(ppth, "/hosts", h1.ID)
+	err = conn.Create(h1pth, &HostNode{Host: h1})
+	c.Assert(err, IsNil)
+	req := StateRequest{
+		PoolID:     p.ID,
+		HostID:     h1.ID,
+		ServiceID:  s.ID,
+		InstanceID: 0,
+	}
+	err = CreateState(conn, req)
+	c.Assert(err, IsNil)
+	poolconn, err := zzk.GetLocalConnection(path.Join("/TestHostRegistry_Spawn_Restarting", ppth))
+	c.Assert(err, IsNil)
+	err = MarkHostRestarting(poolconn, h1.ID, time.Now().Add(2*p.GetConnectionTimeout()))
+	c.Assert(err, IsNil)
+
+	listener := NewHostRegistryListener(p.ID)
+	listener.SetConnection(conn)
+	stop := make(chan interface{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		listener.Spawn(stop, h1.ID)
+	}()
+
+	// the instance is preserved until the deadline
+	time.Sleep(p.GetConnectionTimeout())
+	ok, err := conn.Exists(path.Join(spth, req.StateID()))
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	// the instance is cleaned up after the deadline
+	time.Sleep(2 * p.GetConnectionTimeout())
+	ok, err = conn.Exists(path.Join(spth, req.StateID()))
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+	ok, err = conn.Exists(path.Join(h1pth, "restarting"))
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(p.GetConnectionTimeout()):
+		c.Fatalf("Timed out waiting for listener to exit")
+	}
+}
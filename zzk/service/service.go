@@ -22,6 +22,7 @@ import (
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -48,6 +49,10 @@ type ServiceNode struct {
 	ChangeOptions               []string
 	AddressAssignment           addressassignment.AddressAssignment
 	ShouldHaveAddressAssignment bool
+	HealthChecks                map[string]health.HealthCheck
+	Priority                    string
+	Placement                   servicedefinition.Placement
+	ImageID                     string
 	//non-service fields
 	Locked  bool
 	version interface{}
@@ -61,6 +66,10 @@ func NewServiceNodeFromService(s *service.Service) (*ServiceNode, error) {
 		Instances:     s.Instances,
 		RAMCommitment: s.RAMCommitment,
 		ChangeOptions: s.ChangeOptions,
+		HealthChecks:  s.HealthChecks,
+		Priority:      s.Priority,
+		Placement:     s.Placement,
+		ImageID:       s.ImageID,
 	}
 
 	// Copy address assignment if it exists. Note whether assignment is expected, so the scheduler can verify it later.
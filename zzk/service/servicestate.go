@@ -15,6 +15,7 @@ package service
 
 import (
 	"path"
+	"sync"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
@@ -23,9 +24,17 @@ import (
 	"github.com/control-center/serviced/utils"
 )
 
+// priorityStartTimeout is how long after the listener starts that services
+// wait for services with a higher priority to start before they are scheduled
+const priorityStartTimeout = 5 * time.Minute
+
+// priorityScanInterval is how often the services in the pool are checked for
+// a higher priority service that is still starting
+const priorityScanInterval = time.Second
+
 // ServiceHandler handles all non-zookeeper interactions required by the service
 type ServiceHandler interface {
-	SelectHost(*ServiceNode) (string, error)
+	SelectHost(sn *ServiceNode, instanceID int) (string, error)
 }
 
 // ServiceListener is the listener for /services
@@ -33,6 +42,14 @@ type ServiceListener struct {
 	conn    client.Connection
 	handler ServiceHandler
 	poolid  string
+	started time.Time
+
+	// the services that are waiting for higher priority services to start
+	// share the last scan of the pool
+	priorityMu   sync.Mutex
+	scanned      time.Time
+	startingID   string // highest priority service that is still starting
+	startingRank int
 }
 
 // NewServiceListener instantiates a new ServiceListener
@@ -53,7 +70,12 @@ func (l *ServiceListener) GetPath(nodes ...string) string {
 }
 
 // Ready implements zzk.Listener
-func (l *ServiceListener) Ready() (err error) { return }
+func (l *ServiceListener) Ready() (err error) {
+	if l.started.IsZero() {
+		l.started = time.Now()
+	}
+	return
+}
 
 // Done implements zzk.Listener
 func (l *ServiceListener) Done() { return }
@@ -129,8 +151,11 @@ func (l *ServiceListener) Spawn(shutdown <-chan interface{}, serviceID string) {
 				timer.Reset(time.Second)
 			}
 
-			// Synchronize the number of service states
-			if _, ok := l.Sync(sDat.Locked, sDat, reqs); !ok {
+			// Synchronize the number of service states once the services
+			// with a higher priority have started
+			if l.waitForPriority(logger, sDat, len(reqs)) {
+				timer.Reset(time.Second)
+			} else if _, ok := l.Sync(sDat.Locked, sDat, reqs); !ok {
 				timer.Reset(time.Second)
 			}
 
@@ -169,6 +194,81 @@ func (l *ServiceListener) Spawn(shutdown <-chan interface{}, serviceID string) {
 	}
 }
 
+// waitForPriority returns true if the service needs more instances but the
+// listener started recently and a service with a higher priority has not yet
+// started all of its instances.
+func (l *ServiceListener) waitForPriority(logger *log.Entry, sn *ServiceNode, count int) bool {
+	if count >= sn.Instances || time.Since(l.started) > priorityStartTimeout {
+		return false
+	}
+	rank, _ := service.PriorityRank(sn.Priority)
+	if rank == 0 {
+		return false
+	}
+	if serviceID, startingRank := l.getStartingRank(logger); serviceID != "" && startingRank < rank {
+		logger.WithField("waitingfor", serviceID).Debug("Waiting for a higher priority service to start")
+		return true
+	}
+	return false
+}
+
+// getStartingRank returns the highest priority service in the pool that has
+// not yet started all of its instances, and the rank of its priority.
+// Returns an empty service id if every service has started.  The pool is
+// scanned at most once per interval, and the result is shared by all of the
+// services of the listener.
+func (l *ServiceListener) getStartingRank(logger *log.Entry) (string, int) {
+	l.priorityMu.Lock()
+	defer l.priorityMu.Unlock()
+	if time.Since(l.scanned) < priorityScanInterval {
+		return l.startingID, l.startingRank
+	}
+
+	serviceIDs, err := l.conn.Children(l.GetPath())
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up services to check their priority")
+		return "", 0
+	}
+	startingID, startingRank := "", 0
+	for _, serviceID := range serviceIDs {
+		sn, err := NewServiceNodeFromService(&service.Service{})
+		if err != nil {
+			return "", 0
+		}
+		if err := l.conn.Get(l.GetPath(serviceID), sn); err != nil {
+			continue
+		}
+		if sn.DesiredState != int(service.SVCRun) || sn.Instances == 0 {
+			continue
+		}
+		rank, _ := service.PriorityRank(sn.Priority)
+		if startingID != "" && rank >= startingRank {
+			continue
+		}
+		if !l.hasStarted(serviceID, sn.Instances) {
+			startingID, startingRank = serviceID, rank
+		}
+	}
+	l.scanned = time.Now()
+	l.startingID, l.startingRank = startingID, startingRank
+	return startingID, startingRank
+}
+
+// hasStarted returns true if all of the instances of the service have started
+func (l *ServiceListener) hasStarted(serviceID string, instances int) bool {
+	stateIDs, err := l.conn.Children(l.GetPath(serviceID))
+	if err != nil || len(stateIDs) < instances {
+		return false
+	}
+	for _, stateID := range stateIDs {
+		ss := &ServiceState{}
+		if err := l.conn.Get(l.GetPath(serviceID, stateID), ss); err != nil || !ss.Started.After(ss.Terminated) {
+			return false
+		}
+	}
+	return true
+}
+
 // getStateRequests returns a list of state requests
 func (l *ServiceListener) getStateRequests(logger *log.Entry, stateIDs []string) ([]StateRequest, bool) {
 
@@ -269,7 +369,7 @@ func (l *ServiceListener) Start(sn *ServiceNode, instanceID int) bool {
 	})
 
 	// pick a host
-	hostID, err := l.handler.SelectHost(sn)
+	hostID, err := l.handler.SelectHost(sn, instanceID)
 	if err != nil {
 
 		logger.WithError(err).Warn("Could not select host")
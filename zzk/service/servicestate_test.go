@@ -53,7 +53,7 @@ func (t *ZZKTest) TestServiceListener_Spawn(c *C) {
 	// an online host
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
 	c.Assert(err, IsNil)
-	handler.On("SelectHost", mock.AnythingOfType("*service.ServiceNode")).Return("hostid", nil)
+	handler.On("SelectHost", mock.AnythingOfType("*service.ServiceNode"), mock.AnythingOfType("int")).Return("hostid", nil)
 
 	listener := NewServiceListener("poolid", handler)
 	listener.SetConnection(conn)
@@ -218,7 +218,7 @@ func (t *ZZKTest) TestServiceListener_Sync_Unlocked(c *C) {
 	// an online host
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
 	c.Assert(err, IsNil)
-	handler.On("SelectHost", sn).Return("hostid", nil)
+	handler.On("SelectHost", sn, mock.AnythingOfType("int")).Return("hostid", nil)
 
 	listener := NewServiceListener("poolid", handler)
 	listener.SetConnection(conn)
@@ -364,7 +364,7 @@ func (t *ZZKTest) TestServiceListener_Sync_Locked(c *C) {
 	// an online host
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
 	c.Assert(err, IsNil)
-	handler.On("SelectHost", sn).Return("hostid", nil)
+	handler.On("SelectHost", sn, mock.AnythingOfType("int")).Return("hostid", nil)
 
 	listener := NewServiceListener("poolid", handler)
 	listener.SetConnection(conn)
@@ -424,7 +424,7 @@ func (t *ZZKTest) TestServiceListener_Sync_RestartAllOnInstanceChanged(c *C) {
 	// an online host
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
 	c.Assert(err, IsNil)
-	handler.On("SelectHost", sn).Return("hostid", nil)
+	handler.On("SelectHost", sn, mock.AnythingOfType("int")).Return("hostid", nil)
 
 	listener := NewServiceListener("poolid", handler)
 	listener.SetConnection(conn)
@@ -481,10 +481,10 @@ func (t *ZZKTest) TestServiceListener_Start(c *C) {
 	listener.SetConnection(conn)
 
 	// no host
-	handler.On("SelectHost", sn).Return("", ErrTestHostNotFound).Once()
+	handler.On("SelectHost", sn, mock.AnythingOfType("int")).Return("", ErrTestHostNotFound).Once()
 	c.Assert(listener.Start(sn, 0), Equals, false)
 
-	handler.On("SelectHost", sn).Return("hostid", nil)
+	handler.On("SelectHost", sn, mock.AnythingOfType("int")).Return("hostid", nil)
 
 	// host state exists
 	req := StateRequest{
@@ -501,6 +501,80 @@ func (t *ZZKTest) TestServiceListener_Start(c *C) {
 	c.Check(listener.Start(sn, 1), Equals, true)
 }
 
+func (t *ZZKTest) TestServiceListener_Spawn_WaitForPriority(c *C) {
+	// Pre-requisites
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+	handler := &mocks.ServiceHandler{}
+
+	// Basic set up
+	critical := &ServiceNode{
+		ID:           "serviceid1",
+		Name:         "serviceA",
+		DesiredState: int(service.SVCRun),
+		Instances:    1,
+		Priority:     service.PriorityCritical,
+	}
+	cpth := "/pools/poolid/services/serviceid1"
+	err = conn.Create(cpth, critical)
+	c.Assert(err, IsNil)
+	sn := &ServiceNode{
+		ID:           "serviceid2",
+		Name:         "serviceB",
+		DesiredState: int(service.SVCRun),
+		Instances:    1,
+	}
+	spth := "/pools/poolid/services/serviceid2"
+	err = conn.Create(spth, sn)
+	c.Assert(err, IsNil)
+
+	// an online host
+	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
+	c.Assert(err, IsNil)
+	handler.On("SelectHost", mock.AnythingOfType("*service.ServiceNode"), mock.AnythingOfType("int")).Return("hostid", nil)
+
+	listener := NewServiceListener("poolid", handler)
+	listener.SetConnection(conn)
+	c.Assert(listener.Ready(), IsNil)
+
+	shutdown := make(chan interface{})
+	defer func() { close(shutdown) }()
+
+	done := make(chan struct{})
+	go func() {
+		listener.Spawn(shutdown, "serviceid2")
+		close(done)
+	}()
+
+	// the service waits while the critical service has not started
+	time.Sleep(2 * time.Second)
+	ch, ev, err := conn.ChildrenW(spth, done)
+	c.Assert(err, IsNil)
+	c.Assert(ch, HasLen, 0)
+
+	// the service starts once the critical service no longer needs to run
+	cdat := &ServiceNode{}
+	err = conn.Get(cpth, cdat)
+	c.Assert(err, IsNil)
+	cdat.DesiredState = int(service.SVCStop)
+	err = conn.Set(cpth, cdat)
+	c.Assert(err, IsNil)
+
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	select {
+	case <-ev:
+		time.Sleep(500 * time.Millisecond) // Lag on events with create
+		ch, err = conn.Children(spth)
+		c.Assert(err, IsNil)
+		c.Check(ch, HasLen, 1)
+	case <-done:
+		c.Fatalf("listener exited")
+	case <-timer.C:
+		c.Errorf("listener timed out")
+	}
+}
+
 func (t *ZZKTest) TestServiceListener_Stop_Offline(c *C) {
 	// Pre-requisites
 	conn, err := zzk.GetLocalConnection("/")
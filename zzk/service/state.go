@@ -49,6 +49,7 @@ type ServiceState struct {
 	ContainerID string
 	ImageUUID   string
 	Paused      bool
+	PauseError  string // why the last attempt to pause the container failed
 	PrivateIP   string
 	HostIP      string
 	AssignedIP  string
@@ -57,6 +58,7 @@ type ServiceState struct {
 	Exports     []ExportBinding
 	Started     time.Time
 	Terminated  time.Time
+	Exits       []service.InstanceExit // most recent container exits, oldest first
 	version     interface{}
 }
 
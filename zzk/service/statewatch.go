@@ -0,0 +1,108 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+	"sort"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// WaitStateChange blocks until an instance of any of the services is added,
+// removed, or updated, and returns the ids of the services that changed.  The
+// services map a service id to its pool id.  It returns no ids if cancel is
+// closed first.
+func WaitStateChange(cancel <-chan struct{}, conn client.Connection, services map[string]string) ([]string, error) {
+	done := make(chan struct{})
+	defer close(done)
+
+	changed := make(chan string, len(services))
+	watch := func(serviceID string, ev <-chan client.Event) {
+		select {
+		case <-ev:
+			select {
+			case changed <- serviceID:
+			case <-done:
+			}
+		case <-done:
+		}
+	}
+
+	for serviceID, poolID := range services {
+		basepth := "/"
+		if poolID != "" {
+			basepth = path.Join("/pools", poolID)
+		}
+		logger := plog.WithField("serviceid", serviceID)
+
+		// watch for instances that are added or removed
+		sspth := path.Join(basepth, "/services", serviceID)
+		ch, ev, err := conn.ChildrenW(sspth, done)
+		if err == client.ErrNoNode {
+			continue
+		} else if err != nil {
+			logger.WithError(err).Debug("Could not watch states for service")
+			return nil, err
+		}
+		go watch(serviceID, ev)
+
+		// watch for instances that are updated
+		for _, stateID := range ch {
+			hostID, _, _, err := ParseStateID(stateID)
+			if err != nil {
+				logger.WithError(err).WithField("stateid", stateID).Debug("Skipping invalid state id")
+				continue
+			}
+			for pth, node := range map[string]client.Node{
+				path.Join(sspth, stateID):                                  &ServiceState{},
+				path.Join(basepth, "/hosts", hostID, "instances", stateID): &HostState{},
+			} {
+				ev, err := conn.GetW(pth, node, done)
+				if err == client.ErrNoNode {
+					continue
+				} else if err != nil {
+					logger.WithError(err).WithField("zkpath", pth).Debug("Could not watch state")
+					return nil, err
+				}
+				go watch(serviceID, ev)
+			}
+		}
+	}
+
+	// wait for the first change, and collect any others that have already
+	// fired
+	ids := make(map[string]struct{})
+	select {
+	case serviceID := <-changed:
+		ids[serviceID] = struct{}{}
+	case <-cancel:
+		return nil, nil
+	}
+	for collecting := true; collecting; {
+		select {
+		case serviceID := <-changed:
+			ids[serviceID] = struct{}{}
+		default:
+			collecting = false
+		}
+	}
+
+	serviceIDs := make([]string, 0, len(ids))
+	for serviceID := range ids {
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	sort.Strings(serviceIDs)
+	return serviceIDs, nil
+}
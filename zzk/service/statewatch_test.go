@@ -0,0 +1,88 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration,!quick
+
+package service_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/zzk"
+	. "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+func (t *ZZKTest) TestWaitStateChange(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	err = conn.CreateDir("/pools/poolid/services/serviceid")
+	c.Assert(err, IsNil)
+	err = conn.CreateDir("/pools/poolid/services/otherid")
+	c.Assert(err, IsNil)
+	err = conn.CreateDir("/pools/poolid/hosts/hostid")
+	c.Assert(err, IsNil)
+	services := map[string]string{"serviceid": "poolid", "otherid": "poolid"}
+
+	// times out without a change
+	cancel := make(chan struct{})
+	timer := time.AfterFunc(100*time.Millisecond, func() { close(cancel) })
+	ids, err := WaitStateChange(cancel, conn, services)
+	c.Assert(err, IsNil)
+	c.Assert(ids, HasLen, 0)
+	timer.Stop()
+
+	// an instance is added
+	req := StateRequest{
+		PoolID:     "poolid",
+		HostID:     "hostid",
+		ServiceID:  "serviceid",
+		InstanceID: 0,
+	}
+	done := make(chan []string)
+	go func() {
+		ids, err := WaitStateChange(make(chan struct{}), conn, services)
+		c.Check(err, IsNil)
+		done <- ids
+	}()
+	time.Sleep(100 * time.Millisecond)
+	err = CreateState(conn, req)
+	c.Assert(err, IsNil)
+	select {
+	case ids := <-done:
+		c.Check(ids, DeepEquals, []string{"serviceid"})
+	case <-time.After(5 * time.Second):
+		c.Fatalf("Timed out waiting for the instance to be added")
+	}
+
+	// an instance is updated
+	go func() {
+		ids, err := WaitStateChange(make(chan struct{}), conn, services)
+		c.Check(err, IsNil)
+		done <- ids
+	}()
+	time.Sleep(100 * time.Millisecond)
+	err = UpdateState(conn, req, func(s *State) bool {
+		s.DesiredState = service.SVCPause
+		return true
+	})
+	c.Assert(err, IsNil)
+	select {
+	case ids := <-done:
+		c.Check(ids, DeepEquals, []string{"serviceid"})
+	case <-time.After(5 * time.Second):
+		c.Fatalf("Timed out waiting for the instance to be updated")
+	}
+}
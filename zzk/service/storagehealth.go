@@ -0,0 +1,71 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/host"
+)
+
+// StorageHealthNode is the most recent report of the usage of a host's
+// storage pools
+type StorageHealthNode struct {
+	host.StorageHealth
+	version interface{}
+}
+
+// Version implements client.Node
+func (n *StorageHealthNode) Version() interface{} {
+	return n.version
+}
+
+// SetVersion implements client.Node
+func (n *StorageHealthNode) SetVersion(version interface{}) {
+	n.version = version
+}
+
+// UpdateStorageHealth reports the usage of the host's storage pools.  This is
+// managed by the worker node, so it is expected that the connection will be
+// pre-loaded with the path to the resource pool.  Returns client.ErrNoNode if
+// the host is not registered.
+func UpdateStorageHealth(conn client.Connection, hostid string, health host.StorageHealth) error {
+	pth := path.Join("/hosts", hostid, "storage")
+	node := &StorageHealthNode{StorageHealth: health}
+	existing := &StorageHealthNode{}
+	if err := conn.Get(pth, existing); err == client.ErrNoNode {
+		return conn.CreateIfExists(pth, node)
+	} else if err != nil {
+		return err
+	}
+	node.SetVersion(existing.Version())
+	return conn.Set(pth, node)
+}
+
+// GetStorageHealth returns the last reported usage of the host's storage
+// pools, or nil if the host has not reported.
+func GetStorageHealth(conn client.Connection, poolid, hostid string) (*host.StorageHealth, error) {
+	basepth := "/"
+	if poolid != "" {
+		basepth = path.Join("/pools", poolid)
+	}
+	node := &StorageHealthNode{}
+	if err := conn.Get(path.Join(basepth, "/hosts", hostid, "storage"), node); err == client.ErrNoNode {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &node.StorageHealth, nil
+}
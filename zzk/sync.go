@@ -20,7 +20,6 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/coordinator/client"
-	"github.com/zenoss/glog"
 )
 
 // DefaultRetryTime is the time to retry a failed local operation
@@ -81,13 +80,13 @@ func Sync(conn client.Connection, data []Node, zkpath string) error {
 
 	for _, id := range current {
 		if node, ok := datamap[id]; ok {
-			glog.V(2).Infof("Updating id:'%s' at zkpath:%s with: %+v", id, zkpath, node)
+			plog.Debugf("Updating id:'%s' at zkpath:%s with: %+v", id, zkpath, node)
 			if err := node.Update(conn); err != nil {
 				return err
 			}
 			delete(datamap, id)
 		} else {
-			glog.V(2).Infof("Deleting id:'%s' at zkpath:%s not found in elastic\nzk current children: %v", id, zkpath, current)
+			plog.Debugf("Deleting id:'%s' at zkpath:%s not found in elastic\nzk current children: %v", id, zkpath, current)
 			if err := conn.Delete(path.Join(zkpath, id)); err != nil {
 				return err
 			}
@@ -95,7 +94,7 @@ func Sync(conn client.Connection, data []Node, zkpath string) error {
 	}
 
 	for id, node := range datamap {
-		glog.V(2).Infof("Creating id:'%s' at zkpath:%s with: %+v", id, zkpath, node)
+		plog.Debugf("Creating id:'%s' at zkpath:%s with: %+v", id, zkpath, node)
 		if err := node.Create(conn); err != nil {
 			return err
 		}
@@ -127,7 +126,7 @@ func (l *Synchronizer) PostProcess(processing map[string]struct{}) {
 	// Get all locally stored data
 	nodes, err := l.GetAll()
 	if err != nil {
-		glog.Warningf("Could not access locally stored data: %s", err)
+		plog.Warningf("Could not access locally stored data: %s", err)
 		return
 	}
 
@@ -136,7 +135,7 @@ func (l *Synchronizer) PostProcess(processing map[string]struct{}) {
 		if _, ok := processing[node.GetID()]; ok {
 			// pass
 		} else if err := l.Delete(node.GetID()); err != nil {
-			glog.Warningf("Could not delete %s from locally stored data: %s", node.GetID(), err)
+			plog.Warningf("Could not delete %s from locally stored data: %s", node.GetID(), err)
 		}
 	}
 }
@@ -164,21 +163,21 @@ func (l *Synchronizer) Spawn(shutdown <-chan interface{}, nodeID string) {
 		event, err := l.conn.GetW(l.GetPath(nodeID), node, done)
 		if err == client.ErrNoNode && id != "" {
 			if err := l.Delete(id); err != nil {
-				glog.Errorf("Could not delete node at %s: %s", l.GetPath(nodeID), err)
+				plog.Errorf("Could not delete node at %s: %s", l.GetPath(nodeID), err)
 				wait = time.After(DefaultRetryTime)
 			} else {
 				return
 			}
 		} else if err != nil {
-			glog.Errorf("Could not get node at %s: %s", l.GetPath(nodeID), err)
+			plog.Errorf("Could not get node at %s: %s", l.GetPath(nodeID), err)
 			return
 		}
 
 		if key, err := l.AddUpdate(id, node); err == ErrInvalidType {
-			glog.Errorf("Invalid type detected")
+			plog.Errorf("Invalid type detected")
 			return
 		} else if err != nil {
-			glog.Errorf("Could not update node at %s: %s", l.GetPath(nodeID), err)
+			plog.Errorf("Could not update node at %s: %s", l.GetPath(nodeID), err)
 			wait = time.After(DefaultRetryTime)
 		} else if id == "" {
 			wg.Add(1)
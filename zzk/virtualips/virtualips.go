@@ -22,12 +22,15 @@ import (
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/zzk"
 	zkservice "github.com/control-center/serviced/zzk/service"
-	"github.com/zenoss/glog"
 )
 
+// initialize the package logger
+var plog = logging.PackageLogger()
+
 const (
 	zkVirtualIP            = "/virtualIPs"
 	virtualInterfacePrefix = ":z"
@@ -153,7 +156,7 @@ func (l *VirtualIPListener) Spawn(shutdown <-chan interface{}, ip string) {
 
 	// Check if this ip has exceeded the number of retries for this host
 	if l.retry[ip] > maxRetries {
-		glog.Warningf("Throttling acquisition of %s for %s", ip, l.hostID)
+		plog.Warningf("Throttling acquisition of %s for %s", ip, l.hostID)
 		select {
 		case <-time.After(waitTimeout):
 		case <-shutdown:
@@ -161,11 +164,11 @@ func (l *VirtualIPListener) Spawn(shutdown <-chan interface{}, ip string) {
 		}
 	}
 
-	glog.V(2).Infof("Host %s waiting to acquire virtual ip %s", l.hostID, ip)
+	plog.Debugf("Host %s waiting to acquire virtual ip %s", l.hostID, ip)
 	// Try to take lead on the path
 	leader, err := l.conn.NewLeader(l.GetPath(ip))
 	if err != nil {
-		glog.Errorf("Could not initialize leader node for ip %s: %s", ip, err)
+		plog.Errorf("Could not initialize leader node for ip %s: %s", ip, err)
 		return
 	}
 	hlnode := zzk.HostLeader{
@@ -175,7 +178,7 @@ func (l *VirtualIPListener) Spawn(shutdown <-chan interface{}, ip string) {
 	defer close(leaderDone)
 	_, err = leader.TakeLead(&hlnode, leaderDone)
 	if err != nil {
-		glog.Errorf("Error while trying to acquire a lock for %s: %s", ip, err)
+		plog.Errorf("Error while trying to acquire a lock for %s: %s", ip, err)
 		return
 	}
 	defer l.stopInstances(ip)
@@ -189,7 +192,7 @@ func (l *VirtualIPListener) Spawn(shutdown <-chan interface{}, ip string) {
 
 	// Check if the path still exists
 	if exists, err := zzk.PathExists(l.conn, l.GetPath(ip)); err != nil {
-		glog.Errorf("Error while checking ip %s: %s", ip, err)
+		plog.Errorf("Error while checking ip %s: %s", ip, err)
 		return
 	} else if !exists {
 		return
@@ -202,18 +205,18 @@ func (l *VirtualIPListener) Spawn(shutdown <-chan interface{}, ip string) {
 		var vip pool.VirtualIP
 		event, err := l.conn.GetW(l.GetPath(ip), &VirtualIPNode{VirtualIP: &vip}, done)
 		if err == client.ErrEmptyNode {
-			glog.Errorf("Deleting empty node for ip %s", ip)
+			plog.Errorf("Deleting empty node for ip %s", ip)
 			RemoveVirtualIP(l.conn, ip)
 			return
 		} else if err != nil {
-			glog.Errorf("Could not load virtual ip %s: %s", ip, err)
+			plog.Errorf("Could not load virtual ip %s: %s", ip, err)
 			return
 		}
 
-		glog.V(2).Infof("Host %s binding to %s", l.hostID, ip)
+		plog.Debugf("Host %s binding to %s", l.hostID, ip)
 		rebind, err := l.bind(&vip, index)
 		if err != nil {
-			glog.Errorf("Could not bind to virtual ip %s: %s", ip, err)
+			plog.Errorf("Could not bind to virtual ip %s: %s", ip, err)
 			l.retry[ip]++
 			return
 		}
@@ -226,22 +229,22 @@ func (l *VirtualIPListener) Spawn(shutdown <-chan interface{}, ip string) {
 		case e := <-event:
 			// If the virtual ip is changed, you need to update the bindings
 			if err := l.unbind(ip); err != nil {
-				glog.Errorf("Could not unbind to virtual ip %s: %s", ip, err)
+				plog.Errorf("Could not unbind to virtual ip %s: %s", ip, err)
 				return
 			}
 			if e.Type == client.EventNodeDeleted {
 				return
 			}
-			glog.V(4).Infof("virtual ip listener for %s received event: %v", ip, e)
+			plog.Debugf("virtual ip listener for %s received event: %v", ip, e)
 		case <-rebind:
 			// If the primary virtual IP is removed, all other virtual IPs on
 			// that subnet are removed.  This is in place to restore the
 			// virtual IPs that were removed soley by the removal of the
 			// primary virtual IP.
-			glog.V(2).Infof("Host %s rebinding to %s", l.hostID, ip)
+			plog.Debugf("Host %s rebinding to %s", l.hostID, ip)
 		case <-shutdown:
 			if err := l.unbind(ip); err != nil {
-				glog.Errorf("Could not unbind to virtual ip %s: %s", ip, err)
+				plog.Errorf("Could not unbind to virtual ip %s: %s", ip, err)
 			}
 			return
 		}
@@ -301,12 +304,12 @@ func (l *VirtualIPListener) unbind(ip string) error {
 }
 
 func (l *VirtualIPListener) stopInstances(ip string) {
-	glog.Infof("Stopping service instances using ip %s on host %s", ip, l.hostID)
+	plog.Infof("Stopping service instances using ip %s on host %s", ip, l.hostID)
 
 	// Get all the states on the host
 	states, err := zkservice.GetHostStateIDs(l.conn, "", l.hostID)
 	if err != nil {
-		glog.Errorf("Could not look up host states for host %s: %s", l.hostID, err)
+		plog.Errorf("Could not look up host states for host %s: %s", l.hostID, err)
 		return
 	}
 
@@ -319,7 +322,7 @@ func (l *VirtualIPListener) stopInstances(ip string) {
 			}
 			return false
 		}); err != nil {
-			glog.Warningf("Could not stop service state %s on host %s: %s", req.StateID(), l.hostID, err)
+			plog.Warningf("Could not stop service state %s on host %s: %s", req.StateID(), l.hostID, err)
 			continue
 		}
 	}
@@ -337,7 +340,7 @@ func AddVirtualIP(conn client.Connection, virtualIP *pool.VirtualIP) error {
 	var node VirtualIPNode
 	path := vippath(virtualIP.IP)
 
-	glog.V(1).Infof("Adding virtual ip to zookeeper: %s", path)
+	plog.Debugf("Adding virtual ip to zookeeper: %s", path)
 	if err := conn.Create(path, &node); err != nil {
 		return err
 	}
@@ -346,7 +349,7 @@ func AddVirtualIP(conn client.Connection, virtualIP *pool.VirtualIP) error {
 }
 
 func RemoveVirtualIP(conn client.Connection, ip string) error {
-	glog.V(1).Infof("Removing virtual ip from zookeeper: %s", vippath(ip))
+	plog.Debugf("Removing virtual ip from zookeeper: %s", vippath(ip))
 	err := conn.Delete(vippath(ip))
 	if err == nil || err == client.ErrNoNode {
 		return nil
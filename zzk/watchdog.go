@@ -0,0 +1,194 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zzk
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/rcrowley/go-metrics"
+)
+
+// watchdogGrace is the number of consecutive checks that a listener may
+// exceed its expected counts before it is reported as leaking.  Goroutines
+// for deleted nodes may take a while to clean up (e.g. stopping a container).
+const watchdogGrace = 3
+
+// Metrics is the registry of the listener, goroutine, and watch counts of
+// the zookeeper listeners running on this host.
+var Metrics = metrics.NewRegistry()
+
+// ListenerStats describes the resources held by the listeners at a path
+type ListenerStats struct {
+	Path               string
+	Listeners          int   // listeners registered at the path
+	Goroutines         int64 // goroutines spawned for child nodes
+	ExpectedGoroutines int   // child nodes currently at the path
+	Watches            int64 // watches open against the path
+	ExpectedWatches    int   // one watch per listener
+	Leaking            bool
+}
+
+// listenerRecord tracks the resources held by a single running listener
+type listenerRecord struct {
+	conn       client.Connection
+	path       string
+	goroutines int64
+	watches    int64
+	over       int // consecutive checks over the expected counts
+}
+
+var watchdog = struct {
+	sync.Mutex
+	listeners map[*listenerRecord]struct{}
+}{listeners: make(map[*listenerRecord]struct{})}
+
+// trackListener registers a running listener with the watchdog
+func trackListener(conn client.Connection, path string) *listenerRecord {
+	rec := &listenerRecord{conn: conn, path: path}
+	watchdog.Lock()
+	watchdog.listeners[rec] = struct{}{}
+	watchdog.Unlock()
+	return rec
+}
+
+// untrack removes the listener from the watchdog
+func (rec *listenerRecord) untrack() {
+	watchdog.Lock()
+	delete(watchdog.listeners, rec)
+	watchdog.Unlock()
+}
+
+// spawned counts goroutines started (n > 0) or exited (n < 0) by the listener
+func (rec *listenerRecord) spawned(n int64) {
+	watchdog.Lock()
+	rec.goroutines += n
+	watchdog.Unlock()
+}
+
+// watched counts watches opened (n > 0) or closed (n < 0) by the listener
+func (rec *listenerRecord) watched(n int64) {
+	watchdog.Lock()
+	rec.watches += n
+	watchdog.Unlock()
+}
+
+// CheckListeners compares the goroutines and watches held by each listener
+// path against the counts expected from the current state of zookeeper, and
+// returns the stats of every path, sorted by path.  A path is leaking if it
+// has more than one listener or has exceeded its expected counts for several
+// consecutive checks.
+func CheckListeners() []ListenerStats {
+	watchdog.Lock()
+	records := make(map[string][]*listenerRecord)
+	for rec := range watchdog.listeners {
+		records[rec.path] = append(records[rec.path], rec)
+	}
+	watchdog.Unlock()
+
+	var paths []string
+	for p := range records {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	stats := make([]ListenerStats, 0, len(paths))
+	for _, p := range paths {
+		recs := records[p]
+
+		// look up the children outside of the lock; the connection may be
+		// slow to respond while the session is churning
+		children, err := recs[0].conn.Children(p)
+		if err == client.ErrNoNode {
+			children, err = nil, nil
+		}
+
+		watchdog.Lock()
+		s := ListenerStats{
+			Path:               p,
+			Listeners:          len(recs),
+			ExpectedGoroutines: len(children),
+			ExpectedWatches:    len(recs),
+		}
+		for _, rec := range recs {
+			s.Goroutines += rec.goroutines
+			s.Watches += rec.watches
+		}
+		if err != nil {
+			plog.WithError(err).WithField("path", p).Debug("Could not look up child nodes for the listener watchdog")
+		} else {
+			over := s.Goroutines > int64(s.ExpectedGoroutines) || s.Watches > int64(s.ExpectedWatches)
+			for _, rec := range recs {
+				if over {
+					rec.over++
+				} else {
+					rec.over = 0
+				}
+				if rec.over >= watchdogGrace {
+					s.Leaking = true
+				}
+			}
+		}
+		if s.Listeners > 1 {
+			s.Leaking = true
+		}
+		watchdog.Unlock()
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// RunWatchdog periodically checks the running listeners for leaked
+// goroutines and watches, which are a common failure after zookeeper session
+// churn.  Leaks are logged and the totals are published to Metrics.
+func RunWatchdog(shutdown <-chan interface{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			updateWatchdogMetrics(CheckListeners())
+		case <-shutdown:
+			return
+		}
+	}
+}
+
+// updateWatchdogMetrics logs the leaking listeners and publishes the totals
+func updateWatchdogMetrics(stats []ListenerStats) {
+	var listeners, goroutines, watches, leaks int64
+	for _, s := range stats {
+		listeners += int64(s.Listeners)
+		goroutines += s.Goroutines
+		watches += s.Watches
+		if s.Leaking {
+			leaks++
+			plog.WithFields(logrus.Fields{
+				"path":               s.Path,
+				"listeners":          s.Listeners,
+				"goroutines":         s.Goroutines,
+				"expectedgoroutines": s.ExpectedGoroutines,
+				"watches":            s.Watches,
+				"expectedwatches":    s.ExpectedWatches,
+			}).Warn("Detected leaking zookeeper listener")
+		}
+	}
+	metrics.GetOrRegisterGauge("zzk.listeners", Metrics).Update(listeners)
+	metrics.GetOrRegisterGauge("zzk.goroutines", Metrics).Update(goroutines)
+	metrics.GetOrRegisterGauge("zzk.watches", Metrics).Update(watches)
+	metrics.GetOrRegisterGauge("zzk.leaks", Metrics).Update(leaks)
+}
@@ -0,0 +1,105 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package zzk
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// childrenConn is a connection that only answers Children
+type childrenConn struct {
+	client.Connection
+	children map[string][]string
+}
+
+func (conn *childrenConn) Children(p string) ([]string, error) {
+	if children, ok := conn.children[p]; ok {
+		return children, nil
+	}
+	return nil, client.ErrNoNode
+}
+
+func TestCheckListeners(t *testing.T) {
+	conn := &childrenConn{children: map[string][]string{
+		"/hosts/host1/instances": {"a", "b"},
+	}}
+
+	rec := trackListener(conn, "/hosts/host1/instances")
+	defer rec.untrack()
+	rec.watched(1)
+	rec.spawned(2)
+
+	stats := CheckListeners()
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 listener path, got %d", len(stats))
+	}
+	s := stats[0]
+	if s.Goroutines != 2 || s.ExpectedGoroutines != 2 || s.Watches != 1 || s.ExpectedWatches != 1 || s.Leaking {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+
+	// goroutines that outlive their nodes are only reported after the grace
+	rec.spawned(1)
+	for i := 1; i < watchdogGrace; i++ {
+		if s := CheckListeners()[0]; s.Leaking {
+			t.Fatalf("check %d reported a leak before the grace expired", i)
+		}
+	}
+	if s := CheckListeners()[0]; !s.Leaking || s.Goroutines != 3 {
+		t.Fatalf("expected a goroutine leak, got %+v", s)
+	}
+	rec.spawned(-1)
+	if s := CheckListeners()[0]; s.Leaking {
+		t.Fatalf("expected the leak to clear, got %+v", s)
+	}
+
+	// a second listener on the same path is always a leak
+	dup := trackListener(conn, "/hosts/host1/instances")
+	dup.watched(1)
+	if s := CheckListeners()[0]; !s.Leaking || s.Listeners != 2 || s.ExpectedWatches != 2 {
+		t.Fatalf("expected a duplicate listener leak, got %+v", s)
+	}
+	dup.untrack()
+
+	// a listener whose node was removed expects no goroutines
+	pools := trackListener(conn, "/pools")
+	defer pools.untrack()
+	stats = CheckListeners()
+	if len(stats) != 2 || stats[1].Path != "/pools" || stats[1].ExpectedGoroutines != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestUpdateWatchdogMetrics(t *testing.T) {
+	updateWatchdogMetrics([]ListenerStats{
+		{Path: "/a", Listeners: 1, Goroutines: 3, Watches: 1},
+		{Path: "/b", Listeners: 2, Goroutines: 1, Watches: 2, Leaking: true},
+	})
+	for name, expected := range map[string]int64{
+		"zzk.listeners":  3,
+		"zzk.goroutines": 4,
+		"zzk.watches":    3,
+		"zzk.leaks":      1,
+	} {
+		if actual := Metrics.Get(name).(interface {
+			Value() int64
+		}).Value(); actual != expected {
+			t.Errorf("%s: expected %d, got %d", name, expected, actual)
+		}
+	}
+}
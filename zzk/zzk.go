@@ -18,9 +18,12 @@ import (
 
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/coordinator/client/zookeeper"
-	"github.com/zenoss/glog"
+	"github.com/control-center/serviced/logging"
 )
 
+// initialize the package logger
+var plog = logging.PackageLogger()
+
 const retryLimit = 2
 
 // Errors
@@ -167,21 +170,24 @@ func Listen(shutdown <-chan interface{}, ready chan<- error, conn client.Connect
 	)
 
 	l.SetConnection(conn)
-	glog.Infof("Starting a listener at %s", l.GetPath())
+	plog.Infof("Starting a listener at %s", l.GetPath())
 	if err := Ready(shutdown, conn, l.GetPath()); err != nil {
-		glog.Errorf("Could not start listener at %s: %s", l.GetPath(), err)
+		plog.Errorf("Could not start listener at %s: %s", l.GetPath(), err)
 		ready <- err
 		return
 	} else if err := l.Ready(); err != nil {
-		glog.Errorf("Could not start listener at %s: %s", l.GetPath(), err)
+		plog.Errorf("Could not start listener at %s: %s", l.GetPath(), err)
 		ready <- err
 		return
 	}
 
 	close(ready)
 
+	rec := trackListener(conn, l.GetPath())
+	defer rec.untrack()
+
 	defer func() {
-		glog.Infof("Listener at %s received interrupt", l.GetPath())
+		plog.Infof("Listener at %s received interrupt", l.GetPath())
 		l.Done()
 		close(_shutdown)
 		for len(processing) > 0 {
@@ -189,23 +195,29 @@ func Listen(shutdown <-chan interface{}, ready chan<- error, conn client.Connect
 		}
 	}()
 
-	glog.V(1).Infof("Listener %s started; waiting for data", l.GetPath())
+	plog.Debugf("Listener %s started; waiting for data", l.GetPath())
 	doneW := make(chan struct{})
-	defer func(channel *chan struct{}) { close(*channel) }(&doneW)
+	defer func(channel *chan struct{}) {
+		close(*channel)
+		rec.watched(-1)
+	}(&doneW)
 	for {
+		rec.watched(1)
 		nodes, event, err := conn.ChildrenW(l.GetPath(), doneW)
 		if err != nil {
-			glog.Errorf("Could not watch for nodes at %s: %s", l.GetPath(), err)
+			plog.Errorf("Could not watch for nodes at %s: %s", l.GetPath(), err)
 			return
 		}
 
 		for _, node := range nodes {
 			if _, ok := processing[node]; !ok {
-				glog.V(1).Infof("Spawning a goroutine for %s", l.GetPath(node))
+				plog.Debugf("Spawning a goroutine for %s", l.GetPath(node))
 				processing[node] = struct{}{}
+				rec.spawned(1)
 				go func(node string) {
 					defer func() {
-						glog.V(1).Infof("Goroutine at %s was shutdown", l.GetPath(node))
+						plog.Debugf("Goroutine at %s was shutdown", l.GetPath(node))
+						rec.spawned(-1)
 						done <- node
 					}()
 					l.Spawn(_shutdown, node)
@@ -218,24 +230,25 @@ func Listen(shutdown <-chan interface{}, ready chan<- error, conn client.Connect
 		select {
 		case e := <-event:
 			if e.Type == client.EventNodeDeleted {
-				glog.V(1).Infof("Node %s has been removed; shutting down listener", l.GetPath())
+				plog.Debugf("Node %s has been removed; shutting down listener", l.GetPath())
 				return
 			} else if e.Type == client.EventSession || e.Type == client.EventNotWatching {
-				glog.Warningf("Node %s had a reconnect; resetting listener", l.GetPath())
+				plog.Warningf("Node %s had a reconnect; resetting listener", l.GetPath())
 				if err := l.Ready(); err != nil {
-					glog.Errorf("Could not ready listener; shutting down")
+					plog.Errorf("Could not ready listener; shutting down")
 					return
 				}
 			}
-			glog.V(4).Infof("Node %s received event %v", l.GetPath(), e)
+			plog.Debugf("Node %s received event %v", l.GetPath(), e)
 		case node := <-done:
-			glog.V(3).Infof("Cleaning up %s", l.GetPath(node))
+			plog.Debugf("Cleaning up %s", l.GetPath(node))
 			delete(processing, node)
 		case <-shutdown:
 			return
 		}
 
 		close(doneW)
+		rec.watched(-1)
 		doneW = make(chan struct{})
 	}
 }
@@ -261,7 +274,7 @@ func Start(shutdown <-chan interface{}, conn client.Connection, master Listener,
 	select {
 	case err := <-masterReady:
 		if err != nil {
-			glog.Errorf("master listener at %s failed to start: %s", master.GetPath(), err)
+			plog.Errorf("master listener at %s failed to start: %s", master.GetPath(), err)
 			return
 		}
 
@@ -277,10 +290,10 @@ func Start(shutdown <-chan interface{}, conn client.Connection, master Listener,
 				case <-_shutdown:
 					return
 				default:
-					glog.Warningf("Restarting child listeners for master at %s", master.GetPath())
+					plog.Warningf("Restarting child listeners for master at %s", master.GetPath())
 				}
 			}
-			glog.Warningf("Shutting down master listener at %s; child listeners exceeded retry limit", master.GetPath())
+			plog.Warningf("Shutting down master listener at %s; child listeners exceeded retry limit", master.GetPath())
 		}()
 	case <-masterDone:
 	case <-shutdown:
@@ -289,11 +302,11 @@ func Start(shutdown <-chan interface{}, conn client.Connection, master Listener,
 	defer close(_shutdown)
 	select {
 	case <-masterDone:
-		glog.Warningf("Master listener at %s died prematurely; shutting down", master.GetPath())
+		plog.Warningf("Master listener at %s died prematurely; shutting down", master.GetPath())
 	case <-childDone:
-		glog.Warningf("Child listeners for master %s died prematurely; shutting down", master.GetPath())
+		plog.Warningf("Child listeners for master %s died prematurely; shutting down", master.GetPath())
 	case <-shutdown:
-		glog.Infof("Received signal to shutdown for master listener %s", master.GetPath())
+		plog.Infof("Received signal to shutdown for master listener %s", master.GetPath())
 	}
 }
 
@@ -301,7 +314,7 @@ func start(shutdown <-chan interface{}, conn client.Connection, listeners ...Lis
 	var count int
 	done := make(chan int)
 	defer func() {
-		glog.Infof("Shutting down %d child listeners", len(listeners))
+		plog.Infof("Shutting down %d child listeners", len(listeners))
 		for count > 0 {
 			count -= <-done
 		}
@@ -315,15 +328,15 @@ func start(shutdown <-chan interface{}, conn client.Connection, listeners ...Lis
 		go func(l Listener) {
 			defer func() { done <- 1 }()
 			Listen(_shutdown, make(chan error, 1), conn, l)
-			glog.Infof("Listener at %s exited", l.GetPath())
+			plog.Infof("Listener at %s exited", l.GetPath())
 		}(listeners[i])
 	}
 
 	select {
 	case i := <-done:
-		glog.Warningf("Listener exited prematurely, stopping all listeners")
+		plog.Warningf("Listener exited prematurely, stopping all listeners")
 		count -= i
 	case <-shutdown:
-		glog.Infof("Received signal to shutdown")
+		plog.Infof("Received signal to shutdown")
 	}
 }